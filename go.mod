@@ -59,7 +59,7 @@ require (
 	github.com/mitchellh/copystructure v1.2.0 // indirect; indirect - Deep copying of Go structures (32K)
 	github.com/mitchellh/reflectwalk v1.0.2 // indirect; indirect - Reflection-based struct walking (36K)
 	github.com/pmezard/go-difflib v1.0.0 // indirect; indirect - Diff library (36K source, 0 KB in binary)
-	github.com/spf13/pflag v1.0.9 // indirect; indirect - POSIX/GNU-style flags (312K)
+	github.com/spf13/pflag v1.0.9 // indirect - POSIX/GNU-style flags (312K)
 	golang.org/x/sys v0.39.0 // indirect - Low-level OS primitives (9.0M) ⚠️ LARGEST DEPENDENCY
 )
 
@@ -73,7 +73,10 @@ require (
 	golang.org/x/sync v0.19.0
 )
 
-require github.com/ariel-frischer/claude-clean v0.2.0
+require (
+	github.com/ariel-frischer/claude-clean v0.2.0
+	github.com/go-git/go-git/v5 v5.16.0
+)
 
 require (
 	dario.cat/mergo v1.0.0 // indirect
@@ -84,7 +87,6 @@ require (
 	github.com/emirpasic/gods v1.18.1 // indirect
 	github.com/go-git/gcfg v1.5.1-0.20230307220236-3a3c6141e376 // indirect
 	github.com/go-git/go-billy/v5 v5.6.2 // indirect
-	github.com/go-git/go-git/v5 v5.16.0 // indirect
 	github.com/golang/groupcache v0.0.0-20241129210726-2c02b8208cf8 // indirect
 	github.com/jbenet/go-context v0.0.0-20150711004518-d14ea06fba99 // indirect
 	github.com/kevinburke/ssh_config v1.2.0 // indirect
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 )
 
 const (
@@ -71,7 +73,7 @@ func UpdateAgentFile(filePath string, planData *PlanData, repoRoot string) (*Upd
 	content = updateLastUpdated(content)
 
 	// Write atomically using temp file + rename
-	if err := writeAtomic(absPath, content); err != nil {
+	if err := fsutil.WriteFile(absPath, []byte(content), 0o644); err != nil {
 		result.Error = fmt.Errorf("failed to write %s: %w", filePath, err)
 		return result, result.Error
 	}
@@ -226,42 +228,6 @@ func updateLastUpdated(content string) string {
 	return content
 }
 
-// writeAtomic writes content to a file atomically using temp file + rename.
-func writeAtomic(filePath string, content string) error {
-	// Create temp file in same directory
-	dir := filepath.Dir(filePath)
-	tmpFile, err := os.CreateTemp(dir, ".tmp-*")
-	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
-	}
-	tmpPath := tmpFile.Name()
-
-	// Clean up temp file on error
-	defer func() {
-		if tmpPath != "" {
-			os.Remove(tmpPath)
-		}
-	}()
-
-	// Write content
-	if _, err := tmpFile.WriteString(content); err != nil {
-		tmpFile.Close()
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("failed to close temp file: %w", err)
-	}
-
-	// Rename temp file to target
-	if err := os.Rename(tmpPath, filePath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
-	}
-
-	tmpPath = "" // Prevent cleanup of successfully renamed file
-	return nil
-}
-
 // UpdateAllAgents updates all existing agent context files.
 // It iterates through all supported agents and updates files that exist.
 // If no agent files exist, creates CLAUDE.md from template.
@@ -0,0 +1,111 @@
+// Package agentenv renders the environment variables that the env and
+// stage_env config keys inject into agent sessions, e.g. a DATABASE_URL for
+// the tests an implementation session runs. Values are text/template strings
+// with the spec, stage and task of the session as data.
+package agentenv
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Stages lists the workflow stage names, as accepted for stage_env and
+// workdir keys.
+var Stages = []string{"constitution", "specify", "clarify", "plan", "tasks", "checklist", "analyze", "implement"}
+
+// namePattern matches portable environment variable names.
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Data holds the placeholder values available to env templates.
+//
+//	{{.SpecName}}  spec directory name (e.g., "003-command-timeout"), empty for specify
+//	{{.Stage}}     stage name (e.g., "implement")
+//	{{.TaskID}}    task ID in task mode (e.g., "T004"), empty otherwise
+//	{{.Phase}}     phase number in phase mode, 0 otherwise
+type Data struct {
+	SpecName string
+	Stage    string
+	TaskID   string
+	Phase    int
+}
+
+// Env is the configured environment for agent sessions: Global applies to
+// every session and Stages, keyed by stage name, overrides it per stage.
+type Env struct {
+	Global map[string]string
+	Stages map[string]map[string]string
+}
+
+// Empty reports whether no variables are configured.
+func (e Env) Empty() bool {
+	return len(e.Global) == 0 && len(e.Stages) == 0
+}
+
+// Vars returns the rendered variables for a session of data.Stage.
+func (e Env) Vars(data Data) (map[string]string, error) {
+	vars := make(map[string]string, len(e.Global)+len(e.Stages[data.Stage]))
+	for _, values := range []map[string]string{e.Global, e.Stages[data.Stage]} {
+		for name, value := range values {
+			rendered, err := render(value, data)
+			if err != nil {
+				return nil, fmt.Errorf("env %s: %w", name, err)
+			}
+			vars[name] = rendered
+		}
+	}
+	return vars, nil
+}
+
+// Check validates one variable: its name must be a valid environment
+// variable name and its value a template that renders with Data.
+func Check(name, value string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid environment variable name %q (letters, digits and underscores, not starting with a digit)", name)
+	}
+	if _, err := render(value, Data{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+// IsStage reports whether name is a stage accepted as a stage_env key.
+func IsStage(name string) bool {
+	for _, stage := range Stages {
+		if stage == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the sorted variable names of a map, for stable validation
+// order and messages.
+func Names(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// render executes value as a template. Values without actions are returned
+// as is, so literal values never fail.
+func render(value string, data Data) (string, error) {
+	if !strings.Contains(value, "{{") {
+		return value, nil
+	}
+	tmpl, err := template.New("env").Option("missingkey=error").Parse(value)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
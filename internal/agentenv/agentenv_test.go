@@ -0,0 +1,91 @@
+package agentenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnv_Vars(t *testing.T) {
+	t.Parallel()
+
+	env := Env{
+		Global: map[string]string{
+			"DATABASE_URL": "postgres://localhost/app_test",
+			"SPEC":         "{{.SpecName}}",
+			"LITERAL":      "a}b{c",
+		},
+		Stages: map[string]map[string]string{
+			"implement": {"DATABASE_URL": "postgres://localhost/{{.SpecName}}_{{.TaskID}}"},
+		},
+	}
+
+	tests := map[string]struct {
+		data Data
+		want map[string]string
+	}{
+		"global only": {
+			data: Data{SpecName: "003-auth", Stage: "plan"},
+			want: map[string]string{
+				"DATABASE_URL": "postgres://localhost/app_test",
+				"SPEC":         "003-auth",
+				"LITERAL":      "a}b{c",
+			},
+		},
+		"stage override": {
+			data: Data{SpecName: "003-auth", Stage: "implement", TaskID: "T004"},
+			want: map[string]string{
+				"DATABASE_URL": "postgres://localhost/003-auth_T004",
+				"SPEC":         "003-auth",
+				"LITERAL":      "a}b{c",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := env.Vars(tt.data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnv_Empty(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, Env{}.Empty())
+	assert.False(t, Env{Stages: map[string]map[string]string{"plan": {"A": "1"}}}.Empty())
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name      string
+		value     string
+		wantErrIn string
+	}{
+		"valid literal":  {name: "DATABASE_URL", value: "postgres://x"},
+		"valid template": {name: "_PHASE", value: "phase-{{.Phase}}"},
+		"leading digit":  {name: "1X", value: "v", wantErrIn: "invalid environment variable name"},
+		"dash in name":   {name: "MY-VAR", value: "v", wantErrIn: "invalid environment variable name"},
+		"bad syntax":     {name: "X", value: "{{.SpecName", wantErrIn: "unclosed action"},
+		"unknown field":  {name: "X", value: "{{.Branch}}", wantErrIn: "Branch"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := Check(tt.name, tt.value)
+			if tt.wantErrIn == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErrIn)
+		})
+	}
+}
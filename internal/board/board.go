@@ -0,0 +1,220 @@
+// Package board implements the state, key handling and rendering of the
+// `autospec board` terminal kanban board. It does no terminal or file I/O:
+// the CLI feeds it key presses and tasks, draws what Render returns, and
+// carries out the Actions that HandleKey returns.
+package board
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// Columns are the board's task statuses, in display order.
+var Columns = []string{"Pending", "InProgress", "Blocked", "Completed"}
+
+// mode is what the board is showing on top of the columns.
+type mode int
+
+const (
+	modeBoard  mode = iota
+	modeDetail      // task detail popup
+	modeReason      // blocked reason prompt
+)
+
+// ActionKind identifies an Action.
+type ActionKind int
+
+const (
+	// ActionNone needs nothing beyond redrawing.
+	ActionNone ActionKind = iota
+	// ActionQuit closes the board.
+	ActionQuit
+	// ActionSetStatus sets TaskID's status to Status, with Reason.
+	ActionSetStatus
+	// ActionImplement runs an implement session for TaskID alone.
+	ActionImplement
+	// ActionReload rereads tasks.yaml.
+	ActionReload
+)
+
+// Action is a request from the board to the CLI.
+type Action struct {
+	Kind   ActionKind
+	TaskID string
+	Status string
+	Reason string
+}
+
+// Board is the state of a kanban board.
+type Board struct {
+	Title   string
+	Message string // one-line status shown in the footer
+
+	columns [][]validation.TaskItem
+	col     int
+	rows    []int // selected row per column
+	mode    mode
+	input   []rune
+}
+
+// New returns a board titled title showing tasks.
+func New(title string, tasks []validation.TaskItem) *Board {
+	b := &Board{Title: title, rows: make([]int, len(Columns))}
+	b.SetTasks(tasks)
+	return b
+}
+
+// SetTasks replaces the board's tasks, keeping the selected task selected
+// when it still exists.
+func (b *Board) SetTasks(tasks []validation.TaskItem) {
+	selected := ""
+	if t := b.Selected(); t != nil {
+		selected = t.ID
+	}
+	b.columns = make([][]validation.TaskItem, len(Columns))
+	for _, t := range tasks {
+		i := ColumnIndex(t.Status)
+		b.columns[i] = append(b.columns[i], t)
+	}
+	for i, tasks := range b.columns {
+		for row, t := range tasks {
+			if t.ID == selected {
+				b.col, b.rows[i] = i, row
+			}
+		}
+		b.rows[i] = min(b.rows[i], max(0, len(tasks)-1))
+	}
+}
+
+// ColumnIndex returns the column of a task status. Statuses are matched
+// case-insensitively; unknown ones are shown as Pending.
+func ColumnIndex(status string) int {
+	for i, c := range Columns {
+		if strings.EqualFold(status, c) {
+			return i
+		}
+	}
+	return 0
+}
+
+// Selected returns the selected task, or nil when its column is empty.
+func (b *Board) Selected() *validation.TaskItem {
+	if b.columns == nil || len(b.columns[b.col]) == 0 {
+		return nil
+	}
+	return &b.columns[b.col][b.rows[b.col]]
+}
+
+// HandleKey applies a key press and returns what the CLI should do next.
+func (b *Board) HandleKey(k Key) Action {
+	switch b.mode {
+	case modeDetail:
+		return b.handleDetailKey(k)
+	case modeReason:
+		return b.handleReasonKey(k)
+	}
+
+	b.Message = ""
+	switch {
+	case k.Code == KeyCtrlC || k.Rune == 'q':
+		return Action{Kind: ActionQuit}
+	case k.Code == KeyLeft || k.Rune == 'h':
+		b.col = (b.col + len(Columns) - 1) % len(Columns)
+	case k.Code == KeyRight || k.Rune == 'l':
+		b.col = (b.col + 1) % len(Columns)
+	case k.Code == KeyUp || k.Rune == 'k':
+		b.rows[b.col] = max(0, b.rows[b.col]-1)
+	case k.Code == KeyDown || k.Rune == 'j':
+		b.rows[b.col] = min(max(0, len(b.columns[b.col])-1), b.rows[b.col]+1)
+	case k.Rune == 'R':
+		return Action{Kind: ActionReload}
+	}
+
+	task := b.Selected()
+	if task == nil {
+		return Action{}
+	}
+	switch {
+	case k.Code == KeyEnter || k.Rune == ' ':
+		b.mode = modeDetail
+	case k.Rune == 'p':
+		return b.setStatus(task, "Pending")
+	case k.Rune == 'i':
+		return b.setStatus(task, "InProgress")
+	case k.Rune == 'c':
+		return b.setStatus(task, "Completed")
+	case k.Rune == 'b':
+		b.mode = modeReason
+		b.input = nil
+	case k.Rune == 'x':
+		return Action{Kind: ActionImplement, TaskID: task.ID}
+	}
+	return Action{}
+}
+
+// setStatus returns the action moving task to status, or nothing when it is
+// already there.
+func (b *Board) setStatus(task *validation.TaskItem, status string) Action {
+	if strings.EqualFold(task.Status, status) {
+		return Action{}
+	}
+	return Action{Kind: ActionSetStatus, TaskID: task.ID, Status: status, Reason: "moved on the board"}
+}
+
+func (b *Board) handleDetailKey(k Key) Action {
+	switch {
+	case k.Code == KeyCtrlC:
+		return Action{Kind: ActionQuit}
+	case k.Code == KeyEnter || k.Code == KeyEsc || k.Rune == ' ' || k.Rune == 'q':
+		b.mode = modeBoard
+	case k.Rune == 'x':
+		b.mode = modeBoard
+		if task := b.Selected(); task != nil {
+			return Action{Kind: ActionImplement, TaskID: task.ID}
+		}
+	}
+	return Action{}
+}
+
+func (b *Board) handleReasonKey(k Key) Action {
+	switch k.Code {
+	case KeyCtrlC:
+		return Action{Kind: ActionQuit}
+	case KeyEsc:
+		b.mode = modeBoard
+	case KeyBackspace:
+		if len(b.input) > 0 {
+			b.input = b.input[:len(b.input)-1]
+		}
+	case KeyEnter:
+		reason := strings.TrimSpace(string(b.input))
+		if reason == "" {
+			return Action{}
+		}
+		b.mode = modeBoard
+		if task := b.Selected(); task != nil {
+			return Action{Kind: ActionSetStatus, TaskID: task.ID, Status: "Blocked", Reason: reason}
+		}
+	case KeyNone:
+		if k.Rune != 0 {
+			b.input = append(b.input, k.Rune)
+		}
+	}
+	return Action{}
+}
+
+// footer returns the help or prompt line for the current mode.
+func (b *Board) footer() string {
+	switch b.mode {
+	case modeDetail:
+		return "enter/esc close  x implement  ctrl+c quit"
+	case modeReason:
+		return fmt.Sprintf("Blocked reason: %s_  (enter save, esc cancel)", string(b.input))
+	}
+	if b.Message != "" {
+		return b.Message
+	}
+	return "arrows/hjkl move  enter details  p/i/b/c status  x implement  R reload  q quit"
+}
@@ -0,0 +1,196 @@
+// Package board_test tests the kanban board's state, keys and rendering.
+// Related: internal/board/board.go, internal/board/keys.go, internal/board/render.go
+// Tags: board, tui, kanban, tasks
+package board
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleTasks() []validation.TaskItem {
+	return []validation.TaskItem{
+		{ID: "T001", Title: "Create models", Status: "Completed"},
+		{ID: "T002", Title: "Add service", Status: "InProgress"},
+		{ID: "T003", Title: "Wire handlers", Status: "Pending", Dependencies: []string{"T002"}},
+		{ID: "T004", Title: "Write docs", Status: "pending"},
+		{ID: "T005", Title: "Deploy", Status: "Blocked", BlockedReason: "no credentials"},
+	}
+}
+
+func runes(s string) []Key {
+	return ParseKeys([]byte(s))
+}
+
+func TestNew_GroupsByStatus(t *testing.T) {
+	t.Parallel()
+
+	b := New("spec", sampleTasks())
+	ids := func(col int) []string {
+		var out []string
+		for _, task := range b.columns[col] {
+			out = append(out, task.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"T003", "T004"}, ids(0))
+	assert.Equal(t, []string{"T002"}, ids(1))
+	assert.Equal(t, []string{"T005"}, ids(2))
+	assert.Equal(t, []string{"T001"}, ids(3))
+	require.NotNil(t, b.Selected())
+	assert.Equal(t, "T003", b.Selected().ID)
+}
+
+func TestHandleKey(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		keys         string
+		want         Action
+		wantSelected string
+	}{
+		"quit":                 {keys: "q", want: Action{Kind: ActionQuit}},
+		"move down":            {keys: "j", wantSelected: "T004"},
+		"move down clamps":     {keys: "jjj", wantSelected: "T004"},
+		"move right":           {keys: "l", wantSelected: "T002"},
+		"arrow keys":           {keys: "\x1b[C\x1b[C", wantSelected: "T005"},
+		"wrap left":            {keys: "h", wantSelected: "T001"},
+		"set in progress":      {keys: "i", want: Action{Kind: ActionSetStatus, TaskID: "T003", Status: "InProgress", Reason: "moved on the board"}},
+		"same status ignored":  {keys: "p", want: Action{}},
+		"implement":            {keys: "jx", want: Action{Kind: ActionImplement, TaskID: "T004"}},
+		"implement from popup": {keys: "\rx", want: Action{Kind: ActionImplement, TaskID: "T003"}},
+		"block with reason":    {keys: "bwaiting on API\r", want: Action{Kind: ActionSetStatus, TaskID: "T003", Status: "Blocked", Reason: "waiting on API"}},
+		"block reason edited":  {keys: "bAPIx\x7f\r", want: Action{Kind: ActionSetStatus, TaskID: "T003", Status: "Blocked", Reason: "API"}},
+		"block cancelled":      {keys: "bq\x1bq", want: Action{Kind: ActionQuit}},
+		"reload":               {keys: "R", want: Action{Kind: ActionReload}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			b := New("spec", sampleTasks())
+			var got Action
+			for _, k := range runes(tt.keys) {
+				got = b.HandleKey(k)
+			}
+			assert.Equal(t, tt.want, got)
+			if tt.wantSelected != "" {
+				assert.Equal(t, tt.wantSelected, b.Selected().ID)
+			}
+		})
+	}
+}
+
+func TestHandleKey_EmptyColumn(t *testing.T) {
+	t.Parallel()
+
+	b := New("spec", []validation.TaskItem{{ID: "T001", Title: "Only", Status: "Completed"}})
+	assert.Nil(t, b.Selected())
+	assert.Equal(t, Action{}, b.HandleKey(Key{Rune: 'x'}))
+}
+
+func TestSetTasks_KeepsSelection(t *testing.T) {
+	t.Parallel()
+
+	b := New("spec", sampleTasks())
+	b.HandleKey(Key{Rune: 'j'}) // T004
+	tasks := sampleTasks()
+	tasks[3].Status = "Completed"
+	b.SetTasks(tasks)
+	require.NotNil(t, b.Selected())
+	assert.Equal(t, "T004", b.Selected().ID)
+	assert.Equal(t, 3, b.col)
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+		want  []Key
+	}{
+		"characters":             {input: "aé", want: []Key{{Rune: 'a'}, {Rune: 'é'}}},
+		"arrows":                 {input: "\x1b[A\x1bOB", want: []Key{{Code: KeyUp}, {Code: KeyDown}}},
+		"lone escape":            {input: "\x1b", want: []Key{{Code: KeyEsc}}},
+		"other sequence dropped": {input: "\x1b[3~q", want: []Key{{Rune: 'q'}}},
+		"controls":               {input: "\r\x7f\x03", want: []Key{{Code: KeyEnter}, {Code: KeyBackspace}, {Code: KeyCtrlC}}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ParseKeys([]byte(tt.input)))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	b := New("003-feature", sampleTasks())
+	lines := b.Render(80, 12, false)
+
+	require.Len(t, lines, 12)
+	assert.Contains(t, lines[0], "003-feature  (1/5 tasks completed)")
+	assert.Contains(t, lines[2], "Pending (2)")
+	assert.Contains(t, lines[2], "Completed (1)")
+	assert.Contains(t, lines[4], ">T003 Wire handlers")
+	assert.Contains(t, lines[4], "T005 Deploy (no c")
+	for _, l := range lines {
+		assert.LessOrEqual(t, len([]rune(l)), 80)
+	}
+	assert.Contains(t, lines[len(lines)-1], "q quit")
+}
+
+func TestRender_Color(t *testing.T) {
+	t.Parallel()
+
+	b := New("spec", sampleTasks())
+	lines := b.Render(80, 12, true)
+	assert.Contains(t, lines[4], ansiReverse+">T003")
+}
+
+func TestRender_Scrolls(t *testing.T) {
+	t.Parallel()
+
+	var tasks []validation.TaskItem
+	for _, id := range []string{"T001", "T002", "T003", "T004", "T005"} {
+		tasks = append(tasks, validation.TaskItem{ID: id, Title: "task", Status: "Pending"})
+	}
+	b := New("spec", tasks)
+	for range 4 {
+		b.HandleKey(Key{Code: KeyDown})
+	}
+	lines := b.Render(80, 8, false) // 3 card lines
+	cards := strings.Join(lines[4:7], "\n")
+	assert.NotContains(t, cards, "T002")
+	assert.Contains(t, cards, ">T005")
+}
+
+func TestRender_Detail(t *testing.T) {
+	t.Parallel()
+
+	tasks := sampleTasks()
+	tasks[2].AcceptanceCriteria = []string{"Handlers return 404 for unknown IDs"}
+	b := New("spec", tasks)
+	b.HandleKey(Key{Code: KeyEnter})
+
+	out := strings.Join(b.Render(100, 24, true), "\n")
+	assert.Contains(t, out, "T003 Wire handlers")
+	assert.Contains(t, out, "Depends on: T002")
+	assert.Contains(t, out, "- Handlers return 404 for unknown IDs")
+	assert.NotContains(t, out, "\x1b[", "the popup is drawn without attributes")
+	assert.Contains(t, out, "enter/esc close")
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{"one two", "three"}, wrap("one two three", 8))
+	assert.Equal(t, []string{"abcd", "ef"}, wrap("abcdef", 4))
+	assert.Equal(t, []string{""}, wrap("", 4))
+}
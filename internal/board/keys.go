@@ -0,0 +1,79 @@
+package board
+
+import "unicode/utf8"
+
+// KeyCode identifies a non-character key.
+type KeyCode int
+
+const (
+	// KeyNone marks a character key; see Key.Rune.
+	KeyNone KeyCode = iota
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEsc
+	KeyBackspace
+	KeyCtrlC
+)
+
+// Key is one key press: a KeyCode, or a character in Rune.
+type Key struct {
+	Code KeyCode
+	Rune rune
+}
+
+// arrowKeys maps the final byte of arrow key escape sequences to keys.
+var arrowKeys = map[byte]KeyCode{'A': KeyUp, 'B': KeyDown, 'C': KeyRight, 'D': KeyLeft}
+
+// ParseKeys decodes the bytes read from a terminal in raw mode into key
+// presses. Escape sequences other than the arrow keys are dropped; an escape
+// byte that does not start a sequence is the Esc key.
+func ParseKeys(buf []byte) []Key {
+	var keys []Key
+	for len(buf) > 0 {
+		switch c := buf[0]; {
+		case c == 0x1b:
+			n, code := parseEscape(buf)
+			if code != KeyNone {
+				keys = append(keys, Key{Code: code})
+			}
+			buf = buf[n:]
+			continue
+		case c == '\r' || c == '\n':
+			keys = append(keys, Key{Code: KeyEnter})
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, Key{Code: KeyBackspace})
+		case c == 0x03:
+			keys = append(keys, Key{Code: KeyCtrlC})
+		case c < 0x20:
+			// other control characters are ignored
+		default:
+			r, size := utf8.DecodeRune(buf)
+			keys = append(keys, Key{Rune: r})
+			buf = buf[size:]
+			continue
+		}
+		buf = buf[1:]
+	}
+	return keys
+}
+
+// parseEscape decodes the escape sequence at the start of buf, returning its
+// length and key (KeyNone for unsupported sequences).
+func parseEscape(buf []byte) (int, KeyCode) {
+	if len(buf) < 2 || (buf[1] != '[' && buf[1] != 'O') {
+		return 1, KeyEsc
+	}
+	// CSI and SS3 sequences end with a byte in 0x40-0x7e.
+	for i := 2; i < len(buf); i++ {
+		if buf[i] >= 0x40 && buf[i] <= 0x7e {
+			if i == 2 {
+				return 3, arrowKeys[buf[i]]
+			}
+			return i + 1, KeyNone
+		}
+	}
+	return len(buf), KeyNone
+}
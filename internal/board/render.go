@@ -0,0 +1,219 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+const (
+	ansiBold    = "\x1b[1m"
+	ansiReverse = "\x1b[7m"
+	ansiReset   = "\x1b[0m"
+
+	// chromeLines are the lines around the cards: title, blank, column
+	// headers, rule and footer.
+	chromeLines = 5
+	// maxPopupWidth caps the detail popup on wide terminals.
+	maxPopupWidth = 76
+)
+
+// Render draws the board for a width x height terminal, one string per line.
+// Lines are at most width characters wide, plus ANSI attributes when color is
+// set. Characters are assumed to be one column wide.
+func (b *Board) Render(width, height int, color bool) []string {
+	if b.mode == modeDetail {
+		// The popup is drawn over plain lines, so attributes cannot be cut.
+		color = false
+	}
+	style := func(attr, s string) string {
+		if !color {
+			return s
+		}
+		return attr + s + ansiReset
+	}
+
+	n := len(Columns)
+	colWidth := max(1, (width-(n-1))/n)
+	visible := max(1, height-chromeLines)
+
+	total := 0
+	for _, tasks := range b.columns {
+		total += len(tasks)
+	}
+	done := len(b.columns[ColumnIndex("Completed")])
+
+	lines := []string{
+		style(ansiBold, fit(fmt.Sprintf("%s  (%d/%d tasks completed)", b.Title, done, total), width)),
+		"",
+	}
+
+	headers := make([]string, n)
+	rules := make([]string, n)
+	for i, name := range Columns {
+		header := fit(fmt.Sprintf("%s (%d)", name, len(b.columns[i])), colWidth)
+		if i == b.col {
+			header = style(ansiBold, header)
+		}
+		headers[i] = header
+		rules[i] = strings.Repeat("─", colWidth)
+	}
+	lines = append(lines, strings.Join(headers, "│"), strings.Join(rules, "┼"))
+
+	offsets := make([]int, n)
+	for i := range Columns {
+		offsets[i] = max(0, b.rows[i]-visible+1)
+	}
+	for row := 0; row < visible; row++ {
+		cells := make([]string, n)
+		for i, tasks := range b.columns {
+			idx := offsets[i] + row
+			if idx >= len(tasks) {
+				cells[i] = strings.Repeat(" ", colWidth)
+				continue
+			}
+			selected := i == b.col && idx == b.rows[i]
+			cells[i] = cardText(tasks[idx], selected, colWidth)
+			if selected {
+				cells[i] = style(ansiReverse, cells[i])
+			}
+		}
+		lines = append(lines, strings.Join(cells, "│"))
+	}
+	lines = append(lines, fit(b.footer(), width))
+
+	if b.mode == modeDetail {
+		if task := b.Selected(); task != nil {
+			overlay(lines, detailLines(*task, min(width-4, maxPopupWidth), height-2), width)
+		}
+	}
+	return lines
+}
+
+// cardText renders one task card line, marking the selected card.
+func cardText(t validation.TaskItem, selected bool, width int) string {
+	marker := " "
+	if selected {
+		marker = ">"
+	}
+	text := marker + t.ID + " " + t.Title
+	if strings.EqualFold(t.Status, "Blocked") && t.BlockedReason != "" {
+		text += " (" + t.BlockedReason + ")"
+	}
+	return fit(text, width)
+}
+
+// detailLines renders the detail popup of a task, boxed, at most width wide
+// and height tall.
+func detailLines(t validation.TaskItem, width, height int) []string {
+	inner := max(1, width-4)
+	var body []string
+	add := func(label, value string) {
+		if value == "" {
+			return
+		}
+		body = append(body, wrap(label+": "+value, inner)...)
+	}
+	body = append(body, wrap(t.ID+" "+t.Title, inner)...)
+	body = append(body, "")
+	add("Status", t.Status)
+	add("Blocked", t.BlockedReason)
+	add("Type", t.Type)
+	add("Story", t.StoryID)
+	add("File", t.FilePath)
+	add("Depends on", strings.Join(t.Dependencies, ", "))
+	add("Estimate", t.EstimatedDuration)
+	add("Actual", t.ActualDuration)
+	if t.Parallel {
+		add("Parallel", "yes")
+	}
+	if len(t.AcceptanceCriteria) > 0 {
+		body = append(body, "", "Acceptance criteria:")
+		for _, c := range t.AcceptanceCriteria {
+			for i, l := range wrap(c, inner-2) {
+				prefix := "  "
+				if i == 0 {
+					prefix = "- "
+				}
+				body = append(body, prefix+l)
+			}
+		}
+	}
+	if t.Notes != "" {
+		body = append(body, "", "Notes:")
+		body = append(body, wrap(t.Notes, inner)...)
+	}
+
+	if maxBody := max(1, height-2); len(body) > maxBody {
+		body = append(body[:maxBody-1], "…")
+	}
+	lines := []string{"┌" + strings.Repeat("─", inner+2) + "┐"}
+	for _, l := range body {
+		lines = append(lines, "│ "+fit(l, inner)+" │")
+	}
+	return append(lines, "└"+strings.Repeat("─", inner+2)+"┘")
+}
+
+// overlay draws box centered over lines, which must be plain text.
+func overlay(lines, box []string, width int) {
+	if len(box) == 0 {
+		return
+	}
+	top := max(0, (len(lines)-len(box))/2)
+	left := max(0, (width-len([]rune(box[0])))/2)
+	for i, b := range box {
+		if top+i >= len(lines) {
+			return
+		}
+		line := []rune(fit(lines[top+i], width))
+		boxRunes := []rune(b)
+		end := min(len(line), left+len(boxRunes))
+		lines[top+i] = string(line[:left]) + string(boxRunes[:end-left]) + string(line[end:])
+	}
+}
+
+// fit truncates s to width characters, ending it with "…" when cut, and pads
+// it with spaces to exactly width.
+func fit(s string, width int) string {
+	r := []rune(s)
+	if len(r) > width {
+		if width <= 0 {
+			return ""
+		}
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(r))
+}
+
+// wrap word-wraps s into lines of at most width characters, breaking words
+// longer than width.
+func wrap(s string, width int) []string {
+	width = max(1, width)
+	var lines []string
+	var line []rune
+	for _, word := range strings.Fields(s) {
+		w := []rune(word)
+		for len(w) > width {
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+			lines = append(lines, string(w[:width]))
+			w = w[width:]
+		}
+		switch {
+		case len(line) == 0:
+			line = w
+		case len(line)+1+len(w) <= width:
+			line = append(append(line, ' '), w...)
+		default:
+			lines = append(lines, string(line))
+			line = w
+		}
+	}
+	if len(line) > 0 || len(lines) == 0 {
+		lines = append(lines, string(line))
+	}
+	return lines
+}
@@ -0,0 +1,376 @@
+// Package budget enforces per-run and per-stage usage limits (wall-clock time,
+// estimated tokens, estimated cost) and a run-wide retry budget. The workflow
+// executor checks the budget before every agent session, so an exceeded budget
+// stops the run between sessions with completed stages and tasks intact;
+// rerunning the command resumes.
+package budget
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+)
+
+// bytesPerToken approximates token count from text length when the agent does
+// not report usage.
+const bytesPerToken = 4
+
+// maxLineBuffer bounds the partial-line buffer used to spot usage reports.
+const maxLineBuffer = 1 << 20
+
+// Config holds budget limits. Zero values disable the corresponding limit.
+type Config struct {
+	// MaxRunDuration limits wall-clock time for the whole command.
+	MaxRunDuration time.Duration `koanf:"max_run_duration" yaml:"max_run_duration" json:"max_run_duration"`
+
+	// MaxStageDuration limits agent time per stage, summed over retries, phases and tasks.
+	MaxStageDuration time.Duration `koanf:"max_stage_duration" yaml:"max_stage_duration" json:"max_stage_duration"`
+
+	// MaxRunTokens limits estimated tokens for the whole command.
+	MaxRunTokens int `koanf:"max_run_tokens" yaml:"max_run_tokens" json:"max_run_tokens"`
+
+	// MaxStageTokens limits estimated tokens per stage.
+	MaxStageTokens int `koanf:"max_stage_tokens" yaml:"max_stage_tokens" json:"max_stage_tokens"`
+
+	// MaxRunCostUSD limits estimated cost in USD for the whole command.
+	MaxRunCostUSD float64 `koanf:"max_run_cost_usd" yaml:"max_run_cost_usd" json:"max_run_cost_usd"`
+
+	// MaxStageCostUSD limits estimated cost in USD per stage.
+	MaxStageCostUSD float64 `koanf:"max_stage_cost_usd" yaml:"max_stage_cost_usd" json:"max_stage_cost_usd"`
+
+	// CostPerMillionTokens prices estimated tokens for sessions whose agent does
+	// not report its own cost. Zero counts those sessions as free.
+	CostPerMillionTokens float64 `koanf:"cost_per_million_tokens" yaml:"cost_per_million_tokens" json:"cost_per_million_tokens"`
+
+	// MaxRunRetries limits retries for the whole command, across all stages
+	// and tasks, on top of max_retries per stage.
+	MaxRunRetries int `koanf:"max_run_retries" yaml:"max_run_retries" json:"max_run_retries"`
+}
+
+// Enabled reports whether any limit is set.
+func (c Config) Enabled() bool {
+	return c.MaxRunDuration > 0 || c.MaxStageDuration > 0 ||
+		c.MaxRunTokens > 0 || c.MaxStageTokens > 0 ||
+		c.MaxRunCostUSD > 0 || c.MaxStageCostUSD > 0 ||
+		c.MaxRunRetries > 0
+}
+
+// Usage is accumulated consumption for a run or a stage.
+type Usage struct {
+	Duration time.Duration
+	Tokens   int
+	CostUSD  float64
+}
+
+// RetryUse is how many retries one stage, or one task of a stage, used.
+type RetryUse struct {
+	Scope string // "implement T003", or the stage name outside tasks
+	Count int
+}
+
+// ExceededError reports which budget ran out. The run stops before the next
+// agent session; completed work is kept so the command can be rerun to resume.
+type ExceededError struct {
+	Scope    string // "run" or "stage"
+	Stage    string // stage name for stage budgets
+	Resource string // "duration", "tokens", "cost" or "retries"
+	Used     string
+	Limit    string
+}
+
+func (e *ExceededError) Error() string {
+	scope := "run"
+	if e.Scope == "stage" {
+		scope = fmt.Sprintf("stage '%s'", e.Stage)
+	}
+	return fmt.Sprintf("budget exceeded: %s %s %s reached limit %s; stopped before the next agent session, rerun to resume",
+		scope, e.Resource, e.Used, e.Limit)
+}
+
+// Is classifies the error as apperrors.ErrBudgetExceeded.
+func (e *ExceededError) Is(target error) bool {
+	return target == apperrors.ErrBudgetExceeded
+}
+
+// Tracker accumulates usage and checks it against the configured limits.
+// A nil *Tracker is valid: it records nothing and never reports an exceeded budget.
+type Tracker struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	start   time.Time
+	run     Usage
+	stages  map[string]*Usage
+	session *session
+	retries map[string]int // retry scope -> retries used
+}
+
+// session holds the in-flight agent session.
+type session struct {
+	stage       string
+	start       time.Time
+	promptBytes int
+	outputBytes int
+	line        []byte
+	reported    *reportedUsage
+}
+
+// reportedUsage is what the agent itself reported for a session.
+type reportedUsage struct {
+	tokens  int
+	costUSD float64
+	hasCost bool
+}
+
+// New creates a tracker starting the run clock now.
+// Returns nil when no limit is configured.
+func New(cfg Config) *Tracker {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return newTracker(cfg, time.Now)
+}
+
+func newTracker(cfg Config, now func() time.Time) *Tracker {
+	return &Tracker{
+		cfg:     cfg,
+		now:     now,
+		start:   now(),
+		stages:  make(map[string]*Usage),
+		retries: make(map[string]int),
+	}
+}
+
+// Check returns an *ExceededError when the run or stage budget is used up.
+// Call before starting an agent session for stage.
+func (t *Tracker) Check(stage string) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if lim := t.cfg.MaxRunDuration; lim > 0 {
+		if elapsed := t.now().Sub(t.start); elapsed >= lim {
+			return exceeded("run", "", "duration", formatDuration(elapsed), formatDuration(lim))
+		}
+	}
+	if err := checkUsage("run", "", t.run, t.cfg.MaxRunTokens, t.cfg.MaxRunCostUSD); err != nil {
+		return err
+	}
+
+	stageUsage := t.stageUsage(stage)
+	if lim := t.cfg.MaxStageDuration; lim > 0 && stageUsage.Duration >= lim {
+		return exceeded("stage", stage, "duration", formatDuration(stageUsage.Duration), formatDuration(lim))
+	}
+	return checkUsage("stage", stage, *stageUsage, t.cfg.MaxStageTokens, t.cfg.MaxStageCostUSD)
+}
+
+func checkUsage(scope, stage string, u Usage, maxTokens int, maxCost float64) error {
+	if maxTokens > 0 && u.Tokens >= maxTokens {
+		return exceeded(scope, stage, "tokens", fmt.Sprintf("~%d", u.Tokens), fmt.Sprintf("%d", maxTokens))
+	}
+	if maxCost > 0 && u.CostUSD >= maxCost {
+		return exceeded(scope, stage, "cost", fmt.Sprintf("~$%.2f", u.CostUSD), fmt.Sprintf("$%.2f", maxCost))
+	}
+	return nil
+}
+
+func exceeded(scope, stage, resource, used, limit string) *ExceededError {
+	return &ExceededError{Scope: scope, Stage: stage, Resource: resource, Used: used, Limit: limit}
+}
+
+// UseRetry takes one retry from the run's retry budget for scope, a stage
+// name or "<stage> <task>". Returns an *ExceededError, without counting the
+// retry, once max_run_retries retries have been used.
+func (t *Tracker) UseRetry(scope string) error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	used := 0
+	for _, n := range t.retries {
+		used += n
+	}
+	if lim := t.cfg.MaxRunRetries; lim > 0 && used >= lim {
+		return exceeded("run", "", "retries", fmt.Sprintf("%d", used), fmt.Sprintf("%d", lim))
+	}
+	t.retries[scope]++
+	return nil
+}
+
+// Retries returns where the run's retries went, most retries first.
+func (t *Tracker) Retries() []RetryUse {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	uses := make([]RetryUse, 0, len(t.retries))
+	for scope, n := range t.retries {
+		uses = append(uses, RetryUse{Scope: scope, Count: n})
+	}
+	sort.Slice(uses, func(i, j int) bool {
+		if uses[i].Count != uses[j].Count {
+			return uses[i].Count > uses[j].Count
+		}
+		return uses[i].Scope < uses[j].Scope
+	})
+	return uses
+}
+
+// StartSession begins accounting for an agent session running prompt for stage.
+func (t *Tracker) StartSession(stage, prompt string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.session = &session{stage: stage, start: t.now(), promptBytes: len(prompt)}
+}
+
+// EndSession adds the active session's duration, tokens and cost to the stage and run totals.
+func (t *Tracker) EndSession() {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s := t.session
+	if s == nil {
+		return
+	}
+	t.session = nil
+
+	u := Usage{Duration: t.now().Sub(s.start)}
+	if s.reported != nil {
+		u.Tokens = s.reported.tokens
+	} else {
+		u.Tokens = (s.promptBytes + s.outputBytes + bytesPerToken - 1) / bytesPerToken
+	}
+	if s.reported != nil && s.reported.hasCost {
+		u.CostUSD = s.reported.costUSD
+	} else {
+		u.CostUSD = float64(u.Tokens) * t.cfg.CostPerMillionTokens / 1_000_000
+	}
+
+	stageUsage := t.stageUsage(s.stage)
+	stageUsage.Duration += u.Duration
+	stageUsage.Tokens += u.Tokens
+	stageUsage.CostUSD += u.CostUSD
+	t.run.Tokens += u.Tokens
+	t.run.CostUSD += u.CostUSD
+}
+
+// Run returns the usage so far for the whole run. Duration is wall-clock time since start.
+func (t *Tracker) Run() Usage {
+	if t == nil {
+		return Usage{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	u := t.run
+	u.Duration = t.now().Sub(t.start)
+	return u
+}
+
+// Stage returns the usage so far for stage.
+func (t *Tracker) Stage(stage string) Usage {
+	if t == nil {
+		return Usage{}
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return *t.stageUsage(stage)
+}
+
+// stageUsage returns the mutable usage for stage. Caller must hold t.mu.
+func (t *Tracker) stageUsage(stage string) *Usage {
+	u, ok := t.stages[stage]
+	if !ok {
+		u = &Usage{}
+		t.stages[stage] = u
+	}
+	return u
+}
+
+// Writer returns a writer that counts agent output toward the active session.
+// Stream-json "result" lines carrying usage or total_cost_usd replace the
+// byte-based estimate. Returns nil for a nil tracker.
+func (t *Tracker) Writer() io.Writer {
+	if t == nil {
+		return nil
+	}
+	return &outputCounter{t: t}
+}
+
+type outputCounter struct {
+	t *Tracker
+}
+
+func (o *outputCounter) Write(p []byte) (int, error) {
+	o.t.mu.Lock()
+	defer o.t.mu.Unlock()
+	s := o.t.session
+	if s == nil {
+		return len(p), nil
+	}
+	s.outputBytes += len(p)
+
+	s.line = append(s.line, p...)
+	for {
+		idx := bytes.IndexByte(s.line, '\n')
+		if idx < 0 {
+			break
+		}
+		if r := parseResultLine(s.line[:idx]); r != nil {
+			s.reported = r
+		}
+		s.line = s.line[idx+1:]
+	}
+	if len(s.line) > maxLineBuffer {
+		s.line = nil
+	}
+	return len(p), nil
+}
+
+// resultMessage is the subset of a stream-json result message carrying usage.
+type resultMessage struct {
+	Type         string   `json:"type"`
+	TotalCostUSD *float64 `json:"total_cost_usd"`
+	Usage        *struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// parseResultLine extracts agent-reported usage from a stream-json result line.
+func parseResultLine(line []byte) *reportedUsage {
+	line = bytes.TrimSpace(line)
+	if !bytes.HasPrefix(line, []byte("{")) || !bytes.Contains(line, []byte(`"result"`)) {
+		return nil
+	}
+	var msg resultMessage
+	if err := json.Unmarshal(line, &msg); err != nil || msg.Type != "result" || msg.Usage == nil {
+		return nil
+	}
+	r := &reportedUsage{tokens: msg.Usage.InputTokens + msg.Usage.OutputTokens}
+	if msg.TotalCostUSD != nil {
+		r.costUSD = *msg.TotalCostUSD
+		r.hasCost = true
+	}
+	return r
+}
+
+// formatDuration renders a duration rounded to the second.
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Second).String()
+}
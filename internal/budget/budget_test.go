@@ -0,0 +1,212 @@
+package budget
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a manually advanced clock for deterministic durations.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+func newTestTracker(cfg Config) (*Tracker, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return newTracker(cfg, clock.Now), clock
+}
+
+// runSession simulates one agent session writing output over d.
+func runSession(t *Tracker, clock *fakeClock, stage, prompt, output string, d time.Duration) {
+	t.StartSession(stage, prompt)
+	_, _ = t.Writer().Write([]byte(output))
+	clock.Advance(d)
+	t.EndSession()
+}
+
+func TestNew_DisabledWithoutLimits(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, New(Config{}))
+	assert.Nil(t, New(Config{CostPerMillionTokens: 3}), "pricing alone sets no limit")
+	assert.NotNil(t, New(Config{MaxRunTokens: 1000}))
+	assert.NotNil(t, New(Config{MaxRunRetries: 5}))
+}
+
+func TestTracker_NilSafe(t *testing.T) {
+	t.Parallel()
+
+	var tr *Tracker
+	tr.StartSession("plan", "prompt")
+	tr.EndSession()
+	assert.NoError(t, tr.Check("plan"))
+	assert.Nil(t, tr.Writer())
+	assert.Equal(t, Usage{}, tr.Run())
+	assert.Equal(t, Usage{}, tr.Stage("plan"))
+	assert.NoError(t, tr.UseRetry("plan"))
+	assert.Nil(t, tr.Retries())
+}
+
+func TestTracker_EstimatesTokensFromBytes(t *testing.T) {
+	t.Parallel()
+
+	tr, clock := newTestTracker(Config{MaxRunTokens: 1000, CostPerMillionTokens: 10})
+	runSession(tr, clock, "plan", strings.Repeat("p", 40), strings.Repeat("o", 80), time.Minute)
+
+	stage := tr.Stage("plan")
+	assert.Equal(t, 30, stage.Tokens)
+	assert.Equal(t, time.Minute, stage.Duration)
+	assert.InDelta(t, 0.0003, stage.CostUSD, 1e-9)
+	assert.Equal(t, 30, tr.Run().Tokens)
+}
+
+func TestTracker_PrefersReportedUsage(t *testing.T) {
+	t.Parallel()
+
+	tr, clock := newTestTracker(Config{MaxRunTokens: 100000})
+	output := `{"type":"assistant","message":{"content":"working"}}` + "\n" +
+		`{"type":"result","subtype":"success","total_cost_usd":0.42,"usage":{"input_tokens":1200,"output_tokens":300}}` + "\n"
+
+	// Deliver the result line in two writes to exercise line buffering
+	tr.StartSession("tasks", "/autospec.tasks")
+	w := tr.Writer()
+	_, _ = w.Write([]byte(output[:70]))
+	_, _ = w.Write([]byte(output[70:]))
+	clock.Advance(time.Second)
+	tr.EndSession()
+
+	stage := tr.Stage("tasks")
+	assert.Equal(t, 1500, stage.Tokens)
+	assert.InDelta(t, 0.42, stage.CostUSD, 1e-9)
+}
+
+func TestTracker_Check(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg          Config
+		sessions     int
+		elapsed      time.Duration
+		checkStage   string
+		wantScope    string
+		wantResource string
+	}{
+		"within budget": {
+			cfg:        Config{MaxRunTokens: 1000, MaxStageDuration: time.Hour},
+			sessions:   1,
+			checkStage: "implement",
+		},
+		"run tokens": {
+			cfg:          Config{MaxRunTokens: 50},
+			sessions:     2,
+			checkStage:   "plan",
+			wantScope:    "run",
+			wantResource: "tokens",
+		},
+		"stage tokens only counts that stage": {
+			cfg:        Config{MaxStageTokens: 50},
+			sessions:   2,
+			checkStage: "plan",
+		},
+		"stage tokens": {
+			cfg:          Config{MaxStageTokens: 50},
+			sessions:     2,
+			checkStage:   "implement",
+			wantScope:    "stage",
+			wantResource: "tokens",
+		},
+		"stage duration": {
+			cfg:          Config{MaxStageDuration: 15 * time.Minute},
+			sessions:     2,
+			checkStage:   "implement",
+			wantScope:    "stage",
+			wantResource: "duration",
+		},
+		"run duration includes time between sessions": {
+			cfg:          Config{MaxRunDuration: 30 * time.Minute},
+			sessions:     1,
+			elapsed:      25 * time.Minute,
+			checkStage:   "implement",
+			wantScope:    "run",
+			wantResource: "duration",
+		},
+		"run cost": {
+			cfg:          Config{MaxRunCostUSD: 0.0005, CostPerMillionTokens: 10},
+			sessions:     2,
+			checkStage:   "implement",
+			wantScope:    "run",
+			wantResource: "cost",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			tr, clock := newTestTracker(tc.cfg)
+			for i := 0; i < tc.sessions; i++ {
+				runSession(tr, clock, "implement", fmt.Sprintf("task %d", i), strings.Repeat("x", 120), 10*time.Minute)
+			}
+			clock.Advance(tc.elapsed)
+
+			err := tr.Check(tc.checkStage)
+			if tc.wantScope == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var exceededErr *ExceededError
+			require.True(t, errors.As(err, &exceededErr), "expected ExceededError, got %v", err)
+			assert.Equal(t, tc.wantScope, exceededErr.Scope)
+			assert.Equal(t, tc.wantResource, exceededErr.Resource)
+		})
+	}
+}
+
+func TestExceededError_Message(t *testing.T) {
+	t.Parallel()
+
+	tr, clock := newTestTracker(Config{MaxStageTokens: 10})
+	runSession(tr, clock, "implement", "", strings.Repeat("x", 400), time.Minute)
+
+	err := tr.Check("implement")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stage 'implement' tokens ~100 reached limit 10")
+	assert.Contains(t, err.Error(), "rerun to resume")
+	assert.ErrorIs(t, err, apperrors.ErrBudgetExceeded)
+}
+
+func TestTracker_UseRetry(t *testing.T) {
+	t.Parallel()
+
+	tr, _ := newTestTracker(Config{MaxRunRetries: 3})
+	require.NoError(t, tr.UseRetry("implement T003"))
+	require.NoError(t, tr.UseRetry("plan"))
+	require.NoError(t, tr.UseRetry("implement T003"))
+
+	err := tr.UseRetry("implement T004")
+	var exceededErr *ExceededError
+	require.ErrorAs(t, err, &exceededErr)
+	assert.Equal(t, "retries", exceededErr.Resource)
+	assert.Contains(t, err.Error(), "run retries 3 reached limit 3")
+	assert.Equal(t, []RetryUse{{Scope: "implement T003", Count: 2}, {Scope: "plan", Count: 1}}, tr.Retries(),
+		"a refused retry is not counted")
+}
+
+func TestTracker_WriterIgnoresOutputOutsideSession(t *testing.T) {
+	t.Parallel()
+
+	tr, _ := newTestTracker(Config{MaxRunTokens: 10})
+	n, err := tr.Writer().Write([]byte("stray output"))
+	require.NoError(t, err)
+	assert.Equal(t, 12, n)
+	assert.Equal(t, 0, tr.Run().Tokens)
+}
@@ -0,0 +1,506 @@
+// Package bundle exports a spec to a portable .tar.gz and imports it into
+// another repository.
+//
+// A bundle holds the spec directory (artifacts, transcripts, links), the
+// spec's execution checkpoints from retry.json, and the spec's entries from
+// the run event logs, plus a manifest recording the bundle format and the
+// _meta.version of each artifact. On import, a spec whose number is already
+// taken is renumbered, and references to its old name are rewritten.
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/build"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/retry"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// FormatVersion is bumped on incompatible changes to the bundle layout.
+const FormatVersion = 1
+
+// Paths inside a bundle.
+const (
+	manifestName    = "manifest.json"
+	checkpointsName = "state/checkpoints.json"
+	specPrefix      = "spec/"
+	runsPrefix      = "runs/"
+)
+
+// archiveLimits bound what an imported bundle may hold, guarding against
+// decompression bombs.
+type archiveLimits struct {
+	entrySize int64 // bytes per file
+	totalSize int64 // bytes across all files
+	entries   int   // tar entries of any type
+}
+
+// importLimits are the limits Import applies.
+var importLimits = archiveLimits{entrySize: 64 << 20, totalSize: 256 << 20, entries: 10000}
+
+// specNamePattern splits a spec directory name into number and suffix.
+var specNamePattern = regexp.MustCompile(`^(\d{3})-(.+)$`)
+
+// Manifest describes a bundle's contents.
+type Manifest struct {
+	FormatVersion   int            `json:"format_version"`
+	AutospecVersion string         `json:"autospec_version"`
+	CreatedAt       time.Time      `json:"created_at"`
+	Spec            string         `json:"spec"`
+	Artifacts       []ArtifactInfo `json:"artifacts"`
+	Files           []string       `json:"files"`
+	Runs            []string       `json:"runs,omitempty"`
+	Checkpoints     bool           `json:"checkpoints"`
+}
+
+// ArtifactInfo records the schema version of a bundled artifact.
+type ArtifactInfo struct {
+	File          string `json:"file"`
+	SchemaVersion string `json:"schema_version"`
+}
+
+// checkpoints is the spec's slice of retry.json.
+type checkpoints struct {
+	Retries []*retry.RetryState        `json:"retries,omitempty"`
+	Stage   *retry.StageExecutionState `json:"stage,omitempty"`
+	Task    *retry.TaskExecutionState  `json:"task,omitempty"`
+}
+
+func (c *checkpoints) empty() bool {
+	return len(c.Retries) == 0 && c.Stage == nil && c.Task == nil
+}
+
+// Export writes the spec in specDir, with its checkpoints and run events
+// from stateDir, as a gzipped tar to w.
+func Export(w io.Writer, specDir, stateDir string) (*Manifest, error) {
+	specName := filepath.Base(specDir)
+	m := &Manifest{
+		FormatVersion:   FormatVersion,
+		AutospecVersion: build.Version,
+		CreatedAt:       time.Now().UTC(),
+		Spec:            specName,
+	}
+
+	upgrades, err := validation.UpgradeSpecDir(specDir, true)
+	if err != nil {
+		return nil, fmt.Errorf("reading artifact versions: %w", err)
+	}
+	for _, u := range upgrades {
+		m.Artifacts = append(m.Artifacts, ArtifactInfo{File: filepath.Base(u.Path), SchemaVersion: u.FromVersion})
+	}
+
+	files := map[string][]byte{}
+	err = filepath.WalkDir(specDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(specDir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		name := specPrefix + filepath.ToSlash(rel)
+		files[name] = data
+		m.Files = append(m.Files, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading spec directory: %w", err)
+	}
+
+	cp, err := loadCheckpoints(stateDir, specName)
+	if err != nil {
+		return nil, err
+	}
+	if !cp.empty() {
+		data, err := json.MarshalIndent(cp, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("encoding checkpoints: %w", err)
+		}
+		files[checkpointsName] = data
+		m.Checkpoints = true
+	}
+
+	runs, err := specRunEvents(stateDir, specName)
+	if err != nil {
+		return nil, err
+	}
+	for runID, data := range runs {
+		files[runsPrefix+runID+"/"+events.EventsFileName] = data
+		m.Runs = append(m.Runs, runID)
+	}
+	slices.Sort(m.Runs)
+
+	return m, writeArchive(w, m, files)
+}
+
+// loadCheckpoints collects the spec's retry counts and stage/task progress.
+func loadCheckpoints(stateDir, specName string) (*checkpoints, error) {
+	retries, err := retry.ListRetryStates(stateDir, specName)
+	if err != nil {
+		return nil, fmt.Errorf("reading checkpoints: %w", err)
+	}
+	stage, _ := retry.LoadStageState(stateDir, specName)
+	task, _ := retry.LoadTaskState(stateDir, specName)
+	return &checkpoints{Retries: retries, Stage: stage, Task: task}, nil
+}
+
+// specRunEvents returns, per run, the JSONL of the events about specName.
+// Runs without such events are left out.
+func specRunEvents(stateDir, specName string) (map[string][]byte, error) {
+	runs, err := events.ListRuns(stateDir)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string][]byte{}
+	for _, run := range runs {
+		evs, err := events.Load(stateDir, run.RunID)
+		if err != nil && !encrypt.Partial(err) {
+			continue
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, ev := range evs {
+			if ev.Spec != specName {
+				continue
+			}
+			if err := enc.Encode(ev); err != nil {
+				return nil, fmt.Errorf("encoding events: %w", err)
+			}
+		}
+		if buf.Len() > 0 {
+			out[run.RunID] = buf.Bytes()
+		}
+	}
+	return out, nil
+}
+
+// writeArchive writes the manifest first, then files in name order.
+func writeArchive(w io.Writer, m *Manifest, files map[string][]byte) error {
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(data)), ModTime: m.CreatedAt, Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := add(manifestName, append(manifest, '\n')); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	for _, name := range names {
+		if err := add(name, files[name]); err != nil {
+			return fmt.Errorf("writing bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	return nil
+}
+
+// ImportOptions controls what Import restores.
+type ImportOptions struct {
+	// SpecConfig also imports the spec's .autospec.yaml, which changes how
+	// commands run on the spec. It is skipped by default.
+	SpecConfig bool
+}
+
+// ImportResult describes an imported bundle.
+type ImportResult struct {
+	Manifest          *Manifest
+	SpecName          string // Name the spec was imported as
+	SpecDir           string
+	Renumbered        bool     // SpecName differs from Manifest.Spec
+	Outdated          []string // Artifacts older than the current schema version
+	Checkpoints       bool     // Checkpoints were restored
+	Runs              []string // Run event logs that were added
+	SpecConfig        bool     // The bundle's .autospec.yaml was imported
+	SkippedSpecConfig bool     // The bundle has a .autospec.yaml that was not imported
+}
+
+// Import extracts the bundle read from r into specsDir, restoring its
+// checkpoints and run events into stateDir. The spec gets the next free
+// number when its number is already used in specsDir.
+func Import(r io.Reader, specsDir, stateDir string, opts ImportOptions) (*ImportResult, error) {
+	files, err := readArchive(r, importLimits)
+	if err != nil {
+		return nil, err
+	}
+	m, err := readManifest(files)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ImportResult{Manifest: m, SpecName: m.Spec}
+	if taken(specsDir, m.Spec) {
+		num, err := spec.GetNextBranchNumber(specsDir)
+		if err != nil {
+			return nil, fmt.Errorf("finding a free spec number: %w", err)
+		}
+		res.SpecName = spec.FormatBranchName(num, specNamePattern.FindStringSubmatch(m.Spec)[2])
+		res.Renumbered = true
+	}
+
+	if err := os.MkdirAll(specsDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating specs directory: %w", err)
+	}
+	tmpDir, err := os.MkdirTemp(specsDir, ".import-")
+	if err != nil {
+		return nil, fmt.Errorf("creating import directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for name, data := range files {
+		rel, ok := strings.CutPrefix(name, specPrefix)
+		if !ok {
+			continue
+		}
+		if rel == config.SpecConfigFile {
+			if !opts.SpecConfig {
+				res.SkippedSpecConfig = true
+				continue
+			}
+			res.SpecConfig = true
+		}
+		if res.Renumbered && strings.HasSuffix(rel, ".yaml") {
+			data = bytes.ReplaceAll(data, []byte(m.Spec), []byte(res.SpecName))
+		}
+		dest := filepath.Join(tmpDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return nil, fmt.Errorf("extracting %s: %w", rel, err)
+		}
+		if err := os.WriteFile(dest, data, 0o644); err != nil {
+			return nil, fmt.Errorf("extracting %s: %w", rel, err)
+		}
+	}
+
+	// A dry-run upgrade rejects artifacts newer than this autospec supports
+	upgrades, err := validation.UpgradeSpecDir(tmpDir, true)
+	if err != nil {
+		return nil, fmt.Errorf("bundle artifacts: %w", err)
+	}
+	for _, u := range upgrades {
+		if u.NeedsUpgrade() {
+			res.Outdated = append(res.Outdated, filepath.Base(u.Path))
+		}
+	}
+
+	res.SpecDir = filepath.Join(specsDir, res.SpecName)
+	if err := os.Chmod(tmpDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating spec directory: %w", err)
+	}
+	if err := os.Rename(tmpDir, res.SpecDir); err != nil {
+		return nil, fmt.Errorf("creating spec directory: %w", err)
+	}
+
+	if err := restoreCheckpoints(files, stateDir, res); err != nil {
+		return res, err
+	}
+	if err := restoreRuns(files, stateDir, res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// readArchive reads every regular file of a gzipped tar, rejecting paths
+// that would escape the extraction directory and archives over limits.
+func readArchive(r io.Reader, limits archiveLimits) (map[string][]byte, error) {
+	gz, err := gzip.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, fmt.Errorf("not a bundle (gzip): %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	files := map[string][]byte{}
+	var total int64
+	for entries := 0; ; entries++ {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle: %w", err)
+		}
+		if entries >= limits.entries {
+			return nil, fmt.Errorf("bundle has more than %d entries", limits.entries)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return nil, fmt.Errorf("bundle entry %q escapes the bundle", hdr.Name)
+		}
+		if hdr.Size > limits.entrySize {
+			return nil, fmt.Errorf("bundle entry %s is too large (%d bytes)", name, hdr.Size)
+		}
+		if total += hdr.Size; total > limits.totalSize {
+			return nil, fmt.Errorf("bundle is larger than %d bytes", limits.totalSize)
+		}
+		data, err := io.ReadAll(io.LimitReader(tr, limits.entrySize))
+		if err != nil {
+			return nil, fmt.Errorf("reading bundle entry %s: %w", name, err)
+		}
+		files[name] = data
+	}
+}
+
+func readManifest(files map[string][]byte) (*Manifest, error) {
+	data, ok := files[manifestName]
+	if !ok {
+		return nil, fmt.Errorf("bundle has no %s", manifestName)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", manifestName, err)
+	}
+	if m.FormatVersion > FormatVersion {
+		return nil, fmt.Errorf("bundle format %d is newer than this autospec supports (%d); upgrade autospec", m.FormatVersion, FormatVersion)
+	}
+	if !specNamePattern.MatchString(m.Spec) || m.Spec != filepath.Base(m.Spec) {
+		return nil, fmt.Errorf("bundle spec name %q is not of the form NNN-name", m.Spec)
+	}
+	for _, runID := range m.Runs {
+		if !events.ValidRunID(runID) {
+			return nil, fmt.Errorf("bundle run ID %q is not a plain name (letters, digits, '-' and '_')", runID)
+		}
+	}
+	return &m, nil
+}
+
+// taken reports whether specsDir already has a spec with the number of specName.
+func taken(specsDir, specName string) bool {
+	prefix := specNamePattern.FindStringSubmatch(specName)[1] + "-"
+	entries, err := os.ReadDir(specsDir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreCheckpoints saves the bundled checkpoints under the imported name.
+func restoreCheckpoints(files map[string][]byte, stateDir string, res *ImportResult) error {
+	data, ok := files[checkpointsName]
+	if !ok {
+		return nil
+	}
+	var cp checkpoints
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return fmt.Errorf("parsing checkpoints: %w", err)
+	}
+	for _, r := range cp.Retries {
+		r.SpecName = res.SpecName
+		if err := retry.SaveRetryState(stateDir, r); err != nil {
+			return fmt.Errorf("restoring checkpoints: %w", err)
+		}
+	}
+	if cp.Stage != nil {
+		cp.Stage.SpecName = res.SpecName
+		if err := retry.SaveStageState(stateDir, cp.Stage); err != nil {
+			return fmt.Errorf("restoring checkpoints: %w", err)
+		}
+	}
+	if cp.Task != nil {
+		cp.Task.SpecName = res.SpecName
+		if err := retry.SaveTaskState(stateDir, cp.Task); err != nil {
+			return fmt.Errorf("restoring checkpoints: %w", err)
+		}
+	}
+	res.Checkpoints = true
+	return nil
+}
+
+// restoreRuns adds the bundled run event logs. Runs that already exist in
+// stateDir are left alone, so importing a bundle twice adds nothing.
+func restoreRuns(files map[string][]byte, stateDir string, res *ImportResult) error {
+	for _, runID := range res.Manifest.Runs {
+		// readManifest rejects such IDs; never join one into a path regardless
+		data, ok := files[runsPrefix+runID+"/"+events.EventsFileName]
+		if !ok || !events.ValidRunID(runID) {
+			continue
+		}
+		dest := events.Path(stateDir, runID)
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+		data, last := rewriteEvents(data, res.Manifest.Spec, res.SpecName)
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return fmt.Errorf("restoring run %s: %w", runID, err)
+		}
+		if err := os.WriteFile(dest, data, 0o644); err != nil {
+			return fmt.Errorf("restoring run %s: %w", runID, err)
+		}
+		// Date the log by its last event so imported runs don't become the latest run
+		if !last.IsZero() {
+			_ = os.Chtimes(dest, last, last)
+		}
+		res.Runs = append(res.Runs, runID)
+	}
+	return nil
+}
+
+// rewriteEvents renames the spec in each JSONL event (a no-op when from and
+// to are equal) and returns the time of the last event.
+func rewriteEvents(data []byte, from, to string) ([]byte, time.Time) {
+	var buf bytes.Buffer
+	var last time.Time
+	enc := json.NewEncoder(&buf)
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var ev events.Event
+		if len(bytes.TrimSpace(line)) == 0 || json.Unmarshal(line, &ev) != nil {
+			continue
+		}
+		if ev.Spec == from {
+			ev.Spec = to
+		}
+		if ev.Time.After(last) {
+			last = ev.Time
+		}
+		_ = enc.Encode(ev)
+	}
+	return buf.Bytes(), last
+}
@@ -0,0 +1,251 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bundleSpecYAML = `_meta:
+  version: "1.1.0"
+  artifact_type: "spec"
+feature:
+  branch: "003-dark-mode"
+  created: "2025-01-01"
+  status: "Draft"
+  input: "Add dark mode"
+`
+
+// newExportFixture creates a spec with checkpoints and two runs, one of
+// which is about another spec.
+func newExportFixture(t *testing.T) (specDir, stateDir string) {
+	t.Helper()
+	root := t.TempDir()
+	specDir = filepath.Join(root, "specs", "003-dark-mode")
+	stateDir = filepath.Join(root, "state")
+	require.NoError(t, os.MkdirAll(filepath.Join(specDir, "checklists"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(bundleSpecYAML), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "checklists", "ux.yaml"), []byte("items: []\n"), 0o644))
+
+	require.NoError(t, retry.SaveRetryState(stateDir, &retry.RetryState{SpecName: "003-dark-mode", Phase: "plan", Count: 2, MaxRetries: 3}))
+	require.NoError(t, retry.SaveRetryState(stateDir, &retry.RetryState{SpecName: "009-other", Phase: "plan", Count: 1, MaxRetries: 3}))
+	require.NoError(t, retry.SaveTaskState(stateDir, &retry.TaskExecutionState{SpecName: "003-dark-mode", CompletedTaskIDs: []string{"T001"}, TotalTasks: 4}))
+
+	events.NewRecorder(stateDir, "calm_owl_20250101_100000").Record(events.Event{Type: events.TypeStageStart, Spec: "003-dark-mode", Stage: "plan"})
+	events.NewRecorder(stateDir, "calm_owl_20250101_100000").Record(events.Event{Type: events.TypeStageStart, Spec: "009-other", Stage: "plan"})
+	events.NewRecorder(stateDir, "bold_cat_20250102_100000").Record(events.Event{Type: events.TypeStageStart, Spec: "009-other", Stage: "tasks"})
+	return specDir, stateDir
+}
+
+func TestExportImport_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	specDir, stateDir := newExportFixture(t)
+	var buf bytes.Buffer
+	m, err := Export(&buf, specDir, stateDir)
+	require.NoError(t, err)
+	assert.Equal(t, FormatVersion, m.FormatVersion)
+	assert.Equal(t, "003-dark-mode", m.Spec)
+	assert.Equal(t, []ArtifactInfo{{File: "spec.yaml", SchemaVersion: "1.1.0"}}, m.Artifacts)
+	assert.Equal(t, []string{"checklists/ux.yaml", "spec.yaml"}, m.Files)
+	assert.Equal(t, []string{"calm_owl_20250101_100000"}, m.Runs)
+	assert.True(t, m.Checkpoints)
+
+	root := t.TempDir()
+	specsDir, importState := filepath.Join(root, "specs"), filepath.Join(root, "state")
+	res, err := Import(bytes.NewReader(buf.Bytes()), specsDir, importState, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "003-dark-mode", res.SpecName)
+	assert.False(t, res.Renumbered)
+	assert.Empty(t, res.Outdated)
+	assert.True(t, res.Checkpoints)
+	assert.Equal(t, []string{"calm_owl_20250101_100000"}, res.Runs)
+
+	data, err := os.ReadFile(filepath.Join(specsDir, "003-dark-mode", "spec.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, bundleSpecYAML, string(data))
+	assert.FileExists(t, filepath.Join(specsDir, "003-dark-mode", "checklists", "ux.yaml"))
+
+	retries, err := retry.ListRetryStates(importState, "003-dark-mode")
+	require.NoError(t, err)
+	require.Len(t, retries, 1)
+	assert.Equal(t, 2, retries[0].Count)
+	others, err := retry.ListRetryStates(importState, "009-other")
+	require.NoError(t, err)
+	assert.Empty(t, others, "checkpoints of other specs are not exported")
+
+	evs, err := events.Load(importState, "calm_owl_20250101_100000")
+	require.NoError(t, err)
+	require.Len(t, evs, 1)
+	assert.Equal(t, "003-dark-mode", evs[0].Spec)
+
+	entries, err := os.ReadDir(specsDir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary import directory is left behind")
+}
+
+func TestImport_Renumbers(t *testing.T) {
+	t.Parallel()
+
+	specDir, stateDir := newExportFixture(t)
+	var buf bytes.Buffer
+	_, err := Export(&buf, specDir, stateDir)
+	require.NoError(t, err)
+
+	root := t.TempDir()
+	specsDir, importState := filepath.Join(root, "specs"), filepath.Join(root, "state")
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "003-login"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "004-logout"), 0o755))
+
+	res, err := Import(bytes.NewReader(buf.Bytes()), specsDir, importState, ImportOptions{})
+	require.NoError(t, err)
+	assert.True(t, res.Renumbered)
+	assert.Equal(t, "005-dark-mode", res.SpecName)
+
+	data, err := os.ReadFile(filepath.Join(specsDir, "005-dark-mode", "spec.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `branch: "005-dark-mode"`)
+
+	task, err := retry.LoadTaskState(importState, "005-dark-mode")
+	require.NoError(t, err)
+	require.NotNil(t, task)
+	assert.Equal(t, []string{"T001"}, task.CompletedTaskIDs)
+
+	evs, err := events.Load(importState, "calm_owl_20250101_100000")
+	require.NoError(t, err)
+	assert.Equal(t, "005-dark-mode", evs[0].Spec)
+
+	// Importing again picks the next number and leaves the existing run alone
+	res, err = Import(bytes.NewReader(buf.Bytes()), specsDir, importState, ImportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "006-dark-mode", res.SpecName)
+	assert.Empty(t, res.Runs)
+}
+
+func TestImport_Rejects(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		entries map[string]string
+		wantErr string
+	}{
+		"no manifest": {
+			entries: map[string]string{"spec/spec.yaml": bundleSpecYAML},
+			wantErr: "bundle has no manifest.json",
+		},
+		"newer format": {
+			entries: map[string]string{manifestName: `{"format_version": 99, "spec": "003-dark-mode"}`},
+			wantErr: "bundle format 99 is newer",
+		},
+		"bad spec name": {
+			entries: map[string]string{manifestName: `{"format_version": 1, "spec": "../003-x"}`},
+			wantErr: "not of the form NNN-name",
+		},
+		"run ID traversal": {
+			entries: map[string]string{
+				manifestName:               `{"format_version": 1, "spec": "003-dark-mode", "runs": ["ok_run", ".."]}`,
+				"spec/spec.yaml":           bundleSpecYAML,
+				"runs/ok_run/events.jsonl": "{}\n",
+			},
+			wantErr: `bundle run ID ".." is not a plain name`,
+		},
+		"path traversal": {
+			entries: map[string]string{"../evil": "x"},
+			wantErr: "escapes the bundle",
+		},
+		"newer artifact schema": {
+			entries: map[string]string{
+				manifestName:     `{"format_version": 1, "spec": "003-dark-mode"}`,
+				"spec/spec.yaml": "_meta:\n  version: \"9.0.0\"\nfeature:\n  branch: x\n",
+			},
+			wantErr: "newer than this autospec supports",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			specsDir := filepath.Join(t.TempDir(), "specs")
+			_, err := Import(bytes.NewReader(makeArchive(t, tt.entries)), specsDir, t.TempDir(), ImportOptions{})
+			assert.ErrorContains(t, err, tt.wantErr)
+			_, statErr := os.Stat(filepath.Join(specsDir, "003-dark-mode"))
+			assert.True(t, os.IsNotExist(statErr), "rejected bundles create no spec")
+		})
+	}
+
+	_, err := Import(bytes.NewReader([]byte("plain text")), t.TempDir(), t.TempDir(), ImportOptions{})
+	assert.ErrorContains(t, err, "not a bundle")
+}
+
+func TestImport_SpecConfig(t *testing.T) {
+	t.Parallel()
+
+	entries := map[string]string{
+		manifestName:          `{"format_version": 1, "spec": "003-dark-mode"}`,
+		"spec/spec.yaml":      bundleSpecYAML,
+		"spec/.autospec.yaml": "max_retries: 9\n",
+	}
+
+	specsDir := filepath.Join(t.TempDir(), "specs")
+	res, err := Import(bytes.NewReader(makeArchive(t, entries)), specsDir, t.TempDir(), ImportOptions{})
+	require.NoError(t, err)
+	assert.True(t, res.SkippedSpecConfig)
+	assert.False(t, res.SpecConfig)
+	assert.NoFileExists(t, filepath.Join(res.SpecDir, ".autospec.yaml"))
+
+	specsDir = filepath.Join(t.TempDir(), "specs")
+	res, err = Import(bytes.NewReader(makeArchive(t, entries)), specsDir, t.TempDir(), ImportOptions{SpecConfig: true})
+	require.NoError(t, err)
+	assert.True(t, res.SpecConfig)
+	assert.FileExists(t, filepath.Join(res.SpecDir, ".autospec.yaml"))
+}
+
+func TestReadArchive_Limits(t *testing.T) {
+	t.Parallel()
+
+	entries := map[string]string{"a": "1234", "b": "5678", "c": "90"}
+	limits := archiveLimits{entrySize: 4, totalSize: 10, entries: 3}
+	files, err := readArchive(bytes.NewReader(makeArchive(t, entries)), limits)
+	require.NoError(t, err)
+	assert.Len(t, files, 3)
+
+	tests := map[string]struct {
+		limits  archiveLimits
+		wantErr string
+	}{
+		"entry size":  {limits: archiveLimits{entrySize: 3, totalSize: 10, entries: 3}, wantErr: "is too large"},
+		"total size":  {limits: archiveLimits{entrySize: 4, totalSize: 9, entries: 3}, wantErr: "larger than 9 bytes"},
+		"entry count": {limits: archiveLimits{entrySize: 4, totalSize: 10, entries: 2}, wantErr: "more than 2 entries"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := readArchive(bytes.NewReader(makeArchive(t, entries)), tt.limits)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func makeArchive(t *testing.T, entries map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range entries {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), ModTime: time.Now(), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,172 @@
+// Package changelog appends a generated entry to the project's CHANGELOG.md
+// when a spec is completed. An entry summarizes the user stories the spec
+// delivered and its notable tasks, and is wrapped in HTML comment markers
+// keyed by spec name, so completing the same spec again replaces its entry
+// instead of adding a second one.
+package changelog
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/report"
+)
+
+// DefaultFile is the changelog written when file is not configured.
+const DefaultFile = "CHANGELOG.md"
+
+// maxNotableTasks caps the tasks listed outside user stories.
+const maxNotableTasks = 5
+
+// unreleasedHeading is the Keep a Changelog section new entries go under.
+const unreleasedHeading = "## [Unreleased]"
+
+// Config controls changelog generation (config key: changelog).
+type Config struct {
+	// Enabled appends an entry when a spec is marked completed. Default: false.
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// File is the changelog path, relative to the working directory.
+	// Default: CHANGELOG.md.
+	File string `koanf:"file" yaml:"file" json:"file"`
+}
+
+// Path returns the configured changelog path, or DefaultFile.
+func (c Config) Path() string {
+	if c.File == "" {
+		return DefaultFile
+	}
+	return c.File
+}
+
+// beginMarker and endMarker delimit the entry of one spec.
+func beginMarker(specName string) string {
+	return fmt.Sprintf("<!-- autospec:begin %s -->", specName)
+}
+
+func endMarker(specName string) string {
+	return fmt.Sprintf("<!-- autospec:end %s -->", specName)
+}
+
+// Entry renders the changelog entry for a completed spec, markers included.
+func Entry(r *report.Report) string {
+	var sb strings.Builder
+	sb.WriteString(beginMarker(r.SpecName) + "\n")
+	fmt.Fprintf(&sb, "### %s\n\n", r.SpecName)
+	if summary := firstLine(r.FeatureInput); summary != "" {
+		sb.WriteString(summary + "\n\n")
+	}
+	for _, s := range r.Stories {
+		line := fmt.Sprintf("- %s (%s)", s.Title, s.ID)
+		if !s.Delivered {
+			line += fmt.Sprintf(" - partial, %d/%d tasks", s.CompletedTasks, s.TotalTasks)
+		}
+		sb.WriteString(line + "\n")
+	}
+	for _, t := range notableTasks(r) {
+		fmt.Fprintf(&sb, "- %s (%s)\n", t.Title, t.ID)
+	}
+	sb.WriteString(endMarker(r.SpecName) + "\n")
+	return sb.String()
+}
+
+// notableTasks returns completed tasks that belong to no user story, such as
+// setup, refactoring and documentation work the story lines do not cover.
+func notableTasks(r *report.Report) []report.TaskReport {
+	var notable []report.TaskReport
+	for _, t := range r.Tasks {
+		if t.StoryID != "" || !strings.EqualFold(t.Status, "Completed") {
+			continue
+		}
+		notable = append(notable, t)
+		if len(notable) == maxNotableTasks {
+			break
+		}
+	}
+	return notable
+}
+
+// firstLine returns the first non-empty line of s, trimmed.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// Update writes entry for specName into the changelog at path and reports
+// whether the file changed. An existing entry for the spec is replaced in
+// place; otherwise the entry is inserted at the top of the [Unreleased]
+// section, which is created (with the file) when missing.
+func Update(path, specName, entry string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("reading changelog: %w", err)
+	}
+	content := string(data)
+
+	updated, err := insertEntry(content, specName, entry)
+	if err != nil {
+		return false, fmt.Errorf("updating %s: %w", path, err)
+	}
+	if updated == content {
+		return false, nil
+	}
+	if err := fsutil.WriteFile(path, []byte(updated), 0o644); err != nil {
+		return false, fmt.Errorf("writing changelog: %w", err)
+	}
+	return true, nil
+}
+
+// insertEntry returns content with entry replacing the spec's marked block,
+// or inserted below the [Unreleased] heading.
+func insertEntry(content, specName, entry string) (string, error) {
+	begin, end := beginMarker(specName), endMarker(specName)
+	if start := strings.Index(content, begin); start >= 0 {
+		stop := strings.Index(content[start:], end)
+		if stop < 0 {
+			return "", fmt.Errorf("entry for %s has no end marker %q", specName, end)
+		}
+		stop = start + stop + len(end)
+		if stop < len(content) && content[stop] == '\n' {
+			stop++
+		}
+		return content[:start] + entry + content[stop:], nil
+	}
+
+	if content == "" {
+		return "# Changelog\n\n" + unreleasedHeading + "\n\n" + entry, nil
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	insertAt := -1
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), unreleasedHeading) {
+			insertAt = i + 1
+			break
+		}
+	}
+	if insertAt < 0 {
+		// No [Unreleased] section yet: add one after the title, or at the top
+		section := unreleasedHeading + "\n\n" + entry + "\n"
+		if strings.HasPrefix(lines[0], "# ") {
+			return lines[0] + "\n" + section + strings.TrimLeft(strings.Join(lines[1:], ""), "\n"), nil
+		}
+		return section + content, nil
+	}
+
+	// Skip the blank line under the heading so the entry follows it
+	if insertAt < len(lines) && strings.TrimSpace(lines[insertAt]) == "" {
+		insertAt++
+	}
+	head := strings.Join(lines[:insertAt], "")
+	if !strings.HasSuffix(head, "\n\n") {
+		head = strings.TrimSuffix(head, "\n") + "\n\n"
+	}
+	return head + entry + "\n" + strings.Join(lines[insertAt:], ""), nil
+}
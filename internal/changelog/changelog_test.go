@@ -0,0 +1,120 @@
+package changelog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() *report.Report {
+	return &report.Report{
+		SpecName:     "001-login",
+		FeatureInput: "Add login and logout\nwith sessions",
+		Stories: []report.StoryReport{
+			{ID: "US-001", Title: "Log in", TotalTasks: 1, CompletedTasks: 1, Delivered: true},
+			{ID: "US-002", Title: "Log out", TotalTasks: 2, CompletedTasks: 1},
+		},
+		Tasks: []report.TaskReport{
+			{ID: "T001", Title: "Login handler", Status: "Completed", StoryID: "US-001"},
+			{ID: "T002", Title: "Set up session store", Status: "Completed"},
+			{ID: "T003", Title: "Write docs", Status: "Pending"},
+		},
+	}
+}
+
+func TestEntry(t *testing.T) {
+	t.Parallel()
+
+	entry := Entry(testReport())
+	want := `<!-- autospec:begin 001-login -->
+### 001-login
+
+Add login and logout
+
+- Log in (US-001)
+- Log out (US-002) - partial, 1/2 tasks
+- Set up session store (T002)
+<!-- autospec:end 001-login -->
+`
+	assert.Equal(t, want, entry)
+}
+
+func TestConfig_Path(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, DefaultFile, Config{}.Path())
+	assert.Equal(t, "docs/CHANGES.md", Config{File: "docs/CHANGES.md"}.Path())
+}
+
+func TestInsertEntry(t *testing.T) {
+	t.Parallel()
+
+	entry := "<!-- autospec:begin 001-login -->\n### 001-login\n<!-- autospec:end 001-login -->\n"
+	tests := map[string]struct {
+		content string
+		want    string
+	}{
+		"empty file": {
+			content: "",
+			want:    "# Changelog\n\n## [Unreleased]\n\n" + entry,
+		},
+		"under unreleased heading": {
+			content: "# Changelog\n\n## [Unreleased]\n\n### Added\n- Thing\n\n## [1.0.0]\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n" + entry + "\n### Added\n- Thing\n\n## [1.0.0]\n",
+		},
+		"heading without blank line": {
+			content: "## [Unreleased]\n### Added\n",
+			want:    "## [Unreleased]\n\n" + entry + "\n### Added\n",
+		},
+		"no unreleased section": {
+			content: "# Changelog\n\n## [1.0.0]\n- Thing\n",
+			want:    "# Changelog\n\n## [Unreleased]\n\n" + entry + "\n## [1.0.0]\n- Thing\n",
+		},
+		"replaces existing entry": {
+			content: "## [Unreleased]\n\n<!-- autospec:begin 001-login -->\nold\n<!-- autospec:end 001-login -->\n\n## [1.0.0]\n",
+			want:    "## [Unreleased]\n\n" + entry + "\n## [1.0.0]\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := insertEntry(tt.content, "001-login", entry)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInsertEntry_MissingEndMarker(t *testing.T) {
+	t.Parallel()
+
+	_, err := insertEntry("<!-- autospec:begin 001-login -->\nold\n", "001-login", "new\n")
+	assert.ErrorContains(t, err, "no end marker")
+}
+
+func TestUpdate_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "CHANGELOG.md")
+	require.NoError(t, os.WriteFile(path, []byte("# Changelog\n\n## [Unreleased]\n\n### Added\n- Thing\n"), 0o644))
+	entry := Entry(testReport())
+
+	changed, err := Update(path, "001-login", entry)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = Update(path, "001-login", entry)
+	require.NoError(t, err)
+	assert.False(t, changed, "same entry must not rewrite the file")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "autospec:begin 001-login"))
+	assert.Contains(t, string(data), "### Added\n- Thing\n")
+}
@@ -0,0 +1,193 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolutionKind describes how a deny-list conflict can be resolved.
+type ResolutionKind int
+
+const (
+	// ResolveRemoveDeny removes a deny rule that only blocks autospec paths.
+	ResolveRemoveDeny ResolutionKind = iota
+	// ResolveNarrowDeny replaces a deny rule with narrower rules that keep
+	// blocking what the user most likely meant to block.
+	ResolveNarrowDeny
+	// ResolveManual marks a conflict autospec will not change automatically,
+	// such as a tool-wide deny that also protects unrelated commands or paths.
+	ResolveManual
+)
+
+// String returns a human-readable representation of the resolution kind.
+func (k ResolutionKind) String() string {
+	switch k {
+	case ResolveRemoveDeny:
+		return "RemoveDeny"
+	case ResolveNarrowDeny:
+		return "NarrowDeny"
+	case ResolveManual:
+		return "Manual"
+	default:
+		return "Unknown"
+	}
+}
+
+// destructiveAutospecRules keep blocking the autospec commands that delete
+// files when a blanket Bash(autospec:*) deny is narrowed.
+var destructiveAutospecRules = []string{
+	"Bash(autospec uninstall:*)",
+	"Bash(autospec clean:*)",
+}
+
+// Resolution is a proposed fix for one deny rule that blocks required permissions.
+type Resolution struct {
+	Kind        ResolutionKind
+	DenyRule    string   // The conflicting permissions.deny entry
+	Blocks      []string // Required permissions the rule blocks
+	Replacement []string // Deny rules that replace DenyRule (ResolveNarrowDeny only)
+	Reason      string   // Why this resolution was chosen
+}
+
+// Automatic reports whether the resolution can be applied without user edits.
+func (r Resolution) Automatic() bool {
+	return r.Kind == ResolveRemoveDeny || r.Kind == ResolveNarrowDeny
+}
+
+// Describe returns a one-line summary of the change, e.g. for a report.
+func (r Resolution) Describe() string {
+	switch r.Kind {
+	case ResolveRemoveDeny:
+		return fmt.Sprintf("remove deny rule %s", r.DenyRule)
+	case ResolveNarrowDeny:
+		return fmt.Sprintf("narrow deny rule %s to %s", r.DenyRule, strings.Join(r.Replacement, ", "))
+	default:
+		return fmt.Sprintf("deny rule %s blocks %s; edit permissions.deny manually", r.DenyRule, strings.Join(r.Blocks, ", "))
+	}
+}
+
+// FindDenyConflicts returns one resolution per deny rule that blocks any of
+// the required permissions, in deny-list order. Besides exact matches, a rule
+// conflicts when it covers a required permission: a tool-wide rule ("Bash",
+// "Write(**)"), a shorter Bash prefix, or a parent directory.
+func (s *Settings) FindDenyConflicts(required []string) []Resolution {
+	var resolutions []Resolution
+	for _, deny := range s.getDenyList() {
+		var blocks []string
+		for _, perm := range required {
+			if denyCovers(deny, perm) {
+				blocks = append(blocks, perm)
+			}
+		}
+		if len(blocks) > 0 {
+			resolutions = append(resolutions, resolveConflict(deny, blocks))
+		}
+	}
+	return resolutions
+}
+
+// ApplyResolution updates the deny list as described by r.
+// Manual resolutions are rejected; the caller reports them instead.
+func (s *Settings) ApplyResolution(r Resolution) error {
+	if !r.Automatic() {
+		return fmt.Errorf("deny rule %s must be resolved manually", r.DenyRule)
+	}
+
+	denyList := s.getDenyList()
+	updated := make([]interface{}, 0, len(denyList)+len(r.Replacement))
+	found := false
+	for _, rule := range denyList {
+		if rule != r.DenyRule {
+			updated = append(updated, rule)
+			continue
+		}
+		found = true
+		for _, replacement := range r.Replacement {
+			if !containsString(denyList, replacement) {
+				updated = append(updated, replacement)
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("deny rule %s not found in %s", r.DenyRule, s.filePath)
+	}
+
+	s.getPermissions()["deny"] = updated
+	return nil
+}
+
+// resolveConflict picks the resolution for a deny rule that blocks perms.
+func resolveConflict(deny string, blocks []string) Resolution {
+	r := Resolution{DenyRule: deny, Blocks: blocks}
+	exact := len(blocks) == 1 && normalizeRule(blocks[0]) == normalizeRule(deny)
+
+	switch {
+	case exact && deny == RequiredPermission:
+		r.Kind = ResolveNarrowDeny
+		r.Replacement = destructiveAutospecRules
+		r.Reason = "keeps blocking autospec commands that delete files"
+	case exact:
+		r.Kind = ResolveRemoveDeny
+		r.Reason = "the rule only covers files autospec manages"
+	default:
+		r.Kind = ResolveManual
+		r.Reason = "the rule also covers commands or paths outside autospec"
+	}
+	return r
+}
+
+// denyCovers reports whether deny rule matches everything perm allows.
+func denyCovers(deny, perm string) bool {
+	denyTool, denySpec := parseRule(deny)
+	permTool, permSpec := parseRule(perm)
+	if denyTool != permTool {
+		return false
+	}
+	if denySpec == "" || denySpec == "*" || denySpec == "**" || denySpec == permSpec {
+		return true
+	}
+
+	if denyTool == "Bash" {
+		denyPrefix, ok := strings.CutSuffix(denySpec, ":*")
+		permPrefix, _ := strings.CutSuffix(permSpec, ":*")
+		return ok && strings.HasPrefix(permPrefix, denyPrefix)
+	}
+
+	denyDir, ok := strings.CutSuffix(denySpec, "/**")
+	permDir, _ := strings.CutSuffix(permSpec, "/**")
+	return ok && strings.HasPrefix(permDir, denyDir+"/")
+}
+
+// parseRule splits "Tool(spec)" into its tool and spec. A bare "Tool" has an
+// empty spec, which matches every use of the tool. Path specs are relative to
+// the project, so a leading "./" is dropped ("./specs/**" == "specs/**").
+func parseRule(rule string) (tool, spec string) {
+	open := strings.IndexByte(rule, '(')
+	if open < 0 || !strings.HasSuffix(rule, ")") {
+		return rule, ""
+	}
+	tool, spec = rule[:open], rule[open+1:len(rule)-1]
+	if tool != "Bash" {
+		spec = strings.TrimPrefix(spec, "./")
+	}
+	return tool, spec
+}
+
+// normalizeRule returns rule in the form parseRule compares.
+func normalizeRule(rule string) string {
+	tool, spec := parseRule(rule)
+	if spec == "" {
+		return tool
+	}
+	return tool + "(" + spec + ")"
+}
+
+// containsString reports whether list contains s.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package claude
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testRequired = []string{
+	"Bash(autospec:*)",
+	"Write(.autospec/**)",
+	"Edit(.autospec/**)",
+	"Write(specs/**)",
+	"Edit(specs/**)",
+}
+
+func TestDenyCovers(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		deny string
+		perm string
+		want bool
+	}{
+		"exact match":            {deny: "Bash(autospec:*)", perm: "Bash(autospec:*)", want: true},
+		"bare tool":              {deny: "Bash", perm: "Bash(autospec:*)", want: true},
+		"tool wildcard":          {deny: "Write(**)", perm: "Write(specs/**)", want: true},
+		"shorter bash prefix":    {deny: "Bash(auto:*)", perm: "Bash(autospec:*)", want: true},
+		"narrower bash prefix":   {deny: "Bash(autospec clean:*)", perm: "Bash(autospec:*)", want: false},
+		"unrelated bash command": {deny: "Bash(rm:*)", perm: "Bash(autospec:*)", want: false},
+		"parent directory":       {deny: "Write(docs/**)", perm: "Write(docs/specs/**)", want: true},
+		"sibling directory":      {deny: "Write(spec/**)", perm: "Write(specs/**)", want: false},
+		"subdirectory only":      {deny: "Write(specs/secret/**)", perm: "Write(specs/**)", want: false},
+		"different tool":         {deny: "Edit(specs/**)", perm: "Write(specs/**)", want: false},
+		"dot-relative path":      {deny: "Write(specs/**)", perm: "Write(./specs/**)", want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, denyCovers(tt.deny, tt.perm))
+		})
+	}
+}
+
+func TestFindDenyConflicts(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		deny      []interface{}
+		wantKinds map[string]ResolutionKind
+	}{
+		"no deny list": {
+			wantKinds: map[string]ResolutionKind{},
+		},
+		"unrelated rules": {
+			deny:      []interface{}{"Bash(rm:*)", "Write(.env)"},
+			wantKinds: map[string]ResolutionKind{},
+		},
+		"exact autospec deny is narrowed": {
+			deny:      []interface{}{"Bash(autospec:*)"},
+			wantKinds: map[string]ResolutionKind{"Bash(autospec:*)": ResolveNarrowDeny},
+		},
+		"exact path deny is removed": {
+			deny:      []interface{}{"Edit(specs/**)"},
+			wantKinds: map[string]ResolutionKind{"Edit(specs/**)": ResolveRemoveDeny},
+		},
+		"broad rules need manual resolution": {
+			deny: []interface{}{"Bash", "Write(**)"},
+			wantKinds: map[string]ResolutionKind{
+				"Bash":      ResolveManual,
+				"Write(**)": ResolveManual,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := &Settings{data: map[string]interface{}{
+				"permissions": map[string]interface{}{"deny": tt.deny},
+			}}
+
+			got := make(map[string]ResolutionKind)
+			for _, r := range s.FindDenyConflicts(testRequired) {
+				got[r.DenyRule] = r.Kind
+			}
+			assert.Equal(t, tt.wantKinds, got)
+		})
+	}
+}
+
+func TestFindDenyConflicts_Blocks(t *testing.T) {
+	t.Parallel()
+
+	s := &Settings{data: map[string]interface{}{
+		"permissions": map[string]interface{}{"deny": []interface{}{"Write(**)"}},
+	}}
+
+	conflicts := s.FindDenyConflicts(testRequired)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, []string{"Write(.autospec/**)", "Write(specs/**)"}, conflicts[0].Blocks)
+	assert.Contains(t, conflicts[0].Describe(), "edit permissions.deny manually")
+}
+
+func TestApplyResolution(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	createSettingsFile(t, dir, `{
+		"permissions": {
+			"allow": ["Bash(autospec:*)"],
+			"deny": ["Bash(rm:*)", "Bash(autospec:*)", "Write(specs/**)", "Bash(autospec clean:*)"]
+		}
+	}`)
+	s, err := Load(dir)
+	require.NoError(t, err)
+
+	for _, r := range s.FindDenyConflicts(testRequired) {
+		require.NoError(t, s.ApplyResolution(r))
+	}
+	require.NoError(t, s.Save())
+
+	reloaded, err := Load(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Bash(rm:*)", "Bash(autospec uninstall:*)", "Bash(autospec clean:*)"}, reloaded.getDenyList())
+	assert.Empty(t, reloaded.FindDenyConflicts(testRequired))
+	assert.Equal(t, StatusConfigured, reloaded.Check().Status)
+}
+
+func TestApplyResolution_Errors(t *testing.T) {
+	t.Parallel()
+
+	s := &Settings{data: map[string]interface{}{
+		"permissions": map[string]interface{}{"deny": []interface{}{"Bash"}},
+	}}
+
+	err := s.ApplyResolution(Resolution{Kind: ResolveManual, DenyRule: "Bash"})
+	assert.ErrorContains(t, err, "manually")
+
+	err = s.ApplyResolution(Resolution{Kind: ResolveRemoveDeny, DenyRule: "Write(specs/**)"})
+	assert.ErrorContains(t, err, "not found")
+	assert.Equal(t, []string{"Bash"}, s.getDenyList())
+}
@@ -0,0 +1,79 @@
+package claude
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PermissionChanges lists the permission entries a change to a settings file
+// adds or removes.
+type PermissionChanges struct {
+	AllowAdded  []string
+	DenyRemoved []string
+	DenyAdded   []string
+}
+
+// Empty reports whether the changes leave the settings file untouched.
+func (c PermissionChanges) Empty() bool {
+	return len(c.AllowAdded)+len(c.DenyRemoved)+len(c.DenyAdded) == 0
+}
+
+// PlanPermissions returns the allow entries AddPermissions would add for
+// required, without modifying the settings.
+func (s *Settings) PlanPermissions(required []string) PermissionChanges {
+	var changes PermissionChanges
+	for _, perm := range required {
+		if !s.HasPermission(perm) && !containsString(changes.AllowAdded, perm) {
+			changes.AllowAdded = append(changes.AllowAdded, perm)
+		}
+	}
+	return changes
+}
+
+// PlanResolutions returns the deny entries applying resolutions would remove
+// and add. Manual resolutions change nothing and are skipped.
+func (s *Settings) PlanResolutions(resolutions []Resolution) PermissionChanges {
+	var changes PermissionChanges
+	denyList := s.getDenyList()
+	for _, r := range resolutions {
+		if !r.Automatic() {
+			continue
+		}
+		changes.DenyRemoved = append(changes.DenyRemoved, r.DenyRule)
+		for _, replacement := range r.Replacement {
+			if !containsString(denyList, replacement) && !containsString(changes.DenyAdded, replacement) {
+				changes.DenyAdded = append(changes.DenyAdded, replacement)
+			}
+		}
+	}
+	return changes
+}
+
+// Preview renders the changes as a unified diff of the permission lists in
+// the file at path. Returns "" when there are no changes.
+func (c PermissionChanges) Preview(path string) string {
+	if c.Empty() {
+		return ""
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n", path)
+	fmt.Fprintf(&b, "+++ %s (proposed)\n", path)
+	if len(c.AllowAdded) > 0 {
+		b.WriteString(" permissions.allow:\n")
+		writeDiffLines(&b, "+", c.AllowAdded)
+	}
+	if len(c.DenyRemoved)+len(c.DenyAdded) > 0 {
+		b.WriteString(" permissions.deny:\n")
+		writeDiffLines(&b, "-", c.DenyRemoved)
+		writeDiffLines(&b, "+", c.DenyAdded)
+	}
+	return b.String()
+}
+
+// writeDiffLines writes one quoted entry per line with the given diff marker.
+func writeDiffLines(b *strings.Builder, marker string, entries []string) {
+	for _, entry := range entries {
+		fmt.Fprintf(b, "%s   %q\n", marker, entry)
+	}
+}
@@ -0,0 +1,81 @@
+package claude
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanPermissions(t *testing.T) {
+	t.Parallel()
+
+	s := &Settings{data: map[string]interface{}{
+		"permissions": map[string]interface{}{
+			"allow": []interface{}{"Bash(autospec:*)", "Write(.autospec/**)"},
+		},
+	}}
+
+	changes := s.PlanPermissions(testRequired)
+	assert.Equal(t, []string{"Edit(.autospec/**)", "Write(specs/**)", "Edit(specs/**)"}, changes.AllowAdded)
+	assert.Empty(t, changes.DenyRemoved)
+	assert.Empty(t, changes.DenyAdded)
+	// Planning does not modify the settings
+	assert.Len(t, s.getAllowList(), 2)
+}
+
+func TestPlanResolutions(t *testing.T) {
+	t.Parallel()
+
+	s := &Settings{data: map[string]interface{}{
+		"permissions": map[string]interface{}{
+			"deny": []interface{}{"Bash(autospec:*)", "Bash(autospec clean:*)", "Write(specs/**)", "Bash"},
+		},
+	}}
+
+	changes := s.PlanResolutions(s.FindDenyConflicts(testRequired))
+	assert.Equal(t, []string{"Bash(autospec:*)", "Write(specs/**)"}, changes.DenyRemoved)
+	assert.Equal(t, []string{"Bash(autospec uninstall:*)"}, changes.DenyAdded)
+	assert.Empty(t, changes.AllowAdded)
+}
+
+func TestPermissionChanges_Preview(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		changes PermissionChanges
+		want    string
+	}{
+		"no changes": {
+			want: "",
+		},
+		"allow additions": {
+			changes: PermissionChanges{AllowAdded: []string{"Bash(autospec:*)", "Write(specs/**)"}},
+			want: `--- .claude/settings.local.json
++++ .claude/settings.local.json (proposed)
+ permissions.allow:
++   "Bash(autospec:*)"
++   "Write(specs/**)"
+`,
+		},
+		"deny replacement": {
+			changes: PermissionChanges{
+				DenyRemoved: []string{"Bash(autospec:*)"},
+				DenyAdded:   []string{"Bash(autospec uninstall:*)"},
+			},
+			want: `--- .claude/settings.local.json
++++ .claude/settings.local.json (proposed)
+ permissions.deny:
+-   "Bash(autospec:*)"
++   "Bash(autospec uninstall:*)"
+`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.changes.Preview(".claude/settings.local.json"))
+			assert.Equal(t, tt.want == "", tt.changes.Empty())
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 )
 
 // SettingsStatus represents the state of Claude settings configuration.
@@ -218,10 +220,10 @@ func (s *Settings) Check() SettingsCheckResult {
 		}
 	}
 
-	if s.CheckDenyList(RequiredPermission) {
+	if conflicts := s.FindDenyConflicts([]string{RequiredPermission}); len(conflicts) > 0 {
 		return SettingsCheckResult{
 			Status:   StatusDenied,
-			Message:  fmt.Sprintf("%s is explicitly denied. Remove from permissions.deny in %s to allow autospec commands.", RequiredPermission, s.filePath),
+			Message:  fmt.Sprintf("%s is explicitly denied by %s in %s (run 'autospec config fix-permissions' to resolve)", RequiredPermission, conflicts[0].DenyRule, s.filePath),
 			FilePath: s.filePath,
 		}
 	}
@@ -306,7 +308,7 @@ func (s *Settings) Save() error {
 	// Add trailing newline for POSIX compliance
 	data = append(data, '\n')
 
-	return atomicWrite(s.filePath, data)
+	return fsutil.WriteFile(s.filePath, data, 0o600)
 }
 
 // SandboxConfig represents the sandbox configuration additions for autospec.
@@ -425,37 +427,3 @@ func CheckSandboxStatus(projectDir string) (bool, error) {
 	}
 	return settings.IsSandboxEnabled(), nil
 }
-
-// atomicWrite writes data to a file atomically using temp file + rename.
-func atomicWrite(filePath string, data []byte) error {
-	dir := filepath.Dir(filePath)
-	tmpFile, err := os.CreateTemp(dir, ".settings-*.tmp")
-	if err != nil {
-		return fmt.Errorf("creating temp file: %w", err)
-	}
-	tmpPath := tmpFile.Name()
-
-	// Clean up temp file on any error
-	defer func() {
-		if tmpPath != "" {
-			os.Remove(tmpPath)
-		}
-	}()
-
-	if _, err := tmpFile.Write(data); err != nil {
-		tmpFile.Close()
-		return fmt.Errorf("writing temp file: %w", err)
-	}
-
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("closing temp file: %w", err)
-	}
-
-	if err := os.Rename(tmpPath, filePath); err != nil {
-		return fmt.Errorf("renaming temp file to %s: %w", filePath, err)
-	}
-
-	// Clear tmpPath so defer doesn't try to remove the final file
-	tmpPath = ""
-	return nil
-}
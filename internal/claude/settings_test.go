@@ -211,6 +211,15 @@ func TestCheck(t *testing.T) {
 			wantStatus:      StatusDenied,
 			wantMsgContains: "explicitly denied",
 		},
+		"permission covered by tool-wide deny": {
+			setup: func(t *testing.T, dir string) {
+				createSettingsFile(t, dir, `{
+					"permissions": {"allow": ["Bash(autospec:*)"], "deny": ["Bash"]}
+				}`)
+			},
+			wantStatus:      StatusDenied,
+			wantMsgContains: "fix-permissions",
+		},
 		"permission missing from allow list": {
 			setup: func(t *testing.T, dir string) {
 				createSettingsFile(t, dir, `{
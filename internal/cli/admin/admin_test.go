@@ -988,6 +988,8 @@ func TestAllCommands_RunEFunctionsAreDefined(t *testing.T) {
 		"commands install":      commandsInstallCmd,
 		"commands check":        commandsCheckCmd,
 		"commands info":         commandsInfoCmd,
+		"commands update":       commandsUpdateCmd,
+		"commands diff":         commandsDiffCmd,
 		"completion install":    completionInstallCmd,
 		"completion bash":       completionBashCmd,
 		"completion zsh":        completionZshCmd,
@@ -1128,6 +1130,8 @@ func TestCommandsSubcommands_AreAddedToParent(t *testing.T) {
 	assert.Contains(t, subcommandMap, "install")
 	assert.Contains(t, subcommandMap, "check")
 	assert.Contains(t, subcommandMap, "info")
+	assert.Contains(t, subcommandMap, "update")
+	assert.Contains(t, subcommandMap, "diff")
 }
 
 // Additional helper function tests with more edge cases
@@ -8,7 +8,7 @@ import (
 var commandsCmd = &cobra.Command{
 	Use:   "commands",
 	Short: "Manage autospec command templates",
-	Long:  `Commands for installing, checking, and viewing autospec command templates.`,
+	Long:  `Commands for installing, checking, diffing, updating, and viewing autospec command templates.`,
 }
 
 func init() {
@@ -72,7 +72,7 @@ func runCommandsCheck(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(cmd.OutOrStdout())
 	}
 
-	fmt.Fprintln(cmd.OutOrStdout(), "Run 'autospec commands install' to update.")
+	fmt.Fprintln(cmd.OutOrStdout(), "Run 'autospec commands update' to update.")
 
 	return nil
 }
@@ -0,0 +1,111 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+var commandsDiffCmd = &cobra.Command{
+	Use:   "diff [command-name...]",
+	Short: "Show how installed command templates differ from the embedded versions",
+	Long: `Show a unified diff from each installed command template to the version
+embedded in this binary, i.e. what 'autospec commands update' would change.
+
+Each differing template is labelled outdated (unmodified install of another
+version) or modified (edited locally, or installed before checksums were
+recorded). Without arguments, all autospec templates are compared.
+
+Example:
+  autospec commands diff
+  autospec commands diff autospec.plan
+  autospec commands diff --agent gemini`,
+	RunE: runCommandsDiff,
+}
+
+var (
+	diffTargetDir string
+	diffAgent     string
+)
+
+func init() {
+	commandsCmd.AddCommand(commandsDiffCmd)
+	commandsDiffCmd.Flags().StringVar(&diffTargetDir, "target", "", "Commands directory (default: the agent's commands directory)")
+	commandsDiffCmd.Flags().StringVar(&diffAgent, "agent", "claude",
+		fmt.Sprintf("Command format (%s)", strings.Join(commands.FormatNames(), ", ")))
+}
+
+func runCommandsDiff(cmd *cobra.Command, args []string) error {
+	format, err := commands.LookupFormat(diffAgent)
+	if err != nil {
+		return err
+	}
+	targetDir := diffTargetDir
+	if targetDir == "" {
+		targetDir = format.Dir
+	}
+
+	statuses, err := commands.Status(targetDir, format)
+	if err != nil {
+		return fmt.Errorf("failed to compare templates: %w", err)
+	}
+	statuses, err = filterStatuses(statuses, args)
+	if err != nil {
+		return err
+	}
+	printCommandDiffs(cmd, statuses)
+	return nil
+}
+
+// filterStatuses keeps the statuses of the named commands, all when names is
+// empty.
+func filterStatuses(statuses []commands.CommandStatus, names []string) ([]commands.CommandStatus, error) {
+	if len(names) == 0 {
+		return statuses, nil
+	}
+	byName := make(map[string]commands.CommandStatus, len(statuses))
+	for _, s := range statuses {
+		byName[s.Name] = s
+	}
+	filtered := make([]commands.CommandStatus, 0, len(names))
+	for _, name := range names {
+		s, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("command not found: %s", name)
+		}
+		filtered = append(filtered, s)
+	}
+	return filtered, nil
+}
+
+// printCommandDiffs prints a diff per differing template and a summary.
+func printCommandDiffs(cmd *cobra.Command, statuses []commands.CommandStatus) {
+	out := cmd.OutOrStdout()
+	counts := map[commands.State]int{}
+	for _, s := range statuses {
+		counts[s.State]++
+		switch s.State {
+		case commands.StateMissing:
+			fmt.Fprintf(out, "%s: not installed (v%s available)\n\n", s.Name, s.EmbeddedVersion)
+		case commands.StateOutdated, commands.StateModified:
+			installed := s.InstalledVersion
+			if installed == "" {
+				installed = "unknown version"
+			} else {
+				installed = "v" + installed
+			}
+			fmt.Fprintf(out, "%s: %s (%s → v%s)\n", s.Name, s.State, installed, s.EmbeddedVersion)
+			fmt.Fprintln(out, commands.UnifiedDiff(s.Path+" (installed)", s.Name+" (embedded)", s.Installed, s.Embedded))
+		}
+	}
+
+	if counts[commands.StateCurrent] == len(statuses) {
+		fmt.Fprintln(out, "All commands match the embedded templates.")
+		return
+	}
+	fmt.Fprintf(out, "%d current, %d outdated, %d modified, %d missing\n",
+		counts[commands.StateCurrent], counts[commands.StateOutdated], counts[commands.StateModified], counts[commands.StateMissing])
+	fmt.Fprintln(out, "Run 'autospec commands update' to upgrade outdated and missing templates (--force also replaces modified ones).")
+}
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/ariel-frischer/autospec/internal/commands"
 	"github.com/spf13/cobra"
@@ -18,32 +19,47 @@ DEPRECATED: Use 'autospec init' instead, which handles commands and config in on
 This installs:
   - Command templates (autospec.specify, autospec.plan, etc.) to .claude/commands/
 
+With --agent the templates are converted to another agent's command format
+and installed to its directory (gemini: .gemini/commands, cursor:
+.cursor/rules, ...).
+
 Existing autospec files will be overwritten. Other files are preserved.
 
 Example:
   autospec init                                           # Recommended
   autospec commands install                               # Deprecated
-  autospec commands install --target ./custom/commands`,
+  autospec commands install --target ./custom/commands
+  autospec commands install --agent gemini`,
 	RunE: runCommandsInstall,
 }
 
-var installTargetDir string
+var (
+	installTargetDir string
+	installAgent     string
+)
 
 func init() {
 	commandsCmd.AddCommand(commandsInstallCmd)
-	commandsInstallCmd.Flags().StringVar(&installTargetDir, "target", "", "Target directory for commands (default: .claude/commands)")
+	commandsInstallCmd.Flags().StringVar(&installTargetDir, "target", "", "Target directory for commands (default: the agent's commands directory)")
+	commandsInstallCmd.Flags().StringVar(&installAgent, "agent", "claude",
+		fmt.Sprintf("Command format to install (%s)", strings.Join(commands.FormatNames(), ", ")))
 }
 
 func runCommandsInstall(cmd *cobra.Command, args []string) error {
+	format, err := commands.LookupFormat(installAgent)
+	if err != nil {
+		return err
+	}
+
 	// Install command templates
 	targetDir := installTargetDir
 	if targetDir == "" {
-		targetDir = commands.GetDefaultCommandsDir()
+		targetDir = format.Dir
 	}
 
 	fmt.Fprintf(cmd.OutOrStdout(), "Installing autospec commands to %s...\n", targetDir)
 
-	results, err := commands.InstallTemplates(targetDir)
+	results, err := commands.InstallTemplatesInFormat(targetDir, format)
 	if err != nil {
 		return fmt.Errorf("failed to install templates: %w", err)
 	}
@@ -63,7 +79,7 @@ func runCommandsInstall(cmd *cobra.Command, args []string) error {
 	}
 
 	fmt.Fprintf(cmd.OutOrStdout(), "\nDone: %d installed, %d updated\n", cmdInstalledCount, cmdUpdatedCount)
-	fmt.Fprintf(cmd.OutOrStdout(), "\nCommands are now available as /autospec.* in Claude Code\n")
+	fmt.Fprintf(cmd.OutOrStdout(), "\nCommands are now available as %s\n", fmt.Sprintf(format.Invocation, "autospec.*"))
 
 	return nil
 }
@@ -0,0 +1,90 @@
+package admin
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/commands"
+	"github.com/spf13/cobra"
+)
+
+var commandsUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Upgrade installed command templates, keeping local edits",
+	Long: `Upgrade installed command templates to the versions embedded in this binary.
+
+Every install records each template's version and checksum in
+.autospec-commands.yaml in the commands directory. Update uses it to tell
+unmodified templates, which are replaced, from locally edited ones, which are
+skipped. With --force, edited templates are replaced too, after a copy is
+saved as <file>.bak (or .bak.2, ...). Missing templates are installed.
+
+Run 'autospec commands diff' first to review the changes.
+
+Example:
+  autospec commands update
+  autospec commands update --force
+  autospec commands update --agent gemini`,
+	RunE: runCommandsUpdate,
+}
+
+var (
+	updateTargetDir string
+	updateAgent     string
+	updateForce     bool
+)
+
+func init() {
+	commandsCmd.AddCommand(commandsUpdateCmd)
+	commandsUpdateCmd.Flags().StringVar(&updateTargetDir, "target", "", "Commands directory (default: the agent's commands directory)")
+	commandsUpdateCmd.Flags().StringVar(&updateAgent, "agent", "claude",
+		fmt.Sprintf("Command format (%s)", strings.Join(commands.FormatNames(), ", ")))
+	commandsUpdateCmd.Flags().BoolVarP(&updateForce, "force", "f", false, "Replace locally modified templates (a .bak copy is kept)")
+}
+
+func runCommandsUpdate(cmd *cobra.Command, args []string) error {
+	format, err := commands.LookupFormat(updateAgent)
+	if err != nil {
+		return err
+	}
+	targetDir := updateTargetDir
+	if targetDir == "" {
+		targetDir = format.Dir
+	}
+
+	results, err := commands.UpdateTemplates(targetDir, format, updateForce)
+	if err != nil {
+		return fmt.Errorf("failed to update templates: %w", err)
+	}
+	printUpdateResults(cmd, targetDir, results)
+	return nil
+}
+
+// printUpdateResults lists what UpdateTemplates changed and skipped.
+func printUpdateResults(cmd *cobra.Command, targetDir string, results []commands.InstallResult) {
+	out := cmd.OutOrStdout()
+	if len(results) == 0 {
+		fmt.Fprintf(out, "All commands in %s are up to date.\n", targetDir)
+		return
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Action]++
+		switch {
+		case r.Action == "installed":
+			fmt.Fprintf(out, "  + %s (installed)\n", r.CommandName)
+		case r.Action == "skipped":
+			fmt.Fprintf(out, "  ! %s (locally modified, skipped)\n", r.CommandName)
+		case r.Backup != "":
+			fmt.Fprintf(out, "  ~ %s (updated, local edits saved to %s)\n", r.CommandName, r.Backup)
+		default:
+			fmt.Fprintf(out, "  ~ %s (updated)\n", r.CommandName)
+		}
+	}
+
+	fmt.Fprintf(out, "\nDone: %d installed, %d updated, %d skipped\n", counts["installed"], counts["updated"], counts["skipped"])
+	if counts["skipped"] > 0 {
+		fmt.Fprintln(out, "Review local edits with 'autospec commands diff', then rerun with --force to replace them.")
+	}
+}
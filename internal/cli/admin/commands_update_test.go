@@ -0,0 +1,59 @@
+package admin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/commands"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandsDiffAndUpdate_LocalEdit(t *testing.T) {
+	dir := t.TempDir()
+	_, err := commands.UpdateTemplates(dir, mustFormat(t, "claude"), false)
+	require.NoError(t, err)
+	tasks := filepath.Join(dir, "autospec.tasks.md")
+	require.NoError(t, os.WriteFile(tasks, []byte("my tasks\n"), 0o644))
+
+	diffTargetDir, diffAgent = dir, "claude"
+	updateTargetDir, updateAgent, updateForce = dir, "claude", false
+	t.Cleanup(func() {
+		diffTargetDir, updateTargetDir, updateForce = "", "", false
+	})
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	require.NoError(t, commandsDiffCmd.RunE(cmd, []string{"autospec.tasks"}))
+	assert.Contains(t, out.String(), "autospec.tasks: modified")
+	assert.Contains(t, out.String(), "-my tasks")
+	assert.Contains(t, out.String(), "0 current, 0 outdated, 1 modified, 0 missing")
+
+	err = commandsDiffCmd.RunE(cmd, []string{"autospec.nope"})
+	assert.EqualError(t, err, "command not found: autospec.nope")
+
+	out.Reset()
+	require.NoError(t, commandsUpdateCmd.RunE(cmd, nil))
+	assert.Contains(t, out.String(), "! autospec.tasks (locally modified, skipped)")
+	assert.Contains(t, out.String(), "Done: 0 installed, 0 updated, 1 skipped")
+
+	out.Reset()
+	updateForce = true
+	require.NoError(t, commandsUpdateCmd.RunE(cmd, nil))
+	assert.Contains(t, out.String(), "local edits saved to "+tasks+".bak")
+
+	out.Reset()
+	require.NoError(t, commandsDiffCmd.RunE(cmd, nil))
+	assert.Contains(t, out.String(), "All commands match the embedded templates.")
+}
+
+func mustFormat(t *testing.T, name string) commands.Format {
+	t.Helper()
+	format, err := commands.LookupFormat(name)
+	require.NoError(t, err)
+	return format
+}
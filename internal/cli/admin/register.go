@@ -1,5 +1,5 @@
 // Package admin provides administrative CLI commands for autospec.
-// Includes: commands, completion_install, uninstall
+// Includes: commands, completion_install, uninstall, release
 package admin
 
 import (
@@ -18,4 +18,5 @@ func Register(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(commandsCmd)
 	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(uninstallCmd)
+	rootCmd.AddCommand(releaseCmd)
 }
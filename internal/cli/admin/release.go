@@ -0,0 +1,158 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/update"
+	"github.com/spf13/cobra"
+)
+
+// releaseHTTPTimeout bounds GitHub API and checksum requests.
+const releaseHTTPTimeout = 30 * time.Second
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "Release tooling for maintainers",
+	Long:  `Commands that help maintainers publish autospec releases.`,
+}
+
+var releaseManifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Generate a Homebrew formula or Scoop manifest for a release",
+	Long: `Generate a package manager manifest from a release's checksums.txt.
+
+By default the release is fetched from GitHub (--version, or the latest
+release). With --checksums the manifest is built from a local checksums.txt,
+e.g. goreleaser's dist/checksums.txt before the release is published; the
+version is then taken from the archive names unless --version is set.
+
+Formats:
+  brew   Homebrew formula for the macOS and Linux archives
+  scoop  Scoop manifest for the Windows archives, with autoupdate`,
+	Example: `  # Formula for the latest release
+  autospec release manifest --format brew > Formula/autospec.rb
+
+  # Scoop manifest for a given release
+  autospec release manifest --format scoop --version v0.9.0 -o bucket/autospec.json
+
+  # From a local goreleaser build
+  autospec release manifest --format brew --checksums dist/checksums.txt`,
+	Args: cobra.NoArgs,
+	RunE: runReleaseManifest,
+}
+
+func init() {
+	releaseCmd.GroupID = shared.GroupInternal
+	releaseCmd.AddCommand(releaseManifestCmd)
+
+	releaseManifestCmd.Flags().String("format", update.FormatHomebrew, "Manifest format: "+strings.Join(update.Formats, ", "))
+	releaseManifestCmd.Flags().String("version", "", "Release version (default: latest, or the version in --checksums)")
+	releaseManifestCmd.Flags().String("checksums", "", "Read a local checksums.txt instead of fetching the release")
+	releaseManifestCmd.Flags().String("base-url", "", "Archive URL prefix (default: the GitHub release download URL)")
+	releaseManifestCmd.Flags().StringP("output", "o", "", "Write the manifest to a file instead of stdout")
+	_ = releaseManifestCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(update.Formats, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runReleaseManifest(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+	format, _ := cmd.Flags().GetString("format")
+	version, _ := cmd.Flags().GetString("version")
+	checksumsPath, _ := cmd.Flags().GetString("checksums")
+	baseURL, _ := cmd.Flags().GetString("base-url")
+	output, _ := cmd.Flags().GetString("output")
+
+	var checksums string
+	if checksumsPath != "" {
+		data, err := os.ReadFile(checksumsPath)
+		if err != nil {
+			return fmt.Errorf("reading checksums: %w", err)
+		}
+		checksums = string(data)
+		if version == "" {
+			version = checksumsVersion(checksums)
+		}
+	} else {
+		var err error
+		version, checksums, err = fetchReleaseChecksums(cmd.Context(), update.NewChecker(releaseHTTPTimeout), version)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := renderReleaseManifest(format, version, checksums, baseURL)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0o644); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Wrote %s manifest for %s to %s\n", format, version, output)
+	return nil
+}
+
+// fetchReleaseChecksums fetches the release tagged version (latest when
+// empty) and its checksums.txt, returning the release version.
+func fetchReleaseChecksums(ctx context.Context, checker *update.Checker, version string) (string, string, error) {
+	tag := version
+	if tag != "" && !strings.HasPrefix(tag, "v") {
+		tag = "v" + tag
+	}
+	release, err := checker.FetchRelease(ctx, tag)
+	if err != nil {
+		return "", "", fmt.Errorf("fetching release: %w", err)
+	}
+	for _, asset := range release.Assets {
+		if asset.Name != "checksums.txt" {
+			continue
+		}
+		downloader := update.NewDownloader(&http.Client{Timeout: releaseHTTPTimeout})
+		checksums, err := downloader.FetchChecksums(ctx, asset.BrowserDownloadURL)
+		if err != nil {
+			return "", "", err
+		}
+		return release.TagName, checksums, nil
+	}
+	return "", "", fmt.Errorf("release %s has no checksums.txt", release.TagName)
+}
+
+// renderReleaseManifest builds the manifest for version from checksums. An
+// empty baseURL is the GitHub download URL of the version's tag.
+func renderReleaseManifest(format, version, checksums, baseURL string) ([]byte, error) {
+	if version == "" {
+		return nil, fmt.Errorf("no release archives in checksums; set --version")
+	}
+	version = strings.TrimPrefix(version, "v")
+	if baseURL == "" {
+		baseURL = update.DownloadBaseURL + "/v" + version
+	}
+	manifest, err := update.NewManifest(version, checksums, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	return manifest.Render(format, update.DefaultPackageInfo)
+}
+
+// checksumsVersion returns the version of the first release archive listed
+// in checksums, or "".
+func checksumsVersion(checksums string) string {
+	for _, line := range strings.Split(checksums, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		if version, _, _, ok := update.ParseAssetName(fields[1]); ok {
+			return version
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+// Package admin tests the release manifest command.
+// Related: internal/cli/admin/release.go
+// Tags: admin, cli, release, homebrew, scoop
+
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/update"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var releaseChecksums = strings.Repeat("a", 64) + "  autospec_0.9.0_Darwin_arm64.tar.gz\n" +
+	strings.Repeat("b", 64) + "  autospec_0.9.0_Linux_x86_64.tar.gz\n" +
+	strings.Repeat("c", 64) + "  autospec_0.9.0_Windows_x86_64.tar.gz\n"
+
+func TestRenderReleaseManifest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		format    string
+		version   string
+		checksums string
+		baseURL   string
+		want      string
+		wantErr   string
+	}{
+		"brew": {
+			format:    update.FormatHomebrew,
+			version:   "0.9.0",
+			checksums: releaseChecksums,
+			want:      `url "https://github.com/ariel-frischer/autospec/releases/download/v0.9.0/autospec_0.9.0_Linux_x86_64.tar.gz"`,
+		},
+		"scoop with v prefix": {
+			format:    update.FormatScoop,
+			version:   "v0.9.0",
+			checksums: releaseChecksums,
+			want:      `"version": "0.9.0"`,
+		},
+		"custom base URL": {
+			format:    update.FormatHomebrew,
+			version:   "0.9.0",
+			checksums: releaseChecksums,
+			baseURL:   "https://mirror.example.com/autospec/0.9.0",
+			want:      `url "https://mirror.example.com/autospec/0.9.0/autospec_0.9.0_Darwin_arm64.tar.gz"`,
+		},
+		"no version":     {format: update.FormatHomebrew, checksums: releaseChecksums, wantErr: "set --version"},
+		"wrong version":  {format: update.FormatHomebrew, version: "1.0.0", checksums: releaseChecksums, wantErr: "no release archives for version 1.0.0"},
+		"unknown format": {format: "apt", version: "0.9.0", checksums: releaseChecksums, wantErr: "unknown manifest format"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := renderReleaseManifest(tt.format, tt.version, tt.checksums, tt.baseURL)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, string(got), tt.want)
+		})
+	}
+}
+
+func TestChecksumsVersion(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "0.9.0", checksumsVersion("x  checksums.txt\n"+releaseChecksums))
+	assert.Empty(t, checksumsVersion("x  checksums.txt\n"))
+}
+
+func TestFetchReleaseChecksums(t *testing.T) {
+	t.Parallel()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases/tags/v0.9.0":
+			_ = json.NewEncoder(w).Encode(update.ReleaseInfo{
+				TagName: "v0.9.0",
+				Assets:  []update.Asset{{Name: "checksums.txt", BrowserDownloadURL: server.URL + "/checksums.txt"}},
+			})
+		case "/releases/latest":
+			_ = json.NewEncoder(w).Encode(update.ReleaseInfo{TagName: "v0.9.1"})
+		case "/checksums.txt":
+			fmt.Fprint(w, releaseChecksums)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	checker := update.NewChecker(0)
+	checker.SetAPIURL(server.URL + "/releases/latest")
+
+	version, checksums, err := fetchReleaseChecksums(context.Background(), checker, "0.9.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v0.9.0", version)
+	assert.Equal(t, releaseChecksums, checksums)
+
+	_, _, err = fetchReleaseChecksums(context.Background(), checker, "")
+	assert.EqualError(t, err, "release v0.9.1 has no checksums.txt")
+}
+
+func TestRunReleaseManifest_LocalChecksums(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	checksumsPath := filepath.Join(dir, "checksums.txt")
+	require.NoError(t, os.WriteFile(checksumsPath, []byte(releaseChecksums), 0o644))
+	output := filepath.Join(dir, "autospec.json")
+
+	cmd := &cobra.Command{Use: "manifest", RunE: runReleaseManifest}
+	cmd.Flags().AddFlagSet(releaseManifestCmd.Flags())
+	var stderr bytes.Buffer
+	cmd.SetErr(&stderr)
+	cmd.SetArgs([]string{"--format", "scoop", "--checksums", checksumsPath, "-o", output})
+	require.NoError(t, cmd.Execute())
+
+	data, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"bin": "autospec.exe"`)
+	assert.Contains(t, stderr.String(), "Wrote scoop manifest for 0.9.0")
+}
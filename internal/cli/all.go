@@ -9,6 +9,7 @@ import (
 	clierrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/history"
 	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/logging"
 	"github.com/ariel-frischer/autospec/internal/notify"
 	"github.com/ariel-frischer/autospec/internal/workflow"
 	"github.com/spf13/cobra"
@@ -50,7 +51,7 @@ This is equivalent to running 'autospec run -a <feature-description>'.`,
 		skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
 		maxRetries, _ := cmd.Flags().GetInt("max-retries")
 		resume, _ := cmd.Flags().GetBool("resume")
-		debug, _ := cmd.Flags().GetBool("debug")
+		debug := logging.DebugEnabled() // --debug, --log-level or logging.level
 
 		// Load configuration
 		cfg, err := config.Load(configPath)
@@ -108,7 +109,7 @@ This is equivalent to running 'autospec run -a <feature-description>'.`,
 			orchestrator := workflow.NewWorkflowOrchestrator(cfg)
 			orchestrator.Debug = debug
 			orchestrator.Executor.Debug = debug
-			orchestrator.Executor.NotificationHandler = notifHandler
+			orchestrator.SetNotificationHandler(notifHandler)
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orchestrator)
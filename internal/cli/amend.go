@@ -0,0 +1,141 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var amendCmd = &cobra.Command{
+	Use:   "amend [spec] <change-description>",
+	Short: "Apply a focused change to an existing spec and flag plan/tasks as stale",
+	Long: `Execute the /autospec.amend command to update spec.yaml with a change,
+instead of editing the YAML by hand.
+
+The amend command will:
+- Update only the parts of spec.yaml the change affects
+- Record the change in the spec's amendments section
+- Mark plan.yaml and tasks.yaml (if present) as stale
+- Show which stages need re-running
+
+Stale markers are cleared when 'autospec plan' and 'autospec tasks' regenerate
+the artifacts. 'autospec status' lists stale artifacts, and 'autospec implement'
+warns about them.
+
+Without a spec argument, the current spec is auto-detected.`,
+	Example: `  # Amend the current spec
+  autospec amend "Support login with GitHub in addition to email"
+
+  # Amend a specific spec
+  autospec amend 003 "Drop CSV export; JSON only"`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runAmend,
+}
+
+func init() {
+	amendCmd.GroupID = GroupOptionalStages
+	amendCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts (overrides config when set)")
+	rootCmd.AddCommand(amendCmd)
+}
+
+func runAmend(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
+	maxRetries, _ := cmd.Flags().GetInt("max-retries")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	specArg := ""
+	if len(args) == 2 {
+		specArg = args[0]
+	}
+	if err := shared.ApplySpecConfig(cmd, cfg, specArg); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("skip-preflight") {
+		cfg.SkipPreflight = skipPreflight
+	}
+	if cmd.Flags().Changed("max-retries") {
+		cfg.MaxRetries = maxRetries
+	}
+
+	change := strings.TrimSpace(args[len(args)-1])
+	if change == "" {
+		return fmt.Errorf("change description is required")
+	}
+
+	var metadata *spec.Metadata
+	if len(args) == 2 {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, args[0])
+		if err == nil {
+			metadata.Detection = spec.DetectionExplicit
+		}
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detect spec: %w", err)
+	}
+	PrintSpecInfo(metadata)
+
+	// amend needs the same artifacts as clarify: a valid spec.yaml
+	prereqResult := workflow.ValidateStagePrerequisites(workflow.StageClarify, metadata.Directory)
+	if !prereqResult.Valid {
+		fmt.Fprint(os.Stderr, prereqResult.ErrorMessage)
+		return NewExitError(ExitInvalidArguments)
+	}
+
+	notifHandler := notify.NewHandler(cfg.Notifications)
+	historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
+	specName := metadata.DirName()
+
+	return lifecycle.RunWithHistory(notifHandler, historyLogger, "amend", specName, func() error {
+		orch := workflow.NewWorkflowOrchestrator(cfg)
+		orch.SetNotificationHandler(notifHandler)
+		shared.ApplyOutputStyle(cmd, orch)
+
+		stale, err := orch.ExecuteAmend(specName, change)
+		if err != nil {
+			return fmt.Errorf("amend failed: %w", err)
+		}
+		printAmendResult(cmd.OutOrStdout(), specName, stale)
+		return nil
+	})
+}
+
+// printAmendResult reports the amended spec and the stages to re-run.
+func printAmendResult(out io.Writer, specName string, stale []spec.StaleArtifact) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Fprintf(out, "\n%s Amended specs/%s/spec.yaml\n", green("✓"), specName)
+	if len(stale) == 0 {
+		fmt.Fprintln(out, "No plan or tasks yet. Next: autospec plan")
+		return
+	}
+
+	stages := make([]string, 0, len(stale))
+	for _, s := range stale {
+		fmt.Fprintf(out, "%s %s is now stale\n", yellow("!"), s.Artifact)
+		stages = append(stages, "autospec "+s.Stage)
+	}
+	fmt.Fprintf(out, "Next: %s\n", strings.Join(stages, " → "))
+}
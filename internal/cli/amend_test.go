@@ -0,0 +1,72 @@
+// Package cli_test tests the amend command registration, arguments and result output.
+// Related: internal/cli/amend.go
+// Tags: cli, amend, spec, stale, command
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAmendCmdRegistration(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"amend"})
+	require.NoError(t, err)
+	assert.Equal(t, amendCmd, cmd)
+	assert.Equal(t, GroupOptionalStages, amendCmd.GroupID)
+	assert.NotNil(t, amendCmd.Flags().Lookup("max-retries"))
+}
+
+func TestAmendCmdArgs(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		wantErr bool
+	}{
+		"no args":            {args: nil, wantErr: true},
+		"change only":        {args: []string{"Add GitHub login"}},
+		"spec and change":    {args: []string{"003", "Add GitHub login"}},
+		"too many arguments": {args: []string{"003", "Add", "login"}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := amendCmd.Args(amendCmd, tt.args)
+			assert.Equal(t, tt.wantErr, err != nil, "Args(%v) error = %v", tt.args, err)
+		})
+	}
+}
+
+func TestPrintAmendResult(t *testing.T) {
+	tests := map[string]struct {
+		stale []spec.StaleArtifact
+		want  []string
+	}{
+		"no downstream artifacts": {
+			want: []string{"Amended specs/001-auth/spec.yaml", "No plan or tasks yet. Next: autospec plan"},
+		},
+		"plan and tasks stale": {
+			stale: []spec.StaleArtifact{
+				{Artifact: "plan.yaml", Stage: "plan"},
+				{Artifact: "tasks.yaml", Stage: "tasks"},
+			},
+			want: []string{
+				"plan.yaml is now stale",
+				"tasks.yaml is now stale",
+				"Next: autospec plan → autospec tasks",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			printAmendResult(&out, "001-auth", tt.stale)
+			for _, want := range tt.want {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/ariel-frischer/autospec/internal/lifecycle"
 	"github.com/ariel-frischer/autospec/internal/notify"
 	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
 	"github.com/ariel-frischer/autospec/internal/workflow"
 	"github.com/spf13/cobra"
 )
@@ -26,7 +27,8 @@ The analyze command will:
 - Auto-detect the current spec from git branch or most recent spec
 - Perform non-destructive cross-artifact consistency analysis
 - Check quality across spec.yaml, plan.yaml, and tasks.yaml
-- Report findings and recommendations
+- Write findings with severity, artifact, field and recommendation to analysis.yaml
+- With --fail-on, fail when a finding is at or above the given severity
 
 Prerequisites:
 - spec.yaml must exist (run 'autospec specify' first)
@@ -39,7 +41,10 @@ Prerequisites:
   autospec analyze "Focus on security implications"
 
   # Verify API contracts
-  autospec analyze "Verify API contracts"`,
+  autospec analyze "Verify API contracts"
+
+  # Fail (and block implement) on any CRITICAL finding
+  autospec analyze --fail-on critical`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true // Don't show help for execution errors
 		// Get optional prompt from args
@@ -59,12 +64,20 @@ Prerequisites:
 			clierrors.PrintError(cliErr)
 			return cliErr
 		}
+		if err := shared.ApplySpecConfig(cmd, cfg, ""); err != nil {
+			return err
+		}
 
 		// Override skip-preflight from flag if set
 		if cmd.Flags().Changed("skip-preflight") {
 			cfg.SkipPreflight = skipPreflight
 		}
 
+		// Override analysis gate severity from flag (config: analyze_fail_on)
+		if err := applyFailOnFlag(cmd, cfg); err != nil {
+			return err
+		}
+
 		// Check if constitution exists (required for analyze)
 		constitutionCheck := workflow.CheckConstitutionExists()
 		if !constitutionCheck.Exists {
@@ -92,13 +105,13 @@ Prerequisites:
 		// Create notification handler and history logger
 		notifHandler := notify.NewHandler(cfg.Notifications)
 		historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
-		specName := fmt.Sprintf("%s-%s", metadata.Number, metadata.Name)
+		specName := metadata.DirName()
 
 		// Wrap command execution with lifecycle for timing, notification, and history
 		return lifecycle.RunWithHistory(notifHandler, historyLogger, "analyze", specName, func() error {
 			// Create workflow orchestrator
 			orch := workflow.NewWorkflowOrchestrator(cfg)
-			orch.Executor.NotificationHandler = notifHandler
+			orch.SetNotificationHandler(notifHandler)
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orch)
@@ -116,5 +129,21 @@ Prerequisites:
 func init() {
 	analyzeCmd.GroupID = GroupOptionalStages
 	rootCmd.AddCommand(analyzeCmd)
-	// Note: No --max-retries flag - analyze doesn't produce artifacts that need validation/retry
+	// Note: No --max-retries flag - analyze runs interactively without a retry loop
+	analyzeCmd.Flags().String("fail-on", "", "Fail on findings at or above this severity: critical, high, medium, low (overrides config analyze_fail_on)")
+}
+
+// applyFailOnFlag copies a --fail-on flag value into cfg.AnalyzeFailOn.
+// An explicit empty value disables the gate for this run.
+func applyFailOnFlag(cmd *cobra.Command, cfg *config.Configuration) error {
+	if !cmd.Flags().Changed("fail-on") {
+		return nil
+	}
+	failOn, _ := cmd.Flags().GetString("fail-on")
+	if _, err := validation.ParseSeverity(failOn); err != nil {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("invalid --fail-on: %w", err)
+	}
+	cfg.AnalyzeFailOn = strings.ToLower(failOn)
+	return nil
 }
@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -120,3 +122,35 @@ func TestAnalyze_InheritedFlags(t *testing.T) {
 	f = rootCmd.PersistentFlags().Lookup("config")
 	require.NotNil(t, f)
 }
+
+func TestApplyFailOnFlag(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		initial string
+		want    string
+		wantErr bool
+	}{
+		"flag not set keeps config": {initial: "high", want: "high"},
+		"flag overrides config":     {args: []string{"--fail-on", "critical"}, initial: "low", want: "critical"},
+		"flag is case-insensitive":  {args: []string{"--fail-on", "HIGH"}, want: "high"},
+		"empty flag disables gate":  {args: []string{"--fail-on", ""}, initial: "critical", want: ""},
+		"invalid severity":          {args: []string{"--fail-on", "blocker"}, wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			cmd.Flags().String("fail-on", "", "")
+			require.NoError(t, cmd.Flags().Parse(tc.args))
+			cfg := &config.Configuration{AnalyzeFailOn: tc.initial}
+
+			err := applyFailOnFlag(cmd, cfg)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, cfg.AnalyzeFailOn)
+		})
+	}
+}
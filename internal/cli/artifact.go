@@ -7,7 +7,9 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/spec"
 	"github.com/ariel-frischer/autospec/internal/validation"
 	"github.com/fatih/color"
@@ -17,6 +19,7 @@ import (
 var (
 	artifactSchemaFlag bool
 	artifactFixFlag    bool
+	artifactStrictFlag bool
 )
 
 var artifactCmd = &cobra.Command{
@@ -42,6 +45,10 @@ Validates:
   - Required fields present for artifact type
   - Field types correct (strings, lists, enums)
   - Cross-references valid (e.g. task dependencies exist)
+  - plan.yaml and tasks.yaml are up to date with the spec.yaml/plan.yaml
+    they were generated from (warning, or error with --strict)
+  - No unknown fields or missing optional sections (warnings in lenient
+    mode, errors with --strict or validation_mode: strict)
 
 Output:
   - Shows which spec is being used (with fallback indicator if applicable)
@@ -71,13 +78,21 @@ Exit Codes:
   autospec artifact constitution --schema
 
   # Auto-fix common issues
-  autospec artifact specs/001-feature/plan.yaml --fix`,
+  autospec artifact specs/001-feature/plan.yaml --fix
+
+  # Fail on unknown fields, missing optional sections, or a tasks.yaml
+  # generated from an older spec or plan
+  autospec artifact tasks --strict`,
 	Args:          cobra.RangeArgs(1, 2),
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		configPath, _ := cmd.Flags().GetString("config")
-		return runArtifactCommand(args, configPath, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		var jsonCmd *cobra.Command
+		if shared.WantsJSON(cmd) {
+			jsonCmd = cmd
+		}
+		return runArtifactCommand(args, configPath, cmd.OutOrStdout(), cmd.ErrOrStderr(), jsonCmd)
 	},
 }
 
@@ -86,6 +101,7 @@ func init() {
 	rootCmd.AddCommand(artifactCmd)
 	artifactCmd.Flags().BoolVar(&artifactSchemaFlag, "schema", false, "Print the expected schema for the artifact type")
 	artifactCmd.Flags().BoolVar(&artifactFixFlag, "fix", false, "Auto-fix common issues (missing optional fields, formatting)")
+	artifactCmd.Flags().BoolVar(&artifactStrictFlag, "strict", false, "Treat unknown fields, missing optional sections and out-of-date plan/tasks as errors (validation_mode: strict)")
 }
 
 // artifactArgs represents parsed artifact command arguments.
@@ -170,14 +186,18 @@ func resolveArtifactPath(artType validation.ArtifactType, specsDir string) (stri
 	return artifactPath, metadata, nil
 }
 
-// runArtifactCommand executes the artifact validation command.
-func runArtifactCommand(args []string, configPath string, out, errOut io.Writer) error {
+// runArtifactCommand executes the artifact validation command. With a
+// jsonCmd, the validation result is written as its --json envelope instead.
+func runArtifactCommand(args []string, configPath string, out, errOut io.Writer, jsonCmd *cobra.Command) error {
 	// Load configuration
 	cfg, err := config.Load(configPath)
 	if err != nil {
 		fmt.Fprintf(errOut, "Error loading config: %v\n", err)
 		return NewExitError(ExitInvalidArguments)
 	}
+	if artifactStrictFlag {
+		_ = validation.SetMode(validation.ModeStrict)
+	}
 
 	// Parse arguments
 	parsed, err := parseArtifactArgs(args, cfg.SpecsDir)
@@ -227,11 +247,28 @@ func runArtifactCommand(args []string, configPath string, out, errOut io.Writer)
 
 	// Run validation
 	result := validator.Validate(parsed.filePath)
+	addLineageFindings(result, parsed.filePath, parsed.artType, validation.Mode() == validation.ModeStrict)
 
 	// Format and display results
+	if jsonCmd != nil {
+		return writeArtifactJSON(jsonCmd, result, parsed.filePath, parsed.artType)
+	}
 	return formatValidationResult(result, parsed.filePath, parsed.artType, out, errOut)
 }
 
+// addLineageFindings checks whether a plan.yaml or tasks.yaml is out of date
+// with the upstream artifacts it was generated from. Findings are warnings,
+// or errors when strict is set.
+func addLineageFindings(result *validation.ValidationResult, filePath string, artType validation.ArtifactType, strict bool) {
+	for _, w := range validation.CheckSourceHashes(filePath, artType) {
+		if !strict {
+			result.AddWarning(w)
+			continue
+		}
+		result.AddError(&validation.ValidationError{Path: w.Path, Line: w.Line, Message: w.Message, Hint: w.Hint})
+	}
+}
+
 // printSpecIdentification prints the spec identification message when using auto-detection.
 func printSpecIdentification(parsed *artifactArgs, out io.Writer) {
 	if parsed.specMetadata == nil {
@@ -255,37 +292,11 @@ func printSchema(artType validation.ArtifactType, out io.Writer) error {
 	fmt.Fprintf(out, "Fields:\n")
 	fmt.Fprintf(out, "%s\n", strings.Repeat("-", 40))
 
-	for _, field := range schema.Fields {
-		printSchemaField(field, "", out)
-	}
+	validation.WriteSchemaFields(out, schema.Fields, "")
 
 	return nil
 }
 
-// printSchemaField prints a single schema field with indentation.
-func printSchemaField(field validation.SchemaField, indent string, out io.Writer) {
-	required := ""
-	if field.Required {
-		required = " (required)"
-	}
-
-	typeStr := string(field.Type)
-	if len(field.Enum) > 0 {
-		typeStr = fmt.Sprintf("enum[%s]", strings.Join(field.Enum, ", "))
-	}
-
-	fmt.Fprintf(out, "%s%s: %s%s\n", indent, field.Name, typeStr, required)
-
-	if field.Description != "" {
-		fmt.Fprintf(out, "%s  # %s\n", indent, field.Description)
-	}
-
-	// Print children for nested fields
-	for _, child := range field.Children {
-		printSchemaField(child, indent+"  ", out)
-	}
-}
-
 // formatValidationResult formats and displays the validation result.
 func formatValidationResult(result *validation.ValidationResult, filePath string, artType validation.ArtifactType, out, errOut io.Writer) error {
 	if result.Valid {
@@ -352,6 +363,72 @@ func formatValidationResult(result *validation.ValidationResult, filePath string
 	return NewExitError(ExitValidationFailed)
 }
 
+// artifactResult is the --json result of artifact.
+type artifactResult struct {
+	Path     string                `json:"path"`
+	Type     string                `json:"type"`
+	Valid    bool                  `json:"valid"`
+	Counts   map[string]int        `json:"counts,omitempty"`
+	Errors   []artifactErrorJSON   `json:"errors"`
+	Warnings []artifactWarningJSON `json:"warnings"`
+}
+
+type artifactErrorJSON struct {
+	Path     string `json:"path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Column   int    `json:"column,omitempty"`
+	Message  string `json:"message"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+type artifactWarningJSON struct {
+	Path    string `json:"path,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+// writeArtifactJSON writes the validation result as the --json envelope. An
+// invalid artifact adds a SCHEMA_VIOLATION error and exits with
+// ExitValidationFailed.
+func writeArtifactJSON(cmd *cobra.Command, result *validation.ValidationResult, filePath string, artType validation.ArtifactType) error {
+	res := artifactResult{
+		Path:     filePath,
+		Type:     string(artType),
+		Valid:    result.Valid,
+		Errors:   make([]artifactErrorJSON, 0, len(result.Errors)),
+		Warnings: make([]artifactWarningJSON, 0, len(result.Warnings)),
+	}
+	if result.Summary != nil {
+		res.Counts = result.Summary.Counts
+	}
+	for _, e := range result.Errors {
+		res.Errors = append(res.Errors, artifactErrorJSON{
+			Path:     e.Path,
+			Line:     e.Line,
+			Column:   e.Column,
+			Message:  e.Message,
+			Expected: e.Expected,
+			Actual:   e.Actual,
+			Hint:     e.Hint,
+		})
+	}
+	for _, w := range result.Warnings {
+		res.Warnings = append(res.Warnings, artifactWarningJSON{Path: w.Path, Line: w.Line, Message: w.Message, Hint: w.Hint})
+	}
+
+	if result.Valid {
+		return shared.WriteJSON(cmd, res)
+	}
+	cliErr := clierrors.Classify(clierrors.ErrSchemaViolation.Errorf("%s has %d error(s)", filePath, len(result.Errors)))
+	if err := shared.WriteJSON(cmd, res, cliErr); err != nil {
+		return err
+	}
+	return NewExitError(ExitValidationFailed)
+}
+
 // runAutoFix runs the auto-fix operation on an artifact file.
 func runAutoFix(filePath string, artType validation.ArtifactType, out, errOut io.Writer) error {
 	green := color.New(color.FgGreen).SprintFunc()
@@ -0,0 +1,243 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var artifactIngestCmd = &cobra.Command{
+	Use:   "ingest <file>",
+	Short: "Validate and install an artifact obtained without a CLI agent",
+	Long: `Validate and install an artifact written outside autospec, such as the
+reply to a prompt from 'autospec prompt export' pasted into a web chat UI.
+
+The artifact type comes from --type, the file name (spec.yaml, plan.yaml, ...)
+or the _meta.artifact_type field. A surrounding markdown code fence, as copied
+from a chat reply, is removed. The artifact is validated against its schema
+and only installed when valid:
+
+  spec, plan, tasks, analysis  → <specs_dir>/<spec>/<type>.yaml
+  constitution                 → .autospec/memory/constitution.yaml
+
+The spec directory is --spec, the spec detected from the git branch, or for
+spec.yaml the feature.branch field (the directory is created if needed).
+plan.yaml and tasks.yaml get the source hashes a normal run records.`,
+	Example: `  # Install a plan saved from a web UI reply
+  autospec artifact ingest ~/Downloads/plan.yaml
+
+  # Install a new spec; the directory comes from feature.branch
+  autospec artifact ingest spec.yaml
+
+  # File name does not identify the type
+  autospec artifact ingest reply.txt --type tasks --spec 003-dark-mode --force`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		typeName, _ := cmd.Flags().GetString("type")
+		specName, _ := cmd.Flags().GetString("spec")
+		force, _ := cmd.Flags().GetBool("force")
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error loading config: %v\n", err)
+			return NewExitError(ExitInvalidArguments)
+		}
+		return runArtifactIngest(ingestOptions{
+			source:   args[0],
+			typeName: typeName,
+			specsDir: cfg.SpecsDir,
+			specName: specName,
+			force:    force,
+		}, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	},
+}
+
+func init() {
+	artifactIngestCmd.Flags().String("type", "", "Artifact type (default: inferred from file name or _meta.artifact_type)")
+	artifactIngestCmd.Flags().String("spec", "", "Spec directory name (default: detected)")
+	artifactIngestCmd.Flags().Bool("force", false, "Replace an existing artifact")
+	artifactCmd.AddCommand(artifactIngestCmd)
+}
+
+// ingestOptions holds the inputs of 'autospec artifact ingest'.
+type ingestOptions struct {
+	source   string
+	typeName string
+	specsDir string
+	specName string
+	force    bool
+}
+
+// runArtifactIngest validates the source artifact in its destination
+// directory and moves it into place.
+func runArtifactIngest(opts ingestOptions, out, errOut io.Writer) error {
+	data, err := os.ReadFile(opts.source)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return NewExitError(ExitInvalidArguments)
+	}
+	data = []byte(stripCodeFence(string(data)))
+
+	artType, err := ingestArtifactType(opts.typeName, opts.source, data)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return NewExitError(ExitInvalidArguments)
+	}
+	if artType == validation.ArtifactTypeChecklist {
+		fmt.Fprintln(errOut, "Error: checklists cannot be ingested; copy them to <spec>/checklists/ and check them with 'autospec artifact checklist <path>'")
+		return NewExitError(ExitInvalidArguments)
+	}
+
+	dest, err := ingestDestination(artType, opts, data)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return NewExitError(ExitInvalidArguments)
+	}
+	if _, err := os.Stat(dest); err == nil && !opts.force {
+		fmt.Fprintf(errOut, "Error: %s already exists\nHint: Pass --force to replace it\n", dest)
+		return NewExitError(ExitInvalidArguments)
+	}
+
+	// Validate a copy next to the destination so cross-artifact checks see
+	// the sibling artifacts, then install the validated content.
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(dest), err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".ingest-*.yaml")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	tmp.Close()
+	defer os.Remove(tmpPath)
+	if err := fsutil.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("writing temporary file: %w", err)
+	}
+
+	validator, err := validation.NewArtifactValidator(artType)
+	if err != nil {
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return NewExitError(ExitInvalidArguments)
+	}
+	result := validator.Validate(tmpPath)
+	if !result.Valid {
+		fmt.Fprintf(errOut, "Not installed: %s is not a valid %s artifact\n\n", opts.source, artType)
+		return formatValidationResult(result, opts.source, artType, out, errOut)
+	}
+
+	if err := fsutil.WriteFile(dest, data, 0o644); err != nil {
+		return fmt.Errorf("installing %s: %w", dest, err)
+	}
+	if artType != validation.ArtifactTypeConstitution {
+		recordIngestLineage(dest, artType, errOut)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Fprintf(out, "%s Installed %s as %s\n", green("✓"), opts.source, dest)
+	displayWarnings(result, out)
+	if next := nextOfflineStage(artType); next != "" {
+		fmt.Fprintf(out, "Next: autospec prompt export %s\n", next)
+	}
+	return nil
+}
+
+// ingestArtifactType resolves the artifact type from the flag, the file name
+// or the content's _meta.artifact_type, in that order.
+func ingestArtifactType(typeName, source string, data []byte) (validation.ArtifactType, error) {
+	if typeName != "" {
+		return validation.ParseArtifactType(typeName)
+	}
+	if artType, err := validation.InferArtifactTypeFromFilename(source); err == nil {
+		return artType, nil
+	}
+	var doc struct {
+		Meta struct {
+			ArtifactType string `yaml:"artifact_type"`
+		} `yaml:"_meta"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err == nil && doc.Meta.ArtifactType != "" {
+		return validation.ParseArtifactType(doc.Meta.ArtifactType)
+	}
+	return "", fmt.Errorf("cannot tell the artifact type of %s\nHint: Pass --type (%s)", source, strings.Join(validation.ValidArtifactTypes(), ", "))
+}
+
+// ingestDestination returns where an artifact of artType is installed.
+func ingestDestination(artType validation.ArtifactType, opts ingestOptions, data []byte) (string, error) {
+	filename := string(artType) + ".yaml"
+	if artType == validation.ArtifactTypeConstitution {
+		return filepath.Join(".autospec", "memory", filename), nil
+	}
+	if opts.specName != "" {
+		return filepath.Join(opts.specsDir, opts.specName, filename), nil
+	}
+	if artType == validation.ArtifactTypeSpec {
+		var doc struct {
+			Feature struct {
+				Branch string `yaml:"branch"`
+			} `yaml:"feature"`
+		}
+		if err := yaml.Unmarshal(data, &doc); err == nil && doc.Feature.Branch != "" {
+			branch := filepath.Base(filepath.Clean(doc.Feature.Branch))
+			if branch == "." || branch == ".." || branch != doc.Feature.Branch {
+				return "", fmt.Errorf("feature.branch %q is not a spec directory name; pass --spec", doc.Feature.Branch)
+			}
+			return filepath.Join(opts.specsDir, branch, filename), nil
+		}
+	}
+	metadata, err := spec.DetectCurrentSpec(opts.specsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect spec: %w\nHint: Run from a spec branch or pass --spec", err)
+	}
+	return filepath.Join(metadata.Directory, filename), nil
+}
+
+// recordIngestLineage records source hashes and clears staleness, as a
+// normal plan or tasks run does. Failures only warn: the artifact is valid.
+func recordIngestLineage(dest string, artType validation.ArtifactType, errOut io.Writer) {
+	if err := validation.StampSourceHashes(dest, artType); err != nil {
+		fmt.Fprintf(errOut, "Warning: recording source hashes: %v\n", err)
+	}
+	if err := spec.ClearStale(filepath.Dir(dest), filepath.Base(dest)); err != nil {
+		fmt.Fprintf(errOut, "Warning: %v\n", err)
+	}
+}
+
+// nextOfflineStage names the stage to export after ingesting artType.
+func nextOfflineStage(artType validation.ArtifactType) string {
+	switch artType {
+	case validation.ArtifactTypeConstitution:
+		return `specify "<feature description>"`
+	case validation.ArtifactTypeSpec:
+		return "plan"
+	case validation.ArtifactTypePlan:
+		return "tasks"
+	}
+	return ""
+}
+
+// stripCodeFence removes a markdown code fence wrapping the whole content.
+func stripCodeFence(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if !strings.HasPrefix(trimmed, "```") || !strings.HasSuffix(trimmed, "```") {
+		return content
+	}
+	firstLine := strings.IndexByte(trimmed, '\n')
+	if firstLine < 0 {
+		return content
+	}
+	body := strings.TrimSuffix(trimmed[firstLine+1:], "```")
+	return strings.TrimRight(body, " \t\n") + "\n"
+}
@@ -0,0 +1,166 @@
+// Package cli tests artifact ingestion for workflows without a CLI agent.
+// Related: internal/cli/artifact_ingest.go
+// Tags: cli, artifact, ingest, offline
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFixture(t *testing.T, artType, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "validation", "testdata", artType, name))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	return data
+}
+
+func TestArtifactIngest_SpecFromChatReply(t *testing.T) {
+	specsDir := filepath.Join(t.TempDir(), "specs")
+	source := filepath.Join(t.TempDir(), "reply.md")
+	reply := "```yaml\n" + string(readFixture(t, "spec", "valid.yaml")) + "```\n"
+	if err := os.WriteFile(source, []byte(reply), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := runArtifactIngest(ingestOptions{source: source, typeName: "spec", specsDir: specsDir}, &stdout, &stderr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v\nstderr: %s", err, stderr.String())
+	}
+
+	dest := filepath.Join(specsDir, "001-example-feature", "spec.yaml")
+	installed, err := os.ReadFile(dest)
+	if err != nil {
+		t.Fatalf("spec not installed from feature.branch: %v", err)
+	}
+	if strings.Contains(string(installed), "```") {
+		t.Error("code fence should be stripped")
+	}
+	if !strings.Contains(stdout.String(), "Next: autospec prompt export plan") {
+		t.Errorf("stdout should suggest the next stage, got: %s", stdout.String())
+	}
+}
+
+func TestArtifactIngest_PlanStampsLineage(t *testing.T) {
+	specsDir := t.TempDir()
+	specDir := filepath.Join(specsDir, "001-example-feature")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "spec.yaml"), readFixture(t, "spec", "valid.yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := filepath.Join(t.TempDir(), "plan.yaml")
+	if err := os.WriteFile(source, readFixture(t, "plan", "valid.yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := ingestOptions{source: source, specsDir: specsDir, specName: "001-example-feature"}
+	var stdout, stderr bytes.Buffer
+	if err := runArtifactIngest(opts, &stdout, &stderr); err != nil {
+		t.Fatalf("unexpected error: %v\nstderr: %s", err, stderr.String())
+	}
+	installed, err := os.ReadFile(filepath.Join(specDir, "plan.yaml"))
+	if err != nil {
+		t.Fatalf("plan not installed: %v", err)
+	}
+	if !strings.Contains(string(installed), "spec_hash") {
+		t.Error("ingested plan should record the spec hash")
+	}
+
+	// A second ingest needs --force
+	stderr.Reset()
+	err = runArtifactIngest(opts, &stdout, &stderr)
+	if code := ExitCode(err); code != ExitInvalidArguments {
+		t.Errorf("exit code = %d, want %d", code, ExitInvalidArguments)
+	}
+	if !strings.Contains(stderr.String(), "--force") {
+		t.Errorf("stderr should mention --force, got: %s", stderr.String())
+	}
+
+	opts.force = true
+	if err := runArtifactIngest(opts, &stdout, &stderr); err != nil {
+		t.Errorf("--force should replace the plan: %v", err)
+	}
+}
+
+func TestArtifactIngest_InvalidArtifactNotInstalled(t *testing.T) {
+	specsDir := t.TempDir()
+	source := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(source, readFixture(t, "spec", "missing_feature.yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := runArtifactIngest(ingestOptions{source: source, specsDir: specsDir, specName: "002-x"}, &stdout, &stderr)
+	if code := ExitCode(err); code != ExitValidationFailed {
+		t.Errorf("exit code = %d, want %d", code, ExitValidationFailed)
+	}
+	if !strings.Contains(stderr.String(), "Not installed") {
+		t.Errorf("stderr should say the artifact was not installed, got: %s", stderr.String())
+	}
+	entries, _ := os.ReadDir(filepath.Join(specsDir, "002-x"))
+	if len(entries) != 0 {
+		t.Errorf("nothing should be left in the spec directory, found %d entries", len(entries))
+	}
+}
+
+func TestIngestArtifactType(t *testing.T) {
+	tests := map[string]struct {
+		typeName string
+		source   string
+		content  string
+		want     string
+		wantErr  bool
+	}{
+		"flag wins":        {typeName: "tasks", source: "plan.yaml", want: "tasks"},
+		"file name":        {source: "/tmp/analysis.yaml", want: "analysis"},
+		"meta field":       {source: "reply.txt", content: "_meta:\n  artifact_type: \"plan\"\n", want: "plan"},
+		"unknown":          {source: "reply.txt", content: "foo: bar\n", wantErr: true},
+		"invalid flag":     {typeName: "readme", source: "spec.yaml", wantErr: true},
+		"download renamed": {source: "plan (1).yaml", content: "_meta:\n  artifact_type: tasks\n", want: "tasks"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ingestArtifactType(tt.typeName, tt.source, []byte(tt.content))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got type %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStripCodeFence(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"fenced":     {in: "```yaml\nfoo: bar\n```", want: "foo: bar\n"},
+		"fenced pad": {in: "\n```\nfoo: bar\n```\n\n", want: "foo: bar\n"},
+		"plain":      {in: "foo: bar\n", want: "foo: bar\n"},
+		"inner only": {in: "foo: |\n  ```go\n  x\n  ```\n", want: "foo: |\n  ```go\n  x\n  ```\n"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := stripCodeFence(tt.in); got != tt.want {
+				t.Errorf("stripCodeFence() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -16,7 +16,7 @@ import (
 
 func TestArtifactCommand_InvalidType(t *testing.T) {
 	var stdout, stderr bytes.Buffer
-	err := runArtifactCommand([]string{"unknown"}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{"unknown"}, "", &stdout, &stderr, nil)
 
 	if err == nil {
 		t.Error("expected error for invalid artifact type")
@@ -33,7 +33,7 @@ func TestArtifactCommand_InvalidType(t *testing.T) {
 
 func TestArtifactCommand_MissingFile(t *testing.T) {
 	var stdout, stderr bytes.Buffer
-	err := runArtifactCommand([]string{"spec", "nonexistent.yaml"}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{"spec", "nonexistent.yaml"}, "", &stdout, &stderr, nil)
 
 	if err == nil {
 		t.Error("expected error for missing file")
@@ -51,7 +51,7 @@ func TestArtifactCommand_MissingFile(t *testing.T) {
 func TestArtifactCommand_ValidSpec(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	testFile := filepath.Join("..", "validation", "testdata", "spec", "valid.yaml")
-	err := runArtifactCommand([]string{"spec", testFile}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{"spec", testFile}, "", &stdout, &stderr, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 		t.Logf("stderr: %s", stderr.String())
@@ -69,7 +69,7 @@ func TestArtifactCommand_ValidSpec(t *testing.T) {
 func TestArtifactCommand_InvalidSpec(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	testFile := filepath.Join("..", "validation", "testdata", "spec", "missing_feature.yaml")
-	err := runArtifactCommand([]string{"spec", testFile}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{"spec", testFile}, "", &stdout, &stderr, nil)
 
 	if err == nil {
 		t.Error("expected error for invalid spec")
@@ -87,7 +87,7 @@ func TestArtifactCommand_InvalidSpec(t *testing.T) {
 func TestArtifactCommand_ValidPlan(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	testFile := filepath.Join("..", "validation", "testdata", "plan", "valid.yaml")
-	err := runArtifactCommand([]string{"plan", testFile}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{"plan", testFile}, "", &stdout, &stderr, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 		t.Logf("stderr: %s", stderr.String())
@@ -101,7 +101,7 @@ func TestArtifactCommand_ValidPlan(t *testing.T) {
 func TestArtifactCommand_ValidTasks(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	testFile := filepath.Join("..", "validation", "testdata", "tasks", "valid.yaml")
-	err := runArtifactCommand([]string{"tasks", testFile}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{"tasks", testFile}, "", &stdout, &stderr, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 		t.Logf("stderr: %s", stderr.String())
@@ -138,7 +138,7 @@ func TestArtifactCommand_SchemaSpec(t *testing.T) {
 	defer func() { artifactSchemaFlag = oldSchemaFlag }()
 
 	var stdout, stderr bytes.Buffer
-	err := runArtifactCommand([]string{"spec"}, configFile, &stdout, &stderr)
+	err := runArtifactCommand([]string{"spec"}, configFile, &stdout, &stderr, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 		t.Logf("stderr: %s", stderr.String())
@@ -181,7 +181,7 @@ func TestArtifactCommand_SchemaPlan(t *testing.T) {
 	defer func() { artifactSchemaFlag = oldSchemaFlag }()
 
 	var stdout, stderr bytes.Buffer
-	err := runArtifactCommand([]string{"plan"}, configFile, &stdout, &stderr)
+	err := runArtifactCommand([]string{"plan"}, configFile, &stdout, &stderr, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 		t.Logf("stderr: %s", stderr.String())
@@ -220,7 +220,7 @@ func TestArtifactCommand_SchemaTasks(t *testing.T) {
 	defer func() { artifactSchemaFlag = oldSchemaFlag }()
 
 	var stdout, stderr bytes.Buffer
-	err := runArtifactCommand([]string{"tasks"}, configFile, &stdout, &stderr)
+	err := runArtifactCommand([]string{"tasks"}, configFile, &stdout, &stderr, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 		t.Logf("stderr: %s", stderr.String())
@@ -239,7 +239,7 @@ func TestArtifactCommand_SchemaTasks(t *testing.T) {
 func TestArtifactCommand_CircularDependency(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	testFile := filepath.Join("..", "validation", "testdata", "tasks", "invalid_dep_circular.yaml")
-	err := runArtifactCommand([]string{"tasks", testFile}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{"tasks", testFile}, "", &stdout, &stderr, nil)
 
 	if err == nil {
 		t.Error("expected error for circular dependency")
@@ -391,7 +391,7 @@ technical_context:
 	}
 
 	var stdout, stderr bytes.Buffer
-	err := runArtifactCommand([]string{planFile}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{planFile}, "", &stdout, &stderr, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 		t.Logf("stderr: %s", stderr.String())
@@ -405,7 +405,7 @@ technical_context:
 // Test unrecognized filename error
 func TestArtifactCommand_UnrecognizedFilename(t *testing.T) {
 	var stdout, stderr bytes.Buffer
-	err := runArtifactCommand([]string{"config.yaml"}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{"config.yaml"}, "", &stdout, &stderr, nil)
 
 	if err == nil {
 		t.Error("expected error for unrecognized filename")
@@ -444,7 +444,7 @@ technical_context:
 	}
 
 	var stdout, stderr bytes.Buffer
-	err := runArtifactCommand([]string{ymlFile}, "", &stdout, &stderr)
+	err := runArtifactCommand([]string{ymlFile}, "", &stdout, &stderr, nil)
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 		t.Logf("stderr: %s", stderr.String())
@@ -454,3 +454,44 @@ technical_context:
 		t.Errorf("stdout should contain 'is valid', got: %s", stdout.String())
 	}
 }
+
+func TestAddLineageFindings(t *testing.T) {
+	dir := t.TempDir()
+	specPath := filepath.Join(dir, "spec.yaml")
+	planPath := filepath.Join(dir, "plan.yaml")
+	writeFile := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(specPath, "feature:\n  branch: \"001-test\"\n")
+	writeFile(planPath, "plan:\n  branch: \"001-test\"\n")
+	if err := validation.StampSourceHashes(planPath, validation.ArtifactTypePlan); err != nil {
+		t.Fatal(err)
+	}
+
+	// Up to date: no findings
+	result := &validation.ValidationResult{Valid: true}
+	addLineageFindings(result, planPath, validation.ArtifactTypePlan, true)
+	if !result.Valid || len(result.Warnings) != 0 {
+		t.Errorf("up-to-date plan: Valid=%v, %d warnings", result.Valid, len(result.Warnings))
+	}
+
+	writeFile(specPath, "feature:\n  branch: \"001-test\"\n  summary: changed\n")
+
+	result = &validation.ValidationResult{Valid: true}
+	addLineageFindings(result, planPath, validation.ArtifactTypePlan, false)
+	if !result.Valid || len(result.Warnings) != 1 {
+		t.Fatalf("drift without --strict: Valid=%v, %d warnings; want a single warning", result.Valid, len(result.Warnings))
+	}
+	if !strings.Contains(result.Warnings[0].Message, "spec.yaml changed after plan.yaml was generated") {
+		t.Errorf("warning = %q", result.Warnings[0].Message)
+	}
+
+	result = &validation.ValidationResult{Valid: true}
+	addLineageFindings(result, planPath, validation.ArtifactTypePlan, true)
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Path != "_meta.spec_hash" {
+		t.Errorf("drift with --strict: Valid=%v, errors=%v; want one _meta.spec_hash error", result.Valid, result.Errors)
+	}
+}
@@ -0,0 +1,296 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/board"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// boardRefreshInterval is how often the board checks for terminal resizes
+// and for tasks.yaml changes made outside the board.
+const boardRefreshInterval = time.Second
+
+var boardCmd = &cobra.Command{
+	Use:   "board [spec]",
+	Short: "Show tasks.yaml as an interactive kanban board",
+	Long: `Show a spec's tasks in Pending, InProgress, Blocked and Completed columns
+and work with them from the keyboard:
+
+  arrows, h/j/k/l  move between tasks and columns
+  enter, space     show the task's details
+  p, i, c          set the task to Pending, InProgress or Completed
+  b                block the task (asks for a reason)
+  x                run 'autospec implement --task' for the task
+  R                reload tasks.yaml
+  q, ctrl+c        quit
+
+Status changes are written like 'autospec task set-status': each is recorded
+in the task's status_history as a manual change by your git user.name. The
+board follows changes other processes make to tasks.yaml.
+
+Implementing a task leaves the board while the session runs and returns to it
+afterwards. The task's dependencies must be completed.
+
+Without a spec argument, the current spec is auto-detected.`,
+	Example: `  # Board for the current spec
+  autospec board
+
+  # Board for spec 003
+  autospec board 003`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runBoard,
+}
+
+func init() {
+	boardCmd.GroupID = GroupGettingStarted
+	rootCmd.AddCommand(boardCmd)
+}
+
+func runBoard(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	var metadata *spec.Metadata
+	if len(args) == 1 {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, args[0])
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detect spec: %w", err)
+	}
+
+	tasksPath := validation.GetTasksFilePath(metadata.Directory)
+	tasks, err := validation.GetAllTasks(tasksPath)
+	if err != nil {
+		return fmt.Errorf("loading tasks: %w\nRun 'autospec tasks' first to generate tasks", err)
+	}
+
+	inFd, outFd := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return fmt.Errorf("autospec board needs an interactive terminal; use 'autospec task list' instead")
+	}
+
+	s := &boardSession{
+		board:     board.New(metadata.DirName(), tasks),
+		specName:  metadata.DirName(),
+		tasksPath: tasksPath,
+		inFd:      inFd,
+		outFd:     outFd,
+		out:       os.Stdout,
+		color:     output.Default().ColorEnabled(),
+	}
+	if cmd.Flags().Changed("config") {
+		s.implementArgs = []string{"--config", configPath}
+	}
+	return s.run()
+}
+
+// boardSession runs a board on the terminal.
+type boardSession struct {
+	board         *board.Board
+	specName      string
+	tasksPath     string
+	implementArgs []string // extra arguments for implement sessions
+	inFd, outFd   int
+	out           io.Writer
+	color         bool
+
+	saved   *term.State
+	modTime time.Time
+	width   int
+	height  int
+}
+
+// run shows the board until the user quits.
+func (s *boardSession) run() error {
+	if err := s.enter(); err != nil {
+		return err
+	}
+	defer s.leave()
+	s.modTime = s.tasksModTime()
+
+	// The reader only reads when asked, so no key meant for an implement
+	// session is consumed by the board.
+	input := make(chan []byte)
+	next := make(chan struct{}, 1)
+	defer close(next)
+	go func() {
+		buf := make([]byte, 64)
+		for range next {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				close(input)
+				return
+			}
+			input <- append([]byte(nil), buf[:n]...)
+		}
+	}()
+	next <- struct{}{}
+
+	ticker := time.NewTicker(boardRefreshInterval)
+	defer ticker.Stop()
+	s.draw()
+	for {
+		select {
+		case <-ticker.C:
+			if s.refresh() {
+				s.draw()
+			}
+		case data, ok := <-input:
+			if !ok {
+				return nil
+			}
+			for _, k := range board.ParseKeys(data) {
+				if quit, err := s.apply(s.board.HandleKey(k)); quit || err != nil {
+					return err
+				}
+			}
+			s.draw()
+			next <- struct{}{}
+		}
+	}
+}
+
+// apply carries out a board action, reporting whether the board should close.
+func (s *boardSession) apply(a board.Action) (quit bool, err error) {
+	switch a.Kind {
+	case board.ActionQuit:
+		return true, nil
+	case board.ActionSetStatus:
+		change := statusChange{To: a.Status, By: resolveChangeAuthor(""), At: time.Now().UTC(), Reason: a.Reason}
+		result, err := setTaskStatusInFile(s.tasksPath, a.TaskID, change)
+		if err != nil {
+			s.board.Message = "Error: " + err.Error()
+		} else {
+			s.board.Message = fmt.Sprintf("%s: %s -> %s", result.taskID, result.previousStatus, a.Status)
+		}
+		s.reload()
+	case board.ActionImplement:
+		if err := s.implement(a.TaskID); err != nil {
+			return true, err
+		}
+		s.reload()
+	case board.ActionReload:
+		s.reload()
+		s.board.Message = "Reloaded " + s.tasksPath
+	}
+	return false, nil
+}
+
+// implement leaves the board to run an implement session for one task and
+// returns to it once the user has read the session's output.
+func (s *boardSession) implement(taskID string) error {
+	s.leave()
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("locating autospec: %w", err)
+	}
+	args := append([]string{"implement", s.specName, "--task", taskID}, s.implementArgs...)
+	fmt.Fprintf(s.out, "$ autospec %s\n\n", strings.Join(args, " "))
+
+	// Ctrl+C stops the session, not the board.
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt)
+	c := exec.Command(exe, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	runErr := c.Run()
+	signal.Stop(sig)
+
+	if runErr != nil {
+		s.board.Message = fmt.Sprintf("implement %s failed: %v", taskID, runErr)
+	} else {
+		s.board.Message = fmt.Sprintf("implement %s finished", taskID)
+	}
+	fmt.Fprintf(s.out, "\n%s. Press Enter to return to the board.", s.board.Message)
+	_, _ = bufio.NewReader(os.Stdin).ReadString('\n')
+	return s.enter()
+}
+
+// enter switches the terminal to raw mode and the alternate screen.
+func (s *boardSession) enter() error {
+	saved, err := term.MakeRaw(s.inFd)
+	if err != nil {
+		return fmt.Errorf("setting up terminal: %w", err)
+	}
+	s.saved = saved
+	fmt.Fprint(s.out, "\x1b[?1049h\x1b[?25l")
+	return nil
+}
+
+// leave restores the terminal. It is safe to call when already left.
+func (s *boardSession) leave() {
+	if s.saved == nil {
+		return
+	}
+	fmt.Fprint(s.out, "\x1b[?25h\x1b[?1049l")
+	_ = term.Restore(s.inFd, s.saved)
+	s.saved = nil
+}
+
+// draw redraws the whole board.
+func (s *boardSession) draw() {
+	s.width, s.height = s.size()
+	lines := s.board.Render(s.width, s.height, s.color)
+	fmt.Fprint(s.out, "\x1b[H"+strings.Join(lines, "\x1b[K\r\n")+"\x1b[K\x1b[J")
+}
+
+// refresh reloads tasks.yaml when it changed on disk and reports whether
+// the board needs redrawing.
+func (s *boardSession) refresh() bool {
+	if mod := s.tasksModTime(); !mod.Equal(s.modTime) {
+		s.reload()
+		return true
+	}
+	w, h := s.size()
+	return w != s.width || h != s.height
+}
+
+// reload rereads tasks.yaml into the board.
+func (s *boardSession) reload() {
+	s.modTime = s.tasksModTime()
+	tasks, err := validation.GetAllTasks(s.tasksPath)
+	if err != nil {
+		s.board.Message = "Error: " + err.Error()
+		return
+	}
+	s.board.SetTasks(tasks)
+}
+
+func (s *boardSession) tasksModTime() time.Time {
+	info, err := os.Stat(s.tasksPath)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// size returns the terminal size, falling back to 80x24.
+func (s *boardSession) size() (int, int) {
+	w, h, err := term.GetSize(s.outFd)
+	if err != nil || w <= 0 || h <= 0 {
+		return 80, 24
+	}
+	return w, h
+}
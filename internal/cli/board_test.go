@@ -0,0 +1,121 @@
+// Package cli_test tests the board command's handling of board actions.
+// Related: internal/cli/board.go
+// Tags: cli, board, tasks, status
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/board"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const boardTasksYAML = `_meta:
+  version: "1.0"
+phases:
+  - number: 1
+    title: Setup
+    tasks:
+      - id: T001
+        title: Create models
+        status: Pending
+        type: implementation
+      - id: T002
+        title: Add service
+        status: InProgress
+        type: implementation
+`
+
+// newTestBoardSession returns a session over a temporary tasks.yaml.
+func newTestBoardSession(t *testing.T) *boardSession {
+	t.Helper()
+	tasksPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	require.NoError(t, os.WriteFile(tasksPath, []byte(boardTasksYAML), 0o644))
+	tasks, err := validation.GetAllTasks(tasksPath)
+	require.NoError(t, err)
+	return &boardSession{
+		board:     board.New("001-test", tasks),
+		specName:  "001-test",
+		tasksPath: tasksPath,
+		out:       &bytes.Buffer{},
+	}
+}
+
+func TestBoardSessionApply(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		action      board.Action
+		wantQuit    bool
+		wantStatus  string
+		wantReason  string
+		wantMessage string
+	}{
+		"quit": {
+			action:     board.Action{Kind: board.ActionQuit},
+			wantQuit:   true,
+			wantStatus: "Pending",
+		},
+		"set status": {
+			action:      board.Action{Kind: board.ActionSetStatus, TaskID: "T001", Status: "Completed", Reason: "moved on the board"},
+			wantStatus:  "Completed",
+			wantMessage: "T001: Pending -> Completed",
+		},
+		"block": {
+			action:      board.Action{Kind: board.ActionSetStatus, TaskID: "T001", Status: "Blocked", Reason: "waiting on API"},
+			wantStatus:  "Blocked",
+			wantReason:  "waiting on API",
+			wantMessage: "T001: Pending -> Blocked",
+		},
+		"unknown task": {
+			action:      board.Action{Kind: board.ActionSetStatus, TaskID: "T009", Status: "Completed", Reason: "x"},
+			wantStatus:  "Pending",
+			wantMessage: "Error: ",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			s := newTestBoardSession(t)
+
+			quit, err := s.apply(tt.action)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantQuit, quit)
+			assert.Contains(t, s.board.Message, tt.wantMessage)
+
+			tasks, err := validation.GetAllTasks(s.tasksPath)
+			require.NoError(t, err)
+			task, err := validation.GetTaskByID(tasks, "T001")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, task.Status)
+			assert.Equal(t, tt.wantReason, task.BlockedReason)
+			if tt.wantMessage != "" && tt.wantMessage != "Error: " {
+				require.Len(t, task.StatusHistory, 1)
+				assert.True(t, task.StatusHistory[0].Manual)
+			}
+		})
+	}
+}
+
+func TestBoardSessionRefresh(t *testing.T) {
+	t.Parallel()
+
+	s := newTestBoardSession(t)
+	s.width, s.height = s.size()
+	s.modTime = s.tasksModTime()
+	assert.False(t, s.refresh())
+
+	_, err := validation.UpdateTaskStatus(s.tasksPath, "T002", "Completed")
+	require.NoError(t, err)
+	require.NoError(t, os.Chtimes(s.tasksPath, s.modTime, s.modTime.Add(1e9)))
+	assert.True(t, s.refresh())
+
+	s.board.HandleKey(board.Key{Code: board.KeyRight})
+	assert.Nil(t, s.board.Selected(), "T002 moved out of InProgress")
+}
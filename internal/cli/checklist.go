@@ -57,6 +57,9 @@ Prerequisites:
 			clierrors.PrintError(cliErr)
 			return cliErr
 		}
+		if err := shared.ApplySpecConfig(cmd, cfg, ""); err != nil {
+			return err
+		}
 
 		// Override skip-preflight from flag if set
 		if cmd.Flags().Changed("skip-preflight") {
@@ -95,13 +98,13 @@ Prerequisites:
 		// Create notification handler and history logger
 		notifHandler := notify.NewHandler(cfg.Notifications)
 		historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
-		specName := fmt.Sprintf("%s-%s", metadata.Number, metadata.Name)
+		specName := metadata.DirName()
 
 		// Wrap command execution with lifecycle for timing, notification, and history
 		return lifecycle.RunWithHistory(notifHandler, historyLogger, "checklist", specName, func() error {
 			// Create workflow orchestrator
 			orch := workflow.NewWorkflowOrchestrator(cfg)
-			orch.Executor.NotificationHandler = notifHandler
+			orch.SetNotificationHandler(notifHandler)
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orch)
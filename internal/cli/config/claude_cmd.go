@@ -0,0 +1,144 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/claude"
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var claudeCmd = &cobra.Command{
+	Use:   "claude",
+	Short: "Claude Code integration utilities",
+	Long:  `Utilities for the Claude Code settings autospec relies on.`,
+}
+
+var claudePermissionsCmd = &cobra.Command{
+	Use:   "permissions",
+	Short: "Preview and add the Claude permissions autospec needs",
+	Long: `Compare the Claude settings file with the permissions autospec needs
+(Bash(autospec:*) and Write/Edit for .autospec/** and the specs directory),
+show a diff of the entries that would be added, and add them after
+confirmation.
+
+By default .claude/settings.local.json is used; --global uses
+~/.claude/settings.json instead.
+
+With --check nothing is written: the command exits with status 1 if any
+required permission is missing or blocked by a deny rule, for use in CI.`,
+	Example: `  # Preview and add missing permissions
+  autospec claude permissions
+
+  # Fail a CI job if permissions are missing
+  autospec claude permissions --check`,
+	Args: cobra.NoArgs,
+	RunE: runClaudePermissions,
+}
+
+func init() {
+	claudeCmd.GroupID = shared.GroupConfiguration
+	claudePermissionsCmd.Flags().Bool("check", false, "Report missing permissions and exit non-zero without writing anything")
+	claudePermissionsCmd.Flags().Bool("global", false, "Use ~/.claude/settings.json instead of .claude/settings.local.json")
+	claudePermissionsCmd.Flags().BoolP("yes", "y", false, "Add missing permissions without prompting")
+	claudeCmd.AddCommand(claudePermissionsCmd)
+}
+
+func runClaudePermissions(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	check, _ := cmd.Flags().GetBool("check")
+	global, _ := cmd.Flags().GetBool("global")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	specsDir := cfg.SpecsDir
+	if specsDir == "" {
+		specsDir = "specs"
+	}
+
+	var settings *claude.Settings
+	if global {
+		settings, err = claude.LoadGlobal()
+	} else {
+		settings, err = claude.Load(".")
+	}
+	if err != nil {
+		return fmt.Errorf("loading claude settings: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	required := cliagent.NewClaude().RequiredPermissions(specsDir)
+	changes := settings.PlanPermissions(required)
+	conflicts := settings.FindDenyConflicts(required)
+
+	if changes.Empty() {
+		fmt.Fprintf(out, "%s All required permissions are in %s\n", color.GreenString("✓"), settings.FilePath())
+	} else {
+		printPermissionPreview(out, changes.Preview(settings.FilePath()))
+	}
+	for _, r := range conflicts {
+		fmt.Fprintf(out, "%s %s is blocked by deny rule %s\n", color.RedString("✗"), strings.Join(r.Blocks, ", "), r.DenyRule)
+	}
+	var conflictErr error
+	if len(conflicts) > 0 {
+		conflictErr = fmt.Errorf("%d deny rule(s) block autospec permissions (run 'autospec config fix-permissions' to resolve)", len(conflicts))
+	}
+
+	if check {
+		if !changes.Empty() {
+			return fmt.Errorf("%d required permission(s) missing from %s", len(changes.AllowAdded), settings.FilePath())
+		}
+		return conflictErr
+	}
+
+	if !changes.Empty() {
+		reader := bufio.NewReader(cmd.InOrStdin())
+		if !yes && !confirm(out, reader, "Add these permissions?") {
+			fmt.Fprintln(out, "No changes made")
+			return nil
+		}
+		settings.AddPermissions(changes.AllowAdded)
+		if err := settings.Save(); err != nil {
+			return fmt.Errorf("saving claude settings: %w", err)
+		}
+		fmt.Fprintf(out, "%s Added %d permission(s) to %s\n", color.GreenString("✓"), len(changes.AllowAdded), settings.FilePath())
+	}
+	return conflictErr
+}
+
+// printPermissionPreview prints a diff from PermissionChanges.Preview with
+// added entries in green and removed entries in red.
+func printPermissionPreview(out io.Writer, preview string) {
+	if preview == "" {
+		return
+	}
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	for _, line := range strings.Split(strings.TrimSuffix(preview, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			fmt.Fprintln(out, line)
+		case strings.HasPrefix(line, "+"):
+			fmt.Fprintln(out, green(line))
+		case strings.HasPrefix(line, "-"):
+			fmt.Fprintln(out, red(line))
+		default:
+			fmt.Fprintln(out, line)
+		}
+	}
+}
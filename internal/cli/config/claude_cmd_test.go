@@ -0,0 +1,117 @@
+// Package config tests the claude permissions command.
+// Related: internal/cli/config/claude_cmd.go
+// Tags: config, cli, claude, permissions
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClaudePermissionsCmd(out io.Writer, stdin string, args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "permissions", RunE: runClaudePermissions}
+	cmd.Flags().String("config", ".autospec/config.yml", "")
+	cmd.Flags().Bool("check", false, "")
+	cmd.Flags().Bool("global", false, "")
+	cmd.Flags().BoolP("yes", "y", false, "")
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	return cmd
+}
+
+func readAllowList(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var settings struct {
+		Permissions struct {
+			Allow []string `json:"allow"`
+		} `json:"permissions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &settings))
+	return settings.Permissions.Allow
+}
+
+func TestRunClaudePermissions_Check(t *testing.T) {
+	path := setupClaudeProject(t)
+	before, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = newClaudePermissionsCmd(&out, "", "--check").Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "4 required permission(s) missing")
+	assert.Contains(t, out.String(), "+++ .claude/settings.local.json (proposed)")
+	assert.Contains(t, out.String(), `"Write(./specs/**)"`)
+	assert.NotContains(t, out.String(), `"Bash(autospec:*)"`, "existing entries are not part of the diff")
+
+	after, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(before), string(after), "--check must not write")
+}
+
+func TestRunClaudePermissions_CheckDenied(t *testing.T) {
+	setupClaudeProject(t, "Write(./specs/**)")
+
+	// Adding the allow entries still reports the deny conflict
+	var out bytes.Buffer
+	require.Error(t, newClaudePermissionsCmd(&out, "", "--yes").Execute())
+	assert.Contains(t, out.String(), "Added 4 permission(s)")
+
+	out.Reset()
+	err := newClaudePermissionsCmd(&out, "", "--check").Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fix-permissions")
+	assert.Contains(t, out.String(), "All required permissions are in")
+	assert.Contains(t, out.String(), "blocked by deny rule Write(./specs/**)")
+}
+
+func TestRunClaudePermissions_Add(t *testing.T) {
+	path := setupClaudeProject(t)
+
+	var out bytes.Buffer
+	require.NoError(t, newClaudePermissionsCmd(&out, "y\n").Execute())
+
+	assert.Contains(t, out.String(), "Add these permissions? [y/N]")
+	assert.Contains(t, out.String(), "Added 4 permission(s)")
+	assert.Len(t, readAllowList(t, path), 5)
+
+	out.Reset()
+	require.NoError(t, newClaudePermissionsCmd(&out, "", "--check").Execute())
+	assert.Contains(t, out.String(), "All required permissions are in")
+}
+
+func TestRunClaudePermissions_Declined(t *testing.T) {
+	path := setupClaudeProject(t)
+
+	var out bytes.Buffer
+	require.NoError(t, newClaudePermissionsCmd(&out, "n\n").Execute())
+
+	assert.Contains(t, out.String(), "No changes made")
+	assert.Equal(t, []string{"Bash(autospec:*)"}, readAllowList(t, path))
+}
+
+func TestRunClaudePermissions_Global(t *testing.T) {
+	setupClaudeProject(t)
+
+	var out bytes.Buffer
+	require.NoError(t, newClaudePermissionsCmd(&out, "", "--global", "--yes").Execute())
+
+	home, err := os.UserHomeDir()
+	require.NoError(t, err)
+	assert.Len(t, readAllowList(t, filepath.Join(home, ".claude", "settings.json")), 5)
+}
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 
@@ -52,11 +51,17 @@ func init() {
 	// Add subcommands
 	configCmd.AddCommand(configShowCmd)
 
-	// Show command flags
-	configShowCmd.Flags().Bool("json", false, "Output in JSON format")
+	// Show command flags (--json is global)
 	configShowCmd.Flags().Bool("yaml", true, "Output in YAML format (default)")
 }
 
+// configShowResult is the --json result of config show.
+type configShowResult struct {
+	UserConfig    string         `json:"user_config"`
+	ProjectConfig string         `json:"project_config"`
+	Config        map[string]any `json:"config"`
+}
+
 func runConfigShow(cmd *cobra.Command, args []string) error {
 	out := cmd.OutOrStdout()
 	useJSON, _ := cmd.Flags().GetBool("json")
@@ -69,6 +74,18 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	// Never echo secrets
+	for _, secret := range []*string{
+		&cfg.Notifications.Email.Password,
+		&cfg.Notifications.Ntfy.Token,
+		&cfg.Notifications.Pushover.Token,
+		&cfg.Notifications.Pushover.User,
+	} {
+		if *secret != "" {
+			*secret = "********"
+		}
+	}
+
 	// Convert to map using reflection - automatically includes all koanf-tagged fields
 	configMap := cfg.ToMap()
 
@@ -76,24 +93,24 @@ func runConfigShow(cmd *cobra.Command, args []string) error {
 	userPath, _ := config.UserConfigPath()
 	projectPath := config.ProjectConfigPath()
 
+	if useJSON {
+		return shared.WriteJSON(cmd, configShowResult{
+			UserConfig:    userPath,
+			ProjectConfig: projectPath,
+			Config:        configMap,
+		})
+	}
+
 	fmt.Fprintf(out, "# Configuration Sources\n")
 	fmt.Fprintf(out, "# User config:    %s\n", userPath)
 	fmt.Fprintf(out, "# Project config: %s\n", projectPath)
 	fmt.Fprintf(out, "\n")
 
-	if useJSON {
-		data, err := json.MarshalIndent(configMap, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to serialize config: %w", err)
-		}
-		fmt.Fprintln(out, string(data))
-	} else {
-		data, err := yaml.Marshal(configMap)
-		if err != nil {
-			return fmt.Errorf("failed to serialize config: %w", err)
-		}
-		fmt.Fprint(out, string(data))
+	data, err := yaml.Marshal(configMap)
+	if err != nil {
+		return fmt.Errorf("failed to serialize config: %w", err)
 	}
+	fmt.Fprint(out, string(data))
 
 	return nil
 }
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"io"
+
+	cfgpkg "github.com/ariel-frischer/autospec/internal/config"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var configMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade configuration to the current config_version",
+	Long: `Upgrade a configuration file to the current format (config_version).
+
+Rewrites settings from older formats (for example output_style and
+custom_claude_cmd), stamps config_version, and validates the result.
+The original file is kept as <config>.v<N>.bak.
+
+A legacy JSON config (~/.autospec/config.json or .autospec/config.json) is
+first converted to YAML and renamed to config.json.bak.
+
+Older config files are also migrated automatically when they are loaded.`,
+	Example: `  # Preview changes without applying (dry-run)
+  autospec config migrate --dry-run
+
+  # Migrate user config
+  autospec config migrate
+
+  # Migrate project config
+  autospec config migrate --project`,
+	RunE: runConfigMigrate,
+}
+
+func init() {
+	configMigrateCmd.Flags().Bool("dry-run", false, "Preview changes without applying")
+	configMigrateCmd.Flags().Bool("user", false, "Migrate user config (default)")
+	configMigrateCmd.Flags().Bool("project", false, "Migrate project config instead of user config")
+	configMigrateCmd.MarkFlagsMutuallyExclusive("user", "project")
+	configCmd.AddCommand(configMigrateCmd)
+}
+
+func runConfigMigrate(cmd *cobra.Command, _ []string) error {
+	out := cmd.OutOrStdout()
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	useProject, _ := cmd.Flags().GetBool("project")
+
+	// Resolve config paths
+	var configPath, legacyPath, scope string
+	if useProject {
+		configPath = cfgpkg.ProjectConfigPath()
+		legacyPath = cfgpkg.LegacyProjectConfigPath()
+		scope = "project"
+	} else {
+		var err error
+		if configPath, err = cfgpkg.UserConfigPath(); err != nil {
+			return fmt.Errorf("getting user config path: %w", err)
+		}
+		if legacyPath, err = cfgpkg.LegacyUserConfigPath(); err != nil {
+			return fmt.Errorf("getting legacy user config path: %w", err)
+		}
+		scope = "user"
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "%s Dry run - no changes made\n\n", color.New(color.Faint).Sprint("→"))
+	}
+
+	converted, err := migrateLegacyJSON(out, legacyPath, configPath, dryRun)
+	if err != nil {
+		return err
+	}
+	if converted && dryRun {
+		// The YAML file does not exist yet, so there is nothing more to preview
+		return nil
+	}
+
+	result, err := cfgpkg.MigrateConfigVersion(configPath, dryRun)
+	if err != nil {
+		return fmt.Errorf("migrating config: %w", err)
+	}
+	printVersionMigration(out, scope, result)
+	return nil
+}
+
+// migrateLegacyJSON converts a legacy JSON config to YAML and renames the JSON file.
+// Returns true if a conversion was made (or would be made, for dry runs).
+func migrateLegacyJSON(out io.Writer, legacyPath, configPath string, dryRun bool) (bool, error) {
+	result, err := cfgpkg.MigrateJSONToYAML(legacyPath, configPath, dryRun)
+	if err != nil {
+		return false, fmt.Errorf("migrating legacy JSON config: %w", err)
+	}
+	if !result.Success {
+		return false, nil
+	}
+	fmt.Fprintf(out, "%s %s\n", color.New(color.FgGreen).Sprint("✓"), result.Message)
+	if err := cfgpkg.RemoveLegacyConfig(legacyPath, dryRun); err != nil {
+		return true, fmt.Errorf("renaming legacy JSON config: %w", err)
+	}
+	return true, nil
+}
+
+// printVersionMigration reports the changes made (or planned) by a version migration.
+func printVersionMigration(out io.Writer, scope string, result *cfgpkg.VersionMigrationResult) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	if !result.NeedsMigration() {
+		fmt.Fprintf(out, "%s %s config is at config_version %d\n", green("✓"), scope, result.ToVersion)
+		return
+	}
+
+	fmt.Fprintf(out, "%s %s config: config_version %d -> %d\n", yellow("→"), scope, result.FromVersion, result.ToVersion)
+	for _, change := range result.Changes {
+		fmt.Fprintf(out, "  ~ %s\n", change)
+	}
+
+	if result.DryRun {
+		fmt.Fprintf(out, "\n%s Would migrate %s\n", dim("→"), result.ConfigPath)
+		return
+	}
+	fmt.Fprintf(out, "\n%s Config migrated: %s (backup: %s)\n", green("✓"), result.ConfigPath, result.BackupPath)
+}
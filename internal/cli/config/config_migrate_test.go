@@ -0,0 +1,86 @@
+// Package config tests the config migrate command.
+// Related: internal/cli/config/config_migrate.go
+// Tags: config, cli, migrate, config_version
+
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newConfigMigrateCmd returns an isolated config migrate command writing to out.
+func newConfigMigrateCmd(out *bytes.Buffer, args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "migrate", RunE: runConfigMigrate}
+	cmd.Flags().Bool("dry-run", false, "")
+	cmd.Flags().Bool("user", false, "")
+	cmd.Flags().Bool("project", false, "")
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetArgs(args)
+	return cmd
+}
+
+// setupUserConfig points the user config at a temp dir and writes content to it.
+func setupUserConfig(t *testing.T, content string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	path := filepath.Join(home, ".config", "autospec", "config.yml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestRunConfigMigrate(t *testing.T) {
+	path := setupUserConfig(t, "output_style: minimal\nmax_retries: 1\n")
+
+	var out bytes.Buffer
+	require.NoError(t, newConfigMigrateCmd(&out).Execute())
+
+	assert.Contains(t, out.String(), "user config: config_version 1 -> 2")
+	assert.Contains(t, out.String(), "output_style: minimal -> cclean.style")
+	assert.Contains(t, out.String(), "Config migrated")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "config_version: 2")
+	assert.FileExists(t, path+".v1.bak")
+
+	// Second run is a no-op
+	out.Reset()
+	require.NoError(t, newConfigMigrateCmd(&out).Execute())
+	assert.Contains(t, out.String(), "user config is at config_version 2")
+}
+
+func TestRunConfigMigrate_DryRun(t *testing.T) {
+	content := "output_style: minimal\n"
+	path := setupUserConfig(t, content)
+
+	var out bytes.Buffer
+	require.NoError(t, newConfigMigrateCmd(&out, "--dry-run").Execute())
+
+	assert.Contains(t, out.String(), "Dry run - no changes made")
+	assert.Contains(t, out.String(), "Would migrate")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.NoFileExists(t, path+".v1.bak")
+}
+
+func TestRunConfigMigrate_InvalidConfig(t *testing.T) {
+	setupUserConfig(t, "config_version: 2\ntimeout: later\n")
+
+	var out bytes.Buffer
+	err := newConfigMigrateCmd(&out).Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `config.yml:2:10: timeout: invalid integer: "later"`)
+}
@@ -6,10 +6,13 @@ package config
 
 import (
 	"bytes"
+	"encoding/json"
 	"testing"
 
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRunConfigShow_YAMLOutput(t *testing.T) {
@@ -34,6 +37,30 @@ func TestRunConfigShow_YAMLOutput(t *testing.T) {
 	assert.Contains(t, output, "agent_preset:")
 }
 
+func TestRunConfigShow_RedactsSecrets(t *testing.T) {
+	t.Setenv("AUTOSPEC_NOTIFICATIONS_EMAIL_PASSWORD", "s3cret-smtp")
+	t.Setenv("AUTOSPEC_NOTIFICATIONS_NTFY_TOKEN", "tk_s3cret")
+
+	cmd := &cobra.Command{
+		Use:  "show",
+		RunE: runConfigShow,
+	}
+	cmd.Flags().Bool("json", false, "Output in JSON format")
+	cmd.Flags().Bool("yaml", true, "Output in YAML format")
+
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	output := buf.String()
+	assert.NotContains(t, output, "s3cret-smtp")
+	assert.NotContains(t, output, "tk_s3cret")
+	assert.Contains(t, output, "password: '********'")
+}
+
 func TestRunConfigShow_JSONOutput(t *testing.T) {
 	// Create isolated command
 	cmd := &cobra.Command{
@@ -51,11 +78,15 @@ func TestRunConfigShow_JSONOutput(t *testing.T) {
 	err := cmd.Execute()
 	assert.NoError(t, err)
 
-	output := buf.String()
-	assert.Contains(t, output, "Configuration Sources")
-	// JSON output should have braces
-	assert.Contains(t, output, "{")
-	assert.Contains(t, output, "}")
+	var env shared.JSONEnvelope
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &env), "output: %s", buf.String())
+	assert.Equal(t, shared.JSONVersion, env.Version)
+	assert.Equal(t, "show", env.Command)
+	assert.Empty(t, env.Errors)
+	result, ok := env.Result.(map[string]any)
+	require.True(t, ok)
+	assert.Contains(t, result, "project_config")
+	assert.Contains(t, result["config"], "agent_preset")
 }
 
 func TestConfigShowCmd_OutputFormats(t *testing.T) {
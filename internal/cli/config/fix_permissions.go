@@ -0,0 +1,171 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/claude"
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var configFixPermissionsCmd = &cobra.Command{
+	Use:   "fix-permissions",
+	Short: "Resolve Claude deny rules that block autospec",
+	Long: `Find permissions.deny rules in the Claude settings files that block the
+permissions autospec needs, and resolve them with the narrowest change:
+
+  - Bash(autospec:*) denied: narrowed to deny only the destructive
+    commands (autospec uninstall, autospec clean)
+  - Write/Edit of .autospec/** or the specs directory denied: the rule is removed
+
+Broader rules (e.g. "Bash", "Write(**)" or a parent directory) also protect
+files and commands outside autospec and are reported for manual editing.
+
+Both ~/.claude/settings.json and .claude/settings.local.json are checked.
+Each change is confirmed interactively unless --yes is given. Exits with
+status 1 if any conflict remains unresolved.`,
+	Example: `  # Review and confirm each proposed change
+  autospec config fix-permissions
+
+  # Apply all automatic resolutions (e.g. in scripts)
+  autospec config fix-permissions --yes`,
+	Args: cobra.NoArgs,
+	RunE: runConfigFixPermissions,
+}
+
+func init() {
+	configFixPermissionsCmd.Flags().BoolP("yes", "y", false, "Apply all automatic resolutions without prompting")
+	configCmd.AddCommand(configFixPermissionsCmd)
+}
+
+// permissionChange records the outcome of one proposed resolution for the report.
+type permissionChange struct {
+	file       string
+	resolution claude.Resolution
+}
+
+func runConfigFixPermissions(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	yes, _ := cmd.Flags().GetBool("yes")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	specsDir := cfg.SpecsDir
+	if specsDir == "" {
+		specsDir = "specs"
+	}
+	required := cliagent.NewClaude().RequiredPermissions(specsDir)
+
+	settingsFiles, err := loadClaudeSettingsFiles()
+	if err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	reader := bufio.NewReader(cmd.InOrStdin())
+	var applied, skipped, manual []permissionChange
+
+	for _, settings := range settingsFiles {
+		conflicts := settings.FindDenyConflicts(required)
+		if len(conflicts) == 0 {
+			continue
+		}
+
+		printPermissionPreview(out, settings.PlanResolutions(conflicts).Preview(settings.FilePath()))
+		changed := false
+		for _, r := range conflicts {
+			change := permissionChange{file: settings.FilePath(), resolution: r}
+			if !r.Automatic() {
+				manual = append(manual, change)
+				continue
+			}
+			fmt.Fprintf(out, "  %s blocks %s\n", r.DenyRule, strings.Join(r.Blocks, ", "))
+			fmt.Fprintf(out, "  Proposed: %s (%s)\n", r.Describe(), r.Reason)
+			if !yes && !confirm(out, reader, "  Apply?") {
+				skipped = append(skipped, change)
+				continue
+			}
+			if err := settings.ApplyResolution(r); err != nil {
+				return err
+			}
+			applied = append(applied, change)
+			changed = true
+		}
+		if changed {
+			if err := settings.Save(); err != nil {
+				return fmt.Errorf("saving claude settings: %w", err)
+			}
+		}
+	}
+
+	printPermissionReport(out, applied, skipped, manual)
+	if unresolved := len(skipped) + len(manual); unresolved > 0 {
+		return fmt.Errorf("%d deny rule(s) still block autospec permissions", unresolved)
+	}
+	return nil
+}
+
+// loadClaudeSettingsFiles returns the global and project Claude settings files
+// that exist. Claude merges deny rules from both, so either can block autospec.
+func loadClaudeSettingsFiles() ([]*claude.Settings, error) {
+	global, err := claude.LoadGlobal()
+	if err != nil {
+		return nil, fmt.Errorf("loading claude global settings: %w", err)
+	}
+	project, err := claude.Load(".")
+	if err != nil {
+		return nil, fmt.Errorf("loading claude project settings: %w", err)
+	}
+
+	var files []*claude.Settings
+	for _, s := range []*claude.Settings{global, project} {
+		if s.Exists() {
+			files = append(files, s)
+		}
+	}
+	return files, nil
+}
+
+// confirm asks a yes/no question (default no) using a shared reader, so
+// answers piped in for several prompts are not lost to read-ahead.
+func confirm(out io.Writer, reader *bufio.Reader, question string) bool {
+	fmt.Fprintf(out, "%s [y/N]: ", question)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// printPermissionReport summarizes what was changed and what still needs attention.
+func printPermissionReport(out io.Writer, applied, skipped, manual []permissionChange) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	if len(applied)+len(skipped)+len(manual) == 0 {
+		fmt.Fprintf(out, "%s No deny rules block autospec permissions\n", green("✓"))
+		return
+	}
+
+	fmt.Fprintln(out, "\nReport:")
+	for _, c := range applied {
+		fmt.Fprintf(out, "  %s %s: %s\n", green("✓"), c.file, c.resolution.Describe())
+	}
+	for _, c := range skipped {
+		fmt.Fprintf(out, "  %s %s: skipped: %s\n", yellow("-"), c.file, c.resolution.Describe())
+	}
+	for _, c := range manual {
+		fmt.Fprintf(out, "  %s %s: %s (%s)\n", red("✗"), c.file, c.resolution.Describe(), c.resolution.Reason)
+	}
+}
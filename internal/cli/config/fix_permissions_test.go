@@ -0,0 +1,104 @@
+// Package config tests the config fix-permissions command.
+// Related: internal/cli/config/fix_permissions.go
+// Tags: config, cli, claude, permissions
+
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFixPermissionsCmd(out io.Writer, stdin string, args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "fix-permissions", RunE: runConfigFixPermissions}
+	cmd.Flags().String("config", ".autospec/config.yml", "")
+	cmd.Flags().BoolP("yes", "y", false, "")
+	cmd.SetOut(out)
+	cmd.SetErr(out)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	return cmd
+}
+
+// setupClaudeProject isolates HOME and the working directory and writes
+// .claude/settings.local.json with the given deny rules.
+func setupClaudeProject(t *testing.T, deny ...string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	settings := map[string]interface{}{
+		"permissions": map[string]interface{}{"allow": []string{"Bash(autospec:*)"}, "deny": deny},
+	}
+	data, err := json.Marshal(settings)
+	require.NoError(t, err)
+	path := filepath.Join(dir, ".claude", "settings.local.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	return path
+}
+
+func readDenyList(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var settings struct {
+		Permissions struct {
+			Deny []string `json:"deny"`
+		} `json:"permissions"`
+	}
+	require.NoError(t, json.Unmarshal(data, &settings))
+	return settings.Permissions.Deny
+}
+
+func TestRunConfigFixPermissions_Yes(t *testing.T) {
+	path := setupClaudeProject(t, "Bash(rm:*)", "Bash(autospec:*)", "Write(specs/**)")
+
+	var out bytes.Buffer
+	require.NoError(t, newFixPermissionsCmd(&out, "", "--yes").Execute())
+
+	assert.Contains(t, out.String(), "narrow deny rule Bash(autospec:*) to Bash(autospec uninstall:*), Bash(autospec clean:*)")
+	assert.Contains(t, out.String(), "remove deny rule Write(specs/**)")
+	assert.NotContains(t, out.String(), "[y/N]")
+	assert.Equal(t, []string{"Bash(rm:*)", "Bash(autospec uninstall:*)", "Bash(autospec clean:*)"}, readDenyList(t, path))
+
+	// Second run finds nothing to change
+	out.Reset()
+	require.NoError(t, newFixPermissionsCmd(&out, "", "--yes").Execute())
+	assert.Contains(t, out.String(), "No deny rules block autospec permissions")
+}
+
+func TestRunConfigFixPermissions_Interactive(t *testing.T) {
+	path := setupClaudeProject(t, "Write(.autospec/**)", "Edit(.autospec/**)")
+
+	var out bytes.Buffer
+	err := newFixPermissionsCmd(&out, "y\nn\n").Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 deny rule(s)")
+	assert.Contains(t, out.String(), "skipped: remove deny rule Edit(.autospec/**)")
+	assert.Equal(t, []string{"Edit(.autospec/**)"}, readDenyList(t, path))
+}
+
+func TestRunConfigFixPermissions_Manual(t *testing.T) {
+	path := setupClaudeProject(t, "Bash")
+
+	var out bytes.Buffer
+	err := newFixPermissionsCmd(&out, "", "--yes").Execute()
+
+	require.Error(t, err)
+	assert.Contains(t, out.String(), "deny rule Bash blocks Bash(autospec:*); edit permissions.deny manually")
+	assert.Equal(t, []string{"Bash"}, readDenyList(t, path))
+}
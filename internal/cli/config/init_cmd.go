@@ -84,7 +84,10 @@ Path argument:
   autospec init --project
 
   # Overwrite existing config with defaults
-  autospec init --force`,
+  autospec init --force
+
+  # Also install the commands as Cursor rules and aider prompts
+  autospec init --commands cursor,aider`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: runInit,
 }
@@ -96,6 +99,7 @@ func init() {
 	initCmd.Flags().StringSlice("ai", nil, "Configure specific agents (comma-separated: claude,opencode)")
 	initCmd.Flags().Bool("no-agents", false, "Skip agent configuration prompt")
 	initCmd.Flags().Bool("here", false, "Initialize in current directory (same as 'init .')")
+	initCmd.Flags().StringSlice("commands", nil, "Also install commands in these formats (comma-separated: "+strings.Join(commands.FormatNames(), ",")+")")
 	// Keep --global as hidden alias for backward compatibility
 	initCmd.Flags().BoolP("global", "g", false, "Deprecated: use default behavior instead (creates user-level config)")
 	initCmd.Flags().MarkHidden("global")
@@ -107,8 +111,16 @@ func runInit(cmd *cobra.Command, args []string) error {
 	aiAgents, _ := cmd.Flags().GetStringSlice("ai")
 	noAgents, _ := cmd.Flags().GetBool("no-agents")
 	here, _ := cmd.Flags().GetBool("here")
+	commandFormats, _ := cmd.Flags().GetStringSlice("commands")
 	out := cmd.OutOrStdout()
 
+	// Validate formats before changing anything
+	for _, name := range commandFormats {
+		if _, err := commands.LookupFormat(name); err != nil {
+			return fmt.Errorf("invalid --commands value: %w", err)
+		}
+	}
+
 	// Resolve target directory from path argument or --here flag
 	targetDir, err := resolveTargetDirectory(args, here)
 	if err != nil {
@@ -161,6 +173,9 @@ func runInit(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("configuring agents: %w", err)
 	}
+	if err := installCommandFormats(out, commandFormats, "."); err != nil {
+		return fmt.Errorf("installing commands: %w", err)
+	}
 
 	// Detect Claude auth and configure use_subscription (only if Claude was selected)
 	configPath, _ := getConfigPath(project)
@@ -321,6 +336,7 @@ func configureSpecificAgents(cmd *cobra.Command, out io.Writer, project bool, ai
 			continue
 		}
 
+		previewAgentPermissions(out, agent, ".", specsDir, project)
 		result, err := cliagent.Configure(agent, ".", specsDir, project)
 		if err != nil {
 			fmt.Fprintf(out, "%s %s: configuration failed: %v\n", cYellow("⚠"), agentDisplayNames[agentName], err)
@@ -415,6 +431,7 @@ func configureSelectedAgents(out io.Writer, selected []string, cfg *config.Confi
 			continue
 		}
 
+		previewAgentPermissions(out, agent, projectDir, specsDir, projectLevel)
 		result, err := cliagent.Configure(agent, projectDir, specsDir, projectLevel)
 		if err != nil {
 			fmt.Fprintf(out, "⚠ %s: configuration failed: %v\n", agentDisplayNames[agentName], err)
@@ -437,6 +454,20 @@ func configureSelectedAgents(out io.Writer, selected []string, cfg *config.Confi
 	return sandboxPrompts, nil
 }
 
+// previewAgentPermissions prints the permission entries configuring agent will
+// write, before its settings file is modified. Only Claude is previewed.
+func previewAgentPermissions(out io.Writer, agent cliagent.Agent, projectDir, specsDir string, projectLevel bool) {
+	claudeAgent, ok := agent.(*cliagent.Claude)
+	if !ok {
+		return
+	}
+	changes, path, err := claudeAgent.PreviewPermissions(projectDir, specsDir, projectLevel)
+	if err != nil {
+		return // Configure reports the load error
+	}
+	printPermissionPreview(out, changes.Preview(path))
+}
+
 // checkSandboxConfiguration checks if an agent needs sandbox configuration.
 // Returns nil only if sandbox is fully configured (enabled with all required paths).
 func checkSandboxConfiguration(agentName string, agent cliagent.Agent, projectDir, specsDir string) *sandboxPromptInfo {
@@ -696,6 +727,11 @@ func displayAgentConfigResult(out io.Writer, agentName string, result *cliagent.
 		for _, perm := range result.PermissionsAdded {
 			fmt.Fprintf(out, "    %s %s\n", cDim("-"), perm)
 		}
+		return
+	}
+
+	if result.CommandsDir != "" {
+		fmt.Fprintf(out, "%s %s: commands installed to %s\n", cGreen("✓"), cBold(displayName), result.CommandsDir)
 	}
 }
 
@@ -888,6 +924,25 @@ func writeDefaultConfig(configPath string) error {
 	return nil
 }
 
+// installCommandFormats installs the command templates in extra formats, for
+// editors and tools that autospec does not run as agents (e.g. Cursor rules).
+func installCommandFormats(out io.Writer, formats []string, projectDir string) error {
+	for _, name := range formats {
+		format, err := commands.LookupFormat(name)
+		if err != nil {
+			return err
+		}
+		results, err := commands.InstallTemplatesInFormat(filepath.Join(projectDir, format.Dir), format)
+		if err != nil {
+			return fmt.Errorf("installing %s commands: %w", name, err)
+		}
+		installed, updated := countResults(results)
+		fmt.Fprintf(out, "%s %s: %d commands installed, %d updated in %s (run as %s)\n",
+			cGreen("✓"), cBold(name), installed, updated, format.Dir, fmt.Sprintf(format.Invocation, "autospec.plan"))
+	}
+	return nil
+}
+
 func countResults(results []commands.InstallResult) (installed, updated int) {
 	for _, r := range results {
 		switch r.Action {
@@ -954,7 +1009,7 @@ func runConstitutionFromInitImpl(cmd *cobra.Command, configPath string) bool {
 	// Run constitution with lifecycle wrapper
 	err = lifecycle.RunWithHistory(notifHandler, historyLogger, "constitution", "", func() error {
 		orch := workflow.NewWorkflowOrchestrator(cfg)
-		orch.Executor.NotificationHandler = notifHandler
+		orch.SetNotificationHandler(notifHandler)
 		shared.ApplyOutputStyle(cmd, orch)
 		return orch.ExecuteConstitution("")
 	})
@@ -996,7 +1051,7 @@ func runWorktreeGenScriptFromInitImpl(cmd *cobra.Command, configPath string) boo
 
 	err = lifecycle.RunWithHistory(notifHandler, historyLogger, "worktree-gen-script", "", func() error {
 		orch := workflow.NewWorkflowOrchestrator(cfg)
-		orch.Executor.NotificationHandler = notifHandler
+		orch.SetNotificationHandler(notifHandler)
 		shared.ApplyOutputStyle(cmd, orch)
 
 		fmt.Fprintf(out, "Generating worktree setup script...\n\n")
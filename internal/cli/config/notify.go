@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var notifyCmd = &cobra.Command{
+	Use:   "notify",
+	Short: "Notification utilities",
+	Long:  `Utilities for checking the notification channels configured under 'notifications'.`,
+}
+
+var notifyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a sample notification to every configured channel",
+	Long: `Send a sample notification to each configured channel and report whether
+each one delivered, without running a workflow.
+
+Desktop channels follow notifications.type (visual, sound or both); email,
+ntfy and Pushover are tested when enabled. The test ignores
+notifications.enabled, the on_* hook toggles, quiet hours and CI/TTY
+detection, and waits for every channel, so missing tools (notify-send,
+paplay, osascript, PowerShell) and bad credentials are reported.
+
+Exits with status 1 if any channel failed.`,
+	Example: `  # Test all channels with a command-complete notification
+  autospec notify test
+
+  # Test the notification sent when a command fails
+  autospec notify test --event on_error`,
+	Args: cobra.NoArgs,
+	RunE: runNotifyTest,
+}
+
+func init() {
+	notifyCmd.GroupID = shared.GroupConfiguration
+	notifyTestCmd.Flags().String("event", "on_command_complete", "Hook whose sample notification to send (e.g. on_error, on_stage_complete)")
+	notifyCmd.AddCommand(notifyTestCmd)
+}
+
+func runNotifyTest(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	eventName, _ := cmd.Flags().GetString("event")
+
+	event, err := notify.ParseEvent(eventName)
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	handler := notify.NewHandler(cfg.Notifications)
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Sending a sample %s notification (platform: %s)\n\n", event, notify.Platform())
+
+	failed := printChannelResults(out, handler.TestChannels(event))
+	if reason := handler.DesktopSuppressedReason(); reason != "" {
+		fmt.Fprintf(out, "\nNote: workflow runs skip desktop notifications because %s.\n", reason)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d notification channel(s) failed", failed)
+	}
+	return nil
+}
+
+// printChannelResults prints one line per channel (plus a fix hint for
+// failures) and returns the number of failed channels.
+func printChannelResults(out io.Writer, results []notify.ChannelResult) int {
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	if len(results) == 0 {
+		fmt.Fprintln(out, "No notification channels configured.")
+		fmt.Fprintln(out, dim("Set notifications.type, or enable notifications.email, .ntfy or .pushover"))
+		return 0
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Err == nil {
+			fmt.Fprintf(out, "  %s %-17s sent\n", green("✓"), r.Channel)
+			continue
+		}
+		failed++
+		fmt.Fprintf(out, "  %s %-17s %v\n", red("✗"), r.Channel, r.Err)
+		if r.Hint != "" {
+			fmt.Fprintf(out, "    %s\n", dim(r.Hint))
+		}
+	}
+	return failed
+}
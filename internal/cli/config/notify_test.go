@@ -0,0 +1,57 @@
+// Package config tests the notify test command.
+// Related: internal/cli/config/notify.go
+// Tags: config, cli, notify, notifications
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifyTestCmd(t *testing.T) {
+	assert.Equal(t, "test", notifyTestCmd.Use)
+	assert.Contains(t, notifyCmd.Commands(), notifyTestCmd)
+
+	flag := notifyTestCmd.Flags().Lookup("event")
+	if assert.NotNil(t, flag) {
+		assert.Equal(t, "on_command_complete", flag.DefValue)
+	}
+}
+
+func TestPrintChannelResults(t *testing.T) {
+	tests := map[string]struct {
+		results    []notify.ChannelResult
+		wantFailed int
+		wantOutput []string
+	}{
+		"no channels": {
+			wantOutput: []string{"No notification channels configured."},
+		},
+		"mixed results": {
+			results: []notify.ChannelResult{
+				{Channel: notify.ChannelVisual},
+				{Channel: notify.ChannelSound, Err: errors.New("not available on this system"), Hint: "Install paplay"},
+				{Channel: "ntfy"},
+			},
+			wantFailed: 1,
+			wantOutput: []string{"desktop (visual)  sent", "desktop (sound)   not available on this system", "Install paplay", "ntfy              sent"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			failed := printChannelResults(&buf, tt.results)
+
+			assert.Equal(t, tt.wantFailed, failed)
+			for _, want := range tt.wantOutput {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
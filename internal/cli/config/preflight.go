@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/preflight"
+	"github.com/ariel-frischer/autospec/internal/update"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var preflightCmd = &cobra.Command{
+	Use:   "preflight",
+	Short: "Run the preflight checks without starting a workflow",
+	Long: `Run the checks every workflow runs before its first stage, and print one
+line per check: ✓ passed, ⚠ warning, ✗ failed, - disabled.
+
+Built-in checks:
+  agent        Agent CLI installed (error)
+  project      .claude/commands/ and .autospec/ exist (asks to continue)
+  permissions  State and specs directories writable (error)
+  git-clean    No uncommitted changes (warning)
+  disk-space   At least 1 GiB free (warning)
+  state-size   State directory under the storage limits (error, or warning
+               past a warn limit)
+  network      Notification servers and the update server reachable (warning)
+
+Disable checks with preflight.disable and add shell command checks with
+preflight.commands in the config. Unlike a workflow, this command also
+checks the update server, and it runs in CI too.
+
+Exits with 4 when an error check fails or the project is not initialized.`,
+	Example: `  # Run all checks
+  autospec preflight
+
+  # Machine-readable results
+  autospec preflight --json`,
+	Args: cobra.NoArgs,
+	RunE: runPreflight,
+}
+
+func init() {
+	preflightCmd.GroupID = shared.GroupConfiguration
+}
+
+func runPreflight(cmd *cobra.Command, _ []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	env, err := workflow.PreflightEnv(cfg)
+	if err != nil {
+		return err
+	}
+	env.Endpoints = append(env.Endpoints, preflight.Endpoint(update.GitHubAPIURL))
+	return writePreflightReport(cmd, cmd.OutOrStdout(), preflight.Run(env, cfg.Preflight))
+}
+
+// writePreflightReport prints report, or writes it as the --json result,
+// and returns an ErrPreflightFailed error when it did not pass.
+func writePreflightReport(cmd *cobra.Command, w io.Writer, report *preflight.Report) error {
+	var runErr error
+	if !report.Passed() {
+		failed := len(report.Failed(preflight.LevelError)) + len(report.Failed(preflight.LevelConfirm))
+		runErr = clierrors.ErrPreflightFailed.Errorf("%d preflight check(s) failed", failed)
+	}
+
+	if shared.WantsJSON(cmd) {
+		var errs []*clierrors.CLIError
+		if runErr != nil {
+			errs = append(errs, clierrors.Classify(runErr))
+		}
+		if err := shared.WriteJSON(cmd, report, errs...); err != nil {
+			return err
+		}
+		return runErr
+	}
+
+	report.Print(w)
+	if runErr == nil {
+		fmt.Fprintln(w, "\nAll preflight checks passed")
+	}
+	return runErr
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/preflight"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreflightCmd_Structure(t *testing.T) {
+	assert.Equal(t, "preflight", preflightCmd.Use)
+	assert.NotEmpty(t, preflightCmd.Short)
+	assert.NotEmpty(t, preflightCmd.Example)
+	assert.Equal(t, shared.GroupConfiguration, preflightCmd.GroupID)
+	assert.NotNil(t, preflightCmd.RunE)
+}
+
+func TestWritePreflightReport(t *testing.T) {
+	passed := &preflight.Report{Results: []preflight.Result{
+		{Name: "agent", Description: "Agent CLI installed", Level: preflight.LevelError, Passed: true},
+		{Name: "git-clean", Description: "No uncommitted changes", Level: preflight.LevelWarn, Message: "1 uncommitted change(s)"},
+	}}
+	failed := &preflight.Report{Results: []preflight.Result{
+		{Name: "agent", Description: "Agent CLI installed", Level: preflight.LevelError, Message: "claude not found"},
+	}}
+
+	tests := map[string]struct {
+		report       *preflight.Report
+		json         bool
+		wantErr      bool
+		wantContains []string
+	}{
+		"passed": {
+			report:       passed,
+			wantContains: []string{"✓ Agent CLI installed", "⚠ git-clean: 1 uncommitted change(s)", "All preflight checks passed"},
+		},
+		"failed": {
+			report:       failed,
+			wantErr:      true,
+			wantContains: []string{"✗ agent: claude not found"},
+		},
+		"failed as json": {
+			report:       failed,
+			json:         true,
+			wantErr:      true,
+			wantContains: []string{`"command": "preflight"`, `"passed": false`, `"code": "PREFLIGHT_FAILED"`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			root := &cobra.Command{Use: "autospec"}
+			root.PersistentFlags().Bool("json", tt.json, "")
+			cmd := &cobra.Command{Use: "preflight"}
+			root.AddCommand(cmd)
+			cmd.SetOut(&out)
+
+			err := writePreflightReport(cmd, &out, tt.report)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.True(t, errors.Is(err, clierrors.ErrPreflightFailed))
+			} else {
+				require.NoError(t, err)
+			}
+			for _, want := range tt.wantContains {
+				assert.Contains(t, out.String(), want)
+			}
+			if tt.json {
+				var env shared.JSONEnvelope
+				require.NoError(t, json.Unmarshal(out.Bytes(), &env))
+			}
+		})
+	}
+}
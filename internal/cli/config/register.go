@@ -1,5 +1,5 @@
 // Package config provides CLI commands for autospec configuration management.
-// Includes: init, config, migrate, doctor
+// Includes: init, config, migrate, doctor, preflight, notify, secrets, claude
 package config
 
 import (
@@ -13,4 +13,8 @@ func Register(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(migrateCmd)
 	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(preflightCmd)
+	rootCmd.AddCommand(notifyCmd)
+	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(claudeCmd)
 }
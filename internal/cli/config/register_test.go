@@ -37,6 +37,10 @@ func TestRegister(t *testing.T) {
 	assert.True(t, commandNames["config"], "Should have 'config' command")
 	assert.True(t, commandNames["migrate"], "Should have 'migrate' command")
 	assert.True(t, commandNames["doctor"], "Should have 'doctor' command")
+	assert.True(t, commandNames["preflight"], "Should have 'preflight' command")
+	assert.True(t, commandNames["notify"], "Should have 'notify' command")
+	assert.True(t, commandNames["secrets"], "Should have 'secrets' command")
+	assert.True(t, commandNames["claude"], "Should have 'claude' command")
 }
 
 func TestRegister_CommandAnnotations(t *testing.T) {
@@ -60,6 +64,14 @@ func TestRegister_CommandAnnotations(t *testing.T) {
 			cmdUse:  "doctor",
 			wantCmd: true,
 		},
+		"notify command exists": {
+			cmdUse:  "notify",
+			wantCmd: true,
+		},
+		"claude command exists": {
+			cmdUse:  "claude",
+			wantCmd: true,
+		},
 	}
 
 	for name, tt := range tests {
@@ -146,9 +158,9 @@ func TestConfigShowCmd_Flags(t *testing.T) {
 		flagName string
 		wantFlag bool
 	}{
-		"json flag exists": {
+		"json flag is the global one": {
 			flagName: "json",
-			wantFlag: true,
+			wantFlag: false,
 		},
 		"yaml flag exists": {
 			flagName: "yaml",
@@ -175,8 +187,8 @@ func TestRegister_CommandCount(t *testing.T) {
 
 	Register(rootCmd)
 
-	// Should register exactly 4 commands: init, config, migrate, doctor
-	assert.Equal(t, 4, len(rootCmd.Commands()))
+	// Should register exactly 8 commands: init, config, migrate, doctor, preflight, notify, secrets, claude
+	assert.Equal(t, 8, len(rootCmd.Commands()))
 }
 
 func TestConfigCmd_RunsWithoutArgs(t *testing.T) {
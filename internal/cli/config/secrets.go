@@ -0,0 +1,211 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/secrets"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Store tokens and passwords in the OS keychain",
+	Long: `Store the tokens and passwords autospec uses in the OS keychain instead of
+plain-text config files: macOS Keychain (security), the Secret Service on
+Linux (secret-tool, e.g. GNOME Keyring or KWallet), or the Windows
+Credential Manager.
+
+A value in the config file or its environment variable takes precedence
+over the keychain. Turn the keychain off with secrets.keychain: false.`,
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the secrets autospec reads and where each one is set",
+	Long: `List the secrets autospec reads, with where each one is currently found:
+an environment variable, the keychain, or nowhere. Values are never printed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if err := loadSecretsConfig(cmd); err != nil {
+			return err
+		}
+		return listSecrets(cmd.OutOrStdout(), secrets.Default())
+	},
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a secret in the keychain",
+	Long: `Store a secret in the OS keychain. The value is read without echo from the
+terminal, or from stdin when it is piped. Run 'autospec secrets list' for
+the names.`,
+	Example: `  # Prompt for the Jira API token
+  autospec secrets set jira-token
+
+  # Pipe a value
+  gh auth token | autospec secrets set github-token
+
+  # Generate and store an encryption key
+  openssl rand -base64 32 | autospec secrets set encryption-key`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSecretNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := loadSecretsConfig(cmd); err != nil {
+			return err
+		}
+		value, err := readSecretValue(cmd.InOrStdin(), cmd.ErrOrStderr(), args[0])
+		if err != nil {
+			return err
+		}
+		return setSecret(cmd.OutOrStdout(), secrets.Default(), args[0], value)
+	},
+}
+
+var secretsDeleteCmd = &cobra.Command{
+	Use:               "delete <name>",
+	Short:             "Remove a secret from the keychain",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeSecretNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := loadSecretsConfig(cmd); err != nil {
+			return err
+		}
+		return deleteSecret(cmd.OutOrStdout(), secrets.Default(), args[0])
+	},
+}
+
+func init() {
+	secretsCmd.GroupID = shared.GroupConfiguration
+	secretsCmd.AddCommand(secretsListCmd)
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsDeleteCmd)
+}
+
+// loadSecretsConfig loads the config so secrets.keychain and secrets.service
+// select the keychain.
+func loadSecretsConfig(cmd *cobra.Command) error {
+	cmd.SilenceUsage = true
+	configPath, _ := cmd.Flags().GetString("config")
+	if _, err := config.Load(configPath); err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	return nil
+}
+
+func completeSecretNames(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return secrets.Names(), cobra.ShellCompDirectiveNoFileComp
+}
+
+// errNoKeychain is returned when a command needs the keychain and none is usable.
+var errNoKeychain = errors.New("no OS keychain available: install secret-tool (Linux) and check that secrets.keychain is true")
+
+// knownSecret returns the known secret called name, or an error listing the names.
+func knownSecret(name string) (secrets.Secret, error) {
+	s, ok := secrets.LookupKnown(name)
+	if !ok {
+		return s, fmt.Errorf("unknown secret %q: must be one of %s", name, strings.Join(secrets.Names(), ", "))
+	}
+	return s, nil
+}
+
+// listSecrets prints each known secret with where it is set.
+func listSecrets(w io.Writer, store secrets.Store) error {
+	if store == nil {
+		fmt.Fprintln(w, "Keychain: not available (environment variables only)")
+	} else {
+		fmt.Fprintf(w, "Keychain: %s\n", store.Name())
+	}
+	fmt.Fprintln(w)
+	for _, s := range secrets.Known {
+		fmt.Fprintf(w, "  %-16s %-40s %s\n", s.Name, secretSource(s, store), s.Description)
+	}
+	return nil
+}
+
+// secretSource describes where s is set: its environment variable, the
+// keychain, or nowhere.
+func secretSource(s secrets.Secret, store secrets.Store) string {
+	for _, env := range s.Env {
+		if os.Getenv(env) != "" {
+			return "env " + env
+		}
+	}
+	if store == nil {
+		return "not set"
+	}
+	_, err := store.Get(s.Name)
+	switch {
+	case err == nil:
+		return "keychain"
+	case errors.Is(err, secrets.ErrNotFound):
+		return "not set"
+	default:
+		return "keychain error: " + err.Error()
+	}
+}
+
+// readSecretValue reads a secret value without echo from a terminal, or the
+// whole of a piped stdin.
+func readSecretValue(r io.Reader, prompt io.Writer, name string) (string, error) {
+	if f, ok := r.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprintf(prompt, "Value for %s: ", name)
+		value, err := term.ReadPassword(int(f.Fd()))
+		fmt.Fprintln(prompt)
+		if err != nil {
+			return "", fmt.Errorf("reading value: %w", err)
+		}
+		return strings.TrimSpace(string(value)), nil
+	}
+	data, err := io.ReadAll(bufio.NewReader(r))
+	if err != nil {
+		return "", fmt.Errorf("reading value: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// setSecret stores value as the secret called name.
+func setSecret(w io.Writer, store secrets.Store, name, value string) error {
+	if _, err := knownSecret(name); err != nil {
+		return err
+	}
+	if store == nil {
+		return errNoKeychain
+	}
+	if value == "" {
+		return errors.New("empty value: nothing stored")
+	}
+	if err := store.Set(name, value); err != nil {
+		return fmt.Errorf("storing %s: %w", name, err)
+	}
+	fmt.Fprintf(w, "Stored %s in %s\n", name, store.Name())
+	return nil
+}
+
+// deleteSecret removes the secret called name.
+func deleteSecret(w io.Writer, store secrets.Store, name string) error {
+	if _, err := knownSecret(name); err != nil {
+		return err
+	}
+	if store == nil {
+		return errNoKeychain
+	}
+	if err := store.Delete(name); err != nil {
+		return fmt.Errorf("deleting %s: %w", name, err)
+	}
+	fmt.Fprintf(w, "Deleted %s from %s\n", name, store.Name())
+	return nil
+}
@@ -0,0 +1,139 @@
+// Package config tests the secrets command.
+// Related: internal/cli/config/secrets.go
+// Tags: config, cli, secrets, keychain
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore is a map-backed secrets.Store.
+type fakeStore struct {
+	values map[string]string
+	getErr error
+}
+
+func (f *fakeStore) Name() string { return "test keychain" }
+
+func (f *fakeStore) Get(name string) (string, error) {
+	if f.getErr != nil {
+		return "", f.getErr
+	}
+	v, ok := f.values[name]
+	if !ok {
+		return "", secrets.ErrNotFound
+	}
+	return v, nil
+}
+
+func (f *fakeStore) Set(name, value string) error {
+	f.values[name] = value
+	return nil
+}
+
+func (f *fakeStore) Delete(name string) error {
+	delete(f.values, name)
+	return nil
+}
+
+func TestListSecrets(t *testing.T) {
+	// Not parallel: sets environment variables
+	t.Setenv("JIRA_API_TOKEN", "")
+	t.Setenv("LINEAR_API_KEY", "lin_api")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+
+	store := &fakeStore{values: map[string]string{secrets.JiraToken: "t0ken"}}
+	var out bytes.Buffer
+	require.NoError(t, listSecrets(&out, store))
+	assert.NotContains(t, out.String(), "t0ken", "values are never printed")
+
+	lines := map[string]string{}
+	for _, line := range strings.Split(out.String(), "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 {
+			lines[fields[0]] = line
+		}
+	}
+	assert.Contains(t, out.String(), "Keychain: test keychain")
+	assert.Contains(t, lines[secrets.JiraToken], "keychain")
+	assert.Contains(t, lines[secrets.LinearToken], "env LINEAR_API_KEY")
+	assert.Contains(t, lines[secrets.GitHubToken], "not set")
+
+	store.getErr = errors.New("locked")
+	out.Reset()
+	require.NoError(t, listSecrets(&out, store))
+	assert.Contains(t, out.String(), "keychain error: locked")
+
+	out.Reset()
+	require.NoError(t, listSecrets(&out, nil))
+	assert.Contains(t, out.String(), "Keychain: not available")
+}
+
+func TestSetSecret(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name    string
+		value   string
+		noStore bool
+		wantErr string
+	}{
+		"stores":         {name: secrets.JiraToken, value: "t0ken"},
+		"unknown name":   {name: "jira", value: "t0ken", wantErr: `unknown secret "jira"`},
+		"empty value":    {name: secrets.JiraToken, wantErr: "empty value"},
+		"no keychain":    {name: secrets.JiraToken, value: "t0ken", noStore: true, wantErr: "no OS keychain available"},
+		"encryption key": {name: secrets.EncryptionKey, value: "abc"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			store := &fakeStore{values: map[string]string{}}
+			var s secrets.Store = store
+			if tt.noStore {
+				s = nil
+			}
+			var out bytes.Buffer
+			err := setSecret(&out, s, tt.name, tt.value)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				assert.Empty(t, store.values)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.value, store.values[tt.name])
+			assert.Equal(t, "Stored "+tt.name+" in test keychain\n", out.String())
+		})
+	}
+}
+
+func TestDeleteSecret(t *testing.T) {
+	t.Parallel()
+
+	store := &fakeStore{values: map[string]string{secrets.GitLabToken: "glpat"}}
+	var out bytes.Buffer
+	require.NoError(t, deleteSecret(&out, store, secrets.GitLabToken))
+	assert.Empty(t, store.values)
+	assert.Equal(t, "Deleted gitlab-token from test keychain\n", out.String())
+
+	assert.ErrorContains(t, deleteSecret(&out, store, "gitlab"), "unknown secret")
+	assert.ErrorIs(t, deleteSecret(&out, nil, secrets.GitLabToken), errNoKeychain)
+}
+
+func TestReadSecretValue(t *testing.T) {
+	t.Parallel()
+
+	var prompt bytes.Buffer
+	value, err := readSecretValue(strings.NewReader("  s3cret\n"), &prompt, secrets.JiraToken)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+	assert.Empty(t, prompt.String(), "no prompt when piped")
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -76,68 +77,32 @@ func TestConfigShowCmd_JSONOutput(t *testing.T) {
 	// Capture output
 	var buf bytes.Buffer
 	cmd.SetOut(&buf)
+	t.Cleanup(func() { cmd.SetOut(nil) })
 
-	// Set flag directly
-	cmd.Flags().Set("json", "true")
+	// --json is the global flag; reset it for the other tests
+	require.NoError(t, cmd.Flags().Set("json", "true"))
+	t.Cleanup(func() { _ = cmd.Flags().Set("json", "false") })
 
 	err := cmd.RunE(cmd, []string{})
 	require.NoError(t, err)
 
-	output := buf.String()
-
-	// Should contain config sources header
-	assert.Contains(t, output, "Configuration Sources")
-
-	// Extract JSON portion (after the header comments)
-	lines := bytes.Split(buf.Bytes(), []byte("\n"))
-	var jsonLines []byte
-	for _, line := range lines {
-		if len(line) > 0 && line[0] != '#' {
-			jsonLines = append(jsonLines, line...)
-			jsonLines = append(jsonLines, '\n')
-		}
-	}
+	// The output is only the JSON envelope
+	var env shared.JSONEnvelope
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &env), "Output should be valid JSON")
+	assert.Equal(t, shared.JSONVersion, env.Version)
+	assert.Equal(t, "config show", env.Command)
+	assert.Empty(t, env.Errors)
+	assert.NotContains(t, buf.String(), "Configuration Sources")
 
-	// Should be valid JSON
-	var config map[string]interface{}
-	err = json.Unmarshal(jsonLines, &config)
-	require.NoError(t, err, "Output should contain valid JSON")
+	result, ok := env.Result.(map[string]interface{})
+	require.True(t, ok)
+	assert.Contains(t, result, "user_config")
+	assert.Contains(t, result, "project_config")
 
 	// Verify expected fields
-	assert.Contains(t, config, "agent_preset")
-	assert.Contains(t, config, "max_retries")
-	assert.Contains(t, config, "specs_dir")
-	assert.Contains(t, config, "state_dir")
-}
-
-func TestConfigShowCmd_AllFields(t *testing.T) {
-	cmd := getConfigShowCmd()
-	require.NotNil(t, cmd, "config show command must exist")
-
-	var buf bytes.Buffer
-	cmd.SetOut(&buf)
-
-	// Set flag directly
-	cmd.Flags().Set("json", "true")
-
-	err := cmd.RunE(cmd, []string{})
-	require.NoError(t, err)
-
-	// Extract and parse JSON
-	lines := bytes.Split(buf.Bytes(), []byte("\n"))
-	var jsonLines []byte
-	for _, line := range lines {
-		if len(line) > 0 && line[0] != '#' {
-			jsonLines = append(jsonLines, line...)
-			jsonLines = append(jsonLines, '\n')
-		}
-	}
-
-	var config map[string]interface{}
-	require.NoError(t, json.Unmarshal(jsonLines, &config))
-
-	// All expected fields should be present
-	expectedFields := []string{
+	config, ok := result["config"].(map[string]interface{})
+	require.True(t, ok)
+	for _, field := range []string{
 		"agent_preset",
 		"custom_agent",
 		"max_retries",
@@ -146,9 +111,7 @@ func TestConfigShowCmd_AllFields(t *testing.T) {
 		"skip_preflight",
 		"timeout",
 		"skip_confirmations",
-	}
-
-	for _, field := range expectedFields {
+	} {
 		assert.Contains(t, config, field, "Config should contain field: %s", field)
 	}
 }
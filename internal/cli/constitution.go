@@ -74,7 +74,7 @@ This command has no prerequisites - it can be run at any time.`,
 
 			// Create workflow orchestrator
 			orch := workflow.NewWorkflowOrchestrator(cfg)
-			orch.Executor.NotificationHandler = notifHandler
+			orch.SetNotificationHandler(notifHandler)
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orch)
@@ -0,0 +1,326 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/mockagent"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+)
+
+// demoDescription is the feature the demo specifies when none is given.
+const demoDescription = "Add a hello command that greets the user by name"
+
+// demoSpecName is the spec directory the mock agent writes to.
+const demoSpecName = "001-demo-feature"
+
+// demoProjectFiles is the sample project the demo starts from.
+var demoProjectFiles = map[string]string{
+	"README.md": "# hello\n\nSample project created by `autospec demo`.\n",
+	"go.mod":    "module example.com/hello\n\ngo 1.22\n",
+	"main.go":   "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}\n",
+}
+
+// demoStop is one artifact shown by the tour after the demo workflow.
+type demoStop struct {
+	artifactType validation.ArtifactType
+	path         string // slash-separated, relative to the project
+	about        string
+}
+
+// demoTour lists the artifacts in the order the workflow writes them.
+var demoTour = []demoStop{
+	{
+		artifactType: validation.ArtifactTypeConstitution,
+		path:         ".autospec/memory/constitution.yaml",
+		about: "The project's principles. Every later stage reads them, and 'autospec analyze'\n" +
+			"checks the spec, plan and tasks against them. Written by 'autospec constitution'.",
+	},
+	{
+		artifactType: validation.ArtifactTypeSpec,
+		path:         "specs/" + demoSpecName + "/spec.yaml",
+		about: "What to build: user stories with acceptance scenarios, requirements and\n" +
+			"success criteria, from the feature description. Written by 'autospec specify'.",
+	},
+	{
+		artifactType: validation.ArtifactTypePlan,
+		path:         "specs/" + demoSpecName + "/plan.yaml",
+		about: "How to build it: technical context, data model, contracts and implementation\n" +
+			"phases. Written by 'autospec plan'.",
+	},
+	{
+		artifactType: validation.ArtifactTypeTasks,
+		path:         "specs/" + demoSpecName + "/tasks.yaml",
+		about: "Ordered tasks with dependencies and acceptance criteria, grouped in phases.\n" +
+			"Written by 'autospec tasks'; 'autospec implement' works through them and\n" +
+			"marks each one Completed.",
+	},
+}
+
+var demoCmd = &cobra.Command{
+	Use:   "demo [feature-description]",
+	Short: "Run the whole workflow on a sample project with the built-in mock agent",
+	Long: `Create a small sample project, run constitution, specify, plan, tasks and
+implement on it with the built-in mock agent in place of an AI agent, then walk
+through the artifacts each stage produced. The mock writes fixed, schema-valid
+artifacts, so the demo needs no agent installed, no network access and costs
+nothing.
+
+The project is created in a new temporary directory, or in --dir, which must
+be empty or not exist yet. Its .autospec/config.yml uses the mock agent, so
+'autospec status', 'autospec board' or any stage command can be tried inside
+it afterwards; --clean removes it instead. Your own config is not used.
+
+The walkthrough waits for Enter between artifacts when run in a terminal.`,
+	Example: `  # Run the demo in a temporary directory
+  autospec demo
+
+  # With your own feature description, in ./demo, one second per agent call
+  autospec demo "Add dark mode" --dir ./demo --delay 1
+
+  # Without pauses, removing the project at the end
+  autospec demo --no-pause --clean`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runDemo,
+}
+
+func init() {
+	demoCmd.GroupID = GroupGettingStarted
+	demoCmd.Flags().String("dir", "", "Directory for the demo project (default: a new temporary directory)")
+	demoCmd.Flags().Int("delay", 0, "Seconds each mock agent call takes")
+	demoCmd.Flags().Bool("no-pause", false, "Show the walkthrough without waiting for Enter")
+	demoCmd.Flags().Bool("clean", false, "Remove the demo project when done")
+	rootCmd.AddCommand(demoCmd)
+}
+
+func runDemo(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	description := demoDescription
+	if len(args) == 1 {
+		description = args[0]
+	}
+	dir, _ := cmd.Flags().GetString("dir")
+	delay, _ := cmd.Flags().GetInt("delay")
+	noPause, _ := cmd.Flags().GetBool("no-pause")
+	clean, _ := cmd.Flags().GetBool("clean")
+	if delay < 0 {
+		return fmt.Errorf("--delay must not be negative")
+	}
+
+	dir, err := prepareDemoDir(dir)
+	if err != nil {
+		return err
+	}
+	cfg, err := demoConfig(dir, delay)
+	if err != nil {
+		return err
+	}
+	if err := scaffoldDemoProject(dir, cfg.CustomAgent); err != nil {
+		return err
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Demo project: %s\nAgent: built-in mock (no model is called)\n\n", dir)
+	if err := runDemoWorkflow(cfg, dir, description); err != nil {
+		return err
+	}
+
+	pause := !noPause && isTerminalReader(cmd.InOrStdin())
+	if err := showDemoTour(out, cmd.InOrStdin(), dir, pause); err != nil {
+		return err
+	}
+
+	if clean {
+		if err := os.RemoveAll(dir); err != nil {
+			return fmt.Errorf("removing demo project: %w", err)
+		}
+		fmt.Fprintf(out, "\nDemo complete. Removed %s.\n", dir)
+		return nil
+	}
+	fmt.Fprintf(out, "\nDemo complete. Explore the result:\n  cd %s\n  autospec status\n  autospec board\n", dir)
+	fmt.Fprintf(out, "Stage commands run there use the mock agent too, e.g. 'autospec implement'.\n")
+	return nil
+}
+
+// runDemoWorkflow runs the constitution and the full workflow from inside
+// the demo project, restoring the working directory afterwards.
+func runDemoWorkflow(cfg *config.Configuration, dir, description string) error {
+	// Stage checks such as the constitution lookup are relative to the
+	// project root.
+	previous, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := os.Chdir(dir); err != nil {
+		return fmt.Errorf("entering demo project: %w", err)
+	}
+	defer os.Chdir(previous)
+	// The demo's specs are numbered whatever specs_layout the user has.
+	if err := spec.SetLayout(spec.LayoutNumbered); err != nil {
+		return err
+	}
+
+	orch := workflow.NewWorkflowOrchestrator(cfg)
+	if err := orch.ExecuteConstitution(""); err != nil {
+		return fmt.Errorf("demo constitution: %w", err)
+	}
+	if err := orch.RunFullWorkflow(description, false); err != nil {
+		return fmt.Errorf("demo workflow: %w", err)
+	}
+	return nil
+}
+
+// prepareDemoDir returns the absolute demo project directory, creating it.
+// An existing dir must be empty.
+func prepareDemoDir(dir string) (string, error) {
+	if dir == "" {
+		return os.MkdirTemp("", "autospec-demo-")
+	}
+	entries, err := os.ReadDir(dir)
+	if err == nil && len(entries) > 0 {
+		return "", fmt.Errorf("demo directory %s is not empty", dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("creating demo directory: %w", err)
+	}
+	return filepath.Abs(dir)
+}
+
+// demoConfig returns the configuration of a demo project in dir: defaults,
+// with the mock agent writing to dir/specs and state kept inside dir.
+func demoConfig(dir string, delay int) (*config.Configuration, error) {
+	agent, err := mockagent.Agent()
+	if err != nil {
+		return nil, err
+	}
+	specsDir := filepath.Join(dir, "specs")
+	agent.Env["MOCK_ARTIFACT_DIR"] = specsDir
+	agent.Env["MOCK_SPEC_NAME"] = demoSpecName
+	agent.Env["MOCK_DELAY"] = strconv.Itoa(delay)
+
+	return &config.Configuration{
+		CustomAgent:     agent,
+		SpecsDir:        specsDir,
+		StateDir:        filepath.Join(dir, ".autospec", "state"),
+		SkipPreflight:   true,
+		Timeout:         300,
+		ImplementMethod: "phases",
+	}, nil
+}
+
+// scaffoldDemoProject writes the sample project into dir, with a project
+// config that runs agent, so autospec commands work inside it afterwards.
+func scaffoldDemoProject(dir string, agent *cliagent.CustomAgentConfig) error {
+	for name, content := range demoProjectFiles {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			return fmt.Errorf("writing demo project: %w", err)
+		}
+	}
+
+	env := map[string]string{
+		"MOCK_ARTIFACT_DIR": "./specs",
+		"MOCK_SPEC_NAME":    demoSpecName,
+	}
+	for key, value := range agent.Env {
+		if _, ok := env[key]; !ok && key != "MOCK_DELAY" {
+			env[key] = value
+		}
+	}
+	data, err := yaml.Marshal(map[string]any{
+		"specs_dir":        "./specs",
+		"state_dir":        ".autospec/state",
+		"skip_preflight":   true,
+		"implement_method": "phases",
+		"custom_agent": map[string]any{
+			"command": agent.Command,
+			"args":    agent.Args,
+			"env":     env,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("encoding demo config: %w", err)
+	}
+	header := "# Created by 'autospec demo'. The agent is autospec's built-in mock,\n" +
+		"# which writes fixed artifacts instead of calling a model.\n"
+
+	configDir := filepath.Join(dir, ".autospec")
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		return fmt.Errorf("creating demo config directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, "config.yml"), append([]byte(header), data...), 0o644); err != nil {
+		return fmt.Errorf("writing demo config: %w", err)
+	}
+	return nil
+}
+
+// showDemoTour describes each artifact of the demo project with the counts
+// its validator reports. With pause, it waits for Enter between artifacts.
+func showDemoTour(w io.Writer, r io.Reader, dir string, pause bool) error {
+	in := bufio.NewReader(r)
+	fmt.Fprintf(w, "\nWhat the workflow produced\n")
+	for i, stop := range demoTour {
+		if pause && i > 0 {
+			fmt.Fprint(w, "\nPress Enter for the next artifact...")
+			if _, err := in.ReadString('\n'); err != nil {
+				break
+			}
+		}
+		fmt.Fprintf(w, "\n%d/%d %s\n", i+1, len(demoTour), stop.path)
+		for _, line := range strings.Split(stop.about, "\n") {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+
+		validator, err := validation.NewArtifactValidator(stop.artifactType)
+		if err != nil {
+			return err
+		}
+		result := validator.Validate(filepath.Join(dir, filepath.FromSlash(stop.path)))
+		switch {
+		case !result.Valid:
+			fmt.Fprintf(w, "    Not valid: %d error(s); see 'autospec artifact %s'\n", len(result.Errors), stop.path)
+		case result.Summary != nil:
+			if counts := formatDemoCounts(result.Summary.Counts); counts != "" {
+				fmt.Fprintf(w, "    Contains: %s\n", counts)
+			}
+		}
+	}
+	return nil
+}
+
+// formatDemoCounts renders the non-zero counts of an artifact summary as
+// "2 phases, 5 total tasks", sorted by name.
+func formatDemoCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key, n := range counts {
+		if n > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%d %s", counts[key], strings.ReplaceAll(key, "_", " "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// isTerminalReader reports whether r is a terminal.
+func isTerminalReader(r io.Reader) bool {
+	f, ok := r.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,175 @@
+// Package cli_test tests the demo command and the built-in mock agent.
+// Related: internal/cli/demo.go, internal/cli/mockagent.go
+// Tags: cli, demo, mockagent
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/mockagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMain lets the test binary act as the mock agent of the demo.
+func TestMain(m *testing.M) {
+	mockagent.Main()
+	os.Exit(m.Run())
+}
+
+func TestPrepareDemoDir(t *testing.T) {
+	t.Parallel()
+
+	nonEmpty := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(nonEmpty, "go.mod"), nil, 0o644))
+
+	tests := map[string]struct {
+		dir     string
+		wantErr string
+	}{
+		"temporary": {dir: ""},
+		"new":       {dir: filepath.Join(t.TempDir(), "demo")},
+		"empty":     {dir: t.TempDir()},
+		"not empty": {dir: nonEmpty, wantErr: "is not empty"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := prepareDemoDir(tt.dir)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			if tt.dir == "" {
+				t.Cleanup(func() { os.RemoveAll(got) })
+			}
+			assert.True(t, filepath.IsAbs(got))
+			assert.DirExists(t, got)
+		})
+	}
+}
+
+// Not parallel: the demo changes the working directory.
+func TestRunDemo(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "demo")
+	cwd, err := os.Getwd()
+	require.NoError(t, err)
+
+	require.NoError(t, demoCmd.Flags().Set("dir", dir))
+	t.Cleanup(func() { _ = demoCmd.Flags().Set("dir", "") })
+	var out bytes.Buffer
+	demoCmd.SetOut(&out)
+	demoCmd.SetIn(strings.NewReader(""))
+	t.Cleanup(func() {
+		demoCmd.SetOut(nil)
+		demoCmd.SetIn(nil)
+	})
+
+	require.NoError(t, runDemo(demoCmd, []string{"Add dark mode"}))
+
+	after, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, cwd, after, "working directory restored")
+	assert.Contains(t, out.String(), "Demo complete")
+	assert.Contains(t, out.String(), "4/4 specs/001-demo-feature/tasks.yaml")
+	assert.NotContains(t, out.String(), "Not valid")
+	for _, file := range []string{
+		"README.md",
+		"main.go",
+		".autospec/memory/constitution.yaml",
+		"specs/001-demo-feature/spec.yaml",
+		"specs/001-demo-feature/plan.yaml",
+		"specs/001-demo-feature/tasks.yaml",
+	} {
+		assert.FileExists(t, filepath.Join(dir, filepath.FromSlash(file)))
+	}
+	tasks, err := os.ReadFile(filepath.Join(dir, "specs", "001-demo-feature", "tasks.yaml"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(tasks), `status: "Pending"`)
+}
+
+// Not parallel: the demo changes the working directory.
+func TestRunDemoClean(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "demo")
+
+	require.NoError(t, demoCmd.Flags().Set("dir", dir))
+	require.NoError(t, demoCmd.Flags().Set("clean", "true"))
+	t.Cleanup(func() {
+		_ = demoCmd.Flags().Set("dir", "")
+		_ = demoCmd.Flags().Set("clean", "false")
+	})
+	var out bytes.Buffer
+	demoCmd.SetOut(&out)
+	demoCmd.SetIn(strings.NewReader(""))
+	t.Cleanup(func() {
+		demoCmd.SetOut(nil)
+		demoCmd.SetIn(nil)
+	})
+
+	require.NoError(t, runDemo(demoCmd, nil))
+
+	assert.Contains(t, out.String(), "Removed "+dir)
+	assert.NoDirExists(t, dir)
+}
+
+func TestScaffoldDemoProjectConfigLoads(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfg, err := demoConfig(dir, 0)
+	require.NoError(t, err)
+	require.NoError(t, scaffoldDemoProject(dir, cfg.CustomAgent))
+
+	loaded, err := config.LoadWithOptions(config.LoadOptions{
+		ProjectConfigPath: filepath.Join(dir, ".autospec", "config.yml"),
+		UserConfigPath:    filepath.Join(dir, "no-user-config.yml"),
+		SkipWarnings:      true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, loaded.CustomAgent)
+	assert.Equal(t, cfg.CustomAgent.Command, loaded.CustomAgent.Command)
+	assert.Equal(t, cfg.CustomAgent.Args, loaded.CustomAgent.Args)
+	assert.Equal(t, "./specs", loaded.CustomAgent.Env["MOCK_ARTIFACT_DIR"])
+	assert.Equal(t, demoSpecName, loaded.CustomAgent.Env["MOCK_SPEC_NAME"])
+	assert.True(t, loaded.SkipPreflight)
+}
+
+func TestShowDemoTourPauses(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	// One Enter only: the tour stops at the second pause.
+	require.NoError(t, showDemoTour(&out, strings.NewReader("\n"), t.TempDir(), true))
+
+	assert.Contains(t, out.String(), "1/4 .autospec/memory/constitution.yaml")
+	assert.Contains(t, out.String(), "2/4 specs/001-demo-feature/spec.yaml")
+	assert.NotContains(t, out.String(), "3/4")
+	assert.Contains(t, out.String(), "Not valid")
+}
+
+func TestFormatDemoCounts(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		counts map[string]int
+		want   string
+	}{
+		"empty":         {counts: nil, want: ""},
+		"sorted":        {counts: map[string]int{"total_tasks": 5, "phases": 2}, want: "2 phases, 5 total tasks"},
+		"zeros skipped": {counts: map[string]int{"completed": 3, "blocked": 0}, want: "3 completed"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, formatDemoCounts(tt.counts))
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// printCommandError reports the error that failed cmd, followed by the usage
+// text unless the command silenced it.
+//
+// With --json, the classified error is written to stdout in the errors of
+// the JSON envelope, with its machine-readable code so automation can branch
+// on it, unless the command already wrote its envelope.
+// Otherwise classified errors are printed with their remediation hint block
+// and other errors as plain "Error: ..." lines. CLIErrors returned as-is were
+// already printed by the command, and bare exit-code errors have nothing to
+// say, so neither is repeated.
+func printCommandError(cmd *cobra.Command, err error, stdout, stderr io.Writer) {
+	cliErr := clierrors.Classify(err)
+	_, printed := err.(*clierrors.CLIError)
+	switch {
+	case wantsJSON(cmd):
+		if !shared.JSONWritten(cmd) {
+			_ = shared.FprintJSON(stdout, cmd, nil, cliErr)
+		}
+	case printed || shared.IsExitError(err):
+	case len(cliErr.Remediation) > 0:
+		clierrors.FprintError(stderr, cliErr)
+	default:
+		fmt.Fprintln(stderr, "Error:", err)
+	}
+
+	if cmd != nil && !cmd.SilenceUsage {
+		fmt.Fprintln(stderr, cmd.UsageString())
+	}
+}
+
+// wantsJSON reports whether cmd was run with --json, including commands with
+// a --json flag of their own.
+func wantsJSON(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	useJSON, _ := cmd.Flags().GetBool("json")
+	return useJSON
+}
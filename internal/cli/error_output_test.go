@@ -0,0 +1,148 @@
+// Package cli tests command error rendering for text and --json output.
+// Related: internal/cli/error_output.go
+// Tags: cli, errors, json, remediation
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newErrorTestCmd(jsonFlag bool) *cobra.Command {
+	cmd := &cobra.Command{Use: "test", SilenceUsage: true}
+	cmd.Flags().Bool("json", false, "")
+	if jsonFlag {
+		_ = cmd.Flags().Set("json", "true")
+	}
+	return cmd
+}
+
+func TestPrintCommandError_Text(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err        error
+		wantStderr []string
+		wantEmpty  bool
+	}{
+		"classified error shows hint block": {
+			err: fmt.Errorf("plan stage: %w", clierrors.ErrArtifactMissing.Errorf("spec file not found in specs/001")),
+			wantStderr: []string{
+				"Prerequisite Error",
+				"plan stage: spec file not found in specs/001",
+				"To fix this:",
+				"autospec status",
+			},
+		},
+		"unclassified error keeps plain format": {
+			err:        errors.New("unknown command \"bogus\""),
+			wantStderr: []string{"Error: unknown command \"bogus\"\n"},
+		},
+		"CLIError already printed by the command": {
+			err:       clierrors.NewArgumentError("--phase must be a positive integer"),
+			wantEmpty: true,
+		},
+		"exit code error": {
+			err:       shared.NewExitError(shared.ExitInvalidArguments),
+			wantEmpty: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			printCommandError(newErrorTestCmd(false), tt.err, &stdout, &stderr)
+
+			assert.Empty(t, stdout.String())
+			if tt.wantEmpty {
+				assert.Empty(t, stderr.String())
+				return
+			}
+			for _, want := range tt.wantStderr {
+				assert.Contains(t, stderr.String(), want)
+			}
+		})
+	}
+}
+
+func TestPrintCommandError_JSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err      error
+		wantCode string
+	}{
+		"kind":          {err: clierrors.ErrRetryExhausted.Errorf("plan stage exhausted retries"), wantCode: "RETRY_EXHAUSTED"},
+		"CLIError":      {err: clierrors.NewArgumentError("--phase must be a positive integer"), wantCode: "INVALID_ARGUMENT"},
+		"unclassified":  {err: errors.New("boom"), wantCode: "RUNTIME_ERROR"},
+		"exit code err": {err: shared.NewExitError(shared.ExitTimeout), wantCode: "RUNTIME_ERROR"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var stdout, stderr bytes.Buffer
+			printCommandError(newErrorTestCmd(true), tt.err, &stdout, &stderr)
+
+			var got shared.JSONEnvelope
+			require.NoError(t, json.Unmarshal(stdout.Bytes(), &got), "stdout: %s", stdout.String())
+			assert.Equal(t, shared.JSONVersion, got.Version)
+			assert.Equal(t, "test", got.Command)
+			assert.Nil(t, got.Result)
+			require.Len(t, got.Errors, 1)
+			assert.Equal(t, tt.wantCode, string(got.Errors[0].Code))
+			assert.Equal(t, tt.err.Error(), got.Errors[0].Message)
+			assert.Empty(t, stderr.String())
+		})
+	}
+}
+
+func TestPrintCommandError_JSONAlreadyWritten(t *testing.T) {
+	t.Parallel()
+
+	cmd := newErrorTestCmd(true)
+	var written bytes.Buffer
+	require.NoError(t, shared.FprintJSON(&written, cmd, map[string]bool{"valid": false}))
+
+	var stdout, stderr bytes.Buffer
+	printCommandError(cmd, shared.NewExitError(shared.ExitValidationFailed), &stdout, &stderr)
+	assert.Empty(t, stdout.String(), "the command's own envelope is the only one")
+	assert.Empty(t, stderr.String())
+}
+
+func TestPrintCommandError_Usage(t *testing.T) {
+	t.Parallel()
+
+	cmd := &cobra.Command{Use: "widget"}
+	var stdout, stderr bytes.Buffer
+	printCommandError(cmd, errors.New("unknown flag: --bogus"), &stdout, &stderr)
+
+	out := stderr.String()
+	assert.Contains(t, out, "Error: unknown flag: --bogus")
+	assert.Contains(t, out, "Usage:")
+	assert.Less(t, bytes.Index(stderr.Bytes(), []byte("Error:")), bytes.Index(stderr.Bytes(), []byte("Usage:")),
+		"error should come before usage")
+}
+
+func TestRootCmd_JSONFlagDoesNotShadowLocalFlags(t *testing.T) {
+	t.Parallel()
+
+	flag := rootCmd.PersistentFlags().Lookup("json")
+	require.NotNil(t, flag)
+
+	// Commands with their own --json keep it; errors use the same flag value
+	cmd, _, err := rootCmd.Find([]string{"prereqs"})
+	require.NoError(t, err)
+	assert.NotNil(t, cmd.LocalFlags().Lookup("json"))
+}
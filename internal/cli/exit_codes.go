@@ -24,6 +24,12 @@ const (
 
 	// ExitTimeout indicates command execution timed out
 	ExitTimeout = shared.ExitTimeout
+
+	// ExitBudgetExceeded indicates a budget limit stopped the run
+	ExitBudgetExceeded = shared.ExitBudgetExceeded
+
+	// ExitCancelled indicates the run was stopped by SIGINT or SIGTERM
+	ExitCancelled = shared.ExitCancelled
 )
 
 // NewExitError creates a new exit error with the given code (re-exported from shared).
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var forecastCmd = &cobra.Command{
+	Use:   "forecast [spec] [prompt]",
+	Short: "Estimate effort, risk and sequencing for a spec's tasks into forecast.yaml",
+	Long: `Execute the /autospec.forecast command to estimate the tasks of a spec.
+
+The forecast command will:
+- Estimate effort in hours and a risk level for each task in tasks.yaml
+- Roll the estimates up into a total effort, overall risk and confidence
+- Suggest an order of work that front-loads risky tasks
+- Write forecast.yaml to the spec directory and validate it
+
+'autospec status' and 'autospec report' show the forecast rollup once
+forecast.yaml exists.
+
+Prerequisites:
+- tasks.yaml must exist (run 'autospec tasks' first)
+
+Without a spec argument, the current spec is auto-detected. The optional
+prompt is passed to the agent, e.g. to state team size or assumptions.`,
+	Example: `  # Forecast the current spec
+  autospec forecast
+
+  # Forecast a specific spec with extra context
+  autospec forecast 003 "One developer, new to the OAuth provider"`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runForecast,
+}
+
+func init() {
+	forecastCmd.GroupID = GroupOptionalStages
+	forecastCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts (overrides config when set)")
+	rootCmd.AddCommand(forecastCmd)
+}
+
+func runForecast(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
+	maxRetries, _ := cmd.Flags().GetInt("max-retries")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	specArg := ""
+	if len(args) > 0 {
+		specArg = args[0]
+	}
+	if err := shared.ApplySpecConfig(cmd, cfg, specArg); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("skip-preflight") {
+		cfg.SkipPreflight = skipPreflight
+	}
+	if cmd.Flags().Changed("max-retries") {
+		cfg.MaxRetries = maxRetries
+	}
+
+	var metadata *spec.Metadata
+	if len(args) > 0 {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, args[0])
+		if err == nil {
+			metadata.Detection = spec.DetectionExplicit
+		}
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detect spec: %w", err)
+	}
+	PrintSpecInfo(metadata)
+
+	var prompt string
+	if len(args) == 2 {
+		prompt = args[1]
+	}
+
+	// forecast needs the same artifacts as implement: a tasks.yaml
+	prereqResult := workflow.ValidateStagePrerequisites(workflow.StageImplement, metadata.Directory)
+	if !prereqResult.Valid {
+		fmt.Fprint(os.Stderr, prereqResult.ErrorMessage)
+		return NewExitError(ExitInvalidArguments)
+	}
+
+	notifHandler := notify.NewHandler(cfg.Notifications)
+	historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
+	specName := metadata.DirName()
+
+	return lifecycle.RunWithHistory(notifHandler, historyLogger, "forecast", specName, func() error {
+		orch := workflow.NewWorkflowOrchestrator(cfg)
+		orch.SetNotificationHandler(notifHandler)
+		shared.ApplyOutputStyle(cmd, orch)
+
+		rollup, err := orch.ExecuteForecast(specName, prompt)
+		if err != nil {
+			return fmt.Errorf("forecast stage failed: %w", err)
+		}
+		printForecastResult(cmd.OutOrStdout(), rollup)
+		return nil
+	})
+}
+
+// printForecastResult shows the rollup of a written forecast.
+func printForecastResult(out io.Writer, rollup *validation.ForecastRollup) {
+	if rollup == nil {
+		return
+	}
+	bold := color.New(color.Bold).SprintFunc()
+	fmt.Fprintf(out, "%s %s\n", bold("Forecast:"), rollup)
+	if rollup.HighRisk > 0 {
+		fmt.Fprintln(out, "See the sequencing in forecast.yaml for when to tackle the high-risk tasks.")
+	}
+}
@@ -0,0 +1,75 @@
+// Package cli_test tests the forecast command registration, arguments and result output.
+// Related: internal/cli/forecast.go
+// Tags: cli, forecast, effort, risk, command
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForecastCmdRegistration(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"forecast"})
+	require.NoError(t, err)
+	assert.Equal(t, forecastCmd, cmd)
+	assert.Equal(t, GroupOptionalStages, forecastCmd.GroupID)
+	assert.NotNil(t, forecastCmd.Flags().Lookup("max-retries"))
+}
+
+func TestForecastCmdArgs(t *testing.T) {
+	tests := map[string]struct {
+		args    []string
+		wantErr bool
+	}{
+		"no args":            {args: nil},
+		"spec only":          {args: []string{"003"}},
+		"spec and prompt":    {args: []string{"003", "One developer"}},
+		"too many arguments": {args: []string{"003", "One", "developer"}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := forecastCmd.Args(forecastCmd, tt.args)
+			assert.Equal(t, tt.wantErr, err != nil, "Args(%v) error = %v", tt.args, err)
+		})
+	}
+}
+
+func TestPrintForecastResult(t *testing.T) {
+	tests := map[string]struct {
+		rollup  *validation.ForecastRollup
+		want    []string
+		notWant []string
+	}{
+		"no rollup": {rollup: nil},
+		"low risk": {
+			rollup:  &validation.ForecastRollup{TotalEffortHours: 6, OverallRisk: "low", Tasks: 2},
+			want:    []string{"Forecast:", "6h, low risk"},
+			notWant: []string{"high-risk"},
+		},
+		"high-risk tasks": {
+			rollup: &validation.ForecastRollup{TotalEffortHours: 18.5, OverallRisk: "medium", Confidence: "low", Tasks: 3, HighRisk: 1},
+			want:   []string{"18.5h, medium risk (1 high-risk task), low confidence", "sequencing in forecast.yaml"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			printForecastResult(&out, tt.rollup)
+			if tt.rollup == nil {
+				assert.Empty(t, out.String())
+			}
+			for _, want := range tt.want {
+				assert.Contains(t, out.String(), want)
+			}
+			for _, notWant := range tt.notWant {
+				assert.NotContains(t, out.String(), notWant)
+			}
+		})
+	}
+}
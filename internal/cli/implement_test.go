@@ -65,10 +65,10 @@ func TestParseImplementArgs(t *testing.T) {
 			wantSpecName: "",
 			wantPrompt:   "complete remaining documentation",
 		},
-		"numeric prompt (not spec name)": {
-			args:         []string{"123"},
-			wantSpecName: "",
-			wantPrompt:   "123",
+		"bare spec number": {
+			args:         []string{"4"},
+			wantSpecName: "4",
+			wantPrompt:   "",
 		},
 		"spec with two-digit number": {
 			args:         []string{"42-answer"},
@@ -316,9 +316,9 @@ func TestSpecNamePattern(t *testing.T) {
 			input:      "feature-003",
 			isSpecName: false,
 		},
-		"invalid: just number": {
+		"valid: bare number": {
 			input:      "123",
-			isSpecName: false,
+			isSpecName: true,
 		},
 		"invalid: text only": {
 			input:      "complete-the-tasks",
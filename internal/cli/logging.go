@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"os"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/logging"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/spf13/cobra"
+)
+
+// loadRootConfig loads the configuration the root pre-run needs before the
+// command starts. It returns nil when the config fails to load; the command
+// itself reports the error.
+func loadRootConfig(cmd *cobra.Command) *config.Configuration {
+	configPath, _ := cmd.Flags().GetString("config")
+	opts := config.LoadOptions{ProjectConfigPath: configPath, SkipWarnings: true}
+	cfg, err := config.LoadWithOptions(opts)
+	if err != nil {
+		return nil
+	}
+	return cfg
+}
+
+// configureLogging installs the structured logger from the logging config
+// section before a command runs. --debug and --log-level override the console
+// level. A nil cfg falls back to the defaults. If the log file cannot be
+// opened, or encryption is enabled without a usable key, the run continues
+// with console logging only.
+func configureLogging(cmd *cobra.Command, cfg *config.Configuration) error {
+	var logCfg logging.Config
+	var stateDir string
+	if cfg != nil {
+		logCfg, stateDir = cfg.Logging, cfg.StateDir
+	}
+
+	if debug, _ := cmd.Flags().GetBool("debug"); debug {
+		logCfg.Level = "debug"
+	}
+	if cmd.Flags().Changed("log-level") {
+		logCfg.Level, _ = cmd.Flags().GetString("log-level")
+	}
+	if _, err := logging.ParseLevel(logCfg.Level); err != nil {
+		return err
+	}
+
+	if logCfg.File {
+		cipher, err := encrypt.Writer()
+		if err != nil {
+			output.Default().Warn("Log file disabled: %v", err)
+			logCfg.File = false
+		}
+		logCfg.Cipher = cipher
+	}
+
+	_, err := logging.Setup(logCfg, os.Stderr, stateDir)
+	if err != nil && logCfg.File {
+		output.Default().Warn("Log file disabled: %v", err)
+		logCfg.File = false
+		_, err = logging.Setup(logCfg, os.Stderr, stateDir)
+	}
+	return err
+}
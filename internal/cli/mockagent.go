@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/mockagent"
+	"github.com/spf13/cobra"
+)
+
+var mockAgentCmd = &cobra.Command{
+	Use:   mockagent.CommandName + " [prompt...]",
+	Short: "Run the built-in mock agent",
+	Long: `Act as an agent CLI without calling any model: write the fixed artifact of
+the stage named in the prompt (MOCK_ARTIFACT_DIR, MOCK_SPEC_NAME), print
+MOCK_RESPONSE_FILE and exit with MOCK_EXIT_CODE.
+
+Used by 'autospec demo'. To try the workflow in a project without an agent:
+
+  custom_agent:
+    command: autospec
+    args: ["__mockagent", "{{PROMPT}}"]
+    env:
+      MOCK_ARTIFACT_DIR: ./specs`,
+	Hidden:             true,
+	DisableFlagParsing: true,
+	// Skip the root's config loading and signal handling: the mock is a
+	// child process of a running autospec.
+	PersistentPreRunE: func(*cobra.Command, []string) error { return nil },
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if code := mockagent.Run(args, cmd.OutOrStdout()); code != 0 {
+			return shared.NewExitError(code)
+		}
+		return nil
+	},
+}
+
+func init() {
+	mockAgentCmd.GroupID = GroupInternal
+	rootCmd.AddCommand(mockAgentCmd)
+}
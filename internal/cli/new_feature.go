@@ -18,6 +18,7 @@ var (
 	newFeatureJSON      bool
 	newFeatureShortName string
 	newFeatureNumber    string
+	newFeatureCategory  string
 )
 
 // NewFeatureOutput is the JSON output structure for the new-feature command
@@ -40,6 +41,10 @@ This command:
 3. Creates a git branch (if in a git repository)
 4. Creates the feature directory under specs/
 
+The directory follows the specs_layout config setting: specs/001-name
+(numbered, the default), specs/2025-01-15-name (dated), specs/<category>/001-name
+(categorized, with --category) or specs/name (flat).
+
 The command outputs the created branch name, spec file path, and metadata.`,
 	Example: `  # Create a new feature from description
   autospec new-feature "Add user authentication"
@@ -51,7 +56,10 @@ The command outputs the created branch name, spec file path, and metadata.`,
   autospec new-feature --number 5 "OAuth2 integration"
 
   # JSON output for scripting
-  autospec new-feature --json "Add dark mode support"`,
+  autospec new-feature --json "Add dark mode support"
+
+  # Place the spec in a category folder (specs_layout: categorized)
+  autospec new-feature --category backend "Rate limit the API"`,
 	Args: cobra.ExactArgs(1),
 	RunE: runNewFeature,
 }
@@ -61,11 +69,15 @@ func init() {
 	newFeatureCmd.Flags().BoolVar(&newFeatureJSON, "json", false, "Output in JSON format")
 	newFeatureCmd.Flags().StringVar(&newFeatureShortName, "short-name", "", "Custom short name for the branch (2-4 words)")
 	newFeatureCmd.Flags().StringVar(&newFeatureNumber, "number", "", "Specify branch number manually (overrides auto-detection)")
+	newFeatureCmd.Flags().StringVar(&newFeatureCategory, "category", "", "Category folder for the spec (categorized layout only)")
 	rootCmd.AddCommand(newFeatureCmd)
 }
 
 func runNewFeature(cmd *cobra.Command, args []string) error {
 	featureDescription := args[0]
+	if newFeatureCategory != "" && spec.Locator().Layout() != spec.LayoutCategorized {
+		return fmt.Errorf("--category requires specs_layout: %s (current layout: %s)", spec.LayoutCategorized, spec.Locator().Layout())
+	}
 
 	specsDir, err := resolveSpecsDir(cmd)
 	if err != nil {
@@ -85,7 +97,7 @@ func runNewFeature(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("creating git branch: %w", err)
 	}
 
-	specFile, err := setupFeatureDirectory(specsDir, branchName)
+	specFile, err := setupFeatureDirectory(specsDir, branchName, newFeatureCategory)
 	if err != nil {
 		return fmt.Errorf("setting up feature directory: %w", err)
 	}
@@ -130,7 +142,7 @@ func determineBranchNumber(specsDir string) (string, error) {
 		return fmt.Sprintf("%03d", num), nil
 	}
 
-	branchNumber, err := spec.GetNextBranchNumber(specsDir)
+	branchNumber, err := spec.ReserveNumber(specsDir)
 	if err != nil {
 		return "", fmt.Errorf("failed to determine next branch number: %w", err)
 	}
@@ -163,14 +175,20 @@ func createGitBranch(branchName string, hasGit bool) error {
 }
 
 // setupFeatureDirectory creates the feature directory and returns spec file path
-func setupFeatureDirectory(specsDir, branchName string) (string, error) {
-	featureDir := spec.GetFeatureDirectory(specsDir, branchName)
+func setupFeatureDirectory(specsDir, branchName, category string) (string, error) {
+	featureDir := spec.GetFeatureDirectory(specsDir, branchName, category)
 	if err := os.MkdirAll(featureDir, 0o755); err != nil {
 		return "", fmt.Errorf("failed to create feature directory: %w", err)
 	}
 
 	specFile := filepath.Join(featureDir, "spec.yaml")
-	os.Setenv("SPECIFY_FEATURE", branchName)
+	// The spec name is the directory relative to specsDir, which includes
+	// the category in the categorized layout
+	specName, err := filepath.Rel(specsDir, featureDir)
+	if err != nil {
+		specName = branchName
+	}
+	os.Setenv("SPECIFY_FEATURE", specName)
 
 	return specFile, nil
 }
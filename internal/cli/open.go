@@ -0,0 +1,125 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+// openArtifacts are the artifacts autospec open knows.
+var openArtifacts = []string{"spec", "plan", "tasks"}
+
+var openCmd = &cobra.Command{
+	Use:   "open [spec] [spec|plan|tasks]",
+	Short: "Open a spec's spec.yaml, plan.yaml or tasks.yaml in your editor",
+	Long: `Open an artifact of a spec in the editor set by $VISUAL or $EDITOR. Without
+either, the file is handed to the operating system's default application
+(xdg-open, open or start).
+
+The artifact defaults to spec. Without a spec argument, the current spec is
+auto-detected; a single argument naming an artifact opens it for the
+current spec.
+
+The specify, plan and tasks commands take --open to do the same as soon as
+the artifact they generate passes validation.`,
+	Example: `  # Open spec.yaml of the current spec
+  autospec open
+
+  # Open plan.yaml of the current spec
+  autospec open plan
+
+  # Open tasks.yaml of spec 004
+  autospec open 004 tasks`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runOpen,
+}
+
+func init() {
+	openCmd.GroupID = GroupGettingStarted
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	specArg, artifact, err := parseOpenArgs(args)
+	if err != nil {
+		return err
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	var metadata *spec.Metadata
+	if specArg != "" {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, specArg)
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detect spec: %w", err)
+	}
+
+	path, err := openArtifactPath(metadata.Directory, artifact)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Opening %s\n", path)
+	return shared.OpenFile(path)
+}
+
+// parseOpenArgs splits the arguments of autospec open into a spec ("" to
+// detect it) and an artifact. A single argument is an artifact when it
+// names one, else a spec.
+func parseOpenArgs(args []string) (specArg, artifact string, err error) {
+	artifact = "spec"
+	switch len(args) {
+	case 1:
+		if slices.Contains(openArtifacts, args[0]) {
+			artifact = args[0]
+		} else {
+			specArg = args[0]
+		}
+	case 2:
+		specArg, artifact = args[0], args[1]
+		if !slices.Contains(openArtifacts, artifact) {
+			return "", "", clierrors.NewArgumentError(fmt.Sprintf("unknown artifact %q (expected %s)", artifact, strings.Join(openArtifacts, ", ")))
+		}
+	}
+	return specArg, artifact, nil
+}
+
+// openArtifactPath returns the file of artifact in specDir: its YAML file,
+// or the legacy Markdown one when only that exists.
+func openArtifactPath(specDir, artifact string) (string, error) {
+	path := filepath.Join(specDir, artifact+".yaml")
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	legacy := filepath.Join(specDir, artifact+".md")
+	if _, err := os.Stat(legacy); err == nil {
+		return legacy, nil
+	}
+	return "", fmt.Errorf("%s not found\nRun 'autospec %s' to generate it", path, openStage(artifact))
+}
+
+// openStage returns the command that generates artifact.
+func openStage(artifact string) string {
+	if artifact == "spec" {
+		return "specify"
+	}
+	return artifact
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOpenArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args         []string
+		wantSpec     string
+		wantArtifact string
+		wantErr      string
+	}{
+		"no args":           {wantArtifact: "spec"},
+		"artifact only":     {args: []string{"plan"}, wantArtifact: "plan"},
+		"spec only":         {args: []string{"004"}, wantSpec: "004", wantArtifact: "spec"},
+		"spec and artifact": {args: []string{"004-dark-mode", "tasks"}, wantSpec: "004-dark-mode", wantArtifact: "tasks"},
+		"unknown artifact":  {args: []string{"004", "research"}, wantErr: `unknown artifact "research" (expected spec, plan, tasks)`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			specArg, artifact, err := parseOpenArgs(tt.args)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantSpec, specArg)
+			assert.Equal(t, tt.wantArtifact, artifact)
+		})
+	}
+}
+
+func TestOpenArtifactPath(t *testing.T) {
+	t.Parallel()
+
+	specDir := t.TempDir()
+	for _, file := range []string{"spec.yaml", "spec.md", "tasks.md"} {
+		require.NoError(t, os.WriteFile(filepath.Join(specDir, file), nil, 0o644))
+	}
+
+	tests := map[string]struct {
+		artifact string
+		want     string
+		wantErr  string
+	}{
+		"yaml preferred":  {artifact: "spec", want: "spec.yaml"},
+		"legacy markdown": {artifact: "tasks", want: "tasks.md"},
+		"missing":         {artifact: "plan", wantErr: "Run 'autospec plan' to generate it"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := openArtifactPath(specDir, tt.artifact)
+			if tt.wantErr != "" {
+				require.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(specDir, tt.want), got)
+		})
+	}
+}
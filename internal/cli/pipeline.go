@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+var pipelineCmd = &cobra.Command{
+	Use:   "pipeline",
+	Short: "List and run custom pipelines defined in config",
+	Long: `Custom pipelines are ordered stages defined under 'pipelines' in config.
+Each stage sends a command to the agent, then validates the artifact it
+writes in the spec directory, retrying like the built-in stages.
+
+Available subcommands:
+  list  List the defined pipelines and their stages
+  run   Run a pipeline against a spec`,
+	Example: `  # List pipelines
+  autospec pipeline list
+
+  # Run the 'docs' pipeline on the current spec
+  autospec pipeline run docs
+
+  # Run it on a specific spec, passing a prompt to the command templates
+  autospec pipeline run docs --spec 003-user-auth "Audience: API consumers"`,
+}
+
+var pipelineListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the custom pipelines defined in config",
+	Args:  cobra.NoArgs,
+	RunE:  runPipelineList,
+}
+
+var pipelineRunCmd = &cobra.Command{
+	Use:   "run <pipeline> [prompt]",
+	Short: "Run a custom pipeline's stages in order against a spec",
+	Long: `Run each stage of the named pipeline in order. The first stage that fails
+after its retries stops the pipeline.
+
+The optional prompt is available to stage command templates as {{.Prompt}}.
+Without --spec, the current spec is auto-detected.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runPipelineRun,
+}
+
+func init() {
+	pipelineCmd.GroupID = GroupWorkflows
+	pipelineRunCmd.Flags().String("spec", "", "Spec to run the pipeline against (default: auto-detect)")
+	pipelineRunCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts for stages without their own retries")
+	pipelineCmd.AddCommand(pipelineListCmd)
+	pipelineCmd.AddCommand(pipelineRunCmd)
+	rootCmd.AddCommand(pipelineCmd)
+}
+
+func runPipelineList(cmd *cobra.Command, _ []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	printPipelines(cmd.OutOrStdout(), workflow.NewWorkflowOrchestrator(cfg).Pipelines())
+	return nil
+}
+
+// printPipelines lists each pipeline with its stages and their artifacts.
+func printPipelines(out io.Writer, pipelines []workflow.Pipeline) {
+	if len(pipelines) == 0 {
+		fmt.Fprintln(out, "No pipelines defined. Add one under 'pipelines' in config.")
+		return
+	}
+	for _, p := range pipelines {
+		if p.Description != "" {
+			fmt.Fprintf(out, "%s - %s\n", p.Name, p.Description)
+		} else {
+			fmt.Fprintln(out, p.Name)
+		}
+		for i, s := range p.Stages {
+			line := fmt.Sprintf("  %d. %s", i+1, s.Name)
+			if s.Artifact != "" {
+				line += " → " + s.Artifact
+			}
+			if s.Validator != "" {
+				line += fmt.Sprintf(" (%s)", s.Validator)
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+}
+
+func runPipelineRun(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	specName, _ := cmd.Flags().GetString("spec")
+	maxRetries, _ := cmd.Flags().GetInt("max-retries")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	if err := shared.ApplySpecConfig(cmd, cfg, specName); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("max-retries") {
+		cfg.MaxRetries = maxRetries
+	}
+
+	orch := workflow.NewWorkflowOrchestrator(cfg)
+	name := args[0]
+	if _, err := orch.LookupPipeline(name); err != nil {
+		return err
+	}
+	var prompt string
+	if len(args) == 2 {
+		prompt = args[1]
+	}
+
+	var metadata *spec.Metadata
+	if specName != "" {
+		if _, err := os.Stat(filepath.Join(cfg.SpecsDir, specName)); err != nil {
+			return fmt.Errorf("spec not found: %s", specName)
+		}
+		metadata = &spec.Metadata{Name: specName, Directory: filepath.Join(cfg.SpecsDir, specName), Detection: spec.DetectionExplicit}
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+		if err != nil {
+			return fmt.Errorf("failed to detect spec: %w\n\nUse --spec to choose one explicitly", err)
+		}
+	}
+	PrintSpecInfo(metadata)
+
+	notifHandler := notify.NewHandler(cfg.Notifications)
+	historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
+
+	return lifecycle.RunWithHistory(notifHandler, historyLogger, "pipeline "+name, metadata.DirName(), func() error {
+		orch.SetNotificationHandler(notifHandler)
+		shared.ApplyOutputStyle(cmd, orch)
+		return orch.ExecutePipeline(name, metadata.DirName(), prompt)
+	})
+}
@@ -0,0 +1,44 @@
+// Package cli_test tests the pipeline command registration and listing.
+// Related: internal/cli/pipeline.go
+// Tags: cli, pipeline, config, command
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineCmdRegistration(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"pipeline", "run"})
+	require.NoError(t, err)
+	assert.Equal(t, pipelineRunCmd, cmd)
+	assert.Equal(t, GroupWorkflows, pipelineCmd.GroupID)
+	assert.NotNil(t, pipelineRunCmd.Flags().Lookup("spec"))
+	assert.Error(t, pipelineRunCmd.Args(pipelineRunCmd, []string{}))
+
+	cmd, _, err = rootCmd.Find([]string{"pipeline", "list"})
+	require.NoError(t, err)
+	assert.Equal(t, pipelineListCmd, cmd)
+}
+
+func TestPrintPipelines(t *testing.T) {
+	var out bytes.Buffer
+	printPipelines(&out, nil)
+	assert.Contains(t, out.String(), "No pipelines defined")
+
+	out.Reset()
+	printPipelines(&out, []workflow.Pipeline{{
+		Name:        "docs",
+		Description: "User docs",
+		Stages: []config.PipelineStageConfig{
+			{Name: "outline", Artifact: "outline.yaml", Validator: "yaml"},
+			{Name: "publish"},
+		},
+	}})
+	assert.Equal(t, "docs - User docs\n  1. outline → outline.yaml (yaml)\n  2. publish\n", out.String())
+}
@@ -103,7 +103,7 @@ This is useful when you want to review the generated artifacts before implementa
 
 			// Create workflow orchestrator
 			orchestrator := workflow.NewWorkflowOrchestrator(cfg)
-			orchestrator.Executor.NotificationHandler = notifHandler
+			orchestrator.SetNotificationHandler(notifHandler)
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orchestrator)
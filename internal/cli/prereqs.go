@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ariel-frischer/autospec/internal/cli/util"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/git"
 	"github.com/ariel-frischer/autospec/internal/spec"
 	"github.com/spf13/cobra"
@@ -124,7 +125,7 @@ func runPrereqs(cmd *cobra.Command, args []string) error {
 
 	// Validate feature directory exists
 	if _, err := os.Stat(featureDir); os.IsNotExist(err) {
-		return fmt.Errorf("feature directory not found: %s\nRun /autospec.specify first to create the feature structure", featureDir)
+		return clierrors.ErrArtifactMissing.Errorf("feature directory not found: %s\nRun /autospec.specify first to create the feature structure", featureDir)
 	}
 
 	// Determine what to require (default is require-plan if nothing specified)
@@ -133,19 +134,19 @@ func runPrereqs(cmd *cobra.Command, args []string) error {
 	// Validate required files
 	if prereqsRequireSpec {
 		if _, err := os.Stat(featureSpec); os.IsNotExist(err) {
-			return fmt.Errorf("no spec.yaml found in %s\nRun /autospec.specify first to create the spec", featureDir)
+			return clierrors.ErrArtifactMissing.Errorf("no spec.yaml found in %s\nRun /autospec.specify first to create the spec", featureDir)
 		}
 	}
 
 	if requirePlan {
 		if _, err := os.Stat(implPlan); os.IsNotExist(err) {
-			return fmt.Errorf("no plan.yaml found in %s\nRun /autospec.plan first to create the plan", featureDir)
+			return clierrors.ErrArtifactMissing.Errorf("no plan.yaml found in %s\nRun /autospec.plan first to create the plan", featureDir)
 		}
 	}
 
 	if prereqsRequireTasks {
 		if _, err := os.Stat(tasks); os.IsNotExist(err) {
-			return fmt.Errorf("no tasks.yaml found in %s\nRun /autospec.tasks first to create tasks", featureDir)
+			return clierrors.ErrArtifactMissing.Errorf("no tasks.yaml found in %s\nRun /autospec.tasks first to create tasks", featureDir)
 		}
 	}
 
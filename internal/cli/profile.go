@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/spf13/cobra"
+)
+
+// applyProfileSelection validates the workflow profile selected with
+// --profile or AUTOSPEC_PROFILE and exports it, so config.Load applies it in
+// this command and in agent subprocesses.
+func applyProfileSelection(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("profile")
+	explicit := cmd.Flags().Changed("profile")
+	if !explicit {
+		name = os.Getenv(config.ProfileEnvVar)
+	}
+	if name == "" {
+		return nil
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	if _, err := cfg.LookupProfile(name); err != nil {
+		// Agent subprocesses in a package directory may not see the
+		// project's profiles; only reject names where profiles are defined
+		if !explicit && len(cfg.Profiles) == 0 {
+			return nil
+		}
+		return err
+	}
+	if err := os.Setenv(config.ProfileEnvVar, name); err != nil {
+		return fmt.Errorf("selecting profile: %w", err)
+	}
+	return nil
+}
+
+// applyProfileStages adds the optional stages of the selected profile to a
+// run. Clarify is added to runs that include plan; checklist and analyze to
+// runs that include implement. Stage flags given on the command line win.
+func applyProfileStages(cmd *cobra.Command, cfg *config.Configuration, sc *workflow.StageConfig) {
+	p, ok := cfg.ActiveProfile()
+	if !ok {
+		return
+	}
+	set := func(flag string, include *bool, before bool, stage *bool) {
+		if include != nil && before && !cmd.Flags().Changed(flag) {
+			*stage = *include
+		}
+	}
+	set("clarify", p.Stages.Clarify, sc.Plan, &sc.Clarify)
+	set("checklist", p.Stages.Checklist, sc.Implement, &sc.Checklist)
+	set("analyze", p.Stages.Analyze, sc.Implement, &sc.Analyze)
+}
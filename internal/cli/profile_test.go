@@ -0,0 +1,135 @@
+// Package cli_test tests --profile selection of workflow profiles.
+// Related: internal/cli/profile.go
+// Tags: cli, profile, config, run
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newProfileTestCmd returns a command with the flags profile handling reads.
+func newProfileTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("config", ".autospec/config.yml", "")
+	cmd.Flags().String("profile", "", "")
+	cmd.Flags().Bool("clarify", false, "")
+	cmd.Flags().Bool("checklist", false, "")
+	cmd.Flags().Bool("analyze", false, "")
+	return cmd
+}
+
+// setupProfileProject creates a project whose config defines a nightly profile.
+func setupProfileProject(t *testing.T) {
+	t.Helper()
+	root := t.TempDir()
+	t.Chdir(root)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, ".config"))
+	t.Setenv("HOME", root)
+	t.Setenv(config.ProfileEnvVar, "")
+	require.NoError(t, os.Unsetenv(config.ProfileEnvVar))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".autospec"), 0o755))
+	cfg := "profiles:\n  nightly:\n    max_retries: 4\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".autospec", "config.yml"), []byte(cfg), 0o644))
+}
+
+func TestApplyProfileSelection(t *testing.T) {
+	tests := map[string]struct {
+		flag    string
+		env     string
+		wantEnv string
+		wantErr string
+	}{
+		"no profile":                 {},
+		"built-in profile from flag": {flag: "thorough", wantEnv: "thorough"},
+		"configured profile":         {flag: "nightly", wantEnv: "nightly"},
+		"profile from env":           {env: "fast", wantEnv: "fast"},
+		"unknown profile from flag":  {flag: "quick", wantErr: "available: budget, fast, nightly, thorough"},
+		"unknown profile from env":   {env: "quick", wantEnv: "quick", wantErr: "unknown profile"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setupProfileProject(t)
+			cmd := newProfileTestCmd()
+			if tt.flag != "" {
+				require.NoError(t, cmd.Flags().Set("profile", tt.flag))
+			}
+			if tt.env != "" {
+				t.Setenv(config.ProfileEnvVar, tt.env)
+			}
+
+			err := applyProfileSelection(cmd)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantEnv, os.Getenv(config.ProfileEnvVar))
+		})
+	}
+}
+
+func TestApplyProfileStages(t *testing.T) {
+	tests := map[string]struct {
+		profile       string
+		core          workflow.StageConfig
+		flags         map[string]string
+		wantClarify   bool
+		wantChecklist bool
+		wantAnalyze   bool
+	}{
+		"no profile": {
+			core: workflow.StageConfig{Specify: true, Plan: true, Tasks: true, Implement: true},
+		},
+		"thorough adds all optional stages to a full run": {
+			profile:       "thorough",
+			core:          workflow.StageConfig{Specify: true, Plan: true, Tasks: true, Implement: true},
+			wantClarify:   true,
+			wantChecklist: true,
+			wantAnalyze:   true,
+		},
+		"thorough adds only clarify without implement": {
+			profile:     "thorough",
+			core:        workflow.StageConfig{Specify: true, Plan: true},
+			wantClarify: true,
+		},
+		"flags override the profile": {
+			profile:       "thorough",
+			core:          workflow.StageConfig{Plan: true, Implement: true},
+			flags:         map[string]string{"analyze": "false"},
+			wantClarify:   true,
+			wantChecklist: true,
+		},
+		"fast removes optional stages not given as flags": {
+			profile:     "fast",
+			core:        workflow.StageConfig{Plan: true, Implement: true, Clarify: true},
+			wantClarify: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cmd := newProfileTestCmd()
+			for flag, value := range tt.flags {
+				require.NoError(t, cmd.Flags().Set(flag, value))
+			}
+			cfg := &config.Configuration{Profile: tt.profile}
+			sc := tt.core
+
+			applyProfileStages(cmd, cfg, &sc)
+			assert.Equal(t, tt.wantClarify, sc.Clarify, "clarify")
+			assert.Equal(t, tt.wantChecklist, sc.Checklist, "checklist")
+			assert.Equal(t, tt.wantAnalyze, sc.Analyze, "analyze")
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/cli/util"
+	"github.com/ariel-frischer/autospec/internal/commands"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/promptsize"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// offlineStage describes a stage that can be run by pasting its prompt into
+// a web UI: the artifact the reply must contain and the artifacts included
+// in the context pack.
+type offlineStage struct {
+	output  validation.ArtifactType
+	context []validation.ArtifactType
+	// template names the .autospec/prompts template applied to the prompt
+	// argument, as in a normal run ("" for none).
+	template string
+}
+
+var offlineStages = map[workflow.Stage]offlineStage{
+	workflow.StageConstitution: {output: validation.ArtifactTypeConstitution},
+	workflow.StageSpecify:      {output: validation.ArtifactTypeSpec},
+	workflow.StagePlan: {
+		output:   validation.ArtifactTypePlan,
+		context:  []validation.ArtifactType{validation.ArtifactTypeSpec},
+		template: workflow.PromptPlan,
+	},
+	workflow.StageTasks: {
+		output:   validation.ArtifactTypeTasks,
+		context:  []validation.ArtifactType{validation.ArtifactTypeSpec, validation.ArtifactTypePlan},
+		template: workflow.PromptTasks,
+	},
+	workflow.StageAnalyze: {
+		output:  validation.ArtifactTypeAnalysis,
+		context: []validation.ArtifactType{validation.ArtifactTypeSpec, validation.ArtifactTypePlan, validation.ArtifactTypeTasks},
+	},
+}
+
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Work with stage prompts without a CLI agent",
+	Long: `Work with stage prompts without a CLI agent.
+
+'autospec prompt export' writes a stage's fully rendered prompt to a file, so
+it can be pasted into a web chat UI. Install the reply with
+'autospec artifact ingest'.`,
+}
+
+var promptExportCmd = &cobra.Command{
+	Use:   "export <stage> [prompt]",
+	Short: "Write a stage's rendered prompt and context to a file",
+	Long: `Write a stage's fully rendered prompt to a file for use without a CLI agent.
+
+The file contains the stage's command instructions with the prompt argument
+filled in, followed by a context pack: the feature paths, the project
+constitution, the upstream artifacts the stage reads (spec.yaml, plan.yaml,
+tasks.yaml), and the schema the reply must follow. Paste it into any chat UI,
+save the YAML reply, and install it with 'autospec artifact ingest <file>'.
+Context files that push the prompt over the prompt_size limit are
+summarized, and the trim is reported on stderr.
+
+Stages: constitution, specify, plan, tasks, analyze. Implement changes the
+codebase and needs a CLI agent; clarify and checklist are not supported.
+
+The prompt argument is the feature description for specify, and optional
+guidance for the other stages (prompt templates in .autospec/prompts apply,
+as in a normal run).`,
+	Example: `  # Export the plan prompt for the current spec and paste it into a web UI
+  autospec prompt export plan
+  autospec artifact ingest ~/Downloads/plan.yaml
+
+  # Start a feature without any CLI agent
+  autospec prompt export specify "Add dark mode toggle" -o specify.md
+
+  # Print to stdout for a specific spec
+  autospec prompt export tasks --spec 003-dark-mode -o -`,
+	Args:          cobra.RangeArgs(1, 2),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		configPath, _ := cmd.Flags().GetString("config")
+		specName, _ := cmd.Flags().GetString("spec")
+		outputPath, _ := cmd.Flags().GetString("output")
+
+		cfg, err := config.Load(configPath)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error loading config: %v\n", err)
+			return NewExitError(ExitInvalidArguments)
+		}
+
+		stage, err := parseOfflineStage(args[0])
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			return NewExitError(ExitInvalidArguments)
+		}
+		var prompt string
+		if len(args) == 2 {
+			prompt = args[1]
+		}
+		if stage == workflow.StageSpecify && prompt == "" {
+			fmt.Fprintln(cmd.ErrOrStderr(), "Error: specify needs a feature description")
+			return NewExitError(ExitInvalidArguments)
+		}
+
+		specDir, err := resolveExportSpecDir(stage, cfg.SpecsDir, specName)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			return NewExitError(ExitInvalidArguments)
+		}
+
+		if specDir != "" {
+			if _, err := cfg.ApplySpecConfig(specDir); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+				return NewExitError(ExitInvalidArguments)
+			}
+		}
+
+		templates := &workflow.PromptTemplates{Dir: workflow.DefaultPromptsDir(), Inline: cfg.SpecPrompts}
+		rendered, size, err := renderOfflinePrompt(stage, cfg.SpecsDir, specDir, prompt, templates, cfg.PromptSize)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Error: %v\n", err)
+			return NewExitError(ExitInvalidArguments)
+		}
+		printPromptSize(cmd.ErrOrStderr(), size, cfg.PromptSize)
+
+		if outputPath == "-" {
+			_, err := io.WriteString(cmd.OutOrStdout(), rendered)
+			return err
+		}
+		if outputPath == "" {
+			outputPath = fmt.Sprintf("autospec-%s-prompt.md", stage)
+		}
+		if err := os.WriteFile(outputPath, []byte(rendered), 0o644); err != nil {
+			return fmt.Errorf("writing prompt: %w", err)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Fprintf(cmd.OutOrStdout(), "%s Wrote %s prompt to %s\n", green("✓"), stage, outputPath)
+		fmt.Fprintf(cmd.OutOrStdout(), "Paste it into a chat UI, save the reply as %s.yaml, then run: autospec artifact ingest %s.yaml\n",
+			offlineStages[stage].output, offlineStages[stage].output)
+		return nil
+	},
+}
+
+func init() {
+	promptCmd.GroupID = GroupInternal
+	promptExportCmd.Flags().String("spec", "", "Spec directory name (default: detected from the git branch)")
+	promptExportCmd.Flags().StringP("output", "o", "", "Output file, or - for stdout (default: autospec-<stage>-prompt.md)")
+	promptCmd.AddCommand(promptExportCmd)
+	rootCmd.AddCommand(promptCmd)
+}
+
+// parseOfflineStage parses a stage name and checks it can be exported.
+func parseOfflineStage(name string) (workflow.Stage, error) {
+	stages, err := workflow.ParseStageList([]string{name})
+	if err != nil {
+		return "", err
+	}
+	if len(stages) != 1 {
+		return "", fmt.Errorf("no stage given")
+	}
+	stage := stages[0]
+	if _, ok := offlineStages[stage]; !ok {
+		return "", fmt.Errorf("stage %q cannot be exported (supported: constitution, specify, plan, tasks, analyze)", stage)
+	}
+	return stage, nil
+}
+
+// resolveExportSpecDir returns the spec directory whose artifacts go into the
+// context pack. Specify and constitution work without one.
+func resolveExportSpecDir(stage workflow.Stage, specsDir, specName string) (string, error) {
+	if stage == workflow.StageSpecify || stage == workflow.StageConstitution {
+		return "", nil
+	}
+	if specName != "" {
+		dir := filepath.Join(specsDir, specName)
+		if _, err := os.Stat(dir); err != nil {
+			return "", fmt.Errorf("spec directory not found: %s", dir)
+		}
+		return dir, nil
+	}
+	metadata, err := spec.DetectCurrentSpec(specsDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to detect spec: %w\nHint: Run from a spec branch or pass --spec", err)
+	}
+	return metadata.Directory, nil
+}
+
+// renderOfflinePrompt builds the exported prompt: the stage's command template
+// with $ARGUMENTS filled in, then the context pack. Context files that push
+// the prompt over the prompt_size limit are summarized as limits configures;
+// the returned result says what was trimmed.
+func renderOfflinePrompt(stage workflow.Stage, specsDir, specDir, prompt string, templates *workflow.PromptTemplates, limits promptsize.Config) (string, promptsize.Result, error) {
+	st := offlineStages[stage]
+	if st.template != "" {
+		var err error
+		prompt, err = templates.Render(st.template, workflow.PromptTemplateData{SpecName: filepath.Base(specDir), Prompt: prompt})
+		if err != nil {
+			return "", promptsize.Result{}, err
+		}
+	}
+
+	tmpl, err := commands.GetTemplate("autospec." + string(stage))
+	if err != nil {
+		return "", promptsize.Result{}, fmt.Errorf("loading %s command template: %w", stage, err)
+	}
+	body := strings.ReplaceAll(commands.TemplateBody(tmpl), "$ARGUMENTS", prompt)
+
+	var sb strings.Builder
+	artifact := string(st.output) + ".yaml"
+	fmt.Fprintf(&sb, "# autospec %s\n\n", stage)
+	fmt.Fprintf(&sb, "This prompt was exported with `autospec prompt export` for use without a CLI agent. ")
+	fmt.Fprintf(&sb, "You cannot run commands or read files: the results of the setup commands and the files the instructions refer to are in the Context section at the end. ")
+	fmt.Fprintf(&sb, "Follow the instructions, then reply with the complete content of `%s` in a single ```yaml code block and nothing else.\n\n", artifact)
+	sb.WriteString("---\n\n")
+	sb.WriteString(strings.TrimSpace(body))
+	sb.WriteString("\n\n---\n\n# Context\n\n")
+
+	if err := writeContextPaths(&sb, stage, specsDir, specDir); err != nil {
+		return "", promptsize.Result{}, err
+	}
+	var pack contextPack
+	pack.addFixed("instructions", sb.String())
+	if check := workflow.CheckConstitutionExists(); check.Exists {
+		title := "Constitution"
+		if stage == workflow.StageConstitution {
+			title = "Current constitution"
+		}
+		if err := pack.addFile(title, check.Path); err != nil {
+			return "", promptsize.Result{}, err
+		}
+	}
+	for _, input := range st.context {
+		path := filepath.Join(specDir, string(input)+".yaml")
+		if _, err := os.Stat(path); err != nil {
+			return "", promptsize.Result{}, fmt.Errorf("%s.yaml not found in %s\nHint: export and ingest the %s stage first", input, specDir, producingStage(input))
+		}
+		if err := pack.addFile(string(input)+".yaml", path); err != nil {
+			return "", promptsize.Result{}, err
+		}
+	}
+	schema, err := contextSchema(st.output)
+	if err != nil {
+		return "", promptsize.Result{}, err
+	}
+	pack.addFixed("schema", schema)
+
+	result := promptsize.Fit(pack, limits)
+	return renderParts(result.Parts), result, nil
+}
+
+// contextPack is the exported prompt as parts prompt_size truncation may
+// trim: file contents are File parts, everything else is Fixed.
+type contextPack []promptsize.Part
+
+// addFixed appends rendered text that is never trimmed.
+func (p *contextPack) addFixed(name, text string) {
+	*p = append(*p, promptsize.Part{Name: name, Kind: promptsize.Fixed, Text: text})
+}
+
+// addFile appends a file's content as a fenced block.
+func (p *contextPack) addFile(title, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	lang := "yaml"
+	if filepath.Ext(path) == ".md" {
+		lang = "markdown"
+	}
+	p.addFixed(title, fmt.Sprintf("## %s (`%s`)\n\n```%s\n", title, path, lang))
+	*p = append(*p, promptsize.Part{Name: filepath.Base(path), Kind: promptsize.File, Text: strings.TrimRight(string(data), "\n")})
+	p.addFixed(title, "\n```\n\n")
+	return nil
+}
+
+// renderParts joins the (possibly trimmed) parts of a context pack.
+func renderParts(parts []promptsize.Part) string {
+	var sb strings.Builder
+	for _, part := range parts {
+		sb.WriteString(part.Text)
+	}
+	return sb.String()
+}
+
+// writeContextPaths writes the values the template's setup commands
+// ('autospec prereqs', 'autospec new-feature') would have returned.
+func writeContextPaths(sb *strings.Builder, stage workflow.Stage, specsDir, specDir string) error {
+	sb.WriteString("## Setup command results\n\n")
+	fmt.Fprintf(sb, "- AUTOSPEC_VERSION: autospec %s\n", util.Version)
+	fmt.Fprintf(sb, "- CREATED_DATE: %s\n", time.Now().UTC().Format(time.RFC3339))
+	switch stage {
+	case workflow.StageSpecify:
+		num, err := spec.GetNextBranchNumber(specsDir)
+		if err != nil {
+			return fmt.Errorf("determining feature number: %w", err)
+		}
+		fmt.Fprintf(sb, "- FEATURE_NUM: %s\n", num)
+		fmt.Fprintf(sb, "- BRANCH_NAME: %s-<short-name> (choose the short name as instructed; `autospec artifact ingest` creates the directory from `feature.branch`)\n", num)
+	case workflow.StageConstitution:
+	default:
+		fmt.Fprintf(sb, "- FEATURE_DIR: %s\n", specDir)
+		fmt.Fprintf(sb, "- FEATURE_SPEC: %s\n", filepath.Join(specDir, "spec.yaml"))
+		fmt.Fprintf(sb, "- IMPL_PLAN: %s\n", filepath.Join(specDir, "plan.yaml"))
+		fmt.Fprintf(sb, "- TASKS: %s\n", filepath.Join(specDir, "tasks.yaml"))
+	}
+	sb.WriteString("\n")
+	return nil
+}
+
+// contextSchema renders the fields the reply must have, as printed by
+// 'autospec artifact <type> --schema'.
+func contextSchema(artType validation.ArtifactType) (string, error) {
+	var buf bytes.Buffer
+	if err := printSchema(artType, &buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("## Required schema for %s.yaml\n\n```text\n%s\n```\n", artType, strings.TrimRight(buf.String(), "\n")), nil
+}
+
+// printPromptSize reports what truncation trimmed from the context pack and
+// warns when the prompt is still over the prompt_size limit.
+func printPromptSize(w io.Writer, r promptsize.Result, limits promptsize.Config) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+	if len(r.Trimmed) > 0 {
+		fmt.Fprintf(w, "%s Trimmed the context pack to ~%d tokens: %s\n", yellow("⚠"), r.Tokens, strings.Join(r.Trimmed, ", "))
+	}
+	if r.Over() {
+		fmt.Fprintf(w, "%s Prompt is ~%d tokens, over %d%% of the %d-token context window (prompt_size)\n",
+			yellow("⚠"), r.Tokens, limits.WarnPercent, limits.ContextWindow)
+	}
+}
+
+// producingStage names the stage that writes an artifact type.
+func producingStage(artType validation.ArtifactType) workflow.Stage {
+	for stage, st := range offlineStages {
+		if st.output == artType {
+			return stage
+		}
+	}
+	return workflow.Stage(artType)
+}
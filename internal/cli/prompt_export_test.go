@@ -0,0 +1,144 @@
+// Package cli tests prompt export for workflows without a CLI agent.
+// Related: internal/cli/prompt_export.go
+// Tags: cli, prompt, export, offline
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/promptsize"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+)
+
+func TestParseOfflineStage(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		want    workflow.Stage
+		wantErr string
+	}{
+		"plan":           {name: "plan", want: workflow.StagePlan},
+		"case folded":    {name: "Tasks", want: workflow.StageTasks},
+		"implement":      {name: "implement", wantErr: "cannot be exported"},
+		"unknown stage":  {name: "deploy", wantErr: "unknown stage"},
+		"empty argument": {name: " ", wantErr: "no stage given"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseOfflineStage(tt.name)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("stage = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderOfflinePrompt_Plan(t *testing.T) {
+	specsDir := t.TempDir()
+	specDir := filepath.Join(specsDir, "001-example-feature")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "spec.yaml"), readFixture(t, "spec", "valid.yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := renderOfflinePrompt(workflow.StagePlan, specsDir, specDir, "Use PostgreSQL", nil, promptsize.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{
+		"# autospec plan",
+		"reply with the complete content of `plan.yaml`",
+		"Use PostgreSQL",
+		"FEATURE_DIR: " + specDir,
+		"## spec.yaml",
+		"Add user authentication to the application",
+		"## Required schema for plan.yaml",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("prompt should contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{"$ARGUMENTS", "description: Generate YAML"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("prompt should not contain %q", unwanted)
+		}
+	}
+}
+
+func TestRenderOfflinePrompt_SummarizesOverLimit(t *testing.T) {
+	specsDir := t.TempDir()
+	specDir := filepath.Join(specsDir, "001-example-feature")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "spec.yaml"), readFixture(t, "spec", "valid.yaml"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	full, _, err := renderOfflinePrompt(workflow.StagePlan, specsDir, specDir, "", nil, promptsize.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A window just under the full prompt forces spec.yaml to be summarized.
+	limits := promptsize.Config{
+		ContextWindow: promptsize.EstimateTokens(full) - 10,
+		WarnPercent:   100,
+		Truncation:    []string{promptsize.SummarizeFiles},
+	}
+	got, size, err := renderOfflinePrompt(workflow.StagePlan, specsDir, specDir, "", nil, limits)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(size.Trimmed) != 1 || !strings.HasPrefix(size.Trimmed[0], "summarized spec.yaml") {
+		t.Errorf("Trimmed = %v, want spec.yaml summarized", size.Trimmed)
+	}
+	if size.Over() {
+		t.Errorf("prompt is ~%d tokens, want at most %d", size.Tokens, size.Limit)
+	}
+	for _, want := range []string{"## spec.yaml", "lines omitted by autospec", "## Required schema for plan.yaml"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("prompt should contain %q", want)
+		}
+	}
+}
+
+func TestRenderOfflinePrompt_MissingUpstream(t *testing.T) {
+	specDir := t.TempDir()
+	_, _, err := renderOfflinePrompt(workflow.StageTasks, filepath.Dir(specDir), specDir, "", nil, promptsize.Config{})
+	if err == nil || !strings.Contains(err.Error(), "export and ingest the specify stage first") {
+		t.Errorf("error = %v, want hint about the specify stage", err)
+	}
+}
+
+func TestRenderOfflinePrompt_Specify(t *testing.T) {
+	specsDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(specsDir, "004-existing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _, err := renderOfflinePrompt(workflow.StageSpecify, specsDir, "", "Add dark mode", nil, promptsize.Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got, "FEATURE_NUM: 005") {
+		t.Error("specify prompt should give the next feature number")
+	}
+	if !strings.Contains(got, "Add dark mode") {
+		t.Error("specify prompt should contain the feature description")
+	}
+}
@@ -0,0 +1,147 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/learnings"
+	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var retroCmd = &cobra.Command{
+	Use:   "retro [spec] [prompt]",
+	Short: "Record conventions learned from a spec in .autospec/memory/learnings.yaml",
+	Long: `Execute the /autospec.retro command to look back on an implemented spec.
+
+The retro command will:
+- Review the spec, its tasks and the changes made while implementing it
+- Append project-specific conventions and pitfalls it discovered to
+  .autospec/memory/learnings.yaml
+- Mark existing learnings the spec confirmed as reinforced
+
+The top learnings (learnings.max_in_prompt, default 10) are added to every
+plan and implement prompt. With learnings.retrospect in config, a retro runs
+automatically when a spec completes.
+
+Without a spec argument, the current spec is auto-detected. The optional
+prompt is passed to the agent, e.g. to point at what went wrong.
+
+Use --list to show the learnings in prompt order without running a session.`,
+	Example: `  # Retrospect on the current spec
+  autospec retro
+
+  # Retrospect on a specific spec, with a hint
+  autospec retro 003 "The migration tasks needed three retries"
+
+  # Show the learnings added to prompts
+  autospec retro --list`,
+	Args: cobra.MaximumNArgs(2),
+	RunE: runRetro,
+}
+
+func init() {
+	retroCmd.GroupID = GroupOptionalStages
+	retroCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts (overrides config when set)")
+	retroCmd.Flags().Bool("list", false, "List the learnings in prompt order without running a session")
+	rootCmd.AddCommand(retroCmd)
+}
+
+func runRetro(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	maxRetries, _ := cmd.Flags().GetInt("max-retries")
+	list, _ := cmd.Flags().GetBool("list")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	specArg := ""
+	if len(args) > 0 {
+		specArg = args[0]
+	}
+	if err := shared.ApplySpecConfig(cmd, cfg, specArg); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("max-retries") {
+		cfg.MaxRetries = maxRetries
+	}
+
+	if list {
+		f, err := learnings.Load(learnings.DefaultPath)
+		if err != nil {
+			return err
+		}
+		printLearnings(cmd.OutOrStdout(), f, cfg.Learnings.MaxInPrompt)
+		return nil
+	}
+
+	var metadata *spec.Metadata
+	if len(args) > 0 {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, args[0])
+		if err == nil {
+			metadata.Detection = spec.DetectionExplicit
+		}
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detect spec: %w", err)
+	}
+	PrintSpecInfo(metadata)
+
+	var prompt string
+	if len(args) == 2 {
+		prompt = args[1]
+	}
+
+	notifHandler := notify.NewHandler(cfg.Notifications)
+	historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
+	specName := metadata.DirName()
+
+	return lifecycle.RunWithHistory(notifHandler, historyLogger, "retro", specName, func() error {
+		orch := workflow.NewWorkflowOrchestrator(cfg)
+		orch.SetNotificationHandler(notifHandler)
+		shared.ApplyOutputStyle(cmd, orch)
+
+		added, err := orch.ExecuteRetro(specName, prompt)
+		if err != nil {
+			return fmt.Errorf("retro stage failed: %w", err)
+		}
+		green := color.New(color.FgGreen).SprintFunc()
+		fmt.Fprintf(cmd.OutOrStdout(), "\n%s Retro complete: %d learning(s) added to %s\n", green("✓"), added, learnings.DefaultPath)
+		return nil
+	})
+}
+
+// printLearnings lists learnings in prompt order, marking those beyond
+// maxInPrompt as not sent.
+func printLearnings(out io.Writer, f *learnings.File, maxInPrompt int) {
+	if len(f.Learnings) == 0 {
+		fmt.Fprintln(out, "No learnings yet. Run 'autospec retro' after implementing a spec.")
+		return
+	}
+	dim := color.New(color.Faint).SprintFunc()
+	for i, l := range f.Top(-1) {
+		line := fmt.Sprintf("%s %s", l.ID, l.Learning)
+		if l.Reinforced > 0 {
+			line += fmt.Sprintf(" (reinforced %d×)", l.Reinforced)
+		}
+		if i >= maxInPrompt {
+			line = dim(line + " [not in prompts]")
+		}
+		fmt.Fprintln(out, line)
+	}
+}
@@ -0,0 +1,54 @@
+// Package cli_test tests the retro command registration, arguments and learnings listing.
+// Related: internal/cli/retro.go
+// Tags: cli, retro, learnings, memory, command
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/learnings"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetroCmdRegistration(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"retro"})
+	require.NoError(t, err)
+	assert.Equal(t, retroCmd, cmd)
+	assert.Equal(t, GroupOptionalStages, retroCmd.GroupID)
+	assert.NotNil(t, retroCmd.Flags().Lookup("max-retries"))
+	assert.NotNil(t, retroCmd.Flags().Lookup("list"))
+	assert.Error(t, retroCmd.Args(retroCmd, []string{"003", "a", "b"}))
+}
+
+func TestPrintLearnings(t *testing.T) {
+	tests := map[string]struct {
+		file        *learnings.File
+		maxInPrompt int
+		want        []string
+	}{
+		"empty": {
+			file: &learnings.File{},
+			want: []string{"No learnings yet"},
+		},
+		"prompt order and cut-off": {
+			file: &learnings.File{Learnings: []learnings.Learning{
+				{ID: "L-001", Learning: "Wrap errors", Added: "2025-01-01"},
+				{ID: "L-002", Learning: "Run make gen", Reinforced: 2},
+			}},
+			maxInPrompt: 1,
+			want:        []string{"L-002 Run make gen (reinforced 2×)\nL-001 Wrap errors [not in prompts]"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			printLearnings(&out, tt.file, tt.maxInPrompt)
+			for _, want := range tt.want {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
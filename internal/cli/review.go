@@ -0,0 +1,280 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/review"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review [spec]",
+	Short: "Review spec.yaml section by section and record decisions in review.yaml",
+	Long: `Walk through a spec's spec.yaml one top-level section at a time. For each
+section, accept it, reject it with a reason, comment on it, or skip it.
+Decisions are saved to review.yaml in the spec directory, replacing your
+previous review.
+
+A review approves the spec when every section is accepted or commented on and
+none is rejected. Approvals only count for the spec.yaml content they were
+made on: editing a section afterwards requires a new review.
+
+With review.enabled in config, 'autospec plan' is blocked until every reviewer
+in review.required_reviewers has approved (or anyone has, when the list is
+empty).
+
+Without a spec argument, the current spec is auto-detected.`,
+	Example: `  # Review the current spec as your git user.name
+  autospec review
+
+  # Review a specific spec under another name
+  autospec review 003 --reviewer alice
+
+  # Show who has approved the current spec.yaml
+  autospec review --status`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReview,
+}
+
+func init() {
+	reviewCmd.GroupID = GroupOptionalStages
+	reviewCmd.Flags().String("reviewer", "", "Reviewer name (default: git user.name or $USER)")
+	reviewCmd.Flags().Bool("status", false, "Show the recorded reviews and the review gate without reviewing")
+	rootCmd.AddCommand(reviewCmd)
+}
+
+func runReview(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	reviewer, _ := cmd.Flags().GetString("reviewer")
+	statusOnly, _ := cmd.Flags().GetBool("status")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	var metadata *spec.Metadata
+	if len(args) == 1 {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, args[0])
+		if err == nil {
+			metadata.Detection = spec.DetectionExplicit
+		}
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detect spec: %w", err)
+	}
+	PrintSpecInfo(metadata)
+
+	out := cmd.OutOrStdout()
+	specDir := metadata.Directory
+	if statusOnly {
+		return printReviewStatus(out, specDir, cfg.Review)
+	}
+
+	sections, err := review.Sections(filepath.Join(specDir, "spec.yaml"))
+	if err != nil {
+		return err
+	}
+	hash, err := review.SpecHash(specDir)
+	if err != nil {
+		return err
+	}
+	f, err := review.Load(specDir)
+	if err != nil {
+		return err
+	}
+
+	r := review.Review{
+		Reviewer:   resolveChangeAuthor(reviewer),
+		SpecHash:   hash,
+		ReviewedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	fmt.Fprintf(out, "Reviewing %s as %s (%d sections)\n",
+		filepath.Join(specDir, "spec.yaml"), r.Reviewer, len(sections))
+	r.Sections, err = collectDecisions(cmd.InOrStdin(), out, sections)
+	if err != nil {
+		return err
+	}
+
+	f.Record(r)
+	if err := review.Save(specDir, f); err != nil {
+		return err
+	}
+	printReviewSummary(out, r, sections)
+	if cfg.Review.Enabled {
+		return printReviewStatus(out, specDir, cfg.Review)
+	}
+	return nil
+}
+
+// collectDecisions shows each section and reads the reviewer's decision from
+// in. Skipped sections get no decision. Quitting, or the end of input, stops
+// the walk and returns the decisions made so far.
+func collectDecisions(in io.Reader, out io.Writer, sections []review.Section) ([]review.Decision, error) {
+	reader := bufio.NewReader(in)
+	var decisions []review.Decision
+	for i, s := range sections {
+		fmt.Fprintf(out, "\n── %s (%d/%d) ──\n", s.Name, i+1, len(sections))
+		for _, line := range strings.Split(strings.TrimRight(s.Body, "\n"), "\n") {
+			fmt.Fprintf(out, "  %s\n", line)
+		}
+
+		d, quit, err := promptDecision(reader, out, s.Name)
+		if err != nil {
+			return nil, err
+		}
+		if quit {
+			fmt.Fprintf(out, "Stopped after %d of %d sections\n", i, len(sections))
+			return decisions, nil
+		}
+		if d != nil {
+			decisions = append(decisions, *d)
+		}
+	}
+	return decisions, nil
+}
+
+// promptDecision asks for one section's decision until the answer is valid.
+// Returns nil for a skipped section, and quit at "q" or the end of input.
+func promptDecision(reader *bufio.Reader, out io.Writer, section string) (d *review.Decision, quit bool, err error) {
+	for {
+		fmt.Fprint(out, "[a]ccept, [r]eject, [c]omment, [s]kip, [q]uit: ")
+		answer, eof, err := readAnswer(reader)
+		if err != nil {
+			return nil, false, err
+		}
+		switch strings.ToLower(answer) {
+		case "a", "accept":
+			return &review.Decision{Section: section, Decision: review.DecisionAccept}, false, nil
+		case "r", "reject", "c", "comment":
+			decision, label := review.DecisionReject, "Reason: "
+			if strings.HasPrefix(strings.ToLower(answer), "c") {
+				decision, label = review.DecisionComment, "Comment: "
+			}
+			for {
+				fmt.Fprint(out, label)
+				text, textEOF, err := readAnswer(reader)
+				if err != nil {
+					return nil, false, err
+				}
+				if text != "" {
+					return &review.Decision{Section: section, Decision: decision, Comment: text}, false, nil
+				}
+				if textEOF {
+					fmt.Fprintln(out)
+					return nil, true, nil
+				}
+				fmt.Fprintf(out, "A %s is required\n", strings.ToLower(strings.TrimSuffix(label, ": ")))
+			}
+		case "s", "skip":
+			return nil, false, nil
+		case "q", "quit":
+			return nil, true, nil
+		case "":
+			if eof {
+				fmt.Fprintln(out)
+				return nil, true, nil
+			}
+		}
+		fmt.Fprintln(out, "Please answer a, r, c, s or q")
+	}
+}
+
+// readAnswer reads one trimmed line. eof reports that input has ended.
+func readAnswer(reader *bufio.Reader) (answer string, eof bool, err error) {
+	line, err := reader.ReadString('\n')
+	if errors.Is(err, io.EOF) {
+		return strings.TrimSpace(line), true, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("reading answer: %w", err)
+	}
+	return strings.TrimSpace(line), false, nil
+}
+
+// printReviewSummary counts the decisions of r and says whether it approves the spec.
+func printReviewSummary(out io.Writer, r review.Review, sections []review.Section) {
+	counts := map[string]int{}
+	for _, d := range r.Sections {
+		counts[d.Decision]++
+	}
+	skipped := len(sections) - len(r.Sections)
+	fmt.Fprintf(out, "\nSaved review by %s: %d accepted, %d rejected, %d commented, %d not reviewed\n",
+		r.Reviewer, counts[review.DecisionAccept], counts[review.DecisionReject], counts[review.DecisionComment], skipped)
+
+	if r.Approves(r.SpecHash, review.SectionNames(sections)) {
+		fmt.Fprintf(out, "%s Approved\n", color.GreenString("✓"))
+		return
+	}
+	fmt.Fprintf(out, "%s Not approved (every section must be accepted or commented on, none rejected)\n", color.YellowString("!"))
+}
+
+// printReviewStatus lists the recorded reviews and, when gating is enabled,
+// whether plan is blocked.
+func printReviewStatus(out io.Writer, specDir string, cfg review.Config) error {
+	f, err := review.Load(specDir)
+	if err != nil {
+		return err
+	}
+	hash, err := review.SpecHash(specDir)
+	if err != nil {
+		return err
+	}
+	sections, err := review.Sections(filepath.Join(specDir, "spec.yaml"))
+	if err != nil {
+		return err
+	}
+	names := review.SectionNames(sections)
+
+	if len(f.Reviews) == 0 {
+		fmt.Fprintln(out, "No reviews recorded")
+	}
+	for _, r := range f.Reviews {
+		state := "not approved"
+		switch {
+		case r.SpecHash != hash:
+			state = "outdated (spec.yaml changed since)"
+		case r.Approves(hash, names):
+			state = "approved"
+		}
+		fmt.Fprintf(out, "  %s  %s  %s\n", r.Reviewer, r.ReviewedAt.Local().Format("2006-01-02 15:04"), state)
+		for _, d := range r.Sections {
+			if d.Comment != "" {
+				fmt.Fprintf(out, "      %s %s: %s\n", d.Decision, d.Section, d.Comment)
+			}
+		}
+	}
+
+	if !cfg.Enabled {
+		return nil
+	}
+	err = review.CheckGate(specDir, cfg)
+	var gateErr *review.GateError
+	switch {
+	case err == nil:
+		fmt.Fprintf(out, "%s Review gate passed: plan may run\n", color.GreenString("✓"))
+	case errors.As(err, &gateErr) && len(gateErr.Missing) > 0:
+		fmt.Fprintf(out, "%s Plan blocked: waiting on %s\n", color.YellowString("!"), strings.Join(gateErr.Missing, ", "))
+	case errors.As(err, &gateErr):
+		fmt.Fprintf(out, "%s Plan blocked: no approved review yet\n", color.YellowString("!"))
+	default:
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package cli_test tests the review command registration and section walk.
+// Related: internal/cli/review.go
+// Tags: cli, review, spec, gate, command
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/review"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReviewCmdRegistration(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"review"})
+	require.NoError(t, err)
+	assert.Equal(t, reviewCmd, cmd)
+	assert.Equal(t, GroupOptionalStages, reviewCmd.GroupID)
+	assert.NotNil(t, reviewCmd.Flags().Lookup("reviewer"))
+	assert.NotNil(t, reviewCmd.Flags().Lookup("status"))
+	assert.Error(t, reviewCmd.Args(reviewCmd, []string{"003", "004"}))
+}
+
+func TestCollectDecisions(t *testing.T) {
+	sections := []review.Section{
+		{Name: "feature", Body: "branch: 001-login\n"},
+		{Name: "user_stories", Body: "- id: US-001\n"},
+		{Name: "requirements", Body: "functional: []\n"},
+	}
+
+	tests := map[string]struct {
+		input string
+		want  []review.Decision
+		out   []string
+	}{
+		"accept, comment and reject": {
+			input: "a\nc\nsplit into two stories\nr\nmissing rate limits\n",
+			want: []review.Decision{
+				{Section: "feature", Decision: review.DecisionAccept},
+				{Section: "user_stories", Decision: review.DecisionComment, Comment: "split into two stories"},
+				{Section: "requirements", Decision: review.DecisionReject, Comment: "missing rate limits"},
+			},
+			out: []string{"── feature (1/3) ──", "  branch: 001-login", "── requirements (3/3) ──"},
+		},
+		"skip leaves section undecided": {
+			input: "s\naccept\nA\n",
+			want: []review.Decision{
+				{Section: "user_stories", Decision: review.DecisionAccept},
+				{Section: "requirements", Decision: review.DecisionAccept},
+			},
+		},
+		"invalid answer and empty reason are asked again": {
+			input: "x\nr\n\nunclear scope\na\na\n",
+			want: []review.Decision{
+				{Section: "feature", Decision: review.DecisionReject, Comment: "unclear scope"},
+				{Section: "user_stories", Decision: review.DecisionAccept},
+				{Section: "requirements", Decision: review.DecisionAccept},
+			},
+			out: []string{"Please answer a, r, c, s or q", "A reason is required"},
+		},
+		"quit keeps earlier decisions": {
+			input: "a\nq\n",
+			want:  []review.Decision{{Section: "feature", Decision: review.DecisionAccept}},
+			out:   []string{"Stopped after 1 of 3 sections"},
+		},
+		"end of input stops": {
+			input: "a",
+			want:  []review.Decision{{Section: "feature", Decision: review.DecisionAccept}},
+			out:   []string{"Stopped after 1 of 3 sections"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			got, err := collectDecisions(strings.NewReader(tt.input), &out, sections)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			for _, want := range tt.out {
+				assert.Contains(t, out.String(), want)
+			}
+		})
+	}
+}
+
+func TestPrintReviewSummary(t *testing.T) {
+	sections := []review.Section{{Name: "feature"}, {Name: "requirements"}}
+
+	var out bytes.Buffer
+	printReviewSummary(&out, review.Review{Reviewer: "alice", SpecHash: "h1", Sections: []review.Decision{
+		{Section: "feature", Decision: review.DecisionAccept},
+		{Section: "requirements", Decision: review.DecisionComment, Comment: "ok"},
+	}}, sections)
+	assert.Contains(t, out.String(), "1 accepted, 0 rejected, 1 commented, 0 not reviewed")
+	assert.Contains(t, out.String(), "Approved")
+
+	out.Reset()
+	printReviewSummary(&out, review.Review{Reviewer: "alice", SpecHash: "h1", Sections: []review.Decision{
+		{Section: "feature", Decision: review.DecisionAccept},
+	}}, sections)
+	assert.Contains(t, out.String(), "1 not reviewed")
+	assert.Contains(t, out.String(), "Not approved")
+}
@@ -9,11 +9,14 @@
 package cli
 
 import (
+	"os"
+
 	"github.com/ariel-frischer/autospec/internal/cli/admin"
 	"github.com/ariel-frischer/autospec/internal/cli/config"
 	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/ariel-frischer/autospec/internal/cli/stages"
 	"github.com/ariel-frischer/autospec/internal/cli/util"
+	"github.com/ariel-frischer/autospec/internal/output"
 	"github.com/spf13/cobra"
 )
 
@@ -54,11 +57,55 @@ Source: https://github.com/ariel-frischer/autospec`,
   autospec plan
   autospec tasks
   autospec implement`,
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		if shared.WantsJSON(cmd) {
+			restoreStdout = shared.RedirectStdoutForJSON()
+		}
+		configureOutput(cmd)
+		if err := applyPackageSelection(cmd); err != nil {
+			return err
+		}
+		if err := applyProfileSelection(cmd); err != nil {
+			return err
+		}
+		cfg := loadRootConfig(cmd)
+		if err := configureLogging(cmd, cfg); err != nil {
+			return err
+		}
+		watchSignals(cmd, cfg)
+		return nil
+	},
+}
+
+// configureOutput sets the output level and color from the global --quiet,
+// --verbose, --debug and --no-color flags.
+func configureOutput(cmd *cobra.Command) {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	debug, _ := cmd.Flags().GetBool("debug")
+	noColor, _ := cmd.Flags().GetBool("no-color")
+	output.Configure(output.LevelFromFlags(quiet, verbose, debug), noColor)
 }
 
-// Execute runs the root command
+// restoreStdout undoes the stdout redirection of --json.
+var restoreStdout = func() {}
+
+// Execute runs the root command. Errors are printed here rather than by cobra
+// so they can carry remediation hints and honor --json. With --json, commands
+// that wrote no envelope of their own get one without a result.
 func Execute() error {
-	return rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
+	stopWatchingSignals()
+	restoreStdout()
+	recordUsage(cmd, err)
+	if err != nil {
+		printCommandError(cmd, err, os.Stdout, os.Stderr)
+		return err
+	}
+	if shared.WantsJSON(cmd) && !shared.JSONWritten(cmd) {
+		_ = shared.FprintJSON(os.Stdout, cmd, nil)
+	}
+	return nil
 }
 
 func init() {
@@ -80,10 +127,20 @@ func init() {
 	rootCmd.PersistentFlags().Bool("skip-preflight", false, "Skip pre-flight validation checks")
 	rootCmd.PersistentFlags().BoolP("debug", "d", false, "Enable debug logging")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
+	rootCmd.PersistentFlags().String("log-level", "", "Console log level: debug, info, warn, error (overrides logging.level)")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Print only warnings and errors (agent output is unaffected)")
+	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output (also honors NO_COLOR)")
 	rootCmd.PersistentFlags().String("output-style", "", "Output formatting style: default, compact, minimal, plain, raw")
+	rootCmd.PersistentFlags().Bool("json", false, "Print the result and errors as one JSON envelope on stdout; other output goes to stderr")
+	rootCmd.PersistentFlags().String("package", "", "Run against a workspace package from the 'packages' config (env: AUTOSPEC_PACKAGE)")
+	rootCmd.PersistentFlags().String("profile", "", "Workflow profile: fast, thorough, budget, or one from the 'profiles' config (env: AUTOSPEC_PROFILE)")
+	// Errors and usage are printed by Execute, after the error message
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
 
 	// Register commands from subpackages
 	stages.Register(rootCmd)
+	registerSpecRenumberAlias(rootCmd)
 	config.Register(rootCmd)
 	util.Register(rootCmd)
 	admin.Register(rootCmd)
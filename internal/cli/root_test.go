@@ -263,3 +263,27 @@ func TestRootCmd_SubcommandCategories(t *testing.T) {
 	assert.True(t, commandNames["commands"], "Should have commands command")
 	assert.True(t, commandNames["uninstall"], "Should have uninstall command")
 }
+
+func TestRunsAgents(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args []string
+		want bool
+	}{
+		"workflow":       {args: []string{"run"}, want: true},
+		"core stage":     {args: []string{"implement"}, want: true},
+		"optional stage": {args: []string{"clarify"}, want: true},
+		"config command": {args: []string{"config", "show"}, want: false},
+		"status":         {args: []string{"status"}, want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cmd, _, err := rootCmd.Find(tt.args)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, runsAgents(cmd))
+		})
+	}
+}
@@ -5,12 +5,15 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/ariel-frischer/autospec/internal/config"
 	clierrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/history"
 	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/logging"
 	"github.com/ariel-frischer/autospec/internal/notify"
 	"github.com/ariel-frischer/autospec/internal/spec"
 	"github.com/ariel-frischer/autospec/internal/validation"
@@ -36,8 +39,23 @@ Optional stage flags:
   -l, --checklist     Include checklist stage (note: -c is used for --config)
   -z, --analyze       Include analyze stage
 
+Stage filters (comma-separated stage names):
+  --only <stages>     Run exactly these stages instead of using the stage flags
+  --skip <stages>     Leave out stages selected by flags or --profile
+
+Workflow types (--type):
+  feature   New functionality (default)
+  bugfix    Spec describes the bug; clarify never runs
+  refactor  Spec without user stories
+  spike     Writes research.yaml only; runs specify alone
+
 Stages are always executed in canonical order:
-  constitution -> specify -> clarify -> plan -> tasks -> checklist -> analyze -> implement`,
+  constitution -> specify -> clarify -> plan -> tasks -> checklist -> analyze -> implement
+
+--profile applies a workflow profile (fast, thorough, budget, or one defined
+under 'profiles' in config). A profile sets retries, gates and the agent, and
+adds its optional stages: clarify to runs that include plan, checklist and
+analyze to runs that include implement. Flags still take precedence.`,
 	Example: `  # Run all core stages for a new feature
   autospec run -a "Add user authentication"
 
@@ -51,7 +69,25 @@ Stages are always executed in canonical order:
   autospec run -ti --dry-run
 
   # Skip confirmation prompts for CI/CD
-  autospec run -ti -y`,
+  autospec run -ti -y
+
+  # Everything except tasks and implement, without the stage flags
+  autospec run --only specify,plan "Add user authentication"
+
+  # Thorough profile without the clarify stage
+  autospec run -a --profile thorough --skip clarify "Add user authentication"
+
+  # Full pipeline with clarify, checklist, analyze and strict gates
+  autospec run -a --profile thorough "Add user authentication"
+
+  # Fix a bug without the clarify stage
+  autospec run -a --type bugfix "Login fails when the email has uppercase letters"
+
+  # Investigate before committing to a design
+  autospec run -a --type spike "Evaluate SQLite vs Postgres for the job queue"
+
+  # Analyze first and stop before implement on CRITICAL findings
+  autospec run -zi --fail-on critical`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true // Don't show help for execution errors
 		// Get core stage flags
@@ -74,13 +110,27 @@ Stages are always executed in canonical order:
 		skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
 		maxRetries, _ := cmd.Flags().GetInt("max-retries")
 		resume, _ := cmd.Flags().GetBool("resume")
-		debug, _ := cmd.Flags().GetBool("debug")
+		debug := logging.DebugEnabled() // --debug, --log-level or logging.level
 		dryRun, _ := cmd.Flags().GetBool("dry-run")
 
+		only, skip, err := parseStageFilters(cmd)
+		if err != nil {
+			return err
+		}
+		typeName, _ := cmd.Flags().GetString("type")
+		wfType, err := workflow.LookupWorkflowType(typeName)
+		if err != nil {
+			return fmt.Errorf("invalid --type: %w", err)
+		}
+
 		// Build StageConfig from flags
 		stageConfig := workflow.NewStageConfig()
-		if all {
-			stageConfig.SetAll() // SetAll only sets core stages (specify, plan, tasks, implement)
+		if only != nil {
+			for _, stage := range only {
+				stageConfig.SetStage(stage, true)
+			}
+		} else if all {
+			wfType.SelectAll(stageConfig) // Core stages of the workflow type (specify, plan, tasks, implement for feature)
 		} else {
 			// Core stages
 			stageConfig.Specify = specify
@@ -88,14 +138,23 @@ Stages are always executed in canonical order:
 			stageConfig.Tasks = tasks
 			stageConfig.Implement = implement
 		}
-		// Optional stages are always set from flags (can be combined with -a)
-		stageConfig.Constitution = constitution
-		stageConfig.Clarify = clarify
-		stageConfig.Checklist = checklist
-		stageConfig.Analyze = analyze
+		if only == nil {
+			// Optional stages are always set from flags (can be combined with -a)
+			stageConfig.Constitution = constitution
+			stageConfig.Clarify = clarify
+			stageConfig.Checklist = checklist
+			stageConfig.Analyze = analyze
+		}
+		for _, stage := range skip {
+			stageConfig.SetStage(stage, false)
+		}
+		restrictToWorkflowType(stageConfig, wfType)
 
 		// Validate at least one stage is selected
 		if !stageConfig.HasAnyStage() {
+			if len(skip) > 0 {
+				return fmt.Errorf("--skip removed every selected stage\n\nRun 'autospec run --help' for usage")
+			}
 			return fmt.Errorf("no stages selected. Use -s/-p/-t/-i flags or -a for all stages\n\nRun 'autospec run --help' for usage")
 		}
 
@@ -118,14 +177,34 @@ Stages are always executed in canonical order:
 			clierrors.PrintError(cliErr)
 			return cliErr
 		}
+		if !stageConfig.Specify {
+			if err := shared.ApplySpecConfig(cmd, cfg, specName); err != nil {
+				return err
+			}
+		}
 
-		// Override settings from flags
+		// Add the profile's optional stages (unless --only fixes the stage
+		// list), then let flags override its settings
+		if only == nil {
+			applyProfileStages(cmd, cfg, stageConfig)
+			for _, stage := range skip {
+				stageConfig.SetStage(stage, false)
+			}
+			restrictToWorkflowType(stageConfig, wfType)
+		}
 		if cmd.Flags().Changed("skip-preflight") {
 			cfg.SkipPreflight = skipPreflight
 		}
 		if cmd.Flags().Changed("max-retries") {
 			cfg.MaxRetries = maxRetries
 		}
+		if strict, _ := cmd.Flags().GetBool("strict"); strict {
+			cfg.ValidationMode = validation.ModeStrict
+			_ = validation.SetMode(validation.ModeStrict)
+		}
+		if err := applyFailOnFlag(cmd, cfg); err != nil {
+			return err
+		}
 
 		// Apply agent override from --agent flag (must happen before security notice)
 		if _, err := shared.ApplyAgentOverride(cmd, cfg); err != nil {
@@ -187,8 +266,15 @@ Stages are always executed in canonical order:
 		}
 
 		// Check artifact dependencies before execution - hard fail if missing
-		// These are artifacts that no earlier selected stage will produce
-		if !stageConfig.Specify {
+		// These are artifacts that no earlier selected stage will produce.
+		// A new spec starts empty, so with specify any such artifact is a gap
+		// in the selected stages (e.g. -a --skip plan)
+		if stageConfig.Specify {
+			if gaps := stageConfig.GetAllRequiredArtifacts(); len(gaps) > 0 {
+				fmt.Fprint(os.Stderr, workflow.GeneratePrerequisiteError(stageConfig, gaps, nil))
+				return NewExitError(ExitInvalidArguments)
+			}
+		} else {
 			preflightResult := workflow.CheckArtifactDependencies(stageConfig, specMetadata.Directory)
 			if len(preflightResult.MissingArtifacts) > 0 {
 				fmt.Fprint(os.Stderr, preflightResult.WarningMessage)
@@ -200,6 +286,10 @@ Stages are always executed in canonical order:
 		orchestrator := workflow.NewWorkflowOrchestrator(cfg)
 		orchestrator.Debug = debug
 		orchestrator.Executor.Debug = debug
+		orchestrator.ForceUnlock, _ = cmd.Flags().GetBool("force-unlock")
+		orchestrator.Executor.ForceRerun, _ = cmd.Flags().GetBool("force")
+		orchestrator.Executor.FailFast, _ = cmd.Flags().GetBool("fail-fast")
+		orchestrator.SetWorkflowType(wfType)
 
 		// Disable process replacement for multi-stage runs
 		// This allows interactive stages to return so subsequent stages can execute
@@ -216,7 +306,7 @@ Stages are always executed in canonical order:
 
 		// Handle dry run mode - preview without execution
 		if dryRun {
-			return printDryRunPreview(stageConfig, featureDescription, specMetadata)
+			return printDryRunPreview(stageConfig, wfType, featureDescription, specMetadata)
 		}
 
 		// Create history logger
@@ -224,17 +314,61 @@ Stages are always executed in canonical order:
 
 		// Execute stages in canonical order with context for cancellation support
 		// Pass 'all' flag as isFullWorkflow to control description propagation
-		return executeStages(cmd.Context(), orchestrator, stageConfig, featureDescription, specMetadata, resume, debug, cfg.ImplementMethod, all, historyLogger)
+		var report *runResult
+		if shared.WantsJSON(cmd) {
+			report = &runResult{Stages: []runStageResult{}}
+		}
+		err = executeStages(cmd.Context(), orchestrator, stageConfig, featureDescription, specMetadata, resume, debug, cfg.ImplementMethod, all, historyLogger, report)
+		if report != nil {
+			return writeRunJSON(cmd, report, err)
+		}
+		return err
 	},
 }
 
+// stageSelectionFlags are the run flags that --only replaces.
+var stageSelectionFlags = []string{"specify", "plan", "tasks", "implement", "all", "constitution", "clarify", "checklist", "analyze"}
+
+// parseStageFilters reads --only and --skip. only is nil when --only is not
+// given; it cannot be combined with the stage selection flags.
+func parseStageFilters(cmd *cobra.Command) (only, skip []workflow.Stage, err error) {
+	if cmd.Flags().Changed("only") {
+		for _, name := range stageSelectionFlags {
+			if cmd.Flags().Changed(name) {
+				return nil, nil, fmt.Errorf("--only cannot be combined with --%s; list every stage in --only instead", name)
+			}
+		}
+		names, _ := cmd.Flags().GetStringSlice("only")
+		if only, err = workflow.ParseStageList(names); err != nil {
+			return nil, nil, fmt.Errorf("invalid --only: %w", err)
+		}
+		if len(only) == 0 {
+			return nil, nil, fmt.Errorf("--only requires at least one stage")
+		}
+	}
+	names, _ := cmd.Flags().GetStringSlice("skip")
+	if skip, err = workflow.ParseStageList(names); err != nil {
+		return nil, nil, fmt.Errorf("invalid --skip: %w", err)
+	}
+	return only, skip, nil
+}
+
+// restrictToWorkflowType drops the stages the workflow type never runs and
+// notes each one, so a flag or profile that selected it is not silently ignored.
+func restrictToWorkflowType(stageConfig *workflow.StageConfig, wfType workflow.WorkflowType) {
+	for _, stage := range wfType.Restrict(stageConfig) {
+		fmt.Fprintf(os.Stderr, "Note: skipping %s (not part of the %s workflow)\n", stage, wfType.Name)
+	}
+}
+
 // printDryRunPreview shows what would be executed without actually running
-func printDryRunPreview(stageConfig *workflow.StageConfig, featureDescription string, specMetadata *spec.Metadata) error {
+func printDryRunPreview(stageConfig *workflow.StageConfig, wfType workflow.WorkflowType, featureDescription string, specMetadata *spec.Metadata) error {
 	stages := stageConfig.GetCanonicalOrder()
 
 	fmt.Println("Dry Run Preview")
 	fmt.Println("===============")
 	fmt.Println()
+	fmt.Printf("Workflow type: %s\n", wfType.Name)
 	fmt.Printf("Stages to execute: %d\n", len(stages))
 	fmt.Println()
 
@@ -245,7 +379,7 @@ func printDryRunPreview(stageConfig *workflow.StageConfig, featureDescription st
 	fmt.Println()
 
 	if specMetadata != nil {
-		fmt.Printf("Target spec: specs/%s-%s/\n", specMetadata.Number, specMetadata.Name)
+		fmt.Printf("Target spec: specs/%s/\n", specMetadata.DirName())
 	} else if featureDescription != "" {
 		fmt.Printf("Feature description: %s\n", featureDescription)
 	}
@@ -258,7 +392,7 @@ func printDryRunPreview(stageConfig *workflow.StageConfig, featureDescription st
 		case workflow.StageConstitution:
 			fmt.Println("  - .autospec/constitution.yaml")
 		case workflow.StageSpecify:
-			fmt.Println("  - specs/<new-spec>/spec.yaml")
+			fmt.Printf("  - specs/<new-spec>/%s\n", wfType.Artifact)
 		case workflow.StageClarify:
 			fmt.Println("  - specs/*/spec.yaml (updated)")
 		case workflow.StagePlan:
@@ -296,18 +430,66 @@ type stageExecutionContext struct {
 	// hadAutomatedStage tracks whether any automated (non-interactive) stage has run.
 	// Used to decide whether to send notification before interactive stages.
 	hadAutomatedStage bool
+	// report collects the stage results for --json (nil without it)
+	report *runResult
+}
+
+// runResult is the --json result of run.
+type runResult struct {
+	Spec    string           `json:"spec"` // "" when specify failed
+	SpecDir string           `json:"spec_dir"`
+	Stages  []runStageResult `json:"stages"`
+}
+
+// runStageResult is one executed stage in the --json result of run.
+type runStageResult struct {
+	Stage           string  `json:"stage"`
+	Status          string  `json:"status"` // "completed" or "failed"
+	DurationSeconds float64 `json:"duration_seconds"`
+	Error           string  `json:"error,omitempty"`
+}
+
+// record adds the outcome of stage to the --json report.
+func (ctx *stageExecutionContext) record(stage workflow.Stage, started time.Time, err error) {
+	if ctx.report == nil {
+		return
+	}
+	result := runStageResult{
+		Stage:           string(stage),
+		Status:          "completed",
+		DurationSeconds: time.Since(started).Round(time.Millisecond).Seconds(),
+	}
+	if err != nil {
+		result.Status, result.Error = "failed", err.Error()
+	}
+	ctx.report.Stages = append(ctx.report.Stages, result)
+	ctx.report.Spec, ctx.report.SpecDir = ctx.specName, ctx.specDir
+}
+
+// writeRunJSON writes the --json envelope of run, with runErr in its errors,
+// and returns runErr so the exit code reflects it.
+func writeRunJSON(cmd *cobra.Command, report *runResult, runErr error) error {
+	var errs []*clierrors.CLIError
+	if runErr != nil {
+		errs = append(errs, clierrors.Classify(runErr))
+	}
+	if err := shared.WriteJSON(cmd, report, errs...); err != nil {
+		return err
+	}
+	return runErr
 }
 
 // executeStages executes the selected stages in order
 // isFullWorkflow indicates whether -a flag was used (all core stages), which affects
 // how featureDescription is propagated: only to specify when true, to all stages when false.
-func executeStages(cmdCtx context.Context, orchestrator *workflow.WorkflowOrchestrator, stageConfig *workflow.StageConfig, featureDescription string, specMetadata *spec.Metadata, resume, debug bool, implementMethod string, isFullWorkflow bool, historyLogger *history.Writer) error {
+func executeStages(cmdCtx context.Context, orchestrator *workflow.WorkflowOrchestrator, stageConfig *workflow.StageConfig, featureDescription string, specMetadata *spec.Metadata, resume, debug bool, implementMethod string, isFullWorkflow bool, historyLogger *history.Writer, report *runResult) error {
 	stages := stageConfig.GetCanonicalOrder()
 	orchestrator.Executor.TotalStages = len(stages)
+	orchestrator.Executor.Status.SetTotalStages(len(stages))
 
 	// Create notification handler from config
 	notifHandler := notify.NewHandler(orchestrator.Config.Notifications)
-	orchestrator.Executor.NotificationHandler = notifHandler
+	orchestrator.SetNotificationHandler(notifHandler)
 
 	ctx := &stageExecutionContext{
 		orchestrator:        orchestrator,
@@ -316,12 +498,16 @@ func executeStages(cmdCtx context.Context, orchestrator *workflow.WorkflowOrches
 		isFullWorkflow:      isFullWorkflow,
 		resume:              resume,
 		implementMethod:     implementMethod,
+		report:              report,
 	}
 
 	if specMetadata != nil {
-		ctx.specName = fmt.Sprintf("%s-%s", specMetadata.Number, specMetadata.Name)
+		ctx.specName = specMetadata.DirName()
 		ctx.specDir = specMetadata.Directory
 	}
+	if report != nil {
+		report.Spec, report.SpecDir = ctx.specName, ctx.specDir
+	}
 
 	// Wrap stage execution with lifecycle for timing, notification, and history
 	// Use RunWithHistoryContext to support context cancellation (e.g., Ctrl+C)
@@ -334,7 +520,10 @@ func executeStages(cmdCtx context.Context, orchestrator *workflow.WorkflowOrches
 			}
 
 			fmt.Printf("[Stage %d/%d] %s...\n", i+1, len(stages), stage)
-			if err := ctx.executeStage(stage); err != nil {
+			started := time.Now()
+			err := ctx.executeStage(stage)
+			ctx.record(stage, started, err)
+			if err != nil {
 				return fmt.Errorf("executing stage %s: %w", stage, err)
 			}
 
@@ -522,6 +711,9 @@ func init() {
 	runCmd.Flags().BoolP("clarify", "r", false, "Include clarify stage")
 	runCmd.Flags().BoolP("checklist", "l", false, "Include checklist stage")
 	runCmd.Flags().BoolP("analyze", "z", false, "Include analyze stage")
+	runCmd.Flags().StringSlice("only", nil, "Run exactly these stages, comma-separated (e.g. specify,plan); replaces the stage flags")
+	runCmd.Flags().StringSlice("skip", nil, "Leave out these stages, comma-separated (e.g. clarify,analyze)")
+	runCmd.Flags().String("type", workflow.DefaultWorkflowType, "Workflow type: "+strings.Join(workflow.WorkflowTypeNames(), ", "))
 
 	// Spec selection
 	runCmd.Flags().String("spec", "", "Specify which spec to work with (overrides branch detection)")
@@ -531,8 +723,13 @@ func init() {
 
 	// Other flags (NOTE: max-retries is now long-only, -r is used for clarify)
 	runCmd.Flags().Int("max-retries", 0, "Override max retry attempts (overrides config when set)")
+	runCmd.Flags().Bool("strict", false, "Fail stage validation on unknown fields and missing optional sections (validation_mode: strict)")
 	runCmd.Flags().Bool("resume", false, "Resume implementation from where it left off")
+	runCmd.Flags().Bool("force", false, "Run plan and tasks even if their artifacts are up to date with their inputs")
+	runCmd.Flags().Bool("force-unlock", false, "Remove an existing lock on the spec left by another autospec process")
+	runCmd.Flags().Bool("fail-fast", false, "Stop implement at the first task that fails or times out instead of blocking it and moving on")
 	runCmd.Flags().Bool("dry-run", false, "Preview what stages would run without executing")
+	runCmd.Flags().String("fail-on", "", "Stop before implement on analysis findings at or above this severity: critical, high, medium, low")
 
 	// Agent override flag
 	shared.AddAgentFlag(runCmd)
@@ -4,9 +4,12 @@
 package cli
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/spf13/cobra"
 )
 
 // TestRunImplementMethodConfig verifies that 'autospec run -pti' respects the
@@ -836,3 +839,95 @@ func TestRunCommandModeTransitions(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStageFilters(t *testing.T) {
+	tests := map[string]struct {
+		args     []string
+		wantOnly []workflow.Stage
+		wantSkip []workflow.Stage
+		wantErr  string
+	}{
+		"no filters": {},
+		"only": {
+			args:     []string{"--only", "specify,plan"},
+			wantOnly: []workflow.Stage{workflow.StageSpecify, workflow.StagePlan},
+		},
+		"skip": {
+			args:     []string{"-a", "--skip", "clarify", "--skip", "analyze"},
+			wantSkip: []workflow.Stage{workflow.StageClarify, workflow.StageAnalyze},
+		},
+		"only with stage flag": {
+			args:    []string{"-p", "--only", "plan"},
+			wantErr: "--only cannot be combined with --plan",
+		},
+		"unknown stage": {
+			args:    []string{"--skip", "review"},
+			wantErr: `invalid --skip: unknown stage "review"`,
+		},
+		"empty only": {
+			args:    []string{"--only", ""},
+			wantErr: "--only requires at least one stage",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "run"}
+			cmd.Flags().StringSlice("only", nil, "")
+			cmd.Flags().StringSlice("skip", nil, "")
+			for _, flag := range stageSelectionFlags {
+				cmd.Flags().BoolP(flag, runCmd.Flags().Lookup(flag).Shorthand, false, "")
+			}
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatalf("parsing flags: %v", err)
+			}
+
+			only, skip, err := parseStageFilters(cmd)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(only, tt.wantOnly) {
+				t.Errorf("only: expected %v, got %v", tt.wantOnly, only)
+			}
+			if len(skip) != len(tt.wantSkip) || (len(skip) > 0 && !reflect.DeepEqual(skip, tt.wantSkip)) {
+				t.Errorf("skip: expected %v, got %v", tt.wantSkip, skip)
+			}
+		})
+	}
+}
+
+func TestRunTypeFlag(t *testing.T) {
+	flag := runCmd.Flags().Lookup("type")
+	if flag == nil {
+		t.Fatal("run should have a --type flag")
+	}
+	if flag.DefValue != workflow.DefaultWorkflowType {
+		t.Errorf("--type default = %q, want %q", flag.DefValue, workflow.DefaultWorkflowType)
+	}
+	for _, name := range workflow.WorkflowTypeNames() {
+		if !strings.Contains(flag.Usage, name) {
+			t.Errorf("--type usage %q should list %s", flag.Usage, name)
+		}
+	}
+
+	bugfix, err := workflow.LookupWorkflowType("bugfix")
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+	sc := workflow.NewStageConfig()
+	bugfix.SelectAll(sc)
+	sc.Clarify = true
+	restrictToWorkflowType(sc, bugfix)
+	if sc.Clarify {
+		t.Error("bugfix runs should drop the clarify stage")
+	}
+	if !sc.Specify || !sc.Implement {
+		t.Error("bugfix runs should keep the core stages")
+	}
+}
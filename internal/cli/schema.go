@@ -0,0 +1,364 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/spf13/cobra"
+)
+
+// schemasDir is where write-vscode-settings writes the artifact JSON Schemas.
+var schemasDir = filepath.Join(".autospec", "schemas")
+
+// vscodeSettingsPath is the workspace settings file write-vscode-settings edits.
+var vscodeSettingsPath = filepath.Join(".vscode", "settings.json")
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Export artifact JSON Schemas for editor validation",
+	Long: `The artifact schemas (spec, plan, tasks, ...) are built into autospec. These
+commands export them as JSON Schema, so editors using yaml-language-server
+(the VS Code YAML extension, Neovim, Helix, ...) validate and complete
+artifact files while you edit them by hand.
+
+Available subcommands:
+  print                  Print one artifact type's JSON Schema
+  serve                  Serve the JSON Schemas over HTTP
+  write-vscode-settings  Write the schemas and map them to artifact files in .vscode/settings.json`,
+	Example: `  # Set up VS Code for the current project
+  autospec schema write-vscode-settings
+
+  # Serve schemas for editors configured with URLs
+  autospec schema serve --port 7390`,
+}
+
+var schemaPrintCmd = &cobra.Command{
+	Use:       "print <type>",
+	Short:     "Print an artifact type's JSON Schema",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: validation.ValidArtifactTypes(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		artType, err := validation.ParseArtifactType(args[0])
+		if err != nil {
+			return err
+		}
+		data, err := marshalArtifactJSONSchema(artType)
+		if err != nil {
+			return err
+		}
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	},
+}
+
+var schemaServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the artifact JSON Schemas over HTTP",
+	Long: `Serve each artifact type's JSON Schema at /<type>.json (for example
+http://127.0.0.1:7390/spec.json) until interrupted. Point editors at these
+URLs, or run 'autospec schema write-vscode-settings --url' to do it for VS
+Code. The server listens on 127.0.0.1 by default.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runSchemaServe,
+}
+
+var schemaVSCodeCmd = &cobra.Command{
+	Use:   "write-vscode-settings",
+	Short: "Map artifact files to their JSON Schemas in .vscode/settings.json",
+	Long: `Write each artifact type's JSON Schema to .autospec/schemas/<type>.json and
+add yaml.schemas entries to .vscode/settings.json that associate them with the
+project's artifact files (specs/*/spec.yaml, specs/*/checklists/*.yaml,
+.autospec/memory/constitution.yaml, ...). Other settings are kept. Rerun after
+upgrading autospec to refresh the schemas.
+
+With --url the entries point at a running 'autospec schema serve' instead and
+no schema files are written.
+
+settings.json must be plain JSON: files with comments are left unchanged, and
+the entries to add are printed instead.`,
+	Example: `  autospec schema write-vscode-settings
+  autospec schema write-vscode-settings --url http://127.0.0.1:7390`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runSchemaVSCode,
+}
+
+func init() {
+	schemaCmd.GroupID = GroupConfiguration
+	schemaServeCmd.Flags().Int("port", 7390, "Port to listen on")
+	schemaServeCmd.Flags().String("host", "127.0.0.1", "Interface to listen on")
+	schemaVSCodeCmd.Flags().String("url", "", "Base URL of 'autospec schema serve' to reference instead of writing schema files")
+	schemaCmd.AddCommand(schemaPrintCmd, schemaServeCmd, schemaVSCodeCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
+
+// marshalArtifactJSONSchema returns an artifact type's JSON Schema, indented.
+func marshalArtifactJSONSchema(artType validation.ArtifactType) ([]byte, error) {
+	doc, err := validation.ArtifactJSONSchema(artType)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s schema: %w", artType, err)
+	}
+	return append(data, '\n'), nil
+}
+
+// schemaHandler serves /<type>.json for every artifact type and an index of
+// them at /.
+func schemaHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		for _, t := range validation.ValidArtifactTypes() {
+			fmt.Fprintf(w, "/%s.json\n", t)
+		}
+	})
+	mux.HandleFunc("GET /{file}", func(w http.ResponseWriter, r *http.Request) {
+		name, ok := strings.CutSuffix(r.PathValue("file"), ".json")
+		artType, err := validation.ParseArtifactType(name)
+		if !ok || err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		data, err := marshalArtifactJSONSchema(artType)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/schema+json")
+		_, _ = w.Write(data)
+	})
+	return mux
+}
+
+func runSchemaServe(cmd *cobra.Command, _ []string) error {
+	host, _ := cmd.Flags().GetString("host")
+	port, _ := cmd.Flags().GetInt("port")
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("starting schema server: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "autospec schemas at http://%s/<type>.json (Ctrl+C to stop)\n", ln.Addr())
+	srv := &http.Server{Handler: schemaHandler(), ReadHeaderTimeout: 10 * time.Second}
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("stopping schema server: %w", err)
+		}
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("serving schemas: %w", err)
+		}
+	}
+	fmt.Fprintln(out, "autospec schema server stopped")
+	return nil
+}
+
+func runSchemaVSCode(cmd *cobra.Command, _ []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	baseURL, _ := cmd.Flags().GetString("url")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	associations, err := schemaAssociations(cfg.SpecsDir, baseURL)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if baseURL == "" {
+		fmt.Fprintf(out, "Wrote %d schemas to %s\n", len(associations), schemasDir)
+	}
+
+	existing, err := os.ReadFile(vscodeSettingsPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("reading %s: %w", vscodeSettingsPath, err)
+	}
+	merged, err := mergeVSCodeSettings(existing, associations)
+	if err != nil {
+		snippet, _ := json.MarshalIndent(map[string]any{"yaml.schemas": associations}, "", "  ")
+		fmt.Fprintf(out, "Add these entries to %s:\n%s\n", vscodeSettingsPath, snippet)
+		return fmt.Errorf("%s left unchanged: %w", vscodeSettingsPath, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(vscodeSettingsPath), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(vscodeSettingsPath), err)
+	}
+	if err := fsutil.WriteFile(vscodeSettingsPath, merged, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", vscodeSettingsPath, err)
+	}
+	fmt.Fprintf(out, "Updated yaml.schemas in %s (requires the YAML extension, redhat.vscode-yaml)\n", vscodeSettingsPath)
+	return nil
+}
+
+// schemaAssociations returns the yaml.schemas entries mapping each schema to
+// its artifact files. Without baseURL the schemas are written to schemasDir
+// and referenced by path; with it they are referenced at baseURL/<type>.json.
+func schemaAssociations(specsDir, baseURL string) (map[string][]string, error) {
+	if baseURL == "" {
+		if err := os.MkdirAll(schemasDir, 0o755); err != nil {
+			return nil, fmt.Errorf("creating %s: %w", schemasDir, err)
+		}
+	}
+	associations := make(map[string][]string)
+	for _, t := range validation.ValidArtifactTypes() {
+		artType := validation.ArtifactType(t)
+		var key string
+		if baseURL != "" {
+			key = strings.TrimSuffix(baseURL, "/") + "/" + t + ".json"
+		} else {
+			data, err := marshalArtifactJSONSchema(artType)
+			if err != nil {
+				return nil, err
+			}
+			file := filepath.Join(schemasDir, t+".json")
+			if err := fsutil.WriteFile(file, data, 0o644); err != nil {
+				return nil, fmt.Errorf("writing %s: %w", file, err)
+			}
+			key = "./" + filepath.ToSlash(file)
+		}
+		associations[key] = validation.ArtifactFileGlobs(artType, specsDir)
+	}
+	return associations, nil
+}
+
+// mergeVSCodeSettings returns the settings.json content data (empty for a new
+// file) with associations added to yaml.schemas. Earlier autospec entries are
+// replaced; other settings and yaml.schemas entries are kept, and top-level
+// settings keep their order.
+func mergeVSCodeSettings(data []byte, associations map[string][]string) ([]byte, error) {
+	settings, err := decodeOrderedObject(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing settings (comments are not supported): %w", err)
+	}
+
+	schemas := map[string]json.RawMessage{}
+	idx := -1
+	for i, s := range settings {
+		if s.key == "yaml.schemas" {
+			idx = i
+			if err := json.Unmarshal(s.value, &schemas); err != nil {
+				return nil, fmt.Errorf("parsing yaml.schemas: %w", err)
+			}
+		}
+	}
+	for key := range schemas {
+		if isAutospecSchemaKey(key) {
+			delete(schemas, key)
+		}
+	}
+	for key, globs := range associations {
+		value, _ := json.Marshal(globs)
+		schemas[key] = value
+	}
+	value, err := json.Marshal(schemas)
+	if err != nil {
+		return nil, err
+	}
+	if idx >= 0 {
+		settings[idx].value = value
+	} else {
+		settings = append(settings, jsonMember{key: "yaml.schemas", value: value})
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, s := range settings {
+		key, _ := json.Marshal(s.key)
+		fmt.Fprintf(&buf, "  %s: ", key)
+		if err := json.Indent(&buf, s.value, "  ", "  "); err != nil {
+			return nil, err
+		}
+		if i < len(settings)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+// isAutospecSchemaKey reports whether a yaml.schemas key refers to a schema
+// written or served by autospec.
+func isAutospecSchemaKey(key string) bool {
+	name := key[strings.LastIndex(key, "/")+1:]
+	artType, ok := strings.CutSuffix(name, ".json")
+	if !ok {
+		return false
+	}
+	if _, err := validation.ParseArtifactType(artType); err != nil {
+		return false
+	}
+	return strings.HasPrefix(key, "./"+filepath.ToSlash(schemasDir)+"/") || strings.HasPrefix(key, "http://") || strings.HasPrefix(key, "https://")
+}
+
+// jsonMember is one member of a JSON object.
+type jsonMember struct {
+	key   string
+	value json.RawMessage
+}
+
+// decodeOrderedObject decodes a JSON object's members in order. Empty input
+// is an empty object.
+func decodeOrderedObject(data []byte) ([]jsonMember, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	var members []jsonMember
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		members = append(members, jsonMember{key: key, value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected content after the settings object")
+	}
+	return members, nil
+}
@@ -0,0 +1,146 @@
+// Package cli_test tests the schema command's server and VS Code settings.
+// Related: internal/cli/schema.go
+// Tags: cli, schema, json-schema, vscode, serve
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaHandler(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path        string
+		wantStatus  int
+		wantType    string
+		wantContain string
+	}{
+		"index":        {path: "/", wantStatus: http.StatusOK, wantContain: "/spec.json"},
+		"spec schema":  {path: "/spec.json", wantStatus: http.StatusOK, wantType: "application/schema+json", wantContain: `"user_stories"`},
+		"unknown type": {path: "/bogus.json", wantStatus: http.StatusNotFound},
+		"no extension": {path: "/spec", wantStatus: http.StatusNotFound},
+	}
+
+	srv := httptest.NewServer(schemaHandler())
+	t.Cleanup(srv.Close)
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			resp, err := http.Get(srv.URL + tt.path)
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			var body bytes.Buffer
+			_, err = body.ReadFrom(resp.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantType != "" {
+				assert.Equal(t, tt.wantType, resp.Header.Get("Content-Type"))
+			}
+			assert.Contains(t, body.String(), tt.wantContain)
+		})
+	}
+}
+
+func TestMergeVSCodeSettings(t *testing.T) {
+	t.Parallel()
+
+	associations := map[string][]string{"./.autospec/schemas/spec.json": {"specs/*/spec.yaml"}}
+
+	tests := map[string]struct {
+		existing    string
+		wantSchemas map[string][]string
+		wantKeys    []string
+		wantErr     bool
+	}{
+		"new file": {
+			wantSchemas: associations,
+			wantKeys:    []string{"yaml.schemas"},
+		},
+		"keeps other settings in order": {
+			existing:    `{"editor.tabSize": 2, "files.trimTrailingWhitespace": true}`,
+			wantSchemas: associations,
+			wantKeys:    []string{"editor.tabSize", "files.trimTrailingWhitespace", "yaml.schemas"},
+		},
+		"replaces stale autospec entries and keeps others": {
+			existing: `{"yaml.schemas": {"./.autospec/schemas/plan.json": ["old/*.yaml"], "http://127.0.0.1:7390/spec.json": ["x"], "kubernetes": ["k8s/*.yaml"]}, "z": 1}`,
+			wantSchemas: map[string][]string{
+				"./.autospec/schemas/spec.json": {"specs/*/spec.yaml"},
+				"kubernetes":                    {"k8s/*.yaml"},
+			},
+			wantKeys: []string{"yaml.schemas", "z"},
+		},
+		"comments are rejected": {
+			existing: "{\n  // tabs\n  \"editor.tabSize\": 2\n}",
+			wantErr:  true,
+		},
+		"not an object": {
+			existing: `[]`,
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := mergeVSCodeSettings([]byte(tt.existing), associations)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			members, err := decodeOrderedObject(got)
+			require.NoError(t, err)
+			var keys []string
+			for _, m := range members {
+				keys = append(keys, m.key)
+			}
+			assert.Equal(t, tt.wantKeys, keys)
+
+			var settings struct {
+				Schemas map[string][]string `json:"yaml.schemas"`
+			}
+			require.NoError(t, json.Unmarshal(got, &settings))
+			assert.Equal(t, tt.wantSchemas, settings.Schemas)
+		})
+	}
+}
+
+func TestRunSchemaVSCode(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, ".config"))
+	t.Setenv("HOME", root)
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("config", ".autospec/config.yml", "")
+	cmd.Flags().String("url", "", "")
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+
+	require.NoError(t, runSchemaVSCode(cmd, nil))
+
+	_, err := os.Stat(filepath.Join(root, ".autospec", "schemas", "tasks.json"))
+	assert.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(root, ".vscode", "settings.json"))
+	require.NoError(t, err)
+	var settings struct {
+		Schemas map[string][]string `json:"yaml.schemas"`
+	}
+	require.NoError(t, json.Unmarshal(data, &settings))
+	assert.Equal(t, []string{"specs/*/spec.yaml", "specs/*/spec.yml"}, settings.Schemas["./.autospec/schemas/spec.json"])
+	assert.Len(t, settings.Schemas, 8)
+}
@@ -0,0 +1,179 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/search"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// searchSnippetWidth is how much of a matching field is shown per line.
+const searchSnippetWidth = 100
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query...>",
+	Short: "Search requirements, stories, plans and tasks across all specs",
+	Long: `Search the structured fields of spec.yaml, plan.yaml, tasks.yaml and
+research.yaml in every spec, to find prior art before writing a new spec.
+
+Each hit is the item a match belongs to - a requirement, user story,
+acceptance scenario, plan phase, task, risk, ... - with its ID, title and the
+matching fields. An item matches when every word of the query occurs in its
+fields, ignoring case. Fields of unlabeled entries (such as the spec's
+feature description) are reported under their top-level section.`,
+	Example: `  # Find everything mentioning OAuth
+  autospec search oauth
+
+  # Only requirements that mention both words
+  autospec search "token refresh" --kind requirement
+
+  # Only tasks.yaml, as JSON
+  autospec search retry --artifact tasks --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearch,
+}
+
+func init() {
+	searchCmd.GroupID = GroupGettingStarted
+	searchCmd.Flags().StringSliceP("artifact", "a", nil, "Artifacts to search: spec, plan, tasks, research (default all)")
+	searchCmd.Flags().StringSlice("kind", nil, "Only show items of these kinds, e.g. requirement, story, task")
+	searchCmd.Flags().Int("limit", 0, "Show at most this many hits (0 for all)")
+	rootCmd.AddCommand(searchCmd)
+}
+
+// searchResult is the --json result of search.
+type searchResult struct {
+	Query string       `json:"query"`
+	Hits  []search.Hit `json:"hits"`
+}
+
+func runSearch(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	artifacts, _ := cmd.Flags().GetStringSlice("artifact")
+	for _, a := range artifacts {
+		if !slices.Contains(search.Artifacts, a) {
+			return fmt.Errorf("unknown artifact %q (valid: %s)", a, strings.Join(search.Artifacts, ", "))
+		}
+	}
+	kinds, _ := cmd.Flags().GetStringSlice("kind")
+	limit, _ := cmd.Flags().GetInt("limit")
+	useJSON, _ := cmd.Flags().GetBool("json")
+
+	query := strings.Join(args, " ")
+	hits, err := search.Search(cfg.SpecsDir, query, search.Options{Artifacts: artifacts, Kinds: kinds})
+	if err != nil {
+		return err
+	}
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	out := cmd.OutOrStdout()
+	if useJSON {
+		if hits == nil {
+			hits = []search.Hit{}
+		}
+		return shared.WriteJSON(cmd, searchResult{Query: query, Hits: hits})
+	}
+	printSearchHits(out, cfg.SpecsDir, hits, search.Terms(query), output.Default().ColorEnabled())
+	return nil
+}
+
+// printSearchHits prints hits grouped by spec.
+func printSearchHits(w io.Writer, specsDir string, hits []search.Hit, terms []string, useColor bool) {
+	if len(hits) == 0 {
+		fmt.Fprintln(w, "No matches")
+		return
+	}
+	bold := color.New(color.Bold)
+	cyan := color.New(color.FgCyan)
+	highlight := color.New(color.FgYellow, color.Bold)
+	dim := color.New(color.Faint)
+	for _, c := range []*color.Color{bold, cyan, highlight, dim} {
+		if useColor {
+			c.EnableColor()
+		} else {
+			c.DisableColor()
+		}
+	}
+
+	current := ""
+	for _, h := range hits {
+		if h.Spec != current {
+			if current != "" {
+				fmt.Fprintln(w)
+			}
+			current = h.Spec
+			bold.Fprintln(w, filepath.Join(specsDir, h.Spec))
+		}
+		heading := "  " + cyan.Sprint(h.Label())
+		if h.Title != "" {
+			heading += " " + search.Snippet(h.Title, terms, searchSnippetWidth)
+		}
+		fmt.Fprintf(w, "%s %s\n", heading, dim.Sprintf("(%s:%d)", h.Artifact, h.Line))
+		for _, m := range h.Matches {
+			text := search.Snippet(m.Text, terms, searchSnippetWidth)
+			fmt.Fprintf(w, "    %s %s\n", dim.Sprint(m.Field+":"), highlightTerms(text, terms, highlight))
+		}
+	}
+	fmt.Fprintf(w, "\n%s in %s\n", countNoun(len(hits), "hit"), countNoun(countSpecs(hits), "spec"))
+}
+
+// highlightTerms wraps each case-insensitive occurrence of a term in text.
+func highlightTerms(text string, terms []string, c *color.Color) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return text // offsets would not line up
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		n := 0
+		for _, t := range terms {
+			if strings.HasPrefix(lower[i:], t) && len(t) > n {
+				n = len(t)
+			}
+		}
+		if n == 0 {
+			b.WriteByte(text[i])
+			i++
+			continue
+		}
+		b.WriteString(c.Sprint(text[i : i+n]))
+		i += n
+	}
+	return b.String()
+}
+
+// countNoun returns "1 hit", "2 hits", ...
+func countNoun(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+func countSpecs(hits []search.Hit) int {
+	specs := make(map[string]bool)
+	for _, h := range hits {
+		specs[h.Spec] = true
+	}
+	return len(specs)
+}
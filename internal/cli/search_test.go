@@ -0,0 +1,54 @@
+// Package cli_test tests the search command's output.
+// Related: internal/cli/search.go
+// Tags: cli, search
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/search"
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintSearchHits(t *testing.T) {
+	t.Parallel()
+
+	hits := []search.Hit{
+		{Spec: "004-login", Artifact: "spec.yaml", Kind: "requirement", ID: "FR-003", Title: "Refresh OAuth tokens", Line: 30,
+			Matches: []search.Match{{Field: "description", Text: "System MUST refresh\nOAuth tokens", Line: 31}}},
+		{Spec: "004-login", Artifact: "tasks.yaml", Kind: "task", ID: "T001", Title: "Add OAuth client", Line: 6,
+			Matches: []search.Match{{Field: "title", Text: "Add OAuth client", Line: 6}}},
+		{Spec: "007-sso", Artifact: "spec.yaml", Kind: "feature", Line: 3,
+			Matches: []search.Match{{Field: "description", Text: "SSO via oauth", Line: 4}}},
+	}
+
+	var buf bytes.Buffer
+	printSearchHits(&buf, "specs", hits, []string{"oauth"}, false)
+	assert.Equal(t, `specs/004-login
+  requirement FR-003 Refresh OAuth tokens (spec.yaml:30)
+    description: System MUST refresh OAuth tokens
+  task T001 Add OAuth client (tasks.yaml:6)
+    title: Add OAuth client
+
+specs/007-sso
+  feature (spec.yaml:3)
+    description: SSO via oauth
+
+3 hits in 2 specs
+`, buf.String())
+
+	buf.Reset()
+	printSearchHits(&buf, "specs", nil, []string{"oauth"}, false)
+	assert.Equal(t, "No matches\n", buf.String())
+}
+
+func TestHighlightTerms(t *testing.T) {
+	t.Parallel()
+
+	c := color.New(color.Bold)
+	c.EnableColor()
+	assert.Equal(t, "use \x1b[1mOAuth\x1b[22m and \x1b[1moauth2\x1b[22m",
+		highlightTerms("use OAuth and oauth2", []string{"oauth", "oauth2"}, c))
+}
@@ -2,7 +2,13 @@
 // This package has no dependencies on other CLI packages to avoid circular imports.
 package shared
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+)
 
 // Command group IDs for organizing help output
 const (
@@ -14,16 +20,34 @@ const (
 	GroupInternal       = "internal"
 )
 
-// Exit codes for CLI commands
+// Exit codes for CLI commands. They are part of the CLI contract: add new
+// codes freely, but never renumber one.
 const (
 	ExitSuccess           = 0
-	ExitValidationFailed  = 1
+	ExitValidationFailed  = 1 // Also any failure without a more specific code
 	ExitRetryLimitReached = 2
-	ExitInvalidArguments  = 3
-	ExitMissingDependency = 4
+	ExitInvalidArguments  = 3 // Invalid arguments or config, missing spec artifacts
+	ExitMissingDependency = 4 // Preflight checks failed, a required tool is missing or the state dir is over its limit
 	ExitTimeout           = 5
+	ExitBudgetExceeded    = 6
+	ExitCancelled         = interrupt.ExitCode // Stopped by SIGINT or SIGTERM (128 + SIGINT)
 )
 
+// exitCodes maps error codes to exit codes. Codes not listed exit with
+// ExitValidationFailed.
+var exitCodes = map[apperrors.Code]int{
+	apperrors.CodeRetryExhausted:      ExitRetryLimitReached,
+	apperrors.CodeInvalidArgument:     ExitInvalidArguments,
+	apperrors.CodeConfigInvalid:       ExitInvalidArguments,
+	apperrors.CodeArtifactMissing:     ExitInvalidArguments,
+	apperrors.CodePreflightFailed:     ExitMissingDependency,
+	apperrors.CodePrerequisiteMissing: ExitMissingDependency,
+	apperrors.CodeStorageLimit:        ExitMissingDependency,
+	apperrors.CodeAgentTimeout:        ExitTimeout,
+	apperrors.CodeBudgetExceeded:      ExitBudgetExceeded,
+	apperrors.CodeInterrupted:         ExitCancelled,
+}
+
 // exitError is a custom error type that carries an exit code.
 type exitError struct {
 	code int
@@ -38,13 +62,25 @@ func NewExitError(code int) error {
 	return &exitError{code: code}
 }
 
-// ExitCode returns the exit code from an error.
+// IsExitError reports whether err only carries an exit code, with no message
+// worth printing.
+func IsExitError(err error) bool {
+	_, ok := err.(*exitError)
+	return ok
+}
+
+// ExitCode returns the exit code for an error: the code of an exit error in
+// its chain, else the one its error code maps to (see exitCodes).
 func ExitCode(err error) int {
 	if err == nil {
 		return ExitSuccess
 	}
-	if e, ok := err.(*exitError); ok {
-		return e.code
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+	if code, ok := exitCodes[apperrors.Classify(err).Code]; ok {
+		return code
 	}
 	return ExitValidationFailed
 }
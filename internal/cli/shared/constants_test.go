@@ -7,10 +7,12 @@ package shared
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"testing"
 
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -123,6 +125,19 @@ func TestExitCode(t *testing.T) {
 		"exit error code 5":     {err: NewExitError(5), want: 5},
 		"generic error":         {err: errors.New("generic error"), want: ExitValidationFailed},
 		"wrapped generic error": {err: errors.New("wrapped: something failed"), want: ExitValidationFailed},
+		"interrupted":           {err: apperrors.ErrInterrupted.Errorf("stage plan: interrupted"), want: ExitCancelled},
+		"wrapped exit error":    {err: fmt.Errorf("implement: %w", NewExitError(ExitTimeout)), want: ExitTimeout},
+		"retry exhausted":       {err: apperrors.ErrRetryExhausted.Errorf("plan: retries exhausted"), want: ExitRetryLimitReached},
+		"schema violation":      {err: apperrors.ErrSchemaViolation.Errorf("plan.yaml: bad"), want: ExitValidationFailed},
+		"invalid argument":      {err: apperrors.NewArgumentError("bad flag"), want: ExitInvalidArguments},
+		"invalid config":        {err: apperrors.ErrConfigInvalid.Errorf("timeout: negative"), want: ExitInvalidArguments},
+		"artifact missing":      {err: apperrors.ErrArtifactMissing.Errorf("spec.yaml not found"), want: ExitInvalidArguments},
+		"preflight failed":      {err: apperrors.ErrPreflightFailed.Errorf("claude not found"), want: ExitMissingDependency},
+		"prerequisite missing":  {err: apperrors.NewPrerequisiteError("git not found"), want: ExitMissingDependency},
+		"storage limit":         {err: apperrors.ErrStorageLimit.Errorf("state directory uses 2100.0 MB"), want: ExitMissingDependency},
+		"agent timeout":         {err: fmt.Errorf("stage: %w", apperrors.ErrAgentTimeout.Errorf("timed out")), want: ExitTimeout},
+		"budget exceeded":       {err: apperrors.ErrBudgetExceeded.Errorf("run cost $5.00"), want: ExitBudgetExceeded},
+		"runtime error":         {err: apperrors.NewRuntimeError("boom"), want: ExitValidationFailed},
 	}
 
 	for name, tc := range tests {
@@ -133,16 +148,6 @@ func TestExitCode(t *testing.T) {
 	}
 }
 
-func TestExitCode_WithWrappedExitError(t *testing.T) {
-	t.Parallel()
-
-	// Note: ExitCode doesn't use errors.As, so wrapped exit errors
-	// are treated as generic errors
-	exitErr := NewExitError(ExitTimeout)
-	// Direct exit error should work
-	assert.Equal(t, ExitTimeout, ExitCode(exitErr))
-}
-
 // mockSpecMetadata implements SpecMetadata for testing
 type mockSpecMetadata struct {
 	info string
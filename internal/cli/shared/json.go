@@ -0,0 +1,103 @@
+package shared
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// JSONVersion is the version of the --json envelope. It is raised only when
+// a field is removed or changes meaning; fields may be added at any time.
+const JSONVersion = 1
+
+// JSONEnvelope is what a command run with --json writes to stdout, exactly
+// once. Result is the command's result (null for commands without one) and
+// Errors is empty when the command succeeded.
+type JSONEnvelope struct {
+	Version int                   `json:"version"`
+	Command string                `json:"command"`
+	Result  any                   `json:"result"`
+	Errors  []clierrors.JSONError `json:"errors"`
+}
+
+// jsonWrittenAnnotation marks a command that has written its envelope.
+const jsonWrittenAnnotation = "autospec.json-written"
+
+// jsonStdout is the real stdout while RedirectStdoutForJSON sends human
+// output to stderr.
+var jsonStdout io.Writer
+
+// WantsJSON reports whether cmd runs with --json and writes the envelope.
+// Commands that define a --json flag of their own (prereqs, setup-plan, ...)
+// keep the format their slash command templates parse and report false.
+func WantsJSON(cmd *cobra.Command) bool {
+	if cmd == nil || cmd.LocalNonPersistentFlags().Lookup("json") != nil {
+		return false
+	}
+	useJSON, _ := cmd.Flags().GetBool("json")
+	return useJSON
+}
+
+// JSONCommand returns the name of cmd in the envelope: its path without the
+// root command, e.g. "task list".
+func JSONCommand(cmd *cobra.Command) string {
+	if !cmd.HasParent() {
+		return cmd.Name()
+	}
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
+
+// WriteJSON writes the envelope of cmd with result and errs to stdout.
+func WriteJSON(cmd *cobra.Command, result any, errs ...*clierrors.CLIError) error {
+	w := jsonStdout
+	if w == nil {
+		w = cmd.OutOrStdout()
+	}
+	return FprintJSON(w, cmd, result, errs...)
+}
+
+// FprintJSON writes the envelope of cmd with result and errs to w and marks
+// cmd as having written it.
+func FprintJSON(w io.Writer, cmd *cobra.Command, result any, errs ...*clierrors.CLIError) error {
+	env := JSONEnvelope{
+		Version: JSONVersion,
+		Command: JSONCommand(cmd),
+		Result:  result,
+		Errors:  make([]clierrors.JSONError, 0, len(errs)),
+	}
+	for _, err := range errs {
+		env.Errors = append(env.Errors, err.JSON())
+	}
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[jsonWrittenAnnotation] = "true"
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(env)
+}
+
+// JSONWritten reports whether cmd has written its envelope.
+func JSONWritten(cmd *cobra.Command) bool {
+	return cmd != nil && cmd.Annotations[jsonWrittenAnnotation] == "true"
+}
+
+// RedirectStdoutForJSON sends everything printed to stdout to stderr, so
+// the envelope is the only thing on stdout, and returns the function that
+// undoes it.
+func RedirectStdoutForJSON() (restore func()) {
+	stdout, colorOutput := os.Stdout, color.Output
+	jsonStdout = stdout
+	os.Stdout = os.Stderr
+	color.Output = color.Error
+	return func() {
+		os.Stdout, color.Output = stdout, colorOutput
+		jsonStdout = nil
+	}
+}
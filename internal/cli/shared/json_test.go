@@ -0,0 +1,115 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newJSONTestTree returns autospec > task > list with the global --json flag,
+// and a prereqs command with a --json flag of its own.
+func newJSONTestTree() (root, list, prereqs *cobra.Command) {
+	root = &cobra.Command{Use: "autospec"}
+	root.PersistentFlags().Bool("json", false, "")
+	task := &cobra.Command{Use: "task"}
+	list = &cobra.Command{Use: "list", Run: func(*cobra.Command, []string) {}}
+	prereqs = &cobra.Command{Use: "prereqs", Run: func(*cobra.Command, []string) {}}
+	prereqs.Flags().Bool("json", false, "")
+	task.AddCommand(list)
+	root.AddCommand(task, prereqs)
+	return root, list, prereqs
+}
+
+func TestWantsJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		args []string
+		want bool
+	}{
+		"without --json":       {args: []string{"task", "list"}, want: false},
+		"global --json":        {args: []string{"task", "list", "--json"}, want: true},
+		"own --json flag":      {args: []string{"prereqs", "--json"}, want: false},
+		"own flag not set":     {args: []string{"prereqs"}, want: false},
+		"global flag on root ": {args: []string{"--json"}, want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			root, _, _ := newJSONTestTree()
+			root.Run = func(*cobra.Command, []string) {}
+			root.SetArgs(tt.args)
+			cmd, err := root.ExecuteC()
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, WantsJSON(cmd))
+		})
+	}
+	assert.False(t, WantsJSON(nil))
+}
+
+func TestJSONCommand(t *testing.T) {
+	t.Parallel()
+
+	root, list, prereqs := newJSONTestTree()
+	assert.Equal(t, "autospec", JSONCommand(root))
+	assert.Equal(t, "task list", JSONCommand(list))
+	assert.Equal(t, "prereqs", JSONCommand(prereqs))
+}
+
+func TestFprintJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		result   any
+		errs     []*clierrors.CLIError
+		wantJSON string
+	}{
+		"result": {
+			result:   map[string]int{"total": 3},
+			wantJSON: `{"version":1,"command":"task list","result":{"total":3},"errors":[]}`,
+		},
+		"no result": {
+			wantJSON: `{"version":1,"command":"task list","result":null,"errors":[]}`,
+		},
+		"error": {
+			errs:     []*clierrors.CLIError{clierrors.NewArgumentError("bad phase")},
+			wantJSON: `{"version":1,"command":"task list","result":null,"errors":[{"code":"INVALID_ARGUMENT","category":"argument","message":"bad phase"}]}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, list, _ := newJSONTestTree()
+			var out bytes.Buffer
+			require.NoError(t, FprintJSON(&out, list, tt.result, tt.errs...))
+
+			assert.JSONEq(t, tt.wantJSON, out.String())
+			assert.True(t, JSONWritten(list))
+
+			var env JSONEnvelope
+			require.NoError(t, json.Unmarshal(out.Bytes(), &env))
+			assert.Equal(t, JSONVersion, env.Version)
+		})
+	}
+}
+
+func TestJSONWritten(t *testing.T) {
+	t.Parallel()
+
+	_, list, _ := newJSONTestTree()
+	assert.False(t, JSONWritten(list))
+	assert.False(t, JSONWritten(nil))
+
+	var out bytes.Buffer
+	list.SetOut(&out)
+	require.NoError(t, WriteJSON(list, nil))
+	assert.True(t, JSONWritten(list))
+	assert.Contains(t, out.String(), `"command": "task list"`)
+}
@@ -0,0 +1,31 @@
+package shared
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ariel-frischer/autospec/internal/editor"
+	"github.com/spf13/cobra"
+)
+
+// OpenFlagName is the flag name for opening the generated artifact.
+const OpenFlagName = "open"
+
+// OpenFile opens a file for the user. Tests replace it.
+var OpenFile = editor.Open
+
+// AddOpenFlag adds the --open flag to a stage command.
+func AddOpenFlag(cmd *cobra.Command) {
+	cmd.Flags().Bool(OpenFlagName, false, "Open the generated artifact in $VISUAL/$EDITOR (or the default application) once it passes validation")
+}
+
+// OpenArtifact opens path when --open is set. The stage has already
+// succeeded, so a failure to open is a warning, not an error.
+func OpenArtifact(cmd *cobra.Command, path string) {
+	if open, _ := cmd.Flags().GetBool(OpenFlagName); !open {
+		return
+	}
+	if err := OpenFile(path); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not open %s: %v\n", path, err)
+	}
+}
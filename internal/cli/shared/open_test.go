@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Not parallel: the test replaces OpenFile.
+func TestOpenArtifact(t *testing.T) {
+	var opened []string
+	orig := OpenFile
+	OpenFile = func(path string) error {
+		opened = append(opened, path)
+		return nil
+	}
+	t.Cleanup(func() { OpenFile = orig })
+
+	tests := map[string]struct {
+		args []string
+		want []string
+	}{
+		"without --open": {want: nil},
+		"with --open":    {args: []string{"--open"}, want: []string{"specs/001-test/plan.yaml"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opened = nil
+			cmd := &cobra.Command{Use: "plan"}
+			AddOpenFlag(cmd)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+
+			OpenArtifact(cmd, "specs/001-test/plan.yaml")
+			assert.Equal(t, tt.want, opened)
+		})
+	}
+}
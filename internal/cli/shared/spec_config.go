@@ -0,0 +1,36 @@
+package shared
+
+import (
+	"fmt"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+// ApplySpecConfig merges the .autospec.yaml of the spec a command works on
+// over cfg. specArg is the spec named on the command line, or "" for the
+// current spec. Call it before applying flag overrides so that flags still
+// take precedence. When no spec can be found, cfg is left unchanged and the
+// command reports the missing spec itself.
+func ApplySpecConfig(cmd *cobra.Command, cfg *config.Configuration, specArg string) error {
+	var metadata *spec.Metadata
+	var err error
+	if specArg != "" {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, specArg)
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return nil
+	}
+
+	applied, err := cfg.ApplySpecConfig(metadata.Directory)
+	if err != nil {
+		return err
+	}
+	if applied {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Using spec config %s\n", cfg.AppliedSpecConfig)
+	}
+	return nil
+}
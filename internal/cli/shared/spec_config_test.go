@@ -0,0 +1,45 @@
+package shared
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySpecConfig(t *testing.T) {
+	t.Parallel()
+
+	specsDir := t.TempDir()
+	specDir := filepath.Join(specsDir, "012-db-migration")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, config.SpecConfigFile), []byte("max_retries: 5\n"), 0o644))
+
+	tests := map[string]struct {
+		specArg     string
+		wantRetries int
+		wantOutput  string
+	}{
+		"spec with config": {specArg: "012", wantRetries: 5, wantOutput: "Using spec config " + config.SpecConfigPath(specDir)},
+		"unknown spec":     {specArg: "099", wantRetries: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var stderr bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetErr(&stderr)
+			cfg := &config.Configuration{SpecsDir: specsDir, MaxRetries: 1, AgentPreset: "claude", StateDir: t.TempDir()}
+
+			require.NoError(t, ApplySpecConfig(cmd, cfg, tt.specArg))
+			assert.Equal(t, tt.wantRetries, cfg.MaxRetries)
+			assert.Contains(t, stderr.String(), tt.wantOutput)
+		})
+	}
+}
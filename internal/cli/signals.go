@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/spf13/cobra"
+)
+
+// stopSignals ends the Watch started by watchSignals, if any.
+var stopSignals = func() {}
+
+// stopWatchingSignals restores default signal handling once the command returns.
+func stopWatchingSignals() {
+	stopSignals()
+	stopSignals = func() {}
+}
+
+// watchSignals makes SIGINT and SIGTERM cancel commands that run agent
+// sessions, so the run stops gracefully and can be resumed. Other commands
+// keep the default behavior of exiting at once.
+func watchSignals(cmd *cobra.Command, cfg *config.Configuration) {
+	if !runsAgents(cmd) {
+		return
+	}
+	grace := interrupt.DefaultGracePeriod
+	if cfg != nil && cfg.CancelGracePeriod > 0 {
+		grace = cfg.CancelGracePeriod
+	}
+	ctx, stop := interrupt.Watch(cmd.Context(), grace)
+	cmd.SetContext(ctx)
+	stopSignals = stop
+}
+
+// runsAgents reports whether cmd, or the command it belongs to, is a workflow
+// or stage command.
+func runsAgents(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.GroupID {
+		case GroupWorkflows, GroupCoreStages, GroupOptionalStages:
+			return true
+		}
+	}
+	return false
+}
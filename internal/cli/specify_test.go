@@ -153,7 +153,7 @@ func TestSpecifyCmd_NotificationIntegration(t *testing.T) {
 
 	t.Run("sets notification handler on executor", func(t *testing.T) {
 		assert.True(t,
-			strings.Contains(source, "NotificationHandler = notifHandler") ||
+			strings.Contains(source, "SetNotificationHandler(notifHandler)") ||
 				strings.Contains(source, "Executor.NotificationHandler"),
 			"specify.go must set the notification handler on the executor")
 	})
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var specsCmd = &cobra.Command{
+	Use:   "specs",
+	Short: "Manage spec directories",
+	Long: `Commands for managing the spec directories in the specs directory.
+
+Available subcommands:
+  renumber  Change a spec's number or resolve duplicate spec numbers`,
+	Example: `  # List spec numbers used by more than one spec
+  autospec specs renumber
+
+  # Renumber duplicates left by merging parallel branches
+  autospec specs renumber --resolve-collisions`,
+}
+
+func init() {
+	specsCmd.GroupID = GroupInternal
+	rootCmd.AddCommand(specsCmd)
+}
@@ -0,0 +1,176 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	specsRenumberResolve bool
+	specsRenumberDryRun  bool
+)
+
+var specsRenumberCmd = &cobra.Command{
+	Use:   "renumber [spec number]",
+	Short: "Change a spec's number or resolve duplicate spec numbers",
+	Long: `Change the number of a spec directory, or find and fix spec numbers used by
+more than one spec.
+
+Branches that create specs at the same time can pick the same number, which
+shows up as two directories such as 004-dark-mode and 004-rate-limit after
+merging. Without arguments, the duplicates are listed and the command exits
+with status 1 if there are any. With --resolve-collisions, the oldest spec
+of each duplicate (by feature.created in spec.yaml) keeps its number and the
+others move to the next free numbers.
+
+Renumbering a spec renames its directory, rewrites references to the old
+directory name in the YAML and markdown files of the specs directory, and
+renames the local git branch of the same name if there is one.
+
+Only layouts with sequence numbers (numbered, categorized) can be renumbered.`,
+	Example: `  # List duplicate spec numbers
+  autospec specs renumber
+
+  # Preview and apply collision fixes
+  autospec specs renumber --resolve-collisions --dry-run
+  autospec specs renumber --resolve-collisions
+
+  # Move one spec to number 12
+  autospec specs renumber 004-rate-limit 12`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if specsRenumberResolve && len(args) > 0 {
+			return fmt.Errorf("--resolve-collisions does not take arguments")
+		}
+		if len(args) != 0 && len(args) != 2 {
+			return fmt.Errorf("expected a spec and a new number, got %d argument(s)", len(args))
+		}
+		return nil
+	},
+	SilenceUsage: true,
+	RunE:         runSpecRenumber,
+}
+
+// specRenumberCmd makes 'autospec spec renumber' work: "spec" is an alias
+// of specify, so the command is also registered, hidden, under specify.
+var specRenumberCmd = &cobra.Command{
+	Use:          specsRenumberCmd.Use,
+	Short:        specsRenumberCmd.Short,
+	Long:         specsRenumberCmd.Long,
+	Example:      specsRenumberCmd.Example,
+	Args:         specsRenumberCmd.Args,
+	Hidden:       true,
+	SilenceUsage: true,
+	RunE:         runSpecRenumber,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{specsRenumberCmd, specRenumberCmd} {
+		cmd.Flags().BoolVar(&specsRenumberResolve, "resolve-collisions", false, "Renumber all specs whose number is used by an older spec")
+		cmd.Flags().BoolVar(&specsRenumberDryRun, "dry-run", false, "Show what would be renumbered without changing anything")
+	}
+	specsCmd.AddCommand(specsRenumberCmd)
+}
+
+// registerSpecRenumberAlias adds 'renumber' under the specify command of root.
+func registerSpecRenumberAlias(root *cobra.Command) {
+	if specify, _, err := root.Find([]string{"specify"}); err == nil && specify != root {
+		specify.AddCommand(specRenumberCmd)
+	}
+}
+
+func runSpecRenumber(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	out := cmd.OutOrStdout()
+	switch {
+	case len(args) == 2:
+		return renumberOne(out, cfg.SpecsDir, args[0], args[1], specsRenumberDryRun)
+	case specsRenumberResolve:
+		return resolveCollisions(out, cfg.SpecsDir, specsRenumberDryRun)
+	default:
+		return reportCollisions(out, cfg.SpecsDir)
+	}
+}
+
+// renumberOne moves the spec named by specArg to number.
+func renumberOne(out io.Writer, specsDir, specArg, number string, dryRun bool) error {
+	metadata, err := spec.GetSpecMetadata(specsDir, specArg)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Fprintf(out, "Would renumber %s to %s\n", metadata.DirName(), number)
+		return nil
+	}
+	result, err := spec.Renumber(specsDir, metadata.DirName(), number)
+	if result != nil {
+		printRenumberResult(out, result)
+	}
+	return err
+}
+
+// reportCollisions lists duplicate spec numbers and fails if there are any.
+func reportCollisions(out io.Writer, specsDir string) error {
+	collisions := spec.FindCollisions(specsDir)
+	if len(collisions) == 0 {
+		fmt.Fprintln(out, "No duplicate spec numbers found.")
+		return nil
+	}
+	yellow := color.New(color.FgYellow).SprintFunc()
+	for _, c := range collisions {
+		fmt.Fprintf(out, "%s %s is used by %d specs:\n", yellow("!"), c.Number, len(c.Specs))
+		for _, rel := range c.Specs {
+			fmt.Fprintf(out, "  %s\n", rel)
+		}
+	}
+	fmt.Fprintln(out, "\nRun 'autospec specs renumber --resolve-collisions' to fix them.")
+	return NewExitError(ExitValidationFailed)
+}
+
+// resolveCollisions renumbers every spec that shares its number with an older spec.
+func resolveCollisions(out io.Writer, specsDir string, dryRun bool) error {
+	plan := spec.PlanCollisionFixes(specsDir)
+	if len(plan) == 0 {
+		fmt.Fprintln(out, "No duplicate spec numbers found.")
+		return nil
+	}
+	for _, r := range plan {
+		if dryRun {
+			fmt.Fprintf(out, "Would renumber %s -> %s\n", r.From, r.To)
+			continue
+		}
+		number, _, _ := spec.Locator().Parse(r.To)
+		result, err := spec.Renumber(specsDir, r.From, number)
+		if result != nil {
+			printRenumberResult(out, result)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printRenumberResult(out io.Writer, result *spec.RenumberResult) {
+	green := color.New(color.FgGreen).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+	fmt.Fprintf(out, "%s Renumbered %s -> %s\n", green("✓"), result.From, result.To)
+	for _, file := range result.Files {
+		fmt.Fprintf(out, "  %s\n", dim("updated "+file))
+	}
+	if result.Branch != "" {
+		fmt.Fprintf(out, "  %s\n", dim("renamed branch to "+result.Branch))
+	}
+}
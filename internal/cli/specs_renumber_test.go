@@ -0,0 +1,86 @@
+// Package cli_test tests the specs renumber command.
+// Related: internal/cli/specs_renumber.go
+// Tags: cli, specs, renumber, collision
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func mkSpecDirs(t *testing.T, names ...string) string {
+	t.Helper()
+	specsDir := t.TempDir()
+	for _, name := range names {
+		if err := os.MkdirAll(filepath.Join(specsDir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return specsDir
+}
+
+func TestReportCollisions(t *testing.T) {
+	var out bytes.Buffer
+	err := reportCollisions(&out, mkSpecDirs(t, "001-auth", "002-export", "002-search"))
+
+	if ExitCode(err) != ExitValidationFailed {
+		t.Fatalf("expected validation exit error, got %v", err)
+	}
+	for _, want := range []string{"002 is used by 2 specs", "002-export", "002-search", "--resolve-collisions"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	out.Reset()
+	if err := reportCollisions(&out, mkSpecDirs(t, "001-auth", "002-export")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "No duplicate spec numbers") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestResolveCollisions(t *testing.T) {
+	specsDir := mkSpecDirs(t, "001-auth", "002-export", "002-search")
+
+	var out bytes.Buffer
+	if err := resolveCollisions(&out, specsDir, true); err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !strings.Contains(out.String(), "Would renumber 002-search -> 003-search") {
+		t.Errorf("unexpected dry run output: %s", out.String())
+	}
+	if _, err := os.Stat(filepath.Join(specsDir, "002-search")); err != nil {
+		t.Fatalf("dry run must not rename: %v", err)
+	}
+
+	out.Reset()
+	if err := resolveCollisions(&out, specsDir, false); err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(specsDir, "003-search")); err != nil {
+		t.Errorf("expected 003-search after resolving: %v", err)
+	}
+	if !strings.Contains(out.String(), "Renumbered 002-search -> 003-search") {
+		t.Errorf("unexpected output: %s", out.String())
+	}
+}
+
+func TestSpecRenumberAlias(t *testing.T) {
+	t.Parallel()
+
+	cmd, _, err := rootCmd.Find([]string{"spec", "renumber", "--resolve-collisions"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmd != specRenumberCmd {
+		t.Fatalf("'spec renumber' resolved to %q, want the renumber command", cmd.CommandPath())
+	}
+	if cmd.Flags().Lookup("resolve-collisions") == nil {
+		t.Error("--resolve-collisions is not registered on 'spec renumber'")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAutoCommitFlagsRegistered(t *testing.T) {
@@ -77,3 +78,25 @@ func TestAutoCommitFlagsHelpText(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenFlagRegistered(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cmd *cobra.Command
+	}{
+		"specify": {cmd: specifyCmd},
+		"plan":    {cmd: planCmd},
+		"tasks":   {cmd: tasksCmd},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			openFlag := tt.cmd.Flags().Lookup(shared.OpenFlagName)
+			require.NotNil(t, openFlag, "open flag should be registered")
+			assert.Equal(t, "bool", openFlag.Value.Type())
+		})
+	}
+}
@@ -38,6 +38,7 @@ Execution Modes:
 - --from-phase N: Run phases N through end, each in a fresh session
 - --tasks: Run each task in a separate Claude session (finest granularity)
 - --from-task T003: Start task-level execution from a specific task ID
+- --task T003: Run only task T003 in a fresh Claude session
 - --single-session: Run all tasks in one Claude session (legacy mode)
 
 The default execution mode can be configured in config.yml:
@@ -58,7 +59,12 @@ The --tasks mode provides maximum context isolation:
 - Each task gets a completely fresh Claude session
 - Ideal for complex or long-running tasks
 - Finest-grained recovery points
-- Can combine with --from-task to resume from specific task`,
+- Can combine with --from-task to resume from specific task
+
+A task that runs past its time limit is marked Blocked and the run moves on.
+With --fail-fast, --tasks stops at that task, --phases stops after a phase
+that blocked a task, and --parallel cancels the running tasks at the first
+failure.`,
 	Example: `  # Auto-detect spec and implement
   autospec implement
 
@@ -68,6 +74,9 @@ The --tasks mode provides maximum context isolation:
   # Implement a specific spec by name
   autospec implement 003-my-feature
 
+  # Implement a spec by number (resolves to specs/003-*)
+  autospec implement 3
+
   # Provide prompt guidance for implementation
   autospec implement "Focus on error handling first"
 
@@ -86,8 +95,20 @@ The --tasks mode provides maximum context isolation:
   # Resume task execution from a specific task
   autospec implement --tasks --from-task T003
 
+  # Stop at the first task that fails or times out (exit code 5 on a timeout)
+  autospec implement --tasks --fail-fast
+
+  # Run only one task (its dependencies must be completed)
+  autospec implement --task T003
+
   # Run all tasks in a single Claude session (legacy mode)
-  autospec implement --single-session`,
+  autospec implement --single-session
+
+  # Take over a spec locked by a crashed or abandoned autospec process
+  autospec implement --force-unlock
+
+  # Refuse to start until every checklist item is pass or waived
+  autospec implement --require-checklists`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true // Don't show help for execution errors
 		// Parse args to distinguish between spec-name and prompt
@@ -98,6 +119,7 @@ The --tasks mode provides maximum context isolation:
 		skipPreflight, _ := cmd.Flags().GetBool("skip-preflight")
 		maxRetries, _ := cmd.Flags().GetInt("max-retries")
 		resume, _ := cmd.Flags().GetBool("resume")
+		forceUnlock, _ := cmd.Flags().GetBool("force-unlock")
 
 		// Get phase execution flags
 		runAllPhases, _ := cmd.Flags().GetBool("phases")
@@ -107,6 +129,7 @@ The --tasks mode provides maximum context isolation:
 		// Get task execution flags
 		taskMode, _ := cmd.Flags().GetBool("tasks")
 		fromTask, _ := cmd.Flags().GetString("from-task")
+		singleTask, _ := cmd.Flags().GetString("task")
 
 		// Get single-session flag
 		singleSession, _ := cmd.Flags().GetBool("single-session")
@@ -165,6 +188,9 @@ The --tasks mode provides maximum context isolation:
 			clierrors.PrintError(cliErr)
 			return cliErr
 		}
+		if err := shared.ApplySpecConfig(cmd, cfg, specName); err != nil {
+			return err
+		}
 
 		// Override skip-preflight from flag if set
 		if cmd.Flags().Changed("skip-preflight") {
@@ -176,6 +202,11 @@ The --tasks mode provides maximum context isolation:
 			cfg.MaxRetries = maxRetries
 		}
 
+		// Enable the checklist gate from flag (config: checklist_gate)
+		if requireChecklists, _ := cmd.Flags().GetBool("require-checklists"); requireChecklists {
+			cfg.ChecklistGate = true
+		}
+
 		// Apply agent override from --agent flag (must happen before security notice)
 		if _, err := shared.ApplyAgentOverride(cmd, cfg); err != nil {
 			return err
@@ -199,6 +230,7 @@ The --tasks mode provides maximum context isolation:
 			cmd.Flags().Changed("phase") ||
 			cmd.Flags().Changed("from-phase") ||
 			cmd.Flags().Changed("from-task") ||
+			cmd.Flags().Changed("task") ||
 			cmd.Flags().Changed("single-session") ||
 			(util.IsDevBuild() && cmd.Flags().Changed("parallel"))
 
@@ -251,7 +283,7 @@ The --tasks mode provides maximum context isolation:
 		// Create notification handler and history logger
 		notifHandler := notify.NewHandler(cfg.Notifications)
 		historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
-		historySpecName := fmt.Sprintf("%s-%s", metadata.Number, metadata.Name)
+		historySpecName := metadata.DirName()
 
 		// Show security notice (once per user, only for Claude)
 		shared.ShowSecurityNotice(cmd.OutOrStdout(), cfg, agent.Name())
@@ -261,7 +293,9 @@ The --tasks mode provides maximum context isolation:
 		return lifecycle.RunWithHistoryContext(cmd.Context(), notifHandler, historyLogger, "implement", historySpecName, func(_ context.Context) error {
 			// Create workflow orchestrator
 			orch := workflow.NewWorkflowOrchestrator(cfg)
-			orch.Executor.NotificationHandler = notifHandler
+			orch.SetNotificationHandler(notifHandler)
+			orch.ForceUnlock = forceUnlock
+			orch.Executor.FailFast, _ = cmd.Flags().GetBool("fail-fast")
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orch)
@@ -273,6 +307,7 @@ The --tasks mode provides maximum context isolation:
 				FromPhase:        fromPhase,
 				TaskMode:         taskMode,
 				FromTask:         fromTask,
+				SingleTask:       singleTask,
 				ParallelMode:     parallelMode,
 				MaxParallel:      maxParallel,
 				UseWorktrees:     useWorktrees,
@@ -290,11 +325,12 @@ The --tasks mode provides maximum context isolation:
 	},
 }
 
-// specNamePattern matches spec names like "003-feature-name" or "42-answer"
-var specNamePattern = regexp.MustCompile(`^\d+-[a-z0-9-]+$`)
+// specNamePattern matches spec names like "003-feature-name" or "42-answer",
+// and bare spec numbers like "4" (resolved to "004-*" against the specs dir)
+var specNamePattern = regexp.MustCompile(`^\d+(-[a-z0-9-]+)?$`)
 
 // ParseImplementArgs parses command arguments to distinguish between spec names and prompts.
-// Returns the spec name (if first arg matches NNN-name pattern) and any remaining prompt text.
+// Returns the spec name (if first arg matches NNN-name pattern or is a bare number) and any remaining prompt text.
 // Exported for testing.
 func ParseImplementArgs(args []string) (specName, prompt string) {
 	if len(args) == 0 {
@@ -398,6 +434,8 @@ func init() {
 
 	// Command-specific flags
 	implementCmd.Flags().Bool("resume", false, "Resume implementation from where it left off")
+	implementCmd.Flags().Bool("force-unlock", false, "Remove an existing lock on the spec left by another autospec process")
+	implementCmd.Flags().Bool("require-checklists", false, "Block until all checklist items are pass or waived (overrides config checklist_gate)")
 	implementCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts (overrides config when set)")
 
 	// Phase execution flags
@@ -408,6 +446,8 @@ func init() {
 	// Task execution flags
 	implementCmd.Flags().Bool("tasks", false, "Run each task in a separate Claude session (finest granularity)")
 	implementCmd.Flags().String("from-task", "", "Start execution from a specific task ID (e.g., --from-task T003)")
+	implementCmd.Flags().String("task", "", "Run only a specific task ID in a separate Claude session (e.g., --task T003)")
+	implementCmd.Flags().Bool("fail-fast", false, "Stop at the first task that fails or times out instead of blocking it and moving on")
 
 	// Single-session flag (legacy mode)
 	implementCmd.Flags().Bool("single-session", false, "Run all tasks in one Claude session (legacy mode)")
@@ -421,6 +461,14 @@ func init() {
 	implementCmd.MarkFlagsMutuallyExclusive("tasks", "phase")
 	implementCmd.MarkFlagsMutuallyExclusive("tasks", "from-phase")
 
+	// --task runs one task and cannot be combined with other execution modes
+	implementCmd.MarkFlagsMutuallyExclusive("task", "tasks")
+	implementCmd.MarkFlagsMutuallyExclusive("task", "from-task")
+	implementCmd.MarkFlagsMutuallyExclusive("task", "phases")
+	implementCmd.MarkFlagsMutuallyExclusive("task", "phase")
+	implementCmd.MarkFlagsMutuallyExclusive("task", "from-phase")
+	implementCmd.MarkFlagsMutuallyExclusive("task", "single-session")
+
 	// Mark single-session as mutually exclusive with all other execution modes
 	implementCmd.MarkFlagsMutuallyExclusive("single-session", "phases")
 	implementCmd.MarkFlagsMutuallyExclusive("single-session", "phase")
@@ -441,6 +489,7 @@ func init() {
 		implementCmd.MarkFlagsMutuallyExclusive("parallel", "phase")
 		implementCmd.MarkFlagsMutuallyExclusive("parallel", "from-phase")
 		implementCmd.MarkFlagsMutuallyExclusive("parallel", "single-session")
+		implementCmd.MarkFlagsMutuallyExclusive("parallel", "task")
 	}
 
 	// Agent override flag
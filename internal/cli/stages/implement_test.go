@@ -485,9 +485,9 @@ func TestSpecNamePattern(t *testing.T) {
 			input: "",
 			want:  false,
 		},
-		"invalid - only numbers": {
+		"valid - bare number": {
 			input: "12345",
-			want:  false,
+			want:  true,
 		},
 		"invalid - leading zeros only": {
 			input: "000-",
@@ -767,10 +767,10 @@ func TestParseImplementArgs_EdgeCases(t *testing.T) {
 			wantSpec:   "",
 			wantPrompt: "-",
 		},
-		"starts with number no hyphen": {
-			args:       []string{"123"},
-			wantSpec:   "",
-			wantPrompt: "123",
+		"bare number is a spec number": {
+			args:       []string{"123", "focus"},
+			wantSpec:   "123",
+			wantPrompt: "focus",
 		},
 		"number dash uppercase": {
 			args:       []string{"123-ABC"},
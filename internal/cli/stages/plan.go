@@ -3,6 +3,7 @@ package stages
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ariel-frischer/autospec/internal/cli/shared"
@@ -27,7 +28,11 @@ The plan command will:
 - Execute the planning workflow
 - Create plan.yaml with technical decisions and data models
 
-You can optionally provide a prompt to guide the planning process.`,
+You can optionally provide a prompt to guide the planning process.
+
+If plan.yaml was generated by a successful run and neither spec.yaml, the
+constitution nor the prompt has changed since, the agent is not called and
+the plan is reported as up to date. Use --force to plan again anyway.`,
 	Example: `  # Run planning with no additional guidance
   autospec plan
 
@@ -35,7 +40,10 @@ You can optionally provide a prompt to guide the planning process.`,
   autospec plan "Focus on security best practices"
 
   # Run planning with performance considerations
-  autospec plan "Optimize for low-latency API responses"`,
+  autospec plan "Optimize for low-latency API responses"
+
+  # Regenerate plan.yaml even though spec.yaml is unchanged
+  autospec plan --force`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true // Don't show help for execution errors
 		// Get optional prompt from args
@@ -56,6 +64,9 @@ You can optionally provide a prompt to guide the planning process.`,
 			clierrors.PrintError(cliErr)
 			return cliErr
 		}
+		if err := shared.ApplySpecConfig(cmd, cfg, ""); err != nil {
+			return err
+		}
 
 		// Override skip-preflight from flag if set
 		if cmd.Flags().Changed("skip-preflight") {
@@ -105,21 +116,23 @@ You can optionally provide a prompt to guide the planning process.`,
 		// Create notification handler and history logger
 		notifHandler := notify.NewHandler(cfg.Notifications)
 		historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
-		specName := fmt.Sprintf("%s-%s", metadata.Number, metadata.Name)
+		specName := metadata.DirName()
 
 		// Wrap command execution with lifecycle for timing, notification, and history
 		return lifecycle.RunWithHistory(notifHandler, historyLogger, "plan", specName, func() error {
 			// Create workflow orchestrator
 			orch := workflow.NewWorkflowOrchestrator(cfg)
-			orch.Executor.NotificationHandler = notifHandler
+			orch.SetNotificationHandler(notifHandler)
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orch)
+			orch.Executor.ForceRerun, _ = cmd.Flags().GetBool("force")
 
 			// Execute plan stage
 			if err := orch.ExecutePlan("", prompt); err != nil {
 				return fmt.Errorf("plan stage failed: %w", err)
 			}
+			shared.OpenArtifact(cmd, filepath.Join(metadata.Directory, "plan.yaml"))
 
 			return nil
 		})
@@ -131,10 +144,12 @@ func init() {
 
 	// Command-specific flags
 	planCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts (overrides config when set)")
+	planCmd.Flags().Bool("force", false, "Run even if plan.yaml is up to date with spec.yaml")
 
 	// Agent override flag
 	shared.AddAgentFlag(planCmd)
 
 	// Auto-commit flags
 	shared.AddAutoCommitFlags(planCmd)
+	shared.AddOpenFlag(planCmd)
 }
@@ -1,18 +1,28 @@
 package stages
 
 import (
+	"bufio"
 	"fmt"
+	"io"
+	"math"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/ariel-frischer/autospec/internal/config"
 	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/git"
 	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
 	"github.com/ariel-frischer/autospec/internal/lifecycle"
 	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/search"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/tracker"
 	"github.com/ariel-frischer/autospec/internal/workflow"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 var specifyCmd = &cobra.Command{
@@ -26,7 +36,19 @@ The specify command will:
 - Generate the specification based on your feature description
 - Output the spec name for use in subsequent commands
 
-The feature description should be a clear, concise description of what you want to build.`,
+The feature description should be a clear, concise description of what you want to build.
+
+With --from-issue (GitHub) or --from-gitlab-issue (GitLab), the issue title,
+body and labels are fetched and used as the feature description, and the issue
+URL is recorded as feature.input_source in spec.yaml. Any arguments are added
+as extra notes. A bare issue number is looked up in the origin remote's
+repository; GITHUB_TOKEN (or GH_TOKEN) / GITLAB_TOKEN is used when set, but
+only sent to github.com, gitlab.com and the hosts listed in
+issue_tracker.github.hosts / issue_tracker.gitlab.hosts.
+
+With --check-overlap (or overlap_check.enabled in config), the description is
+first compared with the stories and requirements of the existing specs, and
+specs covering much of it are reported before the agent runs.`,
 	Example: `  # Create a new feature specification
   autospec specify "Add user authentication feature"
 
@@ -34,9 +56,19 @@ The feature description should be a clear, concise description of what you want
   autospec specify "Implement dark mode with system preference detection"
 
   # Feature with quotes in the description
-  autospec specify 'Add "remember me" checkbox to login form'`,
+  autospec specify 'Add "remember me" checkbox to login form'
+
+  # Use a GitHub issue as the feature description
+  autospec specify --from-issue 123
+  autospec specify --from-issue https://github.com/acme/app/issues/123
+
+  # Use a GitLab issue, with extra notes
+  autospec specify --from-gitlab-issue 45 "Keep the existing API unchanged"
+
+  # Warn when an existing spec already covers the feature
+  autospec specify --check-overlap "Add session timeout for logged-in users"`,
 	Args: func(cmd *cobra.Command, args []string) error {
-		if len(args) < 1 {
+		if len(args) < 1 && !cmd.Flags().Changed("from-issue") && !cmd.Flags().Changed("from-gitlab-issue") {
 			cliErr := clierrors.MissingFeatureDescription()
 			clierrors.PrintError(cliErr)
 			return cliErr
@@ -61,6 +93,19 @@ The feature description should be a clear, concise description of what you want
 			return cliErr
 		}
 
+		// Import the description from an issue when requested
+		issue, err := fetchSpecifyIssue(cmd, cfg.IssueTracker)
+		if err != nil {
+			return err
+		}
+		if issue != nil {
+			featureDescription = joinIssueDescription(issue, featureDescription)
+		}
+
+		if !checkOverlap(cmd, cfg, featureDescription) {
+			return nil
+		}
+
 		// Create notification handler and history logger
 		notifHandler := notify.NewHandler(cfg.Notifications)
 		historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
@@ -108,7 +153,7 @@ The feature description should be a clear, concise description of what you want
 
 			// Create workflow orchestrator
 			orch := workflow.NewWorkflowOrchestrator(cfg)
-			orch.Executor.NotificationHandler = notifHandler
+			orch.SetNotificationHandler(notifHandler)
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orch)
@@ -119,21 +164,136 @@ The feature description should be a clear, concise description of what you want
 				return fmt.Errorf("specify stage failed: %w", execErr)
 			}
 
+			if issue != nil {
+				if err := spec.SetInputSource(filepath.Join(cfg.SpecsDir, specName), issue.URL); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not record issue source in spec.yaml: %v\n", err)
+				}
+			}
+
 			fmt.Printf("\nSpec created: %s\n", specName)
+			shared.OpenArtifact(cmd, filepath.Join(cfg.SpecsDir, specName, "spec.yaml"))
 			return nil
 		})
 	},
 }
 
+// fetchSpecifyIssue fetches the issue named by --from-issue or
+// --from-gitlab-issue, or returns nil when neither is set. Tokens are only
+// sent to the public hosts and the hosts listed in issue_tracker.
+func fetchSpecifyIssue(cmd *cobra.Command, trackerCfg tracker.Config) (*tracker.SourceIssue, error) {
+	provider, ref := tracker.SourceGitHub, ""
+	if cmd.Flags().Changed("from-issue") {
+		ref, _ = cmd.Flags().GetString("from-issue")
+	}
+	if cmd.Flags().Changed("from-gitlab-issue") {
+		if ref != "" {
+			return nil, clierrors.NewArgumentError("--from-issue and --from-gitlab-issue cannot be used together")
+		}
+		provider = tracker.SourceGitLab
+		ref, _ = cmd.Flags().GetString("from-gitlab-issue")
+	}
+	if ref == "" {
+		return nil, nil
+	}
+
+	remote, _ := git.GetRemoteURL("origin")
+	source, err := tracker.ParseSourceRef(provider, ref, remote)
+	if err != nil {
+		return nil, clierrors.NewArgumentError(err.Error())
+	}
+	issue, err := tracker.FetchSourceIssue(interrupt.Context(), source, trackerCfg.SourceHosts(provider))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Using issue: %s\n", issue.URL)
+	return issue, nil
+}
+
+// joinIssueDescription builds the feature description from an imported
+// issue, with any command-line text appended as notes.
+func joinIssueDescription(issue *tracker.SourceIssue, notes string) string {
+	description := issue.FeatureDescription()
+	if notes != "" {
+		description += "\n\nAdditional notes: " + notes
+	}
+	return description
+}
+
+// checkOverlap warns about existing specs that already cover the feature
+// description and, when overlap_check.confirm is set and stdin is a terminal,
+// asks whether to create a new spec anyway. It reports whether specify should
+// go on.
+func checkOverlap(cmd *cobra.Command, cfg *config.Configuration, description string) bool {
+	oc := cfg.OverlapCheck
+	if cmd.Flags().Changed("check-overlap") {
+		oc.Enabled, _ = cmd.Flags().GetBool("check-overlap")
+	}
+	if !oc.Enabled {
+		return true
+	}
+	minScore := oc.MinScore
+	if minScore == 0 {
+		minScore = search.DefaultOverlapMinScore
+	}
+
+	overlaps, err := search.FindOverlaps(cfg.SpecsDir, description, float64(minScore)/100)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: overlap check failed: %v\n", err)
+		return true
+	}
+	if len(overlaps) == 0 {
+		return true
+	}
+	printOverlaps(cmd.ErrOrStderr(), overlaps)
+
+	if !oc.Confirm || !term.IsTerminal(int(os.Stdin.Fd())) {
+		return true
+	}
+	fmt.Fprint(cmd.OutOrStdout(), "Create a new spec anyway? [y/N]: ")
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	answer = strings.TrimSpace(strings.ToLower(answer))
+	if answer == "y" || answer == "yes" {
+		return true
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "No spec created. To extend an existing spec, use 'autospec amend <spec> <change>'.")
+	return false
+}
+
+// printOverlaps writes the overlap warning for specify.
+func printOverlaps(w io.Writer, overlaps []search.Overlap) {
+	fmt.Fprintf(w, "Warning: this feature overlaps with %d existing %s:\n", len(overlaps), pluralSpecs(len(overlaps)))
+	for _, o := range overlaps {
+		line := fmt.Sprintf("  %s  %d%%", o.Spec, int(math.Round(o.Score*100)))
+		if o.Item != "" {
+			line += "  " + o.Item
+			if o.ItemTitle != "" {
+				line += fmt.Sprintf(" %q", o.ItemTitle)
+			}
+		}
+		fmt.Fprintf(w, "%s  (shared: %s)\n", line, strings.Join(o.Shared, ", "))
+	}
+}
+
+func pluralSpecs(n int) string {
+	if n == 1 {
+		return "spec"
+	}
+	return "specs"
+}
+
 func init() {
 	specifyCmd.GroupID = shared.GroupCoreStages
 
 	// Command-specific flags
 	specifyCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts (overrides config when set)")
+	specifyCmd.Flags().String("from-issue", "", "Use a GitHub issue (number, owner/repo#number or URL) as the feature description")
+	specifyCmd.Flags().Bool("check-overlap", false, "Warn about existing specs covering the feature first (overrides overlap_check.enabled)")
+	specifyCmd.Flags().String("from-gitlab-issue", "", "Use a GitLab issue (number, group/project#number or URL) as the feature description")
 
 	// Agent override flag
 	shared.AddAgentFlag(specifyCmd)
 
 	// Auto-commit flags
 	shared.AddAutoCommitFlags(specifyCmd)
+	shared.AddOpenFlag(specifyCmd)
 }
@@ -5,9 +5,13 @@
 package stages
 
 import (
+	"bytes"
 	"testing"
 
+	"github.com/ariel-frischer/autospec/internal/search"
+	"github.com/ariel-frischer/autospec/internal/tracker"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSpecifyCmd_Flags(t *testing.T) {
@@ -22,6 +26,18 @@ func TestSpecifyCmd_Flags(t *testing.T) {
 			flagName: "max-retries",
 			wantFlag: true,
 		},
+		"from-issue flag exists": {
+			flagName: "from-issue",
+			wantFlag: true,
+		},
+		"from-gitlab-issue flag exists": {
+			flagName: "from-gitlab-issue",
+			wantFlag: true,
+		},
+		"check-overlap flag exists": {
+			flagName: "check-overlap",
+			wantFlag: true,
+		},
 	}
 
 	for name, tt := range tests {
@@ -363,6 +379,29 @@ func TestSpecifyCmd_ArgsFunction(t *testing.T) {
 	assert.NoError(t, err, "Non-empty args should be valid for specify command")
 }
 
+func TestSpecifyCmd_ArgsFromIssue(t *testing.T) {
+	// Cannot run in parallel - accesses global command state
+
+	flag := specifyCmd.Flags().Lookup("from-issue")
+	require.NoError(t, flag.Value.Set("123"))
+	flag.Changed = true
+	t.Cleanup(func() {
+		_ = flag.Value.Set("")
+		flag.Changed = false
+	})
+
+	assert.NoError(t, specifyCmd.Args(specifyCmd, []string{}), "--from-issue replaces the description argument")
+}
+
+func TestJoinIssueDescription(t *testing.T) {
+	t.Parallel()
+
+	issue := &tracker.SourceIssue{Title: "Dark mode", URL: "https://github.com/acme/app/issues/1"}
+	assert.Equal(t, "Dark mode\n\nSource: https://github.com/acme/app/issues/1", joinIssueDescription(issue, ""))
+	assert.Equal(t, "Dark mode\n\nSource: https://github.com/acme/app/issues/1\n\nAdditional notes: keep it small",
+		joinIssueDescription(issue, "keep it small"))
+}
+
 func TestStageCommands_GroupIDsMatch(t *testing.T) {
 	// Cannot run in parallel - accesses global command state
 
@@ -423,3 +462,17 @@ func TestImplementCmd_PhaseFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestPrintOverlaps(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	printOverlaps(&buf, []search.Overlap{
+		{Spec: "007-user-sessions", Score: 0.625, Item: "story US-002", ItemTitle: "Stay signed in", Shared: []string{"session", "timeout"}},
+		{Spec: "009-audit", Score: 0.41, Shared: []string{"logged"}},
+	})
+	assert.Equal(t, `Warning: this feature overlaps with 2 existing specs:
+  007-user-sessions  63%  story US-002 "Stay signed in"  (shared: session, timeout)
+  009-audit  41%  (shared: logged)
+`, buf.String())
+}
@@ -3,6 +3,7 @@ package stages
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/ariel-frischer/autospec/internal/cli/shared"
@@ -27,7 +28,11 @@ The tasks command will:
 - Execute the task generation workflow
 - Create tasks.yaml with actionable, dependency-ordered tasks
 
-You can optionally provide a prompt to guide the task generation.`,
+You can optionally provide a prompt to guide the task generation.
+
+Like plan, tasks skips the agent call when tasks.yaml is unchanged since its
+last successful run and so are spec.yaml, plan.yaml, the constitution and the
+prompt. Use --force to generate tasks again anyway.`,
 	Example: `  # Generate tasks with default granularity
   autospec tasks
 
@@ -56,6 +61,9 @@ You can optionally provide a prompt to guide the task generation.`,
 			clierrors.PrintError(cliErr)
 			return cliErr
 		}
+		if err := shared.ApplySpecConfig(cmd, cfg, ""); err != nil {
+			return err
+		}
 
 		// Override skip-preflight from flag if set
 		if cmd.Flags().Changed("skip-preflight") {
@@ -105,21 +113,23 @@ You can optionally provide a prompt to guide the task generation.`,
 		// Create notification handler and history logger
 		notifHandler := notify.NewHandler(cfg.Notifications)
 		historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
-		specName := fmt.Sprintf("%s-%s", metadata.Number, metadata.Name)
+		specName := metadata.DirName()
 
 		// Wrap command execution with lifecycle for timing, notification, and history
 		return lifecycle.RunWithHistory(notifHandler, historyLogger, "tasks", specName, func() error {
 			// Create workflow orchestrator
 			orch := workflow.NewWorkflowOrchestrator(cfg)
-			orch.Executor.NotificationHandler = notifHandler
+			orch.SetNotificationHandler(notifHandler)
 
 			// Apply output style from CLI flag (overrides config)
 			shared.ApplyOutputStyle(cmd, orch)
+			orch.Executor.ForceRerun, _ = cmd.Flags().GetBool("force")
 
 			// Execute tasks stage
 			if err := orch.ExecuteTasks("", prompt); err != nil {
 				return fmt.Errorf("tasks stage failed: %w", err)
 			}
+			shared.OpenArtifact(cmd, filepath.Join(metadata.Directory, "tasks.yaml"))
 
 			return nil
 		})
@@ -131,10 +141,12 @@ func init() {
 
 	// Command-specific flags
 	tasksCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts (overrides config when set)")
+	tasksCmd.Flags().Bool("force", false, "Run even if tasks.yaml is up to date with spec.yaml and plan.yaml")
 
 	// Agent override flag
 	shared.AddAgentFlag(tasksCmd)
 
 	// Auto-commit flags
 	shared.AddAutoCommitFlags(tasksCmd)
+	shared.AddOpenFlag(tasksCmd)
 }
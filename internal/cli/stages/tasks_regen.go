@@ -0,0 +1,206 @@
+package stages
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var tasksRegenCmd = &cobra.Command{
+	Use:   "regen --phase <number> [optional-prompt]",
+	Short: "Regenerate the tasks of a single phase after the plan changed",
+	Long: `Ask the agent to regenerate one phase of tasks.yaml, keeping every other
+phase as it is.
+
+The regenerated phase is merged back into tasks.yaml:
+- Tasks that keep their ID keep their status and status history
+- Completed tasks must come back unchanged
+- New task IDs must not clash with tasks in other phases
+- Dependencies must point at tasks that still exist
+
+If any of these rules is broken, tasks.yaml is left untouched and the
+agent's output is saved as tasks.regen.yaml for review. On success the
+previous file is kept as tasks.yaml.bak.`,
+	Example: `  # Regenerate phase 3 after editing plan.yaml
+  autospec tasks regen --phase 3
+
+  # Guide the regeneration
+  autospec tasks regen --phase 3 "Split the API work per endpoint"`,
+	RunE: runTasksRegen,
+}
+
+func init() {
+	tasksRegenCmd.Flags().Int("phase", 0, "Phase number to regenerate (required)")
+	_ = tasksRegenCmd.MarkFlagRequired("phase")
+	tasksRegenCmd.Flags().IntP("max-retries", "r", 0, "Override max retry attempts (overrides config when set)")
+	shared.AddAgentFlag(tasksRegenCmd)
+
+	tasksCmd.AddCommand(tasksRegenCmd)
+}
+
+func runTasksRegen(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	phase, _ := cmd.Flags().GetInt("phase")
+	maxRetries, _ := cmd.Flags().GetInt("max-retries")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	if err := shared.ApplySpecConfig(cmd, cfg, ""); err != nil {
+		return err
+	}
+	if cmd.Flags().Changed("max-retries") {
+		cfg.MaxRetries = maxRetries
+	}
+	if _, err := shared.ApplyAgentOverride(cmd, cfg); err != nil {
+		return err
+	}
+
+	metadata, err := spec.DetectCurrentSpec(cfg.SpecsDir)
+	if err != nil {
+		return fmt.Errorf("failed to detect current spec: %w", err)
+	}
+	shared.PrintSpecInfo(metadata)
+
+	tasksPath := filepath.Join(metadata.Directory, "tasks.yaml")
+	original, err := os.ReadFile(tasksPath)
+	if err != nil {
+		return fmt.Errorf("reading tasks.yaml: %w\n\nRun 'autospec tasks' to generate tasks first", err)
+	}
+	title, err := phaseTitle(tasksPath, phase)
+	if err != nil {
+		return err
+	}
+
+	prompt := buildPhaseRegenPrompt(phase, title, strings.Join(args, " "))
+
+	notifHandler := notify.NewHandler(cfg.Notifications)
+	historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
+	specName := metadata.DirName()
+
+	return lifecycle.RunWithHistory(notifHandler, historyLogger, "tasks-regen", specName, func() error {
+		orch := workflow.NewWorkflowOrchestrator(cfg)
+		orch.SetNotificationHandler(notifHandler)
+		shared.ApplyOutputStyle(cmd, orch)
+		orch.Executor.ForceRerun = true // Regeneration always needs a fresh agent run
+
+		if err := orch.ExecuteTasks("", prompt); err != nil {
+			if restoreErr := os.WriteFile(tasksPath, original, 0o644); restoreErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to restore tasks.yaml: %v\n", restoreErr)
+			}
+			return fmt.Errorf("regenerating phase %d failed: %w", phase, err)
+		}
+		return applyRegeneratedPhase(cmd.OutOrStdout(), tasksPath, original, phase)
+	})
+}
+
+// phaseTitle returns the title of a phase, or an error if tasks.yaml has no such phase.
+func phaseTitle(tasksPath string, phase int) (string, error) {
+	tasks, err := validation.ParseTasksYAML(tasksPath)
+	if err != nil {
+		return "", err
+	}
+	for _, p := range tasks.Phases {
+		if p.Number == phase {
+			return p.Title, nil
+		}
+	}
+	return "", fmt.Errorf("phase %d not found in %s (%d phases)", phase, tasksPath, len(tasks.Phases))
+}
+
+// buildPhaseRegenPrompt builds the /autospec.tasks prompt that limits the
+// agent to a single phase. Double quotes are replaced because the prompt is
+// passed as a quoted slash command argument.
+func buildPhaseRegenPrompt(phase int, title, userPrompt string) string {
+	prompt := fmt.Sprintf("Regenerate ONLY phase %d (%s) of the existing tasks.yaml because the plan changed. "+
+		"Re-read plan.yaml and rewrite the tasks of phase %d; copy every other phase unchanged. "+
+		"Keep the IDs of tasks that still apply, leave tasks with status Completed exactly as they are, "+
+		"and give new tasks IDs that are not used in any other phase.",
+		phase, title, phase)
+	if userPrompt != "" {
+		prompt += " " + userPrompt
+	}
+	return strings.ReplaceAll(prompt, `"`, "'")
+}
+
+// applyRegeneratedPhase merges the phase the agent rewrote in tasksPath into
+// the original tasks.yaml content. On conflict the original is restored and
+// the agent's output is kept as tasks.regen.yaml.
+func applyRegeneratedPhase(out io.Writer, tasksPath string, original []byte, phase int) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	regenerated, err := os.ReadFile(tasksPath)
+	if err != nil {
+		return fmt.Errorf("reading regenerated tasks.yaml: %w", err)
+	}
+
+	merged, result, err := validation.MergeRegeneratedPhase(original, regenerated, phase)
+	if err == nil && !result.HasConflicts() {
+		backupPath := tasksPath + ".bak"
+		if err := fsutil.WriteFile(backupPath, original, 0o644); err != nil {
+			return fmt.Errorf("backing up tasks.yaml: %w", err)
+		}
+		if err := fsutil.WriteFile(tasksPath, merged, 0o644); err != nil {
+			return fmt.Errorf("writing tasks.yaml: %w", err)
+		}
+		// The merge keeps the original _meta, so record the current plan again
+		if err := validation.StampSourceHashes(tasksPath, validation.ArtifactTypeTasks); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: recording source hashes: %v\n", err)
+		}
+
+		fmt.Fprintf(out, "%s Regenerated phase %d: %d added, %d updated, %d removed, %d completed kept\n",
+			green("✓"), phase, len(result.Added), len(result.Updated), len(result.Removed), len(result.Kept))
+		printTaskIDs(out, yellow("+"), result.Added)
+		printTaskIDs(out, yellow("~"), result.Updated)
+		printTaskIDs(out, yellow("-"), result.Removed)
+		fmt.Fprintf(out, "  %s\n", dim("backup: "+backupPath))
+		return nil
+	}
+
+	regenPath := filepath.Join(filepath.Dir(tasksPath), "tasks.regen.yaml")
+	if writeErr := os.WriteFile(regenPath, regenerated, 0o644); writeErr != nil {
+		return fmt.Errorf("saving regenerated tasks: %w", writeErr)
+	}
+	if writeErr := os.WriteFile(tasksPath, original, 0o644); writeErr != nil {
+		return fmt.Errorf("restoring tasks.yaml: %w", writeErr)
+	}
+	if err != nil {
+		return fmt.Errorf("merging phase %d: %w (agent output saved to %s)", phase, err, regenPath)
+	}
+
+	for _, conflict := range result.Conflicts {
+		fmt.Fprintf(out, "%s %s\n", red("✗"), conflict)
+	}
+	return fmt.Errorf("phase %d not merged: %d conflict(s); tasks.yaml left unchanged, agent output saved to %s",
+		phase, len(result.Conflicts), regenPath)
+}
+
+// printTaskIDs prints a marked, comma-separated list of task IDs if there are any.
+func printTaskIDs(out io.Writer, mark string, ids []string) {
+	if len(ids) > 0 {
+		fmt.Fprintf(out, "  %s %s\n", mark, strings.Join(ids, ", "))
+	}
+}
@@ -0,0 +1,143 @@
+// Package stages tests the tasks regen subcommand.
+// Related: internal/cli/stages/tasks_regen.go
+// Tags: stages, cli, tasks, regen, phase, merge
+
+package stages
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const regenTasksYAML = `summary:
+  total_tasks: 2
+phases:
+  - number: 1
+    title: "Setup"
+    tasks:
+      - id: "T001"
+        title: "Create project"
+        status: "Completed"
+  - number: 2
+    title: "API"
+    tasks:
+      - id: "T002"
+        title: "Add handler"
+        status: "Pending"
+        dependencies: ["T001"]
+`
+
+func TestTasksRegenCmd_Registered(t *testing.T) {
+	cmd, _, err := tasksCmd.Find([]string{"regen"})
+	require.NoError(t, err)
+	assert.Equal(t, tasksRegenCmd, cmd)
+
+	flag := tasksRegenCmd.Flags().Lookup("phase")
+	require.NotNil(t, flag)
+	assert.Equal(t, []string{"true"}, flag.Annotations["cobra_annotation_bash_completion_one_required_flag"])
+}
+
+func TestBuildPhaseRegenPrompt(t *testing.T) {
+	t.Parallel()
+
+	prompt := buildPhaseRegenPrompt(3, `The "core" work`, `Split per "endpoint"`)
+	assert.Contains(t, prompt, "Regenerate ONLY phase 3 (The 'core' work)")
+	assert.Contains(t, prompt, "leave tasks with status Completed exactly as they are")
+	assert.Contains(t, prompt, "Split per 'endpoint'")
+	assert.NotContains(t, prompt, `"`)
+}
+
+func TestPhaseTitle(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tasks.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(regenTasksYAML), 0o644))
+
+	title, err := phaseTitle(path, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "API", title)
+
+	_, err = phaseTitle(path, 5)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "phase 5 not found")
+}
+
+func TestApplyRegeneratedPhase(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		regenerated string
+		wantErr     string
+		wantOut     string
+	}{
+		"merged": {
+			regenerated: `phases:
+  - number: 2
+    title: "API"
+    tasks:
+      - id: "T002"
+        title: "Add handler"
+        status: "Pending"
+        dependencies: ["T001"]
+      - id: "T003"
+        title: "Add routes"
+        status: "Pending"
+        dependencies: ["T002"]
+`,
+			wantOut: "Regenerated phase 2: 1 added, 0 updated, 0 removed, 0 completed kept",
+		},
+		"conflict": {
+			regenerated: `phases:
+  - number: 2
+    title: "API"
+    tasks:
+      - id: "T001"
+        title: "Clash"
+`,
+			wantErr: "phase 2 not merged: 1 conflict(s)",
+			wantOut: "T001 is already used by a task in phase 1",
+		},
+		"phase missing from output": {
+			regenerated: "phases: []\n",
+			wantErr:     "merging phase 2: phase 2 not found in regenerated tasks",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			tasksPath := filepath.Join(dir, "tasks.yaml")
+			require.NoError(t, os.WriteFile(tasksPath, []byte(tt.regenerated), 0o644))
+
+			var out bytes.Buffer
+			err := applyRegeneratedPhase(&out, tasksPath, []byte(regenTasksYAML), 2)
+			assert.Contains(t, out.String(), tt.wantOut)
+
+			data, readErr := os.ReadFile(tasksPath)
+			require.NoError(t, readErr)
+
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				assert.Equal(t, regenTasksYAML, string(data), "tasks.yaml should be restored")
+				saved, readErr := os.ReadFile(filepath.Join(dir, "tasks.regen.yaml"))
+				require.NoError(t, readErr)
+				assert.Equal(t, tt.regenerated, string(saved))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Contains(t, string(data), "Create project", "phase 1 should be kept")
+			assert.Contains(t, string(data), "Add routes")
+			backup, readErr := os.ReadFile(tasksPath + ".bak")
+			require.NoError(t, readErr)
+			assert.Equal(t, regenTasksYAML, string(backup))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/ghproject"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Mirror spec progress to external tools",
+	Long:  `Mirror spec progress to tools that people outside the CLI follow.`,
+}
+
+var syncGitHubProjectCmd = &cobra.Command{
+	Use:   "github-project [spec]",
+	Short: "Mirror tasks.yaml into a GitHub Projects board",
+	Long: `Mirror a spec's tasks.yaml into a GitHub Projects (v2) board.
+
+Each task becomes a draft item titled "T001: <title>", placed in the column
+(an option of the project's Status field) of its status:
+
+  Pending     github_project.columns.pending      (default: Todo)
+  InProgress  github_project.columns.in_progress  (default: In Progress)
+  Blocked     github_project.columns.blocked      (default: Blocked)
+  Completed   github_project.columns.completed    (default: Done)
+
+Item IDs are kept in the spec's github-project.yaml, so running it again
+only adds new tasks and moves items whose task status changed. With
+github_project configured, run, implement and tasks keep the board up to
+date after each task or phase without this command.
+
+The token is read from GITHUB_TOKEN, GH_TOKEN or the github-token secret and
+needs the project scope (classic tokens) or Projects read and write access.
+
+Without a spec argument, the current spec is auto-detected.`,
+	Example: `  # Sync the current spec to the configured project
+  autospec sync github-project
+
+  # Sync spec 003 to project 5 of the acme organization
+  autospec sync github-project 003 --owner acme --number 5`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSyncGitHubProject,
+}
+
+func init() {
+	syncCmd.GroupID = GroupGettingStarted
+	syncCmd.AddCommand(syncGitHubProjectCmd)
+	syncGitHubProjectCmd.Flags().String("owner", "", "User or organization that owns the project (overrides github_project.owner)")
+	syncGitHubProjectCmd.Flags().Int("number", 0, "Project number (overrides github_project.number)")
+	rootCmd.AddCommand(syncCmd)
+}
+
+func runSyncGitHubProject(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	project := cfg.GitHubProject
+	if cmd.Flags().Changed("owner") {
+		project.Owner, _ = cmd.Flags().GetString("owner")
+	}
+	if cmd.Flags().Changed("number") {
+		project.Number, _ = cmd.Flags().GetInt("number")
+	}
+	if !project.Enabled() {
+		return fmt.Errorf("no GitHub project configured: set github_project.owner and github_project.number, or pass --owner and --number")
+	}
+
+	var metadata *spec.Metadata
+	if len(args) == 1 {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, args[0])
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detect spec: %w", err)
+	}
+
+	syncer, err := ghproject.New(project)
+	if err != nil {
+		return fmt.Errorf("github_project: %w", err)
+	}
+	result, err := syncer.Sync(interrupt.Context(), metadata.Directory)
+	printProjectSync(cmd.OutOrStdout(), metadata.DirName(), result)
+	return err
+}
+
+// printProjectSync summarizes a GitHub project sync.
+func printProjectSync(w io.Writer, specName string, result ghproject.Result) {
+	if result.URL == "" {
+		return
+	}
+	fmt.Fprintf(w, "Synced %s to %s: %d item(s) added, %d moved\n", specName, result.URL, result.Created, result.Moved)
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/ghproject"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncGitHubProjectCmd_Registered(t *testing.T) {
+	t.Parallel()
+
+	cmd, _, err := rootCmd.Find([]string{"sync", "github-project"})
+	assert.NoError(t, err)
+	assert.Equal(t, syncGitHubProjectCmd, cmd)
+	assert.NotNil(t, cmd.Flags().Lookup("owner"))
+	assert.NotNil(t, cmd.Flags().Lookup("number"))
+}
+
+func TestPrintProjectSync(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		result ghproject.Result
+		want   string
+	}{
+		"project not reached": {result: ghproject.Result{}, want: ""},
+		"synced": {
+			result: ghproject.Result{URL: "https://github.com/orgs/acme/projects/3", Created: 2, Moved: 1},
+			want:   "Synced 001-login to https://github.com/orgs/acme/projects/3: 2 item(s) added, 1 moved\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			printProjectSync(&buf, "001-login", tt.result)
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
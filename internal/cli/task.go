@@ -10,9 +10,10 @@ var taskCmd = &cobra.Command{
 	Long: `Commands for managing tasks in the current feature's tasks.yaml file.
 
 Available subcommands:
-  block     Block a task with a reason
-  unblock   Unblock a task and set its status
-  list      List tasks with optional status filters
+  block       Block a task with a reason
+  unblock     Unblock a task and set its status
+  list        List tasks with optional status filters
+  set-status  Manually set a task status with an audit trail
 
 These commands provide a convenient way to update task statuses and track
 blocking reasons without manually editing the YAML file.`,
@@ -29,7 +30,10 @@ blocking reasons without manually editing the YAML file.`,
   autospec task list --blocked
 
   # List all tasks
-  autospec task list`,
+  autospec task list
+
+  # Manually mark a task completed with an audit trail
+  autospec task set-status T004 Completed --reason "done by hand"`,
 }
 
 func init() {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ariel-frischer/autospec/internal/config"
 	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 	"github.com/ariel-frischer/autospec/internal/spec"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -47,7 +48,7 @@ func runTaskBlock(cmd *cobra.Command, args []string) error {
 
 	// Validate task ID format
 	if !taskIDPattern.MatchString(taskID) {
-		return fmt.Errorf("invalid task ID format: %s (expected T followed by digits, e.g., T001)", taskID)
+		return fmt.Errorf("invalid task ID format: %s (expected T followed by digits, e.g., T001, or just the number)", taskID)
 	}
 
 	// Validate reason is not empty
@@ -89,6 +90,11 @@ func runTaskBlock(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("parsing tasks.yaml: %w", err)
 	}
 
+	taskID, err = resolveTaskRef(&root, taskID, tasksPath)
+	if err != nil {
+		return err
+	}
+
 	// Find and update the task
 	result := findAndBlockTask(&root, taskID, blockReason)
 	if !result.found {
@@ -101,7 +107,7 @@ func runTaskBlock(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("serializing tasks.yaml: %w", err)
 	}
 
-	if err := os.WriteFile(tasksPath, output, 0o644); err != nil {
+	if err := fsutil.WriteArtifact(tasksPath, output, 0o644); err != nil {
 		return fmt.Errorf("writing tasks.yaml: %w", err)
 	}
 
@@ -2,8 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/ariel-frischer/autospec/internal/config"
 	clierrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/spec"
@@ -40,7 +42,10 @@ Multiple filters can be combined to show tasks matching any of the specified sta
   autospec task list --pending --in-progress
 
   # List all non-completed tasks
-  autospec task list --pending --in-progress --blocked`,
+  autospec task list --pending --in-progress --blocked
+
+  # List blocked tasks as JSON
+  autospec task list --blocked --json`,
 	RunE: runTaskList,
 }
 
@@ -65,6 +70,9 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 	}
 
 	filtered := filterTasksByStatus(tasks)
+	if useJSON, _ := cmd.Flags().GetBool("json"); useJSON {
+		return shared.WriteJSON(cmd, newTaskListResult(filepath.Base(filepath.Dir(tasksPath)), filtered))
+	}
 	if len(filtered) == 0 {
 		fmt.Println("No tasks found matching the specified filters.")
 		return nil
@@ -74,6 +82,45 @@ func runTaskList(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// taskListResult is the --json result of task list.
+type taskListResult struct {
+	Spec  string     `json:"spec"`
+	Tasks []taskJSON `json:"tasks"`
+}
+
+// taskJSON is a task in --json results.
+type taskJSON struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Status        string   `json:"status"`
+	Type          string   `json:"type,omitempty"`
+	StoryID       string   `json:"story_id,omitempty"`
+	Dependencies  []string `json:"dependencies"`
+	BlockedReason string   `json:"blocked_reason,omitempty"`
+	Manual        bool     `json:"manual"`
+}
+
+func newTaskListResult(specName string, tasks []validation.TaskItem) taskListResult {
+	result := taskListResult{Spec: specName, Tasks: make([]taskJSON, 0, len(tasks))}
+	for _, task := range tasks {
+		deps := task.Dependencies
+		if deps == nil {
+			deps = []string{}
+		}
+		result.Tasks = append(result.Tasks, taskJSON{
+			ID:            task.ID,
+			Title:         task.Title,
+			Status:        task.Status,
+			Type:          task.Type,
+			StoryID:       task.StoryID,
+			Dependencies:  deps,
+			BlockedReason: task.BlockedReason,
+			Manual:        task.IsManualOverride(),
+		})
+	}
+	return result
+}
+
 // loadTasksConfig loads config and returns the tasks.yaml path
 func loadTasksConfig(cmd *cobra.Command) (*config.Configuration, string, error) {
 	configPath, _ := cmd.Flags().GetString("config")
@@ -136,7 +183,11 @@ func printTaskList(tasks []validation.TaskItem) {
 
 	for _, task := range tasks {
 		statusIcon := getStatusIcon(task.Status)
-		fmt.Printf("  %s %s [%s] %s\n", statusIcon, task.ID, task.Status, task.Title)
+		manual := ""
+		if task.IsManualOverride() {
+			manual = " (manual)"
+		}
+		fmt.Printf("  %s %s [%s]%s %s\n", statusIcon, task.ID, task.Status, manual, task.Title)
 
 		// Show blocked reason for blocked tasks
 		if strings.EqualFold(task.Status, "Blocked") {
@@ -0,0 +1,242 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/git"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	setStatusReason string
+	setStatusBy     string
+)
+
+var taskSetStatusCmd = &cobra.Command{
+	Use:   "set-status <task-id> <status>",
+	Short: "Manually set a task status with an audit trail",
+	Long: `Manually override the status of a task and record the change.
+
+Unlike update-task (which is used by agents during implementation), this
+command is intended for humans correcting task state by hand. Every change
+is appended to the task's status_history block with who made the change,
+when, and why, and is flagged as a manual override so validation and
+status reporting can distinguish it from agent-driven updates.
+
+The file is written atomically (temp file + rename) so an interrupted write
+never leaves a truncated tasks.yaml behind.
+
+Setting a task to Blocked also sets blocked_reason to the given reason.
+Moving a task out of Blocked removes its blocked_reason.`,
+	Example: `  # Mark a task completed by hand
+  autospec task set-status T004 Completed --reason "done by hand"
+
+  # Reset a task back to Pending, recording who did it
+  autospec task set-status T007 Pending --reason "needs rework" --by alice`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTaskSetStatus,
+}
+
+func init() {
+	taskSetStatusCmd.Flags().StringVarP(&setStatusReason, "reason", "r", "", "Reason for the manual status change (required)")
+	taskSetStatusCmd.Flags().StringVar(&setStatusBy, "by", "", "Who made the change (default: git user.name or $USER)")
+	_ = taskSetStatusCmd.MarkFlagRequired("reason")
+	taskCmd.AddCommand(taskSetStatusCmd)
+}
+
+// statusChange describes a single manual status transition to record.
+type statusChange struct {
+	To     string
+	By     string
+	At     time.Time
+	Reason string
+}
+
+// setStatusResult holds the result of a set-status operation
+type setStatusResult struct {
+	taskID         string // Resolved task ID (e.g., "T004" for "4")
+	found          bool
+	previousStatus string
+}
+
+func runTaskSetStatus(cmd *cobra.Command, args []string) error {
+	taskID, newStatus := args[0], args[1]
+	if err := validateSetStatusArgs(taskID, newStatus, setStatusReason); err != nil {
+		return err
+	}
+
+	_, tasksPath, err := loadTasksConfig(cmd)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(tasksPath); os.IsNotExist(err) {
+		return fmt.Errorf("tasks.yaml not found: %s\nRun /autospec.tasks first to generate tasks", tasksPath)
+	}
+
+	change := statusChange{
+		To:     newStatus,
+		By:     resolveChangeAuthor(setStatusBy),
+		At:     time.Now().UTC(),
+		Reason: setStatusReason,
+	}
+	result, err := setTaskStatusInFile(tasksPath, taskID, change)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Task %s: %s -> %s (manual)\n", result.taskID, result.previousStatus, newStatus)
+	fmt.Printf("  By:     %s\n", change.By)
+	fmt.Printf("  Reason: %s\n", truncateReason(change.Reason, 60))
+	return nil
+}
+
+// validateSetStatusArgs validates the task ID, status, and reason arguments.
+func validateSetStatusArgs(taskID, status, reason string) error {
+	if !taskIDPattern.MatchString(taskID) {
+		return fmt.Errorf("invalid task ID format: %s (expected T followed by digits, e.g., T001, or just the number)", taskID)
+	}
+	if !isValidStatus(status) {
+		return fmt.Errorf("invalid status: %s (valid: %v)", status, validStatuses)
+	}
+	if reason == "" {
+		return fmt.Errorf("reason cannot be empty")
+	}
+	return nil
+}
+
+// resolveChangeAuthor returns the explicit author, falling back to git user.name then $USER.
+func resolveChangeAuthor(explicit string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if name, err := git.GetUserName(); err == nil && name != "" {
+		return name
+	}
+	if user := os.Getenv("USER"); user != "" {
+		return user
+	}
+	return "unknown"
+}
+
+// setTaskStatusInFile applies a manual status change to tasksPath and writes it atomically.
+func setTaskStatusInFile(tasksPath, taskID string, change statusChange) (setStatusResult, error) {
+	data, err := os.ReadFile(tasksPath)
+	if err != nil {
+		return setStatusResult{}, fmt.Errorf("reading tasks.yaml: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return setStatusResult{}, fmt.Errorf("parsing tasks.yaml: %w", err)
+	}
+
+	taskID, err = resolveTaskRef(&root, taskID, tasksPath)
+	if err != nil {
+		return setStatusResult{}, err
+	}
+
+	result := findAndSetTaskStatus(&root, taskID, change)
+	result.taskID = taskID
+	if !result.found {
+		return result, fmt.Errorf("task not found: %s\nCheck that the task ID exists in: %s", taskID, tasksPath)
+	}
+
+	output, err := yaml.Marshal(&root)
+	if err != nil {
+		return result, fmt.Errorf("serializing tasks.yaml: %w", err)
+	}
+	if err := fsutil.WriteArtifact(tasksPath, output, 0o644); err != nil {
+		return result, fmt.Errorf("writing tasks.yaml: %w", err)
+	}
+	return result, nil
+}
+
+// findAndSetTaskStatus locates the task by ID and applies the status change,
+// maintaining blocked_reason and appending a status_history entry.
+func findAndSetTaskStatus(root *yaml.Node, taskID string, change statusChange) setStatusResult {
+	taskNode := validation.FindTaskNode(root, taskID)
+	if taskNode == nil {
+		return setStatusResult{}
+	}
+
+	statusNode := findNode(taskNode, "status")
+	if statusNode == nil {
+		return setStatusResult{}
+	}
+
+	result := setStatusResult{found: true, previousStatus: statusNode.Value}
+	statusNode.Value = change.To
+	applyBlockedReason(taskNode, change)
+	appendStatusHistory(taskNode, result.previousStatus, change)
+	return result
+}
+
+// findNode returns the value node for key in a mapping node, or nil.
+func findNode(node *yaml.Node, key string) *yaml.Node {
+	if idx := findKeyIndex(node, key); idx >= 0 {
+		return node.Content[idx+1]
+	}
+	return nil
+}
+
+// findKeyIndex returns the index of key in a mapping node's content, or -1.
+func findKeyIndex(node *yaml.Node, key string) int {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyBlockedReason keeps blocked_reason consistent with the new status.
+func applyBlockedReason(taskNode *yaml.Node, change statusChange) {
+	reasonIdx := findKeyIndex(taskNode, "blocked_reason")
+	if change.To != "Blocked" {
+		if reasonIdx >= 0 {
+			removeBlockedReason(taskNode, reasonIdx)
+		}
+		return
+	}
+
+	if reasonIdx >= 0 {
+		taskNode.Content[reasonIdx+1].Value = change.Reason
+		return
+	}
+	insertBlockedReason(taskNode, findKeyIndex(taskNode, "status"), change.Reason)
+}
+
+// appendStatusHistory appends a manual change entry to the task's status_history block,
+// creating the block if it does not exist yet.
+func appendStatusHistory(taskNode *yaml.Node, previousStatus string, change statusChange) {
+	historyNode := findNode(taskNode, "status_history")
+	if historyNode == nil || historyNode.Kind != yaml.SequenceNode {
+		historyNode = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		validation.SetMappingValue(taskNode, "status_history", historyNode, "")
+	}
+
+	entry := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	addScalarPair(entry, "from", previousStatus, "!!str")
+	addScalarPair(entry, "to", change.To, "!!str")
+	addScalarPair(entry, "by", change.By, "!!str")
+	addScalarPair(entry, "at", change.At.Format(time.RFC3339), "!!str")
+	addScalarPair(entry, "reason", change.Reason, "!!str")
+	addScalarPair(entry, "manual", "true", "!!bool")
+	historyNode.Content = append(historyNode.Content, entry)
+}
+
+// addScalarPair appends a scalar key/value pair to a mapping node.
+func addScalarPair(node *yaml.Node, key, value, tag string) {
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value},
+	)
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ariel-frischer/autospec/internal/validation"
 	"github.com/stretchr/testify/assert"
@@ -1157,3 +1158,159 @@ func TestGetStatusIcon(t *testing.T) {
 		})
 	}
 }
+
+func TestFindAndSetTaskStatus(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		yaml         string
+		taskID       string
+		change       statusChange
+		wantFound    bool
+		wantPrevious string
+		wantContains []string
+		wantMissing  []string
+	}{
+		"pending to completed records history": {
+			yaml: `
+phases:
+  - tasks:
+      - id: T004
+        status: Pending
+`,
+			taskID:       "T004",
+			change:       statusChange{To: "Completed", By: "alice", At: at, Reason: "done by hand"},
+			wantFound:    true,
+			wantPrevious: "Pending",
+			wantContains: []string{"status: Completed", "status_history:", "from: Pending", "to: Completed", "by: alice", "at: \"2025-01-16T09:30:00Z\"", "reason: done by hand", "manual: true"},
+		},
+		"blocked to pending removes blocked_reason": {
+			yaml: `
+tasks:
+  - id: T001
+    status: Blocked
+    blocked_reason: waiting
+`,
+			taskID:       "T001",
+			change:       statusChange{To: "Pending", By: "bob", At: at, Reason: "unblocked"},
+			wantFound:    true,
+			wantPrevious: "Blocked",
+			wantMissing:  []string{"blocked_reason"},
+		},
+		"set blocked adds blocked_reason": {
+			yaml: `
+tasks:
+  - id: T001
+    status: InProgress
+`,
+			taskID:       "T001",
+			change:       statusChange{To: "Blocked", By: "bob", At: at, Reason: "needs API key"},
+			wantFound:    true,
+			wantPrevious: "InProgress",
+			wantContains: []string{"blocked_reason: needs API key"},
+		},
+		"task not found": {
+			yaml: `
+tasks:
+  - id: T001
+    status: Pending
+`,
+			taskID:    "T999",
+			change:    statusChange{To: "Completed", By: "bob", At: at, Reason: "x"},
+			wantFound: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			var root yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tt.yaml), &root))
+
+			result := findAndSetTaskStatus(&root, tt.taskID, tt.change)
+			assert.Equal(t, tt.wantFound, result.found)
+			assert.Equal(t, tt.wantPrevious, result.previousStatus)
+
+			output, err := yaml.Marshal(&root)
+			require.NoError(t, err)
+			for _, want := range tt.wantContains {
+				assert.Contains(t, string(output), want)
+			}
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, string(output), missing)
+			}
+		})
+	}
+}
+
+func TestSetTaskStatusInFile_AppendsHistory(t *testing.T) {
+	t.Parallel()
+
+	tasksPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: Setup
+    tasks:
+      - id: T001
+        title: First task
+        status: Pending
+        type: setup
+`
+	require.NoError(t, os.WriteFile(tasksPath, []byte(content), 0o644))
+
+	first := statusChange{To: "Completed", By: "alice", At: time.Now().UTC(), Reason: "done by hand"}
+	_, err := setTaskStatusInFile(tasksPath, "T001", first)
+	require.NoError(t, err)
+
+	second := statusChange{To: "Pending", By: "bob", At: time.Now().UTC(), Reason: "needs rework"}
+	result, err := setTaskStatusInFile(tasksPath, "T001", second)
+	require.NoError(t, err)
+	assert.Equal(t, "Completed", result.previousStatus)
+
+	tasks, err := validation.GetAllTasks(tasksPath)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	require.Len(t, tasks[0].StatusHistory, 2)
+	assert.Equal(t, "bob", tasks[0].StatusHistory[1].By)
+	assert.True(t, tasks[0].IsManualOverride())
+
+	entries, err := os.ReadDir(filepath.Dir(tasksPath))
+	require.NoError(t, err)
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	assert.Equal(t, []string{"tasks.yaml", "tasks.yaml.bak"}, names, "temp files should not be left behind")
+}
+
+func TestValidateSetStatusArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		taskID  string
+		status  string
+		reason  string
+		wantErr string
+	}{
+		"valid":          {taskID: "T004", status: "Completed", reason: "done"},
+		"invalid id":     {taskID: "X1", status: "Completed", reason: "done", wantErr: "invalid task ID format"},
+		"invalid status": {taskID: "T004", status: "Done", reason: "done", wantErr: "invalid status"},
+		"empty reason":   {taskID: "T004", status: "Completed", reason: "", wantErr: "reason cannot be empty"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := validateSetStatusArgs(tt.taskID, tt.status, tt.reason)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ariel-frischer/autospec/internal/config"
 	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 	"github.com/ariel-frischer/autospec/internal/spec"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -46,7 +47,7 @@ func runTaskUnblock(cmd *cobra.Command, args []string) error {
 
 	// Validate task ID format
 	if !taskIDPattern.MatchString(taskID) {
-		return fmt.Errorf("invalid task ID format: %s (expected T followed by digits, e.g., T001)", taskID)
+		return fmt.Errorf("invalid task ID format: %s (expected T followed by digits, e.g., T001, or just the number)", taskID)
 	}
 
 	// Validate target status (only Pending or InProgress allowed for unblock)
@@ -88,6 +89,11 @@ func runTaskUnblock(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("parsing tasks.yaml: %w", err)
 	}
 
+	taskID, err = resolveTaskRef(&root, taskID, tasksPath)
+	if err != nil {
+		return err
+	}
+
 	// Find and unblock the task
 	result := findAndUnblockTask(&root, taskID, unblockStatus)
 	if !result.found {
@@ -106,7 +112,7 @@ func runTaskUnblock(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("serializing tasks.yaml: %w", err)
 	}
 
-	if err := os.WriteFile(tasksPath, output, 0o644); err != nil {
+	if err := fsutil.WriteArtifact(tasksPath, output, 0o644); err != nil {
 		return fmt.Errorf("writing tasks.yaml: %w", err)
 	}
 
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cli/util"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+// recordUsage sends the opt-in usage ping for cmd (see internal/telemetry).
+// Only the command path is sent, never arguments or flags.
+func recordUsage(cmd *cobra.Command, err error) {
+	name := usageCommandName(cmd)
+	if name == "" {
+		return
+	}
+	dir, dirErr := config.UserConfigDir()
+	if dirErr != nil {
+		return
+	}
+	telemetry.Record(context.Background(), dir, util.Version, name, err == nil)
+}
+
+// usageCommandName returns cmd's path without the root, e.g. "update
+// rollback", or "" for the root command and hidden commands such as shell
+// completion requests.
+func usageCommandName(cmd *cobra.Command) string {
+	if cmd == nil || !cmd.HasParent() {
+		return ""
+	}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if c.Hidden {
+			return ""
+		}
+	}
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsageCommandName(t *testing.T) {
+	t.Parallel()
+
+	root := &cobra.Command{Use: "autospec"}
+	update := &cobra.Command{Use: "update"}
+	rollback := &cobra.Command{Use: "rollback"}
+	complete := &cobra.Command{Use: "__complete", Hidden: true}
+	update.AddCommand(rollback)
+	root.AddCommand(update, complete)
+
+	assert.Equal(t, "", usageCommandName(nil))
+	assert.Equal(t, "", usageCommandName(root))
+	assert.Equal(t, "update", usageCommandName(update))
+	assert.Equal(t, "update rollback", usageCommandName(rollback))
+	assert.Equal(t, "", usageCommandName(complete), "hidden commands are not recorded")
+}
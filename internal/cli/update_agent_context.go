@@ -77,7 +77,7 @@ func runUpdateAgentContext(cmd *cobra.Command, args []string) error {
 	}
 	PrintSpecInfo(metadata)
 
-	specName := fmt.Sprintf("%s-%s", metadata.Number, metadata.Name)
+	specName := metadata.DirName()
 	planPath := filepath.Join(metadata.Directory, "plan.yaml")
 
 	planData, err := parseAgentPlanData(planPath)
@@ -8,7 +8,9 @@ import (
 
 	"github.com/ariel-frischer/autospec/internal/config"
 	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -16,8 +18,8 @@ import (
 // Valid task statuses
 var validStatuses = []string{"Pending", "InProgress", "Completed", "Blocked"}
 
-// taskIDPattern matches task IDs like T001, T1, T123
-var taskIDPattern = regexp.MustCompile(`^T\d+$`)
+// taskIDPattern matches task references like T001, T1, t1, or a bare task number like 4
+var taskIDPattern = regexp.MustCompile(`^[Tt]?\d+$`)
 
 var updateTaskCmd = &cobra.Command{
 	Use:   "update-task <task-id> <status>",
@@ -55,7 +57,7 @@ func runUpdateTask(cmd *cobra.Command, args []string) error {
 
 	// Validate task ID format
 	if !taskIDPattern.MatchString(taskID) {
-		return fmt.Errorf("invalid task ID format: %s (expected T followed by digits, e.g., T001)", taskID)
+		return fmt.Errorf("invalid task ID format: %s (expected T followed by digits, e.g., T001, or just the number)", taskID)
 	}
 
 	// Validate status
@@ -99,6 +101,11 @@ func runUpdateTask(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to parse tasks.yaml: %w", err)
 	}
 
+	taskID, err = resolveTaskRef(&root, taskID, tasksPath)
+	if err != nil {
+		return err
+	}
+
 	// Find and update the task
 	previousStatus, found := findAndUpdateTask(&root, taskID, newStatus)
 	if !found {
@@ -117,7 +124,7 @@ func runUpdateTask(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to serialize tasks.yaml: %w", err)
 	}
 
-	if err := os.WriteFile(tasksPath, output, 0o644); err != nil {
+	if err := fsutil.WriteArtifact(tasksPath, output, 0o644); err != nil {
 		return fmt.Errorf("failed to write tasks.yaml: %w", err)
 	}
 
@@ -190,3 +197,34 @@ func findAndUpdateTask(node *yaml.Node, taskID, newStatus string) (string, bool)
 
 	return "", false
 }
+
+// resolveTaskRef resolves a task reference ("T004", "t4" or "4") to the ID of a
+// task in the parsed tasks.yaml, suggesting close IDs when none matches.
+func resolveTaskRef(root *yaml.Node, ref, tasksPath string) (string, error) {
+	id, err := validation.ResolveTaskID(collectTaskIDs(root), ref)
+	if err != nil {
+		return "", fmt.Errorf("%w\nCheck that the task ID exists in: %s", err, tasksPath)
+	}
+	return id, nil
+}
+
+// collectTaskIDs returns the IDs of all task mappings (those with id and status) under node.
+func collectTaskIDs(node *yaml.Node) []string {
+	if node == nil {
+		return nil
+	}
+	var ids []string
+	if node.Kind == yaml.MappingNode {
+		if idNode := findNode(node, "id"); idNode != nil && findNode(node, "status") != nil {
+			ids = append(ids, idNode.Value)
+		}
+		for i := 1; i < len(node.Content); i += 2 {
+			ids = append(ids, collectTaskIDs(node.Content[i])...)
+		}
+		return ids
+	}
+	for _, child := range node.Content {
+		ids = append(ids, collectTaskIDs(child)...)
+	}
+	return ids
+}
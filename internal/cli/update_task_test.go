@@ -46,7 +46,8 @@ func TestTaskIDPattern(t *testing.T) {
 		"valid T1":             {taskID: "T1", want: true},
 		"valid T123":           {taskID: "T123", want: true},
 		"valid T99999":         {taskID: "T99999", want: true},
-		"invalid lowercase t":  {taskID: "t001", want: false},
+		"valid lowercase t":    {taskID: "t001", want: true},
+		"valid bare number":    {taskID: "4", want: true},
 		"invalid no number":    {taskID: "T", want: false},
 		"invalid with letters": {taskID: "T001a", want: false},
 		"invalid prefix":       {taskID: "Task001", want: false},
@@ -334,3 +335,33 @@ func TestFindAndUpdateTask_AllValidStatuses(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveTaskRef(t *testing.T) {
+	yamlContent := `
+phases:
+  - number: 1
+    tasks:
+      - id: T001
+        title: Setup
+        status: Completed
+      - id: T014
+        title: Wire handlers
+        status: Pending
+notes:
+  - id: N1
+    text: not a task
+`
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(yamlContent), &root))
+
+	assert.Equal(t, []string{"T001", "T014"}, collectTaskIDs(&root))
+
+	id, err := resolveTaskRef(&root, "14", "tasks.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "T014", id)
+
+	_, err = resolveTaskRef(&root, "T015", "tasks.yaml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did you mean T014?")
+	assert.Contains(t, err.Error(), "Check that the task ID exists in: tasks.yaml")
+}
@@ -0,0 +1,137 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var upgradeArtifactsCmd = &cobra.Command{
+	Use:   "upgrade-artifacts [spec...]",
+	Short: "Upgrade spec, plan and tasks artifacts to the current schema version",
+	Long: `Upgrade spec.yaml, plan.yaml and tasks.yaml files written for an older
+artifact schema (_meta.version) so they pass the current validators.
+
+Each upgraded file is rewritten in place; the original is kept next to it
+as <file>.v<old-version>.bak. Files already at the current version are left
+untouched, and unrecognized values are left for 'autospec artifact' to report.
+
+Without arguments, every spec in the specs directory is upgraded.
+Specs can be named by full name, number (003) or name suffix.`,
+	Example: `  # Preview what would change in all specs
+  autospec upgrade-artifacts --dry-run
+
+  # Upgrade every spec
+  autospec upgrade-artifacts
+
+  # Upgrade specific specs
+  autospec upgrade-artifacts 003 007-user-auth`,
+	RunE: runUpgradeArtifacts,
+}
+
+func init() {
+	upgradeArtifactsCmd.GroupID = GroupInternal
+	upgradeArtifactsCmd.Flags().Bool("dry-run", false, "Preview changes without applying")
+	rootCmd.AddCommand(upgradeArtifactsCmd)
+}
+
+func runUpgradeArtifacts(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	specDirs, err := resolveUpgradeSpecDirs(cfg.SpecsDir, args)
+	if err != nil {
+		return err
+	}
+	return upgradeSpecDirs(cmd.OutOrStdout(), specDirs, dryRun)
+}
+
+// resolveUpgradeSpecDirs returns the named spec directories, or all of them if none are named.
+func resolveUpgradeSpecDirs(specsDir string, names []string) ([]string, error) {
+	if len(names) > 0 {
+		dirs := make([]string, 0, len(names))
+		for _, name := range names {
+			dir, err := spec.GetSpecDirectory(specsDir, name)
+			if err != nil {
+				return nil, err
+			}
+			dirs = append(dirs, dir)
+		}
+		return dirs, nil
+	}
+
+	if _, err := os.ReadDir(specsDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading specs directory: %w", err)
+	}
+	var dirs []string
+	for _, name := range spec.ListSpecNames(specsDir) {
+		dirs = append(dirs, filepath.Join(specsDir, name))
+	}
+	return dirs, nil
+}
+
+// upgradeSpecDirs upgrades each spec directory and prints a per-file report.
+// All specs are attempted; an error is returned if any artifact could not be upgraded.
+func upgradeSpecDirs(out io.Writer, specDirs []string, dryRun bool) error {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	if dryRun {
+		fmt.Fprintf(out, "%s Dry run - no changes made\n\n", dim("→"))
+	}
+
+	var upgraded, current, failed int
+	for _, dir := range specDirs {
+		upgrades, err := validation.UpgradeSpecDir(dir, dryRun)
+		for _, u := range upgrades {
+			if !u.NeedsUpgrade() {
+				current++
+				continue
+			}
+			upgraded++
+			fmt.Fprintf(out, "%s %s: %s -> %s\n", yellow("→"), u.Path, u.FromVersion, u.ToVersion)
+			for _, change := range u.Changes {
+				fmt.Fprintf(out, "  ~ %s\n", change)
+			}
+			if u.BackupPath != "" {
+				fmt.Fprintf(out, "  %s\n", dim("backup: "+u.BackupPath))
+			}
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintf(out, "%s %v\n", red("✗"), err)
+		}
+	}
+
+	verb := "Upgraded"
+	if dryRun {
+		verb = "Would upgrade"
+	}
+	fmt.Fprintf(out, "\n%s %s %d artifact(s), %d already at %s\n",
+		green("✓"), verb, upgraded, current, validation.CurrentArtifactVersion)
+
+	if failed > 0 {
+		return fmt.Errorf("%d spec(s) could not be upgraded", failed)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Package cli_test tests the upgrade-artifacts command.
+// Related: internal/cli/upgrade_artifacts.go
+// Tags: cli, upgrade, artifacts, migration, meta, version
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveUpgradeSpecDirs(t *testing.T) {
+	specsDir := t.TempDir()
+	for _, name := range []string{"001-alpha", "002-beta"} {
+		if err := os.MkdirAll(filepath.Join(specsDir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(specsDir, "README.md"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := resolveUpgradeSpecDirs(specsDir, nil)
+	if err != nil || len(all) != 2 {
+		t.Fatalf("resolveUpgradeSpecDirs(all) = %v, %v; want 2 directories", all, err)
+	}
+
+	named, err := resolveUpgradeSpecDirs(specsDir, []string{"002"})
+	if err != nil || len(named) != 1 || filepath.Base(named[0]) != "002-beta" {
+		t.Errorf("resolveUpgradeSpecDirs(002) = %v, %v; want [002-beta]", named, err)
+	}
+
+	if _, err := resolveUpgradeSpecDirs(specsDir, []string{"999"}); err == nil {
+		t.Error("expected error for unknown spec")
+	}
+
+	missing, err := resolveUpgradeSpecDirs(filepath.Join(specsDir, "nope"), nil)
+	if err != nil || len(missing) != 0 {
+		t.Errorf("missing specs dir = %v, %v; want no directories and no error", missing, err)
+	}
+}
+
+func TestUpgradeSpecDirs(t *testing.T) {
+	specsDir := t.TempDir()
+	oldDir := filepath.Join(specsDir, "001-old")
+	badDir := filepath.Join(specsDir, "002-bad")
+	for _, dir := range []string{oldDir, badDir} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	specPath := filepath.Join(oldDir, "spec.yaml")
+	if err := os.WriteFile(specPath, []byte("feature:\n  status: approved\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(badDir, "plan.yaml"), []byte("_meta:\n  version: \"9.0.0\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err := upgradeSpecDirs(&out, []string{oldDir, badDir}, false)
+	if err == nil || !strings.Contains(err.Error(), "1 spec(s) could not be upgraded") {
+		t.Errorf("upgradeSpecDirs() error = %v, want one failed spec", err)
+	}
+
+	for _, want := range []string{
+		"feature.status: approved -> Approved",
+		"backup: " + specPath + ".v1.0.0.bak",
+		"newer than this autospec supports",
+		"Upgraded 1 artifact(s)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
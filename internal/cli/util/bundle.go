@@ -0,0 +1,154 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/bundle"
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [spec-name]",
+	Short: "Package a spec into a shareable .tar.gz bundle",
+	Long: `Package a spec into a .tar.gz bundle that can be imported into another
+repository or attached to a support ticket.
+
+The bundle contains:
+  - Every file in the spec directory (spec, plan, tasks, checklists, transcripts)
+  - The spec's checkpoints: retry counts and phase/task progress
+  - The spec's entries from the run event logs
+  - A manifest with the bundle format and each artifact's schema version
+
+Event logs and transcripts are redacted when they are recorded, but review
+the bundle before sharing it outside your team.`,
+	Example: `  # Export the current spec
+  autospec export
+
+  # Export a spec to a specific file
+  autospec export 003-auth --out auth-bundle.tar.gz`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runExport,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <bundle.tar.gz>",
+	Short: "Import a spec bundle created by 'autospec export'",
+	Long: `Import a spec bundle created by 'autospec export' into the specs directory,
+restoring its checkpoints and run event logs into the state directory.
+
+If the spec's number is already used, the spec gets the next free number and
+references to its old name in the YAML artifacts are rewritten. Bundles with
+artifacts from a newer autospec are rejected; older artifacts are imported
+as-is and can be brought up to date with 'autospec upgrade-artifacts'.
+
+The spec's .autospec.yaml (per-spec retries, gates and prompts) is skipped
+unless --with-spec-config is given, since it changes how commands run on the
+spec. Review the bundle's file before importing it.`,
+	Example: `  # Import a bundle
+  autospec import auth-bundle.tar.gz
+
+  # Also import the spec's .autospec.yaml
+  autospec import auth-bundle.tar.gz --with-spec-config`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE:         runImport,
+}
+
+func init() {
+	exportCmd.GroupID = shared.GroupConfiguration
+	exportCmd.Flags().StringP("out", "o", "", "Bundle file to write (default: <spec>.tar.gz)")
+	importCmd.GroupID = shared.GroupConfiguration
+	importCmd.Flags().Bool("with-spec-config", false, "Also import the spec's .autospec.yaml (skipped by default)")
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	metadata, err := resolveReportSpec(cfg.SpecsDir, args)
+	if err != nil {
+		return fmt.Errorf("detecting spec: %w", err)
+	}
+
+	outPath, _ := cmd.Flags().GetString("out")
+	if outPath == "" {
+		outPath = filepath.Base(metadata.DirName()) + ".tar.gz"
+	}
+	f, err := os.Create(outPath)
+	if err != nil {
+		return fmt.Errorf("creating bundle file: %w", err)
+	}
+	m, err := bundle.Export(f, metadata.Directory, cfg.StateDir)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(outPath)
+		return fmt.Errorf("exporting %s: %w", metadata.Directory, err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "✓ Exported %s to %s\n", m.Spec, outPath)
+	fmt.Fprintf(out, "  %d file(s), %d run log(s), checkpoints: %s\n", len(m.Files), len(m.Runs), yesNo(m.Checkpoints))
+	return nil
+}
+
+func runImport(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("opening bundle: %w", err)
+	}
+	defer f.Close()
+
+	withSpecConfig, _ := cmd.Flags().GetBool("with-spec-config")
+	res, err := bundle.Import(f, cfg.SpecsDir, cfg.StateDir, bundle.ImportOptions{SpecConfig: withSpecConfig})
+	if err != nil {
+		return fmt.Errorf("importing %s: %w", args[0], err)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "✓ Imported %s to %s\n", res.Manifest.Spec, res.SpecDir)
+	if res.Renumbered {
+		fmt.Fprintf(out, "  %s was taken; renumbered to %s\n", res.Manifest.Spec, res.SpecName)
+	}
+	fmt.Fprintf(out, "  checkpoints: %s, %d run log(s) added\n", yesNo(res.Checkpoints), len(res.Runs))
+	if len(res.Outdated) > 0 {
+		fmt.Fprintf(out, "  %s use an older schema; run 'autospec upgrade-artifacts %s'\n",
+			strings.Join(res.Outdated, ", "), res.SpecName)
+	}
+	if res.SkippedSpecConfig {
+		output.Default().Warn("skipped the bundle's %s; review it and copy it in by hand, or import with --with-spec-config", config.SpecConfigFile)
+	}
+	if res.SpecConfig {
+		output.Default().Warn("imported %s: it changes how commands run on %s", config.SpecConfigFile, res.SpecName)
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
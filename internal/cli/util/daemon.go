@@ -0,0 +1,106 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/daemon"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+var daemonCmd = &cobra.Command{
+	Use:   "daemon",
+	Short: "Run a local API server for editors and dashboards",
+	Long: `Run autospec as a long-lived daemon exposing a local HTTP API.
+
+By default the API listens on a Unix socket at <state_dir>/daemon.sock
+(readable only by the current user). Use --addr to listen on a loopback
+TCP address instead; non-loopback addresses are rejected.
+
+Every endpoint but /v1/health requires the bearer token stored in
+<state_dir>/daemon.token (generated on first start, readable only by the
+current user). POST /v1/runs requires Content-Type: application/json, and
+requests with an Origin header or a non-loopback Host are refused.
+
+Runs are queued and executed one at a time, so concurrent clients never
+race on the same specs or state directory.
+
+Endpoints:
+  GET  /v1/health             Daemon liveness and version
+  GET  /v1/runs               List runs (most recent first)
+  POST /v1/runs               Start a run: {"args": ["implement", "--tasks"], "dir": "/path/to/repo"}
+  GET  /v1/runs/{id}          Run status
+  GET  /v1/runs/{id}/logs     Run output; add ?follow=true to stream until the run ends
+  POST /v1/runs/{id}/cancel   Cancel a queued or running run
+  GET  /metrics               Prometheus metrics for all runs in the state directory`,
+	Example: `  # Start the daemon on the default Unix socket
+  autospec daemon
+
+  # Listen on localhost TCP instead
+  autospec daemon --addr 127.0.0.1:7317
+
+  # Start a run and follow its output (from another terminal)
+  TOKEN=$(cat ~/.autospec/state/daemon.token)
+  curl --unix-socket ~/.autospec/state/daemon.sock -X POST localhost/v1/runs \
+    -H "Authorization: Bearer $TOKEN" -H "Content-Type: application/json" \
+    -d '{"args": ["implement"], "dir": "'"$PWD"'"}'
+  curl --unix-socket ~/.autospec/state/daemon.sock -H "Authorization: Bearer $TOKEN" \
+    "localhost/v1/runs/<id>/logs?follow=true"`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDaemon,
+}
+
+func init() {
+	daemonCmd.GroupID = shared.GroupConfiguration
+	daemonCmd.Flags().String("addr", "", "Listen on a loopback TCP address (e.g., 127.0.0.1:7317) instead of a Unix socket")
+	daemonCmd.Flags().String("socket", "", "Unix socket path (default: <state_dir>/daemon.sock)")
+}
+
+func runDaemon(cmd *cobra.Command, _ []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	addr, _ := cmd.Flags().GetString("addr")
+	socketPath, _ := cmd.Flags().GetString("socket")
+	if socketPath == "" {
+		socketPath = filepath.Join(cfg.StateDir, daemon.SocketFileName)
+	}
+
+	tokenPath := filepath.Join(cfg.StateDir, daemon.TokenFileName)
+	token, err := daemon.LoadOrCreateToken(tokenPath)
+	if err != nil {
+		return fmt.Errorf("starting daemon: %w", err)
+	}
+
+	ln, err := daemon.Listen(addr, socketPath)
+	if err != nil {
+		return fmt.Errorf("starting daemon: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(cmd.OutOrStdout(), "autospec daemon listening on %s (Ctrl+C to stop)\n", ln.Addr())
+	fmt.Fprintf(cmd.OutOrStdout(), "API token: %s\n", tokenPath)
+	server := daemon.NewServer(daemon.NewManager(nil), Version, token)
+	server.Metrics = metrics.NewCollector(cfg.StateDir, Version)
+	if err := server.Serve(ctx, ln); err != nil {
+		return fmt.Errorf("serving daemon API: %w", err)
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "autospec daemon stopped")
+	return nil
+}
@@ -0,0 +1,99 @@
+package util
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/explain"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain [run-id]",
+	Short: "Explain why the last validation failed",
+	Long: `Break down the last failure of a run as a decision tree.
+
+The failure is read from the run's event log. The stage's validator is then
+run again against the artifacts on disk, and each error is shown with:
+  - which validator failed and which file it checked
+  - which field failed and what the schema expects
+  - what the agent produced
+  - the offending YAML with line numbers
+
+If the artifacts were fixed after the failure, the errors recorded at the
+time are shown instead. Without a run ID, the most recent run is used.`,
+	Example: `  # Explain the last failure of the most recent run
+  autospec explain
+
+  # Explain a specific run (see 'autospec replay --list')
+  autospec explain brave_falcon_20250115_143022`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runExplain,
+}
+
+func init() {
+	explainCmd.GroupID = shared.GroupConfiguration
+}
+
+func runExplain(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	return runExplainWithDirs(cmd, cfg.StateDir, cfg.SpecsDir, args)
+}
+
+// runExplainWithDirs runs the explain command against specific state and specs directories.
+func runExplainWithDirs(cmd *cobra.Command, stateDir, specsDir string, args []string) error {
+	out := cmd.OutOrStdout()
+
+	runID, err := resolveRunID(stateDir, args)
+	if err != nil {
+		return err
+	}
+	evs, err := events.Load(stateDir, runID)
+	if err != nil && !encrypt.Partial(err) {
+		return fmt.Errorf("loading events: %w", err)
+	}
+	if err != nil {
+		output.Default().Warn("%v", err)
+	}
+
+	failure := explain.LastFailure(evs)
+	if failure == nil {
+		fmt.Fprintf(out, "No failures recorded in run %s.\n", runID)
+		return nil
+	}
+
+	specDir, err := explainSpecDir(failure, specsDir)
+	if err != nil {
+		return err
+	}
+	explain.Render(out, explain.Explain(failure, specDir, "."))
+	return nil
+}
+
+// explainSpecDir returns the spec directory of the failed stage. Specify runs
+// before the spec name is known, so its events carry no spec; the current spec
+// is detected instead.
+func explainSpecDir(f *explain.Failure, specsDir string) (string, error) {
+	if f.Spec != "" {
+		return filepath.Join(specsDir, f.Spec), nil
+	}
+	metadata, err := spec.DetectCurrentSpec(specsDir)
+	if err != nil {
+		return "", fmt.Errorf("detecting spec for %s failure: %w", f.Stage, err)
+	}
+	return metadata.Directory, nil
+}
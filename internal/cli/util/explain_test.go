@@ -0,0 +1,73 @@
+// Package util tests the explain command.
+// Related: internal/cli/util/explain.go
+// Tags: util, cli, explain, validation, events
+
+package util
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newExplainTestCmd(out *bytes.Buffer) *cobra.Command {
+	cmd := &cobra.Command{Use: "explain"}
+	cmd.SetOut(out)
+	return cmd
+}
+
+func TestRunExplain(t *testing.T) {
+	stateDir := t.TempDir()
+	specsDir := t.TempDir()
+	specDir := filepath.Join(specsDir, "001-test")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+
+	rec := events.NewRecorder(stateDir, "brave_falcon_20250115_143022")
+	rec.Record(events.Event{Type: events.TypeStageStart, Spec: "001-test", Stage: "tasks", Attempt: 1})
+	rec.Record(events.Event{Type: events.TypeValidationFailed, Spec: "001-test", Stage: "tasks", Attempt: 1,
+		Errors: []string{"tasks.yaml: file not found"}})
+
+	var out bytes.Buffer
+	require.NoError(t, runExplainWithDirs(newExplainTestCmd(&out), stateDir, specsDir, nil))
+
+	got := out.String()
+	assert.Contains(t, got, "Run:     brave_falcon_20250115_143022")
+	assert.Contains(t, got, "validation failed in stage tasks (spec 001-test, attempt 1)")
+	assert.Contains(t, got, "✗ tasks schema validator: "+filepath.Join(specDir, "tasks.yaml"))
+	assert.Contains(t, got, "File not found")
+}
+
+func TestRunExplain_NoFailures(t *testing.T) {
+	stateDir := t.TempDir()
+	rec := events.NewRecorder(stateDir, "calm_otter_20250115_143022")
+	rec.Record(events.Event{Type: events.TypeStageComplete, Spec: "001-test", Stage: "plan"})
+
+	var out bytes.Buffer
+	require.NoError(t, runExplainWithDirs(newExplainTestCmd(&out), stateDir, t.TempDir(), []string{"calm_otter_20250115_143022"}))
+	assert.Contains(t, out.String(), "No failures recorded in run calm_otter_20250115_143022.")
+}
+
+func TestRunExplain_NoRuns(t *testing.T) {
+	var out bytes.Buffer
+	err := runExplainWithDirs(newExplainTestCmd(&out), t.TempDir(), t.TempDir(), nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no recorded runs")
+}
+
+func TestResolveRunID_RejectsPaths(t *testing.T) {
+	t.Parallel()
+
+	for _, runID := range []string{"../outside", "/etc", "runs/x", ""} {
+		_, err := resolveRunID(t.TempDir(), []string{runID})
+		assert.ErrorContains(t, err, "is not a plain name", runID)
+	}
+	runID, err := resolveRunID(t.TempDir(), []string{"brave_falcon_20250115_143022"})
+	require.NoError(t, err)
+	assert.Equal(t, "brave_falcon_20250115_143022", runID)
+}
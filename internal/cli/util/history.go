@@ -30,6 +30,11 @@ func init() {
 	historyCmd.Flags().String("status", "", "Filter by status (running, completed, failed, cancelled)")
 }
 
+// historyResult is the --json result of history.
+type historyResult struct {
+	Entries []history.HistoryEntry `json:"entries"`
+}
+
 // getDefaultStateDir returns the default state directory path.
 func getDefaultStateDir() string {
 	home, err := os.UserHomeDir()
@@ -68,6 +73,12 @@ func runHistoryWithStateDir(cmd *cobra.Command, stateDir string) error {
 
 	// Get filtered entries
 	entries := filterEntries(histFile.Entries, specFilter, statusFilter, limit)
+	if useJSON, _ := cmd.Flags().GetBool("json"); useJSON {
+		if entries == nil {
+			entries = []history.HistoryEntry{}
+		}
+		return shared.WriteJSON(cmd, historyResult{Entries: entries})
+	}
 
 	// Handle empty result
 	if len(entries) == 0 {
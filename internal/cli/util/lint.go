@@ -0,0 +1,94 @@
+package util
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [spec-name]",
+	Short: "Check spec.yaml and tasks.yaml for weak spots the schema allows",
+	Long: `Lint a spec's artifacts for quality problems that pass schema validation
+but make weak input for the next stage:
+
+  story_acceptance     user story without acceptance scenarios (warning)
+  task_acceptance      task without acceptance criteria (warning)
+  requirement_wording  functional requirement without MUST, SHOULD or MAY (warning)
+  phase_size           phase with more than lint.max_phase_tasks tasks (info)
+
+Severities are configured per rule in the lint config section as error,
+warning, info or off. The command exits 1 when there is an error finding,
+or with --strict, a warning.
+
+Run 'autospec artifact' first: lint expects artifacts that pass the schema.`,
+	Example: `  # Lint the current spec
+  autospec lint
+
+  # Lint a specific spec, failing on warnings too
+  autospec lint 003-auth --strict
+
+  # .autospec/config.yml
+  lint:
+    max_phase_tasks: 8
+    rules:
+      task_acceptance: error
+      phase_size: off`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runLint,
+}
+
+func init() {
+	lintCmd.GroupID = shared.GroupGettingStarted
+	lintCmd.Flags().Bool("strict", false, "Exit 1 on warnings as well as errors")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	metadata, err := resolveReportSpec(cfg.SpecsDir, args)
+	if err != nil {
+		return fmt.Errorf("detecting spec: %w", err)
+	}
+
+	findings, err := validation.LintSpecDir(metadata.Directory, cfg.Lint)
+	if err != nil {
+		return fmt.Errorf("linting %s: %w", metadata.Directory, err)
+	}
+	strict, _ := cmd.Flags().GetBool("strict")
+	return printLintFindings(cmd.OutOrStdout(), metadata.Directory, findings, strict)
+}
+
+// printLintFindings lists the findings with a summary and returns an exit
+// error when an error finding, or with strict a warning, was reported.
+func printLintFindings(out io.Writer, specDir string, findings []validation.LintFinding, strict bool) error {
+	if len(findings) == 0 {
+		fmt.Fprintf(out, "✓ No lint findings in %s\n", specDir)
+		return nil
+	}
+
+	fmt.Fprintf(out, "Linting %s\n", specDir)
+	for _, f := range findings {
+		fmt.Fprintf(out, "  %s\n", f)
+	}
+	counts := validation.CountLintFindings(findings)
+	fmt.Fprintf(out, "\n%d error(s), %d warning(s), %d info\n",
+		counts[validation.LintError], counts[validation.LintWarning], counts[validation.LintInfo])
+
+	if counts[validation.LintError] > 0 || (strict && counts[validation.LintWarning] > 0) {
+		return shared.NewExitError(shared.ExitValidationFailed)
+	}
+	return nil
+}
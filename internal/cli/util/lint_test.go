@@ -0,0 +1,48 @@
+// Package util tests the lint command.
+// Related: internal/cli/util/lint.go
+// Tags: util, cli, lint, validation
+
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintLintFindings(t *testing.T) {
+	t.Parallel()
+
+	warning := validation.LintFinding{Rule: validation.RuleTaskAcceptance, Severity: validation.LintWarning, File: "tasks.yaml", Line: 7, Message: "task T002 has no acceptance criteria"}
+	failure := warning
+	failure.Severity = validation.LintError
+
+	tests := map[string]struct {
+		findings     []validation.LintFinding
+		strict       bool
+		wantExit     bool
+		wantContains string
+	}{
+		"no findings":       {wantContains: "No lint findings"},
+		"warning":           {findings: []validation.LintFinding{warning}, wantContains: "tasks.yaml:7: warning: task T002"},
+		"warning in strict": {findings: []validation.LintFinding{warning}, strict: true, wantExit: true, wantContains: "1 warning(s)"},
+		"error":             {findings: []validation.LintFinding{failure}, wantExit: true, wantContains: "1 error(s)"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			err := printLintFindings(&out, "specs/001-lint", tt.findings, tt.strict)
+			assert.Contains(t, out.String(), tt.wantContains)
+			if tt.wantExit {
+				assert.Equal(t, shared.ExitValidationFailed, shared.ExitCode(err))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/logging"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var logsCmd = &cobra.Command{
+	Use:   "logs [file]",
+	Short: "Print the diagnostic log or another autospec log, decrypted",
+	Long: `Print <state_dir>/logs/autospec.log, the log file written when
+logging.file is enabled.
+
+With a file argument, print that file instead: a rotated log
+(autospec.log.1), a run's events.jsonl, or a stage transcript
+(specs/<spec>/transcript-<stage>.md).
+
+Lines encrypted with encryption.enabled are decrypted with the configured
+key (AUTOSPEC_ENCRYPTION_KEY or encryption.key_command); plain lines are
+printed as they are.`,
+	Example: `  # Print the diagnostic log
+  autospec logs
+
+  # The last 50 lines
+  autospec logs -n 50
+
+  # Read an encrypted transcript
+  autospec logs specs/001-login/transcript-plan.md`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runLogs,
+}
+
+func init() {
+	logsCmd.GroupID = shared.GroupConfiguration
+	logsCmd.Flags().IntP("lines", "n", 0, "Print only the last N lines (0 = all)")
+}
+
+func runLogs(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	return runLogsWithStateDir(cmd, cfg.StateDir, args)
+}
+
+// runLogsWithStateDir runs the logs command against a specific state directory.
+func runLogsWithStateDir(cmd *cobra.Command, stateDir string, args []string) error {
+	path := logging.FilePath(stateDir)
+	if len(args) > 0 {
+		path = args[0]
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) && len(args) == 0 {
+			return fmt.Errorf("no log file at %s (enable it with: autospec config set logging.file true)", path)
+		}
+		return fmt.Errorf("reading log: %w", err)
+	}
+
+	data, err = encrypt.OpenLines(data)
+	if err != nil && !encrypt.Partial(err) {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err != nil {
+		output.Default().Warn("reading %s: %v", path, err)
+	}
+	lines, _ := cmd.Flags().GetInt("lines")
+	_, err = cmd.OutOrStdout().Write(lastLines(data, lines))
+	return err
+}
+
+// lastLines returns the last n lines of data, or all of it when n <= 0.
+func lastLines(data []byte, n int) []byte {
+	if n <= 0 {
+		return data
+	}
+	end := len(bytes.TrimRight(data, "\n"))
+	for i := end - 1; i >= 0; i-- {
+		if data[i] == '\n' {
+			n--
+			if n == 0 {
+				return data[i+1:]
+			}
+		}
+	}
+	return data
+}
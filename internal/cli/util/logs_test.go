@@ -0,0 +1,95 @@
+// Package util tests the logs command.
+// Related: internal/cli/util/logs.go
+// Tags: util, cli, logs, encryption
+
+package util
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/logging"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLogsTestCmd(out *bytes.Buffer, lines int) *cobra.Command {
+	cmd := &cobra.Command{Use: "logs"}
+	cmd.Flags().IntP("lines", "n", lines, "")
+	cmd.SetOut(out)
+	return cmd
+}
+
+func TestRunLogs(t *testing.T) {
+	// Not parallel: sets the encryption key
+	key := bytes.Repeat([]byte{1}, encrypt.KeySize)
+	t.Setenv(encrypt.DefaultKeyEnv, hex.EncodeToString(key))
+	encrypt.Set(encrypt.Config{})
+	t.Cleanup(func() { encrypt.Set(encrypt.Config{}) })
+	cipher, err := encrypt.NewCipher(key)
+	require.NoError(t, err)
+
+	stateDir := t.TempDir()
+	logPath := logging.FilePath(stateDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(logPath), 0o755))
+	var data []byte
+	data = append(data, "plain first\n"...)
+	for _, line := range []string{"secret second", "secret third"} {
+		data = append(append(data, cipher.Seal([]byte(line))...), '\n')
+	}
+	require.NoError(t, os.WriteFile(logPath, data, 0o644))
+
+	var out bytes.Buffer
+	require.NoError(t, runLogsWithStateDir(newLogsTestCmd(&out, 0), stateDir, nil))
+	assert.Equal(t, "plain first\nsecret second\nsecret third\n", out.String())
+
+	out.Reset()
+	require.NoError(t, runLogsWithStateDir(newLogsTestCmd(&out, 2), stateDir, nil))
+	assert.Equal(t, "secret second\nsecret third\n", out.String())
+
+	transcript := filepath.Join(t.TempDir(), "transcript-plan.md")
+	require.NoError(t, os.WriteFile(transcript, append(cipher.Seal([]byte("# Transcript: plan\n")), '\n'), 0o644))
+	out.Reset()
+	require.NoError(t, runLogsWithStateDir(newLogsTestCmd(&out, 0), stateDir, []string{transcript}))
+	assert.Equal(t, "# Transcript: plan\n", out.String())
+
+	t.Setenv(encrypt.DefaultKeyEnv, "")
+	encrypt.Set(encrypt.Config{})
+	err = runLogsWithStateDir(newLogsTestCmd(&out, 0), stateDir, nil)
+	assert.ErrorIs(t, err, encrypt.ErrNoKey)
+}
+
+func TestRunLogs_NoLogFile(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	err := runLogsWithStateDir(newLogsTestCmd(&out, 0), t.TempDir(), nil)
+	assert.ErrorContains(t, err, "logging.file")
+}
+
+func TestLastLines(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		data string
+		n    int
+		want string
+	}{
+		"all":              {data: "a\nb\nc\n", n: 0, want: "a\nb\nc\n"},
+		"last two":         {data: "a\nb\nc\n", n: 2, want: "b\nc\n"},
+		"more than all":    {data: "a\nb\n", n: 5, want: "a\nb\n"},
+		"no final newline": {data: "a\nb\nc", n: 1, want: "c"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, string(lastLines([]byte(tt.data), tt.n)))
+		})
+	}
+}
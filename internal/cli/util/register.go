@@ -1,5 +1,5 @@
 // Package util provides utility CLI commands for autospec.
-// Includes: status, history, version, clean, worktree
+// Includes: status, resume, pause, history, version, clean, report, render, lint, export, import, replay, logs, explain, stats, daemon, serve, telemetry, worktree
 package util
 
 import (
@@ -11,13 +11,27 @@ import (
 // This function is called from the root CLI package during initialization.
 func Register(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(pauseCmd)
 	rootCmd.AddCommand(historyCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(sauceCmd)
 	rootCmd.AddCommand(cleanCmd)
 	rootCmd.AddCommand(viewCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(renderCmd)
+	rootCmd.AddCommand(lintCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(logsCmd)
+	rootCmd.AddCommand(explainCmd)
+	rootCmd.AddCommand(statsCmd)
+	rootCmd.AddCommand(daemonCmd)
+	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(ckCmd)
+	rootCmd.AddCommand(telemetryCmd)
 	rootCmd.AddCommand(worktree.WorktreeCmd)
 
 	// Experimental: DAG command only available in dev builds
@@ -35,11 +35,23 @@ func TestRegister(t *testing.T) {
 
 	// Should have status, history, version, sauce, clean, view, worktree, ck commands
 	assert.True(t, commandNames["status"], "Should have 'status' command")
+	assert.True(t, commandNames["resume"], "Should have 'resume' command")
+	assert.True(t, commandNames["pause"], "Should have 'pause' command")
 	assert.True(t, commandNames["history"], "Should have 'history' command")
 	assert.True(t, commandNames["version"], "Should have 'version' command")
 	assert.True(t, commandNames["sauce"], "Should have 'sauce' command")
 	assert.True(t, commandNames["clean"], "Should have 'clean' command")
 	assert.True(t, commandNames["view"], "Should have 'view' command")
+	assert.True(t, commandNames["report"], "Should have 'report' command")
+	assert.True(t, commandNames["render"], "Should have 'render' command")
+	assert.True(t, commandNames["export"], "Should have 'export' command")
+	assert.True(t, commandNames["import"], "Should have 'import' command")
+	assert.True(t, commandNames["replay"], "Should have 'replay' command")
+	assert.True(t, commandNames["logs"], "Should have 'logs' command")
+	assert.True(t, commandNames["explain"], "Should have 'explain' command")
+	assert.True(t, commandNames["stats"], "Should have 'stats' command")
+	assert.True(t, commandNames["daemon"], "Should have 'daemon' command")
+	assert.True(t, commandNames["serve"], "Should have 'serve' command")
 	assert.True(t, commandNames["worktree"], "Should have 'worktree' command")
 	assert.True(t, commandNames["ck"], "Should have 'ck' command")
 }
@@ -111,8 +123,8 @@ func TestRegister_CommandCount(t *testing.T) {
 
 	Register(rootCmd)
 
-	// Should register exactly 10 commands (status, history, version, update, sauce, clean, view, dag, worktree, ck)
-	assert.Equal(t, 10, len(rootCmd.Commands()))
+	// Should register exactly 24 commands (status, resume, pause, history, version, update, sauce, clean, view, report, render, lint, export, import, replay, logs, explain, stats, daemon, serve, dag, worktree, ck, telemetry)
+	assert.Equal(t, 24, len(rootCmd.Commands()))
 }
 
 func TestStatusCmd_Structure(t *testing.T) {
@@ -0,0 +1,115 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/spf13/cobra"
+)
+
+var renderCmd = &cobra.Command{
+	Use:   "render [spec-name]",
+	Short: "Regenerate spec.md, plan.md, tasks.md, ... from the YAML artifacts",
+	Long: `Render markdown views of a spec's YAML artifacts for teams that prefer
+reading and reviewing markdown.
+
+Each YAML artifact in the spec directory gets a markdown file next to it:
+spec.yaml → spec.md, plan.yaml → plan.md, tasks.yaml → tasks.md,
+analysis.yaml → analysis.md and checklists/*.yaml → checklists/*.md.
+
+The YAML stays the source of truth. Rendered files start with a
+"Generated by autospec" comment and are regenerated, never read back, so
+edit the YAML and render again. Markdown files without that comment (e.g.
+a spec.md written by hand) are left alone unless --force is given.
+
+Set markdown_views: true to render the views after every stage.
+
+With --check nothing is written; the command exits 1 when a view is
+missing or out of date, for use in CI or pre-commit hooks.`,
+	Example: `  # Render the views of the current spec
+  autospec render
+
+  # Render a specific spec
+  autospec render 003-auth
+
+  # Fail when the markdown does not match the YAML
+  autospec render --check`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runRender,
+}
+
+func init() {
+	renderCmd.GroupID = shared.GroupGettingStarted
+	renderCmd.Flags().Bool("check", false, "Report missing or stale views without writing (exit 1 if any)")
+	renderCmd.Flags().Bool("force", false, "Replace markdown files not generated by autospec")
+}
+
+func runRender(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	metadata, err := resolveReportSpec(cfg.SpecsDir, args)
+	if err != nil {
+		return fmt.Errorf("detecting spec: %w", err)
+	}
+
+	check, _ := cmd.Flags().GetBool("check")
+	if check {
+		return checkMarkdownViews(metadata.Directory, cmd.OutOrStdout())
+	}
+	force, _ := cmd.Flags().GetBool("force")
+	return writeMarkdownViews(metadata.Directory, force, cmd.OutOrStdout())
+}
+
+// writeMarkdownViews renders the spec's views and lists what changed.
+func writeMarkdownViews(specDir string, force bool, out io.Writer) error {
+	written, skipped, err := validation.WriteMarkdownViews(specDir, force)
+	for _, path := range written {
+		fmt.Fprintf(out, "✓ Rendered %s\n", path)
+	}
+	if err != nil {
+		return fmt.Errorf("rendering markdown views: %w", err)
+	}
+	for _, path := range skipped {
+		fmt.Fprintf(out, "⚠ Skipped %s: not generated by autospec (use --force to replace it)\n", path)
+	}
+	if len(written) == 0 && len(skipped) == 0 {
+		fmt.Fprintf(out, "✓ Markdown views in %s are up to date\n", specDir)
+	}
+	return nil
+}
+
+// checkMarkdownViews reports views that do not match their YAML source.
+func checkMarkdownViews(specDir string, out io.Writer) error {
+	views := validation.MarkdownViews(specDir)
+	outdated := 0
+	for _, view := range views {
+		state, err := view.Check()
+		if err != nil {
+			return fmt.Errorf("checking %s: %w", view.Path, err)
+		}
+		if state == validation.MarkdownViewCurrent {
+			continue
+		}
+		outdated++
+		fmt.Fprintf(out, "✗ %s is %s (source: %s)\n", view.Path, state, filepath.Base(view.Source))
+	}
+
+	if outdated > 0 {
+		fmt.Fprintf(out, "\n%d of %d markdown views do not match the YAML. Run 'autospec render' to regenerate them.\n", outdated, len(views))
+		return shared.NewExitError(shared.ExitValidationFailed)
+	}
+	fmt.Fprintf(out, "✓ %d markdown views match the YAML\n", len(views))
+	return nil
+}
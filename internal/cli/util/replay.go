@@ -0,0 +1,132 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/spf13/cobra"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [run-id]",
+	Short: "Replay the event log of a previous run",
+	Long: `Reconstruct and pretty-print the timeline of a previous run.
+
+Every workflow run records its orchestrator decisions to
+<state_dir>/runs/<run-id>/events.jsonl: stage transitions, the command
+built for the agent, validation results (with errors), retries and the
+reason for them, and task command outcomes.
+
+Without a run ID, the most recent run is replayed. Encrypted event logs
+(see encryption in the config) are decrypted with the configured key.`,
+	Example: `  # Replay the most recent run
+  autospec replay
+
+  # List recorded runs
+  autospec replay --list
+
+  # Replay a specific run
+  autospec replay brave_falcon_20250115_143022
+
+  # Print the raw JSONL event log
+  autospec replay brave_falcon_20250115_143022 --raw`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runReplay,
+}
+
+func init() {
+	replayCmd.GroupID = shared.GroupConfiguration
+	replayCmd.Flags().Bool("list", false, "List recorded runs (most recent first)")
+	replayCmd.Flags().Bool("raw", false, "Print the raw JSONL event log")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	return runReplayWithStateDir(cmd, cfg.StateDir, args)
+}
+
+// runReplayWithStateDir runs the replay command against a specific state directory.
+func runReplayWithStateDir(cmd *cobra.Command, stateDir string, args []string) error {
+	out := cmd.OutOrStdout()
+
+	if list, _ := cmd.Flags().GetBool("list"); list {
+		return listRuns(out, stateDir)
+	}
+
+	runID, err := resolveRunID(stateDir, args)
+	if err != nil {
+		return err
+	}
+
+	if raw, _ := cmd.Flags().GetBool("raw"); raw {
+		data, err := os.ReadFile(events.Path(stateDir, runID))
+		if err != nil {
+			return fmt.Errorf("reading event log: %w", err)
+		}
+		data, err = encrypt.OpenLines(data)
+		if err != nil && !encrypt.Partial(err) {
+			return fmt.Errorf("reading event log: %w", err)
+		}
+		if err != nil {
+			output.Default().Warn("reading event log: %v", err)
+		}
+		_, err = out.Write(data)
+		return err
+	}
+
+	evs, err := events.Load(stateDir, runID)
+	if err != nil && !encrypt.Partial(err) {
+		return fmt.Errorf("loading events: %w", err)
+	}
+	if err != nil {
+		output.Default().Warn("%v", err)
+	}
+	events.RenderTimeline(out, evs)
+	return nil
+}
+
+// resolveRunID returns the run ID from args or the most recent run.
+func resolveRunID(stateDir string, args []string) (string, error) {
+	if len(args) > 0 {
+		// A run ID is a directory name under state_dir; "../x" would escape it
+		if !events.ValidRunID(args[0]) {
+			return "", fmt.Errorf("run ID %q is not a plain name (letters, digits, '-' and '_')", args[0])
+		}
+		return args[0], nil
+	}
+	runID, err := events.LatestRunID(stateDir)
+	if err != nil {
+		return "", fmt.Errorf("finding latest run: %w", err)
+	}
+	return runID, nil
+}
+
+// listRuns prints recorded run IDs with their last-modified time.
+func listRuns(out io.Writer, stateDir string) error {
+	runs, err := events.ListRuns(stateDir)
+	if err != nil {
+		return fmt.Errorf("listing runs: %w", err)
+	}
+	if len(runs) == 0 {
+		fmt.Fprintln(out, "No recorded runs.")
+		return nil
+	}
+	for _, run := range runs {
+		fmt.Fprintf(out, "%s  %s\n", run.ModTime.Format("2006-01-02 15:04:05"), run.RunID)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package util
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/git"
+	"github.com/ariel-frischer/autospec/internal/report"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report [spec-name]",
+	Short: "Generate a completion report for a spec (markdown or HTML)",
+	Long: `Generate a human-readable completion report for a spec.
+
+The report summarizes:
+  - User stories delivered (all tasks referencing the story are completed)
+  - Tasks completed, with durations derived from task status_history
+  - Files changed on the branch (git diff against the merge-base with --base)
+  - Validation outcomes for spec.yaml, plan.yaml, and tasks.yaml
+  - Notable retries recorded in the state directory
+
+The output is suitable for attaching to pull requests or sharing with stakeholders.`,
+	Example: `  # Markdown report for the current spec to stdout
+  autospec report
+
+  # HTML report for a specific spec written to a file
+  autospec report 003-auth --format html -o report.html
+
+  # Compare changed files against a different base branch
+  autospec report --base develop`,
+	Args:         cobra.MaximumNArgs(1),
+	SilenceUsage: true,
+	RunE:         runReport,
+}
+
+func init() {
+	reportCmd.GroupID = shared.GroupGettingStarted
+	reportCmd.Flags().StringP("format", "f", "markdown", "Output format: markdown or html")
+	reportCmd.Flags().StringP("output", "o", "", "Write the report to a file instead of stdout")
+	reportCmd.Flags().String("base", "main", "Base branch for the files-changed section")
+	reportCmd.Flags().Bool("no-git", false, "Skip the files-changed section")
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	formatFlag, _ := cmd.Flags().GetString("format")
+	format, err := report.ParseFormat(formatFlag)
+	if err != nil {
+		return fmt.Errorf("parsing format: %w", err)
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	metadata, err := resolveReportSpec(cfg.SpecsDir, args)
+	if err != nil {
+		return fmt.Errorf("detecting spec: %w", err)
+	}
+
+	r, err := report.Build(report.Options{
+		SpecDir:      metadata.Directory,
+		StateDir:     cfg.StateDir,
+		ChangedFiles: changedFilesFunc(cmd),
+	})
+	if err != nil {
+		return fmt.Errorf("building report: %w", err)
+	}
+
+	return writeReport(cmd, r, format)
+}
+
+// resolveReportSpec returns the spec named in args, or auto-detects the current spec.
+func resolveReportSpec(specsDir string, args []string) (*spec.Metadata, error) {
+	if len(args) > 0 {
+		return spec.GetSpecMetadata(specsDir, args[0])
+	}
+	return spec.DetectCurrentSpec(specsDir)
+}
+
+// changedFilesFunc returns the git changed-files provider, or nil when disabled.
+func changedFilesFunc(cmd *cobra.Command) func() ([]string, error) {
+	noGit, _ := cmd.Flags().GetBool("no-git")
+	if noGit || !git.IsGitRepository() {
+		return nil
+	}
+	base, _ := cmd.Flags().GetString("base")
+	return func() ([]string, error) {
+		return git.ChangedFiles(base)
+	}
+}
+
+// writeReport renders the report to --output or stdout.
+func writeReport(cmd *cobra.Command, r *report.Report, format report.Format) error {
+	outputPath, _ := cmd.Flags().GetString("output")
+	if outputPath == "" {
+		return report.Render(cmd.OutOrStdout(), r, format)
+	}
+
+	f, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("creating report file: %w", err)
+	}
+	defer f.Close()
+
+	if err := report.Render(f, r, format); err != nil {
+		return fmt.Errorf("rendering report: %w", err)
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "✓ Report written to %s\n", outputPath)
+	return nil
+}
@@ -0,0 +1,166 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/pause"
+	"github.com/spf13/cobra"
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Show or continue a paused implementation run",
+	Long: `Show or continue a run paused between tasks or phases.
+
+A run started with 'implement --tasks' or 'implement --phases' pauses when
+its project's pause file exists, or when 'p' is pressed in its terminal. The
+current task finishes first; the loop then waits before starting the next
+one. Pause a run from another terminal with 'autospec pause'.
+
+Pause files are kept per project (<state_dir>/pause-<project>-<hash>), so
+resume only looks at the project of the current directory. Use --all for
+every project sharing the state directory.
+
+Without --continue, resume reports whether the run is paused. With
+--continue it removes the pause file and the waiting run picks up with its
+next task.`,
+	Example: `  # Is this project's run paused?
+  autospec resume
+
+  # Let the paused run continue
+  autospec resume --continue
+
+  # Continue paused runs in every project
+  autospec resume --continue --all`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runResume,
+}
+
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause this project's implementation run before its next task",
+	Long: `Pause the 'implement --tasks' or 'implement --phases' run of the project
+in the current directory. The current task finishes first; the run then
+waits before its next task or phase until 'autospec resume --continue'.
+
+A pause requested while no run is active pauses the next run before its
+first task.`,
+	Example: `  # Pause the run, then let it continue
+  autospec pause
+  autospec resume --continue`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runPause,
+}
+
+func init() {
+	resumeCmd.GroupID = shared.GroupGettingStarted
+	resumeCmd.Flags().Bool("continue", false, "Remove the pause file so the paused run continues")
+	resumeCmd.Flags().Bool("all", false, "Act on paused runs of every project, not just the current one")
+	pauseCmd.GroupID = shared.GroupGettingStarted
+}
+
+func runResume(cmd *cobra.Command, _ []string) error {
+	stateDir, err := loadStateDir(cmd)
+	if err != nil {
+		return err
+	}
+	cont, _ := cmd.Flags().GetBool("continue")
+	all, _ := cmd.Flags().GetBool("all")
+	if all {
+		return runResumeAll(cmd.OutOrStdout(), stateDir, cont)
+	}
+	return runResumeWithPath(cmd.OutOrStdout(), pause.Path(stateDir, pause.ProjectRoot()), cont)
+}
+
+func runPause(cmd *cobra.Command, _ []string) error {
+	stateDir, err := loadStateDir(cmd)
+	if err != nil {
+		return err
+	}
+	root := pause.ProjectRoot()
+	if err := pause.Request(pause.Path(stateDir, root), root); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Paused %s: its run waits before the next task\n", root)
+	fmt.Fprintln(cmd.OutOrStdout(), "Continue it with: autospec resume --continue")
+	return nil
+}
+
+// loadStateDir returns the configured state directory.
+func loadStateDir(cmd *cobra.Command) (string, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return "", cliErr
+	}
+	return cfg.StateDir, nil
+}
+
+// runResumeWithPath reports or clears the pause file at path.
+func runResumeWithPath(out io.Writer, path string, cont bool) error {
+	if !cont {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			fmt.Fprintln(out, "No run is paused")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading pause file: %w", err)
+		}
+		fmt.Fprintf(out, "A run is paused (%s)\n", strings.TrimSpace(string(data)))
+		fmt.Fprintln(out, "Continue it with: autospec resume --continue")
+		return nil
+	}
+
+	cleared, err := pause.Clear(path)
+	if err != nil {
+		return err
+	}
+	if !cleared {
+		fmt.Fprintln(out, "No run is paused")
+		return nil
+	}
+	fmt.Fprintln(out, "Resumed: the paused run continues with its next task")
+	return nil
+}
+
+// runResumeAll reports or clears the pause files of every project in stateDir.
+func runResumeAll(out io.Writer, stateDir string, cont bool) error {
+	paths, err := pause.List(stateDir)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		fmt.Fprintln(out, "No run is paused")
+		return nil
+	}
+	for _, path := range paths {
+		if cont {
+			if _, err := pause.Clear(path); err != nil {
+				return err
+			}
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("reading pause file: %w", err)
+		}
+		fmt.Fprintf(out, "A run is paused (%s)\n", strings.TrimSpace(string(data)))
+	}
+	if cont {
+		fmt.Fprintf(out, "Resumed %d paused run(s)\n", len(paths))
+		return nil
+	}
+	fmt.Fprintln(out, "Continue them with: autospec resume --continue --all")
+	return nil
+}
@@ -0,0 +1,64 @@
+// Package util tests the resume command.
+// Related: internal/cli/util/resume.go
+// Tags: util, cli, resume, pause
+
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/pause"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunResumeWithPath(t *testing.T) {
+	t.Parallel()
+
+	path := pause.Path(t.TempDir(), "/home/me/app")
+	var out bytes.Buffer
+	require.NoError(t, runResumeWithPath(&out, path, false))
+	assert.Equal(t, "No run is paused\n", out.String())
+
+	require.NoError(t, pause.Request(path, "/home/me/app"))
+	out.Reset()
+	require.NoError(t, runResumeWithPath(&out, path, false))
+	assert.Contains(t, out.String(), "A run is paused (paused at ")
+	assert.Contains(t, out.String(), "in /home/me/app")
+	assert.Contains(t, out.String(), "autospec resume --continue")
+	assert.True(t, pause.Requested(path), "showing the pause must not clear it")
+
+	out.Reset()
+	require.NoError(t, runResumeWithPath(&out, path, true))
+	assert.Equal(t, "Resumed: the paused run continues with its next task\n", out.String())
+	assert.False(t, pause.Requested(path))
+
+	out.Reset()
+	require.NoError(t, runResumeWithPath(&out, path, true))
+	assert.Equal(t, "No run is paused\n", out.String())
+}
+
+func TestRunResumeAll(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	var out bytes.Buffer
+	require.NoError(t, runResumeAll(&out, stateDir, false))
+	assert.Equal(t, "No run is paused\n", out.String())
+
+	app, api := pause.Path(stateDir, "/src/app"), pause.Path(stateDir, "/src/api")
+	require.NoError(t, pause.Request(app, "/src/app"))
+	require.NoError(t, pause.Request(api, "/src/api"))
+
+	out.Reset()
+	require.NoError(t, runResumeAll(&out, stateDir, false))
+	assert.Contains(t, out.String(), "in /src/app")
+	assert.Contains(t, out.String(), "in /src/api")
+
+	out.Reset()
+	require.NoError(t, runResumeAll(&out, stateDir, true))
+	assert.Equal(t, "Resumed 2 paused run(s)\n", out.String())
+	assert.False(t, pause.Requested(app))
+	assert.False(t, pause.Requested(api))
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/dashboard"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/metrics"
+	"github.com/ariel-frischer/autospec/internal/statusfile"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a read-only web dashboard of specs and runs",
+	Long: `Serve a read-only HTML dashboard for the current project.
+
+The dashboard lists specs with their status and task progress, shows a task
+board and stage timeline per spec, and displays recent run logs. It reads the
+same files as 'autospec status' and 'autospec replay' on every request and
+never modifies the project. Pages refresh themselves every few seconds.
+Prometheus metrics for runs in the state directory are served at /metrics.
+
+The dashboard has no authentication. It listens on 127.0.0.1 by default; use
+--host 0.0.0.0 only on trusted networks, such as a shared CI runner.`,
+	Example: `  # Serve on http://127.0.0.1:8080
+  autospec serve
+
+  # Expose the dashboard to your team on a shared runner
+  autospec serve --host 0.0.0.0 --port 9000`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runServe,
+}
+
+func init() {
+	serveCmd.GroupID = shared.GroupConfiguration
+	serveCmd.Flags().Int("port", 8080, "Port to listen on")
+	serveCmd.Flags().String("host", "127.0.0.1", "Interface to listen on")
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	host, _ := cmd.Flags().GetString("host")
+	port, _ := cmd.Flags().GetInt("port")
+	ln, err := net.Listen("tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return fmt.Errorf("starting dashboard: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Warning: the dashboard is unauthenticated and reachable from other hosts on %s\n", host)
+	}
+	fmt.Fprintf(out, "autospec dashboard at http://%s (Ctrl+C to stop)\n", ln.Addr())
+	server := dashboard.New(cfg.SpecsDir, cfg.StateDir, statusfile.DefaultPath(), Version)
+	server.Metrics = metrics.NewCollector(cfg.StateDir, Version)
+	if err := server.Serve(ctx, ln); err != nil {
+		return fmt.Errorf("serving dashboard: %w", err)
+	}
+	fmt.Fprintln(out, "autospec dashboard stopped")
+	return nil
+}
@@ -0,0 +1,145 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/stagestats"
+	"github.com/spf13/cobra"
+)
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show per-stage retry and failure history",
+	Long: `Show how often each stage needed retries across past runs, and why its
+attempts failed: validation, agent_error, timeout or gate (task_commands,
+targeted_tests).
+
+The history is kept in the state directory (stage_stats.json) and covers
+every non-interactive stage execution; with implement --tasks, each task is
+one run of the implement stage.
+
+With --flaky, only stages and spec stages whose retry rate is unusually high
+are listed, highest first, to point at prompts or templates worth fixing.`,
+	Example: `  # Retry and failure history per stage
+  autospec stats
+
+  # Stages and specs averaging at least 0.5 retries per run over 3+ runs
+  autospec stats --flaky
+
+  # Stricter: at least one retry per run over 5+ runs
+  autospec stats --flaky --threshold 1 --min-runs 5`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runStats,
+}
+
+func init() {
+	statsCmd.GroupID = shared.GroupConfiguration
+	addStatsFlags(statsCmd)
+}
+
+// addStatsFlags defines the stats command's flags on cmd.
+func addStatsFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("flaky", false, "List only stages and specs with a high retry rate")
+	cmd.Flags().Float64("threshold", 0.5, "Retries per run at which --flaky lists a stage")
+	cmd.Flags().Int("min-runs", 3, "Runs a stage needs before --flaky considers it")
+}
+
+func runStats(cmd *cobra.Command, _ []string) error {
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	return runStatsWithStateDir(cmd, cfg.StateDir)
+}
+
+// runStatsWithStateDir runs the stats command against a specific state directory.
+func runStatsWithStateDir(cmd *cobra.Command, stateDir string) error {
+	out := cmd.OutOrStdout()
+	flaky, _ := cmd.Flags().GetBool("flaky")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	minRuns, _ := cmd.Flags().GetInt("min-runs")
+	if threshold < 0 {
+		return fmt.Errorf("threshold must not be negative, got %g", threshold)
+	}
+	if minRuns < 1 {
+		return fmt.Errorf("min-runs must be at least 1, got %d", minRuns)
+	}
+
+	store, err := stagestats.Load(stateDir)
+	if err != nil {
+		return err
+	}
+	if len(store.Records) == 0 {
+		fmt.Fprintln(out, "No stage history recorded yet.")
+		return nil
+	}
+
+	if !flaky {
+		renderStats(out, store.ByStage(), specStages(store))
+		return nil
+	}
+	records := store.Flaky(minRuns, threshold)
+	if len(records) == 0 {
+		fmt.Fprintf(out, "No flaky stages: none averages %g+ retries per run over %d+ runs.\n", threshold, minRuns)
+		return nil
+	}
+	fmt.Fprintf(out, "Flaky stages (%g+ retries per run over %d+ runs):\n\n", threshold, minRuns)
+	renderStatsTable(out, records)
+	return nil
+}
+
+// specStages returns the per-spec records sorted by spec, then stage.
+func specStages(store *stagestats.Store) []stagestats.Record {
+	records := make([]stagestats.Record, 0, len(store.Records))
+	for _, rec := range store.Records {
+		records = append(records, *rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Spec != records[j].Spec {
+			return records[i].Spec < records[j].Spec
+		}
+		return records[i].Stage < records[j].Stage
+	})
+	return records
+}
+
+// renderStats prints the stage totals followed by the per-spec breakdown.
+func renderStats(out io.Writer, stages, specs []stagestats.Record) {
+	fmt.Fprintln(out, "By stage:")
+	fmt.Fprintln(out)
+	renderStatsTable(out, stages)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "By spec:")
+	fmt.Fprintln(out)
+	renderStatsTable(out, specs)
+}
+
+// renderStatsTable prints records as an aligned table. Stage totals show
+// "(all)" in the spec column.
+func renderStatsTable(out io.Writer, records []stagestats.Record) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SPEC\tSTAGE\tRUNS\tRETRIES/RUN\tFAILED ATTEMPTS\tTOP FAILURE\tLAST RUN")
+	for _, rec := range records {
+		spec := rec.Spec
+		if spec == "" {
+			spec = "(all)"
+		}
+		top := string(rec.TopFailure())
+		if top == "" {
+			top = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.2f\t%.0f%%\t%s\t%s\n",
+			spec, rec.Stage, rec.Runs, rec.RetryRate(), rec.FailureRate()*100, top, rec.LastRun.Format("2006-01-02"))
+	}
+	tw.Flush()
+}
@@ -0,0 +1,75 @@
+// Package util tests the stats command.
+// Related: internal/cli/util/stats.go
+// Tags: util, cli, stats, retries
+
+package util
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/stagestats"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStatsTestCmd(out *bytes.Buffer, args ...string) *cobra.Command {
+	cmd := &cobra.Command{Use: "stats"}
+	addStatsFlags(cmd)
+	cmd.SetOut(out)
+	if err := cmd.Flags().Parse(args); err != nil {
+		panic(err)
+	}
+	return cmd
+}
+
+func TestRunStats(t *testing.T) {
+	stateDir := t.TempDir()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, stagestats.RecordRun(stateDir, stagestats.Run{
+			Spec: "001-login", Stage: "tasks", Attempts: 2, Failures: []stagestats.Kind{stagestats.KindValidation}, Succeeded: true,
+		}))
+		require.NoError(t, stagestats.RecordRun(stateDir, stagestats.Run{
+			Spec: "002-search", Stage: "plan", Attempts: 1, Succeeded: true,
+		}))
+	}
+
+	tests := map[string]struct {
+		args        []string
+		wantContain []string
+		wantMissing []string
+	}{
+		"all stages": {
+			wantContain: []string{"By stage:", "By spec:", "001-login", "002-search", "validation"},
+		},
+		"flaky only": {
+			args:        []string{"--flaky"},
+			wantContain: []string{"Flaky stages (0.5+ retries per run over 3+ runs)", "(all)", "001-login", "1.00"},
+			wantMissing: []string{"002-search"},
+		},
+		"nothing flaky": {
+			args:        []string{"--flaky", "--threshold", "2"},
+			wantContain: []string{"No flaky stages"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, runStatsWithStateDir(newStatsTestCmd(&out, tt.args...), stateDir))
+			for _, want := range tt.wantContain {
+				assert.Contains(t, out.String(), want)
+			}
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, out.String(), missing)
+			}
+		})
+	}
+}
+
+func TestRunStats_Empty(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, runStatsWithStateDir(newStatsTestCmd(&out), t.TempDir()))
+	assert.Contains(t, out.String(), "No stage history recorded yet.")
+}
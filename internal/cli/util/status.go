@@ -57,28 +57,51 @@ var statusCmd = &cobra.Command{
 			}
 		}
 
-		// Show artifacts
-		if len(existing) > 0 {
-			fmt.Printf("  artifacts: %v\n", existing)
-		} else {
-			fmt.Println("  artifacts: none")
-		}
+		stale, _ := spec.LoadStaleArtifacts(metadata.Directory)
 
 		// Get tasks file path (prefers .yaml over .md)
 		tasksPath := validation.GetTasksFilePath(metadata.Directory)
 
 		// Get task stats (only if tasks file exists)
 		stats, err := validation.GetTaskStats(tasksPath)
-		if err == nil {
-			fmt.Print(validation.FormatTaskSummary(stats))
+		if err != nil {
+			stats = nil
 		}
 
 		// Get risk stats from plan.yaml (if plan.yaml exists)
 		planPath := validation.GetPlanFilePath(metadata.Directory)
 		riskStats, _ := validation.GetRiskStats(planPath)
+
+		// Get the effort and risk rollup (if 'autospec forecast' has run)
+		forecast, _ := validation.GetForecastRollup(metadata.Directory)
+
+		if useJSON, _ := cmd.Flags().GetBool("json"); useJSON {
+			result := newStatusResult(metadata, existing, stale, stats, riskStats, forecast)
+			if stats != nil && stats.BlockedTasks > 0 {
+				result.BlockedTasks = blockedTasksJSON(tasksPath)
+			}
+			return shared.WriteJSON(cmd, result)
+		}
+
+		// Show artifacts
+		if len(existing) > 0 {
+			fmt.Printf("  artifacts: %v\n", existing)
+		} else {
+			fmt.Println("  artifacts: none")
+		}
+
+		// Show artifacts left stale by 'autospec amend'
+		for _, s := range stale {
+			fmt.Printf("  stale: %s (spec amended; run 'autospec %s')\n", s.Artifact, s.Stage)
+		}
+
+		if err == nil {
+			fmt.Print(validation.FormatTaskSummary(stats))
+		}
 		if riskStats != nil {
 			fmt.Print(validation.FormatRiskSummary(riskStats))
 		}
+		fmt.Print(validation.FormatForecastSummary(forecast))
 
 		// Display blocked tasks with reasons
 		if err == nil && stats != nil && stats.BlockedTasks > 0 {
@@ -156,3 +179,114 @@ func truncateStatusReason(s string, maxLen int) string {
 	}
 	return s[:maxLen-3] + "..."
 }
+
+// statusResult is the --json result of status.
+type statusResult struct {
+	Spec         string              `json:"spec"`
+	Directory    string              `json:"directory"`
+	Artifacts    []string            `json:"artifacts"`
+	Stale        []statusStale       `json:"stale"`
+	Tasks        *statusTasks        `json:"tasks"`    // null without tasks
+	Risks        *statusRisks        `json:"risks"`    // null without plan.yaml risks
+	Forecast     *statusForecast     `json:"forecast"` // null before 'autospec forecast'
+	BlockedTasks []statusBlockedTask `json:"blocked_tasks"`
+}
+
+type statusStale struct {
+	Artifact string   `json:"artifact"`
+	Stage    string   `json:"stage"`
+	Reasons  []string `json:"reasons"`
+}
+
+type statusTasks struct {
+	Total             int           `json:"total"`
+	Completed         int           `json:"completed"`
+	InProgress        int           `json:"in_progress"`
+	Pending           int           `json:"pending"`
+	Blocked           int           `json:"blocked"`
+	CompletionPercent float64       `json:"completion_percent"`
+	Phases            []statusPhase `json:"phases"`
+}
+
+type statusPhase struct {
+	Number    int    `json:"number"`
+	Title     string `json:"title"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+}
+
+type statusRisks struct {
+	Total  int `json:"total"`
+	High   int `json:"high"`
+	Medium int `json:"medium"`
+	Low    int `json:"low"`
+}
+
+type statusForecast struct {
+	TotalEffortHours float64 `json:"total_effort_hours"`
+	OverallRisk      string  `json:"overall_risk"`
+	Confidence       string  `json:"confidence,omitempty"`
+}
+
+type statusBlockedTask struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Reason string `json:"reason"`
+}
+
+// newStatusResult gathers what status prints into its --json result.
+func newStatusResult(metadata *spec.Metadata, artifacts []string, stale []spec.StaleArtifact, stats *validation.TaskStats, risks *validation.RiskStats, forecast *validation.ForecastRollup) statusResult {
+	result := statusResult{
+		Spec:         metadata.DirName(),
+		Directory:    metadata.Directory,
+		Artifacts:    append([]string{}, artifacts...),
+		Stale:        []statusStale{},
+		BlockedTasks: []statusBlockedTask{},
+	}
+	for _, s := range stale {
+		result.Stale = append(result.Stale, statusStale{Artifact: s.Artifact, Stage: s.Stage, Reasons: s.Reasons})
+	}
+	if stats != nil {
+		result.Tasks = &statusTasks{
+			Total:             stats.TotalTasks,
+			Completed:         stats.CompletedTasks,
+			InProgress:        stats.InProgressTasks,
+			Pending:           stats.PendingTasks,
+			Blocked:           stats.BlockedTasks,
+			CompletionPercent: stats.CompletionPercentage(),
+			Phases:            []statusPhase{},
+		}
+		for _, phase := range stats.PhaseStats {
+			result.Tasks.Phases = append(result.Tasks.Phases, statusPhase{
+				Number:    phase.Number,
+				Title:     phase.Title,
+				Total:     phase.TotalTasks,
+				Completed: phase.CompletedTasks,
+			})
+		}
+	}
+	if risks != nil {
+		result.Risks = &statusRisks{Total: risks.Total, High: risks.High, Medium: risks.Medium, Low: risks.Low}
+	}
+	if forecast != nil {
+		result.Forecast = &statusForecast{
+			TotalEffortHours: forecast.TotalEffortHours,
+			OverallRisk:      forecast.OverallRisk,
+			Confidence:       forecast.Confidence,
+		}
+	}
+	return result
+}
+
+// blockedTasksJSON lists the blocked tasks of tasksPath for --json.
+func blockedTasksJSON(tasksPath string) []statusBlockedTask {
+	blocked := []statusBlockedTask{}
+	tasks, err := validation.GetAllTasks(tasksPath)
+	if err != nil {
+		return blocked
+	}
+	for _, task := range filterBlockedTasks(tasks) {
+		blocked = append(blocked, statusBlockedTask{ID: task.ID, Title: task.Title, Reason: task.BlockedReason})
+	}
+	return blocked
+}
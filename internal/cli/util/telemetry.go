@@ -0,0 +1,136 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/telemetry"
+	"github.com/spf13/cobra"
+)
+
+var telemetryCmd = &cobra.Command{
+	Use:   "telemetry",
+	Short: "Manage the anonymous, opt-in usage ping",
+	Long: `Manage the anonymous usage ping. It is off unless you enable it.
+
+When enabled, autospec sends one small JSON document after each command with
+exactly these fields: the autospec version, the operating system, the command
+name (no arguments or flags) and whether it succeeded. See it with
+'autospec telemetry status --show-payload'.
+
+DO_NOT_TRACK=1 or AUTOSPEC_TELEMETRY=off turn it off regardless of this
+setting.`,
+}
+
+var telemetryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show whether the usage ping is enabled",
+	Example: `  autospec telemetry status
+
+  # Print the exact payload that would be sent for this command
+  autospec telemetry status --show-payload`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		path, err := telemetryPath()
+		if err != nil {
+			return err
+		}
+		showPayload, _ := cmd.Flags().GetBool("show-payload")
+		return printTelemetryStatus(cmd.OutOrStdout(), path, showPayload)
+	},
+}
+
+var telemetryEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Opt in to the anonymous usage ping",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return setTelemetry(cmd.OutOrStdout(), true)
+	},
+}
+
+var telemetryDisableCmd = &cobra.Command{
+	Use:   "disable",
+	Short: "Opt out of the anonymous usage ping",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return setTelemetry(cmd.OutOrStdout(), false)
+	},
+}
+
+func init() {
+	telemetryCmd.GroupID = shared.GroupConfiguration
+	telemetryCmd.AddCommand(telemetryStatusCmd)
+	telemetryCmd.AddCommand(telemetryEnableCmd)
+	telemetryCmd.AddCommand(telemetryDisableCmd)
+	telemetryStatusCmd.Flags().Bool("show-payload", false, "Print the JSON payload that would be sent for this command")
+}
+
+// telemetryPath returns the consent file in the user config directory.
+func telemetryPath() (string, error) {
+	dir, err := config.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return telemetry.Path(dir), nil
+}
+
+// setTelemetry stores the consent and prints the resulting status.
+func setTelemetry(w io.Writer, enabled bool) error {
+	path, err := telemetryPath()
+	if err != nil {
+		return err
+	}
+	if err := telemetry.Save(path, enabled); err != nil {
+		return err
+	}
+	if enabled {
+		fmt.Fprintln(w, "Telemetry enabled. Thank you! Run 'autospec telemetry disable' to turn it off.")
+	} else {
+		fmt.Fprintln(w, "Telemetry disabled. Nothing will be sent.")
+	}
+	fmt.Fprintln(w)
+	return printTelemetryStatus(w, path, false)
+}
+
+// printTelemetryStatus describes the consent stored at path, what overrides
+// it, and optionally the payload for the telemetry status command.
+func printTelemetryStatus(w io.Writer, path string, showPayload bool) error {
+	settings, err := telemetry.Load(path)
+	if err != nil {
+		return err
+	}
+	endpoint := telemetry.ResolveEndpoint()
+
+	var state string
+	switch {
+	case !settings.Enabled:
+		state = "disabled (opt-in: run 'autospec telemetry enable')"
+	case telemetry.EnvOptOut() != "":
+		state = "disabled by " + telemetry.EnvOptOut()
+	case endpoint == "":
+		state = "enabled, but this build has no endpoint: nothing is sent"
+	default:
+		state = "enabled"
+	}
+	if endpoint == "" {
+		endpoint = "none"
+	}
+
+	fmt.Fprintf(w, "Telemetry: %s\n", state)
+	fmt.Fprintf(w, "Settings:  %s\n", path)
+	fmt.Fprintf(w, "Endpoint:  %s\n", endpoint)
+	fmt.Fprintf(w, "Payload:   version, OS, command name, success (schema %d)\n", telemetry.SchemaVersion)
+
+	if showPayload {
+		data, err := json.MarshalIndent(telemetry.NewPayload(Version, "telemetry status", true), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "\n%s\n", data)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+// Package util tests the telemetry command.
+// Related: internal/cli/util/telemetry.go
+// Tags: util, cli, telemetry
+
+package util
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/telemetry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintTelemetryStatus(t *testing.T) {
+	// Not parallel: sets environment variables
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("AUTOSPEC_TELEMETRY", "")
+	t.Setenv(telemetry.EndpointEnv, "https://collector.example.com/ping")
+	path := telemetry.Path(filepath.Join(t.TempDir(), "autospec"))
+
+	var out bytes.Buffer
+	require.NoError(t, printTelemetryStatus(&out, path, false))
+	assert.Contains(t, out.String(), "Telemetry: disabled (opt-in")
+	assert.NotContains(t, out.String(), `"schema"`)
+
+	require.NoError(t, telemetry.Save(path, true))
+	out.Reset()
+	require.NoError(t, printTelemetryStatus(&out, path, true))
+	assert.Contains(t, out.String(), "Telemetry: enabled\n")
+	assert.Contains(t, out.String(), "Endpoint:  https://collector.example.com/ping")
+	assert.Contains(t, out.String(), `"command": "telemetry status"`)
+
+	t.Setenv("DO_NOT_TRACK", "1")
+	out.Reset()
+	require.NoError(t, printTelemetryStatus(&out, path, false))
+	assert.Contains(t, out.String(), "Telemetry: disabled by DO_NOT_TRACK")
+
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv(telemetry.EndpointEnv, "")
+	out.Reset()
+	require.NoError(t, printTelemetryStatus(&out, path, false))
+	assert.Contains(t, out.String(), "no endpoint: nothing is sent")
+}
+
+func TestSetTelemetry(t *testing.T) {
+	// Not parallel: sets XDG_CONFIG_HOME
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", configHome)
+
+	var out bytes.Buffer
+	require.NoError(t, setTelemetry(&out, true))
+	assert.Contains(t, out.String(), "Telemetry enabled")
+	path, err := telemetryPath()
+	require.NoError(t, err)
+	s, err := telemetry.Load(path)
+	require.NoError(t, err)
+	assert.True(t, s.Enabled)
+
+	out.Reset()
+	require.NoError(t, setTelemetry(&out, false))
+	assert.Contains(t, out.String(), "Telemetry disabled")
+	s, err = telemetry.Load(path)
+	require.NoError(t, err)
+	assert.False(t, s.Enabled)
+}
@@ -21,9 +21,15 @@ const (
 var updateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update autospec to the latest version",
-	Long:  "Download and install the latest version of autospec from GitHub releases.",
+	Long: `Download and install the latest version of autospec from GitHub releases.
+
+The previous binary is kept next to the new one (<binary>.bak), so
+'autospec update rollback' can restore it.`,
 	Example: `  # Update to latest version
-  autospec update`,
+  autospec update
+
+  # Go back to the version installed before the last update
+  autospec update rollback`,
 	RunE: runUpdate,
 }
 
@@ -142,11 +148,13 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("setting permissions: %w (rolled back to previous version)", err)
 	}
 
-	// Cleanup backup
-	if err := installer.CleanupBackup(); err != nil {
-		// Non-fatal, just warn
-		fmt.Printf("%s Warning: failed to cleanup backup: %v\n", dim("!"), err)
-	}
+	// Keep the backup for 'autospec update rollback'
+	recordUpdateHistory(update.HistoryEntry{
+		Action: update.ActionUpdate,
+		From:   Version,
+		To:     check.LatestVersion,
+		Binary: installer.GetExecutablePath(),
+	}, dim)
 
 	fmt.Printf("%s Successfully updated to %s\n", green("✓"), green(check.LatestVersion))
 
@@ -155,6 +163,7 @@ func runUpdate(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("\n")
 	fmt.Printf("  Run 'autospec version' to verify the update.\n")
+	fmt.Printf("  Run 'autospec update rollback' to go back to %s.\n", Version)
 	fmt.Printf("\n")
 	fmt.Printf("%s Run 'autospec init' in each project to update slash commands.\n", yellow("!"))
 	fmt.Printf("  Command templates may have been improved in this release.\n")
@@ -0,0 +1,132 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/update"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var updateRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore the version installed before the last update",
+	Long: `Restore the autospec binary that 'autospec update' replaced.
+
+The previous binary is run first ('version --plain') to check that it works;
+nothing is changed if it does not. After the swap the restored binary is
+checked again, and the swap is undone if it fails. The replaced binary
+becomes the new backup, so running rollback again returns to it.
+
+Each update and rollback is recorded in the update history
+(see 'autospec update history').`,
+	Example: `  # Go back to the previous version
+  autospec update rollback
+
+  # Show past updates and rollbacks
+  autospec update history`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		cmd.SilenceUsage = true
+		installer, err := update.NewInstaller()
+		if err != nil {
+			return fmt.Errorf("initializing installer: %w", err)
+		}
+		if err := installer.CheckWritePermission(); err != nil {
+			return fmt.Errorf("permission check failed: %w", err)
+		}
+		return runUpdateRollback(cmd.Context(), cmd.OutOrStdout(), installer, update.ProbeVersion)
+	},
+}
+
+var updateHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show past updates and rollbacks",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		path, err := updateHistoryPath()
+		if err != nil {
+			return err
+		}
+		return printUpdateHistory(cmd.OutOrStdout(), path)
+	},
+}
+
+func init() {
+	updateCmd.AddCommand(updateRollbackCmd)
+	updateCmd.AddCommand(updateHistoryCmd)
+}
+
+// runUpdateRollback restores the backup kept by the last update and records
+// the rollback.
+func runUpdateRollback(ctx context.Context, w io.Writer, installer *update.Installer, probe update.Prober) error {
+	green := color.New(color.FgGreen, color.Bold).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	fmt.Fprintf(w, "%s Checking %s...\n", yellow("→"), installer.GetBackupPath())
+	result, err := installer.RollbackToBackup(ctx, Version, probe)
+	if err != nil {
+		return fmt.Errorf("rolling back: %w", err)
+	}
+
+	recordUpdateHistory(update.HistoryEntry{
+		Action: update.ActionRollback,
+		From:   result.From,
+		To:     result.To,
+		Binary: installer.GetExecutablePath(),
+	}, dim)
+
+	fmt.Fprintf(w, "%s Rolled back %s → %s\n", green("✓"), result.From, green(result.To))
+	fmt.Fprintf(w, "  %s is kept as %s; run 'autospec update rollback' again to return to it.\n",
+		result.From, installer.GetBackupPath())
+	return nil
+}
+
+// updateHistoryPath returns the update history file in the user config directory.
+func updateHistoryPath() (string, error) {
+	dir, err := config.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return update.HistoryPath(dir), nil
+}
+
+// recordUpdateHistory appends entry to the update history. Failures only
+// warn: the update or rollback itself has succeeded.
+func recordUpdateHistory(entry update.HistoryEntry, dim func(a ...interface{}) string) {
+	path, err := updateHistoryPath()
+	if err == nil {
+		if entry.Time.IsZero() {
+			entry.Time = time.Now()
+		}
+		err = update.AppendHistory(path, entry)
+	}
+	if err != nil {
+		fmt.Printf("%s Warning: failed to record update history: %v\n", dim("!"), err)
+	}
+}
+
+// printUpdateHistory prints the update history at path, newest first.
+func printUpdateHistory(w io.Writer, path string) error {
+	entries, err := update.LoadHistory(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No updates or rollbacks recorded.")
+		return nil
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TIME\tACTION\tFROM\tTO")
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Time.Local().Format("2006-01-02 15:04"), e.Action, e.From, e.To)
+	}
+	return tw.Flush()
+}
@@ -1,9 +1,15 @@
 package util
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/ariel-frischer/autospec/internal/update"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestUpdateCmd_Structure(t *testing.T) {
@@ -102,3 +108,51 @@ func TestPrintProgress(t *testing.T) {
 		})
 	}
 }
+
+func TestRunUpdateRollback(t *testing.T) {
+	// Not parallel: records history in XDG_CONFIG_HOME and sets Version
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("HOME", configHome)
+	origVersion := Version
+	Version = "v0.9.0"
+	defer func() { Version = origVersion }()
+
+	path := filepath.Join(t.TempDir(), "autospec")
+	require.NoError(t, os.WriteFile(path, []byte("v0.9.0"), 0o755))
+	installer := update.NewInstallerAt(path)
+	probe := func(_ context.Context, p string) (string, error) {
+		data, err := os.ReadFile(p)
+		return string(data), err
+	}
+
+	var out bytes.Buffer
+	err := runUpdateRollback(context.Background(), &out, installer, probe)
+	assert.ErrorContains(t, err, "no previous version")
+
+	require.NoError(t, os.WriteFile(installer.GetBackupPath(), []byte("v0.8.1"), 0o755))
+	out.Reset()
+	require.NoError(t, runUpdateRollback(context.Background(), &out, installer, probe))
+	assert.Contains(t, out.String(), "Rolled back v0.9.0 → v0.8.1")
+
+	historyPath, err := updateHistoryPath()
+	require.NoError(t, err)
+	entries, err := update.LoadHistory(historyPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, update.ActionRollback, entries[0].Action)
+	assert.Equal(t, "v0.8.1", entries[0].To)
+
+	out.Reset()
+	require.NoError(t, printUpdateHistory(&out, historyPath))
+	assert.Contains(t, out.String(), "TIME")
+	assert.Regexp(t, `rollback\s+v0.9.0\s+v0.8.1`, out.String())
+}
+
+func TestPrintUpdateHistory_Empty(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	require.NoError(t, printUpdateHistory(&out, filepath.Join(t.TempDir(), "none.yaml")))
+	assert.Equal(t, "No updates or rollbacks recorded.\n", out.String())
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/ariel-frischer/autospec/internal/cli/shared"
 	"github.com/ariel-frischer/autospec/internal/config"
 	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/spec"
 	"github.com/ariel-frischer/autospec/internal/validation"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
@@ -107,8 +108,7 @@ func resolveSpecsDir(cmd *cobra.Command, configValue string) string {
 // scanSpecsDir scans the specs directory and returns summaries for all valid specs.
 // Specs are sorted by LastModified descending (most recent first).
 func scanSpecsDir(specsDir string) ([]SpecSummary, error) {
-	entries, err := os.ReadDir(specsDir)
-	if err != nil {
+	if _, err := os.ReadDir(specsDir); err != nil {
 		if os.IsNotExist(err) {
 			return nil, nil
 		}
@@ -116,12 +116,9 @@ func scanSpecsDir(specsDir string) ([]SpecSummary, error) {
 	}
 
 	var summaries []SpecSummary
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-		specDir := filepath.Join(specsDir, entry.Name())
-		summary, err := getSpecSummary(specDir, entry.Name())
+	for _, name := range spec.ListSpecNames(specsDir) {
+		specDir := filepath.Join(specsDir, name)
+		summary, err := getSpecSummary(specDir, name)
 		if err != nil {
 			// Skip directories without spec.yaml or with parse errors
 			continue
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/specwatch"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/ariel-frischer/autospec/internal/workflow"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [spec]",
+	Short: "Watch spec.yaml for manual edits and flag plan/tasks as stale",
+	Long: `Watch a spec's spec.yaml while you edit it by hand. Each time an edit
+settles (no further changes for the debounce period), autospec:
+
+- Validates spec.yaml and shows its errors, if any
+- Marks plan.yaml and tasks.yaml (if present) as stale, naming the changed
+  spec sections
+- Re-validates plan.yaml and tasks.yaml
+- With --regenerate, re-runs 'autospec plan' and 'autospec tasks' for the
+  stale artifacts
+
+Edits that only change formatting, comments or the feature status are ignored.
+An invalid spec.yaml leaves plan and tasks untouched until it is fixed.
+
+Without a spec argument, the current spec is auto-detected. Press Ctrl+C to stop.`,
+	Example: `  # Watch the current spec
+  autospec watch
+
+  # Watch a specific spec and regenerate plan and tasks after each edit
+  autospec watch 003 --regenerate
+
+  # Wait for 10 seconds without changes before checking
+  autospec watch --debounce 10s`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWatch,
+}
+
+func init() {
+	watchCmd.GroupID = GroupOptionalStages
+	watchCmd.Flags().Duration("debounce", specwatch.DefaultDebounce, "Time spec.yaml must stay unchanged before it is checked")
+	watchCmd.Flags().Bool("regenerate", false, "Re-run plan and tasks for the artifacts an edit made stale")
+	rootCmd.AddCommand(watchCmd)
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	cmd.SilenceUsage = true
+
+	configPath, _ := cmd.Flags().GetString("config")
+	debounce, _ := cmd.Flags().GetDuration("debounce")
+	regenerate, _ := cmd.Flags().GetBool("regenerate")
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+
+	var metadata *spec.Metadata
+	if len(args) == 1 {
+		metadata, err = spec.GetSpecMetadata(cfg.SpecsDir, args[0])
+		if err == nil {
+			metadata.Detection = spec.DetectionExplicit
+		}
+	} else {
+		metadata, err = spec.DetectCurrentSpec(cfg.SpecsDir)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to detect spec: %w", err)
+	}
+	PrintSpecInfo(metadata)
+
+	watcher, err := specwatch.New(metadata.Directory, debounce)
+	if err != nil {
+		return fmt.Errorf("watching spec: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	out := cmd.OutOrStdout()
+	specName := metadata.DirName()
+	fmt.Fprintf(out, "Watching %s (Ctrl+C to stop)\n", filepath.Join(metadata.Directory, "spec.yaml"))
+	return watcher.Run(ctx, func(report specwatch.Report) {
+		printWatchReport(out, report, time.Now())
+		if regenerate && report.Spec.Valid {
+			regenerateStale(cfg, cmd, specName, report.Stale)
+		}
+	})
+}
+
+// printWatchReport summarizes a settled spec.yaml edit: spec errors, the
+// re-validation of plan.yaml and tasks.yaml, and the stale artifacts.
+func printWatchReport(out io.Writer, report specwatch.Report, at time.Time) {
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	stamp := at.Format("15:04:05")
+
+	if !report.Spec.Valid {
+		fmt.Fprintf(out, "\n[%s] %s spec.yaml has %d error(s); plan and tasks left unchanged\n", stamp, red("✗"), len(report.Spec.Errors))
+		printWatchErrors(out, report.Spec.Errors)
+		return
+	}
+
+	fmt.Fprintf(out, "\n[%s] spec.yaml changed: %s\n", stamp, strings.Join(report.Sections, ", "))
+	for _, check := range report.Checks {
+		if check.Result.Valid {
+			fmt.Fprintf(out, "  %s %s valid\n", green("✓"), check.Artifact)
+			continue
+		}
+		fmt.Fprintf(out, "  %s %s has %d error(s)\n", red("✗"), check.Artifact, len(check.Result.Errors))
+		printWatchErrors(out, check.Result.Errors)
+	}
+	if len(report.Stale) == 0 {
+		fmt.Fprintln(out, "  No plan or tasks yet. Next: autospec plan")
+		return
+	}
+	for _, s := range report.Stale {
+		fmt.Fprintf(out, "  %s %s is stale (run 'autospec %s')\n", yellow("!"), s.Artifact, s.Stage)
+	}
+}
+
+// printWatchErrors lists validation errors below a report line.
+func printWatchErrors(out io.Writer, errs []*validation.ValidationError) {
+	for _, e := range errs {
+		fmt.Fprintf(out, "      %s\n", e.Error())
+	}
+}
+
+// regenerateStale re-runs the stages of the stale artifacts in stage order.
+// A failed stage stops the regeneration; watching continues.
+func regenerateStale(cfg *config.Configuration, cmd *cobra.Command, specName string, stale []spec.StaleArtifact) {
+	notifHandler := notify.NewHandler(cfg.Notifications)
+	historyLogger := history.NewWriter(cfg.StateDir, cfg.MaxHistoryEntries)
+
+	for _, stage := range []workflow.Stage{workflow.StagePlan, workflow.StageTasks} {
+		if !hasStaleStage(stale, string(stage)) {
+			continue
+		}
+		err := lifecycle.RunWithHistory(notifHandler, historyLogger, string(stage), specName, func() error {
+			orch := workflow.NewWorkflowOrchestrator(cfg)
+			orch.SetNotificationHandler(notifHandler)
+			shared.ApplyOutputStyle(cmd, orch)
+			if stage == workflow.StagePlan {
+				return orch.ExecutePlan(specName, "")
+			}
+			return orch.ExecuteTasks(specName, "")
+		})
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Regenerating %s failed: %v\n", stage, err)
+			return
+		}
+	}
+}
+
+// hasStaleStage reports whether stage regenerates one of the stale artifacts.
+func hasStaleStage(stale []spec.StaleArtifact, stage string) bool {
+	for _, s := range stale {
+		if s.Stage == stage {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+// Package cli_test tests the watch command registration and report output.
+// Related: internal/cli/watch.go
+// Tags: cli, watch, spec, stale, command
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/specwatch"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchCmdRegistration(t *testing.T) {
+	cmd, _, err := rootCmd.Find([]string{"watch"})
+	require.NoError(t, err)
+	assert.Equal(t, watchCmd, cmd)
+	assert.Equal(t, GroupOptionalStages, watchCmd.GroupID)
+	assert.NotNil(t, watchCmd.Flags().Lookup("debounce"))
+	assert.NotNil(t, watchCmd.Flags().Lookup("regenerate"))
+	assert.Error(t, watchCmd.Args(watchCmd, []string{"003", "004"}))
+}
+
+func TestPrintWatchReport(t *testing.T) {
+	at := time.Date(2025, 1, 15, 14, 30, 5, 0, time.UTC)
+	tests := map[string]struct {
+		report specwatch.Report
+		want   []string
+		absent []string
+	}{
+		"invalid spec": {
+			report: specwatch.Report{Spec: &validation.ValidationResult{
+				Errors: []*validation.ValidationError{{Path: "user_stories[0].priority", Line: 15, Message: "invalid enum value"}},
+			}},
+			want:   []string{"[14:30:05]", "spec.yaml has 1 error(s); plan and tasks left unchanged", "invalid enum value"},
+			absent: []string{"stale"},
+		},
+		"no downstream artifacts": {
+			report: specwatch.Report{Spec: &validation.ValidationResult{Valid: true}, Sections: []string{"user_stories"}},
+			want:   []string{"spec.yaml changed: user_stories", "No plan or tasks yet. Next: autospec plan"},
+		},
+		"plan and tasks stale": {
+			report: specwatch.Report{
+				Spec:     &validation.ValidationResult{Valid: true},
+				Sections: []string{"requirements", "user_stories"},
+				Checks: []specwatch.Check{
+					{Artifact: "plan.yaml", Result: &validation.ValidationResult{Valid: true}},
+					{Artifact: "tasks.yaml", Result: &validation.ValidationResult{Errors: []*validation.ValidationError{{Message: "missing phases"}}}},
+				},
+				Stale: []spec.StaleArtifact{{Artifact: "plan.yaml", Stage: "plan"}, {Artifact: "tasks.yaml", Stage: "tasks"}},
+			},
+			want: []string{
+				"spec.yaml changed: requirements, user_stories",
+				"plan.yaml valid",
+				"tasks.yaml has 1 error(s)",
+				"missing phases",
+				"plan.yaml is stale (run 'autospec plan')",
+				"tasks.yaml is stale (run 'autospec tasks')",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			printWatchReport(&out, tt.report, at)
+			for _, want := range tt.want {
+				assert.Contains(t, out.String(), want)
+			}
+			for _, absent := range tt.absent {
+				assert.NotContains(t, out.String(), absent)
+			}
+		})
+	}
+}
+
+func TestHasStaleStage(t *testing.T) {
+	stale := []spec.StaleArtifact{{Artifact: "tasks.yaml", Stage: "tasks"}}
+	assert.True(t, hasStaleStage(stale, "tasks"))
+	assert.False(t, hasStaleStage(stale, "plan"))
+}
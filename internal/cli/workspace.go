@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/spf13/cobra"
+)
+
+// applyPackageSelection switches into the workspace package selected with
+// --package or AUTOSPEC_PACKAGE before a command runs. Running from the
+// package directory scopes specs, the constitution, agent sessions and task
+// commands to the package; --config is made absolute so the project config
+// (which defines the packages) is still loaded.
+func applyPackageSelection(cmd *cobra.Command) error {
+	name, _ := cmd.Flags().GetString("package")
+	explicit := cmd.Flags().Changed("package")
+	if !explicit {
+		name = os.Getenv(config.PackageEnvVar)
+	}
+	if name == "" {
+		return nil
+	}
+
+	configPath, _ := cmd.Flags().GetString("config")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		cliErr := clierrors.ConfigParseError(configPath, err)
+		clierrors.PrintError(cliErr)
+		return cliErr
+	}
+	// Inherited by agent subprocesses, which already run inside the package
+	if !explicit && len(cfg.Packages) == 0 {
+		return nil
+	}
+
+	pkg, err := cfg.LookupPackage(name)
+	if err != nil {
+		return err
+	}
+	absConfig, err := filepath.Abs(configPath)
+	if err != nil {
+		return fmt.Errorf("resolving config path: %w", err)
+	}
+	if err := cmd.Flags().Set("config", absConfig); err != nil {
+		return fmt.Errorf("setting config path: %w", err)
+	}
+	if err := os.Setenv(config.PackageEnvVar, name); err != nil {
+		return fmt.Errorf("selecting package: %w", err)
+	}
+	if pkg.SpecsDir != "" {
+		if err := os.Setenv("AUTOSPEC_SPECS_DIR", pkg.SpecsDir); err != nil {
+			return fmt.Errorf("selecting package: %w", err)
+		}
+	}
+	if err := os.Chdir(pkg.Path); err != nil {
+		return fmt.Errorf("package %q: %w", name, err)
+	}
+	return nil
+}
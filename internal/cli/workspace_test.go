@@ -0,0 +1,103 @@
+// Package cli_test tests --package selection of monorepo workspace packages.
+// Related: internal/cli/workspace.go
+// Tags: cli, workspace, package, monorepo, config
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPackageTestCmd returns a command with the root flags that package selection reads.
+func newPackageTestCmd() *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().String("config", ".autospec/config.yml", "")
+	cmd.Flags().String("package", "", "")
+	return cmd
+}
+
+// setupWorkspace creates a project with an api package and changes into it.
+func setupWorkspace(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	t.Chdir(root)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, ".config"))
+	t.Setenv("HOME", root)
+	// Set (to restore after the test), then unset: empty values would override config
+	for _, key := range []string{config.PackageEnvVar, "AUTOSPEC_SPECS_DIR"} {
+		t.Setenv(key, "")
+		require.NoError(t, os.Unsetenv(key))
+	}
+
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".autospec"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "services", "api"), 0o755))
+	cfg := "packages:\n  api:\n    path: services/api\n    specs_dir: design/specs\n"
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".autospec", "config.yml"), []byte(cfg), 0o644))
+	return root
+}
+
+func TestApplyPackageSelection(t *testing.T) {
+	root := setupWorkspace(t)
+	cmd := newPackageTestCmd()
+	require.NoError(t, cmd.Flags().Set("package", "api"))
+
+	require.NoError(t, applyPackageSelection(cmd))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	wantDir, err := filepath.EvalSymlinks(filepath.Join(root, "services", "api"))
+	require.NoError(t, err)
+	gotDir, err := filepath.EvalSymlinks(wd)
+	require.NoError(t, err)
+	assert.Equal(t, wantDir, gotDir)
+
+	configPath, _ := cmd.Flags().GetString("config")
+	assert.True(t, filepath.IsAbs(configPath))
+	assert.Equal(t, "api", os.Getenv(config.PackageEnvVar))
+	assert.Equal(t, "design/specs", os.Getenv("AUTOSPEC_SPECS_DIR"))
+
+	// Commands loading config afterwards see the root config and the package scope
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "api", cfg.Package)
+	assert.Equal(t, "design/specs", cfg.SpecsDir)
+}
+
+func TestApplyPackageSelection_NoPackage(t *testing.T) {
+	root := setupWorkspace(t)
+
+	require.NoError(t, applyPackageSelection(newPackageTestCmd()))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Base(root), filepath.Base(wd))
+}
+
+func TestApplyPackageSelection_UnknownPackage(t *testing.T) {
+	setupWorkspace(t)
+	cmd := newPackageTestCmd()
+	require.NoError(t, cmd.Flags().Set("package", "web"))
+
+	err := applyPackageSelection(cmd)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown package "web" (available: api)`)
+}
+
+func TestApplyPackageSelection_InheritedInsidePackage(t *testing.T) {
+	root := setupWorkspace(t)
+	// An agent subprocess runs inside the package, where no packages are configured
+	t.Chdir(filepath.Join(root, "services", "api"))
+	t.Setenv(config.PackageEnvVar, "api")
+
+	require.NoError(t, applyPackageSelection(newPackageTestCmd()))
+
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	assert.Equal(t, "api", filepath.Base(wd))
+}
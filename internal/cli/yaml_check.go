@@ -1,10 +1,14 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 
+	"github.com/ariel-frischer/autospec/internal/cli/shared"
+	clierrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/yaml"
 	"github.com/spf13/cobra"
 )
@@ -21,6 +25,9 @@ Example:
   autospec yaml check specs/007-yaml-structured-output/spec.yaml`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if shared.WantsJSON(cmd) {
+			return runYamlCheckJSON(cmd, args[0])
+		}
 		return runYamlCheckWithOutput(args[0], cmd.OutOrStdout())
 	},
 }
@@ -45,6 +52,29 @@ func runYamlCheckWithOutput(path string, out io.Writer) error {
 	return nil
 }
 
+// yamlCheckResult is the --json result of yaml check.
+type yamlCheckResult struct {
+	Path  string `json:"path"`
+	Valid bool   `json:"valid"`
+}
+
+// runYamlCheckJSON validates a YAML file and writes the result as the --json
+// envelope, with the syntax error in its errors.
+func runYamlCheckJSON(cmd *cobra.Command, path string) error {
+	err := yaml.ValidateFile(path)
+	if err == nil {
+		return shared.WriteJSON(cmd, yamlCheckResult{Path: path, Valid: true})
+	}
+	cliErr, exitCode := clierrors.Classify(clierrors.ErrSchemaViolation.Errorf("%w", err)), ExitValidationFailed
+	if errors.Is(err, fs.ErrNotExist) {
+		cliErr, exitCode = clierrors.NewArgumentError(err.Error()), ExitInvalidArguments
+	}
+	if writeErr := shared.WriteJSON(cmd, yamlCheckResult{Path: path}, cliErr); writeErr != nil {
+		return writeErr
+	}
+	return NewExitError(exitCode)
+}
+
 // yamlCheckExitCode returns the appropriate exit code for yaml check results.
 func yamlCheckExitCode(err error) int {
 	if err != nil {
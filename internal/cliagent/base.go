@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
+
+	"github.com/ariel-frischer/autospec/internal/commands"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
 )
 
 // BaseAgent provides shared implementation for common agent operations.
@@ -78,6 +82,10 @@ func (b *BaseAgent) BuildCommand(prompt string, opts ExecOptions) (*exec.Cmd, er
 func (b *BaseAgent) buildArgs(prompt string, opts ExecOptions) []string {
 	var args []string
 	pd := b.AgentCaps.PromptDelivery
+	// Agents given the command by name (CommandFlag) resolve it themselves
+	if !b.AgentCaps.NativeCommands && pd.CommandFlag == "" {
+		prompt = commands.ExpandSlashCommand(prompt)
+	}
 
 	// Interactive mode: use InteractiveFlag if set, otherwise positional argument
 	// Automated mode: use configured prompt delivery method (e.g., -p flag)
@@ -350,9 +358,8 @@ func (b *BaseAgent) runCommand(ctx context.Context, cmd *exec.Cmd, opts ExecOpti
 	var err error
 	select {
 	case <-ctx.Done():
-		_ = cmd.Process.Kill()
-		<-done // Wait for goroutine to exit
-		return nil, fmt.Errorf("executing %s: %w", b.AgentName, ctx.Err())
+		stopProcess(ctx, cmd, done)
+		return nil, fmt.Errorf("executing %s: %w", b.AgentName, context.Cause(ctx))
 	case err = <-done:
 	}
 	duration := time.Since(start)
@@ -382,6 +389,15 @@ func (b *BaseAgent) execInteractive(cmd *exec.Cmd) (*Result, error) {
 	if err != nil {
 		return nil, fmt.Errorf("finding %s binary: %w", b.AgentName, err)
 	}
+	if cmd.Dir != "" {
+		// syscall.Exec keeps the working directory, so change it first
+		if binary, err = filepath.Abs(binary); err != nil {
+			return nil, fmt.Errorf("finding %s binary: %w", b.AgentName, err)
+		}
+		if err := os.Chdir(cmd.Dir); err != nil {
+			return nil, fmt.Errorf("changing to %s: %w", cmd.Dir, err)
+		}
+	}
 
 	// syscall.Exec replaces the current process with the new one
 	// This gives the child process full control of the terminal
@@ -394,6 +410,25 @@ func (b *BaseAgent) execInteractive(cmd *exec.Cmd) (*Result, error) {
 	return nil, nil
 }
 
+// stopProcess ends a running agent after ctx is done and waits for it to exit.
+// After an interrupt the agent is sent SIGINT and may finish writing for the
+// grace period, or until a second interrupt, before it is killed. Timeouts,
+// and platforms that cannot deliver SIGINT, kill it straight away.
+func stopProcess(ctx context.Context, cmd *exec.Cmd, done <-chan error) {
+	if interrupt.Interrupted(ctx) && cmd.Process.Signal(os.Interrupt) == nil {
+		grace := time.NewTimer(interrupt.GracePeriod())
+		defer grace.Stop()
+		select {
+		case <-done:
+			return
+		case <-grace.C:
+		case <-interrupt.Forced():
+		}
+	}
+	_ = cmd.Process.Kill()
+	<-done
+}
+
 // applyTimeout returns a context with timeout if opts.Timeout is set.
 func (b *BaseAgent) applyTimeout(ctx context.Context, opts ExecOptions) (context.Context, context.CancelFunc) {
 	if opts.Timeout > 0 {
@@ -2,9 +2,12 @@ package cliagent
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/ariel-frischer/autospec/internal/interrupt"
 )
 
 func TestBaseAgent_Name(t *testing.T) {
@@ -762,6 +765,36 @@ func TestOpenCode_BuildCommand_ImplementPhase(t *testing.T) {
 	}
 }
 
+// TestBaseAgent_BuildCommand_ExpandsSlashCommands tests that agents without
+// native command support receive the command template instead of its name.
+func TestBaseAgent_BuildCommand_ExpandsSlashCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		agent      Agent
+		wantExpand bool
+	}{
+		"gemini expands":        {agent: NewGemini(), wantExpand: true},
+		"cline expands":         {agent: NewCline(), wantExpand: true},
+		"claude keeps the name": {agent: NewClaude(), wantExpand: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cmd, err := tt.agent.BuildCommand("/autospec.plan Use PostgreSQL", ExecOptions{Autonomous: true})
+			if err != nil {
+				t.Fatalf("BuildCommand() error = %v", err)
+			}
+			args := strings.Join(cmd.Args, " ")
+			expanded := !strings.Contains(args, "/autospec.plan Use PostgreSQL") && strings.Contains(args, "## User Input")
+			if tt.wantExpand != expanded {
+				t.Errorf("expanded = %v, want %v (args: %q)", expanded, tt.wantExpand, cmd.Args)
+			}
+		})
+	}
+}
+
 // TestParseSlashCommand tests parsing of slash commands.
 func TestParseSlashCommand(t *testing.T) {
 	t.Parallel()
@@ -831,3 +864,27 @@ func TestParseSlashCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseAgent_Execute_Interrupted(t *testing.T) {
+	t.Parallel()
+	agent := &BaseAgent{
+		AgentName: "test",
+		Cmd:       "sleep",
+		AgentCaps: Caps{
+			PromptDelivery: PromptDelivery{Method: PromptMethodPositional},
+		},
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(50*time.Millisecond, func() { cancel(interrupt.ErrInterrupted) })
+
+	start := time.Now()
+	_, err := agent.Execute(ctx, "10", ExecOptions{})
+	if !errors.Is(err, interrupt.ErrInterrupted) {
+		t.Fatalf("Execute() error = %v, want interrupted", err)
+	}
+	// sleep exits on SIGINT, so the grace period is not waited out
+	if elapsed := time.Since(start); elapsed >= interrupt.DefaultGracePeriod {
+		t.Errorf("Execute() took %s, want the agent stopped by SIGINT", elapsed)
+	}
+}
@@ -92,4 +92,10 @@ type Caps struct {
 	// Added after prompt delivery args but before AutonomousFlag and ExtraArgs.
 	// Example: ["--verbose", "--output-format", "stream-json"]
 	DefaultArgs []string
+
+	// NativeCommands indicates the agent resolves the /autospec.* commands
+	// installed by autospec init on its own. When false, a prompt starting
+	// with /autospec.* is replaced by the command's template text before the
+	// agent is run.
+	NativeCommands bool
 }
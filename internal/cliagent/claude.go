@@ -34,6 +34,8 @@ func NewClaude() *Claude {
 				// DefaultArgs enables stream-json output for better terminal parsing.
 				// --verbose is required with stream-json or Claude will error.
 				DefaultArgs: []string{"--verbose", "--output-format", "stream-json"},
+				// Commands are installed to .claude/commands by ConfigureProject
+				NativeCommands: true,
 			},
 		},
 	}
@@ -55,19 +57,9 @@ func (c *Claude) ConfigureProject(projectDir, specsDir string, projectLevel bool
 		return ConfigResult{}, fmt.Errorf("installing claude commands: %w", err)
 	}
 
-	var settings *claude.Settings
-	var err error
-	var configLocation string
-
-	if projectLevel {
-		settings, err = claude.Load(projectDir)
-		configLocation = "project"
-	} else {
-		settings, err = claude.LoadGlobal()
-		configLocation = "global"
-	}
+	settings, configLocation, err := loadClaudeSettings(projectDir, projectLevel)
 	if err != nil {
-		return ConfigResult{}, fmt.Errorf("loading claude %s settings: %w", configLocation, err)
+		return ConfigResult{}, err
 	}
 
 	permissions := buildClaudePermissions(specsDir)
@@ -94,6 +86,41 @@ func (c *Claude) ConfigureProject(projectDir, specsDir string, projectLevel bool
 	}, nil
 }
 
+// PreviewPermissions returns the permission entries ConfigureProject would add,
+// and the settings file they would be written to, without modifying it.
+func (c *Claude) PreviewPermissions(projectDir, specsDir string, projectLevel bool) (claude.PermissionChanges, string, error) {
+	settings, _, err := loadClaudeSettings(projectDir, projectLevel)
+	if err != nil {
+		return claude.PermissionChanges{}, "", err
+	}
+	return settings.PlanPermissions(buildClaudePermissions(specsDir)), settings.FilePath(), nil
+}
+
+// loadClaudeSettings loads the project-level or global Claude settings and
+// returns them with a label for error messages ("project" or "global").
+func loadClaudeSettings(projectDir string, projectLevel bool) (*claude.Settings, string, error) {
+	var settings *claude.Settings
+	var err error
+	var configLocation string
+
+	if projectLevel {
+		settings, err = claude.Load(projectDir)
+		configLocation = "project"
+	} else {
+		settings, err = claude.LoadGlobal()
+		configLocation = "global"
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("loading claude %s settings: %w", configLocation, err)
+	}
+	return settings, configLocation, nil
+}
+
+// RequiredPermissions returns the Claude permissions autospec needs for specsDir.
+func (c *Claude) RequiredPermissions(specsDir string) []string {
+	return buildClaudePermissions(specsDir)
+}
+
 // buildClaudePermissions generates the list of permissions required for autospec.
 func buildClaudePermissions(specsDir string) []string {
 	return []string{
@@ -105,24 +132,24 @@ func buildClaudePermissions(specsDir string) []string {
 	}
 }
 
-// checkDenyConflicts checks if any required permissions are in the deny list.
+// checkDenyConflicts checks if any deny rule blocks the required permissions,
+// including broader rules such as "Bash" or a parent directory.
 // Returns a warning message if conflicts are found, empty string otherwise.
 func checkDenyConflicts(settings *claude.Settings, permissions []string) string {
-	var denied []string
-	for _, perm := range permissions {
-		if settings.CheckDenyList(perm) {
-			denied = append(denied, perm)
-		}
-	}
-
-	if len(denied) == 0 {
+	conflicts := settings.FindDenyConflicts(permissions)
+	if len(conflicts) == 0 {
 		return ""
 	}
 
+	var denied []string
+	for _, c := range conflicts {
+		denied = append(denied, c.Blocks...)
+	}
+	const hint = " (run 'autospec config fix-permissions' to resolve)"
 	if len(denied) == 1 {
-		return fmt.Sprintf("permission %s is explicitly denied in settings", denied[0])
+		return fmt.Sprintf("permission %s is explicitly denied in settings", denied[0]) + hint
 	}
-	return fmt.Sprintf("permissions %v are explicitly denied in settings", denied)
+	return fmt.Sprintf("permissions %v are explicitly denied in settings", denied) + hint
 }
 
 // GetSandboxPaths returns the paths required for Claude sandbox write access.
@@ -25,3 +25,10 @@ func NewCline() *Cline {
 		},
 	}
 }
+
+// ConfigureProject implements the Configurator interface for Cline.
+// It installs the command templates to .clinerules/workflows/, where Cline
+// runs them as /autospec.<name>.md workflows.
+func (c *Cline) ConfigureProject(projectDir, _ string, _ bool) (ConfigResult, error) {
+	return installAgentCommands(c.AgentName, projectDir)
+}
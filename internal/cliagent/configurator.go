@@ -1,5 +1,11 @@
 package cliagent
 
+import (
+	"fmt"
+
+	"github.com/ariel-frischer/autospec/internal/commands"
+)
+
 // Configurator is an optional interface that agents can implement to provide
 // project-level setup and configuration. Agents implementing this interface
 // can configure settings files, permissions, and other project-specific
@@ -35,6 +41,10 @@ type ConfigResult struct {
 	// Warning contains an optional warning message, such as when a deny list
 	// entry conflicts with required permissions.
 	Warning string
+
+	// CommandsDir is where the /autospec.* command templates were installed,
+	// relative to the project directory. Empty if none were installed.
+	CommandsDir string
 }
 
 // SandboxResult describes the outcome of sandbox configuration.
@@ -85,6 +95,16 @@ func Configure(agent Agent, projectDir, specsDir string, projectLevel bool) (*Co
 	return &result, nil
 }
 
+// installAgentCommands installs the command templates in the agent's command
+// format. Used by agents whose only project setup is their commands.
+func installAgentCommands(agentName, projectDir string) (ConfigResult, error) {
+	if _, err := commands.InstallTemplatesForAgent(agentName, projectDir); err != nil {
+		return ConfigResult{}, fmt.Errorf("installing %s commands: %w", agentName, err)
+	}
+	dir, _ := commands.GetCommandsDir(agentName)
+	return ConfigResult{CommandsDir: dir}, nil
+}
+
 // IsConfigurator returns true if the given agent implements the Configurator
 // interface.
 func IsConfigurator(agent Agent) bool {
@@ -722,3 +722,42 @@ func TestClaudeConfigureProject_SpecsDirWithSpaces(t *testing.T) {
 		t.Error("expected Edit(my specs/**) in permissions")
 	}
 }
+
+func TestCommandOnlyConfigureProject(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		agent    Configurator
+		wantFile string
+	}{
+		"gemini installs TOML commands": {
+			agent:    NewGemini(),
+			wantFile: filepath.Join(".gemini", "commands", "autospec.plan.toml"),
+		},
+		"cline installs workflows": {
+			agent:    NewCline(),
+			wantFile: filepath.Join(".clinerules", "workflows", "autospec.plan.md"),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			tempDir := t.TempDir()
+
+			result, err := tt.agent.ConfigureProject(tempDir, "specs", false)
+			if err != nil {
+				t.Fatalf("ConfigureProject() error = %v", err)
+			}
+			if result.CommandsDir != filepath.Dir(tt.wantFile) {
+				t.Errorf("CommandsDir = %q, want %q", result.CommandsDir, filepath.Dir(tt.wantFile))
+			}
+			if len(result.PermissionsAdded) != 0 {
+				t.Errorf("PermissionsAdded = %v, want none", result.PermissionsAdded)
+			}
+			if _, err := os.Stat(filepath.Join(tempDir, tt.wantFile)); err != nil {
+				t.Errorf("expected %s to be installed: %v", tt.wantFile, err)
+			}
+		})
+	}
+}
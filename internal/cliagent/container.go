@@ -0,0 +1,290 @@
+package cliagent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/commands"
+)
+
+// ContainerRuntimes lists the supported container CLIs.
+var ContainerRuntimes = []string{"docker", "podman"}
+
+// ContainerConfig runs an agent inside a container (config: container.<agent>).
+type ContainerConfig struct {
+	// Image is the image with the agent CLI installed.
+	Image string `koanf:"image" yaml:"image"`
+
+	// Runtime is "docker" or "podman". Empty uses docker when it is in PATH,
+	// podman otherwise.
+	Runtime string `koanf:"runtime" yaml:"runtime"`
+
+	// Mounts are extra volumes in the runtime's -v syntax
+	// ("host:container[:options]"). The project is always mounted read-write.
+	Mounts []string `koanf:"mounts" yaml:"mounts"`
+
+	// Env sets variables in the container, on top of those the agent session
+	// sets (env, stage_env).
+	Env map[string]string `koanf:"env" yaml:"env"`
+
+	// PassEnv lists host variables passed into the container, such as API
+	// keys. Variables the agent requires are always passed when set.
+	PassEnv []string `koanf:"pass_env" yaml:"pass_env"`
+
+	// User is passed to --user, e.g. "1000:1000" so files written to the
+	// project are owned by you (optional).
+	User string `koanf:"user" yaml:"user"`
+
+	// Args are extra arguments for the runtime's run command, e.g. --network.
+	Args []string `koanf:"args" yaml:"args"`
+
+	// Command is the agent CLI in the image, when it differs from the local
+	// command name or is not in the image's PATH.
+	Command string `koanf:"command" yaml:"command"`
+
+	// Stages lists the stages that run in the container. Empty means all
+	// stages, as for RemoteConfig.Stages.
+	Stages []string `koanf:"stages" yaml:"stages"`
+}
+
+// Validate checks the image, runtime and mounts.
+func (c ContainerConfig) Validate() error {
+	if strings.TrimSpace(c.Image) == "" {
+		return fmt.Errorf("image is required")
+	}
+	if c.Runtime != "" && !containsString(ContainerRuntimes, c.Runtime) {
+		return fmt.Errorf("unknown runtime %q (valid: %s)", c.Runtime, strings.Join(ContainerRuntimes, ", "))
+	}
+	for _, m := range c.Mounts {
+		if host, target, ok := strings.Cut(m, ":"); !ok || host == "" || target == "" {
+			return fmt.Errorf("mount %q must be host:container[:options]", m)
+		}
+	}
+	for name := range c.Env {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid env variable name %q", name)
+		}
+	}
+	for _, name := range c.PassEnv {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("invalid pass_env variable name %q", name)
+		}
+	}
+	return nil
+}
+
+// RunsStage reports whether stage runs in the container.
+func (c ContainerConfig) RunsStage(stage string) bool {
+	return len(c.Stages) == 0 || containsString(c.Stages, stage)
+}
+
+// runtime returns the container CLI to run.
+func (c ContainerConfig) runtime() string {
+	if c.Runtime != "" {
+		return c.Runtime
+	}
+	if _, err := exec.LookPath("docker"); err != nil {
+		if _, err := exec.LookPath("podman"); err == nil {
+			return "podman"
+		}
+	}
+	return "docker"
+}
+
+// promptFromStdin is the container's entrypoint script. It reads the prompt
+// from stdin and substitutes it for the argument at position $0, so long
+// prompts stay out of the runtime's command line.
+const promptFromStdin = `p=$(cat); i=0; for a do i=$((i+1)); shift; if [ "$i" = "$0" ]; then a=$p; fi; set -- "$@" "$a"; done; exec "$@"`
+
+// ContainerAgent runs another agent's command line inside a container. The
+// project root is mounted read-write at the same path, so absolute paths such
+// as AUTOSPEC_SPECS_DIR and context files work unchanged, and the agent runs
+// in the same working directory as it would on the host.
+type ContainerAgent struct {
+	Agent  Agent
+	Config ContainerConfig
+
+	// Root is the project root mounted into the container ("" is the
+	// current directory).
+	Root string
+
+	runner BaseAgent // runs the container CLI
+}
+
+// NewContainerAgent wraps agent to run in the container described by cfg.
+func NewContainerAgent(agent Agent, cfg ContainerConfig) *ContainerAgent {
+	return &ContainerAgent{
+		Agent:  agent,
+		Config: cfg,
+		runner: BaseAgent{AgentName: agent.Name() + " (" + cfg.Image + ")", Cmd: cfg.runtime()},
+	}
+}
+
+// Name returns the wrapped agent's name.
+func (c *ContainerAgent) Name() string { return c.Agent.Name() }
+
+// Version returns the version of the local agent CLI.
+func (c *ContainerAgent) Version() (string, error) { return c.Agent.Version() }
+
+// Capabilities returns the wrapped agent's capabilities.
+func (c *ContainerAgent) Capabilities() Caps { return c.Agent.Capabilities() }
+
+// Validate checks the container config and that the runtime is in PATH.
+// Whether the image has the agent CLI is only known when a session starts.
+func (c *ContainerAgent) Validate() error {
+	if err := c.Config.Validate(); err != nil {
+		return fmt.Errorf("container %s: %w", c.Agent.Name(), err)
+	}
+	if _, err := exec.LookPath(c.runner.Cmd); err != nil {
+		return fmt.Errorf("container %s: %s not found in PATH", c.Agent.Name(), c.runner.Cmd)
+	}
+	return nil
+}
+
+// BuildCommand builds the wrapped agent's command and returns a runtime
+// command that runs it in a new container. Headless sessions receive the
+// prompt on stdin; interactive ones keep it in the arguments since stdin is
+// the terminal. Variables the agent adds to the local environment and the
+// configured env are passed by name, so their values stay out of the
+// runtime's command line.
+func (c *ContainerAgent) BuildCommand(prompt string, opts ExecOptions) (*exec.Cmd, error) {
+	local, err := c.Agent.BuildCommand(prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+	root, err := filepath.Abs(c.root())
+	if err != nil {
+		return nil, err
+	}
+	workDir := root
+	if opts.WorkDir != "" {
+		if workDir, err = filepath.Abs(opts.WorkDir); err != nil {
+			return nil, err
+		}
+	}
+
+	args := []string{"run", "--rm", "-i"}
+	if opts.Interactive {
+		args = append(args, "-t")
+	}
+	args = append(args, "-v", root+":"+root, "-w", workDir)
+	if workDir != root && !strings.HasPrefix(workDir, root+string(filepath.Separator)) {
+		args = append(args, "-v", workDir+":"+workDir)
+	}
+	for _, m := range c.Config.Mounts {
+		args = append(args, "-v", expandHome(m))
+	}
+	if c.Config.User != "" {
+		args = append(args, "--user", c.Config.User)
+	}
+
+	env := append([]string{}, local.Env...)
+	if env == nil {
+		env = os.Environ()
+	}
+	names := envNames(envDelta(env, os.Environ()))
+	for _, name := range append(append([]string{}, c.Agent.Capabilities().RequiredEnv...), c.Config.PassEnv...) {
+		if _, ok := os.LookupEnv(name); ok {
+			names = append(names, name)
+		}
+	}
+	for _, name := range sortedKeys(c.Config.Env) {
+		env = append(env, name+"="+c.Config.Env[name])
+		names = append(names, name)
+	}
+	for _, name := range dedupe(names) {
+		args = append(args, "-e", name)
+	}
+	args = append(args, c.Config.Args...)
+	args = append(args, c.Config.Image)
+
+	agentArgs := append([]string{}, local.Args...)
+	if c.Config.Command != "" {
+		agentArgs[0] = c.Config.Command
+	}
+	cmd := exec.Command(c.runner.Cmd)
+	if i := promptArg(agentArgs, prompt); i > 0 && !opts.Interactive {
+		cmd.Stdin = strings.NewReader(agentArgs[i])
+		agentArgs[i] = ""
+		args = append(args, "sh", "-c", promptFromStdin, fmt.Sprint(i+1))
+	}
+	cmd.Args = append(cmd.Args, append(args, agentArgs...)...)
+	cmd.Env = env
+	cmd.Dir = local.Dir
+	return cmd, nil
+}
+
+// Execute runs the session in a new container.
+func (c *ContainerAgent) Execute(ctx context.Context, prompt string, opts ExecOptions) (*Result, error) {
+	cmd, err := c.BuildCommand(prompt, opts)
+	if err != nil {
+		return nil, fmt.Errorf("building command: %w", err)
+	}
+	return c.runner.runCommand(ctx, cmd, opts)
+}
+
+func (c *ContainerAgent) root() string {
+	if c.Root == "" {
+		return "."
+	}
+	return c.Root
+}
+
+// promptArg returns the index of the argument that carries the prompt, as
+// given or with its slash command expanded, or -1. Agents that embed the
+// prompt in a larger argument keep it on the command line.
+func promptArg(args []string, prompt string) int {
+	expanded := commands.ExpandSlashCommand(prompt)
+	for i := 1; i < len(args); i++ {
+		if args[i] == prompt || args[i] == expanded {
+			return i
+		}
+	}
+	return -1
+}
+
+// envNames returns the names of NAME=value entries.
+func envNames(env []string) []string {
+	names := make([]string, 0, len(env))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		names = append(names, name)
+	}
+	return names
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dedupe returns names without repeats, keeping the first occurrence.
+func dedupe(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	out := names[:0]
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
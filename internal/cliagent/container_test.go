@@ -0,0 +1,146 @@
+package cliagent
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContainerConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config ContainerConfig
+		errMsg string // empty means valid
+	}{
+		"valid":           {config: ContainerConfig{Image: "agent:1", Runtime: "podman", Mounts: []string{"~/.claude:/root/.claude:ro"}}},
+		"missing image":   {config: ContainerConfig{Runtime: "docker"}, errMsg: "image is required"},
+		"unknown runtime": {config: ContainerConfig{Image: "agent:1", Runtime: "lxc"}, errMsg: `unknown runtime "lxc"`},
+		"bad mount":       {config: ContainerConfig{Image: "agent:1", Mounts: []string{"/data"}}, errMsg: "host:container"},
+		"bad env name":    {config: ContainerConfig{Image: "agent:1", Env: map[string]string{"A=B": "x"}}, errMsg: "invalid env variable name"},
+		"bad pass_env":    {config: ContainerConfig{Image: "agent:1", PassEnv: []string{""}}, errMsg: "invalid pass_env"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.config.Validate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestContainerAgent_BuildCommand(t *testing.T) {
+	t.Parallel()
+
+	agent, err := NewCustomAgentFromConfig(CustomAgentConfig{Command: "myagent", Args: []string{"-p", "{{PROMPT}}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	container := NewContainerAgent(agent, ContainerConfig{
+		Image:   "agent:1",
+		Runtime: "podman",
+		Mounts:  []string{"/cache:/cache"},
+		Env:     map[string]string{"IN_CONTAINER": "1"},
+		User:    "1000:1000",
+		Command: "/usr/local/bin/myagent",
+	})
+	container.Root = root
+
+	t.Run("headless prompt on stdin", func(t *testing.T) {
+		t.Parallel()
+		workDir := filepath.Join(root, "api")
+		cmd, err := container.BuildCommand("it's done", ExecOptions{WorkDir: workDir, Env: map[string]string{"SESSION": "x"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{
+			"podman", "run", "--rm", "-i",
+			"-v", root + ":" + root, "-w", workDir,
+			"-v", "/cache:/cache", "--user", "1000:1000",
+			"-e", "SESSION", "-e", "IN_CONTAINER",
+			"agent:1", "sh", "-c", promptFromStdin, "3",
+			"/usr/local/bin/myagent", "-p", "",
+		}
+		if strings.Join(cmd.Args, "\n") != strings.Join(want, "\n") {
+			t.Errorf("args =\n%q\nwant\n%q", cmd.Args, want)
+		}
+		stdin, _ := io.ReadAll(cmd.Stdin)
+		if string(stdin) != "it's done" {
+			t.Errorf("stdin = %q", stdin)
+		}
+		if !containsString(cmd.Env, "IN_CONTAINER=1") || !containsString(cmd.Env, "SESSION=x") {
+			t.Error("runtime env should carry the values passed by name")
+		}
+	})
+
+	t.Run("interactive prompt in args", func(t *testing.T) {
+		t.Parallel()
+		cmd, err := container.BuildCommand("hello", ExecOptions{Interactive: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cmd.Stdin != nil || cmd.Args[4] != "-t" || cmd.Args[len(cmd.Args)-1] != "hello" {
+			t.Errorf("args = %q", cmd.Args)
+		}
+	})
+}
+
+// TestContainerAgent_Execute runs a session through a fake runtime that
+// skips the run flags and executes the container command on the host.
+func TestContainerAgent_Execute(t *testing.T) {
+	bin := t.TempDir()
+	fake := `#!/bin/sh
+shift
+while [ $# -gt 0 ]; do
+  case "$1" in
+    -v|-e|--user) shift 2 ;;
+    -w) cd "$2" && shift 2 ;;
+    -*) shift ;;
+    *) shift; break ;;
+  esac
+done
+exec "$@"
+`
+	writeTestFile(t, filepath.Join(bin, "docker"), fake)
+	if err := os.Chmod(filepath.Join(bin, "docker"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	root := t.TempDir()
+	agent, err := NewCustomAgentFromConfig(CustomAgentConfig{
+		Command: "sh",
+		Args:    []string{"-c", `printf '%s' "$1" > prompt.txt`, "sh", "{{PROMPT}}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	container := NewContainerAgent(agent, ContainerConfig{Image: "agent:1", Runtime: "docker"})
+	container.Root = root
+
+	prompt := "line one\nit's \"quoted\" $HOME"
+	result, err := container.Execute(context.Background(), prompt, ExecOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExitCode != 0 {
+		t.Fatalf("exit code %d: %s", result.ExitCode, result.Stderr)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "prompt.txt"))
+	if err != nil || string(data) != prompt {
+		t.Errorf("prompt.txt = %q, %v; want %q", data, err, prompt)
+	}
+}
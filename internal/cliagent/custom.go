@@ -9,6 +9,9 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/ariel-frischer/autospec/internal/commands"
+	"github.com/ariel-frischer/autospec/internal/shell"
 )
 
 const promptPlaceholder = "{{PROMPT}}"
@@ -27,6 +30,10 @@ type CustomAgentConfig struct {
 
 	// PostProcessor is an optional command to pipe stdout through (e.g., "cclean").
 	PostProcessor string `koanf:"post_processor" yaml:"post_processor"`
+
+	// ExpandCommands replaces /autospec.* prompts with the command's template
+	// text, for tools that do not read autospec's installed commands (e.g., aider).
+	ExpandCommands bool `koanf:"expand_commands" yaml:"expand_commands"`
 }
 
 // IsValid returns true if the config has at least a command specified.
@@ -121,8 +128,13 @@ func (c *CustomAgent) Capabilities() Caps {
 }
 
 // BuildCommand constructs an exec.Cmd by expanding args with the prompt.
-// If a post-processor is configured, it wraps the command in a shell pipe.
+// If a post-processor is configured, it wraps the command in a pipe through
+// the configured shell (sh, or cmd on Windows).
 func (c *CustomAgent) BuildCommand(prompt string, opts ExecOptions) (*exec.Cmd, error) {
+	if c.config.ExpandCommands {
+		prompt = commands.ExpandSlashCommand(prompt)
+	}
+
 	// Expand {{PROMPT}} in args
 	expandedArgs := make([]string, len(c.config.Args))
 	for i, arg := range c.config.Args {
@@ -132,8 +144,7 @@ func (c *CustomAgent) BuildCommand(prompt string, opts ExecOptions) (*exec.Cmd,
 	var cmd *exec.Cmd
 	if c.config.PostProcessor != "" {
 		// Use shell to handle piping
-		cmdLine := c.buildShellCommand(expandedArgs)
-		cmd = exec.Command("sh", "-c", cmdLine)
+		cmd = shell.Command(c.buildShellCommand(expandedArgs))
 	} else {
 		// Direct execution without shell
 		cmd = exec.Command(c.config.Command, expandedArgs...)
@@ -147,20 +158,21 @@ func (c *CustomAgent) BuildCommand(prompt string, opts ExecOptions) (*exec.Cmd,
 func (c *CustomAgent) buildShellCommand(expandedArgs []string) string {
 	// Build the main command with escaped args
 	parts := make([]string, 0, len(expandedArgs)+1)
-	parts = append(parts, escapeShellArg(c.config.Command))
+	parts = append(parts, shell.Quote(c.config.Command))
 	for _, arg := range expandedArgs {
-		parts = append(parts, escapeShellArg(arg))
+		parts = append(parts, shell.Quote(arg))
 	}
 
 	cmdLine := strings.Join(parts, " ")
 
 	// Add pipe to post-processor
-	cmdLine += " | " + escapeShellArg(c.config.PostProcessor)
+	cmdLine += " | " + shell.Quote(c.config.PostProcessor)
 
 	return cmdLine
 }
 
-// escapeShellArg escapes a string for safe use in a shell command.
+// escapeShellArg escapes a string for safe use in a POSIX shell command,
+// such as one run on a remote host.
 func escapeShellArg(s string) string {
 	// Use single quotes, escaping any embedded single quotes
 	escaped := strings.ReplaceAll(s, "'", `'\''`)
@@ -233,9 +245,8 @@ func (c *CustomAgent) runCommand(ctx context.Context, cmd *exec.Cmd, opts ExecOp
 	var err error
 	select {
 	case <-ctx.Done():
-		_ = cmd.Process.Kill()
-		<-done
-		return nil, fmt.Errorf("executing custom agent: %w", ctx.Err())
+		stopProcess(ctx, cmd, done)
+		return nil, fmt.Errorf("executing custom agent: %w", context.Cause(ctx))
 	case err = <-done:
 	}
 	duration := time.Since(start)
@@ -274,6 +274,29 @@ func TestCustomAgent_BuildCommand(t *testing.T) {
 	}
 }
 
+func TestCustomAgent_BuildCommand_ExpandCommands(t *testing.T) {
+	t.Parallel()
+
+	for _, expand := range []bool{true, false} {
+		agent, err := NewCustomAgentFromConfig(CustomAgentConfig{
+			Command:        "aider",
+			Args:           []string{"--message", "{{PROMPT}}"},
+			ExpandCommands: expand,
+		})
+		if err != nil {
+			t.Fatalf("NewCustomAgentFromConfig() error = %v", err)
+		}
+		cmd, err := agent.BuildCommand("/autospec.tasks", ExecOptions{})
+		if err != nil {
+			t.Fatalf("BuildCommand() error = %v", err)
+		}
+		got := cmd.Args[len(cmd.Args)-1]
+		if expanded := got != "/autospec.tasks"; expanded != expand {
+			t.Errorf("expand_commands=%v: prompt = %.40q...", expand, got)
+		}
+	}
+}
+
 func TestCustomAgent_BuildCommand_WithPostProcessor(t *testing.T) {
 	t.Parallel()
 
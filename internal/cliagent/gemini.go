@@ -26,3 +26,11 @@ func NewGemini() *Gemini {
 		},
 	}
 }
+
+// ConfigureProject implements the Configurator interface for Gemini.
+// It installs the command templates to .gemini/commands/ as TOML custom
+// commands. Gemini needs no permission settings, so specsDir and
+// projectLevel are unused.
+func (g *Gemini) ConfigureProject(projectDir, _ string, _ bool) (ConfigResult, error) {
+	return installAgentCommands(g.AgentName, projectDir)
+}
@@ -33,6 +33,8 @@ func NewOpenCode() *OpenCode {
 				AutonomousFlag: "",
 				RequiredEnv:    []string{},
 				OptionalEnv:    []string{"ANTHROPIC_API_KEY", "OPENAI_API_KEY", "GEMINI_API_KEY"},
+				// Passed via --command; installed to .opencode/command by ConfigureProject
+				NativeCommands: true,
 			},
 		},
 	}
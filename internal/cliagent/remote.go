@@ -0,0 +1,376 @@
+package cliagent
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RemoteConfig runs an agent on another host over ssh (config: remote.<agent>).
+type RemoteConfig struct {
+	// Host is the ssh destination, e.g. "devbox" or "me@devbox.example.com".
+	Host string `koanf:"host" yaml:"host"`
+
+	// Identity is the private key file passed to ssh -i (optional).
+	Identity string `koanf:"identity" yaml:"identity"`
+
+	// Port is the ssh port (0: ssh's default).
+	Port int `koanf:"port" yaml:"port"`
+
+	// Dir is the checkout of the project on the remote host.
+	Dir string `koanf:"dir" yaml:"dir"`
+
+	// Command is the agent CLI on the remote host, when it differs from the
+	// local command name or is not in the remote PATH.
+	Command string `koanf:"command" yaml:"command"`
+
+	// Stages lists the stages that run remotely. Empty means all stages, as
+	// for ContainerConfig.Stages.
+	Stages []string `koanf:"stages" yaml:"stages"`
+}
+
+// Validate checks the fields ssh needs.
+func (c RemoteConfig) Validate() error {
+	switch {
+	case strings.TrimSpace(c.Host) == "":
+		return fmt.Errorf("host is required")
+	case strings.HasPrefix(strings.TrimSpace(c.Host), "-"):
+		// ssh would parse it as an option
+		return fmt.Errorf("host %q must not start with '-'", c.Host)
+	case strings.TrimSpace(c.Dir) == "":
+		return fmt.Errorf("dir is required (the project checkout on %s)", c.Host)
+	case c.Port < 0 || c.Port > 65535:
+		return fmt.Errorf("port %d is out of range", c.Port)
+	}
+	return nil
+}
+
+// RunsStage reports whether stage runs on the remote host.
+func (c RemoteConfig) RunsStage(stage string) bool {
+	return len(c.Stages) == 0 || containsString(c.Stages, stage)
+}
+
+// RemoteAgent runs another agent's command line on a remote host through
+// ssh. Before each session the Sync paths are copied to the remote checkout,
+// and afterwards copied back, so artifacts the agent writes there land in the
+// local spec directory. Other changes, such as code written during
+// implement, stay on the remote host.
+type RemoteAgent struct {
+	Agent  Agent
+	Config RemoteConfig
+
+	// Sync lists paths relative to the project root (e.g., "specs/003-auth")
+	// copied to the remote host before a session and back after it.
+	Sync []string
+
+	// Root is the local project root that Sync paths and working
+	// directories are relative to ("" is the current directory).
+	Root string
+
+	ssh BaseAgent // runs the ssh client
+}
+
+// NewRemoteAgent wraps agent to run on the host in cfg.
+func NewRemoteAgent(agent Agent, cfg RemoteConfig, sync []string) *RemoteAgent {
+	return &RemoteAgent{
+		Agent:  agent,
+		Config: cfg,
+		Sync:   sync,
+		ssh:    BaseAgent{AgentName: agent.Name() + "@" + cfg.Host, Cmd: "ssh"},
+	}
+}
+
+// Name returns the wrapped agent's name.
+func (r *RemoteAgent) Name() string { return r.Agent.Name() }
+
+// Version returns the version of the local agent CLI.
+func (r *RemoteAgent) Version() (string, error) { return r.Agent.Version() }
+
+// Capabilities returns the wrapped agent's capabilities.
+func (r *RemoteAgent) Capabilities() Caps { return r.Agent.Capabilities() }
+
+// Validate checks the remote config and that ssh is in PATH. The remote
+// agent CLI is only found when a session starts.
+func (r *RemoteAgent) Validate() error {
+	if err := r.Config.Validate(); err != nil {
+		return fmt.Errorf("remote %s: %w", r.Agent.Name(), err)
+	}
+	if _, err := exec.LookPath("ssh"); err != nil {
+		return fmt.Errorf("remote %s: ssh not found in PATH", r.Agent.Name())
+	}
+	return nil
+}
+
+// BuildCommand builds the wrapped agent's command and returns an ssh command
+// that runs it in the remote checkout. Environment variables the agent adds
+// to the local environment are passed with env(1); the rest of the local
+// environment is not forwarded.
+func (r *RemoteAgent) BuildCommand(prompt string, opts ExecOptions) (*exec.Cmd, error) {
+	local, err := r.Agent.BuildCommand(prompt, opts)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := r.remoteDir(opts.WorkDir)
+	if err != nil {
+		return nil, err
+	}
+
+	args := append([]string{}, local.Args...)
+	if r.Config.Command != "" {
+		args[0] = r.Config.Command
+	}
+	words := []string{"cd", quoteRemotePath(dir), "&&", "exec", "env"}
+	for _, kv := range envDelta(local.Env, os.Environ()) {
+		words = append(words, escapeShellArg(kv))
+	}
+	for _, arg := range args {
+		words = append(words, escapeShellArg(arg))
+	}
+
+	cmd := exec.Command("ssh", r.sshArgs(opts.Interactive, strings.Join(words, " "))...)
+	cmd.Dir = r.Root
+	return cmd, nil
+}
+
+// Execute copies the Sync paths to the remote host, runs the session there
+// and copies them back, also after a failed session. Interactive sessions
+// run as a subprocess so the copy back can happen.
+func (r *RemoteAgent) Execute(ctx context.Context, prompt string, opts ExecOptions) (*Result, error) {
+	if err := r.Push(ctx); err != nil {
+		return nil, err
+	}
+	opts.ReplaceProcess = false
+	cmd, err := r.BuildCommand(prompt, opts)
+	if err != nil {
+		return nil, fmt.Errorf("building command: %w", err)
+	}
+	result, runErr := r.ssh.runCommand(ctx, cmd, opts)
+	// A cancelled session must still bring back what the agent wrote
+	if err := r.Pull(context.WithoutCancel(ctx)); err != nil {
+		return result, errors.Join(runErr, err)
+	}
+	return result, runErr
+}
+
+// Push copies the Sync paths that exist locally to the remote checkout.
+func (r *RemoteAgent) Push(ctx context.Context) error {
+	var archive bytes.Buffer
+	if err := r.writeArchive(&archive); err != nil {
+		return fmt.Errorf("packing files for %s: %w", r.Config.Host, err)
+	}
+	dir := quoteRemotePath(r.Config.Dir)
+	script := fmt.Sprintf("mkdir -p %s && cd %s && tar -xf -", dir, dir)
+	cmd := exec.CommandContext(ctx, "ssh", r.sshArgs(false, script)...)
+	cmd.Stdin = &archive
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("copying %s to %s: %w: %s", strings.Join(r.Sync, ", "), r.Config.Host, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Pull copies the Sync paths back from the remote checkout, overwriting the
+// local files. Files deleted on the remote host are kept locally.
+func (r *RemoteAgent) Pull(ctx context.Context) error {
+	quoted := make([]string, len(r.Sync))
+	for i, p := range r.Sync {
+		quoted[i] = escapeShellArg(filepath.ToSlash(p))
+	}
+	// Pack only the paths that exist, and nothing when none do
+	script := fmt.Sprintf(`cd %s && set -- && for p in %s; do [ -e "$p" ] && set -- "$@" "$p"; done; [ $# -eq 0 ] || tar -cf - "$@"`,
+		quoteRemotePath(r.Config.Dir), strings.Join(quoted, " "))
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "ssh", r.sshArgs(false, script)...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("copying %s back from %s: %w: %s", strings.Join(r.Sync, ", "), r.Config.Host, err, strings.TrimSpace(stderr.String()))
+	}
+	if stdout.Len() == 0 {
+		return nil
+	}
+	if err := r.extractArchive(&stdout); err != nil {
+		return fmt.Errorf("unpacking files from %s: %w", r.Config.Host, err)
+	}
+	return nil
+}
+
+func (r *RemoteAgent) root() string {
+	if r.Root == "" {
+		return "."
+	}
+	return r.Root
+}
+
+// sshArgs returns the ssh arguments that run script on the host. A terminal
+// is requested for interactive sessions only, since headless output is
+// parsed as a stream.
+func (r *RemoteAgent) sshArgs(interactive bool, script string) []string {
+	var args []string
+	if r.Config.Identity != "" {
+		args = append(args, "-i", expandHome(r.Config.Identity))
+	}
+	if r.Config.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(r.Config.Port))
+	}
+	if interactive {
+		args = append(args, "-t")
+	}
+	// "--" ends ssh's options, so the host is never read as one
+	return append(args, "--", r.Config.Host, script)
+}
+
+// remoteDir maps a local working directory inside the project root to the
+// same directory in the remote checkout.
+func (r *RemoteAgent) remoteDir(workDir string) (string, error) {
+	if workDir == "" {
+		return r.Config.Dir, nil
+	}
+	root, err := filepath.Abs(r.root())
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, workDir)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("workdir %s is outside the project and has no counterpart on %s", workDir, r.Config.Host)
+	}
+	return path.Join(r.Config.Dir, filepath.ToSlash(rel)), nil
+}
+
+// writeArchive writes the regular files and directories under the Sync
+// paths as a tar archive with slash-separated relative names.
+func (r *RemoteAgent) writeArchive(w io.Writer) error {
+	tw := tar.NewWriter(w)
+	for _, rel := range r.Sync {
+		err := filepath.Walk(filepath.Join(r.root(), rel), func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !info.Mode().IsRegular() && !info.IsDir() {
+				return nil
+			}
+			name, err := filepath.Rel(r.root(), p)
+			if err != nil {
+				return err
+			}
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(name)
+			if info.IsDir() {
+				hdr.Name += "/"
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			_, err = tw.Write(data)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// extractArchive writes the regular files and directories of a tar archive
+// under Root. Entries outside the Sync paths are rejected.
+func (r *RemoteAgent) extractArchive(rd io.Reader) error {
+	tr := tar.NewReader(rd)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(path.Clean(hdr.Name))
+		if !r.inSync(name) {
+			return fmt.Errorf("unexpected file %s in archive", hdr.Name)
+		}
+		target := filepath.Join(r.root(), name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(target, data, hdr.FileInfo().Mode().Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// inSync reports whether a cleaned relative name is one of the Sync paths or
+// inside one.
+func (r *RemoteAgent) inSync(name string) bool {
+	for _, p := range r.Sync {
+		p = filepath.Clean(p)
+		if name == p || strings.HasPrefix(name, p+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// envDelta returns the entries of env that are not in base: the variables an
+// agent adds or overrides on top of the process environment.
+func envDelta(env, base []string) []string {
+	inBase := make(map[string]bool, len(base))
+	for _, kv := range base {
+		inBase[kv] = true
+	}
+	var delta []string
+	for _, kv := range env {
+		if !inBase[kv] {
+			delta = append(delta, kv)
+		}
+	}
+	return delta
+}
+
+// quoteRemotePath quotes a path for the remote shell. A leading ~/ stays
+// unquoted so the remote shell expands it to the remote home directory.
+func quoteRemotePath(s string) string {
+	if strings.HasPrefix(s, "~/") {
+		return "~/" + escapeShellArg(s[2:])
+	}
+	return escapeShellArg(s)
+}
+
+// expandHome expands a leading ~/ to the local home directory.
+func expandHome(p string) string {
+	if strings.HasPrefix(p, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, p[2:])
+		}
+	}
+	return p
+}
@@ -0,0 +1,170 @@
+package cliagent
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRemoteConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config RemoteConfig
+		errMsg string // empty means valid
+	}{
+		"valid":        {config: RemoteConfig{Host: "devbox", Dir: "~/src/app"}},
+		"missing host": {config: RemoteConfig{Dir: "~/src/app"}, errMsg: "host is required"},
+		"option host":  {config: RemoteConfig{Host: "-oProxyCommand=touch /tmp/x", Dir: "~/src/app"}, errMsg: "must not start with '-'"},
+		"missing dir":  {config: RemoteConfig{Host: "devbox"}, errMsg: "dir is required"},
+		"bad port":     {config: RemoteConfig{Host: "devbox", Dir: "/src", Port: 70000}, errMsg: "out of range"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.config.Validate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestRemoteConfig_RunsStage(t *testing.T) {
+	t.Parallel()
+
+	def := RemoteConfig{}
+	if !def.RunsStage("implement") || !def.RunsStage("plan") {
+		t.Error("default stages should be all stages")
+	}
+	listed := RemoteConfig{Stages: []string{"plan", "tasks"}}
+	if !listed.RunsStage("tasks") || listed.RunsStage("implement") {
+		t.Error("listed stages should replace the default")
+	}
+}
+
+func TestRemoteAgent_BuildCommand(t *testing.T) {
+	t.Parallel()
+
+	agent, err := NewCustomAgentFromConfig(CustomAgentConfig{Command: "myagent", Args: []string{"-p", "{{PROMPT}}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	remote := NewRemoteAgent(agent, RemoteConfig{Host: "me@devbox", Port: 2222, Dir: "~/src/app", Command: "/opt/bin/myagent"}, nil)
+	remote.Root = root
+
+	cmd, err := remote.BuildCommand("it's done", ExecOptions{
+		WorkDir: filepath.Join(root, "services", "api"),
+		Env:     map[string]string{"AUTOSPEC_TEST_REMOTE": "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args := cmd.Args
+	if args[0] != "ssh" || args[1] != "-p" || args[2] != "2222" || args[3] != "--" || args[4] != "me@devbox" {
+		t.Fatalf("ssh args = %q", args)
+	}
+	want := `cd ~/'src/app/services/api' && exec env 'AUTOSPEC_TEST_REMOTE=1' '/opt/bin/myagent' '-p' 'it'\''s done'`
+	if args[5] != want {
+		t.Errorf("remote script =\n%s\nwant\n%s", args[5], want)
+	}
+
+	if _, err := remote.BuildCommand("x", ExecOptions{WorkDir: t.TempDir()}); err == nil {
+		t.Error("expected an error for a workdir outside the project")
+	}
+}
+
+func TestRemoteAgent_ArchiveRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := t.TempDir()
+	writeTestFile(t, filepath.Join(src, "specs", "001-x", "spec.yaml"), "feature: x\n")
+	writeTestFile(t, filepath.Join(src, "specs", "002-y", "spec.yaml"), "not synced\n")
+
+	var archive bytes.Buffer
+	from := &RemoteAgent{Root: src, Sync: []string{filepath.Join("specs", "001-x"), "missing"}}
+	if err := from.writeArchive(&archive); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := t.TempDir()
+	to := &RemoteAgent{Root: dst, Sync: from.Sync}
+	if err := to.extractArchive(bytes.NewReader(archive.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dst, "specs", "001-x", "spec.yaml")); err != nil || string(data) != "feature: x\n" {
+		t.Errorf("synced file = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "specs", "002-y")); !os.IsNotExist(err) {
+		t.Error("unsynced spec should not be copied")
+	}
+
+	other := &RemoteAgent{Root: t.TempDir(), Sync: []string{"other"}}
+	if err := other.extractArchive(bytes.NewReader(archive.Bytes())); err == nil {
+		t.Error("expected an error for archive entries outside the Sync paths")
+	}
+}
+
+// TestRemoteAgent_Execute runs a session through a fake ssh that executes
+// the remote script locally, with a temp directory as the remote checkout.
+func TestRemoteAgent_Execute(t *testing.T) {
+	bin := t.TempDir()
+	writeTestFile(t, filepath.Join(bin, "ssh"), "#!/bin/sh\neval \"script=\\${$#}\"\nexec sh -c \"$script\"\n")
+	if err := os.Chmod(filepath.Join(bin, "ssh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	root, remoteDir := t.TempDir(), t.TempDir()
+	spec := filepath.Join("specs", "001-x")
+	writeTestFile(t, filepath.Join(root, spec, "spec.yaml"), "feature: x\n")
+
+	agent, err := NewCustomAgentFromConfig(CustomAgentConfig{
+		Command: "sh",
+		Args:    []string{"-c", `cp specs/001-x/spec.yaml specs/001-x/plan.yaml && echo "$GREETING" > specs/001-x/env.txt && touch code.go`, "{{PROMPT}}"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	remote := NewRemoteAgent(agent, RemoteConfig{Host: "devbox", Dir: remoteDir}, []string{spec})
+	remote.Root = root
+
+	if _, err := remote.Execute(context.Background(), "plan", ExecOptions{Env: map[string]string{"GREETING": "hi"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := os.ReadFile(filepath.Join(root, spec, "plan.yaml")); err != nil || string(data) != "feature: x\n" {
+		t.Errorf("plan.yaml = %q, %v", data, err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, spec, "env.txt")); err != nil || string(data) != "hi\n" {
+		t.Errorf("env.txt = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "code.go")); !os.IsNotExist(err) {
+		t.Error("files outside the Sync paths should stay on the remote host")
+	}
+	if _, err := os.Stat(filepath.Join(remoteDir, "code.go")); err != nil {
+		t.Errorf("agent did not run in the remote checkout: %v", err)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,131 @@
+// Package codefmt runs the project's formatters (gofmt, prettier, black, ...)
+// over the files an implement session changed, before the session's output
+// is validated, so agent-written code always lands formatted.
+package codefmt
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/shell"
+)
+
+// Formatter runs a command over changed files matching its patterns
+// (config key: formatters).
+type Formatter struct {
+	// Name labels the formatter in output (default: the command's first word).
+	Name string `koanf:"name" yaml:"name" json:"name,omitempty"`
+
+	// Patterns are globs matched against changed files, e.g. "*.go". A
+	// pattern without a slash matches the file name in any directory; one
+	// with a slash matches the path relative to the repository root.
+	Patterns []string `koanf:"patterns" yaml:"patterns" json:"patterns"`
+
+	// Command is run through the shell from the repository root with the
+	// matching files appended as arguments, e.g. "gofmt -w".
+	Command string `koanf:"command" yaml:"command" json:"command"`
+}
+
+// Label returns Name, or the first word of Command.
+func (f Formatter) Label() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	if fields := strings.Fields(f.Command); len(fields) > 0 {
+		return fields[0]
+	}
+	return "formatter"
+}
+
+// Validate checks that the formatter has a command and valid patterns.
+func (f Formatter) Validate() error {
+	if strings.TrimSpace(f.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if len(f.Patterns) == 0 {
+		return fmt.Errorf("patterns is required (e.g. [\"*.go\"])")
+	}
+	for _, p := range f.Patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// Match returns the files that match one of the patterns. files are
+// slash-separated paths relative to the repository root.
+func (f Formatter) Match(files []string) []string {
+	return MatchPatterns(f.Patterns, files)
+}
+
+// MatchPatterns returns the files that match one of patterns. A pattern
+// without a slash matches the file name in any directory; one with a slash
+// matches the whole slash-separated path.
+func MatchPatterns(patterns, files []string) []string {
+	var matched []string
+	for _, file := range files {
+		for _, p := range patterns {
+			name := file
+			if !strings.Contains(p, "/") {
+				name = path.Base(file)
+			}
+			if ok, _ := path.Match(p, name); ok {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// Result is the outcome of one formatter run.
+type Result struct {
+	Formatter string
+	Files     []string
+	Output    string
+	Err       error
+}
+
+// Runner runs a shell command in dir and returns its combined output.
+// Injectable for testing.
+type Runner func(dir, command string) (string, error)
+
+// RunShell runs command through the configured shell (sh, or cmd on
+// Windows) in dir.
+func RunShell(dir, command string) (string, error) {
+	cmd := shell.Command(command)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// Run runs each formatter, in order, over the changed files that match it
+// and still exist under root. Formatters without matching files are skipped.
+// A failing formatter does not stop the others; its error is in its Result.
+func Run(formatters []Formatter, root string, changed []string, run Runner) []Result {
+	var existing []string
+	for _, file := range changed {
+		if info, err := os.Stat(filepath.Join(root, filepath.FromSlash(file))); err == nil && info.Mode().IsRegular() {
+			existing = append(existing, file)
+		}
+	}
+
+	var results []Result
+	for _, f := range formatters {
+		files := f.Match(existing)
+		if len(files) == 0 {
+			continue
+		}
+		quoted := make([]string, len(files))
+		for i, file := range files {
+			quoted[i] = shell.Quote(file)
+		}
+		out, err := run(root, f.Command+" "+strings.Join(quoted, " "))
+		results = append(results, Result{Formatter: f.Label(), Files: files, Output: out, Err: err})
+	}
+	return results
+}
@@ -0,0 +1,103 @@
+package codefmt
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatter_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		formatter Formatter
+		errMsg    string // empty means valid
+	}{
+		"valid":           {formatter: Formatter{Patterns: []string{"*.go"}, Command: "gofmt -w"}},
+		"missing command": {formatter: Formatter{Patterns: []string{"*.go"}}, errMsg: "command is required"},
+		"no patterns":     {formatter: Formatter{Command: "gofmt -w"}, errMsg: "patterns is required"},
+		"bad pattern":     {formatter: Formatter{Patterns: []string{"[*.go"}, Command: "gofmt -w"}, errMsg: "invalid pattern"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.formatter.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errMsg)
+		})
+	}
+}
+
+func TestFormatter_Match(t *testing.T) {
+	t.Parallel()
+
+	files := []string{"main.go", "pkg/util.go", "web/src/app.ts", "web/app.ts", "README.md"}
+
+	tests := map[string]struct {
+		patterns []string
+		want     []string
+	}{
+		"name pattern in any directory": {patterns: []string{"*.go"}, want: []string{"main.go", "pkg/util.go"}},
+		"path pattern":                  {patterns: []string{"web/src/*.ts"}, want: []string{"web/src/app.ts"}},
+		"several patterns":              {patterns: []string{"*.md", "*.ts"}, want: []string{"web/src/app.ts", "web/app.ts", "README.md"}},
+		"no match":                      {patterns: []string{"*.py"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Formatter{Patterns: tt.patterns}.Match(files))
+		})
+	}
+}
+
+func TestFormatter_Label(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "go", Formatter{Name: "go", Command: "gofmt -w"}.Label())
+	assert.Equal(t, "npx", Formatter{Command: "npx prettier --write"}.Label())
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	for _, f := range []string{"main.go", "it's.go", "app.py"} {
+		require.NoError(t, os.WriteFile(filepath.Join(root, f), nil, 0o644))
+	}
+	formatters := []Formatter{
+		{Patterns: []string{"*.go"}, Command: "gofmt -w"},
+		{Patterns: []string{"*.ts"}, Command: "prettier --write"},
+		{Name: "black", Patterns: []string{"*.py"}, Command: "black -q"},
+	}
+
+	var commands []string
+	run := func(dir, command string) (string, error) {
+		assert.Equal(t, root, dir)
+		commands = append(commands, command)
+		if command == "black -q 'app.py'" {
+			return "error: cannot format app.py", errors.New("exit status 123")
+		}
+		return "", nil
+	}
+
+	results := Run(formatters, root, []string{"main.go", "deleted.go", "it's.go", "app.py"}, run)
+
+	assert.Equal(t, []string{`gofmt -w 'main.go' 'it'\''s.go'`, "black -q 'app.py'"}, commands)
+	require.Len(t, results, 2)
+	assert.Equal(t, "gofmt", results[0].Formatter)
+	assert.Equal(t, []string{"main.go", "it's.go"}, results[0].Files)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "black", results[1].Formatter)
+	assert.Error(t, results[1].Err)
+	assert.Contains(t, results[1].Output, "cannot format")
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffLine is one line of a line diff: ' ' kept, '-' removed, '+' added.
+type diffLine struct {
+	op    byte
+	text  string
+	aLine int // 0-based line in the old text before this line
+	bLine int // 0-based line in the new text before this line
+}
+
+// UnifiedDiff returns a unified diff turning oldText into newText, with
+// oldName and newName in the header, or "" when they are equal.
+func UnifiedDiff(oldName, newName string, oldText, newText []byte) string {
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	for i := 0; i < len(lines); {
+		if lines[i].op == ' ' {
+			i++
+			continue
+		}
+		// Extend the hunk over changes separated by at most 2*diffContext
+		// unchanged lines.
+		last := i
+		for k := i; k < len(lines) && k-last <= 2*diffContext+1; k++ {
+			if lines[k].op != ' ' {
+				last = k
+			}
+		}
+		start := max(0, i-diffContext)
+		end := min(len(lines), last+diffContext+1)
+
+		if sb.Len() == 0 {
+			fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+		}
+		writeHunk(&sb, lines[start:end])
+		i = end
+	}
+	return sb.String()
+}
+
+// writeHunk writes one hunk with its @@ header.
+func writeHunk(sb *strings.Builder, hunk []diffLine) {
+	aLen, bLen := 0, 0
+	for _, l := range hunk {
+		if l.op != '+' {
+			aLen++
+		}
+		if l.op != '-' {
+			bLen++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", hunkRange(hunk[0].aLine, aLen), hunkRange(hunk[0].bLine, bLen))
+	for _, l := range hunk {
+		fmt.Fprintf(sb, "%c%s\n", l.op, l.text)
+	}
+}
+
+// hunkRange formats a hunk's start line and length. An empty range names
+// the line before it, as diff(1) does.
+func hunkRange(start, length int) string {
+	if length == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// splitLines splits text into lines without their newlines.
+func splitLines(text []byte) []string {
+	s := strings.TrimSuffix(string(text), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// diffLines returns the edit script from a to b via their longest common
+// subsequence. Command templates are a few hundred lines, so the quadratic
+// table is fine.
+func diffLines(a, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{op: ' ', text: a[i], aLine: i, bLine: j})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{op: '-', text: a[i], aLine: i, bLine: j})
+			i++
+		default:
+			lines = append(lines, diffLine{op: '+', text: b[j], aLine: i, bLine: j})
+			j++
+		}
+	}
+	return lines
+}
@@ -0,0 +1,27 @@
+package commands
+
+import "strings"
+
+// ExpandSlashCommand replaces a leading /autospec.* command with the text of
+// its template, with $ARGUMENTS set to the rest of the prompt. It is used for
+// agents that cannot resolve autospec's slash commands themselves, so the
+// workflow prompts work whichever agent runs them. Prompts that are not an
+// embedded autospec command are returned unchanged.
+func ExpandSlashCommand(prompt string) string {
+	trimmed := strings.TrimSpace(prompt)
+	if !strings.HasPrefix(trimmed, "/autospec.") {
+		return prompt
+	}
+	name, args, _ := strings.Cut(trimmed[1:], " ")
+	// The command may be followed directly by instructions on the next line
+	if before, after, found := strings.Cut(name, "\n"); found {
+		name, args = before, after+" "+args
+	}
+
+	content, err := GetTemplate(name)
+	if err != nil {
+		return prompt
+	}
+	body := TemplateBody(content)
+	return strings.ReplaceAll(body, argumentsPlaceholder, strings.TrimSpace(args))
+}
@@ -0,0 +1,55 @@
+// Package commands tests inline expansion of autospec slash commands.
+// Related: internal/commands/expand.go
+// Tags: commands, expand, slash-command, agents
+
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandSlashCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		prompt   string
+		contains []string
+		same     bool
+	}{
+		"command with arguments": {
+			prompt:   "/autospec.plan Use PostgreSQL",
+			contains: []string{"Use PostgreSQL"},
+		},
+		"instructions on the next line": {
+			prompt:   "/autospec.implement\nFocus on phase 2",
+			contains: []string{"Focus on phase 2"},
+		},
+		"not a slash command": {
+			prompt: "Fix the failing test",
+			same:   true,
+		},
+		"unknown autospec command": {
+			prompt: "/autospec.deploy now",
+			same:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := ExpandSlashCommand(tt.prompt)
+			if tt.same {
+				assert.Equal(t, tt.prompt, got)
+				return
+			}
+			assert.NotContains(t, got, "$ARGUMENTS")
+			assert.NotContains(t, got, tt.prompt)
+			assert.Contains(t, got, "## User Input")
+			for _, want := range tt.contains {
+				assert.Contains(t, got, want)
+			}
+		})
+	}
+}
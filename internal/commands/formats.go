@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// argumentsPlaceholder is where command templates receive the text given
+// after the command name.
+const argumentsPlaceholder = "$ARGUMENTS"
+
+// Format describes how an agent stores project-level custom commands, so the
+// embedded /autospec.* templates can be installed in a form it picks up.
+type Format struct {
+	Name       string // Agent or tool name (e.g., "gemini", "cursor")
+	Dir        string // Directory relative to the project root
+	Ext        string // File extension, including the dot
+	Invocation string // How a user runs a command, with %s for the command name
+	render     func(tpl CommandTemplate) []byte
+}
+
+// formats lists the supported command formats. Claude and OpenCode read the
+// templates as-is; the others get a converted copy.
+var formats = []Format{
+	{Name: "claude", Dir: filepath.Join(".claude", "commands"), Ext: ".md", Invocation: "/%s", render: renderMarkdownCommand},
+	{Name: "opencode", Dir: filepath.Join(".opencode", "command"), Ext: ".md", Invocation: "/%s", render: renderMarkdownCommand},
+	{Name: "gemini", Dir: filepath.Join(".gemini", "commands"), Ext: ".toml", Invocation: "/%s", render: renderGeminiCommand},
+	{Name: "cline", Dir: filepath.Join(".clinerules", "workflows"), Ext: ".md", Invocation: "/%s.md", render: renderPlainCommand},
+	{Name: "cursor", Dir: filepath.Join(".cursor", "rules"), Ext: ".mdc", Invocation: "@%s", render: renderCursorRule},
+	{Name: "aider", Dir: filepath.Join(".aider", "prompts"), Ext: ".md", Invocation: "/read .aider/prompts/%s.md", render: renderPlainCommand},
+}
+
+// Formats returns the supported command formats.
+func Formats() []Format {
+	return append([]Format(nil), formats...)
+}
+
+// FormatNames returns the names of the supported command formats.
+func FormatNames() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// LookupFormat returns the command format for an agent or tool name.
+func LookupFormat(name string) (Format, error) {
+	for _, f := range formats {
+		if f.Name == name {
+			return f, nil
+		}
+	}
+	return Format{}, fmt.Errorf("unknown agent: %s (command formats: %s)", name, strings.Join(FormatNames(), ", "))
+}
+
+// Render converts a command template to this format.
+func (f Format) Render(tpl CommandTemplate) []byte {
+	return f.render(tpl)
+}
+
+// Filename returns the file name a command is installed as.
+func (f Format) Filename(commandName string) string {
+	return commandName + f.Ext
+}
+
+// renderMarkdownCommand keeps the template unchanged: frontmatter, body and
+// $ARGUMENTS are understood by Claude Code and OpenCode.
+func renderMarkdownCommand(tpl CommandTemplate) []byte {
+	return tpl.Content
+}
+
+// renderGeminiCommand writes a Gemini CLI custom command. Gemini substitutes
+// {{args}} with the text after the command name.
+func renderGeminiCommand(tpl CommandTemplate) []byte {
+	body := strings.ReplaceAll(TemplateBody(tpl.Content), argumentsPlaceholder, "{{args}}")
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# autospec %s\n", tpl.Version)
+	fmt.Fprintf(&sb, "description = %q\n", tpl.Description)
+	if !strings.Contains(body, "'''") {
+		// A literal string needs no escaping
+		fmt.Fprintf(&sb, "prompt = '''\n%s'''\n", body)
+	} else {
+		escaped := strings.NewReplacer(`\`, `\\`, `"""`, `\"\"\"`).Replace(body)
+		fmt.Fprintf(&sb, "prompt = \"\"\"\n%s\"\"\"\n", escaped)
+	}
+	return []byte(sb.String())
+}
+
+// renderPlainCommand writes the template as a plain markdown prompt for tools
+// without command arguments (Cline workflows, aider). The arguments are
+// whatever the user writes alongside the command.
+func renderPlainCommand(tpl CommandTemplate) []byte {
+	body := strings.ReplaceAll(TemplateBody(tpl.Content), argumentsPlaceholder, "(the user's request accompanying this command)")
+	return []byte(fmt.Sprintf("<!-- %s v%s: %s -->\n\n%s", tpl.Name, tpl.Version, tpl.Description, body))
+}
+
+// renderCursorRule writes a Cursor rule that is applied when referenced with
+// @<name> or when the agent judges the description relevant.
+func renderCursorRule(tpl CommandTemplate) []byte {
+	body := strings.ReplaceAll(TemplateBody(tpl.Content), argumentsPlaceholder, "(the user's request accompanying this rule)")
+	return []byte(fmt.Sprintf("---\ndescription: %q\nglobs:\nalwaysApply: false\n---\n\n%s", tpl.Description, body))
+}
+
+// TemplateBody returns a command template without its YAML frontmatter.
+func TemplateBody(content []byte) string {
+	text := string(content)
+	if !strings.HasPrefix(text, "---\n") {
+		return text
+	}
+	end := strings.Index(text[4:], "\n---\n")
+	if end < 0 {
+		return text
+	}
+	return text[4+end+5:]
+}
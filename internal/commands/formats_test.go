@@ -0,0 +1,121 @@
+// Package commands tests the agent command formats.
+// Related: internal/commands/formats.go
+// Tags: commands, formats, gemini, cursor, cline, aider
+
+package commands
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupFormat(t *testing.T) {
+	t.Parallel()
+
+	format, err := LookupFormat("gemini")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(".gemini", "commands"), format.Dir)
+	assert.Equal(t, "autospec.plan.toml", format.Filename("autospec.plan"))
+
+	_, err = LookupFormat("emacs")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown agent: emacs")
+	assert.Contains(t, err.Error(), "cursor")
+}
+
+func TestFormat_Render(t *testing.T) {
+	t.Parallel()
+
+	tpl, err := GetTemplateInfo("autospec.plan")
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		contains []string
+		excludes []string
+	}{
+		"claude": {
+			contains: []string{"---\n", "$ARGUMENTS"},
+		},
+		"gemini": {
+			contains: []string{"description = ", "prompt = ", "{{args}}"},
+			excludes: []string{"$ARGUMENTS", "description: Generate YAML"},
+		},
+		"cline": {
+			contains: []string{"<!-- autospec.plan v"},
+			excludes: []string{"$ARGUMENTS", "description: Generate YAML"},
+		},
+		"cursor": {
+			contains: []string{"---\ndescription: ", "alwaysApply: false"},
+			excludes: []string{"$ARGUMENTS", "description: Generate YAML"},
+		},
+		"aider": {
+			excludes: []string{"$ARGUMENTS", "description: Generate YAML"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			format, err := LookupFormat(name)
+			require.NoError(t, err)
+
+			got := string(format.Render(tpl))
+			for _, want := range tt.contains {
+				assert.Contains(t, got, want)
+			}
+			for _, unwanted := range tt.excludes {
+				assert.NotContains(t, got, unwanted)
+			}
+		})
+	}
+}
+
+func TestRenderGeminiCommand_TripleQuotes(t *testing.T) {
+	t.Parallel()
+
+	got := string(renderGeminiCommand(CommandTemplate{
+		Name:        "autospec.test",
+		Description: "Test",
+		Version:     "1.0.0",
+		Content:     []byte("---\ndescription: Test\n---\nUse ''' and \"\"\" here: $ARGUMENTS\n"),
+	}))
+	assert.Contains(t, got, "prompt = \"\"\"\n")
+	assert.Contains(t, got, `Use ''' and \"\"\" here: {{args}}`)
+}
+
+func TestInstallTemplatesForAgent_Formats(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range FormatNames() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			projectDir := t.TempDir()
+
+			results, err := InstallTemplatesForAgent(name, projectDir)
+			require.NoError(t, err)
+			require.NotEmpty(t, results)
+
+			format, _ := LookupFormat(name)
+			for _, result := range results {
+				path := filepath.Join(projectDir, format.Dir, format.Filename(result.CommandName))
+				assert.FileExists(t, path)
+			}
+		})
+	}
+}
+
+func TestTemplateBody(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "body\n", TemplateBody([]byte("---\ndescription: x\n---\nbody\n")))
+	assert.Equal(t, "no frontmatter\n", TemplateBody([]byte("no frontmatter\n")))
+	assert.Equal(t, "---\nunterminated\n", TemplateBody([]byte("---\nunterminated\n")))
+
+	content, err := GetTemplate("autospec.tasks")
+	require.NoError(t, err)
+	assert.False(t, strings.HasPrefix(TemplateBody(content), "---"))
+}
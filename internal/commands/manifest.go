@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFilename is the file in a commands directory that records the
+// version and checksum of every command autospec installed there, so updates
+// can tell local edits from outdated templates.
+const ManifestFilename = ".autospec-commands.yaml"
+
+// Manifest is the content of ManifestFilename.
+type Manifest struct {
+	Commands map[string]ManifestEntry `yaml:"commands"`
+}
+
+// ManifestEntry records one installed command.
+type ManifestEntry struct {
+	Version  string `yaml:"version"`  // template version installed
+	Checksum string `yaml:"checksum"` // Checksum of the file as installed
+}
+
+// Checksum returns the "sha256:<hex>" checksum of data.
+func Checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// LoadManifest reads the manifest in dir. A missing manifest is empty.
+func LoadManifest(dir string) (*Manifest, error) {
+	m := &Manifest{Commands: map[string]ManifestEntry{}}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading command manifest: %w", err)
+	}
+	if err := yaml.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Join(dir, ManifestFilename), err)
+	}
+	if m.Commands == nil {
+		m.Commands = map[string]ManifestEntry{}
+	}
+	return m, nil
+}
+
+// SaveManifest writes m to dir.
+func SaveManifest(dir string, m *Manifest) error {
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("encoding command manifest: %w", err)
+	}
+	header := []byte("# Written by autospec: versions and checksums of installed command templates.\n")
+	if err := fsutil.WriteFile(filepath.Join(dir, ManifestFilename), append(header, data...), 0o644); err != nil {
+		return fmt.Errorf("writing command manifest: %w", err)
+	}
+	return nil
+}
+
+// record notes that tpl was installed with content.
+func (m *Manifest) record(tpl CommandTemplate, content []byte) {
+	m.Commands[tpl.Name] = ManifestEntry{Version: tpl.Version, Checksum: Checksum(content)}
+}
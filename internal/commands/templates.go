@@ -76,20 +76,27 @@ func InstallTemplates(targetDir string) ([]InstallResult, error) {
 	return installTemplatesToDir(targetDir)
 }
 
-// InstallTemplatesForAgent installs all embedded templates for the specified agent.
-// It uses GetCommandsDir to determine the correct directory based on agent name.
+// InstallTemplatesForAgent installs all embedded templates for the specified agent,
+// converted to the agent's command format (see Formats).
 // The projectDir is the base project directory; the agent-specific commands dir is appended.
 func InstallTemplatesForAgent(agentName, projectDir string) ([]InstallResult, error) {
-	cmdDir, err := GetCommandsDir(agentName)
+	format, err := LookupFormat(agentName)
 	if err != nil {
 		return nil, fmt.Errorf("getting commands dir for agent %s: %w", agentName, err)
 	}
-	targetDir := filepath.Join(projectDir, cmdDir)
-	return installTemplatesToDir(targetDir)
+	return InstallTemplatesInFormat(filepath.Join(projectDir, format.Dir), format)
 }
 
 // installTemplatesToDir is the shared implementation for template installation.
 func installTemplatesToDir(targetDir string) ([]InstallResult, error) {
+	format, _ := LookupFormat("claude")
+	return InstallTemplatesInFormat(targetDir, format)
+}
+
+// InstallTemplatesInFormat writes every embedded template to targetDir,
+// converted to format, and records them in the directory's manifest.
+// Existing autospec files are overwritten; UpdateTemplates keeps local edits.
+func InstallTemplatesInFormat(targetDir string, format Format) ([]InstallResult, error) {
 	if err := os.MkdirAll(targetDir, 0o755); err != nil {
 		return nil, fmt.Errorf("creating directory: %w", err)
 	}
@@ -98,10 +105,14 @@ func installTemplatesToDir(targetDir string) ([]InstallResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	manifest, err := LoadManifest(targetDir)
+	if err != nil {
+		return nil, err
+	}
 
 	var results []InstallResult
 	for _, tpl := range templates {
-		filename := tpl.Name + ".md"
+		filename := format.Filename(tpl.Name)
 		targetPath := filepath.Join(targetDir, filename)
 
 		action := "installed"
@@ -109,9 +120,11 @@ func installTemplatesToDir(targetDir string) ([]InstallResult, error) {
 			action = "updated"
 		}
 
-		if err := os.WriteFile(targetPath, tpl.Content, 0o644); err != nil {
+		content := format.Render(tpl)
+		if err := os.WriteFile(targetPath, content, 0o644); err != nil {
 			return nil, fmt.Errorf("writing %s: %w", filename, err)
 		}
+		manifest.record(tpl, content)
 
 		results = append(results, InstallResult{
 			CommandName: tpl.Name,
@@ -120,6 +133,9 @@ func installTemplatesToDir(targetDir string) ([]InstallResult, error) {
 		})
 	}
 
+	if err := SaveManifest(targetDir, manifest); err != nil {
+		return nil, err
+	}
 	return results, nil
 }
 
@@ -238,17 +254,15 @@ func GetDefaultCommandsDir() string {
 }
 
 // GetCommandsDir returns the command directory path for the specified agent.
-// Known agents: "claude" -> ".claude/commands", "opencode" -> ".opencode/command"
+// Known agents: "claude" -> ".claude/commands", "opencode" -> ".opencode/command",
+// "gemini" -> ".gemini/commands", ... (see Formats).
 // Unknown agents return an error.
 func GetCommandsDir(agentName string) (string, error) {
-	switch agentName {
-	case "claude":
-		return filepath.Join(".claude", "commands"), nil
-	case "opencode":
-		return filepath.Join(".opencode", "command"), nil
-	default:
-		return "", fmt.Errorf("unknown agent: %s", agentName)
+	format, err := LookupFormat(agentName)
+	if err != nil {
+		return "", err
 	}
+	return format.Dir, nil
 }
 
 // CommandExists checks if a command file exists in the target directory.
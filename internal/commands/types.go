@@ -22,6 +22,7 @@ type InstallResult struct {
 	CommandName string // Name of the command that was installed
 	Action      string // "installed", "updated", or "skipped"
 	Path        string // Path where the command was installed
+	Backup      string // Copy of a locally modified file that was replaced ("" when none)
 }
 
 // CommandInfo represents information about an installed command.
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+)
+
+// State classifies an installed command against its embedded template.
+type State string
+
+const (
+	// StateCurrent means the installed file matches the embedded template.
+	StateCurrent State = "current"
+	// StateOutdated means the file is an unmodified install of another
+	// template version.
+	StateOutdated State = "outdated"
+	// StateModified means the file was edited after it was installed, or
+	// differs from the embedded template and has no manifest entry.
+	StateModified State = "modified"
+	// StateMissing means the command is not installed.
+	StateMissing State = "missing"
+)
+
+// CommandStatus compares an installed command with its embedded template.
+type CommandStatus struct {
+	Name             string
+	Path             string
+	State            State
+	InstalledVersion string // from the manifest or the file's frontmatter ("" when unknown)
+	EmbeddedVersion  string
+	Installed        []byte // current file content (nil when missing)
+	Embedded         []byte // embedded template rendered in the directory's format
+}
+
+// Status compares every embedded template with the command installed in
+// targetDir in the given format.
+func Status(targetDir string, format Format) ([]CommandStatus, error) {
+	manifest, err := LoadManifest(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := ListTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]CommandStatus, 0, len(templates))
+	for _, tpl := range templates {
+		s := CommandStatus{
+			Name:            tpl.Name,
+			Path:            filepath.Join(targetDir, format.Filename(tpl.Name)),
+			EmbeddedVersion: tpl.Version,
+			Embedded:        format.Render(tpl),
+		}
+		installed, err := os.ReadFile(s.Path)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				return nil, fmt.Errorf("reading %s: %w", s.Path, err)
+			}
+			s.State = StateMissing
+			statuses = append(statuses, s)
+			continue
+		}
+		s.Installed = installed
+
+		entry, tracked := manifest.Commands[tpl.Name]
+		if tracked {
+			s.InstalledVersion = entry.Version
+		} else if _, version, err := ParseTemplateFrontmatter(installed); err == nil {
+			s.InstalledVersion = version
+		}
+
+		sum := Checksum(installed)
+		switch {
+		case sum == Checksum(s.Embedded):
+			s.State = StateCurrent
+		case tracked && sum == entry.Checksum:
+			s.State = StateOutdated
+		default:
+			s.State = StateModified
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+// backupCommand writes content to the first unused backup path of path
+// (<path>.bak, <path>.bak.2, ...) and returns it.
+func backupCommand(path string, content []byte) (string, error) {
+	for n := 1; ; n++ {
+		backup := fsutil.BackupPath(path, n)
+		if _, err := os.Stat(backup); os.IsNotExist(err) {
+			return backup, fsutil.WriteFile(backup, content, 0o644)
+		}
+	}
+}
+
+// UpdateTemplates brings the commands in targetDir up to date with the
+// embedded templates: missing commands are installed and outdated ones
+// replaced. Locally modified commands are skipped unless force is set, in
+// which case the modified file is kept as <file>.bak (or .bak.2, ...) before
+// it is replaced. Every command that matches its template afterwards is
+// recorded in the manifest.
+func UpdateTemplates(targetDir string, format Format, force bool) ([]InstallResult, error) {
+	statuses, err := Status(targetDir, format)
+	if err != nil {
+		return nil, err
+	}
+	manifest, err := LoadManifest(targetDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating directory: %w", err)
+	}
+
+	var results []InstallResult
+	for _, s := range statuses {
+		tpl := CommandTemplate{Name: s.Name, Version: s.EmbeddedVersion}
+		result := InstallResult{CommandName: s.Name, Path: s.Path}
+		switch s.State {
+		case StateCurrent:
+			manifest.record(tpl, s.Embedded)
+			continue
+		case StateMissing:
+			result.Action = "installed"
+			err = fsutil.WriteFile(s.Path, s.Embedded, 0o644)
+		case StateOutdated:
+			result.Action = "updated"
+			err = fsutil.WriteFile(s.Path, s.Embedded, 0o644)
+		case StateModified:
+			if !force {
+				result.Action = "skipped"
+				results = append(results, result)
+				continue
+			}
+			result.Action = "updated"
+			if result.Backup, err = backupCommand(s.Path, s.Installed); err == nil {
+				err = fsutil.WriteFile(s.Path, s.Embedded, 0o644)
+			}
+		}
+		if err != nil {
+			return results, fmt.Errorf("writing %s: %w", s.Path, err)
+		}
+		manifest.record(tpl, s.Embedded)
+		results = append(results, result)
+	}
+
+	if err := SaveManifest(targetDir, manifest); err != nil {
+		return results, err
+	}
+	return results, nil
+}
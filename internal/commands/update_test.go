@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest_RoundTrip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	m, err := LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Empty(t, m.Commands, "missing manifest is empty")
+
+	m.record(CommandTemplate{Name: "autospec.plan", Version: "1.2.0"}, []byte("plan"))
+	require.NoError(t, SaveManifest(dir, m))
+
+	loaded, err := LoadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, ManifestEntry{Version: "1.2.0", Checksum: Checksum([]byte("plan"))}, loaded.Commands["autospec.plan"])
+	assert.Regexp(t, `^sha256:[0-9a-f]{64}$`, loaded.Commands["autospec.plan"].Checksum)
+}
+
+func TestStatusAndUpdateTemplates(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	format, err := LookupFormat("claude")
+	require.NoError(t, err)
+
+	_, err = InstallTemplatesInFormat(dir, format)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]State{}, nonCurrent(t, dir, format), "fresh install is current")
+
+	plan := filepath.Join(dir, "autospec.plan.md")
+	tasks := filepath.Join(dir, "autospec.tasks.md")
+	analyze := filepath.Join(dir, "autospec.analyze.md")
+
+	// An older, unmodified template: the manifest checksum matches the file.
+	m, err := LoadManifest(dir)
+	require.NoError(t, err)
+	m.record(CommandTemplate{Name: "autospec.plan", Version: "0.1.0"}, []byte("old plan\n"))
+	require.NoError(t, SaveManifest(dir, m))
+	require.NoError(t, os.WriteFile(plan, []byte("old plan\n"), 0o644))
+	// A local edit.
+	require.NoError(t, os.WriteFile(tasks, []byte("my tasks\n"), 0o644))
+	require.NoError(t, os.Remove(analyze))
+
+	want := map[string]State{
+		"autospec.plan":    StateOutdated,
+		"autospec.tasks":   StateModified,
+		"autospec.analyze": StateMissing,
+	}
+	assert.Equal(t, want, nonCurrent(t, dir, format))
+
+	results, err := UpdateTemplates(dir, format, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []InstallResult{
+		{CommandName: "autospec.analyze", Action: "installed", Path: analyze},
+		{CommandName: "autospec.plan", Action: "updated", Path: plan},
+		{CommandName: "autospec.tasks", Action: "skipped", Path: tasks},
+	}, results)
+	assert.Equal(t, map[string]State{"autospec.tasks": StateModified}, nonCurrent(t, dir, format))
+
+	results, err = UpdateTemplates(dir, format, true)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, tasks+".bak", results[0].Backup)
+	backup, err := os.ReadFile(tasks + ".bak")
+	require.NoError(t, err)
+	assert.Equal(t, "my tasks\n", string(backup), "local edits are kept")
+	assert.Equal(t, map[string]State{}, nonCurrent(t, dir, format))
+
+	results, err = UpdateTemplates(dir, format, true)
+	require.NoError(t, err)
+	assert.Empty(t, results, "nothing left to update")
+}
+
+func TestStatus_UntrackedInstall(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	format, err := LookupFormat("claude")
+	require.NoError(t, err)
+	tpl, err := GetTemplateInfo("autospec.plan")
+	require.NoError(t, err)
+	// Installed before manifests existed: identical files are current,
+	// anything else cannot be told apart from a local edit.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "autospec.plan.md"), tpl.Content, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "autospec.tasks.md"), []byte("---\nversion: \"0.1.0\"\n---\nold\n"), 0o644))
+
+	statuses, err := Status(dir, format)
+	require.NoError(t, err)
+	for _, s := range statuses {
+		switch s.Name {
+		case "autospec.plan":
+			assert.Equal(t, StateCurrent, s.State)
+		case "autospec.tasks":
+			assert.Equal(t, StateModified, s.State)
+			assert.Equal(t, "0.1.0", s.InstalledVersion, "version comes from the frontmatter")
+		}
+	}
+}
+
+// nonCurrent returns the state of every command in dir that is not current.
+func nonCurrent(t *testing.T, dir string, format Format) map[string]State {
+	t.Helper()
+	statuses, err := Status(dir, format)
+	require.NoError(t, err)
+	states := map[string]State{}
+	for _, s := range statuses {
+		if s.State != StateCurrent {
+			states[s.Name] = s.State
+		}
+	}
+	return states
+}
@@ -15,9 +15,32 @@ import (
 	"path/filepath"
 	"reflect"
 	"strings"
+	"time"
 
+	"github.com/ariel-frischer/autospec/internal/budget"
+	"github.com/ariel-frischer/autospec/internal/changelog"
 	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/codefmt"
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/ghproject"
+	"github.com/ariel-frischer/autospec/internal/learnings"
+	"github.com/ariel-frischer/autospec/internal/logging"
 	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/preflight"
+	"github.com/ariel-frischer/autospec/internal/promptsize"
+	"github.com/ariel-frischer/autospec/internal/ratelimit"
+	"github.com/ariel-frischer/autospec/internal/redact"
+	"github.com/ariel-frischer/autospec/internal/review"
+	"github.com/ariel-frischer/autospec/internal/search"
+	"github.com/ariel-frischer/autospec/internal/secrets"
+	"github.com/ariel-frischer/autospec/internal/shell"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/statusfile"
+	"github.com/ariel-frischer/autospec/internal/storage"
+	"github.com/ariel-frischer/autospec/internal/testselect"
+	"github.com/ariel-frischer/autospec/internal/tracker"
+	"github.com/ariel-frischer/autospec/internal/validation"
 	"github.com/ariel-frischer/autospec/internal/worktree"
 	"github.com/knadh/koanf/parsers/json"
 	"github.com/knadh/koanf/parsers/yaml"
@@ -40,6 +63,10 @@ const (
 
 // Configuration represents the autospec CLI tool configuration
 type Configuration struct {
+	// ConfigVersion is the config file format version (see CurrentConfigVersion).
+	// Older files are migrated on load; 'autospec config migrate' stamps the version.
+	ConfigVersion int `koanf:"config_version"`
+
 	// AgentPreset selects a built-in agent by name (e.g., "claude", "gemini", "cline").
 	// Can be set via AUTOSPEC_AGENT_PRESET env var.
 	AgentPreset string `koanf:"agent_preset"`
@@ -53,6 +80,7 @@ type Configuration struct {
 	//     env:
 	//       ANTHROPIC_API_KEY: ""
 	//     post_processor: "cclean"
+	//     expand_commands: true  # inline /autospec.* templates (e.g., for aider)
 	CustomAgent *cliagent.CustomAgentConfig `koanf:"custom_agent"`
 
 	// UseSubscription forces Claude to use subscription (Pro/Max) instead of API credits.
@@ -62,22 +90,124 @@ type Configuration struct {
 	// Can be set via AUTOSPEC_USE_SUBSCRIPTION env var.
 	UseSubscription bool `koanf:"use_subscription"`
 
-	MaxRetries        int    `koanf:"max_retries"`
-	SpecsDir          string `koanf:"specs_dir"`
+	MaxRetries int `koanf:"max_retries"`
+
+	// RetryStrategies sets the retry prompt ladder per stage, keyed by stage
+	// name or "default". Retry N uses the Nth strategy (errors, schema,
+	// fix_only) and later retries repeat the last one. Stages without an
+	// entry use "default", or retry.DefaultLadder.
+	// Example:
+	//   retry_strategies:
+	//     plan: [errors, fix_only]
+	RetryStrategies map[string][]string `koanf:"retry_strategies"`
+
+	SpecsDir string `koanf:"specs_dir"`
+	// SpecsLayout selects how spec directories are named and nested:
+	// "numbered" (specs/001-name, the default), "dated" (specs/2025-01-15-name),
+	// "categorized" (specs/<category>/001-name) or "flat" (specs/name).
+	SpecsLayout       string `koanf:"specs_layout"`
 	StateDir          string `koanf:"state_dir"`
 	SkipPreflight     bool   `koanf:"skip_preflight"`
 	Timeout           int    `koanf:"timeout"`
 	SkipConfirmations bool   `koanf:"skip_confirmations"` // Skip confirmation prompts (can also be set via AUTOSPEC_YES env var)
+	// CancelGracePeriod is how long a running agent may take to exit after
+	// Ctrl+C or SIGTERM before it is killed. Default: 10s
+	CancelGracePeriod time.Duration `koanf:"cancel_grace_period"`
+	// Shell runs task commands, formatters and custom agent post-processors:
+	// sh, bash, zsh, cmd, powershell or pwsh. Empty selects the platform's
+	// shell (cmd on Windows, sh elsewhere).
+	Shell string `koanf:"shell"`
 	// ImplementMethod sets the default execution mode for the implement command.
 	// Valid values: "single-session" (legacy), "phases" (default), "tasks"
 	// Can be overridden by CLI flags (--phases, --tasks) or env var AUTOSPEC_IMPLEMENT_METHOD
 	ImplementMethod string `koanf:"implement_method"`
+	// ArtifactBackups is how many previous versions autospec keeps when it
+	// rewrites a spec artifact (spec.yaml, tasks.yaml, ...), as <file>.bak,
+	// <file>.bak.2, ... 0 disables backups. Default: 1
+	ArtifactBackups int `koanf:"artifact_backups"`
+	// Transcripts writes transcript-<stage>.md (prompt, key responses, tool
+	// calls, result) to the spec directory after each stage. Default: true
+	Transcripts bool `koanf:"transcripts"`
+	// MarkdownViews renders spec.md, plan.md, tasks.md, ... next to the YAML
+	// artifacts after each stage. The YAML stays the source of truth; the
+	// markdown is regenerated, never read. Default: false
+	MarkdownViews bool `koanf:"markdown_views"`
+	// ArtifactStaging makes the plan and tasks agents write into a copy of
+	// the spec directory under .autospec/staging. The copy is validated and
+	// its changes promoted into the spec directory only when the stage
+	// succeeds, so a failed or interrupted stage leaves no partial artifacts.
+	// Default: true
+	ArtifactStaging bool `koanf:"artifact_staging"`
+	// ArtifactSalvage recovers an artifact that the agent printed in a fenced
+	// YAML block instead of writing it: when validation fails, the block is
+	// written to the artifact's path and the stage validated again before the
+	// attempt counts as failed. Default: true
+	ArtifactSalvage bool `koanf:"artifact_salvage"`
+	// ValidationMode is how artifact validation treats fields the schema does
+	// not define and missing optional sections: "lenient" reports them as
+	// warnings, "strict" as errors. --strict selects strict for one command.
+	// Default: lenient
+	ValidationMode string `koanf:"validation_mode"`
+
+	// StatusLine writes a one-line run status (spec, stage, task x/y) for
+	// terminal status bars such as tmux status-right, next to status.json.
+	StatusLine statusfile.LineConfig `koanf:"status_line"`
 
 	// Notifications configures notification preferences for command and stage completion.
 	// Supports sound, visual, or both notification types across macOS, Linux, and Windows.
 	// Environment variable support via AUTOSPEC_NOTIFICATIONS_* prefix.
 	Notifications notify.NotificationConfig `koanf:"notifications"`
 
+	// Budget limits wall-clock time, estimated tokens and estimated cost per run
+	// and per stage. When a limit is reached the run stops before the next agent
+	// session and fires the on_budget_exceeded notification hook.
+	Budget budget.Config `koanf:"budget"`
+
+	// RateLimit waits out provider rate limits reported by a failed agent
+	// session instead of counting the session against max_retries.
+	RateLimit ratelimit.Config `koanf:"rate_limit"`
+
+	// Redaction masks secrets (AWS keys, tokens, .env values) in agent output,
+	// run event logs and notification payloads. Enabled by default.
+	Redaction redact.Config `koanf:"redaction"`
+
+	// Redactor is built from Redaction when the config is loaded (nil when disabled).
+	Redactor *redact.Redactor `koanf:"-"`
+
+	// Encryption encrypts run event logs, the log file and transcripts at rest
+	// (AES-256-GCM). The key comes from an environment variable or a key
+	// command; readers such as 'autospec replay' decrypt transparently.
+	Encryption encrypt.Config `koanf:"encryption"`
+
+	// Secrets selects the OS keychain that tokens and passwords missing from
+	// the config and environment are read from ('autospec secrets set').
+	Secrets secrets.Config `koanf:"secrets"`
+
+	// Logging configures structured diagnostic logs: the console level and
+	// format, and an optional rotating log file in state_dir/logs.
+	// Environment variable support via AUTOSPEC_LOGGING_* prefix.
+	Logging logging.Config `koanf:"logging"`
+
+	// Changelog appends a generated entry (delivered user stories and notable
+	// tasks) to CHANGELOG.md when a spec is marked completed. Disabled by default.
+	// Environment variable support via AUTOSPEC_CHANGELOG_* prefix.
+	Changelog changelog.Config `koanf:"changelog"`
+
+	// Lint configures 'autospec lint': rule severities and the phase size
+	// limit. Environment variable support via AUTOSPEC_LINT_* prefix.
+	Lint validation.LintConfig `koanf:"lint"`
+
+	// IssueTracker links user stories to Jira or Linear issues on specify,
+	// moves them as phases complete and posts the completion report.
+	// Disabled while provider is empty. API tokens are read from the
+	// environment variable named by token_env, never from the config file.
+	IssueTracker tracker.Config `koanf:"issue_tracker"`
+
+	// GitHubProject mirrors tasks.yaml into a GitHub Projects (v2) board and
+	// moves its items as tasks complete. Disabled while owner is empty. The
+	// token is read from GITHUB_TOKEN, GH_TOKEN or the keychain.
+	GitHubProject ghproject.Config `koanf:"github_project"`
+
 	// MaxHistoryEntries sets the maximum number of command history entries to retain.
 	// Oldest entries are pruned when this limit is exceeded.
 	// Default: 500. Can be set via AUTOSPEC_MAX_HISTORY_ENTRIES env var.
@@ -121,6 +251,153 @@ type Configuration struct {
 	// Default: false. Can be set via AUTOSPEC_ENABLE_RISK_ASSESSMENT env var.
 	EnableRiskAssessment bool `koanf:"enable_risk_assessment"`
 
+	// ChecklistGate blocks implement until every item in the spec's checklists/*.yaml
+	// is marked pass or waived. Open items are listed in the failure message.
+	// Default: false. Can be enabled per run with 'autospec implement --require-checklists'.
+	ChecklistGate bool `koanf:"checklist_gate"`
+
+	// AnalyzeFailOn is the lowest analysis.yaml finding severity that fails analyze
+	// and blocks implement: "critical", "high", "medium" or "low".
+	// Default: "" (findings are advisory). Can be set per run with '--fail-on'.
+	AnalyzeFailOn string `koanf:"analyze_fail_on"`
+
+	// Review blocks plan until the required reviewers have approved spec.yaml
+	// with 'autospec review'. Default: disabled.
+	Review review.Config `koanf:"review"`
+
+	// Learnings configures the project memory in .autospec/memory/learnings.yaml:
+	// retrospection after each completed spec and how many learnings plan and
+	// implement prompts include.
+	Learnings learnings.Config `koanf:"learnings"`
+
+	// PromptSize estimates agent prompt sizes, warns when one nears the
+	// model's context window and trims learnings and context packs to fit.
+	PromptSize promptsize.Config `koanf:"prompt_size"`
+
+	// OverlapCheck compares new feature descriptions with the existing specs
+	// before 'autospec specify' runs and warns about specs that cover them.
+	OverlapCheck search.OverlapConfig `koanf:"overlap_check"`
+
+	// Preflight disables preflight checks by name and adds shell command
+	// checks that run before every workflow and in 'autospec preflight'.
+	Preflight preflight.Config `koanf:"preflight"`
+
+	// Storage limits the size of the state directory and its logs, and
+	// prunes old run logs (<state_dir>/runs/) before each run.
+	Storage storage.Config `koanf:"storage"`
+
+	// TaskCommands maps task types (e.g., "test", "implementation") to shell commands
+	// that run after each task's agent session in task-level implementation mode.
+	// A non-zero exit marks the task incomplete and feeds the failing output into
+	// the retry prompt. Task types without an entry are not checked.
+	// Example:
+	//   task_commands:
+	//     test: "go test ./..."
+	//     implementation: "go build ./..."
+	TaskCommands map[string]string `koanf:"task_commands"`
+
+	// Formatters run over the files each implement session changed (found
+	// with git status and the commits the session made), before the session's
+	// output is validated. Each formatter gets the changed files matching its
+	// patterns appended to its command.
+	// Example:
+	//   formatters:
+	//     - patterns: ["*.go"]
+	//       command: gofmt -w
+	//     - patterns: ["*.ts", "*.tsx"]
+	//       command: npx prettier --write
+	Formatters []codefmt.Formatter `koanf:"formatters"`
+
+	// TargetedTests run after each task in task-level implementation mode,
+	// selected by the files the task changed: a rule runs when one of the
+	// changed files matches its patterns, with {{dirs}} and {{files}} in its
+	// command expanded to the matching files' directories and paths. Use them
+	// in place of a full-suite task_commands entry to speed up per-task gates.
+	// Example:
+	//   targeted_tests:
+	//     - patterns: ["*.go"]
+	//       command: go test {{dirs}}
+	//     - patterns: ["web/*"]
+	//       command: npx jest --findRelatedTests {{files}}
+	TargetedTests []testselect.Rule `koanf:"targeted_tests"`
+
+	// TaskTimeout caps how long a single task may run in task-level
+	// implementation mode, retries included. A task that runs out of time is
+	// marked Blocked with a timeout reason and the run moves on to the next
+	// task. A task's max_duration in tasks.yaml takes precedence.
+	// Default: 0 (no per-task limit)
+	TaskTimeout time.Duration `koanf:"task_timeout"`
+
+	// Env sets environment variables for every agent session, on top of the
+	// environment autospec runs in. Values are templates with {{.SpecName}},
+	// {{.Stage}}, {{.TaskID}} and {{.Phase}} (see agentenv.Data).
+	// Example:
+	//   env:
+	//     DATABASE_URL: "postgres://localhost/app_test_{{.TaskID}}"
+	Env map[string]string `koanf:"env"`
+
+	// StageEnv overrides Env per stage, keyed by stage name (e.g., "implement").
+	StageEnv map[string]map[string]string `koanf:"stage_env"`
+
+	// WorkDir runs a stage's agent in another directory than the project
+	// root, keyed by stage name. Relative paths are relative to the project
+	// root. A spec's feature.workdir in spec.yaml takes precedence for its
+	// implement stage.
+	// Example:
+	//   workdir:
+	//     implement: services/api
+	WorkDir map[string]string `koanf:"workdir"`
+
+	// Remote runs an agent's sessions on another host over ssh, keyed by
+	// agent name. The spec directory is copied there before each session and
+	// back afterwards; code changes stay on the remote checkout.
+	// Example:
+	//   remote:
+	//     claude:
+	//       host: me@devbox
+	//       identity: ~/.ssh/id_ed25519
+	//       dir: ~/src/myapp
+	//       stages: [implement]
+	Remote map[string]cliagent.RemoteConfig `koanf:"remote"`
+
+	// Container runs an agent's sessions inside docker or podman, keyed by
+	// agent name. The project is mounted read-write at the same path and
+	// headless prompts are passed on stdin.
+	// Example:
+	//   container:
+	//     claude:
+	//       image: ghcr.io/me/claude-agent:1.2
+	//       mounts: [~/.claude:/home/agent/.claude]
+	//       pass_env: [ANTHROPIC_API_KEY]
+	Container map[string]cliagent.ContainerConfig `koanf:"container"`
+
+	// Packages defines the packages of a monorepo workspace, keyed by name.
+	// Select one with '--package <name>' or AUTOSPEC_PACKAGE; see PackageConfig.
+	Packages map[string]PackageConfig `koanf:"packages"`
+
+	// Package is the name of the selected package, or "" (not loaded from config).
+	Package string `koanf:"-"`
+
+	// Profiles defines named workflow profiles, keyed by name. The built-in
+	// fast, thorough and budget profiles can be overridden field by field.
+	// Select one with '--profile <name>' or AUTOSPEC_PROFILE; see ProfileConfig.
+	Profiles map[string]ProfileConfig `koanf:"profiles"`
+
+	// Profile is the name of the selected profile, or "" (not loaded from config).
+	Profile string `koanf:"-"`
+
+	// AppliedSpecConfig is the path of the spec config (SpecConfigFile) merged
+	// by ApplySpecConfig, or "" (not loaded from config).
+	AppliedSpecConfig string `koanf:"-"`
+
+	// SpecPrompts are the prompt templates of the applied spec config, by
+	// command (not loaded from config).
+	SpecPrompts map[string]string `koanf:"-"`
+
+	// Pipelines defines custom pipelines, keyed by name. Run one with
+	// 'autospec pipeline run <name>'; see PipelineConfig.
+	Pipelines map[string]PipelineConfig `koanf:"pipelines"`
+
 	// Cclean configures cclean (claude-clean) output formatting options.
 	// Controls verbose mode, line numbers, and output style for stream-json display.
 	// Environment variable support via AUTOSPEC_CCLEAN_* prefix.
@@ -208,7 +485,7 @@ func loadUserConfig(k *koanf.Koanf, customPath string, warningWriter io.Writer,
 	// If custom path provided, use it exclusively (for testing)
 	if customPath != "" {
 		if fileExists(customPath) {
-			if err := loadYAMLConfig(k, customPath, "user"); err != nil {
+			if err := loadYAMLConfig(k, customPath, "user", warningWriter, skipWarnings); err != nil {
 				return fmt.Errorf("loading user YAML config: %w", err)
 			}
 		}
@@ -222,7 +499,7 @@ func loadUserConfig(k *koanf.Koanf, customPath string, warningWriter io.Writer,
 	legacyUserExists := fileExists(legacyUserPath)
 
 	if userYAMLExists {
-		if err := loadYAMLConfig(k, userYAMLPath, "user"); err != nil {
+		if err := loadYAMLConfig(k, userYAMLPath, "user", warningWriter, skipWarnings); err != nil {
 			return fmt.Errorf("loading user YAML config: %w", err)
 		}
 		warnLegacyExists(warningWriter, legacyUserPath, userYAMLPath, legacyUserExists, skipWarnings, "--user")
@@ -248,7 +525,7 @@ func loadProjectConfig(k *koanf.Koanf, customPath string, warningWriter io.Write
 	legacyProjectExists := fileExists(legacyProjectPath)
 
 	if projectYAMLExists {
-		if err := loadYAMLConfig(k, projectYAMLPath, "project"); err != nil {
+		if err := loadYAMLConfig(k, projectYAMLPath, "project", warningWriter, skipWarnings); err != nil {
 			return fmt.Errorf("loading project YAML config: %w", err)
 		}
 		warnLegacyExists(warningWriter, legacyProjectPath, projectYAMLPath, legacyProjectExists, skipWarnings, "--project")
@@ -260,17 +537,34 @@ func loadProjectConfig(k *koanf.Koanf, customPath string, warningWriter io.Write
 	return nil
 }
 
-// loadYAMLConfig validates and loads a YAML config file
-func loadYAMLConfig(k *koanf.Koanf, path, configType string) error {
+// loadYAMLConfig validates, migrates (see loadVersionedConfig) and loads a YAML config file
+func loadYAMLConfig(k *koanf.Koanf, path, configType string, warningWriter io.Writer, skipWarnings bool) error {
 	if err := ValidateYAMLSyntax(path); err != nil {
 		return fmt.Errorf("validating YAML syntax for %s config: %w", configType, err)
 	}
-	if err := k.Load(file.Provider(path), yaml.Parser()); err != nil {
+	content, err := loadVersionedConfig(path, configType, warningWriter, skipWarnings)
+	if err != nil {
+		return fmt.Errorf("validating %s config: %w", configType, err)
+	}
+	if err := k.Load(bytesProvider(content), yaml.Parser()); err != nil {
 		return fmt.Errorf("failed to load %s config %s: %w", configType, path, err)
 	}
 	return nil
 }
 
+// bytesProvider is a koanf provider for config content already read into memory.
+type bytesProvider []byte
+
+// ReadBytes returns the raw config content.
+func (b bytesProvider) ReadBytes() ([]byte, error) {
+	return b, nil
+}
+
+// Read is not supported; koanf uses ReadBytes when a parser is given.
+func (b bytesProvider) Read() (map[string]interface{}, error) {
+	return nil, fmt.Errorf("bytesProvider does not support Read()")
+}
+
 // loadLegacyJSONConfig loads legacy JSON and warns about migration
 func loadLegacyJSONConfig(k *koanf.Koanf, path, configType string, warningWriter io.Writer, skipWarnings bool, migrateFlag string) error {
 	if err := k.Load(file.Provider(path), json.Parser()); err != nil {
@@ -363,12 +657,29 @@ func finalizeConfigWithWarnings(k *koanf.Koanf, warningWriter io.Writer, skipWar
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	secrets.Use(cfg.Secrets)
+	cfg.fillSecrets()
+
 	if err := ValidateConfigValues(&cfg, "config"); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
 	cfg.StateDir = expandHomePath(cfg.StateDir)
 	cfg.SpecsDir = expandHomePath(cfg.SpecsDir)
+	cfg.applyPackage()
+	cfg.applyProfile()
+	fsutil.SetBackups(cfg.ArtifactBackups)
+	_ = spec.SetLayout(cfg.SpecsLayout)        // Checked by ValidateConfigValues
+	_ = shell.Set(cfg.Shell)                   // Checked by ValidateConfigValues
+	_ = validation.SetMode(cfg.ValidationMode) // Checked by ValidateConfigValues
+	encrypt.Set(cfg.Encryption)
+
+	redactor, err := redact.New(cfg.Redaction)
+	if err != nil {
+		return nil, fmt.Errorf("config validation failed: redaction: %w", err)
+	}
+	cfg.Redactor = redactor
+	cfg.Notifications.Redactor = redactor
 
 	if os.Getenv("AUTOSPEC_YES") != "" {
 		cfg.SkipConfirmations = true
@@ -377,6 +688,25 @@ func finalizeConfigWithWarnings(k *koanf.Koanf, warningWriter io.Writer, skipWar
 	return &cfg, nil
 }
 
+// fillSecrets reads the credentials of enabled notification channels that
+// are not set in the config or environment from the OS keychain.
+func (c *Configuration) fillSecrets() {
+	n := &c.Notifications
+	if !n.Enabled {
+		return
+	}
+	if n.Ntfy.Enabled {
+		secrets.Fill(&n.Ntfy.Token, secrets.NtfyToken)
+	}
+	if n.Pushover.Enabled {
+		secrets.Fill(&n.Pushover.Token, secrets.PushoverToken)
+		secrets.Fill(&n.Pushover.User, secrets.PushoverUser)
+	}
+	if n.Email.Enabled && n.Email.Username != "" {
+		secrets.Fill(&n.Email.Password, secrets.EmailPassword)
+	}
+}
+
 // fileExists returns true if the file exists and is readable
 func fileExists(path string) bool {
 	if path == "" {
@@ -401,11 +731,34 @@ func envTransform(s string) string {
 
 	// Known nested config prefixes that need dot notation.
 	// Order matters: longer prefixes must come first to avoid partial matches.
-	nestedPrefixes := []string{"custom_agent_", "notifications_", "worktree_", "cclean_"}
-	for _, prefix := range nestedPrefixes {
-		if strings.HasPrefix(key, prefix) {
-			// Replace the trailing underscore of the prefix with a dot
-			return prefix[:len(prefix)-1] + "." + key[len(prefix):]
+	nestedPrefixes := []struct{ prefix, path string }{
+		{"custom_agent_", "custom_agent."},
+		{"notifications_schedule_", "notifications.schedule."},
+		{"notifications_email_", "notifications.email."},
+		{"notifications_ntfy_", "notifications.ntfy."},
+		{"notifications_pushover_", "notifications.pushover."},
+		{"notifications_", "notifications."},
+		{"worktree_", "worktree."},
+		{"cclean_", "cclean."},
+		{"budget_", "budget."},
+		{"rate_limit_", "rate_limit."},
+		{"redaction_", "redaction."},
+		{"status_line_", "status_line."},
+		{"review_", "review."},
+		{"logging_", "logging."},
+		{"changelog_", "changelog."},
+		{"lint_", "lint."},
+		{"issue_tracker_jira_", "issue_tracker.jira."},
+		{"issue_tracker_linear_", "issue_tracker.linear."},
+		{"issue_tracker_github_", "issue_tracker.github."},
+		{"issue_tracker_gitlab_", "issue_tracker.gitlab."},
+		{"issue_tracker_", "issue_tracker."},
+		{"github_project_columns_", "github_project.columns."},
+		{"github_project_", "github_project."},
+	}
+	for _, nested := range nestedPrefixes {
+		if strings.HasPrefix(key, nested.prefix) {
+			return nested.path + key[len(nested.prefix):]
 		}
 	}
 
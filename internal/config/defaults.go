@@ -8,16 +8,30 @@ func GetDefaultConfigTemplate() string {
 	return `# Autospec Configuration
 # See 'autospec config -h' for commands, 'autospec config keys' for all options
 
+config_version: 2                     # Config file format (upgrade with 'autospec config migrate')
+
 # Agent settings
 agent_preset: ""                      # Built-in agent: claude | opencode
 use_subscription: true                # Force subscription mode (no API charges); set false to use API key
 
 # Workflow settings
 max_retries: 0                        # Max retry attempts per stage (0-10)
+# retry_strategies:                   # Retry prompt ladder per stage: errors, schema, fix_only
+#   default: [errors, schema, fix_only]
 specs_dir: ./specs                    # Directory for feature specs
+specs_layout: numbered                # Spec directory names: numbered, dated, categorized, flat
+artifact_backups: 1                   # Backups kept when autospec rewrites an artifact (<file>.bak; 0 = none)
+transcripts: true                     # Write transcript-<stage>.md to the spec dir after each stage
+markdown_views: false                 # Render spec.md, plan.md, ... from the YAML artifacts after each stage
+artifact_staging: true                # Plan/tasks write to .autospec/staging; promoted to the spec dir only once valid
+artifact_salvage: true                # Recover artifacts the agent printed instead of writing them
+validation_mode: lenient              # Unknown fields / missing optional sections: lenient (warn) or strict (fail)
 state_dir: ~/.autospec/state          # Directory for state files
 skip_preflight: false                 # Skip preflight checks
 timeout: 2400                         # Timeout in seconds (40 min default, 0 = no timeout)
+cancel_grace_period: 10s              # Time an agent gets to exit after Ctrl+C before it is killed
+shell: ""                             # Shell for task commands and formatters: sh, bash, cmd, powershell, pwsh ("" = platform default)
+task_timeout: 0s                     # Per-task limit for implement --tasks; timed-out tasks are blocked (0s = none)
 skip_confirmations: false             # Skip confirmation prompts
 implement_method: phases              # Default: phases | tasks | single-session
 auto_commit: false                    # Auto-create git commit after workflow (disabled by default)
@@ -52,6 +66,147 @@ notifications:
   on_error: true                      # Notify on failures
   on_long_running: false              # Enable duration-based notifications
   long_running_threshold: 2m          # Threshold for long-running notification
+  message_template: ""                # text/template replacing the message text (empty = built-in + context)
+  link: file                          # Artifact link: file | vscode | cursor | none
+  schedule:
+    quiet_hours: ""                   # Daily quiet window, e.g. "22:00-08:00" (local time)
+    quiet_days: []                    # Whole quiet days: mon..sun, weekends, weekdays
+    mode: suppress                    # suppress | log (desktop notifications only)
+    always_notify: []                 # Hooks that ignore quiet hours, e.g. [on_error]
+  email:
+    enabled: false                    # Email via SMTP (also sent in CI/non-interactive runs)
+    host: ""                          # SMTP server host
+    port: 587                         # SMTP server port
+    starttls: true                    # Require STARTTLS
+    username: ""                      # PLAIN auth user (empty = no auth)
+    password: ""                      # Prefer AUTOSPEC_NOTIFICATIONS_EMAIL_PASSWORD
+    from: ""                          # Sender address
+    to: []                            # Recipient addresses
+    subject: "[autospec] {{.Message}}" # Subject template
+  ntfy:
+    enabled: false                    # Mobile push via ntfy (also sent in CI/non-interactive runs)
+    server: https://ntfy.sh           # ntfy server URL
+    topic: ""                         # Topic subscribed to in the ntfy app
+    token: ""                         # Access token; prefer AUTOSPEC_NOTIFICATIONS_NTFY_TOKEN
+    priority: default                 # min | low | default | high | urgent
+    min_interval: 30s                 # Drop pushes closer together (command completion always sent)
+  pushover:
+    enabled: false                    # Mobile push via Pushover
+    token: ""                         # App token; prefer AUTOSPEC_NOTIFICATIONS_PUSHOVER_TOKEN
+    user: ""                          # User key; prefer AUTOSPEC_NOTIFICATIONS_PUSHOVER_USER
+    device: ""                        # Single device name (empty = all devices)
+    priority: 0                       # -2 (silent) to 1 (high)
+    min_interval: 30s                 # Drop pushes closer together (command completion always sent)
+
+# One-line run status for terminal status bars (tmux status-right)
+status_line:
+  enabled: false                      # Write spec, stage and task x/y on every status update
+  path: ""                            # Status line file (empty = .autospec/status-line)
+  tmux_message: false                 # tmux display-message on stage transitions (inside tmux)
+
+# Spec review before plan ('autospec review')
+review:
+  enabled: false                      # Block plan until required reviewers approve spec.yaml
+  required_reviewers: []              # Reviewer names (empty = any one approval)
+
+# Project memory in .autospec/memory/learnings.yaml ('autospec retro')
+learnings:
+  retrospect: false                   # Run a retrospection session after each completed spec
+  max_in_prompt: 10                   # Top learnings added to plan and implement prompts (0 = none)
+
+# Prompt size estimation and truncation
+prompt_size:
+  context_window: 200000              # Model context window in tokens (0 = no checks)
+  warn_percent: 80                    # Warn and truncate above this share of the window
+  truncation:                         # Applied in order until the prompt fits (empty = warn only)
+    - drop_learnings                  # Drop the least reinforced, oldest learnings
+    - summarize_files                 # Outline context pack files ('autospec prompt export')
+
+# Overlap check of new feature descriptions against existing specs
+overlap_check:
+  enabled: false                      # Compare each 'autospec specify' description with existing specs
+  min_score: 40                       # Warn from this share (percent) of the description found in a spec
+  confirm: true                       # Ask before creating the spec anyway (interactive terminals only)
+
+# Preflight checks run before each workflow ('autospec preflight' lists them)
+preflight:
+  disable: []                         # Checks to skip: agent, project, permissions, git-clean, disk-space, state-size, network
+  commands: {}                        # Extra checks, name: shell command (non-zero exit stops the run)
+
+# State directory size limits and run log pruning, checked before each run
+storage:
+  warn_mb: 500                        # Warn when state_dir grows past this (0 = never)
+  max_mb: 0                           # Refuse to start runs past this (0 = no limit)
+  logs_warn_mb: 200                   # Warn when run logs (runs/) and logs/ grow past this (0 = never)
+  logs_max_mb: 0                      # Refuse to start runs past this (0 = no limit)
+  keep_runs: 100                      # Run logs kept, newest first; older ones are deleted (0 = all)
+  max_run_age: 0s                     # Delete run logs older than this, e.g. 720h (0s = keep)
+
+# Secret redaction in agent output, run logs and notifications
+redaction:
+  enabled: true                       # Mask AWS keys, tokens and similar secrets
+  patterns: []                        # Extra regular expressions to mask
+  env_files:                          # Mask the values of these dotenv files
+    - .env
+
+# At-rest encryption of run logs, the log file and transcripts (AES-256-GCM)
+encryption:
+  enabled: false                      # Encrypt them when written; readers always decrypt
+  key_env: AUTOSPEC_ENCRYPTION_KEY    # Variable holding a base64 or hex 32-byte key
+  key_command: ""                     # Prints the key when the variable is unset, e.g. a keychain lookup
+
+# OS keychain for tokens and passwords missing from the config and environment
+secrets:
+  keychain: true                      # Read them with security, secret-tool or Credential Manager
+  service: autospec                   # Keychain service name ('autospec secrets set <name>')
+
+# Diagnostic logging (attach the log file to support requests)
+logging:
+  level: info                         # Console level: debug | info | warn | error (--log-level)
+  format: text                        # text | json
+  file: false                         # Write <state_dir>/logs/autospec.log (always at debug level)
+  max_size_mb: 10                     # Rotate the log file at this size
+  max_files: 3                        # Rotated log files to keep
+
+# Changelog entry on spec completion (wrapped in markers, replaced on rerun)
+changelog:
+  enabled: false                      # Append delivered stories and notable tasks when a spec completes
+  file: CHANGELOG.md                  # Changelog path, relative to the project root
+
+# Artifact linting ('autospec lint'); rule severities: error, warning, info, off
+lint:
+  max_phase_tasks: 10                 # Phases with more tasks are reported by phase_size
+  rules: {}                           # e.g. task_acceptance: error, phase_size: off
+
+# Issue tracker sync (one issue per user story, links kept in <spec>/issues.yaml)
+issue_tracker:
+  provider: ""                        # jira | linear (empty disables the integration)
+  in_progress_state: In Progress      # State once a story has completed tasks
+  done_state: Done                    # State once every task of a story is completed
+  jira:
+    base_url: ""                      # e.g. https://acme.atlassian.net (https only)
+    project_key: ""                   # Project new issues are created in
+    issue_type: Story
+    email: ""                         # Account the API token belongs to
+    token_env: JIRA_API_TOKEN         # Env var holding the API token
+  linear:
+    team_id: ""                       # Team new issues are created in
+    token_env: LINEAR_API_KEY         # Env var holding the API key
+  github:
+    hosts: []                         # GitHub Enterprise hosts trusted with GITHUB_TOKEN for --from-issue
+  gitlab:
+    hosts: []                         # Self-hosted GitLab hosts trusted with GITLAB_TOKEN for --from-issue
+
+# GitHub Projects board (one draft item per task, item IDs kept in <spec>/github-project.yaml)
+github_project:
+  owner: ""                           # User or organization login (empty disables the sync)
+  number: 0                           # Project number, as in github.com/orgs/<owner>/projects/<number>
+  status_field: Status                # Single-select field whose options are the columns
+  columns:                            # Status field option per task status
+    pending: Todo
+    in_progress: In Progress
+    blocked: Blocked
+    completed: Done
 
 # Cclean (claude-clean) output formatting
 cclean:
@@ -64,11 +219,19 @@ cclean:
 // GetDefaults returns the default configuration values
 func GetDefaults() map[string]interface{} {
 	return map[string]interface{}{
+		"config_version": CurrentConfigVersion,
 		// Agent configuration
 		"agent_preset":       "",
 		"use_subscription":   true, // Protect users from accidental API charges
 		"max_retries":        0,
 		"specs_dir":          "./specs",
+		"specs_layout":       "numbered",
+		"artifact_backups":   1,
+		"transcripts":        true,
+		"markdown_views":     false,
+		"artifact_staging":   true,
+		"artifact_salvage":   true,
+		"validation_mode":    "lenient",
 		"state_dir":          "~/.autospec/state",
 		"skip_preflight":     false,
 		"timeout":            2400,  // 40 minutes default
@@ -77,6 +240,18 @@ func GetDefaults() map[string]interface{} {
 		// This changes the legacy behavior (single-session) to run each phase in a separate Claude session.
 		// Valid values: "single-session", "phases", "tasks"
 		"implement_method": "phases",
+		// cancel_grace_period: How long a running agent may take to exit after Ctrl+C or SIGTERM.
+		"cancel_grace_period": "10s",
+		// shell: Interpreter for configured command lines ("" = cmd on Windows, sh elsewhere).
+		"shell": "",
+		// task_timeout: Per-task time limit in task mode (0s = no limit).
+		"task_timeout": "0s",
+		// status_line: One-line run status for tmux status-right. Disabled by default.
+		"status_line": map[string]interface{}{
+			"enabled":      false,
+			"path":         "",
+			"tmux_message": false,
+		},
 		// notifications: Notification settings for command and stage completion.
 		// Disabled by default (opt-in). When enabled, defaults to both sound and visual notifications.
 		"notifications": map[string]interface{}{
@@ -86,8 +261,144 @@ func GetDefaults() map[string]interface{} {
 			"on_command_complete":    true,                       // Notify when command finishes (default when enabled)
 			"on_stage_complete":      false,                      // Don't notify on each stage by default
 			"on_error":               true,                       // Notify on failures (default when enabled)
+			"on_budget_exceeded":     true,                       // Notify when a budget stops the run
+			"on_rate_limited":        true,                       // Notify when a run waits out a rate limit
+			"on_cancelled":           true,                       // Notify when Ctrl+C or SIGTERM stops a command
 			"on_long_running":        false,                      // Don't use duration threshold by default
 			"long_running_threshold": (2 * time.Minute).String(), // 2 minutes threshold
+			"message_template":       "",                         // Built-in text followed by spec, stage, task, failure and link
+			"link":                   "file",                     // Link artifacts as file:// URLs
+			// schedule: Quiet hours for desktop (sound/visual) notifications. Disabled by default.
+			"schedule": map[string]interface{}{
+				"quiet_hours":   "",
+				"quiet_days":    []string{},
+				"mode":          "suppress",
+				"always_notify": []string{},
+			},
+			// email: SMTP channel for unattended runs. Per-event body templates go in
+			// notifications.email.templates (user-defined map, no defaults).
+			"email": map[string]interface{}{
+				"enabled":  false,
+				"host":     "",
+				"port":     587,
+				"starttls": true,
+				"username": "",
+				"password": "",
+				"from":     "",
+				"to":       []string{},
+				"subject":  "[autospec] {{.Message}}",
+			},
+			// ntfy, pushover: Mobile push channels. min_interval rate-limits each provider.
+			"ntfy": map[string]interface{}{
+				"enabled":      false,
+				"server":       "https://ntfy.sh",
+				"topic":        "",
+				"token":        "",
+				"priority":     "default",
+				"min_interval": (30 * time.Second).String(),
+			},
+			"pushover": map[string]interface{}{
+				"enabled":      false,
+				"token":        "",
+				"user":         "",
+				"device":       "",
+				"priority":     0,
+				"min_interval": (30 * time.Second).String(),
+			},
+		},
+		// budget: Per-run and per-stage usage limits. All zero (unlimited) by default.
+		// When a limit is reached, the run stops before the next agent session and can be resumed.
+		"budget": map[string]interface{}{
+			"max_run_duration":        "0s", // Wall-clock limit for the whole run
+			"max_stage_duration":      "0s", // Agent time limit per stage
+			"max_run_tokens":          0,    // Estimated tokens for the whole run
+			"max_stage_tokens":        0,    // Estimated tokens per stage
+			"max_run_cost_usd":        0.0,  // Estimated USD for the whole run
+			"max_stage_cost_usd":      0.0,  // Estimated USD per stage
+			"cost_per_million_tokens": 0.0,  // Price for estimated tokens when the agent reports no cost
+			"max_run_retries":         0,    // Retries across all stages and tasks of the run
+		},
+		// rate_limit: Wait out provider rate limits (HTTP 429, overloaded, usage limits)
+		// instead of burning retries. max_wait caps the total wait per run.
+		"rate_limit": map[string]interface{}{
+			"enabled":         true,
+			"max_wait":        "1h",
+			"initial_backoff": "30s",
+		},
+		// redaction: Masks secrets in agent output, run event logs and notification payloads.
+		// Built-in patterns cover common credentials; patterns adds user regexes.
+		"redaction": map[string]interface{}{
+			"enabled":   true,
+			"patterns":  []string{},
+			"env_files": []string{".env"},
+		},
+		// encryption: Encrypts run event logs, the log file and transcripts at rest.
+		// The key comes from key_env or the output of key_command.
+		"encryption": map[string]interface{}{
+			"enabled":     false,
+			"key_env":     "AUTOSPEC_ENCRYPTION_KEY",
+			"key_command": "",
+		},
+		// secrets: Reads tokens and passwords missing from the config and
+		// environment from the OS keychain.
+		"secrets": map[string]interface{}{
+			"keychain": true,
+			"service":  "autospec",
+		},
+		// logging: Structured diagnostic logs. The console shows info and above;
+		// the optional log file in state_dir/logs records debug detail.
+		"logging": map[string]interface{}{
+			"level":       "info",
+			"format":      "text",
+			"file":        false,
+			"max_size_mb": 10,
+			"max_files":   3,
+		},
+		// changelog: Generated CHANGELOG.md entry when a spec is completed (opt-in).
+		"changelog": map[string]interface{}{
+			"enabled": false,
+			"file":    "CHANGELOG.md",
+		},
+		// lint: Quality checks beyond the schema, run by 'autospec lint'.
+		// rules overrides the default severity of individual rules.
+		"lint": map[string]interface{}{
+			"max_phase_tasks": 10,
+			"rules":           map[string]interface{}{},
+		},
+		// issue_tracker: Jira/Linear issue per user story (disabled without a provider).
+		"issue_tracker": map[string]interface{}{
+			"provider":          "",
+			"in_progress_state": "In Progress",
+			"done_state":        "Done",
+			"jira": map[string]interface{}{
+				"base_url":    "",
+				"project_key": "",
+				"issue_type":  "Story",
+				"email":       "",
+				"token_env":   "JIRA_API_TOKEN",
+			},
+			"linear": map[string]interface{}{
+				"team_id":   "",
+				"token_env": "LINEAR_API_KEY",
+			},
+			"github": map[string]interface{}{
+				"hosts": []string{},
+			},
+			"gitlab": map[string]interface{}{
+				"hosts": []string{},
+			},
+		},
+		// github_project: GitHub Projects board mirroring tasks.yaml (disabled without an owner).
+		"github_project": map[string]interface{}{
+			"owner":        "",
+			"number":       0,
+			"status_field": "Status",
+			"columns": map[string]interface{}{
+				"pending":     "Todo",
+				"in_progress": "In Progress",
+				"blocked":     "Blocked",
+				"completed":   "Done",
+			},
 		},
 		// max_history_entries: Maximum number of command history entries to retain.
 		// Oldest entries are pruned when this limit is exceeded.
@@ -116,6 +427,55 @@ func GetDefaults() map[string]interface{} {
 		// into the plan stage prompt. When enabled, generated plan.yaml includes a risks section.
 		// Default: false (opt-in feature to reduce cognitive overhead for simple features).
 		"enable_risk_assessment": false,
+		// checklist_gate: Block implement until all checklist items are pass or waived.
+		// Default: false (checklists are advisory unless a team opts in).
+		"checklist_gate": false,
+		// analyze_fail_on: Lowest analysis finding severity that blocks implement.
+		// Default: "" (analysis findings are advisory).
+		"analyze_fail_on": "",
+		// review: Block plan until the required reviewers approve spec.yaml.
+		// Disabled by default; empty required_reviewers means any one approval.
+		"review": map[string]interface{}{
+			"enabled":            false,
+			"required_reviewers": []string{},
+		},
+		// learnings: Project memory of conventions discovered while implementing.
+		// Retrospection is opt-in; the top learnings are added to plan and
+		// implement prompts once learnings.yaml has entries.
+		"learnings": map[string]interface{}{
+			"retrospect":    false,
+			"max_in_prompt": 10,
+		},
+		// prompt_size: Heuristic token estimates for agent prompts. Prompts above
+		// warn_percent of context_window warn and are trimmed by the truncation
+		// strategies, in order.
+		"prompt_size": map[string]interface{}{
+			"context_window": 200000,
+			"warn_percent":   80,
+			"truncation":     []string{"drop_learnings", "summarize_files"},
+		},
+		// overlap_check: Compare new feature descriptions with the existing
+		// specs before specify runs. Opt-in; asks to confirm on a terminal.
+		"overlap_check": map[string]interface{}{
+			"enabled":   false,
+			"min_score": 40,
+			"confirm":   true,
+		},
+		// preflight: Checks skipped by name and extra shell command checks.
+		"preflight": map[string]interface{}{
+			"disable":  []string{},
+			"commands": map[string]string{},
+		},
+		// storage: Warn about a large state directory and prune old run logs,
+		// so long-lived installs such as CI runners do not fill their disks.
+		"storage": map[string]interface{}{
+			"warn_mb":      500,
+			"max_mb":       0,
+			"logs_warn_mb": 200,
+			"logs_max_mb":  0,
+			"keep_runs":    100,
+			"max_run_age":  "0s",
+		},
 		// skip_permissions_notice_shown: Tracks whether the security notice about
 		// --dangerously-skip-permissions has been shown. Set to true after first display.
 		// User-level config only (not shown in project config).
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"text/template"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// Pipeline validator names besides the artifact types.
+const (
+	// PipelineValidatorNone skips validation of the stage's artifact.
+	PipelineValidatorNone = "none"
+	// PipelineValidatorYAML checks that the artifact is well-formed YAML.
+	PipelineValidatorYAML = "yaml"
+)
+
+// PipelineConfig defines a custom pipeline: stages run in order against one
+// spec, each by sending a command to the agent and validating the artifact it
+// writes. Run with 'autospec pipeline run <name>'.
+// Example:
+//
+//	pipelines:
+//	  docs:
+//	    description: "Write user docs for the current spec"
+//	    stages:
+//	      - name: outline
+//	        command: "/docs.outline {{.SpecDir}}/spec.yaml"
+//	        artifact: docs-outline.yaml
+//	        validator: yaml
+//	      - name: write
+//	        command: "/docs.write {{.SpecDir}}/docs-outline.yaml {{.Prompt}}"
+//	        artifact: docs.md
+//	        retries: 1
+type PipelineConfig struct {
+	Description string                `koanf:"description"`
+	Stages      []PipelineStageConfig `koanf:"stages"`
+}
+
+// PipelineStageConfig is one stage of a custom pipeline.
+type PipelineStageConfig struct {
+	// Name identifies the stage in output, state and transcripts.
+	Name string `koanf:"name"`
+	// Command is a Go template for the agent command. Fields: .Pipeline,
+	// .Stage, .Spec, .SpecDir, .Artifact and .Prompt.
+	Command string `koanf:"command"`
+	// Artifact is the file the stage writes, relative to the spec directory.
+	Artifact string `koanf:"artifact"`
+	// Validator checks Artifact after each attempt: an artifact type
+	// (spec, plan, tasks, research, ...) validates against its schema,
+	// "yaml" checks the syntax, and "none" skips validation. Empty only
+	// checks that Artifact exists.
+	Validator string `koanf:"validator"`
+	// Retries overrides max_retries for this stage.
+	Retries *int `koanf:"retries"`
+}
+
+// pipelineNamePattern matches pipeline and stage names: they appear in state
+// and transcript file names.
+var pipelineNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// PipelineNames returns the configured pipeline names in sorted order.
+func (c *Configuration) PipelineNames() []string {
+	names := make([]string, 0, len(c.Pipelines))
+	for name := range c.Pipelines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validatePipelines checks each configured pipeline. Errors name the
+// pipeline's field, e.g. pipelines.docs.stages[1].command.
+func validatePipelines(cfg *Configuration, filePath string) error {
+	for _, name := range cfg.PipelineNames() {
+		if err := validatePipeline(name, cfg.Pipelines[name], filePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePipeline(name string, p PipelineConfig, filePath string) error {
+	field := "pipelines." + name
+	if !pipelineNamePattern.MatchString(name) {
+		return &ValidationError{FilePath: filePath, Field: field,
+			Message: "pipeline name must be lowercase letters, digits, '-' or '_'"}
+	}
+	if len(p.Stages) == 0 {
+		return &ValidationError{FilePath: filePath, Field: field + ".stages",
+			Message: "a pipeline needs at least one stage"}
+	}
+
+	seen := make(map[string]bool, len(p.Stages))
+	for i, s := range p.Stages {
+		stageField := fmt.Sprintf("%s.stages[%d]", field, i)
+		if !pipelineNamePattern.MatchString(s.Name) {
+			return &ValidationError{FilePath: filePath, Field: stageField + ".name",
+				Message: "stage name is required and must be lowercase letters, digits, '-' or '_'"}
+		}
+		if seen[s.Name] {
+			return &ValidationError{FilePath: filePath, Field: stageField + ".name",
+				Message: fmt.Sprintf("duplicate stage name %q", s.Name)}
+		}
+		seen[s.Name] = true
+
+		if s.Command == "" {
+			return &ValidationError{FilePath: filePath, Field: stageField + ".command",
+				Message: "command is required"}
+		}
+		if _, err := template.New(s.Name).Parse(s.Command); err != nil {
+			return &ValidationError{FilePath: filePath, Field: stageField + ".command",
+				Message: fmt.Sprintf("invalid template: %v", err)}
+		}
+		if err := validatePipelineValidator(s); err != nil {
+			return &ValidationError{FilePath: filePath, Field: stageField + ".validator", Message: err.Error()}
+		}
+		if s.Retries != nil && (*s.Retries < 0 || *s.Retries > 10) {
+			return &ValidationError{FilePath: filePath, Field: stageField + ".retries",
+				Message: "must be between 0 and 10"}
+		}
+	}
+	return nil
+}
+
+// validatePipelineValidator checks that a stage's validator is known and has
+// an artifact to validate.
+func validatePipelineValidator(s PipelineStageConfig) error {
+	switch s.Validator {
+	case "", PipelineValidatorNone:
+		return nil
+	case PipelineValidatorYAML:
+	default:
+		if _, err := validation.ParseArtifactType(s.Validator); err != nil {
+			return fmt.Errorf("unknown validator %q (valid: none, yaml or an artifact type: %v)", s.Validator, validation.ValidArtifactTypes())
+		}
+	}
+	if s.Artifact == "" {
+		return fmt.Errorf("validator %q needs an artifact", s.Validator)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Pipelines(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	cfg, err := loadProfileConfig(t, `pipelines:
+  docs:
+    description: "User docs"
+    stages:
+      - name: outline
+        command: "/docs.outline {{.SpecDir}}/spec.yaml"
+        artifact: docs-outline.yaml
+        validator: yaml
+      - name: write
+        command: "/docs.write {{.Prompt}}"
+        artifact: docs.md
+        retries: 1
+`)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"docs"}, cfg.PipelineNames())
+	p := cfg.Pipelines["docs"]
+	assert.Equal(t, "User docs", p.Description)
+	require.Len(t, p.Stages, 2)
+	assert.Equal(t, "outline", p.Stages[0].Name)
+	assert.Equal(t, "yaml", p.Stages[0].Validator)
+	assert.Nil(t, p.Stages[0].Retries)
+	require.NotNil(t, p.Stages[1].Retries)
+	assert.Equal(t, 1, *p.Stages[1].Retries)
+}
+
+func TestValidatePipelines(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	tests := map[string]struct {
+		content   string
+		wantField string
+	}{
+		"no stages": {
+			content:   "pipelines:\n  docs:\n    description: empty\n",
+			wantField: "pipelines.docs.stages",
+		},
+		"invalid pipeline name": {
+			content:   "pipelines:\n  Docs:\n    stages:\n      - {name: a, command: x}\n",
+			wantField: "pipelines.Docs",
+		},
+		"missing stage name": {
+			content:   "pipelines:\n  docs:\n    stages:\n      - {command: x}\n",
+			wantField: "pipelines.docs.stages[0].name",
+		},
+		"duplicate stage name": {
+			content:   "pipelines:\n  docs:\n    stages:\n      - {name: a, command: x}\n      - {name: a, command: y}\n",
+			wantField: "pipelines.docs.stages[1].name",
+		},
+		"missing command": {
+			content:   "pipelines:\n  docs:\n    stages:\n      - {name: a}\n",
+			wantField: "pipelines.docs.stages[0].command",
+		},
+		"invalid command template": {
+			content:   "pipelines:\n  docs:\n    stages:\n      - {name: a, command: \"/x {{.Spec\"}\n",
+			wantField: "pipelines.docs.stages[0].command",
+		},
+		"unknown validator": {
+			content:   "pipelines:\n  docs:\n    stages:\n      - {name: a, command: x, artifact: a.yaml, validator: json}\n",
+			wantField: "pipelines.docs.stages[0].validator",
+		},
+		"validator without artifact": {
+			content:   "pipelines:\n  docs:\n    stages:\n      - {name: a, command: x, validator: plan}\n",
+			wantField: "pipelines.docs.stages[0].validator",
+		},
+		"retries out of range": {
+			content:   "pipelines:\n  docs:\n    stages:\n      - {name: a, command: x, retries: 11}\n",
+			wantField: "pipelines.docs.stages[0].retries",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadProfileConfig(t, tt.content)
+			require.Error(t, err)
+			var verr *ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.wantField, verr.Field)
+		})
+	}
+}
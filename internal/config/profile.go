@@ -0,0 +1,249 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+)
+
+// ProfileEnvVar selects a workflow profile. The --profile flag sets it so
+// agent subprocesses (e.g., 'autospec update-task') inherit the selection.
+const ProfileEnvVar = "AUTOSPEC_PROFILE"
+
+// ProfileConfig bundles workflow settings that a profile applies on top of
+// the project configuration. Unset fields keep the configured value.
+// Example:
+//
+//	profiles:
+//	  thorough:
+//	    max_retries: 5
+//	    custom_agent:
+//	      command: "claude"
+//	      args: ["-p", "--model", "opus", "{{PROMPT}}"]
+type ProfileConfig struct {
+	MaxRetries    *int           `koanf:"max_retries"`
+	Timeout       *int           `koanf:"timeout"`
+	TaskTimeout   *time.Duration `koanf:"task_timeout"`
+	SkipPreflight *bool          `koanf:"skip_preflight"`
+
+	// ImplementMethod overrides implement_method when not empty.
+	ImplementMethod string `koanf:"implement_method"`
+
+	// AgentPreset and CustomAgent choose the agent, and with custom_agent
+	// args, the model. CustomAgent takes precedence, as in the project config.
+	AgentPreset string                      `koanf:"agent_preset"`
+	CustomAgent *cliagent.CustomAgentConfig `koanf:"custom_agent"`
+
+	// Validation strictness
+	ChecklistGate        *bool   `koanf:"checklist_gate"`
+	AnalyzeFailOn        *string `koanf:"analyze_fail_on"`
+	EnableRiskAssessment *bool   `koanf:"enable_risk_assessment"`
+
+	// Stages selects the optional stages 'autospec run' adds around the
+	// core stages it runs.
+	Stages ProfileStages `koanf:"stages"`
+}
+
+// ProfileStages sets whether a profile includes each optional stage.
+// Clarify runs before plan; checklist and analyze run before implement.
+// A stage flag on the command line takes precedence.
+type ProfileStages struct {
+	Clarify   *bool `koanf:"clarify"`
+	Checklist *bool `koanf:"checklist"`
+	Analyze   *bool `koanf:"analyze"`
+}
+
+// BuiltinProfiles returns the profiles available without configuration.
+// A 'profiles' entry with the same name overrides them field by field.
+func BuiltinProfiles() map[string]ProfileConfig {
+	on, off := true, false
+	return map[string]ProfileConfig{
+		"fast": {
+			MaxRetries:    intPtr(0),
+			SkipPreflight: &on,
+			Stages:        ProfileStages{Clarify: &off, Checklist: &off, Analyze: &off},
+		},
+		"thorough": {
+			MaxRetries:           intPtr(3),
+			ChecklistGate:        &on,
+			AnalyzeFailOn:        strPtr("high"),
+			EnableRiskAssessment: &on,
+			Stages:               ProfileStages{Clarify: &on, Checklist: &on, Analyze: &on},
+		},
+		"budget": {
+			MaxRetries:           intPtr(1),
+			ImplementMethod:      "single-session",
+			EnableRiskAssessment: &off,
+			Stages:               ProfileStages{Clarify: &off, Checklist: &off, Analyze: &off},
+		},
+	}
+}
+
+// AllProfiles returns the built-in profiles merged with the configured ones.
+func (c *Configuration) AllProfiles() map[string]ProfileConfig {
+	profiles := BuiltinProfiles()
+	for name, p := range c.Profiles {
+		if base, ok := profiles[name]; ok {
+			p = mergeProfile(base, p)
+		}
+		profiles[name] = p
+	}
+	return profiles
+}
+
+// ProfileNames returns the available profile names in sorted order.
+func (c *Configuration) ProfileNames() []string {
+	profiles := c.AllProfiles()
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProfile returns the named profile, or an error listing the
+// available profiles if there is no such profile.
+func (c *Configuration) LookupProfile(name string) (ProfileConfig, error) {
+	p, ok := c.AllProfiles()[name]
+	if !ok {
+		return ProfileConfig{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	return p, nil
+}
+
+// ActiveProfile returns the profile selected for this run, if any.
+func (c *Configuration) ActiveProfile() (ProfileConfig, bool) {
+	if c.Profile == "" {
+		return ProfileConfig{}, false
+	}
+	p, ok := c.AllProfiles()[c.Profile]
+	return p, ok
+}
+
+// applyProfile applies the profile selected via ProfileEnvVar. Unknown names
+// are ignored here; the CLI rejects them before the command runs.
+func (c *Configuration) applyProfile() {
+	name := os.Getenv(ProfileEnvVar)
+	if name == "" {
+		return
+	}
+	p, ok := c.AllProfiles()[name]
+	if !ok {
+		return
+	}
+	c.Profile = name
+	c.overlayProfile(p)
+}
+
+// overlayProfile copies the fields set in p onto c.
+func (c *Configuration) overlayProfile(p ProfileConfig) {
+	if p.MaxRetries != nil {
+		c.MaxRetries = *p.MaxRetries
+	}
+	if p.Timeout != nil {
+		c.Timeout = *p.Timeout
+	}
+	if p.TaskTimeout != nil {
+		c.TaskTimeout = *p.TaskTimeout
+	}
+	if p.SkipPreflight != nil {
+		c.SkipPreflight = *p.SkipPreflight
+	}
+	if p.ImplementMethod != "" {
+		c.ImplementMethod = p.ImplementMethod
+	}
+	if p.CustomAgent != nil {
+		c.CustomAgent = p.CustomAgent
+	} else if p.AgentPreset != "" {
+		c.AgentPreset = p.AgentPreset
+		c.CustomAgent = nil
+	}
+	if p.ChecklistGate != nil {
+		c.ChecklistGate = *p.ChecklistGate
+	}
+	if p.AnalyzeFailOn != nil {
+		c.AnalyzeFailOn = *p.AnalyzeFailOn
+	}
+	if p.EnableRiskAssessment != nil {
+		c.EnableRiskAssessment = *p.EnableRiskAssessment
+	}
+}
+
+// mergeProfile returns base with the fields set in override replaced.
+func mergeProfile(base, override ProfileConfig) ProfileConfig {
+	if override.MaxRetries != nil {
+		base.MaxRetries = override.MaxRetries
+	}
+	if override.Timeout != nil {
+		base.Timeout = override.Timeout
+	}
+	if override.TaskTimeout != nil {
+		base.TaskTimeout = override.TaskTimeout
+	}
+	if override.SkipPreflight != nil {
+		base.SkipPreflight = override.SkipPreflight
+	}
+	if override.ImplementMethod != "" {
+		base.ImplementMethod = override.ImplementMethod
+	}
+	if override.AgentPreset != "" {
+		base.AgentPreset = override.AgentPreset
+	}
+	if override.CustomAgent != nil {
+		base.CustomAgent = override.CustomAgent
+	}
+	if override.ChecklistGate != nil {
+		base.ChecklistGate = override.ChecklistGate
+	}
+	if override.AnalyzeFailOn != nil {
+		base.AnalyzeFailOn = override.AnalyzeFailOn
+	}
+	if override.EnableRiskAssessment != nil {
+		base.EnableRiskAssessment = override.EnableRiskAssessment
+	}
+	if override.Stages.Clarify != nil {
+		base.Stages.Clarify = override.Stages.Clarify
+	}
+	if override.Stages.Checklist != nil {
+		base.Stages.Checklist = override.Stages.Checklist
+	}
+	if override.Stages.Analyze != nil {
+		base.Stages.Analyze = override.Stages.Analyze
+	}
+	return base
+}
+
+// validateProfiles checks each configured profile by validating the
+// configuration it would produce. Errors name the profile's field.
+func validateProfiles(cfg *Configuration, filePath string) error {
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		applied := *cfg
+		applied.Profiles = nil
+		applied.overlayProfile(cfg.Profiles[name])
+		if err := ValidateConfigValues(&applied, filePath); err != nil {
+			var verr *ValidationError
+			if errors.As(err, &verr) {
+				verr.Field = "profiles." + name + "." + verr.Field
+				return verr
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func intPtr(v int) *int { return &v }
+
+func strPtr(v string) *string { return &v }
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileConfig = `max_retries: 2
+analyze_fail_on: critical
+profiles:
+  thorough:
+    max_retries: 5
+  nightly:
+    task_timeout: 45m
+    agent_preset: gemini
+    stages:
+      analyze: true
+`
+
+// loadProfileConfig loads content as the project config with no user config.
+func loadProfileConfig(t *testing.T, content string) (*Configuration, error) {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(content), 0o644))
+
+	return LoadWithOptions(LoadOptions{
+		ProjectConfigPath: configPath,
+		UserConfigPath:    filepath.Join(dir, "missing.yml"),
+		SkipWarnings:      true,
+	})
+}
+
+func TestLoad_ProfileSelection(t *testing.T) {
+	tests := map[string]struct {
+		profile         string
+		wantProfile     string
+		wantRetries     int
+		wantFailOn      string
+		wantGate        bool
+		wantTaskTimeout time.Duration
+		wantAgent       string
+	}{
+		"no profile selected": {
+			wantRetries: 2,
+			wantFailOn:  "critical",
+		},
+		"built-in profile": {
+			profile:     "fast",
+			wantProfile: "fast",
+			wantRetries: 0,
+			wantFailOn:  "critical",
+		},
+		"configured fields override a built-in profile": {
+			profile:     "thorough",
+			wantProfile: "thorough",
+			wantRetries: 5,
+			wantFailOn:  "high",
+			wantGate:    true,
+		},
+		"custom profile": {
+			profile:         "nightly",
+			wantProfile:     "nightly",
+			wantRetries:     2,
+			wantFailOn:      "critical",
+			wantTaskTimeout: 45 * time.Minute,
+			wantAgent:       "gemini",
+		},
+		"unknown profile is ignored by the loader": {
+			profile:     "missing",
+			wantRetries: 2,
+			wantFailOn:  "critical",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(ProfileEnvVar, tt.profile)
+
+			cfg, err := loadProfileConfig(t, profileConfig)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProfile, cfg.Profile)
+			assert.Equal(t, tt.wantRetries, cfg.MaxRetries)
+			assert.Equal(t, tt.wantFailOn, cfg.AnalyzeFailOn)
+			assert.Equal(t, tt.wantGate, cfg.ChecklistGate)
+			assert.Equal(t, tt.wantTaskTimeout, cfg.TaskTimeout)
+			if tt.wantAgent != "" {
+				assert.Equal(t, tt.wantAgent, cfg.AgentPreset)
+			}
+		})
+	}
+}
+
+func TestLookupProfile(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	cfg, err := loadProfileConfig(t, profileConfig)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"budget", "fast", "nightly", "thorough"}, cfg.ProfileNames())
+
+	p, err := cfg.LookupProfile("thorough")
+	require.NoError(t, err)
+	require.NotNil(t, p.Stages.Clarify)
+	assert.True(t, *p.Stages.Clarify, "built-in stages are kept when other fields are overridden")
+
+	_, err = cfg.LookupProfile("quick")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: budget, fast, nightly, thorough")
+
+	_, active := cfg.ActiveProfile()
+	assert.False(t, active)
+}
+
+func TestValidateProfiles(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	tests := map[string]struct {
+		content   string
+		wantField string
+	}{
+		"max_retries out of range": {
+			content:   "profiles:\n  strict:\n    max_retries: 11\n",
+			wantField: "profiles.strict.max_retries",
+		},
+		"unknown severity": {
+			content:   "profiles:\n  thorough:\n    analyze_fail_on: severe\n",
+			wantField: "profiles.thorough.analyze_fail_on",
+		},
+		"unknown implement method": {
+			content:   "profiles:\n  fast:\n    implement_method: parallel\n",
+			wantField: "profiles.fast.implement_method",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := loadProfileConfig(t, tt.content)
+			require.Error(t, err)
+			var verr *ValidationError
+			require.ErrorAs(t, err, &verr)
+			assert.Equal(t, tt.wantField, verr.Field)
+		})
+	}
+}
+
+func TestBuiltinProfiles_Valid(t *testing.T) {
+	t.Parallel()
+	for name, p := range BuiltinProfiles() {
+		cfg := &Configuration{SpecsDir: "specs", StateDir: "state", Profiles: map[string]ProfileConfig{name: p}}
+		assert.NoError(t, ValidateConfigValues(cfg, "config"), name)
+	}
+}
@@ -16,6 +16,7 @@ const (
 	TypeDuration
 	TypeString
 	TypeEnum
+	TypeFloat
 )
 
 // String returns the string representation of ConfigValueType.
@@ -31,6 +32,8 @@ func (t ConfigValueType) String() string {
 		return "string"
 	case TypeEnum:
 		return "enum"
+	case TypeFloat:
+		return "float"
 	default:
 		return "unknown"
 	}
@@ -47,6 +50,12 @@ type ConfigKeySchema struct {
 
 // KnownKeys is the registry of all known configuration keys with their schemas.
 var KnownKeys = map[string]ConfigKeySchema{
+	"config_version": {
+		Path:        "config_version",
+		Type:        TypeInt,
+		Description: "Config file format version (upgrade with 'autospec config migrate')",
+		Default:     CurrentConfigVersion,
+	},
 	"agent_preset": {
 		Path:          "agent_preset",
 		Type:          TypeEnum,
@@ -66,18 +75,99 @@ var KnownKeys = map[string]ConfigKeySchema{
 		Description: "Maximum number of retry attempts",
 		Default:     0,
 	},
+	"artifact_backups": {
+		Path:        "artifact_backups",
+		Type:        TypeInt,
+		Description: "Backups kept when autospec rewrites a spec artifact (0 disables)",
+		Default:     1,
+	},
+	"transcripts": {
+		Path:        "transcripts",
+		Type:        TypeBool,
+		Description: "Write transcript-<stage>.md to the spec directory after each stage",
+		Default:     true,
+	},
+	"markdown_views": {
+		Path:        "markdown_views",
+		Type:        TypeBool,
+		Description: "Render spec.md, plan.md, ... from the YAML artifacts after each stage",
+		Default:     false,
+	},
+	"artifact_staging": {
+		Path:        "artifact_staging",
+		Type:        TypeBool,
+		Description: "Plan and tasks write to .autospec/staging and are promoted to the spec dir only once valid",
+		Default:     true,
+	},
+	"artifact_salvage": {
+		Path:        "artifact_salvage",
+		Type:        TypeBool,
+		Description: "Recover an artifact the agent printed in a fenced YAML block instead of writing it",
+		Default:     true,
+	},
+	"validation_mode": {
+		Path:          "validation_mode",
+		Type:          TypeEnum,
+		AllowedValues: []string{"lenient", "strict"},
+		Description:   "Artifact validation of unknown fields and missing optional sections: lenient (warnings) or strict (errors)",
+		Default:       "lenient",
+	},
+	"status_line.enabled": {
+		Path:        "status_line.enabled",
+		Type:        TypeBool,
+		Description: "Write a one-line run status (spec, stage, task x/y) for tmux status-right",
+		Default:     false,
+	},
+	"status_line.path": {
+		Path:        "status_line.path",
+		Type:        TypeString,
+		Description: "Status line file (empty = .autospec/status-line)",
+		Default:     "",
+	},
+	"status_line.tmux_message": {
+		Path:        "status_line.tmux_message",
+		Type:        TypeBool,
+		Description: "Show the status line with tmux display-message on stage transitions",
+		Default:     false,
+	},
 	"timeout": {
 		Path:        "timeout",
 		Type:        TypeInt,
 		Description: "Timeout in seconds for Claude operations",
 		Default:     2400,
 	},
+	"cancel_grace_period": {
+		Path:        "cancel_grace_period",
+		Type:        TypeDuration,
+		Description: "Time a running agent gets to exit after Ctrl+C or SIGTERM before it is killed",
+		Default:     "10s",
+	},
+	"task_timeout": {
+		Path:        "task_timeout",
+		Type:        TypeDuration,
+		Description: "Per-task time limit for implement --tasks; tasks that exceed it are marked Blocked (0s = no limit)",
+		Default:     "0s",
+	},
+	"shell": {
+		Path:          "shell",
+		Type:          TypeEnum,
+		Description:   "Shell for task commands, formatters and post-processors (empty: cmd on Windows, sh elsewhere)",
+		Default:       "",
+		AllowedValues: []string{"", "sh", "bash", "zsh", "cmd", "powershell", "pwsh"},
+	},
 	"specs_dir": {
 		Path:        "specs_dir",
 		Type:        TypeString,
 		Description: "Directory for spec files",
 		Default:     "./specs",
 	},
+	"specs_layout": {
+		Path:          "specs_layout",
+		Type:          TypeEnum,
+		Description:   "Spec directory layout: numbered (001-name), dated (2025-01-15-name), categorized (<category>/001-name) or flat (name)",
+		Default:       "numbered",
+		AllowedValues: []string{"numbered", "dated", "categorized", "flat"},
+	},
 	"state_dir": {
 		Path:        "state_dir",
 		Type:        TypeString,
@@ -127,6 +217,193 @@ var KnownKeys = map[string]ConfigKeySchema{
 		Description: "Enable risk assessment in plan generation",
 		Default:     false,
 	},
+	"checklist_gate": {
+		Path:        "checklist_gate",
+		Type:        TypeBool,
+		Description: "Block implement until all checklist items are pass or waived",
+		Default:     false,
+	},
+	"analyze_fail_on": {
+		Path:          "analyze_fail_on",
+		Type:          TypeEnum,
+		AllowedValues: []string{"", "critical", "high", "medium", "low"},
+		Description:   "Lowest analysis finding severity that blocks implement",
+		Default:       "",
+	},
+	"review.enabled": {
+		Path:        "review.enabled",
+		Type:        TypeBool,
+		Description: "Block plan until the required reviewers approve spec.yaml ('autospec review')",
+		Default:     false,
+	},
+	"review.required_reviewers": {
+		Path:        "review.required_reviewers",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Reviewers who must approve spec.yaml (empty = any one approval)",
+		Default:     "",
+	},
+	"learnings.retrospect": {
+		Path:        "learnings.retrospect",
+		Type:        TypeBool,
+		Description: "Run a retrospection session that appends to learnings.yaml after each completed spec",
+		Default:     false,
+	},
+	"learnings.max_in_prompt": {
+		Path:        "learnings.max_in_prompt",
+		Type:        TypeInt,
+		Description: "Top learnings added to plan and implement prompts (0 = none)",
+		Default:     10,
+	},
+	"prompt_size.context_window": {
+		Path:        "prompt_size.context_window",
+		Type:        TypeInt,
+		Description: "Model context window in tokens for prompt size estimates (0 = no checks)",
+		Default:     200000,
+	},
+	"prompt_size.warn_percent": {
+		Path:        "prompt_size.warn_percent",
+		Type:        TypeInt,
+		Description: "Share of the context window a prompt may use before it warns and is truncated",
+		Default:     80,
+	},
+	"prompt_size.truncation": {
+		Path:        "prompt_size.truncation",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Truncation strategies applied in order: drop_learnings, summarize_files",
+		Default:     "drop_learnings,summarize_files",
+	},
+	"overlap_check.enabled": {
+		Path:        "overlap_check.enabled",
+		Type:        TypeBool,
+		Description: "Compare each 'autospec specify' feature description with the existing specs",
+		Default:     false,
+	},
+	"overlap_check.min_score": {
+		Path:        "overlap_check.min_score",
+		Type:        TypeInt,
+		Description: "Share, in percent, of the description an existing spec must cover to be reported",
+		Default:     40,
+	},
+	"overlap_check.confirm": {
+		Path:        "overlap_check.confirm",
+		Type:        TypeBool,
+		Description: "Ask before creating a spec that overlaps existing ones (interactive terminals only)",
+		Default:     true,
+	},
+	"preflight.disable": {
+		Path:        "preflight.disable",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Preflight checks to skip: agent, project, permissions, git-clean, disk-space, network",
+		Default:     "",
+	},
+	"preflight.commands": {
+		Path:        "preflight.commands",
+		Type:        TypeString, // Actually a map, but we handle as string for simplicity
+		Description: "Extra preflight checks as name: shell command; a non-zero exit stops the run",
+		Default:     "",
+	},
+	"storage.warn_mb": {
+		Path:        "storage.warn_mb",
+		Type:        TypeInt,
+		Description: "Warn when the state directory grows past this size in MB (0 = never)",
+		Default:     500,
+	},
+	"storage.max_mb": {
+		Path:        "storage.max_mb",
+		Type:        TypeInt,
+		Description: "Refuse to start runs while the state directory is larger than this in MB (0 = no limit)",
+		Default:     0,
+	},
+	"storage.logs_warn_mb": {
+		Path:        "storage.logs_warn_mb",
+		Type:        TypeInt,
+		Description: "Warn when run logs (runs/) and diagnostic logs (logs/) grow past this size in MB (0 = never)",
+		Default:     200,
+	},
+	"storage.logs_max_mb": {
+		Path:        "storage.logs_max_mb",
+		Type:        TypeInt,
+		Description: "Refuse to start runs while the logs are larger than this in MB (0 = no limit)",
+		Default:     0,
+	},
+	"storage.keep_runs": {
+		Path:        "storage.keep_runs",
+		Type:        TypeInt,
+		Description: "Run logs kept, newest first; older ones are deleted before each run (0 = all)",
+		Default:     100,
+	},
+	"storage.max_run_age": {
+		Path:        "storage.max_run_age",
+		Type:        TypeDuration,
+		Description: "Delete run logs older than this before each run (0s = keep)",
+		Default:     "0s",
+	},
+	"budget.max_run_duration": {
+		Path:        "budget.max_run_duration",
+		Type:        TypeDuration,
+		Description: "Wall-clock limit for a whole run (0 = unlimited)",
+		Default:     "0s",
+	},
+	"budget.max_stage_duration": {
+		Path:        "budget.max_stage_duration",
+		Type:        TypeDuration,
+		Description: "Agent time limit per stage, across retries, phases and tasks (0 = unlimited)",
+		Default:     "0s",
+	},
+	"budget.max_run_tokens": {
+		Path:        "budget.max_run_tokens",
+		Type:        TypeInt,
+		Description: "Estimated token limit for a whole run (0 = unlimited)",
+		Default:     0,
+	},
+	"budget.max_stage_tokens": {
+		Path:        "budget.max_stage_tokens",
+		Type:        TypeInt,
+		Description: "Estimated token limit per stage (0 = unlimited)",
+		Default:     0,
+	},
+	"budget.max_run_cost_usd": {
+		Path:        "budget.max_run_cost_usd",
+		Type:        TypeFloat,
+		Description: "Estimated cost limit in USD for a whole run (0 = unlimited)",
+		Default:     0.0,
+	},
+	"budget.max_stage_cost_usd": {
+		Path:        "budget.max_stage_cost_usd",
+		Type:        TypeFloat,
+		Description: "Estimated cost limit in USD per stage (0 = unlimited)",
+		Default:     0.0,
+	},
+	"budget.cost_per_million_tokens": {
+		Path:        "budget.cost_per_million_tokens",
+		Type:        TypeFloat,
+		Description: "USD price per million estimated tokens when the agent does not report cost",
+		Default:     0.0,
+	},
+	"budget.max_run_retries": {
+		Path:        "budget.max_run_retries",
+		Type:        TypeInt,
+		Description: "Retry limit for a whole run, across all stages and tasks (0 = unlimited)",
+		Default:     0,
+	},
+	"rate_limit.enabled": {
+		Path:        "rate_limit.enabled",
+		Type:        TypeBool,
+		Description: "Wait out provider rate limits instead of counting the session as a failed attempt",
+		Default:     true,
+	},
+	"rate_limit.max_wait": {
+		Path:        "rate_limit.max_wait",
+		Type:        TypeDuration,
+		Description: "Total time a run may spend waiting on rate limits (0 = no cap)",
+		Default:     "1h",
+	},
+	"rate_limit.initial_backoff": {
+		Path:        "rate_limit.initial_backoff",
+		Type:        TypeDuration,
+		Description: "First wait when the provider gives no retry hint; doubles on consecutive limits",
+		Default:     "30s",
+	},
 	"notifications.enabled": {
 		Path:        "notifications.enabled",
 		Type:        TypeBool,
@@ -146,6 +423,19 @@ var KnownKeys = map[string]ConfigKeySchema{
 		Description: "Custom sound file path for notifications",
 		Default:     "",
 	},
+	"notifications.message_template": {
+		Path:        "notifications.message_template",
+		Type:        TypeString,
+		Description: "text/template replacing the notification text (empty = built-in text with spec, stage, task, failure and link)",
+		Default:     "",
+	},
+	"notifications.link": {
+		Path:          "notifications.link",
+		Type:          TypeEnum,
+		AllowedValues: []string{"file", "vscode", "cursor", "none"},
+		Description:   "How notifications link to the stage's artifact",
+		Default:       "file",
+	},
 	"notifications.on_command_complete": {
 		Path:        "notifications.on_command_complete",
 		Type:        TypeBool,
@@ -164,10 +454,180 @@ var KnownKeys = map[string]ConfigKeySchema{
 		Description: "Notify on command or stage failure",
 		Default:     true,
 	},
+	"notifications.on_budget_exceeded": {
+		Path:        "notifications.on_budget_exceeded",
+		Type:        TypeBool,
+		Description: "Notify when a run stops because a budget limit was reached",
+		Default:     true,
+	},
+	"notifications.on_rate_limited": {
+		Path:        "notifications.on_rate_limited",
+		Type:        TypeBool,
+		Description: "Notify when a run waits out a provider rate limit",
+		Default:     true,
+	},
+	"notifications.on_cancelled": {
+		Path:        "notifications.on_cancelled",
+		Type:        TypeBool,
+		Description: "Notify when Ctrl+C or SIGTERM stops a command",
+		Default:     true,
+	},
+	"notifications.schedule.quiet_hours": {
+		Path:        "notifications.schedule.quiet_hours",
+		Type:        TypeString,
+		Description: "Daily quiet window for desktop notifications in local time (e.g., 22:00-08:00)",
+		Default:     "",
+	},
+	"notifications.schedule.quiet_days": {
+		Path:        "notifications.schedule.quiet_days",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Whole days without desktop notifications (day names, weekends, weekdays)",
+		Default:     "",
+	},
+	"notifications.schedule.mode": {
+		Path:          "notifications.schedule.mode",
+		Type:          TypeEnum,
+		AllowedValues: []string{"suppress", "log"},
+		Description:   "What happens to desktop notifications during quiet hours",
+		Default:       "suppress",
+	},
+	"notifications.schedule.always_notify": {
+		Path:        "notifications.schedule.always_notify",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Hooks that notify even during quiet hours (e.g., on_error)",
+		Default:     "",
+	},
+	"notifications.email.enabled": {
+		Path:        "notifications.email.enabled",
+		Type:        TypeBool,
+		Description: "Send notifications by email (SMTP), including from CI and non-interactive runs",
+		Default:     false,
+	},
+	"notifications.email.host": {
+		Path:        "notifications.email.host",
+		Type:        TypeString,
+		Description: "SMTP server host",
+		Default:     "",
+	},
+	"notifications.email.port": {
+		Path:        "notifications.email.port",
+		Type:        TypeInt,
+		Description: "SMTP server port",
+		Default:     587,
+	},
+	"notifications.email.starttls": {
+		Path:        "notifications.email.starttls",
+		Type:        TypeBool,
+		Description: "Require STARTTLS before authenticating and sending",
+		Default:     true,
+	},
+	"notifications.email.username": {
+		Path:        "notifications.email.username",
+		Type:        TypeString,
+		Description: "SMTP username for PLAIN auth (empty disables auth)",
+		Default:     "",
+	},
+	"notifications.email.password": {
+		Path:        "notifications.email.password",
+		Type:        TypeString,
+		Description: "SMTP password (prefer AUTOSPEC_NOTIFICATIONS_EMAIL_PASSWORD)",
+		Default:     "",
+	},
+	"notifications.email.from": {
+		Path:        "notifications.email.from",
+		Type:        TypeString,
+		Description: "Sender address for notification emails",
+		Default:     "",
+	},
+	"notifications.email.to": {
+		Path:        "notifications.email.to",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Recipient addresses for notification emails",
+		Default:     "",
+	},
+	"notifications.email.subject": {
+		Path:        "notifications.email.subject",
+		Type:        TypeString,
+		Description: "Subject line template for notification emails",
+		Default:     "[autospec] {{.Message}}",
+	},
+	"notifications.ntfy.enabled": {
+		Path:        "notifications.ntfy.enabled",
+		Type:        TypeBool,
+		Description: "Send mobile push notifications through ntfy, including from CI and non-interactive runs",
+		Default:     false,
+	},
+	"notifications.ntfy.server": {
+		Path:        "notifications.ntfy.server",
+		Type:        TypeString,
+		Description: "ntfy server URL",
+		Default:     "https://ntfy.sh",
+	},
+	"notifications.ntfy.topic": {
+		Path:        "notifications.ntfy.topic",
+		Type:        TypeString,
+		Description: "ntfy topic to publish to",
+		Default:     "",
+	},
+	"notifications.ntfy.token": {
+		Path:        "notifications.ntfy.token",
+		Type:        TypeString,
+		Description: "ntfy access token (prefer AUTOSPEC_NOTIFICATIONS_NTFY_TOKEN)",
+		Default:     "",
+	},
+	"notifications.ntfy.priority": {
+		Path:          "notifications.ntfy.priority",
+		Type:          TypeEnum,
+		AllowedValues: []string{"min", "low", "default", "high", "urgent"},
+		Description:   "ntfy message priority",
+		Default:       "default",
+	},
+	"notifications.ntfy.min_interval": {
+		Path:        "notifications.ntfy.min_interval",
+		Type:        TypeDuration,
+		Description: "Minimum time between ntfy pushes (0 disables rate limiting)",
+		Default:     "30s",
+	},
+	"notifications.pushover.enabled": {
+		Path:        "notifications.pushover.enabled",
+		Type:        TypeBool,
+		Description: "Send mobile push notifications through Pushover, including from CI and non-interactive runs",
+		Default:     false,
+	},
+	"notifications.pushover.token": {
+		Path:        "notifications.pushover.token",
+		Type:        TypeString,
+		Description: "Pushover application token (prefer AUTOSPEC_NOTIFICATIONS_PUSHOVER_TOKEN)",
+		Default:     "",
+	},
+	"notifications.pushover.user": {
+		Path:        "notifications.pushover.user",
+		Type:        TypeString,
+		Description: "Pushover user or group key (prefer AUTOSPEC_NOTIFICATIONS_PUSHOVER_USER)",
+		Default:     "",
+	},
+	"notifications.pushover.device": {
+		Path:        "notifications.pushover.device",
+		Type:        TypeString,
+		Description: "Pushover device name (empty sends to all devices)",
+		Default:     "",
+	},
+	"notifications.pushover.priority": {
+		Path:        "notifications.pushover.priority",
+		Type:        TypeInt,
+		Description: "Pushover priority from -2 (silent) to 1 (high)",
+		Default:     0,
+	},
+	"notifications.pushover.min_interval": {
+		Path:        "notifications.pushover.min_interval",
+		Type:        TypeDuration,
+		Description: "Minimum time between Pushover pushes (0 disables rate limiting)",
+		Default:     "30s",
+	},
 	"notifications.on_long_running": {
 		Path:        "notifications.on_long_running",
 		Type:        TypeBool,
-		Description: "Notify only if duration exceeds threshold",
+		Description: "Notify when a stage runs past the threshold; also limits command-complete notifications to longer commands",
 		Default:     false,
 	},
 	"notifications.long_running_threshold": {
@@ -224,6 +684,219 @@ var KnownKeys = map[string]ConfigKeySchema{
 		Description: "Non-tracked directories to copy to worktrees",
 		Default:     "",
 	},
+	"redaction.enabled": {
+		Path:        "redaction.enabled",
+		Type:        TypeBool,
+		Description: "Mask secrets in agent output, run logs and notifications",
+		Default:     true,
+	},
+	"redaction.patterns": {
+		Path:        "redaction.patterns",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Extra regular expressions to mask (the first capture group, if any)",
+		Default:     "",
+	},
+	"redaction.env_files": {
+		Path:        "redaction.env_files",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Dotenv files whose values are masked",
+		Default:     ".env",
+	},
+	"encryption.enabled": {
+		Path:        "encryption.enabled",
+		Type:        TypeBool,
+		Description: "Encrypt run logs, the log file and transcripts at rest",
+		Default:     false,
+	},
+	"encryption.key_env": {
+		Path:        "encryption.key_env",
+		Type:        TypeString,
+		Description: "Environment variable holding the base64 or hex encryption key",
+		Default:     "AUTOSPEC_ENCRYPTION_KEY",
+	},
+	"encryption.key_command": {
+		Path:        "encryption.key_command",
+		Type:        TypeString,
+		Description: "Command printing the key when the variable is unset (e.g. a keychain lookup)",
+		Default:     "",
+	},
+	"secrets.keychain": {
+		Path:        "secrets.keychain",
+		Type:        TypeBool,
+		Description: "Read tokens and passwords missing from the config and environment from the OS keychain",
+		Default:     true,
+	},
+	"secrets.service": {
+		Path:        "secrets.service",
+		Type:        TypeString,
+		Description: "Keychain service name secrets are stored under",
+		Default:     "autospec",
+	},
+	"logging.level": {
+		Path:          "logging.level",
+		Type:          TypeEnum,
+		Description:   "Minimum console log level (overridden by --log-level and --debug)",
+		Default:       "info",
+		AllowedValues: []string{"debug", "info", "warn", "error"},
+	},
+	"logging.format": {
+		Path:          "logging.format",
+		Type:          TypeEnum,
+		Description:   "Log record format",
+		Default:       "text",
+		AllowedValues: []string{"text", "json"},
+	},
+	"logging.file": {
+		Path:        "logging.file",
+		Type:        TypeBool,
+		Description: "Write debug-level logs to <state_dir>/logs/autospec.log",
+		Default:     false,
+	},
+	"logging.max_size_mb": {
+		Path:        "logging.max_size_mb",
+		Type:        TypeInt,
+		Description: "Size in MB at which the log file is rotated",
+		Default:     10,
+	},
+	"logging.max_files": {
+		Path:        "logging.max_files",
+		Type:        TypeInt,
+		Description: "Number of rotated log files to keep",
+		Default:     3,
+	},
+	"changelog.enabled": {
+		Path:        "changelog.enabled",
+		Type:        TypeBool,
+		Description: "Append a generated entry to the changelog when a spec is completed",
+		Default:     false,
+	},
+	"changelog.file": {
+		Path:        "changelog.file",
+		Type:        TypeString,
+		Description: "Changelog file that completed specs are added to",
+		Default:     "CHANGELOG.md",
+	},
+	"lint.max_phase_tasks": {
+		Path:        "lint.max_phase_tasks",
+		Type:        TypeInt,
+		Description: "Largest phase the phase_size lint rule accepts",
+		Default:     10,
+	},
+	"issue_tracker.provider": {
+		Path:          "issue_tracker.provider",
+		Type:          TypeEnum,
+		Description:   "Issue tracker to sync user stories with (empty disables)",
+		Default:       "",
+		AllowedValues: []string{"", "jira", "linear"},
+	},
+	"issue_tracker.in_progress_state": {
+		Path:        "issue_tracker.in_progress_state",
+		Type:        TypeString,
+		Description: "Issue state for stories with completed tasks",
+		Default:     "In Progress",
+	},
+	"issue_tracker.done_state": {
+		Path:        "issue_tracker.done_state",
+		Type:        TypeString,
+		Description: "Issue state for stories whose tasks are all completed",
+		Default:     "Done",
+	},
+	"issue_tracker.jira.base_url": {
+		Path:        "issue_tracker.jira.base_url",
+		Type:        TypeString,
+		Description: "Jira site URL, e.g. https://acme.atlassian.net (https required except for localhost)",
+		Default:     "",
+	},
+	"issue_tracker.jira.project_key": {
+		Path:        "issue_tracker.jira.project_key",
+		Type:        TypeString,
+		Description: "Jira project that story issues are created in",
+		Default:     "",
+	},
+	"issue_tracker.jira.issue_type": {
+		Path:        "issue_tracker.jira.issue_type",
+		Type:        TypeString,
+		Description: "Jira issue type for story issues",
+		Default:     "Story",
+	},
+	"issue_tracker.jira.email": {
+		Path:        "issue_tracker.jira.email",
+		Type:        TypeString,
+		Description: "Jira account email the API token belongs to",
+		Default:     "",
+	},
+	"issue_tracker.jira.token_env": {
+		Path:        "issue_tracker.jira.token_env",
+		Type:        TypeString,
+		Description: "Environment variable holding the Jira API token",
+		Default:     "JIRA_API_TOKEN",
+	},
+	"issue_tracker.linear.team_id": {
+		Path:        "issue_tracker.linear.team_id",
+		Type:        TypeString,
+		Description: "Linear team that story issues are created in",
+		Default:     "",
+	},
+	"issue_tracker.linear.token_env": {
+		Path:        "issue_tracker.linear.token_env",
+		Type:        TypeString,
+		Description: "Environment variable holding the Linear API key",
+		Default:     "LINEAR_API_KEY",
+	},
+	"issue_tracker.github.hosts": {
+		Path:        "issue_tracker.github.hosts",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "GitHub Enterprise hosts that --from-issue may send GITHUB_TOKEN to",
+		Default:     "",
+	},
+	"issue_tracker.gitlab.hosts": {
+		Path:        "issue_tracker.gitlab.hosts",
+		Type:        TypeString, // Actually a list, but we handle as string for simplicity
+		Description: "Self-hosted GitLab hosts that --from-issue may send GITLAB_TOKEN to",
+		Default:     "",
+	},
+	"github_project.owner": {
+		Path:        "github_project.owner",
+		Type:        TypeString,
+		Description: "User or organization that owns the GitHub project tasks are mirrored to (empty disables)",
+		Default:     "",
+	},
+	"github_project.number": {
+		Path:        "github_project.number",
+		Type:        TypeInt,
+		Description: "Number of the GitHub project tasks are mirrored to",
+		Default:     0,
+	},
+	"github_project.status_field": {
+		Path:        "github_project.status_field",
+		Type:        TypeString,
+		Description: "Single-select project field whose options are the board columns",
+		Default:     "Status",
+	},
+	"github_project.columns.pending": {
+		Path:        "github_project.columns.pending",
+		Type:        TypeString,
+		Description: "Project column for Pending tasks",
+		Default:     "Todo",
+	},
+	"github_project.columns.in_progress": {
+		Path:        "github_project.columns.in_progress",
+		Type:        TypeString,
+		Description: "Project column for InProgress tasks",
+		Default:     "In Progress",
+	},
+	"github_project.columns.blocked": {
+		Path:        "github_project.columns.blocked",
+		Type:        TypeString,
+		Description: "Project column for Blocked tasks",
+		Default:     "Blocked",
+	},
+	"github_project.columns.completed": {
+		Path:        "github_project.columns.completed",
+		Type:        TypeString,
+		Description: "Project column for Completed tasks",
+		Default:     "Done",
+	},
 	"cclean.verbose": {
 		Path:        "cclean.verbose",
 		Type:        TypeBool,
@@ -307,6 +980,8 @@ func validateAgainstSchema(schema ConfigKeySchema, value string) (ParsedValue, e
 		return parseDurationValue(value)
 	case TypeEnum:
 		return parseEnumValue(schema, value)
+	case TypeFloat:
+		return parseFloatValue(value)
 	case TypeString:
 		return ParsedValue{Raw: value, Parsed: value, Type: TypeString}, nil
 	default:
@@ -335,6 +1010,15 @@ func parseIntValue(value string) (ParsedValue, error) {
 	return ParsedValue{Raw: value, Parsed: n, Type: TypeInt}, nil
 }
 
+// parseFloatValue parses and validates a non-negative decimal value.
+func parseFloatValue(value string) (ParsedValue, error) {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 {
+		return ParsedValue{}, fmt.Errorf("invalid number: %q (expected a non-negative decimal, e.g. 2.50)", value)
+	}
+	return ParsedValue{Raw: value, Parsed: f, Type: TypeFloat}, nil
+}
+
 // parseDurationValue parses and validates a duration value.
 func parseDurationValue(value string) (ParsedValue, error) {
 	d, err := time.ParseDuration(value)
@@ -111,6 +111,7 @@ func TestConfigValueType_String(t *testing.T) {
 		"duration": {valueType: TypeDuration, want: "duration"},
 		"string":   {valueType: TypeString, want: "string"},
 		"enum":     {valueType: TypeEnum, want: "enum"},
+		"float":    {valueType: TypeFloat, want: "float"},
 		"unknown":  {valueType: ConfigValueType(99), want: "unknown"},
 	}
 
@@ -230,6 +231,18 @@ func TestValidateValue(t *testing.T) {
 			wantErr:    true,
 			errContain: "invalid duration",
 		},
+		"valid float": {
+			key:        "budget.max_run_cost_usd",
+			value:      "2.50",
+			wantParsed: 2.5,
+			wantType:   TypeFloat,
+		},
+		"negative float": {
+			key:        "budget.max_run_cost_usd",
+			value:      "-1",
+			wantErr:    true,
+			errContain: "invalid number",
+		},
 		"valid enum": {
 			key:        "notifications.type",
 			value:      "sound",
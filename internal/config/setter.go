@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -113,6 +114,9 @@ func setScalarValue(node *yaml.Node, value interface{}) error {
 	case int:
 		node.Tag = "!!int"
 		node.Value = fmt.Sprintf("%d", v)
+	case float64:
+		node.Tag = "!!float"
+		node.Value = strconv.FormatFloat(v, 'f', -1, 64)
 	case string:
 		node.Tag = "!!str"
 		node.Value = v
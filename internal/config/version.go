@@ -0,0 +1,499 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentConfigVersion is the config file format written by this version of autospec.
+// Files without a config_version key are treated as version 1.
+const CurrentConfigVersion = 2
+
+// configVersionKey is the top-level key holding the config file format version.
+const configVersionKey = "config_version"
+
+// configMigration upgrades a config document from one version to the next.
+// apply edits the top-level mapping node in place and returns a description
+// of each change it made.
+type configMigration struct {
+	from  int
+	apply func(root *yaml.Node) []string
+}
+
+// configMigrations lists the migrations in order; entry i upgrades version i+1.
+var configMigrations = []configMigration{
+	{from: 1, apply: migrateV1ToV2},
+}
+
+// VersionMigrationResult describes the outcome of a config version migration.
+type VersionMigrationResult struct {
+	ConfigPath  string   // Path to the config file
+	FromVersion int      // Version found in the file (1 if config_version is absent)
+	ToVersion   int      // Version after migration
+	Changes     []string // Legacy settings that were rewritten or removed
+	BackupPath  string   // Copy of the original file (empty for dry runs or no-ops)
+	DryRun      bool
+}
+
+// NeedsMigration returns true if the file is older than CurrentConfigVersion.
+func (r *VersionMigrationResult) NeedsMigration() bool {
+	return r.FromVersion < r.ToVersion
+}
+
+// MigrateConfigVersion upgrades a config file to CurrentConfigVersion.
+// The original file is copied to <path>.v<N>.bak before it is rewritten.
+// A missing or empty file is left alone. With dryRun, nothing is written.
+func MigrateConfigVersion(configPath string, dryRun bool) (*VersionMigrationResult, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &VersionMigrationResult{
+				ConfigPath:  configPath,
+				FromVersion: CurrentConfigVersion,
+				ToVersion:   CurrentConfigVersion,
+				DryRun:      dryRun,
+			}, nil
+		}
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	content, result, err := migrateConfigData(data, configPath)
+	if err != nil {
+		return nil, err
+	}
+	result.DryRun = dryRun
+	if dryRun || !result.NeedsMigration() {
+		return result, nil
+	}
+
+	if err := writeMigratedConfig(configPath, data, content, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// loadVersionedConfig reads a YAML config file, migrates it in memory if it
+// uses an older format, and validates it against the schema. Files that
+// contain legacy settings are rewritten (with a backup); failure to write is
+// only a warning since the migrated content is still used.
+// Returns the content to load.
+func loadVersionedConfig(configPath, configType string, warningWriter io.Writer, skipWarnings bool) ([]byte, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s config: %w", configType, err)
+	}
+
+	content, result, err := migrateConfigData(data, configPath)
+	if err != nil {
+		return nil, err
+	}
+	// Only rewrite files with legacy settings; a missing config_version alone
+	// is stamped by 'autospec config migrate'
+	if len(result.Changes) == 0 {
+		return content, nil
+	}
+
+	if err := writeMigratedConfig(configPath, data, content, result); err != nil {
+		if !skipWarnings {
+			fmt.Fprintf(warningWriter, "Warning: could not save migrated %s config %s: %v\n", configType, configPath, err)
+			fmt.Fprintf(warningWriter, "  Run 'autospec config migrate' to retry.\n\n")
+		}
+		return content, nil
+	}
+	if !skipWarnings {
+		fmt.Fprintf(warningWriter, "Migrated %s config %s to config_version %d (backup: %s)\n",
+			configType, configPath, result.ToVersion, result.BackupPath)
+		for _, change := range result.Changes {
+			fmt.Fprintf(warningWriter, "  - %s\n", change)
+		}
+		fmt.Fprintln(warningWriter)
+	}
+	return content, nil
+}
+
+// migrateConfigData migrates and validates config content.
+// Returns the content unchanged if it is already current.
+func migrateConfigData(data []byte, configPath string) ([]byte, *VersionMigrationResult, error) {
+	result := &VersionMigrationResult{
+		ConfigPath:  configPath,
+		FromVersion: CurrentConfigVersion,
+		ToVersion:   CurrentConfigVersion,
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return data, result, nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("parsing config YAML: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return data, result, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, nil, &ValidationError{
+			FilePath: configPath,
+			Line:     root.Line,
+			Column:   root.Column,
+			Message:  "config must be a mapping of keys to values",
+		}
+	}
+
+	version, err := readConfigVersion(root, configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	result.FromVersion = version
+
+	for _, m := range configMigrations {
+		if m.from >= version {
+			result.Changes = append(result.Changes, m.apply(root)...)
+		}
+	}
+
+	if err := ValidateConfigSchema(root, configPath); err != nil {
+		return nil, nil, err
+	}
+
+	if !result.NeedsMigration() {
+		return data, result, nil
+	}
+	setConfigVersion(root, CurrentConfigVersion)
+	content, err := yaml.Marshal(&doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("marshaling migrated config: %w", err)
+	}
+	return content, result, nil
+}
+
+// writeMigratedConfig backs up the original content and writes the migrated content.
+func writeMigratedConfig(configPath string, original, content []byte, result *VersionMigrationResult) error {
+	backupPath := fmt.Sprintf("%s.v%d.bak", configPath, result.FromVersion)
+	// The backup may hold tokens, so it keeps the source file's permissions
+	perm := os.FileMode(0o600)
+	if info, err := os.Stat(configPath); err == nil {
+		perm = info.Mode().Perm()
+	}
+	if err := fsutil.WriteFile(backupPath, original, perm); err != nil {
+		return fmt.Errorf("backing up config: %w", err)
+	}
+	if err := writeAtomically(configPath, content); err != nil {
+		return fmt.Errorf("writing migrated config: %w", err)
+	}
+	result.BackupPath = backupPath
+	return nil
+}
+
+// readConfigVersion returns the config_version of a top-level mapping node.
+func readConfigVersion(root *yaml.Node, configPath string) (int, error) {
+	idx := findKeyIndex(root, configVersionKey)
+	if idx == -1 {
+		return 1, nil
+	}
+	node := root.Content[idx+1]
+	version, err := strconv.Atoi(node.Value)
+	if node.Kind != yaml.ScalarNode || err != nil || version < 1 {
+		return 0, &ValidationError{
+			FilePath: configPath,
+			Line:     node.Line,
+			Column:   node.Column,
+			Field:    configVersionKey,
+			Message:  fmt.Sprintf("%s: must be a positive integer, got %q", configVersionKey, node.Value),
+		}
+	}
+	if version > CurrentConfigVersion {
+		return 0, &ValidationError{
+			FilePath: configPath,
+			Line:     node.Line,
+			Column:   node.Column,
+			Field:    configVersionKey,
+			Message: fmt.Sprintf("%s: %d is newer than this autospec supports (%d); upgrade autospec",
+				configVersionKey, version, CurrentConfigVersion),
+		}
+	}
+	return version, nil
+}
+
+// setConfigVersion sets config_version, adding it as the first key if absent.
+func setConfigVersion(root *yaml.Node, version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if idx := findKeyIndex(root, configVersionKey); idx != -1 {
+		root.Content[idx+1] = value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: configVersionKey}
+	if len(root.Content) > 0 {
+		// Keep the file's header comment at the top
+		key.HeadComment, root.Content[0].HeadComment = root.Content[0].HeadComment, ""
+	}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
+
+// migrateV1ToV2 rewrites settings removed before config_version existed:
+// output_style moved to cclean.style, and claude_cmd, claude_args and
+// custom_claude_cmd were replaced by custom_agent.
+func migrateV1ToV2(root *yaml.Node) []string {
+	var changes []string
+
+	if style := removeKey(root, "output_style"); style != nil {
+		if GetNestedValue(root, []string{"cclean", "style"}) == nil && style.Kind == yaml.ScalarNode {
+			_ = SetNestedValue(root, []string{"cclean", "style"}, style.Value)
+			changes = append(changes, fmt.Sprintf("output_style: %s -> cclean.style", style.Value))
+		} else {
+			changes = append(changes, "output_style: removed (cclean.style already set)")
+		}
+	}
+
+	customCmd := removeKey(root, "custom_claude_cmd")
+	claudeCmd := removeKey(root, "claude_cmd")
+	claudeArgs := removeKey(root, "claude_args")
+	agent := legacyCustomAgent(customCmd, claudeCmd, claudeArgs)
+
+	if agent != nil && findKeyIndex(root, "custom_agent") == -1 {
+		var agentNode yaml.Node
+		_ = agentNode.Encode(agent)
+		root.Content = append(root.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "custom_agent"}, &agentNode)
+		changes = append(changes, fmt.Sprintf("%s -> custom_agent (command: %s)",
+			strings.Join(legacyAgentKeys(customCmd, claudeCmd, claudeArgs), ", "), agent.Command))
+	} else if keys := legacyAgentKeys(customCmd, claudeCmd, claudeArgs); len(keys) > 0 {
+		changes = append(changes, fmt.Sprintf("%s: removed (custom_agent already set)", strings.Join(keys, ", ")))
+	}
+
+	return changes
+}
+
+// legacyAgent is the custom_agent written for legacy agent settings.
+type legacyAgent struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+}
+
+// legacyCustomAgent builds a custom_agent from the legacy agent settings.
+// custom_claude_cmd was a full command template, split into words as a
+// shell would; one using pipes, redirects or expansions runs under sh -c.
+// claude_cmd and claude_args had the prompt appended as the final argument.
+func legacyCustomAgent(customCmd, claudeCmd, claudeArgs *yaml.Node) *legacyAgent {
+	if customCmd != nil {
+		parts, ok := shellSplit(customCmd.Value)
+		if !ok {
+			return &legacyAgent{Command: "sh", Args: []string{"-c", shellScript(customCmd.Value), "sh", "{{PROMPT}}"}}
+		}
+		if len(parts) == 0 {
+			return nil
+		}
+		return &legacyAgent{Command: parts[0], Args: parts[1:]}
+	}
+	if claudeCmd == nil && claudeArgs == nil {
+		return nil
+	}
+	command := "claude"
+	if claudeCmd != nil && claudeCmd.Value != "" {
+		command = claudeCmd.Value
+	}
+	args := []string{}
+	if claudeArgs != nil {
+		for _, arg := range claudeArgs.Content {
+			args = append(args, arg.Value)
+		}
+	}
+	return &legacyAgent{Command: command, Args: append(args, "{{PROMPT}}")}
+}
+
+// shellSplit splits s into words as a POSIX shell does for a simple command:
+// quotes group and backslashes escape. ok is false when s has an unterminated
+// quote or uses other shell syntax, such as pipes, redirects or expansions.
+func shellSplit(s string) (words []string, ok bool) {
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case c == '\\':
+			if i+1 == len(s) {
+				return nil, false
+			}
+			i++
+			word.WriteByte(s[i])
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end == -1 {
+				return nil, false
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+		case c == '"':
+			for i++; ; i++ {
+				if i == len(s) {
+					return nil, false
+				}
+				if s[i] == '"' {
+					break
+				}
+				if s[i] == '$' || s[i] == '`' {
+					return nil, false
+				}
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) != -1 {
+					i++
+				}
+				word.WriteByte(s[i])
+			}
+		case strings.IndexByte("|&;<>()$`*?[", c) != -1, !inWord && (c == '#' || c == '~'):
+			return nil, false
+		default:
+			word.WriteByte(c)
+		}
+		inWord = true
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	if len(words) > 0 && strings.Contains(words[0], "=") {
+		// A leading NAME=value sets an environment variable
+		return nil, false
+	}
+	return words, true
+}
+
+// shellScript turns a command template into a script for sh -c that reads
+// the prompt from $1, quoted so that it expands to a single word.
+func shellScript(s string) string {
+	var b strings.Builder
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "{{PROMPT}}") {
+			switch quote {
+			case '"':
+				b.WriteString("$1")
+			case '\'':
+				b.WriteString(`'"$1"'`)
+			default:
+				b.WriteString(`"$1"`)
+			}
+			i += len("{{PROMPT}}") - 1
+			continue
+		}
+		c := s[i]
+		switch {
+		case c == '\\' && quote != '\'' && i+1 < len(s):
+			b.WriteByte(c)
+			i++
+			c = s[i]
+		case c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// legacyAgentKeys names the legacy agent settings that were present.
+func legacyAgentKeys(customCmd, claudeCmd, claudeArgs *yaml.Node) []string {
+	var keys []string
+	for _, k := range []struct {
+		name string
+		node *yaml.Node
+	}{{"custom_claude_cmd", customCmd}, {"claude_cmd", claudeCmd}, {"claude_args", claudeArgs}} {
+		if k.node != nil {
+			keys = append(keys, k.name)
+		}
+	}
+	return keys
+}
+
+// removeKey deletes a key from a mapping node and returns its value node,
+// or nil if the key was absent.
+func removeKey(node *yaml.Node, key string) *yaml.Node {
+	idx := findKeyIndex(node, key)
+	if idx == -1 {
+		return nil
+	}
+	value := node.Content[idx+1]
+	if comment := node.Content[idx].HeadComment; comment != "" && idx+2 < len(node.Content) {
+		// Keep comments such as a file header that precede the removed key
+		next := node.Content[idx+2]
+		next.HeadComment = strings.TrimSpace(comment + "\n" + next.HeadComment)
+	}
+	node.Content = append(node.Content[:idx], node.Content[idx+2:]...)
+	return value
+}
+
+// ValidateConfigSchema checks the value types in a config document against KnownKeys.
+// Each error is a ValidationError with the line and column of the bad value.
+// Unknown keys are ignored ('autospec config sync' removes them).
+func ValidateConfigSchema(root *yaml.Node, configPath string) error {
+	var errs []error
+	validateSchemaNode("", root, configPath, &errs)
+	return errors.Join(errs...)
+}
+
+// validateSchemaNode recursively validates the children of a mapping node.
+func validateSchemaNode(prefix string, node *yaml.Node, configPath string, errs *[]error) {
+	if node.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		value := node.Content[i+1]
+
+		schema, known := KnownKeys[key]
+		switch {
+		case known:
+			if err := validateSchemaValue(schema, value); err != nil {
+				*errs = append(*errs, &ValidationError{
+					FilePath: configPath,
+					Line:     value.Line,
+					Column:   value.Column,
+					Field:    key,
+					Message:  fmt.Sprintf("%s: %v", key, err),
+				})
+			}
+		case isUserDefinedMapKey(key):
+			// Free-form maps (custom_agent, task_commands, ...) have no schema
+		default:
+			validateSchemaNode(key, value, configPath, errs)
+		}
+	}
+}
+
+// validateSchemaValue checks that a value node has the type its key expects.
+// Only types are checked here, as leniently as the loader decodes them
+// (e.g., 0/1 for booleans); allowed values and ranges are checked by
+// ValidateConfigValues. Null values fall back to defaults, and string keys
+// also cover list-valued keys.
+func validateSchemaValue(schema ConfigKeySchema, value *yaml.Node) error {
+	if schema.Type == TypeString || schema.Type == TypeEnum || value.Tag == "!!null" {
+		return nil
+	}
+	if value.Kind != yaml.ScalarNode {
+		return fmt.Errorf("expected %s, got a list or mapping", schema.Type)
+	}
+	if schema.Type == TypeBool {
+		if _, err := strconv.ParseBool(value.Value); err != nil {
+			return fmt.Errorf("invalid boolean: %q (expected true or false)", value.Value)
+		}
+		return nil
+	}
+	_, err := validateAgainstSchema(schema, value.Value)
+	return err
+}
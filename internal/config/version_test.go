@@ -0,0 +1,328 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const legacyConfigYAML = `# My settings
+output_style: compact
+custom_claude_cmd: "claude -p --verbose {{PROMPT}}"
+max_retries: 2
+`
+
+func TestMigrateConfigVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content     string
+		wantFrom    int
+		wantChanges int
+		wantContent []string
+		wantAbsent  []string
+	}{
+		"legacy settings": {
+			content:     legacyConfigYAML,
+			wantFrom:    1,
+			wantChanges: 2,
+			wantContent: []string{"# My settings", "config_version: 2", "style: compact", "command: claude", "- --verbose", "'{{PROMPT}}'"},
+			wantAbsent:  []string{"output_style", "custom_claude_cmd"},
+		},
+		"claude_cmd and claude_args": {
+			content:     "claude_cmd: myclaude\nclaude_args: [\"-p\"]\n",
+			wantFrom:    1,
+			wantChanges: 1,
+			wantContent: []string{"command: myclaude", "- -p", "'{{PROMPT}}'"},
+			wantAbsent:  []string{"claude_cmd", "claude_args"},
+		},
+		"existing custom_agent wins": {
+			content:     "custom_claude_cmd: old {{PROMPT}}\ncustom_agent:\n  command: new\n",
+			wantFrom:    1,
+			wantChanges: 1,
+			wantContent: []string{"command: new"},
+			wantAbsent:  []string{"custom_claude_cmd", "command: old"},
+		},
+		"unversioned without legacy settings": {
+			content:     "max_retries: 3\n",
+			wantFrom:    1,
+			wantContent: []string{"config_version: 2", "max_retries: 3"},
+		},
+		"already current": {
+			content:     "config_version: 2\nmax_retries: 3\n",
+			wantFrom:    2,
+			wantContent: []string{"config_version: 2\nmax_retries: 3\n"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "config.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			result, err := MigrateConfigVersion(path, false)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFrom, result.FromVersion)
+			assert.Equal(t, CurrentConfigVersion, result.ToVersion)
+			assert.Len(t, result.Changes, tt.wantChanges)
+
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			for _, want := range tt.wantContent {
+				assert.Contains(t, string(data), want)
+			}
+			for _, absent := range tt.wantAbsent {
+				assert.NotContains(t, string(data), absent)
+			}
+
+			if result.NeedsMigration() {
+				backup, err := os.ReadFile(path + ".v1.bak")
+				require.NoError(t, err, "original should be backed up")
+				assert.Equal(t, tt.content, string(backup))
+				assert.Equal(t, path+".v1.bak", result.BackupPath)
+			} else {
+				assert.Empty(t, result.BackupPath)
+				assert.NoFileExists(t, path+".v2.bak")
+			}
+		})
+	}
+}
+
+func TestMigrateConfigVersion_BackupKeepsMode(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(legacyConfigYAML), 0o600))
+
+	result, err := MigrateConfigVersion(path, false)
+	require.NoError(t, err)
+	info, err := os.Stat(result.BackupPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestLegacyCustomAgent(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cmd  string
+		want *legacyAgent
+	}{
+		"plain words": {
+			cmd:  "claude -p {{PROMPT}}",
+			want: &legacyAgent{Command: "claude", Args: []string{"-p", "{{PROMPT}}"}},
+		},
+		"quoted arguments": {
+			cmd:  `claude --append-system-prompt "be brief" 'a b' c\ d "{{PROMPT}}"`,
+			want: &legacyAgent{Command: "claude", Args: []string{"--append-system-prompt", "be brief", "a b", "c d", "{{PROMPT}}"}},
+		},
+		"pipe": {
+			cmd:  "claude -p {{PROMPT}} | tee out.log",
+			want: &legacyAgent{Command: "sh", Args: []string{"-c", `claude -p "$1" | tee out.log`, "sh", "{{PROMPT}}"}},
+		},
+		"environment assignment": {
+			cmd:  `MODEL=opus claude -p "{{PROMPT}}"`,
+			want: &legacyAgent{Command: "sh", Args: []string{"-c", `MODEL=opus claude -p "$1"`, "sh", "{{PROMPT}}"}},
+		},
+		"single-quoted prompt": {
+			cmd:  `wrap "$HOME" '{{PROMPT}}'`,
+			want: &legacyAgent{Command: "sh", Args: []string{"-c", `wrap "$HOME" ''"$1"''`, "sh", "{{PROMPT}}"}},
+		},
+		"unterminated quote": {
+			cmd:  `claude "{{PROMPT}}`,
+			want: &legacyAgent{Command: "sh", Args: []string{"-c", `claude "$1`, "sh", "{{PROMPT}}"}},
+		},
+		"empty": {cmd: "  "},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := legacyCustomAgent(&yaml.Node{Kind: yaml.ScalarNode, Value: tt.cmd}, nil, nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMigrateConfigVersion_DryRun(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(legacyConfigYAML), 0o644))
+
+	result, err := MigrateConfigVersion(path, true)
+	require.NoError(t, err)
+	assert.True(t, result.DryRun)
+	assert.True(t, result.NeedsMigration())
+	assert.Len(t, result.Changes, 2)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, legacyConfigYAML, string(data), "dry run must not modify the file")
+	assert.NoFileExists(t, path+".v1.bak")
+}
+
+func TestMigrateConfigVersion_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yml")
+	result, err := MigrateConfigVersion(path, false)
+	require.NoError(t, err)
+	assert.False(t, result.NeedsMigration())
+	assert.NoFileExists(t, path)
+}
+
+func TestMigrateConfigVersion_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content  string
+		wantErrs []string
+	}{
+		"newer version": {
+			content:  "config_version: 3\n",
+			wantErrs: []string{"config.yml:1:17: config_version: 3 is newer than this autospec supports (2)"},
+		},
+		"non-integer version": {
+			content:  "config_version: two\n",
+			wantErrs: []string{"config.yml:1:17: config_version: must be a positive integer"},
+		},
+		"not a mapping": {
+			content:  "- max_retries\n",
+			wantErrs: []string{"config.yml:1:1: config must be a mapping"},
+		},
+		"type errors reported with locations": {
+			content: "timeout: soon\nnotifications:\n  enabled: maybe\n  long_running_threshold: 90\n",
+			wantErrs: []string{
+				`config.yml:1:10: timeout: invalid integer: "soon"`,
+				`config.yml:3:12: notifications.enabled: invalid boolean: "maybe"`,
+				`config.yml:4:27: notifications.long_running_threshold: invalid duration: "90"`,
+			},
+		},
+		"mapping where a value is expected": {
+			content:  "max_retries:\n  count: 3\n",
+			wantErrs: []string{"config.yml:2:3: max_retries: expected int, got a list or mapping"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "config.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+
+			_, err := MigrateConfigVersion(path, false)
+			require.Error(t, err)
+			for _, want := range tt.wantErrs {
+				assert.Contains(t, err.Error(), want)
+			}
+			assert.True(t, errors.Is(err, apperrors.ErrConfigInvalid))
+
+			data, readErr := os.ReadFile(path)
+			require.NoError(t, readErr)
+			assert.Equal(t, tt.content, string(data), "invalid config must not be rewritten")
+		})
+	}
+}
+
+func TestValidateConfigSchema_LenientTypes(t *testing.T) {
+	t.Parallel()
+
+	// Values the loader accepts must not be rejected by the schema check
+	content := `cclean:
+  verbose: 1
+  style: ""
+notifications:
+  sound_file:
+  email:
+    to: [a@example.com]
+    templates:
+      error: "{{.Message}}"
+custom_agent:
+  command: claude
+  args: ["-p"]
+unknown_key: whatever
+`
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	_, err := MigrateConfigVersion(path, true)
+	assert.NoError(t, err)
+}
+
+func TestLoad_MigratesLegacyConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectPath := filepath.Join(tmpDir, "config.yml")
+	require.NoError(t, os.WriteFile(projectPath, []byte(legacyConfigYAML), 0o644))
+
+	var warnings bytes.Buffer
+	cfg, err := LoadWithOptions(LoadOptions{
+		ProjectConfigPath: projectPath,
+		UserConfigPath:    filepath.Join(tmpDir, "nonexistent.yml"),
+		WarningWriter:     &warnings,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "compact", cfg.Cclean.Style)
+	require.NotNil(t, cfg.CustomAgent)
+	assert.Equal(t, "claude", cfg.CustomAgent.Command)
+	assert.Equal(t, []string{"-p", "--verbose", "{{PROMPT}}"}, cfg.CustomAgent.Args)
+	assert.Equal(t, CurrentConfigVersion, cfg.ConfigVersion)
+
+	assert.Contains(t, warnings.String(), "Migrated project config")
+	assert.Contains(t, warnings.String(), "output_style: compact -> cclean.style")
+	assert.FileExists(t, projectPath+".v1.bak")
+
+	data, err := os.ReadFile(projectPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "config_version: 2")
+}
+
+func TestLoad_UnversionedConfigNotRewritten(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectPath := filepath.Join(tmpDir, "config.yml")
+	content := "# keep my formatting\nmax_retries: 3\n"
+	require.NoError(t, os.WriteFile(projectPath, []byte(content), 0o644))
+
+	var warnings bytes.Buffer
+	cfg, err := LoadWithOptions(LoadOptions{
+		ProjectConfigPath: projectPath,
+		UserConfigPath:    filepath.Join(tmpDir, "nonexistent.yml"),
+		WarningWriter:     &warnings,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, cfg.MaxRetries)
+	assert.Empty(t, warnings.String())
+
+	data, err := os.ReadFile(projectPath)
+	require.NoError(t, err)
+	assert.Equal(t, content, string(data))
+	assert.NoFileExists(t, projectPath+".v1.bak")
+}
+
+func TestLoad_SchemaErrorHasLocation(t *testing.T) {
+	tmpDir := t.TempDir()
+	projectPath := filepath.Join(tmpDir, "config.yml")
+	require.NoError(t, os.WriteFile(projectPath, []byte("max_retries: 2\ntimeout: forever\n"), 0o644))
+
+	_, err := LoadWithOptions(LoadOptions{
+		ProjectConfigPath: projectPath,
+		UserConfigPath:    filepath.Join(tmpDir, "nonexistent.yml"),
+		SkipWarnings:      true,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), projectPath+`:2:10: timeout: invalid integer: "forever"`)
+
+	var valErr *ValidationError
+	require.ErrorAs(t, err, &valErr)
+	assert.Equal(t, 2, valErr.Line)
+	assert.Equal(t, "timeout", valErr.Field)
+}
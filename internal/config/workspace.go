@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// PackageEnvVar selects a workspace package. The --package flag sets it so
+// agent subprocesses (e.g., 'autospec prereqs') inherit the selection.
+const PackageEnvVar = "AUTOSPEC_PACKAGE"
+
+// PackageConfig describes one package of a monorepo workspace.
+// Example:
+//
+//	packages:
+//	  api:
+//	    path: services/api
+//	    task_commands:
+//	      test: "go test ./..."
+type PackageConfig struct {
+	// Path is the package directory, relative to the project root.
+	// autospec runs from this directory when the package is selected, so
+	// specs, the constitution and task commands are scoped to it.
+	Path string `koanf:"path"`
+
+	// SpecsDir overrides specs_dir for this package, relative to Path.
+	// Default: the project's specs_dir, resolved inside the package.
+	SpecsDir string `koanf:"specs_dir"`
+
+	// TaskCommands overrides task_commands entries for this package.
+	// Task types not listed keep the project-level command.
+	TaskCommands map[string]string `koanf:"task_commands"`
+}
+
+// PackageNames returns the configured package names in sorted order.
+func (c *Configuration) PackageNames() []string {
+	names := make([]string, 0, len(c.Packages))
+	for name := range c.Packages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupPackage returns the named package, or an error listing the
+// configured packages if there is no such package.
+func (c *Configuration) LookupPackage(name string) (PackageConfig, error) {
+	pkg, ok := c.Packages[name]
+	if !ok {
+		if len(c.Packages) == 0 {
+			return PackageConfig{}, fmt.Errorf("unknown package %q: no packages configured (add a 'packages' section to .autospec/config.yml)", name)
+		}
+		return PackageConfig{}, fmt.Errorf("unknown package %q (available: %s)", name, strings.Join(c.PackageNames(), ", "))
+	}
+	if pkg.Path == "" {
+		return PackageConfig{}, fmt.Errorf("package %q: path is required", name)
+	}
+	return pkg, nil
+}
+
+// applyPackage applies the package selected via PackageEnvVar on top of the
+// project settings. Unknown names are ignored here: agent subprocesses run
+// inside the package directory, where the workspace config is not visible.
+// The CLI validates the selection before switching directories.
+func (c *Configuration) applyPackage() {
+	name := os.Getenv(PackageEnvVar)
+	pkg, ok := c.Packages[name]
+	if name == "" || !ok {
+		return
+	}
+
+	c.Package = name
+	if pkg.SpecsDir != "" {
+		c.SpecsDir = expandHomePath(pkg.SpecsDir)
+	}
+	if len(pkg.TaskCommands) > 0 {
+		merged := make(map[string]string, len(c.TaskCommands)+len(pkg.TaskCommands))
+		for taskType, command := range c.TaskCommands {
+			merged[taskType] = command
+		}
+		for taskType, command := range pkg.TaskCommands {
+			merged[taskType] = command
+		}
+		c.TaskCommands = merged
+	}
+}
@@ -0,0 +1,105 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const workspaceConfig = `specs_dir: specs
+task_commands:
+  test: "make test"
+  implementation: "make build"
+packages:
+  api:
+    path: services/api
+    task_commands:
+      test: "go test ./..."
+  web:
+    path: apps/web
+    specs_dir: docs/specs
+`
+
+// loadWorkspaceConfig loads workspaceConfig with no user config.
+func loadWorkspaceConfig(t *testing.T) *Configuration {
+	t.Helper()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(workspaceConfig), 0o644))
+
+	cfg, err := LoadWithOptions(LoadOptions{
+		ProjectConfigPath: configPath,
+		UserConfigPath:    filepath.Join(dir, "missing.yml"),
+		SkipWarnings:      true,
+	})
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestLoad_PackageSelection(t *testing.T) {
+	tests := map[string]struct {
+		pkg          string
+		wantPackage  string
+		wantSpecsDir string
+		wantCommands map[string]string
+	}{
+		"no package selected": {
+			wantSpecsDir: "specs",
+			wantCommands: map[string]string{"test": "make test", "implementation": "make build"},
+		},
+		"package overrides task commands": {
+			pkg:          "api",
+			wantPackage:  "api",
+			wantSpecsDir: "specs",
+			wantCommands: map[string]string{"test": "go test ./...", "implementation": "make build"},
+		},
+		"package overrides specs dir": {
+			pkg:          "web",
+			wantPackage:  "web",
+			wantSpecsDir: "docs/specs",
+			wantCommands: map[string]string{"test": "make test", "implementation": "make build"},
+		},
+		"unknown package is ignored by the loader": {
+			pkg:          "missing",
+			wantSpecsDir: "specs",
+			wantCommands: map[string]string{"test": "make test", "implementation": "make build"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(PackageEnvVar, tt.pkg)
+
+			cfg := loadWorkspaceConfig(t)
+			assert.Equal(t, tt.wantPackage, cfg.Package)
+			assert.Equal(t, tt.wantSpecsDir, cfg.SpecsDir)
+			assert.Equal(t, tt.wantCommands, cfg.TaskCommands)
+		})
+	}
+}
+
+func TestLookupPackage(t *testing.T) {
+	t.Setenv(PackageEnvVar, "")
+	cfg := loadWorkspaceConfig(t)
+
+	assert.Equal(t, []string{"api", "web"}, cfg.PackageNames())
+
+	pkg, err := cfg.LookupPackage("api")
+	require.NoError(t, err)
+	assert.Equal(t, "services/api", pkg.Path)
+
+	_, err = cfg.LookupPackage("worker")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "available: api, web")
+
+	_, err = (&Configuration{}).LookupPackage("api")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no packages configured")
+
+	_, err = (&Configuration{Packages: map[string]PackageConfig{"api": {}}}).LookupPackage("api")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "path is required")
+}
@@ -0,0 +1,246 @@
+// Package daemon implements `autospec daemon`: a long-running process exposing
+// a local HTTP API (over a Unix socket or a loopback TCP address) so editor
+// plugins and dashboards can start runs, query their status, and stream logs.
+//
+// Runs are executed one at a time by re-invoking the autospec binary, which
+// serializes access to specs and the state directory across all clients.
+//
+// API (all JSON unless noted):
+//
+//	GET  /v1/health             daemon liveness and version
+//	GET  /v1/runs               list runs (most recent first)
+//	POST /v1/runs               start a run: {"args": ["implement"], "dir": "/repo"}
+//	GET  /v1/runs/{id}          run status
+//	GET  /v1/runs/{id}/logs     run output (text/plain); ?follow=true streams until the run ends
+//	POST /v1/runs/{id}/cancel   cancel a queued or running run
+//	GET  /metrics               Prometheus metrics (text format), when enabled
+//
+// Every endpoint but /v1/health requires "Authorization: Bearer <token>",
+// with the token from <state_dir>/daemon.token. Requests with an Origin
+// header or a non-loopback Host are refused, so web pages cannot reach the
+// API through the browser.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/metrics"
+)
+
+// SocketFileName is the default Unix socket name inside the state directory.
+const SocketFileName = "daemon.sock"
+
+// Server serves the daemon API.
+type Server struct {
+	Manager *Manager
+	Version string
+	Metrics *metrics.Collector // Served at /metrics when set
+	Token   string             // Bearer token clients must send; empty refuses them all
+}
+
+// NewServer creates a server backed by the given run manager that accepts
+// clients presenting token.
+func NewServer(manager *Manager, version, token string) *Server {
+	return &Server{Manager: manager, Version: version, Token: token}
+}
+
+// Handler returns the HTTP handler for the API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/health", s.handleHealth)
+	mux.HandleFunc("GET /v1/runs", s.handleListRuns)
+	mux.HandleFunc("POST /v1/runs", s.handleStartRun)
+	mux.HandleFunc("GET /v1/runs/{id}", s.handleGetRun)
+	mux.HandleFunc("GET /v1/runs/{id}/logs", s.handleLogs)
+	mux.HandleFunc("POST /v1/runs/{id}/cancel", s.handleCancel)
+	if s.Metrics != nil {
+		mux.Handle("GET /metrics", s.Metrics.Handler())
+	}
+	return s.guard(mux)
+}
+
+// Listen opens the daemon listener. A non-empty addr must be a loopback TCP
+// address (e.g., 127.0.0.1:7317); otherwise a Unix socket is created at socketPath.
+func Listen(addr, socketPath string) (net.Listener, error) {
+	if addr != "" {
+		if err := validateLoopback(addr); err != nil {
+			return nil, err
+		}
+		return net.Listen("tcp", addr)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0o755); err != nil {
+		return nil, fmt.Errorf("creating socket directory: %w", err)
+	}
+	if err := removeStaleSocket(socketPath); err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", socketPath, err)
+	}
+	// Restrict the socket to the current user
+	if err := os.Chmod(socketPath, 0o600); err != nil {
+		ln.Close()
+		return nil, fmt.Errorf("securing socket: %w", err)
+	}
+	return ln, nil
+}
+
+// Serve runs the API on ln until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+	s.Manager.Start(ctx)
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// validateLoopback rejects TCP addresses reachable from other machines.
+func validateLoopback(addr string) error {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+	if host == "localhost" {
+		return nil
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("address %q is not a loopback address; the daemon API only listens locally", addr)
+	}
+	return nil
+}
+
+// removeStaleSocket deletes a leftover socket file if no daemon answers on it.
+func removeStaleSocket(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	conn, err := net.DialTimeout("unix", path, 500*time.Millisecond)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("another daemon is already listening on %s", path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing stale socket: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "version": s.Version})
+}
+
+func (s *Server) handleListRuns(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.Manager.List())
+}
+
+func (s *Server) handleStartRun(w http.ResponseWriter, r *http.Request) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("Content-Type must be application/json"))
+		return
+	}
+	var req RunRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("decoding request: %w", err))
+		return
+	}
+	status, err := s.Manager.Submit(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, status)
+}
+
+func (s *Server) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	status, ok := s.Manager.Get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run %q not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (s *Server) handleCancel(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if !s.Manager.Cancel(id) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run %q not found", id))
+		return
+	}
+	status, _ := s.Manager.Get(id)
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleLogs writes the run output; with ?follow=true it streams new output
+// until the run finishes or the client disconnects.
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	run := s.Manager.lookup(r.PathValue("id"))
+	if run == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run %q not found", r.PathValue("id")))
+		return
+	}
+	follow, _ := strconv.ParseBool(r.URL.Query().Get("follow"))
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	flusher, _ := w.(http.Flusher)
+	if follow && flusher != nil {
+		// Send headers right away so clients can start reading before output arrives
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+	}
+	offset := 0
+	for {
+		chunk, next, closed, changed := run.log.readFrom(offset)
+		offset = next
+		if len(chunk) > 0 {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if !follow || closed {
+			return
+		}
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,478 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "test-token"
+
+// bearerTransport adds the test token to every request.
+type bearerTransport struct{ next http.RoundTripper }
+
+func (b bearerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Authorization", "Bearer "+testToken)
+	return b.next.RoundTrip(r)
+}
+
+// newAuthServer serves handler on an httptest server whose Client sends the test token.
+func newAuthServer(t *testing.T, handler http.Handler) *httptest.Server {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+	ts.Client().Transport = bearerTransport{next: ts.Client().Transport}
+	return ts
+}
+
+// newTestServer starts a daemon API backed by runner on an httptest server.
+func newTestServer(t *testing.T, runner Runner) *httptest.Server {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	manager := NewManager(runner)
+	manager.Start(ctx)
+	return newAuthServer(t, NewServer(manager, "test", testToken).Handler())
+}
+
+func startRun(t *testing.T, ts *httptest.Server, req RunRequest) RunStatus {
+	t.Helper()
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	resp, err := ts.Client().Post(ts.URL+"/v1/runs", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	var status RunStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	return status
+}
+
+func getRun(t *testing.T, ts *httptest.Server, id string) RunStatus {
+	t.Helper()
+	resp, err := ts.Client().Get(ts.URL + "/v1/runs/" + id)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var status RunStatus
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	return status
+}
+
+func waitForState(t *testing.T, ts *httptest.Server, id, state string) RunStatus {
+	t.Helper()
+	var status RunStatus
+	require.Eventually(t, func() bool {
+		status = getRun(t, ts, id)
+		return status.State == state
+	}, 5*time.Second, 10*time.Millisecond, "run %s never reached %s", id, state)
+	return status
+}
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t, nil)
+	resp, err := ts.Client().Get(ts.URL + "/v1/health")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, "ok", body["status"])
+	assert.Equal(t, "test", body["version"])
+}
+
+func TestStartRun_CompletesAndServesLogs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	var gotReq RunRequest
+	ts := newTestServer(t, func(_ context.Context, req RunRequest, out io.Writer) (int, error) {
+		gotReq = req
+		fmt.Fprintln(out, "Executing: /autospec.implement")
+		return 0, nil
+	})
+
+	status := startRun(t, ts, RunRequest{Args: []string{"implement", "--tasks"}, Dir: dir})
+	assert.NotEmpty(t, status.ID)
+
+	final := waitForState(t, ts, status.ID, StateCompleted)
+	assert.Equal(t, 0, final.ExitCode)
+	assert.NotNil(t, final.FinishedAt)
+	assert.Equal(t, []string{"implement", "--tasks"}, gotReq.Args)
+	assert.Equal(t, dir, gotReq.Dir)
+	assert.Equal(t, status.ID, gotReq.RunID, "child process inherits the daemon run ID")
+
+	resp, err := ts.Client().Get(ts.URL + "/v1/runs/" + status.ID + "/logs")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	logs, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "Executing: /autospec.implement\n", string(logs))
+}
+
+func TestStartRun_FailedRunReportsExitCode(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t, func(context.Context, RunRequest, io.Writer) (int, error) {
+		return 2, fmt.Errorf("exit status 2")
+	})
+
+	status := startRun(t, ts, RunRequest{Args: []string{"plan"}, Dir: t.TempDir()})
+	final := waitForState(t, ts, status.ID, StateFailed)
+	assert.Equal(t, 2, final.ExitCode)
+	assert.Equal(t, "exit status 2", final.Error)
+}
+
+func TestStartRun_RejectsInvalidRequests(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t, nil)
+	tests := map[string]string{
+		"empty args":     `{"args": []}`,
+		"nested daemon":  `{"args": ["daemon"]}`,
+		"missing dir":    `{"args": ["plan"], "dir": "/does/not/exist"}`,
+		"malformed JSON": `{"args": `,
+	}
+
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := ts.Client().Post(ts.URL+"/v1/runs", "application/json", strings.NewReader(body))
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+}
+
+func TestRuns_ExecuteSerially(t *testing.T) {
+	t.Parallel()
+
+	var active, maxActive int32
+	release := make(chan struct{})
+	ts := newTestServer(t, func(context.Context, RunRequest, io.Writer) (int, error) {
+		n := atomic.AddInt32(&active, 1)
+		for {
+			m := atomic.LoadInt32(&maxActive)
+			if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&active, -1)
+		return 0, nil
+	})
+
+	dir := t.TempDir()
+	first := startRun(t, ts, RunRequest{Args: []string{"implement"}, Dir: dir})
+	second := startRun(t, ts, RunRequest{Args: []string{"implement"}, Dir: dir})
+
+	waitForState(t, ts, first.ID, StateRunning)
+	assert.Equal(t, StateQueued, getRun(t, ts, second.ID).State)
+
+	close(release)
+	waitForState(t, ts, second.ID, StateCompleted)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&maxActive))
+}
+
+func TestCancel_RunningRun(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t, func(ctx context.Context, _ RunRequest, _ io.Writer) (int, error) {
+		<-ctx.Done()
+		return 1, ctx.Err()
+	})
+
+	status := startRun(t, ts, RunRequest{Args: []string{"implement"}, Dir: t.TempDir()})
+	waitForState(t, ts, status.ID, StateRunning)
+
+	resp, err := ts.Client().Post(ts.URL+"/v1/runs/"+status.ID+"/cancel", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	waitForState(t, ts, status.ID, StateCancelled)
+}
+
+func TestLogs_FollowStreamsUntilRunEnds(t *testing.T) {
+	t.Parallel()
+
+	step := make(chan struct{})
+	ts := newTestServer(t, func(_ context.Context, _ RunRequest, out io.Writer) (int, error) {
+		fmt.Fprintln(out, "line 1")
+		<-step
+		fmt.Fprintln(out, "line 2")
+		return 0, nil
+	})
+
+	status := startRun(t, ts, RunRequest{Args: []string{"implement"}, Dir: t.TempDir()})
+	waitForState(t, ts, status.ID, StateRunning)
+
+	resp, err := ts.Client().Get(ts.URL + "/v1/runs/" + status.ID + "/logs?follow=true")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	close(step)
+	logs, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "line 1\nline 2\n", string(logs))
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	server := NewServer(NewManager(nil), "test", testToken)
+	ts := newAuthServer(t, server.Handler())
+	resp, err := ts.Client().Get(ts.URL + "/metrics")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode, "metrics are off without a collector")
+
+	server.Metrics = metrics.NewCollector(t.TempDir(), "test")
+	ts2 := newAuthServer(t, server.Handler())
+	resp, err = ts2.Client().Get(ts2.URL + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `autospec_build_info{version="test"} 1`)
+}
+
+func TestGetRun_NotFound(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t, nil)
+	for _, path := range []string{"/v1/runs/nope", "/v1/runs/nope/logs"} {
+		resp, err := ts.Client().Get(ts.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode, path)
+	}
+}
+
+func TestStartRun_RequiresJSONContentType(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t, nil)
+	body := fmt.Sprintf(`{"args": ["plan"], "dir": %q}`, t.TempDir())
+	resp, err := ts.Client().Post(ts.URL+"/v1/runs", "text/plain", strings.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+	resp, err = ts.Client().Post(ts.URL+"/v1/runs", "application/json; charset=utf-8", strings.NewReader(body))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+}
+
+func TestGuard(t *testing.T) {
+	t.Parallel()
+
+	ts := newTestServer(t, nil)
+	tests := map[string]struct {
+		path     string
+		token    string
+		host     string
+		origin   string
+		wantCode int
+	}{
+		"valid token":         {path: "/v1/runs", token: testToken, wantCode: http.StatusOK},
+		"missing token":       {path: "/v1/runs", wantCode: http.StatusUnauthorized},
+		"wrong token":         {path: "/v1/runs", token: "guess", wantCode: http.StatusUnauthorized},
+		"metrics need token":  {path: "/metrics", wantCode: http.StatusUnauthorized},
+		"health is open":      {path: "/v1/health", wantCode: http.StatusOK},
+		"localhost host":      {path: "/v1/runs", token: testToken, host: "localhost", wantCode: http.StatusOK},
+		"ipv6 loopback host":  {path: "/v1/runs", token: testToken, host: "[::1]:7317", wantCode: http.StatusOK},
+		"foreign host":        {path: "/v1/runs", token: testToken, host: "evil.example:7317", wantCode: http.StatusForbidden},
+		"foreign host health": {path: "/v1/health", host: "evil.example", wantCode: http.StatusForbidden},
+		"any origin":          {path: "/v1/runs", token: testToken, origin: "http://localhost:3000", wantCode: http.StatusForbidden},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			req, err := http.NewRequest(http.MethodGet, ts.URL+tt.path, nil)
+			require.NoError(t, err)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			if tt.host != "" {
+				req.Host = tt.host
+			}
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.wantCode, resp.StatusCode)
+		})
+	}
+}
+
+func TestGuard_NoTokenConfigured(t *testing.T) {
+	t.Parallel()
+
+	ts := newAuthServer(t, NewServer(NewManager(nil), "test", "").Handler())
+	resp, err := ts.Client().Get(ts.URL + "/v1/runs")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestLoadOrCreateToken(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state", TokenFileName)
+	token, err := LoadOrCreateToken(path)
+	require.NoError(t, err)
+	assert.Len(t, token, 64)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	if runtime.GOOS != "windows" {
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	}
+
+	again, err := LoadOrCreateToken(path)
+	require.NoError(t, err)
+	assert.Equal(t, token, again, "the token is kept across restarts")
+}
+
+func TestShutdown_CancelsQueuedRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	started := make(chan struct{})
+	manager := NewManager(func(ctx context.Context, _ RunRequest, _ io.Writer) (int, error) {
+		close(started)
+		<-ctx.Done()
+		return 1, ctx.Err()
+	})
+	manager.Start(ctx)
+
+	dir := t.TempDir()
+	first, err := manager.Submit(RunRequest{Args: []string{"implement"}, Dir: dir})
+	require.NoError(t, err)
+	second, err := manager.Submit(RunRequest{Args: []string{"plan"}, Dir: dir})
+	require.NoError(t, err)
+	<-started
+
+	cancel()
+	for _, id := range []string{first.ID, second.ID} {
+		require.Eventually(t, func() bool {
+			status, _ := manager.Get(id)
+			return status.State == StateCancelled
+		}, 5*time.Second, 10*time.Millisecond, "run %s was not cancelled", id)
+	}
+}
+
+func TestManager_ForgetsOldestFinishedRuns(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager := NewManager(func(context.Context, RunRequest, io.Writer) (int, error) {
+		return 0, nil
+	})
+	manager.maxFinished = 2
+	manager.Start(ctx)
+
+	dir := t.TempDir()
+	var ids []string
+	for i := 0; i < 4; i++ {
+		status, err := manager.Submit(RunRequest{Args: []string{"implement"}, Dir: dir})
+		require.NoError(t, err)
+		ids = append(ids, status.ID)
+		require.Eventually(t, func() bool {
+			status, _ := manager.Get(status.ID)
+			return status.State == StateCompleted
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	assert.Len(t, manager.List(), 2)
+	for i, id := range ids {
+		_, ok := manager.Get(id)
+		assert.Equal(t, i >= 2, ok, "run %d", i)
+	}
+}
+
+func TestLogBuffer_KeepsTail(t *testing.T) {
+	t.Parallel()
+
+	buf := newLogBuffer(8)
+	_, _ = buf.Write([]byte("0123"))
+
+	chunk, next, _, _ := buf.readFrom(0)
+	assert.Equal(t, "0123", string(chunk))
+	assert.Equal(t, 4, next)
+
+	_, _ = buf.Write([]byte("456789abcd"))
+
+	// A follower that fell behind is told that output was dropped
+	chunk, next, _, _ = buf.readFrom(next)
+	assert.Equal(t, truncatedNotice+"6789abcd", string(chunk))
+	assert.Equal(t, 14, next)
+
+	chunk, next, _, _ = buf.readFrom(next)
+	assert.Empty(t, chunk)
+	assert.Equal(t, 14, next)
+
+	chunk, _, _, _ = buf.readFrom(10)
+	assert.Equal(t, "abcd", string(chunk))
+}
+
+func TestListen_RejectsNonLoopback(t *testing.T) {
+	t.Parallel()
+
+	_, err := Listen("0.0.0.0:7317", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a loopback address")
+}
+
+func TestListen_UnixSocket(t *testing.T) {
+	t.Parallel()
+
+	// Keep the path short: Unix socket paths are limited to ~100 bytes
+	dir, err := os.MkdirTemp("", "asd")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socketPath := filepath.Join(dir, SocketFileName)
+
+	ln, err := Listen("", socketPath)
+	require.NoError(t, err)
+
+	// A second daemon on the same socket is refused
+	_, err = Listen("", socketPath)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already listening")
+
+	require.NoError(t, ln.Close())
+
+	// A leftover socket file from an exited daemon is reclaimed
+	ln, err = Listen("", socketPath)
+	require.NoError(t, err)
+	ln.Close()
+}
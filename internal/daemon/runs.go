@@ -0,0 +1,414 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/history"
+)
+
+// Run states reported by the API.
+const (
+	StateQueued    = "queued"
+	StateRunning   = "running"
+	StateCompleted = "completed"
+	StateFailed    = "failed"
+	StateCancelled = "cancelled"
+)
+
+// RunRequest is the body of POST /v1/runs.
+type RunRequest struct {
+	// Args are the autospec arguments, e.g. ["implement", "003-auth", "--tasks"].
+	Args []string `json:"args"`
+	// Dir is the project directory to run in (defaults to the daemon's working directory).
+	Dir string `json:"dir,omitempty"`
+	// RunID is the daemon's run ID, passed to the child process so its logs,
+	// state and notifications carry the same ID. Set by the manager.
+	RunID string `json:"-"`
+}
+
+// RunStatus is the JSON representation of a run.
+type RunStatus struct {
+	ID         string     `json:"id"`
+	Args       []string   `json:"args"`
+	Dir        string     `json:"dir"`
+	State      string     `json:"state"`
+	ExitCode   int        `json:"exit_code"`
+	Error      string     `json:"error,omitempty"`
+	QueuedAt   time.Time  `json:"queued_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+const (
+	// maxFinishedRuns is how many finished runs the manager keeps for status
+	// and log queries; older ones are forgotten.
+	maxFinishedRuns = 100
+	// maxLogBytes caps the output kept per run; the oldest output is dropped first.
+	maxLogBytes = 4 << 20
+	// cancelWaitDelay is how long a cancelled child gets to exit after SIGINT
+	// before it is killed. The child uses part of it to stop its own agent.
+	cancelWaitDelay = 30 * time.Second
+)
+
+// Runner executes a run, writing combined output to out.
+// The default runner re-invokes the autospec binary with the run's arguments.
+type Runner func(ctx context.Context, req RunRequest, out io.Writer) (exitCode int, err error)
+
+// ExecRunner runs the current autospec executable as a child process.
+// Cancelling ctx interrupts the child so it can stop its agent and record the
+// run as cancelled; it is killed if it has not exited after cancelWaitDelay.
+func ExecRunner(ctx context.Context, req RunRequest, out io.Writer) (int, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 1, fmt.Errorf("locating autospec executable: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, exe, req.Args...)
+	cmd.Dir = req.Dir
+	cmd.Env = append(os.Environ(), events.RunIDEnvVar+"="+req.RunID)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	cmd.Cancel = func() error {
+		if err := cmd.Process.Signal(os.Interrupt); err != nil {
+			// Interrupts are not supported on every platform (e.g. Windows)
+			return cmd.Process.Kill()
+		}
+		return nil
+	}
+	cmd.WaitDelay = cancelWaitDelay
+	err = cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), err
+	}
+	if err != nil {
+		return 1, err
+	}
+	return 0, nil
+}
+
+// run is the daemon-side state of a single run.
+type run struct {
+	mu     sync.Mutex
+	status RunStatus
+	log    *logBuffer
+	cancel context.CancelFunc
+}
+
+func (r *run) snapshot() RunStatus {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.status
+	s.Args = append([]string(nil), r.status.Args...)
+	return s
+}
+
+// Manager queues runs and executes them one at a time, so concurrent API
+// clients never race on the same specs or state directory.
+type Manager struct {
+	runner Runner
+	queue  chan *run
+
+	mu          sync.Mutex
+	runs        map[string]*run
+	maxFinished int
+}
+
+// NewManager creates a manager; call Start to begin processing the queue.
+func NewManager(runner Runner) *Manager {
+	if runner == nil {
+		runner = ExecRunner
+	}
+	return &Manager{
+		runner:      runner,
+		queue:       make(chan *run, 64),
+		runs:        make(map[string]*run),
+		maxFinished: maxFinishedRuns,
+	}
+}
+
+// Start processes queued runs sequentially until ctx is cancelled, then
+// cancels the runs still queued.
+func (m *Manager) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				m.cancelQueued()
+				return
+			case r := <-m.queue:
+				if ctx.Err() != nil {
+					// Both cases were ready; shutdown wins over the queue
+					m.cancelQueued()
+					return
+				}
+				m.execute(ctx, r)
+			}
+		}
+	}()
+}
+
+// Submit enqueues a run and returns its initial status.
+func (m *Manager) Submit(req RunRequest) (RunStatus, error) {
+	if len(req.Args) == 0 {
+		return RunStatus{}, fmt.Errorf("args must not be empty")
+	}
+	if req.Args[0] == "daemon" {
+		return RunStatus{}, fmt.Errorf("cannot start a daemon from the daemon")
+	}
+	if req.Dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return RunStatus{}, fmt.Errorf("getting working directory: %w", err)
+		}
+		req.Dir = wd
+	}
+	if info, err := os.Stat(req.Dir); err != nil || !info.IsDir() {
+		return RunStatus{}, fmt.Errorf("dir %q is not a directory", req.Dir)
+	}
+
+	r := &run{
+		status: RunStatus{Args: req.Args, Dir: req.Dir, State: StateQueued, QueuedAt: time.Now()},
+		log:    newLogBuffer(maxLogBytes),
+	}
+
+	m.mu.Lock()
+	id, err := m.newRunID()
+	if err != nil {
+		m.mu.Unlock()
+		return RunStatus{}, err
+	}
+	r.status.ID = id
+	m.runs[id] = r
+	m.mu.Unlock()
+
+	select {
+	case m.queue <- r:
+	default:
+		m.mu.Lock()
+		delete(m.runs, id)
+		m.mu.Unlock()
+		return RunStatus{}, fmt.Errorf("run queue is full")
+	}
+	return r.snapshot(), nil
+}
+
+// execute runs r to completion, recording its final state.
+func (m *Manager) execute(parent context.Context, r *run) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	r.mu.Lock()
+	if r.status.State == StateCancelled {
+		r.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	r.status.State = StateRunning
+	r.status.StartedAt = &now
+	r.cancel = cancel
+	req := RunRequest{Args: r.status.Args, Dir: r.status.Dir, RunID: r.status.ID}
+	r.mu.Unlock()
+
+	code, err := m.runner(ctx, req, r.log)
+
+	r.mu.Lock()
+	finished := time.Now()
+	r.status.FinishedAt = &finished
+	r.status.ExitCode = code
+	switch {
+	case ctx.Err() != nil:
+		r.status.State = StateCancelled
+	case err != nil:
+		r.status.State = StateFailed
+		r.status.Error = err.Error()
+	default:
+		r.status.State = StateCompleted
+	}
+	r.mu.Unlock()
+	r.log.Close()
+	m.pruneFinished()
+}
+
+// Get returns the status of a run.
+func (m *Manager) Get(id string) (RunStatus, bool) {
+	r := m.lookup(id)
+	if r == nil {
+		return RunStatus{}, false
+	}
+	return r.snapshot(), true
+}
+
+// List returns all runs, most recently queued first.
+func (m *Manager) List() []RunStatus {
+	m.mu.Lock()
+	statuses := make([]RunStatus, 0, len(m.runs))
+	for _, r := range m.runs {
+		statuses = append(statuses, r.snapshot())
+	}
+	m.mu.Unlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].QueuedAt.After(statuses[j].QueuedAt)
+	})
+	return statuses
+}
+
+// Cancel stops a queued or running run. Returns false if the run is unknown.
+func (m *Manager) Cancel(id string) bool {
+	r := m.lookup(id)
+	if r == nil {
+		return false
+	}
+	r.mu.Lock()
+	switch r.status.State {
+	case StateQueued:
+		now := time.Now()
+		r.status.State = StateCancelled
+		r.status.FinishedAt = &now
+		r.log.Close()
+	case StateRunning:
+		if r.cancel != nil {
+			r.cancel()
+		}
+	}
+	r.mu.Unlock()
+	m.pruneFinished()
+	return true
+}
+
+// cancelQueued moves every queued run to cancelled, for daemon shutdown.
+func (m *Manager) cancelQueued() {
+	m.mu.Lock()
+	runs := make([]*run, 0, len(m.runs))
+	for _, r := range m.runs {
+		runs = append(runs, r)
+	}
+	m.mu.Unlock()
+
+	for _, r := range runs {
+		r.mu.Lock()
+		if r.status.State == StateQueued {
+			now := time.Now()
+			r.status.State = StateCancelled
+			r.status.FinishedAt = &now
+			r.log.Close()
+		}
+		r.mu.Unlock()
+	}
+	m.pruneFinished()
+}
+
+// pruneFinished forgets the oldest finished runs beyond maxFinished.
+// Queued and running runs are always kept.
+func (m *Manager) pruneFinished() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var finished []RunStatus
+	for _, r := range m.runs {
+		if s := r.snapshot(); s.FinishedAt != nil {
+			finished = append(finished, s)
+		}
+	}
+	if len(finished) <= m.maxFinished {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].FinishedAt.Before(*finished[j].FinishedAt)
+	})
+	for _, s := range finished[:len(finished)-m.maxFinished] {
+		delete(m.runs, s.ID)
+	}
+}
+
+// newRunID returns a run ID not yet used by this manager. Caller must hold m.mu.
+func (m *Manager) newRunID() (string, error) {
+	for {
+		id, err := history.GenerateID()
+		if err != nil {
+			return "", fmt.Errorf("generating run ID: %w", err)
+		}
+		if _, exists := m.runs[id]; !exists {
+			return id, nil
+		}
+	}
+}
+
+func (m *Manager) lookup(id string) *run {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.runs[id]
+}
+
+// truncatedNotice is written before the kept output when a reader starts
+// inside the part of a log that was dropped.
+const truncatedNotice = "[earlier output truncated]\n"
+
+// logBuffer accumulates run output and wakes followers on every write.
+// It keeps at most limit bytes, dropping the oldest output first; offsets
+// passed to readFrom count every byte ever written.
+type logBuffer struct {
+	mu      sync.Mutex
+	data    []byte
+	dropped int
+	limit   int
+	closed  bool
+	changed chan struct{}
+}
+
+func newLogBuffer(limit int) *logBuffer {
+	return &logBuffer{limit: limit, changed: make(chan struct{})}
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.data = append(b.data, p...)
+	if excess := len(b.data) - b.limit; excess > 0 {
+		b.data = append([]byte(nil), b.data[excess:]...)
+		b.dropped += excess
+	}
+	b.broadcast()
+	return len(p), nil
+}
+
+// Close marks the log complete; followers stop once they reach the end.
+func (b *logBuffer) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		b.broadcast()
+	}
+}
+
+// broadcast wakes all waiters. Caller must hold b.mu.
+func (b *logBuffer) broadcast() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// readFrom returns bytes after offset, the offset to read from next, whether
+// the log is closed, and a channel that is closed on the next change. If
+// output after offset was dropped, the chunk starts with truncatedNotice.
+func (b *logBuffer) readFrom(offset int) ([]byte, int, bool, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var chunk []byte
+	if offset < b.dropped {
+		chunk = []byte(truncatedNotice)
+		offset = b.dropped
+	}
+	if end := b.dropped + len(b.data); offset < end {
+		chunk = append(chunk, b.data[offset-b.dropped:]...)
+		offset = end
+	}
+	return chunk, offset, b.closed, b.changed
+}
@@ -0,0 +1,165 @@
+// Package dashboard implements `autospec serve`: a read-only HTML dashboard
+// listing specs, a task board per spec, stage timelines and recent run logs.
+//
+// Pages are rendered server-side with html/template and refresh themselves
+// with a meta tag; there is no JavaScript. Every request re-reads the same
+// files as `autospec status` and `autospec replay` (spec.yaml, tasks.yaml,
+// .stale.yaml, status.json and the run event logs), so the dashboard never
+// holds state of its own and never writes to the project.
+package dashboard
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/metrics"
+	"github.com/ariel-frischer/autospec/internal/spec"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// RefreshSeconds is how often pages reload themselves.
+const RefreshSeconds = 15
+
+// Server serves the dashboard for one project.
+type Server struct {
+	SpecsDir   string
+	StateDir   string
+	StatusPath string // status.json of the project ("" hides the live run panel)
+	Version    string
+	Metrics    *metrics.Collector // Served at /metrics when set
+
+	pages map[string]*template.Template
+}
+
+// New creates a dashboard server.
+func New(specsDir, stateDir, statusPath, version string) *Server {
+	funcs := template.FuncMap{
+		"since":    since,
+		"duration": formatDuration,
+		"lower":    strings.ToLower,
+		"join":     strings.Join,
+	}
+	s := &Server{SpecsDir: specsDir, StateDir: stateDir, StatusPath: statusPath, Version: version, pages: map[string]*template.Template{}}
+	for _, page := range []string{"index", "spec", "run"} {
+		s.pages[page] = template.Must(template.New("layout.html").Funcs(funcs).
+			ParseFS(templateFS, "templates/layout.html", "templates/"+page+".html"))
+	}
+	return s
+}
+
+// Handler returns the HTTP handler for the dashboard.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleIndex)
+	mux.HandleFunc("GET /specs/{name}", s.handleSpec)
+	mux.HandleFunc("GET /runs/{id}", s.handleRun)
+	if s.Metrics != nil {
+		mux.Handle("GET /metrics", s.Metrics.Handler())
+	}
+	return mux
+}
+
+// Serve runs the dashboard on ln until ctx is cancelled.
+func (s *Server) Serve(ctx context.Context, ln net.Listener) error {
+	srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- srv.Serve(ln) }()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	}
+}
+
+// page is the data shared by every template.
+type page struct {
+	Title   string
+	Version string
+	Refresh int
+	Data    any
+}
+
+func (s *Server) render(w http.ResponseWriter, name, title string, data any) {
+	p := page{Title: title, Version: s.Version, Refresh: RefreshSeconds, Data: data}
+	var buf bytes.Buffer
+	if err := s.pages[name].Execute(&buf, p); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	buf.WriteTo(w)
+}
+
+func (s *Server) handleIndex(w http.ResponseWriter, _ *http.Request) {
+	s.render(w, "index", "Specs", s.loadIndex())
+}
+
+func (s *Server) handleSpec(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	// Only names listed in the specs directory are served, which also rules
+	// out path traversal through the URL
+	if !slices.Contains(spec.ListSpecNames(s.SpecsDir), name) {
+		http.NotFound(w, r)
+		return
+	}
+	s.render(w, "spec", name, s.loadSpecPage(name))
+}
+
+func (s *Server) handleRun(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	runs, _ := events.ListRuns(s.StateDir)
+	if !slices.ContainsFunc(runs, func(run events.RunInfo) bool { return run.RunID == id }) {
+		http.NotFound(w, r)
+		return
+	}
+	data, err := s.loadRunPage(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	s.render(w, "run", "Run "+id, data)
+}
+
+// since formats the time elapsed since t, e.g. "5m ago".
+func since(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return formatDuration(time.Since(t)) + " ago"
+}
+
+// formatDuration rounds d for display: 42s, 5m12s, 2h3m.
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	switch {
+	case d <= 0:
+		return "-"
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm%ds", int(d.Minutes()), int(d.Seconds())%60)
+	default:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+}
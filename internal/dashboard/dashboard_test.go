@@ -0,0 +1,174 @@
+package dashboard
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dashboardTasksYAML = `_meta:
+  version: "1.0.0"
+  artifact_type: "tasks"
+phases:
+  - number: 1
+    title: "Setup"
+    tasks:
+      - id: "T001"
+        title: "Create theme tokens"
+        status: "Completed"
+      - id: "T002"
+        title: "Wire <toggle> component"
+        status: "Blocked"
+        blocked_reason: "waiting on design"
+  - number: 2
+    title: "Polish"
+    tasks:
+      - id: "T003"
+        title: "Persist preference"
+        status: "Pending"
+`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	root := t.TempDir()
+	specsDir, stateDir := filepath.Join(root, "specs"), filepath.Join(root, "state")
+	specDir := filepath.Join(specsDir, "003-dark-mode")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte("feature:\n  status: \"Draft\"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "tasks.yaml"), []byte(dashboardTasksYAML), 0o644))
+
+	start := time.Now().Add(-time.Hour)
+	rec := events.NewRecorder(stateDir, "calm_owl_20250101_100000")
+	rec.Record(events.Event{Time: start, Type: events.TypeStageStart, Spec: "003-dark-mode", Stage: "plan", Attempt: 1})
+	rec.Record(events.Event{Time: start.Add(90 * time.Second), Type: events.TypeStageComplete, Spec: "003-dark-mode", Stage: "plan"})
+	rec.Record(events.Event{Time: start.Add(2 * time.Minute), Type: events.TypeStageStart, Spec: "003-dark-mode", Stage: "tasks", Attempt: 1})
+
+	srv := httptest.NewServer(New(specsDir, stateDir, "", "v1.2.3").Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp.StatusCode, string(body)
+}
+
+func TestServer_Pages(t *testing.T) {
+	t.Parallel()
+	srv := newTestServer(t)
+
+	tests := map[string]struct {
+		path     string
+		wantCode int
+		contains []string
+	}{
+		"index lists specs and runs": {
+			path:     "/",
+			wantCode: http.StatusOK,
+			contains: []string{`href="/specs/003-dark-mode"`, "Draft", "1/3", "1 blocked", `href="/runs/calm_owl_20250101_100000"`, "v1.2.3", `http-equiv="refresh"`},
+		},
+		"spec page shows board and timeline": {
+			path:     "/specs/003-dark-mode",
+			wantCode: http.StatusOK,
+			contains: []string{"Blocked (1)", "waiting on design", "Wire &lt;toggle&gt; component", "Persist preference", "1m30s", "incomplete"},
+		},
+		"run page shows events": {
+			path:     "/runs/calm_owl_20250101_100000",
+			wantCode: http.StatusOK,
+			contains: []string{"stage_complete", "stage_start #1"},
+		},
+		"unknown spec": {path: "/specs/999-nope", wantCode: http.StatusNotFound},
+		"unknown run":  {path: "/runs/nope", wantCode: http.StatusNotFound},
+		"traversal":    {path: "/specs/..%2F..%2Fetc", wantCode: http.StatusNotFound},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			code, body := get(t, srv.URL+tt.path)
+			assert.Equal(t, tt.wantCode, code)
+			for _, want := range tt.contains {
+				assert.Contains(t, body, want)
+			}
+		})
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	server := New(filepath.Join(root, "specs"), filepath.Join(root, "state"), "", "dev")
+	server.Metrics = metrics.NewCollector(filepath.Join(root, "state"), "dev")
+	srv := httptest.NewServer(server.Handler())
+	defer srv.Close()
+
+	code, body := get(t, srv.URL+"/metrics")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "autospec_runs_started_total 0")
+}
+
+func TestServer_EmptyProject(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	srv := httptest.NewServer(New(filepath.Join(root, "specs"), filepath.Join(root, "state"), filepath.Join(root, "status.json"), "dev").Handler())
+	defer srv.Close()
+
+	code, body := get(t, srv.URL+"/")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, body, "No specs yet.")
+	assert.Contains(t, body, "No recorded runs.")
+	assert.NotContains(t, body, "Current run")
+}
+
+func TestStageTimeline(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	evs := []events.Event{
+		{Time: start, Type: events.TypeStageStart, Spec: "001-a", Stage: "plan", Attempt: 1},
+		{Time: start.Add(time.Second), Type: events.TypeStageStart, Spec: "002-b", Stage: "plan"},
+		{Time: start.Add(time.Minute), Type: events.TypeRetry, Spec: "001-a", Stage: "plan", Attempt: 2},
+		{Time: start.Add(2 * time.Minute), Type: events.TypeRetriesExhausted, Spec: "001-a", Stage: "plan", Attempt: 3},
+		{Time: start.Add(3 * time.Minute), Type: events.TypeStageStart, Spec: "001-a", Stage: "plan", Attempt: 1},
+		{Time: start.Add(4 * time.Minute), Type: events.TypeBudgetExceeded, Spec: "001-a", Stage: "plan"},
+	}
+
+	got := stageTimeline("run1", "001-a", evs)
+	require.Len(t, got, 2)
+	assert.Equal(t, timelineEntry{RunID: "run1", Stage: "plan", Start: start, Duration: 2 * time.Minute, Attempts: 3, Outcome: "failed"}, got[0])
+	assert.Equal(t, "budget exceeded", got[1].Outcome)
+	assert.Equal(t, time.Minute, got[1].Duration)
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		d    time.Duration
+		want string
+	}{
+		"zero":    {0, "-"},
+		"seconds": {42 * time.Second, "42s"},
+		"minutes": {5*time.Minute + 12*time.Second, "5m12s"},
+		"even":    {time.Minute + 10*time.Second, "1m10s"},
+		"hours":   {2*time.Hour + 3*time.Minute + 30*time.Second, "2h3m"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, formatDuration(tt.d))
+		})
+	}
+}
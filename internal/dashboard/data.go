@@ -0,0 +1,299 @@
+package dashboard
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/statusfile"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// Limits keep pages fast on state directories with long histories.
+const (
+	recentRuns     = 10  // Runs listed on the index page
+	timelineRuns   = 20  // Runs scanned for a spec's stage timeline
+	maxRunEvents   = 500 // Events shown on a run page (the most recent ones)
+	boardTitleSize = 80  // Task titles are truncated on the board
+)
+
+// boardStatuses are the task board columns, in order.
+var boardStatuses = []string{"Pending", "InProgress", "Blocked", "Completed"}
+
+// specRow summarizes one spec.
+type specRow struct {
+	Name      string
+	Status    string // feature.status from spec.yaml
+	Artifacts []string
+	Stale     []string
+	Stats     *validation.TaskStats // nil without tasks.yaml
+	Percent   int
+	Updated   time.Time
+}
+
+// runRow summarizes one recorded run.
+type runRow struct {
+	ID      string
+	Updated time.Time
+	Specs   []string
+	Events  int
+	Last    *events.Event
+}
+
+type indexData struct {
+	Live  *statusfile.Status
+	Specs []specRow
+	Runs  []runRow
+}
+
+type boardColumn struct {
+	Status string
+	Tasks  []boardTask
+}
+
+type boardTask struct {
+	ID      string
+	Title   string
+	Phase   int
+	StoryID string
+	Reason  string // blocked_reason
+}
+
+// timelineEntry is one stage execution of a spec within a run.
+type timelineEntry struct {
+	RunID    string
+	Stage    string
+	Start    time.Time
+	Duration time.Duration
+	Attempts int
+	Outcome  string // completed, failed, cancelled, budget exceeded, incomplete
+}
+
+type specData struct {
+	Spec     specRow
+	Phases   []validation.PhaseStats
+	Columns  []boardColumn
+	Timeline []timelineEntry
+}
+
+type runData struct {
+	ID        string
+	Events    []events.Event
+	Truncated int // Older events left out
+}
+
+func (s *Server) loadIndex() indexData {
+	var data indexData
+	if s.StatusPath != "" {
+		if st, err := statusfile.Load(s.StatusPath); err == nil {
+			data.Live = st
+		}
+	}
+	for _, name := range spec.ListSpecNames(s.SpecsDir) {
+		data.Specs = append(data.Specs, s.loadSpecRow(name))
+	}
+	// Most recently touched specs first
+	sort.SliceStable(data.Specs, func(i, j int) bool { return data.Specs[i].Updated.After(data.Specs[j].Updated) })
+
+	runs, _ := events.ListRuns(s.StateDir)
+	for i, run := range runs {
+		if i == recentRuns {
+			break
+		}
+		row := runRow{ID: run.RunID, Updated: run.ModTime}
+		if evs, err := events.Load(s.StateDir, run.RunID); (err == nil || encrypt.Partial(err)) && len(evs) > 0 {
+			row.Events = len(evs)
+			row.Last = &evs[len(evs)-1]
+			for _, ev := range evs {
+				if ev.Spec != "" && !containsString(row.Specs, ev.Spec) {
+					row.Specs = append(row.Specs, ev.Spec)
+				}
+			}
+		}
+		data.Runs = append(data.Runs, row)
+	}
+	return data
+}
+
+// loadSpecRow reads the spec's status, artifacts and task progress, like
+// `autospec status`.
+func (s *Server) loadSpecRow(name string) specRow {
+	dir := filepath.Join(s.SpecsDir, name)
+	row := specRow{Name: name, Status: readSpecStatus(dir)}
+	for _, artifact := range []string{"spec.yaml", "plan.yaml", "tasks.yaml"} {
+		info, err := os.Stat(filepath.Join(dir, artifact))
+		if err != nil {
+			continue
+		}
+		row.Artifacts = append(row.Artifacts, artifact)
+		if info.ModTime().After(row.Updated) {
+			row.Updated = info.ModTime()
+		}
+	}
+	if stale, err := spec.LoadStaleArtifacts(dir); err == nil {
+		for _, st := range stale {
+			row.Stale = append(row.Stale, st.Artifact)
+		}
+	}
+	if stats, err := validation.GetTaskStats(validation.GetTasksFilePath(dir)); err == nil {
+		row.Stats = stats
+		row.Percent = int(stats.CompletionPercentage())
+	}
+	return row
+}
+
+// readSpecStatus returns feature.status from spec.yaml.
+func readSpecStatus(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "spec.yaml"))
+	if err != nil {
+		return "No spec"
+	}
+	var doc struct {
+		Feature struct {
+			Status string `yaml:"status"`
+		} `yaml:"feature"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return "Parse error"
+	}
+	if doc.Feature.Status == "" {
+		return "Unknown"
+	}
+	return doc.Feature.Status
+}
+
+func (s *Server) loadSpecPage(name string) specData {
+	data := specData{Spec: s.loadSpecRow(name)}
+	if data.Spec.Stats != nil {
+		data.Phases = data.Spec.Stats.PhaseStats
+	}
+	data.Columns = loadBoard(filepath.Join(s.SpecsDir, name))
+	data.Timeline = s.loadTimeline(name)
+	return data
+}
+
+// loadBoard groups the spec's tasks by status.
+func loadBoard(dir string) []boardColumn {
+	tasksPath := validation.GetTasksFilePath(dir)
+	tasks, err := validation.ParseTasksYAML(tasksPath)
+	if err != nil {
+		return nil
+	}
+	columns := make([]boardColumn, len(boardStatuses))
+	index := map[string]int{}
+	for i, status := range boardStatuses {
+		columns[i].Status = status
+		index[strings.ToLower(status)] = i
+	}
+	for _, phase := range tasks.Phases {
+		for _, task := range phase.Tasks {
+			col, ok := index[strings.ToLower(strings.ReplaceAll(task.Status, "_", ""))]
+			if !ok {
+				col = 0 // Unknown statuses show as pending
+			}
+			columns[col].Tasks = append(columns[col].Tasks, boardTask{
+				ID:      task.ID,
+				Title:   truncate(task.Title, boardTitleSize),
+				Phase:   phase.Number,
+				StoryID: task.StoryID,
+				Reason:  task.BlockedReason,
+			})
+		}
+	}
+	return columns
+}
+
+// loadTimeline reconstructs the spec's stage executions from recent run
+// event logs, most recent first.
+func (s *Server) loadTimeline(name string) []timelineEntry {
+	runs, _ := events.ListRuns(s.StateDir)
+	if len(runs) > timelineRuns {
+		runs = runs[:timelineRuns]
+	}
+	var timeline []timelineEntry
+	for _, run := range runs {
+		evs, err := events.Load(s.StateDir, run.RunID)
+		if err != nil && !encrypt.Partial(err) {
+			continue
+		}
+		timeline = append(timeline, stageTimeline(run.RunID, name, evs)...)
+	}
+	sort.SliceStable(timeline, func(i, j int) bool { return timeline[i].Start.After(timeline[j].Start) })
+	return timeline
+}
+
+// stageTimeline folds one run's events for specName into stage executions.
+func stageTimeline(runID, specName string, evs []events.Event) []timelineEntry {
+	var entries []timelineEntry
+	open := map[string]int{} // stage -> index of its unfinished entry
+	for _, ev := range evs {
+		if ev.Spec != specName || ev.Stage == "" {
+			continue
+		}
+		i, ok := open[ev.Stage]
+		if !ok {
+			if ev.Type != events.TypeStageStart {
+				continue
+			}
+			entries = append(entries, timelineEntry{RunID: runID, Stage: ev.Stage, Start: ev.Time, Attempts: 1, Outcome: "incomplete"})
+			i = len(entries) - 1
+			open[ev.Stage] = i
+		}
+		e := &entries[i]
+		if ev.Attempt > e.Attempts {
+			e.Attempts = ev.Attempt
+		}
+		outcome := ""
+		switch ev.Type {
+		case events.TypeStageComplete:
+			outcome = "completed"
+		case events.TypeRetriesExhausted:
+			outcome = "failed"
+		case events.TypeCancelled:
+			outcome = "cancelled"
+		case events.TypeBudgetExceeded:
+			outcome = "budget exceeded"
+		}
+		if outcome != "" {
+			e.Outcome = outcome
+			e.Duration = ev.Time.Sub(e.Start)
+			delete(open, ev.Stage)
+		}
+	}
+	return entries
+}
+
+func (s *Server) loadRunPage(id string) (runData, error) {
+	evs, err := events.Load(s.StateDir, id)
+	if err != nil && !encrypt.Partial(err) {
+		return runData{}, err
+	}
+	data := runData{ID: id, Events: evs}
+	if len(evs) > maxRunEvents {
+		data.Truncated = len(evs) - maxRunEvents
+		data.Events = evs[data.Truncated:]
+	}
+	return data, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
@@ -0,0 +1,74 @@
+// Package editor opens files for the user: in the editor named by $VISUAL or
+// $EDITOR, or with the operating system's default application.
+// Related: internal/cli/open.go, internal/workflow/rescue.go
+// Tags: editor, open, visual
+package editor
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/ariel-frischer/autospec/internal/shell"
+)
+
+// FromEnv returns the editor command line set by $VISUAL, else $EDITOR, or
+// "" when neither is set. It may carry flags, such as "code --wait".
+func FromEnv() string {
+	if editor := os.Getenv("VISUAL"); editor != "" {
+		return editor
+	}
+	return os.Getenv("EDITOR")
+}
+
+// Edit opens path in the $VISUAL or $EDITOR editor, else vi (notepad on
+// Windows), and waits for it to exit.
+func Edit(path string) error {
+	editor := FromEnv()
+	if editor == "" {
+		editor = fallbackEditor(runtime.GOOS)
+	}
+	return run(editor, path)
+}
+
+// Open opens path in the $VISUAL or $EDITOR editor and waits for it to exit.
+// Without either, it hands path to the operating system's default
+// application (xdg-open, open or start) and returns once that has started.
+func Open(path string) error {
+	if editor := FromEnv(); editor != "" {
+		return run(editor, path)
+	}
+	name, args := defaultOpener(runtime.GOOS)
+	return exec.Command(name, append(args, path)...).Start()
+}
+
+// run starts the editor command line on path through the configured shell,
+// attached to the terminal.
+func run(editor, path string) error {
+	cmd := shell.Command(editor + " " + shell.Quote(path))
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func fallbackEditor(goos string) string {
+	if goos == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// defaultOpener returns the program and leading arguments that open a file
+// with the default application on goos.
+func defaultOpener(goos string) (string, []string) {
+	switch goos {
+	case "windows":
+		// start is a cmd builtin; its first quoted argument is the window title.
+		return "cmd", []string{"/c", "start", ""}
+	case "darwin":
+		return "open", nil
+	default:
+		return "xdg-open", nil
+	}
+}
@@ -0,0 +1,62 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Not parallel: the tests set environment variables.
+func TestFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		visual string
+		editor string
+		want   string
+	}{
+		"neither":        {want: ""},
+		"editor":         {editor: "nano", want: "nano"},
+		"visual":         {visual: "code --wait", want: "code --wait"},
+		"visual wins":    {visual: "code --wait", editor: "nano", want: "code --wait"},
+		"empty visual":   {visual: "", editor: "vim", want: "vim"},
+		"editor w flags": {editor: "emacs -nw", want: "emacs -nw"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("VISUAL", tt.visual)
+			t.Setenv("EDITOR", tt.editor)
+			assert.Equal(t, tt.want, FromEnv())
+		})
+	}
+}
+
+func TestDefaultOpener(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		goos     string
+		wantName string
+		wantArgs []string
+	}{
+		"linux":   {goos: "linux", wantName: "xdg-open"},
+		"freebsd": {goos: "freebsd", wantName: "xdg-open"},
+		"darwin":  {goos: "darwin", wantName: "open"},
+		"windows": {goos: "windows", wantName: "cmd", wantArgs: []string{"/c", "start", ""}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			gotName, gotArgs := defaultOpener(tt.goos)
+			assert.Equal(t, tt.wantName, gotName)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestFallbackEditor(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "vi", fallbackEditor("linux"))
+	assert.Equal(t, "notepad", fallbackEditor("windows"))
+}
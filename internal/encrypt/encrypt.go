@@ -0,0 +1,478 @@
+// Package encrypt encrypts run logs, diagnostic logs and transcripts at rest
+// with AES-256-GCM. Encrypted data is stored line by line as
+// "autospec-enc:v2:<base64 header+nonce+ciphertext>", so append-only logs
+// stay appendable and files can mix plain lines (written before encryption
+// was enabled) with encrypted ones. The header names the writer's stream and
+// the line's position in it, and is authenticated with the line (see Stream).
+// Lines in the older "autospec-enc:v1:" format, which has no header, are
+// still read.
+//
+// The key comes from an environment variable, from the output of a key
+// command, or from the OS keychain (see internal/secrets). Readers decrypt
+// transparently with the configured key, whether or not encryption is
+// enabled for writing.
+package encrypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ariel-frischer/autospec/internal/secrets"
+	"github.com/ariel-frischer/autospec/internal/shell"
+)
+
+// DefaultKeyEnv is the environment variable holding the key unless key_env
+// names another one.
+const DefaultKeyEnv = "AUTOSPEC_ENCRYPTION_KEY"
+
+// KeySize is the key length in bytes (AES-256).
+const KeySize = 32
+
+// Encrypted line prefixes. marker is their common part.
+var (
+	marker   = []byte("autospec-enc:")
+	prefixV1 = []byte("autospec-enc:v1:")
+	prefixV2 = []byte("autospec-enc:v2:")
+)
+
+const (
+	// streamIDSize is the length of the random stream ID in a v2 header.
+	streamIDSize = 8
+	// headerSize is the length of a v2 header: stream ID and big-endian line number.
+	headerSize = streamIDSize + 8
+)
+
+// ErrNoKey is returned when encrypted data is read, or encryption is
+// enabled, and no key is configured.
+var ErrNoKey = errors.New("no encryption key")
+
+// Config holds at-rest encryption settings (config: encryption).
+type Config struct {
+	// Enabled encrypts run logs, the log file and transcripts when they are written.
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// KeyEnv names the environment variable holding the key (default: AUTOSPEC_ENCRYPTION_KEY).
+	KeyEnv string `koanf:"key_env" yaml:"key_env" json:"key_env"`
+
+	// KeyCommand prints the key when the variable is unset, e.g. a keychain lookup.
+	// It runs with the configured shell.
+	KeyCommand string `koanf:"key_command" yaml:"key_command" json:"key_command"`
+}
+
+// keyEnv returns the environment variable holding the key.
+func (c Config) keyEnv() string {
+	if c.KeyEnv == "" {
+		return DefaultKeyEnv
+	}
+	return c.KeyEnv
+}
+
+// Key returns the key from the environment, from the key command when the
+// variable is unset, or from the encryption-key keychain secret when no key
+// command is set either. It returns an ErrNoKey error when none has a key.
+func (c Config) Key() ([]byte, error) {
+	if v := os.Getenv(c.keyEnv()); v != "" {
+		key, err := ParseKey(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.keyEnv(), err)
+		}
+		return key, nil
+	}
+	if c.KeyCommand == "" {
+		v, err := secrets.Get(secrets.EncryptionKey)
+		if errors.Is(err, secrets.ErrNotFound) {
+			return nil, fmt.Errorf("%w: set %s or encryption.key_command, or run 'autospec secrets set %s'", ErrNoKey, c.keyEnv(), secrets.EncryptionKey)
+		}
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParseKey(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s secret: %w", secrets.EncryptionKey, err)
+		}
+		return key, nil
+	}
+	out, err := shell.Command(c.KeyCommand).Output()
+	if err != nil {
+		return nil, fmt.Errorf("running encryption.key_command: %w", err)
+	}
+	key, err := ParseKey(strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, fmt.Errorf("encryption.key_command: %w", err)
+	}
+	return key, nil
+}
+
+// ParseKey decodes a base64 or hex encoded 32-byte key.
+func ParseKey(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if key, err := hex.DecodeString(s); err == nil && len(key) == KeySize {
+		return key, nil
+	}
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if key, err := enc.DecodeString(s); err == nil && len(key) == KeySize {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("key must be %d bytes, base64 or hex encoded (generate one with: openssl rand -base64 32)", KeySize)
+}
+
+// Cipher encrypts and decrypts lines. A nil *Cipher is valid: Seal returns
+// data unchanged and Open only passes plain data through.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher returns a cipher for a 32-byte key.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{aead: aead}, nil
+}
+
+// Seal encrypts data into one line, without a trailing newline. The line is
+// a stream of its own; use NewStream for files written line by line.
+func (c *Cipher) Seal(data []byte) []byte {
+	return c.NewStream().Seal(data)
+}
+
+// Stream seals the lines one writer appends to a file, such as one run's
+// event recorder. Every line carries the stream's random ID and its line
+// number, authenticated with the line, so OpenLines notices lines removed
+// from the middle of a stream or moved within it. A nil *Stream returns
+// data unchanged.
+type Stream struct {
+	c   *Cipher
+	id  [streamIDSize]byte
+	mu  sync.Mutex
+	seq uint64
+}
+
+// NewStream starts a stream with a random ID. A nil cipher returns nil.
+func (c *Cipher) NewStream() *Stream {
+	if c == nil {
+		return nil
+	}
+	s := &Stream{c: c}
+	if _, err := rand.Read(s.id[:]); err != nil {
+		panic(fmt.Sprintf("encrypt: reading random stream ID: %v", err))
+	}
+	return s
+}
+
+// Seal encrypts data into the stream's next line, without a trailing newline.
+func (s *Stream) Seal(data []byte) []byte {
+	if s == nil {
+		return data
+	}
+	s.mu.Lock()
+	seq := s.seq
+	s.seq++
+	s.mu.Unlock()
+
+	header := make([]byte, headerSize, headerSize+s.c.aead.NonceSize()+len(data)+s.c.aead.Overhead())
+	copy(header, s.id[:])
+	binary.BigEndian.PutUint64(header[streamIDSize:], seq)
+	nonce := make([]byte, s.c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(fmt.Sprintf("encrypt: reading random nonce: %v", err))
+	}
+	sealed := s.c.aead.Seal(append(header, nonce...), nonce, data, additionalData(header))
+	out := make([]byte, len(prefixV2), len(prefixV2)+base64.StdEncoding.EncodedLen(len(sealed)))
+	copy(out, prefixV2)
+	return base64.StdEncoding.AppendEncode(out, sealed)
+}
+
+// additionalData is the associated data of a v2 line: the prefix, so a line
+// cannot be relabelled as another format, and the header.
+func additionalData(header []byte) []byte {
+	return append(append([]byte(nil), prefixV2...), header...)
+}
+
+// IsSealed reports whether line was encrypted by Seal.
+func IsSealed(line []byte) bool {
+	return bytes.HasPrefix(line, prefixV2) || bytes.HasPrefix(line, prefixV1)
+}
+
+// Open decrypts a line written by Seal. Plain lines are returned unchanged;
+// an encrypted line read with a nil cipher fails with ErrNoKey.
+func (c *Cipher) Open(line []byte) ([]byte, error) {
+	line = bytes.TrimRight(line, "\r\n")
+	if !IsSealed(line) {
+		return line, nil
+	}
+	if c == nil {
+		return nil, ErrNoKey
+	}
+	data, _, err := c.open(line)
+	return data, err
+}
+
+// linePos is the stream and line number a v2 line was sealed with.
+type linePos struct {
+	stream [streamIDSize]byte
+	seq    uint64
+}
+
+// open decrypts a sealed line. For v2 lines it also returns the position
+// from the header.
+func (c *Cipher) open(line []byte) ([]byte, *linePos, error) {
+	malformed := errors.New("malformed encrypted line")
+	v2 := bytes.HasPrefix(line, prefixV2)
+	prefix := prefixV1
+	if v2 {
+		prefix = prefixV2
+	}
+	sealed, err := base64.StdEncoding.DecodeString(string(line[len(prefix):]))
+	if err != nil {
+		return nil, nil, malformed
+	}
+
+	var pos *linePos
+	var ad []byte
+	if v2 {
+		if len(sealed) < headerSize {
+			return nil, nil, malformed
+		}
+		pos = &linePos{seq: binary.BigEndian.Uint64(sealed[streamIDSize:headerSize])}
+		copy(pos.stream[:], sealed[:streamIDSize])
+		ad = additionalData(sealed[:headerSize])
+		sealed = sealed[headerSize:]
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, nil, malformed
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	data, err := c.aead.Open(nil, nonce, ciphertext, ad)
+	if err != nil {
+		return nil, nil, errors.New("decrypting: wrong key or corrupted data")
+	}
+	return data, pos, nil
+}
+
+// LinesError is returned by OpenLines, together with the lines it could
+// read, when some encrypted lines are left out: lines that do not decrypt,
+// such as one cut short by a crash, and lines missing from or out of order
+// in a stream.
+type LinesError struct {
+	Sealed  int   // encrypted lines in the data
+	Failed  int   // encrypted lines that did not decrypt
+	Missing int   // lines missing from, or out of order in, a stream
+	Err     error // the first decryption error, if any line failed
+}
+
+func (e *LinesError) Error() string {
+	var parts []string
+	if e.Failed > 0 {
+		parts = append(parts, fmt.Sprintf("%d of %d encrypted lines could not be decrypted (%v)", e.Failed, e.Sealed, e.Err))
+	}
+	if e.Missing > 0 {
+		parts = append(parts, fmt.Sprintf("%d encrypted lines are missing or out of order", e.Missing))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (e *LinesError) Unwrap() error { return e.Err }
+
+// Partial reports whether err is a *LinesError, meaning the data returned
+// with it is usable but incomplete.
+func Partial(err error) bool {
+	var linesErr *LinesError
+	return errors.As(err, &linesErr)
+}
+
+// OpenLines decrypts every encrypted line of data, keeping plain lines.
+// When no encrypted line decrypts, which means the key is wrong or missing,
+// it returns that error and no data. When only some lines are bad it
+// returns the rest with a *LinesError counting them.
+func (c *Cipher) OpenLines(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, marker) {
+		return data, nil
+	}
+	var out bytes.Buffer
+	report := LinesError{}
+	next := make(map[[streamIDSize]byte]uint64)
+	for len(data) > 0 {
+		line, rest, found := bytes.Cut(data, []byte("\n"))
+		data = rest
+		line = bytes.TrimRight(line, "\r")
+		if !IsSealed(line) {
+			out.Write(line)
+			if found {
+				out.WriteByte('\n')
+			}
+			continue
+		}
+
+		report.Sealed++
+		if c == nil {
+			report.Failed++
+			report.Err = ErrNoKey
+			continue
+		}
+		plain, pos, err := c.open(line)
+		if err != nil {
+			report.Failed++
+			if report.Err == nil {
+				report.Err = err
+			}
+			continue
+		}
+		if pos != nil {
+			if want, seen := next[pos.stream]; seen && pos.seq > want {
+				report.Missing += int(pos.seq - want)
+			} else if seen && pos.seq < want {
+				report.Missing++
+			}
+			next[pos.stream] = max(next[pos.stream], pos.seq+1)
+		}
+		out.Write(plain)
+		if found && (len(plain) == 0 || plain[len(plain)-1] != '\n') {
+			out.WriteByte('\n')
+		}
+	}
+	if report.Sealed > 0 && report.Failed == report.Sealed {
+		return nil, report.Err
+	}
+	if report.Failed > 0 || report.Missing > 0 {
+		return out.Bytes(), &report
+	}
+	return out.Bytes(), nil
+}
+
+// LineWriter returns a writer that encrypts each line written to w as one
+// stream. Writes are buffered up to a newline, so each call should end a
+// line, as slog handlers do. A nil cipher returns w.
+func (c *Cipher) LineWriter(w io.Writer) io.Writer {
+	if c == nil {
+		return w
+	}
+	return &lineWriter{s: c.NewStream(), w: w}
+}
+
+type lineWriter struct {
+	mu  sync.Mutex
+	s   *Stream
+	w   io.Writer
+	buf []byte
+}
+
+// Write encrypts and writes every line that p completes. When writing a line
+// fails, it returns how much of p went into the lines written before it, and
+// drops the rest of p from the buffer.
+func (lw *lineWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	pending := len(lw.buf)
+	lw.buf = append(lw.buf, p...)
+	flushed := 0
+	for {
+		idx := bytes.IndexByte(lw.buf[flushed:], '\n')
+		if idx < 0 {
+			lw.buf = append(lw.buf[:0], lw.buf[flushed:]...)
+			return len(p), nil
+		}
+		line := append(lw.s.Seal(lw.buf[flushed:flushed+idx]), '\n')
+		if _, err := lw.w.Write(line); err != nil {
+			n := max(flushed-pending, 0)
+			lw.buf = append(lw.buf[:0], lw.buf[flushed:pending+n]...)
+			return n, err
+		}
+		flushed += idx + 1
+	}
+}
+
+// The package default is the configuration loaded with the config file. It
+// supplies the key for readers and the cipher for writers.
+var (
+	mu        sync.Mutex
+	current   Config
+	loaded    bool
+	cached    *Cipher
+	cachedErr error
+)
+
+// Set makes cfg the package default. The key is read on first use.
+func Set(cfg Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	current = cfg
+	loaded, cached, cachedErr = false, nil, nil
+}
+
+// defaultCipher returns the cipher of the package default's key, reading the
+// key once.
+func defaultCipher() (*Cipher, error) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !loaded {
+		loaded = true
+		var key []byte
+		if key, cachedErr = current.Key(); cachedErr == nil {
+			cached, cachedErr = NewCipher(key)
+		}
+	}
+	return cached, cachedErr
+}
+
+// Writer returns the cipher that writers encrypt with: nil when encryption
+// is disabled, and an error when it is enabled but the key is missing or
+// invalid, so nothing is written in plain text by mistake.
+func Writer() (*Cipher, error) {
+	mu.Lock()
+	enabled := current.Enabled
+	mu.Unlock()
+	if !enabled {
+		return nil, nil
+	}
+	c, err := defaultCipher()
+	if err != nil {
+		return nil, fmt.Errorf("encryption is enabled but the key is unusable: %w", err)
+	}
+	return c, nil
+}
+
+// Open decrypts line with the package default's key. Plain lines need no key.
+func Open(line []byte) ([]byte, error) {
+	if !IsSealed(line) {
+		return bytes.TrimRight(line, "\r\n"), nil
+	}
+	c, err := defaultCipher()
+	if err != nil {
+		return nil, fmt.Errorf("data is encrypted: %w", err)
+	}
+	return c.Open(line)
+}
+
+// OpenLines decrypts data with the package default's key, as
+// Cipher.OpenLines does. Plain data needs no key.
+func OpenLines(data []byte) ([]byte, error) {
+	if !bytes.Contains(data, marker) {
+		return data, nil
+	}
+	c, err := defaultCipher()
+	if err != nil {
+		return nil, fmt.Errorf("data is encrypted: %w", err)
+	}
+	return c.OpenLines(data)
+}
@@ -0,0 +1,322 @@
+package encrypt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testKey = bytes.Repeat([]byte{7}, KeySize)
+
+func newTestCipher(t *testing.T, key []byte) *Cipher {
+	t.Helper()
+	c, err := NewCipher(key)
+	require.NoError(t, err)
+	return c
+}
+
+func TestParseKey(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input   string
+		wantErr bool
+	}{
+		"base64":            {input: base64.StdEncoding.EncodeToString(testKey)},
+		"base64 url":        {input: base64.RawURLEncoding.EncodeToString(testKey)},
+		"hex":               {input: hex.EncodeToString(testKey)},
+		"surrounding space": {input: "  " + hex.EncodeToString(testKey) + "\n"},
+		"too short":         {input: base64.StdEncoding.EncodeToString(testKey[:16]), wantErr: true},
+		"passphrase":        {input: "correct horse battery staple", wantErr: true},
+		"empty":             {input: "", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			key, err := ParseKey(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testKey, key)
+		})
+	}
+}
+
+func TestCipher_SealOpen(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCipher(t, testKey)
+	plain := []byte(`{"type":"stage_start","stage":"plan"}`)
+
+	sealed := c.Seal(plain)
+	assert.True(t, IsSealed(sealed))
+	assert.NotContains(t, string(sealed), "stage_start")
+	assert.NotContains(t, string(sealed), "\n")
+	assert.NotEqual(t, sealed, c.Seal(plain), "each seal uses a fresh nonce")
+
+	got, err := c.Open(append(sealed, '\n'))
+	require.NoError(t, err)
+	assert.Equal(t, plain, got)
+
+	got, err = c.Open(plain)
+	require.NoError(t, err)
+	assert.Equal(t, plain, got, "plain lines pass through")
+
+	_, err = newTestCipher(t, bytes.Repeat([]byte{8}, KeySize)).Open(sealed)
+	assert.ErrorContains(t, err, "wrong key")
+
+	var none *Cipher
+	assert.Equal(t, plain, none.Seal(plain), "a nil cipher does not encrypt")
+	_, err = none.Open(sealed)
+	assert.ErrorIs(t, err, ErrNoKey)
+}
+
+func TestCipher_OpenLines(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCipher(t, testKey)
+	var data []byte
+	data = append(data, "plain line\n"...)
+	data = append(append(data, c.Seal([]byte("secret line"))...), '\n')
+	data = append(append(data, c.Seal([]byte("# Transcript\n\nbody\n"))...), '\n')
+
+	got, err := c.OpenLines(data)
+	require.NoError(t, err)
+	assert.Equal(t, "plain line\nsecret line\n# Transcript\n\nbody\n", string(got))
+
+	// A line cut short by a crash is dropped and counted; the rest still decrypts
+	sealed := c.Seal([]byte("truncated"))
+	torn := append(append([]byte(nil), data...), sealed[:len(sealed)/2]...)
+	got, err = c.OpenLines(torn)
+	var linesErr *LinesError
+	require.ErrorAs(t, err, &linesErr)
+	assert.True(t, Partial(err))
+	assert.Equal(t, LinesError{Sealed: 3, Failed: 1, Err: linesErr.Err}, *linesErr)
+	assert.ErrorContains(t, err, "1 of 3 encrypted lines could not be decrypted")
+	assert.Equal(t, "plain line\nsecret line\n# Transcript\n\nbody\n", string(got))
+
+	// A mention of the format in a plain line is not an encrypted line
+	got, err = c.OpenLines([]byte("lines start with autospec-enc:v2:\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "lines start with autospec-enc:v2:\n", string(got))
+
+	// No line decrypts with another key: that is a wrong key, not corruption
+	_, err = newTestCipher(t, bytes.Repeat([]byte{9}, KeySize)).OpenLines(data)
+	assert.ErrorContains(t, err, "wrong key")
+
+	plain := []byte("only\nplain\n")
+	got, err = (*Cipher)(nil).OpenLines(plain)
+	require.NoError(t, err)
+	assert.Equal(t, plain, got)
+}
+
+func TestCipher_OpenLinesV1(t *testing.T) {
+	t.Parallel()
+
+	// v1 lines, written before lines carried a stream header, have no
+	// associated data
+	c := newTestCipher(t, testKey)
+	nonce := make([]byte, c.aead.NonceSize())
+	v1 := append([]byte("autospec-enc:v1:"), base64.StdEncoding.EncodeToString(c.aead.Seal(nonce, nonce, []byte("old line"), nil))...)
+
+	got, err := c.OpenLines(append(append(v1, '\n'), append(c.Seal([]byte("new line")), '\n')...))
+	require.NoError(t, err)
+	assert.Equal(t, "old line\nnew line\n", string(got))
+}
+
+func TestCipher_OpenLinesStream(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCipher(t, testKey)
+	s := c.NewStream()
+	lines := make([][]byte, 4)
+	for i := range lines {
+		lines[i] = s.Seal([]byte{'a' + byte(i)})
+	}
+	join := func(order ...int) []byte {
+		var data []byte
+		for _, i := range order {
+			data = append(append(data, lines[i]...), '\n')
+		}
+		return data
+	}
+
+	tests := map[string]struct {
+		data        []byte
+		want        string
+		wantMissing int
+		wantFailed  int
+	}{
+		"in order":     {data: join(0, 1, 2, 3), want: "a\nb\nc\nd\n"},
+		"line removed": {data: join(0, 1, 3), want: "a\nb\nd\n", wantMissing: 1},
+		"reordered":    {data: join(0, 2, 1, 3), want: "a\nc\nb\nd\n", wantMissing: 2},
+		"other stream": {data: append(join(0, 1), append(c.Seal([]byte("x")), '\n')...), want: "a\nb\nx\n"},
+		"moved header": {
+			// Swapping the line number of one line breaks its authentication
+			data: func() []byte {
+				raw, err := base64.StdEncoding.DecodeString(string(lines[1][len(prefixV2):]))
+				require.NoError(t, err)
+				raw[headerSize-1] = 9
+				forged := append(append([]byte(nil), prefixV2...), base64.StdEncoding.EncodeToString(raw)...)
+				return append(append(join(0), forged...), '\n')
+			}(),
+			want:       "a\n",
+			wantFailed: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := c.OpenLines(tt.data)
+			assert.Equal(t, tt.want, string(got))
+			if tt.wantMissing == 0 && tt.wantFailed == 0 {
+				require.NoError(t, err)
+				return
+			}
+			var linesErr *LinesError
+			require.ErrorAs(t, err, &linesErr)
+			assert.Equal(t, tt.wantMissing, linesErr.Missing)
+			assert.Equal(t, tt.wantFailed, linesErr.Failed)
+		})
+	}
+}
+
+func TestCipher_LineWriter(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCipher(t, testKey)
+	var buf bytes.Buffer
+	w := c.LineWriter(&buf)
+
+	_, err := w.Write([]byte("first\nsec"))
+	require.NoError(t, err)
+	_, err = w.Write([]byte("ond\n"))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")))
+	got, err := c.OpenLines(buf.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, "first\nsecond\n", string(got))
+
+	var plain bytes.Buffer
+	assert.Same(t, &plain, (*Cipher)(nil).LineWriter(&plain))
+}
+
+// failingWriter accepts n writes, then fails.
+type failingWriter struct {
+	bytes.Buffer
+	n int
+}
+
+func (f *failingWriter) Write(p []byte) (int, error) {
+	if f.n == 0 {
+		return 0, errors.New("disk full")
+	}
+	f.n--
+	return f.Buffer.Write(p)
+}
+
+func TestCipher_LineWriterPartialWrite(t *testing.T) {
+	t.Parallel()
+
+	c := newTestCipher(t, testKey)
+	out := &failingWriter{n: 1}
+	w := c.LineWriter(out)
+
+	_, err := w.Write([]byte("fir"))
+	require.NoError(t, err)
+	n, err := w.Write([]byte("st\nsecond\nthird\n"))
+	require.Error(t, err)
+	assert.Equal(t, len("st\n"), n, "only the bytes of the written line were consumed")
+
+	out.n = 1
+	_, err = w.Write([]byte("again\n"))
+	require.NoError(t, err)
+	// The line that failed to write is reported as missing
+	got, err := c.OpenLines(out.Bytes())
+	var linesErr *LinesError
+	require.ErrorAs(t, err, &linesErr)
+	assert.Equal(t, 1, linesErr.Missing)
+	assert.Equal(t, "first\nagain\n", string(got))
+}
+
+func TestConfig_Key(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString(testKey)
+
+	t.Run("environment variable", func(t *testing.T) {
+		t.Setenv("TEST_AUTOSPEC_KEY", encoded)
+		key, err := Config{KeyEnv: "TEST_AUTOSPEC_KEY", KeyCommand: "exit 1"}.Key()
+		require.NoError(t, err)
+		assert.Equal(t, testKey, key)
+	})
+
+	t.Run("key command", func(t *testing.T) {
+		t.Setenv("TEST_AUTOSPEC_KEY", "")
+		key, err := Config{KeyEnv: "TEST_AUTOSPEC_KEY", KeyCommand: "echo " + encoded}.Key()
+		require.NoError(t, err)
+		assert.Equal(t, testKey, key)
+	})
+
+	t.Run("failing key command", func(t *testing.T) {
+		t.Setenv("TEST_AUTOSPEC_KEY", "")
+		_, err := Config{KeyEnv: "TEST_AUTOSPEC_KEY", KeyCommand: "exit 1"}.Key()
+		assert.ErrorContains(t, err, "key_command")
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		t.Setenv("TEST_AUTOSPEC_KEY", "short")
+		_, err := Config{KeyEnv: "TEST_AUTOSPEC_KEY"}.Key()
+		assert.ErrorContains(t, err, "TEST_AUTOSPEC_KEY")
+	})
+
+	t.Run("no key", func(t *testing.T) {
+		t.Setenv(DefaultKeyEnv, "")
+		_, err := Config{}.Key()
+		assert.ErrorIs(t, err, ErrNoKey)
+		assert.ErrorContains(t, err, DefaultKeyEnv)
+	})
+}
+
+func TestDefault(t *testing.T) {
+	// Not parallel: changes the package default and the environment
+	t.Cleanup(func() { Set(Config{}) })
+	sealed := newTestCipher(t, testKey).Seal([]byte("secret"))
+
+	t.Setenv(DefaultKeyEnv, "")
+	Set(Config{})
+	c, err := Writer()
+	require.NoError(t, err)
+	assert.Nil(t, c, "disabled encryption writes plain text")
+	_, err = OpenLines(sealed)
+	assert.ErrorIs(t, err, ErrNoKey)
+	got, err := Open([]byte("plain\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "plain", string(got))
+
+	Set(Config{Enabled: true})
+	_, err = Writer()
+	assert.ErrorIs(t, err, ErrNoKey, "enabled without a key")
+
+	t.Setenv(DefaultKeyEnv, hex.EncodeToString(testKey))
+	Set(Config{Enabled: true})
+	c, err = Writer()
+	require.NoError(t, err)
+	assert.NotNil(t, c)
+	got, err = Open(sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", string(got))
+
+	Set(Config{})
+	got, err = OpenLines(append(sealed, '\n'))
+	require.NoError(t, err)
+	assert.Equal(t, "secret\n", string(got), "readers decrypt whether or not encryption is enabled")
+}
@@ -0,0 +1,218 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+)
+
+// Code is a stable, machine-readable error identifier. Codes are part of the
+// --json output contract: add new codes freely, but never rename one.
+type Code string
+
+// Error codes for classified failures.
+const (
+	CodeArtifactMissing Code = "ARTIFACT_MISSING"
+	CodeSchemaViolation Code = "SCHEMA_VIOLATION"
+	CodeAgentTimeout    Code = "AGENT_TIMEOUT"
+	CodeRetryExhausted  Code = "RETRY_EXHAUSTED"
+	CodeBudgetExceeded  Code = "BUDGET_EXCEEDED"
+	CodeConfigInvalid   Code = "CONFIG_INVALID"
+	CodeInterrupted     Code = "INTERRUPTED"
+	CodePreflightFailed Code = "PREFLIGHT_FAILED"
+	CodeStorageLimit    Code = "STORAGE_LIMIT"
+)
+
+// Fallback codes for errors that match no Kind, derived from their category.
+const (
+	CodeInvalidArgument     Code = "INVALID_ARGUMENT"
+	CodePrerequisiteMissing Code = "PREREQUISITE_MISSING"
+	CodeRuntime             Code = "RUNTIME_ERROR"
+)
+
+// Code returns the fallback code for errors in this category.
+func (c ErrorCategory) Code() Code {
+	switch c {
+	case Argument:
+		return CodeInvalidArgument
+	case Configuration:
+		return CodeConfigInvalid
+	case Prerequisite:
+		return CodePrerequisiteMissing
+	default:
+		return CodeRuntime
+	}
+}
+
+// Kind is a class of failure with a code, category and default remediation.
+// Kinds are sentinel errors: tag an error with Errorf (or give a custom error
+// type an Is method) and callers can test it with errors.Is.
+type Kind struct {
+	Code        Code
+	Category    ErrorCategory
+	Remediation []string
+}
+
+// Error returns the kind's code.
+func (k *Kind) Error() string {
+	return string(k.Code)
+}
+
+// Errorf formats an error (supporting %w) tagged with this kind.
+// The message is unchanged; only errors.Is and Classify see the kind.
+func (k *Kind) Errorf(format string, args ...interface{}) error {
+	return &kindError{kind: k, err: fmt.Errorf(format, args...)}
+}
+
+// kindError tags an error with a Kind without changing its message.
+type kindError struct {
+	kind *Kind
+	err  error
+}
+
+func (e *kindError) Error() string        { return e.err.Error() }
+func (e *kindError) Unwrap() error        { return e.err }
+func (e *kindError) Is(target error) bool { return target == e.kind }
+
+// Error kinds for workflow, validation and executor failures.
+var (
+	// ErrArtifactMissing: a spec artifact required by the stage does not exist.
+	ErrArtifactMissing = &Kind{
+		Code:     CodeArtifactMissing,
+		Category: Prerequisite,
+		Remediation: []string{
+			"Run the earlier stage that creates the artifact (specify -> plan -> tasks)",
+			"Check you are on the feature branch, or pass the spec name explicitly",
+			"Run 'autospec status' to see which artifacts exist",
+		},
+	}
+	// ErrSchemaViolation: a generated artifact does not match its schema.
+	ErrSchemaViolation = &Kind{
+		Code:     CodeSchemaViolation,
+		Category: Runtime,
+		Remediation: []string{
+			"Fix the fields listed above, or rerun the stage to regenerate the artifact",
+			"Check an artifact by hand with: autospec artifact <path>",
+		},
+	}
+	// ErrAgentTimeout: the agent did not finish within the configured timeout.
+	ErrAgentTimeout = &Kind{
+		Code:     CodeAgentTimeout,
+		Category: Runtime,
+		Remediation: []string{
+			"Increase the timeout in seconds: autospec config set timeout 3600",
+			"Or set AUTOSPEC_TIMEOUT for a single run (0 disables the timeout)",
+			"Split long implementations into smaller sessions: autospec implement --tasks",
+		},
+	}
+	// ErrRetryExhausted: validation kept failing after all retries.
+	ErrRetryExhausted = &Kind{
+		Code:     CodeRetryExhausted,
+		Category: Runtime,
+		Remediation: []string{
+			"Review the validation errors above and fix the artifact by hand",
+			"Allow more attempts for one run with --max-retries, or: autospec config set max_retries 3",
+		},
+	}
+	// ErrBudgetExceeded: a run or stage budget stopped the run.
+	ErrBudgetExceeded = &Kind{
+		Code:     CodeBudgetExceeded,
+		Category: Runtime,
+		Remediation: []string{
+			"Rerun the command to resume; completed work is kept",
+			"Raise or clear the limit under 'budget' in .autospec/config.yml",
+		},
+	}
+	// ErrInterrupted: SIGINT or SIGTERM cancelled the run.
+	ErrInterrupted = &Kind{
+		Code:     CodeInterrupted,
+		Category: Runtime,
+		Remediation: []string{
+			"Rerun the same command to resume; completed stages and tasks are kept",
+			"Raise cancel_grace_period if agents need longer to finish writing on Ctrl+C",
+		},
+	}
+	// ErrPreflightFailed: a required tool or directory is missing before the run.
+	ErrPreflightFailed = &Kind{
+		Code:     CodePreflightFailed,
+		Category: Prerequisite,
+		Remediation: []string{
+			"Run 'autospec doctor' to see what is missing, then install or create it",
+			"Skip the checks for one run with --skip-preflight, or: autospec config toggle skip_preflight",
+		},
+	}
+	// ErrStorageLimit: the state directory or its logs exceed a storage max limit.
+	ErrStorageLimit = &Kind{
+		Code:     CodeStorageLimit,
+		Category: Prerequisite,
+		Remediation: []string{
+			"Prune more run logs with a lower storage.keep_runs or storage.max_run_age",
+			"Or raise storage.max_mb / storage.logs_max_mb in .autospec/config.yml",
+		},
+	}
+	// ErrConfigInvalid: a configuration value failed validation.
+	ErrConfigInvalid = &Kind{
+		Code:     CodeConfigInvalid,
+		Category: Configuration,
+		Remediation: []string{
+			"Fix the field named above in your config file",
+			"Inspect the effective configuration with: autospec config show",
+		},
+	}
+)
+
+// kinds lists the kinds in the order Classify checks them. A retry failure
+// wraps the validation error that caused it, so outcomes come before causes.
+var kinds = []*Kind{
+	ErrInterrupted,
+	ErrBudgetExceeded,
+	ErrAgentTimeout,
+	ErrRetryExhausted,
+	ErrSchemaViolation,
+	ErrArtifactMissing,
+	ErrPreflightFailed,
+	ErrStorageLimit,
+	ErrConfigInvalid,
+}
+
+// Classify converts any error into a CLIError with a code for display.
+// A CLIError in the chain is used as-is, gaining the code of any kind it wraps;
+// otherwise the first matching kind supplies the code, category and remediation.
+// Unclassified errors become runtime errors with the category's fallback code.
+func Classify(err error) *CLIError {
+	if err == nil {
+		return nil
+	}
+
+	var cliErr *CLIError
+	if stderrors.As(err, &cliErr) {
+		classified := *cliErr
+		if classified.Code == "" {
+			classified.Code = cliErr.Category.Code()
+			if kind := kindOf(err); kind != nil {
+				classified.Code = kind.Code
+			}
+		}
+		return &classified
+	}
+
+	if kind := kindOf(err); kind != nil {
+		return &CLIError{
+			Code:        kind.Code,
+			Category:    kind.Category,
+			Message:     err.Error(),
+			Remediation: kind.Remediation,
+			Err:         err,
+		}
+	}
+	return &CLIError{Code: CodeRuntime, Category: Runtime, Message: err.Error(), Err: err}
+}
+
+// kindOf returns the first kind err matches, or nil.
+func kindOf(err error) *Kind {
+	for _, kind := range kinds {
+		if stderrors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
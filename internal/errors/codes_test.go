@@ -0,0 +1,164 @@
+// Package errors_test tests error kinds, codes, and classification.
+// Related: internal/errors/codes.go
+// Tags: errors, codes, classification, kinds, json
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestKindErrorf(t *testing.T) {
+	t.Parallel()
+
+	cause := stderrors.New("disk full")
+	err := ErrSchemaViolation.Errorf("schema validation failed for plan.yaml: %w", cause)
+
+	if got := err.Error(); got != "schema validation failed for plan.yaml: disk full" {
+		t.Errorf("Error() = %q, message should be unchanged", got)
+	}
+	if !stderrors.Is(err, ErrSchemaViolation) {
+		t.Error("errors.Is(err, ErrSchemaViolation) = false, want true")
+	}
+	if !stderrors.Is(err, cause) {
+		t.Error("errors.Is(err, cause) = false, want true (wrapped cause must be preserved)")
+	}
+	if stderrors.Is(err, ErrArtifactMissing) {
+		t.Error("errors.Is(err, ErrArtifactMissing) = true, want false")
+	}
+}
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	schemaErr := ErrSchemaViolation.Errorf("schema validation failed for tasks.yaml")
+
+	tests := map[string]struct {
+		err             error
+		wantCode        Code
+		wantCategory    ErrorCategory
+		wantMessage     string
+		wantRemediation bool
+	}{
+		"unclassified error": {
+			err:          stderrors.New("boom"),
+			wantCode:     CodeRuntime,
+			wantCategory: Runtime,
+			wantMessage:  "boom",
+		},
+		"kind wrapped by context": {
+			err:             fmt.Errorf("plan stage: %w", ErrArtifactMissing.Errorf("spec file not found in specs/001")),
+			wantCode:        CodeArtifactMissing,
+			wantCategory:    Prerequisite,
+			wantMessage:     "plan stage: spec file not found in specs/001",
+			wantRemediation: true,
+		},
+		"retry outcome wins over schema cause": {
+			err:             ErrRetryExhausted.Errorf("validation failed and retry exhausted: %w", schemaErr),
+			wantCode:        CodeRetryExhausted,
+			wantCategory:    Runtime,
+			wantMessage:     "validation failed and retry exhausted: schema validation failed for tasks.yaml",
+			wantRemediation: true,
+		},
+		"preflight failure": {
+			err:             ErrPreflightFailed.Errorf("%w", stderrors.New("pre-flight checks failed")),
+			wantCode:        CodePreflightFailed,
+			wantCategory:    Prerequisite,
+			wantMessage:     "pre-flight checks failed",
+			wantRemediation: true,
+		},
+		"storage limit": {
+			err:             ErrStorageLimit.Errorf("state directory uses %s", "2100.0 MB"),
+			wantCode:        CodeStorageLimit,
+			wantCategory:    Prerequisite,
+			wantMessage:     "state directory uses 2100.0 MB",
+			wantRemediation: true,
+		},
+		"CLIError keeps its remediation and gets a fallback code": {
+			err:             NewArgumentError("--phase must be a positive integer", "use --phase 2"),
+			wantCode:        CodeInvalidArgument,
+			wantCategory:    Argument,
+			wantMessage:     "--phase must be a positive integer",
+			wantRemediation: true,
+		},
+		"CLIError wrapping a kind takes the kind's code": {
+			err:             ConfigParseError("config.yml", ErrConfigInvalid.Errorf("timeout: must be positive")),
+			wantCode:        CodeConfigInvalid,
+			wantCategory:    Configuration,
+			wantMessage:     "failed to parse config file: config.yml: timeout: must be positive",
+			wantRemediation: true,
+		},
+		"CLIError with explicit code": {
+			err:          TimeoutError("5m", "claude"),
+			wantCode:     CodeAgentTimeout,
+			wantCategory: Runtime,
+			wantMessage:  "command timed out after 5m: claude",
+			// Remediation from the helper, not the kind
+			wantRemediation: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := Classify(tt.err)
+			if got.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", got.Code, tt.wantCode)
+			}
+			if got.Category != tt.wantCategory {
+				t.Errorf("Category = %v, want %v", got.Category, tt.wantCategory)
+			}
+			if got.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMessage)
+			}
+			if (len(got.Remediation) > 0) != tt.wantRemediation {
+				t.Errorf("Remediation = %v, want present=%v", got.Remediation, tt.wantRemediation)
+			}
+		})
+	}
+
+	if Classify(nil) != nil {
+		t.Error("Classify(nil) should return nil")
+	}
+}
+
+func TestClassify_DoesNotModifyCLIError(t *testing.T) {
+	t.Parallel()
+
+	original := NewRuntimeError("failed")
+	_ = Classify(original)
+	if original.Code != "" {
+		t.Errorf("Classify modified the original CLIError code to %q", original.Code)
+	}
+}
+
+func TestCLIErrorJSON(t *testing.T) {
+	t.Parallel()
+
+	got := Classify(ErrAgentTimeout.Errorf("command timed out after 40m0s: claude")).JSON()
+	if got.Code != "AGENT_TIMEOUT" || got.Category != "runtime" {
+		t.Errorf("JSON() = %+v, want code AGENT_TIMEOUT in category runtime", got)
+	}
+	if got.Message != "command timed out after 40m0s: claude" {
+		t.Errorf("JSON().Message = %q", got.Message)
+	}
+	if len(got.Remediation) == 0 || !strings.HasPrefix(got.Remediation[0], "Increase the timeout in seconds") {
+		t.Errorf("JSON().Remediation = %v, want the timeout hint", got.Remediation)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	if strings.Contains(string(data), "usage") {
+		t.Errorf("marshaled %s, empty usage should be omitted", data)
+	}
+
+	fallback := (&CLIError{Category: Prerequisite, Message: "x"}).JSON()
+	if fallback.Code != "PREREQUISITE_MISSING" {
+		t.Errorf("JSON().Code = %q, want fallback code from category", fallback.Code)
+	}
+}
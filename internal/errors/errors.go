@@ -44,6 +44,10 @@ type CLIError struct {
 	Remediation []string
 	// Usage shows the correct command syntax (optional, for argument errors).
 	Usage string
+	// Code is the machine-readable identifier (optional; Classify fills it in).
+	Code Code
+	// Err is the wrapped cause, if any.
+	Err error
 }
 
 // Error implements the error interface.
@@ -51,6 +55,11 @@ func (e *CLIError) Error() string {
 	return e.Message
 }
 
+// Unwrap returns the wrapped cause for errors.Is/As compatibility.
+func (e *CLIError) Unwrap() error {
+	return e.Err
+}
+
 // NewArgumentError creates a new argument error with the given message and remediation steps.
 func NewArgumentError(message string, remediation ...string) *CLIError {
 	return &CLIError{
@@ -106,6 +115,7 @@ func Wrap(err error, category ErrorCategory, remediation ...string) *CLIError {
 		Category:    category,
 		Message:     err.Error(),
 		Remediation: remediation,
+		Err:         err,
 	}
 }
 
@@ -118,6 +128,7 @@ func WrapWithMessage(err error, category ErrorCategory, message string, remediat
 		Category:    category,
 		Message:     fmt.Sprintf("%s: %v", message, err),
 		Remediation: remediation,
+		Err:         err,
 	}
 }
 
@@ -124,3 +124,37 @@ func FormatSimpleError(err error, category ErrorCategory) string {
 func PrintSimpleError(err error, category ErrorCategory) {
 	fmt.Fprint(os.Stderr, FormatSimpleError(err, category))
 }
+
+// JSONError is the --json representation of a CLIError, as listed in the
+// errors of the JSON envelope.
+type JSONError struct {
+	Code        Code     `json:"code"`
+	Category    string   `json:"category"`
+	Message     string   `json:"message"`
+	Remediation []string `json:"remediation,omitempty"`
+	Usage       string   `json:"usage,omitempty"`
+}
+
+// categoryNames are the machine-readable category names used in JSON output.
+var categoryNames = map[ErrorCategory]string{
+	Argument:      "argument",
+	Configuration: "configuration",
+	Prerequisite:  "prerequisite",
+	Runtime:       "runtime",
+}
+
+// JSON returns the --json representation of e. A missing code is filled
+// in from the category.
+func (e *CLIError) JSON() JSONError {
+	code := e.Code
+	if code == "" {
+		code = e.Category.Code()
+	}
+	return JSONError{
+		Code:        code,
+		Category:    categoryNames[e.Category],
+		Message:     e.Message,
+		Remediation: e.Remediation,
+		Usage:       e.Usage,
+	}
+}
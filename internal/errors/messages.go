@@ -17,29 +17,29 @@ func MissingFeatureDescription() *CLIError {
 
 // MissingSpecFile creates an error for missing spec.yaml file.
 func MissingSpecFile(specDir string) *CLIError {
-	return NewPrerequisiteError(
+	return withCode(NewPrerequisiteError(
 		fmt.Sprintf("spec.yaml not found in %s", specDir),
 		"Run 'autospec specify \"<description>\"' first to create spec.yaml",
 		"Or check that you're in the correct feature branch",
-	)
+	), CodeArtifactMissing)
 }
 
 // MissingPlanFile creates an error for missing plan.yaml file.
 func MissingPlanFile(specDir string) *CLIError {
-	return NewPrerequisiteError(
+	return withCode(NewPrerequisiteError(
 		fmt.Sprintf("plan.yaml not found in %s", specDir),
 		"Run 'autospec plan' first to create plan.yaml",
 		"Make sure spec.yaml exists before running plan",
-	)
+	), CodeArtifactMissing)
 }
 
 // MissingTasksFile creates an error for missing tasks.yaml file.
 func MissingTasksFile(specDir string) *CLIError {
-	return NewPrerequisiteError(
+	return withCode(NewPrerequisiteError(
 		fmt.Sprintf("tasks.yaml not found in %s", specDir),
 		"Run 'autospec tasks' first to create tasks.yaml",
 		"Make sure plan.yaml exists before running tasks",
-	)
+	), CodeArtifactMissing)
 }
 
 // SpecNotDetected creates an error when no spec can be auto-detected.
@@ -112,12 +112,12 @@ func InvalidFlagCombination(flags string, reason string) *CLIError {
 
 // TimeoutError creates an error when a command times out.
 func TimeoutError(duration string, command string) *CLIError {
-	return NewRuntimeError(
+	return withCode(NewRuntimeError(
 		fmt.Sprintf("command timed out after %s: %s", duration, command),
 		"Increase timeout in config: AUTOSPEC_TIMEOUT=600",
 		"Or edit .autospec/config.json and set \"timeout\": 600",
 		"Set timeout to 0 to disable timeout",
-	)
+	), CodeAgentTimeout)
 }
 
 // DirectoryNotFound creates an error for missing directory.
@@ -174,3 +174,9 @@ func GitNotRepository() *CLIError {
 		"Or navigate to an existing repository",
 	)
 }
+
+// withCode sets a specific code on err, for helpers that match an error kind.
+func withCode(err *CLIError, code Code) *CLIError {
+	err.Code = code
+	return err
+}
@@ -0,0 +1,264 @@
+// Package events records orchestrator decisions as a JSONL event log per run.
+// Each run gets its own directory under <state_dir>/runs/<run-id>/ containing
+// events.jsonl, one JSON object per line. The log is append-only and is used by
+// `autospec replay` to reconstruct the timeline of failed or confusing runs.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/redact"
+)
+
+const (
+	// RunsDirName is the directory under the state dir holding per-run data.
+	RunsDirName = "runs"
+	// EventsFileName is the name of the JSONL event log inside a run directory.
+	EventsFileName = "events.jsonl"
+	// RunIDEnvVar hands a run ID to a child autospec process, so a run started
+	// by the daemon keeps the daemon's ID in its logs, state and notifications.
+	RunIDEnvVar = "AUTOSPEC_RUN_ID"
+)
+
+// Type identifies the kind of orchestrator decision an event records.
+type Type string
+
+// Event types written by the workflow executor.
+const (
+	TypeStageStart        Type = "stage_start"
+	TypeCommandBuilt      Type = "command_built"
+	TypeExecutionFailed   Type = "execution_failed"
+	TypeValidationPassed  Type = "validation_passed"
+	TypeValidationFailed  Type = "validation_failed"
+	TypeRetry             Type = "retry"
+	TypeRetriesExhausted  Type = "retries_exhausted"
+	TypeStageComplete     Type = "stage_complete"
+	TypeTaskCommand       Type = "task_command"
+	TypeBudgetExceeded    Type = "budget_exceeded"
+	TypeCancelled         Type = "cancelled"
+	TypeTaskTimedOut      Type = "task_timed_out"
+	TypeRateLimited       Type = "rate_limited"
+	TypeFormatted         Type = "formatted"
+	TypeArtifactRecovered Type = "artifact_recovered"
+)
+
+// Event is a single line in events.jsonl.
+type Event struct {
+	Time    time.Time `json:"time"`
+	RunID   string    `json:"run_id"`
+	Type    Type      `json:"type"`
+	Spec    string    `json:"spec,omitempty"`
+	Stage   string    `json:"stage,omitempty"`
+	Task    string    `json:"task,omitempty"`
+	Attempt int       `json:"attempt,omitempty"`
+	Command string    `json:"command,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Errors  []string  `json:"errors,omitempty"`
+}
+
+// Recorder appends events to a run's events.jsonl.
+// A nil *Recorder is valid and discards all events, so callers never need to
+// check whether event logging is enabled. Write errors are non-fatal: the first
+// one is reported to stderr and subsequent events are dropped.
+type Recorder struct {
+	RunID    string
+	StateDir string
+	Redactor *redact.Redactor // Masks secrets in commands, messages and errors (nil: none)
+	Cipher   *encrypt.Cipher  // Encrypts each event line (nil: plain text)
+
+	mu     sync.Mutex
+	failed bool
+	now    func() time.Time
+	stream *encrypt.Stream // Seals event lines, started with the first event
+}
+
+// NewRunID generates a memorable run identifier (adjective_noun_YYYYMMDD_HHMMSS).
+func NewRunID() (string, error) {
+	return history.GenerateID()
+}
+
+// AssignRunID returns the run ID inherited through RunIDEnvVar, or a new one.
+// An inherited ID that is not a plain name (letters, digits, '-' and '_') is
+// ignored, since it becomes a directory under the state dir.
+func AssignRunID() (string, error) {
+	if id := os.Getenv(RunIDEnvVar); ValidRunID(id) {
+		return id, nil
+	}
+	return NewRunID()
+}
+
+// ValidRunID reports whether id is a plain name (letters, digits, '-' and
+// '_'), and therefore safe to use as a directory under the state dir.
+func ValidRunID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// NewRecorder creates a recorder for the given run. The run directory is created
+// lazily on the first recorded event. Returns nil when stateDir is empty.
+func NewRecorder(stateDir, runID string) *Recorder {
+	if stateDir == "" || runID == "" {
+		return nil
+	}
+	return &Recorder{RunID: runID, StateDir: stateDir, now: time.Now}
+}
+
+// RunDir returns the directory holding data for a run.
+func RunDir(stateDir, runID string) string {
+	return filepath.Join(stateDir, RunsDirName, runID)
+}
+
+// Path returns the path to events.jsonl for a run.
+func Path(stateDir, runID string) string {
+	return filepath.Join(RunDir(stateDir, runID), EventsFileName)
+}
+
+// Record appends an event, filling in Time and RunID. Secrets in the command,
+// message and errors are masked before the event is written.
+func (r *Recorder) Record(ev Event) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failed {
+		return
+	}
+
+	ev.RunID = r.RunID
+	ev.Command = r.Redactor.String(ev.Command)
+	ev.Message = r.Redactor.String(ev.Message)
+	ev.Errors = r.Redactor.Strings(ev.Errors)
+	if ev.Time.IsZero() {
+		ev.Time = r.now()
+	}
+	if err := r.append(ev); err != nil {
+		r.failed = true
+		fmt.Fprintf(os.Stderr, "Warning: failed to write event log: %v\n", err)
+	}
+}
+
+// append writes one JSON line to the run's event log.
+func (r *Recorder) append(ev Event) error {
+	if err := os.MkdirAll(RunDir(r.StateDir, r.RunID), 0o755); err != nil {
+		return fmt.Errorf("creating run directory: %w", err)
+	}
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("marshaling event: %w", err)
+	}
+
+	if r.stream == nil {
+		r.stream = r.Cipher.NewStream()
+	}
+	data = r.stream.Seal(data)
+
+	f, err := os.OpenFile(Path(r.StateDir, r.RunID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening event log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing event: %w", err)
+	}
+	return nil
+}
+
+// Load reads all events for a run in file order, decrypting encrypted lines
+// with the configured key. Malformed lines are skipped. When some encrypted
+// lines cannot be read, such as a partial write from a crash, the other
+// events are returned with an error for which encrypt.Partial is true; when
+// no encrypted line decrypts, as with a wrong key, only the error is returned.
+func Load(stateDir, runID string) ([]Event, error) {
+	data, err := os.ReadFile(Path(stateDir, runID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no event log for run %q", runID)
+		}
+		return nil, fmt.Errorf("opening event log: %w", err)
+	}
+	data, decryptErr := encrypt.OpenLines(data)
+	if decryptErr != nil && !encrypt.Partial(decryptErr) {
+		return nil, fmt.Errorf("reading event log: %w", decryptErr)
+	}
+
+	var events []Event
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			continue
+		}
+		events = append(events, ev)
+	}
+	if decryptErr != nil {
+		return events, fmt.Errorf("reading event log: %w", decryptErr)
+	}
+	return events, nil
+}
+
+// RunInfo summarizes a recorded run for listing.
+type RunInfo struct {
+	RunID   string
+	ModTime time.Time
+}
+
+// ListRuns returns runs that have an event log, most recent first.
+func ListRuns(stateDir string) ([]RunInfo, error) {
+	entries, err := os.ReadDir(filepath.Join(stateDir, RunsDirName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading runs directory: %w", err)
+	}
+
+	var runs []RunInfo
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := os.Stat(Path(stateDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		runs = append(runs, RunInfo{RunID: entry.Name(), ModTime: info.ModTime()})
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].ModTime.After(runs[j].ModTime)
+	})
+	return runs, nil
+}
+
+// LatestRunID returns the most recently written run ID.
+func LatestRunID(stateDir string) (string, error) {
+	runs, err := ListRuns(stateDir)
+	if err != nil {
+		return "", err
+	}
+	if len(runs) == 0 {
+		return "", fmt.Errorf("no recorded runs in %s", filepath.Join(stateDir, RunsDirName))
+	}
+	return runs[0].RunID, nil
+}
@@ -0,0 +1,104 @@
+package events
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxReplayCommandLen truncates built commands in the timeline; --raw shows them in full.
+const maxReplayCommandLen = 160
+
+// RenderTimeline pretty-prints events as a chronological timeline.
+// Times are shown as offsets from the first event so retries and slow stages
+// stand out; the absolute start time is printed in the header.
+func RenderTimeline(w io.Writer, events []Event) {
+	if len(events) == 0 {
+		fmt.Fprintln(w, "No events recorded.")
+		return
+	}
+
+	start := events[0].Time
+	fmt.Fprintf(w, "Run %s\n", events[0].RunID)
+	fmt.Fprintf(w, "Started: %s\n", start.Format(time.RFC3339))
+	if spec := firstSpec(events); spec != "" {
+		fmt.Fprintf(w, "Spec:    %s\n", spec)
+	}
+	fmt.Fprintln(w)
+
+	for _, ev := range events {
+		offset := ev.Time.Sub(start).Round(time.Millisecond)
+		fmt.Fprintf(w, "+%-10s %s %s\n", formatOffset(offset), symbolFor(ev.Type), describe(ev))
+		for _, e := range ev.Errors {
+			fmt.Fprintf(w, "%14s- %s\n", "", e)
+		}
+	}
+
+	last := events[len(events)-1]
+	fmt.Fprintf(w, "\n%d events over %s\n", len(events), formatOffset(last.Time.Sub(start)))
+}
+
+// firstSpec returns the first non-empty spec name in the log.
+func firstSpec(events []Event) string {
+	for _, ev := range events {
+		if ev.Spec != "" {
+			return ev.Spec
+		}
+	}
+	return ""
+}
+
+// formatOffset renders a duration compactly (e.g., 0s, 1.5s, 2m3s).
+func formatOffset(d time.Duration) string {
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// symbolFor returns a status marker for an event type.
+func symbolFor(t Type) string {
+	switch t {
+	case TypeValidationPassed, TypeStageComplete:
+		return "✓"
+	case TypeValidationFailed, TypeExecutionFailed, TypeRetriesExhausted, TypeBudgetExceeded:
+		return "✗"
+	case TypeRetry, TypeRateLimited:
+		return "⟳"
+	default:
+		return "→"
+	}
+}
+
+// describe renders the one-line summary for an event.
+func describe(ev Event) string {
+	var sb strings.Builder
+	sb.WriteString(string(ev.Type))
+	if ev.Stage != "" {
+		fmt.Fprintf(&sb, " [%s]", ev.Stage)
+	}
+	if ev.Task != "" {
+		fmt.Fprintf(&sb, " task=%s", ev.Task)
+	}
+	if ev.Attempt > 0 {
+		fmt.Fprintf(&sb, " attempt=%d", ev.Attempt)
+	}
+	if ev.Command != "" {
+		fmt.Fprintf(&sb, " %s", truncate(ev.Command, maxReplayCommandLen))
+	}
+	if ev.Message != "" {
+		fmt.Fprintf(&sb, ": %s", ev.Message)
+	}
+	return sb.String()
+}
+
+// truncate shortens s to max runes, collapsing newlines so each event stays on one line.
+func truncate(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-3]) + "..."
+}
@@ -0,0 +1,240 @@
+// Package explain breaks down why the last workflow failure happened. It reads
+// the failure from a run's event log, re-runs the stage's validator against the
+// artifacts on disk, and renders a decision tree: which validator failed, which
+// field, what the schema expects, what the agent produced, and a snippet of the
+// offending YAML with line numbers.
+package explain
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// snippetContext is the number of lines shown before and after an error line.
+const snippetContext = 2
+
+// Failure is the last failure recorded in a run's event log.
+type Failure struct {
+	RunID   string
+	Time    time.Time
+	Type    events.Type
+	Spec    string
+	Stage   string
+	Attempt int
+	Message string
+	Errors  []string // Validation errors as recorded at the time of the failure
+
+	// Exhausted is true when the stage ran out of retries after this failure.
+	Exhausted bool
+	// Recovered is true when a later attempt of the same stage passed.
+	Recovered bool
+}
+
+// LastFailure returns the most recent failure in evs, or nil when the run has
+// no failures. A retries_exhausted event is folded into the validation failure
+// that caused it, so the recorded errors are kept.
+func LastFailure(evs []events.Event) *Failure {
+	idx := -1
+	for i := len(evs) - 1; i >= 0; i-- {
+		if isFailure(evs[i].Type) {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil
+	}
+
+	ev := evs[idx]
+	f := &Failure{
+		RunID:   ev.RunID,
+		Time:    ev.Time,
+		Type:    ev.Type,
+		Spec:    ev.Spec,
+		Stage:   ev.Stage,
+		Attempt: ev.Attempt,
+		Message: ev.Message,
+		Errors:  ev.Errors,
+	}
+	if ev.Type == events.TypeRetriesExhausted {
+		f.Exhausted = true
+		for i := idx - 1; i >= 0; i-- {
+			prev := evs[i]
+			if prev.Type == events.TypeValidationFailed && prev.Stage == ev.Stage && prev.Spec == ev.Spec {
+				f.Type = prev.Type
+				f.Time = prev.Time
+				f.Attempt = prev.Attempt
+				f.Errors = prev.Errors
+				break
+			}
+		}
+	}
+	for _, later := range evs[idx+1:] {
+		if later.Type == events.TypeValidationPassed && later.Stage == ev.Stage && later.Spec == ev.Spec {
+			f.Recovered = true
+		}
+	}
+	return f
+}
+
+func isFailure(t events.Type) bool {
+	switch t {
+	case events.TypeValidationFailed, events.TypeExecutionFailed,
+		events.TypeRetriesExhausted, events.TypeBudgetExceeded:
+		return true
+	}
+	return false
+}
+
+// Explanation is the breakdown of a failure.
+type Explanation struct {
+	Failure *Failure
+	SpecDir string
+	Checks  []*Check // One per validated artifact file; empty for non-validation failures
+	Note    string   // Why the artifacts could not be re-validated, if they were not
+}
+
+// Check is the result of re-running a validator against one artifact file.
+type Check struct {
+	Validator    string // e.g. "plan schema"
+	ArtifactType validation.ArtifactType
+	Path         string
+	Missing      bool // The artifact file does not exist
+	Findings     []*Finding
+}
+
+// Finding is one validation error with the schema's expectation and the
+// offending lines.
+type Finding struct {
+	Err     *validation.ValidationError
+	Field   *validation.SchemaField // nil when the path is not in the schema
+	Snippet []SnippetLine
+}
+
+// SnippetLine is one numbered source line around a finding.
+type SnippetLine struct {
+	Number    int
+	Text      string
+	Offending bool
+}
+
+// Explain re-validates the artifacts of f's stage in specDir. projectDir
+// locates project-level artifacts (the constitution).
+func Explain(f *Failure, specDir, projectDir string) *Explanation {
+	exp := &Explanation{Failure: f, SpecDir: specDir}
+	switch f.Type {
+	case events.TypeExecutionFailed:
+		exp.Note = "the agent command itself failed, so no artifact was validated"
+		return exp
+	case events.TypeBudgetExceeded:
+		exp.Note = "a budget limit stopped the run before the next agent session"
+		return exp
+	}
+
+	artType, paths, err := stageArtifacts(f.Stage, specDir, projectDir)
+	if err != nil {
+		exp.Note = err.Error()
+		return exp
+	}
+	for _, path := range paths {
+		exp.Checks = append(exp.Checks, check(artType, path))
+	}
+	return exp
+}
+
+// Passing reports whether every re-validated artifact passes now, which means
+// the artifacts changed after the failure.
+func (e *Explanation) Passing() bool {
+	if len(e.Checks) == 0 {
+		return false
+	}
+	for _, c := range e.Checks {
+		if c.Missing || len(c.Findings) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// stageArtifacts maps a stage to the artifact type and files its validator checks.
+func stageArtifacts(stage, specDir, projectDir string) (validation.ArtifactType, []string, error) {
+	switch stage {
+	case "specify":
+		return validation.ArtifactTypeSpec, []string{filepath.Join(specDir, "spec.yaml")}, nil
+	case "plan":
+		return validation.ArtifactTypePlan, []string{filepath.Join(specDir, "plan.yaml")}, nil
+	case "tasks":
+		return validation.ArtifactTypeTasks, []string{filepath.Join(specDir, "tasks.yaml")}, nil
+	case "analyze":
+		return validation.ArtifactTypeAnalysis, []string{filepath.Join(specDir, validation.AnalysisFileName)}, nil
+	case "constitution":
+		return validation.ArtifactTypeConstitution,
+			[]string{filepath.Join(projectDir, ".autospec", "memory", "constitution.yaml")}, nil
+	case "checklist":
+		files, err := validation.ChecklistFiles(specDir)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(files) == 0 {
+			files = []string{filepath.Join(specDir, validation.ChecklistsDirName, "<domain>.yaml")}
+		}
+		return validation.ArtifactTypeChecklist, files, nil
+	case "implement":
+		return "", nil, fmt.Errorf("implement is validated by task completion in tasks.yaml, not by a schema; see the recorded errors")
+	default:
+		return "", nil, fmt.Errorf("stage %q has no artifact validator", stage)
+	}
+}
+
+// check runs the validator for artType against path.
+func check(artType validation.ArtifactType, path string) *Check {
+	c := &Check{Validator: string(artType) + " schema", ArtifactType: artType, Path: path}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		c.Missing = true
+		return c
+	}
+
+	validator, err := validation.NewArtifactValidator(artType)
+	if err != nil {
+		c.Findings = append(c.Findings, &Finding{Err: &validation.ValidationError{Message: err.Error()}})
+		return c
+	}
+	lines := readLines(path)
+	for _, verr := range validator.Validate(path).Errors {
+		c.Findings = append(c.Findings, &Finding{
+			Err:     verr,
+			Field:   validation.FindSchemaField(artType, verr.Path),
+			Snippet: snippet(lines, verr.Line),
+		})
+	}
+	return c
+}
+
+// readLines returns the file's lines, or nil when it cannot be read.
+func readLines(path string) []string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+}
+
+// snippet returns the lines around line (1-based), marking line itself.
+func snippet(lines []string, line int) []SnippetLine {
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+	start := max(1, line-snippetContext)
+	end := min(len(lines), line+snippetContext)
+	out := make([]SnippetLine, 0, end-start+1)
+	for n := start; n <= end; n++ {
+		out = append(out, SnippetLine{Number: n, Text: lines[n-1], Offending: n == line})
+	}
+	return out
+}
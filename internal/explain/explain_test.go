@@ -0,0 +1,225 @@
+// Package explain tests failure lookup, re-validation and decision tree rendering.
+// Related: internal/explain/explain.go, internal/explain/render.go
+// Tags: explain, validation, events, cli
+
+package explain
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// invalidPlan is missing summary and uses the wrong type for technical_context.
+const invalidPlan = `plan:
+  branch: "001-test"
+  created: "2025-01-01"
+technical_context: "Go"
+`
+
+func ev(typ events.Type, stage string, attempt int, errs ...string) events.Event {
+	return events.Event{
+		Time:    time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC),
+		RunID:   "brave_falcon_20250115_143022",
+		Type:    typ,
+		Spec:    "001-test",
+		Stage:   stage,
+		Attempt: attempt,
+		Errors:  errs,
+	}
+}
+
+func TestLastFailure(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		events        []events.Event
+		wantNil       bool
+		wantType      events.Type
+		wantStage     string
+		wantErrors    []string
+		wantExhausted bool
+		wantRecovered bool
+	}{
+		"no failures": {
+			events:  []events.Event{ev(events.TypeStageStart, "plan", 1), ev(events.TypeValidationPassed, "plan", 1)},
+			wantNil: true,
+		},
+		"validation failure": {
+			events: []events.Event{
+				ev(events.TypeStageStart, "plan", 1),
+				ev(events.TypeValidationFailed, "plan", 1, "missing plan.yaml"),
+			},
+			wantType:   events.TypeValidationFailed,
+			wantStage:  "plan",
+			wantErrors: []string{"missing plan.yaml"},
+		},
+		"exhausted keeps recorded errors": {
+			events: []events.Event{
+				ev(events.TypeValidationFailed, "tasks", 2, "phases: missing required field"),
+				ev(events.TypeRetriesExhausted, "tasks", 2),
+			},
+			wantType:      events.TypeValidationFailed,
+			wantStage:     "tasks",
+			wantErrors:    []string{"phases: missing required field"},
+			wantExhausted: true,
+		},
+		"later attempt passed": {
+			events: []events.Event{
+				ev(events.TypeValidationFailed, "plan", 1, "bad"),
+				ev(events.TypeRetry, "plan", 2),
+				ev(events.TypeValidationPassed, "plan", 2),
+			},
+			wantType:      events.TypeValidationFailed,
+			wantStage:     "plan",
+			wantErrors:    []string{"bad"},
+			wantRecovered: true,
+		},
+		"execution failure": {
+			events:    []events.Event{ev(events.TypeExecutionFailed, "specify", 1)},
+			wantType:  events.TypeExecutionFailed,
+			wantStage: "specify",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			f := LastFailure(tt.events)
+			if tt.wantNil {
+				assert.Nil(t, f)
+				return
+			}
+			require.NotNil(t, f)
+			assert.Equal(t, tt.wantType, f.Type)
+			assert.Equal(t, tt.wantStage, f.Stage)
+			assert.Equal(t, tt.wantErrors, f.Errors)
+			assert.Equal(t, tt.wantExhausted, f.Exhausted)
+			assert.Equal(t, tt.wantRecovered, f.Recovered)
+		})
+	}
+}
+
+func TestExplain_InvalidArtifact(t *testing.T) {
+	t.Parallel()
+
+	specDir := t.TempDir()
+	planPath := filepath.Join(specDir, "plan.yaml")
+	require.NoError(t, os.WriteFile(planPath, []byte(invalidPlan), 0o644))
+
+	f := LastFailure([]events.Event{ev(events.TypeValidationFailed, "plan", 1, "recorded")})
+	exp := Explain(f, specDir, t.TempDir())
+
+	require.Len(t, exp.Checks, 1)
+	c := exp.Checks[0]
+	assert.Equal(t, planPath, c.Path)
+	assert.False(t, c.Missing)
+	require.NotEmpty(t, c.Findings)
+	assert.False(t, exp.Passing())
+
+	var out bytes.Buffer
+	Render(&out, exp)
+	got := out.String()
+
+	assert.Contains(t, got, "Failure: validation failed in stage plan (spec 001-test, attempt 1)")
+	assert.Contains(t, got, "plan stage\n└─ ✗ plan schema validator: "+planPath)
+	assert.Contains(t, got, "technical_context: wrong type for field 'technical_context'")
+	assert.Contains(t, got, "Schema expects: object, required")
+	assert.Contains(t, got, "Expected: object")
+	assert.Contains(t, got, "Agent produced: scalar")
+	assert.Contains(t, got, "> 4 | technical_context: \"Go\"")
+	assert.Contains(t, got, "  2 |   branch: \"001-test\"")
+	assert.Contains(t, got, "summary")
+}
+
+func TestExplain_MissingArtifact(t *testing.T) {
+	t.Parallel()
+
+	f := LastFailure([]events.Event{ev(events.TypeValidationFailed, "tasks", 1)})
+	exp := Explain(f, t.TempDir(), t.TempDir())
+
+	require.Len(t, exp.Checks, 1)
+	assert.True(t, exp.Checks[0].Missing)
+
+	var out bytes.Buffer
+	Render(&out, exp)
+	assert.Contains(t, out.String(), "File not found: the agent did not write this artifact")
+}
+
+func TestExplain_FixedSinceFailure(t *testing.T) {
+	t.Parallel()
+
+	specDir := t.TempDir()
+	valid, err := os.ReadFile(filepath.Join("..", "validation", "testdata", "plan", "valid.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "plan.yaml"), valid, 0o644))
+
+	failure := LastFailure([]events.Event{
+		ev(events.TypeValidationFailed, "plan", 1, "summary: missing required field"),
+		ev(events.TypeRetriesExhausted, "plan", 1),
+	})
+	exp := Explain(failure, specDir, t.TempDir())
+	require.True(t, exp.Passing())
+
+	var out bytes.Buffer
+	Render(&out, exp)
+	got := out.String()
+	assert.Contains(t, got, "Status:  retries exhausted")
+	assert.Contains(t, got, "passes now")
+	assert.Contains(t, got, "summary: missing required field")
+}
+
+func TestExplain_NonValidationFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		event    events.Event
+		wantNote string
+	}{
+		"execution failure": {
+			event:    events.Event{Type: events.TypeExecutionFailed, Stage: "plan", Message: "agent claude exited with code 1"},
+			wantNote: "the agent command itself failed",
+		},
+		"budget exceeded": {
+			event:    events.Event{Type: events.TypeBudgetExceeded, Stage: "tasks", Message: "budget exceeded: stage 'tasks' tokens"},
+			wantNote: "a budget limit stopped the run",
+		},
+		"implement": {
+			event:    events.Event{Type: events.TypeValidationFailed, Stage: "implement", Message: "", Errors: []string{"phase 2 has incomplete tasks"}},
+			wantNote: "validated by task completion",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			exp := Explain(LastFailure([]events.Event{tt.event}), t.TempDir(), t.TempDir())
+			assert.Empty(t, exp.Checks)
+
+			var out bytes.Buffer
+			Render(&out, exp)
+			assert.Contains(t, out.String(), tt.wantNote)
+			assert.Contains(t, out.String(), "Recorded in the event log")
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	t.Parallel()
+
+	lines := []string{"a", "b", "c", "d", "e", "f"}
+	got := snippet(lines, 2)
+	require.Len(t, got, 4)
+	assert.Equal(t, SnippetLine{Number: 1, Text: "a"}, got[0])
+	assert.Equal(t, SnippetLine{Number: 2, Text: "b", Offending: true}, got[1])
+	assert.Equal(t, 4, got[3].Number)
+
+	assert.Nil(t, snippet(lines, 0))
+	assert.Nil(t, snippet(lines, 7))
+}
@@ -0,0 +1,205 @@
+package explain
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// node is one entry in the rendered decision tree.
+type node struct {
+	label    string
+	children []*node
+}
+
+func (n *node) add(label string) *node {
+	child := &node{label: label}
+	n.children = append(n.children, child)
+	return child
+}
+
+// Render prints the explanation as a decision tree.
+func Render(w io.Writer, e *Explanation) {
+	f := e.Failure
+	fmt.Fprintf(w, "Run:     %s\n", f.RunID)
+	fmt.Fprintf(w, "Failure: %s\n", describeFailure(f))
+	if status := failureStatus(f); status != "" {
+		fmt.Fprintf(w, "Status:  %s\n", status)
+	}
+	fmt.Fprintln(w)
+
+	root := &node{label: fmt.Sprintf("%s stage", f.Stage)}
+	if f.Stage == "" {
+		root.label = "run"
+	}
+	switch {
+	case e.Note != "":
+		root.add("✗ " + failureKind(f)).add(e.Note)
+		addRecorded(root, f)
+	case e.Passing():
+		for _, c := range e.Checks {
+			root.add(fmt.Sprintf("✓ %s validator: %s passes now", c.Validator, c.Path))
+		}
+		root.add("The artifacts changed after the failure; errors recorded at the time:")
+		addRecorded(root, f)
+	default:
+		for _, c := range e.Checks {
+			addCheck(root, c)
+		}
+	}
+	renderNode(w, root, "", "")
+}
+
+// describeFailure is the one-line summary of the failure.
+func describeFailure(f *Failure) string {
+	var sb strings.Builder
+	sb.WriteString(failureKind(f))
+	if f.Stage != "" {
+		fmt.Fprintf(&sb, " in stage %s", f.Stage)
+	}
+	var details []string
+	if f.Spec != "" {
+		details = append(details, "spec "+f.Spec)
+	}
+	if f.Attempt > 0 {
+		details = append(details, fmt.Sprintf("attempt %d", f.Attempt))
+	}
+	if len(details) > 0 {
+		fmt.Fprintf(&sb, " (%s)", strings.Join(details, ", "))
+	}
+	if !f.Time.IsZero() {
+		fmt.Fprintf(&sb, " at %s", f.Time.Format(time.RFC3339))
+	}
+	return sb.String()
+}
+
+func failureKind(f *Failure) string {
+	switch f.Type {
+	case events.TypeValidationFailed:
+		return "validation failed"
+	case events.TypeExecutionFailed:
+		return "agent execution failed"
+	case events.TypeBudgetExceeded:
+		return "budget exceeded"
+	default:
+		return strings.ReplaceAll(string(f.Type), "_", " ")
+	}
+}
+
+func failureStatus(f *Failure) string {
+	switch {
+	case f.Recovered:
+		return "a later attempt of this stage passed"
+	case f.Exhausted:
+		return "retries exhausted; fix the artifact or rerun the stage"
+	}
+	return ""
+}
+
+// addRecorded adds the message and errors recorded in the event log.
+func addRecorded(parent *node, f *Failure) {
+	if f.Message == "" && len(f.Errors) == 0 {
+		return
+	}
+	recorded := parent.add("Recorded in the event log")
+	if f.Message != "" {
+		recorded.add(f.Message)
+	}
+	for _, e := range f.Errors {
+		recorded.add(e)
+	}
+}
+
+// addCheck adds one validator run and its findings.
+func addCheck(parent *node, c *Check) {
+	if c.Missing {
+		parent.add(fmt.Sprintf("✗ %s validator: %s", c.Validator, c.Path)).
+			add("File not found: the agent did not write this artifact")
+		return
+	}
+	label := fmt.Sprintf("✗ %s validator: %s (%d error(s))", c.Validator, c.Path, len(c.Findings))
+	checkNode := parent.add(label)
+	for _, finding := range c.Findings {
+		addFinding(checkNode, c, finding)
+	}
+}
+
+// addFinding adds one validation error with the schema expectation, what the
+// agent produced, and the offending lines.
+func addFinding(parent *node, c *Check, finding *Finding) {
+	verr := finding.Err
+	label := "✗ "
+	if verr.Line > 0 {
+		label += fmt.Sprintf("line %d", verr.Line)
+		if verr.Column > 0 {
+			label += fmt.Sprintf(":%d", verr.Column)
+		}
+		label += " "
+	}
+	if verr.Path != "" {
+		label += verr.Path + ": "
+	}
+	errNode := parent.add(label + verr.Message)
+
+	if finding.Field != nil {
+		errNode.add("Schema expects: " + describeField(finding.Field))
+	}
+	if verr.Expected != "" {
+		errNode.add("Expected: " + verr.Expected)
+	}
+	if verr.Actual != "" {
+		errNode.add("Agent produced: " + verr.Actual)
+	}
+	if verr.Hint != "" {
+		errNode.add("Hint: " + verr.Hint)
+	}
+	if len(finding.Snippet) > 0 {
+		src := errNode.add(c.Path)
+		width := len(fmt.Sprint(finding.Snippet[len(finding.Snippet)-1].Number))
+		for _, line := range finding.Snippet {
+			marker := " "
+			if line.Offending {
+				marker = ">"
+			}
+			src.add(fmt.Sprintf("%s %*d | %s", marker, width, line.Number, line.Text))
+		}
+	}
+}
+
+// describeField summarizes a schema field: type, whether it is required,
+// allowed values or pattern, and its description.
+func describeField(f *validation.SchemaField) string {
+	parts := []string{string(f.Type)}
+	if f.Required {
+		parts = append(parts, "required")
+	} else {
+		parts = append(parts, "optional")
+	}
+	if len(f.Enum) > 0 {
+		parts = append(parts, "one of "+strings.Join(f.Enum, ", "))
+	}
+	if f.Pattern != "" {
+		parts = append(parts, "matching "+f.Pattern)
+	}
+	s := strings.Join(parts, ", ")
+	if f.Description != "" {
+		s += " (" + f.Description + ")"
+	}
+	return s
+}
+
+// renderNode prints n and its children with box-drawing branches.
+func renderNode(w io.Writer, n *node, prefix, childPrefix string) {
+	fmt.Fprintf(w, "%s%s\n", prefix, n.label)
+	for i, child := range n.children {
+		if i == len(n.children)-1 {
+			renderNode(w, child, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			renderNode(w, child, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
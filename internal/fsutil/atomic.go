@@ -0,0 +1,133 @@
+// Package fsutil writes files atomically: data goes to a temp file in the
+// target's directory, is fsynced, and is renamed over the target, so readers
+// (and a crash or power loss) never observe a partially written file.
+//
+// Artifact writes can also keep rotating backups of the previous contents
+// (<name>.bak, <name>.bak.2, ...). The number kept is process-wide and set
+// from the artifact_backups config key when the configuration is loaded.
+package fsutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultBackups is the number of backups kept until SetBackups is called.
+const DefaultBackups = 1
+
+var backups atomic.Int32
+
+func init() {
+	backups.Store(DefaultBackups)
+}
+
+// SetBackups sets how many backups WriteArtifact keeps per file (0 disables them).
+func SetBackups(n int) {
+	if n < 0 {
+		n = 0
+	}
+	backups.Store(int32(n))
+}
+
+// Backups returns how many backups WriteArtifact keeps per file.
+func Backups() int {
+	return int(backups.Load())
+}
+
+// WriteFile atomically replaces path with data. The file gets mode perm.
+func WriteFile(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmpFile, err := os.CreateTemp(dir, "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath) // no-op after a successful rename
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("syncing temp file: %w", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return fmt.Errorf("setting file mode: %w", err)
+	}
+	if err := replaceFile(tmpPath, path); err != nil {
+		return fmt.Errorf("renaming temp file to %s: %w", path, err)
+	}
+	return syncDir(dir)
+}
+
+// WriteArtifact is WriteFile for spec artifacts: when path already exists, its
+// current contents are kept as a backup first (see SetBackups).
+func WriteArtifact(path string, data []byte, perm os.FileMode) error {
+	if err := backup(path, Backups()); err != nil {
+		return err
+	}
+	return WriteFile(path, data, perm)
+}
+
+// BackupPath returns the path of the n-th most recent backup of path
+// (n starts at 1): <path>.bak, then <path>.bak.2, <path>.bak.3, ...
+func BackupPath(path string, n int) string {
+	if n <= 1 {
+		return path + ".bak"
+	}
+	return fmt.Sprintf("%s.bak.%d", path, n)
+}
+
+// IsBackup reports whether path names a backup written by WriteArtifact
+// (<name>.bak or <name>.bak.<n>).
+func IsBackup(path string) bool {
+	if strings.HasSuffix(path, ".bak") {
+		return true
+	}
+	i := strings.LastIndex(path, ".bak.")
+	if i < 0 {
+		return false
+	}
+	_, err := strconv.Atoi(path[i+len(".bak."):])
+	return err == nil
+}
+
+// backup rotates existing backups of path and copies path to BackupPath(path, 1),
+// keeping at most keep backups. A missing path is not an error.
+func backup(path string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("checking %s for backup: %w", path, err)
+	}
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s for backup: %w", path, err)
+	}
+
+	if err := os.Remove(BackupPath(path, keep)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing oldest backup: %w", err)
+	}
+	for n := keep - 1; n >= 1; n-- {
+		if err := replaceFile(BackupPath(path, n), BackupPath(path, n+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("rotating backups: %w", err)
+		}
+	}
+	if err := WriteFile(BackupPath(path, 1), current, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Package fsutil tests atomic file writes and artifact backups.
+// Related: internal/fsutil/atomic.go
+// Tags: fsutil, atomic, fsync, backup
+
+package fsutil
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		existing string
+		data     string
+	}{
+		"creates new file":       {data: "new"},
+		"replaces existing file": {existing: "old contents that are longer", data: "new"},
+		"writes empty file":      {existing: "old", data: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			path := filepath.Join(dir, "spec.yaml")
+			if tt.existing != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.existing), 0o644))
+			}
+
+			require.NoError(t, WriteFile(path, []byte(tt.data), 0o644))
+
+			got, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.data, string(got))
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Len(t, entries, 1, "temp file must not be left behind")
+		})
+	}
+}
+
+func TestWriteFile_Mode(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permission bits are not supported on Windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "settings.json")
+	require.NoError(t, WriteFile(path, []byte("{}"), 0o600))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestWriteFile_MissingDir(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "missing", "spec.yaml")
+	err := WriteFile(path, []byte("data"), 0o644)
+	assert.ErrorContains(t, err, "creating temp file")
+}
+
+func TestBackupPath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "tasks.yaml.bak", BackupPath("tasks.yaml", 1))
+	assert.Equal(t, "tasks.yaml.bak.2", BackupPath("tasks.yaml", 2))
+	assert.Equal(t, "tasks.yaml.bak.5", BackupPath("tasks.yaml", 5))
+}
+
+func TestIsBackup(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]bool{
+		"tasks.yaml.bak":     true,
+		"tasks.yaml.bak.2":   true,
+		"tasks.yaml":         false,
+		"notes.bak.md":       false,
+		"tasks.yaml.bak.old": false,
+	}
+	for path, want := range tests {
+		assert.Equal(t, want, IsBackup(path), path)
+	}
+}
+
+func TestBackup_Rotation(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "tasks.yaml")
+	for _, version := range []string{"v1", "v2", "v3", "v4"} {
+		require.NoError(t, backup(path, 3))
+		require.NoError(t, WriteFile(path, []byte(version), 0o644))
+	}
+
+	want := map[string]string{
+		path:                "v4",
+		BackupPath(path, 1): "v3",
+		BackupPath(path, 2): "v2",
+		BackupPath(path, 3): "v1",
+	}
+	for file, content := range want {
+		got, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, content, string(got), file)
+	}
+	assert.NoFileExists(t, BackupPath(path, 4))
+}
+
+func TestBackup_Disabled(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("v1"), 0o644))
+
+	require.NoError(t, backup(path, 0))
+	assert.NoFileExists(t, BackupPath(path, 1))
+}
+
+func TestWriteArtifact(t *testing.T) {
+	previous := Backups()
+	t.Cleanup(func() { SetBackups(previous) })
+
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+
+	SetBackups(2)
+	require.NoError(t, WriteArtifact(path, []byte("v1"), 0o644))
+	assert.NoFileExists(t, BackupPath(path, 1), "first write has nothing to back up")
+
+	require.NoError(t, WriteArtifact(path, []byte("v2"), 0o644))
+	backup, err := os.ReadFile(BackupPath(path, 1))
+	require.NoError(t, err)
+	assert.Equal(t, "v1", string(backup))
+
+	SetBackups(-1)
+	assert.Equal(t, 0, Backups())
+}
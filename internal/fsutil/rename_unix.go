@@ -0,0 +1,26 @@
+//go:build !windows
+
+package fsutil
+
+import (
+	"fmt"
+	"os"
+)
+
+// replaceFile renames src over dst; rename(2) replaces dst atomically.
+func replaceFile(src, dst string) error {
+	return os.Rename(src, dst)
+}
+
+// syncDir fsyncs dir so a completed rename survives a crash.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("opening directory for sync: %w", err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("syncing directory: %w", err)
+	}
+	return nil
+}
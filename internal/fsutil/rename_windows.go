@@ -0,0 +1,54 @@
+//go:build windows
+
+package fsutil
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// Windows error codes returned while another process (an editor, a virus
+// scanner, the search indexer) briefly holds the target open.
+const (
+	errorAccessDenied     syscall.Errno = 5
+	errorSharingViolation syscall.Errno = 32
+)
+
+// renameAttempts and renameBackoff bound how long replaceFile waits for such
+// a handle to be released.
+const (
+	renameAttempts = 10
+	renameBackoff  = 20 * time.Millisecond
+)
+
+// replaceFile renames src over dst. os.Rename uses MoveFileEx with
+// MOVEFILE_REPLACE_EXISTING, which fails while dst is open elsewhere, so
+// transient access and sharing errors are retried with backoff.
+func replaceFile(src, dst string) error {
+	var err error
+	for attempt := 1; attempt <= renameAttempts; attempt++ {
+		err = os.Rename(src, dst)
+		if err == nil || !isTransient(err) {
+			return err
+		}
+		time.Sleep(time.Duration(attempt) * renameBackoff)
+	}
+	return err
+}
+
+// isTransient reports whether err may clear once another handle is closed.
+func isTransient(err error) bool {
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return false
+	}
+	return errno == errorAccessDenied || errno == errorSharingViolation
+}
+
+// syncDir is a no-op: Windows cannot open directories for fsync, and
+// MoveFileEx metadata updates are journaled by NTFS.
+func syncDir(string) error {
+	return nil
+}
@@ -0,0 +1,111 @@
+// Package fuzzy suggests close matches for mistyped identifiers such as spec
+// names and task IDs, so errors can say "did you mean 004-user-auth?".
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the number of suggestions returned by Suggest.
+const maxSuggestions = 3
+
+// Distance returns the case-insensitive Levenshtein edit distance between a and b.
+func Distance(a, b string) int {
+	ra := []rune(strings.ToLower(a))
+	rb := []rune(strings.ToLower(b))
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+// Contains returns the candidates that contain input, ignoring case.
+func Contains(input string, candidates []string) []string {
+	needle := strings.ToLower(input)
+	var matches []string
+	for _, c := range candidates {
+		if strings.Contains(strings.ToLower(c), needle) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// Suggest returns up to three candidates close to input, best first. A
+// candidate matches when it contains input, or when input is within a few
+// edits of the candidate or of one of its dash-separated suffixes ("user-auth"
+// and "auth" for "004-user-auth"), so the numeric prefix of a spec name does
+// not count against it.
+func Suggest(input string, candidates []string) []string {
+	if input == "" {
+		return nil
+	}
+	threshold := max(1, len([]rune(input))/3)
+
+	type scored struct {
+		name  string
+		score int
+	}
+	var matches []scored
+	for _, c := range candidates {
+		score := score(input, c)
+		if score <= threshold {
+			matches = append(matches, scored{name: c, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].name < matches[j].name
+	})
+
+	out := make([]string, 0, min(len(matches), maxSuggestions))
+	for _, m := range matches[:min(len(matches), maxSuggestions)] {
+		out = append(out, m.name)
+	}
+	return out
+}
+
+// score is the smallest edit distance between input and candidate or one of
+// its dash-separated suffixes; 0 when candidate contains input.
+func score(input, candidate string) int {
+	if strings.Contains(strings.ToLower(candidate), strings.ToLower(input)) {
+		return 0
+	}
+	best := Distance(input, candidate)
+	for i, r := range candidate {
+		if r == '-' && i+1 < len(candidate) {
+			best = min(best, Distance(input, candidate[i+1:]))
+		}
+	}
+	return best
+}
+
+// DidYouMean formats suggestions as "did you mean X?" or "did you mean X or
+// Y?". It returns "" when there are none.
+func DidYouMean(suggestions []string) string {
+	switch len(suggestions) {
+	case 0:
+		return ""
+	case 1:
+		return "did you mean " + suggestions[0] + "?"
+	default:
+		last := len(suggestions) - 1
+		return "did you mean " + strings.Join(suggestions[:last], ", ") + " or " + suggestions[last] + "?"
+	}
+}
@@ -0,0 +1,68 @@
+package fuzzy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		a, b string
+		want int
+	}{
+		"equal":         {a: "auth", b: "auth", want: 0},
+		"case ignored":  {a: "AUTH", b: "auth", want: 0},
+		"empty":         {a: "", b: "abc", want: 3},
+		"substitution":  {a: "T014", b: "T015", want: 1},
+		"transposition": {a: "user-auht", b: "user-auth", want: 2},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Distance(tt.a, tt.b))
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	specs := []string{"001-cli-setup", "004-user-auth", "005-user-profile", "012-billing"}
+
+	tests := map[string]struct {
+		input string
+		want  []string
+	}{
+		"typo in name ignores number prefix": {input: "user-auht", want: []string{"004-user-auth"}},
+		"substring ranks first":              {input: "user", want: []string{"004-user-auth", "005-user-profile"}},
+		"suffix typo":                        {input: "biling", want: []string{"012-billing"}},
+		"nothing close":                      {input: "payments", want: []string{}},
+		"empty input":                        {input: "", want: nil},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Suggest(tt.input, specs))
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	t.Parallel()
+
+	got := Contains("USER", []string{"004-user-auth", "005-user-profile", "012-billing"})
+	assert.Equal(t, []string{"004-user-auth", "005-user-profile"}, got)
+}
+
+func TestDidYouMean(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, DidYouMean(nil))
+	assert.Equal(t, "did you mean T014?", DidYouMean([]string{"T014"}))
+	assert.Equal(t, "did you mean a, b or c?", DidYouMean([]string{"a", "b", "c"}))
+}
@@ -0,0 +1,184 @@
+package ghproject
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds each GitHub API request.
+const requestTimeout = 15 * time.Second
+
+// maxErrorBody caps how much of an error response is included in errors.
+const maxErrorBody = 300
+
+// graphQLClient talks to the GitHub GraphQL API.
+type graphQLClient struct {
+	http     *http.Client
+	endpoint string
+	token    string
+	cfg      Config
+}
+
+func newGraphQLClient(cfg Config, token, endpoint string) *graphQLClient {
+	return &graphQLClient{
+		http:     &http.Client{Timeout: requestTimeout},
+		endpoint: endpoint,
+		token:    token,
+		cfg:      cfg,
+	}
+}
+
+// query runs a GraphQL operation and decodes its data into out.
+func (c *graphQLClient) query(ctx context.Context, query string, vars map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": vars})
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Authorization", "bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", c.endpoint, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > maxErrorBody {
+			msg = msg[:maxErrorBody] + "…"
+		}
+		return fmt.Errorf("POST %s: %s: %s", c.endpoint, resp.Status, msg)
+	}
+
+	result := struct {
+		Data   any `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}{Data: out}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		msgs := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			msgs[i] = e.Message
+		}
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// Project resolves the configured project of a user or organization and
+// its status field.
+func (c *graphQLClient) Project(ctx context.Context) (Project, error) {
+	const q = `query($owner: String!, $number: Int!, $field: String!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        url
+        field(name: $field) { ... on ProjectV2SingleSelectField { id options { id name } } }
+      }
+    }
+  }
+}`
+	var data struct {
+		RepositoryOwner *struct {
+			ProjectV2 *struct {
+				ID    string `json:"id"`
+				URL   string `json:"url"`
+				Field *struct {
+					ID      string `json:"id"`
+					Options []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"options"`
+				} `json:"field"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	vars := map[string]any{"owner": c.cfg.Owner, "number": c.cfg.Number, "field": c.cfg.StatusField}
+	if err := c.query(ctx, q, vars, &data); err != nil {
+		return Project{}, err
+	}
+	if data.RepositoryOwner == nil {
+		return Project{}, fmt.Errorf("no user or organization %q", c.cfg.Owner)
+	}
+	p := data.RepositoryOwner.ProjectV2
+	if p == nil {
+		return Project{}, fmt.Errorf("%s has no project %d", c.cfg.Owner, c.cfg.Number)
+	}
+	if p.Field == nil || p.Field.ID == "" {
+		return Project{}, fmt.Errorf("project has no single-select field %q", c.cfg.StatusField)
+	}
+	project := Project{ID: p.ID, URL: p.URL, FieldID: p.Field.ID, Options: map[string]string{}}
+	for _, o := range p.Field.Options {
+		project.Options[strings.ToLower(o.Name)] = o.ID
+	}
+	return project, nil
+}
+
+// AddDraftItem adds a draft issue to a project.
+func (c *graphQLClient) AddDraftItem(ctx context.Context, projectID, title, body string) (string, error) {
+	const mutation = `mutation($input: AddProjectV2DraftIssueInput!) {
+  addProjectV2DraftIssue(input: $input) { projectItem { id } }
+}`
+	var data struct {
+		AddProjectV2DraftIssue struct {
+			ProjectItem struct {
+				ID string `json:"id"`
+			} `json:"projectItem"`
+		} `json:"addProjectV2DraftIssue"`
+	}
+	input := map[string]any{"projectId": projectID, "title": title, "body": body}
+	if err := c.query(ctx, mutation, map[string]any{"input": input}, &data); err != nil {
+		return "", err
+	}
+	if data.AddProjectV2DraftIssue.ProjectItem.ID == "" {
+		return "", errors.New("github did not create the item")
+	}
+	return data.AddProjectV2DraftIssue.ProjectItem.ID, nil
+}
+
+// SetStatus sets an item's status field to a single-select option.
+func (c *graphQLClient) SetStatus(ctx context.Context, p Project, itemID, optionID string) error {
+	const mutation = `mutation($input: UpdateProjectV2ItemFieldValueInput!) {
+  updateProjectV2ItemFieldValue(input: $input) { projectV2Item { id } }
+}`
+	var data struct {
+		UpdateProjectV2ItemFieldValue struct {
+			ProjectV2Item struct {
+				ID string `json:"id"`
+			} `json:"projectV2Item"`
+		} `json:"updateProjectV2ItemFieldValue"`
+	}
+	input := map[string]any{
+		"projectId": p.ID,
+		"itemId":    itemID,
+		"fieldId":   p.FieldID,
+		"value":     map[string]any{"singleSelectOptionId": optionID},
+	}
+	if err := c.query(ctx, mutation, map[string]any{"input": input}, &data); err != nil {
+		return err
+	}
+	if data.UpdateProjectV2ItemFieldValue.ProjectV2Item.ID == "" {
+		return fmt.Errorf("github did not update item %s", itemID)
+	}
+	return nil
+}
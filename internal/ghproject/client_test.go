@@ -0,0 +1,100 @@
+package ghproject
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLClient(t *testing.T) {
+	t.Parallel()
+
+	var projectVars, addInput, updateInput map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer ghp_token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "repositoryOwner"):
+			projectVars = req.Variables
+			_, _ = w.Write([]byte(`{"data":{"repositoryOwner":{"projectV2":{"id":"PVT_1","url":"https://github.com/orgs/acme/projects/3",
+				"field":{"id":"F1","options":[{"id":"o1","name":"Todo"},{"id":"o2","name":"In Progress"}]}}}}}`))
+		case strings.Contains(req.Query, "addProjectV2DraftIssue"):
+			addInput = req.Variables["input"].(map[string]any)
+			_, _ = w.Write([]byte(`{"data":{"addProjectV2DraftIssue":{"projectItem":{"id":"PVTI_9"}}}}`))
+		case strings.Contains(req.Query, "updateProjectV2ItemFieldValue"):
+			updateInput = req.Variables["input"].(map[string]any)
+			_, _ = w.Write([]byte(`{"data":null,"errors":[{"message":"Could not resolve to a node with the global id of 'PVTI_9'"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	cfg := Config{Owner: "acme", Number: 3}.withDefaults()
+	client := newGraphQLClient(cfg, "ghp_token", srv.URL)
+	ctx := context.Background()
+
+	project, err := client.Project(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Project{
+		ID:      "PVT_1",
+		URL:     "https://github.com/orgs/acme/projects/3",
+		FieldID: "F1",
+		Options: map[string]string{"todo": "o1", "in progress": "o2"},
+	}, project)
+	assert.Equal(t, map[string]any{"owner": "acme", "number": float64(3), "field": "Status"}, projectVars)
+
+	id, err := client.AddDraftItem(ctx, "PVT_1", "T001: Login handler", "body")
+	require.NoError(t, err)
+	assert.Equal(t, "PVTI_9", id)
+	assert.Equal(t, map[string]any{"projectId": "PVT_1", "title": "T001: Login handler", "body": "body"}, addInput)
+
+	err = client.SetStatus(ctx, project, "PVTI_9", "o2")
+	assert.ErrorContains(t, err, "Could not resolve to a node")
+	assert.Equal(t, map[string]any{"singleSelectOptionId": "o2"}, updateInput["value"])
+	assert.Equal(t, "F1", updateInput["fieldId"])
+
+	_, err = newGraphQLClient(cfg, "wrong", srv.URL).Project(ctx)
+	assert.ErrorContains(t, err, "401 Unauthorized")
+}
+
+func TestGraphQLClient_ProjectNotFound(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		response string
+		wantErr  string
+	}{
+		"no owner":   {response: `{"data":{"repositoryOwner":null}}`, wantErr: `no user or organization "acme"`},
+		"no project": {response: `{"data":{"repositoryOwner":{"projectV2":null}}}`, wantErr: "acme has no project 3"},
+		"no field": {
+			response: `{"data":{"repositoryOwner":{"projectV2":{"id":"PVT_1","field":null}}}}`,
+			wantErr:  `no single-select field "Status"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer srv.Close()
+
+			client := newGraphQLClient(Config{Owner: "acme", Number: 3}.withDefaults(), "t", srv.URL)
+			_, err := client.Project(context.Background())
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,310 @@
+// Package ghproject mirrors a spec's tasks.yaml into a GitHub Projects (v2)
+// board, so that people who do not use the CLI can follow progress.
+//
+// Each task becomes a draft item titled "T001: <title>", and the board's
+// status field (a single-select field, "Status" by default) is set to the
+// column configured for the task's status. Item IDs are kept in the spec's
+// github-project.yaml, so later syncs only move items whose task status
+// changed. The token is read from GITHUB_TOKEN, GH_TOKEN or the github-token
+// keychain secret, never from config files.
+package ghproject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/board"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/secrets"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// ItemsFileName is the file in a spec directory that maps tasks to project items.
+const ItemsFileName = "github-project.yaml"
+
+// Defaults for unset config values.
+const (
+	DefaultStatusField      = "Status"
+	DefaultPendingColumn    = "Todo"
+	DefaultInProgressColumn = "In Progress"
+	DefaultBlockedColumn    = "Blocked"
+	DefaultCompletedColumn  = "Done"
+	defaultAPI              = "https://api.github.com/graphql"
+)
+
+// Config configures the GitHub Projects sync (config key: github_project).
+type Config struct {
+	// Owner is the user or organization login that owns the project; empty disables the sync
+	Owner string `koanf:"owner" yaml:"owner" json:"owner"`
+
+	// Number is the project number, as in github.com/orgs/<owner>/projects/<number>
+	Number int `koanf:"number" yaml:"number" json:"number"`
+
+	// StatusField is the single-select field whose options are the board's columns (default: Status)
+	StatusField string `koanf:"status_field" yaml:"status_field" json:"status_field"`
+
+	// Columns maps task statuses to options of the status field
+	Columns Columns `koanf:"columns" yaml:"columns" json:"columns"`
+}
+
+// Columns are the status field options of each task status.
+type Columns struct {
+	Pending    string `koanf:"pending" yaml:"pending" json:"pending"`
+	InProgress string `koanf:"in_progress" yaml:"in_progress" json:"in_progress"`
+	Blocked    string `koanf:"blocked" yaml:"blocked" json:"blocked"`
+	Completed  string `koanf:"completed" yaml:"completed" json:"completed"`
+}
+
+// Enabled reports whether a project is configured.
+func (c Config) Enabled() bool {
+	return c.Owner != "" || c.Number != 0
+}
+
+// Validate checks that a configured project has an owner and a number.
+func (c Config) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.Owner == "" {
+		return errors.New("owner is required")
+	}
+	if c.Number <= 0 {
+		return fmt.Errorf("number must be a positive project number, got %d", c.Number)
+	}
+	return nil
+}
+
+// withDefaults fills unset values.
+func (c Config) withDefaults() Config {
+	if c.StatusField == "" {
+		c.StatusField = DefaultStatusField
+	}
+	for _, col := range []struct {
+		value    *string
+		fallback string
+	}{
+		{&c.Columns.Pending, DefaultPendingColumn},
+		{&c.Columns.InProgress, DefaultInProgressColumn},
+		{&c.Columns.Blocked, DefaultBlockedColumn},
+		{&c.Columns.Completed, DefaultCompletedColumn},
+	} {
+		if *col.value == "" {
+			*col.value = col.fallback
+		}
+	}
+	return c
+}
+
+// column returns the status field option for a task status. Statuses are
+// matched like 'autospec board' does; unknown ones are pending.
+func (c Config) column(status string) string {
+	switch board.Columns[board.ColumnIndex(status)] {
+	case "InProgress":
+		return c.Columns.InProgress
+	case "Blocked":
+		return c.Columns.Blocked
+	case "Completed":
+		return c.Columns.Completed
+	}
+	return c.Columns.Pending
+}
+
+// Project is a resolved project and its status field.
+type Project struct {
+	ID      string
+	URL     string
+	FieldID string
+	Options map[string]string // lower-case option name -> option ID
+}
+
+// Client is the GitHub API used by Syncer.
+type Client interface {
+	// Project resolves the configured project and its status field.
+	Project(ctx context.Context) (Project, error)
+	// AddDraftItem adds a draft issue to the project and returns its item ID.
+	AddDraftItem(ctx context.Context, projectID, title, body string) (string, error)
+	// SetStatus sets an item's status field to the option optionID.
+	SetStatus(ctx context.Context, p Project, itemID, optionID string) error
+}
+
+// Item records the project item of one task.
+type Item struct {
+	ID     string `yaml:"id"`
+	Status string `yaml:"status,omitempty"` // Last column autospec moved the item to
+}
+
+// Items is the content of github-project.yaml.
+type Items struct {
+	Project string          `yaml:"project"` // Project node ID
+	URL     string          `yaml:"url,omitempty"`
+	Tasks   map[string]Item `yaml:"tasks"`
+}
+
+// LoadItems reads github-project.yaml from specDir; a missing file yields no items.
+func LoadItems(specDir string) (*Items, error) {
+	items := &Items{Tasks: map[string]Item{}}
+	data, err := os.ReadFile(filepath.Join(specDir, ItemsFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return items, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ItemsFileName, err)
+	}
+	if err := yaml.Unmarshal(data, items); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", ItemsFileName, err)
+	}
+	if items.Tasks == nil {
+		items.Tasks = map[string]Item{}
+	}
+	return items, nil
+}
+
+// save writes the items to specDir/github-project.yaml.
+func (i *Items) save(specDir string) error {
+	data, err := yaml.Marshal(i)
+	if err != nil {
+		return fmt.Errorf("serializing %s: %w", ItemsFileName, err)
+	}
+	if err := fsutil.WriteFile(filepath.Join(specDir, ItemsFileName), data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", ItemsFileName, err)
+	}
+	return nil
+}
+
+// Result counts the changes of a sync.
+type Result struct {
+	URL     string // Project URL
+	Created int    // Items added for new tasks
+	Moved   int    // Items moved to another column
+}
+
+// Syncer mirrors tasks into a project. A nil Syncer does nothing.
+type Syncer struct {
+	cfg     Config
+	client  Client
+	project *Project // resolved on first use
+}
+
+// New creates a Syncer for the configured project, reading the GitHub token
+// from GITHUB_TOKEN, GH_TOKEN or the keychain. It returns nil when no project
+// is configured.
+func New(cfg Config) (*Syncer, error) {
+	if !cfg.Enabled() {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	token, err := secrets.Get(secrets.GitHubToken, "GITHUB_TOKEN", "GH_TOKEN")
+	if err != nil {
+		return nil, fmt.Errorf("GitHub token not set (it needs the project scope): %w", err)
+	}
+	return NewWithClient(cfg, newGraphQLClient(cfg.withDefaults(), token, defaultAPI)), nil
+}
+
+// NewWithClient creates a Syncer that uses client for API calls.
+func NewWithClient(cfg Config, client Client) *Syncer {
+	return &Syncer{cfg: cfg.withDefaults(), client: client}
+}
+
+// Sync adds an item for every task in specDir's tasks.yaml that has none
+// yet and moves items whose task status changed since the last sync. Items
+// are saved after each one is added, so a failure part-way keeps the items
+// already created. Tasks whose column is not an option of the status field
+// are reported in the error and left out.
+func (s *Syncer) Sync(ctx context.Context, specDir string) (Result, error) {
+	if s == nil {
+		return Result{}, nil
+	}
+	tasks, err := validation.GetAllTasks(validation.GetTasksFilePath(specDir))
+	if err != nil {
+		return Result{}, fmt.Errorf("loading tasks: %w", err)
+	}
+	if s.project == nil {
+		p, err := s.client.Project(ctx)
+		if err != nil {
+			return Result{}, fmt.Errorf("loading project %s/%d: %w", s.cfg.Owner, s.cfg.Number, err)
+		}
+		s.project = &p
+	}
+	items, err := LoadItems(specDir)
+	if err != nil {
+		return Result{}, err
+	}
+	if items.Project != s.project.ID {
+		// Another project: its items are not ours to move
+		items = &Items{Project: s.project.ID, Tasks: map[string]Item{}}
+	}
+	items.URL = s.project.URL
+
+	result := Result{URL: s.project.URL}
+	missing := map[string]bool{}
+	var errs []error
+	for _, task := range tasks {
+		column := s.cfg.column(task.Status)
+		optionID, ok := s.project.Options[strings.ToLower(column)]
+		if !ok {
+			if !missing[column] {
+				missing[column] = true
+				errs = append(errs, fmt.Errorf("field %q has no option %q; add the column to the project or set github_project.columns", s.cfg.StatusField, column))
+			}
+			continue
+		}
+		item := items.Tasks[task.ID]
+		if item.ID != "" && item.Status == column {
+			continue
+		}
+		if item.ID == "" {
+			id, err := s.client.AddDraftItem(ctx, s.project.ID, taskTitle(task), taskBody(task, specDir))
+			if err != nil {
+				return result, errors.Join(append(errs, fmt.Errorf("adding item for %s: %w", task.ID, err))...)
+			}
+			item = Item{ID: id}
+			items.Tasks[task.ID] = item
+			if err := items.save(specDir); err != nil {
+				return result, errors.Join(append(errs, err)...)
+			}
+			result.Created++
+		}
+		if err := s.client.SetStatus(ctx, *s.project, item.ID, optionID); err != nil {
+			errs = append(errs, fmt.Errorf("moving %s to %q: %w", task.ID, column, err))
+			continue
+		}
+		if item.Status != "" {
+			result.Moved++
+		}
+		item.Status = column
+		items.Tasks[task.ID] = item
+	}
+	if err := items.save(specDir); err != nil {
+		errs = append(errs, err)
+	}
+	return result, errors.Join(errs...)
+}
+
+// taskTitle is the item title for a task.
+func taskTitle(task validation.TaskItem) string {
+	return fmt.Sprintf("%s: %s", task.ID, task.Title)
+}
+
+// taskBody is the markdown item body for a task.
+func taskBody(task validation.TaskItem, specDir string) string {
+	var sb strings.Builder
+	if task.StoryID != "" {
+		fmt.Fprintf(&sb, "Story: %s\n\n", task.StoryID)
+	}
+	if len(task.AcceptanceCriteria) > 0 {
+		sb.WriteString("Acceptance criteria:\n")
+		for _, c := range task.AcceptanceCriteria {
+			fmt.Fprintf(&sb, "- %s\n", c)
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "Synced by autospec from %s.", filepath.Join(specDir, "tasks.yaml"))
+	return sb.String()
+}
@@ -0,0 +1,238 @@
+package ghproject
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const projectTasksYAML = `phases:
+  - number: 1
+    title: "Core"
+    tasks:
+      - id: "T001"
+        title: "Login handler"
+        status: "Completed"
+        type: "implementation"
+        story_id: "US-001"
+        acceptance_criteria:
+          - "Valid credentials log the user in"
+      - id: "T002"
+        title: "Logout handler"
+        status: "InProgress"
+        type: "implementation"
+      - id: "T003"
+        title: "Logout button"
+        status: "Pending"
+        type: "implementation"
+`
+
+// fakeClient records GitHub API calls.
+type fakeClient struct {
+	project     Project
+	projectErr  error
+	added       []string
+	moves       []string
+	addErr      error
+	projectCall int
+}
+
+func (f *fakeClient) Project(context.Context) (Project, error) {
+	f.projectCall++
+	return f.project, f.projectErr
+}
+
+func (f *fakeClient) AddDraftItem(_ context.Context, projectID, title, _ string) (string, error) {
+	if f.addErr != nil {
+		return "", f.addErr
+	}
+	f.added = append(f.added, title)
+	return fmt.Sprintf("%s-item-%d", projectID, len(f.added)), nil
+}
+
+func (f *fakeClient) SetStatus(_ context.Context, _ Project, itemID, optionID string) error {
+	f.moves = append(f.moves, itemID+"="+optionID)
+	return nil
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{project: Project{
+		ID:      "PVT_1",
+		URL:     "https://github.com/orgs/acme/projects/3",
+		FieldID: "F1",
+		Options: map[string]string{"todo": "o-todo", "in progress": "o-doing", "blocked": "o-blocked", "done": "o-done"},
+	}}
+}
+
+func writeTasks(t *testing.T, content string) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tasks.yaml"), []byte(content), 0o644))
+	return dir
+}
+
+func TestConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"disabled":       {cfg: Config{}},
+		"complete":       {cfg: Config{Owner: "acme", Number: 3}},
+		"missing owner":  {cfg: Config{Number: 3}, wantErr: "owner is required"},
+		"missing number": {cfg: Config{Owner: "acme"}, wantErr: "positive project number"},
+		"negative":       {cfg: Config{Owner: "acme", Number: -1}, wantErr: "positive project number"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigColumn(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{Columns: Columns{Blocked: "On hold"}}.withDefaults()
+	tests := map[string]string{
+		"Pending":    "Todo",
+		"InProgress": "In Progress",
+		"in_review":  "Todo",
+		"blocked":    "On hold",
+		"Completed":  "Done",
+	}
+	for status, want := range tests {
+		assert.Equal(t, want, cfg.column(status), status)
+	}
+	assert.Equal(t, DefaultStatusField, cfg.StatusField)
+}
+
+func TestNew_Disabled(t *testing.T) {
+	t.Parallel()
+
+	s, err := New(Config{})
+	require.NoError(t, err)
+	assert.Nil(t, s)
+
+	result, err := s.Sync(context.Background(), t.TempDir())
+	require.NoError(t, err)
+	assert.Equal(t, Result{}, result)
+}
+
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTasks(t, projectTasksYAML)
+	client := newFakeClient()
+	s := NewWithClient(Config{Owner: "acme", Number: 3}, client)
+	ctx := context.Background()
+
+	result, err := s.Sync(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, Result{URL: "https://github.com/orgs/acme/projects/3", Created: 3}, result)
+	assert.Equal(t, []string{"T001: Login handler", "T002: Logout handler", "T003: Logout button"}, client.added)
+	assert.Equal(t, []string{"PVT_1-item-1=o-done", "PVT_1-item-2=o-doing", "PVT_1-item-3=o-todo"}, client.moves)
+
+	items, err := LoadItems(dir)
+	require.NoError(t, err)
+	assert.Equal(t, "PVT_1", items.Project)
+	assert.Equal(t, Item{ID: "PVT_1-item-2", Status: "In Progress"}, items.Tasks["T002"])
+
+	// Only changed tasks move; the project is resolved once
+	client.moves = nil
+	updated := strings.Replace(projectTasksYAML, `status: "InProgress"`, `status: "Completed"`, 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tasks.yaml"), []byte(updated), 0o644))
+
+	result, err = s.Sync(ctx, dir)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Created)
+	assert.Equal(t, 1, result.Moved)
+	assert.Equal(t, []string{"PVT_1-item-2=o-done"}, client.moves)
+	assert.Len(t, client.added, 3)
+	assert.Equal(t, 1, client.projectCall)
+}
+
+func TestSync_MissingColumn(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTasks(t, projectTasksYAML)
+	client := newFakeClient()
+	delete(client.project.Options, "in progress")
+	s := NewWithClient(Config{Owner: "acme", Number: 3}, client)
+
+	result, err := s.Sync(context.Background(), dir)
+	require.ErrorContains(t, err, `field "Status" has no option "In Progress"`)
+	assert.Equal(t, 2, result.Created, "tasks with a known column are still synced")
+
+	items, err := LoadItems(dir)
+	require.NoError(t, err)
+	assert.NotContains(t, items.Tasks, "T002")
+}
+
+func TestSync_Errors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		tasks   string
+		client  func(*fakeClient)
+		wantErr string
+	}{
+		"no tasks.yaml": {
+			wantErr: "loading tasks",
+		},
+		"project lookup fails": {
+			tasks:   projectTasksYAML,
+			client:  func(f *fakeClient) { f.projectErr = errors.New("Could not resolve to a ProjectV2") },
+			wantErr: "loading project acme/3: Could not resolve to a ProjectV2",
+		},
+		"adding an item fails": {
+			tasks:   projectTasksYAML,
+			client:  func(f *fakeClient) { f.addErr = errors.New("missing project scope") },
+			wantErr: "adding item for T001: missing project scope",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			if tt.tasks != "" {
+				dir = writeTasks(t, tt.tasks)
+			}
+			client := newFakeClient()
+			if tt.client != nil {
+				tt.client(client)
+			}
+			_, err := NewWithClient(Config{Owner: "acme", Number: 3}, client).Sync(context.Background(), dir)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestSync_OtherProject(t *testing.T) {
+	t.Parallel()
+
+	dir := writeTasks(t, projectTasksYAML)
+	stale := "project: PVT_old\ntasks:\n  T001: {id: old-item, status: Done}\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ItemsFileName), []byte(stale), 0o644))
+	client := newFakeClient()
+
+	result, err := NewWithClient(Config{Owner: "acme", Number: 3}, client).Sync(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Created, "items of another project are not reused")
+}
@@ -31,6 +31,104 @@ func GetRepositoryRoot() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// GetCommonDir returns the absolute path of the git directory shared by all
+// worktrees of the repository (the main worktree's .git directory).
+func GetCommonDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-common-dir")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ListBranchRefs returns the full ref names of all local and remote-tracking
+// branches, e.g. "refs/heads/main" and "refs/remotes/origin/main".
+func ListBranchRefs() ([]string, error) {
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch refs: %w", err)
+	}
+	var refs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" && !strings.HasSuffix(line, "/HEAD") {
+			refs = append(refs, line)
+		}
+	}
+	return refs, nil
+}
+
+// ListTreeDirs returns the directories below dir, recursively, in the tree of
+// ref. dir is relative to the repository root and so are the returned paths.
+// A dir missing from ref yields no paths.
+func ListTreeDirs(ref, dir string) ([]string, error) {
+	cmd := exec.Command("git", "ls-tree", "-r", "-d", "--name-only", "--full-tree", ref, "--", strings.TrimSuffix(dir, "/")+"/")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s in %s: %w", dir, ref, err)
+	}
+	var dirs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			dirs = append(dirs, line)
+		}
+	}
+	return dirs, nil
+}
+
+// RenameBranch renames a local branch.
+func RenameBranch(oldName, newName string) error {
+	if out, err := exec.Command("git", "branch", "-m", oldName, newName).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to rename branch '%s' to '%s': %s", oldName, newName, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// GetRemoteURL returns the URL of the named remote, or an error if it does not exist
+func GetRemoteURL(name string) (string, error) {
+	cmd := exec.Command("git", "remote", "get-url", name)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetUserName returns the configured git user.name, or an error if unset
+func GetUserName() (string, error) {
+	cmd := exec.Command("git", "config", "user.name")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// ChangedFiles returns files changed between the merge-base of base and HEAD,
+// including uncommitted changes in the working tree. Paths are repo-relative and sorted.
+func ChangedFiles(base string) ([]string, error) {
+	mergeBase, err := exec.Command("git", "merge-base", base, "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("finding merge-base with %s: %w", base, err)
+	}
+
+	cmd := exec.Command("git", "diff", "--name-only", strings.TrimSpace(string(mergeBase)))
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing changed files: %w", err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 // IsGitRepository checks if the current directory is within a git repository
 func IsGitRepository() bool {
 	cmd := exec.Command("git", "rev-parse", "--git-dir")
@@ -0,0 +1,119 @@
+package git
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Snapshot records HEAD and the content of the uncommitted files of a
+// repository, so the files an agent session touched can be listed afterwards,
+// whether it left them uncommitted or committed them.
+type Snapshot struct {
+	// Root is the repository root the file paths are relative to.
+	Root string
+
+	head  string            // HEAD commit ("" in a repository without commits)
+	dirty map[string]string // uncommitted path -> content hash ("" if deleted)
+}
+
+// TakeSnapshot records the current state of the repository containing the
+// working directory.
+func TakeSnapshot() (*Snapshot, error) {
+	root, err := GetRepositoryRoot()
+	if err != nil {
+		return nil, fmt.Errorf("finding repository root: %w", err)
+	}
+	s := &Snapshot{Root: root}
+	if out, err := exec.Command("git", "-C", root, "rev-parse", "--verify", "-q", "HEAD").Output(); err == nil {
+		s.head = strings.TrimSpace(string(out))
+	}
+	paths, err := statusPaths(root)
+	if err != nil {
+		return nil, err
+	}
+	s.dirty = make(map[string]string, len(paths))
+	for _, p := range paths {
+		s.dirty[p] = hashFile(filepath.Join(root, p))
+	}
+	return s, nil
+}
+
+// ChangedSince returns the files that differ from the snapshot: files
+// committed since, and uncommitted files that are new or whose content
+// changed. Deleted files are included. Paths are relative to Root and sorted.
+func (s *Snapshot) ChangedSince() ([]string, error) {
+	candidates := make(map[string]bool)
+	for p := range s.dirty {
+		candidates[p] = true
+	}
+	paths, err := statusPaths(s.Root)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range paths {
+		candidates[p] = true
+	}
+	if out, err := exec.Command("git", "-C", s.Root, "rev-parse", "--verify", "-q", "HEAD").Output(); err == nil && s.head != "" {
+		if head := strings.TrimSpace(string(out)); head != s.head {
+			committed, err := exec.Command("git", "-C", s.Root, "diff", "--name-only", "-z", s.head, head).Output()
+			if err != nil {
+				return nil, fmt.Errorf("listing committed files: %w", err)
+			}
+			for _, p := range strings.Split(string(committed), "\x00") {
+				if p != "" {
+					candidates[p] = true
+				}
+			}
+		}
+	}
+
+	var changed []string
+	for p := range candidates {
+		before, wasDirty := s.dirty[p]
+		if wasDirty && hashFile(filepath.Join(s.Root, p)) == before {
+			continue
+		}
+		changed = append(changed, p)
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// statusPaths returns the paths git status reports as modified, added,
+// deleted or untracked, relative to root. Renames report the new path.
+func statusPaths(root string) ([]string, error) {
+	out, err := exec.Command("git", "-C", root, "status", "--porcelain", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading git status: %w", err)
+	}
+	var paths []string
+	entries := bytes.Split(out, []byte{0})
+	for i := 0; i < len(entries); i++ {
+		entry := string(entries[i])
+		if len(entry) < 4 {
+			continue
+		}
+		paths = append(paths, entry[3:])
+		if entry[0] == 'R' || entry[0] == 'C' {
+			i++ // the original path follows a rename or copy
+		}
+	}
+	return paths, nil
+}
+
+// hashFile returns the hex SHA-256 of the file, or "" if it cannot be read.
+func hashFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
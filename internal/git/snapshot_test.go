@@ -0,0 +1,76 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initSnapshotRepo creates a repository with one commit and changes the
+// working directory to it.
+func initSnapshotRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "test"},
+	} {
+		require.NoError(t, exec.Command("git", args...).Run())
+	}
+	writeFile(t, "clean.go", "package a\n")
+	writeFile(t, "wip.go", "package a\n")
+	runGit(t, "add", ".")
+	runGit(t, "commit", "-q", "-m", "init")
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func runGit(t *testing.T, args ...string) {
+	t.Helper()
+	out, err := exec.Command("git", args...).CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestSnapshot_ChangedSince(t *testing.T) {
+	initSnapshotRepo(t)
+	writeFile(t, "wip.go", "package a // user edit\n")
+	writeFile(t, "notes.txt", "user file\n")
+
+	snapshot, err := TakeSnapshot()
+	require.NoError(t, err)
+
+	// The session edits a clean file, adds one, commits another, changes the
+	// user's work in progress and deletes a file; notes.txt is untouched.
+	writeFile(t, "clean.go", "package a // agent\n")
+	writeFile(t, "pkg/new.go", "package pkg\n")
+	writeFile(t, "committed.go", "package a\n")
+	runGit(t, "add", "committed.go")
+	runGit(t, "commit", "-q", "-m", "agent")
+	writeFile(t, "wip.go", "package a // agent edit\n")
+
+	changed, err := snapshot.ChangedSince()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"clean.go", "committed.go", "pkg/new.go", "wip.go"}, changed)
+}
+
+func TestSnapshot_ChangedSince_NothingChanged(t *testing.T) {
+	initSnapshotRepo(t)
+	writeFile(t, "wip.go", "package a // user edit\n")
+
+	snapshot, err := TakeSnapshot()
+	require.NoError(t, err)
+	changed, err := snapshot.ChangedSince()
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+}
@@ -221,7 +221,7 @@ func formatClaudeCheckResult(result claude.SettingsCheckResult) CheckResult {
 		return CheckResult{
 			Name:    "Claude settings",
 			Passed:  false,
-			Message: fmt.Sprintf("%s is explicitly denied. Remove from permissions.deny in %s to allow autospec commands.", claude.RequiredPermission, result.FilePath),
+			Message: fmt.Sprintf("%s is explicitly denied in %s (run 'autospec config fix-permissions' to resolve)", claude.RequiredPermission, result.FilePath),
 		}
 	default:
 		return CheckResult{
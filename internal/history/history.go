@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 	"gopkg.in/yaml.v3"
 )
 
@@ -33,27 +34,27 @@ const (
 type HistoryEntry struct {
 	// ID is a unique identifier in adjective_noun_YYYYMMDD_HHMMSS format.
 	// Optional for backward compatibility with old entries.
-	ID string `yaml:"id,omitempty"`
+	ID string `yaml:"id,omitempty" json:"id,omitempty"`
 	// Timestamp is when the command started executing (RFC3339 format in YAML).
 	// Kept for backward compatibility with existing entries.
-	Timestamp time.Time `yaml:"timestamp"`
+	Timestamp time.Time `yaml:"timestamp" json:"timestamp"`
 	// Command is the name of the autospec command (e.g., "specify", "run").
-	Command string `yaml:"command"`
+	Command string `yaml:"command" json:"command"`
 	// Spec is the name or path of the spec being worked on (may be empty).
-	Spec string `yaml:"spec,omitempty"`
+	Spec string `yaml:"spec,omitempty" json:"spec,omitempty"`
 	// Status is the current state: running, completed, failed, cancelled.
 	// Optional for backward compatibility with old entries.
-	Status string `yaml:"status,omitempty"`
+	Status string `yaml:"status,omitempty" json:"status,omitempty"`
 	// CreatedAt is when the command started (explicit field, same as Timestamp).
 	// Optional for backward compatibility with old entries.
-	CreatedAt time.Time `yaml:"created_at,omitempty"`
+	CreatedAt time.Time `yaml:"created_at,omitempty" json:"created_at,omitempty"`
 	// CompletedAt is when the command finished (nil if still running).
 	// Pointer allows distinguishing between "not set" and "zero time".
-	CompletedAt *time.Time `yaml:"completed_at,omitempty"`
+	CompletedAt *time.Time `yaml:"completed_at,omitempty" json:"completed_at,omitempty"`
 	// ExitCode is the exit code of the command (0=success).
-	ExitCode int `yaml:"exit_code"`
+	ExitCode int `yaml:"exit_code" json:"exit_code"`
 	// Duration is the execution duration in Go duration format (e.g., "2m15.123s").
-	Duration string `yaml:"duration"`
+	Duration string `yaml:"duration" json:"duration"`
 }
 
 // HistoryFile represents the YAML file containing all history entries.
@@ -123,15 +124,8 @@ func SaveHistory(stateDir string, history *HistoryFile) error {
 	}
 
 	historyPath := filepath.Join(stateDir, HistoryFileName)
-	tmpPath := historyPath + ".tmp"
-
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return fmt.Errorf("writing temp history file: %w", err)
-	}
-
-	if err := os.Rename(tmpPath, historyPath); err != nil {
-		os.Remove(tmpPath)
-		return fmt.Errorf("renaming temp history file: %w", err)
+	if err := fsutil.WriteFile(historyPath, data, 0o644); err != nil {
+		return fmt.Errorf("writing history file: %w", err)
 	}
 
 	return nil
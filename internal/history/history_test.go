@@ -253,9 +253,9 @@ func TestSaveHistory_AtomicWrite(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify temp file doesn't exist
-	tmpPath := filepath.Join(stateDir, HistoryFileName+".tmp")
-	_, err = os.Stat(tmpPath)
-	assert.True(t, os.IsNotExist(err), "temp file should not exist after atomic write")
+	tmpFiles, err := filepath.Glob(filepath.Join(stateDir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tmpFiles, "temp file should not exist after atomic write")
 
 	// Verify final file exists
 	historyPath := filepath.Join(stateDir, HistoryFileName)
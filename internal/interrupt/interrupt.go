@@ -0,0 +1,164 @@
+// Package interrupt turns SIGINT and SIGTERM into context cancellation.
+//
+// The first signal cancels the context returned by Watch with ErrInterrupted
+// as its cause. The workflow then starts no further agent sessions, and the
+// running agent is asked to stop and given a grace period before it is
+// killed. A second signal ends the grace period at once; if the process has
+// still not exited shortly after, it exits with ExitCode.
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/output"
+)
+
+// ExitCode is the process exit code for an interrupted run (128 + SIGINT).
+const ExitCode = 130
+
+// DefaultGracePeriod is how long a running agent may take to exit after the
+// first signal when cancel_grace_period is not configured.
+const DefaultGracePeriod = 10 * time.Second
+
+// forceExitDelay is how long a second signal waits for an orderly shutdown
+// before exiting the process.
+const forceExitDelay = 3 * time.Second
+
+// ErrInterrupted is the cancellation cause after SIGINT or SIGTERM. It matches
+// context.Canceled and apperrors.ErrInterrupted with errors.Is.
+var ErrInterrupted error = interruptedError{}
+
+type interruptedError struct{}
+
+func (interruptedError) Error() string { return "interrupted" }
+
+func (interruptedError) Is(target error) bool {
+	return target == context.Canceled || target == apperrors.ErrInterrupted
+}
+
+// watcher is the state of an active Watch call.
+type watcher struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	grace  time.Duration
+	exit   func(int)
+
+	once   sync.Once
+	signal int
+	forced chan struct{}
+}
+
+var (
+	mu     sync.Mutex
+	active *watcher
+)
+
+// Watch returns a context that is cancelled with ErrInterrupted on the first
+// SIGINT or SIGTERM, and installs it as the process-wide Context. grace is the
+// time an agent gets to exit before it is killed; 0 uses DefaultGracePeriod.
+// Call stop when the command is done to restore default signal handling.
+func Watch(parent context.Context, grace time.Duration) (ctx context.Context, stop func()) {
+	w := newWatcher(parent, grace)
+	w.exit = os.Exit
+
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				w.handleSignal()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	mu.Lock()
+	active = w
+	mu.Unlock()
+
+	return w.ctx, func() {
+		signal.Stop(sigCh)
+		close(done)
+		mu.Lock()
+		if active == w {
+			active = nil
+		}
+		mu.Unlock()
+		w.cancel(nil)
+	}
+}
+
+func newWatcher(parent context.Context, grace time.Duration) *watcher {
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	ctx, cancel := context.WithCancelCause(parent)
+	return &watcher{ctx: ctx, cancel: cancel, grace: grace, forced: make(chan struct{})}
+}
+
+// handleSignal cancels the run on the first signal and forces shutdown on the second.
+func (w *watcher) handleSignal() {
+	w.signal++
+	switch w.signal {
+	case 1:
+		output.Default().Warn("Interrupted: finishing the current agent session (up to %s); press Ctrl+C again to stop now", w.grace)
+		w.cancel(ErrInterrupted)
+	case 2:
+		output.Default().Warn("Stopping now")
+		w.once.Do(func() { close(w.forced) })
+		time.AfterFunc(forceExitDelay, func() { w.exit(ExitCode) })
+	}
+}
+
+// current returns the active watcher, or nil outside Watch.
+func current() *watcher {
+	mu.Lock()
+	defer mu.Unlock()
+	return active
+}
+
+// Context returns the context of the active Watch call, or
+// context.Background when no command is watching for signals.
+func Context() context.Context {
+	if w := current(); w != nil {
+		return w.ctx
+	}
+	return context.Background()
+}
+
+// Interrupted reports whether ctx was cancelled by a signal.
+func Interrupted(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrInterrupted)
+}
+
+// Requested reports whether the active run has been interrupted.
+func Requested() bool {
+	return Interrupted(Context())
+}
+
+// GracePeriod returns how long an agent may take to exit after the first signal.
+func GracePeriod() time.Duration {
+	if w := current(); w != nil {
+		return w.grace
+	}
+	return DefaultGracePeriod
+}
+
+// Forced returns a channel that is closed on the second signal. Outside Watch
+// it returns nil, which blocks forever in a select.
+func Forced() <-chan struct{} {
+	if w := current(); w != nil {
+		return w.forced
+	}
+	return nil
+}
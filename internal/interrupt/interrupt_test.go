@@ -0,0 +1,84 @@
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrInterrupted(t *testing.T) {
+	t.Parallel()
+
+	err := fmt.Errorf("executing claude: %w", ErrInterrupted)
+	assert.ErrorIs(t, err, ErrInterrupted)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, apperrors.ErrInterrupted)
+	assert.Equal(t, apperrors.CodeInterrupted, apperrors.Classify(err).Code)
+	assert.False(t, errors.Is(context.Canceled, ErrInterrupted))
+}
+
+func TestWatcher_HandleSignal(t *testing.T) {
+	t.Parallel()
+
+	w := newWatcher(context.Background(), 0)
+	exited := make(chan int, 1)
+	w.exit = func(code int) { exited <- code }
+	assert.Equal(t, DefaultGracePeriod, w.grace)
+
+	w.handleSignal()
+	assert.True(t, Interrupted(w.ctx))
+	select {
+	case <-w.forced:
+		t.Fatal("first signal must not force shutdown")
+	default:
+	}
+
+	w.handleSignal()
+	select {
+	case <-w.forced:
+	default:
+		t.Fatal("second signal must force shutdown")
+	}
+	select {
+	case code := <-exited:
+		assert.Equal(t, ExitCode, code)
+	case <-time.After(2 * forceExitDelay):
+		t.Fatal("second signal did not exit the process")
+	}
+}
+
+func TestInterrupted_OtherCancellation(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, Interrupted(ctx))
+
+	ctx, cancelTimeout := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancelTimeout()
+	<-ctx.Done()
+	assert.False(t, Interrupted(ctx))
+}
+
+// TestWatch installs the process-wide watcher, so it is not parallel.
+func TestWatch(t *testing.T) {
+	assert.False(t, Requested())
+	assert.Nil(t, Forced())
+
+	ctx, stop := Watch(context.Background(), 2*time.Second)
+	assert.Equal(t, ctx, Context())
+	assert.Equal(t, 2*time.Second, GracePeriod())
+	assert.NotNil(t, Forced())
+
+	current().handleSignal()
+	assert.True(t, Requested())
+
+	stop()
+	assert.Equal(t, context.Background(), Context())
+	assert.Equal(t, DefaultGracePeriod, GracePeriod())
+}
@@ -0,0 +1,141 @@
+// Package learnings keeps project-specific conventions discovered while
+// implementing specs in .autospec/memory/learnings.yaml, so later plan and
+// implement sessions can follow them.
+package learnings
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath is the learnings file, relative to the project root.
+var DefaultPath = filepath.Join(".autospec", "memory", "learnings.yaml")
+
+// DefaultMaxInPrompt is how many learnings plan and implement prompts include
+// when learnings.max_in_prompt is not set.
+const DefaultMaxInPrompt = 10
+
+// Config configures the learnings memory (config key: learnings).
+type Config struct {
+	// Retrospect runs a retrospection session after each spec completes,
+	// which appends new learnings (default: false)
+	Retrospect bool `koanf:"retrospect" yaml:"retrospect" json:"retrospect"`
+
+	// MaxInPrompt is how many top learnings are added to plan and implement
+	// prompts. 0 leaves prompts unchanged.
+	MaxInPrompt int `koanf:"max_in_prompt" yaml:"max_in_prompt" json:"max_in_prompt"`
+}
+
+// File is the content of learnings.yaml.
+type File struct {
+	Learnings []Learning `yaml:"learnings"`
+}
+
+// Learning is one convention or pitfall discovered while implementing a spec.
+type Learning struct {
+	ID         string `yaml:"id"`                   // L-001, L-002, ...
+	Learning   string `yaml:"learning"`             // the convention, stated as an instruction
+	Category   string `yaml:"category,omitempty"`   // e.g. convention, testing, tooling, pitfall
+	Source     string `yaml:"source,omitempty"`     // spec the learning came from
+	Added      string `yaml:"added,omitempty"`      // date added (YYYY-MM-DD)
+	Reinforced int    `yaml:"reinforced,omitempty"` // later specs that confirmed it
+}
+
+// idPattern matches learning IDs.
+var idPattern = regexp.MustCompile(`^L-\d+$`)
+
+// Load reads the learnings file at path. A missing file yields an empty File.
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// Save writes f to path, creating its directory.
+func Save(path string, f *File) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("serializing %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := fsutil.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
+
+// Validate checks that every learning has a unique L-NNN ID and text.
+func (f *File) Validate() error {
+	var errs []error
+	seen := make(map[string]bool, len(f.Learnings))
+	for i, l := range f.Learnings {
+		field := fmt.Sprintf("learnings[%d]", i)
+		switch {
+		case !idPattern.MatchString(l.ID):
+			errs = append(errs, fmt.Errorf("%s.id: %q must match L-NNN", field, l.ID))
+		case seen[l.ID]:
+			errs = append(errs, fmt.Errorf("%s.id: duplicate id %s", field, l.ID))
+		}
+		seen[l.ID] = true
+		if strings.TrimSpace(l.Learning) == "" {
+			errs = append(errs, fmt.Errorf("%s.learning: must not be empty", field))
+		}
+		if l.Reinforced < 0 {
+			errs = append(errs, fmt.Errorf("%s.reinforced: must not be negative", field))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Top returns up to n learnings, most reinforced first and, among equally
+// reinforced ones, most recently added first.
+func (f *File) Top(n int) []Learning {
+	sorted := make([]Learning, len(f.Learnings))
+	copy(sorted, f.Learnings)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Reinforced != sorted[j].Reinforced {
+			return sorted[i].Reinforced > sorted[j].Reinforced
+		}
+		return sorted[i].Added > sorted[j].Added
+	})
+	if n >= 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// FormatForPrompt renders learnings as a Markdown list for agent prompts.
+func FormatForPrompt(items []Learning) string {
+	var sb strings.Builder
+	for _, l := range items {
+		sb.WriteString("- ")
+		if l.Category != "" {
+			fmt.Fprintf(&sb, "[%s] ", l.Category)
+		}
+		sb.WriteString(strings.TrimSpace(l.Learning))
+		if l.Source != "" {
+			fmt.Fprintf(&sb, " (from %s)", l.Source)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,103 @@
+package learnings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSave(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), ".autospec", "memory", "learnings.yaml")
+
+	f, err := Load(path)
+	require.NoError(t, err)
+	assert.Empty(t, f.Learnings, "missing file is empty")
+
+	f.Learnings = append(f.Learnings, Learning{ID: "L-001", Learning: "Use table-driven tests", Category: "testing", Source: "003-auth", Added: "2025-01-15"})
+	require.NoError(t, Save(path, f))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, f, loaded)
+
+	require.NoError(t, os.WriteFile(path, []byte("learnings: [\n"), 0o644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "parsing")
+}
+
+func TestFile_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		learnings []Learning
+		wantErr   string
+	}{
+		"valid": {
+			learnings: []Learning{{ID: "L-001", Learning: "a"}, {ID: "L-002", Learning: "b", Reinforced: 2}},
+		},
+		"empty file": {},
+		"bad id": {
+			learnings: []Learning{{ID: "1", Learning: "a"}},
+			wantErr:   "learnings[0].id",
+		},
+		"duplicate id": {
+			learnings: []Learning{{ID: "L-001", Learning: "a"}, {ID: "L-001", Learning: "b"}},
+			wantErr:   "learnings[1].id: duplicate id L-001",
+		},
+		"empty text": {
+			learnings: []Learning{{ID: "L-001", Learning: "  "}},
+			wantErr:   "learnings[0].learning",
+		},
+		"negative reinforced": {
+			learnings: []Learning{{ID: "L-001", Learning: "a", Reinforced: -1}},
+			wantErr:   "learnings[0].reinforced",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := (&File{Learnings: tt.learnings}).Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestFile_Top(t *testing.T) {
+	t.Parallel()
+	f := &File{Learnings: []Learning{
+		{ID: "L-001", Added: "2025-01-01"},
+		{ID: "L-002", Added: "2025-01-03"},
+		{ID: "L-003", Added: "2025-01-02", Reinforced: 2},
+		{ID: "L-004", Added: "2025-01-03"},
+	}}
+
+	ids := func(items []Learning) []string {
+		var out []string
+		for _, l := range items {
+			out = append(out, l.ID)
+		}
+		return out
+	}
+	assert.Equal(t, []string{"L-003", "L-002", "L-004", "L-001"}, ids(f.Top(10)))
+	assert.Equal(t, []string{"L-003", "L-002"}, ids(f.Top(2)))
+	assert.Empty(t, f.Top(0))
+	assert.Equal(t, "L-001", f.Learnings[0].ID, "Top does not reorder the file")
+}
+
+func TestFormatForPrompt(t *testing.T) {
+	t.Parallel()
+	got := FormatForPrompt([]Learning{
+		{ID: "L-001", Learning: "Wrap errors with %w ", Category: "convention", Source: "003-auth"},
+		{ID: "L-002", Learning: "Run make gen after editing schemas"},
+	})
+	assert.Equal(t, "- [convention] Wrap errors with %w (from 003-auth)\n- Run make gen after editing schemas\n", got)
+}
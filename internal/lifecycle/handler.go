@@ -31,6 +31,13 @@ type NotificationHandler interface {
 	OnStageComplete(name string, success bool)
 }
 
+// CancelNotifier is implemented by handlers that report commands stopped by
+// SIGINT or SIGTERM separately from completion. *notify.Handler satisfies it.
+type CancelNotifier interface {
+	// OnCancelled is called instead of OnCommandComplete for interrupted commands.
+	OnCancelled(name string, duration time.Duration)
+}
+
 // HistoryLogger defines the interface for command history logging.
 // This interface is satisfied by *history.Writer but defined separately
 // to avoid circular imports between lifecycle and history packages.
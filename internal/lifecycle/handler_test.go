@@ -20,4 +20,5 @@ func TestNotifyHandlerSatisfiesInterface(t *testing.T) {
 	// This assignment will fail to compile if notify.Handler
 	// doesn't satisfy lifecycle.NotificationHandler
 	var _ lifecycle.NotificationHandler = (*notify.Handler)(nil)
+	var _ lifecycle.CancelNotifier = (*notify.Handler)(nil)
 }
@@ -14,6 +14,8 @@ import (
 	"fmt"
 	"os"
 	"time"
+
+	"github.com/ariel-frischer/autospec/internal/interrupt"
 )
 
 // Status constants for history entries.
@@ -35,7 +37,7 @@ func Run(handler NotificationHandler, name string, fn func() error) error {
 	fnErr := fn()
 	duration := time.Since(start)
 
-	notifyCommandComplete(handler, name, fnErr == nil, duration)
+	notifyCommandComplete(handler, name, fnErr, duration)
 
 	return fnErr
 }
@@ -61,7 +63,7 @@ func RunWithHistory(handler NotificationHandler, logger HistoryLogger, name, spe
 	fnErr := fn()
 	duration := time.Since(start)
 
-	notifyCommandComplete(handler, name, fnErr == nil, duration)
+	notifyCommandComplete(handler, name, fnErr, duration)
 	updateHistoryComplete(logger, entryID, fnErr, duration)
 
 	return fnErr
@@ -78,14 +80,14 @@ func RunWithContext(ctx context.Context, handler NotificationHandler, name strin
 	// Check if context is already cancelled
 	if err := ctx.Err(); err != nil {
 		duration := time.Since(start)
-		notifyCommandComplete(handler, name, false, duration)
+		notifyCommandComplete(handler, name, err, duration)
 		return err
 	}
 
 	fnErr := fn(ctx)
 	duration := time.Since(start)
 
-	notifyCommandComplete(handler, name, fnErr == nil, duration)
+	notifyCommandComplete(handler, name, fnErr, duration)
 
 	return fnErr
 }
@@ -111,7 +113,7 @@ func RunWithHistoryContext(ctx context.Context, handler NotificationHandler, log
 	// Check if context is already cancelled
 	if err := ctx.Err(); err != nil {
 		duration := time.Since(start)
-		notifyCommandComplete(handler, name, false, duration)
+		notifyCommandComplete(handler, name, err, duration)
 		updateHistoryComplete(logger, entryID, err, duration)
 		return err
 	}
@@ -119,7 +121,7 @@ func RunWithHistoryContext(ctx context.Context, handler NotificationHandler, log
 	fnErr := fn(ctx)
 	duration := time.Since(start)
 
-	notifyCommandComplete(handler, name, fnErr == nil, duration)
+	notifyCommandComplete(handler, name, fnErr, duration)
 	updateHistoryComplete(logger, entryID, fnErr, duration)
 
 	return fnErr
@@ -137,12 +139,20 @@ func RunStage(handler NotificationHandler, name string, fn func() error) error {
 }
 
 // notifyCommandComplete safely calls OnCommandComplete with panic recovery.
-func notifyCommandComplete(handler NotificationHandler, name string, success bool, duration time.Duration) {
+// Commands stopped by a signal call OnCancelled instead when the handler
+// implements CancelNotifier.
+func notifyCommandComplete(handler NotificationHandler, name string, fnErr error, duration time.Duration) {
 	if handler == nil {
 		return
 	}
 	defer func() { _ = recover() }()
-	handler.OnCommandComplete(name, success, duration)
+	if errors.Is(fnErr, interrupt.ErrInterrupted) {
+		if c, ok := handler.(CancelNotifier); ok {
+			c.OnCancelled(name, duration)
+			return
+		}
+	}
+	handler.OnCommandComplete(name, fnErr == nil, duration)
 }
 
 // notifyStageComplete safely calls OnStageComplete with panic recovery.
@@ -189,6 +199,9 @@ func determineStatusAndCode(fnErr error) (status string, exitCode int) {
 	if fnErr == nil {
 		return StatusCompleted, 0
 	}
+	if errors.Is(fnErr, interrupt.ErrInterrupted) {
+		return StatusCancelled, interrupt.ExitCode
+	}
 	if errors.Is(fnErr, context.Canceled) || errors.Is(fnErr, context.DeadlineExceeded) {
 		return StatusCancelled, 1
 	}
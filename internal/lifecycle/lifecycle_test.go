@@ -7,9 +7,12 @@ package lifecycle
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/ariel-frischer/autospec/internal/interrupt"
 )
 
 // mockHandler records notification calls for testing.
@@ -51,6 +54,18 @@ func (m *mockHandler) OnStageComplete(name string, success bool) {
 	m.stageCalls = append(m.stageCalls, stageCall{name, success})
 }
 
+// cancelHandler is a mockHandler that also reports cancellations.
+type cancelHandler struct {
+	mockHandler
+	cancelled []string
+}
+
+func (c *cancelHandler) OnCancelled(name string, _ time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cancelled = append(c.cancelled, name)
+}
+
 func (m *mockHandler) getCommandCalls() []commandCall {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -666,6 +681,31 @@ func TestRunWithHistoryContext_NilLogger(t *testing.T) {
 	}
 }
 
+func TestRun_Interrupted(t *testing.T) {
+	t.Parallel()
+
+	interrupted := func() error { return fmt.Errorf("agent claude stopped: %w", interrupt.ErrInterrupted) }
+
+	handler := &cancelHandler{}
+	err := Run(handler, "implement", interrupted)
+	if !errors.Is(err, interrupt.ErrInterrupted) {
+		t.Errorf("Run() error = %v, want interrupted", err)
+	}
+	if len(handler.cancelled) != 1 || handler.cancelled[0] != "implement" {
+		t.Errorf("OnCancelled calls = %v, want [implement]", handler.cancelled)
+	}
+	if calls := handler.getCommandCalls(); len(calls) != 0 {
+		t.Errorf("OnCommandComplete called %d times for a cancelled command, want 0", len(calls))
+	}
+
+	// Handlers without OnCancelled still hear about the failed command
+	plain := &mockHandler{}
+	_ = Run(plain, "implement", interrupted)
+	if calls := plain.getCommandCalls(); len(calls) != 1 || calls[0].success {
+		t.Errorf("OnCommandComplete calls = %+v, want one failure", calls)
+	}
+}
+
 func TestDetermineStatusAndCode(t *testing.T) {
 	t.Parallel()
 
@@ -699,6 +739,11 @@ func TestDetermineStatusAndCode(t *testing.T) {
 			wantStatus:   StatusCancelled,
 			wantExitCode: 1,
 		},
+		"interrupted": {
+			err:          fmt.Errorf("stage implement: %w", interrupt.ErrInterrupted),
+			wantStatus:   StatusCancelled,
+			wantExitCode: interrupt.ExitCode,
+		},
 	}
 
 	for name, tt := range tests {
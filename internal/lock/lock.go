@@ -0,0 +1,274 @@
+// Package lock provides per-spec run locks so two autospec processes cannot
+// implement the same spec at the same time.
+//
+// Locks live in <state_dir>/locks/ (not in the spec directory, so they never
+// show up in git status or get auto-committed). A lock file records the owning
+// PID and host; a lock whose process is no longer alive on this host is treated
+// as stale and reclaimed automatically.
+package lock
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LocksDirName is the directory under the state dir holding lock files.
+const LocksDirName = "locks"
+
+// Info is the content of a lock file.
+type Info struct {
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	Command   string    `json:"command"`
+	SpecDir   string    `json:"spec_dir"`
+	StartedAt time.Time `json:"started_at"`
+	RunID     string    `json:"run_id,omitempty"`
+}
+
+// String describes the lock holder for error messages.
+func (i Info) String() string {
+	run := ""
+	if i.RunID != "" {
+		run = ", run " + i.RunID
+	}
+	return fmt.Sprintf("'%s' (pid %d on %s, started %s%s)",
+		i.Command, i.PID, i.Host, i.StartedAt.Format("2006-01-02 15:04:05"), run)
+}
+
+// HeldError is returned by Acquire when another live process holds the lock.
+type HeldError struct {
+	Path   string
+	Holder Info
+}
+
+func (e *HeldError) Error() string {
+	if e.Holder.PID == 0 {
+		// The lock file was unreadable, most likely because its owner is still writing it
+		return fmt.Sprintf("spec %s is locked (lock file %s is being written by another process)", e.Holder.SpecDir, e.Path)
+	}
+	return fmt.Sprintf("spec %s is locked by %s", e.Holder.SpecDir, e.Holder)
+}
+
+// Lock is an acquired spec lock. Call Release when the run finishes.
+type Lock struct {
+	Path string
+	Info Info
+}
+
+// unreadableGrace is how long an unreadable lock file is treated as held.
+// A lock created with O_EXCL is empty until its owner writes it, so only an
+// unreadable file older than this is assumed abandoned and reclaimed.
+const unreadableGrace = 10 * time.Second
+
+// processAlive reports whether a process with the given PID exists.
+// Overridable in tests.
+var processAlive = isProcessAlive
+
+// Path returns the lock file path for a spec directory.
+// The file name combines the spec directory name with a hash of its absolute
+// path so identically named specs in different repositories don't collide.
+func Path(stateDir, specDir string) string {
+	abs, err := filepath.Abs(specDir)
+	if err != nil {
+		abs = specDir
+	}
+	sum := sha256.Sum256([]byte(abs))
+	name := fmt.Sprintf("%s-%s.lock", filepath.Base(abs), hex.EncodeToString(sum[:])[:12])
+	return filepath.Join(stateDir, LocksDirName, name)
+}
+
+// Acquire takes the lock for specDir on behalf of command in run runID (may be empty).
+// A stale lock (owner process gone on this host) is removed and acquisition retried.
+// An unreadable lock is treated as held until it is older than unreadableGrace.
+// Returns *HeldError if a live process holds the lock.
+func Acquire(stateDir, specDir, command, runID string) (*Lock, error) {
+	path := Path(stateDir, specDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating locks directory: %w", err)
+	}
+
+	info := newInfo(specDir, command)
+	info.RunID = runID
+	for attempt := 0; attempt < 3; attempt++ {
+		err := writeExclusive(path, info)
+		if err == nil {
+			return &Lock{Path: path, Info: info}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		seen, err := os.Stat(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue // Released between our create and stat
+			}
+			return nil, fmt.Errorf("reading lock: %w", err)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading lock: %w", err)
+		}
+		holder, parseErr := parseInfo(data)
+		switch {
+		case parseErr != nil && time.Since(seen.ModTime()) < unreadableGrace:
+			return nil, &HeldError{Path: path, Holder: Info{SpecDir: info.SpecDir}}
+		case parseErr == nil && !IsStale(holder):
+			return nil, &HeldError{Path: path, Holder: *holder}
+		}
+		if err := reclaim(path, seen, data); err != nil {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("acquiring lock %s: lock file keeps reappearing", path)
+}
+
+// reclaim removes the stale lock at path, but only if it is still the file
+// that was inspected (seen, with content data). The file is first renamed
+// aside so that a process which reclaimed it concurrently and created a
+// fresh lock never has that fresh lock deleted from under it.
+func reclaim(path string, seen os.FileInfo, data []byte) error {
+	aside := fmt.Sprintf("%s.stale-%d-%d", path, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(path, aside); err != nil {
+		if os.IsNotExist(err) {
+			return nil // Someone else reclaimed it first
+		}
+		return fmt.Errorf("removing stale lock: %w", err)
+	}
+
+	moved, statErr := os.Stat(aside)
+	current, readErr := os.ReadFile(aside)
+	if statErr == nil && readErr == nil && os.SameFile(seen, moved) && bytes.Equal(current, data) {
+		if err := os.Remove(aside); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing stale lock: %w", err)
+		}
+		return nil
+	}
+
+	// We moved a lock that another process created after our inspection:
+	// put it back unless yet another lock has taken its place.
+	if err := os.Link(aside, path); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("restoring lock: %w", err)
+	}
+	if err := os.Remove(aside); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("restoring lock: %w", err)
+	}
+	return nil
+}
+
+// Release removes the lock file if it is still owned by this lock.
+// Safe to call on a nil lock.
+func (l *Lock) Release() error {
+	if l == nil {
+		return nil
+	}
+	current, err := Read(l.Path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("reading lock: %w", err)
+	}
+	if current.PID != l.Info.PID || current.Host != l.Info.Host {
+		// Lock was force-unlocked and taken by another process; leave it alone
+		return nil
+	}
+	if err := os.Remove(l.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing lock: %w", err)
+	}
+	return nil
+}
+
+// ForceUnlock removes the lock for specDir regardless of its owner.
+// Returns the previous holder, or nil if the spec was not locked.
+func ForceUnlock(stateDir, specDir string) (*Info, error) {
+	path := Path(stateDir, specDir)
+	holder, _ := Read(path) // Best effort: an unreadable lock is still removed
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("removing lock: %w", err)
+	}
+	return holder, nil
+}
+
+// Read parses a lock file.
+func Read(path string) (*Info, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseInfo(data)
+}
+
+// parseInfo decodes lock file content.
+func parseInfo(data []byte) (*Info, error) {
+	var info Info
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parsing lock file: %w", err)
+	}
+	return &info, nil
+}
+
+// IsStale reports whether the lock holder is a dead process on this host.
+// Locks held from another host cannot be checked and are never considered stale.
+func IsStale(info *Info) bool {
+	if info.Host != hostname() {
+		return false
+	}
+	return !processAlive(info.PID)
+}
+
+// newInfo builds lock info for the current process.
+func newInfo(specDir, command string) Info {
+	abs, err := filepath.Abs(specDir)
+	if err != nil {
+		abs = specDir
+	}
+	return Info{
+		PID:       os.Getpid(),
+		Host:      hostname(),
+		Command:   strings.TrimSpace(command),
+		SpecDir:   abs,
+		StartedAt: time.Now(),
+	}
+}
+
+// writeExclusive creates path with O_EXCL so only one process can win.
+func writeExclusive(path string, info Info) error {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling lock: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(path)
+		return fmt.Errorf("writing lock: %w", err)
+	}
+	return f.Close()
+}
+
+// hostname returns the local host name, or "unknown" if it cannot be determined.
+func hostname() string {
+	h, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return h
+}
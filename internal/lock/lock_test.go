@@ -0,0 +1,211 @@
+package lock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath_DistinctForSameNameInDifferentRepos(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	a := Path(stateDir, filepath.Join(t.TempDir(), "specs", "001-auth"))
+	b := Path(stateDir, filepath.Join(t.TempDir(), "specs", "001-auth"))
+
+	assert.NotEqual(t, a, b)
+	assert.Contains(t, filepath.Base(a), "001-auth-")
+	assert.Equal(t, filepath.Join(stateDir, LocksDirName), filepath.Dir(a))
+}
+
+func TestAcquire_RefusesLiveHolder(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	specDir := filepath.Join(t.TempDir(), "001-auth")
+
+	first, err := Acquire(stateDir, specDir, "implement", "brave_fox_20250115_140000")
+	require.NoError(t, err)
+
+	// The current process is alive, so a second acquisition must fail
+	_, err = Acquire(stateDir, specDir, "implement", "")
+	var held *HeldError
+	require.True(t, errors.As(err, &held), "want *HeldError, got %v", err)
+	assert.Equal(t, os.Getpid(), held.Holder.PID)
+	assert.Contains(t, err.Error(), "locked by 'implement'")
+	assert.Contains(t, err.Error(), "run brave_fox_20250115_140000)")
+
+	require.NoError(t, first.Release())
+	second, err := Acquire(stateDir, specDir, "implement", "")
+	require.NoError(t, err)
+	require.NoError(t, second.Release())
+}
+
+func TestAcquire_ReclaimsStaleLock(t *testing.T) {
+	// Not parallel: overrides processAlive
+
+	stateDir := t.TempDir()
+	specDir := filepath.Join(t.TempDir(), "001-auth")
+	path := Path(stateDir, specDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, writeExclusive(path, Info{
+		PID:       999999,
+		Host:      hostname(),
+		Command:   "implement",
+		StartedAt: time.Now().Add(-time.Hour),
+	}))
+
+	orig := processAlive
+	processAlive = func(pid int) bool { return pid != 999999 }
+	t.Cleanup(func() { processAlive = orig })
+
+	lk, err := Acquire(stateDir, specDir, "implement", "")
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), lk.Info.PID)
+	require.NoError(t, lk.Release())
+}
+
+func TestAcquire_ReclaimsCorruptLock(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	specDir := filepath.Join(t.TempDir(), "001-auth")
+	path := Path(stateDir, specDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+	old := time.Now().Add(-2 * unreadableGrace)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	lk, err := Acquire(stateDir, specDir, "implement", "")
+	require.NoError(t, err)
+	require.NoError(t, lk.Release())
+}
+
+func TestAcquire_FreshUnreadableLockIsHeld(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	specDir := filepath.Join(t.TempDir(), "001-auth")
+	path := Path(stateDir, specDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	// An O_EXCL-created lock is empty until its owner writes it
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+
+	_, err := Acquire(stateDir, specDir, "implement", "")
+	var held *HeldError
+	require.True(t, errors.As(err, &held), "want *HeldError, got %v", err)
+	assert.Contains(t, err.Error(), "being written by another process")
+	assert.FileExists(t, path, "a lock that may still be being written must not be removed")
+}
+
+func TestAcquire_ConcurrentReclaim(t *testing.T) {
+	// Not parallel: overrides processAlive
+
+	stateDir := t.TempDir()
+	specDir := filepath.Join(t.TempDir(), "001-auth")
+	path := Path(stateDir, specDir)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, writeExclusive(path, Info{PID: 999999, Host: hostname(), Command: "implement"}))
+
+	orig := processAlive
+	processAlive = func(pid int) bool { return pid != 999999 }
+	t.Cleanup(func() { processAlive = orig })
+
+	const workers = 8
+	var (
+		wg      sync.WaitGroup
+		start   = make(chan struct{})
+		results = make(chan error, workers)
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := Acquire(stateDir, specDir, "implement", "")
+			results <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(results)
+
+	acquired := 0
+	for err := range results {
+		if err == nil {
+			acquired++
+			continue
+		}
+		var held *HeldError
+		assert.True(t, errors.As(err, &held), "want *HeldError, got %v", err)
+	}
+	assert.Equal(t, 1, acquired, "exactly one process must win a stale lock")
+
+	holder, err := Read(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.Getpid(), holder.PID)
+
+	leftovers, err := filepath.Glob(path + ".stale-*")
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+func TestIsStale_OtherHostNeverStale(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, IsStale(&Info{PID: 999999, Host: "some-other-host.invalid"}))
+}
+
+func TestRelease_LeavesLockTakenByOthers(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	specDir := filepath.Join(t.TempDir(), "001-auth")
+
+	lk, err := Acquire(stateDir, specDir, "implement", "")
+	require.NoError(t, err)
+
+	// Another process force-unlocked and took over
+	_, err = ForceUnlock(stateDir, specDir)
+	require.NoError(t, err)
+	require.NoError(t, writeExclusive(lk.Path, Info{PID: os.Getpid() + 1, Host: hostname()}))
+
+	require.NoError(t, lk.Release())
+	_, err = os.Stat(lk.Path)
+	assert.NoError(t, err, "lock owned by another process must not be removed")
+}
+
+func TestForceUnlock(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	specDir := filepath.Join(t.TempDir(), "001-auth")
+
+	holder, err := ForceUnlock(stateDir, specDir)
+	require.NoError(t, err)
+	assert.Nil(t, holder, "unlocking an unlocked spec is a no-op")
+
+	_, err = Acquire(stateDir, specDir, "run", "")
+	require.NoError(t, err)
+
+	holder, err = ForceUnlock(stateDir, specDir)
+	require.NoError(t, err)
+	require.NotNil(t, holder)
+	assert.Equal(t, "run", holder.Command)
+
+	_, err = os.Stat(Path(stateDir, specDir))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestNilLockRelease(t *testing.T) {
+	t.Parallel()
+
+	var lk *Lock
+	assert.NoError(t, lk.Release())
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package lock
+
+import "os"
+
+// isProcessAlive checks for process existence; on Windows FindProcess opens a
+// handle to the process and fails if it does not exist.
+func isProcessAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
@@ -0,0 +1,183 @@
+// Package logging configures structured slog logging for autospec.
+//
+// Log records go to stderr at the configured level and, when enabled, to a
+// rotating log file in the state directory. The file always records debug
+// detail so it can be attached to support requests without rerunning with
+// --debug. Components log through For, which tags records with a component
+// attribute. With encryption enabled, each line of the file is encrypted.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+)
+
+// Log file location and rotation defaults.
+const (
+	// DirName is the directory under state_dir holding log files.
+	DirName = "logs"
+	// FileName is the name of the active log file.
+	FileName = "autospec.log"
+	// DefaultMaxSizeMB is the size at which the log file is rotated.
+	DefaultMaxSizeMB = 10
+	// DefaultMaxFiles is the number of rotated log files kept.
+	DefaultMaxFiles = 3
+)
+
+// Config holds logging preferences loaded from the logging config section.
+type Config struct {
+	// Level is the minimum console level: debug, info, warn or error (default: info).
+	Level string `koanf:"level" yaml:"level" json:"level"`
+
+	// Format is the record format: text or json (default: text).
+	Format string `koanf:"format" yaml:"format" json:"format"`
+
+	// File enables <state_dir>/logs/autospec.log (default: false).
+	File bool `koanf:"file" yaml:"file" json:"file"`
+
+	// MaxSizeMB rotates the log file when it would grow past this size (default: 10).
+	MaxSizeMB int `koanf:"max_size_mb" yaml:"max_size_mb" json:"max_size_mb"`
+
+	// MaxFiles is how many rotated files (autospec.log.1, .2, ...) are kept (default: 3).
+	MaxFiles int `koanf:"max_files" yaml:"max_files" json:"max_files"`
+
+	// Cipher encrypts each line of the log file (nil: plain text). Set from the
+	// encryption config before Setup.
+	Cipher *encrypt.Cipher `koanf:"-" yaml:"-" json:"-"`
+}
+
+// ParseLevel parses a level name. An empty name is info.
+func ParseLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("invalid log level %q: must be one of debug, info, warn, error", name)
+	}
+}
+
+// ValidateFormat reports whether format is a supported record format.
+func ValidateFormat(format string) error {
+	switch format {
+	case "", "text", "json":
+		return nil
+	default:
+		return fmt.Errorf("invalid log format %q: must be text or json", format)
+	}
+}
+
+// FilePath returns the active log file path for a state directory.
+func FilePath(stateDir string) string {
+	return filepath.Join(stateDir, DirName, FileName)
+}
+
+// Setup builds the logger described by cfg, installs it as the slog default
+// and returns a function that closes the log file. Console records go to
+// console; the file sink is opened in stateDir when cfg.File is set.
+func Setup(cfg Config, console io.Writer, stateDir string) (func() error, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateFormat(cfg.Format); err != nil {
+		return nil, err
+	}
+
+	handlers := []slog.Handler{newHandler(console, cfg.Format, level)}
+	closeFn := func() error { return nil }
+
+	if cfg.File {
+		maxSize := cfg.MaxSizeMB
+		if maxSize <= 0 {
+			maxSize = DefaultMaxSizeMB
+		}
+		maxFiles := cfg.MaxFiles
+		if maxFiles < 0 {
+			maxFiles = DefaultMaxFiles
+		}
+		file, err := OpenRotatingFile(FilePath(stateDir), int64(maxSize)<<20, maxFiles)
+		if err != nil {
+			return nil, fmt.Errorf("opening log file: %w", err)
+		}
+		handlers = append(handlers, newHandler(cfg.Cipher.LineWriter(file), cfg.Format, slog.LevelDebug))
+		closeFn = file.Close
+	}
+
+	slog.SetDefault(slog.New(fanout(handlers)))
+	consoleDebug.Store(level <= slog.LevelDebug)
+	return closeFn, nil
+}
+
+// consoleDebug records whether the console level set up last is debug.
+var consoleDebug atomic.Bool
+
+// DebugEnabled reports whether the console shows debug records, as set by
+// --debug, --log-level or logging.level.
+func DebugEnabled() bool {
+	return consoleDebug.Load()
+}
+
+// For returns the default logger tagged with a component attribute.
+func For(component string) *slog.Logger {
+	return slog.Default().With("component", component)
+}
+
+func newHandler(w io.Writer, format string, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if format == "json" {
+		return slog.NewJSONHandler(w, opts)
+	}
+	return slog.NewTextHandler(w, opts)
+}
+
+// fanout sends each record to every handler enabled for its level.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	out := make(fanout, len(f))
+	for i, h := range f {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
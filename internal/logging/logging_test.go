@@ -0,0 +1,162 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLevel(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name    string
+		want    slog.Level
+		wantErr bool
+	}{
+		"empty is info": {name: "", want: slog.LevelInfo},
+		"debug":         {name: "debug", want: slog.LevelDebug},
+		"case ignored":  {name: "WARN", want: slog.LevelWarn},
+		"warning alias": {name: "warning", want: slog.LevelWarn},
+		"error":         {name: "error", want: slog.LevelError},
+		"invalid":       {name: "trace", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseLevel(tt.name)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+// TestSetup replaces the process-wide slog default, so it is not parallel.
+func TestSetup(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	t.Run("console level filters records", func(t *testing.T) {
+		var console bytes.Buffer
+		closeFn, err := Setup(Config{Level: "warn"}, &console, t.TempDir())
+		require.NoError(t, err)
+		defer closeFn()
+
+		For("executor").Info("hidden")
+		For("executor").Warn("shown", "stage", "plan")
+		out := console.String()
+		assert.NotContains(t, out, "hidden")
+		assert.Contains(t, out, "level=WARN msg=shown component=executor stage=plan")
+	})
+
+	t.Run("debug level enables debug mode", func(t *testing.T) {
+		_, err := Setup(Config{Level: "debug"}, &bytes.Buffer{}, t.TempDir())
+		require.NoError(t, err)
+		assert.True(t, DebugEnabled())
+		closeFn, err := Setup(Config{Level: "info", File: true}, &bytes.Buffer{}, t.TempDir())
+		require.NoError(t, err)
+		defer closeFn()
+		assert.False(t, DebugEnabled(), "the file sink's debug level is not the console's")
+	})
+
+	t.Run("file records debug detail as json", func(t *testing.T) {
+		var console bytes.Buffer
+		stateDir := t.TempDir()
+		closeFn, err := Setup(Config{Level: "error", Format: "json", File: true}, &console, stateDir)
+		require.NoError(t, err)
+
+		For("task_executor").Debug("task started", "task", "T001")
+		require.NoError(t, closeFn())
+
+		assert.Empty(t, console.String())
+		data, err := os.ReadFile(FilePath(stateDir))
+		require.NoError(t, err)
+		var record map[string]any
+		require.NoError(t, json.Unmarshal(data, &record))
+		assert.Equal(t, "DEBUG", record["level"])
+		assert.Equal(t, "task_executor", record["component"])
+		assert.Equal(t, "T001", record["task"])
+	})
+
+	t.Run("file lines are encrypted with a cipher", func(t *testing.T) {
+		stateDir := t.TempDir()
+		cipher, err := encrypt.NewCipher(bytes.Repeat([]byte{1}, encrypt.KeySize))
+		require.NoError(t, err)
+		closeFn, err := Setup(Config{Level: "error", File: true, Cipher: cipher}, &bytes.Buffer{}, stateDir)
+		require.NoError(t, err)
+
+		For("executor").Debug("proprietary detail")
+		require.NoError(t, closeFn())
+
+		data, err := os.ReadFile(FilePath(stateDir))
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "proprietary detail")
+		plain, err := cipher.OpenLines(data)
+		require.NoError(t, err)
+		assert.Contains(t, string(plain), "msg=\"proprietary detail\"")
+	})
+
+	t.Run("invalid settings", func(t *testing.T) {
+		_, err := Setup(Config{Level: "loud"}, &bytes.Buffer{}, t.TempDir())
+		assert.ErrorContains(t, err, "invalid log level")
+		_, err = Setup(Config{Format: "xml"}, &bytes.Buffer{}, t.TempDir())
+		assert.ErrorContains(t, err, "invalid log format")
+	})
+}
+
+func TestRotatingFile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		maxFiles  int
+		wantFiles []string
+	}{
+		"keeps rotated files": {maxFiles: 2, wantFiles: []string{"autospec.log", "autospec.log.1", "autospec.log.2"}},
+		"truncates with zero": {maxFiles: 0, wantFiles: []string{"autospec.log"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "logs", FileName)
+			r, err := OpenRotatingFile(path, 10, tt.maxFiles)
+			require.NoError(t, err)
+
+			for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+				_, err := r.Write([]byte(line))
+				require.NoError(t, err)
+			}
+			require.NoError(t, r.Close())
+
+			entries, err := os.ReadDir(filepath.Dir(path))
+			require.NoError(t, err)
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			assert.Equal(t, tt.wantFiles, names)
+
+			current, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, "fourth\n", string(current))
+			if tt.maxFiles > 0 {
+				oldest, err := os.ReadFile(path + ".2")
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(string(oldest), "second"))
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+// Package metrics exposes workflow metrics in the Prometheus text format for
+// `autospec daemon` and `autospec serve`.
+//
+// Metrics are derived from the run event logs in the state directory
+// (<state_dir>/runs/<run-id>/events.jsonl), so runs started from any terminal,
+// CI job or the daemon are all counted. Each scrape reads only the logs that
+// changed since the previous one. Counters start at zero when the server
+// starts; history recorded before then is not counted, which keeps rate()
+// and increase() meaningful across restarts.
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/events"
+)
+
+// DurationBuckets are the stage duration histogram bounds in seconds,
+// from quick stages up to long implement runs.
+var DurationBuckets = []float64{10, 30, 60, 120, 300, 600, 1200, 1800, 3600, 7200}
+
+// failureCategories maps failure events to the category label of
+// autospec_failures_total.
+var failureCategories = map[events.Type]string{
+	events.TypeExecutionFailed:  "execution",
+	events.TypeValidationFailed: "validation",
+	events.TypeRetriesExhausted: "retries_exhausted",
+	events.TypeBudgetExceeded:   "budget_exceeded",
+	events.TypeTaskTimedOut:     "task_timeout",
+}
+
+// stageOutcomes maps events that end a stage to the outcome label of
+// autospec_stage_duration_seconds.
+var stageOutcomes = map[events.Type]string{
+	events.TypeStageComplete:    "completed",
+	events.TypeRetriesExhausted: "failed",
+	events.TypeBudgetExceeded:   "budget_exceeded",
+	events.TypeCancelled:        "cancelled",
+}
+
+// Collector turns run event logs into Prometheus metrics.
+type Collector struct {
+	StateDir string
+	Version  string
+
+	mu      sync.Mutex
+	started time.Time
+	runs    map[string]*runState
+
+	runsStarted    *counterVec
+	stagesStarted  *counterVec
+	retries        *counterVec
+	failures       *counterVec
+	stageDurations *histogramVec
+}
+
+// runState tracks how far a run's event log has been read.
+type runState struct {
+	modTime time.Time
+	seen    int                  // Events already processed
+	open    map[string]time.Time // "spec\x00stage" -> start of the running stage
+}
+
+// NewCollector creates a collector for stateDir. Existing event logs are read
+// once so stages already running are timed, without counting past events.
+func NewCollector(stateDir, version string) *Collector {
+	c := &Collector{
+		StateDir:       stateDir,
+		Version:        version,
+		started:        time.Now(),
+		runs:           map[string]*runState{},
+		runsStarted:    newCounterVec("autospec_runs_started_total", "Workflow runs started."),
+		stagesStarted:  newCounterVec("autospec_stages_started_total", "Stage executions started.", "stage"),
+		retries:        newCounterVec("autospec_retries_total", "Stage and task retries.", "stage"),
+		failures:       newCounterVec("autospec_failures_total", "Failures by stage and category.", "stage", "category"),
+		stageDurations: newHistogramVec("autospec_stage_duration_seconds", "Stage execution time by outcome.", DurationBuckets, "stage", "outcome"),
+	}
+	// Export the run counter at zero so alerts see it before the first run
+	c.runsStarted.values[labelKey(nil)] = 0
+	c.scan(false)
+	return c
+}
+
+// Handler serves the metrics, reading new events on each request.
+func (c *Collector) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w.Write(c.Gather())
+	})
+}
+
+// Gather reads new events and returns all metrics in the text format.
+func (c *Collector) Gather() []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.scan(true)
+
+	var buf bytes.Buffer
+	buf.WriteString("# HELP autospec_build_info autospec version serving the metrics.\n# TYPE autospec_build_info gauge\n")
+	buf.WriteString("autospec_build_info" + formatLabels([]string{"version"}, c.Version, "") + " 1\n")
+	c.runsStarted.write(&buf)
+	c.stagesStarted.write(&buf)
+	c.retries.write(&buf)
+	c.failures.write(&buf)
+	c.stageDurations.write(&buf)
+	return buf.Bytes()
+}
+
+// scan processes events appended since the last scan. With count false it
+// only advances read positions and stage start times.
+func (c *Collector) scan(count bool) {
+	runs, err := events.ListRuns(c.StateDir)
+	if err != nil {
+		return
+	}
+
+	listed := make(map[string]bool, len(runs))
+	for _, run := range runs {
+		listed[run.RunID] = true
+		st := c.runs[run.RunID]
+		if st != nil && run.ModTime.Equal(st.modTime) {
+			continue
+		}
+		evs, err := events.Load(c.StateDir, run.RunID)
+		if err != nil && !encrypt.Partial(err) {
+			continue
+		}
+		countRun := count
+		if st == nil {
+			st = &runState{open: map[string]time.Time{}}
+			c.runs[run.RunID] = st
+			// Logs restored by `autospec import` keep their old mtime; they
+			// are history, not new activity
+			countRun = count && run.ModTime.After(c.started)
+			if countRun {
+				c.runsStarted.inc()
+			}
+		}
+		if st.seen > len(evs) {
+			st.seen = len(evs) // The log was truncated or replaced
+		}
+		for _, ev := range evs[st.seen:] {
+			c.observe(st, ev, countRun)
+		}
+		st.seen = len(evs)
+		st.modTime = run.ModTime
+	}
+
+	// Forget runs removed by `autospec clean`
+	for id := range c.runs {
+		if !listed[id] {
+			delete(c.runs, id)
+		}
+	}
+}
+
+func (c *Collector) observe(st *runState, ev events.Event, count bool) {
+	key := ev.Spec + "\x00" + ev.Stage
+	if ev.Type == events.TypeStageStart {
+		if _, running := st.open[key]; !running {
+			st.open[key] = ev.Time
+			if count {
+				c.stagesStarted.inc(ev.Stage)
+			}
+		}
+		return
+	}
+	if count {
+		if ev.Type == events.TypeRetry {
+			c.retries.inc(ev.Stage)
+		}
+		if category, ok := failureCategories[ev.Type]; ok {
+			c.failures.inc(ev.Stage, category)
+		}
+	}
+	if outcome, ok := stageOutcomes[ev.Type]; ok {
+		if start, running := st.open[key]; running {
+			if count {
+				c.stageDurations.observe(ev.Time.Sub(start).Seconds(), ev.Stage, outcome)
+			}
+			delete(st.open, key)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector_CountsNewEventsOnly(t *testing.T) {
+	t.Parallel()
+	stateDir := t.TempDir()
+	start := time.Now().Add(-time.Hour)
+
+	// History before the collector starts: one finished run and one run
+	// whose plan stage is still going
+	old := events.NewRecorder(stateDir, "old_run")
+	old.Record(events.Event{Time: start, Type: events.TypeStageStart, Spec: "001-a", Stage: "specify"})
+	old.Record(events.Event{Time: start.Add(time.Minute), Type: events.TypeStageComplete, Spec: "001-a", Stage: "specify"})
+	live := events.NewRecorder(stateDir, "live_run")
+	live.Record(events.Event{Time: start, Type: events.TypeStageStart, Spec: "002-b", Stage: "plan"})
+
+	c := NewCollector(stateDir, "v1.0.0")
+	out := string(c.Gather())
+	assert.Contains(t, out, `autospec_build_info{version="v1.0.0"} 1`)
+	assert.Contains(t, out, "autospec_runs_started_total 0")
+	assert.NotContains(t, out, `stage="specify"`, "history before startup is not counted")
+
+	// The live run retries, then finishes
+	live.Record(events.Event{Time: start.Add(time.Minute), Type: events.TypeValidationFailed, Spec: "002-b", Stage: "plan"})
+	live.Record(events.Event{Time: start.Add(time.Minute), Type: events.TypeRetry, Spec: "002-b", Stage: "plan", Attempt: 2})
+	live.Record(events.Event{Time: start.Add(45 * time.Second), Type: events.TypeStageStart, Spec: "002-b", Stage: "plan", Attempt: 2})
+	live.Record(events.Event{Time: start.Add(5 * time.Minute), Type: events.TypeStageComplete, Spec: "002-b", Stage: "plan"})
+
+	// A new run fails
+	fresh := events.NewRecorder(stateDir, "fresh_run")
+	fresh.Record(events.Event{Time: start, Type: events.TypeStageStart, Spec: "003-c", Stage: "implement"})
+	fresh.Record(events.Event{Time: start.Add(time.Second), Type: events.TypeExecutionFailed, Spec: "003-c", Stage: "implement"})
+	fresh.Record(events.Event{Time: start.Add(20 * time.Second), Type: events.TypeRetriesExhausted, Spec: "003-c", Stage: "implement"})
+
+	out = string(c.Gather())
+	assert.Contains(t, out, "autospec_runs_started_total 1")
+	assert.Contains(t, out, `autospec_stages_started_total{stage="implement"} 1`)
+	assert.NotContains(t, out, `autospec_stages_started_total{stage="plan"}`, "a retried stage is one execution")
+	assert.Contains(t, out, `autospec_retries_total{stage="plan"} 1`)
+	assert.Contains(t, out, `autospec_failures_total{stage="plan",category="validation"} 1`)
+	assert.Contains(t, out, `autospec_failures_total{stage="implement",category="execution"} 1`)
+	assert.Contains(t, out, `autospec_failures_total{stage="implement",category="retries_exhausted"} 1`)
+	assert.Contains(t, out, `autospec_stage_duration_seconds_bucket{stage="plan",outcome="completed",le="300"} 1`)
+	assert.Contains(t, out, `autospec_stage_duration_seconds_bucket{stage="plan",outcome="completed",le="120"} 0`)
+	assert.Contains(t, out, `autospec_stage_duration_seconds_sum{stage="plan",outcome="completed"} 300`)
+	assert.Contains(t, out, `autospec_stage_duration_seconds_bucket{stage="implement",outcome="failed",le="+Inf"} 1`)
+
+	// Scraping again without new events changes nothing
+	assert.Equal(t, out, string(c.Gather()))
+}
+
+func TestCollector_SkipsImportedRuns(t *testing.T) {
+	t.Parallel()
+	stateDir := t.TempDir()
+	c := NewCollector(stateDir, "dev")
+
+	events.NewRecorder(stateDir, "imported").Record(events.Event{Type: events.TypeRetry, Spec: "001-a", Stage: "plan"})
+	past := time.Now().Add(-24 * time.Hour)
+	require.NoError(t, os.Chtimes(events.Path(stateDir, "imported"), past, past))
+
+	out := string(c.Gather())
+	assert.Contains(t, out, "autospec_runs_started_total 0")
+	assert.NotContains(t, out, "autospec_retries_total{")
+}
+
+func TestCollector_Handler(t *testing.T) {
+	t.Parallel()
+	rec := httptest.NewRecorder()
+	NewCollector(t.TempDir(), "dev").Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	assert.Equal(t, 200, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "version=0.0.4")
+	assert.Contains(t, rec.Body.String(), "# TYPE autospec_stage_duration_seconds histogram")
+}
+
+func TestFormatLabels_Escapes(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, `{stage="a\"b\\c\nd",le="+Inf"}`, formatLabels([]string{"stage"}, "a\"b\\c\nd", "+Inf"))
+	assert.Equal(t, `{a="x",b="y"}`, formatLabels([]string{"a", "b"}, labelKey([]string{"x", "y"}), ""))
+	assert.Equal(t, "", formatLabels(nil, "", ""))
+}
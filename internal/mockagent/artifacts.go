@@ -0,0 +1,237 @@
+package mockagent
+
+// Artifacts written by the mock agent, one per stage.
+const (
+	specYAML = `feature:
+  branch: "001-test-feature"
+  created: "2025-01-01"
+  status: "Draft"
+  input: "test feature"
+user_stories:
+  - id: "US-001"
+    title: "Test"
+    priority: "P1"
+    as_a: "developer"
+    i_want: "to test"
+    so_that: "it works"
+    why_this_priority: "required"
+    independent_test: "run test"
+    acceptance_scenarios:
+      - given: "setup"
+        when: "action"
+        then: "result"
+requirements:
+  functional:
+    - id: "FR-001"
+      description: "test feature"
+      testable: true
+      acceptance_criteria: "test passes"
+  non_functional:
+    - id: "NFR-001"
+      category: "code_quality"
+      description: "quality"
+      measurable_target: "target"
+success_criteria:
+  measurable_outcomes:
+    - id: "SC-001"
+      description: "success"
+      metric: "metric"
+      target: "target"
+key_entities: []
+edge_cases: []
+assumptions: []
+constraints: []
+out_of_scope: []
+_meta:
+  version: "1.0.0"
+  generator: "autospec"
+  generator_version: "test"
+  created: "2025-01-01T00:00:00Z"
+  artifact_type: "spec"
+`
+
+	planYAML = `plan:
+  branch: "001-test-feature"
+  created: "2025-01-01"
+  spec_path: "specs/001-test-feature/spec.yaml"
+summary: "Test plan"
+technical_context:
+  language: "Go"
+  framework: "None"
+  primary_dependencies: []
+  storage: "None"
+  testing:
+    framework: "Go testing"
+    approach: "Unit tests"
+  target_platform: "Linux"
+  project_type: "cli"
+  performance_goals: "Fast"
+  constraints: []
+  scale_scope: "Small"
+constitution_check:
+  constitution_path: ".autospec/memory/constitution.yaml"
+  gates: []
+research_findings:
+  decisions: []
+data_model:
+  entities: []
+api_contracts:
+  endpoints: []
+project_structure:
+  documentation: []
+  source_code: []
+  tests: []
+implementation_phases:
+  - phase: 1
+    name: "Test"
+    goal: "Test"
+    deliverables: []
+risks: []
+open_questions: []
+_meta:
+  version: "1.0.0"
+  generator: "autospec"
+  generator_version: "test"
+  created: "2025-01-01T00:00:00Z"
+  artifact_type: "plan"
+`
+
+	tasksYAML = `tasks:
+  branch: "001-test-feature"
+  created: "2025-01-01"
+  spec_path: "specs/001-test-feature/spec.yaml"
+  plan_path: "specs/001-test-feature/plan.yaml"
+summary:
+  total_tasks: 1
+  total_phases: 1
+  parallel_opportunities: 0
+  estimated_complexity: "low"
+phases:
+  - number: 1
+    title: "Test"
+    purpose: "Test"
+    tasks:
+      - id: "T001"
+        title: "Test task"
+        status: "Pending"
+        type: "implementation"
+        parallel: false
+        story_id: "US-001"
+        file_path: "test.go"
+        dependencies: []
+        acceptance_criteria:
+          - "Test passes"
+dependencies:
+  user_story_order: []
+  phase_order: []
+parallel_execution: []
+implementation_strategy:
+  mvp_scope:
+    phases: [1]
+    description: "MVP"
+    validation: "Tests pass"
+  incremental_delivery: []
+_meta:
+  version: "1.0.0"
+  generator: "autospec"
+  generator_version: "test"
+  created: "2025-01-01T00:00:00Z"
+  artifact_type: "tasks"
+`
+
+	constitutionYAML = `constitution:
+  project_name: "test-project"
+  version: "1.0.0"
+  ratified: "2025-01-01"
+  last_amended: "2025-01-01"
+
+preamble: "Test project constitution for automated testing."
+
+principles:
+  - name: "Test-First Development"
+    id: "PRIN-001"
+    category: "quality"
+    priority: "NON-NEGOTIABLE"
+    description: "All new code must have tests."
+    rationale: "Ensures code quality"
+    enforcement:
+      - mechanism: "CI"
+        description: "Tests run on commit"
+    exceptions: []
+
+sections:
+  - name: "Code Quality"
+    content: "All code must pass linting."
+
+governance:
+  amendment_process:
+    - step: 1
+      action: "Propose"
+      requirements: "Include rationale"
+  versioning_policy: "Semantic versioning"
+  compliance_review:
+    frequency: "quarterly"
+    process: "Review"
+  rules:
+    - "Changes require review"
+
+sync_impact:
+  version_change: "1.0.0 -> 1.0.0"
+  modified_principles: []
+  added_sections: []
+  removed_sections: []
+  templates_requiring_updates: []
+  follow_up_todos: []
+
+_meta:
+  version: "1.0.0"
+  generator: "autospec"
+  generator_version: "test"
+  created: "2025-01-01T00:00:00Z"
+  artifact_type: "constitution"
+`
+
+	checklistYAML = `checklist:
+  feature: "Test Feature"
+  branch: "001-test-feature"
+  domain: "general"
+
+categories:
+  - name: "Requirement Completeness"
+    items:
+      - id: "CHK001"
+        description: "Are all functional requirements testable?"
+        status: "pending"
+
+_meta:
+  version: "1.0.0"
+  generator: "autospec"
+  generator_version: "test"
+  created: "2025-01-01T00:00:00Z"
+  artifact_type: "checklist"
+`
+
+	analysisYAML = `analysis:
+  branch: "001-test-feature"
+  timestamp: "2025-01-01T00:00:00Z"
+
+findings:
+  - id: "AMB-001"
+    category: "ambiguity"
+    severity: "LOW"
+    artifact: "spec"
+    field: "requirements.functional[0]"
+    summary: "Requirement wording could be more precise"
+    recommendation: "Add a measurable acceptance threshold"
+
+summary:
+  overall_status: "PASS"
+
+_meta:
+  version: "1.0.0"
+  generator: "autospec"
+  generator_version: "test"
+  created: "2025-01-01T00:00:00Z"
+  artifact_type: "analysis"
+`
+)
@@ -0,0 +1,180 @@
+// Package mockagent is autospec's built-in mock agent: a stand-in for an AI
+// CLI that writes fixed, schema-valid artifacts for each stage without
+// network access. It runs as the hidden 'autospec __mockagent' command, for
+// 'autospec demo' and users trying the workflow, and inside test binaries
+// that call Main from TestMain, on every platform.
+//
+// The mock is configured through environment variables:
+//
+//	MOCK_RESPONSE_FILE  file copied to stdout
+//	MOCK_CALL_LOG       file each call is appended to, as YAML
+//	MOCK_EXIT_CODE      exit code (default 0)
+//	MOCK_DELAY          seconds to wait before responding (default 0)
+//	MOCK_ARTIFACT_DIR   specs directory artifacts are written to
+//	MOCK_SPEC_NAME      spec directory name (default 001-test-feature)
+//
+// Related: internal/cli/mockagent.go, internal/cli/demo.go, internal/workflow/main_test.go
+// Tags: mocks, testing, demo, agent
+package mockagent
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+)
+
+// CommandName is the hidden autospec command that runs the mock agent.
+const CommandName = "__mockagent"
+
+// EnvVar, set to "1", makes a test binary run as the mock agent.
+const EnvVar = "AUTOSPEC_MOCK_AGENT"
+
+// defaultSpecName is the spec artifacts are written to without MOCK_SPEC_NAME.
+const defaultSpecName = "001-test-feature"
+
+// Main runs the mock agent and exits when the test binary was started as
+// one. Call it first in TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		mockagent.Main()
+//		os.Exit(m.Run())
+//	}
+func Main() {
+	if os.Getenv(EnvVar) != "1" {
+		return
+	}
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == CommandName {
+		args = args[1:]
+	}
+	os.Exit(Run(args, os.Stdout))
+}
+
+// Agent returns a custom agent config that starts the running executable as
+// the mock agent, with the prompt as its argument: autospec runs its
+// __mockagent command, and test binaries calling Main see EnvVar.
+func Agent() (*cliagent.CustomAgentConfig, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("locating executable: %w", err)
+	}
+	return &cliagent.CustomAgentConfig{
+		Command: exe,
+		Args:    []string{CommandName, "{{PROMPT}}"},
+		Env:     map[string]string{EnvVar: "1"},
+	}, nil
+}
+
+// Run is the mock agent: it logs the call, waits MOCK_DELAY seconds, writes
+// the artifact of the stage named in args, copies MOCK_RESPONSE_FILE to
+// stdout and returns MOCK_EXIT_CODE.
+func Run(args []string, stdout io.Writer) int {
+	exitCode, _ := strconv.Atoi(os.Getenv("MOCK_EXIT_CODE"))
+	delay, _ := strconv.Atoi(os.Getenv("MOCK_DELAY"))
+
+	if err := logCall(args, exitCode, delay); err != nil {
+		fmt.Fprintf(os.Stderr, "mock agent: %v\n", err)
+		return 1
+	}
+	if delay > 0 {
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+	if err := generateArtifact(strings.Join(args, " ")); err != nil {
+		fmt.Fprintf(os.Stderr, "mock agent: %v\n", err)
+		return 1
+	}
+	if path := os.Getenv("MOCK_RESPONSE_FILE"); path != "" {
+		if response, err := os.ReadFile(path); err == nil {
+			stdout.Write(response)
+		}
+	}
+	return exitCode
+}
+
+// logCall appends the call to MOCK_CALL_LOG, as a YAML document.
+func logCall(args []string, exitCode, delay int) error {
+	path := os.Getenv("MOCK_CALL_LOG")
+	if path == "" {
+		return nil
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "---\ntimestamp: %q\nargs:\n", time.Now().Format(time.RFC3339))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "  - \"%s\"\n", strings.ReplaceAll(arg, `"`, `\"`))
+	}
+	fmt.Fprintf(&b, "pid: %d\nresponse_file: %q\nexit_code: %d\ndelay: %d\n",
+		os.Getpid(), os.Getenv("MOCK_RESPONSE_FILE"), exitCode, delay)
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening call log: %w", err)
+	}
+	defer f.Close()
+	_, err = f.WriteString(b.String())
+	return err
+}
+
+// generateArtifact writes the artifact of the stage command names to
+// MOCK_ARTIFACT_DIR/MOCK_SPEC_NAME, or marks its tasks completed for
+// implement.
+func generateArtifact(command string) error {
+	artifactDir := os.Getenv("MOCK_ARTIFACT_DIR")
+	if artifactDir == "" {
+		return nil
+	}
+	specName := os.Getenv("MOCK_SPEC_NAME")
+	if specName == "" {
+		specName = defaultSpecName
+	}
+	specDir := filepath.Join(artifactDir, specName)
+
+	switch {
+	case strings.Contains(command, "/autospec.specify"):
+		return writeFile(filepath.Join(specDir, "spec.yaml"), specYAML)
+	case strings.Contains(command, "/autospec.plan"):
+		return writeFile(filepath.Join(specDir, "plan.yaml"), planYAML)
+	case strings.Contains(command, "/autospec.tasks"):
+		return writeFile(filepath.Join(specDir, "tasks.yaml"), tasksYAML)
+	case strings.Contains(command, "/autospec.implement"):
+		return markTasksCompleted(filepath.Join(specDir, "tasks.yaml"))
+	case strings.Contains(command, "/autospec.checklist"):
+		return writeFile(filepath.Join(specDir, "checklists", "general.yaml"), checklistYAML)
+	case strings.Contains(command, "/autospec.analyze"):
+		return writeFile(filepath.Join(specDir, "analysis.yaml"), analysisYAML)
+	case strings.Contains(command, "/autospec.constitution"):
+		// The constitution belongs to the project, the parent of the specs dir.
+		memoryDir := filepath.Join(filepath.Dir(artifactDir), ".autospec", "memory")
+		return writeFile(filepath.Join(memoryDir, "constitution.yaml"), constitutionYAML)
+	}
+	return nil
+}
+
+// markTasksCompleted sets every Pending or InProgress task in path to
+// Completed, simulating an implementation. A missing file is left alone.
+func markTasksCompleted(path string) error {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	updated := strings.NewReplacer(
+		`status: "Pending"`, `status: "Completed"`,
+		`status: "InProgress"`, `status: "Completed"`,
+	).Replace(string(content))
+	return os.WriteFile(path, []byte(updated), 0o644)
+}
+
+func writeFile(path, content string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
@@ -0,0 +1,128 @@
+package mockagent
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	Main()
+	os.Exit(m.Run())
+}
+
+// runMock starts the test binary as the mock agent with env added.
+func runMock(t *testing.T, env []string, args ...string) (string, int) {
+	t.Helper()
+	agent, err := Agent()
+	require.NoError(t, err)
+	cmd := exec.Command(agent.Command, append([]string{CommandName}, args...)...)
+	cmd.Env = append(os.Environ(), EnvVar+"=1")
+	cmd.Env = append(cmd.Env, env...)
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return string(out), exitErr.ExitCode()
+	}
+	require.NoError(t, err)
+	return string(out), 0
+}
+
+func TestRunResponseAndExitCode(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	responseFile := filepath.Join(dir, "response.txt")
+	require.NoError(t, os.WriteFile(responseFile, []byte("hello from mock"), 0o644))
+
+	tests := map[string]struct {
+		env      []string
+		wantOut  string
+		wantCode int
+	}{
+		"response file": {env: []string{"MOCK_RESPONSE_FILE=" + responseFile}, wantOut: "hello from mock"},
+		"no response":   {wantOut: ""},
+		"exit code":     {env: []string{"MOCK_EXIT_CODE=3"}, wantCode: 3},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			out, code := runMock(t, tt.env, "-p", "test")
+			assert.Equal(t, tt.wantOut, out)
+			assert.Equal(t, tt.wantCode, code)
+		})
+	}
+}
+
+func TestRunDelay(t *testing.T) {
+	t.Parallel()
+
+	start := time.Now()
+	_, code := runMock(t, []string{"MOCK_DELAY=1"}, "test")
+	require.Equal(t, 0, code)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestRunCallLog(t *testing.T) {
+	t.Parallel()
+
+	callLog := filepath.Join(t.TempDir(), "calls.log")
+	env := []string{"MOCK_CALL_LOG=" + callLog}
+	runMock(t, env, "/autospec.plan", `say "hi"`)
+	runMock(t, env, "/autospec.tasks")
+
+	content, err := os.ReadFile(callLog)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(content), "---\n"))
+	assert.Contains(t, string(content), `  - "/autospec.plan"`)
+	assert.Contains(t, string(content), `  - "say \"hi\""`)
+}
+
+func TestRunArtifacts(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		prompt   string
+		wantFile string
+	}{
+		"specify":      {prompt: "/autospec.specify add login", wantFile: "specs/007-login/spec.yaml"},
+		"plan":         {prompt: "/autospec.plan", wantFile: "specs/007-login/plan.yaml"},
+		"tasks":        {prompt: "/autospec.tasks", wantFile: "specs/007-login/tasks.yaml"},
+		"checklist":    {prompt: "/autospec.checklist", wantFile: "specs/007-login/checklists/general.yaml"},
+		"analyze":      {prompt: "/autospec.analyze", wantFile: "specs/007-login/analysis.yaml"},
+		"constitution": {prompt: "/autospec.constitution", wantFile: ".autospec/memory/constitution.yaml"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			root := t.TempDir()
+			env := []string{"MOCK_ARTIFACT_DIR=" + filepath.Join(root, "specs"), "MOCK_SPEC_NAME=007-login"}
+			_, code := runMock(t, env, tt.prompt)
+			require.Equal(t, 0, code)
+			assert.FileExists(t, filepath.Join(root, filepath.FromSlash(tt.wantFile)))
+		})
+	}
+}
+
+func TestRunImplementMarksTasksCompleted(t *testing.T) {
+	t.Parallel()
+
+	specsDir := t.TempDir()
+	tasksPath := filepath.Join(specsDir, defaultSpecName, "tasks.yaml")
+	require.NoError(t, writeFile(tasksPath, tasksYAML))
+
+	_, code := runMock(t, []string{"MOCK_ARTIFACT_DIR=" + specsDir}, "/autospec.implement")
+	require.Equal(t, 0, code)
+
+	content, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `status: "Completed"`)
+	assert.NotContains(t, string(content), `status: "Pending"`)
+}
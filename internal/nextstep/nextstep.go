@@ -0,0 +1,177 @@
+// Package nextstep suggests the commands that move a spec forward, from the
+// artifacts, stale markers, reviews and task states found in its directory.
+package nextstep
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/review"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// Step is one suggested command.
+type Step struct {
+	Command string // e.g. "autospec implement 004 --from-task T003"
+	Reason  string // what in the spec directory calls for it
+}
+
+// Options are the gates that change which command comes next.
+type Options struct {
+	Review        review.Config // plan waits for approvals when enabled
+	AnalyzeFailOn string        // implement waits for findings at or above it to be resolved
+}
+
+// Suggest returns the next commands for specName in specsDir, most pressing
+// first. An empty specName suggests starting a spec.
+func Suggest(specsDir, specName string, opts Options) []Step {
+	if specName == "" {
+		return []Step{{Command: `autospec specify "<feature description>"`, Reason: "start a spec"}}
+	}
+	specDir := filepath.Join(specsDir, specName)
+	ref := specRef(specName)
+
+	if !exists(filepath.Join(specDir, "spec.yaml")) {
+		return []Step{{Command: `autospec specify "<feature description>"`, Reason: "no spec.yaml yet"}}
+	}
+	if stale, err := spec.LoadStaleArtifacts(specDir); err == nil && len(stale) > 0 {
+		steps := make([]Step, 0, len(stale))
+		for _, s := range stale {
+			steps = append(steps, Step{Command: "autospec " + s.Stage, Reason: s.Artifact + " predates the last spec change"})
+		}
+		return steps
+	}
+	if !exists(filepath.Join(specDir, "plan.yaml")) {
+		var steps []Step
+		if review.CheckGate(specDir, opts.Review) != nil {
+			steps = append(steps, Step{Command: "autospec review " + ref, Reason: "plan waits for an approved review of spec.yaml"})
+		}
+		return append(steps, Step{Command: "autospec plan", Reason: "spec.yaml has no plan yet"})
+	}
+	tasksPath := filepath.Join(specDir, "tasks.yaml")
+	if !exists(tasksPath) {
+		if exists(filepath.Join(specDir, "tasks.md")) {
+			// Legacy markdown tasks carry no statuses to go by.
+			return nil
+		}
+		return []Step{{Command: "autospec tasks", Reason: "plan.yaml has no tasks yet"}}
+	}
+
+	tasks, err := validation.ParseTasksYAML(tasksPath)
+	if err != nil {
+		return []Step{{Command: "autospec artifact " + filepath.ToSlash(tasksPath), Reason: "tasks.yaml cannot be read"}}
+	}
+	return suggestForTasks(specDir, ref, tasks, opts)
+}
+
+// suggestForTasks picks the next commands once tasks.yaml exists.
+func suggestForTasks(specDir, ref string, tasks *validation.TasksYAML, opts Options) []Step {
+	var total, completed int
+	var firstOpen, firstBlocked, blockedReason string
+	blocked := 0
+	for _, phase := range tasks.Phases {
+		for _, task := range phase.Tasks {
+			total++
+			switch strings.ToLower(task.Status) {
+			case "completed", "done", "complete":
+				completed++
+			case "blocked":
+				blocked++
+				if firstBlocked == "" {
+					firstBlocked, blockedReason = task.ID, task.BlockedReason
+				}
+			default:
+				if firstOpen == "" {
+					firstOpen = task.ID
+				}
+			}
+		}
+	}
+
+	if total > 0 && completed == total {
+		return []Step{
+			{Command: "autospec report " + ref, Reason: "all tasks are completed; the report describes the change for the pull request"},
+			{Command: "autospec retro " + ref, Reason: "record the conventions this spec taught in the learnings file"},
+		}
+	}
+
+	var steps []Step
+	analysisPath := filepath.Join(specDir, "analysis.yaml")
+	if opts.AnalyzeFailOn != "" && exists(analysisPath) {
+		if findings, err := validation.FindBlockingFindings(analysisPath, opts.AnalyzeFailOn); err == nil && len(findings) > 0 {
+			steps = append(steps, Step{
+				Command: "autospec analyze",
+				Reason:  fmt.Sprintf("%d finding(s) at or above %s block implement; resolve them, then re-run", len(findings), opts.AnalyzeFailOn),
+			})
+		}
+	}
+
+	switch {
+	case firstOpen == "":
+		// Only blocked tasks are left.
+	case completed == 0:
+		steps = append(steps, Step{
+			Command: "autospec implement " + ref,
+			Reason:  fmt.Sprintf("%d tasks in %d phase(s) to implement", total, len(tasks.Phases)),
+		})
+		if !exists(analysisPath) {
+			steps = append(steps, Step{Command: "autospec analyze", Reason: "optional: check spec, plan and tasks agree before implementing"})
+		}
+	default:
+		steps = append(steps, Step{
+			Command: fmt.Sprintf("autospec implement %s --from-task %s", ref, firstOpen),
+			Reason:  fmt.Sprintf("%d of %d tasks completed; %s is the first unfinished", completed, total, firstOpen),
+		})
+	}
+
+	if blocked > 0 {
+		reason := firstBlocked + " is blocked"
+		if blockedReason != "" {
+			reason += ": " + blockedReason
+		}
+		if blocked > 1 {
+			reason += fmt.Sprintf(" (%d blocked tasks)", blocked)
+		}
+		steps = append(steps, Step{Command: "autospec task unblock " + firstBlocked, Reason: reason})
+	}
+	return steps
+}
+
+// Print writes steps as a "Next steps:" block, commands aligned. It writes
+// nothing for no steps.
+func Print(w io.Writer, steps []Step) {
+	if len(steps) == 0 {
+		return
+	}
+	width := 0
+	for _, s := range steps {
+		width = max(width, len(s.Command))
+	}
+	fmt.Fprintln(w, "\nNext steps:")
+	for _, s := range steps {
+		fmt.Fprintf(w, "  %-*s  # %s\n", width, s.Command, s.Reason)
+	}
+}
+
+// specRef returns the shortest argument that names specName on the command
+// line: its number in the numbered layout, else its name. Dates and
+// categories can be shared by several specs.
+func specRef(specName string) string {
+	locator := spec.Locator()
+	if locator.Layout() != spec.LayoutNumbered {
+		return specName
+	}
+	if number, _, ok := locator.Parse(specName); ok {
+		return number
+	}
+	return specName
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
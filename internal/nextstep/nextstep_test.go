@@ -0,0 +1,163 @@
+package nextstep
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/review"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const specYAML = `feature:
+  branch: "004-dark-mode"
+user_stories: []
+`
+
+// tasksYAML returns a tasks.yaml with one phase holding tasks with the
+// given statuses, numbered from T001.
+func tasksYAML(statuses ...string) string {
+	out := "phases:\n  - number: 1\n    title: Setup\n    tasks:\n"
+	for i, status := range statuses {
+		out += fmt.Sprintf("      - id: T%03d\n        status: %s\n", i+1, status)
+		if status == "Blocked" {
+			out += "        blocked_reason: waiting for API keys\n"
+		}
+	}
+	return out
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		files map[string]string
+		opts  Options
+		want  []string
+	}{
+		"no spec": {
+			want: []string{`autospec specify "<feature description>"`},
+		},
+		"spec only": {
+			files: map[string]string{"spec.yaml": specYAML},
+			want:  []string{"autospec plan"},
+		},
+		"spec awaiting review": {
+			files: map[string]string{"spec.yaml": specYAML},
+			opts:  Options{Review: review.Config{Enabled: true}},
+			want:  []string{"autospec review 004", "autospec plan"},
+		},
+		"stale plan": {
+			files: map[string]string{
+				"spec.yaml":   specYAML,
+				"plan.yaml":   "plan: {}\n",
+				".stale.yaml": "artifacts:\n  - artifact: plan.yaml\n    stage: plan\n  - artifact: tasks.yaml\n    stage: tasks\n",
+			},
+			want: []string{"autospec plan", "autospec tasks"},
+		},
+		"plan only": {
+			files: map[string]string{"spec.yaml": specYAML, "plan.yaml": "plan: {}\n"},
+			want:  []string{"autospec tasks"},
+		},
+		"tasks pending": {
+			files: map[string]string{"spec.yaml": specYAML, "plan.yaml": "plan: {}\n", "tasks.yaml": tasksYAML("Pending", "Pending")},
+			want:  []string{"autospec implement 004", "autospec analyze"},
+		},
+		"tasks pending and analyzed": {
+			files: map[string]string{
+				"spec.yaml":     specYAML,
+				"plan.yaml":     "plan: {}\n",
+				"tasks.yaml":    tasksYAML("Pending"),
+				"analysis.yaml": "findings: []\n",
+			},
+			want: []string{"autospec implement 004"},
+		},
+		"blocking findings": {
+			files: map[string]string{
+				"spec.yaml":     specYAML,
+				"plan.yaml":     "plan: {}\n",
+				"tasks.yaml":    tasksYAML("Pending"),
+				"analysis.yaml": "findings:\n  - id: F1\n    severity: HIGH\n",
+			},
+			opts: Options{AnalyzeFailOn: "HIGH"},
+			want: []string{"autospec analyze", "autospec implement 004"},
+		},
+		"partly implemented": {
+			files: map[string]string{"spec.yaml": specYAML, "plan.yaml": "plan: {}\n", "tasks.yaml": tasksYAML("Completed", "InProgress", "Pending")},
+			want:  []string{"autospec implement 004 --from-task T002"},
+		},
+		"blocked task": {
+			files: map[string]string{"spec.yaml": specYAML, "plan.yaml": "plan: {}\n", "tasks.yaml": tasksYAML("Completed", "Blocked")},
+			want:  []string{"autospec task unblock T002"},
+		},
+		"all completed": {
+			files: map[string]string{"spec.yaml": specYAML, "plan.yaml": "plan: {}\n", "tasks.yaml": tasksYAML("Completed", "Done")},
+			want:  []string{"autospec report 004", "autospec retro 004"},
+		},
+		"legacy markdown tasks": {
+			files: map[string]string{"spec.yaml": specYAML, "plan.yaml": "plan: {}\n", "tasks.md": "- [ ] T001 Setup\n"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			specsDir := t.TempDir()
+			specDir := filepath.Join(specsDir, "004-dark-mode")
+			require.NoError(t, os.MkdirAll(specDir, 0o755))
+			for file, content := range tt.files {
+				require.NoError(t, os.WriteFile(filepath.Join(specDir, file), []byte(content), 0o644))
+			}
+
+			var got []string
+			for _, step := range Suggest(specsDir, "004-dark-mode", tt.opts) {
+				got = append(got, step.Command)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSuggestReasons(t *testing.T) {
+	t.Parallel()
+
+	specsDir := t.TempDir()
+	specDir := filepath.Join(specsDir, "004-dark-mode")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(specYAML), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "plan.yaml"), []byte("plan: {}\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "tasks.yaml"), []byte(tasksYAML("Completed", "Pending", "Blocked", "Blocked")), 0o644))
+
+	steps := Suggest(specsDir, "004-dark-mode", Options{})
+	require.Len(t, steps, 2)
+	assert.Equal(t, "1 of 4 tasks completed; T002 is the first unfinished", steps[0].Reason)
+	assert.Equal(t, "T003 is blocked: waiting for API keys (2 blocked tasks)", steps[1].Reason)
+}
+
+func TestSuggestNoSpecName(t *testing.T) {
+	t.Parallel()
+
+	steps := Suggest(t.TempDir(), "", Options{})
+	require.Len(t, steps, 1)
+	assert.Equal(t, `autospec specify "<feature description>"`, steps[0].Command)
+}
+
+func TestPrint(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	Print(&out, []Step{
+		{Command: "autospec implement 004", Reason: "2 tasks to implement"},
+		{Command: "autospec analyze", Reason: "optional"},
+	})
+	assert.Equal(t, "\nNext steps:\n"+
+		"  autospec implement 004  # 2 tasks to implement\n"+
+		"  autospec analyze        # optional\n", out.String())
+
+	out.Reset()
+	Print(&out, nil)
+	assert.Empty(t, out.String())
+}
@@ -0,0 +1,179 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ariel-frischer/autospec/internal/logging"
+)
+
+// Artifact link styles for notifications.link.
+const (
+	LinkFile   = "file"   // file:///path
+	LinkVSCode = "vscode" // vscode://file/path
+	LinkCursor = "cursor" // cursor://file/path
+	LinkNone   = "none"   // no link
+)
+
+// ValidLink reports whether s is a notifications.link value. Empty means file.
+func ValidLink(s string) bool {
+	switch s {
+	case "", LinkFile, LinkVSCode, LinkCursor, LinkNone:
+		return true
+	default:
+		return false
+	}
+}
+
+// ArtifactLink returns a link that opens path, written in the given style.
+// Returns "" for an empty path or the none style.
+func ArtifactLink(style, path string) string {
+	if path == "" || style == LinkNone {
+		return ""
+	}
+	p := filepath.ToSlash(path)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // Windows drive paths: C:/x -> /C:/x
+	}
+	switch style {
+	case LinkVSCode, LinkCursor:
+		return style + "://file" + (&url.URL{Path: p}).EscapedPath()
+	default:
+		return (&url.URL{Scheme: "file", Path: p}).String()
+	}
+}
+
+// ParseMessageTemplate parses notifications.message_template.
+func ParseMessageTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("message").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing message template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// newMessageTemplate parses the configured message template, or returns nil
+// when none is set. An invalid template is logged and the built-in text used.
+func newMessageTemplate(config NotificationConfig) *template.Template {
+	if config.MessageTemplate == "" {
+		return nil
+	}
+	tmpl, err := ParseMessageTemplate(config.MessageTemplate)
+	if err != nil {
+		logging.For("notify").Warn("using built-in notification text", "error", err)
+		return nil
+	}
+	return tmpl
+}
+
+// runContext is what the run is working on, added to every notification.
+type runContext struct {
+	spec      string
+	stage     string
+	artifact  string
+	taskID    string
+	taskTitle string
+	failure   string
+}
+
+// SetStage records the spec, stage and artifact (absolute path of the file
+// the stage writes) named in later notifications, and clears the failure of
+// the previous stage. Safe to call on a nil handler.
+func (h *Handler) SetStage(spec, stage, artifact string) {
+	if h == nil {
+		return
+	}
+	h.ctxMu.Lock()
+	defer h.ctxMu.Unlock()
+	h.ctx.spec, h.ctx.stage, h.ctx.artifact = spec, stage, artifact
+	h.ctx.failure = ""
+}
+
+// SetTask records the task named in later notifications. An empty id clears
+// it. Safe to call on a nil handler.
+func (h *Handler) SetTask(id, title string) {
+	if h == nil {
+		return
+	}
+	h.ctxMu.Lock()
+	defer h.ctxMu.Unlock()
+	h.ctx.taskID, h.ctx.taskTitle = id, title
+	if id == "" {
+		h.ctx.taskTitle = ""
+	}
+}
+
+// SetFailure records why the current stage's last attempt failed
+// (validation, agent_error, timeout or gate). Empty clears it. Safe to call
+// on a nil handler.
+func (h *Handler) SetFailure(kind string) {
+	if h == nil {
+		return
+	}
+	h.ctxMu.Lock()
+	defer h.ctxMu.Unlock()
+	h.ctx.failure = kind
+}
+
+// compose adds the run context to data and sets the notification text: the
+// message template when configured, otherwise the built-in text followed by
+// the context details and artifact link.
+func (h *Handler) compose(n Notification, data EventData) (Notification, EventData) {
+	data, details := h.withContext(data)
+	data.Message = n.Message
+	if h.message != nil {
+		var buf bytes.Buffer
+		err := h.message.Execute(&buf, data)
+		if err == nil {
+			n.Message = strings.TrimSpace(buf.String())
+			return n, data
+		}
+		logging.For("notify").Warn("using built-in notification text", "error", err)
+	}
+	if details != "" {
+		n.Message += "\n" + details
+	}
+	if data.Link != "" {
+		n.Message += "\n" + data.Link
+	}
+	return n, data
+}
+
+// withContext fills data from the run context and returns the details line
+// for the built-in text. The stage and task are left out of the details when
+// the event already names them.
+func (h *Handler) withContext(data EventData) (EventData, string) {
+	h.ctxMu.Lock()
+	c := h.ctx
+	h.ctxMu.Unlock()
+
+	var details []string
+	if c.spec != "" {
+		details = append(details, "spec "+c.spec)
+	}
+	if data.Stage == "" && c.stage != "" {
+		data.Stage = c.stage
+		details = append(details, "stage "+c.stage)
+	}
+	if data.Task == "" && c.taskID != "" {
+		task := c.taskID
+		if c.taskTitle != "" {
+			task += ": " + c.taskTitle
+		}
+		details = append(details, task)
+	}
+	if c.failure != "" {
+		details = append(details, c.failure+" failure")
+	}
+
+	data.Spec = c.spec
+	data.TaskID, data.TaskTitle = c.taskID, c.taskTitle
+	data.Failure = c.failure
+	data.Artifact = c.artifact
+	data.Link = ArtifactLink(h.config.Link, c.artifact)
+	return data, strings.Join(details, " · ")
+}
@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestArtifactLink(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		style string
+		path  string
+		want  string
+	}{
+		"file":            {style: LinkFile, path: "/repo/specs/001-login/tasks.yaml", want: "file:///repo/specs/001-login/tasks.yaml"},
+		"default is file": {style: "", path: "/repo/spec.yaml", want: "file:///repo/spec.yaml"},
+		"escaped":         {style: LinkFile, path: "/my repo/spec.yaml", want: "file:///my%20repo/spec.yaml"},
+		"vscode":          {style: LinkVSCode, path: "/repo/plan.yaml", want: "vscode://file/repo/plan.yaml"},
+		"cursor":          {style: LinkCursor, path: "/repo/plan.yaml", want: "cursor://file/repo/plan.yaml"},
+		"none":            {style: LinkNone, path: "/repo/plan.yaml", want: ""},
+		"no artifact":     {style: LinkFile, path: "", want: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := ArtifactLink(tt.style, tt.path); got != tt.want {
+				t.Errorf("ArtifactLink(%q, %q) = %q, want %q", tt.style, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_ContextInMessages(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+
+	email := &recordingEmailSender{}
+	handler, _ := newTestHandler(config)
+	handler.remote = []remoteSender{email}
+	handler.SetStage("001-login", "implement", "/repo/specs/001-login/tasks.yaml")
+	handler.SetTask("T003", "Add login handler")
+	handler.SetFailure("gate")
+	handler.OnError("implement", errors.New("task T003: go test failed"))
+
+	sent := sentEmails(email)
+	if len(sent) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(sent))
+	}
+	want := "Error in 'implement': task T003: go test failed\n" +
+		"spec 001-login · stage implement · T003: Add login handler · gate failure\n" +
+		"file:///repo/specs/001-login/tasks.yaml"
+	if sent[0].Message != want {
+		t.Errorf("message = %q, want %q", sent[0].Message, want)
+	}
+	got := sent[0]
+	if got.Spec != "001-login" || got.Stage != "implement" || got.TaskID != "T003" || got.TaskTitle != "Add login handler" ||
+		got.Failure != "gate" || got.Link != "file:///repo/specs/001-login/tasks.yaml" {
+		t.Errorf("event data = %+v", got)
+	}
+}
+
+func TestHandler_ContextSkipsWhatTheEventNames(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+	config.OnStageComplete = true
+	config.Link = LinkNone
+
+	email := &recordingEmailSender{}
+	handler, _ := newTestHandler(config)
+	handler.remote = []remoteSender{email}
+	handler.SetStage("001-login", "plan", "/repo/specs/001-login/plan.yaml")
+	handler.SetFailure("validation")
+	handler.SetStage("001-login", "tasks", "/repo/specs/001-login/tasks.yaml")
+	handler.OnStageComplete("tasks", true)
+
+	sent := sentEmails(email)
+	if len(sent) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(sent))
+	}
+	if want := "Stage 'tasks' completed\nspec 001-login"; sent[0].Message != want {
+		t.Errorf("message = %q, want %q (stage not repeated, failure cleared, no link)", sent[0].Message, want)
+	}
+}
+
+func TestHandler_MessageTemplate(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+	config.Link = LinkVSCode
+	config.MessageTemplate = "[{{.Spec}}/{{.TaskID}}] {{.Message}} {{.Link}}"
+
+	email := &recordingEmailSender{}
+	handler, _ := newTestHandler(config)
+	handler.remote = []remoteSender{email}
+	handler.SetStage("001-login", "implement", "/repo/specs/001-login/tasks.yaml")
+	handler.SetTask("T003", "Add login handler")
+	handler.OnError("implement", errors.New("boom"))
+
+	sent := sentEmails(email)
+	if len(sent) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(sent))
+	}
+	want := "[001-login/T003] Error in 'implement': boom vscode://file/repo/specs/001-login/tasks.yaml"
+	if sent[0].Message != want {
+		t.Errorf("message = %q, want %q", sent[0].Message, want)
+	}
+}
+
+func TestHandler_InvalidMessageTemplateFallsBack(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+	config.MessageTemplate = "{{.Nope}}"
+
+	email := &recordingEmailSender{}
+	handler, _ := newTestHandler(config)
+	handler.remote = []remoteSender{email}
+	handler.OnError("plan", errors.New("boom"))
+
+	sent := sentEmails(email)
+	if len(sent) != 1 || sent[0].Message != "Error in 'plan': boom" {
+		t.Errorf("notifications = %+v, want the built-in text", sent)
+	}
+}
+
+func TestHandler_SetTask_Clears(t *testing.T) {
+	t.Parallel()
+	handler, _ := newTestHandler(DefaultConfig())
+	handler.SetTask("T001", "Write tests")
+	handler.SetTask("", "ignored")
+
+	data, details := handler.withContext(EventData{})
+	if data.TaskID != "" || data.TaskTitle != "" || strings.Contains(details, "T001") {
+		t.Errorf("task not cleared: %+v, details %q", data, details)
+	}
+
+	var nilHandler *Handler
+	nilHandler.SetStage("001", "plan", "") // must not panic
+	nilHandler.SetTask("T001", "")
+	nilHandler.SetFailure("gate")
+}
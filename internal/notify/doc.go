@@ -10,6 +10,7 @@
 //   - Visual notifications via native OS notification systems
 //   - Audio alerts via system sound tools
 //   - Configurable notification hooks (on_command_complete, on_stage_complete, on_error, on_long_running)
+//   - Email (SMTP) and mobile push (ntfy, Pushover) channels for CI and unattended runs
 //   - Graceful degradation when notification tools are unavailable
 //   - Non-blocking async dispatch with configurable timeout
 //
@@ -0,0 +1,250 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// smtpTimeout bounds the whole SMTP exchange so an unreachable server cannot
+// hold up the end of a run.
+const smtpTimeout = 30 * time.Second
+
+// DefaultEmailSubject is the subject template used when none is configured.
+const DefaultEmailSubject = "[autospec] {{.Message}}"
+
+// defaultEmailBody is the body template for events without a configured template.
+const defaultEmailBody = `{{.Message}}
+
+Event:     {{.Event}}
+{{with .Command}}Command:   {{.}}
+{{end}}{{with .Spec}}Spec:      {{.}}
+{{end}}{{with .Stage}}Stage:     {{.}}
+{{end}}{{with .Duration}}Duration:  {{.}}
+{{end}}{{if .Task}}Task:      {{.Task}}
+{{else if .TaskID}}Task:      {{.TaskID}}{{with .TaskTitle}}: {{.}}{{end}}
+{{end}}{{with .Failure}}Failure:   {{.}}
+{{end}}{{with .Link}}Artifact:  {{.}}
+{{end}}{{with .RunID}}Run:       {{.}}
+{{end}}Host:      {{.Hostname}}
+Directory: {{.WorkDir}}
+Time:      {{.Time}}
+{{with .Error}}
+Error details:
+{{.}}
+{{end}}`
+
+// EmailConfig configures the SMTP notification channel. Email is sent for the
+// same hooks as desktop notifications, but also from CI and non-interactive
+// sessions, so unattended runs can report back.
+type EmailConfig struct {
+	// Enabled turns on email delivery (requires notifications.enabled)
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// Host is the SMTP server host name
+	Host string `koanf:"host" yaml:"host" json:"host"`
+
+	// Port is the SMTP server port (default: 587)
+	Port int `koanf:"port" yaml:"port" json:"port"`
+
+	// StartTLS upgrades the connection with STARTTLS and fails if the server
+	// does not support it (default: true)
+	StartTLS bool `koanf:"starttls" yaml:"starttls" json:"starttls"`
+
+	// Username enables PLAIN authentication when set
+	Username string `koanf:"username" yaml:"username" json:"username"`
+
+	// Password for PLAIN authentication; prefer AUTOSPEC_NOTIFICATIONS_EMAIL_PASSWORD
+	Password string `koanf:"password" yaml:"password" json:"password"`
+
+	// From is the sender address
+	From string `koanf:"from" yaml:"from" json:"from"`
+
+	// To lists recipient addresses
+	To []string `koanf:"to" yaml:"to" json:"to"`
+
+	// Subject is a text/template for the subject line (default: DefaultEmailSubject)
+	Subject string `koanf:"subject" yaml:"subject" json:"subject"`
+
+	// Templates maps event names (see Events) to text/template message bodies.
+	// Events without an entry use a built-in summary.
+	Templates map[string]string `koanf:"templates" yaml:"templates" json:"templates"`
+}
+
+// Validate checks that an enabled email config can be used to send mail.
+func (c EmailConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Host == "" {
+		return errors.New("host is required")
+	}
+	if c.Port <= 0 || c.Port > 65535 {
+		return fmt.Errorf("port %d is out of range", c.Port)
+	}
+	if c.From == "" {
+		return errors.New("from is required")
+	}
+	if len(c.To) == 0 {
+		return errors.New("to must list at least one recipient")
+	}
+	_, err := NewSMTPNotifier(c)
+	return err
+}
+
+// SMTPNotifier sends notifications as email using net/smtp.
+type SMTPNotifier struct {
+	cfg       EmailConfig
+	subject   *template.Template
+	bodies    map[string]*template.Template
+	fallback  *template.Template
+	tlsConfig *tls.Config
+}
+
+// NewSMTPNotifier parses the configured templates and returns a notifier.
+// Returns an error for unknown template events or invalid template syntax.
+func NewSMTPNotifier(cfg EmailConfig) (*SMTPNotifier, error) {
+	subject := cfg.Subject
+	if subject == "" {
+		subject = DefaultEmailSubject
+	}
+	subjectTmpl, err := template.New("subject").Parse(subject)
+	if err != nil {
+		return nil, fmt.Errorf("parsing subject template: %w", err)
+	}
+
+	bodies := make(map[string]*template.Template, len(cfg.Templates))
+	for event, text := range cfg.Templates {
+		if !isEvent(event) {
+			return nil, fmt.Errorf("unknown template event %q (valid: %s)", event, strings.Join(Events, ", "))
+		}
+		tmpl, err := template.New(event).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s template: %w", event, err)
+		}
+		bodies[event] = tmpl
+	}
+
+	return &SMTPNotifier{
+		cfg:       cfg,
+		subject:   subjectTmpl,
+		bodies:    bodies,
+		fallback:  template.Must(template.New("default").Parse(defaultEmailBody)),
+		tlsConfig: &tls.Config{ServerName: cfg.Host, MinVersion: tls.VersionTLS12},
+	}, nil
+}
+
+// Name identifies the channel in log messages.
+func (s *SMTPNotifier) Name() string { return "email" }
+
+// Send renders the templates for data.Event and delivers the message to all recipients.
+func (s *SMTPNotifier) Send(data EventData) error {
+	if data.Time == "" {
+		data.Time = time.Now().Format(time.RFC3339)
+	}
+	if data.Hostname == "" {
+		data.Hostname, _ = os.Hostname()
+	}
+	if data.WorkDir == "" {
+		data.WorkDir, _ = os.Getwd()
+	}
+
+	msg, err := s.buildMessage(data)
+	if err != nil {
+		return err
+	}
+	return s.deliver(msg)
+}
+
+// buildMessage renders the RFC 5322 message for data.
+func (s *SMTPNotifier) buildMessage(data EventData) ([]byte, error) {
+	var subject bytes.Buffer
+	if err := s.subject.Execute(&subject, data); err != nil {
+		return nil, fmt.Errorf("rendering subject: %w", err)
+	}
+
+	body := s.bodies[data.Event]
+	if body == nil {
+		body = s.fallback
+	}
+	var text bytes.Buffer
+	if err := body.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("rendering %s template: %w", data.Event, err)
+	}
+
+	// Keep the subject on one line; error messages often span several
+	subjectLine := strings.Join(strings.Fields(subject.String()), " ")
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(s.cfg.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subjectLine))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(text.String(), "\r\n", "\n"), "\n", "\r\n"))
+	return msg.Bytes(), nil
+}
+
+// deliver runs the SMTP exchange: STARTTLS (if configured), AUTH, MAIL, RCPT, DATA.
+func (s *SMTPNotifier) deliver(msg []byte) error {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+	conn, err := net.DialTimeout("tcp", addr, smtpTimeout)
+	if err != nil {
+		return fmt.Errorf("connecting to %s: %w", addr, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("starting SMTP session with %s: %w", addr, err)
+	}
+	defer c.Close()
+
+	if s.cfg.StartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%s does not support STARTTLS", addr)
+		}
+		if err := c.StartTLS(s.tlsConfig); err != nil {
+			return fmt.Errorf("STARTTLS: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		auth := smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+		if err := c.Auth(auth); err != nil {
+			return fmt.Errorf("authenticating as %s: %w", s.cfg.Username, err)
+		}
+	}
+
+	if err := c.Mail(s.cfg.From); err != nil {
+		return fmt.Errorf("MAIL FROM: %w", err)
+	}
+	for _, rcpt := range s.cfg.To {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("RCPT TO %s: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("DATA: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("writing message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("sending message: %w", err)
+	}
+	return c.Quit()
+}
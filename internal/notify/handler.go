@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sync"
+	"text/template"
 	"time"
 
+	"github.com/ariel-frischer/autospec/internal/logging"
 	"golang.org/x/term"
 )
 
@@ -15,7 +18,33 @@ import (
 type Handler struct {
 	config    NotificationConfig
 	sender    Sender
+	remote    []remoteSender
 	startTime time.Time
+	now       func() time.Time   // clock for quiet hours
+	runID     string             // run ID added to every notification payload
+	message   *template.Template // notifications.message_template, nil for the built-in text
+
+	ctxMu sync.Mutex
+	ctx   runContext
+
+	watchMu sync.Mutex
+	watch   *stageWatch
+}
+
+// remoteSender delivers notifications over the network (email, push).
+// Satisfied by *SMTPNotifier, *NtfyNotifier and *PushoverNotifier.
+type remoteSender interface {
+	Name() string
+	Send(data EventData) error
+}
+
+// stageWatch is the long-running watchdog for the stage currently executing.
+type stageWatch struct {
+	stage string
+	start time.Time
+	task  string
+	refs  int
+	done  chan struct{}
 }
 
 // NewHandler creates a new notification handler with the given configuration.
@@ -25,8 +54,60 @@ func NewHandler(config NotificationConfig) *Handler {
 	return &Handler{
 		config:    config,
 		sender:    NewSender(),
+		remote:    newRemoteSenders(config),
 		startTime: time.Now(),
+		now:       time.Now,
+		message:   newMessageTemplate(config),
+	}
+}
+
+// newRemoteSenders returns the enabled email and push channels.
+// An unusable channel config is logged and disables only that channel.
+func newRemoteSenders(config NotificationConfig) []remoteSender {
+	if !config.Enabled {
+		return nil
+	}
+	var senders []remoteSender
+	for _, ch := range remoteChannels(config) {
+		if ch.err != nil {
+			logging.For("notify").Warn("notifications disabled", "channel", ch.name, "error", ch.err)
+			continue
+		}
+		senders = append(senders, ch.sender)
+	}
+	return senders
+}
+
+// remoteChannel is an enabled remote channel, or why its config is unusable.
+type remoteChannel struct {
+	name   string
+	sender remoteSender
+	err    error
+}
+
+// remoteChannels builds every remote channel enabled in config.
+func remoteChannels(config NotificationConfig) []remoteChannel {
+	var channels []remoteChannel
+	add := func(name string, enabled bool, build func() (remoteSender, error)) {
+		if !enabled {
+			return
+		}
+		sender, err := build()
+		channels = append(channels, remoteChannel{name: name, sender: sender, err: err})
 	}
+	add("email", config.Email.Enabled, func() (remoteSender, error) {
+		if err := config.Email.Validate(); err != nil {
+			return nil, err
+		}
+		return NewSMTPNotifier(config.Email)
+	})
+	add("ntfy", config.Ntfy.Enabled, func() (remoteSender, error) {
+		return NewNtfyNotifier(config.Ntfy)
+	})
+	add("pushover", config.Pushover.Enabled, func() (remoteSender, error) {
+		return NewPushoverNotifier(config.Pushover)
+	})
+	return channels
 }
 
 // NewHandlerWithSender creates a handler with a custom sender (for testing).
@@ -35,6 +116,8 @@ func NewHandlerWithSender(config NotificationConfig, sender Sender) *Handler {
 		config:    config,
 		sender:    sender,
 		startTime: time.Now(),
+		now:       time.Now,
+		message:   newMessageTemplate(config),
 	}
 }
 
@@ -43,6 +126,14 @@ func (h *Handler) SetStartTime(t time.Time) {
 	h.startTime = t
 }
 
+// SetRunID sets the run ID included in notification payloads. Safe to call on
+// a nil handler.
+func (h *Handler) SetRunID(runID string) {
+	if h != nil {
+		h.runID = runID
+	}
+}
+
 // Config returns the handler's notification configuration
 func (h *Handler) Config() NotificationConfig {
 	return h.config
@@ -68,6 +159,16 @@ func (h *Handler) isEnabled() bool {
 	return true
 }
 
+// isActive reports whether any channel should receive notifications.
+// Remote channels deliver from CI and non-interactive sessions, where desktop
+// notifications are suppressed, so unattended runs can still report back.
+func (h *Handler) isActive() bool {
+	if h.config.Enabled && len(h.remote) > 0 {
+		return true
+	}
+	return h.isEnabled()
+}
+
 // isCI checks for common CI environment variables.
 // Returns true if any CI-related environment variable is set.
 func isCI() bool {
@@ -141,6 +242,58 @@ func (h *Handler) dispatch(n Notification) {
 	}
 }
 
+// send delivers n to the desktop (when interactive) and remote channels.
+// data supplies the event details for remote channels and email templates;
+// the run context (spec, task, failure, artifact) is added to both. Secrets
+// are masked before delivery.
+func (h *Handler) send(n Notification, data EventData) {
+	data.RunID = h.runID
+	n, data = h.compose(n, data)
+	n, data = h.redact(n, data)
+	if h.isEnabled() {
+		h.sendDesktop(n, data.Event)
+	}
+	h.sendRemote(n, data)
+}
+
+// redact masks secrets in the message, error and task text.
+func (h *Handler) redact(n Notification, data EventData) (Notification, EventData) {
+	redactor := h.config.Redactor
+	n.Message = redactor.String(n.Message)
+	data.Error = redactor.String(data.Error)
+	data.Task = redactor.String(data.Task)
+	data.TaskTitle = redactor.String(data.TaskTitle)
+	return n, data
+}
+
+// sendDesktop shows n unless quiet hours apply to event, in which case it is
+// dropped or logged depending on the schedule mode.
+func (h *Handler) sendDesktop(n Notification, event string) {
+	schedule := h.config.Schedule
+	if !schedule.IsQuiet(h.now()) || schedule.Bypasses(event) {
+		h.dispatch(n)
+		return
+	}
+	if schedule.Mode == QuietLog {
+		logging.For("notify").Info("suppressed during quiet hours", "message", n.Message)
+	}
+}
+
+// sendRemote delivers to each remote channel synchronously, since the process
+// usually exits right after the final notification. Failures are logged only.
+func (h *Handler) sendRemote(n Notification, data EventData) {
+	if !h.config.Enabled {
+		return
+	}
+	data.Message = n.Message
+	data.Status = string(n.NotificationType)
+	for _, sender := range h.remote {
+		if err := sender.Send(data); err != nil {
+			logging.For("notify").Warn("notification failed", "channel", sender.Name(), "error", err)
+		}
+	}
+}
+
 // sendNotification sends the notification based on configured type
 func (h *Handler) sendNotification(n Notification) {
 	switch h.config.Type {
@@ -165,7 +318,7 @@ func (h *Handler) sendNotification(n Notification) {
 //
 // TEST COVERAGE BLOCKED: isEnabled() requires TTY; dispatch() calls OS notification APIs.
 func (h *Handler) OnCommandComplete(commandName string, success bool, duration time.Duration) {
-	if !h.isEnabled() {
+	if !h.isActive() {
 		return
 	}
 
@@ -195,7 +348,7 @@ func (h *Handler) OnCommandComplete(commandName string, success bool, duration t
 		fmt.Sprintf("Command '%s' %s (%s)", commandName, status, formatDuration(duration)),
 		notifType,
 	)
-	h.dispatch(n)
+	h.send(n, EventData{Event: EventCommandComplete, Command: commandName, Duration: formatDuration(duration)})
 }
 
 // OnStageComplete is called when a workflow stage finishes.
@@ -203,7 +356,7 @@ func (h *Handler) OnCommandComplete(commandName string, success bool, duration t
 //
 // TEST COVERAGE BLOCKED: isEnabled() requires TTY; dispatch() calls OS notification APIs.
 func (h *Handler) OnStageComplete(stageName string, success bool) {
-	if !h.isEnabled() {
+	if !h.isActive() {
 		return
 	}
 
@@ -223,7 +376,7 @@ func (h *Handler) OnStageComplete(stageName string, success bool) {
 		fmt.Sprintf("Stage '%s' %s", stageName, status),
 		notifType,
 	)
-	h.dispatch(n)
+	h.send(n, EventData{Event: EventStageComplete, Stage: stageName})
 }
 
 // OnError is called when a command or stage fails.
@@ -233,7 +386,7 @@ func (h *Handler) OnStageComplete(stageName string, success bool) {
 //
 // TEST COVERAGE BLOCKED: isEnabled() requires TTY; dispatch() calls OS notification APIs.
 func (h *Handler) OnError(commandName string, err error) {
-	if !h.isEnabled() {
+	if !h.isActive() {
 		return
 	}
 
@@ -251,7 +404,80 @@ func (h *Handler) OnError(commandName string, err error) {
 		fmt.Sprintf("Error in '%s': %s", commandName, errMsg),
 		TypeFailure,
 	)
-	h.dispatch(n)
+	h.send(n, EventData{Event: EventError, Command: commandName, Error: errMsg})
+}
+
+// OnBudgetExceeded is called when a run stops because a budget limit was reached.
+// It sends a notification if the on_budget_exceeded hook is enabled.
+//
+// TEST COVERAGE BLOCKED: isEnabled() requires TTY; dispatch() calls OS notification APIs.
+func (h *Handler) OnBudgetExceeded(stageName string, err error) {
+	if !h.isActive() {
+		return
+	}
+
+	if !h.config.OnBudgetExceeded {
+		return
+	}
+
+	detail := "limit reached"
+	if err != nil {
+		detail = err.Error()
+	}
+
+	n := NewNotification(
+		"autospec",
+		fmt.Sprintf("Stopped before '%s': %s", stageName, detail),
+		TypeFailure,
+	)
+	h.send(n, EventData{Event: EventBudgetExceeded, Stage: stageName, Error: detail})
+}
+
+// OnRateLimited is called when a run pauses for wait to let a provider rate
+// limit clear. It sends a notification if the on_rate_limited hook is enabled.
+//
+// TEST COVERAGE BLOCKED: isEnabled() requires TTY; dispatch() calls OS notification APIs.
+func (h *Handler) OnRateLimited(stageName string, wait time.Duration) {
+	if !h.isActive() {
+		return
+	}
+
+	if !h.config.OnRateLimited {
+		return
+	}
+
+	n := rateLimitedNotification(stageName, wait)
+	h.send(n, EventData{Event: EventRateLimited, Stage: stageName, Duration: formatDuration(wait)})
+}
+
+// rateLimitedNotification builds the on_rate_limited notification.
+func rateLimitedNotification(stageName string, wait time.Duration) Notification {
+	return NewNotification(
+		"autospec",
+		fmt.Sprintf("Rate limited in '%s': waiting %s before continuing", stageName, formatDuration(wait)),
+		TypeInfo,
+	)
+}
+
+// OnCancelled is called when a signal stops a command. It replaces the
+// command-complete notification and sends one if the on_cancelled hook is enabled.
+//
+// TEST COVERAGE BLOCKED: isEnabled() requires TTY; dispatch() calls OS notification APIs.
+func (h *Handler) OnCancelled(commandName string, duration time.Duration) {
+	if !h.isActive() {
+		return
+	}
+
+	if !h.config.OnCancelled {
+		return
+	}
+
+	n := NewNotification(
+		"autospec",
+		fmt.Sprintf("Command '%s' cancelled after %s; rerun it to resume", commandName, formatDuration(duration)),
+		TypeInfo,
+	)
+	h.send(n, EventData{Event: EventCancelled, Command: commandName, Duration: formatDuration(duration)})
 }
 
 // OnInteractiveSessionStart is called before an interactive stage begins.
@@ -262,7 +488,7 @@ func (h *Handler) OnError(commandName string, err error) {
 //
 // TEST COVERAGE BLOCKED: isEnabled() requires TTY; dispatch() calls OS notification APIs.
 func (h *Handler) OnInteractiveSessionStart(stageName string) {
-	if !h.isEnabled() {
+	if !h.isActive() {
 		return
 	}
 
@@ -275,7 +501,105 @@ func (h *Handler) OnInteractiveSessionStart(stageName string) {
 		fmt.Sprintf("Interactive session starting: %s (your input required)", stageName),
 		TypeInfo,
 	)
-	h.dispatch(n)
+	h.send(n, EventData{Event: EventInteractiveSession, Stage: stageName})
+}
+
+// WatchStage starts a watchdog goroutine that sends one notification if
+// stageName is still running after long_running_threshold. Call the returned
+// function when the stage ends.
+//
+// Nested calls for the stage already being watched share its start time, so
+// task and phase loops (one agent session per task) are timed as one stage.
+// Watching a different stage replaces the previous watch.
+//
+// TEST COVERAGE BLOCKED: isActive() requires TTY or a remote channel; see watchStage for the logic.
+func (h *Handler) WatchStage(stageName string) (stop func()) {
+	if h == nil || !h.isActive() {
+		return func() {}
+	}
+	return h.watchStage(stageName)
+}
+
+// watchStage implements WatchStage without the TTY/CI check.
+func (h *Handler) watchStage(stageName string) func() {
+	threshold := h.config.LongRunningThreshold
+	if !h.config.OnLongRunning || threshold <= 0 {
+		return func() {}
+	}
+
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+
+	w := h.watch
+	if w == nil || w.stage != stageName {
+		if w != nil {
+			close(w.done)
+		}
+		w = &stageWatch{stage: stageName, start: time.Now(), done: make(chan struct{})}
+		h.watch = w
+		go h.runWatchdog(w, threshold)
+	}
+	w.refs++
+
+	var once sync.Once
+	return func() { once.Do(func() { h.releaseWatch(w) }) }
+}
+
+// releaseWatch drops one reference to w, stopping its watchdog at zero.
+func (h *Handler) releaseWatch(w *stageWatch) {
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+	if h.watch != w {
+		return
+	}
+	w.refs--
+	if w.refs <= 0 {
+		close(w.done)
+		h.watch = nil
+	}
+}
+
+// runWatchdog waits for the threshold or the end of the stage, whichever comes first.
+func (h *Handler) runWatchdog(w *stageWatch, threshold time.Duration) {
+	timer := time.NewTimer(threshold)
+	defer timer.Stop()
+
+	select {
+	case <-w.done:
+	case <-timer.C:
+		h.watchMu.Lock()
+		task := w.task
+		h.watchMu.Unlock()
+		elapsed := time.Since(w.start)
+		h.send(longRunningNotification(w.stage, elapsed, task), EventData{
+			Event:    EventLongRunning,
+			Stage:    w.stage,
+			Duration: formatDuration(elapsed),
+			Task:     task,
+		})
+	}
+}
+
+// SetCurrentTask records the task named in long-running notifications for the
+// watched stage (e.g., "T005: Add login handler"). Empty clears it.
+func (h *Handler) SetCurrentTask(task string) {
+	if h == nil {
+		return
+	}
+	h.watchMu.Lock()
+	defer h.watchMu.Unlock()
+	if h.watch != nil {
+		h.watch.task = task
+	}
+}
+
+// longRunningNotification builds the watchdog notification for a stage.
+func longRunningNotification(stageName string, elapsed time.Duration, task string) Notification {
+	msg := fmt.Sprintf("Stage '%s' still running after %s", stageName, formatDuration(elapsed))
+	if task != "" {
+		msg += fmt.Sprintf(" (current task: %s)", task)
+	}
+	return NewNotification("autospec", msg, TypeInfo)
 }
 
 // formatDuration formats a duration for display in notifications
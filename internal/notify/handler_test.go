@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -50,7 +52,7 @@ func TestNewHandler(t *testing.T) {
 		t.Fatal("NewHandler returned nil")
 	}
 
-	if handler.Config() != config {
+	if !reflect.DeepEqual(handler.Config(), config) {
 		t.Error("handler config doesn't match input")
 	}
 }
@@ -93,7 +95,7 @@ func TestHandler_Config(t *testing.T) {
 	handler := NewHandler(config)
 
 	gotConfig := handler.Config()
-	if gotConfig != config {
+	if !reflect.DeepEqual(gotConfig, config) {
 		t.Error("Config() returned different config")
 	}
 }
@@ -184,6 +186,161 @@ func TestHandler_OnError_HookDisabled(t *testing.T) {
 	}
 }
 
+func TestHandler_OnBudgetExceeded_HookDisabled(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+	config.OnBudgetExceeded = false
+
+	handler, mock := newTestHandler(config)
+	handler.OnBudgetExceeded("implement", errors.New("budget exceeded"))
+
+	if mock.visualCalled > 0 || mock.soundCalled > 0 {
+		t.Error("notification sent when budget hook disabled")
+	}
+}
+
+// sentEmails returns a snapshot of the emails recorded so far.
+func sentEmails(r *recordingEmailSender) []EventData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]EventData(nil), r.sent...)
+}
+
+func TestHandler_SetRunID_TagsPayloads(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+
+	email := &recordingEmailSender{}
+	handler, _ := newTestHandler(config)
+	handler.remote = []remoteSender{email}
+	handler.SetRunID("brave_fox_20250115_140000")
+	handler.OnError("plan", errors.New("validation failed"))
+
+	sent := sentEmails(email)
+	if len(sent) != 1 || sent[0].RunID != "brave_fox_20250115_140000" {
+		t.Errorf("notifications = %+v, want one with the run ID", sent)
+	}
+
+	var nilHandler *Handler
+	nilHandler.SetRunID("ignored") // must not panic
+}
+
+func TestHandler_WatchStage_FiresOnceWithTask(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+	config.OnLongRunning = true
+	config.LongRunningThreshold = 20 * time.Millisecond
+
+	email := &recordingEmailSender{}
+	handler, _ := newTestHandler(config)
+	handler.remote = []remoteSender{email}
+
+	stop := handler.watchStage("implement")
+	handler.SetCurrentTask("T005: Add login handler")
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(sentEmails(email)) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	stop()
+
+	sent := sentEmails(email)
+	if len(sent) != 1 {
+		t.Fatalf("got %d notifications, want 1", len(sent))
+	}
+	msg := sent[0].Message
+	if !strings.Contains(msg, "Stage 'implement' still running after") {
+		t.Errorf("message %q missing stage and elapsed time", msg)
+	}
+	if !strings.Contains(msg, "current task: T005: Add login handler") {
+		t.Errorf("message %q missing current task", msg)
+	}
+	if sent[0].Event != EventLongRunning || sent[0].Task != "T005: Add login handler" || sent[0].Status != string(TypeInfo) {
+		t.Errorf("long_running notification = %+v", sent[0])
+	}
+}
+
+func TestHandler_WatchStage_StoppedBeforeThreshold(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+	config.OnLongRunning = true
+	config.LongRunningThreshold = 50 * time.Millisecond
+
+	email := &recordingEmailSender{}
+	handler, _ := newTestHandler(config)
+	handler.remote = []remoteSender{email}
+
+	stop := handler.watchStage("plan")
+	stop()
+	stop() // idempotent
+	time.Sleep(100 * time.Millisecond)
+
+	if n := len(sentEmails(email)); n != 0 {
+		t.Errorf("got %d notifications after stop, want 0", n)
+	}
+}
+
+func TestHandler_WatchStage_NestedSharesStart(t *testing.T) {
+	t.Parallel()
+	config := DefaultConfig()
+	config.Enabled = true
+	config.OnLongRunning = true
+	config.LongRunningThreshold = time.Hour
+
+	handler := NewHandlerWithSender(config, NewMockSender())
+
+	outer := handler.watchStage("implement")
+	first := handler.watch
+	inner := handler.watchStage("implement")
+	if handler.watch != first {
+		t.Fatal("nested watch of the same stage replaced the watchdog")
+	}
+	inner()
+	if handler.watch != first {
+		t.Fatal("inner stop ended the outer watch")
+	}
+	outer()
+	if handler.watch != nil {
+		t.Error("watch still active after outer stop")
+	}
+
+	handler.watchStage("plan")
+	replaced := handler.watch
+	handler.watchStage("tasks")
+	if handler.watch == replaced || handler.watch.stage != "tasks" {
+		t.Error("watching a different stage did not replace the previous watch")
+	}
+}
+
+func TestHandler_WatchStage_Disabled(t *testing.T) {
+	t.Parallel()
+	tests := map[string]NotificationConfig{
+		"hook off":       {Enabled: true, OnLongRunning: false, LongRunningThreshold: time.Millisecond},
+		"zero threshold": {Enabled: true, OnLongRunning: true, LongRunningThreshold: 0},
+	}
+
+	for name, config := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			handler := NewHandlerWithSender(config, NewMockSender())
+			stop := handler.watchStage("plan")
+			defer stop()
+			if handler.watch != nil {
+				t.Error("watchdog started while disabled")
+			}
+		})
+	}
+
+	var nilHandler *Handler
+	nilHandler.WatchStage("plan")()
+	nilHandler.SetCurrentTask("T001")
+}
+
 func TestHandler_OnLongRunning_BelowThreshold(t *testing.T) {
 	t.Parallel()
 	config := DefaultConfig()
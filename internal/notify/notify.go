@@ -1,6 +1,12 @@
 package notify
 
-import "time"
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/redact"
+)
 
 // NotificationType represents the type of notification event
 type NotificationType string
@@ -57,16 +63,49 @@ type NotificationConfig struct {
 	// OnError notifies on command/stage failure (default: true when enabled)
 	OnError bool `koanf:"on_error" yaml:"on_error" json:"on_error"`
 
-	// OnLongRunning notifies only if duration exceeds threshold (default: false)
+	// OnBudgetExceeded notifies when a run stops because a budget limit was reached (default: true when enabled)
+	OnBudgetExceeded bool `koanf:"on_budget_exceeded" yaml:"on_budget_exceeded" json:"on_budget_exceeded"`
+
+	// OnRateLimited notifies when a run pauses to wait out a provider rate limit (default: true when enabled)
+	OnRateLimited bool `koanf:"on_rate_limited" yaml:"on_rate_limited" json:"on_rate_limited"`
+
+	// OnCancelled notifies when Ctrl+C or SIGTERM stops a command (default: true when enabled)
+	OnCancelled bool `koanf:"on_cancelled" yaml:"on_cancelled" json:"on_cancelled"`
+
+	// OnLongRunning notifies once when a stage runs past LongRunningThreshold, and
+	// limits command-complete notifications to commands exceeding it (default: false)
 	OnLongRunning bool `koanf:"on_long_running" yaml:"on_long_running" json:"on_long_running"`
 
-	// LongRunningThreshold is the threshold for on_long_running hook (default: 30s)
-	// A value of 0 or negative means "always notify"
+	// LongRunningThreshold is the threshold for on_long_running hook (default: 2m)
+	// A value of 0 or negative disables the stage watchdog and means "always notify" on completion
 	LongRunningThreshold time.Duration `koanf:"long_running_threshold" yaml:"long_running_threshold" json:"long_running_threshold"`
 
 	// OnInteractiveSession notifies when an interactive stage is about to begin (default: true when enabled)
 	// This alerts users to return to the terminal after automated stages complete.
 	OnInteractiveSession bool `koanf:"on_interactive_session" yaml:"on_interactive_session" json:"on_interactive_session"`
+
+	// MessageTemplate is a text/template over EventData that replaces the text of
+	// every notification (default: the built-in text followed by the spec, stage,
+	// task, failure category and artifact link)
+	MessageTemplate string `koanf:"message_template" yaml:"message_template" json:"message_template"`
+
+	// Link selects how the artifact link is written: file, vscode, cursor, or none (default: file)
+	Link string `koanf:"link" yaml:"link" json:"link"`
+
+	// Schedule defines quiet hours for visual and sound notifications
+	Schedule ScheduleConfig `koanf:"schedule" yaml:"schedule" json:"schedule"`
+
+	// Email configures the SMTP channel, which also works in CI and non-interactive sessions
+	Email EmailConfig `koanf:"email" yaml:"email" json:"email"`
+
+	// Ntfy configures mobile push through an ntfy topic (also works in CI)
+	Ntfy NtfyConfig `koanf:"ntfy" yaml:"ntfy" json:"ntfy"`
+
+	// Pushover configures mobile push through the Pushover API (also works in CI)
+	Pushover PushoverConfig `koanf:"pushover" yaml:"pushover" json:"pushover"`
+
+	// Redactor masks secrets in notification text; set from the redaction config on load
+	Redactor *redact.Redactor `koanf:"-" yaml:"-" json:"-"`
 }
 
 // DefaultConfig returns a NotificationConfig with default values
@@ -78,10 +117,52 @@ func DefaultConfig() NotificationConfig {
 		OnCommandComplete:    true,
 		OnStageComplete:      false,
 		OnError:              true,
+		OnBudgetExceeded:     true,
+		OnRateLimited:        true,
+		OnCancelled:          true,
 		OnLongRunning:        false,
 		LongRunningThreshold: 2 * time.Minute,
 		OnInteractiveSession: true,
+		Link:                 LinkFile,
+		Schedule: ScheduleConfig{
+			Mode: QuietSuppress,
+		},
+		Email: EmailConfig{
+			Port:     587,
+			StartTLS: true,
+		},
+		Ntfy: NtfyConfig{
+			Server:      defaultNtfyServer,
+			Priority:    "default",
+			MinInterval: defaultMinInterval,
+		},
+		Pushover: PushoverConfig{
+			MinInterval: defaultMinInterval,
+		},
+	}
+}
+
+// RemoteURLs returns the servers of the enabled remote channels (ntfy,
+// Pushover, SMTP as smtp://host:port), for the preflight network check.
+func (c NotificationConfig) RemoteURLs() []string {
+	if !c.Enabled {
+		return nil
+	}
+	var urls []string
+	if c.Ntfy.Enabled {
+		server := c.Ntfy.Server
+		if server == "" {
+			server = defaultNtfyServer
+		}
+		urls = append(urls, server)
+	}
+	if c.Pushover.Enabled {
+		urls = append(urls, defaultPushoverAPI)
+	}
+	if c.Email.Enabled && c.Email.Host != "" {
+		urls = append(urls, "smtp://"+net.JoinHostPort(c.Email.Host, strconv.Itoa(c.Email.Port)))
 	}
+	return urls
 }
 
 // Notification represents a single notification event to dispatch
@@ -104,3 +185,61 @@ func NewNotification(title, message string, notificationType NotificationType) N
 		NotificationType: notificationType,
 	}
 }
+
+// Notification events, passed to remote channels and used as keys for
+// per-event email templates.
+const (
+	EventCommandComplete    = "command_complete"
+	EventStageComplete      = "stage_complete"
+	EventError              = "error"
+	EventBudgetExceeded     = "budget_exceeded"
+	EventRateLimited        = "rate_limited"
+	EventCancelled          = "cancelled"
+	EventLongRunning        = "long_running"
+	EventInteractiveSession = "interactive_session"
+)
+
+// Events lists every notification event.
+var Events = []string{
+	EventCommandComplete,
+	EventStageComplete,
+	EventError,
+	EventBudgetExceeded,
+	EventRateLimited,
+	EventCancelled,
+	EventLongRunning,
+	EventInteractiveSession,
+}
+
+// isEvent reports whether name is one of Events.
+func isEvent(name string) bool {
+	for _, e := range Events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EventData describes a notification event for remote channels (email, push)
+// and is the data passed to email templates.
+type EventData struct {
+	Event     string // One of Events
+	Message   string // The desktop notification text
+	Status    string // success, failure, or info
+	Command   string // Command name for command_complete and error
+	Stage     string // Stage name for stage events
+	Duration  string // Elapsed time, when known
+	Task      string // Current task for long_running
+	Error     string // Full error text
+	Spec      string // Spec the run was working on
+	TaskID    string // Current task ID, or "phase N/M" when running by phase
+	TaskTitle string // Current task or phase title
+	Failure   string // Why the stage's last attempt failed: validation, agent_error, timeout or gate
+	Artifact  string // Absolute path of the artifact the stage writes
+	Link      string // Artifact as a file://, vscode:// or cursor:// link
+	RunID     string // Run ID for correlating with logs and `autospec replay`
+	Hostname  string
+	WorkDir   string
+	Time      string // RFC 3339 send time
+}
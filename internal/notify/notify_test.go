@@ -321,3 +321,52 @@ func TestNotificationStruct(t *testing.T) {
 		})
 	}
 }
+
+func TestNotificationConfig_RemoteURLs(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		config NotificationConfig
+		want   []string
+	}{
+		"notifications disabled": {
+			config: NotificationConfig{Ntfy: NtfyConfig{Enabled: true}},
+			want:   nil,
+		},
+		"no remote channels": {
+			config: NotificationConfig{Enabled: true},
+			want:   nil,
+		},
+		"all remote channels": {
+			config: NotificationConfig{
+				Enabled:  true,
+				Ntfy:     NtfyConfig{Enabled: true, Server: "https://ntfy.example.com"},
+				Pushover: PushoverConfig{Enabled: true},
+				Email:    EmailConfig{Enabled: true, Host: "smtp.example.com", Port: 465},
+			},
+			want: []string{"https://ntfy.example.com", defaultPushoverAPI, "smtp://smtp.example.com:465"},
+		},
+		"ntfy without server uses default": {
+			config: NotificationConfig{Enabled: true, Ntfy: NtfyConfig{Enabled: true}},
+			want:   []string{defaultNtfyServer},
+		},
+		"email without host is skipped": {
+			config: NotificationConfig{Enabled: true, Email: EmailConfig{Enabled: true, Port: 587}},
+			want:   nil,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.config.RemoteURLs()
+			if len(got) != len(tt.want) {
+				t.Fatalf("RemoteURLs() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("RemoteURLs()[%d] = %q, want %q", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Desktop channel names reported by TestChannels.
+const (
+	ChannelVisual = "desktop (visual)"
+	ChannelSound  = "desktop (sound)"
+)
+
+// ChannelResult is the outcome of sending a test notification to one channel.
+type ChannelResult struct {
+	Channel string // ChannelVisual, ChannelSound, "email", "ntfy" or "pushover"
+	Err     error  // nil when the notification was handed to the channel
+	Hint    string // How to fix a failure, e.g. the tool to install
+}
+
+// ParseEvent accepts an event name ("error") or its hook name ("on_error").
+func ParseEvent(name string) (string, error) {
+	event := strings.TrimPrefix(name, "on_")
+	if !isEvent(event) {
+		return "", fmt.Errorf("unknown event %q (valid: on_%s)", name, strings.Join(Events, ", on_"))
+	}
+	return event, nil
+}
+
+// TestChannels sends a sample notification for event to every configured
+// channel and reports per-channel delivery. Unlike the hooks, it ignores
+// notifications.enabled, the hook toggles, quiet hours and TTY/CI detection,
+// and it waits for each channel, so missing tools and bad credentials show up.
+func (h *Handler) TestChannels(event string) []ChannelResult {
+	n, data := h.redact(sampleNotification(event))
+
+	var results []ChannelResult
+	if h.config.Type == OutputVisual || h.config.Type == OutputBoth {
+		results = append(results, h.testVisual(n))
+	}
+	if h.config.Type == OutputSound || h.config.Type == OutputBoth {
+		results = append(results, h.testSound())
+	}
+
+	data.Message = n.Message
+	data.Status = string(n.NotificationType)
+	for _, ch := range remoteChannels(h.config) {
+		result := ChannelResult{Channel: ch.name, Err: ch.err}
+		if ch.err == nil {
+			result.Err = ch.sender.Send(data)
+		}
+		if result.Err != nil {
+			result.Hint = fmt.Sprintf("Check notifications.%s in your config", ch.name)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// DesktopSuppressedReason explains why workflow hooks would not show desktop
+// notifications in this session, or returns "" if they would.
+func (h *Handler) DesktopSuppressedReason() string {
+	switch {
+	case !h.config.Enabled:
+		return "notifications.enabled is false"
+	case isCI():
+		return "a CI environment was detected"
+	case !isInteractive():
+		return "the session is not interactive (no TTY)"
+	}
+	return ""
+}
+
+// testVisual shows n and reports whether the OS accepted it.
+func (h *Handler) testVisual(n Notification) ChannelResult {
+	result := ChannelResult{Channel: ChannelVisual}
+	if !h.sender.VisualAvailable() {
+		result.Err = errors.New("not available on this system")
+		result.Hint = visualRequirement()
+		return result
+	}
+	if err := h.sender.SendVisual(n); err != nil {
+		result.Err = err
+		result.Hint = visualRequirement()
+	}
+	return result
+}
+
+// testSound plays the configured sound and reports whether it could be played.
+func (h *Handler) testSound() ChannelResult {
+	result := ChannelResult{Channel: ChannelSound}
+	if !h.sender.SoundAvailable() {
+		result.Err = errors.New("not available on this system")
+		result.Hint = soundRequirement()
+		return result
+	}
+	if runtime.GOOS == "linux" && ValidateSoundFile(h.config.SoundFile) == "" {
+		// The Linux sender has no default sound and skips silently
+		result.Err = errors.New("no usable sound_file (Linux has no default notification sound)")
+		result.Hint = "Set notifications.sound_file to a .wav, .ogg or .flac file"
+		return result
+	}
+	if err := h.sender.SendSound(h.config.SoundFile); err != nil {
+		result.Err = err
+		result.Hint = soundRequirement()
+	}
+	return result
+}
+
+// sampleNotification returns the notification a hook would send for event,
+// with sample values.
+func sampleNotification(event string) (Notification, EventData) {
+	const command, stage = "implement", "implement"
+	data := EventData{Event: event}
+	var n Notification
+	switch event {
+	case EventStageComplete:
+		n = NewNotification("autospec", fmt.Sprintf("Stage '%s' completed", stage), TypeSuccess)
+		data.Stage = stage
+	case EventError:
+		data.Command, data.Error = command, "sample error from 'autospec notify test'"
+		n = NewNotification("autospec", fmt.Sprintf("Error in '%s': %s", command, data.Error), TypeFailure)
+	case EventBudgetExceeded:
+		data.Stage, data.Error = stage, "sample budget limit from 'autospec notify test'"
+		n = NewNotification("autospec", fmt.Sprintf("Stopped before '%s': %s", stage, data.Error), TypeFailure)
+	case EventRateLimited:
+		n = rateLimitedNotification(stage, 2*time.Minute)
+		data.Stage, data.Duration = stage, formatDuration(2*time.Minute)
+	case EventCancelled:
+		data.Command, data.Duration = command, formatDuration(90*time.Second)
+		n = NewNotification("autospec", fmt.Sprintf("Command '%s' cancelled after %s; rerun it to resume", command, data.Duration), TypeInfo)
+	case EventLongRunning:
+		n = longRunningNotification(stage, 5*time.Minute, "T001")
+		data.Stage, data.Duration, data.Task = stage, formatDuration(5*time.Minute), "T001"
+	case EventInteractiveSession:
+		n = NewNotification("autospec", "Interactive session starting: clarify (your input required)", TypeInfo)
+		data.Stage = "clarify"
+	default:
+		data.Event = EventCommandComplete
+		data.Command, data.Duration = command, formatDuration(90*time.Second)
+		n = NewNotification("autospec", fmt.Sprintf("Command '%s' completed successfully (%s)", command, data.Duration), TypeSuccess)
+	}
+	return n, data
+}
+
+// visualRequirement describes what desktop notifications need on this OS.
+func visualRequirement() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "Install notify-send (libnotify-bin / libnotify) and run inside a graphical session (DISPLAY or WAYLAND_DISPLAY set)"
+	case "darwin":
+		return "osascript must be on PATH and your terminal allowed to post notifications (System Settings > Notifications)"
+	case "windows":
+		return "powershell must be on PATH and toast notifications allowed (Settings > System > Notifications)"
+	}
+	return fmt.Sprintf("Desktop notifications are not supported on %s", runtime.GOOS)
+}
+
+// soundRequirement describes what sound notifications need on this OS.
+func soundRequirement() string {
+	switch runtime.GOOS {
+	case "linux":
+		return "Install paplay (pulseaudio-utils or pipewire-pulse) and set notifications.sound_file"
+	case "darwin":
+		return "afplay must be on PATH (part of macOS)"
+	case "windows":
+		return "powershell must be on PATH (sounds play through System.Media.SoundPlayer)"
+	}
+	return fmt.Sprintf("Sound notifications are not supported on %s", runtime.GOOS)
+}
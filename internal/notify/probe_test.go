@@ -0,0 +1,142 @@
+// Package notify_test tests sending sample notifications to each channel.
+// Related: internal/notify/probe.go
+// Tags: notify, probe, channels, test-command
+
+package notify
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestParseEvent(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		"hook name":  {input: "on_error", want: EventError},
+		"event name": {input: "stage_complete", want: EventStageComplete},
+		"unknown":    {input: "on_deploy", wantErr: true},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseEvent(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseEvent(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseEvent(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTestChannels_Desktop(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultConfig()
+	config.Type = OutputVisual
+	sender := NewMockSender()
+	handler := NewHandlerWithSender(config, sender)
+
+	// Sent even though notifications.enabled is false
+	results := handler.TestChannels(EventError)
+	if len(results) != 1 || results[0].Channel != ChannelVisual || results[0].Err != nil {
+		t.Fatalf("results = %+v, want one successful visual result", results)
+	}
+	if sender.VisualCallCount != 1 || !strings.Contains(sender.LastNotification.Message, "Error in 'implement'") {
+		t.Errorf("visual notification = %+v", sender.LastNotification)
+	}
+
+	unavailable := NewHandlerWithSender(config, NewMockSender().WithVisualAvailable(false))
+	results = unavailable.TestChannels(EventCommandComplete)
+	if len(results) != 1 || results[0].Err == nil || results[0].Hint == "" {
+		t.Errorf("unavailable visual result = %+v, want error with hint", results)
+	}
+
+	failing := NewHandlerWithSender(config, NewMockSender().WithVisualError(errors.New("dbus error")))
+	results = failing.TestChannels(EventCommandComplete)
+	if len(results) != 1 || results[0].Err == nil || results[0].Err.Error() != "dbus error" {
+		t.Errorf("failing visual result = %+v, want dbus error", results)
+	}
+}
+
+func TestTestChannels_Sound(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultConfig()
+	config.Type = OutputSound
+	sender := NewMockSender()
+	results := NewHandlerWithSender(config, sender).TestChannels(EventCommandComplete)
+
+	if len(results) != 1 || results[0].Channel != ChannelSound {
+		t.Fatalf("results = %+v, want one sound result", results)
+	}
+	if runtime.GOOS == "linux" {
+		// No default sound on Linux: reported instead of silently skipped
+		if results[0].Err == nil || sender.SoundCallCount != 0 {
+			t.Errorf("linux without sound_file: result = %+v, sound calls = %d", results[0], sender.SoundCallCount)
+		}
+		return
+	}
+	if results[0].Err != nil || sender.SoundCallCount != 1 {
+		t.Errorf("result = %+v, sound calls = %d", results[0], sender.SoundCallCount)
+	}
+}
+
+func TestTestChannels_Remote(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.Type = ""
+	config.Ntfy = NtfyConfig{Enabled: true, Server: server.URL, Topic: "builds"}
+	config.Pushover = PushoverConfig{Enabled: true} // missing token and user
+
+	results := NewHandlerWithSender(config, NewMockSender()).TestChannels(EventBudgetExceeded)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2: %+v", len(results), results)
+	}
+	if results[0].Channel != "ntfy" || results[0].Err != nil {
+		t.Errorf("ntfy result = %+v, want success", results[0])
+	}
+	if results[1].Channel != "pushover" || results[1].Err == nil || !strings.Contains(results[1].Hint, "notifications.pushover") {
+		t.Errorf("pushover result = %+v, want config error with hint", results[1])
+	}
+	if len(bodies) != 1 || !strings.Contains(bodies[0], "Stopped before 'implement'") {
+		t.Errorf("ntfy bodies = %q", bodies)
+	}
+}
+
+func TestSampleNotification_CoversEveryEvent(t *testing.T) {
+	t.Parallel()
+
+	for _, event := range Events {
+		n, data := sampleNotification(event)
+		if data.Event != event {
+			t.Errorf("sampleNotification(%q).Event = %q", event, data.Event)
+		}
+		if n.Message == "" {
+			t.Errorf("sampleNotification(%q) has no message", event)
+		}
+	}
+}
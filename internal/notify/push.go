@@ -0,0 +1,318 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// pushTimeout bounds each push API request.
+const pushTimeout = 10 * time.Second
+
+// Provider message limits. Longer text is truncated with an ellipsis.
+const (
+	ntfyMaxMessage      = 4096 // bytes; ntfy turns longer bodies into attachments
+	pushoverMaxMessage  = 1024 // characters
+	pushoverMaxTitle    = 250  // characters
+	defaultNtfyServer   = "https://ntfy.sh"
+	defaultPushoverAPI  = "https://api.pushover.net/1/messages.json"
+	defaultMinInterval  = 30 * time.Second
+	truncationIndicator = "…"
+)
+
+// NtfyPriorities lists the priorities accepted by ntfy.
+var NtfyPriorities = []string{"min", "low", "default", "high", "urgent"}
+
+// NtfyConfig configures push notifications through an ntfy topic.
+type NtfyConfig struct {
+	// Enabled turns on ntfy delivery (requires notifications.enabled)
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// Server is the ntfy server URL (default: https://ntfy.sh)
+	Server string `koanf:"server" yaml:"server" json:"server"`
+
+	// Topic is the topic name subscribed to in the ntfy app
+	Topic string `koanf:"topic" yaml:"topic" json:"topic"`
+
+	// Token is an access token for protected topics; prefer AUTOSPEC_NOTIFICATIONS_NTFY_TOKEN
+	Token string `koanf:"token" yaml:"token" json:"token"`
+
+	// Priority is min, low, default, high or urgent (default: default)
+	Priority string `koanf:"priority" yaml:"priority" json:"priority"`
+
+	// MinInterval is the minimum time between pushes; extra pushes are dropped,
+	// except command_complete (default: 30s, 0 disables rate limiting)
+	MinInterval time.Duration `koanf:"min_interval" yaml:"min_interval" json:"min_interval"`
+}
+
+// Validate checks that an enabled ntfy config can be used to push.
+func (c NtfyConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Topic == "" {
+		return errors.New("topic is required")
+	}
+	if c.Server != "" {
+		u, err := url.Parse(c.Server)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("server %q must be an http(s) URL", c.Server)
+		}
+	}
+	if c.Priority != "" && !containsString(NtfyPriorities, c.Priority) {
+		return fmt.Errorf("priority %q must be one of: %s", c.Priority, strings.Join(NtfyPriorities, ", "))
+	}
+	if c.MinInterval < 0 {
+		return errors.New("min_interval must not be negative")
+	}
+	return nil
+}
+
+// PushoverConfig configures push notifications through the Pushover API.
+type PushoverConfig struct {
+	// Enabled turns on Pushover delivery (requires notifications.enabled)
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// Token is the application API token; prefer AUTOSPEC_NOTIFICATIONS_PUSHOVER_TOKEN
+	Token string `koanf:"token" yaml:"token" json:"token"`
+
+	// User is the user or group key; prefer AUTOSPEC_NOTIFICATIONS_PUSHOVER_USER
+	User string `koanf:"user" yaml:"user" json:"user"`
+
+	// Device limits delivery to one device name (empty = all devices)
+	Device string `koanf:"device" yaml:"device" json:"device"`
+
+	// Priority is -2 (silent) through 1 (high); emergency (2) is not supported
+	Priority int `koanf:"priority" yaml:"priority" json:"priority"`
+
+	// MinInterval is the minimum time between pushes; extra pushes are dropped,
+	// except command_complete (default: 30s, 0 disables rate limiting)
+	MinInterval time.Duration `koanf:"min_interval" yaml:"min_interval" json:"min_interval"`
+}
+
+// Validate checks that an enabled Pushover config can be used to push.
+func (c PushoverConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.Token == "" {
+		return errors.New("token is required")
+	}
+	if c.User == "" {
+		return errors.New("user is required")
+	}
+	if c.Priority < -2 || c.Priority > 1 {
+		return fmt.Errorf("priority %d must be between -2 and 1", c.Priority)
+	}
+	if c.MinInterval < 0 {
+		return errors.New("min_interval must not be negative")
+	}
+	return nil
+}
+
+// rateLimiter drops pushes sent within minInterval of the previous push.
+// Errors and the final command_complete summary are always delivered and do
+// not count against the interval.
+type rateLimiter struct {
+	minInterval time.Duration
+	now         func() time.Time
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func newRateLimiter(minInterval time.Duration) *rateLimiter {
+	return &rateLimiter{minInterval: minInterval, now: time.Now}
+}
+
+// allow reports whether a push for event may be sent now, and records it if so.
+func (r *rateLimiter) allow(event string) bool {
+	if event == EventError || event == EventCommandComplete {
+		return true
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := r.now()
+	if r.minInterval > 0 && !r.last.IsZero() && now.Sub(r.last) < r.minInterval {
+		return false
+	}
+	r.last = now
+	return true
+}
+
+// NtfyNotifier publishes notifications to an ntfy topic.
+type NtfyNotifier struct {
+	cfg     NtfyConfig
+	url     string
+	client  *http.Client
+	limiter *rateLimiter
+}
+
+// NewNtfyNotifier validates cfg and returns a notifier for its topic.
+func NewNtfyNotifier(cfg NtfyConfig) (*NtfyNotifier, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	server := cfg.Server
+	if server == "" {
+		server = defaultNtfyServer
+	}
+	return &NtfyNotifier{
+		cfg:     cfg,
+		url:     strings.TrimRight(server, "/") + "/" + url.PathEscape(cfg.Topic),
+		client:  &http.Client{Timeout: pushTimeout},
+		limiter: newRateLimiter(cfg.MinInterval),
+	}, nil
+}
+
+// Name identifies the channel in log messages.
+func (n *NtfyNotifier) Name() string { return "ntfy" }
+
+// Send publishes data.Message to the topic. Rate-limited events are dropped.
+func (n *NtfyNotifier) Send(data EventData) error {
+	if !n.limiter.allow(data.Event) {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, strings.NewReader(truncateBytes(data.Message, ntfyMaxMessage)))
+	if err != nil {
+		return fmt.Errorf("building ntfy request: %w", err)
+	}
+	req.Header.Set("Title", pushTitle(data))
+	req.Header.Set("Tags", ntfyTag(data.Status))
+	if n.cfg.Priority != "" {
+		req.Header.Set("Priority", n.cfg.Priority)
+	}
+	if n.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.cfg.Token)
+	}
+	return doPush(n.client, req, "ntfy")
+}
+
+// ntfyTag maps a notification status to an ntfy emoji tag.
+func ntfyTag(status string) string {
+	switch NotificationType(status) {
+	case TypeSuccess:
+		return "white_check_mark"
+	case TypeFailure:
+		return "x"
+	default:
+		return "hourglass_flowing_sand"
+	}
+}
+
+// PushoverNotifier sends notifications through the Pushover messages API.
+type PushoverNotifier struct {
+	cfg     PushoverConfig
+	apiURL  string
+	client  *http.Client
+	limiter *rateLimiter
+}
+
+// NewPushoverNotifier validates cfg and returns a notifier.
+func NewPushoverNotifier(cfg PushoverConfig) (*PushoverNotifier, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &PushoverNotifier{
+		cfg:     cfg,
+		apiURL:  defaultPushoverAPI,
+		client:  &http.Client{Timeout: pushTimeout},
+		limiter: newRateLimiter(cfg.MinInterval),
+	}, nil
+}
+
+// Name identifies the channel in log messages.
+func (p *PushoverNotifier) Name() string { return "pushover" }
+
+// Send posts data.Message to Pushover. Rate-limited events are dropped.
+func (p *PushoverNotifier) Send(data EventData) error {
+	if !p.limiter.allow(data.Event) {
+		return nil
+	}
+
+	form := url.Values{
+		"token":    {p.cfg.Token},
+		"user":     {p.cfg.User},
+		"title":    {truncateRunes(pushTitle(data), pushoverMaxTitle)},
+		"message":  {truncateRunes(data.Message, pushoverMaxMessage)},
+		"priority": {strconv.Itoa(p.cfg.Priority)},
+	}
+	if p.cfg.Device != "" {
+		form.Set("device", p.cfg.Device)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, p.apiURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("building pushover request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doPush(p.client, req, "pushover")
+}
+
+// pushTitle is the notification title shown on the phone.
+func pushTitle(data EventData) string {
+	switch {
+	case data.Command != "":
+		return "autospec " + data.Command
+	case data.Stage != "":
+		return "autospec " + data.Stage
+	default:
+		return "autospec"
+	}
+}
+
+// doPush sends req and converts non-2xx responses into errors with the
+// provider's response text.
+func doPush(client *http.Client, req *http.Request, provider string) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending %s request: %w", provider, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s returned %s: %s", provider, resp.Status, strings.TrimSpace(string(body)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// truncateRunes shortens s to at most max characters, ending with an ellipsis.
+func truncateRunes(s string, max int) string {
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:max-1]) + truncationIndicator
+}
+
+// truncateBytes shortens s to at most max bytes without splitting a character,
+// ending with an ellipsis.
+func truncateBytes(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max - len(truncationIndicator)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + truncationIndicator
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,299 @@
+package notify
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// pushRequest is a request captured by newPushServer.
+type pushRequest struct {
+	path   string
+	header http.Header
+	body   string
+}
+
+// newPushServer records requests and answers with status.
+func newPushServer(t *testing.T, status int) (*httptest.Server, func() []pushRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var reqs []pushRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		reqs = append(reqs, pushRequest{path: r.URL.Path, header: r.Header.Clone(), body: string(body)})
+		mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":"invalid topic"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []pushRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]pushRequest(nil), reqs...)
+	}
+}
+
+func TestNtfyConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     NtfyConfig
+		wantErr string
+	}{
+		"disabled":        {cfg: NtfyConfig{}},
+		"valid":           {cfg: NtfyConfig{Enabled: true, Topic: "autospec-ari", Priority: "high"}},
+		"missing topic":   {cfg: NtfyConfig{Enabled: true}, wantErr: "topic is required"},
+		"bad server":      {cfg: NtfyConfig{Enabled: true, Topic: "t", Server: "ntfy.sh"}, wantErr: "http(s) URL"},
+		"bad priority":    {cfg: NtfyConfig{Enabled: true, Topic: "t", Priority: "max"}, wantErr: "priority"},
+		"negative window": {cfg: NtfyConfig{Enabled: true, Topic: "t", MinInterval: -time.Second}, wantErr: "min_interval"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPushoverConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     PushoverConfig
+		wantErr string
+	}{
+		"disabled":           {cfg: PushoverConfig{}},
+		"valid":              {cfg: PushoverConfig{Enabled: true, Token: "app", User: "user", Priority: 1}},
+		"missing token":      {cfg: PushoverConfig{Enabled: true, User: "user"}, wantErr: "token is required"},
+		"missing user":       {cfg: PushoverConfig{Enabled: true, Token: "app"}, wantErr: "user is required"},
+		"emergency priority": {cfg: PushoverConfig{Enabled: true, Token: "app", User: "user", Priority: 2}, wantErr: "between -2 and 1"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNtfyNotifier_Send(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPushServer(t, http.StatusOK)
+	notifier, err := NewNtfyNotifier(NtfyConfig{
+		Enabled:  true,
+		Server:   server.URL + "/",
+		Topic:    "autospec-builds",
+		Token:    "tk_secret",
+		Priority: "high",
+	})
+	if err != nil {
+		t.Fatalf("NewNtfyNotifier() error: %v", err)
+	}
+
+	err = notifier.Send(EventData{
+		Event:   EventCommandComplete,
+		Command: "implement",
+		Message: "Command 'implement' completed successfully (1.2h)",
+		Status:  string(TypeSuccess),
+	})
+	if err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	req := reqs[0]
+	if req.path != "/autospec-builds" {
+		t.Errorf("path = %q, want /autospec-builds", req.path)
+	}
+	if req.body != "Command 'implement' completed successfully (1.2h)" {
+		t.Errorf("body = %q", req.body)
+	}
+	wantHeaders := map[string]string{
+		"Title":         "autospec implement",
+		"Priority":      "high",
+		"Tags":          "white_check_mark",
+		"Authorization": "Bearer tk_secret",
+	}
+	for key, want := range wantHeaders {
+		if got := req.header.Get(key); got != want {
+			t.Errorf("header %s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestNtfyNotifier_Send_ErrorStatus(t *testing.T) {
+	t.Parallel()
+
+	server, _ := newPushServer(t, http.StatusBadRequest)
+	notifier, err := NewNtfyNotifier(NtfyConfig{Enabled: true, Server: server.URL, Topic: "t"})
+	if err != nil {
+		t.Fatalf("NewNtfyNotifier() error: %v", err)
+	}
+
+	err = notifier.Send(EventData{Event: EventError, Message: "boom"})
+	if err == nil || !strings.Contains(err.Error(), "ntfy returned 400") || !strings.Contains(err.Error(), "invalid topic") {
+		t.Errorf("Send() error = %v, want status and response text", err)
+	}
+}
+
+func TestPushoverNotifier_Send(t *testing.T) {
+	t.Parallel()
+
+	server, requests := newPushServer(t, http.StatusOK)
+	notifier, err := NewPushoverNotifier(PushoverConfig{
+		Enabled:  true,
+		Token:    "app-token",
+		User:     "user-key",
+		Device:   "phone",
+		Priority: 1,
+	})
+	if err != nil {
+		t.Fatalf("NewPushoverNotifier() error: %v", err)
+	}
+	notifier.apiURL = server.URL + "/1/messages.json"
+
+	long := strings.Repeat("é", 1500)
+	err = notifier.Send(EventData{Event: EventError, Command: "run", Message: long, Status: string(TypeFailure)})
+	if err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("got %d requests, want 1", len(reqs))
+	}
+	form, err := url.ParseQuery(reqs[0].body)
+	if err != nil {
+		t.Fatalf("parsing form: %v", err)
+	}
+	wantFields := map[string]string{
+		"token":    "app-token",
+		"user":     "user-key",
+		"device":   "phone",
+		"priority": "1",
+		"title":    "autospec run",
+	}
+	for key, want := range wantFields {
+		if got := form.Get(key); got != want {
+			t.Errorf("form %s = %q, want %q", key, got, want)
+		}
+	}
+	msg := form.Get("message")
+	if n := utf8.RuneCountInString(msg); n != pushoverMaxMessage {
+		t.Errorf("message length = %d characters, want %d", n, pushoverMaxMessage)
+	}
+	if !strings.HasSuffix(msg, truncationIndicator) {
+		t.Error("truncated message does not end with an ellipsis")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := newRateLimiter(30 * time.Second)
+	limiter.now = func() time.Time { return now }
+
+	if !limiter.allow(EventStageComplete) {
+		t.Fatal("first push dropped")
+	}
+	now = now.Add(10 * time.Second)
+	if limiter.allow(EventStageComplete) {
+		t.Error("push within min_interval was allowed")
+	}
+	if !limiter.allow(EventError) {
+		t.Error("error was rate limited")
+	}
+	if !limiter.allow(EventCommandComplete) {
+		t.Error("command_complete summary was rate limited")
+	}
+	now = now.Add(21 * time.Second)
+	if !limiter.allow(EventStageComplete) {
+		t.Error("push after min_interval was dropped")
+	}
+
+	unlimited := newRateLimiter(0)
+	for i := 0; i < 3; i++ {
+		if !unlimited.allow(EventStageComplete) {
+			t.Fatal("push dropped with rate limiting disabled")
+		}
+	}
+}
+
+func TestTruncateBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+		max   int
+		want  string
+	}{
+		"short unchanged":      {input: "done", max: 10, want: "done"},
+		"ascii cut":            {input: "abcdefghij", max: 8, want: "abcde…"},
+		"no split multibyte":   {input: "ééééé", max: 8, want: "éé…"},
+		"exact limit keeps it": {input: "abcdefgh", max: 8, want: "abcdefgh"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := truncateBytes(tt.input, tt.max)
+			if got != tt.want {
+				t.Errorf("truncateBytes(%q, %d) = %q, want %q", tt.input, tt.max, got, tt.want)
+			}
+			if len(got) > tt.max || !utf8.ValidString(got) {
+				t.Errorf("truncateBytes(%q, %d) = %q exceeds limit or is invalid UTF-8", tt.input, tt.max, got)
+			}
+		})
+	}
+}
+
+func TestNewHandler_PushChannels(t *testing.T) {
+	t.Parallel()
+
+	config := DefaultConfig()
+	config.Enabled = true
+	config.Ntfy.Enabled = true
+	config.Ntfy.Topic = "autospec"
+	config.Pushover.Enabled = true // missing token and user: disabled with a warning
+
+	h := NewHandler(config)
+	if len(h.remote) != 1 || h.remote[0].Name() != "ntfy" {
+		var names []string
+		for _, r := range h.remote {
+			names = append(names, r.Name())
+		}
+		t.Errorf("remote channels = %v, want [ntfy]", names)
+	}
+}
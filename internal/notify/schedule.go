@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Quiet-hours modes for desktop notifications.
+const (
+	// QuietSuppress drops desktop notifications during quiet hours
+	QuietSuppress = "suppress"
+	// QuietLog writes desktop notifications to the log instead of showing them
+	QuietLog = "log"
+)
+
+// ScheduleConfig defines quiet hours during which visual and sound
+// notifications are suppressed or downgraded to log-only. Email and push
+// channels are not affected. Times use the local time zone.
+type ScheduleConfig struct {
+	// QuietHours is a daily window such as "22:00-08:00" (empty disables)
+	QuietHours string `koanf:"quiet_hours" yaml:"quiet_hours" json:"quiet_hours"`
+
+	// QuietDays lists whole days that are quiet: day names (monday or mon),
+	// "weekends" or "weekdays"
+	QuietDays []string `koanf:"quiet_days" yaml:"quiet_days" json:"quiet_days"`
+
+	// Mode is "suppress" (default) or "log"
+	Mode string `koanf:"mode" yaml:"mode" json:"mode"`
+
+	// AlwaysNotify lists hooks that ignore quiet hours (e.g., on_error)
+	AlwaysNotify []string `koanf:"always_notify" yaml:"always_notify" json:"always_notify"`
+}
+
+// Validate checks the quiet-hours window, day names, mode and hook names.
+func (c ScheduleConfig) Validate() error {
+	if c.QuietHours != "" {
+		if _, _, err := parseQuietHours(c.QuietHours); err != nil {
+			return err
+		}
+	}
+	if _, err := parseQuietDays(c.QuietDays); err != nil {
+		return err
+	}
+	if c.Mode != "" && c.Mode != QuietSuppress && c.Mode != QuietLog {
+		return fmt.Errorf("mode %q must be one of: %s, %s", c.Mode, QuietSuppress, QuietLog)
+	}
+	for _, hook := range c.AlwaysNotify {
+		if !isEvent(strings.TrimPrefix(hook, "on_")) || !strings.HasPrefix(hook, "on_") {
+			return fmt.Errorf("always_notify: unknown hook %q (valid: on_%s)", hook, strings.Join(Events, ", on_"))
+		}
+	}
+	return nil
+}
+
+// IsQuiet reports whether t falls on a quiet day or inside the quiet-hours window.
+// Invalid settings are treated as not quiet; Validate reports them.
+func (c ScheduleConfig) IsQuiet(t time.Time) bool {
+	if days, err := parseQuietDays(c.QuietDays); err == nil && days[t.Weekday()] {
+		return true
+	}
+	if c.QuietHours == "" {
+		return false
+	}
+	start, end, err := parseQuietHours(c.QuietHours)
+	if err != nil {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if start < end {
+		return minute >= start && minute < end
+	}
+	// Window wraps past midnight (e.g., 22:00-08:00)
+	return minute >= start || minute < end
+}
+
+// Bypasses reports whether event is listed in AlwaysNotify.
+func (c ScheduleConfig) Bypasses(event string) bool {
+	for _, hook := range c.AlwaysNotify {
+		if hook == "on_"+event {
+			return true
+		}
+	}
+	return false
+}
+
+// parseQuietHours parses "HH:MM-HH:MM" into start and end minutes after midnight.
+func parseQuietHours(s string) (start, end int, err error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("quiet_hours %q must look like 22:00-08:00", s)
+	}
+	if start, err = parseClock(strings.TrimSpace(from)); err != nil {
+		return 0, 0, fmt.Errorf("quiet_hours %q: %w", s, err)
+	}
+	if end, err = parseClock(strings.TrimSpace(to)); err != nil {
+		return 0, 0, fmt.Errorf("quiet_hours %q: %w", s, err)
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("quiet_hours %q: start and end must differ", s)
+	}
+	return start, end, nil
+}
+
+// parseClock parses "HH:MM" (24-hour) into minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q (use 24-hour HH:MM)", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseQuietDays expands day names and the weekends/weekdays shortcuts.
+func parseQuietDays(names []string) (map[time.Weekday]bool, error) {
+	days := make(map[time.Weekday]bool)
+	for _, name := range names {
+		switch n := strings.ToLower(strings.TrimSpace(name)); n {
+		case "weekends", "weekend":
+			days[time.Saturday] = true
+			days[time.Sunday] = true
+		case "weekdays":
+			for d := time.Monday; d <= time.Friday; d++ {
+				days[d] = true
+			}
+		default:
+			day, ok := parseWeekday(n)
+			if !ok {
+				return nil, fmt.Errorf("quiet_days: unknown day %q", name)
+			}
+			days[day] = true
+		}
+	}
+	return days, nil
+}
+
+// parseWeekday accepts full ("monday") or three-letter ("mon") day names.
+func parseWeekday(name string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		full := strings.ToLower(d.String())
+		if name == full || name == full[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
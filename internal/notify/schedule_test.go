@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduleConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     ScheduleConfig
+		wantErr string
+	}{
+		"empty":            {cfg: ScheduleConfig{}},
+		"overnight window": {cfg: ScheduleConfig{QuietHours: "22:00-08:00", Mode: QuietLog}},
+		"spaces allowed":   {cfg: ScheduleConfig{QuietHours: "12:30 - 13:30"}},
+		"days and hooks": {cfg: ScheduleConfig{
+			QuietDays:    []string{"weekends", "Fri"},
+			AlwaysNotify: []string{"on_error", "on_budget_exceeded"},
+		}},
+		"missing dash":   {cfg: ScheduleConfig{QuietHours: "22:00"}, wantErr: "must look like 22:00-08:00"},
+		"bad clock":      {cfg: ScheduleConfig{QuietHours: "10pm-8am"}, wantErr: "24-hour HH:MM"},
+		"empty window":   {cfg: ScheduleConfig{QuietHours: "08:00-08:00"}, wantErr: "start and end must differ"},
+		"unknown day":    {cfg: ScheduleConfig{QuietDays: []string{"someday"}}, wantErr: `unknown day "someday"`},
+		"unknown mode":   {cfg: ScheduleConfig{Mode: "mute"}, wantErr: `mode "mute"`},
+		"unknown hook":   {cfg: ScheduleConfig{AlwaysNotify: []string{"on_finish"}}, wantErr: `unknown hook "on_finish"`},
+		"event not hook": {cfg: ScheduleConfig{AlwaysNotify: []string{"error"}}, wantErr: `unknown hook "error"`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScheduleConfig_IsQuiet(t *testing.T) {
+	t.Parallel()
+
+	// 2026-01-07 is a Wednesday, 2026-01-10 a Saturday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 1, day, hour, minute, 0, 0, time.Local)
+	}
+
+	tests := map[string]struct {
+		cfg  ScheduleConfig
+		at   time.Time
+		want bool
+	}{
+		"no schedule":                {cfg: ScheduleConfig{}, at: at(7, 23, 0), want: false},
+		"overnight before midnight":  {cfg: ScheduleConfig{QuietHours: "22:00-08:00"}, at: at(7, 23, 15), want: true},
+		"overnight after midnight":   {cfg: ScheduleConfig{QuietHours: "22:00-08:00"}, at: at(7, 7, 59), want: true},
+		"overnight end is exclusive": {cfg: ScheduleConfig{QuietHours: "22:00-08:00"}, at: at(7, 8, 0), want: false},
+		"overnight daytime":          {cfg: ScheduleConfig{QuietHours: "22:00-08:00"}, at: at(7, 14, 0), want: false},
+		"same-day window":            {cfg: ScheduleConfig{QuietHours: "12:00-13:00"}, at: at(7, 12, 30), want: true},
+		"same-day outside":           {cfg: ScheduleConfig{QuietHours: "12:00-13:00"}, at: at(7, 13, 30), want: false},
+		"weekend all day":            {cfg: ScheduleConfig{QuietDays: []string{"weekends"}}, at: at(10, 14, 0), want: true},
+		"weekday not in weekends":    {cfg: ScheduleConfig{QuietDays: []string{"weekends"}}, at: at(7, 14, 0), want: false},
+		"named day":                  {cfg: ScheduleConfig{QuietDays: []string{"wednesday"}}, at: at(7, 9, 0), want: true},
+		"invalid window ignored":     {cfg: ScheduleConfig{QuietHours: "late"}, at: at(7, 23, 0), want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := tt.cfg.IsQuiet(tt.at); got != tt.want {
+				t.Errorf("IsQuiet(%s) = %v, want %v", tt.at.Format("Mon 15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandler_SendDesktop_QuietHours(t *testing.T) {
+	t.Parallel()
+
+	quietNight := time.Date(2026, 1, 7, 23, 0, 0, 0, time.Local)
+	daytime := time.Date(2026, 1, 7, 14, 0, 0, 0, time.Local)
+
+	tests := map[string]struct {
+		now      time.Time
+		event    string
+		wantSent bool
+	}{
+		"outside quiet hours":          {now: daytime, event: EventCommandComplete, wantSent: true},
+		"suppressed in quiet hours":    {now: quietNight, event: EventCommandComplete, wantSent: false},
+		"always_notify bypasses quiet": {now: quietNight, event: EventError, wantSent: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			config := DefaultConfig()
+			config.Enabled = true
+			config.Type = OutputVisual
+			config.Schedule = ScheduleConfig{
+				QuietHours:   "22:00-08:00",
+				Mode:         QuietLog,
+				AlwaysNotify: []string{"on_error"},
+			}
+
+			mock := NewMockSender()
+			handler := NewHandlerWithSender(config, mock)
+			handler.now = func() time.Time { return tt.now }
+
+			handler.sendDesktop(NewNotification("autospec", "done", TypeSuccess), tt.event)
+
+			if sent := mock.AssertVisualCalled(); sent != tt.wantSent {
+				t.Errorf("visual notification sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
@@ -1,12 +1,13 @@
 package notify
 
 import (
-	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/logging"
 )
 
 // Sender defines the interface for platform-specific notification senders
@@ -82,23 +83,23 @@ func ValidateSoundFile(soundFile string) string {
 	info, err := os.Stat(soundFile)
 	if err != nil {
 		if os.IsNotExist(err) {
-			log.Printf("[notify] warning: custom sound file not found: %s, falling back to default", soundFile)
+			logging.For("notify").Warn("custom sound file not found, using default", "path", soundFile)
 		} else {
-			log.Printf("[notify] warning: cannot access custom sound file %s: %v, falling back to default", soundFile, err)
+			logging.For("notify").Warn("cannot access custom sound file, using default", "path", soundFile, "error", err)
 		}
 		return ""
 	}
 
 	// Check if it's a regular file (not a directory)
 	if info.IsDir() {
-		log.Printf("[notify] warning: sound path is a directory, not a file: %s, falling back to default", soundFile)
+		logging.For("notify").Warn("sound path is a directory, using default", "path", soundFile)
 		return ""
 	}
 
 	// Validate file extension
 	ext := strings.ToLower(filepath.Ext(soundFile))
 	if !supportedAudioExtensions[ext] {
-		log.Printf("[notify] warning: unsupported audio format '%s' for file: %s, falling back to default", ext, soundFile)
+		logging.For("notify").Warn("unsupported audio format, using default", "format", ext, "path", soundFile)
 		return ""
 	}
 
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 )
 
 // SettingsStatus represents the state of OpenCode settings configuration.
@@ -303,7 +305,7 @@ func (s *Settings) Save() error {
 		return fmt.Errorf("serializing settings: %w", err)
 	}
 
-	return atomicWrite(s.filePath, data)
+	return fsutil.WriteFile(s.filePath, data, 0o600)
 }
 
 // marshalWithExtra marshals the settings while preserving extra fields.
@@ -332,37 +334,3 @@ func (s *Settings) marshalWithExtra() ([]byte, error) {
 	data = append(data, '\n')
 	return data, nil
 }
-
-// atomicWrite writes data to a file atomically using temp file + rename.
-func atomicWrite(filePath string, data []byte) error {
-	dir := filepath.Dir(filePath)
-	tmpFile, err := os.CreateTemp(dir, ".settings-*.tmp")
-	if err != nil {
-		return fmt.Errorf("creating temp file: %w", err)
-	}
-	tmpPath := tmpFile.Name()
-
-	// Clean up temp file on any error
-	defer func() {
-		if tmpPath != "" {
-			os.Remove(tmpPath)
-		}
-	}()
-
-	if _, err := tmpFile.Write(data); err != nil {
-		tmpFile.Close()
-		return fmt.Errorf("writing temp file: %w", err)
-	}
-
-	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("closing temp file: %w", err)
-	}
-
-	if err := os.Rename(tmpPath, filePath); err != nil {
-		return fmt.Errorf("renaming temp file to %s: %w", filePath, err)
-	}
-
-	// Clear tmpPath so defer doesn't try to remove the final file
-	tmpPath = ""
-	return nil
-}
@@ -0,0 +1,200 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// Level controls how much status output is printed.
+type Level int
+
+const (
+	// LevelQuiet prints only warnings and errors.
+	LevelQuiet Level = iota
+	// LevelNormal prints status, success, warning and error messages.
+	LevelNormal
+	// LevelVerbose adds detail messages.
+	LevelVerbose
+	// LevelDebug adds debug messages.
+	LevelDebug
+)
+
+// String returns the level name used in flags and logs.
+func (l Level) String() string {
+	switch l {
+	case LevelQuiet:
+		return "quiet"
+	case LevelNormal:
+		return "normal"
+	case LevelVerbose:
+		return "verbose"
+	case LevelDebug:
+		return "debug"
+	default:
+		return "unknown"
+	}
+}
+
+// Symbols is the set of status markers used by UI.
+type Symbols struct {
+	Success string
+	Failure string
+	Warning string
+	Arrow   string
+}
+
+// UnicodeSymbols are used by default.
+var UnicodeSymbols = Symbols{Success: "✓", Failure: "✗", Warning: "⚠", Arrow: "→"}
+
+// ASCIISymbols are used when AUTOSPEC_ASCII=1.
+var ASCIISymbols = Symbols{Success: "[OK]", Failure: "[FAIL]", Warning: "[WARN]", Arrow: "->"}
+
+// UI prints user-facing status messages at a verbosity level, with consistent
+// symbols and optional color. Agent output does not go through UI.
+type UI struct {
+	out     io.Writer // nil writes to the current os.Stdout
+	level   Level
+	color   bool
+	symbols Symbols
+}
+
+// NewUI creates a UI writing to out. A nil out writes to os.Stdout as it is at
+// the time of each call.
+func NewUI(out io.Writer, level Level, colorEnabled bool, symbols Symbols) *UI {
+	return &UI{out: out, level: level, color: colorEnabled, symbols: symbols}
+}
+
+var (
+	defaultMu sync.RWMutex
+	defaultUI = NewUI(nil, LevelNormal, !color.NoColor, defaultSymbols())
+)
+
+// Default returns the process-wide UI configured by Configure.
+func Default() *UI {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultUI
+}
+
+// Configure sets the level and color of the process-wide UI. Color is off
+// when noColor is set or the NO_COLOR environment variable is present; turning
+// it off also disables color in fatih/color based output.
+func Configure(level Level, noColor bool) {
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		color.NoColor = true
+	}
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultUI = NewUI(nil, level, !color.NoColor, defaultSymbols())
+}
+
+// LevelFromFlags maps the global --quiet, --verbose and --debug flags to a
+// level. Debug wins over verbose, which wins over quiet.
+func LevelFromFlags(quiet, verbose, debug bool) Level {
+	switch {
+	case debug:
+		return LevelDebug
+	case verbose:
+		return LevelVerbose
+	case quiet:
+		return LevelQuiet
+	default:
+		return LevelNormal
+	}
+}
+
+func defaultSymbols() Symbols {
+	if os.Getenv("AUTOSPEC_ASCII") == "1" {
+		return ASCIISymbols
+	}
+	return UnicodeSymbols
+}
+
+// Level returns the UI's verbosity level.
+func (u *UI) Level() Level {
+	return u.level
+}
+
+// Symbols returns the UI's status markers.
+func (u *UI) Symbols() Symbols {
+	return u.symbols
+}
+
+// ColorEnabled reports whether the UI prints color.
+func (u *UI) ColorEnabled() bool {
+	return u.color
+}
+
+// Enabled reports whether messages at level are printed.
+func (u *UI) Enabled(level Level) bool {
+	return u.level >= level
+}
+
+// Info prints a plain status line.
+func (u *UI) Info(format string, args ...any) {
+	u.print(LevelNormal, "", nil, format, args...)
+}
+
+// Success prints a status line with the success marker.
+func (u *UI) Success(format string, args ...any) {
+	u.print(LevelNormal, u.symbols.Success, color.New(color.FgGreen), format, args...)
+}
+
+// Warn prints a status line with the warning marker. Shown at every level.
+func (u *UI) Warn(format string, args ...any) {
+	u.print(LevelQuiet, u.symbols.Warning, color.New(color.FgYellow), format, args...)
+}
+
+// Error prints a status line with the failure marker. Shown at every level.
+func (u *UI) Error(format string, args ...any) {
+	u.print(LevelQuiet, u.symbols.Failure, color.New(color.FgRed), format, args...)
+}
+
+// Verbose prints a detail line shown with --verbose or --debug.
+func (u *UI) Verbose(format string, args ...any) {
+	u.print(LevelVerbose, "", nil, format, args...)
+}
+
+// Debug prints a debug line shown with --debug.
+func (u *UI) Debug(format string, args ...any) {
+	u.print(LevelDebug, "", nil, "[DEBUG] "+format, args...)
+}
+
+// Blank prints an empty line at normal level.
+func (u *UI) Blank() {
+	u.print(LevelNormal, "", nil, "")
+}
+
+// Block prints preformatted multi-line text at normal level as-is.
+func (u *UI) Block(text string) {
+	if !u.Enabled(LevelNormal) {
+		return
+	}
+	fmt.Fprint(u.writer(), text)
+}
+
+func (u *UI) print(level Level, symbol string, c *color.Color, format string, args ...any) {
+	if !u.Enabled(level) {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	if symbol != "" {
+		if u.color && c != nil {
+			c.EnableColor()
+			symbol = c.Sprint(symbol)
+		}
+		msg = symbol + " " + msg
+	}
+	fmt.Fprintln(u.writer(), msg)
+}
+
+func (u *UI) writer() io.Writer {
+	if u.out == nil {
+		return os.Stdout
+	}
+	return u.out
+}
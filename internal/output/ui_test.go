@@ -0,0 +1,80 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUI_Levels(t *testing.T) {
+	t.Parallel()
+
+	emit := func(u *UI) {
+		u.Info("info")
+		u.Success("done")
+		u.Warn("careful")
+		u.Error("broken")
+		u.Verbose("detail")
+		u.Debug("internals")
+	}
+
+	tests := map[string]struct {
+		level Level
+		want  string
+	}{
+		"quiet": {
+			level: LevelQuiet,
+			want:  "⚠ careful\n✗ broken\n",
+		},
+		"normal": {
+			level: LevelNormal,
+			want:  "info\n✓ done\n⚠ careful\n✗ broken\n",
+		},
+		"verbose": {
+			level: LevelVerbose,
+			want:  "info\n✓ done\n⚠ careful\n✗ broken\ndetail\n",
+		},
+		"debug": {
+			level: LevelDebug,
+			want:  "info\n✓ done\n⚠ careful\n✗ broken\ndetail\n[DEBUG] internals\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			emit(NewUI(&buf, tt.level, false, UnicodeSymbols))
+			assert.Equal(t, tt.want, buf.String())
+		})
+	}
+}
+
+func TestUI_SymbolsAndColor(t *testing.T) {
+	t.Parallel()
+
+	var plain bytes.Buffer
+	NewUI(&plain, LevelNormal, false, ASCIISymbols).Success("Task %s complete", "T001")
+	assert.Equal(t, "[OK] Task T001 complete\n", plain.String())
+
+	var colored bytes.Buffer
+	NewUI(&colored, LevelNormal, true, UnicodeSymbols).Success("ok")
+	assert.Contains(t, colored.String(), "\x1b[32m✓\x1b[0m ok")
+
+	var block bytes.Buffer
+	u := NewUI(&block, LevelQuiet, false, UnicodeSymbols)
+	u.Block("  1/2 tasks completed\n")
+	u.Blank()
+	assert.Empty(t, block.String())
+}
+
+func TestLevelFromFlags(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, LevelNormal, LevelFromFlags(false, false, false))
+	assert.Equal(t, LevelQuiet, LevelFromFlags(true, false, false))
+	assert.Equal(t, LevelVerbose, LevelFromFlags(true, true, false))
+	assert.Equal(t, LevelDebug, LevelFromFlags(false, true, true))
+	assert.Equal(t, "verbose", LevelVerbose.String())
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package pause
+
+// ListenKeys is unavailable on this platform; use the pause file instead.
+func ListenKeys(path, projectRoot string, onToggle func(paused bool)) (stop func()) {
+	return func() {}
+}
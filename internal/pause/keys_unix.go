@@ -0,0 +1,97 @@
+//go:build linux || darwin
+
+package pause
+
+import (
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+// keyPollMillis bounds how long the key reader blocks, so stop returns promptly.
+const keyPollMillis = 200
+
+// ListenKeys toggles the pause file at path each time 'p' is pressed on
+// an interactive terminal and calls onToggle with the new state. The terminal
+// is switched to cbreak mode: keys are read without Enter and not echoed,
+// while output processing and Ctrl+C keep working. The returned function
+// stops listening and restores the terminal. Without a terminal on stdin it
+// does nothing.
+func ListenKeys(path, projectRoot string, onToggle func(paused bool)) (stop func()) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return func() {}
+	}
+	saved, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return func() {}
+	}
+	cbreak := *saved
+	cbreak.Lflag &^= unix.ICANON | unix.ECHO
+	cbreak.Cc[unix.VMIN] = 1
+	cbreak.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &cbreak); err != nil {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		readKeys(fd, done, func() {
+			paused := !Requested(path)
+			var err error
+			if paused {
+				err = Request(path, projectRoot)
+			} else {
+				_, err = Clear(path)
+			}
+			if err == nil && onToggle != nil {
+				onToggle(paused)
+			}
+		})
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			wg.Wait()
+			_ = unix.IoctlSetTermios(fd, ioctlSetTermios, saved)
+		})
+	}
+}
+
+// readKeys calls toggle for every 'p' read from fd until done is closed.
+// It polls rather than blocking in read, so no keypress meant for a later
+// prompt is consumed after stop.
+func readKeys(fd int, done <-chan struct{}, toggle func()) {
+	buf := make([]byte, 1)
+	fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		n, err := unix.Poll(fds, keyPollMillis)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil {
+			return
+		}
+		if n == 0 || fds[0].Revents&unix.POLLIN == 0 {
+			continue
+		}
+		if _, err := unix.Read(fd, buf); err != nil {
+			return
+		}
+		if buf[0] == 'p' || buf[0] == 'P' {
+			toggle()
+		}
+	}
+}
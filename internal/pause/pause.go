@@ -0,0 +1,136 @@
+// Package pause lets users pause batch execution between tasks and phases.
+//
+// A run is paused while its project's pause file exists in the state
+// directory. Pause files are keyed by project root, so pausing one project
+// leaves runs in other projects sharing the state directory alone. The task
+// and phase loops check for the file before starting their next item, so the
+// current task finishes and its agent session ends before the loop waits. The
+// run continues once the file is removed by `autospec resume --continue`, by
+// pressing 'p' again, or by hand.
+package pause
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/git"
+)
+
+// FilePrefix starts the name of every pause file in the state directory.
+const FilePrefix = "pause-"
+
+// PollInterval is how often Wait checks whether the pause file is gone.
+const PollInterval = time.Second
+
+// unsafeChars are replaced in the project name part of a pause file name.
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// ProjectRoot returns the project a command run from the working directory
+// belongs to: the git repository root, or the working directory outside a
+// repository.
+func ProjectRoot() string {
+	if root, err := git.GetRepositoryRoot(); err == nil && root != "" {
+		return root
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return wd
+}
+
+// Path returns the pause file of the project at projectRoot:
+// <state_dir>/pause-<name>-<hash>, where name is the root's base name and
+// hash identifies its absolute path. An empty stateDir has no pause file.
+func Path(stateDir, projectRoot string) string {
+	if stateDir == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(projectRoot); err == nil {
+		projectRoot = abs
+	}
+	sum := sha256.Sum256([]byte(filepath.Clean(projectRoot)))
+	name := unsafeChars.ReplaceAllString(filepath.Base(projectRoot), "_")
+	return filepath.Join(stateDir, FilePrefix+name+"-"+hex.EncodeToString(sum[:4]))
+}
+
+// Requested reports whether the pause file at path exists. An empty path is
+// never paused.
+func Requested(path string) bool {
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// Request creates the pause file at path, recording when, by whom and for
+// which project it was created.
+func Request(path, projectRoot string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	content := fmt.Sprintf("paused at %s by pid %d in %s\n", time.Now().Format(time.RFC3339), os.Getpid(), projectRoot)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("writing pause file: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the pause file at path and reports whether a pause was pending.
+func Clear(path string) (bool, error) {
+	err := os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("removing pause file: %w", err)
+	}
+	return true, nil
+}
+
+// List returns the pause files of all projects in stateDir, sorted by name.
+func List(stateDir string) ([]string, error) {
+	entries, err := os.ReadDir(stateDir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading state directory: %w", err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), FilePrefix) {
+			paths = append(paths, filepath.Join(stateDir, entry.Name()))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// Wait blocks while the pause file at path exists, checking every poll
+// interval. It returns the cancellation cause if ctx is done first.
+func Wait(ctx context.Context, path string, poll time.Duration) error {
+	if poll <= 0 {
+		poll = PollInterval
+	}
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+	for Requested(path) {
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package pause
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestAndClear(t *testing.T) {
+	t.Parallel()
+
+	path := Path(t.TempDir(), "/home/me/app")
+	assert.False(t, Requested(path))
+	assert.False(t, Requested(""), "an empty path is never paused")
+
+	require.NoError(t, Request(path, "/home/me/app"))
+	assert.True(t, Requested(path))
+
+	cleared, err := Clear(path)
+	require.NoError(t, err)
+	assert.True(t, cleared)
+	assert.False(t, Requested(path))
+
+	cleared, err = Clear(path)
+	require.NoError(t, err)
+	assert.False(t, cleared)
+}
+
+func TestPath_PerProject(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	app := Path(stateDir, "/home/me/app")
+	other := Path(stateDir, "/home/me/work/app")
+	assert.Equal(t, stateDir, filepath.Dir(app))
+	assert.Regexp(t, `^pause-app-[0-9a-f]{8}$`, filepath.Base(app))
+	assert.NotEqual(t, app, other, "projects with the same name get different files")
+	assert.Equal(t, app, Path(stateDir, "/home/me/app/"), "the root is cleaned")
+	assert.Empty(t, Path("", "/home/me/app"))
+
+	require.NoError(t, Request(app, "/home/me/app"))
+	assert.False(t, Requested(other), "pausing one project leaves the others running")
+
+	require.NoError(t, Request(other, "/home/me/work/app"))
+	paths, err := List(stateDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{app, other}, paths)
+
+	paths, err = List(filepath.Join(stateDir, "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, paths)
+}
+
+func TestWait(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns once the pause file is removed", func(t *testing.T) {
+		t.Parallel()
+		path := Path(t.TempDir(), "app")
+		require.NoError(t, Request(path, "app"))
+		time.AfterFunc(30*time.Millisecond, func() { _, _ = Clear(path) })
+
+		require.NoError(t, Wait(context.Background(), path, 5*time.Millisecond))
+		assert.False(t, Requested(path))
+	})
+
+	t.Run("returns the cancellation cause", func(t *testing.T) {
+		t.Parallel()
+		path := Path(t.TempDir(), "app")
+		require.NoError(t, Request(path, "app"))
+		stopped := errors.New("stopped")
+		ctx, cancel := context.WithCancelCause(context.Background())
+		time.AfterFunc(30*time.Millisecond, func() { cancel(stopped) })
+
+		assert.ErrorIs(t, Wait(ctx, path, 5*time.Millisecond), stopped)
+		assert.True(t, Requested(path), "a cancelled wait leaves the pause in place")
+	})
+
+	t.Run("no pause returns at once", func(t *testing.T) {
+		t.Parallel()
+		require.NoError(t, Wait(context.Background(), Path(t.TempDir(), "app"), time.Hour))
+	})
+}
@@ -0,0 +1,231 @@
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/shell"
+	"github.com/ariel-frischer/autospec/internal/storage"
+)
+
+// minFreeBytes is the free disk space below which disk-space warns.
+const minFreeBytes = 1 << 30
+
+// dialTimeout bounds each connection attempt of the network check.
+const dialTimeout = 3 * time.Second
+
+// projectDirs are the directories autospec init creates.
+var projectDirs = []string{".claude/commands/", ".autospec/"}
+
+func init() {
+	Register(Check{Name: "agent", Description: "Agent CLI installed", Level: LevelError, Run: checkAgent})
+	Register(Check{Name: "project", Description: "Project initialized (.claude/commands/, .autospec/)", Level: LevelConfirm, Run: checkProject})
+	Register(Check{Name: "permissions", Description: "State and specs directories writable", Level: LevelError, Run: checkPermissions})
+	Register(Check{Name: "git-clean", Description: "No uncommitted changes", Level: LevelWarn, Run: checkGitClean})
+	Register(Check{Name: "disk-space", Description: "At least 1 GiB of free disk space", Level: LevelWarn, Run: checkDiskSpace})
+	Register(Check{Name: "state-size", Description: "State directory and logs within storage limits", Level: LevelError, Run: checkStateSize})
+	Register(Check{Name: "network", Description: "Notification and update endpoints reachable", Level: LevelWarn, Run: checkNetwork})
+}
+
+// MissingDirsError is the failure of the project check.
+type MissingDirsError struct {
+	Dirs []string
+}
+
+func (e *MissingDirsError) Error() string {
+	return fmt.Sprintf("missing %s (run 'autospec init')", strings.Join(e.Dirs, ", "))
+}
+
+// checkAgent fails when the agent's CLI is not installed.
+func checkAgent(env Env) error {
+	if env.Agent == nil {
+		return nil
+	}
+	return env.Agent.Validate()
+}
+
+// checkProject fails with a *MissingDirsError when autospec init has not run.
+func checkProject(env Env) error {
+	var missing []string
+	for _, dir := range projectDirs {
+		if _, err := os.Stat(inDir(env.Dir, dir)); errors.Is(err, os.ErrNotExist) {
+			missing = append(missing, dir)
+		}
+	}
+	if len(missing) > 0 {
+		return &MissingDirsError{Dirs: missing}
+	}
+	return nil
+}
+
+// checkPermissions fails when a directory the run writes to, or the nearest
+// existing parent of one not created yet, is not writable.
+func checkPermissions(env Env) error {
+	var denied []string
+	for _, dir := range env.WriteDirs {
+		if !canWrite(existingAncestor(inDir(env.Dir, dir))) {
+			denied = append(denied, dir)
+		}
+	}
+	if len(denied) > 0 {
+		return fmt.Errorf("cannot write to %s", strings.Join(denied, ", "))
+	}
+	return nil
+}
+
+// checkGitClean warns about uncommitted changes, which agent edits would mix
+// with. Outside a git repository it passes.
+func checkGitClean(env Env) error {
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = env.Dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	if changes := strings.Count(string(out), "\n"); changes > 0 {
+		return fmt.Errorf("%d uncommitted change(s); commit or stash them to keep agent edits separate", changes)
+	}
+	return nil
+}
+
+// checkDiskSpace warns when the project's file system is nearly full.
+func checkDiskSpace(env Env) error {
+	dir := env.Dir
+	if dir == "" {
+		dir = "."
+	}
+	free, err := freeBytes(dir)
+	if err != nil {
+		return nil
+	}
+	if free < minFreeBytes {
+		return fmt.Errorf("only %d MiB free", free>>20)
+	}
+	return nil
+}
+
+// checkStateSize fails when the state directory or its logs exceed a storage
+// max limit, and warns past a warn limit.
+func checkStateSize(env Env) error {
+	if env.StateDir == "" {
+		return nil
+	}
+	usage, err := storage.Measure(env.StateDir)
+	if err != nil {
+		return nil
+	}
+	warnings, err := env.Storage.Check(usage)
+	if err != nil {
+		return err
+	}
+	if len(warnings) > 0 {
+		return Warn(errors.New(strings.Join(warnings, "; ")))
+	}
+	return nil
+}
+
+// checkNetwork warns about endpoints that do not accept a TCP connection.
+func checkNetwork(env Env) error {
+	var unreachable []string
+	for _, endpoint := range env.Endpoints {
+		conn, err := net.DialTimeout("tcp", endpoint, dialTimeout)
+		if err != nil {
+			unreachable = append(unreachable, endpoint)
+			continue
+		}
+		conn.Close()
+	}
+	if len(unreachable) > 0 {
+		return fmt.Errorf("cannot reach %s", strings.Join(unreachable, ", "))
+	}
+	return nil
+}
+
+// CommandCheck returns a check that runs command through the configured
+// shell in the project directory and fails on a non-zero exit.
+func CommandCheck(name, command string) Check {
+	return Check{
+		Name:        name,
+		Description: command,
+		Level:       LevelError,
+		Run: func(env Env) error {
+			cmd := shell.Command(command)
+			cmd.Dir = env.Dir
+			out, err := cmd.CombinedOutput()
+			if err == nil {
+				return nil
+			}
+			if last := lastLine(string(out)); last != "" {
+				return fmt.Errorf("%v: %s", err, last)
+			}
+			return err
+		},
+	}
+}
+
+// Endpoint returns the host:port of rawURL, with the scheme's default port
+// when it has none, or "" when rawURL has no host.
+func Endpoint(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return ""
+	}
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "http":
+			port = "80"
+		case "smtp":
+			port = "587"
+		default:
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// inDir resolves path against dir unless it is absolute.
+func inDir(dir, path string) string {
+	if dir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// existingAncestor returns path, or its nearest parent that exists.
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// canWrite reports whether a file can be created and removed in dir.
+func canWrite(dir string) bool {
+	f, err := os.CreateTemp(dir, ".autospec-preflight-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name) == nil
+}
+
+// lastLine returns the last non-empty line of s.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,209 @@
+package preflight
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubAgent is a cliagent.Agent whose Validate returns err.
+type stubAgent struct {
+	cliagent.Agent
+	err error
+}
+
+func (s stubAgent) Validate() error { return s.err }
+
+func TestCheckAgent(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, checkAgent(Env{}), "no agent skips the check")
+	assert.NoError(t, checkAgent(Env{Agent: stubAgent{}}))
+	assert.EqualError(t, checkAgent(Env{Agent: stubAgent{err: assert.AnError}}), assert.AnError.Error())
+}
+
+func TestCheckProject(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		dirs        []string
+		wantMissing []string
+	}{
+		"initialized":          {dirs: []string{".claude/commands", ".autospec"}},
+		"missing .autospec":    {dirs: []string{".claude/commands"}, wantMissing: []string{".autospec/"}},
+		"missing both":         {wantMissing: []string{".claude/commands/", ".autospec/"}},
+		"missing .claude only": {dirs: []string{".autospec"}, wantMissing: []string{".claude/commands/"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			for _, d := range tt.dirs {
+				require.NoError(t, os.MkdirAll(filepath.Join(dir, d), 0o755))
+			}
+
+			err := checkProject(Env{Dir: dir})
+			if tt.wantMissing == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var missing *MissingDirsError
+			require.ErrorAs(t, err, &missing)
+			assert.Equal(t, tt.wantMissing, missing.Dirs)
+		})
+	}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	assert.NoError(t, checkPermissions(Env{Dir: dir, WriteDirs: []string{"specs", filepath.Join(dir, "state", "nested")}}),
+		"directories not created yet are checked through their parent")
+
+	if runtime.GOOS == "windows" || os.Geteuid() == 0 {
+		t.Skip("permission bits are not enforced")
+	}
+	readOnly := filepath.Join(dir, "readonly")
+	require.NoError(t, os.Mkdir(readOnly, 0o555))
+	err := checkPermissions(Env{WriteDirs: []string{readOnly}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot write to")
+}
+
+func TestCheckGitClean(t *testing.T) {
+	t.Parallel()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, checkGitClean(Env{Dir: dir}), "outside a repository the check passes")
+
+	require.NoError(t, exec.Command("git", "-C", dir, "init", "-q").Run())
+	assert.NoError(t, checkGitClean(Env{Dir: dir}))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "new.txt"), []byte("x"), 0o644))
+	err := checkGitClean(Env{Dir: dir})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 uncommitted change(s)")
+}
+
+func TestCheckDiskSpace(t *testing.T) {
+	t.Parallel()
+
+	free, err := freeBytes(t.TempDir())
+	require.NoError(t, err)
+	assert.Positive(t, free)
+}
+
+func TestCheckStateSize(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "big"), make([]byte, 2<<20), 0o644))
+
+	assert.NoError(t, checkStateSize(Env{}), "no state directory skips the check")
+	assert.NoError(t, checkStateSize(Env{StateDir: stateDir, Storage: storage.Config{WarnMB: 5}}))
+
+	err := checkStateSize(Env{StateDir: stateDir, Storage: storage.Config{WarnMB: 1}})
+	require.ErrorAs(t, err, new(warning))
+	assert.Contains(t, err.Error(), "storage.warn_mb")
+
+	err = checkStateSize(Env{StateDir: stateDir, Storage: storage.Config{WarnMB: 1, MaxMB: 1}})
+	require.ErrorIs(t, err, apperrors.ErrStorageLimit)
+	assert.NotErrorAs(t, err, new(warning))
+}
+
+func TestCheckNetwork(t *testing.T) {
+	t.Parallel()
+
+	// Nothing listens on a port once its listener is closed
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closed := ln.Addr().String()
+	ln.Close()
+
+	ln, err = net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	assert.NoError(t, checkNetwork(Env{}))
+	assert.NoError(t, checkNetwork(Env{Endpoints: []string{ln.Addr().String()}}))
+	err = checkNetwork(Env{Endpoints: []string{ln.Addr().String(), closed}})
+	require.Error(t, err)
+	assert.Equal(t, "cannot reach "+closed, err.Error())
+}
+
+func TestCommandCheck(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		command     string
+		wantErr     bool
+		errContains string
+	}{
+		"success":             {command: "exit 0"},
+		"failure":             {command: "exit 3", wantErr: true},
+		"failure with output": {command: "echo first && echo last line && exit 1", wantErr: true, errContains: "last line"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			check := CommandCheck("custom", tt.command)
+			assert.Equal(t, "custom", check.Name)
+			assert.Equal(t, LevelError, check.Level)
+
+			err := check.Run(Env{Dir: t.TempDir()})
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.errContains)
+		})
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		url  string
+		want string
+	}{
+		"https default port": {url: "https://ntfy.sh", want: "ntfy.sh:443"},
+		"http default port":  {url: "http://example.com/path", want: "example.com:80"},
+		"smtp default port":  {url: "smtp://mail.example.com", want: "mail.example.com:587"},
+		"explicit port":      {url: "https://example.com:8443/x", want: "example.com:8443"},
+		"ipv6":               {url: "http://[::1]:8080", want: "[::1]:8080"},
+		"no host":            {url: "not a url", want: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Endpoint(tt.url))
+		})
+	}
+}
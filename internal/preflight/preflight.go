@@ -0,0 +1,233 @@
+// Package preflight runs named checks before a workflow starts: agent CLI
+// installed, project initialized, directories writable, git tree clean, disk
+// space, state directory size and network reachability. Checks live in a registry, so other packages
+// can add their own with Register; config can disable checks by name and add
+// shell command checks.
+// Related: internal/workflow/preflight.go, internal/cli/config/preflight.go
+// Tags: preflight, checks, registry
+package preflight
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/storage"
+)
+
+// Level is what a failing check does to the run.
+type Level string
+
+const (
+	// LevelError stops the run.
+	LevelError Level = "error"
+	// LevelConfirm asks whether to continue; without a terminal the run stops.
+	LevelConfirm Level = "confirm"
+	// LevelWarn prints a warning and the run continues.
+	LevelWarn Level = "warn"
+)
+
+// Env is what checks know about the run.
+type Env struct {
+	Agent     cliagent.Agent // Agent whose CLI must be installed (nil skips the check)
+	Dir       string         // Project directory ("" = current directory)
+	WriteDirs []string       // Directories the run writes to (state, specs)
+	Endpoints []string       // host:port the run reaches (notification channels, update check)
+	StateDir  string         // State directory measured against Storage ("" skips the check)
+	Storage   storage.Config // State directory size limits (config: storage)
+}
+
+// Check is a named preflight check. Run returns nil when the check passes.
+type Check struct {
+	Name        string
+	Description string
+	Level       Level
+	Run         func(env Env) error
+}
+
+// warning marks a check failure as a warning.
+type warning struct{ error }
+
+func (w warning) Unwrap() error { return w.error }
+
+// Warn makes err fail its check as a warning whatever the check's level, for
+// checks that warn past one threshold and stop the run past another.
+func Warn(err error) error {
+	if err == nil {
+		return nil
+	}
+	return warning{err}
+}
+
+// Config selects checks (config: preflight).
+type Config struct {
+	// Disable lists checks to skip by name, built-in or custom.
+	Disable []string `koanf:"disable" yaml:"disable" json:"disable"`
+
+	// Commands adds checks that run a shell command, keyed by check name.
+	// A non-zero exit fails the check and stops the run.
+	Commands map[string]string `koanf:"commands" yaml:"commands" json:"commands"`
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Level       Level  `json:"level"`
+	Passed      bool   `json:"passed"`
+	Disabled    bool   `json:"disabled,omitempty"`
+	Message     string `json:"message,omitempty"`
+	Err         error  `json:"-"`
+}
+
+// Report holds the results of a preflight run, in check order.
+type Report struct {
+	Results []Result `json:"results"`
+}
+
+// Failed returns the failed results at level.
+func (r *Report) Failed(level Level) []Result {
+	var failed []Result
+	for _, res := range r.Results {
+		if !res.Passed && !res.Disabled && res.Level == level {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// Passed reports whether no error or confirm check failed. Failed warn
+// checks do not count.
+func (r *Report) Passed() bool {
+	return len(r.Failed(LevelError)) == 0 && len(r.Failed(LevelConfirm)) == 0
+}
+
+// Print writes one line per check: ✓ passed, ⚠ failed warn check, ✗ failed
+// error or confirm check, - disabled.
+func (r *Report) Print(w io.Writer) {
+	for _, res := range r.Results {
+		switch {
+		case res.Disabled:
+			fmt.Fprintf(w, "- %s (disabled)\n", res.Description)
+		case res.Passed:
+			fmt.Fprintf(w, "✓ %s\n", res.Description)
+		case res.Level == LevelWarn:
+			fmt.Fprintf(w, "⚠ %s: %s\n", res.Name, res.Message)
+		default:
+			fmt.Fprintf(w, "✗ %s: %s\n", res.Name, res.Message)
+		}
+	}
+}
+
+// Registry is an ordered, thread-safe set of checks. Checks run in the order
+// they were first registered.
+type Registry struct {
+	mu     sync.RWMutex
+	checks []Check
+}
+
+// NewRegistry creates an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds check, replacing a check of the same name in place.
+func (r *Registry) Register(check Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, c := range r.checks {
+		if c.Name == check.Name {
+			r.checks[i] = check
+			return
+		}
+	}
+	r.checks = append(r.checks, check)
+}
+
+// Checks returns the registered checks in order.
+func (r *Registry) Checks() []Check {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.checks)
+}
+
+// Names returns the names of the registered checks in order.
+func (r *Registry) Names() []string {
+	checks := r.Checks()
+	names := make([]string, len(checks))
+	for i, c := range checks {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Run runs the registered checks followed by the command checks of cfg,
+// skipping the disabled ones.
+func (r *Registry) Run(env Env, cfg Config) *Report {
+	checks := append(r.Checks(), CommandChecks(cfg.Commands)...)
+	report := &Report{Results: make([]Result, 0, len(checks))}
+	for _, check := range checks {
+		res := Result{Name: check.Name, Description: check.Description, Level: check.Level}
+		if slices.Contains(cfg.Disable, check.Name) {
+			res.Disabled = true
+		} else if res.Err = check.Run(env); res.Err != nil {
+			res.Message = res.Err.Error()
+			if errors.As(res.Err, new(warning)) {
+				res.Level = LevelWarn
+			}
+		} else {
+			res.Passed = true
+		}
+		report.Results = append(report.Results, res)
+	}
+	return report
+}
+
+// CommandChecks returns the checks for commands (config: preflight.commands),
+// sorted by name.
+func CommandChecks(commands map[string]string) []Check {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	checks := make([]Check, 0, len(names))
+	for _, name := range names {
+		checks = append(checks, CommandCheck(name, commands[name]))
+	}
+	return checks
+}
+
+// Validate reports disabled names that match no check in r or cfg.Commands.
+func (r *Registry) Validate(cfg Config) error {
+	names := r.Names()
+	for _, name := range cfg.Disable {
+		if slices.Contains(names, name) {
+			continue
+		}
+		if _, ok := cfg.Commands[name]; ok {
+			continue
+		}
+		return fmt.Errorf("unknown preflight check %q (valid: %v)", name, names)
+	}
+	return nil
+}
+
+// Default is the registry of built-in checks. Other packages add theirs with
+// Register.
+var Default = NewRegistry()
+
+// Register adds check to the default registry.
+func Register(check Check) {
+	Default.Register(check)
+}
+
+// Run runs the checks of the default registry.
+func Run(env Env, cfg Config) *Report {
+	return Default.Run(env, cfg)
+}
@@ -0,0 +1,162 @@
+package preflight
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func passing(name string, level Level) Check {
+	return Check{Name: name, Description: name + " ok", Level: level, Run: func(Env) error { return nil }}
+}
+
+func failing(name string, level Level) Check {
+	return Check{Name: name, Description: name + " ok", Level: level, Run: func(Env) error { return errors.New(name + " failed") }}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.Register(passing("a", LevelError))
+	r.Register(passing("b", LevelWarn))
+	r.Register(failing("a", LevelWarn))
+
+	assert.Equal(t, []string{"a", "b"}, r.Names(), "replacing a check keeps its position")
+	assert.Equal(t, LevelWarn, r.Checks()[0].Level)
+}
+
+func TestRegistry_Run(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		checks      []Check
+		cfg         Config
+		wantPassed  bool
+		wantResults []Result
+	}{
+		"all pass": {
+			checks:     []Check{passing("a", LevelError), passing("b", LevelWarn)},
+			wantPassed: true,
+			wantResults: []Result{
+				{Name: "a", Description: "a ok", Level: LevelError, Passed: true},
+				{Name: "b", Description: "b ok", Level: LevelWarn, Passed: true},
+			},
+		},
+		"failed warn check still passes": {
+			checks:     []Check{failing("a", LevelWarn)},
+			wantPassed: true,
+			wantResults: []Result{
+				{Name: "a", Description: "a ok", Level: LevelWarn, Message: "a failed"},
+			},
+		},
+		"error check failing with Warn is a warning": {
+			checks: []Check{{Name: "a", Description: "a ok", Level: LevelError, Run: func(Env) error {
+				return Warn(errors.New("a is large"))
+			}}},
+			wantPassed: true,
+			wantResults: []Result{
+				{Name: "a", Description: "a ok", Level: LevelWarn, Message: "a is large"},
+			},
+		},
+		"failed confirm check fails": {
+			checks: []Check{failing("a", LevelConfirm)},
+			wantResults: []Result{
+				{Name: "a", Description: "a ok", Level: LevelConfirm, Message: "a failed"},
+			},
+		},
+		"disabled check is skipped": {
+			checks:     []Check{failing("a", LevelError)},
+			cfg:        Config{Disable: []string{"a"}},
+			wantPassed: true,
+			wantResults: []Result{
+				{Name: "a", Description: "a ok", Level: LevelError, Disabled: true},
+			},
+		},
+		"command checks run after registered checks": {
+			checks:     []Check{passing("a", LevelError)},
+			cfg:        Config{Commands: map[string]string{"z": "exit 0", "y": "exit 0"}},
+			wantPassed: true,
+			wantResults: []Result{
+				{Name: "a", Description: "a ok", Level: LevelError, Passed: true},
+				{Name: "y", Description: "exit 0", Level: LevelError, Passed: true},
+				{Name: "z", Description: "exit 0", Level: LevelError, Passed: true},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			r := NewRegistry()
+			for _, c := range tt.checks {
+				r.Register(c)
+			}
+
+			report := r.Run(Env{Dir: t.TempDir()}, tt.cfg)
+			for i := range report.Results {
+				report.Results[i].Err = nil
+			}
+			assert.Equal(t, tt.wantPassed, report.Passed())
+			assert.Equal(t, tt.wantResults, report.Results)
+		})
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	t.Parallel()
+
+	r := NewRegistry()
+	r.Register(passing("a", LevelError))
+
+	tests := map[string]struct {
+		cfg     Config
+		wantErr bool
+	}{
+		"empty":               {},
+		"registered check":    {cfg: Config{Disable: []string{"a"}}},
+		"command check":       {cfg: Config{Disable: []string{"lint"}, Commands: map[string]string{"lint": "make lint"}}},
+		"unknown check":       {cfg: Config{Disable: []string{"b"}}, wantErr: true},
+		"unknown after known": {cfg: Config{Disable: []string{"a", "b"}}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := r.Validate(tt.cfg)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "unknown preflight check")
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReport_Print(t *testing.T) {
+	t.Parallel()
+
+	report := &Report{Results: []Result{
+		{Name: "a", Description: "A works", Level: LevelError, Passed: true},
+		{Name: "b", Description: "B works", Level: LevelWarn, Message: "b is slow"},
+		{Name: "c", Description: "C works", Level: LevelError, Message: "c is broken"},
+		{Name: "d", Description: "D works", Level: LevelWarn, Disabled: true},
+	}}
+
+	var buf bytes.Buffer
+	report.Print(&buf)
+	assert.Equal(t, "✓ A works\n⚠ b: b is slow\n✗ c: c is broken\n- D works (disabled)\n", buf.String())
+}
+
+func TestDefault_BuiltinChecks(t *testing.T) {
+	t.Parallel()
+
+	names := Default.Names()
+	for _, name := range []string{"agent", "project", "permissions", "git-clean", "disk-space", "state-size", "network"} {
+		assert.Contains(t, names, name)
+	}
+}
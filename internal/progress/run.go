@@ -0,0 +1,160 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// averageWindow is the number of recent item durations averaged for the ETA.
+const averageWindow = 5
+
+// RunProgress renders progress across the items (tasks or phases) of a
+// multi-session implement run: a bar, items done/total, the current item,
+// the moving-average duration per item and an ETA.
+//
+// A line is printed when an item starts and when it completes rather than
+// redrawn in place, because agent output streams to the same terminal between
+// them. On a TTY the line has a drawn bar; otherwise it degrades to a plain
+// log line without escape codes.
+type RunProgress struct {
+	w     io.Writer
+	caps  TerminalCapabilities
+	unit  string // "task" or "phase"
+	total int
+	done  int
+
+	current   string
+	started   time.Time
+	durations []time.Duration // Most recent item durations, oldest first
+	now       func() time.Time
+}
+
+// NewRunProgress creates a renderer for total items of the given unit, of
+// which done are already complete.
+func NewRunProgress(w io.Writer, caps TerminalCapabilities, unit string, total, done int) *RunProgress {
+	return &RunProgress{
+		w:     w,
+		caps:  caps,
+		unit:  unit,
+		total: total,
+		done:  done,
+		now:   time.Now,
+	}
+}
+
+// Start marks the item titled title as running and prints the progress line.
+func (r *RunProgress) Start(title string) {
+	if r == nil {
+		return
+	}
+	r.current = title
+	r.started = r.now()
+	fmt.Fprintln(r.w, r.line())
+}
+
+// Complete marks the current item as done, records its duration for the
+// moving average and prints the progress line.
+func (r *RunProgress) Complete() {
+	if r == nil || r.started.IsZero() {
+		return
+	}
+	r.durations = append(r.durations, r.now().Sub(r.started))
+	if len(r.durations) > averageWindow {
+		r.durations = r.durations[1:]
+	}
+	r.done = min(r.done+1, r.total)
+	r.current = ""
+	r.started = time.Time{}
+	fmt.Fprintln(r.w, r.line())
+}
+
+// Average returns the moving-average duration of the last completed items,
+// or 0 before any item has completed.
+func (r *RunProgress) Average() time.Duration {
+	if len(r.durations) == 0 {
+		return 0
+	}
+	var sum time.Duration
+	for _, d := range r.durations {
+		sum += d
+	}
+	return sum / time.Duration(len(r.durations))
+}
+
+// ETA returns the estimated time until all items are done, or 0 when no
+// item has completed yet. The running item's elapsed time is subtracted.
+func (r *RunProgress) ETA() time.Duration {
+	avg := r.Average()
+	if avg == 0 {
+		return 0
+	}
+	eta := avg * time.Duration(r.total-r.done)
+	if !r.started.IsZero() {
+		eta -= min(r.now().Sub(r.started), avg)
+	}
+	return max(eta, 0)
+}
+
+// line builds the progress line for the current state.
+func (r *RunProgress) line() string {
+	parts := []string{fmt.Sprintf("%d/%d %ss", r.done, r.total, r.unit)}
+	if r.current != "" {
+		parts = append(parts, r.current)
+	}
+	if avg := r.Average(); avg > 0 {
+		parts = append(parts, "avg "+formatETA(avg)+"/"+r.unit)
+		if r.done < r.total {
+			parts = append(parts, "ETA "+formatETA(r.ETA()))
+		}
+	}
+
+	if !r.caps.IsTTY {
+		return "progress: " + strings.Join(parts, ", ")
+	}
+	sep := " · "
+	if !r.caps.SupportsUnicode {
+		sep = " | "
+	}
+	return r.bar() + " " + strings.Join(parts, sep)
+}
+
+// bar draws the progress bar, narrower on small terminals.
+func (r *RunProgress) bar() string {
+	width := 20
+	if r.caps.Width > 0 && r.caps.Width < 80 {
+		width = 10
+	}
+	filled := 0
+	if r.total > 0 {
+		filled = width * r.done / r.total
+	}
+
+	full, empty := "█", "░"
+	if !r.caps.SupportsUnicode {
+		full, empty = "#", "-"
+	}
+	bar := strings.Repeat(full, filled) + strings.Repeat(empty, width-filled)
+	if r.caps.SupportsColor {
+		bar = "\033[32m" + strings.Repeat(full, filled) + "\033[0m" + strings.Repeat(empty, width-filled)
+	}
+	return "[" + bar + "]"
+}
+
+// formatETA renders a duration rounded to the second, dropping zero units
+// ("3m12s", "1h5m", "45s").
+func formatETA(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		d = d.Round(time.Minute)
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
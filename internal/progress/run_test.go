@@ -0,0 +1,141 @@
+// Package progress tests the multi-task run progress renderer: bar, moving average and ETA.
+// Related: internal/progress/run.go
+// Tags: progress, eta, tasks, phases, tty
+package progress
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a clock function and a way to advance it.
+func fakeClock() (func() time.Time, func(time.Duration)) {
+	now := time.Date(2025, 1, 15, 14, 0, 0, 0, time.UTC)
+	return func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestRunProgress_NonTTY(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRunProgress(&buf, TerminalCapabilities{}, "task", 4, 1)
+	clock, advance := fakeClock()
+	r.now = clock
+
+	r.Start("T002 - Add parser")
+	advance(2 * time.Minute)
+	r.Complete()
+	r.Start("T003 - Wire handlers")
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	want := []string{
+		"progress: 1/4 tasks, T002 - Add parser",
+		"progress: 2/4 tasks, avg 2m/task, ETA 4m",
+		"progress: 2/4 tasks, T003 - Wire handlers, avg 2m/task, ETA 4m",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("got %d lines, want %d: %q", len(lines), len(want), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+	if strings.Contains(buf.String(), "\033[") {
+		t.Errorf("non-TTY output contains escape codes: %q", buf.String())
+	}
+}
+
+func TestRunProgress_TTYBar(t *testing.T) {
+	tests := map[string]struct {
+		caps    TerminalCapabilities
+		wantBar string
+		wantSep string
+	}{
+		"unicode": {
+			caps:    TerminalCapabilities{IsTTY: true, SupportsUnicode: true, Width: 120},
+			wantBar: "[██████████░░░░░░░░░░]",
+			wantSep: " · ",
+		},
+		"ascii narrow": {
+			caps:    TerminalCapabilities{IsTTY: true, Width: 60},
+			wantBar: "[#####-----]",
+			wantSep: " | ",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			r := NewRunProgress(&buf, tc.caps, "phase", 2, 1)
+			r.Start("Phase 2: Core")
+
+			got := buf.String()
+			if !strings.HasPrefix(got, tc.wantBar) {
+				t.Errorf("output = %q, want bar %q", got, tc.wantBar)
+			}
+			if !strings.Contains(got, "1/2 phases"+tc.wantSep+"Phase 2: Core") {
+				t.Errorf("output = %q, want separator %q", got, tc.wantSep)
+			}
+		})
+	}
+}
+
+func TestRunProgress_MovingAverageAndETA(t *testing.T) {
+	var buf bytes.Buffer
+	r := NewRunProgress(&buf, TerminalCapabilities{}, "task", 10, 0)
+	clock, advance := fakeClock()
+	r.now = clock
+
+	if r.ETA() != 0 {
+		t.Errorf("ETA() before any task = %v, want 0", r.ETA())
+	}
+
+	// Six tasks: the first (10m) falls out of the five-task window.
+	for _, d := range []time.Duration{10, 1, 1, 1, 1, 1} {
+		r.Start("task")
+		advance(d * time.Minute)
+		r.Complete()
+	}
+	if r.Average() != time.Minute {
+		t.Errorf("Average() = %v, want 1m", r.Average())
+	}
+	if r.ETA() != 4*time.Minute {
+		t.Errorf("ETA() = %v, want 4m", r.ETA())
+	}
+
+	// Elapsed time of the running task counts against the ETA.
+	r.Start("task")
+	advance(30 * time.Second)
+	if r.ETA() != 3*time.Minute+30*time.Second {
+		t.Errorf("ETA() while running = %v, want 3m30s", r.ETA())
+	}
+}
+
+func TestRunProgress_NilSafe(t *testing.T) {
+	var r *RunProgress
+	r.Start("task")
+	r.Complete()
+}
+
+func TestFormatETA(t *testing.T) {
+	tests := map[string]struct {
+		d    time.Duration
+		want string
+	}{
+		"seconds":         {d: 45 * time.Second, want: "45s"},
+		"minutes":         {d: 3*time.Minute + 12*time.Second, want: "3m12s"},
+		"whole minutes":   {d: 4 * time.Minute, want: "4m"},
+		"hours":           {d: time.Hour + 5*time.Minute + 20*time.Second, want: "1h5m"},
+		"whole hours":     {d: 2 * time.Hour, want: "2h"},
+		"sub-second trim": {d: 1500 * time.Millisecond, want: "2s"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := formatETA(tc.d); got != tc.want {
+				t.Errorf("formatETA(%v) = %q, want %q", tc.d, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+// Package promptsize estimates the token size of agent prompts and trims
+// context packs that would crowd the model's context window. Estimates use a
+// heuristic tokenizer, not the model's own, so limits keep a safety margin.
+package promptsize
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Truncation strategies, applied in the configured order until a prompt fits.
+const (
+	// DropLearnings removes learnings from the end of the ranked list: least
+	// reinforced first and, among equally reinforced ones, oldest first.
+	DropLearnings = "drop_learnings"
+	// SummarizeFiles replaces file content with its outline: the least
+	// indented lines of YAML files and the headings of Markdown files.
+	SummarizeFiles = "summarize_files"
+)
+
+// Strategies lists the valid truncation strategies.
+var Strategies = []string{DropLearnings, SummarizeFiles}
+
+// Defaults used when prompt_size is not configured.
+const (
+	DefaultContextWindow = 200000
+	DefaultWarnPercent   = 80
+)
+
+// Config configures prompt size checks (config key: prompt_size).
+type Config struct {
+	// ContextWindow is the model's context window in tokens. 0 disables
+	// estimation, warnings and truncation.
+	ContextWindow int `koanf:"context_window" yaml:"context_window" json:"context_window"`
+
+	// WarnPercent is the share of ContextWindow a prompt may use before
+	// autospec warns and applies Truncation.
+	WarnPercent int `koanf:"warn_percent" yaml:"warn_percent" json:"warn_percent"`
+
+	// Truncation lists the strategies applied, in order, to prompts over the
+	// limit. Empty only warns.
+	Truncation []string `koanf:"truncation" yaml:"truncation" json:"truncation"`
+}
+
+// Limit returns the estimated token count above which prompts warn and are
+// truncated, 0 when checks are disabled.
+func (c Config) Limit() int {
+	if c.ContextWindow <= 0 || c.WarnPercent <= 0 {
+		return 0
+	}
+	return c.ContextWindow * c.WarnPercent / 100
+}
+
+// EstimateTokens approximates how many tokens a model's tokenizer produces for
+// s: words split into chunks of about four characters, every punctuation or
+// symbol character and every CJK character count as one token, and whitespace
+// is free.
+func EstimateTokens(s string) int {
+	tokens, word := 0, 0
+	flush := func() {
+		tokens += (word + 3) / 4
+		word = 0
+	}
+	for _, r := range s {
+		switch {
+		case r >= 0x2E80 && unicode.IsLetter(r):
+			flush()
+			tokens++
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word++
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// Kind says how truncation may trim a Part.
+type Kind int
+
+const (
+	// Fixed parts (instructions, schemas) are never trimmed.
+	Fixed Kind = iota
+	// Learnings parts hold one item per learning, best first.
+	Learnings
+	// File parts hold a file's content, which may be summarized.
+	File
+)
+
+// Part is one piece of a prompt.
+type Part struct {
+	Name  string   // shown in the trim report, e.g. "learnings" or "plan.yaml"
+	Kind  Kind     // what truncation may do with the part
+	Text  string   // content of Fixed and File parts
+	Items []string // content of Learnings parts, best first
+}
+
+// Content returns the text the part contributes to the prompt.
+func (p Part) Content() string {
+	if p.Kind == Learnings {
+		return strings.Join(p.Items, "\n")
+	}
+	return p.Text
+}
+
+// Estimate returns the estimated token count of parts together.
+func Estimate(parts []Part) int {
+	total := 0
+	for _, p := range parts {
+		total += EstimateTokens(p.Content())
+	}
+	return total
+}
+
+// Result is the outcome of Fit.
+type Result struct {
+	Parts   []Part   // the parts after truncation, in the original order
+	Tokens  int      // estimated tokens after truncation
+	Limit   int      // the limit the parts were fitted to
+	Trimmed []string // what was trimmed, e.g. "dropped 3 of 10 learnings"
+}
+
+// Over reports whether the prompt is still above the limit.
+func (r Result) Over() bool {
+	return r.Limit > 0 && r.Tokens > r.Limit
+}
+
+// Fit applies cfg's truncation strategies to parts until their estimate is
+// within cfg.Limit(). Parts under the limit are returned unchanged.
+func Fit(parts []Part, cfg Config) Result {
+	r := Result{Parts: append([]Part(nil), parts...), Limit: cfg.Limit()}
+	r.Tokens = Estimate(r.Parts)
+	for _, strategy := range cfg.Truncation {
+		if !r.Over() {
+			break
+		}
+		switch strategy {
+		case DropLearnings:
+			r.dropLearnings()
+		case SummarizeFiles:
+			r.summarizeFiles()
+		}
+	}
+	return r
+}
+
+// dropLearnings removes learnings from the end of each Learnings part.
+func (r *Result) dropLearnings() {
+	for i := range r.Parts {
+		p := &r.Parts[i]
+		if p.Kind != Learnings {
+			continue
+		}
+		total := len(p.Items)
+		before := EstimateTokens(p.Content())
+		for len(p.Items) > 0 && r.Over() {
+			p.Items = p.Items[:len(p.Items)-1]
+			after := EstimateTokens(p.Content())
+			r.Tokens -= before - after
+			before = after
+		}
+		if dropped := total - len(p.Items); dropped > 0 {
+			r.Trimmed = append(r.Trimmed, fmt.Sprintf("dropped %d of %d %s", dropped, total, p.Name))
+		}
+	}
+}
+
+// summarizeFiles outlines File parts, largest first, each only as far as
+// needed for the prompt to fit.
+func (r *Result) summarizeFiles() {
+	for r.Over() {
+		i := r.largestFile()
+		if i < 0 {
+			return
+		}
+		p := &r.Parts[i]
+		before := EstimateTokens(p.Text)
+		lines := strings.Count(strings.TrimRight(p.Text, "\n"), "\n") + 1
+		summary, kept := "", 0
+		for _, depth := range summaryDepths {
+			summary, kept = Summarize(p.Name, p.Text, depth)
+			if r.Tokens-before+EstimateTokens(summary) <= r.Limit {
+				break
+			}
+		}
+		p.Text = summary
+		p.Kind = Fixed // summarized once; never again
+		r.Tokens += EstimateTokens(summary) - before
+		r.Trimmed = append(r.Trimmed, fmt.Sprintf("summarized %s (kept %d of %d lines)", p.Name, kept, lines))
+	}
+}
+
+// largestFile returns the index of the largest File part, -1 when none is left.
+func (r *Result) largestFile() int {
+	best, size := -1, 0
+	for i, p := range r.Parts {
+		if p.Kind == File && utf8.RuneCountInString(p.Text) > size {
+			best, size = i, utf8.RuneCountInString(p.Text)
+		}
+	}
+	return best
+}
+
+// summaryDepths are the indentation depths tried when outlining a file, most
+// detailed first.
+var summaryDepths = []int{6, 4, 2, 0}
+
+// Summarize returns an outline of a file and the number of lines it kept.
+// Markdown files (by name) keep their headings; other files keep the lines
+// indented at most depth spaces. A trailing comment says how many lines were
+// omitted.
+func Summarize(name, text string, depth int) (string, int) {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	markdown := strings.HasSuffix(name, ".md")
+	var kept []string
+	for _, line := range lines {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" {
+			continue
+		}
+		if markdown && !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if !markdown && len(line)-len(trimmed) > depth {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	count := len(kept)
+	if omitted := len(lines) - count; omitted > 0 {
+		kept = append(kept, fmt.Sprintf("# ... %d of %d lines omitted by autospec to fit the prompt size limit", omitted, len(lines)))
+	}
+	return strings.Join(kept, "\n"), count
+}
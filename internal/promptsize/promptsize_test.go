@@ -0,0 +1,157 @@
+package promptsize
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		text string
+		want int
+	}{
+		"empty":            {text: "", want: 0},
+		"whitespace only":  {text: " \n\t ", want: 0},
+		"short words":      {text: "add a test", want: 3},
+		"long word chunks": {text: "internationalization", want: 5},
+		"punctuation":      {text: "run: go test ./...", want: 9},
+		"cjk characters":   {text: "日本語", want: 3},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, EstimateTokens(tt.text))
+		})
+	}
+}
+
+func TestConfigLimit(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 160000, Config{ContextWindow: 200000, WarnPercent: 80}.Limit())
+	assert.Zero(t, Config{WarnPercent: 80}.Limit(), "no context window disables checks")
+	assert.Zero(t, Config{ContextWindow: 200000}.Limit())
+}
+
+func TestFit(t *testing.T) {
+	t.Parallel()
+	instructions := Part{Name: "instructions", Kind: Fixed, Text: words(40)}
+	learnings := Part{Name: "learnings", Kind: Learnings, Items: []string{words(10), words(10), words(10), words(10)}}
+	plan := Part{Name: "plan.yaml", Kind: File, Text: "plan:\n  summary: x\n" + strings.Repeat("    - detail line here\n", 30)}
+
+	tests := map[string]struct {
+		window      int
+		truncation  []string
+		wantItems   int
+		wantTrimmed []string
+		wantOver    bool
+	}{
+		"under the limit": {
+			window:     1000,
+			truncation: Strategies,
+			wantItems:  4,
+		},
+		"drop learnings only": {
+			window:      150,
+			truncation:  []string{DropLearnings},
+			wantItems:   0,
+			wantTrimmed: []string{"dropped 4 of 4 learnings"},
+			wantOver:    true,
+		},
+		"drop learnings then summarize": {
+			window:      150,
+			truncation:  Strategies,
+			wantItems:   0,
+			wantTrimmed: []string{"dropped 4 of 4 learnings", "summarized plan.yaml (kept 2 of 32 lines)"},
+		},
+		"summarize is enough": {
+			window:      200,
+			truncation:  []string{SummarizeFiles, DropLearnings},
+			wantItems:   4,
+			wantTrimmed: []string{"summarized plan.yaml (kept 2 of 32 lines)"},
+		},
+		"warn only": {
+			window:    100,
+			wantItems: 4,
+			wantOver:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cfg := Config{ContextWindow: tt.window, WarnPercent: 100, Truncation: tt.truncation}
+			r := Fit([]Part{instructions, learnings, plan}, cfg)
+			require.Len(t, r.Parts, 3)
+			assert.Equal(t, instructions, r.Parts[0])
+			assert.Len(t, r.Parts[1].Items, tt.wantItems)
+			assert.Equal(t, tt.wantTrimmed, r.Trimmed)
+			assert.Equal(t, tt.wantOver, r.Over())
+			assert.Equal(t, Estimate(r.Parts), r.Tokens)
+			assert.Len(t, learnings.Items, 4, "input parts are not modified")
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	t.Parallel()
+	yamlText := "plan:\n  summary: x\n  steps:\n    - one\n    - two\n"
+	tests := map[string]struct {
+		name     string
+		text     string
+		depth    int
+		want     string
+		wantKept int
+	}{
+		"yaml top level": {
+			name:     "plan.yaml",
+			text:     yamlText,
+			depth:    0,
+			want:     "plan:\n# ... 4 of 5 lines omitted by autospec to fit the prompt size limit",
+			wantKept: 1,
+		},
+		"yaml second level": {
+			name:     "plan.yaml",
+			text:     yamlText,
+			depth:    2,
+			want:     "plan:\n  summary: x\n  steps:\n# ... 2 of 5 lines omitted by autospec to fit the prompt size limit",
+			wantKept: 3,
+		},
+		"nothing to omit": {
+			name:     "plan.yaml",
+			text:     yamlText,
+			depth:    6,
+			want:     strings.TrimRight(yamlText, "\n"),
+			wantKept: 5,
+		},
+		"markdown headings": {
+			name:     "constitution.md",
+			text:     "# Constitution\n\nText.\n## Principles\n- one\n",
+			depth:    6,
+			want:     "# Constitution\n## Principles\n# ... 3 of 5 lines omitted by autospec to fit the prompt size limit",
+			wantKept: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, kept := Summarize(tt.name, tt.text, tt.depth)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantKept, kept)
+		})
+	}
+}
+
+// words returns n short words, one token each.
+func words(n int) string {
+	w := make([]string, n)
+	for i := range w {
+		w[i] = "word"
+	}
+	return strings.Join(w, " ")
+}
@@ -0,0 +1,379 @@
+// Package ratelimit recognises provider rate limits in agent output and
+// decides how long to wait before the session is run again. The workflow
+// executor waits out a rate-limited session instead of counting it as a
+// failed attempt, so provider throttling does not use up max_retries.
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tailLines is how many trailing output lines are searched for a rate limit.
+// Agents report API errors last; searching only the tail keeps code or docs
+// that merely mention rate limits from triggering a wait.
+const tailLines = 20
+
+// Output streams of an agent session.
+const (
+	stdout = iota
+	stderr
+)
+
+// maxLineBuffer bounds the partial-line buffer.
+const maxLineBuffer = 64 << 10
+
+// maxBackoff caps a single wait computed without a provider hint.
+const maxBackoff = 15 * time.Minute
+
+// Config controls rate-limit handling.
+type Config struct {
+	// Enabled turns detection and waiting on. When off, a rate-limited
+	// session is an ordinary execution failure.
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// MaxWait caps the total time a run spends waiting on rate limits.
+	// Once reached, a rate-limited session fails like any other.
+	MaxWait time.Duration `koanf:"max_wait" yaml:"max_wait" json:"max_wait"`
+
+	// InitialBackoff is the first wait when the provider gives no retry hint.
+	// Consecutive rate limits double it, up to 15 minutes per wait.
+	InitialBackoff time.Duration `koanf:"initial_backoff" yaml:"initial_backoff" json:"initial_backoff"`
+}
+
+// Signal is a rate limit reported by the agent.
+type Signal struct {
+	// Provider names the API whose error format matched: "anthropic",
+	// "openai", "gemini", or "" for generic HTTP 429 wording.
+	Provider string
+	// RetryAfter is the provider's hint, zero when it gave none.
+	RetryAfter time.Duration
+	// Line is the output line reporting the limit.
+	Line string
+}
+
+// ExhaustedError reports that waiting for a rate limit would exceed MaxWait.
+type ExhaustedError struct {
+	Waited  time.Duration
+	Wanted  time.Duration
+	MaxWait time.Duration
+}
+
+func (e *ExhaustedError) Error() string {
+	return fmt.Sprintf("rate limit wait cap reached: waited %s of rate_limit.max_wait %s, next wait would be %s",
+		e.Waited.Round(time.Second), e.MaxWait, e.Wanted.Round(time.Second))
+}
+
+// Limiter watches agent sessions for rate limits and tracks time spent
+// waiting on them. A nil *Limiter is valid: it detects nothing.
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu       sync.Mutex
+	partial  [2][]byte // Unterminated last line, per stream
+	tail     []outputLine
+	signaled bool
+	streak   int
+	waited   time.Duration
+}
+
+// New creates a limiter. Returns nil when rate-limit handling is disabled.
+func New(cfg Config) *Limiter {
+	if !cfg.Enabled {
+		return nil
+	}
+	return &Limiter{cfg: cfg, now: time.Now}
+}
+
+// StartSession clears the output seen so far. A session that ended without
+// a rate limit also resets the backoff streak.
+func (l *Limiter) StartSession() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.signaled {
+		l.streak = 0
+	}
+	l.partial, l.tail, l.signaled = [2][]byte{}, nil, false
+}
+
+// StdoutWriter returns a writer that feeds the agent's stdout to the
+// limiter. Returns nil for a nil limiter.
+func (l *Limiter) StdoutWriter() io.Writer {
+	if l == nil {
+		return nil
+	}
+	return limiterWriter{l, stdout}
+}
+
+// StderrWriter returns a writer that feeds the agent's stderr to the
+// limiter. Returns nil for a nil limiter.
+func (l *Limiter) StderrWriter() io.Writer {
+	if l == nil {
+		return nil
+	}
+	return limiterWriter{l, stderr}
+}
+
+// outputLine is a line of agent output and the stream it was written to.
+type outputLine struct {
+	text   string
+	stream int
+}
+
+type limiterWriter struct {
+	l      *Limiter
+	stream int
+}
+
+func (w limiterWriter) Write(p []byte) (int, error) {
+	w.l.mu.Lock()
+	defer w.l.mu.Unlock()
+	buf := append(w.l.partial[w.stream], p...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			break
+		}
+		w.l.addLine(outputLine{string(buf[:i]), w.stream})
+		buf = buf[i+1:]
+	}
+	if len(buf) > maxLineBuffer {
+		buf = buf[len(buf)-maxLineBuffer:]
+	}
+	w.l.partial[w.stream] = buf
+	return len(p), nil
+}
+
+func (l *Limiter) addLine(line outputLine) {
+	if strings.TrimSpace(line.text) == "" {
+		return
+	}
+	l.tail = append(l.tail, line)
+	if len(l.tail) > tailLines {
+		l.tail = l.tail[len(l.tail)-tailLines:]
+	}
+}
+
+// Detect reports whether the current session's output ends with a rate
+// limit. Only provider errors are searched: stderr, stream-json error and
+// failed result messages, and stdout lines that start like an API error.
+// Tool results and assistant text may quote rate-limit errors from the code
+// being worked on and are ignored. Call after the session failed.
+func (l *Limiter) Detect() (Signal, bool) {
+	if l == nil {
+		return Signal{}, false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	tail := l.tail
+	for stream, partial := range l.partial {
+		if len(partial) > 0 {
+			tail = append(append([]outputLine(nil), tail...), outputLine{string(partial), stream})
+		}
+	}
+	var lines []string
+	for _, line := range tail {
+		if providerError(line) {
+			lines = append(lines, line.text)
+		}
+	}
+	sig, ok := Parse(lines, l.now())
+	l.signaled = ok
+	return sig, ok
+}
+
+// apiErrorLine matches plain-text stdout lines in which an agent CLI reports
+// a failed API call, e.g. "API Error: 429 ..." or "stream error: ...", and
+// Claude's subscription limit notice.
+var apiErrorLine = regexp.MustCompile(`(?i)^\W*(\w+\s+)?error\b|usage limit reached\|\d`)
+
+// providerError reports whether an output line can carry a provider error.
+func providerError(line outputLine) bool {
+	if line.stream == stderr {
+		return true
+	}
+	text := strings.TrimSpace(line.text)
+	if !strings.HasPrefix(text, "{") {
+		return apiErrorLine.MatchString(text)
+	}
+	var msg struct {
+		Type    string          `json:"type"`
+		IsError bool            `json:"is_error"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(text), &msg); err != nil {
+		return false
+	}
+	switch msg.Type {
+	case "result":
+		return msg.IsError
+	case "error":
+		return true
+	case "":
+		// A bare API error body, e.g. {"error": {"code": 429, ...}}
+		return len(msg.Error) > 0
+	}
+	return false
+}
+
+// Delay returns how long to wait before running a rate-limited session
+// again: the provider's hint when there is one, otherwise an exponential
+// backoff. It returns an *ExhaustedError when the wait would take the run
+// past MaxWait.
+func (l *Limiter) Delay(sig Signal) (time.Duration, error) {
+	if l == nil {
+		return 0, fmt.Errorf("rate limit handling is disabled")
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delay := sig.RetryAfter
+	if delay <= 0 {
+		delay = l.cfg.InitialBackoff << l.streak
+		if delay > maxBackoff || delay <= 0 {
+			delay = maxBackoff
+		}
+	}
+	if l.cfg.MaxWait > 0 && l.waited+delay > l.cfg.MaxWait {
+		return 0, &ExhaustedError{Waited: l.waited, Wanted: delay, MaxWait: l.cfg.MaxWait}
+	}
+	return delay, nil
+}
+
+// Wait sleeps for d, or until ctx is done, and adds the time to the run's
+// total. Returns the context's cause if it ended the wait early.
+func (l *Limiter) Wait(ctx context.Context, d time.Duration) error {
+	if l == nil {
+		return nil
+	}
+	start := l.now()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-ctx.Done():
+		err = context.Cause(ctx)
+	case <-timer.C:
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waited += l.now().Sub(start)
+	l.streak++
+	return err
+}
+
+// Waited returns the total time spent waiting on rate limits.
+func (l *Limiter) Waited() time.Duration {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.waited
+}
+
+// pattern recognises one provider's rate-limit error.
+type pattern struct {
+	provider string
+	match    *regexp.Regexp
+}
+
+// Most specific first: the first matching pattern names the provider.
+var patterns = []pattern{
+	{"anthropic", regexp.MustCompile(`(?i)rate_limit_error|overloaded_error|usage limit reached|"api_error_status"\s*:\s*(429|529)`)},
+	{"openai", regexp.MustCompile(`(?i)rate_limit_exceeded|rate limit reached for`)},
+	{"gemini", regexp.MustCompile(`(?i)resource_exhausted|quota exceeded`)},
+	{"", regexp.MustCompile(`(?i)(status|error|http|code)\D{0,12}\b429\b|too many requests|rate[ _-]?limit(ed)?\b|\boverloaded\b`)},
+}
+
+// Retry hints, in the formats providers use.
+var (
+	// Claude subscription limits: "Claude AI usage limit reached|1735689600"
+	resetEpochHint = regexp.MustCompile(`(?i)usage limit reached\|(\d{9,})`)
+	// Gemini: "retryDelay": "32s"
+	retryDelayHint = regexp.MustCompile(`(?i)"retryDelay"\s*:\s*"(\d+(?:\.\d+)?)s"`)
+	// OpenAI and others: "Please try again in 20s" or "in 1.5 minutes"
+	tryAgainHint = regexp.MustCompile(`(?i)(?:try again|retry) in (\d+(?:\.\d+)?)\s*(ms|milliseconds?|s|secs?|seconds?|m|mins?|minutes?|h|hours?)\b`)
+	// HTTP header echoed in errors: "retry-after: 30"
+	retryAfterHint = regexp.MustCompile(`(?i)retry[-_ ]after["']?\s*[:=]\s*["']?(\d+)`)
+)
+
+// Parse looks for a rate limit in the given provider error lines, the last
+// one first, and extracts any retry hint. now resolves reset timestamps.
+func Parse(lines []string, now time.Time) (Signal, bool) {
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := lines[i]
+		for _, p := range patterns {
+			if !p.match.MatchString(line) {
+				continue
+			}
+			sig := Signal{Provider: p.provider, Line: strings.TrimSpace(line)}
+			// A hint may sit on another line of a multi-line error.
+			for j := i; j < len(lines) && sig.RetryAfter == 0; j++ {
+				sig.RetryAfter = parseHint(lines[j], now)
+			}
+			for j := i - 1; j >= 0 && sig.RetryAfter == 0; j-- {
+				sig.RetryAfter = parseHint(lines[j], now)
+			}
+			return sig, true
+		}
+	}
+	return Signal{}, false
+}
+
+// parseHint returns the wait a line asks for, or zero.
+func parseHint(line string, now time.Time) time.Duration {
+	if m := resetEpochHint.FindStringSubmatch(line); m != nil {
+		if sec, err := strconv.ParseInt(m[1], 10, 64); err == nil {
+			if d := time.Unix(sec, 0).Sub(now); d > 0 {
+				return d
+			}
+		}
+	}
+	if m := retryDelayHint.FindStringSubmatch(line); m != nil {
+		return seconds(m[1], time.Second)
+	}
+	if m := tryAgainHint.FindStringSubmatch(line); m != nil {
+		return seconds(m[1], unit(m[2]))
+	}
+	if m := retryAfterHint.FindStringSubmatch(line); m != nil {
+		return seconds(m[1], time.Second)
+	}
+	return 0
+}
+
+func unit(s string) time.Duration {
+	s = strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(s, "ms"), strings.HasPrefix(s, "milli"):
+		return time.Millisecond
+	case strings.HasPrefix(s, "h"):
+		return time.Hour
+	case strings.HasPrefix(s, "m"):
+		return time.Minute
+	default:
+		return time.Second
+	}
+}
+
+func seconds(value string, per time.Duration) time.Duration {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f <= 0 {
+		return 0
+	}
+	return time.Duration(f * float64(per))
+}
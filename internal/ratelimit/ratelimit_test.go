@@ -0,0 +1,220 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	now := time.Unix(1_700_000_000, 0)
+
+	tests := map[string]struct {
+		lines        []string
+		wantOK       bool
+		wantProvider string
+		wantWait     time.Duration
+	}{
+		"anthropic stream-json error": {
+			lines:        []string{`{"type":"result","is_error":true,"api_error_status":429,"result":"API Error: 429 rate_limit_error"}`},
+			wantOK:       true,
+			wantProvider: "anthropic",
+		},
+		"anthropic overloaded": {
+			lines:        []string{`API Error: 529 {"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`},
+			wantOK:       true,
+			wantProvider: "anthropic",
+		},
+		"claude usage limit with reset time": {
+			lines:        []string{fmt.Sprintf("Claude AI usage limit reached|%d", now.Add(90*time.Minute).Unix())},
+			wantOK:       true,
+			wantProvider: "anthropic",
+			wantWait:     90 * time.Minute,
+		},
+		"openai try again hint": {
+			lines:        []string{"Rate limit reached for gpt-4o in organization org-x on tokens per min. Please try again in 1.5s."},
+			wantOK:       true,
+			wantProvider: "openai",
+			wantWait:     1500 * time.Millisecond,
+		},
+		"gemini retry delay on another line": {
+			lines:        []string{`"status": "RESOURCE_EXHAUSTED",`, `"retryDelay": "32s"`},
+			wantOK:       true,
+			wantProvider: "gemini",
+			wantWait:     32 * time.Second,
+		},
+		"generic 429 with retry-after header": {
+			lines:    []string{"HTTP 429 Too Many Requests", "retry-after: 45"},
+			wantOK:   true,
+			wantWait: 45 * time.Second,
+		},
+		"minutes hint": {
+			lines:    []string{"You are being rate limited, try again in 2 minutes"},
+			wantOK:   true,
+			wantWait: 2 * time.Minute,
+		},
+		"past reset time gives no hint": {
+			lines:        []string{fmt.Sprintf("Claude AI usage limit reached|%d", now.Add(-time.Minute).Unix())},
+			wantOK:       true,
+			wantProvider: "anthropic",
+		},
+		"unrelated failure": {
+			lines: []string{"Error: tests failed", "exit status 1"},
+		},
+		"bare number is not a status": {
+			lines: []string{"main.go:429: undefined: foo"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			sig, ok := Parse(tt.lines, now)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantProvider, sig.Provider)
+			assert.Equal(t, tt.wantWait, sig.RetryAfter)
+		})
+	}
+}
+
+func TestLimiter_DetectsOnlyTail(t *testing.T) {
+	t.Parallel()
+	l := New(Config{Enabled: true, InitialBackoff: time.Second})
+	w := l.StderrWriter()
+
+	l.StartSession()
+	_, _ = fmt.Fprintln(w, "Implementing rate limit middleware")
+	_, _ = fmt.Fprint(w, strings.Repeat("ok\n", tailLines))
+	_, ok := l.Detect()
+	assert.False(t, ok, "mentions scrolled out of the tail are ignored")
+
+	l.StartSession()
+	_, _ = fmt.Fprint(w, "working\nError: 429 Too Many Requests")
+	sig, ok := l.Detect()
+	require.True(t, ok, "an unterminated last line is searched")
+	assert.Equal(t, "Error: 429 Too Many Requests", sig.Line)
+}
+
+func TestLimiter_OnlyProviderErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		stdout string
+		stderr string
+		want   bool
+	}{
+		"stream-json failed result": {
+			stdout: `{"type":"result","subtype":"success","is_error":true,"result":"API Error: 429 {\"type\":\"error\",\"error\":{\"type\":\"rate_limit_error\"}}"}`,
+			want:   true,
+		},
+		"stream-json error event": {
+			stdout: `{"type":"error","error":{"type":"overloaded_error","message":"Overloaded"}}`,
+			want:   true,
+		},
+		"bare API error body": {
+			stdout: `{"error":{"code":429,"status":"RESOURCE_EXHAUSTED"}}`,
+			want:   true,
+		},
+		"plain API error line": {
+			stdout: "API Error: 429 Too Many Requests",
+			want:   true,
+		},
+		"usage limit notice": {
+			stdout: "Claude AI usage limit reached|1735689600",
+			want:   true,
+		},
+		"stderr": {
+			stderr: "HTTP 429 Too Many Requests",
+			want:   true,
+		},
+		"tool result quoting a 429": {
+			stdout: `{"type":"user","message":{"content":[{"type":"tool_result","content":"FAIL: expected rate_limit_error, got 429 Too Many Requests"}]}}`,
+		},
+		"assistant text about rate limits": {
+			stdout: `{"type":"assistant","message":{"content":[{"type":"text","text":"The API is rate limited: overloaded_error means retry later"}]}}`,
+		},
+		"successful result": {
+			stdout: `{"type":"result","is_error":false,"result":"Added 429 Too Many Requests handling"}`,
+		},
+		"plain text mention": {
+			stdout: "The handler now returns 429 Too Many Requests when rate limited",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			l := New(Config{Enabled: true, InitialBackoff: time.Second})
+			l.StartSession()
+			_, _ = fmt.Fprintln(l.StdoutWriter(), tt.stdout)
+			_, _ = fmt.Fprintln(l.StderrWriter(), tt.stderr)
+			_, ok := l.Detect()
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestLimiter_Delay(t *testing.T) {
+	t.Parallel()
+	l := New(Config{Enabled: true, MaxWait: 10 * time.Second, InitialBackoff: 2 * time.Second})
+
+	d, err := l.Delay(Signal{RetryAfter: 3 * time.Second})
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, d, "provider hint wins")
+
+	d, err = l.Delay(Signal{})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, d)
+
+	// Consecutive rate limits double the backoff
+	l.streak, l.signaled = 1, true
+	l.StartSession()
+	d, err = l.Delay(Signal{})
+	require.NoError(t, err)
+	assert.Equal(t, 4*time.Second, d)
+
+	// A session without a rate limit resets the streak
+	l.StartSession()
+	d, err = l.Delay(Signal{})
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, d)
+
+	l.waited = 9 * time.Second
+	_, err = l.Delay(Signal{})
+	var exhausted *ExhaustedError
+	require.ErrorAs(t, err, &exhausted)
+	assert.Contains(t, err.Error(), "rate_limit.max_wait 10s")
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	t.Parallel()
+	l := New(Config{Enabled: true, InitialBackoff: time.Second})
+
+	require.NoError(t, l.Wait(context.Background(), 5*time.Millisecond))
+	assert.GreaterOrEqual(t, l.Waited(), 5*time.Millisecond)
+
+	stop := errors.New("stopped")
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(stop)
+	assert.ErrorIs(t, l.Wait(ctx, time.Hour), stop)
+}
+
+func TestNilLimiter(t *testing.T) {
+	t.Parallel()
+	var l *Limiter
+	assert.Nil(t, New(Config{}))
+	assert.Nil(t, l.StdoutWriter())
+	assert.Nil(t, l.StderrWriter())
+	l.StartSession()
+	_, ok := l.Detect()
+	assert.False(t, ok)
+	assert.NoError(t, l.Wait(context.Background(), time.Hour))
+	assert.Zero(t, l.Waited())
+}
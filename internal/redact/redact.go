@@ -0,0 +1,217 @@
+// Package redact masks secrets in agent output before it is displayed, written
+// to run logs or sent in notifications. Secrets are found with regular
+// expressions (built-in patterns for common credentials plus user-configured
+// ones) and with the literal values of the project's .env files.
+package redact
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Mask replaces each redacted secret.
+const Mask = "[REDACTED]"
+
+// minEnvValueLength skips short .env values (ports, flags, "true") that would
+// mask ordinary output.
+const minEnvValueLength = 8
+
+// plainWord matches .env values such as "production" or "development", which
+// are settings rather than secrets and would mask ordinary output.
+var plainWord = regexp.MustCompile(`^[A-Za-z]+$`)
+
+// maxLineBuffer bounds the partial line a Writer holds back. Longer lines are
+// redacted and written in pieces.
+const maxLineBuffer = 1 << 20
+
+// DefaultPatterns match common credentials. When a pattern has a capture
+// group, only the first group is masked, so "API_KEY=abc..." keeps its name.
+var DefaultPatterns = []string{
+	`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`,                                                  // AWS access key ID
+	`(?i)aws_secret_access_key["']?\s*[=:]\s*["']?([A-Za-z0-9/+=]{40})`,              // AWS secret access key
+	`\bgh[pousr]_[A-Za-z0-9]{36,}\b`,                                                 // GitHub tokens
+	`\bgithub_pat_[A-Za-z0-9_]{22,}\b`,                                               // GitHub fine-grained tokens
+	`\bsk-[A-Za-z0-9_-]{20,}`,                                                        // Anthropic and OpenAI API keys
+	`\bxox[abprs]-[A-Za-z0-9-]{10,}`,                                                 // Slack tokens
+	`\bglpat-[A-Za-z0-9_-]{20,}`,                                                     // GitLab tokens
+	`(?i)\bbearer\s+([A-Za-z0-9._~+/-]{20,}=*)`,                                      // Authorization headers
+	`(?i)(?:password|passwd|secret|token|api_?key)["']?\s*[=:]\s*["']?([^\s"']{8,})`, // KEY=value assignments
+}
+
+// Config controls redaction (config key: redaction).
+type Config struct {
+	// Enabled turns redaction on (default: true).
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// Patterns are extra regular expressions to mask, added to DefaultPatterns.
+	Patterns []string `koanf:"patterns" yaml:"patterns" json:"patterns"`
+
+	// EnvFiles are dotenv files whose values are masked wherever they appear
+	// (default: [".env"]). Missing files are skipped.
+	EnvFiles []string `koanf:"env_files" yaml:"env_files" json:"env_files"`
+}
+
+// Redactor masks secrets in strings and streams.
+// A nil *Redactor is valid and leaves text unchanged, so callers never need to
+// check whether redaction is enabled.
+type Redactor struct {
+	patterns []*regexp.Regexp
+	literals []string
+}
+
+// New builds a Redactor from cfg. Returns nil when redaction is disabled, and
+// an error when a pattern does not compile or an env file cannot be read.
+func New(cfg Config) (*Redactor, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	r := &Redactor{}
+	for _, expr := range append(append([]string{}, DefaultPatterns...), cfg.Patterns...) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", expr, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	for _, path := range cfg.EnvFiles {
+		values, err := envFileValues(path)
+		if err != nil {
+			return nil, err
+		}
+		r.literals = append(r.literals, values...)
+	}
+	return r, nil
+}
+
+// String returns s with every secret replaced by Mask.
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	for _, literal := range r.literals {
+		s = strings.ReplaceAll(s, literal, Mask)
+	}
+	for _, re := range r.patterns {
+		s = replacePattern(re, s)
+	}
+	return s
+}
+
+// Strings returns a copy of ss with each element redacted.
+func (r *Redactor) Strings(ss []string) []string {
+	if r == nil || len(ss) == 0 {
+		return ss
+	}
+	out := make([]string, len(ss))
+	for i, s := range ss {
+		out[i] = r.String(s)
+	}
+	return out
+}
+
+// replacePattern masks the matches of re in s, or only their first capture
+// group when re has one.
+func replacePattern(re *regexp.Regexp, s string) string {
+	if re.NumSubexp() == 0 {
+		return re.ReplaceAllLiteralString(s, Mask)
+	}
+	return re.ReplaceAllStringFunc(s, func(match string) string {
+		sub := re.FindStringSubmatchIndex(match)
+		if len(sub) < 4 || sub[2] < 0 {
+			return Mask
+		}
+		return match[:sub[2]] + Mask + match[sub[3]:]
+	})
+}
+
+// envFileValues returns the values in a dotenv file that look like secrets:
+// long enough and not a plain word. A missing file yields no values.
+func envFileValues(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading redaction env file: %w", err)
+	}
+
+	var values []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		_, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if len(value) >= minEnvValueLength && !plainWord.MatchString(value) {
+			values = append(values, value)
+		}
+	}
+	return values, nil
+}
+
+// Writer redacts complete lines before passing them to the underlying writer.
+// Partial lines are held back until their newline arrives (or Flush is
+// called), so a secret split across writes is still matched.
+type Writer struct {
+	r   *Redactor
+	w   io.Writer
+	mu  sync.Mutex
+	buf []byte
+}
+
+// NewWriter returns w wrapped in a redacting Writer, or w itself when r is nil.
+func (r *Redactor) NewWriter(w io.Writer) io.Writer {
+	if r == nil {
+		return w
+	}
+	return &Writer{r: r, w: w}
+}
+
+// Write buffers p and writes every completed line, redacted.
+// It reports len(p) on success, as the bytes were consumed.
+func (rw *Writer) Write(p []byte) (int, error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	rw.buf = append(rw.buf, p...)
+	end := bytes.LastIndexByte(rw.buf, '\n')
+	if end < 0 && len(rw.buf) < maxLineBuffer {
+		return len(p), nil
+	}
+	if end < 0 {
+		end = len(rw.buf) - 1
+	}
+
+	lines := string(rw.buf[:end+1])
+	rw.buf = append(rw.buf[:0], rw.buf[end+1:]...)
+	if _, err := io.WriteString(rw.w, rw.r.String(lines)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered partial line.
+func (rw *Writer) Flush() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if len(rw.buf) == 0 {
+		return nil
+	}
+	rest := string(rw.buf)
+	rw.buf = rw.buf[:0]
+	_, err := io.WriteString(rw.w, rw.r.String(rest))
+	return err
+}
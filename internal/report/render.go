@@ -0,0 +1,351 @@
+package report
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format selects the output format of a rendered report.
+type Format string
+
+const (
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat validates a user-supplied format name.
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	default:
+		return "", fmt.Errorf("unknown report format %q (valid: markdown, html)", s)
+	}
+}
+
+// Render writes the report to w in the given format.
+func Render(w io.Writer, r *Report, format Format) error {
+	var out string
+	switch format {
+	case FormatHTML:
+		out = RenderHTML(r)
+	default:
+		out = RenderMarkdown(r)
+	}
+	if _, err := io.WriteString(w, out); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// RenderMarkdown renders the report as GitHub-flavored Markdown.
+func RenderMarkdown(r *Report) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Completion Report: %s\n\n", r.SpecName)
+	writeMarkdownOverview(&sb, r)
+	writeMarkdownStories(&sb, r)
+	writeMarkdownTasks(&sb, r)
+	writeMarkdownFiles(&sb, r)
+	writeMarkdownTaskFiles(&sb, r)
+	writeMarkdownValidation(&sb, r)
+	writeMarkdownRetries(&sb, r)
+	fmt.Fprintf(&sb, "_Generated by autospec on %s_\n", r.GeneratedAt.Format(time.RFC3339))
+	return sb.String()
+}
+
+func writeMarkdownOverview(sb *strings.Builder, r *Report) {
+	if r.FeatureInput != "" {
+		fmt.Fprintf(sb, "> %s\n\n", r.FeatureInput)
+	}
+	if r.SpecStatus != "" {
+		fmt.Fprintf(sb, "- **Status:** %s\n", r.SpecStatus)
+	}
+	if len(r.Stories) > 0 {
+		fmt.Fprintf(sb, "- **User stories delivered:** %d/%d\n", r.DeliveredStories(), len(r.Stories))
+	}
+	if r.Stats != nil {
+		fmt.Fprintf(sb, "- **Tasks completed:** %d/%d (%.0f%%)\n",
+			r.Stats.CompletedTasks, r.Stats.TotalTasks, r.Stats.CompletionPercentage())
+	}
+	if r.Forecast != nil {
+		fmt.Fprintf(sb, "- **Forecast:** %s\n", r.Forecast)
+	}
+	if total := totalCommandDuration(r); total > 0 {
+		fmt.Fprintf(sb, "- **Total agent time:** %s\n", formatDuration(total))
+	}
+	sb.WriteString("\n")
+}
+
+func writeMarkdownStories(sb *strings.Builder, r *Report) {
+	if len(r.Stories) == 0 {
+		return
+	}
+	sb.WriteString("## User Stories\n\n| Story | Priority | Tasks | Delivered |\n|---|---|---|---|\n")
+	for _, s := range r.Stories {
+		fmt.Fprintf(sb, "| %s %s | %s | %d/%d | %s |\n",
+			s.ID, mdCell(s.Title), s.Priority, s.CompletedTasks, s.TotalTasks, checkmark(s.Delivered))
+	}
+	sb.WriteString("\n")
+}
+
+func writeMarkdownTasks(sb *strings.Builder, r *Report) {
+	if len(r.Tasks) == 0 {
+		return
+	}
+	sb.WriteString("## Tasks\n\n| Task | Phase | Status | Duration | Estimate |\n|---|---|---|---|---|\n")
+	for _, t := range r.Tasks {
+		status := t.Status
+		if t.Manual {
+			status += " (manual)"
+		}
+		fmt.Fprintf(sb, "| %s %s | %s | %s | %s | %s |\n",
+			t.ID, mdCell(t.Title), mdCell(t.Phase), status, formatDuration(t.Duration), formatDuration(t.Estimate))
+	}
+	sb.WriteString("\n")
+}
+
+func writeMarkdownFiles(sb *strings.Builder, r *Report) {
+	if len(r.FilesChanged) == 0 && r.FilesError == "" {
+		return
+	}
+	fmt.Fprintf(sb, "## Files Changed (%d)\n\n", len(r.FilesChanged))
+	if r.FilesError != "" {
+		fmt.Fprintf(sb, "_Could not determine changed files: %s_\n\n", r.FilesError)
+		return
+	}
+	for _, f := range r.FilesChanged {
+		fmt.Fprintf(sb, "- `%s`\n", f)
+	}
+	sb.WriteString("\n")
+}
+
+func writeMarkdownTaskFiles(sb *strings.Builder, r *Report) {
+	if !hasTaskFiles(r) {
+		return
+	}
+	sb.WriteString("## Files Changed by Task\n\n")
+	for _, t := range r.Tasks {
+		if len(t.Files) == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "- **%s** %s\n", t.ID, t.Title)
+		for _, f := range t.Files {
+			fmt.Fprintf(sb, "  - `%s`\n", f)
+		}
+	}
+	sb.WriteString("\n")
+}
+
+func writeMarkdownValidation(sb *strings.Builder, r *Report) {
+	sb.WriteString("## Validation\n\n| Artifact | Result | Errors | Warnings |\n|---|---|---|---|\n")
+	for _, v := range r.Validation {
+		fmt.Fprintf(sb, "| %s | %s | %d | %d |\n", v.Artifact, validationLabel(v), v.Errors, v.Warnings)
+	}
+	sb.WriteString("\n")
+}
+
+func writeMarkdownRetries(sb *strings.Builder, r *Report) {
+	if len(r.Retries) == 0 {
+		return
+	}
+	sb.WriteString("## Notable Retries\n\n")
+	for _, rt := range r.Retries {
+		fmt.Fprintf(sb, "- **%s**: %d retr%s (last %s)\n",
+			rt.Stage, rt.Count, pluralY(rt.Count), rt.LastAttempt.Format("2006-01-02 15:04"))
+	}
+	sb.WriteString("\n")
+}
+
+// RenderHTML renders the report as a standalone HTML document.
+func RenderHTML(r *Report) string {
+	var sb strings.Builder
+	title := "Completion Report: " + r.SpecName
+	fmt.Fprintf(&sb, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n<title>%s</title>\n", esc(title))
+	sb.WriteString("<style>body{font-family:sans-serif;max-width:960px;margin:2em auto}" +
+		"table{border-collapse:collapse;width:100%}td,th{border:1px solid #ccc;padding:4px 8px;text-align:left}</style>\n")
+	fmt.Fprintf(&sb, "</head>\n<body>\n<h1>%s</h1>\n", esc(title))
+	writeHTMLOverview(&sb, r)
+	writeHTMLStories(&sb, r)
+	writeHTMLTasks(&sb, r)
+	writeHTMLFiles(&sb, r)
+	writeHTMLTaskFiles(&sb, r)
+	writeHTMLValidation(&sb, r)
+	writeHTMLRetries(&sb, r)
+	fmt.Fprintf(&sb, "<p><em>Generated by autospec on %s</em></p>\n</body>\n</html>\n", r.GeneratedAt.Format(time.RFC3339))
+	return sb.String()
+}
+
+func writeHTMLOverview(sb *strings.Builder, r *Report) {
+	if r.FeatureInput != "" {
+		fmt.Fprintf(sb, "<blockquote>%s</blockquote>\n", esc(r.FeatureInput))
+	}
+	sb.WriteString("<ul>\n")
+	if r.SpecStatus != "" {
+		fmt.Fprintf(sb, "<li><strong>Status:</strong> %s</li>\n", esc(r.SpecStatus))
+	}
+	if len(r.Stories) > 0 {
+		fmt.Fprintf(sb, "<li><strong>User stories delivered:</strong> %d/%d</li>\n", r.DeliveredStories(), len(r.Stories))
+	}
+	if r.Stats != nil {
+		fmt.Fprintf(sb, "<li><strong>Tasks completed:</strong> %d/%d (%.0f%%)</li>\n",
+			r.Stats.CompletedTasks, r.Stats.TotalTasks, r.Stats.CompletionPercentage())
+	}
+	if r.Forecast != nil {
+		fmt.Fprintf(sb, "<li><strong>Forecast:</strong> %s</li>\n", esc(r.Forecast.String()))
+	}
+	if total := totalCommandDuration(r); total > 0 {
+		fmt.Fprintf(sb, "<li><strong>Total agent time:</strong> %s</li>\n", formatDuration(total))
+	}
+	sb.WriteString("</ul>\n")
+}
+
+func writeHTMLStories(sb *strings.Builder, r *Report) {
+	if len(r.Stories) == 0 {
+		return
+	}
+	sb.WriteString("<h2>User Stories</h2>\n<table>\n<tr><th>Story</th><th>Priority</th><th>Tasks</th><th>Delivered</th></tr>\n")
+	for _, s := range r.Stories {
+		fmt.Fprintf(sb, "<tr><td>%s %s</td><td>%s</td><td>%d/%d</td><td>%s</td></tr>\n",
+			esc(s.ID), esc(s.Title), esc(s.Priority), s.CompletedTasks, s.TotalTasks, checkmark(s.Delivered))
+	}
+	sb.WriteString("</table>\n")
+}
+
+func writeHTMLTasks(sb *strings.Builder, r *Report) {
+	if len(r.Tasks) == 0 {
+		return
+	}
+	sb.WriteString("<h2>Tasks</h2>\n<table>\n<tr><th>Task</th><th>Phase</th><th>Status</th><th>Duration</th><th>Estimate</th></tr>\n")
+	for _, t := range r.Tasks {
+		status := t.Status
+		if t.Manual {
+			status += " (manual)"
+		}
+		fmt.Fprintf(sb, "<tr><td>%s %s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+			esc(t.ID), esc(t.Title), esc(t.Phase), esc(status), formatDuration(t.Duration), formatDuration(t.Estimate))
+	}
+	sb.WriteString("</table>\n")
+}
+
+func writeHTMLFiles(sb *strings.Builder, r *Report) {
+	if len(r.FilesChanged) == 0 && r.FilesError == "" {
+		return
+	}
+	fmt.Fprintf(sb, "<h2>Files Changed (%d)</h2>\n", len(r.FilesChanged))
+	if r.FilesError != "" {
+		fmt.Fprintf(sb, "<p><em>Could not determine changed files: %s</em></p>\n", esc(r.FilesError))
+		return
+	}
+	sb.WriteString("<ul>\n")
+	for _, f := range r.FilesChanged {
+		fmt.Fprintf(sb, "<li><code>%s</code></li>\n", esc(f))
+	}
+	sb.WriteString("</ul>\n")
+}
+
+func writeHTMLTaskFiles(sb *strings.Builder, r *Report) {
+	if !hasTaskFiles(r) {
+		return
+	}
+	sb.WriteString("<h2>Files Changed by Task</h2>\n<ul>\n")
+	for _, t := range r.Tasks {
+		if len(t.Files) == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "<li><strong>%s</strong> %s<ul>\n", esc(t.ID), esc(t.Title))
+		for _, f := range t.Files {
+			fmt.Fprintf(sb, "<li><code>%s</code></li>\n", esc(f))
+		}
+		sb.WriteString("</ul></li>\n")
+	}
+	sb.WriteString("</ul>\n")
+}
+
+func writeHTMLValidation(sb *strings.Builder, r *Report) {
+	sb.WriteString("<h2>Validation</h2>\n<table>\n<tr><th>Artifact</th><th>Result</th><th>Errors</th><th>Warnings</th></tr>\n")
+	for _, v := range r.Validation {
+		fmt.Fprintf(sb, "<tr><td>%s</td><td>%s</td><td>%d</td><td>%d</td></tr>\n",
+			esc(v.Artifact), validationLabel(v), v.Errors, v.Warnings)
+	}
+	sb.WriteString("</table>\n")
+}
+
+func writeHTMLRetries(sb *strings.Builder, r *Report) {
+	if len(r.Retries) == 0 {
+		return
+	}
+	sb.WriteString("<h2>Notable Retries</h2>\n<ul>\n")
+	for _, rt := range r.Retries {
+		fmt.Fprintf(sb, "<li><strong>%s</strong>: %d retr%s (last %s)</li>\n",
+			esc(rt.Stage), rt.Count, pluralY(rt.Count), rt.LastAttempt.Format("2006-01-02 15:04"))
+	}
+	sb.WriteString("</ul>\n")
+}
+
+// totalCommandDuration sums the recorded durations of the spec's commands.
+func totalCommandDuration(r *Report) time.Duration {
+	var total time.Duration
+	for _, entry := range r.Commands {
+		if d, err := time.ParseDuration(entry.Duration); err == nil {
+			total += d
+		}
+	}
+	return total
+}
+
+// hasTaskFiles reports whether any task recorded the files it changed.
+func hasTaskFiles(r *Report) bool {
+	for _, t := range r.Tasks {
+		if len(t.Files) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// formatDuration renders a duration rounded to seconds, or "—" when unknown.
+func formatDuration(d time.Duration) string {
+	if d <= 0 {
+		return "—"
+	}
+	return d.Round(time.Second).String()
+}
+
+func validationLabel(v ValidationOutcome) string {
+	switch {
+	case !v.Present:
+		return "missing"
+	case v.Valid:
+		return "✓ valid"
+	default:
+		return "✗ invalid"
+	}
+}
+
+func checkmark(ok bool) string {
+	if ok {
+		return "✓"
+	}
+	return "—"
+}
+
+func pluralY(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+// mdCell escapes pipe characters so text is safe inside a Markdown table cell.
+func mdCell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func esc(s string) string {
+	return html.EscapeString(s)
+}
@@ -0,0 +1,296 @@
+// Package report generates human-readable completion reports for a spec.
+// A report summarizes user stories delivered, task completion and durations,
+// files changed (from git), artifact validation outcomes, and notable retries,
+// and renders them as Markdown or HTML for PRs and stakeholders.
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/history"
+	"github.com/ariel-frischer/autospec/internal/retry"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	autospecyaml "github.com/ariel-frischer/autospec/internal/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+// Report is the data model rendered into Markdown or HTML.
+type Report struct {
+	SpecName     string
+	FeatureInput string
+	SpecStatus   string
+	GeneratedAt  time.Time
+	Stories      []StoryReport
+	Tasks        []TaskReport
+	Stats        *validation.TaskStats
+	Forecast     *validation.ForecastRollup // nil until 'autospec forecast' has run
+	FilesChanged []string
+	FilesError   string // Set when changed files could not be determined
+	Validation   []ValidationOutcome
+	Retries      []RetryReport
+	Commands     []history.HistoryEntry
+}
+
+// StoryReport describes delivery status of a single user story.
+type StoryReport struct {
+	ID             string
+	Title          string
+	Priority       string
+	TotalTasks     int
+	CompletedTasks int
+	Delivered      bool
+}
+
+// TaskReport describes a single task and how long it took, when known.
+type TaskReport struct {
+	ID       string
+	Title    string
+	Status   string
+	Phase    string
+	StoryID  string        // User story the task belongs to, if any
+	Duration time.Duration // Zero when neither recorded nor derivable from status_history
+	Estimate time.Duration // estimated_duration; zero when unset
+	Manual   bool
+	Files    []string // changed_files recorded by implement --tasks
+}
+
+// ValidationOutcome is the validation result of one artifact.
+type ValidationOutcome struct {
+	Artifact string
+	Present  bool
+	Valid    bool
+	Errors   int
+	Warnings int
+}
+
+// RetryReport records a stage that needed retries.
+type RetryReport struct {
+	Stage       string
+	Count       int
+	LastAttempt time.Time
+}
+
+// Options configures report generation.
+type Options struct {
+	SpecDir  string // Path to the spec directory (e.g., specs/001-feature)
+	StateDir string // autospec state directory (history, retry state)
+	// ChangedFiles returns files changed for the spec; nil skips the section.
+	ChangedFiles func() ([]string, error)
+	Now          func() time.Time
+}
+
+// Build assembles a Report from the spec artifacts, state directory, and git.
+func Build(opts Options) (*Report, error) {
+	if _, err := os.Stat(opts.SpecDir); err != nil {
+		return nil, fmt.Errorf("reading spec directory: %w", err)
+	}
+
+	now := time.Now
+	if opts.Now != nil {
+		now = opts.Now
+	}
+	r := &Report{
+		SpecName:    filepath.Base(opts.SpecDir),
+		GeneratedAt: now(),
+	}
+
+	tasks := r.loadTasks(opts.SpecDir)
+	r.loadSpec(opts.SpecDir, tasks)
+	r.Validation = collectValidation(opts.SpecDir)
+	r.Forecast, _ = validation.GetForecastRollup(opts.SpecDir)
+	r.loadState(opts.StateDir)
+	r.loadChangedFiles(opts.ChangedFiles)
+	return r, nil
+}
+
+// loadTasks populates task reports and stats; returns parsed tasks for story mapping.
+func (r *Report) loadTasks(specDir string) []validation.TaskItem {
+	tasksPath := validation.GetTasksFilePath(specDir)
+	parsed, err := validation.ParseTasksYAML(tasksPath)
+	if err != nil {
+		return nil
+	}
+	if stats, err := validation.GetTaskStats(tasksPath); err == nil {
+		r.Stats = stats
+	}
+
+	var all []validation.TaskItem
+	for _, phase := range parsed.Phases {
+		for _, task := range phase.Tasks {
+			all = append(all, task)
+			r.Tasks = append(r.Tasks, TaskReport{
+				ID:       task.ID,
+				Title:    task.Title,
+				Status:   task.Status,
+				Phase:    fmt.Sprintf("%d. %s", phase.Number, phase.Title),
+				StoryID:  task.StoryID,
+				Duration: TaskDuration(task),
+				Estimate: task.Estimate(),
+				Manual:   task.IsManualOverride(),
+				Files:    task.ChangedFiles,
+			})
+		}
+	}
+	return all
+}
+
+// loadSpec populates feature info and user story delivery status.
+func (r *Report) loadSpec(specDir string, tasks []validation.TaskItem) {
+	data, err := os.ReadFile(filepath.Join(specDir, "spec.yaml"))
+	if err != nil {
+		return
+	}
+	var spec autospecyaml.SpecArtifact
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return
+	}
+
+	r.FeatureInput = spec.Feature.Input
+	r.SpecStatus = spec.Feature.Status
+	for _, story := range spec.UserStories {
+		r.Stories = append(r.Stories, storyReport(story, tasks))
+	}
+}
+
+// storyReport computes delivery status for a story from tasks referencing it.
+// A story is delivered when it has at least one task and all of them are completed.
+func storyReport(story autospecyaml.UserStory, tasks []validation.TaskItem) StoryReport {
+	sr := StoryReport{ID: story.ID, Title: story.Title, Priority: story.Priority}
+	for _, task := range tasks {
+		if task.StoryID != story.ID {
+			continue
+		}
+		sr.TotalTasks++
+		if isCompleted(task.Status) {
+			sr.CompletedTasks++
+		}
+	}
+	sr.Delivered = sr.TotalTasks > 0 && sr.CompletedTasks == sr.TotalTasks
+	return sr
+}
+
+// collectValidation validates the core artifacts present in specDir.
+func collectValidation(specDir string) []ValidationOutcome {
+	artifacts := []struct {
+		file string
+		typ  validation.ArtifactType
+	}{
+		{"spec.yaml", validation.ArtifactTypeSpec},
+		{"plan.yaml", validation.ArtifactTypePlan},
+		{"tasks.yaml", validation.ArtifactTypeTasks},
+	}
+
+	outcomes := make([]ValidationOutcome, 0, len(artifacts))
+	for _, a := range artifacts {
+		outcome := ValidationOutcome{Artifact: a.file}
+		path := filepath.Join(specDir, a.file)
+		if _, err := os.Stat(path); err == nil {
+			outcome.Present = true
+			validator, _ := validation.NewArtifactValidator(a.typ)
+			result := validator.Validate(path)
+			outcome.Valid = result.Valid
+			outcome.Errors = len(result.Errors)
+			outcome.Warnings = len(result.Warnings)
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	// forecast.yaml is optional, so it is only listed once written
+	path := validation.GetForecastFilePath(specDir)
+	if _, err := os.Stat(path); err == nil {
+		result := (&validation.ForecastValidator{}).Validate(path)
+		outcomes = append(outcomes, ValidationOutcome{
+			Artifact: "forecast.yaml",
+			Present:  true,
+			Valid:    result.Valid,
+			Errors:   len(result.Errors),
+			Warnings: len(result.Warnings),
+		})
+	}
+	return outcomes
+}
+
+// loadState populates retries and command history for the spec.
+func (r *Report) loadState(stateDir string) {
+	if stateDir == "" {
+		return
+	}
+	if states, err := retry.ListRetryStates(stateDir, r.SpecName); err == nil {
+		for _, s := range states {
+			if s.Count > 0 {
+				r.Retries = append(r.Retries, RetryReport{Stage: s.Phase, Count: s.Count, LastAttempt: s.LastAttempt})
+			}
+		}
+	}
+	if hist, err := history.LoadHistory(stateDir); err == nil {
+		for _, entry := range hist.Entries {
+			if entry.Spec == r.SpecName || strings.HasSuffix(entry.Spec, "/"+r.SpecName) {
+				r.Commands = append(r.Commands, entry)
+			}
+		}
+	}
+}
+
+// loadChangedFiles populates the files changed section.
+func (r *Report) loadChangedFiles(changedFiles func() ([]string, error)) {
+	if changedFiles == nil {
+		return
+	}
+	files, err := changedFiles()
+	if err != nil {
+		r.FilesError = err.Error()
+		return
+	}
+	r.FilesChanged = files
+}
+
+// TaskDuration returns how long a task took: the actual_duration recorded by
+// implement --tasks when present, otherwise the span in its status_history
+// from the first transition to InProgress to the last transition to Completed.
+// Returns zero when neither is available.
+func TaskDuration(task validation.TaskItem) time.Duration {
+	if actual := task.Actual(); actual > 0 {
+		return actual
+	}
+	var started, finished time.Time
+	for _, change := range task.StatusHistory {
+		at, err := time.Parse(time.RFC3339, change.At)
+		if err != nil {
+			continue
+		}
+		if change.To == "InProgress" && started.IsZero() {
+			started = at
+		}
+		if change.To == "Completed" {
+			finished = at
+		}
+	}
+	if started.IsZero() || finished.IsZero() || finished.Before(started) {
+		return 0
+	}
+	return finished.Sub(started)
+}
+
+// isCompleted reports whether a status counts as completed.
+func isCompleted(status string) bool {
+	switch strings.ToLower(status) {
+	case "completed", "done", "complete":
+		return true
+	}
+	return false
+}
+
+// DeliveredStories returns the number of delivered user stories.
+func (r *Report) DeliveredStories() int {
+	count := 0
+	for _, s := range r.Stories {
+		if s.Delivered {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,254 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reportSpecYAML = `feature:
+  branch: "001-login"
+  status: "Completed"
+  input: "Add login | logout"
+user_stories:
+  - id: "US-001"
+    title: "Log in"
+    priority: "P1"
+  - id: "US-002"
+    title: "Log out"
+    priority: "P2"
+`
+
+const reportTasksYAML = `phases:
+  - number: 1
+    title: "Core"
+    tasks:
+      - id: "T001"
+        title: "Login handler"
+        status: "Completed"
+        type: "implementation"
+        story_id: "US-001"
+        status_history:
+          - from: "Pending"
+            to: "InProgress"
+            at: "2025-01-01T10:00:00Z"
+            manual: true
+            reason: "start"
+          - from: "InProgress"
+            to: "Completed"
+            at: "2025-01-01T10:30:00Z"
+            manual: true
+            reason: "done"
+        changed_files:
+          - "internal/auth/login.go"
+      - id: "T002"
+        title: "Logout handler"
+        status: "Pending"
+        type: "implementation"
+        story_id: "US-002"
+`
+
+func writeReportFixture(t *testing.T) string {
+	t.Helper()
+	specDir := filepath.Join(t.TempDir(), "001-login")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(reportSpecYAML), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "tasks.yaml"), []byte(reportTasksYAML), 0o644))
+	return specDir
+}
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeReportFixture(t)
+	fixed := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	r, err := Build(Options{
+		SpecDir:      specDir,
+		ChangedFiles: func() ([]string, error) { return []string{"auth/login.go"}, nil },
+		Now:          func() time.Time { return fixed },
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "001-login", r.SpecName)
+	assert.Equal(t, "Completed", r.SpecStatus)
+	assert.Equal(t, fixed, r.GeneratedAt)
+	require.Len(t, r.Stories, 2)
+	assert.True(t, r.Stories[0].Delivered)
+	assert.False(t, r.Stories[1].Delivered)
+	assert.Equal(t, 1, r.DeliveredStories())
+	require.Len(t, r.Tasks, 2)
+	assert.Equal(t, 30*time.Minute, r.Tasks[0].Duration)
+	assert.True(t, r.Tasks[0].Manual)
+	assert.Equal(t, []string{"auth/login.go"}, r.FilesChanged)
+	require.Len(t, r.Validation, 3)
+	assert.False(t, r.Validation[1].Present, "plan.yaml is missing")
+}
+
+func TestBuild_MissingSpecDir(t *testing.T) {
+	t.Parallel()
+
+	_, err := Build(Options{SpecDir: filepath.Join(t.TempDir(), "nope")})
+	assert.Error(t, err)
+}
+
+func TestBuild_ChangedFilesError(t *testing.T) {
+	t.Parallel()
+
+	r, err := Build(Options{
+		SpecDir:      writeReportFixture(t),
+		ChangedFiles: func() ([]string, error) { return nil, errors.New("no merge-base") },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "no merge-base", r.FilesError)
+	assert.Contains(t, RenderMarkdown(r), "Could not determine changed files: no merge-base")
+}
+
+func TestBuild_Forecast(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeReportFixture(t)
+	r, err := Build(Options{SpecDir: specDir})
+	require.NoError(t, err)
+	assert.Nil(t, r.Forecast, "no forecast.yaml yet")
+	assert.NotContains(t, RenderMarkdown(r), "Forecast")
+
+	forecast := "forecast:\n  branch: \"001-login\"\n  created: \"2025-01-01\"\n" +
+		"summary:\n  total_effort_hours: 5\n  overall_risk: medium\n" +
+		"estimates:\n  - {task_id: T001, effort_hours: 2, risk: low}\n  - {task_id: T002, effort_hours: 3, risk: high}\n" +
+		"sequencing: []\n"
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "forecast.yaml"), []byte(forecast), 0o644))
+
+	r, err = Build(Options{SpecDir: specDir})
+	require.NoError(t, err)
+	require.NotNil(t, r.Forecast)
+	assert.Equal(t, 1, r.Forecast.HighRisk)
+	require.Len(t, r.Validation, 4)
+	assert.Equal(t, ValidationOutcome{Artifact: "forecast.yaml", Present: true, Valid: true}, r.Validation[3])
+	assert.Contains(t, RenderMarkdown(r), "- **Forecast:** 5h, medium risk (1 high-risk task)\n")
+	assert.Contains(t, RenderHTML(r), "<li><strong>Forecast:</strong> 5h, medium risk (1 high-risk task)</li>")
+}
+
+func TestTaskDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		actual  string
+		history []validation.TaskStatusChange
+		want    time.Duration
+	}{
+		"no history": {want: 0},
+		"start and finish": {
+			history: []validation.TaskStatusChange{
+				{To: "InProgress", At: "2025-01-01T10:00:00Z"},
+				{To: "Completed", At: "2025-01-01T11:15:00Z"},
+			},
+			want: 75 * time.Minute,
+		},
+		"never completed": {
+			history: []validation.TaskStatusChange{{To: "InProgress", At: "2025-01-01T10:00:00Z"}},
+			want:    0,
+		},
+		"unparseable timestamp": {
+			history: []validation.TaskStatusChange{
+				{To: "InProgress", At: "yesterday"},
+				{To: "Completed", At: "2025-01-01T11:15:00Z"},
+			},
+			want: 0,
+		},
+		"recorded actual_duration wins": {
+			actual: "42m",
+			history: []validation.TaskStatusChange{
+				{To: "InProgress", At: "2025-01-01T10:00:00Z"},
+				{To: "Completed", At: "2025-01-01T11:15:00Z"},
+			},
+			want: 42 * time.Minute,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, TaskDuration(validation.TaskItem{ActualDuration: tt.actual, StatusHistory: tt.history}))
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	r, err := Build(Options{SpecDir: writeReportFixture(t)})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		format       Format
+		wantContains []string
+	}{
+		"markdown": {
+			format: FormatMarkdown,
+			wantContains: []string{
+				"# Completion Report: 001-login",
+				"**User stories delivered:** 1/2",
+				"| T001 Login handler | 1. Core | Completed (manual) | 30m0s |",
+				"> Add login | logout",
+				"| plan.yaml | missing |",
+				"## Files Changed by Task\n\n- **T001** Login handler\n  - `internal/auth/login.go`\n",
+			},
+		},
+		"html escapes content": {
+			format: FormatHTML,
+			wantContains: []string{
+				"<title>Completion Report: 001-login</title>",
+				"<blockquote>Add login | logout</blockquote>",
+				"<td>T001 Login handler</td>",
+				"<li><code>internal/auth/login.go</code></li>",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var buf bytes.Buffer
+			require.NoError(t, Render(&buf, r, tt.format))
+			for _, want := range tt.wantContains {
+				assert.Contains(t, buf.String(), want)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input   string
+		want    Format
+		wantErr bool
+	}{
+		"markdown": {input: "markdown", want: FormatMarkdown},
+		"md alias": {input: "md", want: FormatMarkdown},
+		"html":     {input: "HTML", want: FormatHTML},
+		"unknown":  {input: "pdf", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseFormat(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
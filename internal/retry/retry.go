@@ -9,7 +9,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 )
 
 // RetryState represents retry tracking for a specific spec and phase combination
@@ -19,6 +23,7 @@ type RetryState struct {
 	Count       int       `json:"count"`
 	LastAttempt time.Time `json:"last_attempt"`
 	MaxRetries  int       `json:"max_retries"`
+	RunID       string    `json:"run_id,omitempty"` // Run that made the last attempt
 }
 
 // RetryStore contains all retry states persisted to disk
@@ -111,16 +116,9 @@ func SaveRetryState(stateDir string, state *RetryState) error {
 		return fmt.Errorf("failed to marshal retry state: %w", err)
 	}
 
-	// Write to temp file
 	retryPath := filepath.Join(stateDir, "retry.json")
-	tmpPath := retryPath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, retryPath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	if err := fsutil.WriteFile(retryPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write retry state: %w", err)
 	}
 
 	return nil
@@ -184,6 +182,27 @@ func ResetRetryCount(stateDir, specName, phase string) error {
 	return SaveRetryState(stateDir, state)
 }
 
+// ListRetryStates returns all retry states recorded for a spec, sorted by phase name.
+// Returns an empty slice if no retry state file exists.
+func ListRetryStates(stateDir, specName string) ([]*RetryState, error) {
+	store, err := loadStore(stateDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*RetryState{}, nil
+		}
+		return nil, fmt.Errorf("loading retry store: %w", err)
+	}
+
+	states := make([]*RetryState, 0)
+	for _, state := range store.Retries {
+		if state != nil && state.SpecName == specName {
+			states = append(states, state)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Phase < states[j].Phase })
+	return states, nil
+}
+
 // loadStore loads the retry store from disk with backward-compatible parsing.
 // Handles migration from legacy format: "phase_states" → "stage_states".
 //
@@ -251,8 +270,7 @@ func LoadStageState(stateDir, specName string) (*StageExecutionState, error) {
 	return store.StageStates[specName], nil
 }
 
-// SaveStageState persists stage state atomically via temp file + rename.
-// Atomic write pattern: write to .tmp file, then rename to final path.
+// SaveStageState persists stage state atomically via fsutil.WriteFile.
 // This prevents partial writes from corrupting state on crash/interrupt.
 // Merges with existing store to preserve other specs' states.
 func SaveStageState(stateDir string, state *StageExecutionState) error {
@@ -285,16 +303,9 @@ func SaveStageState(stateDir string, state *StageExecutionState) error {
 		return fmt.Errorf("failed to marshal stage state: %w", err)
 	}
 
-	// Write to temp file
 	retryPath := filepath.Join(stateDir, "retry.json")
-	tmpPath := retryPath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, retryPath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	if err := fsutil.WriteFile(retryPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write retry state: %w", err)
 	}
 
 	return nil
@@ -358,16 +369,9 @@ func ResetStageState(stateDir, specName string) error {
 		return fmt.Errorf("failed to marshal stage state: %w", err)
 	}
 
-	// Write to temp file
 	retryPath := filepath.Join(stateDir, "retry.json")
-	tmpPath := retryPath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, retryPath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	if err := fsutil.WriteFile(retryPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write retry state: %w", err)
 	}
 
 	return nil
@@ -429,16 +433,9 @@ func SaveTaskState(stateDir string, state *TaskExecutionState) error {
 		return fmt.Errorf("failed to marshal task state: %w", err)
 	}
 
-	// Write to temp file
 	retryPath := filepath.Join(stateDir, "retry.json")
-	tmpPath := retryPath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, retryPath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	if err := fsutil.WriteFile(retryPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write retry state: %w", err)
 	}
 
 	return nil
@@ -501,16 +498,9 @@ func ResetTaskState(stateDir, specName string) error {
 		return fmt.Errorf("failed to marshal task state: %w", err)
 	}
 
-	// Write to temp file
 	retryPath := filepath.Join(stateDir, "retry.json")
-	tmpPath := retryPath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, retryPath); err != nil {
-		return fmt.Errorf("failed to rename temp file: %w", err)
+	if err := fsutil.WriteFile(retryPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write retry state: %w", err)
 	}
 
 	return nil
@@ -539,6 +529,11 @@ func (e *RetryExhaustedError) Error() string {
 		e.SpecName, e.Phase, e.Count, e.MaxRetries)
 }
 
+// Is classifies the error as apperrors.ErrRetryExhausted.
+func (e *RetryExhaustedError) Is(target error) bool {
+	return target == apperrors.ErrRetryExhausted
+}
+
 // ExitCode returns the exit code for retry exhausted (2)
 func (e *RetryExhaustedError) ExitCode() int {
 	return 2
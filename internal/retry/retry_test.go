@@ -6,11 +6,13 @@ package retry
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -398,9 +400,9 @@ func TestAtomicWrite(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify temp file doesn't exist
-	tmpPath := filepath.Join(stateDir, "retry.json.tmp")
-	_, err = os.Stat(tmpPath)
-	assert.True(t, os.IsNotExist(err), "temp file should not exist after atomic write")
+	tmpFiles, err := filepath.Glob(filepath.Join(stateDir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tmpFiles, "temp file should not exist after atomic write")
 
 	// Verify final file exists
 	finalPath := filepath.Join(stateDir, "retry.json")
@@ -441,6 +443,7 @@ func TestRetryExhaustedError(t *testing.T) {
 	assert.Equal(t, 2, err.ExitCode())
 	assert.Contains(t, err.Error(), "001:specify")
 	assert.Contains(t, err.Error(), "3/3")
+	assert.ErrorIs(t, fmt.Errorf("plan stage: %w", err), apperrors.ErrRetryExhausted)
 }
 
 // Stage Execution State Tests
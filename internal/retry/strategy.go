@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Retry prompt strategies. Each retry after a validation failure uses one
+// strategy of the stage's ladder, escalating from a cheap hint to a focused
+// repair prompt.
+const (
+	// StrategyErrors reruns the stage command with the validation errors appended.
+	StrategyErrors = "errors"
+	// StrategySchema also appends the full schema of the stage's artifact.
+	StrategySchema = "schema"
+	// StrategyFixOnly replaces the stage command with a prompt that asks the
+	// agent to repair only the failing sections of the existing artifact.
+	StrategyFixOnly = "fix_only"
+)
+
+// Strategies lists the valid retry strategies, from least to most focused.
+var Strategies = []string{StrategyErrors, StrategySchema, StrategyFixOnly}
+
+// DefaultLadder is the strategy ladder of stages without a configured one.
+var DefaultLadder = []string{StrategyErrors, StrategySchema, StrategyFixOnly}
+
+// StrategyFor returns the strategy of a retry (1-based) on ladder. Retries
+// beyond the end of the ladder repeat its last strategy, and an empty ladder
+// is DefaultLadder.
+func StrategyFor(ladder []string, retry int) string {
+	if len(ladder) == 0 {
+		ladder = DefaultLadder
+	}
+	switch {
+	case retry < 1:
+		return ladder[0]
+	case retry > len(ladder):
+		return ladder[len(ladder)-1]
+	default:
+		return ladder[retry-1]
+	}
+}
+
+// ValidateLadder checks that a configured ladder is non-empty and names only
+// known strategies.
+func ValidateLadder(ladder []string) error {
+	if len(ladder) == 0 {
+		return fmt.Errorf("must list at least one strategy")
+	}
+	for _, strategy := range ladder {
+		if !isStrategy(strategy) {
+			return fmt.Errorf("unknown strategy %q (valid: %s)", strategy, strings.Join(Strategies, ", "))
+		}
+	}
+	return nil
+}
+
+func isStrategy(name string) bool {
+	for _, s := range Strategies {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package retry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyFor(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ladder []string
+		retry  int
+		want   string
+	}{
+		"default first retry":        {retry: 1, want: StrategyErrors},
+		"default second retry":       {retry: 2, want: StrategySchema},
+		"default third retry":        {retry: 3, want: StrategyFixOnly},
+		"beyond ladder repeats last": {retry: 7, want: StrategyFixOnly},
+		"configured ladder":          {ladder: []string{StrategyFixOnly, StrategyErrors}, retry: 2, want: StrategyErrors},
+		"single step ladder":         {ladder: []string{StrategySchema}, retry: 4, want: StrategySchema},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, StrategyFor(tt.ladder, tt.retry))
+		})
+	}
+}
+
+func TestValidateLadder(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, ValidateLadder([]string{StrategyErrors, StrategyFixOnly}))
+	assert.ErrorContains(t, ValidateLadder(nil), "at least one strategy")
+	assert.ErrorContains(t, ValidateLadder([]string{StrategyErrors, "rewrite"}), `unknown strategy "rewrite"`)
+}
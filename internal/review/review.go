@@ -0,0 +1,249 @@
+// Package review records reviewer decisions on a spec's spec.yaml sections in
+// review.yaml, and gates the plan stage on the approval of required reviewers.
+package review
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the review file name inside a spec directory.
+const FileName = "review.yaml"
+
+// Section decisions.
+const (
+	DecisionAccept  = "accept"  // section approved as written
+	DecisionReject  = "reject"  // section must change before plan
+	DecisionComment = "comment" // remark that does not block approval
+)
+
+// Config configures review gating (config key: review).
+type Config struct {
+	// Enabled blocks plan until every required reviewer has approved the
+	// current spec.yaml (default: false)
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// RequiredReviewers must each approve. Empty means any one approval.
+	RequiredReviewers []string `koanf:"required_reviewers" yaml:"required_reviewers" json:"required_reviewers"`
+}
+
+// File is the content of review.yaml.
+type File struct {
+	Reviews []Review `yaml:"reviews"`
+}
+
+// Review is one reviewer's pass over spec.yaml.
+type Review struct {
+	Reviewer   string     `yaml:"reviewer"`
+	SpecHash   string     `yaml:"spec_hash"` // content hash of the reviewed spec.yaml
+	ReviewedAt time.Time  `yaml:"reviewed_at"`
+	Sections   []Decision `yaml:"sections"`
+}
+
+// Decision is a reviewer's verdict on one spec.yaml section.
+type Decision struct {
+	Section  string `yaml:"section"`
+	Decision string `yaml:"decision"`
+	Comment  string `yaml:"comment,omitempty"`
+}
+
+// Section is a top-level spec.yaml section, rendered as YAML.
+type Section struct {
+	Name string
+	Body string
+}
+
+// Path returns the review.yaml path for specDir.
+func Path(specDir string) string {
+	return filepath.Join(specDir, FileName)
+}
+
+// Load reads specDir/review.yaml. A missing file yields an empty File.
+func Load(specDir string) (*File, error) {
+	data, err := os.ReadFile(Path(specDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", FileName, err)
+	}
+	var f File
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", FileName, err)
+	}
+	return &f, nil
+}
+
+// Save writes f to specDir/review.yaml.
+func Save(specDir string, f *File) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("serializing %s: %w", FileName, err)
+	}
+	if err := fsutil.WriteFile(Path(specDir), data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", FileName, err)
+	}
+	return nil
+}
+
+// Record replaces the reviewer's previous review with r.
+func (f *File) Record(r Review) {
+	f.Reviews = slices.DeleteFunc(f.Reviews, func(old Review) bool {
+		return strings.EqualFold(old.Reviewer, r.Reviewer)
+	})
+	f.Reviews = append(f.Reviews, r)
+}
+
+// Find returns the reviewer's review, or nil.
+func (f *File) Find(reviewer string) *Review {
+	for i := range f.Reviews {
+		if strings.EqualFold(f.Reviews[i].Reviewer, reviewer) {
+			return &f.Reviews[i]
+		}
+	}
+	return nil
+}
+
+// Approves reports whether r approves the spec.yaml with the given content
+// hash and sections: r was made on that content, decides every section, and
+// rejects none.
+func (r Review) Approves(specHash string, sections []string) bool {
+	if r.SpecHash != specHash {
+		return false
+	}
+	decided := make(map[string]bool, len(r.Sections))
+	for _, d := range r.Sections {
+		if d.Decision == DecisionReject {
+			return false
+		}
+		decided[d.Section] = true
+	}
+	for _, name := range sections {
+		if !decided[name] {
+			return false
+		}
+	}
+	return len(sections) > 0
+}
+
+// Sections returns the top-level sections of spec.yaml in file order,
+// leaving out _meta.
+func Sections(specPath string) ([]Section, error) {
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading spec.yaml: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing spec.yaml: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("spec.yaml is not a mapping")
+	}
+
+	root := doc.Content[0]
+	var sections []Section
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		name := root.Content[i].Value
+		if name == "_meta" {
+			continue
+		}
+		body, err := yaml.Marshal(root.Content[i+1])
+		if err != nil {
+			return nil, fmt.Errorf("rendering section %s: %w", name, err)
+		}
+		sections = append(sections, Section{Name: name, Body: string(body)})
+	}
+	return sections, nil
+}
+
+// SectionNames returns the names of sections.
+func SectionNames(sections []Section) []string {
+	names := make([]string, len(sections))
+	for i, s := range sections {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// SpecHash returns the content hash of specDir's spec.yaml recorded in reviews.
+func SpecHash(specDir string) (string, error) {
+	return validation.ArtifactContentHash(filepath.Join(specDir, "spec.yaml"), validation.ArtifactTypeSpec)
+}
+
+// GateError reports the reviewers whose approval of the current spec.yaml is missing.
+type GateError struct {
+	SpecDir string
+	Missing []string // required reviewers without an approval; empty when any one approval is enough
+}
+
+func (e *GateError) Error() string {
+	var sb strings.Builder
+	if len(e.Missing) == 0 {
+		fmt.Fprintf(&sb, "review gate: %s has no approved review of the current spec.yaml", e.SpecDir)
+	} else {
+		fmt.Fprintf(&sb, "review gate: %d reviewer(s) have not approved the current spec.yaml in %s: %s",
+			len(e.Missing), e.SpecDir, strings.Join(e.Missing, ", "))
+	}
+	sb.WriteString("\n\nEach reviewer runs 'autospec review' and accepts or comments on every section")
+	return sb.String()
+}
+
+// Pending returns the required reviewers who have not approved the current
+// spec.yaml in specDir. With no required reviewers, it returns nil once
+// anyone has approved and ok is false otherwise.
+func Pending(specDir string, required []string) (missing []string, ok bool, err error) {
+	hash, err := SpecHash(specDir)
+	if err != nil {
+		return nil, false, err
+	}
+	sections, err := Sections(filepath.Join(specDir, "spec.yaml"))
+	if err != nil {
+		return nil, false, err
+	}
+	names := SectionNames(sections)
+	f, err := Load(specDir)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(required) == 0 {
+		for _, r := range f.Reviews {
+			if r.Approves(hash, names) {
+				return nil, true, nil
+			}
+		}
+		return nil, false, nil
+	}
+	for _, reviewer := range required {
+		if r := f.Find(reviewer); r == nil || !r.Approves(hash, names) {
+			missing = append(missing, reviewer)
+		}
+	}
+	return missing, len(missing) == 0, nil
+}
+
+// CheckGate returns a *GateError unless cfg's required reviewers have all
+// approved the current spec.yaml in specDir. Returns nil when gating is off.
+func CheckGate(specDir string, cfg Config) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	missing, ok, err := Pending(specDir, cfg.RequiredReviewers)
+	if err != nil {
+		return fmt.Errorf("checking reviews: %w", err)
+	}
+	if !ok {
+		return &GateError{SpecDir: specDir, Missing: missing}
+	}
+	return nil
+}
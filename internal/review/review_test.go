@@ -0,0 +1,179 @@
+package review
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSpecDir returns a spec directory holding the valid spec.yaml fixture.
+func newSpecDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("..", "validation", "testdata", "spec", "valid.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.yaml"), data, 0o644))
+	return dir
+}
+
+// approval returns a review by reviewer that accepts every section of specDir's spec.yaml.
+func approval(t *testing.T, specDir, reviewer string) Review {
+	t.Helper()
+	hash, err := SpecHash(specDir)
+	require.NoError(t, err)
+	sections, err := Sections(filepath.Join(specDir, "spec.yaml"))
+	require.NoError(t, err)
+	r := Review{Reviewer: reviewer, SpecHash: hash, ReviewedAt: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)}
+	for _, s := range sections {
+		r.Sections = append(r.Sections, Decision{Section: s.Name, Decision: DecisionAccept})
+	}
+	return r
+}
+
+func TestSections(t *testing.T) {
+	t.Parallel()
+	dir := newSpecDir(t)
+	path := filepath.Join(dir, "spec.yaml")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, append(data, []byte("_meta:\n  version: \"1.0.0\"\n")...), 0o644))
+
+	sections, err := Sections(path)
+	require.NoError(t, err)
+	names := SectionNames(sections)
+	require.NotEmpty(t, names)
+	assert.Equal(t, []string{"feature", "user_stories", "requirements"}, names[:3], "file order")
+	assert.NotContains(t, names, "_meta")
+	assert.Contains(t, sections[0].Body, "branch: \"001-example-feature\"")
+}
+
+func TestReview_Approves(t *testing.T) {
+	t.Parallel()
+	sections := []string{"feature", "requirements"}
+
+	tests := map[string]struct {
+		review Review
+		want   bool
+	}{
+		"all accepted": {
+			review: Review{SpecHash: "h1", Sections: []Decision{{Section: "feature", Decision: DecisionAccept}, {Section: "requirements", Decision: DecisionAccept}}},
+			want:   true,
+		},
+		"comment does not block": {
+			review: Review{SpecHash: "h1", Sections: []Decision{{Section: "feature", Decision: DecisionAccept}, {Section: "requirements", Decision: DecisionComment, Comment: "tighten FR-002"}}},
+			want:   true,
+		},
+		"rejected section": {
+			review: Review{SpecHash: "h1", Sections: []Decision{{Section: "feature", Decision: DecisionAccept}, {Section: "requirements", Decision: DecisionReject, Comment: "missing rate limits"}}},
+		},
+		"skipped section": {
+			review: Review{SpecHash: "h1", Sections: []Decision{{Section: "feature", Decision: DecisionAccept}}},
+		},
+		"outdated spec": {
+			review: Review{SpecHash: "h0", Sections: []Decision{{Section: "feature", Decision: DecisionAccept}, {Section: "requirements", Decision: DecisionAccept}}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.review.Approves("h1", sections))
+		})
+	}
+}
+
+func TestFile_RecordReplacesReviewer(t *testing.T) {
+	t.Parallel()
+	dir := newSpecDir(t)
+
+	f, err := Load(dir)
+	require.NoError(t, err)
+	assert.Empty(t, f.Reviews, "missing review.yaml")
+
+	f.Record(Review{Reviewer: "alice", SpecHash: "old"})
+	f.Record(Review{Reviewer: "bob", SpecHash: "h1"})
+	f.Record(Review{Reviewer: "Alice", SpecHash: "h1"})
+	require.NoError(t, Save(dir, f))
+
+	loaded, err := Load(dir)
+	require.NoError(t, err)
+	require.Len(t, loaded.Reviews, 2)
+	assert.Equal(t, "h1", loaded.Find("alice").SpecHash)
+	assert.Nil(t, loaded.Find("carol"))
+}
+
+func TestCheckGate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		config      Config
+		reviews     []string // reviewers with an approval of the current spec
+		editSpec    bool     // change spec.yaml after the reviews
+		wantMissing []string
+		wantBlocked bool
+	}{
+		"disabled": {
+			config: Config{Enabled: false, RequiredReviewers: []string{"alice"}},
+		},
+		"all required approved": {
+			config:  Config{Enabled: true, RequiredReviewers: []string{"alice", "bob"}},
+			reviews: []string{"alice", "bob"},
+		},
+		"required reviewer missing": {
+			config:      Config{Enabled: true, RequiredReviewers: []string{"alice", "bob"}},
+			reviews:     []string{"alice"},
+			wantMissing: []string{"bob"},
+			wantBlocked: true,
+		},
+		"spec edited after approval": {
+			config:      Config{Enabled: true, RequiredReviewers: []string{"alice"}},
+			reviews:     []string{"alice"},
+			editSpec:    true,
+			wantMissing: []string{"alice"},
+			wantBlocked: true,
+		},
+		"any reviewer": {
+			config:  Config{Enabled: true},
+			reviews: []string{"carol"},
+		},
+		"any reviewer without reviews": {
+			config:      Config{Enabled: true},
+			wantBlocked: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := newSpecDir(t)
+			f := &File{}
+			for _, reviewer := range tt.reviews {
+				f.Record(approval(t, dir, reviewer))
+			}
+			require.NoError(t, Save(dir, f))
+			if tt.editSpec {
+				path := filepath.Join(dir, "spec.yaml")
+				data, err := os.ReadFile(path)
+				require.NoError(t, err)
+				edited := strings.Replace(string(data), "MUST hash passwords before storage", "MUST hash passwords with argon2id", 1)
+				require.NoError(t, os.WriteFile(path, []byte(edited), 0o644))
+			}
+
+			err := CheckGate(dir, tt.config)
+			if !tt.wantBlocked {
+				assert.NoError(t, err)
+				return
+			}
+			var gateErr *GateError
+			require.True(t, errors.As(err, &gateErr), "error = %v", err)
+			assert.Equal(t, tt.wantMissing, gateErr.Missing)
+			assert.Contains(t, err.Error(), "autospec review")
+		})
+	}
+}
@@ -0,0 +1,219 @@
+// Package salvage recovers artifacts that an agent printed instead of
+// writing. A common agent failure is to answer with the finished plan.yaml
+// in a fenced code block and never call its write tool; the stage then fails
+// validation although the artifact exists, in the agent's output.
+//
+// Capture collects the text of an agent session (Claude stream-json
+// responses, or other agents' plain output), and Find picks the fenced YAML
+// block in it that is an artifact of the expected type.
+package salvage
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/ariel-frischer/claude-clean/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// Limits keep capture bounded for long sessions.
+const (
+	maxTextBytes  = 1 << 22 // per session; later output is dropped
+	maxLineBuffer = 1 << 22 // partial-line buffer for stream-json parsing
+)
+
+// Capture records the text of the current agent session. It is an io.Writer
+// for raw agent output. A nil *Capture is valid and records nothing.
+type Capture struct {
+	mu   sync.Mutex
+	text strings.Builder
+	line []byte
+}
+
+// New returns an empty capture.
+func New() *Capture {
+	return &Capture{}
+}
+
+// Reset discards the recorded text; call it when a session starts.
+func (c *Capture) Reset() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.text.Reset()
+	c.line = nil
+}
+
+// Writer returns c as an io.Writer, or nil for a nil capture, so that it can
+// be left out of a tee.
+func (c *Capture) Writer() io.Writer {
+	if c == nil {
+		return nil
+	}
+	return c
+}
+
+// Write records agent output.
+func (c *Capture) Write(p []byte) (int, error) {
+	if c == nil {
+		return len(p), nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.line = append(c.line, p...)
+	for {
+		idx := bytes.IndexByte(c.line, '\n')
+		if idx < 0 {
+			break
+		}
+		c.handleLine(c.line[:idx])
+		c.line = c.line[idx+1:]
+	}
+	if len(c.line) > maxLineBuffer {
+		c.line = nil
+	}
+	return len(p), nil
+}
+
+// Text returns the text recorded since the last Reset, including a final
+// line without a newline.
+func (c *Capture) Text() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.line) > 0 {
+		c.handleLine(c.line)
+		c.line = nil
+	}
+	return c.text.String()
+}
+
+// handleLine records the assistant text of a stream-json message, or the
+// line itself for plain output.
+func (c *Capture) handleLine(line []byte) {
+	var msg parser.StreamMessage
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' || json.Unmarshal(trimmed, &msg) != nil || msg.Type == "" {
+		c.add(string(bytes.TrimRight(line, "\r")) + "\n")
+		return
+	}
+	switch msg.Type {
+	case "assistant":
+		if msg.Message == nil {
+			return
+		}
+		for _, block := range msg.Message.Content {
+			if block.Type == "text" {
+				c.add(block.Text + "\n")
+			}
+		}
+	case "result":
+		c.add(msg.Result + "\n")
+	}
+}
+
+func (c *Capture) add(s string) {
+	if c.text.Len()+len(s) > maxTextBytes {
+		return
+	}
+	c.text.WriteString(s)
+}
+
+// Block is a fenced code block.
+type Block struct {
+	// Lang is the info string after the opening fence, e.g. "yaml".
+	Lang string
+	// Body is the block content with a trailing newline.
+	Body string
+}
+
+// FencedBlocks returns the ``` and ~~~ fenced code blocks in text, in order.
+// An unclosed block at the end is dropped: it is likely cut off.
+func FencedBlocks(text string) []Block {
+	var blocks []Block
+	var open *Block
+	var fence string
+	var body strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(strings.TrimRight(line, "\r"))
+		if open == nil {
+			for _, f := range []string{"```", "~~~"} {
+				if strings.HasPrefix(trimmed, f) {
+					open, fence = &Block{Lang: strings.TrimSpace(strings.TrimLeft(trimmed, f[:1]))}, f
+					body.Reset()
+					break
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+			open.Body = body.String()
+			blocks = append(blocks, *open)
+			open = nil
+			continue
+		}
+		body.WriteString(strings.TrimRight(line, "\r") + "\n")
+	}
+	return blocks
+}
+
+// Find returns the last fenced YAML block in text that is an artifact of
+// artType: a YAML mapping whose _meta.artifact_type is artType or, without
+// one, that has the artifact's header section (the first required field of
+// its schema, e.g. plan: for plan.yaml). Whether the rest is valid is left to
+// validation.
+// Blocks tagged with a language other than yaml or yml are skipped.
+func Find(text string, artType validation.ArtifactType) ([]byte, bool) {
+	schema, err := validation.GetSchema(artType)
+	if err != nil {
+		return nil, false
+	}
+	blocks := FencedBlocks(text)
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if lang := blockLang(blocks[i]); lang != "" && lang != "yaml" && lang != "yml" {
+			continue
+		}
+		if matches(blocks[i].Body, schema) {
+			return []byte(blocks[i].Body), true
+		}
+	}
+	return nil, false
+}
+
+// blockLang returns the language of b in lower case ("" when untagged),
+// ignoring attributes after it such as "yaml title=plan.yaml".
+func blockLang(b Block) string {
+	fields := strings.Fields(b.Lang)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// matches reports whether body is a YAML mapping of schema's artifact type.
+func matches(body string, schema *validation.Schema) bool {
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(body), &doc); err != nil || len(doc) == 0 {
+		return false
+	}
+	if meta, ok := doc["_meta"].(map[string]any); ok {
+		if t, ok := meta["artifact_type"].(string); ok && t != "" {
+			return t == string(schema.Type)
+		}
+	}
+	for _, field := range schema.Fields {
+		if field.Required {
+			_, ok := doc[field.Name]
+			return ok
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package salvage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/stretchr/testify/assert"
+)
+
+const planBlock = "plan:\n  branch: 001-x\n  spec_path: specs/001-x/spec.yaml\nsummary: Add x\n"
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		writes []string
+		want   string
+	}{
+		"plain output": {
+			writes: []string{"Here is the plan:\n```yaml\n", "plan:\n  branch: x\n```"},
+			want:   "Here is the plan:\n```yaml\nplan:\n  branch: x\n```\n",
+		},
+		"stream-json text and result": {
+			writes: []string{
+				`{"type":"assistant","message":{"content":[{"type":"text","text":"Done:\n` + "```yaml" + `\nplan: {}\n` + "```" + `"},{"type":"tool_use","name":"Read","input":{}}]}}` + "\n",
+				`{"type":"result","result":"finished"}` + "\n",
+			},
+			want: "Done:\n```yaml\nplan: {}\n```\nfinished\n",
+		},
+		"crlf": {
+			writes: []string{"a\r\nb\r\n"},
+			want:   "a\nb\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			c := New()
+			for _, w := range tt.writes {
+				n, err := c.Write([]byte(w))
+				assert.NoError(t, err)
+				assert.Equal(t, len(w), n)
+			}
+			assert.Equal(t, tt.want, c.Text())
+
+			c.Reset()
+			assert.Empty(t, c.Text())
+		})
+	}
+}
+
+func TestCapture_Nil(t *testing.T) {
+	t.Parallel()
+
+	var c *Capture
+	c.Reset()
+	n, err := c.Write([]byte("x"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Empty(t, c.Text())
+	assert.Nil(t, c.Writer())
+}
+
+func TestFencedBlocks(t *testing.T) {
+	t.Parallel()
+
+	text := "intro\n```yaml\na: 1\n```\n~~~\nb: 2\n~~~\n  ```go title=x.go\nfunc f() {}\n  ```\n```yaml\nunclosed: true\n"
+	assert.Equal(t, []Block{
+		{Lang: "yaml", Body: "a: 1\n"},
+		{Lang: "", Body: "b: 2\n"},
+		{Lang: "go title=x.go", Body: "func f() {}\n"},
+	}, FencedBlocks(text))
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	fence := func(lang, body string) string { return fmt.Sprintf("```%s\n%s```\n", lang, body) }
+
+	tests := map[string]struct {
+		text    string
+		artType validation.ArtifactType
+		want    string
+	}{
+		"yaml block with required fields": {
+			text:    "Here it is:\n" + fence("yaml", planBlock),
+			artType: validation.ArtifactTypePlan,
+			want:    planBlock,
+		},
+		"untagged block": {
+			text:    fence("", planBlock),
+			artType: validation.ArtifactTypePlan,
+			want:    planBlock,
+		},
+		"last matching block wins": {
+			text:    fence("yaml", planBlock) + fence("yml", planBlock+"notes: second\n"),
+			artType: validation.ArtifactTypePlan,
+			want:    planBlock + "notes: second\n",
+		},
+		"meta artifact type decides": {
+			text:    fence("yaml", "tasks: {}\n_meta:\n  artifact_type: plan\n"),
+			artType: validation.ArtifactTypePlan,
+			want:    "tasks: {}\n_meta:\n  artifact_type: plan\n",
+		},
+		"other artifact type": {
+			text:    fence("yaml", planBlock+"_meta:\n  artifact_type: tasks\n"),
+			artType: validation.ArtifactTypePlan,
+		},
+		"missing header section": {
+			text:    fence("yaml", "summary: only\n"),
+			artType: validation.ArtifactTypePlan,
+		},
+		"other language": {
+			text:    fence("json", planBlock),
+			artType: validation.ArtifactTypePlan,
+		},
+		"not yaml": {
+			text:    fence("yaml", "plan: [\n"),
+			artType: validation.ArtifactTypePlan,
+		},
+		"no blocks": {
+			text:    planBlock,
+			artType: validation.ArtifactTypePlan,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := Find(tt.text, tt.artType)
+			assert.Equal(t, tt.want != "", ok)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
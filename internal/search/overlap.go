@@ -0,0 +1,188 @@
+package search
+
+import (
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/spec"
+)
+
+// DefaultOverlapMinScore is the overlap score, in percent, from which
+// specify warns when overlap_check.min_score is not set.
+const DefaultOverlapMinScore = 40
+
+// OverlapConfig configures the check of new feature descriptions against
+// existing specs (config key: overlap_check).
+type OverlapConfig struct {
+	// Enabled compares the feature description of each 'autospec specify'
+	// with the existing specs before the agent runs (default: false).
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// MinScore is the share, in percent, of the description's weighted terms
+	// an existing spec must contain to be reported.
+	MinScore int `koanf:"min_score" yaml:"min_score" json:"min_score"`
+
+	// Confirm asks whether to create the spec anyway when overlaps are
+	// found on an interactive terminal (default: true).
+	Confirm bool `koanf:"confirm" yaml:"confirm" json:"confirm"`
+}
+
+// Overlap is an existing spec that covers much of a feature description.
+type Overlap struct {
+	Spec  string  `json:"spec"`
+	Score float64 `json:"score"` // 0..1, share of the description's weighted terms found in the spec
+
+	// Item is the story or requirement sharing the most terms, e.g.
+	// "story US-002", with its title.
+	Item      string `json:"item,omitempty"`
+	ItemTitle string `json:"item_title,omitempty"`
+
+	// Shared are the most distinctive terms found in both, at most five.
+	Shared []string `json:"shared"`
+}
+
+// wordPattern matches the words terms are made of.
+var wordPattern = regexp.MustCompile(`[a-z0-9]+`)
+
+// stopWords are left out of terms: common English and the boilerplate every
+// spec contains.
+var stopWords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		about after all also and any are been before being both but can could
+		does each for from had has have how into its may more most must not
+		only other our over same should some such than that the their them
+		then there these they this those through under use used using very
+		was were what when where which while who will with within without
+		would you your able add allow feature features need new per shall
+		so support system user users want given`) {
+		stopWords[w] = true
+	}
+}
+
+// overlapTerms returns the distinct terms of text: lower-case words of three
+// or more characters, without stop words, with a plural "s" removed.
+func overlapTerms(text string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, w := range wordPattern.FindAllString(strings.ToLower(text), -1) {
+		if len(w) > 4 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") {
+			w = strings.TrimSuffix(w, "s")
+		}
+		if len(w) < 3 || stopWords[w] {
+			continue
+		}
+		terms[w] = true
+	}
+	return terms
+}
+
+// specTerms holds the terms of one spec.yaml.
+type specTerms struct {
+	name  string
+	all   map[string]bool
+	items []itemTerms
+}
+
+type itemTerms struct {
+	label string
+	title string
+	terms map[string]bool
+}
+
+// FindOverlaps compares description with the spec.yaml of every spec in
+// specsDir and returns those scoring at least minScore (0..1), best first.
+// Terms are weighted by how few specs contain them, so words shared by the
+// whole project count for little.
+func FindOverlaps(specsDir, description string, minScore float64) ([]Overlap, error) {
+	want := overlapTerms(description)
+	if len(want) == 0 {
+		return nil, nil
+	}
+
+	var specs []specTerms
+	for _, name := range spec.ListSpecNames(specsDir) {
+		items, err := loadItems(filepath.Join(specsDir, name, "spec.yaml"))
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			continue
+		}
+		st := specTerms{name: name, all: make(map[string]bool)}
+		for _, it := range items {
+			terms := make(map[string]bool)
+			for _, f := range it.fields {
+				for t := range overlapTerms(f.text) {
+					terms[t] = true
+					st.all[t] = true
+				}
+			}
+			if it.id != "" {
+				st.items = append(st.items, itemTerms{label: Hit{Kind: it.kind, ID: it.id}.Label(), title: it.title, terms: terms})
+			}
+		}
+		specs = append(specs, st)
+	}
+
+	weight := make(map[string]float64, len(want))
+	total := 0.0
+	for t := range want {
+		df := 0
+		for _, s := range specs {
+			if s.all[t] {
+				df++
+			}
+		}
+		weight[t] = math.Log(1 + float64(len(specs)+1)/float64(df+1))
+		total += weight[t]
+	}
+	score := func(terms map[string]bool) float64 {
+		sum := 0.0
+		for t := range want {
+			if terms[t] {
+				sum += weight[t]
+			}
+		}
+		return sum / total
+	}
+
+	var overlaps []Overlap
+	for _, s := range specs {
+		sc := score(s.all)
+		if sc == 0 || sc < minScore {
+			continue
+		}
+		o := Overlap{Spec: s.name, Score: sc, Shared: sharedTerms(want, s.all, weight)}
+		best := 0.0
+		for _, it := range s.items {
+			if itemScore := score(it.terms); itemScore > best {
+				best, o.Item, o.ItemTitle = itemScore, it.label, it.title
+			}
+		}
+		overlaps = append(overlaps, o)
+	}
+	sort.SliceStable(overlaps, func(i, j int) bool { return overlaps[i].Score > overlaps[j].Score })
+	return overlaps, nil
+}
+
+// sharedTerms returns up to five terms of want found in have, the most
+// distinctive first.
+func sharedTerms(want, have map[string]bool, weight map[string]float64) []string {
+	var shared []string
+	for t := range want {
+		if have[t] {
+			shared = append(shared, t)
+		}
+	}
+	sort.Slice(shared, func(i, j int) bool {
+		if weight[shared[i]] != weight[shared[j]] {
+			return weight[shared[i]] > weight[shared[j]]
+		}
+		return shared[i] < shared[j]
+	})
+	return shared[:min(5, len(shared))]
+}
@@ -0,0 +1,120 @@
+// Package search_test tests overlap detection between feature descriptions
+// and existing specs.
+// Related: internal/search/overlap.go
+// Tags: search, overlap, specify
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sessionsSpecYAML = `_meta:
+  version: "1.0"
+feature:
+  description: "Keep users signed in with server-side sessions"
+user_stories:
+  - id: US-001
+    title: Stay signed in
+    i_want: my session to survive browser restarts
+  - id: US-002
+    title: Expire idle sessions
+    i_want: idle sessions to expire after a timeout
+requirements:
+  functional:
+    - id: FR-001
+      description: System MUST expire idle sessions after a configurable timeout
+`
+
+const exportSpecYAML = `feature:
+  description: "Export monthly reports as CSV files"
+user_stories:
+  - id: US-001
+    title: Download a CSV report
+`
+
+func writeOverlapSpecs(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"007-user-sessions/spec.yaml": sessionsSpecYAML,
+		"008-export/spec.yaml":        exportSpecYAML,
+	} {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestFindOverlaps(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		description string
+		minScore    float64
+		wantSpecs   []string
+		wantItem    string
+	}{
+		"overlapping feature": {
+			description: "Add an idle session timeout",
+			minScore:    0.4,
+			wantSpecs:   []string{"007-user-sessions"},
+			wantItem:    "story US-002",
+		},
+		"unrelated feature": {
+			description: "Dark mode with system preference detection",
+			minScore:    0.4,
+		},
+		"low threshold reports partial matches": {
+			description: "Export idle sessions",
+			minScore:    0.1,
+			wantSpecs:   []string{"007-user-sessions", "008-export"},
+		},
+		"only stop words": {
+			description: "the system must support users",
+			minScore:    0.1,
+		},
+	}
+
+	dir := writeOverlapSpecs(t)
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			overlaps, err := FindOverlaps(dir, tt.description, tt.minScore)
+			require.NoError(t, err)
+			var specs []string
+			for _, o := range overlaps {
+				specs = append(specs, o.Spec)
+				assert.GreaterOrEqual(t, o.Score, tt.minScore)
+				assert.NotEmpty(t, o.Shared)
+			}
+			assert.Equal(t, tt.wantSpecs, specs)
+			if tt.wantItem != "" {
+				assert.Equal(t, tt.wantItem, overlaps[0].Item)
+			}
+		})
+	}
+}
+
+func TestFindOverlaps_SharedTerms(t *testing.T) {
+	t.Parallel()
+
+	overlaps, err := FindOverlaps(writeOverlapSpecs(t), "Expire idle sessions after a timeout", 0.5)
+	require.NoError(t, err)
+	require.Len(t, overlaps, 1)
+	assert.InDelta(t, 1.0, overlaps[0].Score, 0.001)
+	assert.ElementsMatch(t, []string{"expire", "idle", "session", "timeout"}, overlaps[0].Shared)
+	assert.Equal(t, "Expire idle sessions", overlaps[0].ItemTitle)
+}
+
+func TestOverlapTerms(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, map[string]bool{"idle": true, "session": true, "access": true, "timeout": true},
+		overlapTerms("The system MUST add idle sessions, access & timeouts"))
+}
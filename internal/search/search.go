@@ -0,0 +1,312 @@
+// Package search finds text in the structured fields of spec artifacts across
+// all specs, reporting each hit as the typed item it belongs to (requirement
+// FR-003 of spec 004, task T012, ...) rather than as a raw line. It also
+// scores how much of a new feature description existing specs already cover.
+package search
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"gopkg.in/yaml.v3"
+)
+
+// Artifacts are the artifact files searched, in result order.
+var Artifacts = []string{"spec", "plan", "tasks", "research"}
+
+// itemKinds names the items of well-known artifact lists, by list key.
+var itemKinds = map[string]string{
+	"user_stories":          "story",
+	"acceptance_scenarios":  "scenario",
+	"functional":            "requirement",
+	"non_functional":        "requirement",
+	"key_entities":          "entity",
+	"implementation_phases": "plan phase",
+	"risks":                 "risk",
+	"phases":                "phase",
+	"tasks":                 "task",
+	"findings":              "finding",
+	"options":               "option",
+}
+
+// labelKeys are the keys that identify an item, in order of preference. A
+// list item without any of them is part of the item that contains it.
+var labelKeys = []string{"id", "number", "phase", "name", "title"}
+
+// titleKeys are the keys whose value describes an item, in order of preference.
+var titleKeys = []string{"title", "name", "topic", "description", "risk", "summary"}
+
+// Options controls a search.
+type Options struct {
+	// Artifacts limits the search to these artifacts (default: Artifacts).
+	Artifacts []string
+	// Kinds limits hits to items of these kinds, e.g. "requirement".
+	Kinds []string
+}
+
+// Hit is an item whose fields contain every query term.
+type Hit struct {
+	Spec     string  `json:"spec"`         // spec directory, relative to the specs dir
+	Artifact string  `json:"artifact"`     // artifact file, e.g. "spec.yaml"
+	Kind     string  `json:"kind"`         // item kind, e.g. "requirement"; the section name for top-level fields
+	ID       string  `json:"id,omitempty"` // item label, e.g. "FR-003"
+	Title    string  `json:"title,omitempty"`
+	Line     int     `json:"line"`
+	Matches  []Match `json:"matches"` // fields containing at least one term
+}
+
+// Label returns the hit's kind and ID, e.g. "requirement FR-003".
+func (h Hit) Label() string {
+	if h.ID == "" {
+		return h.Kind
+	}
+	return h.Kind + " " + h.ID
+}
+
+// Match is one matching field of a hit.
+type Match struct {
+	Field string `json:"field"` // path within the item, e.g. "acceptance_scenarios[1].then"
+	Text  string `json:"text"`
+	Line  int    `json:"line"`
+}
+
+// Terms splits a query into lower-case terms.
+func Terms(query string) []string {
+	return strings.Fields(strings.ToLower(query))
+}
+
+// Search returns the hits for query in every spec in specsDir, ordered by
+// spec, artifact and line. An item matches when each query term occurs,
+// ignoring case, in at least one of its fields.
+func Search(specsDir, query string, opts Options) ([]Hit, error) {
+	terms := Terms(query)
+	if len(terms) == 0 {
+		return nil, errors.New("empty search query")
+	}
+	artifacts := opts.Artifacts
+	if len(artifacts) == 0 {
+		artifacts = Artifacts
+	}
+
+	var hits []Hit
+	for _, name := range spec.ListSpecNames(specsDir) {
+		for _, artifact := range artifacts {
+			file := artifact + ".yaml"
+			items, err := loadItems(filepath.Join(specsDir, name, file))
+			if err != nil {
+				return nil, err
+			}
+			for _, it := range items {
+				if hit, ok := it.match(terms); ok && kindAllowed(hit.Kind, opts.Kinds) {
+					hit.Spec, hit.Artifact = name, file
+					hits = append(hits, hit)
+				}
+			}
+		}
+	}
+	return hits, nil
+}
+
+func kindAllowed(kind string, kinds []string) bool {
+	if len(kinds) == 0 {
+		return true
+	}
+	for _, k := range kinds {
+		if strings.EqualFold(k, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// field is a scalar value in an artifact.
+type field struct {
+	path string
+	text string
+	line int
+}
+
+// item is a searchable unit of an artifact: a labeled list item, or a
+// top-level section without labeled items.
+type item struct {
+	kind   string
+	id     string
+	title  string
+	line   int
+	fields []field
+}
+
+// match returns the item as a hit when every term occurs in its fields.
+func (it *item) match(terms []string) (Hit, bool) {
+	found := make(map[string]bool, len(terms))
+	var matches []Match
+	for _, f := range it.fields {
+		text := strings.ToLower(f.text)
+		hit := false
+		for _, t := range terms {
+			if strings.Contains(text, t) {
+				found[t] = true
+				hit = true
+			}
+		}
+		if hit {
+			matches = append(matches, Match{Field: f.path, Text: f.text, Line: f.line})
+		}
+	}
+	if len(found) < len(terms) {
+		return Hit{}, false
+	}
+	return Hit{Kind: it.kind, ID: it.id, Title: it.title, Line: it.line, Matches: matches}, true
+}
+
+// loadItems parses an artifact into items. A missing file has none.
+func loadItems(path string) ([]*item, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	var items []*item
+	doc := root.Content[0]
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		key, value := doc.Content[i], doc.Content[i+1]
+		if key.Value == "_meta" {
+			continue
+		}
+		section := &item{kind: key.Value, line: key.Line}
+		items = append(items, section)
+		w := walker{items: &items}
+		w.walk(value, key.Value, key.Value, section, key.Value)
+	}
+
+	kept := items[:0]
+	for _, it := range items {
+		if len(it.fields) > 0 {
+			kept = append(kept, it)
+		}
+	}
+	sort.SliceStable(kept, func(i, j int) bool { return kept[i].line < kept[j].line })
+	return kept, nil
+}
+
+// walker collects the fields of an artifact into items.
+type walker struct {
+	items *[]*item
+}
+
+// walk adds the scalars under n to owner, with paths relative to owner
+// starting at path. listKey is the key of the innermost list, which names
+// the kind of the items in it.
+func (w walker) walk(n *yaml.Node, path, listKey string, owner *item, ownerPath string) {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.Value != "" {
+			rel := strings.TrimPrefix(strings.TrimPrefix(path, ownerPath), ".")
+			if rel == "" {
+				rel = path
+			}
+			owner.fields = append(owner.fields, field{path: rel, text: n.Value, line: n.Line})
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			w.walk(n.Content[i+1], path+"."+key, key, owner, ownerPath)
+		}
+	case yaml.SequenceNode:
+		for i, child := range n.Content {
+			childPath := fmt.Sprintf("%s[%d]", path, i)
+			if id := label(child); id != "" {
+				it := &item{kind: kindOf(listKey), id: id, title: title(child), line: child.Line}
+				*w.items = append(*w.items, it)
+				w.walk(child, childPath, listKey, it, childPath)
+				continue
+			}
+			w.walk(child, childPath, listKey, owner, ownerPath)
+		}
+	}
+}
+
+// kindOf returns the kind of the items of the list under key.
+func kindOf(key string) string {
+	if kind, ok := itemKinds[key]; ok {
+		return kind
+	}
+	return strings.ReplaceAll(strings.TrimSuffix(key, "s"), "_", " ")
+}
+
+// label returns the identifying value of a list item, or "" when it has none.
+func label(n *yaml.Node) string {
+	for _, key := range labelKeys {
+		if v := scalar(n, key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// title returns the describing value of a list item.
+func title(n *yaml.Node) string {
+	for _, key := range titleKeys {
+		if v := scalar(n, key); v != "" && v != label(n) {
+			return v
+		}
+	}
+	return ""
+}
+
+// scalar returns the scalar value of key in mapping n, or "".
+func scalar(n *yaml.Node, key string) string {
+	if n.Kind != yaml.MappingNode {
+		return ""
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key && n.Content[i+1].Kind == yaml.ScalarNode {
+			return n.Content[i+1].Value
+		}
+	}
+	return ""
+}
+
+// Snippet returns text on one line, shortened to about width characters
+// around the first query term it contains.
+func Snippet(text string, terms []string, width int) string {
+	r := []rune(strings.Join(strings.Fields(text), " "))
+	if len(r) <= width {
+		return string(r)
+	}
+	lower := []rune(strings.ToLower(string(r)))
+	pos := 0
+	for _, t := range terms {
+		if i := strings.Index(string(lower), t); i >= 0 {
+			pos = len([]rune(string(lower)[:i]))
+			break
+		}
+	}
+	start := max(0, pos-width/3)
+	end := min(len(r), start+width)
+	start = max(0, end-width)
+
+	snippet := string(r[start:end])
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(r) {
+		snippet += "…"
+	}
+	return snippet
+}
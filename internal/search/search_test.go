@@ -0,0 +1,167 @@
+// Package search_test tests searching spec artifacts across specs.
+// Related: internal/search/search.go
+// Tags: search, spec, artifacts
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const searchSpecYAML = `_meta:
+  version: "1.0"
+feature:
+  branch: "004-login"
+  description: "Let users sign in with OAuth providers"
+user_stories:
+  - id: US-001
+    title: Sign in with GitHub
+    acceptance_scenarios:
+      - given: a GitHub account
+        when: the user signs in
+        then: an OAuth token is stored
+requirements:
+  functional:
+    - id: FR-001
+      description: System MUST store sessions
+    - id: FR-003
+      description: System MUST refresh OAuth tokens before they expire
+`
+
+const searchTasksYAML = `_meta:
+  version: "1.0"
+phases:
+  - number: 1
+    title: Setup
+    tasks:
+      - id: T001
+        title: Add OAuth client
+        status: Pending
+        acceptance_criteria:
+          - Token refresh retries once
+`
+
+// writeSpecs creates a specs dir with a login spec and an unrelated one.
+func writeSpecs(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"004-login/spec.yaml":  searchSpecYAML,
+		"004-login/tasks.yaml": searchTasksYAML,
+		"005-export/spec.yaml": "feature:\n  description: Export reports as CSV\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	return dir
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		query      string
+		opts       Options
+		wantLabels []string
+	}{
+		"typed hits across artifacts": {
+			query:      "oauth",
+			wantLabels: []string{"feature", "story US-001", "requirement FR-003", "task T001"},
+		},
+		"all terms must match": {
+			query:      "refresh OAUTH",
+			wantLabels: []string{"requirement FR-003", "task T001"},
+		},
+		"kind filter": {
+			query:      "oauth",
+			opts:       Options{Kinds: []string{"Requirement"}},
+			wantLabels: []string{"requirement FR-003"},
+		},
+		"artifact filter": {
+			query:      "oauth",
+			opts:       Options{Artifacts: []string{"tasks"}},
+			wantLabels: []string{"task T001"},
+		},
+		"other spec": {
+			query:      "csv",
+			wantLabels: []string{"feature"},
+		},
+		"no match": {
+			query: "kubernetes",
+		},
+	}
+
+	dir := writeSpecs(t)
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			hits, err := Search(dir, tt.query, tt.opts)
+			require.NoError(t, err)
+			var labels []string
+			for _, h := range hits {
+				labels = append(labels, h.Label())
+			}
+			assert.Equal(t, tt.wantLabels, labels)
+		})
+	}
+}
+
+func TestSearch_HitDetails(t *testing.T) {
+	t.Parallel()
+
+	hits, err := Search(writeSpecs(t), "oauth token", Options{Kinds: []string{"story"}})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+
+	h := hits[0]
+	assert.Equal(t, "004-login", h.Spec)
+	assert.Equal(t, "spec.yaml", h.Artifact)
+	assert.Equal(t, "Sign in with GitHub", h.Title)
+	assert.Equal(t, 7, h.Line)
+	assert.Equal(t, []Match{{Field: "acceptance_scenarios[0].then", Text: "an OAuth token is stored", Line: 12}}, h.Matches)
+}
+
+func TestSearch_SectionFields(t *testing.T) {
+	t.Parallel()
+
+	hits, err := Search(writeSpecs(t), "providers", Options{})
+	require.NoError(t, err)
+	require.Len(t, hits, 1)
+	assert.Equal(t, "feature", hits[0].Label())
+	assert.Equal(t, "description", hits[0].Matches[0].Field)
+}
+
+func TestSearch_EmptyQuery(t *testing.T) {
+	t.Parallel()
+
+	_, err := Search(t.TempDir(), "  ", Options{})
+	assert.Error(t, err)
+}
+
+func TestSnippet(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		text  string
+		width int
+		want  string
+	}{
+		"short text kept":    {text: "sign in\nwith  OAuth", width: 40, want: "sign in with OAuth"},
+		"cut around match":   {text: "aaaaaaaaaa bbbbbbbbbb OAuth cccccccccc", width: 12, want: "…bbb OAuth cc…"},
+		"cut at start":       {text: "OAuth aaaaaaaaaa", width: 8, want: "OAuth aa…"},
+		"no match cut start": {text: "aaaaaaaaaa", width: 4, want: "aaaa…"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Snippet(tt.text, []string{"oauth"}, tt.width))
+		})
+	}
+}
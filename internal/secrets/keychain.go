@@ -0,0 +1,49 @@
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// result is the outcome of a keychain tool invocation.
+type result struct {
+	stdout string
+	stderr string
+	code   int
+}
+
+// runner runs a keychain tool with stdin and extra environment variables.
+// A non-zero exit is a result, not an error; the error is for tools that
+// could not be started.
+type runner func(stdin string, env []string, name string, args ...string) (result, error)
+
+// execRun runs name as a child process.
+func execRun(stdin string, env []string, name string, args ...string) (result, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	res := result{stdout: strings.TrimRight(stdout.String(), "\r\n"), stderr: strings.TrimSpace(stderr.String())}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		res.code = exitErr.ExitCode()
+		return res, nil
+	}
+	return res, err
+}
+
+// failed describes a keychain tool's failure.
+func (r result) failed(action string) error {
+	if r.stderr != "" {
+		return fmt.Errorf("%s: %s", action, r.stderr)
+	}
+	return fmt.Errorf("%s: exit status %d", action, r.code)
+}
@@ -0,0 +1,60 @@
+package secrets
+
+import "os/exec"
+
+// errItemNotFound is the exit status of security when no item matches.
+const errItemNotFound = 44
+
+// macKeychain stores secrets as generic passwords in the login keychain.
+type macKeychain struct {
+	service string
+	run     runner
+}
+
+func newKeychain(service string) Store {
+	if _, err := exec.LookPath("security"); err != nil {
+		return nil
+	}
+	return &macKeychain{service: service, run: execRun}
+}
+
+func (k *macKeychain) Name() string { return "macOS Keychain" }
+
+func (k *macKeychain) Get(name string) (string, error) {
+	res, err := k.run("", nil, "security", "find-generic-password", "-s", k.service, "-a", name, "-w")
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case res.code == errItemNotFound:
+		return "", ErrNotFound
+	case res.code != 0:
+		return "", res.failed("security find-generic-password")
+	}
+	return res.stdout, nil
+}
+
+// Set leaves the value off the command line, where other users could see it
+// in the process list: with a bare -w, security prompts for the password and
+// its confirmation, and both answers are written to its stdin.
+func (k *macKeychain) Set(name, value string) error {
+	res, err := k.run(value+"\n"+value+"\n", nil, "security", "add-generic-password", "-U", "-s", k.service, "-a", name, "-l", k.service+" "+name, "-w")
+	if err != nil {
+		return err
+	}
+	if res.code != 0 {
+		return res.failed("security add-generic-password")
+	}
+	return nil
+}
+
+func (k *macKeychain) Delete(name string) error {
+	res, err := k.run("", nil, "security", "delete-generic-password", "-s", k.service, "-a", name)
+	if err != nil {
+		return err
+	}
+	if res.code != 0 && res.code != errItemNotFound {
+		return res.failed("security delete-generic-password")
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRun records invocations and returns res.
+type fakeRun struct {
+	calls [][]string
+	stdin []string
+	res   result
+}
+
+func (f *fakeRun) run(stdin string, _ []string, name string, args ...string) (result, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	f.stdin = append(f.stdin, stdin)
+	return f.res, nil
+}
+
+func TestMacKeychain_SetKeepsValueOffCommandLine(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeRun{}
+	k := &macKeychain{service: "autospec", run: fake.run}
+	require.NoError(t, k.Set(GitHubToken, "s3cret"))
+
+	require.Len(t, fake.calls, 1)
+	assert.Equal(t, []string{"security", "add-generic-password", "-U", "-s", "autospec", "-a", "github-token", "-l", "autospec github-token", "-w"}, fake.calls[0])
+	assert.NotContains(t, fake.calls[0], "s3cret")
+	assert.Equal(t, "s3cret\ns3cret\n", fake.stdin[0])
+}
+
+func TestMacKeychain_SetFailure(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeRun{res: result{code: 1, stderr: "User interaction is not allowed."}}
+	k := &macKeychain{service: "autospec", run: fake.run}
+	assert.EqualError(t, k.Set(GitHubToken, "s3cret"), "security add-generic-password: User interaction is not allowed.")
+}
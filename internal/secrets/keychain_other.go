@@ -0,0 +1,60 @@
+//go:build !darwin && !windows
+
+package secrets
+
+import "os/exec"
+
+// secretService stores secrets through secret-tool in the Secret Service
+// (GNOME Keyring, KWallet and compatible daemons).
+type secretService struct {
+	service string
+	run     runner
+}
+
+func newKeychain(service string) Store {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil
+	}
+	return &secretService{service: service, run: execRun}
+}
+
+func (k *secretService) Name() string { return "Secret Service" }
+
+// Get treats a failed lookup without an error message as a missing secret:
+// secret-tool exits with 1 in both cases.
+func (k *secretService) Get(name string) (string, error) {
+	res, err := k.run("", nil, "secret-tool", "lookup", "service", k.service, "account", name)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case res.code == 1 && res.stderr == "":
+		return "", ErrNotFound
+	case res.code != 0:
+		return "", res.failed("secret-tool lookup")
+	}
+	return res.stdout, nil
+}
+
+// Set passes value on stdin, keeping it out of the process list.
+func (k *secretService) Set(name, value string) error {
+	res, err := k.run(value, nil, "secret-tool", "store", "--label="+k.service+" "+name, "service", k.service, "account", name)
+	if err != nil {
+		return err
+	}
+	if res.code != 0 {
+		return res.failed("secret-tool store")
+	}
+	return nil
+}
+
+func (k *secretService) Delete(name string) error {
+	res, err := k.run("", nil, "secret-tool", "clear", "service", k.service, "account", name)
+	if err != nil {
+		return err
+	}
+	if res.code != 0 && res.stderr != "" {
+		return res.failed("secret-tool clear")
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+//go:build !darwin && !windows
+
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRun records invocations and returns res.
+type fakeRun struct {
+	calls [][]string
+	stdin []string
+	res   result
+}
+
+func (f *fakeRun) run(stdin string, _ []string, name string, args ...string) (result, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	f.stdin = append(f.stdin, stdin)
+	return f.res, nil
+}
+
+func TestSecretService(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		res     result
+		want    string
+		wantErr string
+	}{
+		"found":     {res: result{stdout: "s3cret"}, want: "s3cret"},
+		"not found": {res: result{code: 1}, wantErr: ErrNotFound.Error()},
+		"failure":   {res: result{code: 1, stderr: "Cannot autolaunch D-Bus"}, wantErr: "secret-tool lookup: Cannot autolaunch D-Bus"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			fake := &fakeRun{res: tt.res}
+			k := &secretService{service: "autospec", run: fake.run}
+			got, err := k.Get(GitHubToken)
+			assert.Equal(t, []string{"secret-tool", "lookup", "service", "autospec", "account", "github-token"}, fake.calls[0])
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSecretService_SetDelete(t *testing.T) {
+	t.Parallel()
+
+	fake := &fakeRun{}
+	k := &secretService{service: "work", run: fake.run}
+	require.NoError(t, k.Set(JiraToken, "t0ken"))
+	require.NoError(t, k.Delete(JiraToken))
+
+	assert.Equal(t, []string{"secret-tool", "store", "--label=work jira-token", "service", "work", "account", "jira-token"}, fake.calls[0])
+	assert.Equal(t, "t0ken", fake.stdin[0], "the value is passed on stdin")
+	assert.Equal(t, []string{"secret-tool", "clear", "service", "work", "account", "jira-token"}, fake.calls[1])
+
+	fake.res = result{code: 1, stderr: "No such interface"}
+	assert.EqualError(t, k.Set(JiraToken, "t0ken"), "secret-tool store: No such interface")
+}
@@ -0,0 +1,143 @@
+package secrets
+
+import (
+	"encoding/base64"
+	"os/exec"
+	"unicode/utf16"
+)
+
+// errItemNotFound is the exit status of credScript when no credential matches.
+const errItemNotFound = 44
+
+// credScript reads, writes or deletes a generic credential through the
+// Credential Manager API. The target, action and value are passed in
+// environment variables so they never appear on the command line.
+const credScript = `$ErrorActionPreference = 'Stop'
+Add-Type -TypeDefinition @'
+using System;
+using System.Runtime.InteropServices;
+using System.Text;
+public static class AutospecCred {
+  [StructLayout(LayoutKind.Sequential, CharSet = CharSet.Unicode)]
+  struct CREDENTIAL {
+    public int Flags; public int Type; public string TargetName; public string Comment;
+    public System.Runtime.InteropServices.ComTypes.FILETIME LastWritten;
+    public int CredentialBlobSize; public IntPtr CredentialBlob; public int Persist;
+    public int AttributeCount; public IntPtr Attributes; public string TargetAlias; public string UserName;
+  }
+  [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+  static extern bool CredRead(string target, int type, int flags, out IntPtr cred);
+  [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+  static extern bool CredWrite(ref CREDENTIAL cred, int flags);
+  [DllImport("advapi32.dll", CharSet = CharSet.Unicode, SetLastError = true)]
+  static extern bool CredDelete(string target, int type, int flags);
+  [DllImport("advapi32.dll")]
+  static extern void CredFree(IntPtr cred);
+  public static string Read(string target) {
+    IntPtr p;
+    if (!CredRead(target, 1, 0, out p)) return null;
+    try {
+      CREDENTIAL c = (CREDENTIAL)Marshal.PtrToStructure(p, typeof(CREDENTIAL));
+      return Marshal.PtrToStringUni(c.CredentialBlob, c.CredentialBlobSize / 2);
+    } finally { CredFree(p); }
+  }
+  public static void Write(string target, string user, string secret) {
+    byte[] blob = Encoding.Unicode.GetBytes(secret);
+    CREDENTIAL c = new CREDENTIAL();
+    c.Type = 1; c.TargetName = target; c.UserName = user; c.Persist = 2;
+    c.CredentialBlobSize = blob.Length;
+    c.CredentialBlob = Marshal.AllocHGlobal(blob.Length);
+    try {
+      Marshal.Copy(blob, 0, c.CredentialBlob, blob.Length);
+      if (!CredWrite(ref c, 0)) throw new System.ComponentModel.Win32Exception(Marshal.GetLastWin32Error());
+    } finally { Marshal.FreeHGlobal(c.CredentialBlob); }
+  }
+  public static void Delete(string target) { CredDelete(target, 1, 0); }
+}
+'@
+switch ($env:AUTOSPEC_CRED_ACTION) {
+  'get' {
+    $v = [AutospecCred]::Read($env:AUTOSPEC_CRED_TARGET)
+    if ($v -eq $null) { exit 44 }
+    [Console]::Out.Write($v)
+  }
+  'set' { [AutospecCred]::Write($env:AUTOSPEC_CRED_TARGET, $env:AUTOSPEC_CRED_USER, $env:AUTOSPEC_CRED_VALUE) }
+  'delete' { [AutospecCred]::Delete($env:AUTOSPEC_CRED_TARGET) }
+}
+`
+
+// credManager stores secrets as generic credentials named <service>:<name>
+// in the Windows Credential Manager.
+type credManager struct {
+	service    string
+	powershell string
+	run        runner
+}
+
+func newKeychain(service string) Store {
+	for _, name := range []string{"powershell.exe", "pwsh.exe"} {
+		if path, err := exec.LookPath(name); err == nil {
+			return &credManager{service: service, powershell: path, run: execRun}
+		}
+	}
+	return nil
+}
+
+func (k *credManager) Name() string { return "Windows Credential Manager" }
+
+// invoke runs credScript for action on the secret called name.
+func (k *credManager) invoke(action, name, value string) (result, error) {
+	env := []string{
+		"AUTOSPEC_CRED_ACTION=" + action,
+		"AUTOSPEC_CRED_TARGET=" + k.service + ":" + name,
+		"AUTOSPEC_CRED_USER=" + name,
+		"AUTOSPEC_CRED_VALUE=" + value,
+	}
+	return k.run("", env, k.powershell, "-NoProfile", "-NonInteractive", "-EncodedCommand", encodeCommand(credScript))
+}
+
+// encodeCommand encodes a script for -EncodedCommand (base64 of UTF-16LE).
+func encodeCommand(script string) string {
+	units := utf16.Encode([]rune(script))
+	b := make([]byte, 2*len(units))
+	for i, u := range units {
+		b[2*i], b[2*i+1] = byte(u), byte(u>>8)
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func (k *credManager) Get(name string) (string, error) {
+	res, err := k.invoke("get", name, "")
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case res.code == errItemNotFound:
+		return "", ErrNotFound
+	case res.code != 0:
+		return "", res.failed("reading credential")
+	}
+	return res.stdout, nil
+}
+
+func (k *credManager) Set(name, value string) error {
+	res, err := k.invoke("set", name, value)
+	if err != nil {
+		return err
+	}
+	if res.code != 0 {
+		return res.failed("writing credential")
+	}
+	return nil
+}
+
+func (k *credManager) Delete(name string) error {
+	res, err := k.invoke("delete", name, "")
+	if err != nil {
+		return err
+	}
+	if res.code != 0 {
+		return res.failed("deleting credential")
+	}
+	return nil
+}
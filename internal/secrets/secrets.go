@@ -0,0 +1,190 @@
+// Package secrets reads tokens and passwords from environment variables and
+// the OS keychain, so they never need to live in plain-text config files.
+//
+// The keychain is reached through the platform's command-line tool: security
+// on macOS, secret-tool (Secret Service, e.g. GNOME Keyring or KWallet) on
+// Linux and other Unix systems, and PowerShell calling the Credential Manager
+// (wincred) on Windows. Secrets are stored under a service name ("autospec" by
+// default) with the secret's name as the account.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// Names of the secrets autospec reads.
+const (
+	NtfyToken     = "ntfy-token"
+	PushoverToken = "pushover-token"
+	PushoverUser  = "pushover-user"
+	EmailPassword = "email-password"
+	JiraToken     = "jira-token"
+	LinearToken   = "linear-token"
+	GitHubToken   = "github-token"
+	GitLabToken   = "gitlab-token"
+	EncryptionKey = "encryption-key"
+)
+
+// Secret describes a secret autospec reads and the environment variables
+// that take precedence over the keychain.
+type Secret struct {
+	Name        string
+	Description string
+	Env         []string
+}
+
+// Known lists the secrets autospec reads.
+var Known = []Secret{
+	{Name: NtfyToken, Description: "ntfy access token (notifications.ntfy.token)", Env: []string{"AUTOSPEC_NOTIFICATIONS_NTFY_TOKEN"}},
+	{Name: PushoverToken, Description: "Pushover application token (notifications.pushover.token)", Env: []string{"AUTOSPEC_NOTIFICATIONS_PUSHOVER_TOKEN"}},
+	{Name: PushoverUser, Description: "Pushover user key (notifications.pushover.user)", Env: []string{"AUTOSPEC_NOTIFICATIONS_PUSHOVER_USER"}},
+	{Name: EmailPassword, Description: "SMTP password (notifications.email.password)", Env: []string{"AUTOSPEC_NOTIFICATIONS_EMAIL_PASSWORD"}},
+	{Name: JiraToken, Description: "Jira API token (issue_tracker.jira)", Env: []string{"JIRA_API_TOKEN"}},
+	{Name: LinearToken, Description: "Linear API key (issue_tracker.linear)", Env: []string{"LINEAR_API_KEY"}},
+	{Name: GitHubToken, Description: "GitHub token for specify --from-issue and the GitHub Projects sync", Env: []string{"GITHUB_TOKEN", "GH_TOKEN"}},
+	{Name: GitLabToken, Description: "GitLab token for specify --from-issue", Env: []string{"GITLAB_TOKEN"}},
+	{Name: EncryptionKey, Description: "At-rest encryption key (encryption)", Env: []string{"AUTOSPEC_ENCRYPTION_KEY"}},
+}
+
+// LookupKnown returns the known secret called name, or false.
+func LookupKnown(name string) (Secret, bool) {
+	for _, s := range Known {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return Secret{}, false
+}
+
+// Names returns the names of the known secrets, sorted.
+func Names() []string {
+	names := make([]string, 0, len(Known))
+	for _, s := range Known {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ErrNotFound is returned when a secret is neither in the environment nor in
+// the keychain.
+var ErrNotFound = errors.New("secret not found")
+
+// Store is a place secrets are kept.
+type Store interface {
+	// Name describes the store for messages, e.g. "macOS Keychain".
+	Name() string
+	// Get returns the secret called name, or ErrNotFound.
+	Get(name string) (string, error)
+	// Set stores value as the secret called name, replacing any previous value.
+	Set(name, value string) error
+	// Delete removes the secret called name; a missing secret is not an error.
+	Delete(name string) error
+}
+
+// DefaultService is the service name secrets are stored under unless
+// secrets.service names another.
+const DefaultService = "autospec"
+
+// Config holds the secret storage settings (config: secrets).
+type Config struct {
+	// Keychain reads secrets missing from the environment and config from the OS keychain (default: true).
+	Keychain bool `koanf:"keychain" yaml:"keychain" json:"keychain"`
+
+	// Service is the keychain service name secrets are stored under (default: autospec).
+	Service string `koanf:"service" yaml:"service" json:"service"`
+}
+
+// Keychain returns the OS keychain store for service, or nil when the
+// platform's keychain tool is not installed.
+func Keychain(service string) Store {
+	if service == "" {
+		service = DefaultService
+	}
+	return newKeychain(service)
+}
+
+// The package default store is set from the config when it is loaded.
+var (
+	mu      sync.Mutex
+	current Store
+	loaded  bool
+	cfg     = Config{Keychain: true}
+)
+
+// Use makes the keychain described by c the default store. The keychain
+// tool is looked up on first use.
+func Use(c Config) {
+	mu.Lock()
+	defer mu.Unlock()
+	cfg, current, loaded = c, nil, false
+}
+
+// UseStore makes s the default store (nil: environment only).
+func UseStore(s Store) {
+	mu.Lock()
+	defer mu.Unlock()
+	current, loaded = s, true
+}
+
+// Default returns the default store, or nil when the keychain is disabled
+// or unavailable.
+func Default() Store {
+	mu.Lock()
+	defer mu.Unlock()
+	if !loaded {
+		loaded = true
+		if cfg.Keychain {
+			current = Keychain(cfg.Service)
+		}
+	}
+	return current
+}
+
+// Get returns the secret called name from the first set environment variable
+// in env, then from the default store. It returns an ErrNotFound error naming
+// where to set it when neither has it.
+func Get(name string, env ...string) (string, error) {
+	for _, v := range env {
+		if value := os.Getenv(v); value != "" {
+			return value, nil
+		}
+	}
+	store := Default()
+	if store == nil {
+		return "", notFound(name, env)
+	}
+	value, err := store.Get(name)
+	if errors.Is(err, ErrNotFound) {
+		return "", notFound(name, env)
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s from %s: %w", name, store.Name(), err)
+	}
+	return value, nil
+}
+
+// notFound describes where a missing secret can be set.
+func notFound(name string, env []string) error {
+	if len(env) == 0 {
+		return fmt.Errorf("%w: run 'autospec secrets set %s'", ErrNotFound, name)
+	}
+	return fmt.Errorf("%w: export %s or run 'autospec secrets set %s'", ErrNotFound, env[0], name)
+}
+
+// Fill sets *dst to the secret called name when it is empty, reading the
+// known secret's environment variables and then the default store. Lookup
+// errors leave *dst empty for the caller's own validation to report.
+func Fill(dst *string, name string) {
+	if *dst != "" {
+		return
+	}
+	s, _ := LookupKnown(name)
+	if value, err := Get(name, s.Env...); err == nil {
+		*dst = value
+	}
+}
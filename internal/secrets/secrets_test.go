@@ -0,0 +1,114 @@
+package secrets
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memStore is an in-memory Store.
+type memStore struct {
+	values map[string]string
+	err    error
+}
+
+func (m *memStore) Name() string { return "memory" }
+
+func (m *memStore) Get(name string) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	v, ok := m.values[name]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memStore) Set(name, value string) error {
+	m.values[name] = value
+	return nil
+}
+
+func (m *memStore) Delete(name string) error {
+	delete(m.values, name)
+	return nil
+}
+
+func TestKnown(t *testing.T) {
+	t.Parallel()
+
+	seen := map[string]bool{}
+	for _, s := range Known {
+		assert.False(t, seen[s.Name], "duplicate secret %s", s.Name)
+		seen[s.Name] = true
+		assert.NotEmpty(t, s.Description, s.Name)
+		assert.NotEmpty(t, s.Env, s.Name)
+	}
+	_, ok := LookupKnown(JiraToken)
+	assert.True(t, ok)
+	_, ok = LookupKnown("nope")
+	assert.False(t, ok)
+	assert.Len(t, Names(), len(Known))
+}
+
+func TestGet(t *testing.T) {
+	// Not parallel: changes the default store and the environment
+	t.Cleanup(func() { Use(Config{Keychain: true}) })
+	store := &memStore{values: map[string]string{JiraToken: "from-keychain"}}
+	UseStore(store)
+
+	t.Setenv("TEST_SECRET_A", "")
+	t.Setenv("TEST_SECRET_B", "from-env")
+	v, err := Get(JiraToken, "TEST_SECRET_A", "TEST_SECRET_B")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", v, "environment variables come first")
+
+	v, err = Get(JiraToken, "TEST_SECRET_A")
+	require.NoError(t, err)
+	assert.Equal(t, "from-keychain", v)
+
+	_, err = Get(LinearToken, "TEST_SECRET_A")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.EqualError(t, err, "secret not found: export TEST_SECRET_A or run 'autospec secrets set linear-token'")
+
+	store.err = errors.New("keychain locked")
+	_, err = Get(JiraToken)
+	assert.EqualError(t, err, "reading jira-token from memory: keychain locked")
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	UseStore(nil)
+	_, err = Get(JiraToken)
+	assert.EqualError(t, err, "secret not found: run 'autospec secrets set jira-token'")
+}
+
+func TestFill(t *testing.T) {
+	// Not parallel: changes the default store and the environment
+	t.Cleanup(func() { Use(Config{Keychain: true}) })
+	UseStore(&memStore{values: map[string]string{NtfyToken: "tk_keychain", PushoverUser: "u_keychain"}})
+	t.Setenv("AUTOSPEC_NOTIFICATIONS_NTFY_TOKEN", "")
+	t.Setenv("AUTOSPEC_NOTIFICATIONS_PUSHOVER_USER", "")
+	t.Setenv("AUTOSPEC_NOTIFICATIONS_PUSHOVER_TOKEN", "")
+
+	token := ""
+	Fill(&token, NtfyToken)
+	assert.Equal(t, "tk_keychain", token)
+
+	user := "u_config"
+	Fill(&user, PushoverUser)
+	assert.Equal(t, "u_config", user, "config values are kept")
+
+	missing := ""
+	Fill(&missing, PushoverToken)
+	assert.Empty(t, missing)
+}
+
+func TestUse(t *testing.T) {
+	// Not parallel: changes the default store
+	t.Cleanup(func() { Use(Config{Keychain: true}) })
+
+	Use(Config{Keychain: false})
+	assert.Nil(t, Default(), "keychain disabled")
+}
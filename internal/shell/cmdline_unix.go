@@ -0,0 +1,11 @@
+//go:build !windows
+
+package shell
+
+import "os/exec"
+
+// setCmdLine passes line to cmd.exe. Unix has no raw command line, so line
+// is one argument after the switches.
+func setCmdLine(cmd *exec.Cmd, line string) {
+	cmd.Args = append(cmd.Args, "/d", "/s", "/c", line)
+}
@@ -0,0 +1,17 @@
+//go:build windows
+
+package shell
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setCmdLine passes line to cmd.exe verbatim, bypassing the CreateProcess
+// quoting of exec.Cmd.Args, which cmd.exe does not parse: /s strips the
+// outer quotes and leaves line as written.
+func setCmdLine(cmd *exec.Cmd, line string) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine: syscall.EscapeArg(cmd.Path) + ` /d /s /c "` + line + `"`,
+	}
+}
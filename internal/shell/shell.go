@@ -0,0 +1,212 @@
+// Package shell runs user-configured command lines and scripts through the
+// platform's command interpreter: sh on Unix, cmd.exe (or PowerShell, with
+// the shell config key) on Windows. Task commands, formatters and custom
+// agent post-processors go through it so nothing depends on /bin/sh.
+// Related: internal/workflow/task_commands.go, internal/codefmt/codefmt.go, internal/cliagent/custom.go
+// Tags: shell, windows, quoting, exec
+package shell
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// Syntax is the command-line syntax a shell parses.
+type Syntax int
+
+const (
+	// POSIX is sh, bash and zsh.
+	POSIX Syntax = iota
+	// Cmd is Windows cmd.exe.
+	Cmd
+	// PowerShell is Windows PowerShell and PowerShell 7 (pwsh).
+	PowerShell
+)
+
+// syntaxes maps the supported shell names to their syntax.
+var syntaxes = map[string]Syntax{
+	"sh":         POSIX,
+	"bash":       POSIX,
+	"zsh":        POSIX,
+	"cmd":        Cmd,
+	"powershell": PowerShell,
+	"pwsh":       PowerShell,
+}
+
+// Shell is a command interpreter.
+type Shell struct {
+	// Name is the interpreter's executable, e.g. "sh" or "cmd".
+	Name   string
+	Syntax Syntax
+}
+
+// Names returns the supported shell names, sorted.
+func Names() []string {
+	names := make([]string, 0, len(syntaxes))
+	for name := range syntaxes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the platform's shell: cmd on Windows, sh elsewhere.
+func Default() Shell {
+	if runtime.GOOS == "windows" {
+		return Shell{Name: "cmd", Syntax: Cmd}
+	}
+	return Shell{Name: "sh", Syntax: POSIX}
+}
+
+// Lookup returns the shell called name. An empty name is the platform
+// default.
+func Lookup(name string) (Shell, error) {
+	if name == "" {
+		return Default(), nil
+	}
+	syntax, ok := syntaxes[name]
+	if !ok {
+		return Shell{}, fmt.Errorf("unknown shell %q (valid: %s)", name, strings.Join(Names(), ", "))
+	}
+	return Shell{Name: name, Syntax: syntax}, nil
+}
+
+var current atomic.Pointer[Shell]
+
+func init() {
+	def := Default()
+	current.Store(&def)
+}
+
+// Set selects the shell used by Command and Quote. It is set from the shell
+// config key when the config is loaded.
+func Set(name string) error {
+	s, err := Lookup(name)
+	if err != nil {
+		return err
+	}
+	current.Store(&s)
+	return nil
+}
+
+// Current returns the selected shell.
+func Current() Shell {
+	return *current.Load()
+}
+
+// Command returns the command that runs line through the selected shell.
+func Command(line string) *exec.Cmd {
+	return Current().Command(line)
+}
+
+// Quote quotes arg as a single word for the selected shell.
+func Quote(arg string) string {
+	return Current().Quote(arg)
+}
+
+// Command returns the command that runs line through s.
+func (s Shell) Command(line string) *exec.Cmd {
+	switch s.Syntax {
+	case Cmd:
+		cmd := exec.Command(s.Name)
+		setCmdLine(cmd, line)
+		return cmd
+	case PowerShell:
+		return exec.Command(s.Name, "-NoProfile", "-NonInteractive", "-Command", line)
+	default:
+		return exec.Command(s.Name, "-c", line)
+	}
+}
+
+// Quote quotes arg so s passes it to a program as one argument, unchanged.
+func (s Shell) Quote(arg string) string {
+	switch s.Syntax {
+	case Cmd:
+		return quoteCmd(arg)
+	case PowerShell:
+		return "'" + strings.ReplaceAll(arg, "'", "''") + "'"
+	default:
+		return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+}
+
+// quoteCmd quotes arg for a program started by cmd.exe: first as the C
+// runtime splits a Windows command line, then with every cmd.exe
+// metacharacter escaped by ^ so that quotes, pipes and %VAR% in arg are
+// not interpreted.
+func quoteCmd(arg string) string {
+	var b strings.Builder
+	for _, c := range quoteArgv(arg) {
+		if strings.ContainsRune(`()%!^"<>&|`, c) {
+			b.WriteByte('^')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// quoteArgv quotes arg following the CommandLineToArgvW rules: backslashes
+// are literal unless they precede a quote, where they are doubled.
+func quoteArgv(arg string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	slashes := 0
+	for i := 0; i < len(arg); i++ {
+		switch arg[i] {
+		case '\\':
+			slashes++
+		case '"':
+			b.WriteString(strings.Repeat(`\`, slashes+1))
+			slashes = 0
+		default:
+			slashes = 0
+		}
+		b.WriteByte(arg[i])
+	}
+	b.WriteString(strings.Repeat(`\`, slashes))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// Interpreted reports whether Script runs path through an interpreter, so
+// that it need not be executable.
+func Interpreted(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ps1":
+		return true
+	case ".sh":
+		return runtime.GOOS == "windows"
+	}
+	return false
+}
+
+// Script returns the command that runs the script at path with args:
+// .ps1 scripts through PowerShell and, on Windows, .sh scripts through sh
+// (as installed with Git for Windows). Other files, including .cmd and .bat
+// on Windows, are started directly.
+func Script(path string, args ...string) *exec.Cmd {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ps1":
+		psArgs := []string{"-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-File", path}
+		return exec.Command(powerShell(), append(psArgs, args...)...)
+	case ".sh":
+		if runtime.GOOS == "windows" {
+			return exec.Command("sh", append([]string{path}, args...)...)
+		}
+	}
+	return exec.Command(path, args...)
+}
+
+// powerShell returns PowerShell 7 when installed, Windows PowerShell
+// otherwise.
+func powerShell() string {
+	if _, err := exec.LookPath("pwsh"); err == nil || runtime.GOOS != "windows" {
+		return "pwsh"
+	}
+	return "powershell"
+}
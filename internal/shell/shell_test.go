@@ -0,0 +1,155 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name       string
+		wantShell  Shell
+		wantErrMsg string
+	}{
+		"empty is default": {name: "", wantShell: Default()},
+		"bash":             {name: "bash", wantShell: Shell{Name: "bash", Syntax: POSIX}},
+		"cmd":              {name: "cmd", wantShell: Shell{Name: "cmd", Syntax: Cmd}},
+		"pwsh":             {name: "pwsh", wantShell: Shell{Name: "pwsh", Syntax: PowerShell}},
+		"unknown":          {name: "fish", wantErrMsg: `unknown shell "fish" (valid: bash, cmd, powershell, pwsh, sh, zsh)`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := Lookup(tt.name)
+			if tt.wantErrMsg != "" {
+				require.EqualError(t, err, tt.wantErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantShell, got)
+		})
+	}
+}
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		syntax Syntax
+		arg    string
+		want   string
+	}{
+		"posix plain":            {syntax: POSIX, arg: "hello world", want: `'hello world'`},
+		"posix single quote":     {syntax: POSIX, arg: "it's", want: `'it'\''s'`},
+		"posix empty":            {syntax: POSIX, arg: "", want: `''`},
+		"powershell single":      {syntax: PowerShell, arg: "it's $HOME", want: `'it''s $HOME'`},
+		"cmd plain":              {syntax: Cmd, arg: "hello world", want: `^"hello world^"`},
+		"cmd metacharacters":     {syntax: Cmd, arg: "a&b|c>%PATH%", want: `^"a^&b^|c^>^%PATH^%^"`},
+		"cmd embedded quote":     {syntax: Cmd, arg: `say "hi"`, want: `^"say \^"hi\^"^"`},
+		"cmd trailing backslash": {syntax: Cmd, arg: `C:\dir\`, want: `^"C:\dir\\^"`},
+		"cmd backslash quote":    {syntax: Cmd, arg: `a\"b`, want: `^"a\\\^"b^"`},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Shell{Syntax: tt.syntax}.Quote(tt.arg))
+		})
+	}
+}
+
+func TestCommandArgs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		shell Shell
+		want  []string
+	}{
+		"posix": {
+			shell: Shell{Name: "sh", Syntax: POSIX},
+			want:  []string{"sh", "-c", "echo hi"},
+		},
+		"powershell": {
+			shell: Shell{Name: "pwsh", Syntax: PowerShell},
+			want:  []string{"pwsh", "-NoProfile", "-NonInteractive", "-Command", "echo hi"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.shell.Command("echo hi").Args)
+		})
+	}
+}
+
+func TestCommandRunsQuotedArgs(t *testing.T) {
+	t.Parallel()
+
+	sh := Default()
+	arg := `it's "quoted" & 100% | <odd>`
+	out, err := sh.Command("echo " + sh.Quote(arg)).Output()
+	require.NoError(t, err)
+	want := arg
+	if sh.Syntax == Cmd {
+		// cmd's echo prints its arguments as written, quotes included.
+		want = `"it's \"quoted\" & 100% | <odd>"`
+	}
+	assert.Equal(t, want, strings.TrimRight(string(out), "\r\n"))
+}
+
+func TestScript(t *testing.T) {
+	t.Parallel()
+
+	shProgram, shInterpreted := "setup.sh", false
+	if runtime.GOOS == "windows" {
+		shProgram, shInterpreted = "sh", true
+	}
+
+	tests := map[string]struct {
+		path        string
+		wantProgram string
+		interpreted bool
+	}{
+		"powershell": {path: "setup.ps1", wantProgram: powerShell(), interpreted: true},
+		"sh":         {path: "setup.sh", wantProgram: shProgram, interpreted: shInterpreted},
+		"batch":      {path: "setup.cmd", wantProgram: "setup.cmd"},
+		"binary":     {path: "setup", wantProgram: "setup"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			cmd := Script(tt.path, "a b")
+			assert.Equal(t, tt.wantProgram, cmd.Args[0])
+			assert.Equal(t, "a b", cmd.Args[len(cmd.Args)-1])
+			assert.Equal(t, tt.interpreted, Interpreted(tt.path))
+		})
+	}
+}
+
+func TestScriptRunsNativeScript(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "setup.sh")
+	content := "#!/bin/sh\necho \"setup $1\"\n"
+	if runtime.GOOS == "windows" {
+		path = filepath.Join(dir, "setup.cmd")
+		content = "@echo setup %~1\r\n"
+	}
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o755))
+
+	out, err := Script(path, "worktree").Output()
+	require.NoError(t, err)
+	assert.Equal(t, "setup worktree", strings.TrimRight(string(out), "\r\n"))
+}
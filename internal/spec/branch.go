@@ -149,26 +149,25 @@ func TruncateBranchName(branchName string) string {
 	return truncated
 }
 
-// GetNextBranchNumber scans git branches and spec directories to find the next available number
-// It returns a zero-padded three-digit string (e.g., "004")
+// GetNextBranchNumber returns the number for the next feature in the
+// configured layout (see SetLayout). For numbered layouts it scans git
+// branches and spec directories and returns a zero-padded three-digit string
+// (e.g., "004"); dated layouts return today's date and flat layouts "".
 func GetNextBranchNumber(specsDir string) (string, error) {
+	return Locator().NextNumber(specsDir)
+}
+
+// nextSequenceNumber returns one more than the highest number among the spec
+// directories and git branches.
+func nextSequenceNumber(specDirs []string) (string, error) {
 	highest := 0
 
 	// Scan spec directories
-	if info, err := os.Stat(specsDir); err == nil && info.IsDir() {
-		entries, err := os.ReadDir(specsDir)
-		if err == nil {
-			for _, entry := range entries {
-				if !entry.IsDir() {
-					continue
-				}
-				name := entry.Name()
-				if match := branchNumberPattern.FindStringSubmatch(name); match != nil {
-					num, err := strconv.Atoi(match[1])
-					if err == nil && num > highest {
-						highest = num
-					}
-				}
+	for _, dir := range specDirs {
+		if match := branchNumberPattern.FindStringSubmatch(filepath.Base(dir)); match != nil {
+			num, err := strconv.Atoi(match[1])
+			if err == nil && num > highest {
+				highest = num
 			}
 		}
 	}
@@ -193,12 +192,17 @@ func GetNextBranchNumber(specsDir string) (string, error) {
 	return fmt.Sprintf("%03d", next), nil
 }
 
-// FormatBranchName creates a full branch name from a number and suffix
+// FormatBranchName creates a full branch name from a number and suffix.
+// Without a number (flat layout) the suffix is the branch name.
 func FormatBranchName(number, suffix string) string {
+	if number == "" {
+		return suffix
+	}
 	return fmt.Sprintf("%s-%s", number, suffix)
 }
 
-// GetFeatureDirectory returns the path to a feature's spec directory
-func GetFeatureDirectory(specsDir, branchName string) string {
-	return filepath.Join(specsDir, branchName)
+// GetFeatureDirectory returns the path to a feature's spec directory, placed
+// in category when the configured layout groups specs by category.
+func GetFeatureDirectory(specsDir, branchName, category string) string {
+	return filepath.Join(specsDir, Locator().Directory(branchName, category))
 }
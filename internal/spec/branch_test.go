@@ -328,7 +328,7 @@ func TestFormatBranchName(t *testing.T) {
 }
 
 func TestGetFeatureDirectory(t *testing.T) {
-	result := GetFeatureDirectory("/home/user/project/specs", "001-my-feature")
+	result := GetFeatureDirectory("/home/user/project/specs", "001-my-feature", "")
 	assert.Equal(t, "/home/user/project/specs/001-my-feature", result)
 }
 
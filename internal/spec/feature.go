@@ -0,0 +1,35 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Feature holds the fields of a spec.yaml feature section that autospec
+// reads outside of artifact validation.
+type Feature struct {
+	Created string `yaml:"created"`
+
+	// WorkDir is the directory the implement stage runs the agent in,
+	// relative to the project root (e.g., "services/api"). Empty means the
+	// project root.
+	WorkDir string `yaml:"workdir"`
+}
+
+// ReadFeature returns the feature section of the spec.yaml in specDir.
+// A missing or unparsable spec.yaml yields an empty Feature.
+func ReadFeature(specDir string) Feature {
+	data, err := os.ReadFile(filepath.Join(specDir, "spec.yaml"))
+	if err != nil {
+		return Feature{}
+	}
+	var doc struct {
+		Feature Feature `yaml:"feature"`
+	}
+	if yaml.Unmarshal(data, &doc) != nil {
+		return Feature{}
+	}
+	return doc.Feature
+}
@@ -0,0 +1,228 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SpecLocator maps spec directories to feature numbers and names for one
+// layout of the specs directory. Spec directories are identified by their
+// path relative to the specs directory (e.g., "002-go-binary-migration" or
+// "backend/002-auth"), which is also the spec name used by the workflow.
+type SpecLocator interface {
+	// Layout returns the layout name used in config (e.g., "numbered").
+	Layout() string
+
+	// List returns the spec directories in specsDir, relative to it and sorted.
+	List(specsDir string) []string
+
+	// Parse splits a relative spec directory into its number and name.
+	// It returns false when rel is not a spec directory in this layout.
+	Parse(rel string) (number, name string, ok bool)
+
+	// NextNumber returns the number for the next feature created in specsDir,
+	// or "" when the layout does not number features.
+	NextNumber(specsDir string) (string, error)
+
+	// Directory returns the relative directory of a new feature from its
+	// branch name and an optional category.
+	Directory(branchName, category string) string
+}
+
+// Layout names accepted by the specs_layout config key.
+const (
+	LayoutNumbered    = "numbered"    // specs/001-user-auth
+	LayoutDated       = "dated"       // specs/2025-01-15-user-auth
+	LayoutCategorized = "categorized" // specs/backend/001-user-auth
+	LayoutFlat        = "flat"        // specs/user-auth
+)
+
+// datedDirPattern matches directory names like "2025-01-15-user-auth"
+var datedDirPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-(.+)$`)
+
+var locators = map[string]SpecLocator{
+	LayoutNumbered:    numberedLocator{},
+	LayoutDated:       datedLocator{},
+	LayoutCategorized: categorizedLocator{},
+	LayoutFlat:        flatLocator{},
+}
+
+var currentLocator atomic.Pointer[SpecLocator]
+
+func init() {
+	locator := locators[LayoutNumbered]
+	currentLocator.Store(&locator)
+}
+
+// Layouts returns the supported layout names, sorted.
+func Layouts() []string {
+	names := make([]string, 0, len(locators))
+	for name := range locators {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LocatorFor returns the locator of a layout. An empty layout is numbered.
+func LocatorFor(layout string) (SpecLocator, error) {
+	if layout == "" {
+		layout = LayoutNumbered
+	}
+	locator, ok := locators[layout]
+	if !ok {
+		return nil, fmt.Errorf("unknown specs layout %q (valid: %s)", layout, strings.Join(Layouts(), ", "))
+	}
+	return locator, nil
+}
+
+// SetLayout selects the layout used by the package-level functions such as
+// DetectCurrentSpec and GetSpecMetadata. It is set from the specs_layout
+// config key when the config is loaded.
+func SetLayout(layout string) error {
+	locator, err := LocatorFor(layout)
+	if err != nil {
+		return err
+	}
+	currentLocator.Store(&locator)
+	return nil
+}
+
+// Locator returns the locator of the configured layout.
+func Locator() SpecLocator {
+	return *currentLocator.Load()
+}
+
+// numberedLocator is the default layout: NNN-name directories at the top of
+// the specs directory, numbered in sequence across directories and branches.
+type numberedLocator struct{}
+
+func (numberedLocator) Layout() string { return LayoutNumbered }
+
+func (l numberedLocator) List(specsDir string) []string {
+	return listDirs(specsDir, "", l.Parse)
+}
+
+func (numberedLocator) Parse(rel string) (string, string, bool) {
+	return parsePattern(specDirPattern, rel)
+}
+
+func (l numberedLocator) NextNumber(specsDir string) (string, error) {
+	return nextSequenceNumber(l.List(specsDir))
+}
+
+func (numberedLocator) Directory(branchName, _ string) string { return branchName }
+
+// datedLocator prefixes directories with their creation date instead of a
+// sequence number, which avoids number clashes between parallel branches.
+type datedLocator struct{}
+
+func (datedLocator) Layout() string { return LayoutDated }
+
+func (l datedLocator) List(specsDir string) []string {
+	return listDirs(specsDir, "", l.Parse)
+}
+
+func (datedLocator) Parse(rel string) (string, string, bool) {
+	return parsePattern(datedDirPattern, rel)
+}
+
+func (datedLocator) NextNumber(string) (string, error) {
+	return time.Now().Format("2006-01-02"), nil
+}
+
+func (datedLocator) Directory(branchName, _ string) string { return branchName }
+
+// categorizedLocator groups numbered directories in category folders, e.g.
+// specs/backend/001-auth. Numbers are unique across categories, and numbered
+// directories at the top level are still found.
+type categorizedLocator struct{}
+
+func (categorizedLocator) Layout() string { return LayoutCategorized }
+
+func (l categorizedLocator) List(specsDir string) []string {
+	specs := listDirs(specsDir, "", l.Parse)
+	entries, _ := os.ReadDir(specsDir)
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && !specDirPattern.MatchString(entry.Name()) {
+			specs = append(specs, listDirs(specsDir, entry.Name(), l.Parse)...)
+		}
+	}
+	sort.Strings(specs)
+	return specs
+}
+
+func (categorizedLocator) Parse(rel string) (string, string, bool) {
+	rel = filepath.ToSlash(rel)
+	if strings.Count(rel, "/") > 1 {
+		return "", "", false
+	}
+	return parsePattern(specDirPattern, rel[strings.LastIndex(rel, "/")+1:])
+}
+
+func (l categorizedLocator) NextNumber(specsDir string) (string, error) {
+	return nextSequenceNumber(l.List(specsDir))
+}
+
+func (categorizedLocator) Directory(branchName, category string) string {
+	if category == "" {
+		return branchName
+	}
+	return filepath.Join(category, branchName)
+}
+
+// flatLocator uses the feature name as the directory name, without a number.
+// Every directory that is not hidden is a spec.
+type flatLocator struct{}
+
+func (flatLocator) Layout() string { return LayoutFlat }
+
+func (l flatLocator) List(specsDir string) []string {
+	return listDirs(specsDir, "", l.Parse)
+}
+
+func (flatLocator) Parse(rel string) (string, string, bool) {
+	if rel == "" || strings.ContainsAny(rel, `/\`) || strings.HasPrefix(rel, ".") {
+		return "", "", false
+	}
+	return "", rel, true
+}
+
+func (flatLocator) NextNumber(string) (string, error) { return "", nil }
+
+func (flatLocator) Directory(branchName, _ string) string { return branchName }
+
+// listDirs returns the directories in specsDir/sub that parse as specs,
+// relative to specsDir.
+func listDirs(specsDir, sub string, parse func(string) (string, string, bool)) []string {
+	entries, err := os.ReadDir(filepath.Join(specsDir, sub))
+	if err != nil {
+		return nil
+	}
+	var specs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		rel := filepath.Join(sub, entry.Name())
+		if _, _, ok := parse(rel); ok {
+			specs = append(specs, rel)
+		}
+	}
+	return specs
+}
+
+// parsePattern splits a directory name with a number and name group.
+func parsePattern(pattern *regexp.Regexp, name string) (string, string, bool) {
+	match := pattern.FindStringSubmatch(name)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
@@ -0,0 +1,186 @@
+// Package spec_test tests the specs directory layouts.
+// Related: internal/spec/layout.go
+// Tags: spec, layout, locator, directory
+
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useLayout selects a layout for the rest of the test. Tests calling it must
+// not run in parallel, since the layout is package state.
+func useLayout(t *testing.T, layout string) {
+	t.Helper()
+	require.NoError(t, SetLayout(layout))
+	t.Cleanup(func() { _ = SetLayout(LayoutNumbered) })
+}
+
+func mkdirs(t *testing.T, root string, dirs ...string) {
+	t.Helper()
+	for _, dir := range dirs {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+}
+
+func TestLocator_Parse(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		layout     string
+		rel        string
+		wantNumber string
+		wantName   string
+		wantOK     bool
+	}{
+		"numbered":                  {layout: LayoutNumbered, rel: "001-user-auth", wantNumber: "001", wantName: "user-auth", wantOK: true},
+		"numbered rejects dates":    {layout: LayoutNumbered, rel: "2025-01-15-user-auth", wantOK: false},
+		"dated":                     {layout: LayoutDated, rel: "2025-01-15-user-auth", wantNumber: "2025-01-15", wantName: "user-auth", wantOK: true},
+		"dated rejects numbers":     {layout: LayoutDated, rel: "001-user-auth", wantOK: false},
+		"categorized":               {layout: LayoutCategorized, rel: filepath.Join("backend", "001-api"), wantNumber: "001", wantName: "api", wantOK: true},
+		"categorized top level":     {layout: LayoutCategorized, rel: "002-cli", wantNumber: "002", wantName: "cli", wantOK: true},
+		"categorized too deep":      {layout: LayoutCategorized, rel: filepath.Join("a", "b", "001-api"), wantOK: false},
+		"flat":                      {layout: LayoutFlat, rel: "user-auth", wantName: "user-auth", wantOK: true},
+		"flat rejects hidden":       {layout: LayoutFlat, rel: ".autospec", wantOK: false},
+		"flat rejects nested paths": {layout: LayoutFlat, rel: filepath.Join("a", "b"), wantOK: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			locator, err := LocatorFor(tt.layout)
+			require.NoError(t, err)
+
+			number, specName, ok := locator.Parse(tt.rel)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantNumber, number)
+			assert.Equal(t, tt.wantName, specName)
+		})
+	}
+}
+
+func TestLocatorFor(t *testing.T) {
+	t.Parallel()
+
+	locator, err := LocatorFor("")
+	require.NoError(t, err)
+	assert.Equal(t, LayoutNumbered, locator.Layout())
+
+	_, err = LocatorFor("nested")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "categorized, dated, flat, numbered")
+}
+
+func TestLayouts_Resolution(t *testing.T) {
+	tests := map[string]struct {
+		layout     string
+		dirs       []string
+		identifier string
+		wantDir    string
+		wantNumber string
+		wantName   string
+	}{
+		"dated by date": {
+			layout:     LayoutDated,
+			dirs:       []string{"2025-01-15-user-auth", "2025-02-01-billing", "notes"},
+			identifier: "2025-01-15",
+			wantDir:    "2025-01-15-user-auth",
+			wantNumber: "2025-01-15",
+			wantName:   "user-auth",
+		},
+		"categorized by number": {
+			layout:     LayoutCategorized,
+			dirs:       []string{filepath.Join("backend", "001-api"), filepath.Join("frontend", "002-dark-mode")},
+			identifier: "2",
+			wantDir:    filepath.Join("frontend", "002-dark-mode"),
+			wantNumber: "002",
+			wantName:   "dark-mode",
+		},
+		"categorized by name": {
+			layout:     LayoutCategorized,
+			dirs:       []string{filepath.Join("backend", "001-api"), "003-cli"},
+			identifier: "api",
+			wantDir:    filepath.Join("backend", "001-api"),
+			wantNumber: "001",
+			wantName:   "api",
+		},
+		"flat by name": {
+			layout:     LayoutFlat,
+			dirs:       []string{"user-auth", "billing"},
+			identifier: "billing",
+			wantDir:    "billing",
+			wantName:   "billing",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			useLayout(t, tt.layout)
+			specsDir := t.TempDir()
+			mkdirs(t, specsDir, tt.dirs...)
+
+			meta, err := GetSpecMetadata(specsDir, tt.identifier)
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Join(specsDir, tt.wantDir), meta.Directory)
+			assert.Equal(t, tt.wantNumber, meta.Number)
+			assert.Equal(t, tt.wantName, meta.Name)
+			assert.Equal(t, tt.wantDir, meta.DirName())
+		})
+	}
+}
+
+func TestLayouts_CategorizedBranch(t *testing.T) {
+	useLayout(t, LayoutCategorized)
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, filepath.Join("backend", "001-api"), filepath.Join("frontend", "002-dark-mode"))
+
+	// Branches are named after the spec directory, without the category
+	meta := SpecForBranch(specsDir, "001-api")
+	require.NotNil(t, meta)
+	assert.Equal(t, filepath.Join("backend", "001-api"), meta.DirName())
+
+	assert.Equal(t, []string{filepath.Join("backend", "001-api"), filepath.Join("frontend", "002-dark-mode")}, ListSpecNames(specsDir))
+}
+
+func TestLayouts_NextNumber(t *testing.T) {
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, filepath.Join("backend", "007-api"), "002-cli")
+
+	useLayout(t, LayoutCategorized)
+	num, err := GetNextBranchNumber(specsDir)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, num, "008", "numbers are unique across categories")
+
+	useLayout(t, LayoutDated)
+	num, err = GetNextBranchNumber(specsDir)
+	require.NoError(t, err)
+	assert.Equal(t, time.Now().Format("2006-01-02"), num)
+
+	useLayout(t, LayoutFlat)
+	num, err = GetNextBranchNumber(specsDir)
+	require.NoError(t, err)
+	assert.Empty(t, num)
+	assert.Equal(t, "user-auth", FormatBranchName(num, "user-auth"))
+}
+
+func TestGetFeatureDirectory_Category(t *testing.T) {
+	useLayout(t, LayoutCategorized)
+	assert.Equal(t, filepath.Join("specs", "backend", "001-api"), GetFeatureDirectory("specs", "001-api", "backend"))
+	assert.Equal(t, filepath.Join("specs", "001-api"), GetFeatureDirectory("specs", "001-api", ""))
+}
+
+func TestDetectCurrentSpec_LayoutMismatch(t *testing.T) {
+	useLayout(t, LayoutDated)
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, "001-user-auth")
+
+	_, err := DetectCurrentSpec(specsDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "specs_layout: dated")
+}
@@ -0,0 +1,202 @@
+package spec
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/git"
+)
+
+// Collision is a feature number used by more than one spec directory,
+// typically after merging branches that created specs concurrently.
+type Collision struct {
+	Number string
+	Specs  []string // relative spec directories, oldest first
+}
+
+// Renumbering moves a spec directory to a new feature number.
+type Renumbering struct {
+	From string // relative spec directory, e.g. "004-dark-mode"
+	To   string // relative spec directory, e.g. "006-dark-mode"
+}
+
+// RenumberResult describes an applied Renumbering.
+type RenumberResult struct {
+	Renumbering
+	Files  []string // files whose references were rewritten, relative to specsDir
+	Branch string   // renamed local git branch, or "" if none was renamed
+}
+
+// FindCollisions returns the feature numbers shared by several spec
+// directories in specsDir, sorted by number. Layouts without sequence
+// numbers never collide.
+func FindCollisions(specsDir string) []Collision {
+	locator := Locator()
+	byNumber := make(map[string][]string)
+	for _, rel := range locator.List(specsDir) {
+		number, _, _ := locator.Parse(rel)
+		if isSequenceNumber(number) {
+			byNumber[number] = append(byNumber[number], rel)
+		}
+	}
+
+	var collisions []Collision
+	for number, specs := range byNumber {
+		if len(specs) < 2 {
+			continue
+		}
+		sortByCreation(specsDir, specs)
+		collisions = append(collisions, Collision{Number: number, Specs: specs})
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i].Number < collisions[j].Number
+	})
+	return collisions
+}
+
+// PlanCollisionFixes returns the renumberings that resolve all collisions in
+// specsDir: the oldest spec of each collision keeps its number and the others
+// move to the next free numbers.
+func PlanCollisionFixes(specsDir string) []Renumbering {
+	collisions := FindCollisions(specsDir)
+	if len(collisions) == 0 {
+		return nil
+	}
+	locator := Locator()
+	next := 0
+	for _, rel := range locator.List(specsDir) {
+		number, _, _ := locator.Parse(rel)
+		if n, err := strconv.Atoi(number); err == nil && n > next {
+			next = n
+		}
+	}
+
+	var plan []Renumbering
+	for _, c := range collisions {
+		for _, rel := range c.Specs[1:] {
+			next++
+			_, name, _ := locator.Parse(rel)
+			to := filepath.Join(filepath.Dir(rel), FormatBranchName(fmt.Sprintf("%03d", next), name))
+			plan = append(plan, Renumbering{From: rel, To: to})
+		}
+	}
+	return plan
+}
+
+// Renumber moves the spec directory rel in specsDir to newNumber, rewrites
+// references to its old directory name in the YAML and markdown files under
+// specsDir, and renames the local git branch of the same name.
+func Renumber(specsDir, rel, newNumber string) (*RenumberResult, error) {
+	number, name, ok := Locator().Parse(rel)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a spec directory (specs_layout: %s)", rel, Locator().Layout())
+	}
+	if !isSequenceNumber(number) {
+		return nil, fmt.Errorf("spec %s has no sequence number to change (specs_layout: %s)", rel, Locator().Layout())
+	}
+	n, err := strconv.Atoi(newNumber)
+	if err != nil || n < 0 {
+		return nil, fmt.Errorf("invalid spec number %q: must be a positive integer", newNumber)
+	}
+
+	to := filepath.Join(filepath.Dir(rel), FormatBranchName(fmt.Sprintf("%03d", n), name))
+	if to == filepath.Clean(rel) {
+		return nil, fmt.Errorf("spec %s already has number %03d", rel, n)
+	}
+	if _, err := os.Stat(filepath.Join(specsDir, to)); err == nil {
+		return nil, fmt.Errorf("cannot renumber %s: %s already exists", rel, to)
+	}
+	if err := os.Rename(filepath.Join(specsDir, rel), filepath.Join(specsDir, to)); err != nil {
+		return nil, fmt.Errorf("renaming spec directory: %w", err)
+	}
+
+	result := &RenumberResult{Renumbering: Renumbering{From: rel, To: to}}
+	oldBase, newBase := filepath.Base(rel), filepath.Base(to)
+	if result.Files, err = rewriteReferences(specsDir, oldBase, newBase); err != nil {
+		return result, err
+	}
+	if hasLocalBranch(oldBase) {
+		if err := git.RenameBranch(oldBase, newBase); err != nil {
+			return result, err
+		}
+		result.Branch = newBase
+	}
+	return result, nil
+}
+
+// rewriteReferences replaces whole-word occurrences of oldName with newName
+// in the .yaml, .yml and .md files under specsDir.
+func rewriteReferences(specsDir, oldName, newName string) ([]string, error) {
+	pattern := regexp.MustCompile(`(^|[^A-Za-z0-9_-])` + regexp.QuoteMeta(oldName) + `($|[^A-Za-z0-9_-])`)
+	var changed []string
+	err := filepath.WalkDir(specsDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".md":
+		default:
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		content := string(data)
+		// Adjacent matches share a separator, so repeat until none are left
+		for updated := content; ; {
+			updated = pattern.ReplaceAllString(updated, "${1}"+newName+"${2}")
+			if updated == content {
+				break
+			}
+			content = updated
+		}
+		if content == string(data) {
+			return nil
+		}
+		if err := fsutil.WriteFile(path, []byte(content), 0o644); err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(specsDir, path)
+		changed = append(changed, rel)
+		return nil
+	})
+	if err != nil {
+		return changed, fmt.Errorf("rewriting references to %s: %w", oldName, err)
+	}
+	return changed, nil
+}
+
+// hasLocalBranch reports whether a local git branch with the given name exists.
+func hasLocalBranch(name string) bool {
+	branches, err := git.GetAllBranches()
+	if err != nil {
+		return false
+	}
+	for _, b := range branches {
+		if !b.IsRemote && b.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sortByCreation orders spec directories by the feature.created date in
+// their spec.yaml, oldest first. Specs without a date sort last, and ties
+// keep their existing (path) order.
+func sortByCreation(specsDir string, specs []string) {
+	created := make(map[string]string, len(specs))
+	for _, rel := range specs {
+		created[rel] = ReadFeature(filepath.Join(specsDir, rel)).Created
+	}
+	sort.SliceStable(specs, func(i, j int) bool {
+		a, b := created[specs[i]], created[specs[j]]
+		return a != "" && (b == "" || a < b)
+	})
+}
@@ -0,0 +1,159 @@
+// Package spec_test tests spec number collisions and renumbering.
+// Related: internal/spec/renumber.go, internal/spec/reserve.go
+// Tags: spec, renumber, collision, reservation
+
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeSpecYAML(t *testing.T, dir, created string) {
+	t.Helper()
+	content := "feature:\n  branch: \"" + filepath.Base(dir) + "\"\n  created: \"" + created + "\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "spec.yaml"), []byte(content), 0o644))
+}
+
+func TestFindCollisions(t *testing.T) {
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, "001-auth", "002-dark-mode", "002-rate-limit", "003-export")
+	writeSpecYAML(t, filepath.Join(specsDir, "002-dark-mode"), "2025-03-02")
+	writeSpecYAML(t, filepath.Join(specsDir, "002-rate-limit"), "2025-03-01")
+
+	collisions := FindCollisions(specsDir)
+
+	require.Len(t, collisions, 1)
+	assert.Equal(t, "002", collisions[0].Number)
+	assert.Equal(t, []string{"002-rate-limit", "002-dark-mode"}, collisions[0].Specs,
+		"older spec should come first")
+}
+
+func TestFindCollisions_UndatedSortLast(t *testing.T) {
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, "004-alpha", "004-beta", "004-gamma")
+	writeSpecYAML(t, filepath.Join(specsDir, "004-gamma"), "2025-01-01")
+
+	collisions := FindCollisions(specsDir)
+
+	require.Len(t, collisions, 1)
+	assert.Equal(t, []string{"004-gamma", "004-alpha", "004-beta"}, collisions[0].Specs)
+}
+
+func TestFindCollisions_Categorized(t *testing.T) {
+	useLayout(t, LayoutCategorized)
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, "backend/003-api", "frontend/003-theme", "004-docs")
+
+	collisions := FindCollisions(specsDir)
+
+	require.Len(t, collisions, 1)
+	assert.Equal(t, []string{"backend/003-api", "frontend/003-theme"}, collisions[0].Specs)
+}
+
+func TestFindCollisions_DatedNeverCollide(t *testing.T) {
+	useLayout(t, LayoutDated)
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, "2025-01-15-auth", "2025-01-15-export")
+
+	assert.Empty(t, FindCollisions(specsDir))
+}
+
+func TestPlanCollisionFixes(t *testing.T) {
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, "001-auth", "001-billing", "002-export", "002-import", "002-search", "005-theme")
+
+	plan := PlanCollisionFixes(specsDir)
+
+	assert.Equal(t, []Renumbering{
+		{From: "001-billing", To: "006-billing"},
+		{From: "002-import", To: "007-import"},
+		{From: "002-search", To: "008-search"},
+	}, plan)
+}
+
+func TestRenumber(t *testing.T) {
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, "002-dark-mode", "003-theme/checklists")
+	writeSpecYAML(t, filepath.Join(specsDir, "002-dark-mode"), "2025-03-02")
+	other := filepath.Join(specsDir, "003-theme", "plan.md")
+	require.NoError(t, os.WriteFile(other,
+		[]byte("Builds on 002-dark-mode and specs/002-dark-mode/spec.yaml, not 002-dark-mode-v2 or x002-dark-mode.\n002-dark-mode 002-dark-mode"), 0o644))
+	untouched := filepath.Join(specsDir, "003-theme", "notes.txt")
+	require.NoError(t, os.WriteFile(untouched, []byte("002-dark-mode"), 0o644))
+
+	result, err := Renumber(specsDir, "002-dark-mode", "7")
+	require.NoError(t, err)
+
+	assert.Equal(t, "007-dark-mode", result.To)
+	assert.DirExists(t, filepath.Join(specsDir, "007-dark-mode"))
+	assert.NoDirExists(t, filepath.Join(specsDir, "002-dark-mode"))
+	assert.ElementsMatch(t, []string{"007-dark-mode/spec.yaml", "003-theme/plan.md"}, result.Files)
+
+	data, err := os.ReadFile(other)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"Builds on 007-dark-mode and specs/007-dark-mode/spec.yaml, not 002-dark-mode-v2 or x002-dark-mode.\n007-dark-mode 007-dark-mode",
+		string(data))
+	data, err = os.ReadFile(untouched)
+	require.NoError(t, err)
+	assert.Equal(t, "002-dark-mode", string(data), "only yaml and markdown files are rewritten")
+}
+
+func TestRenumber_Errors(t *testing.T) {
+	specsDir := t.TempDir()
+	mkdirs(t, specsDir, "002-dark-mode", "004-dark-mode")
+
+	tests := map[string]struct {
+		rel     string
+		number  string
+		wantErr string
+	}{
+		"not a spec":     {rel: "notes", number: "3", wantErr: "not a spec directory"},
+		"invalid number": {rel: "002-dark-mode", number: "abc", wantErr: "invalid spec number"},
+		"same number":    {rel: "002-dark-mode", number: "2", wantErr: "already has number 002"},
+		"target exists":  {rel: "002-dark-mode", number: "4", wantErr: "004-dark-mode already exists"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Renumber(specsDir, tt.rel, tt.number)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLockReservation(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), ReservationFileName+".lock")
+
+	unlock, err := lockReservation(path)
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+	unlock()
+	assert.NoFileExists(t, path)
+
+	// A lock left behind by a crashed process is reclaimed
+	require.NoError(t, os.WriteFile(path, []byte("1\n"), 0o644))
+	old := time.Now().Add(-2 * reservationLockStale)
+	require.NoError(t, os.Chtimes(path, old, old))
+	unlock, err = lockReservation(path)
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestReadCounter(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, ReservationFileName)
+
+	assert.Equal(t, 0, readCounter(path))
+	require.NoError(t, os.WriteFile(path, []byte("12\n"), 0o644))
+	assert.Equal(t, 12, readCounter(path))
+}
@@ -0,0 +1,155 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/git"
+)
+
+// ReservationFileName is the counter file, in the git common directory, that
+// records the highest spec number handed out in the repository. It is shared
+// by all worktrees, so parallel worktrees never get the same number.
+const ReservationFileName = "autospec-spec-number"
+
+const (
+	reservationLockTimeout = 5 * time.Second
+	reservationLockStale   = 30 * time.Second
+)
+
+// ReserveNumber returns the number for the next feature created in specsDir
+// and records it so no other worktree or branch of the repository gets it.
+//
+// Besides the local spec directories and branch names, it considers spec
+// directories committed on every local and remote-tracking branch and the
+// reservation counter. Outside a git repository, and for layouts that do not
+// number features in sequence, it returns the layout's next number unchanged.
+func ReserveNumber(specsDir string) (string, error) {
+	locator := Locator()
+	local, err := locator.NextNumber(specsDir)
+	if err != nil || !isSequenceNumber(local) || !git.IsGitRepository() {
+		return local, err
+	}
+	commonDir, err := git.GetCommonDir()
+	if err != nil {
+		return local, nil
+	}
+
+	unlock, err := lockReservation(filepath.Join(commonDir, ReservationFileName+".lock"))
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	next, _ := strconv.Atoi(local)
+	next = max(next, highestCommittedNumber(specsDir, locator)+1)
+	counterPath := filepath.Join(commonDir, ReservationFileName)
+	next = max(next, readCounter(counterPath)+1)
+
+	if err := os.WriteFile(counterPath, []byte(strconv.Itoa(next)+"\n"), 0o644); err != nil {
+		return "", fmt.Errorf("recording reserved spec number: %w", err)
+	}
+	return fmt.Sprintf("%03d", next), nil
+}
+
+// highestCommittedNumber returns the highest spec number committed under
+// specsDir on any local or remote-tracking branch, or 0 if there is none.
+func highestCommittedNumber(specsDir string, locator SpecLocator) int {
+	relDir, ok := repoRelative(specsDir)
+	if !ok {
+		return 0
+	}
+	refs, err := git.ListBranchRefs()
+	if err != nil {
+		return 0
+	}
+	highest := 0
+	for _, ref := range refs {
+		dirs, err := git.ListTreeDirs(ref, relDir)
+		if err != nil {
+			continue
+		}
+		for _, dir := range dirs {
+			number, _, ok := locator.Parse(strings.TrimPrefix(dir, relDir+"/"))
+			if n, err := strconv.Atoi(number); ok && err == nil && n > highest {
+				highest = n
+			}
+		}
+	}
+	return highest
+}
+
+// repoRelative returns specsDir relative to the repository root, with
+// forward slashes, or false if it lies outside the repository.
+func repoRelative(specsDir string) (string, bool) {
+	root, err := git.GetRepositoryRoot()
+	if err != nil {
+		return "", false
+	}
+	abs, err := filepath.Abs(specsDir)
+	if err != nil {
+		return "", false
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// readCounter returns the number stored in the reservation counter, or 0.
+func readCounter(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(data)))
+	return n
+}
+
+// lockReservation takes the reservation lock file, waiting for another
+// process holding it. A lock older than reservationLockStale was left by a
+// crashed process and is removed.
+func lockReservation(path string) (func(), error) {
+	deadline := time.Now().Add(reservationLockTimeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("locking spec number reservation: %w", err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > reservationLockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for spec number reservation lock %s", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// isSequenceNumber reports whether a feature number is a plain sequence
+// number such as "007", as opposed to a date or no number at all.
+func isSequenceNumber(number string) bool {
+	if number == "" {
+		return false
+	}
+	_, err := strconv.Atoi(number)
+	return err == nil
+}
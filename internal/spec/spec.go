@@ -6,21 +6,22 @@ package spec
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/fuzzy"
 	"github.com/ariel-frischer/autospec/internal/git"
 	"gopkg.in/yaml.v3"
 )
 
-var (
-	// specBranchPattern matches branch names like "002-go-binary-migration"
-	specBranchPattern = regexp.MustCompile(`^(\d{3})-(.+)$`)
-	// specDirPattern matches directory names like "002-go-binary-migration"
-	specDirPattern = regexp.MustCompile(`^(\d{3})-(.+)$`)
-)
+// specDirPattern matches directory names like "002-go-binary-migration"
+var specDirPattern = regexp.MustCompile(`^(\d{3})-(.+)$`)
 
 // DetectionMethod indicates how the spec was detected
 type DetectionMethod string
@@ -43,6 +44,33 @@ type Metadata struct {
 	Directory string          // Full path to spec directory
 	Branch    string          // Git branch name (if in git repo)
 	Detection DetectionMethod // How the spec was detected
+
+	dirName string // Directory relative to the specs directory
+}
+
+// DirName returns the spec directory relative to the specs directory, e.g.
+// "002-go-binary-migration" or "backend/002-auth" in the categorized layout.
+// This is the spec name the workflow joins with the specs directory.
+func (m *Metadata) DirName() string {
+	if m.dirName != "" {
+		return m.dirName
+	}
+	return FormatBranchName(m.Number, m.Name)
+}
+
+// newMetadata parses a spec directory relative to specsDir with the
+// configured layout.
+func newMetadata(specsDir, rel string) (*Metadata, bool) {
+	number, name, ok := Locator().Parse(rel)
+	if !ok {
+		return nil, false
+	}
+	return &Metadata{
+		Number:    number,
+		Name:      name,
+		Directory: filepath.Join(specsDir, rel),
+		dirName:   rel,
+	}, true
 }
 
 // FormatInfo returns a formatted string showing the detected spec with detection method.
@@ -67,7 +95,7 @@ func (m *Metadata) FormatInfo() string {
 // DetectCurrentSpec attempts to detect the current spec from git branch or directory.
 //
 // Two-strategy detection:
-//  1. Git branch: Parse branch name matching "NNN-name" pattern, verify directory exists
+//  1. Git branch: Match the branch to a spec directory (see SpecForBranch)
 //  2. Fallback: Glob all spec directories, sort by modification time, return most recent
 //
 // Strategy 1 provides branch-based workflow; Strategy 2 handles detached HEAD or non-git.
@@ -75,36 +103,17 @@ func (m *Metadata) FormatInfo() string {
 func DetectCurrentSpec(specsDir string) (*Metadata, error) {
 	// Strategy 1: Try git branch name
 	if git.IsGitRepository() {
-		branch, err := git.GetCurrentBranch()
-		if err == nil {
-			if match := specBranchPattern.FindStringSubmatch(branch); match != nil {
-				number := match[1]
-				name := match[2]
-				directory := filepath.Join(specsDir, fmt.Sprintf("%s-%s", number, name))
-
-				// Verify the directory exists
-				if _, err := os.Stat(directory); err == nil {
-					return &Metadata{
-						Number:    number,
-						Name:      name,
-						Directory: directory,
-						Branch:    branch,
-						Detection: DetectionGitBranch,
-					}, nil
-				}
+		if branch, err := git.GetCurrentBranch(); err == nil {
+			if metadata := SpecForBranch(specsDir, branch); metadata != nil {
+				return metadata, nil
 			}
 		}
 	}
 
 	// Strategy 2: Find most recently modified spec directory
-	pattern := filepath.Join(specsDir, "*-*")
-	matches, err := filepath.Glob(pattern)
-	if err != nil {
-		return nil, fmt.Errorf("failed to glob spec directories: %w", err)
-	}
-
+	matches := Locator().List(specsDir)
 	if len(matches) == 0 {
-		return nil, fmt.Errorf("no spec directories found in %s", specsDir)
+		return nil, noSpecsError(specsDir)
 	}
 
 	// Sort by modification time (most recent first)
@@ -115,7 +124,7 @@ func DetectCurrentSpec(specsDir string) (*Metadata, error) {
 
 	var dirs []dirInfo
 	for _, match := range matches {
-		info, err := os.Stat(match)
+		info, err := os.Stat(filepath.Join(specsDir, match))
 		if err != nil || !info.IsDir() {
 			continue
 		}
@@ -130,30 +139,89 @@ func DetectCurrentSpec(specsDir string) (*Metadata, error) {
 		return dirs[i].modTime.After(dirs[j].modTime)
 	})
 
-	// Parse the most recent directory
-	mostRecent := dirs[0].path
-	baseName := filepath.Base(mostRecent)
-	if match := specDirPattern.FindStringSubmatch(baseName); match != nil {
-		return &Metadata{
-			Number:    match[1],
-			Name:      match[2],
-			Directory: mostRecent,
-			Branch:    "",
-			Detection: DetectionFallbackRecent,
-		}, nil
+	metadata, _ := newMetadata(specsDir, dirs[0].path)
+	metadata.Detection = DetectionFallbackRecent
+	return metadata, nil
+}
+
+// noSpecsError explains why specsDir has no spec directories: it is empty,
+// or its directories do not follow the configured layout.
+func noSpecsError(specsDir string) error {
+	entries, _ := os.ReadDir(specsDir)
+	for _, entry := range entries {
+		if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") {
+			return fmt.Errorf("could not parse spec directory name: %s (specs_layout: %s)", entry.Name(), Locator().Layout())
+		}
 	}
+	return fmt.Errorf("no spec directories found in %s", specsDir)
+}
 
-	return nil, fmt.Errorf("could not parse spec directory name: %s", baseName)
+// SpecForBranch returns the spec directory that belongs to a git branch, or
+// nil when none does. A branch matches when its name, or its last path segment
+// ("feature/004-user-auth"), is a spec directory name, or when it equals the
+// branch field in a spec's spec.yaml. The latter covers branches whose names
+// differ from the spec directory.
+func SpecForBranch(specsDir, branch string) *Metadata {
+	if branch == "" {
+		return nil
+	}
+
+	names := ListSpecNames(specsDir)
+	for _, candidate := range []string{branch, path.Base(branch)} {
+		for _, name := range names {
+			// Categorized specs match on their directory name alone
+			if name == filepath.FromSlash(candidate) || filepath.Base(name) == candidate {
+				return branchMetadata(specsDir, name, branch)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if readSpecBranch(filepath.Join(specsDir, name)) == branch {
+			return branchMetadata(specsDir, name, branch)
+		}
+	}
+	return nil
+}
+
+// branchMetadata returns the metadata of a spec found from a git branch.
+func branchMetadata(specsDir, name, branch string) *Metadata {
+	metadata, _ := newMetadata(specsDir, name)
+	metadata.Branch = branch
+	metadata.Detection = DetectionGitBranch
+	return metadata
+}
+
+// readSpecBranch returns feature.branch from specDir/spec.yaml, or "" when the
+// file is missing, unparseable or has no branch.
+func readSpecBranch(specDir string) string {
+	data, err := os.ReadFile(filepath.Join(specDir, "spec.yaml"))
+	if err != nil {
+		return ""
+	}
+	var doc struct {
+		Feature struct {
+			Branch string `yaml:"branch"`
+		} `yaml:"feature"`
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return ""
+	}
+	return doc.Feature.Branch
 }
 
 // GetSpecDirectory returns the full path to a spec directory given its number or name.
 //
-// Three-level matching (tries in order, returns first match):
+// Four-level matching (tries in order, returns first match):
 //  1. Exact: "002-migration" → specs/002-migration
-//  2. Number: "002" → specs/002-* (glob, must be unique)
-//  3. Name: "migration" → specs/*-migration (glob, must be unique)
+//  2. Number: "002" or bare "2" → specs/002-* (must be unique)
+//  3. Name: "migration" → specs/*-migration (must be unique)
+//  4. Substring: "migr" → the one spec whose directory name contains it
 //
-// Returns error if multiple matches found (ambiguous) or no matches.
+// Numbers and names are those of the configured layout (see SetLayout), so
+// "2025-01-15" finds a dated spec and "auth" finds specs/backend/001-auth.
+// Returns error if multiple matches found (ambiguous) or no matches. When
+// nothing matches, the error suggests close spec names ("did you mean ...?").
 func GetSpecDirectory(specsDir, specIdentifier string) (string, error) {
 	// Try exact match first (e.g., "002-go-binary-migration")
 	exactPath := filepath.Join(specsDir, specIdentifier)
@@ -161,27 +229,30 @@ func GetSpecDirectory(specsDir, specIdentifier string) (string, error) {
 		return exactPath, nil
 	}
 
-	// Try number match (e.g., "002" -> "002-*")
-	if regexp.MustCompile(`^\d{3}$`).MatchString(specIdentifier) {
-		pattern := filepath.Join(specsDir, specIdentifier+"-*")
-		matches, err := filepath.Glob(pattern)
-		if err != nil {
-			return "", fmt.Errorf("failed to glob spec directory: %w", err)
-		}
-		if len(matches) == 1 {
-			return matches[0], nil
-		}
-		if len(matches) > 1 {
-			return "", fmt.Errorf("multiple specs found for number %s: %v", specIdentifier, matches)
-		}
+	names := ListSpecNames(specsDir)
+	locator := Locator()
+
+	// Try number match (e.g., "002" -> "002-*", "2" -> "002-*")
+	number := specIdentifier
+	if n, err := strconv.Atoi(specIdentifier); err == nil && n >= 0 {
+		number = fmt.Sprintf("%03d", n)
+	}
+	matches := matchSpecs(specsDir, names, func(rel string) bool {
+		num, _, _ := locator.Parse(rel)
+		return num != "" && num == number
+	})
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return "", fmt.Errorf("multiple specs found for number %s: %v", number, matches)
 	}
 
 	// Try name match (e.g., "go-binary-migration" -> "*-go-binary-migration")
-	pattern := filepath.Join(specsDir, "*-"+specIdentifier)
-	matches, err := filepath.Glob(pattern)
-	if err != nil {
-		return "", fmt.Errorf("failed to glob spec directory: %w", err)
-	}
+	matches = matchSpecs(specsDir, names, func(rel string) bool {
+		_, name, _ := locator.Parse(rel)
+		return name == specIdentifier
+	})
 	if len(matches) == 1 {
 		return matches[0], nil
 	}
@@ -189,9 +260,37 @@ func GetSpecDirectory(specsDir, specIdentifier string) (string, error) {
 		return "", fmt.Errorf("multiple specs found for name %s: %v", specIdentifier, matches)
 	}
 
+	// Try substring match (e.g., "binary" -> "002-go-binary-migration")
+	if contained := fuzzy.Contains(specIdentifier, names); len(contained) == 1 {
+		return filepath.Join(specsDir, contained[0]), nil
+	} else if len(contained) > 1 {
+		return "", fmt.Errorf("multiple specs found for %s: %v", specIdentifier, contained)
+	}
+
+	if hint := fuzzy.DidYouMean(fuzzy.Suggest(specIdentifier, names)); hint != "" {
+		return "", fmt.Errorf("spec directory not found for identifier: %s (%s)", specIdentifier, hint)
+	}
 	return "", fmt.Errorf("spec directory not found for identifier: %s", specIdentifier)
 }
 
+// matchSpecs returns the paths of the named specs for which match is true.
+func matchSpecs(specsDir string, names []string, match func(rel string) bool) []string {
+	var matches []string
+	for _, name := range names {
+		if match(name) {
+			matches = append(matches, filepath.Join(specsDir, name))
+		}
+	}
+	return matches
+}
+
+// ListSpecNames returns the spec directories in specsDir in the configured
+// layout, relative to specsDir (e.g., "002-go-binary-migration" or
+// "backend/002-auth"), sorted. It returns nil when specsDir cannot be read.
+func ListSpecNames(specsDir string) []string {
+	return Locator().List(specsDir)
+}
+
 // GetSpecMetadata returns metadata for a given spec identifier
 func GetSpecMetadata(specsDir, specIdentifier string) (*Metadata, error) {
 	directory, err := GetSpecDirectory(specsDir, specIdentifier)
@@ -199,26 +298,25 @@ func GetSpecMetadata(specsDir, specIdentifier string) (*Metadata, error) {
 		return nil, err
 	}
 
-	// Parse directory name to extract number and name
-	baseName := filepath.Base(directory)
-	if match := specDirPattern.FindStringSubmatch(baseName); match != nil {
-		metadata := &Metadata{
-			Number:    match[1],
-			Name:      match[2],
-			Directory: directory,
-		}
+	// Parse the directory relative to specsDir to extract number and name
+	rel, err := filepath.Rel(specsDir, directory)
+	if err != nil {
+		rel = filepath.Base(directory)
+	}
+	metadata, ok := newMetadata(specsDir, rel)
+	if !ok {
+		return nil, fmt.Errorf("could not parse spec directory name: %s", filepath.Base(directory))
+	}
+	metadata.Directory = directory
 
-		// Try to get branch if in git repo
-		if git.IsGitRepository() {
-			if branch, err := git.GetCurrentBranch(); err == nil {
-				metadata.Branch = branch
-			}
+	// Try to get branch if in git repo
+	if git.IsGitRepository() {
+		if branch, err := git.GetCurrentBranch(); err == nil {
+			metadata.Branch = branch
 		}
-
-		return metadata, nil
 	}
 
-	return nil, fmt.Errorf("could not parse spec directory name: %s", baseName)
+	return metadata, nil
 }
 
 // UpdateResult contains the result of a spec status update
@@ -271,7 +369,7 @@ func UpdateSpecStatus(specDir string, newStatus string, completedAt time.Time) (
 		return nil, fmt.Errorf("failed to serialize spec.yaml: %w", err)
 	}
 
-	if err := os.WriteFile(specPath, output, 0o644); err != nil {
+	if err := fsutil.WriteArtifact(specPath, output, 0o644); err != nil {
 		return nil, fmt.Errorf("failed to write spec.yaml: %w", err)
 	}
 
@@ -406,3 +504,59 @@ func updateFeatureStatus(node *yaml.Node, newStatus string, completedAt time.Tim
 
 	return result, nil
 }
+
+// SetInputSource records where feature.input came from (e.g. the URL of an
+// imported issue) as feature.input_source in spec.yaml, right after input.
+func SetInputSource(specDir, source string) error {
+	specPath := filepath.Join(specDir, "spec.yaml")
+	data, err := os.ReadFile(specPath)
+	if err != nil {
+		return fmt.Errorf("failed to read spec.yaml: %w", err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("failed to parse spec.yaml: %w", err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("spec.yaml is not a mapping")
+	}
+
+	var featureNode *yaml.Node
+	top := root.Content[0]
+	for i := 0; i < len(top.Content)-1; i += 2 {
+		if top.Content[i].Value == "feature" {
+			featureNode = top.Content[i+1]
+			break
+		}
+	}
+	if featureNode == nil || featureNode.Kind != yaml.MappingNode {
+		return fmt.Errorf("feature section not found in spec.yaml")
+	}
+
+	insertIdx, found := len(featureNode.Content), false
+	for i := 0; i < len(featureNode.Content)-1; i += 2 {
+		switch featureNode.Content[i].Value {
+		case "input_source":
+			featureNode.Content[i+1].Value = source
+			found = true
+		case "input":
+			insertIdx = i + 2
+		}
+	}
+	if !found {
+		pair := []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "input_source"},
+			{Kind: yaml.ScalarNode, Value: source},
+		}
+		featureNode.Content = append(featureNode.Content[:insertIdx], append(pair, featureNode.Content[insertIdx:]...)...)
+	}
+
+	output, err := yaml.Marshal(&root)
+	if err != nil {
+		return fmt.Errorf("failed to serialize spec.yaml: %w", err)
+	}
+	if err := fsutil.WriteArtifact(specPath, output, 0o644); err != nil {
+		return fmt.Errorf("failed to write spec.yaml: %w", err)
+	}
+	return nil
+}
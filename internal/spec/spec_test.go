@@ -84,6 +84,45 @@ func TestDetectCurrentSpec_NoSpecsFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "no spec directories found")
 }
 
+func TestSpecForBranch(t *testing.T) {
+	t.Parallel()
+
+	specsDir := t.TempDir()
+	authDir := filepath.Join(specsDir, "004-user-auth")
+	billingDir := filepath.Join(specsDir, "007-billing")
+	require.NoError(t, os.MkdirAll(authDir, 0o755))
+	require.NoError(t, os.MkdirAll(billingDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(billingDir, "spec.yaml"),
+		[]byte("feature:\n  branch: \"feat/invoices\"\n  status: Draft\n"), 0o644))
+
+	tests := map[string]struct {
+		branch  string
+		wantDir string
+	}{
+		"branch equals directory name": {branch: "004-user-auth", wantDir: authDir},
+		"branch with path prefix":      {branch: "feature/004-user-auth", wantDir: authDir},
+		"branch field in spec.yaml":    {branch: "feat/invoices", wantDir: billingDir},
+		"unrelated branch":             {branch: "main"},
+		"numbered branch without dir":  {branch: "009-missing"},
+		"empty branch":                 {branch: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			meta := SpecForBranch(specsDir, tt.branch)
+			if tt.wantDir == "" {
+				assert.Nil(t, meta)
+				return
+			}
+			require.NotNil(t, meta)
+			assert.Equal(t, tt.wantDir, meta.Directory)
+			assert.Equal(t, tt.branch, meta.Branch)
+			assert.Equal(t, DetectionGitBranch, meta.Detection)
+		})
+	}
+}
+
 func TestGetSpecDirectory_ExactMatch(t *testing.T) {
 	t.Parallel()
 
@@ -150,6 +189,69 @@ func TestGetSpecDirectory_MultipleMatches(t *testing.T) {
 	assert.Contains(t, err.Error(), "multiple specs found")
 }
 
+func TestGetSpecDirectory_BareNumber(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	specDir := filepath.Join(specsDir, "004-user-auth")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+
+	for _, id := range []string{"4", "04", "004"} {
+		result, err := GetSpecDirectory(specsDir, id)
+		require.NoError(t, err, id)
+		assert.Equal(t, specDir, result, id)
+	}
+}
+
+func TestGetSpecDirectory_Substring(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	specDir := filepath.Join(specsDir, "004-user-auth")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "005-user-profile"), 0o755))
+
+	result, err := GetSpecDirectory(specsDir, "auth")
+	require.NoError(t, err)
+	assert.Equal(t, specDir, result)
+
+	_, err = GetSpecDirectory(specsDir, "user")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "multiple specs found for user")
+}
+
+func TestGetSpecDirectory_SuggestsCloseNames(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	specsDir := filepath.Join(tmpDir, "specs")
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "004-user-auth"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "012-billing"), 0o755))
+
+	_, err := GetSpecDirectory(specsDir, "user-auht")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "did you mean 004-user-auth?")
+
+	_, err = GetSpecDirectory(specsDir, "payments")
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "did you mean")
+}
+
+func TestListSpecNames(t *testing.T) {
+	t.Parallel()
+
+	specsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "002-beta"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "001-alpha"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "notes"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specsDir, "003-file"), nil, 0o644))
+
+	assert.Equal(t, []string{"001-alpha", "002-beta"}, ListSpecNames(specsDir))
+	assert.Nil(t, ListSpecNames(filepath.Join(specsDir, "missing")))
+}
+
 func TestUpdateSpecStatus(t *testing.T) {
 	t.Parallel()
 
@@ -613,3 +715,27 @@ func TestUpdateSpecStatus_FeatureNotMapping(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "feature section is not a mapping")
 }
+
+func TestSetInputSource(t *testing.T) {
+	t.Parallel()
+
+	specDir := t.TempDir()
+	content := `feature:
+  branch: "001-test"
+  status: "Draft"
+  input: "Add login"
+  created: "2025-01-01"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(content), 0o644))
+
+	require.NoError(t, SetInputSource(specDir, "https://github.com/o/r/issues/1"))
+	require.NoError(t, SetInputSource(specDir, "https://github.com/o/r/issues/2"))
+
+	data, err := os.ReadFile(filepath.Join(specDir, "spec.yaml"))
+	require.NoError(t, err)
+	got := string(data)
+	assert.Equal(t, 1, strings.Count(got, "input_source:"))
+	assert.Contains(t, got, "input: \"Add login\"\n    input_source: https://github.com/o/r/issues/2\n    created:")
+
+	assert.Error(t, SetInputSource(t.TempDir(), "x"), "missing spec.yaml")
+}
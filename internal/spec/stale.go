@@ -0,0 +1,126 @@
+package spec
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// StaleFileName is the marker file in a spec directory that lists artifacts
+// which are out of date with spec.yaml (written by 'autospec amend').
+const StaleFileName = ".stale.yaml"
+
+// StaleArtifact records a downstream artifact that needs regenerating.
+type StaleArtifact struct {
+	Artifact string    `yaml:"artifact"` // File name, e.g. "plan.yaml"
+	Stage    string    `yaml:"stage"`    // Stage that regenerates it, e.g. "plan"
+	Reasons  []string  `yaml:"reasons"`  // Spec changes since the artifact was generated
+	Since    time.Time `yaml:"since"`    // When the artifact first became stale
+}
+
+// staleMarker is the on-disk format of StaleFileName.
+type staleMarker struct {
+	Artifacts []StaleArtifact `yaml:"artifacts"`
+}
+
+// downstreamArtifacts lists the artifacts derived from spec.yaml, in stage order.
+var downstreamArtifacts = []struct{ artifact, stage string }{
+	{"plan.yaml", "plan"},
+	{"tasks.yaml", "tasks"},
+}
+
+// MarkDownstreamStale marks the plan.yaml and tasks.yaml that exist in specDir
+// as stale because of reason. Artifacts already marked keep their Since time
+// and gain the new reason. Returns all stale artifacts after the update.
+func MarkDownstreamStale(specDir, reason string) ([]StaleArtifact, error) {
+	stale, err := LoadStaleArtifacts(specDir)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, d := range downstreamArtifacts {
+		if _, err := os.Stat(filepath.Join(specDir, d.artifact)); err != nil {
+			continue
+		}
+		if existing := findStale(stale, d.artifact); existing != nil {
+			existing.Reasons = append(existing.Reasons, reason)
+			continue
+		}
+		stale = append(stale, StaleArtifact{Artifact: d.artifact, Stage: d.stage, Reasons: []string{reason}, Since: now})
+	}
+
+	if err := writeStaleMarker(specDir, stale); err != nil {
+		return nil, err
+	}
+	return stale, nil
+}
+
+// LoadStaleArtifacts returns the stale artifacts recorded in specDir.
+// A missing marker file means nothing is stale.
+func LoadStaleArtifacts(specDir string) ([]StaleArtifact, error) {
+	data, err := os.ReadFile(filepath.Join(specDir, StaleFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading stale marker: %w", err)
+	}
+
+	var marker staleMarker
+	if err := yaml.Unmarshal(data, &marker); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", StaleFileName, err)
+	}
+	return marker.Artifacts, nil
+}
+
+// ClearStale removes artifact from the stale marker after it was regenerated.
+// The marker file is deleted once nothing is stale.
+func ClearStale(specDir, artifact string) error {
+	stale, err := LoadStaleArtifacts(specDir)
+	if err != nil || findStale(stale, artifact) == nil {
+		return err
+	}
+
+	remaining := stale[:0]
+	for _, s := range stale {
+		if s.Artifact != artifact {
+			remaining = append(remaining, s)
+		}
+	}
+	return writeStaleMarker(specDir, remaining)
+}
+
+// findStale returns the entry for artifact, or nil.
+func findStale(stale []StaleArtifact, artifact string) *StaleArtifact {
+	for i := range stale {
+		if stale[i].Artifact == artifact {
+			return &stale[i]
+		}
+	}
+	return nil
+}
+
+// writeStaleMarker writes the marker file, or removes it when stale is empty.
+func writeStaleMarker(specDir string, stale []StaleArtifact) error {
+	path := filepath.Join(specDir, StaleFileName)
+	if len(stale) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("removing stale marker: %w", err)
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(staleMarker{Artifacts: stale})
+	if err != nil {
+		return fmt.Errorf("serializing stale marker: %w", err)
+	}
+	if err := fsutil.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing stale marker: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Package spec_test tests the stale artifact marker written by spec amendments.
+// Related: internal/spec/stale.go
+// Tags: spec, stale, amend, plan, tasks
+
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkDownstreamStale(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"spec.yaml", "plan.yaml", "tasks.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("x: 1\n"), 0o644))
+	}
+
+	stale, err := MarkDownstreamStale(dir, "Add GitHub login")
+	require.NoError(t, err)
+	require.Len(t, stale, 2)
+	assert.Equal(t, "plan.yaml", stale[0].Artifact)
+	assert.Equal(t, "plan", stale[0].Stage)
+	assert.Equal(t, "tasks", stale[1].Stage)
+	since := stale[0].Since
+
+	// A second amendment adds its reason and keeps the original time
+	stale, err = MarkDownstreamStale(dir, "Drop CSV export")
+	require.NoError(t, err)
+	require.Len(t, stale, 2)
+	assert.Equal(t, []string{"Add GitHub login", "Drop CSV export"}, stale[0].Reasons)
+	assert.True(t, stale[0].Since.Equal(since))
+
+	loaded, err := LoadStaleArtifacts(dir)
+	require.NoError(t, err)
+	assert.Len(t, loaded, 2)
+}
+
+func TestMarkDownstreamStale_NoDownstreamArtifacts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	stale, err := MarkDownstreamStale(dir, "Add GitHub login")
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+	assert.NoFileExists(t, filepath.Join(dir, StaleFileName))
+}
+
+func TestClearStale(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"plan.yaml", "tasks.yaml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte("x: 1\n"), 0o644))
+	}
+	_, err := MarkDownstreamStale(dir, "Add GitHub login")
+	require.NoError(t, err)
+
+	require.NoError(t, ClearStale(dir, "plan.yaml"))
+	stale, err := LoadStaleArtifacts(dir)
+	require.NoError(t, err)
+	require.Len(t, stale, 1)
+	assert.Equal(t, "tasks.yaml", stale[0].Artifact)
+
+	// Clearing an artifact that is not stale is a no-op
+	require.NoError(t, ClearStale(dir, "plan.yaml"))
+
+	require.NoError(t, ClearStale(dir, "tasks.yaml"))
+	assert.NoFileExists(t, filepath.Join(dir, StaleFileName))
+}
+
+func TestLoadStaleArtifacts_Invalid(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, StaleFileName), []byte("artifacts: [\n"), 0o644))
+	_, err := LoadStaleArtifacts(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing .stale.yaml")
+}
@@ -0,0 +1,204 @@
+// Package specwatch watches a spec's spec.yaml for manual edits. Once an edit
+// settles, it re-validates the spec and the plan.yaml and tasks.yaml derived
+// from it, and marks those artifacts stale so the next run regenerates them.
+package specwatch
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// DefaultDebounce is how long spec.yaml must stay unchanged before an edit
+// is evaluated, so editors that save in several writes trigger one check.
+const DefaultDebounce = 2 * time.Second
+
+// pollInterval is how often spec.yaml is checked for changes.
+const pollInterval = 500 * time.Millisecond
+
+// downstream lists the artifacts derived from spec.yaml, in stage order.
+var downstream = []struct {
+	artifact     string
+	artifactType validation.ArtifactType
+}{
+	{"plan.yaml", validation.ArtifactTypePlan},
+	{"tasks.yaml", validation.ArtifactTypeTasks},
+}
+
+// Report is the outcome of one settled edit of spec.yaml.
+type Report struct {
+	Sections []string                     // Top-level spec.yaml sections whose content changed
+	Spec     *validation.ValidationResult // spec.yaml validation; downstream is left alone when invalid
+	Checks   []Check                      // Re-validation of the downstream artifacts that exist
+	Stale    []spec.StaleArtifact         // Every stale artifact after the edit
+}
+
+// Check is the re-validation of one downstream artifact.
+type Check struct {
+	Artifact string
+	Result   *validation.ValidationResult
+}
+
+// Watcher polls a spec directory's spec.yaml and reports each settled edit.
+type Watcher struct {
+	specDir  string
+	debounce time.Duration
+	interval time.Duration
+	now      func() time.Time
+
+	seen    [sha256.Size]byte // raw content last observed
+	content map[string]any    // sections of the last valid spec.yaml
+}
+
+// New returns a watcher for specDir, starting from the current spec.yaml.
+// A negative debounce is treated as zero.
+func New(specDir string, debounce time.Duration) (*Watcher, error) {
+	w := &Watcher{
+		specDir:  specDir,
+		debounce: max(debounce, 0),
+		interval: pollInterval,
+		now:      time.Now,
+	}
+	sum, err := w.fileSum()
+	if err != nil {
+		return nil, err
+	}
+	w.seen = sum
+	// An invalid spec.yaml has no sections yet; the first valid edit lists all of them
+	w.content, _ = validation.LoadArtifactContent(w.specPath(), validation.ArtifactTypeSpec)
+	return w, nil
+}
+
+// Run polls spec.yaml until ctx is done, calling onEdit once for each edit
+// that stayed unchanged for the debounce period. Edits that only change
+// formatting, comments, _meta or the feature status are not reported.
+func (w *Watcher) Run(ctx context.Context, onEdit func(Report)) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	pending := false
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		sum, err := w.fileSum()
+		if err != nil {
+			continue // Editors may briefly remove the file while saving
+		}
+		if sum != w.seen {
+			w.seen = sum
+			pending = true
+			changedAt = w.now()
+			continue
+		}
+		if !pending || w.now().Sub(changedAt) < w.debounce {
+			continue
+		}
+		pending = false
+		report, changed, err := w.Evaluate()
+		if err != nil {
+			return err
+		}
+		if changed {
+			onEdit(report)
+		}
+	}
+}
+
+// Evaluate checks the current spec.yaml against the last valid one. When its
+// content changed, the downstream artifacts are marked stale and
+// re-validated. changed is false for edits that leave the content as it was.
+// An invalid spec.yaml is reported without touching the downstream artifacts.
+func (w *Watcher) Evaluate() (report Report, changed bool, err error) {
+	validator, err := validation.NewArtifactValidator(validation.ArtifactTypeSpec)
+	if err != nil {
+		return Report{}, false, err
+	}
+	report.Spec = validator.Validate(w.specPath())
+	if !report.Spec.Valid {
+		return report, true, nil
+	}
+
+	content, err := validation.LoadArtifactContent(w.specPath(), validation.ArtifactTypeSpec)
+	if err != nil {
+		return Report{}, false, err
+	}
+	report.Sections = changedSections(w.content, content)
+	if len(report.Sections) == 0 {
+		return report, false, nil
+	}
+	w.content = content
+
+	reason := fmt.Sprintf("spec.yaml edited (%s)", strings.Join(report.Sections, ", "))
+	report.Stale, err = spec.MarkDownstreamStale(w.specDir, reason)
+	if err != nil {
+		return Report{}, false, err
+	}
+	report.Checks, err = w.checkDownstream()
+	if err != nil {
+		return Report{}, false, err
+	}
+	return report, true, nil
+}
+
+// checkDownstream validates the downstream artifacts that exist.
+func (w *Watcher) checkDownstream() ([]Check, error) {
+	var checks []Check
+	for _, d := range downstream {
+		path := filepath.Join(w.specDir, d.artifact)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		validator, err := validation.NewArtifactValidator(d.artifactType)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, Check{Artifact: d.artifact, Result: validator.Validate(path)})
+	}
+	return checks, nil
+}
+
+// specPath returns the watched spec.yaml.
+func (w *Watcher) specPath() string {
+	return filepath.Join(w.specDir, "spec.yaml")
+}
+
+// fileSum hashes the raw content of spec.yaml.
+func (w *Watcher) fileSum() ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(w.specPath())
+	if err != nil {
+		return [sha256.Size]byte{}, fmt.Errorf("reading spec.yaml: %w", err)
+	}
+	return sha256.Sum256(data), nil
+}
+
+// changedSections returns the sorted top-level keys that differ between
+// before and after, including added and removed sections.
+func changedSections(before, after map[string]any) []string {
+	var changed []string
+	for key, value := range after {
+		if !reflect.DeepEqual(before[key], value) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
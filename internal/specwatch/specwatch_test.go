@@ -0,0 +1,168 @@
+package specwatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSpecDir returns a spec directory with a valid spec.yaml and the given
+// downstream fixtures copied from the validation testdata.
+func newSpecDir(t *testing.T, downstream ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	copyFixture(t, "spec", filepath.Join(dir, "spec.yaml"))
+	for _, artifact := range downstream {
+		copyFixture(t, strings.TrimSuffix(artifact, ".yaml"), filepath.Join(dir, artifact))
+	}
+	return dir
+}
+
+func copyFixture(t *testing.T, kind, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "validation", "testdata", kind, "valid.yaml"))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, data, 0o644))
+}
+
+// editSpec rewrites spec.yaml with old replaced by new.
+func editSpec(t *testing.T, dir, old, new string) {
+	t.Helper()
+	path := filepath.Join(dir, "spec.yaml")
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), old)
+	require.NoError(t, os.WriteFile(path, []byte(strings.Replace(string(data), old, new, 1)), 0o644))
+}
+
+func TestWatcher_Evaluate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		downstream  []string
+		old, new    string
+		wantChanged bool
+		wantValid   bool
+		wantSection []string
+		wantStale   []string
+	}{
+		"requirement edit marks plan and tasks stale": {
+			downstream:  []string{"plan.yaml", "tasks.yaml"},
+			old:         "MUST hash passwords before storage",
+			new:         "MUST hash passwords with argon2id",
+			wantChanged: true,
+			wantValid:   true,
+			wantSection: []string{"requirements"},
+			wantStale:   []string{"plan.yaml", "tasks.yaml"},
+		},
+		"no downstream artifacts yet": {
+			old:         "User can reset forgotten password",
+			new:         "User can reset a forgotten password",
+			wantChanged: true,
+			wantValid:   true,
+			wantSection: []string{"user_stories"},
+		},
+		"comment and status edits are ignored": {
+			downstream: []string{"plan.yaml"},
+			old:        `status: "Draft"`,
+			new:        "status: \"Review\" # ready for review",
+			wantValid:  true,
+		},
+		"invalid spec leaves downstream alone": {
+			downstream:  []string{"plan.yaml"},
+			old:         `priority: "P1"`,
+			new:         `priority: "urgent"`,
+			wantChanged: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := newSpecDir(t, tt.downstream...)
+			w, err := New(dir, 0)
+			require.NoError(t, err)
+
+			editSpec(t, dir, tt.old, tt.new)
+			report, changed, err := w.Evaluate()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantChanged, changed)
+			assert.Equal(t, tt.wantValid, report.Spec.Valid)
+			assert.Equal(t, tt.wantSection, report.Sections)
+
+			var stale []string
+			for _, s := range report.Stale {
+				stale = append(stale, s.Artifact)
+			}
+			assert.Equal(t, tt.wantStale, stale)
+			onDisk, err := spec.LoadStaleArtifacts(dir)
+			require.NoError(t, err)
+			assert.Len(t, onDisk, len(tt.wantStale))
+			if len(tt.wantStale) > 0 {
+				assert.Len(t, report.Checks, len(tt.downstream))
+				assert.Contains(t, onDisk[0].Reasons[0], "spec.yaml edited (requirements)")
+			}
+		})
+	}
+}
+
+func TestWatcher_RunDebouncesEdits(t *testing.T) {
+	t.Parallel()
+
+	dir := newSpecDir(t, "plan.yaml")
+	w, err := New(dir, 50*time.Millisecond)
+	require.NoError(t, err)
+	w.interval = 5 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	reports := make(chan Report, 4)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx, func(r Report) { reports <- r })
+	}()
+
+	// Two quick saves settle into one report
+	editSpec(t, dir, "MUST hash passwords before storage", "MUST hash passwords")
+	time.Sleep(10 * time.Millisecond)
+	editSpec(t, dir, "SHOULD support password reset via email", "MUST support password reset via email")
+
+	select {
+	case r := <-reports:
+		assert.Equal(t, []string{"requirements"}, r.Sections)
+		require.Len(t, r.Stale, 1)
+		assert.Equal(t, "plan", r.Stale[0].Stage)
+	case <-time.After(2 * time.Second):
+		t.Fatal("no report for the edit")
+	}
+	select {
+	case r := <-reports:
+		t.Fatalf("unexpected second report %+v", r)
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestNew_MissingSpec(t *testing.T) {
+	t.Parallel()
+	_, err := New(t.TempDir(), DefaultDebounce)
+	assert.ErrorContains(t, err, "reading spec.yaml")
+}
+
+func TestChangedSections(t *testing.T) {
+	t.Parallel()
+
+	before := map[string]any{"feature": "a", "requirements": []any{"x"}, "notes": "n"}
+	after := map[string]any{"feature": "a", "requirements": []any{"y"}, "assumptions": []any{"z"}}
+	assert.Equal(t, []string{"assumptions", "notes", "requirements"}, changedSections(before, after))
+	assert.Empty(t, changedSections(after, after))
+}
@@ -0,0 +1,220 @@
+// Package stagestats keeps per-stage failure history across runs in the state
+// directory (stage_stats.json), classified by kind of failure, so stages and
+// specs that keep needing retries stand out and their prompts or templates
+// can be fixed.
+package stagestats
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+)
+
+// FileName is the stats file in the state directory.
+const FileName = "stage_stats.json"
+
+// Kind classifies why a stage attempt failed.
+type Kind string
+
+const (
+	// KindValidation: the agent finished but its output failed validation.
+	KindValidation Kind = "validation"
+	// KindAgentError: the agent command itself failed.
+	KindAgentError Kind = "agent_error"
+	// KindTimeout: the agent did not finish within its timeout.
+	KindTimeout Kind = "timeout"
+	// KindGate: a verification gate such as task_commands failed.
+	KindGate Kind = "gate"
+)
+
+// Record is the history of one stage of one spec, or of one stage across
+// all specs when Spec is empty.
+type Record struct {
+	Spec      string       `json:"spec,omitempty"`
+	Stage     string       `json:"stage"`
+	Runs      int          `json:"runs"`      // Stage executions (one per task for implement --tasks)
+	Attempts  int          `json:"attempts"`  // Agent sessions, retries included
+	Succeeded int          `json:"succeeded"` // Runs that passed validation
+	Failures  map[Kind]int `json:"failures,omitempty"`
+	LastRun   time.Time    `json:"last_run"`
+}
+
+// Retries returns the attempts beyond the first of each run.
+func (r Record) Retries() int {
+	return r.Attempts - r.Runs
+}
+
+// RetryRate returns the average number of retries per run.
+func (r Record) RetryRate() float64 {
+	if r.Runs == 0 {
+		return 0
+	}
+	return float64(r.Retries()) / float64(r.Runs)
+}
+
+// FailureRate returns the share of attempts that failed.
+func (r Record) FailureRate() float64 {
+	if r.Attempts == 0 {
+		return 0
+	}
+	failed := 0
+	for _, n := range r.Failures {
+		failed += n
+	}
+	return float64(failed) / float64(r.Attempts)
+}
+
+// TopFailure returns the most frequent failure kind, or "" when none failed.
+func (r Record) TopFailure() Kind {
+	var top Kind
+	for kind, n := range r.Failures {
+		if n > r.Failures[top] || (n == r.Failures[top] && kind < top) {
+			top = kind
+		}
+	}
+	return top
+}
+
+// add merges other's counts into r.
+func (r *Record) add(other Record) {
+	r.Runs += other.Runs
+	r.Attempts += other.Attempts
+	r.Succeeded += other.Succeeded
+	for kind, n := range other.Failures {
+		if r.Failures == nil {
+			r.Failures = make(map[Kind]int)
+		}
+		r.Failures[kind] += n
+	}
+	if other.LastRun.After(r.LastRun) {
+		r.LastRun = other.LastRun
+	}
+}
+
+// Store holds the records by "spec:stage".
+type Store struct {
+	Records map[string]*Record `json:"records"`
+}
+
+// Load reads the stats file from stateDir. A missing file is an empty store.
+func Load(stateDir string) (*Store, error) {
+	store := &Store{Records: make(map[string]*Record)}
+	data, err := os.ReadFile(filepath.Join(stateDir, FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading stage stats: %w", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", FileName, err)
+	}
+	if store.Records == nil {
+		store.Records = make(map[string]*Record)
+	}
+	return store, nil
+}
+
+// Run is the outcome of one stage execution.
+type Run struct {
+	Spec      string
+	Stage     string
+	Attempts  int
+	Failures  []Kind // One per failed attempt
+	Succeeded bool
+	At        time.Time
+}
+
+// RecordRun adds a stage execution to the stats file in stateDir, written
+// atomically.
+func RecordRun(stateDir string, run Run) error {
+	store, err := Load(stateDir)
+	if err != nil {
+		return err
+	}
+	key := run.Spec + ":" + run.Stage
+	rec, ok := store.Records[key]
+	if !ok {
+		rec = &Record{Spec: run.Spec, Stage: run.Stage}
+		store.Records[key] = rec
+	}
+	entry := Record{Runs: 1, Attempts: run.Attempts, LastRun: run.At}
+	if run.Succeeded {
+		entry.Succeeded = 1
+	}
+	for _, kind := range run.Failures {
+		if entry.Failures == nil {
+			entry.Failures = make(map[Kind]int)
+		}
+		entry.Failures[kind]++
+	}
+	rec.add(entry)
+
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	data, err := json.MarshalIndent(store, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding stage stats: %w", err)
+	}
+	if err := fsutil.WriteFile(filepath.Join(stateDir, FileName), data, 0o644); err != nil {
+		return fmt.Errorf("writing stage stats: %w", err)
+	}
+	return nil
+}
+
+// ByStage returns one record per stage, summed across specs, sorted by
+// stage name.
+func (s *Store) ByStage() []Record {
+	stages := make(map[string]*Record)
+	for _, rec := range s.Records {
+		total, ok := stages[rec.Stage]
+		if !ok {
+			total = &Record{Stage: rec.Stage}
+			stages[rec.Stage] = total
+		}
+		total.add(*rec)
+	}
+	records := make([]Record, 0, len(stages))
+	for _, rec := range stages {
+		records = append(records, *rec)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Stage < records[j].Stage })
+	return records
+}
+
+// Flaky returns the stages (summed across specs) and the spec stages with at
+// least minRuns runs whose retry rate is at least threshold retries per run,
+// highest retry rate first. Stage totals come before spec stages on ties.
+func (s *Store) Flaky(minRuns int, threshold float64) []Record {
+	candidates := s.ByStage()
+	for _, rec := range s.Records {
+		candidates = append(candidates, *rec)
+	}
+	var flaky []Record
+	for _, rec := range candidates {
+		if rec.Runs >= minRuns && rec.Retries() > 0 && rec.RetryRate() >= threshold {
+			flaky = append(flaky, rec)
+		}
+	}
+	sort.SliceStable(flaky, func(i, j int) bool {
+		a, b := flaky[i], flaky[j]
+		if a.RetryRate() != b.RetryRate() {
+			return a.RetryRate() > b.RetryRate()
+		}
+		if (a.Spec == "") != (b.Spec == "") {
+			return a.Spec == ""
+		}
+		if a.Stage != b.Stage {
+			return a.Stage < b.Stage
+		}
+		return a.Spec < b.Spec
+	})
+	return flaky
+}
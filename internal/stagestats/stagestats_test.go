@@ -0,0 +1,94 @@
+package stagestats
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recordRuns(t *testing.T, stateDir string, runs ...Run) {
+	t.Helper()
+	for _, run := range runs {
+		require.NoError(t, RecordRun(stateDir, run))
+	}
+}
+
+func TestRecordRun(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	at := time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC)
+	recordRuns(t, stateDir,
+		Run{Spec: "001-login", Stage: "plan", Attempts: 1, Succeeded: true, At: at},
+		Run{Spec: "001-login", Stage: "plan", Attempts: 3, Failures: []Kind{KindValidation, KindGate, KindValidation}, At: at.Add(time.Hour)},
+	)
+
+	store, err := Load(stateDir)
+	require.NoError(t, err)
+	rec := store.Records["001-login:plan"]
+	require.NotNil(t, rec)
+	assert.Equal(t, 2, rec.Runs)
+	assert.Equal(t, 4, rec.Attempts)
+	assert.Equal(t, 1, rec.Succeeded)
+	assert.Equal(t, 2, rec.Retries())
+	assert.InDelta(t, 1.0, rec.RetryRate(), 0.001)
+	assert.InDelta(t, 0.75, rec.FailureRate(), 0.001)
+	assert.Equal(t, KindValidation, rec.TopFailure())
+	assert.True(t, rec.LastRun.Equal(at.Add(time.Hour)))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file is empty", func(t *testing.T) {
+		t.Parallel()
+		store, err := Load(t.TempDir())
+		require.NoError(t, err)
+		assert.Empty(t, store.Records)
+	})
+
+	t.Run("corrupt file", func(t *testing.T) {
+		t.Parallel()
+		stateDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(stateDir, FileName), []byte("{"), 0o644))
+		_, err := Load(stateDir)
+		assert.ErrorContains(t, err, "parsing stage_stats.json")
+	})
+}
+
+func TestStore_Flaky(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	ok := func(spec, stage string) Run {
+		return Run{Spec: spec, Stage: stage, Attempts: 1, Succeeded: true}
+	}
+	retried := func(spec, stage string) Run {
+		return Run{Spec: spec, Stage: stage, Attempts: 3, Failures: []Kind{KindTimeout, KindTimeout}, Succeeded: true}
+	}
+	recordRuns(t, stateDir,
+		// 001 plans keep needing retries
+		retried("001", "plan"), retried("001", "plan"), ok("001", "plan"),
+		// 002 plans are fine
+		ok("002", "plan"), ok("002", "plan"), ok("002", "plan"),
+		// Too few runs to judge
+		retried("003", "tasks"), retried("003", "tasks"),
+	)
+
+	store, err := Load(stateDir)
+	require.NoError(t, err)
+	flaky := store.Flaky(3, 0.5)
+	require.Len(t, flaky, 2)
+	assert.Equal(t, "001", flaky[0].Spec)
+	assert.InDelta(t, 4.0/3, flaky[0].RetryRate(), 0.001)
+	assert.Equal(t, KindTimeout, flaky[0].TopFailure())
+	assert.Equal(t, "", flaky[1].Spec, "the plan stage across specs")
+	assert.Equal(t, "plan", flaky[1].Stage)
+	assert.Equal(t, 6, flaky[1].Runs)
+
+	assert.Empty(t, store.Flaky(3, 2))
+}
@@ -9,6 +9,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 )
 
 // NoticeFileName is the name of the file that stores notice state
@@ -53,7 +55,7 @@ func LoadNoticeState(stateDir string) (*AutoCommitNoticeState, error) {
 
 // SaveNoticeState persists the notice state to the state directory using atomic write.
 // Creates the state directory if it doesn't exist.
-// Uses atomic write pattern: write to .tmp file, then rename to final path.
+// Uses fsutil.WriteFile so a crash never leaves a partial file.
 func SaveNoticeState(stateDir string, state *AutoCommitNoticeState) error {
 	// Ensure state directory exists
 	if err := os.MkdirAll(stateDir, 0o755); err != nil {
@@ -66,16 +68,9 @@ func SaveNoticeState(stateDir string, state *AutoCommitNoticeState) error {
 		return fmt.Errorf("marshaling notice state: %w", err)
 	}
 
-	// Write to temp file
 	noticePath := filepath.Join(stateDir, NoticeFileName)
-	tmpPath := noticePath + ".tmp"
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return fmt.Errorf("writing temp file: %w", err)
-	}
-
-	// Atomic rename
-	if err := os.Rename(tmpPath, noticePath); err != nil {
-		return fmt.Errorf("renaming temp file: %w", err)
+	if err := fsutil.WriteFile(noticePath, data, 0o644); err != nil {
+		return fmt.Errorf("writing notice state: %w", err)
 	}
 
 	return nil
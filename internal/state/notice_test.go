@@ -307,8 +307,9 @@ func TestAtomicWritePattern(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify no temp file remains
-	tmpPath := filepath.Join(stateDir, NoticeFileName+".tmp")
-	assert.NoFileExists(t, tmpPath, "Temp file should not exist after successful save")
+	tmpFiles, err := filepath.Glob(filepath.Join(stateDir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tmpFiles, "Temp file should not exist after successful save")
 
 	// Verify main file exists
 	assert.FileExists(t, filepath.Join(stateDir, NoticeFileName))
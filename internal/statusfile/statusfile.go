@@ -0,0 +1,311 @@
+// Package statusfile maintains .autospec/status.json, a continuously-updated
+// snapshot of the current run (stage, active task, progress percentages).
+// Editor extensions and status-bar widgets poll this file instead of parsing
+// logs. The file is rewritten atomically on every change, so readers always
+// see a complete JSON document.
+package statusfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+)
+
+const (
+	// FileName is the status file name inside the project .autospec directory.
+	FileName = "status.json"
+	// SchemaVersion is bumped on incompatible changes to the file layout.
+	SchemaVersion = 1
+)
+
+// Run states written to the State field.
+const (
+	StateRunning   = "running"
+	StateCompleted = "completed"
+	StateFailed    = "failed"
+	StateCancelled = "cancelled"
+	StatePaused    = "paused"
+)
+
+// Status is the JSON document written to status.json.
+type Status struct {
+	Version   int          `json:"version"`
+	RunID     string       `json:"run_id,omitempty"`
+	PID       int          `json:"pid"`
+	State     string       `json:"state"`
+	Spec      string       `json:"spec,omitempty"`
+	Stage     *StageStatus `json:"stage,omitempty"`
+	Task      *TaskStatus  `json:"task,omitempty"`
+	Progress  Progress     `json:"progress"`
+	Error     string       `json:"error,omitempty"`
+	StartedAt time.Time    `json:"started_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// StageStatus describes the stage currently executing (or last executed).
+type StageStatus struct {
+	Name       string `json:"name"`
+	Attempt    int    `json:"attempt"`
+	MaxRetries int    `json:"max_retries"`
+}
+
+// TaskStatus describes the active task during task-by-task implementation.
+type TaskStatus struct {
+	ID    string `json:"id"`
+	Title string `json:"title,omitempty"`
+}
+
+// Progress holds completion counts and percentages (0-100).
+type Progress struct {
+	StagesCompleted int `json:"stages_completed"`
+	StagesTotal     int `json:"stages_total"`
+	StagesPercent   int `json:"stages_percent"`
+	TasksCompleted  int `json:"tasks_completed,omitempty"`
+	TasksTotal      int `json:"tasks_total,omitempty"`
+	TasksPercent    int `json:"tasks_percent,omitempty"`
+}
+
+// Writer keeps the in-memory status and persists it after every update.
+// A nil *Writer is valid and ignores all updates. Write errors are non-fatal:
+// the first one is reported to stderr and later updates are kept in memory only.
+type Writer struct {
+	path string
+
+	mu        sync.Mutex
+	status    Status
+	completed map[string]bool
+	held      bool
+	failed    bool
+	now       func() time.Time
+
+	// Status line (see EnableLine); linePath is empty when disabled
+	linePath       string
+	lineFailed     bool
+	display        func(msg string) error // tmux display-message, nil when off
+	lastTransition string                 // state and stage of the last displayed line
+}
+
+// DefaultPath returns the status file path for the project in the current directory.
+func DefaultPath() string {
+	return filepath.Join(".autospec", FileName)
+}
+
+// New creates a writer for path. Returns nil (status disabled) when path is
+// empty or its parent directory does not exist, so runs outside an initialized
+// project never create a stray .autospec directory.
+func New(path, runID string) *Writer {
+	if path == "" {
+		return nil
+	}
+	if info, err := os.Stat(filepath.Dir(path)); err != nil || !info.IsDir() {
+		return nil
+	}
+	w := &Writer{
+		path:      path,
+		completed: make(map[string]bool),
+		now:       time.Now,
+	}
+	w.status = Status{
+		Version:   SchemaVersion,
+		RunID:     runID,
+		PID:       os.Getpid(),
+		State:     StateRunning,
+		Progress:  Progress{StagesTotal: 1},
+		StartedAt: w.now(),
+	}
+	return w
+}
+
+// Path returns the file the writer persists to, or "" for a nil writer.
+func (w *Writer) Path() string {
+	if w == nil {
+		return ""
+	}
+	return w.path
+}
+
+// SetTotalStages sets how many stages this run will execute. Single-stage
+// commands leave the default of 1; multi-stage workflows call this up front
+// so the run is only reported completed after its last stage.
+func (w *Writer) SetTotalStages(n int) {
+	if w == nil || n <= 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status.Progress.StagesTotal = n
+}
+
+// StartStage records that stage began an attempt for spec.
+func (w *Writer) StartStage(spec, stage string, attempt, maxRetries int) {
+	w.update(func(s *Status) {
+		s.State = StateRunning
+		s.Error = ""
+		if spec != "" {
+			s.Spec = spec
+		}
+		s.Stage = &StageStatus{Name: stage, Attempt: attempt, MaxRetries: maxRetries}
+	})
+}
+
+// CompleteStage marks stage as completed. Ignored while the stage is held
+// (see HoldStage), since loops run one agent session per task or phase.
+func (w *Writer) CompleteStage(stage string) {
+	w.update(func(s *Status) {
+		if w.held {
+			return
+		}
+		w.completeStage(s, stage)
+	})
+}
+
+// HoldStage defers stage completion until ReleaseStage. Task and phase loops
+// call it so the stage is not reported done after the first session.
+func (w *Writer) HoldStage() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.held = true
+}
+
+// ReleaseStage ends a held loop, clears the active task, and completes stage.
+func (w *Writer) ReleaseStage(stage string) {
+	w.update(func(s *Status) {
+		w.held = false
+		s.Task = nil
+		w.completeStage(s, stage)
+	})
+}
+
+// FailStage marks the run as failed with err.
+func (w *Writer) FailStage(err error) {
+	w.update(func(s *Status) {
+		s.State = StateFailed
+		if err != nil {
+			s.Error = err.Error()
+		}
+	})
+}
+
+// Cancel marks the run as stopped by a signal; rerunning the command resumes it.
+func (w *Writer) Cancel() {
+	w.update(func(s *Status) {
+		s.State = StateCancelled
+	})
+}
+
+// Pause marks the run as waiting for 'autospec resume --continue'.
+func (w *Writer) Pause() {
+	w.update(func(s *Status) {
+		s.State = StatePaused
+	})
+}
+
+// Resume marks a paused run as running again.
+func (w *Writer) Resume() {
+	w.update(func(s *Status) {
+		s.State = StateRunning
+	})
+}
+
+// StartTask records the active task; completed and total are task counts for
+// the whole tasks.yaml, so resumed runs report accurate percentages.
+func (w *Writer) StartTask(id, title string, completed, total int) {
+	w.update(func(s *Status) {
+		s.Task = &TaskStatus{ID: id, Title: title}
+		s.Progress.TasksCompleted = completed
+		s.Progress.TasksTotal = total
+		s.Progress.TasksPercent = percent(completed, total)
+	})
+}
+
+// CompleteTask counts the active task as done.
+func (w *Writer) CompleteTask() {
+	w.update(func(s *Status) {
+		if s.Progress.TasksCompleted < s.Progress.TasksTotal {
+			s.Progress.TasksCompleted++
+		}
+		s.Progress.TasksPercent = percent(s.Progress.TasksCompleted, s.Progress.TasksTotal)
+	})
+}
+
+// Snapshot returns a copy of the current status.
+func (w *Writer) Snapshot() Status {
+	if w == nil {
+		return Status{}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+// completeStage records stage completion and finishes the run once every
+// stage is done. Caller must hold w.mu.
+func (w *Writer) completeStage(s *Status, stage string) {
+	w.completed[stage] = true
+	s.Progress.StagesCompleted = len(w.completed)
+	if s.Progress.StagesTotal < s.Progress.StagesCompleted {
+		s.Progress.StagesTotal = s.Progress.StagesCompleted
+	}
+	s.Progress.StagesPercent = percent(s.Progress.StagesCompleted, s.Progress.StagesTotal)
+	if s.Progress.StagesCompleted == s.Progress.StagesTotal {
+		s.State = StateCompleted
+	}
+}
+
+// update applies fn to the status and persists the result.
+func (w *Writer) update(fn func(*Status)) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	fn(&w.status)
+	w.status.UpdatedAt = w.now()
+	w.writeLine()
+	if w.failed {
+		return
+	}
+	if err := w.write(); err != nil {
+		w.failed = true
+		fmt.Fprintf(os.Stderr, "Warning: failed to write %s: %v\n", w.path, err)
+	}
+}
+
+// write atomically replaces the status file. Caller must hold w.mu.
+func (w *Writer) write() error {
+	data, err := json.MarshalIndent(w.status, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling status: %w", err)
+	}
+	return fsutil.WriteFile(w.path, append(data, '\n'), 0o644)
+}
+
+// Load reads a status file written by a Writer.
+func Load(path string) (*Status, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Status
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// percent returns done/total as an integer percentage, or 0 when total is 0.
+func percent(done, total int) int {
+	if total <= 0 {
+		return 0
+	}
+	return done * 100 / total
+}
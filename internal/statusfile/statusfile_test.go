@@ -0,0 +1,223 @@
+package statusfile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWriter(t *testing.T) (*Writer, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	w := New(path, "brave_falcon_20250115_143022")
+	require.NotNil(t, w)
+	return w, path
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path    func(t *testing.T) string
+		wantNil bool
+	}{
+		"empty path disables status": {
+			path:    func(*testing.T) string { return "" },
+			wantNil: true,
+		},
+		"missing parent directory disables status": {
+			path:    func(t *testing.T) string { return filepath.Join(t.TempDir(), ".autospec", FileName) },
+			wantNil: true,
+		},
+		"existing parent directory enables status": {
+			path: func(t *testing.T) string { return filepath.Join(t.TempDir(), FileName) },
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			w := New(tt.path(t), "run")
+			assert.Equal(t, tt.wantNil, w == nil)
+		})
+	}
+}
+
+func TestNilWriterIsNoop(t *testing.T) {
+	t.Parallel()
+
+	var w *Writer
+	assert.NotPanics(t, func() {
+		w.SetTotalStages(3)
+		w.StartStage("001-test", "plan", 1, 3)
+		w.HoldStage()
+		w.StartTask("T001", "Setup", 0, 2)
+		w.CompleteTask()
+		w.ReleaseStage("implement")
+		w.CompleteStage("plan")
+		w.FailStage(errors.New("boom"))
+	})
+	assert.Equal(t, "", w.Path())
+	assert.Equal(t, Status{}, w.Snapshot())
+}
+
+func TestWriter_StageProgress(t *testing.T) {
+	t.Parallel()
+
+	w, path := newTestWriter(t)
+	w.SetTotalStages(4)
+
+	w.StartStage("001-auth", "specify", 1, 3)
+	st, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, st.Version)
+	assert.Equal(t, "brave_falcon_20250115_143022", st.RunID)
+	assert.Equal(t, os.Getpid(), st.PID)
+	assert.Equal(t, StateRunning, st.State)
+	assert.Equal(t, "001-auth", st.Spec)
+	assert.Equal(t, &StageStatus{Name: "specify", Attempt: 1, MaxRetries: 3}, st.Stage)
+	assert.Equal(t, 0, st.Progress.StagesPercent)
+
+	w.CompleteStage("specify")
+	w.StartStage("", "plan", 1, 3)
+	st, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "001-auth", st.Spec, "empty spec keeps the previous value")
+	assert.Equal(t, 1, st.Progress.StagesCompleted)
+	assert.Equal(t, 25, st.Progress.StagesPercent)
+	assert.Equal(t, StateRunning, st.State)
+
+	w.CompleteStage("plan")
+	w.CompleteStage("plan")
+	assert.Equal(t, 2, w.Snapshot().Progress.StagesCompleted, "repeated completions count once")
+
+	w.CompleteStage("tasks")
+	w.CompleteStage("implement")
+	st, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StateCompleted, st.State)
+	assert.Equal(t, 100, st.Progress.StagesPercent)
+}
+
+func TestWriter_SingleStageCompletes(t *testing.T) {
+	t.Parallel()
+
+	w, path := newTestWriter(t)
+	w.StartStage("001-auth", "plan", 1, 3)
+	w.CompleteStage("plan")
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StateCompleted, st.State)
+	assert.Equal(t, 100, st.Progress.StagesPercent)
+}
+
+func TestWriter_TaskProgress(t *testing.T) {
+	t.Parallel()
+
+	w, path := newTestWriter(t)
+	w.HoldStage()
+
+	w.StartStage("001-auth", "implement", 1, 3)
+	w.StartTask("T002", "Add login handler", 1, 4)
+	st, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, &TaskStatus{ID: "T002", Title: "Add login handler"}, st.Task)
+	assert.Equal(t, 1, st.Progress.TasksCompleted)
+	assert.Equal(t, 4, st.Progress.TasksTotal)
+	assert.Equal(t, 25, st.Progress.TasksPercent)
+
+	// Each task session completes the implement stage; held until the loop ends
+	w.CompleteStage("implement")
+	w.CompleteTask()
+	st, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StateRunning, st.State)
+	assert.Equal(t, 0, st.Progress.StagesCompleted)
+	assert.Equal(t, 50, st.Progress.TasksPercent)
+
+	w.ReleaseStage("implement")
+	st, err = Load(path)
+	require.NoError(t, err)
+	assert.Nil(t, st.Task)
+	assert.Equal(t, StateCompleted, st.State)
+}
+
+func TestWriter_FailStage(t *testing.T) {
+	t.Parallel()
+
+	w, path := newTestWriter(t)
+	w.StartStage("001-auth", "plan", 3, 3)
+	w.FailStage(errors.New("validation failed: missing field"))
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StateFailed, st.State)
+	assert.Equal(t, "validation failed: missing field", st.Error)
+
+	// A new attempt clears the failure
+	w.StartStage("001-auth", "plan", 1, 3)
+	st, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StateRunning, st.State)
+	assert.Empty(t, st.Error)
+}
+
+func TestWriter_Cancel(t *testing.T) {
+	t.Parallel()
+
+	w, path := newTestWriter(t)
+	w.StartStage("001-auth", "implement", 1, 0)
+	w.FailStage(errors.New("interrupted"))
+	w.Cancel()
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StateCancelled, st.State)
+	assert.Equal(t, "implement", st.Stage.Name)
+}
+
+func TestWriter_PauseAndResume(t *testing.T) {
+	t.Parallel()
+
+	w, path := newTestWriter(t)
+	w.StartStage("001-auth", "implement", 1, 0)
+	w.Pause()
+
+	st, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StatePaused, st.State)
+
+	w.Resume()
+	st, err = Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, StateRunning, st.State)
+}
+
+func TestWriter_AtomicWriteLeavesNoTempFile(t *testing.T) {
+	t.Parallel()
+
+	w, path := newTestWriter(t)
+	w.StartStage("001-auth", "plan", 1, 3)
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".tmp")
+	}
+}
+
+func TestLoad_InvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), FileName)
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o644))
+
+	_, err := Load(path)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing")
+}
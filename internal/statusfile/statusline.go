@@ -0,0 +1,120 @@
+package statusfile
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+)
+
+// LineFileName is the status line file name inside the project .autospec directory.
+const LineFileName = "status-line"
+
+// LineConfig configures the one-line run status for terminal status bars,
+// such as tmux status-right (config key: status_line).
+type LineConfig struct {
+	// Enabled writes the status line on every status update (default: false)
+	Enabled bool `koanf:"enabled" yaml:"enabled" json:"enabled"`
+
+	// Path of the status line file (default: .autospec/status-line)
+	Path string `koanf:"path" yaml:"path" json:"path"`
+
+	// TmuxMessage shows the status line with `tmux display-message` when a
+	// stage starts or the run ends, if autospec runs inside tmux (default: false)
+	TmuxMessage bool `koanf:"tmux_message" yaml:"tmux_message" json:"tmux_message"`
+}
+
+// DefaultLinePath returns the status line path for the project in the current directory.
+func DefaultLinePath() string {
+	return filepath.Join(".autospec", LineFileName)
+}
+
+// Line formats s as one concise line, e.g.
+// "autospec · 003-user-auth · implement 4/4 · T004 4/10".
+func Line(s Status) string {
+	parts := []string{"autospec"}
+	if s.Spec != "" {
+		parts = append(parts, s.Spec)
+	}
+	if s.Stage != nil {
+		stage := s.Stage.Name
+		if total := s.Progress.StagesTotal; total > 1 {
+			stage += fmt.Sprintf(" %d/%d", min(s.Progress.StagesCompleted+1, total), total)
+		}
+		if s.Stage.Attempt > 1 {
+			stage += fmt.Sprintf(" (attempt %d)", s.Stage.Attempt)
+		}
+		parts = append(parts, stage)
+	}
+	if s.Task != nil && s.Progress.TasksTotal > 0 {
+		current := min(s.Progress.TasksCompleted+1, s.Progress.TasksTotal)
+		parts = append(parts, fmt.Sprintf("%s %d/%d", s.Task.ID, current, s.Progress.TasksTotal))
+	}
+	switch s.State {
+	case StateRunning, "":
+	case StateCompleted:
+		parts = append(parts, "done")
+	default:
+		parts = append(parts, s.State)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// EnableLine turns on the status line for cfg. Without Path the line is
+// written to .autospec/status-line. tmux messages are only sent when the
+// TMUX environment variable is set. Safe to call on a nil writer.
+func (w *Writer) EnableLine(cfg LineConfig) {
+	if w == nil || !cfg.Enabled {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.linePath = cfg.Path
+	if w.linePath == "" {
+		w.linePath = DefaultLinePath()
+	}
+	if cfg.TmuxMessage && os.Getenv("TMUX") != "" {
+		w.display = tmuxDisplay
+	}
+}
+
+// writeLine rewrites the status line file and, on a stage transition or at the
+// end of the run, shows the line in tmux. Failures are reported to stderr
+// once. Caller must hold w.mu.
+func (w *Writer) writeLine() {
+	if w.linePath == "" {
+		return
+	}
+	line := Line(w.status)
+	if !w.lineFailed {
+		if err := fsutil.WriteFile(w.linePath, []byte(line+"\n"), 0o644); err != nil {
+			w.lineFailed = true
+			fmt.Fprintf(os.Stderr, "Warning: failed to write %s: %v\n", w.linePath, err)
+		}
+	}
+
+	transition := w.status.State
+	if w.status.Stage != nil {
+		transition += ":" + w.status.Stage.Name
+	}
+	if w.display == nil || transition == w.lastTransition {
+		return
+	}
+	w.lastTransition = transition
+	_ = w.display(line)
+}
+
+// tmuxDisplay shows msg in the status line of the current tmux client.
+func tmuxDisplay(msg string) error {
+	return exec.Command("tmux", "display-message", tmuxEscape(msg)).Run()
+}
+
+// tmuxEscape doubles '#' so that tmux shows msg literally instead of
+// expanding formats such as #(cmd), which would run a shell command taken
+// from a spec or task name.
+func tmuxEscape(msg string) string {
+	return strings.ReplaceAll(msg, "#", "##")
+}
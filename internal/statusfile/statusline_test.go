@@ -0,0 +1,116 @@
+package statusfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLine(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		status Status
+		want   string
+	}{
+		"no stage yet": {
+			status: Status{State: StateRunning},
+			want:   "autospec",
+		},
+		"single stage": {
+			status: Status{State: StateRunning, Spec: "003-auth", Stage: &StageStatus{Name: "plan", Attempt: 1}, Progress: Progress{StagesTotal: 1}},
+			want:   "autospec · 003-auth · plan",
+		},
+		"multi-stage retry": {
+			status: Status{State: StateRunning, Spec: "003-auth", Stage: &StageStatus{Name: "tasks", Attempt: 2}, Progress: Progress{StagesCompleted: 2, StagesTotal: 4}},
+			want:   "autospec · 003-auth · tasks 3/4 (attempt 2)",
+		},
+		"task mode": {
+			status: Status{
+				State: StateRunning, Spec: "003-auth", Stage: &StageStatus{Name: "implement", Attempt: 1},
+				Task:     &TaskStatus{ID: "T004", Title: "Add login handler"},
+				Progress: Progress{StagesTotal: 1, TasksCompleted: 3, TasksTotal: 10},
+			},
+			want: "autospec · 003-auth · implement · T004 4/10",
+		},
+		"completed": {
+			status: Status{State: StateCompleted, Spec: "003-auth", Stage: &StageStatus{Name: "implement", Attempt: 1}, Progress: Progress{StagesCompleted: 4, StagesTotal: 4}},
+			want:   "autospec · 003-auth · implement 4/4 · done",
+		},
+		"failed": {
+			status: Status{State: StateFailed, Spec: "003-auth", Stage: &StageStatus{Name: "plan", Attempt: 3}},
+			want:   "autospec · 003-auth · plan (attempt 3) · failed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, Line(tt.status))
+		})
+	}
+}
+
+func TestWriter_StatusLine(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newTestWriter(t)
+	linePath := filepath.Join(t.TempDir(), LineFileName)
+	w.EnableLine(LineConfig{Enabled: true, Path: linePath})
+	var shown []string
+	w.display = func(msg string) error {
+		shown = append(shown, msg)
+		return nil
+	}
+
+	w.StartStage("003-auth", "implement", 1, 3)
+	w.HoldStage()
+	w.StartTask("T001", "Setup", 0, 2)
+	data, err := os.ReadFile(linePath)
+	require.NoError(t, err)
+	assert.Equal(t, "autospec · 003-auth · implement · T001 1/2\n", string(data))
+
+	w.CompleteTask()
+	w.StartStage("003-auth", "implement", 1, 3)
+	w.StartTask("T002", "Handler", 1, 2)
+	w.CompleteTask()
+	w.ReleaseStage("implement")
+	data, err = os.ReadFile(linePath)
+	require.NoError(t, err)
+	assert.Equal(t, "autospec · 003-auth · implement · done\n", string(data))
+
+	assert.Equal(t, []string{
+		"autospec · 003-auth · implement",
+		"autospec · 003-auth · implement · done",
+	}, shown, "tmux messages only on stage transitions and the end of the run")
+}
+
+func TestWriter_EnableLine(t *testing.T) {
+	t.Parallel()
+
+	w, _ := newTestWriter(t)
+	w.EnableLine(LineConfig{})
+	assert.Empty(t, w.linePath, "disabled config leaves the line off")
+
+	w.EnableLine(LineConfig{Enabled: true})
+	assert.Equal(t, DefaultLinePath(), w.linePath)
+
+	var nilWriter *Writer
+	assert.NotPanics(t, func() { nilWriter.EnableLine(LineConfig{Enabled: true}) })
+}
+
+func TestTmuxEscape(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"autospec · 003-auth · implement": "autospec · 003-auth · implement",
+		"autospec · 004-#(rm -rf ~)":      "autospec · 004-##(rm -rf ~)",
+		"#{pane_id} ## #":                 "##{pane_id} #### ##",
+	}
+	for msg, want := range tests {
+		assert.Equal(t, want, tmuxEscape(msg), msg)
+	}
+}
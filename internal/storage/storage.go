@@ -0,0 +1,225 @@
+// Package storage keeps the state directory from filling the disk. It prunes
+// old run logs (<state_dir>/runs/<run-id>/) by count and age, and compares
+// the size of the state directory and of its logs with configured limits:
+// above a warn limit a run prints a warning, above a max limit it does not
+// start. Long-lived installs, such as CI runners with a cached state
+// directory, otherwise grow without bound.
+package storage
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/logging"
+	"github.com/ariel-frischer/autospec/internal/output"
+)
+
+// Config holds the size limits and pruning policy (config: storage). Zero
+// values disable the corresponding limit or policy.
+type Config struct {
+	// WarnMB warns when the state directory grows past this size.
+	WarnMB int `koanf:"warn_mb" yaml:"warn_mb" json:"warn_mb"`
+
+	// MaxMB refuses to start runs while the state directory is larger.
+	MaxMB int `koanf:"max_mb" yaml:"max_mb" json:"max_mb"`
+
+	// LogsWarnMB warns when the run logs (runs/) and diagnostic logs (logs/)
+	// grow past this size.
+	LogsWarnMB int `koanf:"logs_warn_mb" yaml:"logs_warn_mb" json:"logs_warn_mb"`
+
+	// LogsMaxMB refuses to start runs while the logs are larger.
+	LogsMaxMB int `koanf:"logs_max_mb" yaml:"logs_max_mb" json:"logs_max_mb"`
+
+	// KeepRuns is how many run logs are kept, newest first.
+	KeepRuns int `koanf:"keep_runs" yaml:"keep_runs" json:"keep_runs"`
+
+	// MaxRunAge deletes run logs last written longer ago than this.
+	MaxRunAge time.Duration `koanf:"max_run_age" yaml:"max_run_age" json:"max_run_age"`
+}
+
+// Enabled reports whether any limit or pruning policy is set.
+func (c Config) Enabled() bool {
+	return c.WarnMB > 0 || c.MaxMB > 0 || c.LogsWarnMB > 0 || c.LogsMaxMB > 0 ||
+		c.KeepRuns > 0 || c.MaxRunAge > 0
+}
+
+// Usage is the disk space used by a state directory.
+type Usage struct {
+	StateBytes int64 // Whole state directory
+	LogsBytes  int64 // runs/ and logs/
+}
+
+// Measure returns the disk space used by stateDir. A missing directory uses
+// none.
+func Measure(stateDir string) (Usage, error) {
+	var u Usage
+	runs := filepath.Join(stateDir, events.RunsDirName)
+	logs := filepath.Join(stateDir, logging.DirName)
+	err := filepath.WalkDir(stateDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		u.StateBytes += info.Size()
+		if within(path, runs) || within(path, logs) {
+			u.LogsBytes += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return Usage{}, fmt.Errorf("measuring state directory: %w", err)
+	}
+	return u, nil
+}
+
+// within reports whether path, found walking the state directory, is
+// inside dir.
+func within(path, dir string) bool {
+	return strings.HasPrefix(path, dir+string(filepath.Separator))
+}
+
+// Check compares u with the limits of c. It returns a warning for each warn
+// limit exceeded, and an ErrStorageLimit error when a max limit is exceeded.
+func (c Config) Check(u Usage) (warnings []string, err error) {
+	if exceeds(u.StateBytes, c.MaxMB) {
+		return nil, apperrors.ErrStorageLimit.Errorf("state directory uses %s, over storage.max_mb (%d MB)", formatMB(u.StateBytes), c.MaxMB)
+	}
+	if exceeds(u.LogsBytes, c.LogsMaxMB) {
+		return nil, apperrors.ErrStorageLimit.Errorf("run and diagnostic logs use %s, over storage.logs_max_mb (%d MB)", formatMB(u.LogsBytes), c.LogsMaxMB)
+	}
+	if exceeds(u.StateBytes, c.WarnMB) {
+		warnings = append(warnings, fmt.Sprintf("state directory uses %s, over storage.warn_mb (%d MB)", formatMB(u.StateBytes), c.WarnMB))
+	}
+	if exceeds(u.LogsBytes, c.LogsWarnMB) {
+		warnings = append(warnings, fmt.Sprintf("run and diagnostic logs use %s, over storage.logs_warn_mb (%d MB)", formatMB(u.LogsBytes), c.LogsWarnMB))
+	}
+	return warnings, nil
+}
+
+// exceeds reports whether size is over a limit in MB (0 = no limit).
+func exceeds(size int64, limitMB int) bool {
+	return limitMB > 0 && size > int64(limitMB)<<20
+}
+
+// formatMB formats size in MB with one decimal.
+func formatMB(size int64) string {
+	return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+}
+
+// PruneResult lists what Prune deleted.
+type PruneResult struct {
+	Removed []string // Run IDs
+	Freed   int64    // Bytes
+}
+
+// Prune deletes the run logs that c's pruning policy does not keep: all but
+// the newest KeepRuns, and those last written before now minus MaxRunAge.
+// The log of keepRunID, the current run, is never deleted.
+func Prune(stateDir string, c Config, keepRunID string, now time.Time) (PruneResult, error) {
+	var result PruneResult
+	if c.KeepRuns <= 0 && c.MaxRunAge <= 0 {
+		return result, nil
+	}
+	runs, err := events.ListRuns(stateDir)
+	if err != nil {
+		return result, err
+	}
+
+	kept := 0
+	for _, run := range runs {
+		if run.RunID == keepRunID {
+			continue
+		}
+		tooMany := c.KeepRuns > 0 && kept >= c.KeepRuns
+		tooOld := c.MaxRunAge > 0 && now.Sub(run.ModTime) > c.MaxRunAge
+		if !tooMany && !tooOld {
+			kept++
+			continue
+		}
+		dir := events.RunDir(stateDir, run.RunID)
+		size := dirSize(dir)
+		if err := os.RemoveAll(dir); err != nil {
+			return result, fmt.Errorf("pruning run log %s: %w", run.RunID, err)
+		}
+		result.Removed = append(result.Removed, run.RunID)
+		result.Freed += size
+	}
+	return result, nil
+}
+
+// dirSize returns the size of the regular files under dir.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+// Guard prunes run logs and checks the limits once per run, before its first
+// agent session. A nil *Guard is valid and checks nothing.
+type Guard struct {
+	stateDir string
+	runID    string
+	cfg      Config
+	now      func() time.Time
+	warn     func(format string, args ...any)
+
+	once sync.Once
+	err  error
+}
+
+// NewGuard returns the guard of the run runID, or nil when cfg sets no limit
+// or policy.
+func NewGuard(stateDir, runID string, cfg Config) *Guard {
+	if stateDir == "" || !cfg.Enabled() {
+		return nil
+	}
+	return &Guard{stateDir: stateDir, runID: runID, cfg: cfg, now: time.Now, warn: output.Default().Warn}
+}
+
+// Check prunes and measures the state directory on its first call, printing
+// warnings, and returns the same ErrStorageLimit error, or nil, on every call.
+// Failing to prune or measure is a warning, not an error.
+func (g *Guard) Check() error {
+	if g == nil {
+		return nil
+	}
+	g.once.Do(func() {
+		if _, err := Prune(g.stateDir, g.cfg, g.runID, g.now()); err != nil {
+			g.warn("%v", err)
+		}
+		usage, err := Measure(g.stateDir)
+		if err != nil {
+			g.warn("%v", err)
+			return
+		}
+		warnings, err := g.cfg.Check(usage)
+		for _, w := range warnings {
+			g.warn("%s", w)
+		}
+		g.err = err
+	})
+	return g.err
+}
@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeRun creates a run log of size bytes last written at modTime.
+func writeRun(t *testing.T, stateDir, runID string, size int, modTime time.Time) {
+	t.Helper()
+	path := events.Path(stateDir, runID)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o644))
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestMeasure(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	writeRun(t, stateDir, "run1", 100, time.Now())
+	require.NoError(t, os.MkdirAll(filepath.Join(stateDir, logging.DirName), 0o755))
+	require.NoError(t, os.WriteFile(logging.FilePath(stateDir), make([]byte, 50), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(stateDir, "history.yaml"), make([]byte, 25), 0o644))
+
+	u, err := Measure(stateDir)
+	require.NoError(t, err)
+	assert.Equal(t, Usage{StateBytes: 175, LogsBytes: 150}, u)
+
+	u, err = Measure(filepath.Join(stateDir, "missing"))
+	require.NoError(t, err)
+	assert.Equal(t, Usage{}, u, "a missing state directory uses nothing")
+}
+
+func TestConfig_Check(t *testing.T) {
+	t.Parallel()
+
+	const mb = 1 << 20
+	tests := map[string]struct {
+		cfg          Config
+		usage        Usage
+		wantWarnings int
+		wantErr      string
+	}{
+		"no limits": {
+			usage: Usage{StateBytes: 900 * mb, LogsBytes: 800 * mb},
+		},
+		"under limits": {
+			cfg:   Config{WarnMB: 500, MaxMB: 1000, LogsWarnMB: 200, LogsMaxMB: 400},
+			usage: Usage{StateBytes: 100 * mb, LogsBytes: 50 * mb},
+		},
+		"over both warn limits": {
+			cfg:          Config{WarnMB: 500, LogsWarnMB: 200},
+			usage:        Usage{StateBytes: 600 * mb, LogsBytes: 300 * mb},
+			wantWarnings: 2,
+		},
+		"over state max": {
+			cfg:     Config{WarnMB: 500, MaxMB: 1000},
+			usage:   Usage{StateBytes: 1100 * mb},
+			wantErr: "state directory uses 1100.0 MB, over storage.max_mb (1000 MB)",
+		},
+		"over logs max": {
+			cfg:     Config{LogsMaxMB: 100},
+			usage:   Usage{StateBytes: 150 * mb, LogsBytes: 150 * mb},
+			wantErr: "run and diagnostic logs use 150.0 MB, over storage.logs_max_mb (100 MB)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			warnings, err := tt.cfg.Check(tt.usage)
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, apperrors.ErrStorageLimit)
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, warnings, tt.wantWarnings)
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	tests := map[string]struct {
+		cfg         Config
+		keepRunID   string
+		wantRemoved []string
+	}{
+		"no policy": {},
+		"keep newest two": {
+			cfg:         Config{KeepRuns: 2},
+			wantRemoved: []string{"run3", "run4"},
+		},
+		"max age": {
+			cfg:         Config{MaxRunAge: 48 * time.Hour},
+			wantRemoved: []string{"run4"},
+		},
+		"current run is kept and not counted": {
+			cfg:         Config{KeepRuns: 1},
+			keepRunID:   "run1",
+			wantRemoved: []string{"run3", "run4"},
+		},
+		"count and age combined": {
+			cfg:         Config{KeepRuns: 3, MaxRunAge: 36 * time.Hour},
+			wantRemoved: []string{"run3", "run4"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			stateDir := t.TempDir()
+			// run1 is the newest, run4 the oldest
+			for i := 1; i <= 4; i++ {
+				writeRun(t, stateDir, fmt.Sprintf("run%d", i), 10, now.Add(-time.Duration(i-1)*24*time.Hour))
+			}
+
+			result, err := Prune(stateDir, tt.cfg, tt.keepRunID, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRemoved, result.Removed)
+			assert.Equal(t, int64(10*len(tt.wantRemoved)), result.Freed)
+
+			runs, err := events.ListRuns(stateDir)
+			require.NoError(t, err)
+			assert.Len(t, runs, 4-len(tt.wantRemoved))
+		})
+	}
+}
+
+func TestNewGuard(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, NewGuard(t.TempDir(), "run", Config{}), "no limit or policy")
+	assert.Nil(t, NewGuard("", "run", Config{WarnMB: 1}), "no state directory")
+	assert.NotNil(t, NewGuard(t.TempDir(), "run", Config{KeepRuns: 1}))
+
+	var g *Guard
+	assert.NoError(t, g.Check(), "a nil guard checks nothing")
+}
+
+func TestGuard_Check(t *testing.T) {
+	t.Parallel()
+
+	stateDir := t.TempDir()
+	now := time.Now()
+	writeRun(t, stateDir, "current", 10, now)
+	writeRun(t, stateDir, "old", 2<<20, now.Add(-time.Hour))
+	writeRun(t, stateDir, "older", 2<<20, now.Add(-2*time.Hour))
+
+	var warnings []string
+	g := NewGuard(stateDir, "current", Config{KeepRuns: 1, LogsWarnMB: 1, MaxMB: 3})
+	g.warn = func(format string, args ...any) { warnings = append(warnings, fmt.Sprintf(format, args...)) }
+
+	// Pruning "older" brings the state directory under max_mb, leaving a warning
+	require.NoError(t, g.Check())
+	assert.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "storage.logs_warn_mb")
+	assert.NoDirExists(t, events.RunDir(stateDir, "older"))
+	assert.DirExists(t, events.RunDir(stateDir, "old"))
+
+	// Later calls reuse the first result
+	writeRun(t, stateDir, "big", 4<<20, now)
+	require.NoError(t, g.Check())
+	assert.Len(t, warnings, 1)
+
+	g = NewGuard(stateDir, "current", Config{MaxMB: 3})
+	g.warn = func(string, ...any) {}
+	assert.ErrorIs(t, g.Check(), apperrors.ErrStorageLimit)
+}
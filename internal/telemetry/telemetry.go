@@ -0,0 +1,183 @@
+// Package telemetry sends an anonymous, opt-in usage ping after each command.
+//
+// Nothing is sent unless the user runs 'autospec telemetry enable', and
+// DO_NOT_TRACK or AUTOSPEC_TELEMETRY=off override that choice. The ping is a
+// fixed schema (Payload) with the autospec version, the operating system,
+// the command name and whether it succeeded: no arguments, paths, spec
+// content, identifiers or IP-derived data are collected by autospec.
+//
+// The consent is stored per user (telemetry.yaml in the user config
+// directory), never in project config, so a repository cannot opt its
+// contributors in.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the version of the Payload schema. It changes whenever a
+// field is added or removed, and the change is listed in the changelog.
+const SchemaVersion = 1
+
+// FileName is the consent file in the user config directory.
+const FileName = "telemetry.yaml"
+
+// Endpoint receives pings. It is set at build time with -ldflags
+// "-X github.com/ariel-frischer/autospec/internal/telemetry.Endpoint=<url>";
+// builds without one never send, even when enabled.
+var Endpoint = ""
+
+// EndpointEnv overrides Endpoint, e.g. to send pings to a self-hosted collector.
+const EndpointEnv = "AUTOSPEC_TELEMETRY_ENDPOINT"
+
+// sendTimeout bounds a ping so a slow or unreachable endpoint never delays
+// the command noticeably.
+const sendTimeout = 2 * time.Second
+
+// Payload is the complete ping. Every field is listed here; there is no
+// free-form or optional data.
+type Payload struct {
+	// Schema is SchemaVersion.
+	Schema int `json:"schema"`
+	// Version is the autospec version, e.g. "0.9.0" or "dev".
+	Version string `json:"version"`
+	// OS is the operating system (GOOS), e.g. "linux".
+	OS string `json:"os"`
+	// Command is the command path without arguments or flags, e.g. "update rollback".
+	Command string `json:"command"`
+	// Success reports whether the command exited without error.
+	Success bool `json:"success"`
+}
+
+// NewPayload returns the ping for command on this system.
+func NewPayload(version, command string, success bool) Payload {
+	return Payload{
+		Schema:  SchemaVersion,
+		Version: version,
+		OS:      runtime.GOOS,
+		Command: command,
+		Success: success,
+	}
+}
+
+// Settings is the stored consent.
+type Settings struct {
+	// Enabled is true only after 'autospec telemetry enable'.
+	Enabled bool `yaml:"enabled"`
+	// UpdatedAt is when the choice was made.
+	UpdatedAt time.Time `yaml:"updated_at,omitempty"`
+}
+
+// Path returns the consent file in the user config directory dir.
+func Path(dir string) string {
+	return filepath.Join(dir, FileName)
+}
+
+// Load reads the consent at path. A missing file means telemetry was never
+// enabled.
+func Load(path string) (Settings, error) {
+	var s Settings
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return s, fmt.Errorf("reading telemetry settings: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("parsing telemetry settings %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save records the consent enabled at path.
+func Save(path string, enabled bool) error {
+	data, err := yaml.Marshal(Settings{Enabled: enabled, UpdatedAt: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("encoding telemetry settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing telemetry settings: %w", err)
+	}
+	return nil
+}
+
+// EnvOptOut returns the environment variable that turns telemetry off
+// regardless of the stored consent (DO_NOT_TRACK, or AUTOSPEC_TELEMETRY set
+// to 0, false, off or no), or "".
+func EnvOptOut() string {
+	if v := os.Getenv("DO_NOT_TRACK"); v != "" && v != "0" {
+		return "DO_NOT_TRACK"
+	}
+	switch strings.ToLower(os.Getenv("AUTOSPEC_TELEMETRY")) {
+	case "0", "false", "off", "no":
+		return "AUTOSPEC_TELEMETRY"
+	}
+	return ""
+}
+
+// ResolveEndpoint returns the endpoint pings go to: EndpointEnv, then the
+// build's Endpoint. Empty means pings are not sent.
+func ResolveEndpoint() string {
+	if v := os.Getenv(EndpointEnv); v != "" {
+		return v
+	}
+	return Endpoint
+}
+
+// Active reports whether a ping would be sent: the user enabled telemetry,
+// no environment variable opts out, and an endpoint is configured.
+func Active(s Settings) bool {
+	return s.Enabled && EnvOptOut() == "" && ResolveEndpoint() != ""
+}
+
+// Send posts p as JSON to endpoint. The request carries no cookies or
+// identifying headers beyond a fixed User-Agent.
+func Send(ctx context.Context, client *http.Client, endpoint string, p Payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "autospec-telemetry")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending ping: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("sending ping: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Record sends the ping for command when telemetry is active for the
+// consent stored in dir. Errors are ignored: telemetry never affects a
+// command's outcome.
+func Record(ctx context.Context, dir, version, command string, success bool) {
+	s, err := Load(Path(dir))
+	if err != nil || !Active(s) {
+		return
+	}
+	_ = Send(ctx, http.DefaultClient, ResolveEndpoint(), NewPayload(version, command, success))
+}
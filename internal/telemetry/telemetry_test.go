@@ -0,0 +1,133 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPayload_Schema(t *testing.T) {
+	t.Parallel()
+
+	data, err := json.Marshal(NewPayload("0.9.0", "update rollback", false))
+	require.NoError(t, err)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(data, &fields))
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	// Adding a field requires bumping SchemaVersion and documenting it
+	assert.Equal(t, []string{"command", "os", "schema", "success", "version"}, keys)
+	assert.Equal(t, map[string]any{
+		"schema":  float64(SchemaVersion),
+		"version": "0.9.0",
+		"os":      runtime.GOOS,
+		"command": "update rollback",
+		"success": false,
+	}, fields)
+}
+
+func TestLoadSave(t *testing.T) {
+	t.Parallel()
+
+	path := Path(filepath.Join(t.TempDir(), "autospec"))
+	s, err := Load(path)
+	require.NoError(t, err)
+	assert.False(t, s.Enabled, "off until enabled")
+
+	require.NoError(t, Save(path, true))
+	s, err = Load(path)
+	require.NoError(t, err)
+	assert.True(t, s.Enabled)
+	assert.False(t, s.UpdatedAt.IsZero())
+
+	require.NoError(t, Save(path, false))
+	s, err = Load(path)
+	require.NoError(t, err)
+	assert.False(t, s.Enabled)
+
+	require.NoError(t, os.WriteFile(path, []byte("enabled: [\n"), 0o644))
+	_, err = Load(path)
+	assert.ErrorContains(t, err, "parsing telemetry settings")
+}
+
+func TestEnvOptOut(t *testing.T) {
+	tests := map[string]struct {
+		doNotTrack string
+		telemetry  string
+		want       string
+	}{
+		"none":                  {},
+		"DO_NOT_TRACK":          {doNotTrack: "1", want: "DO_NOT_TRACK"},
+		"DO_NOT_TRACK zero":     {doNotTrack: "0"},
+		"AUTOSPEC_TELEMETRY":    {telemetry: "off", want: "AUTOSPEC_TELEMETRY"},
+		"AUTOSPEC_TELEMETRY 0":  {telemetry: "0", want: "AUTOSPEC_TELEMETRY"},
+		"AUTOSPEC_TELEMETRY on": {telemetry: "on"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Not parallel: sets environment variables
+			t.Setenv("DO_NOT_TRACK", tt.doNotTrack)
+			t.Setenv("AUTOSPEC_TELEMETRY", tt.telemetry)
+			assert.Equal(t, tt.want, EnvOptOut())
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	// Not parallel: sets environment variables
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+	t.Setenv(EndpointEnv, server.URL)
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv("AUTOSPEC_TELEMETRY", "")
+
+	dir := t.TempDir()
+	Record(context.Background(), dir, "0.9.0", "status", true)
+	assert.Empty(t, bodies, "nothing is sent before opting in")
+
+	require.NoError(t, Save(Path(dir), true))
+	Record(context.Background(), dir, "0.9.0", "status", true)
+	require.Len(t, bodies, 1)
+	assert.JSONEq(t, `{"schema":1,"version":"0.9.0","os":"`+runtime.GOOS+`","command":"status","success":true}`, bodies[0])
+
+	t.Setenv("DO_NOT_TRACK", "1")
+	Record(context.Background(), dir, "0.9.0", "status", true)
+	assert.Len(t, bodies, 1, "DO_NOT_TRACK wins over the stored consent")
+
+	t.Setenv("DO_NOT_TRACK", "")
+	t.Setenv(EndpointEnv, "")
+	assert.False(t, Active(Settings{Enabled: true}), "no endpoint in this build")
+}
+
+func TestSend_Error(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	err := Send(context.Background(), server.Client(), server.URL, NewPayload("dev", "run", true))
+	assert.EqualError(t, err, "sending ping: unexpected status 429")
+}
@@ -0,0 +1,128 @@
+// Package testselect picks the test commands related to the files a task
+// changed, so per-task gates in large repositories run the tests of the
+// touched packages instead of the full suite.
+package testselect
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/codefmt"
+	"github.com/ariel-frischer/autospec/internal/shell"
+)
+
+// Placeholders expanded in a rule's command.
+const (
+	// FilesPlaceholder expands to the matching changed files.
+	FilesPlaceholder = "{{files}}"
+	// DirsPlaceholder expands to the directories of the matching changed
+	// files, as "./dir" ("." for the repository root), e.g. Go packages.
+	DirsPlaceholder = "{{dirs}}"
+)
+
+// Rule maps changed files to the test command that covers them
+// (config key: targeted_tests).
+type Rule struct {
+	// Name labels the rule in output (default: the command's first word).
+	Name string `koanf:"name" yaml:"name" json:"name,omitempty"`
+
+	// Patterns are globs matched against changed files, e.g. "*.go". A
+	// pattern without a slash matches the file name in any directory; one
+	// with a slash matches the path relative to the repository root.
+	Patterns []string `koanf:"patterns" yaml:"patterns" json:"patterns"`
+
+	// Command is run through the shell from the repository root when a
+	// changed file matches, with {{files}} and {{dirs}} expanded, e.g.
+	// "go test {{dirs}}". A command without placeholders runs as is.
+	Command string `koanf:"command" yaml:"command" json:"command"`
+}
+
+// Label returns Name, or the first word of Command.
+func (r Rule) Label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	if fields := strings.Fields(r.Command); len(fields) > 0 {
+		return fields[0]
+	}
+	return "tests"
+}
+
+// Validate checks that the rule has a command and valid patterns.
+func (r Rule) Validate() error {
+	if strings.TrimSpace(r.Command) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if len(r.Patterns) == 0 {
+		return fmt.Errorf("patterns is required (e.g. [\"*.go\"])")
+	}
+	for _, p := range r.Patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// Selection is a rule selected by the changed files, with its command
+// expanded.
+type Selection struct {
+	Rule    string
+	Command string
+	Files   []string
+}
+
+// Select returns, in rule order, the rules that match at least one of the
+// changed files. changed are slash-separated paths relative to the
+// repository root.
+func Select(rules []Rule, changed []string) []Selection {
+	var selected []Selection
+	for _, r := range rules {
+		files := codefmt.MatchPatterns(r.Patterns, changed)
+		if len(files) == 0 {
+			continue
+		}
+		selected = append(selected, Selection{
+			Rule:    r.Label(),
+			Command: Expand(r.Command, files),
+			Files:   files,
+		})
+	}
+	return selected
+}
+
+// Expand replaces the placeholders in command with the files and their
+// directories, quoted for the configured shell.
+func Expand(command string, files []string) string {
+	quotedFiles := make([]string, len(files))
+	for i, f := range files {
+		quotedFiles[i] = shell.Quote(f)
+	}
+	var quotedDirs []string
+	for _, d := range Dirs(files) {
+		quotedDirs = append(quotedDirs, shell.Quote(d))
+	}
+	command = strings.ReplaceAll(command, FilesPlaceholder, strings.Join(quotedFiles, " "))
+	return strings.ReplaceAll(command, DirsPlaceholder, strings.Join(quotedDirs, " "))
+}
+
+// Dirs returns the sorted, unique directories of files as "./dir", or "."
+// for files at the repository root.
+func Dirs(files []string) []string {
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, f := range files {
+		dir := path.Dir(f)
+		if dir != "." {
+			dir = "./" + dir
+		}
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
@@ -0,0 +1,59 @@
+package testselect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRule_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		rule   Rule
+		errMsg string // empty means valid
+	}{
+		"valid":           {rule: Rule{Patterns: []string{"*.go"}, Command: "go test {{dirs}}"}},
+		"missing command": {rule: Rule{Patterns: []string{"*.go"}}, errMsg: "command is required"},
+		"missing pattern": {rule: Rule{Command: "make test"}, errMsg: "patterns is required"},
+		"bad pattern":     {rule: Rule{Patterns: []string{"[a"}, Command: "make test"}, errMsg: "invalid pattern"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.rule.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	t.Parallel()
+
+	rules := []Rule{
+		{Patterns: []string{"*.go"}, Command: "go test {{dirs}}"},
+		{Name: "web", Patterns: []string{"web/*"}, Command: "npx jest --findRelatedTests {{files}}"},
+		{Patterns: []string{"*.py"}, Command: "pytest"},
+	}
+	changed := []string{"README.md", "internal/a/a.go", "internal/a/a_test.go", "main.go", "web/app's.ts"}
+
+	got := Select(rules, changed)
+	assert.Equal(t, []Selection{
+		{
+			Rule:    "go",
+			Command: "go test '.' './internal/a'",
+			Files:   []string{"internal/a/a.go", "internal/a/a_test.go", "main.go"},
+		},
+		{
+			Rule:    "web",
+			Command: `npx jest --findRelatedTests 'web/app'\''s.ts'`,
+			Files:   []string{"web/app's.ts"},
+		},
+	}, got)
+	assert.Empty(t, Select(rules, []string{"docs/guide.md"}))
+}
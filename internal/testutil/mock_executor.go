@@ -6,13 +6,12 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"runtime"
 	"sync"
 	"testing"
 	"time"
 
-	"github.com/ariel-frischer/autospec/internal/cliagent"
 	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/mockagent"
 	"github.com/ariel-frischer/autospec/internal/workflow"
 )
 
@@ -98,7 +97,7 @@ func (b *MockExecutorBuilder) WithArtifactDir(dir string) *MockExecutorBuilder {
 	return b
 }
 
-// WithMockClaudePath sets the path to mock-claude.sh for integration tests.
+// WithMockClaudePath sets the mock agent command for integration tests.
 func (b *MockExecutorBuilder) WithMockClaudePath(path string) *MockExecutorBuilder {
 	b.mockClaudePath = path
 	return b
@@ -448,7 +447,8 @@ func writeArtifact(dir, filename, content string) {
 }
 
 // NewTestOrchestrator creates a WorkflowOrchestrator configured for testing.
-// It uses mock-claude.sh as the Claude command to avoid real API calls.
+// It runs the test binary as the mock agent (see mockagent) to avoid real
+// API calls; the calling package must call mockagent.Main from TestMain.
 // The specsDir parameter should be an isolated temp directory (e.g., t.TempDir()).
 func NewTestOrchestrator(t *testing.T, specsDir string) *workflow.WorkflowOrchestrator {
 	t.Helper()
@@ -460,20 +460,19 @@ func NewTestOrchestrator(t *testing.T, specsDir string) *workflow.WorkflowOrches
 func NewTestOrchestratorWithSpecName(t *testing.T, specsDir, specName string) *workflow.WorkflowOrchestrator {
 	t.Helper()
 
-	// Find the mock-claude.sh script path
-	mockClaudePath := findMockClaudePath(t)
-
 	// Create state directory within the test temp area
 	stateDir := filepath.Join(specsDir, ".autospec", "state")
 	if err := os.MkdirAll(stateDir, 0o755); err != nil {
 		t.Fatalf("failed to create state directory: %v", err)
 	}
 
+	agent, err := mockagent.Agent()
+	if err != nil {
+		t.Fatalf("configuring mock agent: %v", err)
+	}
+
 	cfg := &config.Configuration{
-		CustomAgent: &cliagent.CustomAgentConfig{
-			Command: mockClaudePath,
-			Args:    []string{"{{PROMPT}}"},
-		},
+		CustomAgent:   agent,
 		SpecsDir:      specsDir,
 		StateDir:      stateDir,
 		MaxRetries:    1, // Minimal retries for faster tests
@@ -481,42 +480,13 @@ func NewTestOrchestratorWithSpecName(t *testing.T, specsDir, specName string) *w
 		Timeout:       30, // 30 second timeout for tests
 	}
 
-	// Set environment variables for mock-claude.sh to generate artifacts
+	// Set environment variables for the mock agent to generate artifacts
 	t.Setenv("MOCK_ARTIFACT_DIR", specsDir)
 	t.Setenv("MOCK_SPEC_NAME", specName)
 
 	return workflow.NewWorkflowOrchestrator(cfg)
 }
 
-// findMockClaudePath locates the mock-claude.sh script relative to the repo root.
-func findMockClaudePath(t *testing.T) string {
-	t.Helper()
-
-	// Get the path to the current source file
-	_, currentFile, _, ok := runtime.Caller(0)
-	if !ok {
-		t.Fatal("failed to determine current file location")
-	}
-
-	// Navigate from internal/testutil/ to repo root
-	repoRoot := filepath.Join(filepath.Dir(currentFile), "..", "..")
-
-	// Try the primary location first
-	mockPath := filepath.Join(repoRoot, "mocks", "scripts", "mock-claude.sh")
-	if _, err := os.Stat(mockPath); err == nil {
-		return mockPath
-	}
-
-	// Fallback location
-	mockPath = filepath.Join(repoRoot, "tests", "mocks", "mock-claude.sh")
-	if _, err := os.Stat(mockPath); err == nil {
-		return mockPath
-	}
-
-	t.Fatalf("mock-claude.sh not found at expected locations")
-	return ""
-}
-
 // SetupSpecDirectory creates a test spec directory with the given name.
 // Returns the full path to the spec directory.
 func SetupSpecDirectory(t *testing.T, specsDir, specName string) string {
@@ -0,0 +1,76 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// requestTimeout bounds each tracker API request.
+const requestTimeout = 15 * time.Second
+
+// maxErrorBody caps how much of an error response is included in errors.
+const maxErrorBody = 300
+
+// apiClient sends JSON requests to a tracker API.
+type apiClient struct {
+	http    *http.Client
+	headers map[string]string
+}
+
+func newAPIClient(headers map[string]string) apiClient {
+	return apiClient{http: &http.Client{Timeout: requestTimeout}, headers: headers}
+}
+
+// do sends in as JSON (when non-nil) and decodes the response into out (when
+// non-nil). Non-2xx responses are returned as errors with the start of the body.
+func (c apiClient) do(ctx context.Context, method, url string, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, url, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > maxErrorBody {
+			msg = msg[:maxErrorBody] + "…"
+		}
+		return fmt.Errorf("%s %s: %s: %s", method, url, resp.Status, msg)
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+	return nil
+}
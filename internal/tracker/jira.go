@@ -0,0 +1,86 @@
+package tracker
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// jiraClient talks to the Jira REST API (v2, plain-text descriptions).
+type jiraClient struct {
+	api     apiClient
+	baseURL string
+	cfg     JiraConfig
+}
+
+func newJiraClient(cfg JiraConfig, token string) *jiraClient {
+	auth := base64.StdEncoding.EncodeToString([]byte(cfg.Email + ":" + token))
+	if cfg.IssueType == "" {
+		cfg.IssueType = DefaultJiraIssueType
+	}
+	return &jiraClient{
+		api:     newAPIClient(map[string]string{"Authorization": "Basic " + auth}),
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		cfg:     cfg,
+	}
+}
+
+func (c *jiraClient) issueURL(key, suffix string) string {
+	return fmt.Sprintf("%s/rest/api/2/issue/%s%s", c.baseURL, url.PathEscape(key), suffix)
+}
+
+// CreateIssue creates an issue in the configured project.
+func (c *jiraClient) CreateIssue(ctx context.Context, title, description string) (Issue, error) {
+	req := map[string]any{
+		"fields": map[string]any{
+			"project":     map[string]string{"key": c.cfg.ProjectKey},
+			"summary":     title,
+			"description": description,
+			"issuetype":   map[string]string{"name": c.cfg.IssueType},
+		},
+	}
+	var resp struct {
+		Key string `json:"key"`
+	}
+	if err := c.api.do(ctx, http.MethodPost, c.baseURL+"/rest/api/2/issue", req, &resp); err != nil {
+		return Issue{}, err
+	}
+	if resp.Key == "" {
+		return Issue{}, fmt.Errorf("jira returned no issue key")
+	}
+	return Issue{Key: resp.Key, URL: c.baseURL + "/browse/" + resp.Key}, nil
+}
+
+// Transition applies the workflow transition whose name, or target status
+// name, matches state.
+func (c *jiraClient) Transition(ctx context.Context, key, state string) error {
+	var resp struct {
+		Transitions []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+			To   struct {
+				Name string `json:"name"`
+			} `json:"to"`
+		} `json:"transitions"`
+	}
+	if err := c.api.do(ctx, http.MethodGet, c.issueURL(key, "/transitions"), nil, &resp); err != nil {
+		return err
+	}
+	var available []string
+	for _, tr := range resp.Transitions {
+		if strings.EqualFold(tr.Name, state) || strings.EqualFold(tr.To.Name, state) {
+			req := map[string]any{"transition": map[string]string{"id": tr.ID}}
+			return c.api.do(ctx, http.MethodPost, c.issueURL(key, "/transitions"), req, nil)
+		}
+		available = append(available, tr.Name)
+	}
+	return fmt.Errorf("no transition to %q (available: %s)", state, strings.Join(available, ", "))
+}
+
+// Comment adds a comment to an issue.
+func (c *jiraClient) Comment(ctx context.Context, key, body string) error {
+	return c.api.do(ctx, http.MethodPost, c.issueURL(key, "/comment"), map[string]string{"body": body}, nil)
+}
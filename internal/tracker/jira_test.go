@@ -0,0 +1,66 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJiraClient(t *testing.T) {
+	t.Parallel()
+
+	var requests []string
+	var created, transitioned, commented map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "me@example.com" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			_, _ = w.Write([]byte(`{"id":"10001","key":"PROJ-12"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/rest/api/2/issue/PROJ-12/transitions":
+			_, _ = w.Write([]byte(`{"transitions":[{"id":"11","name":"Start work","to":{"name":"In Progress"}},{"id":"31","name":"Done","to":{"name":"Done"}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/PROJ-12/transitions":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&transitioned))
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue/PROJ-12/comment":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&commented))
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := newJiraClient(JiraConfig{BaseURL: srv.URL + "/", ProjectKey: "PROJ", Email: "me@example.com"}, "secret")
+	ctx := context.Background()
+
+	issue, err := client.CreateIssue(ctx, "US-001: Log in", "As a user...")
+	require.NoError(t, err)
+	assert.Equal(t, Issue{Key: "PROJ-12", URL: srv.URL + "/browse/PROJ-12"}, issue)
+	fields := created["fields"].(map[string]any)
+	assert.Equal(t, "US-001: Log in", fields["summary"])
+	assert.Equal(t, map[string]any{"name": "Story"}, fields["issuetype"])
+	assert.Equal(t, map[string]any{"key": "PROJ"}, fields["project"])
+
+	require.NoError(t, client.Transition(ctx, "PROJ-12", "in progress"))
+	assert.Equal(t, map[string]any{"transition": map[string]any{"id": "11"}}, transitioned)
+
+	err = client.Transition(ctx, "PROJ-12", "Review")
+	assert.ErrorContains(t, err, `no transition to "Review" (available: Start work, Done)`)
+
+	require.NoError(t, client.Comment(ctx, "PROJ-12", "report"))
+	assert.Equal(t, "report", commented["body"])
+
+	err = client.Comment(ctx, "PROJ-99", "report")
+	assert.ErrorContains(t, err, "404")
+}
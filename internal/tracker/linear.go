@@ -0,0 +1,147 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// linearClient talks to the Linear GraphQL API.
+type linearClient struct {
+	api      apiClient
+	endpoint string
+	cfg      LinearConfig
+	states   map[string]string // lower-case workflow state name -> ID, loaded on first use
+}
+
+func newLinearClient(cfg LinearConfig, token, endpoint string) *linearClient {
+	return &linearClient{
+		api:      newAPIClient(map[string]string{"Authorization": token}),
+		endpoint: endpoint,
+		cfg:      cfg,
+	}
+}
+
+// graphQLError is one entry of a GraphQL errors array.
+type graphQLError struct {
+	Message string `json:"message"`
+}
+
+// query runs a GraphQL operation and decodes its data into out.
+func (c *linearClient) query(ctx context.Context, query string, vars map[string]any, out any) error {
+	req := map[string]any{"query": query, "variables": vars}
+	resp := struct {
+		Data   any            `json:"data"`
+		Errors []graphQLError `json:"errors"`
+	}{Data: out}
+	if err := c.api.do(ctx, http.MethodPost, c.endpoint, req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return errors.New(strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// CreateIssue creates an issue in the configured team.
+func (c *linearClient) CreateIssue(ctx context.Context, title, description string) (Issue, error) {
+	const mutation = `mutation($input: IssueCreateInput!) {
+  issueCreate(input: $input) { success issue { identifier url } }
+}`
+	var data struct {
+		IssueCreate struct {
+			Success bool `json:"success"`
+			Issue   struct {
+				Identifier string `json:"identifier"`
+				URL        string `json:"url"`
+			} `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	input := map[string]any{"teamId": c.cfg.TeamID, "title": title, "description": description}
+	if err := c.query(ctx, mutation, map[string]any{"input": input}, &data); err != nil {
+		return Issue{}, err
+	}
+	if !data.IssueCreate.Success || data.IssueCreate.Issue.Identifier == "" {
+		return Issue{}, fmt.Errorf("linear did not create the issue")
+	}
+	return Issue{Key: data.IssueCreate.Issue.Identifier, URL: data.IssueCreate.Issue.URL}, nil
+}
+
+// Transition moves an issue to the team workflow state named state.
+func (c *linearClient) Transition(ctx context.Context, key, state string) error {
+	stateID, err := c.stateID(ctx, state)
+	if err != nil {
+		return err
+	}
+	const mutation = `mutation($id: String!, $input: IssueUpdateInput!) {
+  issueUpdate(id: $id, input: $input) { success }
+}`
+	var data struct {
+		IssueUpdate struct {
+			Success bool `json:"success"`
+		} `json:"issueUpdate"`
+	}
+	vars := map[string]any{"id": key, "input": map[string]any{"stateId": stateID}}
+	if err := c.query(ctx, mutation, vars, &data); err != nil {
+		return err
+	}
+	if !data.IssueUpdate.Success {
+		return fmt.Errorf("linear did not update %s", key)
+	}
+	return nil
+}
+
+// stateID resolves a workflow state name of the configured team.
+func (c *linearClient) stateID(ctx context.Context, state string) (string, error) {
+	if c.states == nil {
+		const q = `query($id: String!) { team(id: $id) { states { nodes { id name } } } }`
+		var data struct {
+			Team struct {
+				States struct {
+					Nodes []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"nodes"`
+				} `json:"states"`
+			} `json:"team"`
+		}
+		if err := c.query(ctx, q, map[string]any{"id": c.cfg.TeamID}, &data); err != nil {
+			return "", fmt.Errorf("loading workflow states: %w", err)
+		}
+		c.states = map[string]string{}
+		for _, n := range data.Team.States.Nodes {
+			c.states[strings.ToLower(n.Name)] = n.ID
+		}
+	}
+	id, ok := c.states[strings.ToLower(state)]
+	if !ok {
+		return "", fmt.Errorf("team has no workflow state %q", state)
+	}
+	return id, nil
+}
+
+// Comment adds a comment to an issue.
+func (c *linearClient) Comment(ctx context.Context, key, body string) error {
+	const mutation = `mutation($input: CommentCreateInput!) {
+  commentCreate(input: $input) { success }
+}`
+	var data struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	input := map[string]any{"issueId": key, "body": body}
+	if err := c.query(ctx, mutation, map[string]any{"input": input}, &data); err != nil {
+		return err
+	}
+	if !data.CommentCreate.Success {
+		return fmt.Errorf("linear did not add the comment to %s", key)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package tracker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinearClient(t *testing.T) {
+	t.Parallel()
+
+	var updates []map[string]any
+	var comment map[string]any
+	stateQueries := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "lin_api_key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		switch {
+		case strings.Contains(req.Query, "issueCreate"):
+			input := req.Variables["input"].(map[string]any)
+			assert.Equal(t, "team-1", input["teamId"])
+			_, _ = w.Write([]byte(`{"data":{"issueCreate":{"success":true,"issue":{"identifier":"ENG-5","url":"https://linear.app/x/issue/ENG-5"}}}}`))
+		case strings.Contains(req.Query, "team("):
+			stateQueries++
+			_, _ = w.Write([]byte(`{"data":{"team":{"states":{"nodes":[{"id":"s1","name":"In Progress"},{"id":"s2","name":"Done"}]}}}}`))
+		case strings.Contains(req.Query, "issueUpdate"):
+			updates = append(updates, req.Variables)
+			_, _ = w.Write([]byte(`{"data":{"issueUpdate":{"success":true}}}`))
+		case strings.Contains(req.Query, "commentCreate"):
+			comment = req.Variables["input"].(map[string]any)
+			_, _ = w.Write([]byte(`{"data":{"commentCreate":{"success":false}},"errors":[{"message":"Entity not found"}]}`))
+		}
+	}))
+	defer srv.Close()
+
+	client := newLinearClient(LinearConfig{TeamID: "team-1"}, "lin_api_key", srv.URL)
+	ctx := context.Background()
+
+	issue, err := client.CreateIssue(ctx, "US-001: Log in", "As a user...")
+	require.NoError(t, err)
+	assert.Equal(t, Issue{Key: "ENG-5", URL: "https://linear.app/x/issue/ENG-5"}, issue)
+
+	require.NoError(t, client.Transition(ctx, "ENG-5", "In Progress"))
+	require.NoError(t, client.Transition(ctx, "ENG-5", "done"))
+	assert.Equal(t, 1, stateQueries, "workflow states are loaded once")
+	require.Len(t, updates, 2)
+	assert.Equal(t, "ENG-5", updates[1]["id"])
+	assert.Equal(t, map[string]any{"stateId": "s2"}, updates[1]["input"])
+
+	err = client.Transition(ctx, "ENG-5", "Review")
+	assert.ErrorContains(t, err, `no workflow state "Review"`)
+
+	err = client.Comment(ctx, "ENG-5", "report")
+	assert.ErrorContains(t, err, "Entity not found")
+	assert.Equal(t, "report", comment["body"])
+}
@@ -0,0 +1,224 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/secrets"
+)
+
+// Issue hosts that feature descriptions can be imported from.
+const (
+	SourceGitHub = "github"
+	SourceGitLab = "gitlab"
+)
+
+// SourceRef identifies an issue on GitHub or GitLab.
+type SourceRef struct {
+	Provider string // SourceGitHub or SourceGitLab
+	APIBase  string // e.g. https://api.github.com, https://gitlab.com/api/v4
+	Project  string // owner/repo on GitHub, the full group path on GitLab
+	Number   int
+}
+
+// SourceIssue is an issue imported as a feature description.
+type SourceIssue struct {
+	Title  string
+	Body   string
+	Labels []string
+	URL    string
+}
+
+// ParseSourceRef resolves an issue reference for provider. ref is an issue
+// number ("123" or "#123"), "project#123", or an issue URL. Bare numbers are
+// looked up in the project of remoteURL (the origin remote).
+func ParseSourceRef(provider, ref, remoteURL string) (SourceRef, error) {
+	if provider != SourceGitHub && provider != SourceGitLab {
+		return SourceRef{}, fmt.Errorf("unknown issue host %q", provider)
+	}
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "https://") || strings.HasPrefix(ref, "http://") {
+		return parseIssueURL(provider, ref)
+	}
+
+	project, num, hasProject := strings.Cut(ref, "#")
+	if !hasProject {
+		project, num = "", ref
+	}
+	number, err := strconv.Atoi(num)
+	if err != nil || number <= 0 {
+		return SourceRef{}, fmt.Errorf("invalid issue %q: want a number, project#number or an issue URL", ref)
+	}
+
+	host := defaultHost(provider)
+	if project == "" {
+		if remoteURL == "" {
+			return SourceRef{}, fmt.Errorf("issue %q has no project and the repository has no origin remote", ref)
+		}
+		host, project, err = parseRemote(remoteURL)
+		if err != nil {
+			return SourceRef{}, err
+		}
+	}
+	return SourceRef{Provider: provider, APIBase: apiBase(provider, host), Project: project, Number: number}, nil
+}
+
+// parseIssueURL parses https://github.com/o/r/issues/N and
+// https://gitlab.com/group/proj/-/issues/N.
+func parseIssueURL(provider, raw string) (SourceRef, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return SourceRef{}, fmt.Errorf("invalid issue URL: %w", err)
+	}
+	path := strings.Trim(u.Path, "/")
+	sep := "/issues/"
+	if provider == SourceGitLab {
+		sep = "/-/issues/"
+	}
+	project, num, ok := strings.Cut(path, sep)
+	number, numErr := strconv.Atoi(num)
+	if !ok || project == "" || numErr != nil || number <= 0 {
+		return SourceRef{}, fmt.Errorf("%s is not a %s issue URL", raw, provider)
+	}
+	return SourceRef{Provider: provider, APIBase: apiBase(provider, u.Host), Project: project, Number: number}, nil
+}
+
+// parseRemote extracts the host and project path from a git remote URL in
+// scp (git@host:owner/repo.git) or URL (https://, ssh://) form.
+func parseRemote(remote string) (host, project string, err error) {
+	remote = strings.TrimSpace(remote)
+	if strings.Contains(remote, "://") {
+		u, parseErr := url.Parse(remote)
+		if parseErr != nil {
+			return "", "", fmt.Errorf("parsing origin remote: %w", parseErr)
+		}
+		host, project = u.Hostname(), u.Path
+	} else if at, path, ok := strings.Cut(remote, ":"); ok {
+		host, project = at[strings.LastIndex(at, "@")+1:], path
+	}
+	project = strings.TrimSuffix(strings.Trim(project, "/"), ".git")
+	if host == "" || !strings.Contains(project, "/") {
+		return "", "", fmt.Errorf("cannot find a project in origin remote %q", remote)
+	}
+	return host, project, nil
+}
+
+func defaultHost(provider string) string {
+	if provider == SourceGitLab {
+		return "gitlab.com"
+	}
+	return "github.com"
+}
+
+// apiBase returns the REST API root for host, including GitHub Enterprise
+// and self-managed GitLab.
+func apiBase(provider, host string) string {
+	switch {
+	case provider == SourceGitLab:
+		return "https://" + host + "/api/v4"
+	case host == "github.com":
+		return "https://api.github.com"
+	default:
+		return "https://" + host + "/api/v3"
+	}
+}
+
+// FetchSourceIssue fetches an issue's title, body and labels. A token from
+// GITHUB_TOKEN (or GH_TOKEN) / GITLAB_TOKEN, or the github-token / gitlab-token
+// keychain secret, is sent when set and the API host is trusted: github.com,
+// gitlab.com or one of hosts. Other hosts come from an issue URL or the
+// origin remote and may not be the user's, so they are asked without
+// credentials; public issues need none.
+func FetchSourceIssue(ctx context.Context, ref SourceRef, hosts []string) (*SourceIssue, error) {
+	trusted := trustedHost(ref, hosts)
+	if ref.Provider == SourceGitLab {
+		return fetchGitLabIssue(ctx, ref, trusted)
+	}
+	return fetchGitHubIssue(ctx, ref, trusted)
+}
+
+// trustedHost reports whether the token of ref's provider may be sent to
+// its API host.
+func trustedHost(ref SourceRef, hosts []string) bool {
+	u, err := url.Parse(ref.APIBase)
+	if err != nil || u.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case ref.Provider == SourceGitHub && host == "api.github.com",
+		ref.Provider == SourceGitLab && host == "gitlab.com":
+		return true
+	}
+	for _, h := range hosts {
+		if strings.EqualFold(strings.TrimSpace(h), host) {
+			return true
+		}
+	}
+	return false
+}
+
+func fetchGitHubIssue(ctx context.Context, ref SourceRef, trusted bool) (*SourceIssue, error) {
+	headers := map[string]string{"Accept": "application/vnd.github+json"}
+	if trusted {
+		if token, _ := secrets.Get(secrets.GitHubToken, "GITHUB_TOKEN", "GH_TOKEN"); token != "" {
+			headers["Authorization"] = "Bearer " + token
+		}
+	}
+	var resp struct {
+		Title   string `json:"title"`
+		Body    string `json:"body"`
+		HTMLURL string `json:"html_url"`
+		Labels  []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/issues/%d", ref.APIBase, ref.Project, ref.Number)
+	if err := newAPIClient(headers).do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, fmt.Errorf("fetching GitHub issue %s#%d: %w", ref.Project, ref.Number, err)
+	}
+	issue := &SourceIssue{Title: resp.Title, Body: resp.Body, URL: resp.HTMLURL}
+	for _, l := range resp.Labels {
+		issue.Labels = append(issue.Labels, l.Name)
+	}
+	return issue, nil
+}
+
+func fetchGitLabIssue(ctx context.Context, ref SourceRef, trusted bool) (*SourceIssue, error) {
+	headers := map[string]string{}
+	if trusted {
+		if token, _ := secrets.Get(secrets.GitLabToken, "GITLAB_TOKEN"); token != "" {
+			headers["PRIVATE-TOKEN"] = token
+		}
+	}
+	var resp struct {
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		WebURL      string   `json:"web_url"`
+		Labels      []string `json:"labels"`
+	}
+	endpoint := fmt.Sprintf("%s/projects/%s/issues/%d", ref.APIBase, url.PathEscape(ref.Project), ref.Number)
+	if err := newAPIClient(headers).do(ctx, http.MethodGet, endpoint, nil, &resp); err != nil {
+		return nil, fmt.Errorf("fetching GitLab issue %s#%d: %w", ref.Project, ref.Number, err)
+	}
+	return &SourceIssue{Title: resp.Title, Body: resp.Description, Labels: resp.Labels, URL: resp.WebURL}, nil
+}
+
+// FeatureDescription renders the issue as a specify feature description.
+func (i *SourceIssue) FeatureDescription() string {
+	var sb strings.Builder
+	sb.WriteString(i.Title)
+	if body := strings.TrimSpace(i.Body); body != "" {
+		sb.WriteString("\n\n" + body)
+	}
+	sb.WriteString("\n")
+	if len(i.Labels) > 0 {
+		sb.WriteString("\nLabels: " + strings.Join(i.Labels, ", "))
+	}
+	sb.WriteString("\nSource: " + i.URL)
+	return sb.String()
+}
@@ -0,0 +1,166 @@
+package tracker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSourceRef(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		provider string
+		ref      string
+		remote   string
+		want     SourceRef
+		wantErr  string
+	}{
+		"github number from ssh remote": {
+			provider: SourceGitHub, ref: "123", remote: "git@github.com:acme/app.git",
+			want: SourceRef{Provider: SourceGitHub, APIBase: "https://api.github.com", Project: "acme/app", Number: 123},
+		},
+		"github hash number from https remote": {
+			provider: SourceGitHub, ref: "#7", remote: "https://github.com/acme/app",
+			want: SourceRef{Provider: SourceGitHub, APIBase: "https://api.github.com", Project: "acme/app", Number: 7},
+		},
+		"github enterprise remote": {
+			provider: SourceGitHub, ref: "7", remote: "https://git.acme.io/acme/app.git",
+			want: SourceRef{Provider: SourceGitHub, APIBase: "https://git.acme.io/api/v3", Project: "acme/app", Number: 7},
+		},
+		"github project ref": {
+			provider: SourceGitHub, ref: "other/repo#9",
+			want: SourceRef{Provider: SourceGitHub, APIBase: "https://api.github.com", Project: "other/repo", Number: 9},
+		},
+		"github url": {
+			provider: SourceGitHub, ref: "https://github.com/acme/app/issues/42",
+			want: SourceRef{Provider: SourceGitHub, APIBase: "https://api.github.com", Project: "acme/app", Number: 42},
+		},
+		"gitlab nested group from ssh url remote": {
+			provider: SourceGitLab, ref: "5", remote: "ssh://git@gitlab.acme.io/team/sub/app.git",
+			want: SourceRef{Provider: SourceGitLab, APIBase: "https://gitlab.acme.io/api/v4", Project: "team/sub/app", Number: 5},
+		},
+		"gitlab url": {
+			provider: SourceGitLab, ref: "https://gitlab.com/team/app/-/issues/3",
+			want: SourceRef{Provider: SourceGitLab, APIBase: "https://gitlab.com/api/v4", Project: "team/app", Number: 3},
+		},
+		"not a number":          {provider: SourceGitHub, ref: "abc", wantErr: "invalid issue"},
+		"no remote":             {provider: SourceGitHub, ref: "12", wantErr: "no origin remote"},
+		"bad remote":            {provider: SourceGitHub, ref: "12", remote: "/srv/repo", wantErr: "cannot find a project"},
+		"gitlab url for github": {provider: SourceGitHub, ref: "https://gitlab.com/team/app/-/merge_requests/3", wantErr: "not a github issue URL"},
+		"unknown provider":      {provider: "bitbucket", ref: "1", wantErr: "unknown issue host"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseSourceRef(tt.provider, tt.ref, tt.remote)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFetchSourceIssue_GitHub(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "ghp_test")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/acme/app/issues/42", r.URL.Path)
+		assert.Equal(t, "Bearer ghp_test", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"title":"Dark mode","body":"Follow the system theme.","html_url":"https://github.com/acme/app/issues/42","labels":[{"name":"ui"},{"name":"feature"}]}`))
+	}))
+	defer srv.Close()
+
+	// The test server stands in for a GitHub Enterprise host listed in config
+	issue, err := FetchSourceIssue(context.Background(), SourceRef{Provider: SourceGitHub, APIBase: srv.URL, Project: "acme/app", Number: 42}, []string{"127.0.0.1"})
+	require.NoError(t, err)
+	assert.Equal(t, &SourceIssue{
+		Title:  "Dark mode",
+		Body:   "Follow the system theme.",
+		Labels: []string{"ui", "feature"},
+		URL:    "https://github.com/acme/app/issues/42",
+	}, issue)
+	assert.Equal(t, "Dark mode\n\nFollow the system theme.\n\nLabels: ui, feature\nSource: https://github.com/acme/app/issues/42",
+		issue.FeatureDescription())
+}
+
+func TestFetchSourceIssue_GitLab(t *testing.T) {
+	t.Setenv("GITLAB_TOKEN", "")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("PRIVATE-TOKEN"))
+		if r.URL.EscapedPath() != "/projects/team%2Fapp/issues/3" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"title":"Export CSV","description":"","web_url":"https://gitlab.com/team/app/-/issues/3","labels":[]}`))
+	}))
+	defer srv.Close()
+
+	ref := SourceRef{Provider: SourceGitLab, APIBase: srv.URL, Project: "team/app", Number: 3}
+	issue, err := FetchSourceIssue(context.Background(), ref, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Export CSV\n\nSource: https://gitlab.com/team/app/-/issues/3", issue.FeatureDescription())
+
+	ref.APIBase = srv.URL + "/missing"
+	_, err = FetchSourceIssue(context.Background(), ref, nil)
+	assert.ErrorContains(t, err, "fetching GitLab issue team/app#3")
+}
+
+func TestFetchSourceIssue_UntrustedHostGetsNoToken(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "ghp_secret")
+	t.Setenv("GITLAB_TOKEN", "glpat_secret")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"), "token sent to an unknown host")
+		assert.Empty(t, r.Header.Get("PRIVATE-TOKEN"), "token sent to an unknown host")
+		_, _ = w.Write([]byte(`{"title":"Public issue"}`))
+	}))
+	defer srv.Close()
+
+	for _, provider := range []string{SourceGitHub, SourceGitLab} {
+		ref := SourceRef{Provider: provider, APIBase: srv.URL, Project: "evil/app", Number: 1}
+		issue, err := FetchSourceIssue(context.Background(), ref, []string{"github.acme.com"})
+		require.NoError(t, err, provider)
+		assert.Equal(t, "Public issue", issue.Title)
+	}
+}
+
+func TestTrustedHost(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		ref   SourceRef
+		hosts []string
+		want  bool
+	}{
+		"github.com":             {ref: SourceRef{Provider: SourceGitHub, APIBase: "https://api.github.com"}, want: true},
+		"gitlab.com":             {ref: SourceRef{Provider: SourceGitLab, APIBase: "https://gitlab.com/api/v4"}, want: true},
+		"gitlab token to github": {ref: SourceRef{Provider: SourceGitLab, APIBase: "https://api.github.com"}},
+		"unknown enterprise":     {ref: SourceRef{Provider: SourceGitHub, APIBase: "https://evil.example/api/v3"}},
+		"listed enterprise": {
+			ref:   SourceRef{Provider: SourceGitHub, APIBase: "https://GitHub.Acme.com/api/v3"},
+			hosts: []string{"github.acme.com"},
+			want:  true,
+		},
+		"lookalike host": {
+			ref:   SourceRef{Provider: SourceGitHub, APIBase: "https://api.github.com.evil.example"},
+			hosts: []string{"github.acme.com"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, trustedHost(tt.ref, tt.hosts))
+		})
+	}
+}
@@ -0,0 +1,438 @@
+// Package tracker syncs specs with an issue tracker (Jira or Linear).
+//
+// After specify, each user story gets an issue, unless it is already linked
+// in the spec's issues.yaml. As phases complete, issues move to the configured
+// in-progress and done states based on the story's task completion, and when
+// the spec is completed its completion report is posted as a comment. API
+// tokens are read from environment variables or the OS keychain, never from
+// config files.
+//
+// It also imports GitHub and GitLab issues as feature descriptions for
+// specify --from-issue (see source.go).
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/report"
+	"github.com/ariel-frischer/autospec/internal/secrets"
+	autospecyaml "github.com/ariel-frischer/autospec/internal/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported providers.
+const (
+	ProviderJira   = "jira"
+	ProviderLinear = "linear"
+)
+
+// Providers lists the accepted values of issue_tracker.provider.
+var Providers = []string{ProviderJira, ProviderLinear}
+
+// LinksFileName is the file in a spec directory that maps user stories to issues.
+const LinksFileName = "issues.yaml"
+
+// Defaults for unset config values.
+const (
+	DefaultInProgressState = "In Progress"
+	DefaultDoneState       = "Done"
+	DefaultJiraTokenEnv    = "JIRA_API_TOKEN"
+	DefaultJiraIssueType   = "Story"
+	DefaultLinearTokenEnv  = "LINEAR_API_KEY"
+	defaultLinearAPI       = "https://api.linear.app/graphql"
+)
+
+// Config configures the issue tracker integration (config key: issue_tracker).
+type Config struct {
+	// Provider is "jira" or "linear"; empty disables the integration
+	Provider string `koanf:"provider" yaml:"provider" json:"provider"`
+
+	// InProgressState is the state (Jira: transition or status name) for stories with completed tasks
+	InProgressState string `koanf:"in_progress_state" yaml:"in_progress_state" json:"in_progress_state"`
+
+	// DoneState is the state for stories whose tasks are all completed
+	DoneState string `koanf:"done_state" yaml:"done_state" json:"done_state"`
+
+	// Jira configures the Jira Cloud or Server REST API
+	Jira JiraConfig `koanf:"jira" yaml:"jira" json:"jira"`
+
+	// Linear configures the Linear GraphQL API
+	Linear LinearConfig `koanf:"linear" yaml:"linear" json:"linear"`
+
+	// GitHub configures issue imports from GitHub (specify --from-issue)
+	GitHub SourceConfig `koanf:"github" yaml:"github" json:"github"`
+
+	// GitLab configures issue imports from GitLab (specify --from-gitlab-issue)
+	GitLab SourceConfig `koanf:"gitlab" yaml:"gitlab" json:"gitlab"`
+}
+
+// SourceConfig configures issue imports from a GitHub or GitLab host.
+type SourceConfig struct {
+	// Hosts are self-hosted instances (GitHub Enterprise, self-managed
+	// GitLab) that the token may be sent to, e.g. github.acme.com.
+	// github.com and gitlab.com are always trusted.
+	Hosts []string `koanf:"hosts" yaml:"hosts" json:"hosts"`
+}
+
+// SourceHosts returns the configured hosts of an issue source provider.
+func (c Config) SourceHosts(provider string) []string {
+	if provider == SourceGitLab {
+		return c.GitLab.Hosts
+	}
+	return c.GitHub.Hosts
+}
+
+// JiraConfig configures issue creation in a Jira project.
+type JiraConfig struct {
+	// BaseURL is the site URL, e.g. https://example.atlassian.net
+	BaseURL string `koanf:"base_url" yaml:"base_url" json:"base_url"`
+
+	// ProjectKey is the project new issues are created in, e.g. PROJ
+	ProjectKey string `koanf:"project_key" yaml:"project_key" json:"project_key"`
+
+	// IssueType is the type of created issues (default: Story)
+	IssueType string `koanf:"issue_type" yaml:"issue_type" json:"issue_type"`
+
+	// Email is the account the API token belongs to
+	Email string `koanf:"email" yaml:"email" json:"email"`
+
+	// TokenEnv names the environment variable holding the API token (default: JIRA_API_TOKEN)
+	TokenEnv string `koanf:"token_env" yaml:"token_env" json:"token_env"`
+}
+
+// LinearConfig configures issue creation in a Linear team.
+type LinearConfig struct {
+	// TeamID is the ID of the team new issues are created in
+	TeamID string `koanf:"team_id" yaml:"team_id" json:"team_id"`
+
+	// TokenEnv names the environment variable holding the API key (default: LINEAR_API_KEY)
+	TokenEnv string `koanf:"token_env" yaml:"token_env" json:"token_env"`
+}
+
+// Validate checks that a configured provider has the settings it needs.
+func (c Config) Validate() error {
+	switch c.Provider {
+	case "":
+		return nil
+	case ProviderJira:
+		if c.Jira.BaseURL == "" {
+			return errors.New("jira.base_url is required")
+		}
+		// The API token is sent with every request, so plain http is only
+		// accepted for a server on the local machine
+		u, err := url.Parse(c.Jira.BaseURL)
+		if err != nil || u.Host == "" || (u.Scheme != "https" && (u.Scheme != "http" || !loopback(u.Hostname()))) {
+			return fmt.Errorf("jira.base_url %q must be an https URL (http is only allowed for localhost)", c.Jira.BaseURL)
+		}
+		if c.Jira.ProjectKey == "" {
+			return errors.New("jira.project_key is required")
+		}
+		if c.Jira.Email == "" {
+			return errors.New("jira.email is required")
+		}
+	case ProviderLinear:
+		if c.Linear.TeamID == "" {
+			return errors.New("linear.team_id is required")
+		}
+	default:
+		return fmt.Errorf("provider %q must be one of: %s", c.Provider, strings.Join(Providers, ", "))
+	}
+	return nil
+}
+
+// loopback reports whether host names the local machine.
+func loopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// Issue identifies an issue in the tracker.
+type Issue struct {
+	Key string // Jira issue key or Linear identifier, e.g. PROJ-12
+	URL string
+}
+
+// Client is the provider API used by Tracker.
+type Client interface {
+	CreateIssue(ctx context.Context, title, description string) (Issue, error)
+	Transition(ctx context.Context, key, state string) error
+	Comment(ctx context.Context, key, body string) error
+}
+
+// Link records the issue of one user story.
+type Link struct {
+	Key      string `yaml:"key"`
+	URL      string `yaml:"url,omitempty"`
+	State    string `yaml:"state,omitempty"`    // Last state autospec moved the issue to
+	Reported bool   `yaml:"reported,omitempty"` // Completion report already posted
+}
+
+// Links is the content of issues.yaml. Adding a story with only a key links
+// an existing issue instead of creating one.
+type Links struct {
+	Provider string          `yaml:"provider"`
+	Stories  map[string]Link `yaml:"stories"`
+}
+
+// LoadLinks reads issues.yaml from specDir; a missing file yields no links.
+func LoadLinks(specDir string) (*Links, error) {
+	links := &Links{Stories: map[string]Link{}}
+	data, err := os.ReadFile(filepath.Join(specDir, LinksFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return links, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", LinksFileName, err)
+	}
+	if err := yaml.Unmarshal(data, links); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", LinksFileName, err)
+	}
+	if links.Stories == nil {
+		links.Stories = map[string]Link{}
+	}
+	return links, nil
+}
+
+// save writes the links to specDir/issues.yaml.
+func (l *Links) save(specDir string) error {
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("serializing %s: %w", LinksFileName, err)
+	}
+	if err := fsutil.WriteFile(filepath.Join(specDir, LinksFileName), data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", LinksFileName, err)
+	}
+	return nil
+}
+
+// Tracker syncs spec user stories with issues. A nil Tracker does nothing.
+type Tracker struct {
+	cfg    Config
+	client Client
+}
+
+// New creates a Tracker for the configured provider, reading the API token
+// from its environment variable. It returns nil when no provider is set.
+func New(cfg Config) (*Tracker, error) {
+	if cfg.Provider == "" {
+		return nil, nil
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	var client Client
+	switch cfg.Provider {
+	case ProviderJira:
+		token, err := loadToken(secrets.JiraToken, cfg.Jira.TokenEnv, DefaultJiraTokenEnv)
+		if err != nil {
+			return nil, err
+		}
+		client = newJiraClient(cfg.Jira, token)
+	case ProviderLinear:
+		token, err := loadToken(secrets.LinearToken, cfg.Linear.TokenEnv, DefaultLinearTokenEnv)
+		if err != nil {
+			return nil, err
+		}
+		client = newLinearClient(cfg.Linear, token, defaultLinearAPI)
+	}
+	return NewWithClient(cfg, client), nil
+}
+
+// NewWithClient creates a Tracker that uses client for API calls.
+func NewWithClient(cfg Config, client Client) *Tracker {
+	if cfg.InProgressState == "" {
+		cfg.InProgressState = DefaultInProgressState
+	}
+	if cfg.DoneState == "" {
+		cfg.DoneState = DefaultDoneState
+	}
+	return &Tracker{cfg: cfg, client: client}
+}
+
+// loadToken reads the API token from the environment variable env, or
+// fallback when env is empty, and then from the keychain secret.
+func loadToken(secret, env, fallback string) (string, error) {
+	if env == "" {
+		env = fallback
+	}
+	token, err := secrets.Get(secret, env)
+	if err != nil {
+		return "", fmt.Errorf("issue tracker token not set: %w", err)
+	}
+	return token, nil
+}
+
+// LinkStories creates an issue for every user story in specDir's spec.yaml
+// that has no link yet, and returns how many were created. Links are saved
+// after each issue, so a failure part-way keeps the issues already created.
+func (t *Tracker) LinkStories(ctx context.Context, specDir string) (int, error) {
+	if t == nil {
+		return 0, nil
+	}
+	spec, err := loadSpec(specDir)
+	if err != nil {
+		return 0, err
+	}
+	links, err := LoadLinks(specDir)
+	if err != nil {
+		return 0, err
+	}
+	links.Provider = t.cfg.Provider
+
+	created := 0
+	for _, story := range spec.UserStories {
+		if links.Stories[story.ID].Key != "" {
+			continue
+		}
+		issue, err := t.client.CreateIssue(ctx, storyTitle(story), storyDescription(story, specDir))
+		if err != nil {
+			return created, fmt.Errorf("creating issue for %s: %w", story.ID, err)
+		}
+		links.Stories[story.ID] = Link{Key: issue.Key, URL: issue.URL}
+		if err := links.save(specDir); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// SyncProgress moves linked issues to the in-progress state once their story
+// has a completed task, and to the done state once all of its tasks are
+// completed. Issues already moved to a state are not moved again, and done
+// issues are never moved back. Returns how many issues were transitioned.
+func (t *Tracker) SyncProgress(ctx context.Context, specDir string) (int, error) {
+	if t == nil {
+		return 0, nil
+	}
+	links, err := LoadLinks(specDir)
+	if err != nil || len(links.Stories) == 0 {
+		return 0, err
+	}
+	r, err := report.Build(report.Options{SpecDir: specDir})
+	if err != nil {
+		return 0, err
+	}
+
+	moved := 0
+	var errs []error
+	for _, story := range r.Stories {
+		link, ok := links.Stories[story.ID]
+		if !ok || link.Key == "" || link.State == t.cfg.DoneState {
+			continue
+		}
+		state := t.targetState(story)
+		if state == "" || state == link.State {
+			continue
+		}
+		if err := t.client.Transition(ctx, link.Key, state); err != nil {
+			errs = append(errs, fmt.Errorf("moving %s to %q: %w", link.Key, state, err))
+			continue
+		}
+		link.State = state
+		links.Stories[story.ID] = link
+		moved++
+	}
+	if moved > 0 {
+		if err := links.save(specDir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return moved, errors.Join(errs...)
+}
+
+// targetState returns the state a story's issue should be in, or "".
+func (t *Tracker) targetState(story report.StoryReport) string {
+	switch {
+	case story.Delivered:
+		return t.cfg.DoneState
+	case story.CompletedTasks > 0:
+		return t.cfg.InProgressState
+	}
+	return ""
+}
+
+// PostReport adds body as a comment to every linked issue that has not
+// received the completion report yet, and returns how many were commented.
+func (t *Tracker) PostReport(ctx context.Context, specDir, body string) (int, error) {
+	if t == nil {
+		return 0, nil
+	}
+	links, err := LoadLinks(specDir)
+	if err != nil || len(links.Stories) == 0 {
+		return 0, err
+	}
+
+	posted := 0
+	var errs []error
+	for _, id := range slices.Sorted(maps.Keys(links.Stories)) {
+		link := links.Stories[id]
+		if link.Key == "" || link.Reported {
+			continue
+		}
+		if err := t.client.Comment(ctx, link.Key, body); err != nil {
+			errs = append(errs, fmt.Errorf("commenting on %s: %w", link.Key, err))
+			continue
+		}
+		link.Reported = true
+		links.Stories[id] = link
+		posted++
+	}
+	if posted > 0 {
+		if err := links.save(specDir); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return posted, errors.Join(errs...)
+}
+
+// loadSpec reads spec.yaml from specDir.
+func loadSpec(specDir string) (*autospecyaml.SpecArtifact, error) {
+	data, err := os.ReadFile(filepath.Join(specDir, "spec.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("reading spec.yaml: %w", err)
+	}
+	var spec autospecyaml.SpecArtifact
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("parsing spec.yaml: %w", err)
+	}
+	return &spec, nil
+}
+
+// storyTitle is the issue summary for a user story.
+func storyTitle(story autospecyaml.UserStory) string {
+	return fmt.Sprintf("%s: %s", story.ID, story.Title)
+}
+
+// storyDescription is the plain-text issue body for a user story.
+func storyDescription(story autospecyaml.UserStory, specDir string) string {
+	var sb strings.Builder
+	if story.AsA != "" {
+		fmt.Fprintf(&sb, "As a %s, I want %s, so that %s.\n\n", story.AsA, story.IWant, story.SoThat)
+	}
+	if len(story.AcceptanceScenarios) > 0 {
+		sb.WriteString("Acceptance scenarios:\n")
+		for _, sc := range story.AcceptanceScenarios {
+			fmt.Fprintf(&sb, "- Given %s, when %s, then %s\n", sc.Given, sc.When, sc.Then)
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "Created by autospec from %s.", filepath.Join(specDir, "spec.yaml"))
+	if story.Priority != "" {
+		fmt.Fprintf(&sb, " Priority: %s.", story.Priority)
+	}
+	return sb.String()
+}
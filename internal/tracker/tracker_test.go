@@ -0,0 +1,269 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const trackerSpecYAML = `feature:
+  branch: "001-login"
+  status: "Draft"
+  input: "Add login and logout"
+user_stories:
+  - id: "US-001"
+    title: "Log in"
+    priority: "P1"
+    as_a: "user"
+    i_want: "to log in"
+    so_that: "I can see my account"
+    acceptance_scenarios:
+      - given: "a registered user"
+        when: "they submit valid credentials"
+        then: "they are logged in"
+  - id: "US-002"
+    title: "Log out"
+    priority: "P2"
+`
+
+const trackerTasksYAML = `phases:
+  - number: 1
+    title: "Core"
+    tasks:
+      - id: "T001"
+        title: "Login handler"
+        status: "Completed"
+        type: "implementation"
+        story_id: "US-001"
+      - id: "T002"
+        title: "Logout handler"
+        status: "Completed"
+        type: "implementation"
+        story_id: "US-002"
+      - id: "T003"
+        title: "Logout button"
+        status: "Pending"
+        type: "implementation"
+        story_id: "US-002"
+`
+
+// fakeClient records tracker API calls.
+type fakeClient struct {
+	created     []string
+	transitions []string
+	comments    []string
+	failOn      string
+}
+
+func (f *fakeClient) CreateIssue(_ context.Context, title, _ string) (Issue, error) {
+	if f.failOn == "create" {
+		return Issue{}, errors.New("boom")
+	}
+	f.created = append(f.created, title)
+	key := fmt.Sprintf("PROJ-%d", len(f.created))
+	return Issue{Key: key, URL: "https://example.test/browse/" + key}, nil
+}
+
+func (f *fakeClient) Transition(_ context.Context, key, state string) error {
+	if f.failOn == "transition" {
+		return errors.New("boom")
+	}
+	f.transitions = append(f.transitions, key+" -> "+state)
+	return nil
+}
+
+func (f *fakeClient) Comment(_ context.Context, key, _ string) error {
+	f.comments = append(f.comments, key)
+	return nil
+}
+
+func writeTrackerFixture(t *testing.T, withTasks bool) string {
+	t.Helper()
+	specDir := filepath.Join(t.TempDir(), "001-login")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(trackerSpecYAML), 0o644))
+	if withTasks {
+		require.NoError(t, os.WriteFile(filepath.Join(specDir, "tasks.yaml"), []byte(trackerTasksYAML), 0o644))
+	}
+	return specDir
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	jira := JiraConfig{BaseURL: "https://example.atlassian.net", ProjectKey: "PROJ", Email: "me@example.com"}
+	tests := map[string]struct {
+		cfg     Config
+		wantErr string
+	}{
+		"disabled":          {cfg: Config{}},
+		"jira":              {cfg: Config{Provider: ProviderJira, Jira: jira}},
+		"linear":            {cfg: Config{Provider: ProviderLinear, Linear: LinearConfig{TeamID: "team"}}},
+		"unknown provider":  {cfg: Config{Provider: "github"}, wantErr: "must be one of"},
+		"jira missing url":  {cfg: Config{Provider: ProviderJira}, wantErr: "base_url is required"},
+		"jira bad url":      {cfg: Config{Provider: ProviderJira, Jira: JiraConfig{BaseURL: "example.net"}}, wantErr: "https URL"},
+		"jira plain http":   {cfg: Config{Provider: ProviderJira, Jira: JiraConfig{BaseURL: "http://jira.example.com"}}, wantErr: "https URL"},
+		"jira local http":   {cfg: Config{Provider: ProviderJira, Jira: JiraConfig{BaseURL: "http://127.0.0.1:8080", ProjectKey: "PROJ", Email: "me@example.com"}}},
+		"jira localhost":    {cfg: Config{Provider: ProviderJira, Jira: JiraConfig{BaseURL: "http://localhost:8080", ProjectKey: "PROJ", Email: "me@example.com"}}},
+		"jira missing key":  {cfg: Config{Provider: ProviderJira, Jira: JiraConfig{BaseURL: jira.BaseURL}}, wantErr: "project_key"},
+		"linear missing id": {cfg: Config{Provider: ProviderLinear}, wantErr: "team_id"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNew(t *testing.T) {
+	tr, err := New(Config{})
+	require.NoError(t, err)
+	assert.Nil(t, tr)
+
+	cfg := Config{Provider: ProviderLinear, Linear: LinearConfig{TeamID: "team", TokenEnv: "AUTOSPEC_TEST_LINEAR_KEY"}}
+	t.Setenv("AUTOSPEC_TEST_LINEAR_KEY", "")
+	_, err = New(cfg)
+	assert.ErrorContains(t, err, "export AUTOSPEC_TEST_LINEAR_KEY")
+
+	t.Setenv("AUTOSPEC_TEST_LINEAR_KEY", "lin_api_test")
+	tr, err = New(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultDoneState, tr.cfg.DoneState)
+}
+
+func TestTracker_Nil(t *testing.T) {
+	t.Parallel()
+
+	var tr *Tracker
+	ctx := context.Background()
+	n, err := tr.LinkStories(ctx, "missing")
+	assert.Zero(t, n)
+	assert.NoError(t, err)
+	n, err = tr.SyncProgress(ctx, "missing")
+	assert.Zero(t, n)
+	assert.NoError(t, err)
+	n, err = tr.PostReport(ctx, "missing", "report")
+	assert.Zero(t, n)
+	assert.NoError(t, err)
+}
+
+func TestTracker_LinkStories(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeTrackerFixture(t, false)
+	// US-002 is already linked to an existing issue by hand
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, LinksFileName),
+		[]byte("stories:\n  US-002:\n    key: OLD-7\n"), 0o644))
+
+	client := &fakeClient{}
+	tr := NewWithClient(Config{Provider: ProviderJira}, client)
+
+	created, err := tr.LinkStories(context.Background(), specDir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, []string{"US-001: Log in"}, client.created)
+
+	links, err := LoadLinks(specDir)
+	require.NoError(t, err)
+	assert.Equal(t, ProviderJira, links.Provider)
+	assert.Equal(t, "PROJ-1", links.Stories["US-001"].Key)
+	assert.Equal(t, "OLD-7", links.Stories["US-002"].Key)
+
+	created, err = tr.LinkStories(context.Background(), specDir)
+	require.NoError(t, err)
+	assert.Zero(t, created, "linked stories must not get a second issue")
+}
+
+func TestTracker_LinkStories_Error(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeTrackerFixture(t, false)
+	tr := NewWithClient(Config{Provider: ProviderJira}, &fakeClient{failOn: "create"})
+
+	_, err := tr.LinkStories(context.Background(), specDir)
+	assert.ErrorContains(t, err, "creating issue for US-001")
+}
+
+func TestTracker_SyncProgress(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeTrackerFixture(t, true)
+	client := &fakeClient{}
+	tr := NewWithClient(Config{Provider: ProviderJira}, client)
+	_, err := tr.LinkStories(context.Background(), specDir)
+	require.NoError(t, err)
+
+	moved, err := tr.SyncProgress(context.Background(), specDir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+	assert.ElementsMatch(t, []string{"PROJ-1 -> Done", "PROJ-2 -> In Progress"}, client.transitions)
+
+	moved, err = tr.SyncProgress(context.Background(), specDir)
+	require.NoError(t, err)
+	assert.Zero(t, moved, "issues already in their state are not moved again")
+
+	links, err := LoadLinks(specDir)
+	require.NoError(t, err)
+	assert.Equal(t, "Done", links.Stories["US-001"].State)
+	assert.Equal(t, "In Progress", links.Stories["US-002"].State)
+}
+
+func TestTracker_SyncProgress_Error(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeTrackerFixture(t, true)
+	client := &fakeClient{}
+	tr := NewWithClient(Config{Provider: ProviderJira}, client)
+	_, err := tr.LinkStories(context.Background(), specDir)
+	require.NoError(t, err)
+
+	client.failOn = "transition"
+	moved, err := tr.SyncProgress(context.Background(), specDir)
+	assert.Zero(t, moved)
+	assert.ErrorContains(t, err, "moving PROJ-1 to \"Done\"")
+}
+
+func TestTracker_PostReport(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeTrackerFixture(t, false)
+	client := &fakeClient{}
+	tr := NewWithClient(Config{Provider: ProviderJira}, client)
+	_, err := tr.LinkStories(context.Background(), specDir)
+	require.NoError(t, err)
+
+	posted, err := tr.PostReport(context.Background(), specDir, "# Completion Report")
+	require.NoError(t, err)
+	assert.Equal(t, 2, posted)
+	assert.Equal(t, []string{"PROJ-1", "PROJ-2"}, client.comments)
+
+	posted, err = tr.PostReport(context.Background(), specDir, "# Completion Report")
+	require.NoError(t, err)
+	assert.Zero(t, posted, "the report is posted once per issue")
+}
+
+func TestStoryDescription(t *testing.T) {
+	t.Parallel()
+
+	spec, err := loadSpec(writeTrackerFixture(t, false))
+	require.NoError(t, err)
+
+	desc := storyDescription(spec.UserStories[0], "specs/001-login")
+	assert.Contains(t, desc, "As a user, I want to log in, so that I can see my account.")
+	assert.Contains(t, desc, "- Given a registered user, when they submit valid credentials, then they are logged in")
+	assert.Contains(t, desc, "specs/001-login/spec.yaml")
+	assert.Contains(t, desc, "Priority: P1.")
+}
@@ -0,0 +1,411 @@
+// Package transcript records what the agent was told and what it did during a
+// workflow stage, and writes it to the spec directory as transcript-<stage>.md.
+// Reviewers can audit a stage (prompt, key responses, tool calls, final
+// result) without rerunning it.
+//
+// The recorder parses Claude stream-json output; other agents' plain-text
+// output is kept as-is. All recorded text is redacted before it is written,
+// and the file is encrypted when a cipher is set (see internal/encrypt).
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/redact"
+	"github.com/ariel-frischer/claude-clean/parser"
+)
+
+// Limits keep a transcript readable and bounded for long sessions.
+const (
+	maxResponseBytes = 4000    // per assistant response; longer ones are truncated
+	maxOutputBytes   = 1 << 18 // per session across all responses
+	maxToolCalls     = 200     // individual tool calls listed per session
+	maxLineBuffer    = 1 << 20 // partial-line buffer for stream-json parsing
+	maxSummaryLen    = 120     // tool call input summaries
+)
+
+// Path returns the transcript path for stage in specDir.
+func Path(specDir, stage string) string {
+	return filepath.Join(specDir, "transcript-"+stage+".md")
+}
+
+// Recorder collects agent sessions per stage for the current run.
+// A nil *Recorder is valid: it records nothing and writes no files.
+type Recorder struct {
+	// Redactor masks secrets in recorded text (nil disables redaction).
+	Redactor *redact.Redactor
+
+	// Cipher encrypts written transcripts (nil: plain Markdown).
+	Cipher *encrypt.Cipher
+
+	now func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string][]*session
+	active   *session
+}
+
+// session is one agent invocation (an attempt, retry, phase or task).
+type session struct {
+	prompt    string
+	start     time.Time
+	duration  time.Duration
+	err       error
+	responses []string
+	outBytes  int
+	truncated bool
+	lastPlain bool
+	toolCalls []toolCall
+	toolCount map[string]int
+	result    *result
+	line      []byte
+}
+
+// toolCall is a tool invocation with a one-line summary of its input.
+type toolCall struct {
+	name    string
+	summary string
+}
+
+// result is the agent's final stream-json result message.
+type result struct {
+	text     string
+	isError  bool
+	numTurns int
+	costUSD  float64
+}
+
+// New creates a recorder that redacts with redactor.
+func New(redactor *redact.Redactor) *Recorder {
+	return &Recorder{
+		Redactor: redactor,
+		now:      time.Now,
+		sessions: make(map[string][]*session),
+	}
+}
+
+// StartSession begins recording an agent session running prompt for stage.
+func (r *Recorder) StartSession(stage, prompt string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.active = &session{prompt: prompt, start: r.now(), toolCount: make(map[string]int)}
+	r.sessions[stage] = append(r.sessions[stage], r.active)
+}
+
+// EndSession finishes the active session. err is the execution error, if any.
+func (r *Recorder) EndSession(err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.active
+	if s == nil {
+		return
+	}
+	r.active = nil
+	if len(s.line) > 0 {
+		s.handleLine(s.line)
+		s.line = nil
+	}
+	s.duration = r.now().Sub(s.start)
+	s.err = err
+}
+
+// Writer returns a writer that records agent output into the active session.
+// Returns nil for a nil recorder.
+func (r *Recorder) Writer() io.Writer {
+	if r == nil {
+		return nil
+	}
+	return &outputWriter{r: r}
+}
+
+type outputWriter struct {
+	r *Recorder
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.r.mu.Lock()
+	defer w.r.mu.Unlock()
+	s := w.r.active
+	if s == nil {
+		return len(p), nil
+	}
+	s.line = append(s.line, p...)
+	for {
+		idx := bytes.IndexByte(s.line, '\n')
+		if idx < 0 {
+			break
+		}
+		s.handleLine(s.line[:idx])
+		s.line = s.line[idx+1:]
+	}
+	if len(s.line) > maxLineBuffer {
+		s.line = nil
+	}
+	return len(p), nil
+}
+
+// handleLine records one line of agent output.
+func (s *session) handleLine(line []byte) {
+	line = bytes.TrimRight(line, " \t\r")
+	var msg parser.StreamMessage
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' || json.Unmarshal(trimmed, &msg) != nil || msg.Type == "" {
+		s.addPlainLine(string(line))
+		return
+	}
+	s.lastPlain = false
+
+	switch msg.Type {
+	case "assistant":
+		if msg.Message == nil {
+			return
+		}
+		for _, block := range msg.Message.Content {
+			switch block.Type {
+			case "text":
+				s.addResponse(parser.StripSystemReminders(block.Text))
+			case "tool_use":
+				s.addToolCall(block.Name, summarizeInput(block.Input))
+			}
+		}
+	case "result":
+		s.result = &result{
+			text:     msg.Result,
+			isError:  msg.IsError,
+			numTurns: msg.NumTurns,
+			costUSD:  msg.TotalCostUSD,
+		}
+	}
+}
+
+// addResponse keeps text within the per-session limit.
+func (s *session) addResponse(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" || !s.reserve(len(text)) {
+		return
+	}
+	s.responses = append(s.responses, text)
+}
+
+// addPlainLine records non-JSON output (agents without stream-json).
+// Consecutive lines are merged into one response; blank lines are kept
+// only inside a response.
+func (s *session) addPlainLine(line string) {
+	if !s.lastPlain {
+		if strings.TrimSpace(line) == "" {
+			return
+		}
+		s.addResponse(line)
+		s.lastPlain = len(s.responses) > 0
+		return
+	}
+	if !s.reserve(len(line) + 1) {
+		return
+	}
+	last := len(s.responses) - 1
+	s.responses[last] += "\n" + line
+}
+
+// reserve accounts n bytes of output, reporting false once the session limit
+// is reached.
+func (s *session) reserve(n int) bool {
+	if s.outBytes+n > maxOutputBytes {
+		s.truncated = true
+		return false
+	}
+	s.outBytes += n
+	return true
+}
+
+func (s *session) addToolCall(name, summary string) {
+	if name == "" {
+		return
+	}
+	s.toolCount[name]++
+	if len(s.toolCalls) < maxToolCalls {
+		s.toolCalls = append(s.toolCalls, toolCall{name: name, summary: summary})
+	}
+}
+
+// summaryKeys are tool input fields that identify what a call touched, in
+// order of preference.
+var summaryKeys = []string{"file_path", "path", "command", "pattern", "url", "description", "prompt"}
+
+// summarizeInput returns a one-line description of a tool call's input.
+func summarizeInput(input map[string]interface{}) string {
+	for _, key := range summaryKeys {
+		if v, ok := input[key].(string); ok && v != "" {
+			return truncate(strings.Join(strings.Fields(v), " "), maxSummaryLen)
+		}
+	}
+	return ""
+}
+
+// Write renders every session recorded for stage in this run to
+// transcript-<stage>.md in specDir and returns the file path. Later sessions
+// of the same stage (retries, phases, tasks) are appended to the same
+// transcript, so the file always covers the whole stage. Returns "" and no
+// error when nothing was recorded.
+func (r *Recorder) Write(specDir, stage string) (string, error) {
+	if r == nil {
+		return "", nil
+	}
+	r.mu.Lock()
+	sessions := r.sessions[stage]
+	content := r.render(stage, sessions)
+	r.mu.Unlock()
+	if len(sessions) == 0 {
+		return "", nil
+	}
+
+	data := []byte(content)
+	if r.Cipher != nil {
+		data = append(r.Cipher.Seal(data), '\n')
+	}
+	path := Path(specDir, stage)
+	if err := fsutil.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing transcript: %w", err)
+	}
+	return path, nil
+}
+
+// render builds the Markdown transcript. Caller must hold r.mu.
+func (r *Recorder) render(stage string, sessions []*session) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Transcript: %s\n\n", stage)
+	fmt.Fprintf(&b, "Generated by autospec on %s. Secrets are redacted; long responses are truncated.\n",
+		r.now().UTC().Format(time.RFC3339))
+
+	for i, s := range sessions {
+		fmt.Fprintf(&b, "\n## Session %d\n\n", i+1)
+		fmt.Fprintf(&b, "- **Started**: %s\n", s.start.UTC().Format(time.RFC3339))
+		if s.duration > 0 {
+			fmt.Fprintf(&b, "- **Duration**: %s\n", s.duration.Round(time.Second))
+		}
+		fmt.Fprintf(&b, "- **Outcome**: %s\n", r.redact(s.outcome()))
+
+		b.WriteString("\n### Prompt\n\n")
+		writeFenced(&b, r.redact(s.prompt))
+
+		b.WriteString("\n### Responses\n\n")
+		if len(s.responses) == 0 {
+			b.WriteString("_No agent output was captured (interactive session or empty output)._\n")
+		}
+		for _, text := range s.responses {
+			b.WriteString(r.redact(truncate(text, maxResponseBytes)))
+			b.WriteString("\n\n")
+		}
+		if s.truncated {
+			b.WriteString("_Further output omitted._\n")
+		}
+
+		if len(s.toolCount) > 0 {
+			r.writeToolCalls(&b, s)
+		}
+
+		if s.result != nil && s.result.text != "" {
+			b.WriteString("\n### Final Result\n\n")
+			b.WriteString(r.redact(truncate(strings.TrimSpace(s.result.text), maxResponseBytes)))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// writeToolCalls renders the per-tool counts and the individual calls.
+func (r *Recorder) writeToolCalls(b *strings.Builder, s *session) {
+	names := make([]string, 0, len(s.toolCount))
+	for name := range s.toolCount {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if s.toolCount[names[i]] != s.toolCount[names[j]] {
+			return s.toolCount[names[i]] > s.toolCount[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	b.WriteString("\n### Tool Calls\n\n")
+	b.WriteString("| Tool | Calls |\n|------|-------|\n")
+	total := 0
+	for _, name := range names {
+		fmt.Fprintf(b, "| %s | %d |\n", name, s.toolCount[name])
+		total += s.toolCount[name]
+	}
+	b.WriteString("\n")
+	for _, call := range s.toolCalls {
+		if call.summary == "" {
+			fmt.Fprintf(b, "- %s\n", call.name)
+			continue
+		}
+		fmt.Fprintf(b, "- %s: `%s`\n", call.name, strings.ReplaceAll(r.redact(call.summary), "`", "'"))
+	}
+	if total > len(s.toolCalls) {
+		fmt.Fprintf(b, "- _%d more call(s) omitted_\n", total-len(s.toolCalls))
+	}
+}
+
+// outcome summarizes how the session ended.
+func (s *session) outcome() string {
+	var parts []string
+	switch {
+	case s.err != nil:
+		parts = append(parts, "failed: "+s.err.Error())
+	case s.result != nil && s.result.isError:
+		parts = append(parts, "agent reported an error")
+	default:
+		parts = append(parts, "completed")
+	}
+	if s.result != nil {
+		if s.result.numTurns > 0 {
+			parts = append(parts, fmt.Sprintf("%d turns", s.result.numTurns))
+		}
+		if s.result.costUSD > 0 {
+			parts = append(parts, fmt.Sprintf("$%.2f", s.result.costUSD))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (r *Recorder) redact(s string) string {
+	return r.Redactor.String(s)
+}
+
+// writeFenced writes text in a code fence long enough not to clash with
+// fences inside text.
+func writeFenced(b *strings.Builder, text string) {
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	fmt.Fprintf(b, "%stext\n%s\n%s\n", fence, strings.TrimRight(text, "\n"), fence)
+}
+
+// truncate shortens s to at most n bytes, marking the cut.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	cut := n
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + " …[truncated]"
+}
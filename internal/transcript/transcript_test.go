@@ -0,0 +1,208 @@
+// Package transcript tests stage transcript recording and rendering.
+// Related: internal/transcript/transcript.go
+// Tags: transcript, workflow, audit, stream-json
+
+package transcript
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	"github.com/ariel-frischer/autospec/internal/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const streamOutput = `{"type":"system","subtype":"init","session_id":"abc"}
+{"type":"assistant","message":{"content":[{"type":"text","text":"Reading the spec first.<system-reminder>internal</system-reminder>"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{"file_path":"specs/001-test/spec.yaml"}}]}}
+{"type":"user","message":{"content":[{"type":"tool_result","tool_use_id":"1","content":"file contents"}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Bash","input":{"command":"autospec artifact\n  plan.yaml"}}]}}
+{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{"file_path":"specs/001-test/plan.yaml"}}]}}
+{"type":"result","subtype":"success","is_error":false,"num_turns":7,"total_cost_usd":0.42,"result":"Created plan.yaml"}
+`
+
+func newTestRecorder(redactor *redact.Redactor) *Recorder {
+	r := New(redactor)
+	clock := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.now = func() time.Time {
+		clock = clock.Add(30 * time.Second)
+		return clock
+	}
+	return r
+}
+
+func TestRecorder_NilSafe(t *testing.T) {
+	t.Parallel()
+
+	var r *Recorder
+	r.StartSession("plan", "prompt")
+	r.EndSession(nil)
+	assert.Nil(t, r.Writer())
+
+	path, err := r.Write(t.TempDir(), "plan")
+	require.NoError(t, err)
+	assert.Empty(t, path)
+}
+
+func TestRecorder_StreamJSON(t *testing.T) {
+	t.Parallel()
+
+	r := newTestRecorder(nil)
+	r.StartSession("plan", "/autospec.plan")
+	w := r.Writer()
+	// Split mid-line to exercise line buffering
+	_, _ = w.Write([]byte(streamOutput[:90]))
+	_, _ = w.Write([]byte(streamOutput[90:]))
+	r.EndSession(nil)
+
+	dir := t.TempDir()
+	path, err := r.Write(dir, "plan")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "transcript-plan.md"), path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	content := string(data)
+
+	assert.Contains(t, content, "# Transcript: plan")
+	assert.Contains(t, content, "```text\n/autospec.plan\n```")
+	assert.Contains(t, content, "- **Outcome**: completed, 7 turns, $0.42")
+	assert.Contains(t, content, "- **Duration**: 30s")
+	assert.Contains(t, content, "Reading the spec first.")
+	assert.NotContains(t, content, "system-reminder")
+	assert.Contains(t, content, "| Read | 2 |")
+	assert.Contains(t, content, "| Bash | 1 |")
+	assert.Contains(t, content, "- Read: `specs/001-test/spec.yaml`")
+	assert.Contains(t, content, "- Bash: `autospec artifact plan.yaml`")
+	assert.Contains(t, content, "### Final Result\n\nCreated plan.yaml")
+	assert.NotContains(t, content, "file contents", "tool results are not transcribed")
+}
+
+func TestRecorder_PlainTextOutput(t *testing.T) {
+	t.Parallel()
+
+	r := newTestRecorder(nil)
+	r.StartSession("tasks", "/autospec.tasks")
+	_, _ = r.Writer().Write([]byte("\nWorking on tasks\n  - T001\n\nDone\n"))
+	r.EndSession(errors.New("exit status 1"))
+
+	content := render(t, r, "tasks")
+	assert.Contains(t, content, "Working on tasks\n  - T001\n\nDone")
+	assert.Contains(t, content, "- **Outcome**: failed: exit status 1")
+	assert.NotContains(t, content, "### Tool Calls")
+}
+
+func TestRecorder_SessionsAccumulatePerStage(t *testing.T) {
+	t.Parallel()
+
+	r := newTestRecorder(nil)
+	r.StartSession("implement", "/autospec.implement --phase 1")
+	r.EndSession(nil)
+	r.StartSession("plan", "/autospec.plan")
+	r.EndSession(nil)
+	r.StartSession("implement", "/autospec.implement --phase 2")
+	r.EndSession(nil)
+
+	content := render(t, r, "implement")
+	assert.Contains(t, content, "## Session 1")
+	assert.Contains(t, content, "--phase 1")
+	assert.Contains(t, content, "## Session 2")
+	assert.Contains(t, content, "--phase 2")
+	assert.NotContains(t, content, "/autospec.plan")
+	assert.Contains(t, content, "_No agent output was captured")
+
+	path, err := r.Write(t.TempDir(), "analyze")
+	require.NoError(t, err)
+	assert.Empty(t, path, "stages without sessions write nothing")
+}
+
+func TestRecorder_Redacts(t *testing.T) {
+	t.Parallel()
+
+	redactor, err := redact.New(redact.Config{Enabled: true})
+	require.NoError(t, err)
+	secret := "ghp_" + strings.Repeat("a", 36)
+
+	r := newTestRecorder(redactor)
+	r.StartSession("plan", "/autospec.plan token="+secret)
+	_, _ = r.Writer().Write([]byte(`{"type":"assistant","message":{"content":[{"type":"text","text":"using ` + secret + `"},{"type":"tool_use","name":"Bash","input":{"command":"echo ` + secret + `"}}]}}` + "\n"))
+	r.EndSession(nil)
+
+	content := render(t, r, "plan")
+	assert.NotContains(t, content, secret)
+	assert.Contains(t, content, redact.Mask)
+}
+
+func TestRecorder_Encrypts(t *testing.T) {
+	t.Parallel()
+
+	cipher, err := encrypt.NewCipher(bytes.Repeat([]byte{1}, encrypt.KeySize))
+	require.NoError(t, err)
+	r := newTestRecorder(nil)
+	r.Cipher = cipher
+	r.StartSession("plan", "/autospec.plan proprietary")
+	r.EndSession(nil)
+
+	path, err := r.Write(t.TempDir(), "plan")
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "proprietary")
+
+	plain, err := cipher.OpenLines(data)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(plain), "# Transcript: plan\n"))
+	assert.Contains(t, string(plain), "/autospec.plan proprietary")
+}
+
+func TestRecorder_Truncates(t *testing.T) {
+	t.Parallel()
+
+	r := newTestRecorder(nil)
+	r.StartSession("plan", "/autospec.plan")
+	long := strings.Repeat("x", maxResponseBytes+100)
+	_, _ = r.Writer().Write([]byte(`{"type":"assistant","message":{"content":[{"type":"text","text":"` + long + `"}]}}` + "\n"))
+	for i := 0; i < maxToolCalls+5; i++ {
+		_, _ = r.Writer().Write([]byte(`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Edit","input":{}}]}}` + "\n"))
+	}
+	r.EndSession(nil)
+
+	content := render(t, r, "plan")
+	assert.Contains(t, content, "…[truncated]")
+	assert.NotContains(t, content, long)
+	assert.Contains(t, content, "| Edit | 205 |")
+	assert.Contains(t, content, "_5 more call(s) omitted_")
+}
+
+func TestWriteFenced(t *testing.T) {
+	t.Parallel()
+
+	var b strings.Builder
+	writeFenced(&b, "prompt with ```yaml\nblock\n```")
+	assert.True(t, strings.HasPrefix(b.String(), "````text\n"))
+	assert.True(t, strings.HasSuffix(b.String(), "\n````\n"))
+}
+
+func TestTruncate_RuneBoundary(t *testing.T) {
+	t.Parallel()
+
+	got := truncate("ab€cd", 3)
+	assert.Equal(t, "ab …[truncated]", got)
+}
+
+// render writes stage's transcript to a temp dir and returns its contents.
+func render(t *testing.T, r *Recorder, stage string) string {
+	t.Helper()
+	path, err := r.Write(t.TempDir(), stage)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,18 @@
+//go:build windows
+
+package uninstall
+
+import "os"
+
+// canWrite reports whether the current user may create and remove files in
+// dir. Windows has no access(2) and its ACLs are not reflected in file
+// modes, so a temporary file is created and removed.
+func canWrite(dir string) bool {
+	f, err := os.CreateTemp(dir, ".autospec-write-check-*")
+	if err != nil {
+		return false
+	}
+	name := f.Name()
+	f.Close()
+	return os.Remove(name) == nil
+}
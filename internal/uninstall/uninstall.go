@@ -1,13 +1,9 @@
-//go:build !windows
-
 // Package uninstall provides functionality for completely removing autospec from a system.
 package uninstall
 
 import (
 	"os"
 	"path/filepath"
-
-	"golang.org/x/sys/unix"
 )
 
 // TargetType represents the type of an uninstall target
@@ -60,10 +56,7 @@ func DetectBinaryLocation() (string, error) {
 // doesn't have write permission.
 func RequiresSudo(path string) bool {
 	// Check if we can write to the parent directory
-	dir := filepath.Dir(path)
-
-	// Use unix.Access to check write permission
-	return unix.Access(dir, unix.W_OK) != nil
+	return !canWrite(filepath.Dir(path))
 }
 
 // GetUninstallTargets returns all targets that should be removed during uninstall.
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"runtime"
+	"strings"
 	"time"
 )
 
@@ -13,6 +14,10 @@ const (
 	// GitHubAPIURL is the endpoint for fetching the latest release.
 	GitHubAPIURL = "https://api.github.com/repos/ariel-frischer/autospec/releases/latest"
 
+	// DownloadBaseURL is the prefix of release asset URLs, followed by
+	// /<tag>/<asset>.
+	DownloadBaseURL = "https://github.com/ariel-frischer/autospec/releases/download"
+
 	// DefaultHTTPTimeout is the default timeout for HTTP requests.
 	DefaultHTTPTimeout = 5 * time.Second
 )
@@ -104,7 +109,21 @@ func (c *Checker) CheckForUpdate(ctx context.Context, currentVersion string) (*U
 
 // fetchLatestRelease fetches the latest release from GitHub API.
 func (c *Checker) fetchLatestRelease(ctx context.Context) (*ReleaseInfo, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL, nil)
+	return c.fetchRelease(ctx, c.apiURL)
+}
+
+// FetchRelease fetches the release tagged tag, or the latest release when
+// tag is empty.
+func (c *Checker) FetchRelease(ctx context.Context, tag string) (*ReleaseInfo, error) {
+	if tag == "" {
+		return c.fetchLatestRelease(ctx)
+	}
+	return c.fetchRelease(ctx, strings.TrimSuffix(c.apiURL, "/latest")+"/tags/"+tag)
+}
+
+// fetchRelease fetches one release from the GitHub API URL url.
+func (c *Checker) fetchRelease(ctx context.Context, url string) (*ReleaseInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating request: %w", err)
 	}
@@ -179,24 +198,46 @@ func (c *Checker) CheckForUpdateAsync(ctx context.Context, currentVersion string
 
 // buildAssetName constructs the asset name for the current platform.
 func buildAssetName(version string) string {
+	return AssetName(version, runtime.GOOS, runtime.GOARCH)
+}
+
+// AssetName returns the release archive name for a platform, following the
+// goreleaser name_template: autospec_<version>_<Os>_<arch>.tar.gz.
+func AssetName(version, goos, goarch string) string {
 	// Map Go's GOOS/GOARCH to goreleaser naming conventions
-	osName := runtime.GOOS
-	if osName == "darwin" {
-		osName = "Darwin"
-	} else if osName == "linux" {
-		osName = "Linux"
+	osName := goos
+	if osName != "" {
+		osName = strings.ToUpper(osName[:1]) + osName[1:]
 	}
 
-	archName := runtime.GOARCH
-	if archName == "amd64" {
+	archName := goarch
+	switch archName {
+	case "amd64":
 		archName = "x86_64"
+	case "386":
+		archName = "i386"
 	}
 
-	// Strip 'v' prefix for version in filename
-	ver := version
-	if len(ver) > 0 && ver[0] == 'v' {
-		ver = ver[1:]
-	}
+	return fmt.Sprintf("autospec_%s_%s_%s.tar.gz", strings.TrimPrefix(version, "v"), osName, archName)
+}
 
-	return fmt.Sprintf("autospec_%s_%s_%s.tar.gz", ver, osName, archName)
+// ParseAssetName is the inverse of AssetName. It reports false for names
+// that are not release archives, such as checksums.txt.
+func ParseAssetName(name string) (version, goos, goarch string, ok bool) {
+	base, found := strings.CutSuffix(name, ".tar.gz")
+	if !found {
+		return "", "", "", false
+	}
+	parts := strings.SplitN(base, "_", 4)
+	if len(parts) != 4 || parts[0] != "autospec" {
+		return "", "", "", false
+	}
+	goarch = parts[3]
+	switch goarch {
+	case "x86_64":
+		goarch = "amd64"
+	case "i386":
+		goarch = "386"
+	}
+	return parts[1], strings.ToLower(parts[2]), goarch, true
 }
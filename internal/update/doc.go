@@ -5,6 +5,8 @@
 //   - GitHub API client for fetching release info (check.go)
 //   - Binary download with progress display (download.go)
 //   - Binary installation with backup and rollback (install.go)
+//   - Restoring the previous binary after an update, with a version probe and
+//     update history (rollback.go)
 //
 // The update check is designed to be non-blocking when used with the version command,
 // using goroutines to fetch release info without delaying the display of version
@@ -88,6 +88,15 @@ func (d *Downloader) DownloadBinary(ctx context.Context, url string, onProgress
 
 // FetchChecksum downloads the checksums.txt file and returns the checksum for the given asset.
 func (d *Downloader) FetchChecksum(ctx context.Context, checksumURL, assetName string) (string, error) {
+	content, err := d.FetchChecksums(ctx, checksumURL)
+	if err != nil {
+		return "", err
+	}
+	return ParseChecksum(content, assetName)
+}
+
+// FetchChecksums downloads a checksums.txt file and returns its content.
+func (d *Downloader) FetchChecksums(ctx context.Context, checksumURL string) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumURL, nil)
 	if err != nil {
 		return "", fmt.Errorf("creating checksum request: %w", err)
@@ -108,7 +117,7 @@ func (d *Downloader) FetchChecksum(ctx context.Context, checksumURL, assetName s
 		return "", fmt.Errorf("reading checksum body: %w", err)
 	}
 
-	return ParseChecksum(string(body), assetName)
+	return string(body), nil
 }
 
 // ParseChecksum extracts the checksum for a specific asset from checksums.txt format.
@@ -29,10 +29,15 @@ func NewInstaller() (*Installer, error) {
 		realPath = execPath
 	}
 
+	return NewInstallerAt(realPath), nil
+}
+
+// NewInstallerAt creates an installer for the binary at path.
+func NewInstallerAt(path string) *Installer {
 	return &Installer{
-		executablePath: realPath,
-		backupPath:     realPath + ".bak",
-	}, nil
+		executablePath: path,
+		backupPath:     path + ".bak",
+	}
 }
 
 // GetExecutablePath returns the path to the current executable.
@@ -148,7 +153,8 @@ func (i *Installer) Rollback() error {
 	return nil
 }
 
-// CleanupBackup removes the backup file after successful installation.
+// CleanupBackup removes the backup file. Updates keep it for
+// 'autospec update rollback'.
 func (i *Installer) CleanupBackup() error {
 	if err := os.Remove(i.backupPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("cleaning up backup: %w", err)
@@ -0,0 +1,262 @@
+package update
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Package manager manifest formats.
+const (
+	FormatHomebrew = "brew"
+	FormatScoop    = "scoop"
+)
+
+// Formats lists the manifest formats Render supports.
+var Formats = []string{FormatHomebrew, FormatScoop}
+
+// PackageInfo describes the package in generated manifests.
+type PackageInfo struct {
+	Name        string
+	Description string
+	Homepage    string
+	License     string
+}
+
+// DefaultPackageInfo describes autospec.
+var DefaultPackageInfo = PackageInfo{
+	Name:        "autospec",
+	Description: "Spec-Driven Development Automation",
+	Homepage:    "https://github.com/ariel-frischer/autospec",
+	License:     "MIT",
+}
+
+// ManifestAsset is a release archive for one platform.
+type ManifestAsset struct {
+	Name   string
+	OS     string
+	Arch   string
+	URL    string
+	SHA256 string
+}
+
+// Manifest holds the release archives of one version, as listed in the
+// release's checksums.txt.
+type Manifest struct {
+	// Version is the release version without the "v" prefix.
+	Version string
+	// Assets are sorted by OS, then architecture.
+	Assets []ManifestAsset
+}
+
+// NewManifest builds a manifest for version from the content of a
+// checksums.txt file. Archive URLs are baseURL/<asset name>; entries for
+// other versions and non-archive files are skipped.
+func NewManifest(version, checksums, baseURL string) (*Manifest, error) {
+	version = strings.TrimPrefix(version, "v")
+	m := &Manifest{Version: version}
+	for _, line := range strings.Split(checksums, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		sum, name := fields[0], fields[1]
+		assetVersion, goos, goarch, ok := ParseAssetName(name)
+		if !ok || assetVersion != version {
+			continue
+		}
+		if raw, err := hex.DecodeString(sum); err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("invalid SHA-256 checksum for %s: %q", name, sum)
+		}
+		m.Assets = append(m.Assets, ManifestAsset{
+			Name:   name,
+			OS:     goos,
+			Arch:   goarch,
+			URL:    strings.TrimSuffix(baseURL, "/") + "/" + name,
+			SHA256: strings.ToLower(sum),
+		})
+	}
+	if len(m.Assets) == 0 {
+		return nil, fmt.Errorf("checksums list no release archives for version %s", version)
+	}
+	sort.Slice(m.Assets, func(i, j int) bool {
+		if m.Assets[i].OS != m.Assets[j].OS {
+			return m.Assets[i].OS < m.Assets[j].OS
+		}
+		return m.Assets[i].Arch < m.Assets[j].Arch
+	})
+	return m, nil
+}
+
+// Asset returns the archive for goos/goarch.
+func (m *Manifest) Asset(goos, goarch string) (ManifestAsset, bool) {
+	for _, a := range m.Assets {
+		if a.OS == goos && a.Arch == goarch {
+			return a, true
+		}
+	}
+	return ManifestAsset{}, false
+}
+
+// Render returns the manifest in format (see Formats).
+func (m *Manifest) Render(format string, info PackageInfo) ([]byte, error) {
+	switch format {
+	case FormatHomebrew:
+		return m.Homebrew(info)
+	case FormatScoop:
+		return m.Scoop(info)
+	default:
+		return nil, fmt.Errorf("unknown manifest format %q: must be one of %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+var homebrewTemplate = template.Must(template.New("formula").Parse(`# typed: false
+# frozen_string_literal: true
+
+# Generated by 'autospec release manifest --format brew'. Do not edit.
+class {{.Class}} < Formula
+  desc "{{.Info.Description}}"
+  homepage "{{.Info.Homepage}}"
+  version "{{.Version}}"
+  license "{{.Info.License}}"
+{{range .Platforms}}
+  on_{{.Block}} do
+{{- range .Arches}}
+    on_{{.Block}} do
+      url "{{.Asset.URL}}"
+      sha256 "{{.Asset.SHA256}}"
+    end
+{{- end}}
+  end
+{{end}}
+  def install
+    bin.install "{{.Info.Name}}"
+  end
+
+  test do
+    assert_match version.to_s, shell_output("#{bin}/{{.Info.Name}} version --plain")
+  end
+end
+`))
+
+// homebrewBlocks maps GOOS and GOARCH to Homebrew's on_<block> names.
+var homebrewBlocks = map[string]string{
+	"darwin": "macos",
+	"linux":  "linux",
+	"amd64":  "intel",
+	"arm64":  "arm",
+}
+
+// Homebrew renders a Homebrew formula for the macOS and Linux archives.
+func (m *Manifest) Homebrew(info PackageInfo) ([]byte, error) {
+	type arch struct {
+		Block string
+		Asset ManifestAsset
+	}
+	type platform struct {
+		Block  string
+		Arches []arch
+	}
+	var platforms []platform
+	for _, goos := range []string{"darwin", "linux"} {
+		p := platform{Block: homebrewBlocks[goos]}
+		for _, goarch := range []string{"amd64", "arm64"} {
+			if a, ok := m.Asset(goos, goarch); ok {
+				p.Arches = append(p.Arches, arch{Block: homebrewBlocks[goarch], Asset: a})
+			}
+		}
+		if len(p.Arches) > 0 {
+			platforms = append(platforms, p)
+		}
+	}
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("release %s has no macOS or Linux archives", m.Version)
+	}
+
+	var buf bytes.Buffer
+	err := homebrewTemplate.Execute(&buf, map[string]any{
+		"Class":     formulaClass(info.Name),
+		"Info":      info,
+		"Version":   m.Version,
+		"Platforms": platforms,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rendering formula: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// formulaClass turns a formula name such as "autospec" or "foo-bar" into
+// its Ruby class name ("Autospec", "FooBar").
+func formulaClass(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '-' || r == '_' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// scoopArch maps GOARCH to Scoop's architecture keys.
+var scoopArch = map[string]string{
+	"amd64": "64bit",
+	"386":   "32bit",
+	"arm64": "arm64",
+}
+
+type scoopURL struct {
+	URL  string `json:"url"`
+	Hash string `json:"hash,omitempty"`
+}
+
+type scoopManifest struct {
+	Version      string              `json:"version"`
+	Description  string              `json:"description"`
+	Homepage     string              `json:"homepage"`
+	License      string              `json:"license"`
+	Architecture map[string]scoopURL `json:"architecture"`
+	Bin          string              `json:"bin"`
+	Checkver     map[string]string   `json:"checkver"`
+	Autoupdate   struct {
+		Architecture map[string]scoopURL `json:"architecture"`
+		Hash         map[string]string   `json:"hash"`
+	} `json:"autoupdate"`
+}
+
+// Scoop renders a Scoop manifest for the Windows archives. Its autoupdate
+// section lets the bucket pick up later releases without regenerating it.
+func (m *Manifest) Scoop(info PackageInfo) ([]byte, error) {
+	s := scoopManifest{
+		Version:      m.Version,
+		Description:  info.Description,
+		Homepage:     info.Homepage,
+		License:      info.License,
+		Architecture: map[string]scoopURL{},
+		Bin:          info.Name + ".exe",
+		Checkver:     map[string]string{"github": info.Homepage},
+	}
+	s.Autoupdate.Architecture = map[string]scoopURL{}
+	var baseURL string
+	for _, a := range m.Assets {
+		key, ok := scoopArch[a.Arch]
+		if a.OS != "windows" || !ok {
+			continue
+		}
+		s.Architecture[key] = scoopURL{URL: a.URL, Hash: a.SHA256}
+		s.Autoupdate.Architecture[key] = scoopURL{URL: strings.ReplaceAll(a.URL, m.Version, "$version")}
+		baseURL = strings.TrimSuffix(a.URL, a.Name)
+	}
+	if len(s.Architecture) == 0 {
+		return nil, fmt.Errorf("release %s has no Windows archives", m.Version)
+	}
+	s.Autoupdate.Hash = map[string]string{"url": strings.ReplaceAll(baseURL, m.Version, "$version") + "checksums.txt"}
+
+	data, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return nil, fmt.Errorf("encoding scoop manifest: %w", err)
+	}
+	return append(data, '\n'), nil
+}
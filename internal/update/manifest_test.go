@@ -0,0 +1,160 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChecksums = `1111111111111111111111111111111111111111111111111111111111111111  autospec_0.9.0_Darwin_arm64.tar.gz
+2222222222222222222222222222222222222222222222222222222222222222  autospec_0.9.0_Darwin_x86_64.tar.gz
+3333333333333333333333333333333333333333333333333333333333333333  autospec_0.9.0_Linux_arm64.tar.gz
+4444444444444444444444444444444444444444444444444444444444444444  autospec_0.9.0_Linux_x86_64.tar.gz
+5555555555555555555555555555555555555555555555555555555555555555  autospec_0.9.0_Windows_x86_64.tar.gz
+6666666666666666666666666666666666666666666666666666666666666666  autospec_0.8.1_Linux_x86_64.tar.gz
+7777777777777777777777777777777777777777777777777777777777777777  sbom.json
+`
+
+const testBaseURL = DownloadBaseURL + "/v0.9.0"
+
+func TestAssetName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		goos, goarch string
+		want         string
+	}{
+		"linux amd64":   {goos: "linux", goarch: "amd64", want: "autospec_0.9.0_Linux_x86_64.tar.gz"},
+		"darwin arm64":  {goos: "darwin", goarch: "arm64", want: "autospec_0.9.0_Darwin_arm64.tar.gz"},
+		"windows amd64": {goos: "windows", goarch: "amd64", want: "autospec_0.9.0_Windows_x86_64.tar.gz"},
+		"linux 386":     {goos: "linux", goarch: "386", want: "autospec_0.9.0_Linux_i386.tar.gz"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := AssetName("v0.9.0", tt.goos, tt.goarch)
+			assert.Equal(t, tt.want, got)
+
+			version, goos, goarch, ok := ParseAssetName(got)
+			require.True(t, ok)
+			assert.Equal(t, []string{"0.9.0", tt.goos, tt.goarch}, []string{version, goos, goarch})
+		})
+	}
+
+	for _, name := range []string{"checksums.txt", "autospec_0.9.0_Linux.tar.gz", "other_0.9.0_Linux_x86_64.tar.gz"} {
+		_, _, _, ok := ParseAssetName(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestNewManifest(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewManifest("v0.9.0", testChecksums, testBaseURL+"/")
+	require.NoError(t, err)
+	assert.Equal(t, "0.9.0", m.Version)
+	require.Len(t, m.Assets, 5, "other versions and non-archives are skipped")
+	assert.Equal(t, "darwin", m.Assets[0].OS)
+
+	a, ok := m.Asset("linux", "amd64")
+	require.True(t, ok)
+	assert.Equal(t, testBaseURL+"/autospec_0.9.0_Linux_x86_64.tar.gz", a.URL)
+	assert.Equal(t, strings.Repeat("4", 64), a.SHA256)
+
+	_, err = NewManifest("1.0.0", testChecksums, testBaseURL)
+	assert.ErrorContains(t, err, "no release archives for version 1.0.0")
+
+	_, err = NewManifest("0.9.0", "abc  autospec_0.9.0_Linux_x86_64.tar.gz\n", testBaseURL)
+	assert.ErrorContains(t, err, "invalid SHA-256")
+}
+
+func TestManifest_Homebrew(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewManifest("0.9.0", testChecksums, testBaseURL)
+	require.NoError(t, err)
+	formula, err := m.Render(FormatHomebrew, DefaultPackageInfo)
+	require.NoError(t, err)
+
+	out := string(formula)
+	assert.Contains(t, out, "class Autospec < Formula")
+	assert.Contains(t, out, `version "0.9.0"`)
+	assert.Contains(t, out, `license "MIT"`)
+	assert.Contains(t, out, "  on_macos do\n    on_intel do\n      url \""+testBaseURL+"/autospec_0.9.0_Darwin_x86_64.tar.gz\"\n      sha256 \""+strings.Repeat("2", 64)+"\"\n    end\n    on_arm do")
+	assert.Contains(t, out, "  on_linux do\n    on_intel do")
+	assert.NotContains(t, out, "Windows")
+	assert.Contains(t, out, `bin.install "autospec"`)
+
+	windowsOnly, err := NewManifest("0.9.0", "5555555555555555555555555555555555555555555555555555555555555555  autospec_0.9.0_Windows_x86_64.tar.gz", testBaseURL)
+	require.NoError(t, err)
+	_, err = windowsOnly.Homebrew(DefaultPackageInfo)
+	assert.ErrorContains(t, err, "no macOS or Linux archives")
+}
+
+func TestManifest_Scoop(t *testing.T) {
+	t.Parallel()
+
+	m, err := NewManifest("0.9.0", testChecksums, testBaseURL)
+	require.NoError(t, err)
+	data, err := m.Render(FormatScoop, DefaultPackageInfo)
+	require.NoError(t, err)
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "0.9.0", got["version"])
+	assert.Equal(t, "autospec.exe", got["bin"])
+	assert.Equal(t, map[string]any{
+		"64bit": map[string]any{
+			"url":  testBaseURL + "/autospec_0.9.0_Windows_x86_64.tar.gz",
+			"hash": strings.Repeat("5", 64),
+		},
+	}, got["architecture"])
+	assert.Equal(t, map[string]any{
+		"architecture": map[string]any{
+			"64bit": map[string]any{"url": DownloadBaseURL + "/v$version/autospec_$version_Windows_x86_64.tar.gz"},
+		},
+		"hash": map[string]any{"url": DownloadBaseURL + "/v$version/checksums.txt"},
+	}, got["autoupdate"])
+
+	unixOnly, err := NewManifest("0.9.0", "4444444444444444444444444444444444444444444444444444444444444444  autospec_0.9.0_Linux_x86_64.tar.gz", testBaseURL)
+	require.NoError(t, err)
+	_, err = unixOnly.Scoop(DefaultPackageInfo)
+	assert.ErrorContains(t, err, "no Windows archives")
+
+	_, err = m.Render("apt", DefaultPackageInfo)
+	assert.ErrorContains(t, err, `unknown manifest format "apt"`)
+}
+
+func TestFormulaClass(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Autospec", formulaClass("autospec"))
+	assert.Equal(t, "AutospecNightly", formulaClass("autospec-nightly"))
+}
+
+func TestChecker_FetchRelease(t *testing.T) {
+	t.Parallel()
+
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_ = json.NewEncoder(w).Encode(ReleaseInfo{TagName: "v0.9.0"})
+	}))
+	defer server.Close()
+
+	checker := NewChecker(0)
+	checker.SetAPIURL(server.URL + "/repos/o/r/releases/latest")
+	_, err := checker.FetchRelease(context.Background(), "")
+	require.NoError(t, err)
+	release, err := checker.FetchRelease(context.Background(), "v0.9.0")
+	require.NoError(t, err)
+	assert.Equal(t, "v0.9.0", release.TagName)
+	assert.Equal(t, []string{"/repos/o/r/releases/latest", "/repos/o/r/releases/tags/v0.9.0"}, paths)
+}
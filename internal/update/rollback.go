@@ -0,0 +1,191 @@
+package update
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// HistoryFileName is the name of the update history file in the user
+	// config directory.
+	HistoryFileName = "update-history.yaml"
+
+	// maxHistoryEntries caps the update history; older entries are dropped.
+	maxHistoryEntries = 50
+
+	// probeTimeout bounds the version probe of a binary.
+	probeTimeout = 10 * time.Second
+)
+
+// History actions.
+const (
+	ActionUpdate   = "update"
+	ActionRollback = "rollback"
+)
+
+// Prober reports the version of the autospec binary at path, failing when
+// the binary does not run.
+type Prober func(ctx context.Context, path string) (string, error)
+
+// ProbeVersion runs "<path> version --plain" and returns the version it
+// prints. Every release supports this form, so it also works for binaries
+// older than the running one.
+func ProbeVersion(ctx context.Context, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "version", "--plain")
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("running %s version: %w: %s", filepath.Base(path), err, msg)
+		}
+		return "", fmt.Errorf("running %s version: %w", filepath.Base(path), err)
+	}
+	return parseVersionOutput(out)
+}
+
+// parseVersionOutput extracts the version from "autospec <version>", the
+// first line of "version --plain".
+func parseVersionOutput(out []byte) (string, error) {
+	line, _, _ := strings.Cut(string(out), "\n")
+	fields := strings.Fields(line)
+	if len(fields) != 2 || fields[0] != "autospec" {
+		return "", fmt.Errorf("unexpected version output %q", strings.TrimSpace(line))
+	}
+	return fields[1], nil
+}
+
+// HasBackup reports whether a previous binary is kept at the backup path.
+func (i *Installer) HasBackup() bool {
+	info, err := os.Stat(i.backupPath)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// SwapBackup exchanges the current binary and the backup, so the previous
+// version becomes current and the current one becomes the backup. Swapping
+// again undoes it. Renaming works for a running binary on every platform.
+func (i *Installer) SwapBackup() error {
+	if !i.HasBackup() {
+		return fmt.Errorf("no backup found at %s", i.backupPath)
+	}
+	tmpPath := i.executablePath + ".swap"
+	if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing stale %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(i.executablePath, tmpPath); err != nil {
+		return fmt.Errorf("moving current binary aside: %w", err)
+	}
+	if err := os.Rename(i.backupPath, i.executablePath); err != nil {
+		if restoreErr := os.Rename(tmpPath, i.executablePath); restoreErr != nil {
+			return fmt.Errorf("restoring backup: %w (and putting the current binary back failed: %v)", err, restoreErr)
+		}
+		return fmt.Errorf("restoring backup: %w", err)
+	}
+	if err := os.Rename(tmpPath, i.backupPath); err != nil {
+		return fmt.Errorf("keeping the replaced binary as %s: %w", i.backupPath, err)
+	}
+	return nil
+}
+
+// RollbackResult describes a completed rollback.
+type RollbackResult struct {
+	// From is the version that was replaced.
+	From string
+	// To is the version now installed.
+	To string
+}
+
+// RollbackToBackup restores the backup kept by the last update. The backup
+// is probed before anything is moved, and the restored binary is probed
+// again afterwards; if that fails the swap is undone. current is the
+// running version, recorded as From.
+func (i *Installer) RollbackToBackup(ctx context.Context, current string, probe Prober) (*RollbackResult, error) {
+	if !i.HasBackup() {
+		return nil, fmt.Errorf("no previous version to roll back to (expected %s)", i.backupPath)
+	}
+	to, err := probe(ctx, i.backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("previous binary does not run, nothing changed: %w", err)
+	}
+	if err := i.SwapBackup(); err != nil {
+		return nil, err
+	}
+	if _, err := probe(ctx, i.executablePath); err != nil {
+		if undoErr := i.SwapBackup(); undoErr != nil {
+			return nil, fmt.Errorf("restored binary does not run (%v) and undoing the rollback failed (%v)", err, undoErr)
+		}
+		return nil, fmt.Errorf("restored binary does not run, rollback undone: %w", err)
+	}
+	return &RollbackResult{From: current, To: to}, nil
+}
+
+// HistoryEntry records one update or rollback.
+type HistoryEntry struct {
+	Time   time.Time `yaml:"time" json:"time"`
+	Action string    `yaml:"action" json:"action"`
+	From   string    `yaml:"from" json:"from"`
+	To     string    `yaml:"to" json:"to"`
+	Binary string    `yaml:"binary,omitempty" json:"binary,omitempty"`
+}
+
+// historyFile is the on-disk layout of the update history.
+type historyFile struct {
+	Entries []HistoryEntry `yaml:"entries"`
+}
+
+// HistoryPath returns the update history file in configDir.
+func HistoryPath(configDir string) string {
+	return filepath.Join(configDir, HistoryFileName)
+}
+
+// LoadHistory reads the update history, oldest first. A missing file is an
+// empty history.
+func LoadHistory(path string) ([]HistoryEntry, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading update history: %w", err)
+	}
+	var file historyFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing update history %s: %w", path, err)
+	}
+	return file.Entries, nil
+}
+
+// AppendHistory adds entry to the update history, keeping the newest
+// maxHistoryEntries entries.
+func AppendHistory(path string, entry HistoryEntry) error {
+	entries, err := LoadHistory(path)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, entry)
+	if len(entries) > maxHistoryEntries {
+		entries = entries[len(entries)-maxHistoryEntries:]
+	}
+	data, err := yaml.Marshal(historyFile{Entries: entries})
+	if err != nil {
+		return fmt.Errorf("encoding update history: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing update history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeBinaries creates a current binary and a backup holding their names.
+func writeBinaries(t *testing.T, withBackup bool) *Installer {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "autospec")
+	require.NoError(t, os.WriteFile(path, []byte("new"), 0o755))
+	installer := NewInstallerAt(path)
+	if withBackup {
+		require.NoError(t, os.WriteFile(installer.GetBackupPath(), []byte("old"), 0o755))
+	}
+	return installer
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// contentProber reports a file's content as its version, failing for the
+// content named in broken.
+func contentProber(broken string) Prober {
+	return func(_ context.Context, path string) (string, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		if string(data) == broken {
+			return "", errors.New("exec format error")
+		}
+		return string(data), nil
+	}
+}
+
+func TestParseVersionOutput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		out     string
+		want    string
+		wantErr bool
+	}{
+		"plain output":  {out: "autospec v0.8.1\ncommit: abc\n", want: "v0.8.1"},
+		"dev build":     {out: "autospec dev\n", want: "dev"},
+		"other program": {out: "Usage: foo\n", wantErr: true},
+		"empty":         {out: "", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := parseVersionOutput([]byte(tt.out))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProbeVersion(t *testing.T) {
+	t.Parallel()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the binary")
+	}
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good")
+	require.NoError(t, os.WriteFile(good, []byte("#!/bin/sh\necho \"autospec v0.7.0\"\necho \"commit: abc\"\n"), 0o755))
+	got, err := ProbeVersion(context.Background(), good)
+	require.NoError(t, err)
+	assert.Equal(t, "v0.7.0", got)
+
+	bad := filepath.Join(dir, "bad")
+	require.NoError(t, os.WriteFile(bad, []byte("#!/bin/sh\necho boom >&2\nexit 2\n"), 0o755))
+	_, err = ProbeVersion(context.Background(), bad)
+	assert.ErrorContains(t, err, "boom")
+}
+
+func TestInstaller_SwapBackup(t *testing.T) {
+	t.Parallel()
+
+	installer := writeBinaries(t, true)
+	require.NoError(t, installer.SwapBackup())
+	assert.Equal(t, "old", readFile(t, installer.GetExecutablePath()))
+	assert.Equal(t, "new", readFile(t, installer.GetBackupPath()))
+
+	require.NoError(t, installer.SwapBackup())
+	assert.Equal(t, "new", readFile(t, installer.GetExecutablePath()))
+	assert.Equal(t, "old", readFile(t, installer.GetBackupPath()))
+
+	assert.Error(t, writeBinaries(t, false).SwapBackup())
+}
+
+func TestInstaller_RollbackToBackup(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		withBackup bool
+		broken     string
+		wantErr    string
+		wantExe    string
+	}{
+		"restores backup":    {withBackup: true, wantExe: "old"},
+		"no backup":          {wantErr: "no previous version", wantExe: "new"},
+		"backup broken":      {withBackup: true, broken: "old", wantErr: "nothing changed", wantExe: "new"},
+		"restored is broken": {withBackup: true, broken: "restored", wantErr: "rollback undone", wantExe: "new"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			installer := writeBinaries(t, tt.withBackup)
+			probe := contentProber(tt.broken)
+			if tt.broken == "restored" {
+				// The backup probes fine, the installed binary does not
+				probe = func(ctx context.Context, path string) (string, error) {
+					if path == installer.GetExecutablePath() {
+						return "", errors.New("killed")
+					}
+					return contentProber("")(ctx, path)
+				}
+			}
+
+			result, err := installer.RollbackToBackup(context.Background(), "v0.9.0", probe)
+			assert.Equal(t, tt.wantExe, readFile(t, installer.GetExecutablePath()))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &RollbackResult{From: "v0.9.0", To: "old"}, result)
+			assert.Equal(t, "new", readFile(t, installer.GetBackupPath()), "replaced binary becomes the backup")
+		})
+	}
+}
+
+func TestHistory(t *testing.T) {
+	t.Parallel()
+
+	path := HistoryPath(filepath.Join(t.TempDir(), "autospec"))
+	entries, err := LoadHistory(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < maxHistoryEntries+2; i++ {
+		require.NoError(t, AppendHistory(path, HistoryEntry{
+			Time:   start.Add(time.Duration(i) * time.Hour),
+			Action: ActionUpdate,
+			From:   "v0.1.0",
+			To:     "v0.2.0",
+		}))
+	}
+	require.NoError(t, AppendHistory(path, HistoryEntry{Time: start, Action: ActionRollback, From: "v0.2.0", To: "v0.1.0"}))
+
+	entries, err = LoadHistory(path)
+	require.NoError(t, err)
+	require.Len(t, entries, maxHistoryEntries)
+	assert.Equal(t, start.Add(3*time.Hour), entries[0].Time.UTC(), "oldest entries are dropped")
+	assert.Equal(t, ActionRollback, entries[len(entries)-1].Action)
+
+	require.NoError(t, os.WriteFile(path, []byte("entries: [oops"), 0o644))
+	_, err = LoadHistory(path)
+	assert.ErrorContains(t, err, "parsing update history")
+}
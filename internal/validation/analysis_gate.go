@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// AnalysisFileName is the analyze stage output inside a spec directory.
+const AnalysisFileName = "analysis.yaml"
+
+// Finding severities, from most to least severe.
+const (
+	SeverityCritical = "CRITICAL"
+	SeverityHigh     = "HIGH"
+	SeverityMedium   = "MEDIUM"
+	SeverityLow      = "LOW"
+)
+
+// AnalysisSeverities lists the valid finding severities, most severe first.
+var AnalysisSeverities = []string{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow}
+
+// AnalysisArtifacts lists the artifacts a finding can point at.
+var AnalysisArtifacts = []string{"spec", "plan", "tasks", "constitution"}
+
+// ParseSeverity normalizes a --fail-on value (case-insensitive) to a severity.
+// An empty string is returned unchanged and disables the gate.
+func ParseSeverity(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	upper := strings.ToUpper(s)
+	for _, sev := range AnalysisSeverities {
+		if upper == sev {
+			return sev, nil
+		}
+	}
+	return "", fmt.Errorf("invalid severity %q: must be one of critical, high, medium, low", s)
+}
+
+// severityRank returns higher numbers for more severe findings, 0 if unknown.
+func severityRank(severity string) int {
+	for i, sev := range AnalysisSeverities {
+		if sev == severity {
+			return len(AnalysisSeverities) - i
+		}
+	}
+	return 0
+}
+
+// AnalysisFinding is a single analyze finding as read for gating.
+type AnalysisFinding struct {
+	ID             string `yaml:"id"`
+	Severity       string `yaml:"severity"`
+	Artifact       string `yaml:"artifact"`
+	Field          string `yaml:"field"`
+	Location       string `yaml:"location"` // Older analyses point here instead of Artifact
+	Summary        string `yaml:"summary"`
+	Recommendation string `yaml:"recommendation"`
+}
+
+// AnalysisGateError reports findings at or above the fail-on severity.
+type AnalysisGateError struct {
+	Path     string
+	FailOn   string
+	Findings []AnalysisFinding
+}
+
+func (e *AnalysisGateError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "analysis gate: %d finding(s) at or above %s in %s:\n",
+		len(e.Findings), e.FailOn, e.Path)
+	for _, f := range e.Findings {
+		location := f.Artifact
+		if location == "" {
+			location = f.Location
+		}
+		if f.Field != "" {
+			location += ":" + f.Field
+		}
+		fmt.Fprintf(&sb, "  - %s [%s] %s: %s\n", f.ID, f.Severity, location, f.Summary)
+		if f.Recommendation != "" {
+			fmt.Fprintf(&sb, "    fix: %s\n", f.Recommendation)
+		}
+	}
+	sb.WriteString("\nResolve these findings and rerun analyze before implementing")
+	return sb.String()
+}
+
+// FindBlockingFindings returns the findings in the analysis file at path whose
+// severity is at or above failOn, in file order.
+func FindBlockingFindings(path, failOn string) ([]AnalysisFinding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading analysis: %w", err)
+	}
+	var analysis struct {
+		Findings []AnalysisFinding `yaml:"findings"`
+	}
+	if err := yaml.Unmarshal(data, &analysis); err != nil {
+		return nil, fmt.Errorf("parsing analysis %s: %w", path, err)
+	}
+
+	threshold := severityRank(failOn)
+	var blocking []AnalysisFinding
+	for _, f := range analysis.Findings {
+		if severityRank(f.Severity) >= threshold {
+			blocking = append(blocking, f)
+		}
+	}
+	return blocking, nil
+}
+
+// CheckAnalysisGate returns an *AnalysisGateError when specDir's analysis.yaml
+// has findings at or above failOn. An empty failOn, or a spec that was never
+// analyzed, passes the gate.
+func CheckAnalysisGate(specDir, failOn string) error {
+	failOn, err := ParseSeverity(failOn)
+	if err != nil || failOn == "" {
+		return err
+	}
+	path := filepath.Join(specDir, AnalysisFileName)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	blocking, err := FindBlockingFindings(path, failOn)
+	if err != nil {
+		return err
+	}
+	if len(blocking) > 0 {
+		return &AnalysisGateError{Path: path, FailOn: failOn, Findings: blocking}
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+package validation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gateAnalysis = `analysis:
+  branch: "001-auth"
+  timestamp: "2025-01-01T00:00:00Z"
+findings:
+  - id: "CON-001"
+    category: "constitution"
+    severity: "CRITICAL"
+    artifact: "tasks"
+    field: "phases[2].tasks[0]"
+    summary: "Missing test task before implementation"
+    recommendation: "Add a test task before T011"
+  - id: "AMB-001"
+    category: "ambiguity"
+    severity: "HIGH"
+    artifact: "spec"
+    field: "requirements.non_functional[0]"
+    summary: "Vague performance requirement"
+    recommendation: "Quantify as p95 latency"
+  - id: "INC-001"
+    category: "inconsistency"
+    severity: "LOW"
+    artifact: "plan"
+    summary: "Entity naming mismatch"
+    recommendation: "Standardize naming"
+summary:
+  overall_status: "FAIL"
+`
+
+func writeAnalysis(t *testing.T, content string) string {
+	t.Helper()
+	specDir := t.TempDir()
+	if content != "" {
+		require.NoError(t, os.WriteFile(filepath.Join(specDir, AnalysisFileName), []byte(content), 0o644))
+	}
+	return specDir
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		"empty disables":   {input: "", want: ""},
+		"lowercase":        {input: "critical", want: SeverityCritical},
+		"mixed case":       {input: "High", want: SeverityHigh},
+		"uppercase":        {input: "LOW", want: SeverityLow},
+		"unknown severity": {input: "severe", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseSeverity(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestCheckAnalysisGate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		analysis string
+		failOn   string
+		wantIDs  []string
+		wantErr  bool
+	}{
+		"gate disabled": {
+			analysis: gateAnalysis,
+			failOn:   "",
+		},
+		"no analysis passes": {
+			failOn: "critical",
+		},
+		"critical blocks only critical": {
+			analysis: gateAnalysis,
+			failOn:   "critical",
+			wantIDs:  []string{"CON-001"},
+		},
+		"high includes critical": {
+			analysis: gateAnalysis,
+			failOn:   "high",
+			wantIDs:  []string{"CON-001", "AMB-001"},
+		},
+		"low includes everything": {
+			analysis: gateAnalysis,
+			failOn:   "low",
+			wantIDs:  []string{"CON-001", "AMB-001", "INC-001"},
+		},
+		"clean analysis passes": {
+			analysis: "analysis:\n  branch: x\n  timestamp: y\nfindings: []\nsummary:\n  overall_status: PASS\n",
+			failOn:   "low",
+		},
+		"invalid fail-on": {
+			analysis: gateAnalysis,
+			failOn:   "blocker",
+			wantErr:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			specDir := writeAnalysis(t, tc.analysis)
+
+			err := CheckAnalysisGate(specDir, tc.failOn)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			if len(tc.wantIDs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			var gateErr *AnalysisGateError
+			require.True(t, errors.As(err, &gateErr), "expected AnalysisGateError, got %v", err)
+			var ids []string
+			for _, f := range gateErr.Findings {
+				ids = append(ids, f.ID)
+			}
+			assert.Equal(t, tc.wantIDs, ids)
+		})
+	}
+}
+
+func TestAnalysisGateError_Message(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeAnalysis(t, gateAnalysis)
+	err := CheckAnalysisGate(specDir, "high")
+	require.Error(t, err)
+
+	msg := err.Error()
+	assert.Contains(t, msg, "2 finding(s) at or above HIGH")
+	assert.Contains(t, msg, "CON-001 [CRITICAL] tasks:phases[2].tasks[0]: Missing test task before implementation")
+	assert.Contains(t, msg, "fix: Quantify as p95 latency")
+	assert.NotContains(t, msg, "INC-001")
+}
+
+func TestAnalysisGateError_LegacyLocation(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeAnalysis(t, `findings:
+  - id: "DUP-001"
+    category: "duplication"
+    severity: "HIGH"
+    location: "spec.yaml:FR-003"
+    summary: "Duplicate requirement"
+`)
+	err := CheckAnalysisGate(specDir, "high")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "DUP-001 [HIGH] spec.yaml:FR-003: Duplicate requirement")
+}
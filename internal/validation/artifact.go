@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -140,6 +142,10 @@ func NewArtifactValidator(artifactType ArtifactType) (ArtifactValidator, error)
 		return &ChecklistValidator{}, nil
 	case ArtifactTypeConstitution:
 		return &ConstitutionValidator{}, nil
+	case ArtifactTypeResearch:
+		return &ResearchValidator{}, nil
+	case ArtifactTypeForecast:
+		return &ForecastValidator{}, nil
 	default:
 		return nil, fmt.Errorf("unknown artifact type: %s", artifactType)
 	}
@@ -223,12 +229,19 @@ func getNodeColumn(node *yaml.Node) int {
 }
 
 // validateRequiredField checks if a required field exists in a mapping node.
-func validateRequiredField(root *yaml.Node, fieldName string, result *ValidationResult) *yaml.Node {
+// parentPath is the mapping's own path ("" for the document root). A missing
+// field is reported at the mapping's first key, which is where it belongs.
+func validateRequiredField(root *yaml.Node, parentPath, fieldName string, result *ValidationResult) *yaml.Node {
 	node := findNode(root, fieldName)
 	if node == nil {
+		path := fieldName
+		if parentPath != "" {
+			path = parentPath + "." + fieldName
+		}
 		result.AddError(&ValidationError{
-			Path:    fieldName,
+			Path:    path,
 			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
 			Message: fmt.Sprintf("missing required field: %s", fieldName),
 			Hint:    fmt.Sprintf("Add the '%s' field to your YAML file", fieldName),
 		})
@@ -237,6 +250,37 @@ func validateRequiredField(root *yaml.Node, fieldName string, result *Validation
 	return node
 }
 
+// yamlErrorLine matches the location yaml.v3 puts in syntax errors
+// ("yaml: line 4: ..." or "yaml: line 4, column 7: ...").
+var yamlErrorLine = regexp.MustCompile(`line (\d+)(?:, column (\d+))?`)
+
+// parseErrorLocation extracts the 1-based line and column of a YAML syntax
+// error. Either is 0 when the parser did not report it.
+func parseErrorLocation(err error) (line, column int) {
+	m := yamlErrorLine.FindStringSubmatch(err.Error())
+	if m == nil {
+		return 0, 0
+	}
+	line, _ = strconv.Atoi(m[1])
+	if m[2] != "" {
+		column, _ = strconv.Atoi(m[2])
+	}
+	return line, column
+}
+
+// parseError reports a file that is not valid YAML, at the location the
+// parser reported.
+func parseError(path string, err error) *ValidationError {
+	line, column := parseErrorLocation(err)
+	return &ValidationError{
+		Path:    path,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf("failed to parse YAML: %v", err),
+		Hint:    "Check the YAML syntax for errors",
+	}
+}
+
 // validateFieldType checks if a field has the expected YAML node kind.
 func validateFieldType(node *yaml.Node, path string, expectedKind yaml.Kind, expectedType string, result *ValidationResult) bool {
 	if node == nil {
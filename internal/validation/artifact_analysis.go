@@ -23,11 +23,7 @@ func (v *AnalysisValidator) Validate(path string) *ValidationResult {
 	// Parse the YAML file
 	root, err := parseYAMLFile(path)
 	if err != nil {
-		result.AddError(&ValidationError{
-			Path:    path,
-			Message: fmt.Sprintf("failed to parse YAML: %v", err),
-			Hint:    "Check the YAML syntax for errors",
-		})
+		result.AddError(parseError(path, err))
 		return result
 	}
 
@@ -35,6 +31,8 @@ func (v *AnalysisValidator) Validate(path string) *ValidationResult {
 	if rootMapping == nil {
 		result.AddError(&ValidationError{
 			Path:    path,
+			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
 			Message: "expected a YAML mapping at document root",
 			Hint:    "The analysis.yaml file should start with key-value pairs, not a list or scalar",
 		})
@@ -42,9 +40,9 @@ func (v *AnalysisValidator) Validate(path string) *ValidationResult {
 	}
 
 	// Validate required fields
-	analysisNode := validateRequiredField(rootMapping, "analysis", result)
-	findingsNode := validateRequiredField(rootMapping, "findings", result)
-	summaryNode := validateRequiredField(rootMapping, "summary", result)
+	analysisNode := validateRequiredField(rootMapping, "", "analysis", result)
+	findingsNode := validateRequiredField(rootMapping, "", "findings", result)
+	summaryNode := validateRequiredField(rootMapping, "", "summary", result)
 
 	// Validate analysis section
 	if analysisNode != nil {
@@ -61,6 +59,8 @@ func (v *AnalysisValidator) Validate(path string) *ValidationResult {
 		v.validateSummary(summaryNode, result)
 	}
 
+	checkStrictness(rootMapping, &AnalysisSchema, result)
+
 	// Build summary if valid
 	if result.Valid {
 		result.Summary = v.buildSummary(rootMapping)
@@ -76,8 +76,8 @@ func (v *AnalysisValidator) validateAnalysisSection(node *yaml.Node, result *Val
 	}
 
 	// Required fields in analysis
-	validateRequiredField(node, "branch", result)
-	validateRequiredField(node, "timestamp", result)
+	validateRequiredField(node, "analysis", "branch", result)
+	validateRequiredField(node, "analysis", "timestamp", result)
 }
 
 // validateFindings validates the findings section.
@@ -98,6 +98,7 @@ func (v *AnalysisValidator) validateFinding(node *yaml.Node, path string, result
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -105,20 +106,33 @@ func (v *AnalysisValidator) validateFinding(node *yaml.Node, path string, result
 		return
 	}
 
-	// Required fields
-	requiredFields := []string{"id", "category", "severity", "location", "summary"}
+	// Required fields; recommendation and field are optional
+	requiredFields := []string{"id", "category", "severity", "summary"}
 	for _, field := range requiredFields {
 		fieldNode := findNode(node, field)
 		if fieldNode == nil {
 			result.AddError(&ValidationError{
 				Path:    fmt.Sprintf("%s.%s", path, field),
 				Line:    getNodeLine(node),
+				Column:  getNodeColumn(node),
 				Message: fmt.Sprintf("missing required field: %s", field),
 				Hint:    fmt.Sprintf("Add the '%s' field to this finding", field),
 			})
 		}
 	}
 
+	// A finding points at its artifact, or at a free-form location in
+	// analyses written before artifact existed
+	if findNode(node, "artifact") == nil && findNode(node, "location") == nil {
+		result.AddError(&ValidationError{
+			Path:    path + ".artifact",
+			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
+			Message: "missing required field: artifact",
+			Hint:    "Add the 'artifact' field (spec, plan, tasks or constitution) to this finding",
+		})
+	}
+
 	// Validate category enum
 	categoryNode := findNode(node, "category")
 	if categoryNode != nil {
@@ -129,8 +143,13 @@ func (v *AnalysisValidator) validateFinding(node *yaml.Node, path string, result
 	// Validate severity enum
 	severityNode := findNode(node, "severity")
 	if severityNode != nil {
-		validateEnumValue(severityNode, path+".severity",
-			[]string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}, result)
+		validateEnumValue(severityNode, path+".severity", AnalysisSeverities, result)
+	}
+
+	// Validate artifact enum
+	artifactNode := findNode(node, "artifact")
+	if artifactNode != nil {
+		validateEnumValue(artifactNode, path+".artifact", AnalysisArtifacts, result)
 	}
 }
 
@@ -146,6 +165,7 @@ func (v *AnalysisValidator) validateSummary(node *yaml.Node, result *ValidationR
 		result.AddError(&ValidationError{
 			Path:    "summary.overall_status",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: overall_status",
 			Hint:    "Add the 'overall_status' field with value PASS, WARN, or FAIL",
 		})
@@ -171,13 +191,13 @@ func (v *AnalysisValidator) buildSummary(root *yaml.Node) *ArtifactSummary {
 			severityNode := findNode(finding, "severity")
 			if severityNode != nil {
 				switch severityNode.Value {
-				case "CRITICAL":
+				case SeverityCritical:
 					summary.Counts["critical_findings"]++
-				case "HIGH":
+				case SeverityHigh:
 					summary.Counts["high_findings"]++
-				case "MEDIUM":
+				case SeverityMedium:
 					summary.Counts["medium_findings"]++
-				case "LOW":
+				case SeverityLow:
 					summary.Counts["low_findings"]++
 				}
 			}
@@ -35,7 +35,8 @@ findings:
   - id: "F-001"
     category: "duplication"
     severity: "HIGH"
-    location: "spec.yaml"
+    artifact: "spec"
+    recommendation: "Fix it"
     summary: "Duplicate requirement found"
 
 summary:
@@ -53,7 +54,8 @@ _meta:
   - id: "F-001"
     category: "duplication"
     severity: "HIGH"
-    location: "spec.yaml"
+    artifact: "spec"
+    recommendation: "Fix it"
     summary: "Test"
 
 summary:
@@ -92,7 +94,8 @@ findings:
   - id: "F-001"
     category: "duplication"
     severity: "INVALID"
-    location: "spec.yaml"
+    artifact: "spec"
+    recommendation: "Fix it"
     summary: "Test"
 
 summary:
@@ -110,11 +113,32 @@ findings:
   - id: "F-001"
     category: "invalid_category"
     severity: "HIGH"
-    location: "spec.yaml"
+    artifact: "spec"
+    recommendation: "Fix it"
     summary: "Test"
 
 summary:
   overall_status: "PASS"
+`,
+			wantValid: false,
+			wantErrs:  1,
+		},
+		"invalid artifact": {
+			yaml: `analysis:
+  branch: "001-test"
+  timestamp: "2025-01-01"
+
+findings:
+  - id: "F-001"
+    category: "coverage"
+    severity: "HIGH"
+    artifact: "readme"
+    field: "intro"
+    summary: "Test"
+    recommendation: "Fix it"
+
+summary:
+  overall_status: "WARN"
 `,
 			wantValid: false,
 			wantErrs:  1,
@@ -145,6 +169,24 @@ summary:
 			wantValid: false,
 			wantErrs:  1,
 		},
+		"legacy finding with location valid": {
+			yaml: `analysis:
+  branch: "001-test"
+  timestamp: "2025-01-01"
+
+findings:
+  - id: "F-001"
+    category: "duplication"
+    severity: "HIGH"
+    location: "spec.yaml"
+    summary: "Duplicate requirement found"
+
+summary:
+  overall_status: "WARN"
+`,
+			wantValid: true,
+			wantErrs:  0,
+		},
 		"empty findings array valid": {
 			yaml: `analysis:
   branch: "001-test"
@@ -170,7 +212,7 @@ summary:
   overall_status: "WARN"
 `,
 			wantValid: false,
-			wantErrs:  4, // missing category, severity, location, summary
+			wantErrs:  4, // missing category, severity, summary, artifact
 		},
 		"analysis section wrong type": {
 			yaml: `analysis: "not a mapping"
@@ -231,22 +273,26 @@ findings:
   - id: "F-001"
     category: "duplication"
     severity: "CRITICAL"
-    location: "spec.yaml"
+    artifact: "spec"
+    recommendation: "Fix it"
     summary: "Critical issue"
   - id: "F-002"
     category: "ambiguity"
     severity: "HIGH"
-    location: "plan.yaml"
+    artifact: "plan"
+    recommendation: "Fix it"
     summary: "High issue"
   - id: "F-003"
     category: "coverage"
     severity: "MEDIUM"
-    location: "tasks.yaml"
+    artifact: "tasks"
+    recommendation: "Fix it"
     summary: "Medium issue"
   - id: "F-004"
     category: "inconsistency"
     severity: "LOW"
-    location: "spec.yaml"
+    artifact: "spec"
+    recommendation: "Fix it"
     summary: "Low issue"
 
 summary:
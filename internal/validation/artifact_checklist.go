@@ -2,10 +2,22 @@ package validation
 
 import (
 	"fmt"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Checklist item status values.
+const (
+	ChecklistStatusPending = "pending"
+	ChecklistStatusPass    = "pass"
+	ChecklistStatusFail    = "fail"
+	ChecklistStatusWaived  = "waived"
+)
+
+// ChecklistStatuses lists the valid checklist item statuses.
+var ChecklistStatuses = []string{ChecklistStatusPending, ChecklistStatusPass, ChecklistStatusFail, ChecklistStatusWaived}
+
 // ChecklistValidator validates checklist.yaml artifacts.
 type ChecklistValidator struct {
 	baseValidator
@@ -23,11 +35,7 @@ func (v *ChecklistValidator) Validate(path string) *ValidationResult {
 	// Parse the YAML file
 	root, err := parseYAMLFile(path)
 	if err != nil {
-		result.AddError(&ValidationError{
-			Path:    path,
-			Message: fmt.Sprintf("failed to parse YAML: %v", err),
-			Hint:    "Check the YAML syntax for errors",
-		})
+		result.AddError(parseError(path, err))
 		return result
 	}
 
@@ -35,6 +43,8 @@ func (v *ChecklistValidator) Validate(path string) *ValidationResult {
 	if rootMapping == nil {
 		result.AddError(&ValidationError{
 			Path:    path,
+			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
 			Message: "expected a YAML mapping at document root",
 			Hint:    "The checklist.yaml file should start with key-value pairs, not a list or scalar",
 		})
@@ -42,8 +52,8 @@ func (v *ChecklistValidator) Validate(path string) *ValidationResult {
 	}
 
 	// Validate required fields
-	checklistNode := validateRequiredField(rootMapping, "checklist", result)
-	categoriesNode := validateRequiredField(rootMapping, "categories", result)
+	checklistNode := validateRequiredField(rootMapping, "", "checklist", result)
+	categoriesNode := validateRequiredField(rootMapping, "", "categories", result)
 
 	// Validate checklist section
 	if checklistNode != nil {
@@ -55,6 +65,8 @@ func (v *ChecklistValidator) Validate(path string) *ValidationResult {
 		v.validateCategories(categoriesNode, result)
 	}
 
+	checkStrictness(rootMapping, &ChecklistSchema, result)
+
 	// Build summary if valid
 	if result.Valid {
 		result.Summary = v.buildSummary(rootMapping)
@@ -70,9 +82,9 @@ func (v *ChecklistValidator) validateChecklistSection(node *yaml.Node, result *V
 	}
 
 	// Required fields in checklist
-	validateRequiredField(node, "feature", result)
-	validateRequiredField(node, "branch", result)
-	validateRequiredField(node, "domain", result)
+	validateRequiredField(node, "checklist", "feature", result)
+	validateRequiredField(node, "checklist", "branch", result)
+	validateRequiredField(node, "checklist", "domain", result)
 
 	// Validate audience enum if present
 	audienceNode := findNode(node, "audience")
@@ -107,6 +119,7 @@ func (v *ChecklistValidator) validateCategory(node *yaml.Node, path string, resu
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -120,6 +133,7 @@ func (v *ChecklistValidator) validateCategory(node *yaml.Node, path string, resu
 		result.AddError(&ValidationError{
 			Path:    fmt.Sprintf("%s.name", path),
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: name",
 			Hint:    "Add the 'name' field to this category",
 		})
@@ -130,6 +144,7 @@ func (v *ChecklistValidator) validateCategory(node *yaml.Node, path string, resu
 		result.AddError(&ValidationError{
 			Path:    fmt.Sprintf("%s.items", path),
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: items",
 			Hint:    "Add the 'items' field with a list of checklist items",
 		})
@@ -147,6 +162,7 @@ func (v *ChecklistValidator) validateChecklistItem(node *yaml.Node, path string,
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -162,6 +178,7 @@ func (v *ChecklistValidator) validateChecklistItem(node *yaml.Node, path string,
 			result.AddError(&ValidationError{
 				Path:    fmt.Sprintf("%s.%s", path, field),
 				Line:    getNodeLine(node),
+				Column:  getNodeColumn(node),
 				Message: fmt.Sprintf("missing required field: %s", field),
 				Hint:    fmt.Sprintf("Add the '%s' field to this checklist item", field),
 			})
@@ -171,8 +188,20 @@ func (v *ChecklistValidator) validateChecklistItem(node *yaml.Node, path string,
 	// Validate status enum
 	statusNode := findNode(node, "status")
 	if statusNode != nil {
-		validateEnumValue(statusNode, path+".status",
-			[]string{"pending", "pass", "fail"}, result)
+		validateEnumValue(statusNode, path+".status", ChecklistStatuses, result)
+		// A waiver must say why the item does not need to pass
+		if statusNode.Value == ChecklistStatusWaived {
+			notesNode := findNode(node, "notes")
+			if notesNode == nil || strings.TrimSpace(notesNode.Value) == "" {
+				result.AddError(&ValidationError{
+					Path:    path + ".notes",
+					Line:    getNodeLine(statusNode),
+					Column:  getNodeColumn(statusNode),
+					Message: "waived item requires notes explaining the waiver",
+					Hint:    "Add a 'notes' field with the reason this item is waived",
+				})
+			}
+		}
 	}
 
 	// Validate quality_dimension enum if present
@@ -199,6 +228,7 @@ func (v *ChecklistValidator) buildSummary(root *yaml.Node) *ArtifactSummary {
 		passedItems := 0
 		failedItems := 0
 		pendingItems := 0
+		waivedItems := 0
 
 		for _, category := range categoriesNode.Content {
 			itemsNode := findNode(category, "items")
@@ -215,6 +245,8 @@ func (v *ChecklistValidator) buildSummary(root *yaml.Node) *ArtifactSummary {
 							failedItems++
 						case "pending":
 							pendingItems++
+						case "waived":
+							waivedItems++
 						}
 					}
 				}
@@ -225,6 +257,7 @@ func (v *ChecklistValidator) buildSummary(root *yaml.Node) *ArtifactSummary {
 		summary.Counts["passed"] = passedItems
 		summary.Counts["failed"] = failedItems
 		summary.Counts["pending"] = pendingItems
+		summary.Counts["waived"] = waivedItems
 	}
 
 	return summary
@@ -290,6 +290,40 @@ categories:
 			wantValid: true,
 			wantErrs:  0,
 		},
+		"waived item with notes": {
+			yaml: `checklist:
+  feature: "Test"
+  branch: "001-test"
+  domain: "security"
+
+categories:
+  - name: "Security"
+    items:
+      - id: "CHK001"
+        description: "Are rate limits defined?"
+        status: "waived"
+        notes: "Rate limiting handled by the API gateway"
+`,
+			wantValid: true,
+			wantErrs:  0,
+		},
+		"waived item without notes": {
+			yaml: `checklist:
+  feature: "Test"
+  branch: "001-test"
+  domain: "security"
+
+categories:
+  - name: "Security"
+    items:
+      - id: "CHK001"
+        description: "Are rate limits defined?"
+        status: "waived"
+        notes: "  "
+`,
+			wantValid: false,
+			wantErrs:  1,
+		},
 	}
 
 	for name, tc := range tests {
@@ -23,11 +23,7 @@ func (v *ConstitutionValidator) Validate(path string) *ValidationResult {
 	// Parse the YAML file
 	root, err := parseYAMLFile(path)
 	if err != nil {
-		result.AddError(&ValidationError{
-			Path:    path,
-			Message: fmt.Sprintf("failed to parse YAML: %v", err),
-			Hint:    "Check the YAML syntax for errors",
-		})
+		result.AddError(parseError(path, err))
 		return result
 	}
 
@@ -35,6 +31,8 @@ func (v *ConstitutionValidator) Validate(path string) *ValidationResult {
 	if rootMapping == nil {
 		result.AddError(&ValidationError{
 			Path:    path,
+			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
 			Message: "expected a YAML mapping at document root",
 			Hint:    "The constitution.yaml file should start with key-value pairs, not a list or scalar",
 		})
@@ -42,8 +40,8 @@ func (v *ConstitutionValidator) Validate(path string) *ValidationResult {
 	}
 
 	// Validate required fields
-	constitutionNode := validateRequiredField(rootMapping, "constitution", result)
-	principlesNode := validateRequiredField(rootMapping, "principles", result)
+	constitutionNode := validateRequiredField(rootMapping, "", "constitution", result)
+	principlesNode := validateRequiredField(rootMapping, "", "principles", result)
 
 	// Validate constitution section
 	if constitutionNode != nil {
@@ -61,6 +59,8 @@ func (v *ConstitutionValidator) Validate(path string) *ValidationResult {
 		v.validateSections(sectionsNode, result)
 	}
 
+	checkStrictness(rootMapping, &ConstitutionSchema, result)
+
 	// Build summary if valid
 	if result.Valid {
 		result.Summary = v.buildSummary(rootMapping)
@@ -76,8 +76,8 @@ func (v *ConstitutionValidator) validateConstitutionSection(node *yaml.Node, res
 	}
 
 	// Required fields in constitution
-	validateRequiredField(node, "project_name", result)
-	validateRequiredField(node, "version", result)
+	validateRequiredField(node, "constitution", "project_name", result)
+	validateRequiredField(node, "constitution", "version", result)
 }
 
 // validatePrinciples validates the principles section.
@@ -98,6 +98,7 @@ func (v *ConstitutionValidator) validatePrinciple(node *yaml.Node, path string,
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -113,6 +114,7 @@ func (v *ConstitutionValidator) validatePrinciple(node *yaml.Node, path string,
 			result.AddError(&ValidationError{
 				Path:    fmt.Sprintf("%s.%s", path, field),
 				Line:    getNodeLine(node),
+				Column:  getNodeColumn(node),
 				Message: fmt.Sprintf("missing required field: %s", field),
 				Hint:    fmt.Sprintf("Add the '%s' field to this principle", field),
 			})
@@ -152,6 +154,7 @@ func (v *ConstitutionValidator) validateSection(node *yaml.Node, path string, re
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -167,6 +170,7 @@ func (v *ConstitutionValidator) validateSection(node *yaml.Node, path string, re
 			result.AddError(&ValidationError{
 				Path:    fmt.Sprintf("%s.%s", path, field),
 				Line:    getNodeLine(node),
+				Column:  getNodeColumn(node),
 				Message: fmt.Sprintf("missing required field: %s", field),
 				Hint:    fmt.Sprintf("Add the '%s' field to this section", field),
 			})
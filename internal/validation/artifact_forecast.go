@@ -0,0 +1,181 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ForecastValidator validates forecast.yaml artifacts.
+type ForecastValidator struct {
+	baseValidator
+}
+
+// Type returns the artifact type.
+func (v *ForecastValidator) Type() ArtifactType {
+	return ArtifactTypeForecast
+}
+
+// Validate validates a forecast.yaml file at the given path.
+func (v *ForecastValidator) Validate(path string) *ValidationResult {
+	result := &ValidationResult{Valid: true}
+
+	// Parse the YAML file
+	root, err := parseYAMLFile(path)
+	if err != nil {
+		result.AddError(parseError(path, err))
+		return result
+	}
+
+	rootMapping := getRootMapping(root)
+	if rootMapping == nil {
+		result.AddError(&ValidationError{
+			Path:    path,
+			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
+			Message: "expected a YAML mapping at document root",
+			Hint:    "The forecast.yaml file should start with key-value pairs, not a list or scalar",
+		})
+		return result
+	}
+
+	// Validate required fields
+	forecastNode := validateRequiredField(rootMapping, "", "forecast", result)
+	summaryNode := validateRequiredField(rootMapping, "", "summary", result)
+	estimatesNode := validateRequiredField(rootMapping, "", "estimates", result)
+
+	if forecastNode != nil && validateFieldType(forecastNode, "forecast", yaml.MappingNode, "object", result) {
+		validateRequiredField(forecastNode, "forecast", "branch", result)
+		validateRequiredField(forecastNode, "forecast", "created", result)
+	}
+
+	if summaryNode != nil && validateFieldType(summaryNode, "summary", yaml.MappingNode, "object", result) {
+		if n := validateRequiredField(summaryNode, "summary", "total_effort_hours", result); n != nil {
+			validateEffortHours(n, "summary.total_effort_hours", result)
+		}
+		if n := validateRequiredField(summaryNode, "summary", "overall_risk", result); n != nil {
+			validateEnumValue(n, "summary.overall_risk", ForecastLevels, result)
+		}
+		if n := findNode(summaryNode, "confidence"); n != nil {
+			validateEnumValue(n, "summary.confidence", ForecastLevels, result)
+		}
+	}
+
+	if estimatesNode != nil {
+		v.validateEstimates(estimatesNode, result)
+	}
+
+	// Validate optional sections
+	if sequencingNode := findNode(rootMapping, "sequencing"); sequencingNode != nil {
+		v.validateSequencing(sequencingNode, result)
+	}
+
+	checkStrictness(rootMapping, &ForecastSchema, result)
+
+	// Build summary if valid
+	if result.Valid {
+		result.Summary = v.buildSummary(rootMapping)
+	}
+
+	return result
+}
+
+// validateEstimates validates the estimates section, which needs at least one estimate.
+func (v *ForecastValidator) validateEstimates(node *yaml.Node, result *ValidationResult) {
+	if !validateFieldType(node, "estimates", yaml.SequenceNode, "array", result) {
+		return
+	}
+	if len(node.Content) == 0 {
+		result.AddError(&ValidationError{
+			Path:    "estimates",
+			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
+			Message: "estimates must not be empty",
+			Hint:    "Add one estimate per task in tasks.yaml",
+		})
+		return
+	}
+
+	for i, estimateNode := range node.Content {
+		path := fmt.Sprintf("estimates[%d]", i)
+		if !validateFieldType(estimateNode, path, yaml.MappingNode, "object", result) {
+			continue
+		}
+		validateRequiredField(estimateNode, path, "task_id", result)
+		if n := validateRequiredField(estimateNode, path, "effort_hours", result); n != nil {
+			validateEffortHours(n, path+".effort_hours", result)
+		}
+		if n := validateRequiredField(estimateNode, path, "risk", result); n != nil {
+			validateEnumValue(n, path+".risk", ForecastLevels, result)
+		}
+	}
+}
+
+// validateSequencing validates the optional sequencing steps.
+func (v *ForecastValidator) validateSequencing(node *yaml.Node, result *ValidationResult) {
+	if !validateFieldType(node, "sequencing", yaml.SequenceNode, "array", result) {
+		return
+	}
+	for i, stepNode := range node.Content {
+		path := fmt.Sprintf("sequencing[%d]", i)
+		if !validateFieldType(stepNode, path, yaml.MappingNode, "object", result) {
+			continue
+		}
+		validateRequiredField(stepNode, path, "step", result)
+		if n := validateRequiredField(stepNode, path, "task_ids", result); n != nil {
+			validateFieldType(n, path+".task_ids", yaml.SequenceNode, "array", result)
+		}
+	}
+}
+
+// validateEffortHours checks that an effort value is a non-negative number.
+func validateEffortHours(node *yaml.Node, path string, result *ValidationResult) {
+	if _, ok := parseEffortHours(node); ok {
+		return
+	}
+	result.AddError(&ValidationError{
+		Path:     path,
+		Line:     getNodeLine(node),
+		Column:   getNodeColumn(node),
+		Message:  fmt.Sprintf("'%s' must be a non-negative number of hours", path),
+		Expected: "number",
+		Actual:   node.Value,
+		Hint:     "Use an unquoted number such as 4 or 2.5",
+	})
+}
+
+// parseEffortHours returns the hours in a scalar node, accepting integers and
+// decimals but not quoted strings.
+func parseEffortHours(node *yaml.Node) (float64, bool) {
+	if node.Kind != yaml.ScalarNode || (node.Tag != "!!int" && node.Tag != "!!float") {
+		return 0, false
+	}
+	hours, err := strconv.ParseFloat(node.Value, 64)
+	if err != nil || hours < 0 {
+		return 0, false
+	}
+	return hours, true
+}
+
+// buildSummary builds the summary for a valid forecast artifact.
+func (v *ForecastValidator) buildSummary(root *yaml.Node) *ArtifactSummary {
+	summary := &ArtifactSummary{
+		Type:   ArtifactTypeForecast,
+		Counts: make(map[string]int),
+	}
+
+	if estimatesNode := findNode(root, "estimates"); estimatesNode != nil && estimatesNode.Kind == yaml.SequenceNode {
+		summary.Counts["estimates"] = len(estimatesNode.Content)
+		for _, estimateNode := range estimatesNode.Content {
+			if riskNode := findNode(estimateNode, "risk"); riskNode != nil && riskNode.Value == "high" {
+				summary.Counts["high_risk"]++
+			}
+		}
+	}
+	if sequencingNode := findNode(root, "sequencing"); sequencingNode != nil && sequencingNode.Kind == yaml.SequenceNode {
+		summary.Counts["steps"] = len(sequencingNode.Content)
+	}
+
+	return summary
+}
@@ -0,0 +1,150 @@
+// Package validation_test tests forecast.yaml schema validation and rollup.
+// Related: internal/validation/artifact_forecast.go, internal/validation/forecast.go
+// Tags: validation, forecast, effort, risk, schema, yaml, artifact
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestForecastValidator_ValidFile(t *testing.T) {
+	t.Parallel()
+	result := (&ForecastValidator{}).Validate(filepath.Join("testdata", "forecast", "valid.yaml"))
+
+	if !result.Valid {
+		t.Fatalf("expected valid result, got errors: %v", result.Errors)
+	}
+	if result.Summary == nil || result.Summary.Type != ArtifactTypeForecast {
+		t.Fatalf("summary = %+v, want forecast summary", result.Summary)
+	}
+	for key, want := range map[string]int{"estimates": 3, "high_risk": 1, "steps": 2} {
+		if got := result.Summary.Counts[key]; got != want {
+			t.Errorf("summary.Counts[%s] = %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestForecastValidator_Errors(t *testing.T) {
+	t.Parallel()
+
+	header := "forecast:\n  branch: \"003-auth\"\n  created: \"2025-01-15\"\n"
+	summary := "summary:\n  total_effort_hours: 6\n  overall_risk: \"low\"\n"
+	estimate := "estimates:\n  - task_id: \"T001\"\n    effort_hours: 6\n    risk: \"low\"\n"
+
+	tests := map[string]struct {
+		content   string
+		wantError string
+	}{
+		"missing estimates": {
+			content:   header + summary,
+			wantError: "missing required field: estimates",
+		},
+		"empty estimates": {
+			content:   header + summary + "estimates: []\n",
+			wantError: "estimates must not be empty",
+		},
+		"estimate without risk": {
+			content:   header + summary + "estimates:\n  - task_id: \"T001\"\n    effort_hours: 2\n",
+			wantError: "estimates[0].risk",
+		},
+		"invalid risk": {
+			content:   header + summary + "estimates:\n  - task_id: \"T001\"\n    effort_hours: 2\n    risk: \"extreme\"\n",
+			wantError: "invalid value for field 'estimates[0].risk'",
+		},
+		"quoted effort": {
+			content:   header + summary + "estimates:\n  - task_id: \"T001\"\n    effort_hours: \"2h\"\n    risk: \"low\"\n",
+			wantError: "'estimates[0].effort_hours' must be a non-negative number",
+		},
+		"negative total": {
+			content:   header + "summary:\n  total_effort_hours: -1\n  overall_risk: \"low\"\n" + estimate,
+			wantError: "'summary.total_effort_hours' must be a non-negative number",
+		},
+		"missing overall risk": {
+			content:   header + "summary:\n  total_effort_hours: 6\n" + estimate,
+			wantError: "missing required field: overall_risk",
+		},
+		"sequencing step without tasks": {
+			content:   header + summary + estimate + "sequencing:\n  - step: 1\n",
+			wantError: "sequencing[0].task_ids",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "forecast.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			result := (&ForecastValidator{}).Validate(path)
+			if result.Valid {
+				t.Fatal("expected validation to fail")
+			}
+			for _, err := range result.Errors {
+				if strings.Contains(err.Error(), tt.wantError) {
+					return
+				}
+			}
+			t.Errorf("expected error containing %q, got: %v", tt.wantError, result.Errors)
+		})
+	}
+}
+
+func TestGetForecastRollup(t *testing.T) {
+	t.Parallel()
+
+	rollup, err := GetForecastRollup(t.TempDir())
+	if err != nil || rollup != nil {
+		t.Fatalf("missing forecast.yaml: got %+v, %v; want nil, nil", rollup, err)
+	}
+
+	dir := t.TempDir()
+	data, err := os.ReadFile(filepath.Join("testdata", "forecast", "valid.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(GetForecastFilePath(dir), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	rollup, err = GetForecastRollup(dir)
+	if err != nil {
+		t.Fatalf("GetForecastRollup() error = %v", err)
+	}
+	want := ForecastRollup{TotalEffortHours: 18.5, OverallRisk: "medium", Confidence: "low", Tasks: 3, HighRisk: 1}
+	if *rollup != want {
+		t.Errorf("rollup = %+v, want %+v", *rollup, want)
+	}
+}
+
+func TestFormatForecastSummary(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		rollup *ForecastRollup
+		want   string
+	}{
+		"nil": {rollup: nil, want: ""},
+		"full": {
+			rollup: &ForecastRollup{TotalEffortHours: 18.5, OverallRisk: "medium", Confidence: "low", Tasks: 3, HighRisk: 2},
+			want:   "  forecast: 18.5h, medium risk (2 high-risk tasks), low confidence\n",
+		},
+		"one high-risk task": {
+			rollup: &ForecastRollup{TotalEffortHours: 6, OverallRisk: "high", Tasks: 1, HighRisk: 1},
+			want:   "  forecast: 6h, high risk (1 high-risk task)\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := FormatForecastSummary(tt.rollup); got != tt.want {
+				t.Errorf("FormatForecastSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
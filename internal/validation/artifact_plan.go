@@ -25,11 +25,7 @@ func (v *PlanValidator) Validate(path string) *ValidationResult {
 	// Parse the YAML file
 	root, err := parseYAMLFile(path)
 	if err != nil {
-		result.AddError(&ValidationError{
-			Path:    path,
-			Message: fmt.Sprintf("failed to parse YAML: %v", err),
-			Hint:    "Check the YAML syntax for errors",
-		})
+		result.AddError(parseError(path, err))
 		return result
 	}
 
@@ -37,6 +33,8 @@ func (v *PlanValidator) Validate(path string) *ValidationResult {
 	if rootMapping == nil {
 		result.AddError(&ValidationError{
 			Path:    path,
+			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
 			Message: "expected a YAML mapping at document root",
 			Hint:    "The plan.yaml file should start with key-value pairs, not a list or scalar",
 		})
@@ -44,9 +42,9 @@ func (v *PlanValidator) Validate(path string) *ValidationResult {
 	}
 
 	// Validate required fields
-	planNode := validateRequiredField(rootMapping, "plan", result)
-	summaryNode := validateRequiredField(rootMapping, "summary", result)
-	techContextNode := validateRequiredField(rootMapping, "technical_context", result)
+	planNode := validateRequiredField(rootMapping, "", "plan", result)
+	summaryNode := validateRequiredField(rootMapping, "", "summary", result)
+	techContextNode := validateRequiredField(rootMapping, "", "technical_context", result)
 
 	// Validate plan section
 	if planNode != nil {
@@ -59,6 +57,7 @@ func (v *PlanValidator) Validate(path string) *ValidationResult {
 			result.AddError(&ValidationError{
 				Path:     "summary",
 				Line:     getNodeLine(summaryNode),
+				Column:   getNodeColumn(summaryNode),
 				Message:  "wrong type for field 'summary'",
 				Expected: "string",
 				Actual:   nodeKindToString(summaryNode.Kind),
@@ -83,6 +82,8 @@ func (v *PlanValidator) Validate(path string) *ValidationResult {
 		v.validateRisks(risksNode, result)
 	}
 
+	checkStrictness(rootMapping, &PlanSchema, result)
+
 	// Build summary if valid
 	if result.Valid {
 		result.Summary = v.buildSummary(rootMapping)
@@ -98,8 +99,8 @@ func (v *PlanValidator) validatePlanSection(node *yaml.Node, result *ValidationR
 	}
 
 	// Required fields in plan
-	validateRequiredField(node, "branch", result)
-	validateRequiredField(node, "spec_path", result)
+	validateRequiredField(node, "plan", "branch", result)
+	validateRequiredField(node, "plan", "spec_path", result)
 }
 
 // validateTechnicalContext validates the technical_context section.
@@ -144,6 +145,7 @@ func (v *PlanValidator) validatePhase(node *yaml.Node, path string, result *Vali
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -157,6 +159,7 @@ func (v *PlanValidator) validatePhase(node *yaml.Node, path string, result *Vali
 		result.AddError(&ValidationError{
 			Path:    path + ".phase",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: phase",
 			Hint:    "Add a 'phase' field with the phase number",
 		})
@@ -167,6 +170,7 @@ func (v *PlanValidator) validatePhase(node *yaml.Node, path string, result *Vali
 		result.AddError(&ValidationError{
 			Path:    path + ".name",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: name",
 			Hint:    "Add a 'name' field with the phase name",
 		})
@@ -200,6 +204,7 @@ func (v *PlanValidator) validateRisk(node *yaml.Node, path string, result *Valid
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -214,6 +219,7 @@ func (v *PlanValidator) validateRisk(node *yaml.Node, path string, result *Valid
 			result.AddError(&ValidationError{
 				Path:     path + ".id",
 				Line:     getNodeLine(idNode),
+				Column:   getNodeColumn(idNode),
 				Message:  fmt.Sprintf("invalid risk ID format: '%s'", idNode.Value),
 				Expected: "RISK-NNN (e.g., RISK-001)",
 				Actual:   idNode.Value,
@@ -228,6 +234,7 @@ func (v *PlanValidator) validateRisk(node *yaml.Node, path string, result *Valid
 		result.AddError(&ValidationError{
 			Path:    path + ".risk",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: risk",
 			Hint:    "Add a 'risk' field describing the risk",
 		})
@@ -239,6 +246,7 @@ func (v *PlanValidator) validateRisk(node *yaml.Node, path string, result *Valid
 		result.AddError(&ValidationError{
 			Path:    path + ".likelihood",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: likelihood",
 			Hint:    "Add a 'likelihood' field with value: low, medium, or high",
 		})
@@ -252,6 +260,7 @@ func (v *PlanValidator) validateRisk(node *yaml.Node, path string, result *Valid
 		result.AddError(&ValidationError{
 			Path:    path + ".impact",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: impact",
 			Hint:    "Add an 'impact' field with value: low, medium, or high",
 		})
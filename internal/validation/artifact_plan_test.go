@@ -321,8 +321,9 @@ func TestPlanValidator_RiskWarnings(t *testing.T) {
 				}
 			}
 
-			if len(result.Warnings) != tt.expectWarningCt {
-				t.Errorf("Warning count = %d, want %d", len(result.Warnings), tt.expectWarningCt)
+			warnings := withoutStrictness(result)
+			if len(warnings) != tt.expectWarningCt {
+				t.Errorf("Warning count = %d, want %d", len(warnings), tt.expectWarningCt)
 				t.Logf("Warnings:")
 				for _, w := range result.Warnings {
 					t.Logf("  - %s", w.Message)
@@ -0,0 +1,134 @@
+package validation
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ResearchValidator validates research.yaml artifacts written by spikes.
+type ResearchValidator struct {
+	baseValidator
+}
+
+// Type returns the artifact type.
+func (v *ResearchValidator) Type() ArtifactType {
+	return ArtifactTypeResearch
+}
+
+// Validate validates a research.yaml file at the given path.
+func (v *ResearchValidator) Validate(path string) *ValidationResult {
+	result := &ValidationResult{Valid: true}
+
+	// Parse the YAML file
+	root, err := parseYAMLFile(path)
+	if err != nil {
+		result.AddError(parseError(path, err))
+		return result
+	}
+
+	rootMapping := getRootMapping(root)
+	if rootMapping == nil {
+		result.AddError(&ValidationError{
+			Path:    path,
+			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
+			Message: "expected a YAML mapping at document root",
+			Hint:    "The research.yaml file should start with key-value pairs, not a list or scalar",
+		})
+		return result
+	}
+
+	// Validate required fields
+	researchNode := validateRequiredField(rootMapping, "", "research", result)
+	findingsNode := validateRequiredField(rootMapping, "", "findings", result)
+	recommendationNode := validateRequiredField(rootMapping, "", "recommendation", result)
+
+	if researchNode != nil && validateFieldType(researchNode, "research", yaml.MappingNode, "object", result) {
+		validateRequiredField(researchNode, "research", "branch", result)
+		validateRequiredField(researchNode, "research", "created", result)
+		validateRequiredField(researchNode, "research", "question", result)
+	}
+
+	if findingsNode != nil {
+		v.validateFindings(findingsNode, result)
+	}
+
+	if recommendationNode != nil && validateFieldType(recommendationNode, "recommendation", yaml.MappingNode, "object", result) {
+		validateRequiredField(recommendationNode, "recommendation", "summary", result)
+	}
+
+	// Validate optional sections
+	if optionsNode := findNode(rootMapping, "options"); optionsNode != nil {
+		validateFieldType(optionsNode, "options", yaml.SequenceNode, "array", result)
+	}
+
+	checkStrictness(rootMapping, &ResearchSchema, result)
+
+	// Build summary if valid
+	if result.Valid {
+		result.Summary = v.buildSummary(rootMapping)
+	}
+
+	return result
+}
+
+// validateFindings validates the findings section, which needs at least one finding.
+func (v *ResearchValidator) validateFindings(node *yaml.Node, result *ValidationResult) {
+	if !validateFieldType(node, "findings", yaml.SequenceNode, "array", result) {
+		return
+	}
+	if len(node.Content) == 0 {
+		result.AddError(&ValidationError{
+			Path:    "findings",
+			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
+			Message: "findings must not be empty",
+			Hint:    "Record at least one finding; a spike that learned nothing should say so in a finding",
+		})
+		return
+	}
+
+	for i, findingNode := range node.Content {
+		path := fmt.Sprintf("findings[%d]", i)
+		if findingNode.Kind != yaml.MappingNode {
+			result.AddError(&ValidationError{
+				Path:     path,
+				Line:     getNodeLine(findingNode),
+				Column:   getNodeColumn(findingNode),
+				Message:  fmt.Sprintf("wrong type for '%s'", path),
+				Expected: "object",
+				Actual:   nodeKindToString(findingNode.Kind),
+			})
+			continue
+		}
+		for _, field := range []string{"id", "topic", "summary"} {
+			if findNode(findingNode, field) == nil {
+				result.AddError(&ValidationError{
+					Path:    fmt.Sprintf("%s.%s", path, field),
+					Line:    getNodeLine(findingNode),
+					Column:  getNodeColumn(findingNode),
+					Message: fmt.Sprintf("missing required field: %s", field),
+					Hint:    fmt.Sprintf("Add the '%s' field to this finding", field),
+				})
+			}
+		}
+	}
+}
+
+// buildSummary builds the summary for a valid research artifact.
+func (v *ResearchValidator) buildSummary(root *yaml.Node) *ArtifactSummary {
+	summary := &ArtifactSummary{
+		Type:   ArtifactTypeResearch,
+		Counts: make(map[string]int),
+	}
+
+	if findingsNode := findNode(root, "findings"); findingsNode != nil && findingsNode.Kind == yaml.SequenceNode {
+		summary.Counts["findings"] = len(findingsNode.Content)
+	}
+	if optionsNode := findNode(root, "options"); optionsNode != nil && optionsNode.Kind == yaml.SequenceNode {
+		summary.Counts["options"] = len(optionsNode.Content)
+	}
+
+	return summary
+}
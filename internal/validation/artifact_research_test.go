@@ -0,0 +1,83 @@
+// Package validation_test tests research.yaml (spike output) schema validation.
+// Related: internal/validation/artifact_research.go
+// Tags: validation, research, spike, schema, yaml, artifact
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResearchValidator_ValidFile(t *testing.T) {
+	t.Parallel()
+	result := (&ResearchValidator{}).Validate(filepath.Join("testdata", "research", "valid.yaml"))
+
+	if !result.Valid {
+		t.Fatalf("expected valid result, got errors: %v", result.Errors)
+	}
+	if result.Summary == nil || result.Summary.Type != ArtifactTypeResearch {
+		t.Fatalf("summary = %+v, want research summary", result.Summary)
+	}
+	if got := result.Summary.Counts["findings"]; got != 2 {
+		t.Errorf("summary.Counts[findings] = %d, want 2", got)
+	}
+	if got := result.Summary.Counts["options"]; got != 2 {
+		t.Errorf("summary.Counts[options] = %d, want 2", got)
+	}
+}
+
+func TestResearchValidator_Errors(t *testing.T) {
+	t.Parallel()
+
+	header := "research:\n  branch: \"012-cache\"\n  created: \"2025-01-15\"\n  question: \"Which cache?\"\n"
+	recommendation := "recommendation:\n  summary: \"Use Redis\"\n"
+
+	tests := map[string]struct {
+		content   string
+		wantError string
+	}{
+		"missing findings": {
+			content:   header + recommendation,
+			wantError: "missing required field: findings",
+		},
+		"empty findings": {
+			content:   header + "findings: []\n" + recommendation,
+			wantError: "findings must not be empty",
+		},
+		"finding without summary": {
+			content:   header + "findings:\n  - id: \"F-001\"\n    topic: \"Redis\"\n" + recommendation,
+			wantError: "findings[0].summary",
+		},
+		"missing question": {
+			content:   "research:\n  branch: \"012-cache\"\n  created: \"2025-01-15\"\nfindings:\n  - id: \"F-001\"\n    topic: \"Redis\"\n    summary: \"fast\"\n" + recommendation,
+			wantError: "missing required field: question",
+		},
+		"missing recommendation": {
+			content:   header + "findings:\n  - id: \"F-001\"\n    topic: \"Redis\"\n    summary: \"fast\"\n",
+			wantError: "missing required field: recommendation",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "research.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			result := (&ResearchValidator{}).Validate(path)
+			if result.Valid {
+				t.Fatal("expected validation to fail")
+			}
+			for _, err := range result.Errors {
+				if strings.Contains(err.Error(), tt.wantError) {
+					return
+				}
+			}
+			t.Errorf("expected error containing %q, got: %v", tt.wantError, result.Errors)
+		})
+	}
+}
@@ -23,11 +23,7 @@ func (v *SpecValidator) Validate(path string) *ValidationResult {
 	// Parse the YAML file
 	root, err := parseYAMLFile(path)
 	if err != nil {
-		result.AddError(&ValidationError{
-			Path:    path,
-			Message: fmt.Sprintf("failed to parse YAML: %v", err),
-			Hint:    "Check the YAML syntax for errors",
-		})
+		result.AddError(parseError(path, err))
 		return result
 	}
 
@@ -35,16 +31,30 @@ func (v *SpecValidator) Validate(path string) *ValidationResult {
 	if rootMapping == nil {
 		result.AddError(&ValidationError{
 			Path:    path,
+			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
 			Message: "expected a YAML mapping at document root",
 			Hint:    "The spec.yaml file should start with key-value pairs, not a list or scalar",
 		})
 		return result
 	}
 
-	// Validate required fields
-	featureNode := validateRequiredField(rootMapping, "feature", result)
-	userStoriesNode := validateRequiredField(rootMapping, "user_stories", result)
-	requirementsNode := validateRequiredField(rootMapping, "requirements", result)
+	// Validate required fields. Bugfix and refactor specs use a lighter
+	// schema: user_stories is optional, and a bugfix spec describes the bug.
+	specType := specTypeOf(rootMapping)
+	featureNode := validateRequiredField(rootMapping, "", "feature", result)
+	var userStoriesNode *yaml.Node
+	if specType == SpecTypeFeature {
+		userStoriesNode = validateRequiredField(rootMapping, "", "user_stories", result)
+	} else {
+		userStoriesNode = findNode(rootMapping, "user_stories")
+	}
+	if specType == SpecTypeBugfix {
+		if bugNode := validateRequiredField(rootMapping, "", "bug", result); bugNode != nil {
+			v.validateBug(bugNode, result)
+		}
+	}
+	requirementsNode := validateRequiredField(rootMapping, "", "requirements", result)
 
 	// Validate feature section
 	if featureNode != nil {
@@ -61,6 +71,8 @@ func (v *SpecValidator) Validate(path string) *ValidationResult {
 		v.validateRequirements(requirementsNode, result)
 	}
 
+	checkStrictness(rootMapping, &SpecSchema, result)
+
 	// Build summary if valid
 	if result.Valid {
 		result.Summary = v.buildSummary(rootMapping)
@@ -76,14 +88,53 @@ func (v *SpecValidator) validateFeature(node *yaml.Node, result *ValidationResul
 	}
 
 	// Required fields in feature
-	validateRequiredField(node, "branch", result)
-	validateRequiredField(node, "created", result)
+	validateRequiredField(node, "feature", "branch", result)
+	validateRequiredField(node, "feature", "created", result)
 
 	// Validate status enum if present
 	statusNode := findNode(node, "status")
 	if statusNode != nil {
 		validateEnumValue(statusNode, "feature.status", []string{"Draft", "Review", "Approved", "Completed"}, result)
 	}
+
+	// Validate type enum if present
+	if typeNode := findNode(node, "type"); typeNode != nil {
+		validateEnumValue(typeNode, "feature.type", SpecTypes, result)
+	}
+}
+
+// specTypeOf returns the spec's feature.type, or SpecTypeFeature when it is
+// missing or unknown (an unknown type is reported by validateFeature).
+func specTypeOf(root *yaml.Node) string {
+	featureNode := findNode(root, "feature")
+	if featureNode == nil || featureNode.Kind != yaml.MappingNode {
+		return SpecTypeFeature
+	}
+	typeNode := findNode(featureNode, "type")
+	if typeNode == nil {
+		return SpecTypeFeature
+	}
+	switch typeNode.Value {
+	case SpecTypeBugfix, SpecTypeRefactor:
+		return typeNode.Value
+	default:
+		return SpecTypeFeature
+	}
+}
+
+// validateBug validates the bug section of a bugfix spec.
+func (v *SpecValidator) validateBug(node *yaml.Node, result *ValidationResult) {
+	if !validateFieldType(node, "bug", yaml.MappingNode, "object", result) {
+		return
+	}
+
+	validateRequiredField(node, "bug", "summary", result)
+	validateRequiredField(node, "bug", "expected", result)
+	validateRequiredField(node, "bug", "actual", result)
+
+	if stepsNode := findNode(node, "steps_to_reproduce"); stepsNode != nil {
+		validateFieldType(stepsNode, "bug.steps_to_reproduce", yaml.SequenceNode, "array", result)
+	}
 }
 
 // validateUserStories validates the user_stories section.
@@ -104,6 +155,7 @@ func (v *SpecValidator) validateUserStory(node *yaml.Node, path string, result *
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -119,6 +171,7 @@ func (v *SpecValidator) validateUserStory(node *yaml.Node, path string, result *
 			result.AddError(&ValidationError{
 				Path:    fmt.Sprintf("%s.%s", path, field),
 				Line:    getNodeLine(node),
+				Column:  getNodeColumn(node),
 				Message: fmt.Sprintf("missing required field: %s", field),
 				Hint:    fmt.Sprintf("Add the '%s' field to this user story", field),
 			})
@@ -150,6 +203,7 @@ func (v *SpecValidator) validateRequirements(node *yaml.Node, result *Validation
 		result.AddError(&ValidationError{
 			Path:    "requirements.functional",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: functional",
 			Hint:    "Add a 'functional' field with a list of functional requirements",
 		})
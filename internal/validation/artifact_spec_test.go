@@ -4,6 +4,7 @@
 package validation
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -237,3 +238,77 @@ func TestNewArtifactValidator_Spec(t *testing.T) {
 		t.Errorf("validator.Type() = %q, want %q", validator.Type(), ArtifactTypeSpec)
 	}
 }
+
+func TestSpecValidator_SpecTypes(t *testing.T) {
+	t.Parallel()
+
+	feature := func(specType string) string {
+		return "feature:\n  branch: \"042-fix-login\"\n  created: \"2025-01-15\"\n  type: \"" + specType + "\"\n"
+	}
+	requirements := `requirements:
+  functional:
+    - id: "FR-001"
+      description: "MUST accept passwords with unicode characters"
+      testable: true
+`
+	bug := `bug:
+  summary: "Login fails for passwords with emoji"
+  steps_to_reproduce:
+    - "Register with password 'p@ss🔑'"
+    - "Log in"
+  expected: "Login succeeds"
+  actual: "401 Unauthorized"
+`
+
+	tests := map[string]struct {
+		content   string
+		wantError string // substring of an expected error; empty means valid
+	}{
+		"bugfix without user stories": {
+			content: feature("bugfix") + bug + requirements,
+		},
+		"bugfix requires bug section": {
+			content:   feature("bugfix") + requirements,
+			wantError: "missing required field: bug",
+		},
+		"bugfix bug needs expected and actual": {
+			content:   feature("bugfix") + "bug:\n  summary: \"Login fails\"\n" + requirements,
+			wantError: "missing required field: expected",
+		},
+		"refactor without user stories": {
+			content: feature("refactor") + requirements,
+		},
+		"feature still requires user stories": {
+			content:   feature("feature") + requirements,
+			wantError: "missing required field: user_stories",
+		},
+		"unknown type": {
+			content:   feature("chore") + requirements,
+			wantError: "feature.type",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "spec.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			result := (&SpecValidator{}).Validate(path)
+			if tt.wantError == "" {
+				if !result.Valid {
+					t.Errorf("expected valid spec, got errors: %v", result.Errors)
+				}
+				return
+			}
+			for _, err := range result.Errors {
+				if strings.Contains(err.Error(), tt.wantError) {
+					return
+				}
+			}
+			t.Errorf("expected error containing %q, got: %v", tt.wantError, result.Errors)
+		})
+	}
+}
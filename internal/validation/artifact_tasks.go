@@ -2,6 +2,7 @@ package validation
 
 import (
 	"fmt"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -26,11 +27,7 @@ func (v *TasksValidator) Validate(path string) *ValidationResult {
 	// Parse the YAML file
 	root, err := parseYAMLFile(path)
 	if err != nil {
-		result.AddError(&ValidationError{
-			Path:    path,
-			Message: fmt.Sprintf("failed to parse YAML: %v", err),
-			Hint:    "Check the YAML syntax for errors",
-		})
+		result.AddError(parseError(path, err))
 		return result
 	}
 
@@ -38,6 +35,8 @@ func (v *TasksValidator) Validate(path string) *ValidationResult {
 	if rootMapping == nil {
 		result.AddError(&ValidationError{
 			Path:    path,
+			Line:    getNodeLine(root),
+			Column:  getNodeColumn(root),
 			Message: "expected a YAML mapping at document root",
 			Hint:    "The tasks.yaml file should start with key-value pairs, not a list or scalar",
 		})
@@ -45,9 +44,9 @@ func (v *TasksValidator) Validate(path string) *ValidationResult {
 	}
 
 	// Validate required fields
-	tasksNode := validateRequiredField(rootMapping, "tasks", result)
-	summaryNode := validateRequiredField(rootMapping, "summary", result)
-	phasesNode := validateRequiredField(rootMapping, "phases", result)
+	tasksNode := validateRequiredField(rootMapping, "", "tasks", result)
+	summaryNode := validateRequiredField(rootMapping, "", "summary", result)
+	phasesNode := validateRequiredField(rootMapping, "", "phases", result)
 
 	// Validate tasks section
 	if tasksNode != nil {
@@ -73,6 +72,8 @@ func (v *TasksValidator) Validate(path string) *ValidationResult {
 		v.validateAllDependencies(phasesNode, taskIDs, taskLines, result)
 	}
 
+	checkStrictness(rootMapping, &TasksSchema, result)
+
 	// Build summary if valid
 	if result.Valid {
 		result.Summary = v.buildSummary(rootMapping, taskIDs)
@@ -88,7 +89,7 @@ func (v *TasksValidator) validateTasksSection(node *yaml.Node, result *Validatio
 	}
 
 	// Required field: branch
-	validateRequiredField(node, "branch", result)
+	validateRequiredField(node, "tasks", "branch", result)
 }
 
 // validateSummarySection validates the summary section.
@@ -117,6 +118,7 @@ func (v *TasksValidator) validatePhase(node *yaml.Node, path string, result *Val
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -125,8 +127,8 @@ func (v *TasksValidator) validatePhase(node *yaml.Node, path string, result *Val
 	}
 
 	// Required fields: number, title, tasks
-	validateRequiredField(node, "number", result)
-	validateRequiredField(node, "title", result)
+	validateRequiredField(node, path, "number", result)
+	validateRequiredField(node, path, "title", result)
 
 	// Validate tasks array
 	tasksNode := findNode(node, "tasks")
@@ -134,6 +136,7 @@ func (v *TasksValidator) validatePhase(node *yaml.Node, path string, result *Val
 		result.AddError(&ValidationError{
 			Path:    path + ".tasks",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: tasks",
 			Hint:    "Add a 'tasks' field with a list of tasks",
 		})
@@ -156,6 +159,7 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 		result.AddError(&ValidationError{
 			Path:     path,
 			Line:     getNodeLine(node),
+			Column:   getNodeColumn(node),
 			Message:  fmt.Sprintf("wrong type for '%s'", path),
 			Expected: "object",
 			Actual:   nodeKindToString(node.Kind),
@@ -169,6 +173,7 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 		result.AddError(&ValidationError{
 			Path:    path + ".id",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: id",
 			Hint:    "Add an 'id' field with format 'TNNN' (e.g., 'T001')",
 		})
@@ -179,6 +184,7 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 			result.AddError(&ValidationError{
 				Path:    path + ".id",
 				Line:    getNodeLine(idNode),
+				Column:  getNodeColumn(idNode),
 				Message: fmt.Sprintf("duplicate task ID: %s (first defined at line %d)", taskID, existingLine),
 				Hint:    "Each task must have a unique ID",
 			})
@@ -188,7 +194,7 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 		}
 	}
 
-	validateRequiredField(node, "title", result)
+	validateRequiredField(node, path, "title", result)
 
 	// Validate status enum
 	statusNode := findNode(node, "status")
@@ -196,6 +202,7 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 		result.AddError(&ValidationError{
 			Path:    path + ".status",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: status",
 			Hint:    "Add a 'status' field with one of: Pending, InProgress, Completed, Blocked",
 		})
@@ -209,6 +216,7 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 		result.AddError(&ValidationError{
 			Path:    path + ".type",
 			Line:    getNodeLine(node),
+			Column:  getNodeColumn(node),
 			Message: "missing required field: type",
 			Hint:    "Add a 'type' field with one of: setup, implementation, test, documentation, refactor",
 		})
@@ -231,6 +239,26 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 	// Validate blocked_reason for blocked tasks
 	v.validateBlockedReason(node, path, statusNode, result)
 
+	// Validate manual status_history entries if present
+	v.validateStatusHistory(node, path, result)
+
+	// Time limit, estimate and recorded time should be positive Go durations if present
+	for _, field := range []string{"max_duration", "estimated_duration", "actual_duration"} {
+		durNode := findNode(node, field)
+		if durNode == nil {
+			continue
+		}
+		if d, err := time.ParseDuration(durNode.Value); durNode.Kind != yaml.ScalarNode || err != nil || d <= 0 {
+			result.AddError(&ValidationError{
+				Path:    path + "." + field,
+				Line:    getNodeLine(durNode),
+				Column:  getNodeColumn(durNode),
+				Message: fmt.Sprintf("invalid %s: %q", field, durNode.Value),
+				Hint:    "Use a positive duration such as '30m' or '1h30m'",
+			})
+		}
+	}
+
 	// notes should be a string with max length if present
 	notesNode := findNode(node, "notes")
 	if notesNode != nil {
@@ -238,6 +266,7 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 			result.AddError(&ValidationError{
 				Path:     path + ".notes",
 				Line:     getNodeLine(notesNode),
+				Column:   getNodeColumn(notesNode),
 				Message:  fmt.Sprintf("wrong type for '%s.notes'", path),
 				Expected: "string",
 				Actual:   nodeKindToString(notesNode.Kind),
@@ -246,6 +275,7 @@ func (v *TasksValidator) validateTask(node *yaml.Node, path string, result *Vali
 			result.AddError(&ValidationError{
 				Path:    path + ".notes",
 				Line:    getNodeLine(notesNode),
+				Column:  getNodeColumn(notesNode),
 				Message: fmt.Sprintf("notes too long: %d characters (max %d)", len(notesNode.Value), MaxTaskNotesLength),
 				Hint:    "Shorten the notes to be more concise",
 			})
@@ -270,6 +300,67 @@ func (v *TasksValidator) validateBlockedReason(node *yaml.Node, path string, sta
 	}
 }
 
+// validateStatusHistory validates the optional status_history block written by
+// `autospec task set-status`. Each entry must be an object with a valid 'to' status
+// and, for manual overrides, a non-empty reason.
+func (v *TasksValidator) validateStatusHistory(node *yaml.Node, path string, result *ValidationResult) {
+	historyNode := findNode(node, "status_history")
+	if historyNode == nil {
+		return
+	}
+	if !validateFieldType(historyNode, path+".status_history", yaml.SequenceNode, "array", result) {
+		return
+	}
+
+	for i, entry := range historyNode.Content {
+		entryPath := fmt.Sprintf("%s.status_history[%d]", path, i)
+		if !validateFieldType(entry, entryPath, yaml.MappingNode, "object", result) {
+			continue
+		}
+		toNode := findNode(entry, "to")
+		if toNode == nil {
+			result.AddError(&ValidationError{
+				Path:    entryPath + ".to",
+				Line:    getNodeLine(entry),
+				Column:  getNodeColumn(entry),
+				Message: "missing required field: to",
+				Hint:    "Each status_history entry must record the status it changed to",
+			})
+		} else {
+			validateEnumValue(toNode, entryPath+".to", []string{"Pending", "InProgress", "Completed", "Blocked"}, result)
+		}
+		if isManualHistoryEntry(entry) {
+			if reasonNode := findNode(entry, "reason"); reasonNode == nil || reasonNode.Value == "" {
+				result.AddWarning(&ValidationWarning{
+					Path:    entryPath + ".reason",
+					Line:    getNodeLine(entry),
+					Message: "manual status change is missing a reason",
+					Hint:    "Use 'autospec task set-status <id> <status> --reason \"...\"' to record why",
+				})
+			}
+		}
+	}
+}
+
+// isManualHistoryEntry reports whether a status_history entry is flagged manual: true.
+func isManualHistoryEntry(entry *yaml.Node) bool {
+	manualNode := findNode(entry, "manual")
+	return manualNode != nil && manualNode.Value == "true"
+}
+
+// isManualOverrideNode reports whether a task's current status comes from a manual
+// override: the last status_history entry is manual and matches the current status.
+func isManualOverrideNode(taskNode *yaml.Node) bool {
+	statusNode := findNode(taskNode, "status")
+	historyNode := findNode(taskNode, "status_history")
+	if statusNode == nil || historyNode == nil || historyNode.Kind != yaml.SequenceNode || len(historyNode.Content) == 0 {
+		return false
+	}
+	last := historyNode.Content[len(historyNode.Content)-1]
+	toNode := findNode(last, "to")
+	return isManualHistoryEntry(last) && toNode != nil && toNode.Value == statusNode.Value
+}
+
 // validateAllDependencies validates all task dependencies after collecting task IDs.
 // Performs triple-nested traversal: phases[i] → tasks[j] → dependencies[k]
 //
@@ -325,6 +416,7 @@ func (v *TasksValidator) validateAllDependencies(phasesNode *yaml.Node, taskIDs
 					result.AddError(&ValidationError{
 						Path:    fmt.Sprintf("%s.dependencies[%d]", taskPath, k),
 						Line:    getNodeLine(depNode),
+						Column:  getNodeColumn(depNode),
 						Message: fmt.Sprintf("task '%s' cannot depend on itself", taskID),
 						Hint:    "Remove the self-reference from the dependencies list",
 					})
@@ -336,6 +428,7 @@ func (v *TasksValidator) validateAllDependencies(phasesNode *yaml.Node, taskIDs
 					result.AddError(&ValidationError{
 						Path:    fmt.Sprintf("%s.dependencies[%d]", taskPath, k),
 						Line:    getNodeLine(depNode),
+						Column:  getNodeColumn(depNode),
 						Message: fmt.Sprintf("invalid dependency: task '%s' depends on '%s' which does not exist", taskID, depID),
 						Hint:    fmt.Sprintf("Either create a task with ID '%s' or remove this dependency", depID),
 					})
@@ -472,6 +565,7 @@ func (v *TasksValidator) buildSummary(root *yaml.Node, taskIDs map[string]int) *
 	summary.Counts["completed"] = completed
 	summary.Counts["blocked"] = blocked
 	summary.Counts["blocked_without_reason"] = countBlockedWithoutReason(phasesNode)
+	summary.Counts["manual_overrides"] = countManualOverrides(phasesNode)
 
 	return summary
 }
@@ -501,3 +595,24 @@ func countBlockedWithoutReason(phasesNode *yaml.Node) int {
 	}
 	return count
 }
+
+// countManualOverrides counts tasks whose current status was set manually.
+func countManualOverrides(phasesNode *yaml.Node) int {
+	if phasesNode == nil || phasesNode.Kind != yaml.SequenceNode {
+		return 0
+	}
+
+	count := 0
+	for _, phaseNode := range phasesNode.Content {
+		tasksNode := findNode(phaseNode, "tasks")
+		if tasksNode == nil || tasksNode.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, taskNode := range tasksNode.Content {
+			if isManualOverrideNode(taskNode) {
+				count++
+			}
+		}
+	}
+	return count
+}
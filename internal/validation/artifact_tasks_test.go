@@ -375,8 +375,9 @@ func TestTasksValidator_BlockedReasonValidation(t *testing.T) {
 				}
 			}
 
-			if len(result.Warnings) != tt.wantWarnings {
-				t.Errorf("got %d warnings, want %d", len(result.Warnings), tt.wantWarnings)
+			warnings := withoutStrictness(result)
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("got %d warnings, want %d", len(warnings), tt.wantWarnings)
 				for _, w := range result.Warnings {
 					t.Logf("  Warning: %s", w.Message)
 				}
@@ -441,3 +442,100 @@ func TestTasksValidator_BlockedWithoutReasonCount(t *testing.T) {
 		})
 	}
 }
+
+func TestTasksValidator_DurationFields(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		filename  string
+		wantValid bool
+		wantField string
+	}{
+		"valid max_duration": {
+			filename:  "max_duration_valid.yaml",
+			wantValid: true,
+		},
+		"invalid max_duration fails": {
+			filename:  "max_duration_invalid.yaml",
+			wantValid: false,
+			wantField: "max_duration",
+		},
+		"valid estimated and actual durations": {
+			filename:  "durations_valid.yaml",
+			wantValid: true,
+		},
+		"invalid estimated_duration fails": {
+			filename:  "durations_invalid.yaml",
+			wantValid: false,
+			wantField: "estimated_duration",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			validator := &TasksValidator{}
+			result := validator.Validate(filepath.Join("testdata", "tasks", tt.filename))
+
+			if result.Valid != tt.wantValid {
+				t.Fatalf("Valid = %v, want %v (errors: %v)", result.Valid, tt.wantValid, result.Errors)
+			}
+			if !tt.wantValid && !strings.Contains(result.Errors[0].Path, tt.wantField) {
+				t.Errorf("error path = %q, want %s", result.Errors[0].Path, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestTasksValidator_StatusHistory(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		filename            string
+		wantValid           bool
+		wantWarnings        int
+		wantManualOverrides int
+	}{
+		"manual status history is valid and counted": {
+			filename:            "manual_status_history.yaml",
+			wantValid:           true,
+			wantWarnings:        0,
+			wantManualOverrides: 1,
+		},
+		"invalid status history entry fails": {
+			filename:     "invalid_status_history.yaml",
+			wantValid:    false,
+			wantWarnings: 1,
+		},
+		"no status history means no manual overrides": {
+			filename:            "blocked_with_reason.yaml",
+			wantValid:           true,
+			wantWarnings:        0,
+			wantManualOverrides: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			validator := &TasksValidator{}
+			result := validator.Validate(filepath.Join("testdata", "tasks", tt.filename))
+
+			if result.Valid != tt.wantValid {
+				t.Fatalf("Valid = %v, want %v (errors: %v)", result.Valid, tt.wantValid, result.Errors)
+			}
+			warnings := withoutStrictness(result)
+			if len(warnings) != tt.wantWarnings {
+				t.Errorf("got %d warnings, want %d", len(warnings), tt.wantWarnings)
+			}
+			if !tt.wantValid {
+				return
+			}
+			if got := result.Summary.Counts["manual_overrides"]; got != tt.wantManualOverrides {
+				t.Errorf("manual_overrides = %d, want %d", got, tt.wantManualOverrides)
+			}
+		})
+	}
+}
@@ -4,6 +4,9 @@
 package validation
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -229,6 +232,10 @@ func TestNewArtifactValidator(t *testing.T) {
 			artifactType: ArtifactTypeConstitution,
 			wantErr:      false,
 		},
+		"research validator": {
+			artifactType: ArtifactTypeResearch,
+			wantErr:      false,
+		},
 		"unknown type": {
 			artifactType: ArtifactType("unknown"),
 			wantErr:      true,
@@ -553,3 +560,115 @@ user_stories:
 		})
 	}
 }
+
+func TestParseErrorLocation(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		err        error
+		wantLine   int
+		wantColumn int
+	}{
+		"line only": {
+			err:      errors.New("yaml: line 4: did not find expected key"),
+			wantLine: 4,
+		},
+		"line and column": {
+			err:        errors.New("yaml: line 7, column 3: mapping values are not allowed in this context"),
+			wantLine:   7,
+			wantColumn: 3,
+		},
+		"no location": {
+			err: errors.New("EOF"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			line, column := parseErrorLocation(tc.err)
+			if line != tc.wantLine || column != tc.wantColumn {
+				t.Errorf("parseErrorLocation() = %d:%d, want %d:%d", line, column, tc.wantLine, tc.wantColumn)
+			}
+		})
+	}
+}
+
+func TestValidate_ErrorLocations(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content    string
+		wantPath   string
+		wantLine   int
+		wantColumn int
+	}{
+		"missing nested field reported at its mapping": {
+			content: `feature:
+  created: "2025-01-01"
+  status: Draft
+  input: "test"
+user_stories: []
+requirements:
+  functional: []
+_meta:
+  version: "1.0.0"
+  artifact_type: spec
+`,
+			wantPath:   "feature.branch",
+			wantLine:   2,
+			wantColumn: 3,
+		},
+		"wrong type reported at the value": {
+			content: `feature:
+  branch: "001-test"
+  created: "2025-01-01"
+  status: Draft
+  input: "test"
+user_stories: "none"
+requirements:
+  functional: []
+_meta:
+  version: "1.0.0"
+  artifact_type: spec
+`,
+			wantPath:   "user_stories",
+			wantLine:   6,
+			wantColumn: 15,
+		},
+		"syntax error reported at the parser location": {
+			content: `feature:
+  branch: "001-test"
+  created: a: b
+`,
+			wantLine: 3,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "spec.yaml")
+			if err := os.WriteFile(path, []byte(tc.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			result := (&SpecValidator{}).Validate(path)
+
+			for _, verr := range result.Errors {
+				if tc.wantPath != "" && verr.Path != tc.wantPath {
+					continue
+				}
+				if verr.Line != tc.wantLine {
+					t.Errorf("line = %d, want %d (%s)", verr.Line, tc.wantLine, verr.Error())
+				}
+				if tc.wantColumn > 0 && verr.Column != tc.wantColumn {
+					t.Errorf("column = %d, want %d (%s)", verr.Column, tc.wantColumn, verr.Error())
+				}
+				return
+			}
+			t.Errorf("no error with path %q in %v", tc.wantPath, result.Errors)
+		})
+	}
+}
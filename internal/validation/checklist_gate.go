@@ -0,0 +1,114 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChecklistsDirName is the directory inside a spec holding checklist YAML files.
+const ChecklistsDirName = "checklists"
+
+// OpenChecklistItem is a checklist item that is neither passed nor waived.
+type OpenChecklistItem struct {
+	File        string // checklist file name (e.g., "security.yaml")
+	ID          string
+	Description string
+	Status      string
+}
+
+// ChecklistGateError reports checklist items blocking implementation.
+type ChecklistGateError struct {
+	SpecDir string
+	Items   []OpenChecklistItem
+}
+
+func (e *ChecklistGateError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "checklist gate: %d open item(s) in %s:\n",
+		len(e.Items), filepath.Join(e.SpecDir, ChecklistsDirName))
+	for _, item := range e.Items {
+		fmt.Fprintf(&sb, "  - %s %s [%s] %s\n", item.File, item.ID, item.Status, item.Description)
+	}
+	sb.WriteString("\nMark each item 'pass', or 'waived' with notes explaining why, then rerun implement")
+	return sb.String()
+}
+
+// ChecklistFiles returns the checklist YAML files in specDir/checklists, sorted by name.
+// Returns an empty slice when the directory does not exist.
+func ChecklistFiles(specDir string) ([]string, error) {
+	dir := filepath.Join(specDir, ChecklistsDirName)
+	var files []string
+	for _, pattern := range []string{"*.yaml", "*.yml"} {
+		matches, err := filepath.Glob(filepath.Join(dir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("listing checklists: %w", err)
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// checklistItemsFile is the subset of a checklist file needed for gating.
+type checklistItemsFile struct {
+	Categories []struct {
+		Items []struct {
+			ID          string `yaml:"id"`
+			Description string `yaml:"description"`
+			Status      string `yaml:"status"`
+		} `yaml:"items"`
+	} `yaml:"categories"`
+}
+
+// FindOpenChecklistItems returns all checklist items in specDir whose status
+// is not pass or waived, in file order.
+func FindOpenChecklistItems(specDir string) ([]OpenChecklistItem, error) {
+	files, err := ChecklistFiles(specDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var open []OpenChecklistItem
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading checklist: %w", err)
+		}
+		var checklist checklistItemsFile
+		if err := yaml.Unmarshal(data, &checklist); err != nil {
+			return nil, fmt.Errorf("parsing checklist %s: %w", path, err)
+		}
+		for _, category := range checklist.Categories {
+			for _, item := range category.Items {
+				if item.Status == ChecklistStatusPass || item.Status == ChecklistStatusWaived {
+					continue
+				}
+				open = append(open, OpenChecklistItem{
+					File:        filepath.Base(path),
+					ID:          item.ID,
+					Description: item.Description,
+					Status:      item.Status,
+				})
+			}
+		}
+	}
+	return open, nil
+}
+
+// CheckChecklistGate returns a *ChecklistGateError when any checklist item in
+// specDir is still open. A spec without checklists passes the gate.
+func CheckChecklistGate(specDir string) error {
+	open, err := FindOpenChecklistItems(specDir)
+	if err != nil {
+		return err
+	}
+	if len(open) > 0 {
+		return &ChecklistGateError{SpecDir: specDir, Items: open}
+	}
+	return nil
+}
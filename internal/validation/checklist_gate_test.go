@@ -0,0 +1,137 @@
+package validation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const securityChecklist = `checklist:
+  feature: "Auth"
+  branch: "001-auth"
+  domain: "security"
+categories:
+  - name: "Completeness"
+    items:
+      - id: "CHK001"
+        description: "Are auth requirements specified?"
+        status: "pass"
+      - id: "CHK002"
+        description: "Are rate limits defined?"
+        status: "pending"
+      - id: "CHK003"
+        description: "Is token expiry defined?"
+        status: "waived"
+        notes: "Handled by identity provider"
+`
+
+const uxChecklist = `checklist:
+  feature: "Auth"
+  branch: "001-auth"
+  domain: "ux"
+categories:
+  - name: "Clarity"
+    items:
+      - id: "CHK001"
+        description: "Is the login error copy specified?"
+        status: "fail"
+`
+
+func writeChecklists(t *testing.T, files map[string]string) string {
+	t.Helper()
+	specDir := t.TempDir()
+	if len(files) == 0 {
+		return specDir
+	}
+	dir := filepath.Join(specDir, ChecklistsDirName)
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+	}
+	return specDir
+}
+
+func TestFindOpenChecklistItems(t *testing.T) {
+	t.Parallel()
+
+	specDir := writeChecklists(t, map[string]string{
+		"security.yaml": securityChecklist,
+		"ux.yml":        uxChecklist,
+		"notes.txt":     "ignored",
+	})
+
+	open, err := FindOpenChecklistItems(specDir)
+	require.NoError(t, err)
+	assert.Equal(t, []OpenChecklistItem{
+		{File: "security.yaml", ID: "CHK002", Description: "Are rate limits defined?", Status: "pending"},
+		{File: "ux.yml", ID: "CHK001", Description: "Is the login error copy specified?", Status: "fail"},
+	}, open)
+}
+
+func TestCheckChecklistGate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		files     map[string]string
+		wantItems int
+		wantErrIn string
+	}{
+		"no checklists directory passes": {},
+		"all passed or waived passes": {
+			files: map[string]string{"security.yaml": `categories:
+  - name: "x"
+    items:
+      - {id: "CHK001", description: "a", status: "pass"}
+      - {id: "CHK002", description: "b", status: "waived", notes: "n/a"}
+`},
+		},
+		"open items block": {
+			files:     map[string]string{"security.yaml": securityChecklist, "ux.yaml": uxChecklist},
+			wantItems: 2,
+		},
+		"malformed checklist errors": {
+			files:     map[string]string{"bad.yaml": "categories: [unclosed"},
+			wantErrIn: "parsing checklist",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			specDir := writeChecklists(t, tt.files)
+
+			err := CheckChecklistGate(specDir)
+			switch {
+			case tt.wantErrIn != "":
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrIn)
+			case tt.wantItems > 0:
+				var gateErr *ChecklistGateError
+				require.True(t, errors.As(err, &gateErr), "want *ChecklistGateError, got %v", err)
+				assert.Len(t, gateErr.Items, tt.wantItems)
+			default:
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChecklistGateError_Error(t *testing.T) {
+	t.Parallel()
+
+	err := &ChecklistGateError{
+		SpecDir: "specs/001-auth",
+		Items: []OpenChecklistItem{
+			{File: "security.yaml", ID: "CHK002", Description: "Are rate limits defined?", Status: "pending"},
+		},
+	}
+
+	msg := err.Error()
+	assert.Contains(t, msg, "1 open item(s) in specs/001-auth/checklists")
+	assert.Contains(t, msg, "security.yaml CHK002 [pending] Are rate limits defined?")
+	assert.Contains(t, msg, "waived")
+}
@@ -0,0 +1,100 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ForecastRollup summarizes a forecast.yaml for status and report output.
+type ForecastRollup struct {
+	TotalEffortHours float64 // summary.total_effort_hours
+	OverallRisk      string  // summary.overall_risk
+	Confidence       string  // summary.confidence, empty if not given
+	Tasks            int     // Number of estimated tasks
+	HighRisk         int     // Estimates with risk "high"
+}
+
+// forecastYAML represents the partial structure of a forecast.yaml file.
+type forecastYAML struct {
+	Summary struct {
+		TotalEffortHours float64 `yaml:"total_effort_hours"`
+		OverallRisk      string  `yaml:"overall_risk"`
+		Confidence       string  `yaml:"confidence"`
+	} `yaml:"summary"`
+	Estimates []struct {
+		TaskID string `yaml:"task_id"`
+		Risk   string `yaml:"risk"`
+	} `yaml:"estimates"`
+}
+
+// GetForecastFilePath returns the path to forecast.yaml in the spec directory.
+func GetForecastFilePath(specDir string) string {
+	return filepath.Join(specDir, "forecast.yaml")
+}
+
+// GetForecastRollup reads forecast.yaml from the spec directory and returns
+// its rollup. Returns nil and no error if the file doesn't exist.
+func GetForecastRollup(specDir string) (*ForecastRollup, error) {
+	path := GetForecastFilePath(specDir)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading forecast.yaml: %w", err)
+	}
+
+	var forecast forecastYAML
+	if err := yaml.Unmarshal(data, &forecast); err != nil {
+		return nil, fmt.Errorf("parsing forecast.yaml: %w", err)
+	}
+
+	rollup := &ForecastRollup{
+		TotalEffortHours: forecast.Summary.TotalEffortHours,
+		OverallRisk:      strings.ToLower(forecast.Summary.OverallRisk),
+		Confidence:       strings.ToLower(forecast.Summary.Confidence),
+		Tasks:            len(forecast.Estimates),
+	}
+	for _, estimate := range forecast.Estimates {
+		if strings.EqualFold(estimate.Risk, "high") {
+			rollup.HighRisk++
+		}
+	}
+
+	return rollup, nil
+}
+
+// String formats the rollup on one line, e.g.
+// "18.5h, medium risk (2 high-risk tasks), low confidence".
+func (r *ForecastRollup) String() string {
+	var sb strings.Builder
+	sb.WriteString(strconv.FormatFloat(r.TotalEffortHours, 'f', -1, 64) + "h")
+	if r.OverallRisk != "" {
+		sb.WriteString(fmt.Sprintf(", %s risk", r.OverallRisk))
+	}
+	switch r.HighRisk {
+	case 0:
+	case 1:
+		sb.WriteString(" (1 high-risk task)")
+	default:
+		sb.WriteString(fmt.Sprintf(" (%d high-risk tasks)", r.HighRisk))
+	}
+	if r.Confidence != "" {
+		sb.WriteString(fmt.Sprintf(", %s confidence", r.Confidence))
+	}
+	return sb.String()
+}
+
+// FormatForecastSummary returns a formatted string for displaying a forecast rollup.
+func FormatForecastSummary(rollup *ForecastRollup) string {
+	if rollup == nil {
+		return ""
+	}
+	return fmt.Sprintf("  forecast: %s\n", rollup.String())
+}
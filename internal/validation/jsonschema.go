@@ -0,0 +1,130 @@
+package validation
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the generated schemas; it is
+// the newest one yaml-language-server fully supports.
+const jsonSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema is a JSON Schema document or subschema, with the keywords the
+// artifact schemas need.
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        any                    `json:"type,omitempty"` // a type name or a list of them
+	Pattern     string                 `json:"pattern,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	AllOf       []*JSONSchema          `json:"allOf,omitempty"`
+	If          *JSONSchema            `json:"if,omitempty"`
+	Then        *JSONSchema            `json:"then,omitempty"`
+	Else        *JSONSchema            `json:"else,omitempty"`
+}
+
+// ArtifactJSONSchema converts an artifact schema to JSON Schema, for editors
+// that validate YAML with yaml-language-server. It accepts what the artifact
+// validators accept: unknown fields are allowed, and string fields take any
+// scalar, since validators only check that they are not lists or mappings.
+func ArtifactJSONSchema(artifactType ArtifactType) (*JSONSchema, error) {
+	schema, err := GetSchema(artifactType)
+	if err != nil {
+		return nil, err
+	}
+	doc := objectJSONSchema(schema.Fields)
+	doc.Schema = jsonSchemaDraft
+	doc.Title = fmt.Sprintf("autospec %s.yaml", artifactType)
+	doc.Description = schema.Description
+	if artifactType == ArtifactTypeSpec {
+		applySpecTypeRules(doc)
+	}
+	return doc, nil
+}
+
+// objectJSONSchema returns the schema of a mapping with the given fields.
+func objectJSONSchema(fields []SchemaField) *JSONSchema {
+	s := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema, len(fields))}
+	for _, f := range fields {
+		s.Properties[f.Name] = fieldJSONSchema(f)
+		if f.Required {
+			s.Required = append(s.Required, f.Name)
+		}
+	}
+	return s
+}
+
+// fieldJSONSchema returns the schema of one field.
+func fieldJSONSchema(f SchemaField) *JSONSchema {
+	var s *JSONSchema
+	switch f.Type {
+	case FieldTypeObject:
+		s = objectJSONSchema(f.Children)
+	case FieldTypeArray:
+		s = &JSONSchema{Type: "array"}
+		if len(f.Children) > 0 {
+			s.Items = objectJSONSchema(f.Children)
+		}
+	case FieldTypeInt:
+		s = &JSONSchema{Type: "integer"}
+	case FieldTypeNumber:
+		s = &JSONSchema{Type: "number"}
+	case FieldTypeBool:
+		s = &JSONSchema{Type: "boolean"}
+	default:
+		s = &JSONSchema{Type: []string{"string", "number", "boolean"}}
+		if f.Pattern != "" || len(f.Enum) > 0 {
+			s.Type = "string"
+		}
+	}
+	s.Description = f.Description
+	s.Pattern = f.Pattern
+	s.Enum = f.Enum
+	return s
+}
+
+// applySpecTypeRules mirrors the spec validator's rules for feature.type:
+// user_stories is required only in feature specs, and bugfix specs require
+// a bug section.
+func applySpecTypeRules(doc *JSONSchema) {
+	required := doc.Required[:0]
+	for _, name := range doc.Required {
+		if name != "user_stories" {
+			required = append(required, name)
+		}
+	}
+	doc.Required = required
+
+	specType := func(types ...string) *JSONSchema {
+		return &JSONSchema{
+			Properties: map[string]*JSONSchema{"feature": {
+				Properties: map[string]*JSONSchema{"type": {Enum: types}},
+				Required:   []string{"type"},
+			}},
+			Required: []string{"feature"},
+		}
+	}
+	doc.AllOf = []*JSONSchema{
+		{If: specType(SpecTypeBugfix, SpecTypeRefactor), Else: &JSONSchema{Required: []string{"user_stories"}}},
+		{If: specType(SpecTypeBugfix), Then: &JSONSchema{Required: []string{"bug"}}},
+	}
+}
+
+// ArtifactFileGlobs returns glob patterns, relative to the project root, for
+// the files of an artifact type in a project whose specs live in specsDir.
+func ArtifactFileGlobs(artifactType ArtifactType, specsDir string) []string {
+	specsDir = path.Clean(filepath.ToSlash(specsDir))
+	switch artifactType {
+	case ArtifactTypeConstitution:
+		return []string{".autospec/memory/constitution.yaml", ".autospec/memory/constitution.yml"}
+	case ArtifactTypeChecklist:
+		return []string{path.Join(specsDir, "*", ChecklistsDirName, "*.yaml"), path.Join(specsDir, "*", ChecklistsDirName, "*.yml")}
+	default:
+		return []string{path.Join(specsDir, "*", string(artifactType)+".yaml"), path.Join(specsDir, "*", string(artifactType)+".yml")}
+	}
+}
@@ -0,0 +1,97 @@
+// Package validation_test tests JSON Schema export of artifact schemas.
+// Related: internal/validation/jsonschema.go
+// Tags: validation, schema, json-schema, editor, yaml-language-server
+package validation
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactJSONSchema_AllTypes(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range ValidArtifactTypes() {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			doc, err := ArtifactJSONSchema(ArtifactType(name))
+			require.NoError(t, err)
+			assert.Equal(t, jsonSchemaDraft, doc.Schema)
+			assert.Equal(t, "object", doc.Type)
+			assert.NotEmpty(t, doc.Properties)
+
+			_, err = json.Marshal(doc)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestArtifactJSONSchema_Spec(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ArtifactJSONSchema(ArtifactTypeSpec)
+	require.NoError(t, err)
+
+	assert.Contains(t, doc.Required, "feature")
+	assert.NotContains(t, doc.Required, "user_stories", "feature.type decides whether user stories are required")
+	require.Len(t, doc.AllOf, 2)
+	assert.Equal(t, []string{"user_stories"}, doc.AllOf[0].Else.Required)
+	assert.Equal(t, []string{"bug"}, doc.AllOf[1].Then.Required)
+
+	stories := doc.Properties["user_stories"]
+	require.NotNil(t, stories)
+	assert.Equal(t, "array", stories.Type)
+	id := stories.Items.Properties["id"]
+	require.NotNil(t, id)
+	assert.Equal(t, "string", id.Type)
+	assert.Equal(t, `^US-\d+$`, id.Pattern)
+	assert.Contains(t, stories.Items.Required, "id")
+}
+
+func TestArtifactJSONSchema_InvalidType(t *testing.T) {
+	t.Parallel()
+
+	_, err := ArtifactJSONSchema(ArtifactType("bogus"))
+	assert.Error(t, err)
+}
+
+func TestArtifactFileGlobs(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		artifactType ArtifactType
+		specsDir     string
+		want         []string
+	}{
+		"spec": {
+			artifactType: ArtifactTypeSpec,
+			specsDir:     "specs",
+			want:         []string{"specs/*/spec.yaml", "specs/*/spec.yml"},
+		},
+		"custom specs dir": {
+			artifactType: ArtifactTypeTasks,
+			specsDir:     "./docs/specs/",
+			want:         []string{"docs/specs/*/tasks.yaml", "docs/specs/*/tasks.yml"},
+		},
+		"checklist": {
+			artifactType: ArtifactTypeChecklist,
+			specsDir:     "specs",
+			want:         []string{"specs/*/checklists/*.yaml", "specs/*/checklists/*.yml"},
+		},
+		"constitution ignores specs dir": {
+			artifactType: ArtifactTypeConstitution,
+			specsDir:     "specs",
+			want:         []string{".autospec/memory/constitution.yaml", ".autospec/memory/constitution.yml"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ArtifactFileGlobs(tt.artifactType, tt.specsDir))
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package validation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"gopkg.in/yaml.v3"
+)
+
+// lineageSource is an upstream artifact whose content hash is recorded in a
+// downstream artifact's _meta under key.
+type lineageSource struct {
+	key    string
+	source ArtifactType
+	stage  string // Stage that regenerates the downstream artifact
+}
+
+// lineageSources lists the upstream hashes recorded in each downstream artifact.
+var lineageSources = map[ArtifactType][]lineageSource{
+	ArtifactTypePlan: {
+		{key: "spec_hash", source: ArtifactTypeSpec, stage: "plan"},
+	},
+	ArtifactTypeTasks: {
+		{key: "spec_hash", source: ArtifactTypeSpec, stage: "tasks"},
+		{key: "plan_hash", source: ArtifactTypePlan, stage: "tasks"},
+	},
+}
+
+// ArtifactContentHash returns a hash of an artifact's content. Comments,
+// formatting, key order and _meta are ignored, as are spec.yaml's
+// feature.status and feature.completed_at, which autospec updates as
+// implementation progresses.
+func ArtifactContentHash(path string, artifactType ArtifactType) (string, error) {
+	content, err := LoadArtifactContent(path, artifactType)
+	if err != nil {
+		return "", err
+	}
+
+	canonical, err := yaml.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("serializing %s: %w", filepath.Base(path), err)
+	}
+	sum := sha256.Sum256(canonical)
+	// 16 hex characters are plenty to tell two versions of one file apart
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// LoadArtifactContent parses an artifact into its top-level sections, leaving
+// out what ArtifactContentHash ignores: _meta, and spec.yaml's feature.status
+// and feature.completed_at.
+func LoadArtifactContent(path string, artifactType ArtifactType) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+
+	var content map[string]any
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	delete(content, "_meta")
+	if feature, ok := content["feature"].(map[string]any); ok && artifactType == ArtifactTypeSpec {
+		delete(feature, "status")
+		delete(feature, "completed_at")
+	}
+	return content, nil
+}
+
+// StampSourceHashes records the content hashes of the upstream artifacts
+// (spec.yaml, and plan.yaml for tasks) in the _meta of a plan.yaml or
+// tasks.yaml, so later edits upstream can be detected. Upstream files that
+// do not exist are skipped.
+func StampSourceHashes(path string, artifactType ArtifactType) error {
+	sources := lineageSources[artifactType]
+	if len(sources) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("parsing %s: %w", filepath.Base(path), err)
+	}
+	rootMapping := getRootMapping(&root)
+	if rootMapping == nil {
+		return fmt.Errorf("parsing %s: file is not a YAML mapping", filepath.Base(path))
+	}
+
+	modified := addMetaSection(rootMapping, artifactType) != nil
+	meta := findNode(rootMapping, "_meta")
+	if meta == nil || meta.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: _meta is not a mapping", filepath.Base(path))
+	}
+
+	for _, src := range sources {
+		sourcePath := filepath.Join(filepath.Dir(path), string(src.source)+".yaml")
+		if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+			continue
+		}
+		hash, err := ArtifactContentHash(sourcePath, src.source)
+		if err != nil {
+			return err
+		}
+		if existing := findNode(meta, src.key); existing != nil {
+			if existing.Value != hash {
+				existing.Value = hash
+				existing.Style = yaml.DoubleQuotedStyle
+				modified = true
+			}
+			continue
+		}
+		meta.Content = append(meta.Content,
+			&yaml.Node{Kind: yaml.ScalarNode, Value: src.key},
+			&yaml.Node{Kind: yaml.ScalarNode, Value: hash, Style: yaml.DoubleQuotedStyle},
+		)
+		modified = true
+	}
+
+	if !modified {
+		return nil
+	}
+	output, err := yaml.Marshal(&root)
+	if err != nil {
+		return fmt.Errorf("serializing %s: %w", filepath.Base(path), err)
+	}
+	if err := fsutil.WriteFile(path, output, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+// CheckSourceHashes compares the upstream hashes recorded in a plan.yaml or
+// tasks.yaml with the current upstream files, returning a warning for each
+// upstream artifact that changed since the file was generated. Artifacts
+// without recorded hashes (e.g., from older autospec versions) are not checked.
+func CheckSourceHashes(path string, artifactType ArtifactType) []*ValidationWarning {
+	sources := lineageSources[artifactType]
+	if len(sources) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+	meta := findNode(getRootMapping(&root), "_meta")
+
+	var warnings []*ValidationWarning
+	for _, src := range sources {
+		recorded := findNode(meta, src.key)
+		if recorded == nil || recorded.Value == "" {
+			continue
+		}
+		sourceFile := string(src.source) + ".yaml"
+		current, err := ArtifactContentHash(filepath.Join(filepath.Dir(path), sourceFile), src.source)
+		if err != nil || current == recorded.Value {
+			continue
+		}
+		warnings = append(warnings, &ValidationWarning{
+			Path:    "_meta." + src.key,
+			Line:    recorded.Line,
+			Message: fmt.Sprintf("%s changed after %s was generated from it", sourceFile, filepath.Base(path)),
+			Hint:    fmt.Sprintf("Run 'autospec %s' to regenerate it", src.stage),
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,146 @@
+// Package validation_test tests upstream content hashes recorded in plan and tasks _meta.
+// Related: internal/validation/lineage.go
+// Tags: validation, lineage, hash, stale, drift, meta
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const lineageSpecYAML = `# Feature spec
+feature:
+  branch: "001-test"
+  status: "Draft"
+user_stories:
+  - id: "US-001"
+    title: "Login"
+_meta:
+  version: "1.1.0"
+`
+
+// writeLineageSpecDir writes spec.yaml, plan.yaml and tasks.yaml into a temp dir.
+func writeLineageSpecDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"spec.yaml":  lineageSpecYAML,
+		"plan.yaml":  "plan:\n  branch: \"001-test\"\n_meta:\n  version: \"1.1.0\"\n",
+		"tasks.yaml": "phases: []\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestArtifactContentHash(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	hashOf := func(content string) string {
+		t.Helper()
+		path := filepath.Join(dir, "spec.yaml")
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		hash, err := ArtifactContentHash(path, ArtifactTypeSpec)
+		if err != nil {
+			t.Fatalf("ArtifactContentHash() error: %v", err)
+		}
+		return hash
+	}
+
+	base := hashOf(lineageSpecYAML)
+	if len(base) != 16 {
+		t.Errorf("hash %q should be 16 hex characters", base)
+	}
+
+	same := map[string]string{
+		"comments and formatting": strings.ReplaceAll(strings.Replace(lineageSpecYAML, "# Feature spec\n", "", 1), `"`, ""),
+		"_meta":                   strings.Replace(lineageSpecYAML, `version: "1.1.0"`, `version: "1.0.0"`, 1),
+		"feature.status":          strings.Replace(lineageSpecYAML, `status: "Draft"`, "status: Completed\n  completed_at: \"2025-01-01\"", 1),
+	}
+	for name, content := range same {
+		if got := hashOf(content); got != base {
+			t.Errorf("changing %s should not change the hash", name)
+		}
+	}
+
+	if got := hashOf(strings.Replace(lineageSpecYAML, "Login", "Logout", 1)); got == base {
+		t.Error("changing content should change the hash")
+	}
+}
+
+func TestStampAndCheckSourceHashes(t *testing.T) {
+	t.Parallel()
+
+	dir := writeLineageSpecDir(t)
+	planPath := filepath.Join(dir, "plan.yaml")
+	tasksPath := filepath.Join(dir, "tasks.yaml")
+
+	if err := StampSourceHashes(planPath, ArtifactTypePlan); err != nil {
+		t.Fatalf("StampSourceHashes(plan) error: %v", err)
+	}
+	if err := StampSourceHashes(tasksPath, ArtifactTypeTasks); err != nil {
+		t.Fatalf("StampSourceHashes(tasks) error: %v", err)
+	}
+
+	data, _ := os.ReadFile(tasksPath)
+	for _, key := range []string{"spec_hash:", "plan_hash:", "artifact_type: tasks"} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("tasks.yaml missing %q after stamping:\n%s", key, data)
+		}
+	}
+
+	for _, tc := range []struct {
+		path string
+		typ  ArtifactType
+	}{{planPath, ArtifactTypePlan}, {tasksPath, ArtifactTypeTasks}} {
+		if warnings := CheckSourceHashes(tc.path, tc.typ); len(warnings) != 0 {
+			t.Errorf("freshly stamped %s should have no warnings, got %v", tc.typ, warnings[0].Message)
+		}
+	}
+
+	// Stamping again without upstream changes leaves the file alone
+	before, _ := os.ReadFile(planPath)
+	if err := StampSourceHashes(planPath, ArtifactTypePlan); err != nil {
+		t.Fatal(err)
+	}
+	if after, _ := os.ReadFile(planPath); string(after) != string(before) {
+		t.Error("re-stamping an up-to-date plan should not rewrite it")
+	}
+
+	// Editing the spec makes both downstream artifacts stale
+	edited := strings.Replace(lineageSpecYAML, "Login", "Login with GitHub", 1)
+	if err := os.WriteFile(filepath.Join(dir, "spec.yaml"), []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	warnings := CheckSourceHashes(planPath, ArtifactTypePlan)
+	if len(warnings) != 1 {
+		t.Fatalf("got %d plan warnings, want 1", len(warnings))
+	}
+	if warnings[0].Message != "spec.yaml changed after plan.yaml was generated from it" ||
+		warnings[0].Hint != "Run 'autospec plan' to regenerate it" || warnings[0].Line == 0 {
+		t.Errorf("warning = %+v", warnings[0])
+	}
+	if got := CheckSourceHashes(tasksPath, ArtifactTypeTasks); len(got) != 1 || got[0].Path != "_meta.spec_hash" {
+		t.Errorf("tasks warnings = %d, want one for _meta.spec_hash", len(got))
+	}
+}
+
+func TestCheckSourceHashes_Unstamped(t *testing.T) {
+	t.Parallel()
+
+	dir := writeLineageSpecDir(t)
+	if warnings := CheckSourceHashes(filepath.Join(dir, "plan.yaml"), ArtifactTypePlan); len(warnings) != 0 {
+		t.Errorf("plan without recorded hashes should not warn, got %d warnings", len(warnings))
+	}
+	if warnings := CheckSourceHashes(filepath.Join(dir, "spec.yaml"), ArtifactTypeSpec); warnings != nil {
+		t.Error("spec.yaml has no upstream artifacts to check")
+	}
+}
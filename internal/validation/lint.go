@@ -0,0 +1,255 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint severities, from most to least severe. LintOff disables a rule.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintInfo    = "info"
+	LintOff     = "off"
+)
+
+// LintSeverities lists the valid lint rule severities.
+var LintSeverities = []string{LintError, LintWarning, LintInfo, LintOff}
+
+// Lint rules. Each flags artifacts that pass schema validation but are weak
+// input for the next stage.
+const (
+	// RuleStoryAcceptance flags user stories without acceptance_scenarios.
+	RuleStoryAcceptance = "story_acceptance"
+	// RuleTaskAcceptance flags tasks without acceptance_criteria.
+	RuleTaskAcceptance = "task_acceptance"
+	// RuleRequirementWording flags functional requirements that do not state
+	// their obligation with MUST, SHOULD or MAY.
+	RuleRequirementWording = "requirement_wording"
+	// RulePhaseSize flags phases with more than MaxPhaseTasks tasks.
+	RulePhaseSize = "phase_size"
+)
+
+// DefaultMaxPhaseTasks is the phase size above which RulePhaseSize reports.
+const DefaultMaxPhaseTasks = 10
+
+// defaultLintSeverities is the severity of each rule when not configured.
+var defaultLintSeverities = map[string]string{
+	RuleStoryAcceptance:    LintWarning,
+	RuleTaskAcceptance:     LintWarning,
+	RuleRequirementWording: LintWarning,
+	RulePhaseSize:          LintInfo,
+}
+
+// obligationPattern matches the RFC 2119 keywords a requirement should use.
+var obligationPattern = regexp.MustCompile(`\b(MUST|SHOULD|MAY)\b`)
+
+// LintConfig configures artifact linting (config key: lint).
+type LintConfig struct {
+	// Rules overrides rule severities, keyed by rule name
+	// (e.g., task_acceptance: error, phase_size: off).
+	Rules map[string]string `koanf:"rules" yaml:"rules" json:"rules"`
+
+	// MaxPhaseTasks is the largest phase phase_size accepts.
+	// Zero uses DefaultMaxPhaseTasks.
+	MaxPhaseTasks int `koanf:"max_phase_tasks" yaml:"max_phase_tasks" json:"max_phase_tasks"`
+}
+
+// LintRules returns the rule names, sorted.
+func LintRules() []string {
+	rules := make([]string, 0, len(defaultLintSeverities))
+	for rule := range defaultLintSeverities {
+		rules = append(rules, rule)
+	}
+	sort.Strings(rules)
+	return rules
+}
+
+// Severity returns the configured severity of a rule.
+func (c LintConfig) Severity(rule string) string {
+	if severity, ok := c.Rules[rule]; ok {
+		return severity
+	}
+	return defaultLintSeverities[rule]
+}
+
+// Validate checks rule names, severities and the phase size limit.
+func (c LintConfig) Validate() error {
+	for _, rule := range sortedKeys(c.Rules) {
+		if _, ok := defaultLintSeverities[rule]; !ok {
+			return fmt.Errorf("unknown lint rule %q (valid: %s)", rule, strings.Join(LintRules(), ", "))
+		}
+		if !containsString(LintSeverities, c.Rules[rule]) {
+			return fmt.Errorf("lint rule %s: invalid severity %q (valid: %s)", rule, c.Rules[rule], strings.Join(LintSeverities, ", "))
+		}
+	}
+	if c.MaxPhaseTasks < 0 {
+		return fmt.Errorf("lint max_phase_tasks must not be negative")
+	}
+	return nil
+}
+
+// LintFinding is a single lint result.
+type LintFinding struct {
+	Rule     string
+	Severity string
+	File     string // artifact file name, e.g. "tasks.yaml"
+	Path     string // field location, e.g. "phases[1].tasks[0]"
+	Line     int
+	Message  string
+}
+
+// String formats the finding as "file:line: severity: message [rule]".
+func (f LintFinding) String() string {
+	location := f.File
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return fmt.Sprintf("%s: %s: %s [%s]", location, f.Severity, f.Message, f.Rule)
+}
+
+// LintSpecDir lints the spec.yaml and tasks.yaml in specDir. Missing
+// artifacts are skipped; YAML that cannot be parsed is an error, since schema
+// validation ('autospec artifact') must pass first.
+func LintSpecDir(specDir string, cfg LintConfig) ([]LintFinding, error) {
+	l := &linter{cfg: cfg}
+	for _, artifact := range []struct {
+		file string
+		lint func(*yaml.Node)
+	}{
+		{"spec.yaml", l.lintSpec},
+		{"tasks.yaml", l.lintTasks},
+	} {
+		data, err := os.ReadFile(filepath.Join(specDir, artifact.file))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", artifact.file, err)
+		}
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", artifact.file, err)
+		}
+		l.file = artifact.file
+		artifact.lint(&root)
+	}
+	return l.findings, nil
+}
+
+// CountLintFindings returns the number of findings per severity.
+func CountLintFindings(findings []LintFinding) map[string]int {
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Severity]++
+	}
+	return counts
+}
+
+// linter collects findings for one spec directory.
+type linter struct {
+	cfg      LintConfig
+	file     string
+	findings []LintFinding
+}
+
+func (l *linter) report(rule string, node *yaml.Node, path, format string, args ...interface{}) {
+	severity := l.cfg.Severity(rule)
+	if severity == LintOff {
+		return
+	}
+	finding := LintFinding{Rule: rule, Severity: severity, File: l.file, Path: path, Message: fmt.Sprintf(format, args...)}
+	if node != nil {
+		finding.Line = node.Line
+	}
+	l.findings = append(l.findings, finding)
+}
+
+func (l *linter) lintSpec(root *yaml.Node) {
+	if stories := findNode(root, "user_stories"); stories != nil && stories.Kind == yaml.SequenceNode {
+		for i, story := range stories.Content {
+			scenarios := findNode(story, "acceptance_scenarios")
+			if scenarios == nil || len(scenarios.Content) == 0 {
+				l.report(RuleStoryAcceptance, story, fmt.Sprintf("user_stories[%d]", i),
+					"user story %s has no acceptance scenarios", nodeLabel(story, "id", i))
+			}
+		}
+	}
+
+	functional := findNode(findNode(root, "requirements"), "functional")
+	if functional == nil || functional.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, req := range functional.Content {
+		desc := findNode(req, "description")
+		text := req.Value // plain string requirements
+		if desc != nil {
+			text = desc.Value
+		}
+		if !obligationPattern.MatchString(text) {
+			l.report(RuleRequirementWording, req, fmt.Sprintf("requirements.functional[%d]", i),
+				"requirement %s does not say MUST, SHOULD or MAY", nodeLabel(req, "id", i))
+		}
+	}
+}
+
+func (l *linter) lintTasks(root *yaml.Node) {
+	phases := findNode(root, "phases")
+	if phases == nil || phases.Kind != yaml.SequenceNode {
+		return
+	}
+	maxTasks := l.cfg.MaxPhaseTasks
+	if maxTasks == 0 {
+		maxTasks = DefaultMaxPhaseTasks
+	}
+	for i, phase := range phases.Content {
+		tasks := findNode(phase, "tasks")
+		if tasks == nil || tasks.Kind != yaml.SequenceNode {
+			continue
+		}
+		if len(tasks.Content) > maxTasks {
+			l.report(RulePhaseSize, phase, fmt.Sprintf("phases[%d]", i),
+				"phase %s has %d tasks (more than %d); consider splitting it", nodeLabel(phase, "number", i+1), len(tasks.Content), maxTasks)
+		}
+		for j, task := range tasks.Content {
+			criteria := findNode(task, "acceptance_criteria")
+			if criteria == nil || len(criteria.Content) == 0 {
+				l.report(RuleTaskAcceptance, task, fmt.Sprintf("phases[%d].tasks[%d]", i, j),
+					"task %s has no acceptance criteria", nodeLabel(task, "id", j))
+			}
+		}
+	}
+}
+
+// nodeLabel returns the value of key in a mapping node, or a positional
+// label when it is missing.
+func nodeLabel(node *yaml.Node, key string, index int) string {
+	if value := findNode(node, key); value != nil && value.Value != "" {
+		return value.Value
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const lintSpecYAML = `feature:
+  branch: 001-lint
+user_stories:
+  - id: US-001
+    title: Covered
+    acceptance_scenarios:
+      - given: a user
+        when: they log in
+        then: they see the dashboard
+  - id: US-002
+    title: Uncovered
+requirements:
+  functional:
+    - id: FR-001
+      description: The system MUST store sessions
+    - id: FR-002
+      description: Sessions are stored somewhere
+`
+
+const lintTasksYAML = `phases:
+  - number: 1
+    tasks:
+      - id: T001
+        acceptance_criteria:
+          - Builds
+      - id: T002
+      - id: T003
+        acceptance_criteria: []
+`
+
+func writeLintSpec(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "spec.yaml"), []byte(lintSpecYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tasks.yaml"), []byte(lintTasksYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLintSpecDir(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg  LintConfig
+		want []string
+	}{
+		"default severities": {
+			want: []string{
+				"spec.yaml:10: warning: user story US-002 has no acceptance scenarios [story_acceptance]",
+				"spec.yaml:16: warning: requirement FR-002 does not say MUST, SHOULD or MAY [requirement_wording]",
+				"tasks.yaml:7: warning: task T002 has no acceptance criteria [task_acceptance]",
+				"tasks.yaml:8: warning: task T003 has no acceptance criteria [task_acceptance]",
+			},
+		},
+		"configured severities": {
+			cfg: LintConfig{Rules: map[string]string{
+				RuleStoryAcceptance:    LintOff,
+				RuleRequirementWording: LintOff,
+				RuleTaskAcceptance:     LintError,
+			}},
+			want: []string{
+				"tasks.yaml:7: error: task T002 has no acceptance criteria [task_acceptance]",
+				"tasks.yaml:8: error: task T003 has no acceptance criteria [task_acceptance]",
+			},
+		},
+		"phase size": {
+			cfg: LintConfig{
+				Rules:         map[string]string{RuleStoryAcceptance: LintOff, RuleRequirementWording: LintOff, RuleTaskAcceptance: LintOff},
+				MaxPhaseTasks: 2,
+			},
+			want: []string{
+				"tasks.yaml:2: info: phase 1 has 3 tasks (more than 2); consider splitting it [phase_size]",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			findings, err := LintSpecDir(writeLintSpec(t), tt.cfg)
+			if err != nil {
+				t.Fatalf("LintSpecDir() error = %v", err)
+			}
+			got := make([]string, len(findings))
+			for i, f := range findings {
+				got[i] = f.String()
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestLintSpecDir_MissingAndInvalidArtifacts(t *testing.T) {
+	t.Parallel()
+
+	findings, err := LintSpecDir(t.TempDir(), LintConfig{})
+	if err != nil || len(findings) != 0 {
+		t.Errorf("empty spec dir: findings = %v, err = %v", findings, err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "tasks.yaml"), []byte("phases: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LintSpecDir(dir, LintConfig{}); err == nil || !strings.Contains(err.Error(), "tasks.yaml") {
+		t.Errorf("invalid YAML: err = %v, want parse error naming tasks.yaml", err)
+	}
+}
+
+func TestLintConfig_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		cfg     LintConfig
+		wantErr string
+	}{
+		"empty":            {},
+		"valid":            {cfg: LintConfig{Rules: map[string]string{RulePhaseSize: LintError}, MaxPhaseTasks: 4}},
+		"unknown rule":     {cfg: LintConfig{Rules: map[string]string{"spelling": LintError}}, wantErr: "unknown lint rule"},
+		"invalid severity": {cfg: LintConfig{Rules: map[string]string{RulePhaseSize: "fatal"}}, wantErr: "invalid severity"},
+		"negative size":    {cfg: LintConfig{MaxPhaseTasks: -1}, wantErr: "must not be negative"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package validation
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	autospecyaml "github.com/ariel-frischer/autospec/internal/yaml"
+)
+
+// MarkdownView pairs a YAML artifact with the markdown file rendered from it,
+// e.g. spec.yaml and spec.md. The YAML is the source of truth.
+type MarkdownView struct {
+	Source string       // YAML artifact path
+	Path   string       // Rendered markdown path
+	Type   ArtifactType // Artifact type of Source
+}
+
+// MarkdownViewState describes a markdown view relative to its YAML source.
+type MarkdownViewState string
+
+const (
+	MarkdownViewCurrent     MarkdownViewState = "current"      // Matches the YAML
+	MarkdownViewStale       MarkdownViewState = "stale"        // Rendered from an older YAML
+	MarkdownViewMissing     MarkdownViewState = "missing"      // Not rendered yet
+	MarkdownViewHandWritten MarkdownViewState = "hand-written" // Not generated by autospec
+)
+
+// markdownViewTypes lists the spec directory artifacts that have a markdown
+// view, in workflow order. Checklists are added per file.
+var markdownViewTypes = []ArtifactType{
+	ArtifactTypeSpec,
+	ArtifactTypePlan,
+	ArtifactTypeTasks,
+	ArtifactTypeAnalysis,
+}
+
+// MarkdownViews returns the markdown views of the YAML artifacts present in
+// specDir, including checklists/*.yaml.
+func MarkdownViews(specDir string) []MarkdownView {
+	var views []MarkdownView
+	for _, artType := range markdownViewTypes {
+		source := filepath.Join(specDir, string(artType)+".yaml")
+		if _, err := os.Stat(source); err == nil {
+			views = append(views, newMarkdownView(source, artType))
+		}
+	}
+
+	checklists, _ := filepath.Glob(filepath.Join(specDir, "checklists", "*.yaml"))
+	sort.Strings(checklists)
+	for _, source := range checklists {
+		views = append(views, newMarkdownView(source, ArtifactTypeChecklist))
+	}
+	return views
+}
+
+func newMarkdownView(source string, artType ArtifactType) MarkdownView {
+	return MarkdownView{
+		Source: source,
+		Path:   strings.TrimSuffix(source, filepath.Ext(source)) + ".md",
+		Type:   artType,
+	}
+}
+
+// Render renders the markdown view from the current YAML source.
+func (v MarkdownView) Render() ([]byte, error) {
+	data, err := os.ReadFile(v.Source)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filepath.Base(v.Source), err)
+	}
+	return autospecyaml.RenderMarkdown(data, string(v.Type), filepath.Base(v.Source))
+}
+
+// Check compares the markdown file with a fresh render of its YAML source.
+// A markdown file without the generated-by marker is reported as
+// hand-written rather than stale, since rendering would overwrite it.
+func (v MarkdownView) Check() (MarkdownViewState, error) {
+	existing, err := os.ReadFile(v.Path)
+	if os.IsNotExist(err) {
+		return MarkdownViewMissing, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", filepath.Base(v.Path), err)
+	}
+	if !bytes.HasPrefix(existing, []byte(autospecyaml.MarkdownViewMarker)) {
+		return MarkdownViewHandWritten, nil
+	}
+
+	rendered, err := v.Render()
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(existing, rendered) {
+		return MarkdownViewStale, nil
+	}
+	return MarkdownViewCurrent, nil
+}
+
+// WriteMarkdownViews renders the markdown views of every YAML artifact in
+// specDir. Views that are up to date are left untouched, and hand-written
+// markdown files are only replaced when force is set (keeping a backup, see
+// fsutil.WriteArtifact); otherwise they are returned in skipped so callers
+// can say why.
+func WriteMarkdownViews(specDir string, force bool) (written, skipped []string, err error) {
+	for _, view := range MarkdownViews(specDir) {
+		state, err := view.Check()
+		if err != nil {
+			return written, skipped, err
+		}
+		switch {
+		case state == MarkdownViewCurrent:
+			continue
+		case state == MarkdownViewHandWritten && !force:
+			skipped = append(skipped, view.Path)
+			continue
+		}
+
+		rendered, err := view.Render()
+		if err != nil {
+			return written, skipped, err
+		}
+		write := fsutil.WriteFile
+		if state == MarkdownViewHandWritten {
+			write = fsutil.WriteArtifact
+		}
+		if err := write(view.Path, rendered, 0o644); err != nil {
+			return written, skipped, fmt.Errorf("writing %s: %w", filepath.Base(view.Path), err)
+		}
+		written = append(written, view.Path)
+	}
+	return written, skipped, nil
+}
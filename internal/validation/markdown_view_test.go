@@ -0,0 +1,107 @@
+// Package validation_test tests markdown views rendered from YAML artifacts.
+// Related: internal/validation/markdown_view.go
+// Tags: validation, markdown, render, views, sync
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+)
+
+func writeMarkdownViewFixtures(t *testing.T) string {
+	t.Helper()
+	specDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(lineageSpecYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(specDir, "checklists"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	checklist := "checklist:\n  feature: \"001-test\"\ncategories: []\n"
+	if err := os.WriteFile(filepath.Join(specDir, "checklists", "ux.yaml"), []byte(checklist), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return specDir
+}
+
+func TestMarkdownViews(t *testing.T) {
+	specDir := writeMarkdownViewFixtures(t)
+
+	views := MarkdownViews(specDir)
+	if len(views) != 2 {
+		t.Fatalf("got %d views, want 2 (spec and checklist)", len(views))
+	}
+	if views[0].Type != ArtifactTypeSpec || views[0].Path != filepath.Join(specDir, "spec.md") {
+		t.Errorf("views[0] = %+v, want spec.md", views[0])
+	}
+	if views[1].Type != ArtifactTypeChecklist || views[1].Path != filepath.Join(specDir, "checklists", "ux.md") {
+		t.Errorf("views[1] = %+v, want checklists/ux.md", views[1])
+	}
+}
+
+func TestMarkdownView_CheckStates(t *testing.T) {
+	specDir := writeMarkdownViewFixtures(t)
+	view := MarkdownViews(specDir)[0]
+
+	assertState := func(want MarkdownViewState) {
+		t.Helper()
+		got, err := view.Check()
+		if err != nil {
+			t.Fatalf("Check() error: %v", err)
+		}
+		if got != want {
+			t.Errorf("Check() = %q, want %q", got, want)
+		}
+	}
+
+	assertState(MarkdownViewMissing)
+
+	if _, _, err := WriteMarkdownViews(specDir, false); err != nil {
+		t.Fatalf("WriteMarkdownViews() error: %v", err)
+	}
+	assertState(MarkdownViewCurrent)
+
+	edited := lineageSpecYAML + "assumptions:\n  - \"New assumption\"\n"
+	if err := os.WriteFile(view.Source, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assertState(MarkdownViewStale)
+
+	if err := os.WriteFile(view.Path, []byte("# My spec\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	assertState(MarkdownViewHandWritten)
+}
+
+func TestWriteMarkdownViews_HandWrittenNeedsForce(t *testing.T) {
+	specDir := writeMarkdownViewFixtures(t)
+	specMD := filepath.Join(specDir, "spec.md")
+	if err := os.WriteFile(specMD, []byte("# My spec\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	written, skipped, err := WriteMarkdownViews(specDir, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(written) != 1 || len(skipped) != 1 || skipped[0] != specMD {
+		t.Errorf("written = %v, skipped = %v; want checklist written and spec.md skipped", written, skipped)
+	}
+	if data, _ := os.ReadFile(specMD); string(data) != "# My spec\n" {
+		t.Error("hand-written spec.md must not be replaced without force")
+	}
+
+	written, _, err = WriteMarkdownViews(specDir, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(written) != 1 || written[0] != specMD {
+		t.Errorf("written = %v, want only spec.md (checklist is current)", written)
+	}
+	if data, _ := os.ReadFile(fsutil.BackupPath(specMD, 1)); string(data) != "# My spec\n" {
+		t.Error("replaced hand-written spec.md should be kept as a backup")
+	}
+}
@@ -2,7 +2,9 @@ package validation
 
 import (
 	"fmt"
+	"io"
 	"path/filepath"
+	"strings"
 )
 
 // ArtifactType represents the type of artifact to validate.
@@ -21,6 +23,10 @@ const (
 	ArtifactTypeChecklist ArtifactType = "checklist"
 	// ArtifactTypeConstitution represents constitution.yaml artifacts.
 	ArtifactTypeConstitution ArtifactType = "constitution"
+	// ArtifactTypeResearch represents research.yaml artifacts written by spikes.
+	ArtifactTypeResearch ArtifactType = "research"
+	// ArtifactTypeForecast represents forecast.yaml effort and risk estimates.
+	ArtifactTypeForecast ArtifactType = "forecast"
 )
 
 // FieldType represents the expected type of a schema field.
@@ -29,6 +35,7 @@ type FieldType string
 const (
 	FieldTypeString FieldType = "string"
 	FieldTypeInt    FieldType = "int"
+	FieldTypeNumber FieldType = "number"
 	FieldTypeBool   FieldType = "bool"
 	FieldTypeArray  FieldType = "array"
 	FieldTypeObject FieldType = "object"
@@ -52,6 +59,17 @@ type Schema struct {
 	Fields      []SchemaField
 }
 
+// Spec types (feature.type). Bugfix and refactor specs use a lighter schema:
+// user_stories is optional, and a bugfix spec requires a bug section instead.
+const (
+	SpecTypeFeature  = "feature"
+	SpecTypeBugfix   = "bugfix"
+	SpecTypeRefactor = "refactor"
+)
+
+// SpecTypes lists the valid feature.type values.
+var SpecTypes = []string{SpecTypeFeature, SpecTypeBugfix, SpecTypeRefactor}
+
 // SpecSchema defines the schema for spec.yaml artifacts.
 var SpecSchema = Schema{
 	Type:        ArtifactTypeSpec,
@@ -66,14 +84,31 @@ var SpecSchema = Schema{
 				{Name: "branch", Type: FieldTypeString, Required: true, Description: "Git branch name for the feature"},
 				{Name: "created", Type: FieldTypeString, Required: true, Description: "Creation date (YYYY-MM-DD)"},
 				{Name: "status", Type: FieldTypeString, Required: false, Enum: []string{"Draft", "Review", "Approved", "Completed"}, Description: "Feature status"},
+				{Name: "completed_at", Type: FieldTypeString, Required: false, Description: "Completion timestamp, set by autospec"},
 				{Name: "input", Type: FieldTypeString, Required: false, Description: "Original input description"},
+				{Name: "input_source", Type: FieldTypeString, Required: false, Description: "URL of the issue the input was imported from"},
+				{Name: "workdir", Type: FieldTypeString, Required: false, Description: "Directory the implement stage runs the agent in, relative to the project root"},
+				{Name: "type", Type: FieldTypeString, Required: false, Enum: SpecTypes, Description: "Kind of work (default: feature); bugfix and refactor specs make user_stories optional"},
+			},
+		},
+		{
+			Name:        "bug",
+			Type:        FieldTypeObject,
+			Required:    false,
+			Description: "The bug being fixed (required when feature.type is bugfix)",
+			Children: []SchemaField{
+				{Name: "summary", Type: FieldTypeString, Required: true, Description: "One-line description of the bug"},
+				{Name: "steps_to_reproduce", Type: FieldTypeArray, Required: false, Description: "Steps that trigger the bug"},
+				{Name: "expected", Type: FieldTypeString, Required: true, Description: "Correct behavior"},
+				{Name: "actual", Type: FieldTypeString, Required: true, Description: "Observed behavior"},
+				{Name: "root_cause", Type: FieldTypeString, Required: false, Description: "Suspected cause, if known"},
 			},
 		},
 		{
 			Name:        "user_stories",
 			Type:        FieldTypeArray,
 			Required:    true,
-			Description: "List of user stories defining feature requirements",
+			Description: "List of user stories defining feature requirements (optional in bugfix and refactor specs)",
 			Children: []SchemaField{
 				{Name: "id", Type: FieldTypeString, Required: true, Pattern: `^US-\d+$`, Description: "Story ID (US-NNN format)"},
 				{Name: "title", Type: FieldTypeString, Required: true, Description: "Short story title"},
@@ -81,6 +116,8 @@ var SpecSchema = Schema{
 				{Name: "as_a", Type: FieldTypeString, Required: true, Description: "User role"},
 				{Name: "i_want", Type: FieldTypeString, Required: true, Description: "Desired functionality"},
 				{Name: "so_that", Type: FieldTypeString, Required: true, Description: "Business value"},
+				{Name: "why_this_priority", Type: FieldTypeString, Required: false, Description: "Reason for the priority"},
+				{Name: "independent_test", Type: FieldTypeString, Required: false, Description: "How the story can be tested on its own"},
 				{Name: "acceptance_scenarios", Type: FieldTypeArray, Required: true, Description: "Given/When/Then scenarios"},
 			},
 		},
@@ -259,6 +296,7 @@ var PlanSchema = Schema{
 				{Name: "generator_version", Type: FieldTypeString, Required: false, Description: "Generator version"},
 				{Name: "created", Type: FieldTypeString, Required: false, Description: "Creation timestamp"},
 				{Name: "artifact_type", Type: FieldTypeString, Required: false, Enum: []string{"plan"}, Description: "Artifact type"},
+				{Name: "spec_hash", Type: FieldTypeString, Required: false, Description: "Content hash of the spec.yaml this artifact was generated from"},
 			},
 		},
 	},
@@ -336,6 +374,8 @@ var TasksSchema = Schema{
 				{Name: "generator_version", Type: FieldTypeString, Required: false, Description: "Generator version"},
 				{Name: "created", Type: FieldTypeString, Required: false, Description: "Creation timestamp"},
 				{Name: "artifact_type", Type: FieldTypeString, Required: false, Enum: []string{"tasks"}, Description: "Artifact type"},
+				{Name: "spec_hash", Type: FieldTypeString, Required: false, Description: "Content hash of the spec.yaml this artifact was generated from"},
+				{Name: "plan_hash", Type: FieldTypeString, Required: false, Description: "Content hash of the plan.yaml this artifact was generated from"},
 			},
 		},
 	},
@@ -352,6 +392,9 @@ var TaskFieldSchema = []SchemaField{
 	{Name: "file_path", Type: FieldTypeString, Required: false, Description: "Primary file path for this task"},
 	{Name: "dependencies", Type: FieldTypeArray, Required: false, Description: "List of task IDs this task depends on"},
 	{Name: "acceptance_criteria", Type: FieldTypeArray, Required: false, Description: "Acceptance criteria for the task"},
+	{Name: "estimated_duration", Type: FieldTypeString, Required: false, Description: "Estimated agent working time (e.g., 20m)"},
+	{Name: "actual_duration", Type: FieldTypeString, Required: false, Description: "Agent time spent on the task, recorded by implement --tasks"},
+	{Name: "changed_files", Type: FieldTypeArray, Required: false, Description: "Files the task's agent sessions created, modified or deleted, recorded by implement --tasks"},
 }
 
 // AnalysisSchema defines the schema for analysis.yaml artifacts.
@@ -382,7 +425,9 @@ var AnalysisSchema = Schema{
 				{Name: "id", Type: FieldTypeString, Required: true, Description: "Finding ID (e.g., DUP-001, AMB-001)"},
 				{Name: "category", Type: FieldTypeString, Required: true, Enum: []string{"duplication", "ambiguity", "coverage", "constitution", "inconsistency", "underspecification"}, Description: "Finding category"},
 				{Name: "severity", Type: FieldTypeString, Required: true, Enum: []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}, Description: "Finding severity"},
-				{Name: "location", Type: FieldTypeString, Required: true, Description: "Location of the finding"},
+				{Name: "artifact", Type: FieldTypeString, Required: false, Description: "Artifact the finding is in (spec, plan, tasks, constitution)"},
+				{Name: "location", Type: FieldTypeString, Required: false, Description: "Free-form location, used by older analyses instead of artifact"},
+				{Name: "field", Type: FieldTypeString, Required: false, Description: "Field path the finding is about"},
 				{Name: "summary", Type: FieldTypeString, Required: true, Description: "Brief summary of the finding"},
 				{Name: "details", Type: FieldTypeString, Required: false, Description: "Detailed explanation"},
 				{Name: "recommendation", Type: FieldTypeString, Required: false, Description: "Suggested fix"},
@@ -427,6 +472,8 @@ var AnalysisSchema = Schema{
 				{Name: "high_issues", Type: FieldTypeInt, Required: false, Description: "Number of high issues"},
 				{Name: "medium_issues", Type: FieldTypeInt, Required: false, Description: "Number of medium issues"},
 				{Name: "low_issues", Type: FieldTypeInt, Required: false, Description: "Number of low issues"},
+				{Name: "ambiguity_count", Type: FieldTypeInt, Required: false, Description: "Number of ambiguity findings"},
+				{Name: "duplication_count", Type: FieldTypeInt, Required: false, Description: "Number of duplication findings"},
 			},
 		},
 		{
@@ -522,8 +569,8 @@ var ChecklistItemSchema = []SchemaField{
 	{Name: "description", Type: FieldTypeString, Required: true, Description: "Item description (question format)"},
 	{Name: "quality_dimension", Type: FieldTypeString, Required: false, Enum: []string{"completeness", "clarity", "consistency", "measurability", "coverage", "edge_cases"}, Description: "Quality dimension being checked"},
 	{Name: "spec_reference", Type: FieldTypeString, Required: false, Description: "Reference to spec requirement"},
-	{Name: "status", Type: FieldTypeString, Required: true, Enum: []string{"pending", "pass", "fail"}, Description: "Item status"},
-	{Name: "notes", Type: FieldTypeString, Required: false, Description: "Additional notes"},
+	{Name: "status", Type: FieldTypeString, Required: true, Enum: ChecklistStatuses, Description: "Item status"},
+	{Name: "notes", Type: FieldTypeString, Required: false, Description: "Additional notes (required when status is waived)"},
 }
 
 // ConstitutionSchema defines the schema for constitution.yaml artifacts.
@@ -609,6 +656,143 @@ var ConstitutionSchema = Schema{
 	},
 }
 
+// ResearchSchema defines the schema for research.yaml artifacts, the only
+// output of a spike.
+var ResearchSchema = Schema{
+	Type:        ArtifactTypeResearch,
+	Description: "Spike research artifact containing the question, findings, and a recommendation",
+	Fields: []SchemaField{
+		{
+			Name:        "research",
+			Type:        FieldTypeObject,
+			Required:    true,
+			Description: "Spike metadata and the question it answers",
+			Children: []SchemaField{
+				{Name: "branch", Type: FieldTypeString, Required: true, Description: "Git branch name for the spike"},
+				{Name: "created", Type: FieldTypeString, Required: true, Description: "Creation date (YYYY-MM-DD)"},
+				{Name: "question", Type: FieldTypeString, Required: true, Description: "The question the spike answers"},
+				{Name: "input", Type: FieldTypeString, Required: false, Description: "Original input description"},
+			},
+		},
+		{
+			Name:        "findings",
+			Type:        FieldTypeArray,
+			Required:    true,
+			Description: "What the spike found out",
+			Children: []SchemaField{
+				{Name: "id", Type: FieldTypeString, Required: true, Pattern: `^F-\d+$`, Description: "Finding ID (e.g., F-001)"},
+				{Name: "topic", Type: FieldTypeString, Required: true, Description: "Area investigated"},
+				{Name: "summary", Type: FieldTypeString, Required: true, Description: "What was learned"},
+				{Name: "evidence", Type: FieldTypeArray, Required: false, Description: "Sources, experiments, or code references"},
+			},
+		},
+		{
+			Name:        "options",
+			Type:        FieldTypeArray,
+			Required:    false,
+			Description: "Approaches considered",
+			Children: []SchemaField{
+				{Name: "name", Type: FieldTypeString, Required: true, Description: "Option name"},
+				{Name: "pros", Type: FieldTypeArray, Required: false, Description: "Advantages"},
+				{Name: "cons", Type: FieldTypeArray, Required: false, Description: "Disadvantages"},
+			},
+		},
+		{
+			Name:        "recommendation",
+			Type:        FieldTypeObject,
+			Required:    true,
+			Description: "Conclusion of the spike",
+			Children: []SchemaField{
+				{Name: "summary", Type: FieldTypeString, Required: true, Description: "Recommended approach"},
+				{Name: "next_steps", Type: FieldTypeArray, Required: false, Description: "Follow-up work, e.g. a feature to specify"},
+			},
+		},
+		{
+			Name:        "_meta",
+			Type:        FieldTypeObject,
+			Required:    false,
+			Description: "Artifact metadata",
+			Children: []SchemaField{
+				{Name: "version", Type: FieldTypeString, Required: false, Description: "Schema version"},
+				{Name: "generator", Type: FieldTypeString, Required: false, Description: "Generator tool name"},
+				{Name: "generator_version", Type: FieldTypeString, Required: false, Description: "Generator version"},
+				{Name: "created", Type: FieldTypeString, Required: false, Description: "Creation timestamp"},
+				{Name: "artifact_type", Type: FieldTypeString, Required: false, Enum: []string{"research"}, Description: "Artifact type"},
+			},
+		},
+	},
+}
+
+// ForecastLevels are the values of forecast risk and confidence fields.
+var ForecastLevels = []string{"low", "medium", "high"}
+
+// ForecastSchema defines the schema for forecast.yaml artifacts.
+var ForecastSchema = Schema{
+	Type:        ArtifactTypeForecast,
+	Description: "Effort and risk forecast for the tasks of a feature, with suggested sequencing",
+	Fields: []SchemaField{
+		{
+			Name:        "forecast",
+			Type:        FieldTypeObject,
+			Required:    true,
+			Description: "Forecast metadata",
+			Children: []SchemaField{
+				{Name: "branch", Type: FieldTypeString, Required: true, Description: "Git branch name for the feature"},
+				{Name: "created", Type: FieldTypeString, Required: true, Description: "Creation date (YYYY-MM-DD)"},
+				{Name: "tasks_path", Type: FieldTypeString, Required: false, Description: "Path to the tasks.yaml that was estimated"},
+			},
+		},
+		{
+			Name:        "summary",
+			Type:        FieldTypeObject,
+			Required:    true,
+			Description: "Rollup across all estimates",
+			Children: []SchemaField{
+				{Name: "total_effort_hours", Type: FieldTypeNumber, Required: true, Description: "Estimated total effort in hours"},
+				{Name: "overall_risk", Type: FieldTypeString, Required: true, Enum: ForecastLevels, Description: "Overall delivery risk"},
+				{Name: "confidence", Type: FieldTypeString, Required: false, Enum: ForecastLevels, Description: "Confidence in the estimates"},
+				{Name: "notes", Type: FieldTypeString, Required: false, Description: "Assumptions behind the estimates"},
+			},
+		},
+		{
+			Name:        "estimates",
+			Type:        FieldTypeArray,
+			Required:    true,
+			Description: "Per-task effort and risk",
+			Children: []SchemaField{
+				{Name: "task_id", Type: FieldTypeString, Required: true, Pattern: `^T\d+$`, Description: "Task ID from tasks.yaml (e.g., T001)"},
+				{Name: "effort_hours", Type: FieldTypeNumber, Required: true, Description: "Estimated effort in hours"},
+				{Name: "risk", Type: FieldTypeString, Required: true, Enum: ForecastLevels, Description: "Risk that the task overruns or fails"},
+				{Name: "risk_reasons", Type: FieldTypeArray, Required: false, Description: "Why the task is risky"},
+			},
+		},
+		{
+			Name:        "sequencing",
+			Type:        FieldTypeArray,
+			Required:    false,
+			Description: "Suggested order of work, one entry per step",
+			Children: []SchemaField{
+				{Name: "step", Type: FieldTypeInt, Required: true, Description: "Step number, starting at 1"},
+				{Name: "task_ids", Type: FieldTypeArray, Required: true, Description: "Tasks to do in this step"},
+				{Name: "rationale", Type: FieldTypeString, Required: false, Description: "Why these tasks come at this point"},
+			},
+		},
+		{
+			Name:        "_meta",
+			Type:        FieldTypeObject,
+			Required:    false,
+			Description: "Artifact metadata",
+			Children: []SchemaField{
+				{Name: "version", Type: FieldTypeString, Required: false, Description: "Schema version"},
+				{Name: "generator", Type: FieldTypeString, Required: false, Description: "Generator tool name"},
+				{Name: "generator_version", Type: FieldTypeString, Required: false, Description: "Generator version"},
+				{Name: "created", Type: FieldTypeString, Required: false, Description: "Creation timestamp"},
+				{Name: "artifact_type", Type: FieldTypeString, Required: false, Enum: []string{"forecast"}, Description: "Artifact type"},
+			},
+		},
+	},
+}
+
 // GetSchema returns the schema for the given artifact type.
 func GetSchema(artifactType ArtifactType) (*Schema, error) {
 	switch artifactType {
@@ -624,11 +808,67 @@ func GetSchema(artifactType ArtifactType) (*Schema, error) {
 		return &ChecklistSchema, nil
 	case ArtifactTypeConstitution:
 		return &ConstitutionSchema, nil
+	case ArtifactTypeResearch:
+		return &ResearchSchema, nil
+	case ArtifactTypeForecast:
+		return &ForecastSchema, nil
 	default:
 		return nil, fmt.Errorf("unknown artifact type: %s", artifactType)
 	}
 }
 
+// WriteSchemaFields writes fields as an indented outline, one
+// "name: type (required)" line per field followed by its description, as
+// shown by 'autospec artifact <type> --schema'.
+func WriteSchemaFields(out io.Writer, fields []SchemaField, indent string) {
+	for _, field := range fields {
+		required := ""
+		if field.Required {
+			required = " (required)"
+		}
+		typeStr := string(field.Type)
+		if len(field.Enum) > 0 {
+			typeStr = fmt.Sprintf("enum[%s]", strings.Join(field.Enum, ", "))
+		}
+
+		fmt.Fprintf(out, "%s%s: %s%s\n", indent, field.Name, typeStr, required)
+		if field.Description != "" {
+			fmt.Fprintf(out, "%s  # %s\n", indent, field.Description)
+		}
+		WriteSchemaFields(out, field.Children, indent+"  ")
+	}
+}
+
+// FindSchemaField returns the schema field at a validation error path such as
+// "user_stories[0].priority". Array indices are ignored.
+// Returns nil when the path is not described by the schema.
+func FindSchemaField(artifactType ArtifactType, path string) *SchemaField {
+	schema, err := GetSchema(artifactType)
+	if err != nil || path == "" {
+		return nil
+	}
+
+	fields := schema.Fields
+	var found *SchemaField
+	for _, part := range strings.Split(path, ".") {
+		if idx := strings.IndexByte(part, '['); idx >= 0 {
+			part = part[:idx]
+		}
+		found = nil
+		for i := range fields {
+			if fields[i].Name == part {
+				found = &fields[i]
+				break
+			}
+		}
+		if found == nil {
+			return nil
+		}
+		fields = found.Children
+	}
+	return found
+}
+
 // ParseArtifactType parses a string into an ArtifactType.
 func ParseArtifactType(s string) (ArtifactType, error) {
 	switch s {
@@ -644,14 +884,18 @@ func ParseArtifactType(s string) (ArtifactType, error) {
 		return ArtifactTypeChecklist, nil
 	case "constitution":
 		return ArtifactTypeConstitution, nil
+	case "research":
+		return ArtifactTypeResearch, nil
+	case "forecast":
+		return ArtifactTypeForecast, nil
 	default:
-		return "", fmt.Errorf("invalid artifact type: %s (valid types: spec, plan, tasks, analysis, checklist, constitution)", s)
+		return "", fmt.Errorf("invalid artifact type: %s (valid types: spec, plan, tasks, analysis, checklist, constitution, research, forecast)", s)
 	}
 }
 
 // ValidArtifactTypes returns a list of valid artifact type strings.
 func ValidArtifactTypes() []string {
-	return []string{"spec", "plan", "tasks", "analysis", "checklist", "constitution"}
+	return []string{"spec", "plan", "tasks", "analysis", "checklist", "constitution", "research", "forecast"}
 }
 
 // artifactFilenames maps canonical filenames to artifact types.
@@ -666,6 +910,10 @@ var artifactFilenames = map[string]ArtifactType{
 	"analysis.yml":      ArtifactTypeAnalysis,
 	"constitution.yaml": ArtifactTypeConstitution,
 	"constitution.yml":  ArtifactTypeConstitution,
+	"research.yaml":     ArtifactTypeResearch,
+	"research.yml":      ArtifactTypeResearch,
+	"forecast.yaml":     ArtifactTypeForecast,
+	"forecast.yml":      ArtifactTypeForecast,
 }
 
 // InferArtifactTypeFromFilename infers the artifact type from a filename.
@@ -683,5 +931,5 @@ func InferArtifactTypeFromFilename(filename string) (ArtifactType, error) {
 
 // ValidArtifactFilenames returns a list of recognized artifact filenames.
 func ValidArtifactFilenames() []string {
-	return []string{"spec.yaml", "plan.yaml", "tasks.yaml", "analysis.yaml", "constitution.yaml"}
+	return []string{"spec.yaml", "plan.yaml", "tasks.yaml", "analysis.yaml", "constitution.yaml", "research.yaml", "forecast.yaml"}
 }
@@ -98,6 +98,36 @@ func TestGetSchema_UnknownType(t *testing.T) {
 	}
 }
 
+func TestFindSchemaField(t *testing.T) {
+	tests := map[string]struct {
+		artType  ArtifactType
+		path     string
+		wantName string
+	}{
+		"top-level field":     {artType: ArtifactTypeSpec, path: "user_stories", wantName: "user_stories"},
+		"indexed child":       {artType: ArtifactTypeSpec, path: "user_stories[2].priority", wantName: "priority"},
+		"nested object":       {artType: ArtifactTypeSpec, path: "feature.branch", wantName: "branch"},
+		"unknown field":       {artType: ArtifactTypeSpec, path: "user_stories[0].nope", wantName: ""},
+		"empty path":          {artType: ArtifactTypePlan, path: "", wantName: ""},
+		"unknown schema type": {artType: ArtifactType("unknown"), path: "feature", wantName: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := FindSchemaField(tt.artType, tt.path)
+			if tt.wantName == "" {
+				if got != nil {
+					t.Errorf("FindSchemaField(%q) = %q, want nil", tt.path, got.Name)
+				}
+				return
+			}
+			if got == nil || got.Name != tt.wantName {
+				t.Errorf("FindSchemaField(%q) = %v, want field %q", tt.path, got, tt.wantName)
+			}
+		})
+	}
+}
+
 func TestParseArtifactType(t *testing.T) {
 	tests := map[string]struct {
 		input    string
@@ -128,8 +158,8 @@ func TestParseArtifactType(t *testing.T) {
 
 func TestValidArtifactTypes(t *testing.T) {
 	types := ValidArtifactTypes()
-	if len(types) != 6 {
-		t.Errorf("ValidArtifactTypes() returned %d types, want 6", len(types))
+	if len(types) != 8 {
+		t.Errorf("ValidArtifactTypes() returned %d types, want 8", len(types))
 	}
 
 	expected := map[string]bool{
@@ -139,6 +169,8 @@ func TestValidArtifactTypes(t *testing.T) {
 		"analysis":     true,
 		"checklist":    true,
 		"constitution": true,
+		"research":     true,
+		"forecast":     true,
 	}
 	for _, typ := range types {
 		if !expected[typ] {
@@ -328,8 +360,8 @@ func TestInferArtifactTypeFromFilename(t *testing.T) {
 
 func TestValidArtifactFilenames(t *testing.T) {
 	filenames := ValidArtifactFilenames()
-	if len(filenames) != 5 {
-		t.Errorf("ValidArtifactFilenames() returned %d filenames, want 5", len(filenames))
+	if len(filenames) != 7 {
+		t.Errorf("ValidArtifactFilenames() returned %d filenames, want 7", len(filenames))
 	}
 
 	expected := map[string]bool{
@@ -338,6 +370,8 @@ func TestValidArtifactFilenames(t *testing.T) {
 		"tasks.yaml":        true,
 		"analysis.yaml":     true,
 		"constitution.yaml": true,
+		"research.yaml":     true,
+		"forecast.yaml":     true,
 	}
 	for _, filename := range filenames {
 		if !expected[filename] {
@@ -0,0 +1,112 @@
+package validation
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Validation modes accepted by the validation_mode config key.
+const (
+	ModeLenient = "lenient" // unknown fields and missing optional sections are warnings
+	ModeStrict  = "strict"  // unknown fields and missing optional sections are errors
+)
+
+// Modes lists the validation modes.
+var Modes = []string{ModeLenient, ModeStrict}
+
+var strict atomic.Bool
+
+// SetMode selects the validation mode used by all artifact validators. It is
+// set from the validation_mode config key when the config is loaded, and by
+// --strict.
+func SetMode(mode string) error {
+	switch mode {
+	case "", ModeLenient:
+		strict.Store(false)
+	case ModeStrict:
+		strict.Store(true)
+	default:
+		return fmt.Errorf("unknown validation mode %q (valid: %s, %s)", mode, ModeLenient, ModeStrict)
+	}
+	return nil
+}
+
+// Mode returns the validation mode in use.
+func Mode() string {
+	if strict.Load() {
+		return ModeStrict
+	}
+	return ModeLenient
+}
+
+// strictnessExempt are optional top-level sections that are never reported
+// as missing: _meta is written by autospec, and bug only applies to bugfix
+// specs, which the spec validator already checks.
+var strictnessExempt = map[string]bool{
+	"_meta": true,
+	"bug":   true,
+}
+
+// checkStrictness reports the fields of root that schema does not define and
+// the optional top-level sections it lacks: as warnings in lenient mode, as
+// errors in strict mode. Fields without children in the schema (free-form
+// objects and lists of scalars) are not checked inside.
+func checkStrictness(root *yaml.Node, schema *Schema, result *ValidationResult) {
+	report := func(path string, line, column int, message, hint string) {
+		if strict.Load() {
+			result.AddError(&ValidationError{Path: path, Line: line, Column: column, Message: message, Hint: hint})
+			return
+		}
+		result.AddWarning(&ValidationWarning{Path: path, Line: line, Message: message, Hint: hint})
+	}
+
+	unknownHint := fmt.Sprintf("Remove the field or check its spelling against 'autospec artifact %s --schema'", schema.Type)
+	checkUnknownFields(root, "", schema.Fields, func(path string, line, column int) {
+		report(path, line, column, fmt.Sprintf("unknown field: %s", path), unknownHint)
+	})
+	for _, field := range schema.Fields {
+		if field.Required || strictnessExempt[field.Name] || findNode(root, field.Name) != nil {
+			continue
+		}
+		report(field.Name, getNodeLine(root), getNodeColumn(root),
+			fmt.Sprintf("missing optional section: %s", field.Name),
+			fmt.Sprintf("Add '%s' (%s)", field.Name, field.Description))
+	}
+}
+
+// checkUnknownFields reports the keys of mapping that fields does not define,
+// and recurses into the fields that have children.
+func checkUnknownFields(mapping *yaml.Node, parentPath string, fields []SchemaField, report func(path string, line, column int)) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return
+	}
+	known := make(map[string]SchemaField, len(fields))
+	for _, field := range fields {
+		known[field.Name] = field
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		path := key.Value
+		if parentPath != "" {
+			path = parentPath + "." + key.Value
+		}
+		field, ok := known[key.Value]
+		if !ok {
+			report(path, key.Line, key.Column)
+			continue
+		}
+		if len(field.Children) == 0 {
+			continue
+		}
+		switch value.Kind {
+		case yaml.MappingNode:
+			checkUnknownFields(value, path, field.Children, report)
+		case yaml.SequenceNode:
+			for j, item := range value.Content {
+				checkUnknownFields(item, fmt.Sprintf("%s[%d]", path, j), field.Children, report)
+			}
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withoutStrictness returns the warnings of result that are not about
+// unknown fields or missing optional sections, for tests of other checks.
+func withoutStrictness(result *ValidationResult) []*ValidationWarning {
+	var warnings []*ValidationWarning
+	for _, w := range result.Warnings {
+		if strings.HasPrefix(w.Message, "unknown field: ") || strings.HasPrefix(w.Message, "missing optional section: ") {
+			continue
+		}
+		warnings = append(warnings, w)
+	}
+	return warnings
+}
+
+// setMode selects mode for the rest of the test. Tests that use it must not
+// be parallel: the mode is process-wide.
+func setMode(t *testing.T, mode string) {
+	t.Helper()
+	require.NoError(t, SetMode(mode))
+	t.Cleanup(func() { _ = SetMode(ModeLenient) })
+}
+
+func TestSetMode(t *testing.T) {
+	// Not parallel: changes the process-wide validation mode.
+	tests := map[string]struct {
+		mode    string
+		want    string
+		wantErr bool
+	}{
+		"empty is lenient": {mode: "", want: ModeLenient},
+		"lenient":          {mode: ModeLenient, want: ModeLenient},
+		"strict":           {mode: ModeStrict, want: ModeStrict},
+		"unknown":          {mode: "pedantic", want: ModeLenient, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setMode(t, ModeLenient)
+			err := SetMode(tt.mode)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "unknown validation mode")
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, Mode())
+		})
+	}
+}
+
+const strictnessPlan = `plan:
+  branch: "001-test"
+  spec_path: "specs/001-test/spec.yaml"
+  reviewer: "sam"
+summary: "A plan"
+technical_context:
+  language: "Go"
+risks:
+  - id: "RISK-001"
+    risk: "Slow queries"
+    likelihood: "low"
+    impact: "low"
+    owner: "ops"
+notes: "extra"
+`
+
+func TestCheckStrictness(t *testing.T) {
+	// Not parallel: changes the process-wide validation mode.
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(strictnessPlan), 0o644))
+
+	wantIssues := []string{
+		"unknown field: plan.reviewer",
+		"unknown field: risks[0].owner",
+		"unknown field: notes",
+		"missing optional section: data_model",
+	}
+
+	tests := map[string]struct {
+		mode      string
+		wantValid bool
+	}{
+		"lenient warns": {mode: ModeLenient, wantValid: true},
+		"strict fails":  {mode: ModeStrict, wantValid: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			setMode(t, tt.mode)
+			result := (&PlanValidator{}).Validate(path)
+
+			assert.Equal(t, tt.wantValid, result.Valid)
+			var messages []string
+			if tt.mode == ModeStrict {
+				for _, e := range result.Errors {
+					messages = append(messages, e.Message)
+				}
+				assert.Empty(t, withoutStrictness(result))
+			} else {
+				assert.Empty(t, result.Errors)
+				for _, w := range result.Warnings {
+					messages = append(messages, w.Message)
+				}
+			}
+			for _, want := range wantIssues {
+				assert.Contains(t, messages, want)
+			}
+			assert.NotContains(t, messages, "missing optional section: risks")
+			assert.NotContains(t, messages, "unknown field: technical_context.language")
+		})
+	}
+}
+
+func TestCheckStrictness_CompleteArtifact(t *testing.T) {
+	// Not parallel: changes the process-wide validation mode.
+	setMode(t, ModeStrict)
+
+	constitution := `constitution:
+  project_name: "demo"
+  version: "1.0.0"
+preamble: "Rules for demo"
+principles:
+  - name: "Tests first"
+    id: "PRIN-001"
+    priority: "MUST"
+    description: "Write tests before code"
+sections:
+  - name: "Style"
+    content: "gofmt"
+governance:
+  rules: ["Amend by PR"]
+sync_impact:
+  version_change: "0.0.0 -> 1.0.0"
+_meta:
+  artifact_type: "constitution"
+`
+	path := filepath.Join(t.TempDir(), "constitution.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(constitution), 0o644))
+
+	result := (&ConstitutionValidator{}).Validate(path)
+	for _, e := range result.Errors {
+		t.Logf("error: %s", e.Error())
+	}
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Warnings)
+}
@@ -0,0 +1,264 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// taskRuntimeKeys are task fields owned by implementation progress rather than
+// by task generation. They are carried over from the original task when a
+// phase is regenerated.
+var taskRuntimeKeys = []string{"status", "blocked_reason", "status_history"}
+
+// PhaseRegenResult describes the merge of a regenerated phase into tasks.yaml.
+type PhaseRegenResult struct {
+	Phase     int
+	Added     []string // Task IDs new to the phase
+	Updated   []string // Task IDs whose definition changed
+	Removed   []string // Task IDs dropped from the phase
+	Kept      []string // Completed task IDs kept unchanged
+	Conflicts []string // Reasons the regenerated phase cannot be merged
+}
+
+// HasConflicts returns true if the regenerated phase was not merged.
+func (r *PhaseRegenResult) HasConflicts() bool {
+	return len(r.Conflicts) > 0
+}
+
+// MergeRegeneratedPhase replaces one phase of the original tasks.yaml with the
+// same phase from a regenerated tasks.yaml. All other phases are taken from the
+// original, and tasks that keep their ID keep their status and status history.
+//
+// The merge is refused (Conflicts set, nil output) when the regenerated phase:
+//   - reuses a task ID from another phase or repeats an ID
+//   - drops or redefines a Completed task
+//   - depends on a task ID that does not exist after the merge
+//   - drops a task that a task in another phase depends on
+func MergeRegeneratedPhase(original, regenerated []byte, phase int) ([]byte, *PhaseRegenResult, error) {
+	var origDoc, regenDoc yaml.Node
+	if err := yaml.Unmarshal(original, &origDoc); err != nil {
+		return nil, nil, fmt.Errorf("parsing original tasks YAML: %w", err)
+	}
+	if err := yaml.Unmarshal(regenerated, &regenDoc); err != nil {
+		return nil, nil, fmt.Errorf("parsing regenerated tasks YAML: %w", err)
+	}
+
+	origPhase := findPhaseNode(getRootMapping(&origDoc), phase)
+	if origPhase == nil {
+		return nil, nil, fmt.Errorf("phase %d not found in tasks.yaml", phase)
+	}
+	regenPhase := findPhaseNode(getRootMapping(&regenDoc), phase)
+	if regenPhase == nil {
+		return nil, nil, fmt.Errorf("phase %d not found in regenerated tasks", phase)
+	}
+
+	result := &PhaseRegenResult{Phase: phase}
+
+	// Task IDs that live outside the regenerated phase
+	otherIDs := make(map[string]int)
+	var otherTasks []*yaml.Node
+	for _, p := range phaseNodes(getRootMapping(&origDoc)) {
+		if p == origPhase {
+			continue
+		}
+		number, _ := strconv.Atoi(scalarValue(p, "number"))
+		for _, task := range taskNodes(p) {
+			otherIDs[scalarValue(task, "id")] = number
+			otherTasks = append(otherTasks, task)
+		}
+	}
+
+	origTasks := make(map[string]*yaml.Node)
+	for _, task := range taskNodes(origPhase) {
+		origTasks[scalarValue(task, "id")] = task
+	}
+
+	regenTasks := findNode(regenPhase, "tasks")
+	if regenTasks == nil || regenTasks.Kind != yaml.SequenceNode {
+		return nil, nil, fmt.Errorf("phase %d in regenerated tasks has no task list", phase)
+	}
+
+	seen := make(map[string]bool)
+	for i, task := range regenTasks.Content {
+		id := scalarValue(task, "id")
+		switch {
+		case id == "":
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("task %d in the regenerated phase has no id", i+1))
+			continue
+		case seen[id]:
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s appears more than once in the regenerated phase", id))
+			continue
+		}
+		seen[id] = true
+		if other, ok := otherIDs[id]; ok {
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s is already used by a task in phase %d", id, other))
+			continue
+		}
+
+		orig, ok := origTasks[id]
+		if !ok {
+			result.Added = append(result.Added, id)
+			continue
+		}
+		changed := !sameTaskDefinition(orig, task)
+		if isCompletedStatus(scalarValue(orig, "status")) {
+			if changed {
+				result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s is Completed but the regenerated phase redefines it", id))
+				continue
+			}
+			regenTasks.Content[i] = orig
+			result.Kept = append(result.Kept, id)
+			continue
+		}
+		copyRuntimeFields(orig, task)
+		if changed {
+			result.Updated = append(result.Updated, id)
+		}
+	}
+
+	for _, task := range taskNodes(origPhase) {
+		id := scalarValue(task, "id")
+		if seen[id] {
+			continue
+		}
+		if isCompletedStatus(scalarValue(task, "status")) {
+			result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s is Completed but the regenerated phase removes it", id))
+			continue
+		}
+		result.Removed = append(result.Removed, id)
+	}
+
+	for _, task := range regenTasks.Content {
+		for _, dep := range sequenceValues(findNode(task, "dependencies")) {
+			if _, ok := otherIDs[dep]; !ok && !seen[dep] {
+				result.Conflicts = append(result.Conflicts,
+					fmt.Sprintf("%s depends on %s, which does not exist", scalarValue(task, "id"), dep))
+			}
+		}
+	}
+
+	for _, task := range otherTasks {
+		for _, dep := range sequenceValues(findNode(task, "dependencies")) {
+			if slices.Contains(result.Removed, dep) {
+				id := scalarValue(task, "id")
+				result.Conflicts = append(result.Conflicts,
+					fmt.Sprintf("%s in phase %d depends on %s, which the regenerated phase removes", id, otherIDs[id], dep))
+			}
+		}
+	}
+
+	if result.HasConflicts() {
+		return nil, result, nil
+	}
+
+	SetMappingValue(origPhase, "tasks", regenTasks, "")
+	for _, key := range []string{"title", "purpose", "story_reference"} {
+		if value := findNode(regenPhase, key); value != nil {
+			SetMappingValue(origPhase, key, value, "")
+		}
+	}
+	if total := findNode(findNode(getRootMapping(&origDoc), "summary"), "total_tasks"); total != nil {
+		total.Value = strconv.Itoa(len(otherIDs) + len(regenTasks.Content))
+	}
+
+	output, err := yaml.Marshal(&origDoc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("serializing tasks YAML: %w", err)
+	}
+	return output, result, nil
+}
+
+// phaseNodes returns the phase mappings of a tasks.yaml root mapping.
+func phaseNodes(root *yaml.Node) []*yaml.Node {
+	phases := findNode(root, "phases")
+	if phases == nil || phases.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return phases.Content
+}
+
+// findPhaseNode returns the phase mapping with the given number, or nil.
+func findPhaseNode(root *yaml.Node, number int) *yaml.Node {
+	for _, phase := range phaseNodes(root) {
+		if scalarValue(phase, "number") == strconv.Itoa(number) {
+			return phase
+		}
+	}
+	return nil
+}
+
+// taskNodes returns the task mappings of a phase mapping.
+func taskNodes(phase *yaml.Node) []*yaml.Node {
+	tasks := findNode(phase, "tasks")
+	if tasks == nil || tasks.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return tasks.Content
+}
+
+// scalarValue returns the value of a scalar key in a mapping, or "".
+func scalarValue(node *yaml.Node, key string) string {
+	if value := findNode(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// sequenceValues returns the scalar items of a sequence node.
+func sequenceValues(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	values := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		values = append(values, item.Value)
+	}
+	return values
+}
+
+// removeMappingKey removes key and its value from a mapping.
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// copyRuntimeFields copies status, blocked_reason and status_history from
+// one task mapping to another.
+func copyRuntimeFields(from, to *yaml.Node) {
+	for _, key := range taskRuntimeKeys {
+		if value := findNode(from, key); value != nil {
+			SetMappingValue(to, key, value, "")
+		} else {
+			removeMappingKey(to, key)
+		}
+	}
+}
+
+// sameTaskDefinition reports whether two task mappings define the same work,
+// ignoring runtime fields and YAML formatting.
+func sameTaskDefinition(a, b *yaml.Node) bool {
+	var ta, tb TaskItem
+	if a.Decode(&ta) != nil || b.Decode(&tb) != nil {
+		return false
+	}
+	return ta.Title == tb.Title &&
+		ta.Type == tb.Type &&
+		ta.Parallel == tb.Parallel &&
+		ta.StoryID == tb.StoryID &&
+		ta.FilePath == tb.FilePath &&
+		ta.Notes == tb.Notes &&
+		slices.Equal(ta.Dependencies, tb.Dependencies) &&
+		slices.Equal(ta.AcceptanceCriteria, tb.AcceptanceCriteria)
+}
+
+// isCompletedStatus reports whether a task status counts as completed.
+func isCompletedStatus(status string) bool {
+	return normalizeEnumKey(status) == "completed" || taskStatusAliases[normalizeEnumKey(status)] == "Completed"
+}
@@ -0,0 +1,243 @@
+// Package validation_test tests merging a regenerated phase into tasks.yaml.
+// Related: internal/validation/tasks_regen.go
+// Tags: validation, tasks, regen, phase, merge, conflict
+package validation
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const regenOriginalYAML = `tasks:
+  branch: "001-test"
+summary:
+  total_tasks: 4
+  total_phases: 2
+phases:
+  - number: 1
+    title: "Setup"
+    purpose: "Setup"
+    tasks:
+      - id: "T001"
+        title: "Create project"
+        status: "Completed"
+        type: "setup"
+        dependencies: []
+        acceptance_criteria: ["Project builds"]
+  - number: 2
+    title: "API"
+    purpose: "Build the API"
+    tasks:
+      - id: "T002"
+        title: "Add handler"
+        status: "Completed"
+        type: "implementation"
+        dependencies: ["T001"]
+        acceptance_criteria: ["Handler responds"]
+      - id: "T003"
+        title: "Add routes"
+        status: "InProgress"
+        type: "implementation"
+        dependencies: ["T002"]
+        acceptance_criteria: ["Routes registered"]
+        status_history:
+          - from: "Pending"
+            to: "InProgress"
+            by: "agent"
+            at: "2025-01-01T00:00:00Z"
+      - id: "T004"
+        title: "Add docs"
+        status: "Pending"
+        type: "documentation"
+        dependencies: []
+        acceptance_criteria: ["Docs written"]
+`
+
+// regenPhase2 returns a regenerated tasks.yaml whose phase 2 has the given tasks.
+// Phase 1 is changed too, to check that edits outside the phase are discarded.
+func regenPhase2(tasks string) []byte {
+	return []byte(`phases:
+  - number: 1
+    title: "Setup (edited)"
+    tasks:
+      - id: "T001"
+        title: "Create project"
+        status: "Pending"
+  - number: 2
+    title: "API v2"
+    purpose: "Build the API"
+    tasks:
+` + tasks)
+}
+
+const regenKeptT002 = `      - id: T002
+        title: Add handler
+        status: Pending
+        type: implementation
+        dependencies: [T001]
+        acceptance_criteria: [Handler responds]
+`
+
+func TestMergeRegeneratedPhase(t *testing.T) {
+	t.Parallel()
+
+	regenerated := regenPhase2(regenKeptT002 + `      - id: "T003"
+        title: "Add routes with versioning"
+        status: "Pending"
+        type: "implementation"
+        dependencies: ["T002"]
+        acceptance_criteria: ["Routes registered under /v2"]
+      - id: "T005"
+        title: "Add middleware"
+        status: "Pending"
+        type: "implementation"
+        dependencies: ["T003"]
+        acceptance_criteria: ["Middleware runs"]
+`)
+
+	merged, result, err := MergeRegeneratedPhase([]byte(regenOriginalYAML), regenerated, 2)
+	if err != nil {
+		t.Fatalf("MergeRegeneratedPhase() error: %v", err)
+	}
+	if result.HasConflicts() {
+		t.Fatalf("unexpected conflicts: %v", result.Conflicts)
+	}
+	for name, got := range map[string][]string{
+		"Added": result.Added, "Updated": result.Updated, "Removed": result.Removed, "Kept": result.Kept,
+	} {
+		want := map[string]string{"Added": "T005", "Updated": "T003", "Removed": "T004", "Kept": "T002"}[name]
+		if strings.Join(got, ",") != want {
+			t.Errorf("%s = %v, want [%s]", name, got, want)
+		}
+	}
+
+	var tasks TasksYAML
+	if err := yaml.Unmarshal(merged, &tasks); err != nil {
+		t.Fatalf("merged output is not valid YAML: %v", err)
+	}
+	if tasks.Phases[0].Title != "Setup" || tasks.Phases[0].Tasks[0].Status != "Completed" {
+		t.Errorf("phase 1 should be unchanged, got %+v", tasks.Phases[0])
+	}
+	if tasks.Phases[1].Title != "API v2" {
+		t.Errorf("phase 2 title = %q, want %q", tasks.Phases[1].Title, "API v2")
+	}
+	if tasks.Summary.TotalTasks != 4 {
+		t.Errorf("summary.total_tasks = %d, want 4", tasks.Summary.TotalTasks)
+	}
+
+	phase2 := tasks.Phases[1].Tasks
+	if len(phase2) != 3 {
+		t.Fatalf("phase 2 has %d tasks, want 3", len(phase2))
+	}
+	if phase2[0].Status != "Completed" {
+		t.Errorf("T002 status = %q, want Completed", phase2[0].Status)
+	}
+	if phase2[1].Status != "InProgress" || len(phase2[1].StatusHistory) != 1 {
+		t.Errorf("T003 should keep its status and history, got %q with %d entries", phase2[1].Status, len(phase2[1].StatusHistory))
+	}
+	if phase2[1].Title != "Add routes with versioning" {
+		t.Errorf("T003 title = %q, want the regenerated title", phase2[1].Title)
+	}
+}
+
+func TestMergeRegeneratedPhase_Conflicts(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		tasks string
+		want  string
+	}{
+		"completed task redefined": {
+			tasks: `      - id: T002
+        title: Add a different handler
+        dependencies: [T001]
+        acceptance_criteria: [Handler responds]
+`,
+			want: "T002 is Completed but the regenerated phase redefines it",
+		},
+		"completed task removed": {
+			tasks: `      - id: T010
+        title: Something else
+`,
+			want: "T002 is Completed but the regenerated phase removes it",
+		},
+		"id used by another phase": {
+			tasks: regenKeptT002 + `      - id: T001
+        title: Clash
+`,
+			want: "T001 is already used by a task in phase 1",
+		},
+		"duplicate id": {
+			tasks: regenKeptT002 + `      - id: T006
+        title: One
+      - id: T006
+        title: Two
+`,
+			want: "T006 appears more than once",
+		},
+		"unknown dependency": {
+			tasks: regenKeptT002 + `      - id: T006
+        title: One
+        dependencies: [T099]
+`,
+			want: "T006 depends on T099, which does not exist",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			merged, result, err := MergeRegeneratedPhase([]byte(regenOriginalYAML), regenPhase2(tt.tasks), 2)
+			if err != nil {
+				t.Fatalf("MergeRegeneratedPhase() error: %v", err)
+			}
+			if merged != nil {
+				t.Error("conflicting merge should not return output")
+			}
+			if !strings.Contains(strings.Join(result.Conflicts, "\n"), tt.want) {
+				t.Errorf("Conflicts = %q, want one containing %q", result.Conflicts, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeRegeneratedPhase_MissingPhase(t *testing.T) {
+	t.Parallel()
+
+	if _, _, err := MergeRegeneratedPhase([]byte(regenOriginalYAML), regenPhase2(regenKeptT002), 7); err == nil ||
+		!strings.Contains(err.Error(), "phase 7 not found in tasks.yaml") {
+		t.Errorf("error = %v, want phase 7 not found", err)
+	}
+	onlyPhase1 := []byte("phases:\n  - number: 1\n    tasks: []\n")
+	if _, _, err := MergeRegeneratedPhase([]byte(regenOriginalYAML), onlyPhase1, 2); err == nil ||
+		!strings.Contains(err.Error(), "not found in regenerated tasks") {
+		t.Errorf("error = %v, want phase 2 missing from regenerated tasks", err)
+	}
+}
+
+func TestMergeRegeneratedPhase_RemovedDependency(t *testing.T) {
+	t.Parallel()
+
+	original := regenOriginalYAML + `  - number: 3
+    title: "Polish"
+    tasks:
+      - id: "T005"
+        title: "Link docs from the README"
+        status: "Pending"
+        dependencies: ["T004"]
+`
+	// T003 and T004 are dropped; only T004 has a dependent outside phase 2
+	merged, result, err := MergeRegeneratedPhase([]byte(original), regenPhase2(regenKeptT002), 2)
+	if err != nil {
+		t.Fatalf("MergeRegeneratedPhase() error: %v", err)
+	}
+	if merged != nil {
+		t.Error("conflicting merge should not return output")
+	}
+	want := []string{"T005 in phase 3 depends on T004, which the regenerated phase removes"}
+	if strings.Join(result.Conflicts, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Conflicts = %q, want %q", result.Conflicts, want)
+	}
+}
@@ -3,8 +3,13 @@ package validation
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/fuzzy"
 	"gopkg.in/yaml.v3"
 )
 
@@ -52,17 +57,82 @@ type TaskPhase struct {
 
 // TaskItem represents an individual task
 type TaskItem struct {
-	ID                 string   `yaml:"id"`
-	Title              string   `yaml:"title"`
-	Status             string   `yaml:"status"`
-	Type               string   `yaml:"type"`
-	Parallel           bool     `yaml:"parallel"`
-	StoryID            string   `yaml:"story_id,omitempty"`
-	FilePath           string   `yaml:"file_path,omitempty"`
-	Dependencies       []string `yaml:"dependencies"`
-	AcceptanceCriteria []string `yaml:"acceptance_criteria"`
-	BlockedReason      string   `yaml:"blocked_reason,omitempty"`
-	Notes              string   `yaml:"notes,omitempty"`
+	ID                 string             `yaml:"id"`
+	Title              string             `yaml:"title"`
+	Status             string             `yaml:"status"`
+	Type               string             `yaml:"type"`
+	Parallel           bool               `yaml:"parallel"`
+	StoryID            string             `yaml:"story_id,omitempty"`
+	FilePath           string             `yaml:"file_path,omitempty"`
+	Dependencies       []string           `yaml:"dependencies"`
+	AcceptanceCriteria []string           `yaml:"acceptance_criteria"`
+	BlockedReason      string             `yaml:"blocked_reason,omitempty"`
+	Notes              string             `yaml:"notes,omitempty"`
+	MaxDuration        string             `yaml:"max_duration,omitempty"`
+	EstimatedDuration  string             `yaml:"estimated_duration,omitempty"`
+	ActualDuration     string             `yaml:"actual_duration,omitempty"`
+	ChangedFiles       []string           `yaml:"changed_files,omitempty"`
+	StatusHistory      []TaskStatusChange `yaml:"status_history,omitempty"`
+}
+
+// TaskStatusChange records a single status transition in a task's status_history.
+// Entries with Manual set were written by `autospec task set-status`.
+type TaskStatusChange struct {
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	By     string `yaml:"by"`
+	At     string `yaml:"at"`
+	Reason string `yaml:"reason"`
+	Manual bool   `yaml:"manual"`
+}
+
+// TimeLimit parses the task's max_duration (e.g. "30m"). It returns 0 when
+// the task sets no limit.
+func (t *TaskItem) TimeLimit() (time.Duration, error) {
+	if t.MaxDuration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(t.MaxDuration)
+	if err != nil {
+		return 0, fmt.Errorf("task %s: invalid max_duration %q: %w", t.ID, t.MaxDuration, err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("task %s: max_duration must be positive, got %q", t.ID, t.MaxDuration)
+	}
+	return d, nil
+}
+
+// Estimate returns the task's estimated_duration, or 0 when it is unset or
+// invalid (the tasks validator reports invalid values).
+func (t *TaskItem) Estimate() time.Duration {
+	return parsePositiveDuration(t.EstimatedDuration)
+}
+
+// Actual returns the agent time recorded in actual_duration, or 0 when none
+// has been recorded.
+func (t *TaskItem) Actual() time.Duration {
+	return parsePositiveDuration(t.ActualDuration)
+}
+
+func parsePositiveDuration(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
+// IsManualOverride returns true if the task's current status was set manually,
+// i.e. the most recent status_history entry is manual and matches the current status.
+func (t *TaskItem) IsManualOverride() bool {
+	if len(t.StatusHistory) == 0 {
+		return false
+	}
+	last := t.StatusHistory[len(t.StatusHistory)-1]
+	return last.Manual && last.To == t.Status
 }
 
 // TaskStats contains computed statistics about task completion
@@ -75,6 +145,49 @@ type TaskStats struct {
 	TotalPhases     int
 	CompletedPhases int
 	PhaseStats      []PhaseStats
+	Time            TimeStats
+}
+
+// TimeStats compares estimated and actual task durations.
+type TimeStats struct {
+	EstimatedTasks int           // Tasks with an estimated_duration
+	Estimated      time.Duration // Sum of all estimates
+	TrackedTasks   int           // Tasks with a recorded actual_duration
+	Actual         time.Duration // Sum of all recorded actual durations
+
+	// Completed tasks with both an estimate and an actual duration, which
+	// are the ones estimates can be calibrated against
+	ComparedTasks    int
+	ComparedEstimate time.Duration
+	ComparedActual   time.Duration
+}
+
+// Ratio returns actual time divided by estimated time over the compared
+// tasks: 1.5 means tasks took 50% longer than estimated. Returns 0 when no
+// task has both.
+func (t TimeStats) Ratio() float64 {
+	if t.ComparedEstimate <= 0 {
+		return 0
+	}
+	return float64(t.ComparedActual) / float64(t.ComparedEstimate)
+}
+
+// add accounts for one task's durations.
+func (t *TimeStats) add(task TaskItem, completed bool) {
+	estimate, actual := task.Estimate(), task.Actual()
+	if estimate > 0 {
+		t.EstimatedTasks++
+		t.Estimated += estimate
+	}
+	if actual > 0 {
+		t.TrackedTasks++
+		t.Actual += actual
+	}
+	if completed && estimate > 0 && actual > 0 {
+		t.ComparedTasks++
+		t.ComparedEstimate += estimate
+		t.ComparedActual += actual
+	}
 }
 
 // PhaseStats contains statistics for a single phase
@@ -147,7 +260,9 @@ func GetTaskStats(tasksPath string) (*TaskStats, error) {
 		for _, task := range phase.Tasks {
 			stats.TotalTasks++
 
-			switch strings.ToLower(task.Status) {
+			status := strings.ToLower(task.Status)
+			stats.Time.add(task, status == "completed" || status == "done" || status == "complete")
+			switch status {
 			case "completed", "done", "complete":
 				stats.CompletedTasks++
 				phaseStat.CompletedTasks++
@@ -366,6 +481,41 @@ func GetTaskByID(tasks []TaskItem, id string) (*TaskItem, error) {
 	return nil, fmt.Errorf("task %s not found", id)
 }
 
+// ResolveTaskID resolves a task reference to one of ids. The reference is
+// either an exact ID ("T004") or a task number with or without the T prefix
+// ("t4", "4"), so "4" finds T004. When nothing matches, the error suggests
+// close IDs ("did you mean T014?").
+func ResolveTaskID(ids []string, ref string) (string, error) {
+	for _, id := range ids {
+		if id == ref {
+			return id, nil
+		}
+	}
+
+	if n, ok := taskNumber(ref); ok {
+		for _, id := range ids {
+			if idNum, ok := taskNumber(id); ok && idNum == n {
+				return id, nil
+			}
+		}
+	}
+
+	if hint := fuzzy.DidYouMean(fuzzy.Suggest(ref, ids)); hint != "" {
+		return "", fmt.Errorf("task %s not found in tasks.yaml (%s)", ref, hint)
+	}
+	return "", fmt.Errorf("task %s not found in tasks.yaml", ref)
+}
+
+// taskNumber parses the number of a task reference like "T004", "t4" or "4".
+func taskNumber(ref string) (int, bool) {
+	digits := strings.TrimPrefix(strings.TrimPrefix(ref, "T"), "t")
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(digits)
+	return n, err == nil
+}
+
 // GetTasksInDependencyOrder returns tasks sorted by dependency order (topological sort)
 // Tasks with no dependencies come first, followed by tasks whose dependencies are satisfied
 // Returns an error if a circular dependency is detected
@@ -482,5 +632,263 @@ func FormatTaskSummary(stats *TaskStats) string {
 		sb.WriteString(fmt.Sprintf("  (%s)\n", strings.Join(parts, ", ")))
 	}
 
+	sb.WriteString(formatTimeSummary(stats.Time))
 	return sb.String()
 }
+
+// formatTimeSummary renders estimated vs actual task time, or "" when no
+// task has either.
+func formatTimeSummary(t TimeStats) string {
+	if t.EstimatedTasks == 0 && t.TrackedTasks == 0 {
+		return ""
+	}
+	var parts []string
+	if t.TrackedTasks > 0 {
+		parts = append(parts, fmt.Sprintf("%s actual (%d task(s))", t.Actual.Round(time.Second), t.TrackedTasks))
+	}
+	if t.EstimatedTasks > 0 {
+		parts = append(parts, fmt.Sprintf("%s estimated (%d task(s))", t.Estimated.Round(time.Second), t.EstimatedTasks))
+	}
+	line := "  Time: " + strings.Join(parts, ", ") + "\n"
+	if t.ComparedTasks > 0 {
+		line += fmt.Sprintf("  Completed tasks took %.2fx their estimates (%s vs %s over %d task(s))\n",
+			t.Ratio(), t.ComparedActual.Round(time.Second), t.ComparedEstimate.Round(time.Second), t.ComparedTasks)
+	}
+	return line
+}
+
+// UpdateTaskStatus sets the status of the task with the given ID in tasks.yaml,
+// preserving the rest of the document structure. Returns the previous status.
+func UpdateTaskStatus(tasksPath, taskID, newStatus string) (string, error) {
+	data, err := os.ReadFile(tasksPath)
+	if err != nil {
+		return "", fmt.Errorf("reading tasks file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return "", fmt.Errorf("parsing tasks YAML: %w", err)
+	}
+
+	taskNode := FindTaskNode(&root, taskID)
+	if taskNode == nil {
+		return "", fmt.Errorf("task %s not found in %s", taskID, tasksPath)
+	}
+	statusNode := findNode(taskNode, "status")
+	previous := statusNode.Value
+	statusNode.Value = newStatus
+
+	output, err := yaml.Marshal(&root)
+	if err != nil {
+		return "", fmt.Errorf("serializing tasks YAML: %w", err)
+	}
+	if err := fsutil.WriteArtifact(tasksPath, output, 0o644); err != nil {
+		return "", fmt.Errorf("writing tasks file: %w", err)
+	}
+	return previous, nil
+}
+
+// BlockTask marks the task with the given ID as Blocked on autospec's behalf,
+// setting blocked_reason and appending a non-manual status_history entry.
+// Returns the previous status.
+func BlockTask(tasksPath, taskID, reason string) (string, error) {
+	data, err := os.ReadFile(tasksPath)
+	if err != nil {
+		return "", fmt.Errorf("reading tasks file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return "", fmt.Errorf("parsing tasks YAML: %w", err)
+	}
+
+	taskNode := FindTaskNode(&root, taskID)
+	if taskNode == nil {
+		return "", fmt.Errorf("task %s not found in %s", taskID, tasksPath)
+	}
+	statusNode := findNode(taskNode, "status")
+	previous := statusNode.Value
+	statusNode.Value = "Blocked"
+	SetMappingValue(taskNode, "blocked_reason", strNode(reason), "status")
+	appendAutoStatusChange(taskNode, TaskStatusChange{
+		From:   previous,
+		To:     "Blocked",
+		By:     "autospec",
+		At:     time.Now().Format(time.RFC3339),
+		Reason: reason,
+	})
+
+	output, err := yaml.Marshal(&root)
+	if err != nil {
+		return "", fmt.Errorf("serializing tasks YAML: %w", err)
+	}
+	if err := fsutil.WriteArtifact(tasksPath, output, 0o644); err != nil {
+		return "", fmt.Errorf("writing tasks file: %w", err)
+	}
+	return previous, nil
+}
+
+// AddTaskActualDuration adds d, rounded to seconds, to the actual_duration of
+// the task with the given ID, so time spent across several runs of a task
+// accumulates. Returns the new total; the file is left untouched when d
+// rounds to zero.
+func AddTaskActualDuration(tasksPath, taskID string, d time.Duration) (time.Duration, error) {
+	data, err := os.ReadFile(tasksPath)
+	if err != nil {
+		return 0, fmt.Errorf("reading tasks file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return 0, fmt.Errorf("parsing tasks YAML: %w", err)
+	}
+
+	taskNode := FindTaskNode(&root, taskID)
+	if taskNode == nil {
+		return 0, fmt.Errorf("task %s not found in %s", taskID, tasksPath)
+	}
+	var total time.Duration
+	if existing := findNode(taskNode, "actual_duration"); existing != nil {
+		total = parsePositiveDuration(existing.Value)
+	}
+	if d.Round(time.Second) <= 0 {
+		return total, nil
+	}
+	total += d.Round(time.Second)
+	after := "status"
+	if findNode(taskNode, "estimated_duration") != nil {
+		after = "estimated_duration"
+	}
+	SetMappingValue(taskNode, "actual_duration", strNode(total.String()), after)
+
+	output, err := yaml.Marshal(&root)
+	if err != nil {
+		return 0, fmt.Errorf("serializing tasks YAML: %w", err)
+	}
+	if err := fsutil.WriteArtifact(tasksPath, output, 0o644); err != nil {
+		return 0, fmt.Errorf("writing tasks file: %w", err)
+	}
+	return total, nil
+}
+
+// AddTaskChangedFiles adds files to the changed_files of the task with the
+// given ID, keeping the list sorted and free of duplicates, so files touched
+// across several runs of a task accumulate. Returns the new list; the file is
+// left untouched when nothing is added.
+func AddTaskChangedFiles(tasksPath, taskID string, files []string) ([]string, error) {
+	data, err := os.ReadFile(tasksPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading tasks file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing tasks YAML: %w", err)
+	}
+
+	taskNode := FindTaskNode(&root, taskID)
+	if taskNode == nil {
+		return nil, fmt.Errorf("task %s not found in %s", taskID, tasksPath)
+	}
+	seen := make(map[string]bool)
+	var merged []string
+	existing := findNode(taskNode, "changed_files")
+	if existing != nil {
+		for _, item := range existing.Content {
+			if item.Kind == yaml.ScalarNode && !seen[item.Value] {
+				seen[item.Value] = true
+				merged = append(merged, item.Value)
+			}
+		}
+	}
+	added := false
+	for _, f := range files {
+		if f != "" && !seen[f] {
+			seen[f] = true
+			merged = append(merged, f)
+			added = true
+		}
+	}
+	if !added {
+		return merged, nil
+	}
+	sort.Strings(merged)
+
+	list := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	for _, f := range merged {
+		list.Content = append(list.Content, strNode(f))
+	}
+	after := "status"
+	if findNode(taskNode, "actual_duration") != nil {
+		after = "actual_duration"
+	}
+	SetMappingValue(taskNode, "changed_files", list, after)
+
+	output, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, fmt.Errorf("serializing tasks YAML: %w", err)
+	}
+	if err := fsutil.WriteArtifact(tasksPath, output, 0o644); err != nil {
+		return nil, fmt.Errorf("writing tasks file: %w", err)
+	}
+	return merged, nil
+}
+
+// FindTaskNode returns the mapping node of the task with the given ID in a
+// parsed tasks.yaml document, or nil if there is no such task.
+func FindTaskNode(node *yaml.Node, taskID string) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Kind == yaml.MappingNode {
+		if idNode := findNode(node, "id"); idNode != nil && idNode.Value == taskID && findNode(node, "status") != nil {
+			return node
+		}
+	}
+	for _, child := range node.Content {
+		if found := FindTaskNode(child, taskID); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// SetMappingValue sets key in a mapping node to value. A missing key is
+// inserted right after the after key, or appended when after is empty or
+// not present.
+func SetMappingValue(node *yaml.Node, key string, value *yaml.Node, after string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = value
+			return
+		}
+	}
+	insertIdx := len(node.Content)
+	for i := 0; after != "" && i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == after {
+			insertIdx = i + 2
+			break
+		}
+	}
+	pair := []*yaml.Node{{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value}
+	node.Content = append(node.Content[:insertIdx], append(pair, node.Content[insertIdx:]...)...)
+}
+
+// strNode returns a string scalar node.
+func strNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+// appendAutoStatusChange appends an entry to the task's status_history,
+// creating the block if needed.
+func appendAutoStatusChange(taskNode *yaml.Node, change TaskStatusChange) {
+	var entry yaml.Node
+	if err := entry.Encode(change); err != nil {
+		return
+	}
+	if historyNode := findNode(taskNode, "status_history"); historyNode != nil && historyNode.Kind == yaml.SequenceNode {
+		historyNode.Content = append(historyNode.Content, &entry)
+		return
+	}
+	SetMappingValue(taskNode, "status_history", &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{&entry}}, "")
+}
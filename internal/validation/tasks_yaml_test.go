@@ -3,10 +3,13 @@ package validation
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestParseTasksYAML(t *testing.T) {
@@ -312,6 +315,43 @@ func TestTaskStats_CompletionPercentage(t *testing.T) {
 	}
 }
 
+func TestTaskItem_IsManualOverride(t *testing.T) {
+	tests := map[string]struct {
+		task TaskItem
+		want bool
+	}{
+		"no history": {
+			task: TaskItem{Status: "Completed"},
+			want: false,
+		},
+		"last entry manual and matches status": {
+			task: TaskItem{Status: "Completed", StatusHistory: []TaskStatusChange{
+				{From: "Pending", To: "Completed", Manual: true},
+			}},
+			want: true,
+		},
+		"status changed after manual override": {
+			task: TaskItem{Status: "InProgress", StatusHistory: []TaskStatusChange{
+				{From: "Pending", To: "Completed", Manual: true},
+			}},
+			want: false,
+		},
+		"last entry not manual": {
+			task: TaskItem{Status: "Completed", StatusHistory: []TaskStatusChange{
+				{From: "Pending", To: "Completed", Manual: false},
+			}},
+			want: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, tc.task.IsManualOverride())
+		})
+	}
+}
+
 func TestTaskStats_IsComplete(t *testing.T) {
 	tests := map[string]struct {
 		stats *TaskStats
@@ -450,6 +490,41 @@ func TestFormatTaskSummary(t *testing.T) {
 				"1 blocked",
 			},
 		},
+		"without durations": {
+			stats: &TaskStats{TotalTasks: 2, TotalPhases: 1},
+			wantNotContains: []string{
+				"Time:",
+			},
+		},
+		"with estimated and actual time": {
+			stats: &TaskStats{
+				TotalTasks:     4,
+				CompletedTasks: 2,
+				TotalPhases:    1,
+				Time: TimeStats{
+					EstimatedTasks:   4,
+					Estimated:        80 * time.Minute,
+					TrackedTasks:     2,
+					Actual:           75 * time.Minute,
+					ComparedTasks:    2,
+					ComparedEstimate: 50 * time.Minute,
+					ComparedActual:   75 * time.Minute,
+				},
+			},
+			wantContains: []string{
+				"Time: 1h15m0s actual (2 task(s)), 1h20m0s estimated (4 task(s))",
+				"Completed tasks took 1.50x their estimates (1h15m0s vs 50m0s over 2 task(s))",
+			},
+		},
+		"estimates only": {
+			stats: &TaskStats{
+				TotalTasks:  1,
+				TotalPhases: 1,
+				Time:        TimeStats{EstimatedTasks: 1, Estimated: 20 * time.Minute},
+			},
+			wantContains:    []string{"Time: 20m0s estimated (1 task(s))"},
+			wantNotContains: []string{"actual", "took"},
+		},
 	}
 
 	for name, tc := range tests {
@@ -1127,6 +1202,46 @@ func TestGetTaskByID(t *testing.T) {
 	}
 }
 
+func TestResolveTaskID(t *testing.T) {
+	t.Parallel()
+
+	ids := []string{"T001", "T004", "T014"}
+
+	tests := map[string]struct {
+		ref      string
+		want     string
+		wantErr  string
+		wantHint string
+	}{
+		"exact id":             {ref: "T004", want: "T004"},
+		"bare number":          {ref: "4", want: "T004"},
+		"lowercase short form": {ref: "t14", want: "T014"},
+		"padded number":        {ref: "0001", want: "T001"},
+		"typo suggests":        {ref: "T015", wantErr: "task T015 not found", wantHint: "did you mean T014?"},
+		"no close match":       {ref: "T999", wantErr: "task T999 not found"},
+		"not a task reference": {ref: "setup", wantErr: "task setup not found"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ResolveTaskID(ids, tc.ref)
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.wantErr)
+				if tc.wantHint != "" {
+					assert.Contains(t, err.Error(), tc.wantHint)
+				} else {
+					assert.NotContains(t, err.Error(), "did you mean")
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
 func TestGetTasksInDependencyOrder(t *testing.T) {
 	tests := map[string]struct {
 		tasks       []TaskItem
@@ -1675,3 +1790,301 @@ func TestValidateTaskDependenciesMet(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskItem_TimeLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		maxDuration string
+		want        time.Duration
+		wantErr     bool
+	}{
+		"no limit": {maxDuration: "", want: 0},
+		"minutes":  {maxDuration: "30m", want: 30 * time.Minute},
+		"compound": {maxDuration: "1h30m", want: 90 * time.Minute},
+		"invalid":  {maxDuration: "half an hour", wantErr: true},
+		"zero":     {maxDuration: "0s", wantErr: true},
+		"negative": {maxDuration: "-5m", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			task := TaskItem{ID: "T001", MaxDuration: tc.maxDuration}
+			got, err := task.TimeLimit()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestGetTaskStats_Time(t *testing.T) {
+	t.Parallel()
+
+	tasksPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: Phase 1
+    tasks:
+      - id: T001
+        title: Done on time
+        status: Completed
+        type: implementation
+        estimated_duration: 20m
+        actual_duration: 30m
+      - id: T002
+        title: Blocked midway
+        status: Blocked
+        type: implementation
+        estimated_duration: 10m
+        actual_duration: 25m
+      - id: T003
+        title: Not started
+        status: Pending
+        type: implementation
+        estimated_duration: 1h
+      - id: T004
+        title: Unestimated
+        status: Completed
+        type: implementation
+        actual_duration: 5m
+`
+	require.NoError(t, os.WriteFile(tasksPath, []byte(content), 0o644))
+
+	stats, err := GetTaskStats(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, TimeStats{
+		EstimatedTasks:   3,
+		Estimated:        90 * time.Minute,
+		TrackedTasks:     3,
+		Actual:           60 * time.Minute,
+		ComparedTasks:    1,
+		ComparedEstimate: 20 * time.Minute,
+		ComparedActual:   30 * time.Minute,
+	}, stats.Time)
+	assert.InDelta(t, 1.5, stats.Time.Ratio(), 0.001)
+	assert.Zero(t, TimeStats{}.Ratio())
+}
+
+func TestTaskItem_EstimateAndActual(t *testing.T) {
+	t.Parallel()
+
+	task := TaskItem{EstimatedDuration: "45m", ActualDuration: "1h2m3s"}
+	assert.Equal(t, 45*time.Minute, task.Estimate())
+	assert.Equal(t, time.Hour+2*time.Minute+3*time.Second, task.Actual())
+
+	invalid := TaskItem{EstimatedDuration: "a while", ActualDuration: "-5m"}
+	assert.Zero(t, invalid.Estimate())
+	assert.Zero(t, invalid.Actual())
+}
+
+func TestAddTaskActualDuration(t *testing.T) {
+	t.Parallel()
+
+	tasksPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: Phase 1
+    tasks:
+      - id: T001
+        title: Estimated task
+        status: Completed
+        type: implementation
+        estimated_duration: 30m
+        dependencies: []
+      - id: T002
+        title: Other task
+        status: Pending
+        type: implementation
+`
+	require.NoError(t, os.WriteFile(tasksPath, []byte(content), 0o644))
+
+	total, err := AddTaskActualDuration(tasksPath, "T001", 10*time.Minute+400*time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, total)
+
+	// A second run of the same task adds to the recorded time
+	total, err = AddTaskActualDuration(tasksPath, "T001", 5*time.Minute+30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute+30*time.Second, total)
+
+	tasks, err := GetAllTasks(tasksPath)
+	require.NoError(t, err)
+	task, err := GetTaskByID(tasks, "T001")
+	require.NoError(t, err)
+	assert.Equal(t, "15m30s", task.ActualDuration)
+	other, err := GetTaskByID(tasks, "T002")
+	require.NoError(t, err)
+	assert.Empty(t, other.ActualDuration)
+
+	data, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(string(data), "estimated_duration:"), strings.Index(string(data), "actual_duration:"),
+		"actual_duration is placed next to the estimate")
+
+	// Sub-second sessions leave the recorded time alone
+	total, err = AddTaskActualDuration(tasksPath, "T002", 300*time.Millisecond)
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	after, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(after))
+
+	_, err = AddTaskActualDuration(tasksPath, "T999", time.Minute)
+	assert.Error(t, err)
+}
+
+func TestAddTaskChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	tasksPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: Phase 1
+    tasks:
+      - id: T001
+        title: Build API
+        status: Completed
+        type: implementation
+        actual_duration: 10m0s
+        dependencies: []
+      - id: T002
+        title: Other task
+        status: Pending
+        type: implementation
+`
+	require.NoError(t, os.WriteFile(tasksPath, []byte(content), 0o644))
+
+	files, err := AddTaskChangedFiles(tasksPath, "T001", []string{"pkg/api.go", "main.go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.go", "pkg/api.go"}, files)
+
+	// A second run of the same task adds to the recorded files
+	files, err = AddTaskChangedFiles(tasksPath, "T001", []string{"pkg/api_test.go", "main.go"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"main.go", "pkg/api.go", "pkg/api_test.go"}, files)
+
+	tasks, err := GetAllTasks(tasksPath)
+	require.NoError(t, err)
+	task, err := GetTaskByID(tasks, "T001")
+	require.NoError(t, err)
+	assert.Equal(t, files, task.ChangedFiles)
+	other, err := GetTaskByID(tasks, "T002")
+	require.NoError(t, err)
+	assert.Empty(t, other.ChangedFiles)
+
+	data, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(string(data), "actual_duration:"), strings.Index(string(data), "changed_files:"),
+		"changed_files is placed after actual_duration")
+
+	// Nothing new leaves the file alone
+	_, err = AddTaskChangedFiles(tasksPath, "T001", []string{"main.go"})
+	require.NoError(t, err)
+	after, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), string(after))
+
+	_, err = AddTaskChangedFiles(tasksPath, "T999", []string{"main.go"})
+	assert.Error(t, err)
+}
+
+func TestBlockTask(t *testing.T) {
+	t.Parallel()
+
+	tasksPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: Phase 1
+    tasks:
+      - id: T001
+        title: Slow task
+        status: InProgress
+        type: implementation
+        max_duration: 30m
+      - id: T002
+        title: Other task
+        status: Pending
+        type: implementation
+`
+	require.NoError(t, os.WriteFile(tasksPath, []byte(content), 0o644))
+
+	previous, err := BlockTask(tasksPath, "T001", "timed out after 30m")
+	require.NoError(t, err)
+	assert.Equal(t, "InProgress", previous)
+
+	tasks, err := GetAllTasks(tasksPath)
+	require.NoError(t, err)
+	blocked, err := GetTaskByID(tasks, "T001")
+	require.NoError(t, err)
+	assert.Equal(t, "Blocked", blocked.Status)
+	assert.Equal(t, "timed out after 30m", blocked.BlockedReason)
+	assert.Equal(t, "30m", blocked.MaxDuration)
+	require.Len(t, blocked.StatusHistory, 1)
+	assert.Equal(t, "InProgress", blocked.StatusHistory[0].From)
+	assert.Equal(t, "Blocked", blocked.StatusHistory[0].To)
+	assert.Equal(t, "autospec", blocked.StatusHistory[0].By)
+	assert.False(t, blocked.StatusHistory[0].Manual)
+	assert.False(t, blocked.IsManualOverride())
+
+	other, err := GetTaskByID(tasks, "T002")
+	require.NoError(t, err)
+	assert.Equal(t, "Pending", other.Status)
+
+	data, err := os.ReadFile(tasksPath)
+	require.NoError(t, err)
+	assert.Less(t, strings.Index(string(data), "status: Blocked"), strings.Index(string(data), "blocked_reason:"))
+
+	_, err = BlockTask(tasksPath, "T999", "timed out")
+	assert.Error(t, err)
+}
+
+func TestFindTaskNode(t *testing.T) {
+	t.Parallel()
+
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`phases:
+  - number: 1
+    tasks:
+      - id: T001
+        status: Pending
+      - id: T002
+        status: Completed
+`), &root))
+
+	task := FindTaskNode(&root, "T002")
+	require.NotNil(t, task)
+	assert.Equal(t, "Completed", findNode(task, "status").Value)
+	assert.Nil(t, FindTaskNode(&root, "T999"))
+}
+
+func TestSetMappingValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		key   string
+		after string
+		want  string
+	}{
+		"replaces existing key":        {key: "status", after: "id", want: "id: T001\nstatus: x\nnotes: n\n"},
+		"inserts after key":            {key: "reason", after: "status", want: "id: T001\nstatus: Pending\nreason: x\nnotes: n\n"},
+		"appends when after is empty":  {key: "reason", want: "id: T001\nstatus: Pending\nnotes: n\nreason: x\n"},
+		"appends when after is absent": {key: "reason", after: "missing", want: "id: T001\nstatus: Pending\nnotes: n\nreason: x\n"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var root yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte("id: T001\nstatus: Pending\nnotes: n\n"), &root))
+
+			SetMappingValue(root.Content[0], tt.key, strNode("x"), tt.after)
+			out, err := yaml.Marshal(&root)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(out))
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package validation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	autospecyaml "github.com/ariel-frischer/autospec/internal/yaml"
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentArtifactVersion is the _meta.version of spec, plan and tasks artifacts
+// written by this version of autospec. Artifacts without a version are 1.0.0.
+const CurrentArtifactVersion = "1.1.0"
+
+// baseArtifactVersion is assumed for artifacts without _meta.version.
+const baseArtifactVersion = "1.0.0"
+
+// artifactMigration upgrades an artifact from one schema version to the next.
+// apply edits the root mapping node in place and returns a description of
+// each change it made.
+type artifactMigration struct {
+	to    string
+	apply func(root *yaml.Node, artifactType ArtifactType) []string
+}
+
+// artifactMigrations lists the migrations in version order. An artifact gets
+// every migration whose target version is newer than its own.
+var artifactMigrations = []artifactMigration{
+	// 1.1.0: enum fields use the exact spellings the validators require
+	{to: "1.1.0", apply: normalizeEnumFields},
+}
+
+// UpgradableArtifacts are the artifact files upgraded by UpgradeSpecDir.
+var UpgradableArtifacts = []ArtifactType{ArtifactTypeSpec, ArtifactTypePlan, ArtifactTypeTasks}
+
+// ArtifactUpgrade describes the outcome of upgrading one artifact file.
+type ArtifactUpgrade struct {
+	Path        string       // Path to the artifact file
+	Type        ArtifactType // Artifact type
+	FromVersion string       // _meta.version found in the file
+	ToVersion   string       // _meta.version after the upgrade
+	Changes     []string     // Fields that were rewritten
+	BackupPath  string       // Copy of the original file (empty for dry runs or no-ops)
+	DryRun      bool
+}
+
+// NeedsUpgrade returns true if the artifact is older than CurrentArtifactVersion.
+func (u *ArtifactUpgrade) NeedsUpgrade() bool {
+	return u.FromVersion != u.ToVersion
+}
+
+// UpgradeSpecDir upgrades the spec, plan and tasks artifacts in a spec directory.
+// Missing artifacts are skipped. Stops at the first artifact that cannot be upgraded.
+func UpgradeSpecDir(specDir string, dryRun bool) ([]*ArtifactUpgrade, error) {
+	var upgrades []*ArtifactUpgrade
+	for _, artifactType := range UpgradableArtifacts {
+		path := filepath.Join(specDir, string(artifactType)+".yaml")
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			continue
+		}
+		upgrade, err := UpgradeArtifact(path, artifactType, dryRun)
+		if err != nil {
+			return upgrades, err
+		}
+		upgrades = append(upgrades, upgrade)
+	}
+	return upgrades, nil
+}
+
+// UpgradeArtifact upgrades an artifact file to CurrentArtifactVersion in place.
+// The original file is copied to <path>.v<version>.bak before it is rewritten.
+// With dryRun, nothing is written.
+//
+// Upgrade pipeline:
+//  1. Parse YAML into AST → 2. Read _meta.version (add _meta if missing)
+//  3. Apply each newer migration → 4. Stamp _meta.version
+//  5. Back up the original and write the upgraded file
+func UpgradeArtifact(path string, artifactType ArtifactType, dryRun bool) (*ArtifactUpgrade, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading artifact: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	rootMapping := getRootMapping(&root)
+	if rootMapping == nil {
+		return nil, fmt.Errorf("parsing %s: file is not a YAML mapping", path)
+	}
+
+	upgrade := &ArtifactUpgrade{
+		Path:      path,
+		Type:      artifactType,
+		ToVersion: CurrentArtifactVersion,
+		DryRun:    dryRun,
+	}
+	version, err := artifactVersion(rootMapping)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	upgrade.FromVersion = version.String()
+
+	if !upgrade.NeedsUpgrade() {
+		return upgrade, nil
+	}
+
+	if fix := addMetaSection(rootMapping, artifactType); fix != nil {
+		upgrade.Changes = append(upgrade.Changes, "_meta: added")
+	}
+	for _, m := range artifactMigrations {
+		target, _ := autospecyaml.ParseVersion(m.to)
+		if version.Compare(target) < 0 {
+			upgrade.Changes = append(upgrade.Changes, m.apply(rootMapping, artifactType)...)
+		}
+	}
+	setMetaVersion(rootMapping, CurrentArtifactVersion)
+
+	if dryRun {
+		return upgrade, nil
+	}
+
+	output, err := yaml.Marshal(&root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize YAML: %w", err)
+	}
+	backupPath := fmt.Sprintf("%s.v%s.bak", path, upgrade.FromVersion)
+	if err := os.WriteFile(backupPath, data, 0o644); err != nil {
+		return nil, fmt.Errorf("backing up %s: %w", path, err)
+	}
+	if err := os.WriteFile(path, output, 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write file: %w", err)
+	}
+	upgrade.BackupPath = backupPath
+	return upgrade, nil
+}
+
+// artifactVersion returns the _meta.version of an artifact.
+// Artifacts newer than CurrentArtifactVersion are rejected.
+func artifactVersion(root *yaml.Node) (autospecyaml.Version, error) {
+	current, _ := autospecyaml.ParseVersion(CurrentArtifactVersion)
+
+	raw := baseArtifactVersion
+	if versionNode := findNode(findNode(root, "_meta"), "version"); versionNode != nil && versionNode.Value != "" {
+		raw = versionNode.Value
+	}
+	version, err := autospecyaml.ParseVersion(raw)
+	if err != nil {
+		return autospecyaml.Version{}, fmt.Errorf("_meta.version: %w", err)
+	}
+	if version.Compare(current) > 0 {
+		return autospecyaml.Version{}, fmt.Errorf(
+			"_meta.version %s is newer than this autospec supports (%s); upgrade autospec", raw, CurrentArtifactVersion)
+	}
+	return version, nil
+}
+
+// setMetaVersion sets _meta.version, adding the key if _meta has none.
+func setMetaVersion(root *yaml.Node, version string) {
+	meta := findNode(root, "_meta")
+	if meta == nil || meta.Kind != yaml.MappingNode {
+		return
+	}
+	if versionNode := findNode(meta, "version"); versionNode != nil {
+		versionNode.Value = version
+		versionNode.Style = yaml.DoubleQuotedStyle
+		return
+	}
+	meta.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "version"},
+		{Kind: yaml.ScalarNode, Value: version, Style: yaml.DoubleQuotedStyle},
+	}, meta.Content...)
+}
+
+// enumField is an enum-valued field, located by a path in which "[]" marks
+// a sequence (e.g., "phases[].tasks[].status").
+type enumField struct {
+	path    string
+	allowed []string
+	aliases map[string]string // Normalized legacy spelling -> allowed value
+}
+
+var taskStatusAliases = map[string]string{"done": "Completed", "complete": "Completed", "todo": "Pending"}
+
+// enumFields lists the enum fields normalized for each artifact type.
+var enumFields = map[ArtifactType][]enumField{
+	ArtifactTypeSpec: {
+		{path: "feature.status", allowed: []string{"Draft", "Review", "Approved", "Completed"}},
+		{path: "user_stories[].priority", allowed: []string{"P0", "P1", "P2", "P3"}},
+	},
+	ArtifactTypePlan: {
+		{path: "risks[].likelihood", allowed: []string{"low", "medium", "high"}, aliases: map[string]string{"med": "medium"}},
+		{path: "risks[].impact", allowed: []string{"low", "medium", "high"}, aliases: map[string]string{"med": "medium"}},
+	},
+	ArtifactTypeTasks: {
+		{path: "phases[].tasks[].status", allowed: []string{"Pending", "InProgress", "Completed", "Blocked"}, aliases: taskStatusAliases},
+		{path: "phases[].tasks[].status_history[].to", allowed: []string{"Pending", "InProgress", "Completed", "Blocked"}, aliases: taskStatusAliases},
+		{
+			path:    "phases[].tasks[].type",
+			allowed: []string{"setup", "implementation", "test", "documentation", "refactor"},
+			aliases: map[string]string{"impl": "implementation", "tests": "test", "testing": "test", "docs": "documentation", "refactoring": "refactor"},
+		},
+	},
+}
+
+// normalizeEnumFields rewrites enum values that differ from an allowed value
+// only in case or separators (e.g., "in_progress" -> "InProgress"), or that use
+// a known legacy alias. Unrecognized values are left for the validator to report.
+func normalizeEnumFields(root *yaml.Node, artifactType ArtifactType) []string {
+	var changes []string
+	for _, field := range enumFields[artifactType] {
+		forEachNodeAt(root, field.path, "", func(path string, node *yaml.Node) {
+			if node.Kind != yaml.ScalarNode {
+				return
+			}
+			if canonical := field.canonical(node.Value); canonical != "" && canonical != node.Value {
+				changes = append(changes, fmt.Sprintf("%s: %s -> %s", path, node.Value, canonical))
+				node.Value = canonical
+			}
+		})
+	}
+	return changes
+}
+
+// canonical returns the allowed value matching value, or "" if none does.
+func (f enumField) canonical(value string) string {
+	key := normalizeEnumKey(value)
+	for _, allowed := range f.allowed {
+		if normalizeEnumKey(allowed) == key {
+			return allowed
+		}
+	}
+	return f.aliases[key]
+}
+
+// normalizeEnumKey lowercases a value and drops separators.
+func normalizeEnumKey(value string) string {
+	return strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(value)))
+}
+
+// forEachNodeAt calls fn for every node at path below node, with the
+// concrete path (e.g., "phases[0].tasks[2].status") of each.
+func forEachNodeAt(node *yaml.Node, path, prefix string, fn func(path string, node *yaml.Node)) {
+	if node == nil {
+		return
+	}
+	key, rest, _ := strings.Cut(path, ".")
+	name, isSeq := strings.CutSuffix(key, "[]")
+
+	child := findNode(node, name)
+	if child == nil {
+		return
+	}
+	childPath := name
+	if prefix != "" {
+		childPath = prefix + "." + name
+	}
+
+	if !isSeq {
+		if rest == "" {
+			fn(childPath, child)
+			return
+		}
+		forEachNodeAt(child, rest, childPath, fn)
+		return
+	}
+
+	if child.Kind != yaml.SequenceNode {
+		return
+	}
+	for i, item := range child.Content {
+		forEachNodeAt(item, rest, fmt.Sprintf("%s[%d]", childPath, i), fn)
+	}
+}
@@ -0,0 +1,217 @@
+// Package validation_test tests artifact schema version upgrades.
+// Related: internal/validation/upgrade.go
+// Tags: validation, upgrade, migration, artifact, meta, version, backup
+package validation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const legacyTasksYAML = `tasks:
+  branch: "001-test"
+  created: "2025-01-01"
+  spec_path: "specs/001-test/spec.yaml"
+  plan_path: "specs/001-test/plan.yaml"
+summary:
+  total_tasks: 2
+  total_phases: 1
+phases:
+  - number: 1
+    title: "Setup"
+    purpose: "Setup"
+    tasks:
+      - id: "T001"
+        title: "Create project"
+        status: "done"
+        type: "Setup"
+        parallel: false
+        story_id: "US-001"
+        file_path: "main.go"
+        dependencies: []
+        acceptance_criteria:
+          - "Project builds"
+      - id: "T002"
+        title: "Write tests"
+        status: "in_progress"
+        type: "tests"
+        parallel: false
+        story_id: "US-001"
+        file_path: "main_test.go"
+        dependencies: ["T001"]
+        acceptance_criteria:
+          - "Tests pass"
+_meta:
+  version: "1.0.0"
+  generator: "autospec"
+  artifact_type: "tasks"
+`
+
+// writeArtifact writes content to name in a temp directory and returns the path.
+func writeArtifact(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing %s: %v", name, err)
+	}
+	return path
+}
+
+func TestUpgradeArtifact_Tasks(t *testing.T) {
+	t.Parallel()
+
+	path := writeArtifact(t, "tasks.yaml", legacyTasksYAML)
+	if result := validateTasksResult(t, path); result.Valid {
+		t.Fatal("legacy fixture should fail validation before the upgrade")
+	}
+
+	upgrade, err := UpgradeArtifact(path, ArtifactTypeTasks, false)
+	if err != nil {
+		t.Fatalf("UpgradeArtifact() error: %v", err)
+	}
+
+	if upgrade.FromVersion != "1.0.0" || upgrade.ToVersion != CurrentArtifactVersion {
+		t.Errorf("versions = %s -> %s, want 1.0.0 -> %s", upgrade.FromVersion, upgrade.ToVersion, CurrentArtifactVersion)
+	}
+	wantChanges := []string{
+		"phases[0].tasks[0].status: done -> Completed",
+		"phases[0].tasks[1].status: in_progress -> InProgress",
+		"phases[0].tasks[0].type: Setup -> setup",
+		"phases[0].tasks[1].type: tests -> test",
+	}
+	if strings.Join(upgrade.Changes, "\n") != strings.Join(wantChanges, "\n") {
+		t.Errorf("Changes = %q, want %q", upgrade.Changes, wantChanges)
+	}
+
+	if result := validateTasksResult(t, path); !result.Valid {
+		t.Errorf("upgraded tasks.yaml should validate, got errors: %v", result.Errors)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), `version: "`+CurrentArtifactVersion+`"`) {
+		t.Errorf("upgraded file should be stamped with %s:\n%s", CurrentArtifactVersion, data)
+	}
+
+	backup, err := os.ReadFile(path + ".v1.0.0.bak")
+	if err != nil {
+		t.Fatalf("backup not written: %v", err)
+	}
+	if string(backup) != legacyTasksYAML {
+		t.Error("backup should hold the original content")
+	}
+	if upgrade.BackupPath != path+".v1.0.0.bak" {
+		t.Errorf("BackupPath = %q", upgrade.BackupPath)
+	}
+}
+
+// validateTasksResult runs the tasks artifact validator on path.
+func validateTasksResult(t *testing.T, path string) *ValidationResult {
+	t.Helper()
+	validator, err := NewArtifactValidator(ArtifactTypeTasks)
+	if err != nil {
+		t.Fatalf("NewArtifactValidator() error: %v", err)
+	}
+	return validator.Validate(path)
+}
+
+func TestUpgradeArtifact_Versions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		content     string
+		wantFrom    string
+		wantUpgrade bool
+		wantChanges []string
+		wantErr     string
+	}{
+		"missing _meta is added": {
+			content:     "feature:\n  status: draft\nuser_stories:\n  - priority: p1\n",
+			wantFrom:    "1.0.0",
+			wantUpgrade: true,
+			wantChanges: []string{"_meta: added", "feature.status: draft -> Draft", "user_stories[0].priority: p1 -> P1"},
+		},
+		"unknown values are left alone": {
+			content:     "feature:\n  status: shipped\n_meta:\n  version: \"1.0.0\"\n",
+			wantFrom:    "1.0.0",
+			wantUpgrade: true,
+		},
+		"already current": {
+			content:  "feature:\n  status: draft\n_meta:\n  version: \"" + CurrentArtifactVersion + "\"\n",
+			wantFrom: CurrentArtifactVersion,
+		},
+		"newer than supported": {
+			content: "_meta:\n  version: \"9.0.0\"\n",
+			wantErr: "newer than this autospec supports",
+		},
+		"invalid version": {
+			content: "_meta:\n  version: \"v1\"\n",
+			wantErr: "_meta.version",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			path := writeArtifact(t, "spec.yaml", tt.content)
+
+			upgrade, err := UpgradeArtifact(path, ArtifactTypeSpec, false)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("UpgradeArtifact() error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UpgradeArtifact() error: %v", err)
+			}
+			if upgrade.FromVersion != tt.wantFrom {
+				t.Errorf("FromVersion = %s, want %s", upgrade.FromVersion, tt.wantFrom)
+			}
+			if upgrade.NeedsUpgrade() != tt.wantUpgrade {
+				t.Errorf("NeedsUpgrade() = %v, want %v", upgrade.NeedsUpgrade(), tt.wantUpgrade)
+			}
+			if strings.Join(upgrade.Changes, "\n") != strings.Join(tt.wantChanges, "\n") {
+				t.Errorf("Changes = %q, want %q", upgrade.Changes, tt.wantChanges)
+			}
+
+			data, _ := os.ReadFile(path)
+			if !tt.wantUpgrade && string(data) != tt.content {
+				t.Error("current artifact should not be rewritten")
+			}
+		})
+	}
+}
+
+func TestUpgradeSpecDir_DryRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	tasksPath := filepath.Join(dir, "tasks.yaml")
+	planContent := "risks:\n  - likelihood: High\n    impact: med\n"
+	if err := os.WriteFile(tasksPath, []byte(legacyTasksYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plan.yaml"), []byte(planContent), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	upgrades, err := UpgradeSpecDir(dir, true)
+	if err != nil {
+		t.Fatalf("UpgradeSpecDir() error: %v", err)
+	}
+	if len(upgrades) != 2 {
+		t.Fatalf("got %d upgrades, want 2 (spec.yaml is missing)", len(upgrades))
+	}
+	if upgrades[0].Type != ArtifactTypePlan || !strings.Contains(strings.Join(upgrades[0].Changes, ","), "risks[0].impact: med -> medium") {
+		t.Errorf("plan upgrade = %+v", upgrades[0])
+	}
+
+	data, _ := os.ReadFile(tasksPath)
+	if string(data) != legacyTasksYAML {
+		t.Error("dry run must not modify files")
+	}
+	if _, err := os.Stat(tasksPath + ".v1.0.0.bak"); !os.IsNotExist(err) {
+		t.Error("dry run must not write backups")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/yaml"
 )
 
@@ -27,7 +28,7 @@ func ValidateSpecFile(specDir string) error {
 		return nil // spec.md exists
 	}
 
-	return fmt.Errorf("spec file not found in %s - run 'autospec specify <description>' to create it", specDir)
+	return apperrors.ErrArtifactMissing.Errorf("spec file not found in %s - run 'autospec specify <description>' to create it", specDir)
 }
 
 // ValidatePlanFile checks if plan.md or plan.yaml exists in the given spec directory
@@ -44,7 +45,7 @@ func ValidatePlanFile(specDir string) error {
 		return nil // plan.md exists
 	}
 
-	return fmt.Errorf("plan file not found in %s - run 'autospec plan' to create it", specDir)
+	return apperrors.ErrArtifactMissing.Errorf("plan file not found in %s - run 'autospec plan' to create it", specDir)
 }
 
 // ValidateTasksFile checks if tasks.md or tasks.yaml exists in the given spec directory
@@ -61,7 +62,7 @@ func ValidateTasksFile(specDir string) error {
 		return nil // tasks.md exists
 	}
 
-	return fmt.Errorf("tasks file not found in %s - run 'autospec tasks' to create it", specDir)
+	return apperrors.ErrArtifactMissing.Errorf("tasks file not found in %s - run 'autospec tasks' to create it", specDir)
 }
 
 // ValidateConstitutionFile checks if constitution.yaml exists and validates its schema.
@@ -72,7 +73,7 @@ func ValidateConstitutionFile(projectDir string) error {
 
 	if _, err := os.Stat(constitutionPath); err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("constitution file not found at %s - run 'autospec constitution' to create it", constitutionPath)
+			return apperrors.ErrArtifactMissing.Errorf("constitution file not found at %s - run 'autospec constitution' to create it", constitutionPath)
 		}
 		return fmt.Errorf("checking constitution file: %w", err)
 	}
@@ -4,9 +4,12 @@
 package validation
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
 )
 
 func TestValidateSpecFile(t *testing.T) {
@@ -47,6 +50,9 @@ func TestValidateSpecFile(t *testing.T) {
 			if (err != nil) != tc.wantErr {
 				t.Errorf("ValidateSpecFile() error = %v, wantErr %v", err, tc.wantErr)
 			}
+			if tc.wantErr && !errors.Is(err, apperrors.ErrArtifactMissing) {
+				t.Errorf("ValidateSpecFile() error = %v, want ErrArtifactMissing", err)
+			}
 		})
 	}
 }
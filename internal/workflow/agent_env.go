@@ -0,0 +1,40 @@
+package workflow
+
+import "github.com/ariel-frischer/autospec/internal/agentenv"
+
+// sessionScope is the task or phase the next stage sessions work on, used as
+// template data for the env and stage_env config keys.
+type sessionScope struct {
+	taskID string
+	phase  int
+}
+
+// scopeSessions sets the task or phase for the agent sessions started until
+// the returned function is called.
+func (e *Executor) scopeSessions(taskID string, phase int) (restore func()) {
+	previous := e.scope
+	e.scope = sessionScope{taskID: taskID, phase: phase}
+	return func() { e.scope = previous }
+}
+
+// injectAgentEnv renders the configured env for a stage's sessions and hands
+// it to the agent runner until the returned function is called. Only
+// ClaudeExecutor runners take an environment; others are left unchanged.
+func (e *Executor) injectAgentEnv(specName string, stage Stage) (restore func(), err error) {
+	claude, ok := e.Claude.(*ClaudeExecutor)
+	if !ok || e.AgentEnv.Empty() {
+		return func() {}, nil
+	}
+	vars, err := e.AgentEnv.Vars(agentenv.Data{
+		SpecName: specName,
+		Stage:    string(stage),
+		TaskID:   e.scope.taskID,
+		Phase:    e.scope.phase,
+	})
+	if err != nil {
+		return nil, err
+	}
+	previous := claude.Env
+	claude.Env = vars
+	return func() { claude.Env = previous }, nil
+}
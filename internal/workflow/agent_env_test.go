@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/agentenv"
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_InjectAgentEnv(t *testing.T) {
+	t.Parallel()
+
+	claude := &ClaudeExecutor{Env: map[string]string{"KEEP": "1"}}
+	e := &Executor{
+		Claude: claude,
+		AgentEnv: agentenv.Env{
+			Global: map[string]string{"DATABASE_URL": "postgres://localhost/{{.SpecName}}"},
+			Stages: map[string]map[string]string{
+				"implement": {"SCOPE": "{{.Stage}}-{{.TaskID}}-{{.Phase}}"},
+			},
+		},
+	}
+
+	restoreScope := e.scopeSessions("T004", 0)
+	restore, err := e.injectAgentEnv("003-auth", StageImplement)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"DATABASE_URL": "postgres://localhost/003-auth",
+		"SCOPE":        "implement-T004-0",
+	}, claude.Env)
+
+	restore()
+	restoreScope()
+	assert.Equal(t, map[string]string{"KEEP": "1"}, claude.Env, "previous env should be restored")
+	assert.Equal(t, sessionScope{}, e.scope)
+}
+
+func TestExecutor_InjectAgentEnv_NoConfig(t *testing.T) {
+	t.Parallel()
+
+	claude := &ClaudeExecutor{}
+	e := &Executor{Claude: claude}
+
+	restore, err := e.injectAgentEnv("003-auth", StagePlan)
+	require.NoError(t, err)
+	restore()
+	assert.Nil(t, claude.Env)
+}
+
+func TestClaudeExecutor_PassesEnvToAgent(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "env.txt")
+	agent, err := cliagent.NewCustomAgentFromConfig(cliagent.CustomAgentConfig{
+		Command: "sh",
+		Args:    []string{"-c", `printf %s "$DATABASE_URL" > "$OUT"`, "{{PROMPT}}"},
+	})
+	require.NoError(t, err)
+
+	claude := &ClaudeExecutor{
+		Agent: agent,
+		Env:   map[string]string{"DATABASE_URL": "postgres://localhost/test", "OUT": out},
+	}
+	require.NoError(t, claude.Execute("ignored"))
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "postgres://localhost/test", string(data))
+}
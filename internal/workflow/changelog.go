@@ -0,0 +1,42 @@
+package workflow
+
+import (
+	"github.com/ariel-frischer/autospec/internal/changelog"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/report"
+)
+
+// completeSpec marks the spec as completed and, when enabled, records it in
+// the project changelog, on its tracker issues and on the GitHub project.
+func (e *Executor) completeSpec(specDir string) {
+	if markSpecCompletedAndPrint(specDir) {
+		e.updateChangelog(specDir)
+	}
+	e.reportIssues(specDir)
+	e.syncProject(specDir)
+	e.retrospect(specDir)
+}
+
+// updateChangelog writes the changelog entry of a completed spec. Failures
+// only warn: the spec is already complete and the entry can be regenerated
+// by completing it again.
+func (e *Executor) updateChangelog(specDir string) {
+	if e == nil || !e.Changelog.Enabled {
+		return
+	}
+	ui := output.Default()
+	r, err := report.Build(report.Options{SpecDir: specDir})
+	if err != nil {
+		ui.Warn("could not build changelog entry: %v", err)
+		return
+	}
+	path := e.Changelog.Path()
+	changed, err := changelog.Update(path, r.SpecName, changelog.Entry(r))
+	if err != nil {
+		ui.Warn("could not update changelog: %v", err)
+		return
+	}
+	if changed {
+		ui.Info("Updated %s with %s", path, r.SpecName)
+	}
+}
@@ -10,6 +10,9 @@ import (
 
 	"github.com/ariel-frischer/autospec/internal/cliagent"
 	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/ariel-frischer/autospec/internal/ratelimit"
+	"github.com/ariel-frischer/autospec/internal/redact"
 )
 
 // ClaudeExecutor handles CLI agent command execution.
@@ -19,6 +22,10 @@ type ClaudeExecutor struct {
 
 	Timeout int // Timeout in seconds (0 = no timeout)
 
+	// Deadline, when set, also stops sessions that are still running at that
+	// time. The task loop sets it to enforce per-task time limits.
+	Deadline time.Time
+
 	// CcleanConfig provides detailed configuration for cclean output formatting.
 	// Controls verbose mode, line numbers, and style for stream-json display.
 	// Style field controls output formatting: default, compact, minimal, plain, raw.
@@ -32,6 +39,27 @@ type ClaudeExecutor struct {
 	// When true (default), uses syscall.Exec for full terminal control in interactive mode.
 	// Set to false for multi-stage runs where we need to continue after interactive stages.
 	ReplaceProcessForInteractive bool
+
+	// UsageWriter, when set, also receives raw agent output in headless mode so
+	// budget tracking can count tokens and stage transcripts can be recorded
+	// (see budget.Tracker.Writer and transcript.Recorder.Writer).
+	UsageWriter io.Writer
+
+	// Redactor masks secrets in headless agent output before it is displayed
+	// (nil: output is shown as is). See config key redaction.
+	Redactor *redact.Redactor
+
+	// RateLimit, when set, receives headless stdout and stderr so a failed
+	// session that hit a provider rate limit returns a *RateLimitedError.
+	RateLimit *ratelimit.Limiter
+
+	// Env holds extra environment variables for agent sessions, set per stage
+	// by the executor from the env and stage_env config keys.
+	Env map[string]string
+
+	// WorkDir is the directory agent sessions run in ("" is the current
+	// directory), set per stage by the executor from the workdir config key.
+	WorkDir string
 }
 
 // Execute runs an agent command with the given prompt.
@@ -57,6 +85,7 @@ func (c *ClaudeExecutor) ExecuteInteractive(prompt string) error {
 // executeWithAgent uses the new Agent interface for execution.
 // When interactive is true, sets ExecOptions.Interactive to skip headless flags.
 func (c *ClaudeExecutor) executeWithAgent(prompt string, interactive bool) error {
+	started := time.Now()
 	ctx, cancel := c.createTimeoutContext()
 	if cancel != nil {
 		defer cancel()
@@ -68,44 +97,75 @@ func (c *ClaudeExecutor) executeWithAgent(prompt string, interactive bool) error
 	if !interactive {
 		stdout = c.getFormattedStdout(os.Stdout)
 	}
+	redactedStdout := c.withRedaction(stdout, interactive)
+	redactedStderr := c.withRedaction(os.Stderr, interactive)
 
 	opts := cliagent.ExecOptions{
-		Stdout:          stdout,
-		Stderr:          os.Stderr,
+		Stdout:          c.withRateLimitWriter(c.withUsageWriter(redactedStdout, interactive), c.RateLimit.StdoutWriter(), interactive),
+		Stderr:          c.withRateLimitWriter(redactedStderr, c.RateLimit.StderrWriter(), interactive),
 		Timeout:         time.Duration(c.Timeout) * time.Second,
 		UseSubscription: c.UseSubscription,
+		Env:             c.Env,
+		WorkDir:         c.WorkDir,
 		Interactive:     interactive,
 		ReplaceProcess:  interactive && c.ReplaceProcessForInteractive,
 	}
 
 	result, err := c.Agent.Execute(ctx, prompt, opts)
 
-	// Flush formatter if used (only applies to non-interactive mode)
+	// Flush redaction and formatter if used (only applies to non-interactive mode)
+	flushRedaction(redactedStdout)
+	flushRedaction(redactedStderr)
 	if !interactive {
 		c.flushFormatter(stdout)
 	}
 
+	// The agent may exit on the terminal's SIGINT before it is stopped
+	// for the interrupt, so check the cause before the exit status.
+	if interrupt.Interrupted(ctx) {
+		return fmt.Errorf("agent %s stopped: %w", c.Agent.Name(), interrupt.ErrInterrupted)
+	}
 	if err != nil {
 		// Check for timeout specifically
 		if ctx.Err() == context.DeadlineExceeded {
-			return NewTimeoutError(time.Duration(c.Timeout)*time.Second, c.FormatCommand(prompt))
+			return NewTimeoutError(c.sessionTimeout(started), c.FormatCommand(prompt))
 		}
-		return fmt.Errorf("agent %s command failed: %w", c.Agent.Name(), err)
+		return c.rateLimited(fmt.Errorf("agent %s command failed: %w", c.Agent.Name(), err), interactive)
 	}
 
 	// Check exit code
 	if result.ExitCode != 0 {
-		return fmt.Errorf("agent %s exited with code %d", c.Agent.Name(), result.ExitCode)
+		return c.rateLimited(fmt.Errorf("agent %s exited with code %d", c.Agent.Name(), result.ExitCode), interactive)
 	}
 	return nil
 }
 
-// createTimeoutContext creates a context with optional timeout
+// createTimeoutContext creates a context with optional timeout, derived from
+// the interrupt context so a signal stops the agent.
 func (c *ClaudeExecutor) createTimeoutContext() (context.Context, context.CancelFunc) {
-	if c.Timeout > 0 {
-		return context.WithTimeout(context.Background(), time.Duration(c.Timeout)*time.Second)
+	parent := interrupt.Context()
+	timeout := time.Duration(c.Timeout) * time.Second
+	if c.Deadline.IsZero() {
+		if timeout > 0 {
+			return context.WithTimeout(parent, timeout)
+		}
+		return parent, nil
+	}
+	deadline := c.Deadline
+	if timeout > 0 && time.Now().Add(timeout).Before(deadline) {
+		deadline = time.Now().Add(timeout)
+	}
+	return context.WithDeadline(parent, deadline)
+}
+
+// sessionTimeout returns the limit a timed-out session ran into: the
+// configured timeout, or the time the session had left before Deadline.
+func (c *ClaudeExecutor) sessionTimeout(started time.Time) time.Duration {
+	timeout := time.Duration(c.Timeout) * time.Second
+	if !c.Deadline.IsZero() && (timeout == 0 || c.Deadline.Sub(started) < timeout) {
+		return c.Deadline.Sub(started).Round(time.Second)
 	}
-	return context.Background(), nil
+	return timeout
 }
 
 // FormatCommand returns a human-readable command string for display and error messages.
@@ -134,6 +194,7 @@ func (c *ClaudeExecutor) StreamCommand(prompt string, stdout, stderr io.Writer)
 		return fmt.Errorf("no agent configured")
 	}
 
+	started := time.Now()
 	ctx, cancel := c.createTimeoutContext()
 	if cancel != nil {
 		defer cancel()
@@ -141,22 +202,29 @@ func (c *ClaudeExecutor) StreamCommand(prompt string, stdout, stderr io.Writer)
 
 	// Optionally wrap stdout with formatter
 	formattedStdout := c.getFormattedStdout(stdout)
+	redactedStdout := c.withRedaction(formattedStdout, false)
+	redactedStderr := c.withRedaction(stderr, false)
 
 	opts := cliagent.ExecOptions{
-		Stdout:          formattedStdout,
-		Stderr:          stderr,
+		Stdout:          c.withUsageWriter(redactedStdout, false),
+		Stderr:          redactedStderr,
 		Timeout:         time.Duration(c.Timeout) * time.Second,
 		UseSubscription: c.UseSubscription,
 	}
 
 	result, err := c.Agent.Execute(ctx, prompt, opts)
 
-	// Flush formatter if used
+	// Flush redaction and formatter if used
+	flushRedaction(redactedStdout)
+	flushRedaction(redactedStderr)
 	c.flushFormatter(formattedStdout)
 
+	if interrupt.Interrupted(ctx) {
+		return fmt.Errorf("agent %s stopped: %w", c.Agent.Name(), interrupt.ErrInterrupted)
+	}
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
-			return NewTimeoutError(time.Duration(c.Timeout)*time.Second, c.FormatCommand(prompt))
+			return NewTimeoutError(c.sessionTimeout(started), c.FormatCommand(prompt))
 		}
 		return fmt.Errorf("agent %s command failed: %w", c.Agent.Name(), err)
 	}
@@ -167,6 +235,55 @@ func (c *ClaudeExecutor) StreamCommand(prompt string, stdout, stderr io.Writer)
 	return nil
 }
 
+// withUsageWriter tees w into UsageWriter when set. Interactive sessions keep
+// the terminal writer unwrapped so the agent still detects a TTY.
+func (c *ClaudeExecutor) withUsageWriter(w io.Writer, interactive bool) io.Writer {
+	if c.UsageWriter == nil || interactive {
+		return w
+	}
+	return io.MultiWriter(w, c.UsageWriter)
+}
+
+// withRateLimitWriter tees w into detector, one of the RateLimit stream
+// writers, when set. Interactive sessions are left alone: the user sees and
+// handles limits.
+func (c *ClaudeExecutor) withRateLimitWriter(w, detector io.Writer, interactive bool) io.Writer {
+	if detector == nil || interactive {
+		return w
+	}
+	return io.MultiWriter(w, detector)
+}
+
+// rateLimited wraps a failed session's error in a *RateLimitedError when its
+// output ended with a provider rate limit.
+func (c *ClaudeExecutor) rateLimited(err error, interactive bool) error {
+	if interactive {
+		return err
+	}
+	if sig, ok := c.RateLimit.Detect(); ok {
+		return &RateLimitedError{Signal: sig, Err: err}
+	}
+	return err
+}
+
+// withRedaction wraps w so secrets are masked before they reach it.
+// Interactive sessions keep the terminal writer unwrapped so the agent still
+// detects a TTY.
+func (c *ClaudeExecutor) withRedaction(w io.Writer, interactive bool) io.Writer {
+	if interactive {
+		return w
+	}
+	return c.Redactor.NewWriter(w)
+}
+
+// flushRedaction writes any partial line held back by a redacting writer.
+// Safe to call on any io.Writer (no-op for other writers).
+func flushRedaction(w io.Writer) {
+	if rw, ok := w.(*redact.Writer); ok {
+		_ = rw.Flush()
+	}
+}
+
 // getFormattedStdout returns either a FormatterWriter or the original writer.
 // Returns a FormatterWriter when:
 // - CcleanConfig.Style is set (not empty or "raw")
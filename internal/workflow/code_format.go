@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/codefmt"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/git"
+	"github.com/ariel-frischer/autospec/internal/output"
+)
+
+// maxFormatterOutputLines caps how many trailing output lines of a failed
+// formatter are shown.
+const maxFormatterOutputLines = 8
+
+// snapshotForFormatters records the repository before an implement session
+// when formatters are configured, so the files the session changes can be
+// formatted afterwards. Returns nil when there is nothing to format: no
+// formatters, another stage, an agent on a remote host (the code stays
+// there), or no git repository.
+func (e *Executor) snapshotForFormatters(stage Stage) *git.Snapshot {
+	if len(e.Formatters) == 0 || stage != StageImplement {
+		return nil
+	}
+	if _, remote := e.remoteFor(stage); remote {
+		return nil
+	}
+	snapshot, err := git.TakeSnapshot()
+	if err != nil {
+		e.debugLog("Formatters skipped: %v", err)
+		return nil
+	}
+	return snapshot
+}
+
+// formatChangedFiles runs the formatters over the files changed since
+// snapshot. Formatter failures only warn: validation decides whether the
+// session's output is acceptable.
+func (e *Executor) formatChangedFiles(ctx *stageExecutionContext, snapshot *git.Snapshot) {
+	if snapshot == nil {
+		return
+	}
+	changed, err := snapshot.ChangedSince()
+	if err != nil {
+		output.Default().Warn("Formatters skipped: %v", err)
+		return
+	}
+	run := e.formatRunner
+	if run == nil {
+		run = codefmt.RunShell
+	}
+	for _, r := range codefmt.Run(e.Formatters, snapshot.Root, changed, run) {
+		message := fmt.Sprintf("%s: %d file(s)", r.Formatter, len(r.Files))
+		if r.Err != nil {
+			message = fmt.Sprintf("%s failed on %d file(s): %v", r.Formatter, len(r.Files), r.Err)
+			output.Default().Warn("%s", message)
+			for _, line := range tailLines(r.Output, maxFormatterOutputLines) {
+				fmt.Printf("  %s\n", line)
+			}
+		} else {
+			e.debugLog("Formatted with %s: %s", r.Formatter, strings.Join(r.Files, ", "))
+		}
+		e.Events.Record(events.Event{
+			Type:    events.TypeFormatted,
+			Spec:    ctx.specName,
+			Stage:   string(ctx.stage),
+			Task:    e.scope.taskID,
+			Attempt: ctx.retryState.Count + 1,
+			Message: message,
+		})
+	}
+}
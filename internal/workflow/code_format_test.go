@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/codefmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writingClaude is a ClaudeRunner whose sessions write files.
+type writingClaude struct {
+	mockClaudeExecutor
+	files map[string]string
+}
+
+func (w *writingClaude) Execute(prompt string) error {
+	for name, content := range w.files {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			return err
+		}
+	}
+	return w.mockClaudeExecutor.Execute(prompt)
+}
+
+func TestExecuteStage_FormatsChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, args := range [][]string{{"init", "-q"}, {"config", "user.email", "t@example.com"}, {"config", "user.name", "t"}} {
+		require.NoError(t, exec.Command("git", args...).Run())
+	}
+	require.NoError(t, os.WriteFile("untouched.go", []byte("package a\n"), 0o644))
+
+	var commands []string
+	e := &Executor{
+		Claude:     &writingClaude{files: map[string]string{"main.go": "package main\n", "app.py": "x = 1\n"}},
+		StateDir:   t.TempDir(),
+		SpecsDir:   "specs",
+		Formatters: []codefmt.Formatter{{Patterns: []string{"*.go"}, Command: "gofmt -w"}},
+		formatRunner: func(dir, command string) (string, error) {
+			commands = append(commands, command)
+			return "", nil
+		},
+	}
+
+	validate := func(string) error {
+		assert.Equal(t, []string{"gofmt -w 'main.go'"}, commands, "formatters should run before validation")
+		return nil
+	}
+	_, err := e.ExecuteStage("001-x", StageImplement, "/autospec.implement", validate)
+	require.NoError(t, err)
+
+	commands = nil
+	_, err = e.ExecuteStage("001-x", StagePlan, "/autospec.plan", func(string) error { return nil })
+	require.NoError(t, err)
+	assert.Empty(t, commands, "only implement sessions are formatted")
+}
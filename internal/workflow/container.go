@@ -0,0 +1,28 @@
+package workflow
+
+import (
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+)
+
+// applyContainer makes the stage's agent sessions run in the agent's
+// container until the returned function is called. The project is mounted
+// at the same path, so workdirs, AUTOSPEC_SPECS_DIR and context file paths
+// need no mapping.
+func (e *Executor) applyContainer(stage Stage) (restore func()) {
+	if len(e.Container) == 0 {
+		return func() {}
+	}
+	claude, ok := e.Claude.(*ClaudeExecutor)
+	if !ok || claude.Agent == nil {
+		return func() {}
+	}
+	container, ok := e.Container[claude.Agent.Name()]
+	if !ok || !container.RunsStage(string(stage)) {
+		return func() {}
+	}
+
+	previous := claude.Agent
+	claude.Agent = cliagent.NewContainerAgent(previous, container)
+	e.debugLog("Running %s agent in container %s", stage, container.Image)
+	return func() { claude.Agent = previous }
+}
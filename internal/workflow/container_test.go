@@ -0,0 +1,32 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_ApplyContainer(t *testing.T) {
+	t.Parallel()
+
+	agent, err := cliagent.NewCustomAgentFromConfig(cliagent.CustomAgentConfig{Command: "myagent", Args: []string{"{{PROMPT}}"}})
+	require.NoError(t, err)
+	claude := &ClaudeExecutor{Agent: agent}
+	e := &Executor{
+		Claude:    claude,
+		Container: map[string]cliagent.ContainerConfig{agent.Name(): {Image: "agent:1", Stages: []string{"implement"}}},
+	}
+
+	restore := e.applyContainer(StagePlan)
+	assert.Same(t, agent, claude.Agent, "plan is not in the container's stages")
+	restore()
+
+	restore = e.applyContainer(StageImplement)
+	container, ok := claude.Agent.(*cliagent.ContainerAgent)
+	require.True(t, ok, "implement should run in the container, got %T", claude.Agent)
+	assert.Equal(t, "agent:1", container.Config.Image)
+	restore()
+	assert.Same(t, agent, claude.Agent, "agent should be restored")
+}
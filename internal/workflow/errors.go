@@ -4,6 +4,9 @@ import (
 	"context"
 	"fmt"
 	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/ratelimit"
 )
 
 // TimeoutError represents a command timeout failure
@@ -23,6 +26,11 @@ func (e *TimeoutError) Unwrap() error {
 	return e.Err
 }
 
+// Is classifies timeouts as apperrors.ErrAgentTimeout.
+func (e *TimeoutError) Is(target error) bool {
+	return target == apperrors.ErrAgentTimeout
+}
+
 // NewTimeoutError creates a new TimeoutError with the given details
 func NewTimeoutError(timeout time.Duration, command string) *TimeoutError {
 	return &TimeoutError{
@@ -31,3 +39,21 @@ func NewTimeoutError(timeout time.Duration, command string) *TimeoutError {
 		Err:     context.DeadlineExceeded,
 	}
 }
+
+// RateLimitedError reports an agent session that failed because the
+// provider rate-limited it. The executor waits and reruns the session
+// instead of counting it against max_retries.
+type RateLimitedError struct {
+	Signal ratelimit.Signal
+	Err    error // The session's execution error
+}
+
+// Error describes the rate limit and the failed session.
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf("rate limited: %s (%v)", e.Signal.Line, e.Err)
+}
+
+// Unwrap returns the session's execution error.
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
@@ -9,6 +9,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
 )
 
 func TestTimeoutError_Error(t *testing.T) {
@@ -58,6 +60,9 @@ func TestTimeoutError_Unwrap(t *testing.T) {
 	if !errors.Is(err, context.DeadlineExceeded) {
 		t.Error("errors.Is(err, context.DeadlineExceeded) = false, want true")
 	}
+	if !errors.Is(err, apperrors.ErrAgentTimeout) {
+		t.Error("errors.Is(err, apperrors.ErrAgentTimeout) = false, want true")
+	}
 }
 
 func TestTimeoutError_ErrorMessageFormat(t *testing.T) {
@@ -1,15 +1,39 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
-
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/agentenv"
+	"github.com/ariel-frischer/autospec/internal/budget"
+	"github.com/ariel-frischer/autospec/internal/changelog"
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/codefmt"
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/ghproject"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/ariel-frischer/autospec/internal/learnings"
 	"github.com/ariel-frischer/autospec/internal/lifecycle"
+	"github.com/ariel-frischer/autospec/internal/logging"
 	"github.com/ariel-frischer/autospec/internal/notify"
 	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/pause"
 	"github.com/ariel-frischer/autospec/internal/progress"
+	"github.com/ariel-frischer/autospec/internal/promptsize"
+	"github.com/ariel-frischer/autospec/internal/ratelimit"
 	"github.com/ariel-frischer/autospec/internal/retry"
+	"github.com/ariel-frischer/autospec/internal/salvage"
+	"github.com/ariel-frischer/autospec/internal/stagestats"
+	"github.com/ariel-frischer/autospec/internal/statusfile"
+	"github.com/ariel-frischer/autospec/internal/storage"
+	"github.com/ariel-frischer/autospec/internal/tracker"
+	"github.com/ariel-frischer/autospec/internal/transcript"
 	"github.com/ariel-frischer/autospec/internal/validation"
 )
 
@@ -21,17 +45,49 @@ import (
 // *ClaudeExecutor, tests can inject mock implementations to verify
 // execution behavior without actual Claude CLI invocations.
 type Executor struct {
-	Claude              ClaudeRunner              // Interface for Claude command execution (allows mocking)
-	StateDir            string                    // Directory for retry state storage
-	SpecsDir            string                    // Directory for spec files
-	MaxRetries          int                       // Maximum retry attempts (1-10 range)
-	TotalStages         int                       // Total stages in workflow
-	Debug               bool                      // Enable debug logging
-	AutoCommit          bool                      // Enable auto-commit instruction injection
-	Progress            *ProgressController       // Optional progress display controller
-	Notify              *NotifyDispatcher         // Optional notification dispatcher
-	ProgressDisplay     *progress.ProgressDisplay // Deprecated: use Progress instead
-	NotificationHandler *notify.Handler           // Deprecated: use Notify instead
+	Claude              ClaudeRunner                        // Interface for Claude command execution (allows mocking)
+	RunID               string                              // Run ID shared by logs, state files, events and notifications
+	StateDir            string                              // Directory for retry state storage
+	ProjectRoot         string                              // Project the run belongs to; keys its pause file
+	PauseFile           string                              // Pause file checked between tasks and phases (empty disables pausing)
+	SpecsDir            string                              // Directory for spec files
+	MaxRetries          int                                 // Maximum retry attempts (1-10 range)
+	ForceRerun          bool                                // Run plan and tasks even when their inputs are unchanged (--force)
+	FailFast            bool                                // Stop --tasks, --phases and --parallel runs at the first task failure (--fail-fast)
+	TotalStages         int                                 // Total stages in workflow
+	Debug               bool                                // Console log level is debug; shows instruction hints
+	AutoCommit          bool                                // Enable auto-commit instruction injection
+	Progress            *ProgressController                 // Optional progress display controller
+	Notify              *NotifyDispatcher                   // Optional notification dispatcher
+	Events              *events.Recorder                    // Optional run event log (nil discards events)
+	Status              *statusfile.Writer                  // Optional .autospec/status.json writer (nil disables)
+	PromptTemplates     *PromptTemplates                    // Optional prompt templates (nil uses prompts as given)
+	Budget              *budget.Tracker                     // Optional run/stage usage limits (nil disables)
+	Storage             *storage.Guard                      // Optional state dir size limits and run log pruning (nil disables)
+	RateLimit           *ratelimit.Limiter                  // Optional provider rate-limit waits (nil disables)
+	Transcript          *transcript.Recorder                // Optional per-stage transcript writer (nil disables)
+	MarkdownViews       bool                                // Render spec.md, plan.md, ... from the YAML artifacts after each stage
+	Salvage             *salvage.Capture                    // Agent output searched for artifacts printed instead of written (config: artifact_salvage; nil disables)
+	StagingDir          string                              // Plan and tasks agents write here until validation passes (config: artifact_staging; empty disables)
+	Changelog           changelog.Config                    // CHANGELOG.md entry on spec completion (zero value disables)
+	Issues              *tracker.Tracker                    // Optional Jira/Linear story sync (nil disables)
+	Project             *ghproject.Syncer                   // Optional GitHub Projects task board (config: github_project; nil disables)
+	AgentEnv            agentenv.Env                        // Variables injected into agent sessions (config: env, stage_env)
+	WorkDirs            map[string]string                   // Agent working directory per stage (config: workdir)
+	RetryStrategies     map[string][]string                 // Retry prompt ladder per stage (config: retry_strategies)
+	Remote              map[string]cliagent.RemoteConfig    // Agents run over ssh, by agent name (config: remote)
+	Container           map[string]cliagent.ContainerConfig // Agents run in docker/podman, by agent name (config: container)
+	Formatters          []codefmt.Formatter                 // Run over files changed by implement sessions (config: formatters)
+	Learnings           learnings.Config                    // Project memory added to plan/implement prompts (config: learnings)
+	LearningsPath       string                              // learnings.yaml location (empty: learnings.DefaultPath)
+	PromptSize          promptsize.Config                   // Prompt token estimates, warnings and truncation (config: prompt_size)
+	Rescue              Rescuer                             // Asks how to go on when a stage exhausts its retries (nil fails the stage)
+	ProgressDisplay     *progress.ProgressDisplay           // Deprecated: use Progress instead
+	NotificationHandler *notify.Handler                     // Deprecated: use Notify instead
+
+	scope        sessionScope   // Task or phase of the current sessions, for AgentEnv templates
+	formatRunner codefmt.Runner // Runs formatter commands (nil: codefmt.RunShell)
+	keys         *pauseKeys     // 'p' key listener of the running task or phase loop
 }
 
 // Stage represents a workflow stage (specify, plan, tasks, implement)
@@ -49,13 +105,23 @@ const (
 	StageClarify      Stage = "clarify"
 	StageChecklist    Stage = "checklist"
 	StageAnalyze      Stage = "analyze"
+	StageForecast     Stage = "forecast"
+	StageRetro        Stage = "retro"
 )
 
-// debugLog prints a debug message if debug mode is enabled
+// debugLog writes a debug record tagged with the executor component.
 func (e *Executor) debugLog(format string, args ...interface{}) {
-	if e.Debug {
-		fmt.Printf("[DEBUG] "+format+"\n", args...)
+	e.logger("executor").Debug(fmt.Sprintf(format, args...))
+}
+
+// logger returns the logger for component, tagged with the run ID when known.
+// Safe to call on a nil executor.
+func (e *Executor) logger(component string) *slog.Logger {
+	l := logging.For(component)
+	if e != nil && e.RunID != "" {
+		l = l.With("run_id", e.RunID)
 	}
+	return l
 }
 
 // getStageNumber returns the sequential number for a stage (1-based)
@@ -103,7 +169,8 @@ type StageResult struct {
 	Error            error
 	RetryCount       int
 	Exhausted        bool
-	ValidationErrors []string // Schema validation errors for retry context
+	ValidationErrors []string        // Schema validation errors for retry context
+	Failure          stagestats.Kind // Class of the last failed attempt ("" when none failed)
 }
 
 // ExecuteStage executes a workflow stage with validation and retry logic.
@@ -124,6 +191,10 @@ func (e *Executor) ExecuteStage(specName string, stage Stage, command string, va
 	e.debugLog("ExecuteStage called - spec: %s, stage: %s, command: %s", specName, stage, command)
 	result := &StageResult{Stage: stage, Success: false}
 
+	if err := e.Storage.Check(); err != nil {
+		return result, err
+	}
+
 	retryState, err := e.loadStageRetryState(specName, stage)
 	if err != nil {
 		return result, err
@@ -132,6 +203,43 @@ func (e *Executor) ExecuteStage(specName string, stage Stage, command string, va
 	// Inject auto-commit instructions if enabled
 	commandWithInstructions := InjectAutoCommitInstructions(command, e.AutoCommit)
 	e.debugLog("AutoCommit enabled: %v", e.AutoCommit)
+	commandWithInstructions = e.injectLearnings(commandWithInstructions, stage)
+	e.checkPromptSize(stage, commandWithInstructions)
+
+	e.setNotifyStage(specName, stage)
+	if !IsInteractive(stage) {
+		defer e.watchStage(stage)()
+	}
+
+	e.Events.Record(events.Event{Type: events.TypeStageStart, Spec: specName, Stage: string(stage), Attempt: retryState.Count + 1})
+	e.Events.Record(events.Event{
+		Type:    events.TypeCommandBuilt,
+		Spec:    specName,
+		Stage:   string(stage),
+		Command: CompactInstructionsForDisplay(commandWithInstructions, true),
+	})
+
+	restoreEnv, err := e.injectAgentEnv(specName, stage)
+	if err != nil {
+		return result, err
+	}
+	defer restoreEnv()
+	restoreWorkDir, err := e.applyWorkDir(specName, stage)
+	if err != nil {
+		return result, err
+	}
+	defer restoreWorkDir()
+	stagingDir, restoreStaging, err := e.applyStaging(specName, stage)
+	if err != nil {
+		return result, err
+	}
+	defer restoreStaging()
+	restoreRemote, err := e.applyRemote(specName, stage)
+	if err != nil {
+		return result, err
+	}
+	defer restoreRemote()
+	defer e.applyContainer(stage)()
 
 	ctx := &stageExecutionContext{
 		specName:       specName,
@@ -142,9 +250,55 @@ func (e *Executor) ExecuteStage(specName string, stage Stage, command string, va
 		result:         result,
 		retryState:     retryState,
 		interactive:    IsInteractive(stage),
+		stagingDir:     stagingDir,
+	}
+
+	result, err = e.executeStageLoop(ctx)
+	err = e.finishStaging(ctx, err)
+	e.recordStageStats(ctx)
+	e.writeTranscript(specName, stage)
+	if err == nil {
+		e.renderMarkdownViews(specName)
+	}
+	return result, err
+}
+
+// renderMarkdownViews regenerates the markdown views of the spec's YAML
+// artifacts when markdown_views is enabled. Like transcripts, specify renders
+// from the stage executor once the new spec is detected. Failures only warn.
+func (e *Executor) renderMarkdownViews(specName string) {
+	if !e.MarkdownViews || specName == "" {
+		return
+	}
+	ui := output.Default()
+	written, skipped, err := validation.WriteMarkdownViews(filepath.Join(e.SpecsDir, specName), false)
+	if err != nil {
+		ui.Warn("rendering markdown views: %v", err)
+	}
+	for _, path := range skipped {
+		ui.Warn("%s was not generated by autospec; run 'autospec render --force' to replace it", path)
 	}
+	if len(written) > 0 {
+		e.debugLog("Rendered markdown views: %s", strings.Join(written, ", "))
+	}
+}
 
-	return e.executeStageLoop(ctx)
+// writeTranscript writes the stage's transcript to the spec directory.
+// Specify runs before the spec name is known, so its transcript is written by
+// the stage executor once the new spec is detected. Failures only warn: a
+// missing transcript must not fail a stage.
+func (e *Executor) writeTranscript(specName string, stage Stage) {
+	if specName == "" {
+		return
+	}
+	path, err := e.Transcript.Write(filepath.Join(e.SpecsDir, specName), string(stage))
+	if err != nil {
+		output.Default().Warn("%v", err)
+		return
+	}
+	if path != "" {
+		e.debugLog("Wrote transcript: %s", path)
+	}
 }
 
 // stageExecutionContext holds state for stage execution loop
@@ -157,7 +311,10 @@ type stageExecutionContext struct {
 	result               *StageResult
 	retryState           *retry.RetryState
 	lastValidationErrors []string
-	interactive          bool // When true, skip retry loop and use interactive mode
+	interactive          bool              // When true, skip retry loop and use interactive mode
+	stagingDir           string            // Staging copy of the spec dir the agent writes to ("" when not staged)
+	attempts             int               // Agent sessions that ran to a result, for stage stats
+	failures             []stagestats.Kind // Class of each failed attempt
 }
 
 // executeStageLoop runs the retry loop for stage execution.
@@ -169,11 +326,22 @@ func (e *Executor) executeStageLoop(ctx *stageExecutionContext) (*StageResult, e
 	}
 
 	for {
+		if err := e.checkInterrupted(ctx); err != nil {
+			return ctx.result, err
+		}
+		if err := e.checkBudget(ctx); err != nil {
+			return ctx.result, err
+		}
+
 		stageInfo := e.buildStageInfo(ctx.stage, ctx.retryState.Count)
 		e.startProgressDisplay(stageInfo)
+		e.Status.StartStage(ctx.specName, string(ctx.stage), ctx.retryState.Count+1, e.MaxRetries)
 
 		stageErr, validationErr := e.executeStageAttempt(ctx, stageInfo)
 
+		if errors.Is(stageErr, errRateLimitWaited) {
+			continue
+		}
 		if stageErr != nil {
 			return ctx.result, stageErr
 		}
@@ -191,14 +359,33 @@ func (e *Executor) executeStageLoop(ctx *stageExecutionContext) (*StageResult, e
 // Interactive stages skip validation and rely on user conversation.
 func (e *Executor) executeInteractiveStage(ctx *stageExecutionContext) (*StageResult, error) {
 	e.debugLog("Executing interactive stage: %s", ctx.stage)
+	if err := e.checkInterrupted(ctx); err != nil {
+		return ctx.result, err
+	}
+	if err := e.checkBudget(ctx); err != nil {
+		return ctx.result, err
+	}
+	e.Status.StartStage(ctx.specName, string(ctx.stage), 1, 0)
 
 	e.displayInteractiveCommandExecution(ctx.currentCommand)
-	if err := e.Claude.ExecuteInteractive(ctx.currentCommand); err != nil {
+	e.Budget.StartSession(string(ctx.stage), ctx.currentCommand)
+	e.Transcript.StartSession(string(ctx.stage), ctx.currentCommand)
+	err := e.Claude.ExecuteInteractive(ctx.currentCommand)
+	e.Budget.EndSession()
+	e.Transcript.EndSession(err)
+	if err != nil {
 		output.PrintAgentOutputEnd(os.Stdout)
+		if errors.Is(err, interrupt.ErrInterrupted) {
+			return ctx.result, e.cancelStage(ctx, err)
+		}
 		ctx.result.Error = fmt.Errorf("interactive session failed: %w", err)
+		e.recordStageEvent(ctx, events.TypeExecutionFailed, err.Error(), nil)
+		e.Status.FailStage(ctx.result.Error)
 		return ctx.result, ctx.result.Error
 	}
 	output.PrintAgentOutputEnd(os.Stdout)
+	e.recordStageEvent(ctx, events.TypeStageComplete, "interactive session ended", nil)
+	e.Status.CompleteStage(string(ctx.stage))
 
 	ctx.result.Success = true
 	e.debugLog("Interactive stage %s completed", ctx.stage)
@@ -209,23 +396,56 @@ func (e *Executor) executeInteractiveStage(ctx *stageExecutionContext) (*StageRe
 func (e *Executor) executeStageAttempt(ctx *stageExecutionContext, stageInfo progress.StageInfo) (stageErr, validationErr error) {
 	_ = lifecycle.RunStage(e.NotificationHandler, string(ctx.stage), func() error {
 		e.displayCommandExecution(ctx.currentCommand)
-		if err := e.Claude.Execute(ctx.currentCommand); err != nil {
+		e.Budget.StartSession(string(ctx.stage), ctx.currentCommand)
+		e.Transcript.StartSession(string(ctx.stage), ctx.currentCommand)
+		e.RateLimit.StartSession()
+		e.Salvage.Reset()
+		snapshot := e.snapshotForFormatters(ctx.stage)
+		err := e.Claude.Execute(ctx.currentCommand)
+		e.Budget.EndSession()
+		e.Transcript.EndSession(err)
+		if err != nil {
 			output.PrintAgentOutputEnd(os.Stdout)
+			if errors.Is(err, interrupt.ErrInterrupted) {
+				e.failStageProgress(stageInfo, err)
+				stageErr = e.cancelStage(ctx, err)
+				return stageErr
+			}
+			if waited, waitErr := e.waitOutRateLimit(ctx, err); waited {
+				if waitErr != nil {
+					e.failStageProgress(stageInfo, waitErr)
+					stageErr = e.cancelStage(ctx, waitErr)
+					return stageErr
+				}
+				stageErr = errRateLimitWaited
+				return stageErr
+			}
+			e.recordStageEvent(ctx, events.TypeExecutionFailed, err.Error(), nil)
+			e.noteAttempt(ctx, classifyExecutionFailure(err))
 			stageErr = e.handleExecutionFailure(ctx.result, ctx.retryState, stageInfo, err)
 			return stageErr
 		}
 		output.PrintAgentOutputEnd(os.Stdout)
 		e.debugLog("Claude.Execute() completed successfully")
+		e.formatChangedFiles(ctx, snapshot)
 
-		specDir := fmt.Sprintf("%s/%s", e.SpecsDir, ctx.specName)
-		if err := ctx.validateFunc(specDir); err != nil {
+		err = ctx.validateFunc(e.stageSpecDir(ctx))
+		if err != nil && e.salvageArtifact(ctx) {
+			err = nil
+		}
+		if err != nil {
 			validationErr = err
 			ctx.result.ValidationErrors = ExtractValidationErrors(err)
 			ctx.lastValidationErrors = ctx.result.ValidationErrors
 			e.debugLog("Validation failed: %v", err)
+			e.recordStageEvent(ctx, events.TypeValidationFailed, "", ctx.lastValidationErrors)
+			e.noteAttempt(ctx, classifyValidationFailure(err))
 			return err
 		}
 		e.debugLog("Validation passed!")
+		e.noteAttempt(ctx, "")
+		e.recordStageEvent(ctx, events.TypeValidationPassed, "", nil)
+		e.recordStageEvent(ctx, events.TypeStageComplete, "", nil)
 
 		e.completeStageSuccessNoNotify(ctx.result, stageInfo, ctx.specName, ctx.stage)
 		return nil
@@ -237,29 +457,222 @@ func (e *Executor) executeStageAttempt(ctx *stageExecutionContext, stageInfo pro
 // Returns (done bool, err error) - done=true means stop the loop
 func (e *Executor) handleStageRetry(ctx *stageExecutionContext, stageInfo progress.StageInfo, validationErr error) (bool, error) {
 	if !ctx.retryState.CanRetry() {
+		if rescued, done, err := e.rescueExhaustedStage(ctx, stageInfo, validationErr); rescued {
+			return done, err
+		}
 		ctx.result.Exhausted = true
 		ctx.result.RetryCount = ctx.retryState.Count
 		ctx.result.Error = fmt.Errorf("validation failed: %w", validationErr)
 		e.failStageProgress(stageInfo, ctx.result.Error)
-		return true, fmt.Errorf("validation failed and retry exhausted: %w", validationErr)
+		e.recordStageEvent(ctx, events.TypeRetriesExhausted,
+			fmt.Sprintf("%d/%d retries used", ctx.retryState.Count, e.MaxRetries), nil)
+		return true, apperrors.ErrRetryExhausted.Errorf("validation failed and retry exhausted: %w", validationErr)
+	}
+	if err := e.useRetryBudget(ctx, stageInfo); err != nil {
+		return true, err
 	}
 
 	if err := ctx.retryState.Increment(); err != nil {
 		return true, fmt.Errorf("failed to increment retry: %w", err)
 	}
+	ctx.retryState.RunID = e.RunID
 	if err := retry.SaveRetryState(e.StateDir, ctx.retryState); err != nil {
 		return true, fmt.Errorf("failed to save retry state: %w", err)
 	}
 
-	retryContext := FormatRetryContext(ctx.retryState.Count, e.MaxRetries, ctx.lastValidationErrors)
-	ctx.currentCommand = BuildRetryCommand(ctx.command, retryContext, "")
+	command, strategy := e.buildRetryPrompt(ctx)
+	ctx.currentCommand = command
 	ctx.result.RetryCount = ctx.retryState.Count
+	e.recordStageEvent(ctx, events.TypeRetry,
+		fmt.Sprintf("retry %d/%d (%s): validation failed, injecting %d error(s)", ctx.retryState.Count, e.MaxRetries, strategy, len(ctx.lastValidationErrors)), nil)
 
-	e.debugLog("Retrying (attempt %d/%d) with error context", ctx.retryState.Count, e.MaxRetries)
-	fmt.Printf("\n⟳ Retry %d/%d - injecting validation errors into command\n", ctx.retryState.Count, e.MaxRetries)
+	e.debugLog("Retrying (attempt %d/%d) with %s strategy", ctx.retryState.Count, e.MaxRetries, strategy)
+	fmt.Printf("\n⟳ Retry %d/%d - %s\n", ctx.retryState.Count, e.MaxRetries, retryStrategyDescriptions[strategy])
 	return false, nil
 }
 
+// errRateLimitWaited tells the stage loop to rerun an attempt after waiting
+// out a rate limit. The attempt does not count as a retry.
+var errRateLimitWaited = errors.New("waited for rate limit")
+
+// waitOutRateLimit waits when err is a *RateLimitedError and the run's
+// rate_limit.max_wait allows it. waited is false when the failure should be
+// handled as an ordinary execution failure; a non-nil error means a signal
+// interrupted the wait.
+func (e *Executor) waitOutRateLimit(ctx *stageExecutionContext, err error) (waited bool, waitErr error) {
+	var rlErr *RateLimitedError
+	if e.RateLimit == nil || !errors.As(err, &rlErr) {
+		return false, nil
+	}
+	delay, err := e.RateLimit.Delay(rlErr.Signal)
+	if err != nil {
+		output.Default().Warn("%v", err)
+		e.recordStageEvent(ctx, events.TypeRateLimited, err.Error(), nil)
+		return false, nil
+	}
+
+	msg := fmt.Sprintf("agent rate limited; waiting %s before rerunning (attempt not counted as a retry)", delay.Round(time.Second))
+	if rlErr.Signal.Provider != "" {
+		msg = fmt.Sprintf("%s rate limit; waiting %s before rerunning (attempt not counted as a retry)", rlErr.Signal.Provider, delay.Round(time.Second))
+	}
+	output.Default().Warn("%s", msg)
+	e.recordStageEvent(ctx, events.TypeRateLimited, msg, []string{rlErr.Signal.Line})
+	e.sendRateLimitNotification(string(ctx.stage), delay)
+
+	e.Status.Pause()
+	if err := e.RateLimit.Wait(interrupt.Context(), delay); err != nil {
+		return true, err
+	}
+	e.Status.Resume()
+	return true, nil
+}
+
+// checkBudget stops the stage before its next agent session once a run or stage
+// budget is used up. Retry state is left as-is so rerunning the command resumes.
+func (e *Executor) checkBudget(ctx *stageExecutionContext) error {
+	err := e.Budget.Check(string(ctx.stage))
+	if err == nil {
+		return nil
+	}
+	ctx.result.Error = err
+	e.recordStageEvent(ctx, events.TypeBudgetExceeded, err.Error(), nil)
+	e.Status.FailStage(err)
+	e.sendBudgetNotification(string(ctx.stage), err)
+	return err
+}
+
+// useRetryBudget takes a retry from the run's retry budget
+// (budget.max_run_retries). Once it is used up the stage stops like any
+// exceeded budget, with a summary of where the run's retries went. Retry
+// state is left as-is so rerunning the command resumes.
+func (e *Executor) useRetryBudget(ctx *stageExecutionContext, stageInfo progress.StageInfo) error {
+	scope := string(ctx.stage)
+	if e.scope.taskID != "" {
+		scope += " " + e.scope.taskID
+	} else if e.scope.phase > 0 {
+		scope += fmt.Sprintf(" phase %d", e.scope.phase)
+	}
+	err := e.Budget.UseRetry(scope)
+	if err == nil {
+		return nil
+	}
+
+	var summary []string
+	for _, use := range e.Budget.Retries() {
+		summary = append(summary, fmt.Sprintf("%s: %d retr%s", use.Scope, use.Count, retrySuffix(use.Count)))
+	}
+	output.Default().Warn("Run retry budget used up; retries this run:")
+	for _, line := range summary {
+		fmt.Printf("  %s\n", line)
+	}
+
+	ctx.result.Error = err
+	e.failStageProgress(stageInfo, err)
+	e.recordStageEvent(ctx, events.TypeBudgetExceeded, err.Error(), summary)
+	e.Status.FailStage(err)
+	e.sendBudgetNotification(string(ctx.stage), err)
+	return err
+}
+
+// retrySuffix returns the plural suffix for n retries.
+func retrySuffix(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
+
+// checkInterrupted stops the stage before its next agent session once a signal
+// has interrupted the run.
+func (e *Executor) checkInterrupted(ctx *stageExecutionContext) error {
+	if !interrupt.Requested() {
+		return nil
+	}
+	return e.cancelStage(ctx, interrupt.ErrInterrupted)
+}
+
+// waitIfPaused blocks between tasks or phases while the project's pause file
+// exists. next names the item that runs once the user continues.
+func (e *Executor) waitIfPaused(next string) error {
+	if e == nil || !pause.Requested(e.PauseFile) {
+		return nil
+	}
+	ui := output.Default()
+	ui.Warn("Paused before %s. Continue with 'autospec resume --continue' or press 'p'", next)
+	e.Status.Pause()
+	if err := pause.Wait(interrupt.Context(), e.PauseFile, pause.PollInterval); err != nil {
+		return err
+	}
+	e.Status.Resume()
+	ui.Info("Resuming with %s", next)
+	return nil
+}
+
+// listenForPause lets the user press 'p' to pause or continue a task or phase
+// loop. The returned function restores the terminal.
+func (e *Executor) listenForPause(unit string) func() {
+	if e == nil || e.PauseFile == "" {
+		return func() {}
+	}
+	e.keys = &pauseKeys{unit: unit, stop: e.startPauseKeys(unit)}
+	return func() {
+		if e.keys != nil {
+			e.keys.stop()
+			e.keys = nil
+		}
+	}
+}
+
+// pauseKeys is the 'p' key listener of the running task or phase loop.
+type pauseKeys struct {
+	unit string
+	stop func()
+}
+
+// startPauseKeys starts listening for 'p' and returns the function that stops it.
+func (e *Executor) startPauseKeys(unit string) func() {
+	return pause.ListenKeys(e.PauseFile, e.ProjectRoot, func(paused bool) {
+		if paused {
+			output.Default().Info("Pause requested: the current %s will finish first (press 'p' to cancel)", unit)
+			return
+		}
+		output.Default().Info("Pause cancelled")
+	})
+}
+
+// suspendPauseKeys stops the 'p' key listener, which reads stdin key by key,
+// so a prompt can read whole lines. The returned function restarts it.
+func (e *Executor) suspendPauseKeys() (resume func()) {
+	keys := e.keys
+	if keys == nil {
+		return func() {}
+	}
+	keys.stop()
+	return func() { keys.stop = e.startPauseKeys(keys.unit) }
+}
+
+// cancelStage records that a signal stopped the stage. Retry state is left
+// untouched and no error notification is sent: the command reports the
+// interruption once, through the on_cancelled hook.
+func (e *Executor) cancelStage(ctx *stageExecutionContext, err error) error {
+	ctx.result.Error = err
+	e.recordStageEvent(ctx, events.TypeCancelled, err.Error(), nil)
+	e.Status.Cancel()
+	return err
+}
+
+// recordStageEvent appends an event for the current stage attempt to the run log.
+func (e *Executor) recordStageEvent(ctx *stageExecutionContext, typ events.Type, message string, errs []string) {
+	e.Events.Record(events.Event{
+		Type:    typ,
+		Spec:    ctx.specName,
+		Stage:   string(ctx.stage),
+		Attempt: ctx.retryState.Count + 1,
+		Message: message,
+		Errors:  errs,
+	})
+}
+
 // loadStageRetryState loads retry state for a stage
 func (e *Executor) loadStageRetryState(specName string, stage Stage) (*retry.RetryState, error) {
 	e.debugLog("Loading retry state from: %s", e.StateDir)
@@ -330,6 +743,8 @@ func (e *Executor) formatInteractiveCommand(prompt string) string {
 // failStageProgress marks a stage as failed in the progress display.
 // Uses Progress controller if set, falls back to deprecated ProgressDisplay field.
 func (e *Executor) failStageProgress(stageInfo progress.StageInfo, err error) {
+	e.Status.FailStage(err)
+
 	// Prefer new Progress controller
 	if e.Progress != nil {
 		e.Progress.FailStage(stageInfo, err)
@@ -378,6 +793,58 @@ func (e *Executor) sendErrorNotification(stageName string, err error) {
 	}
 }
 
+// sendBudgetNotification dispatches a budget-exceeded notification.
+// Uses Notify dispatcher if it has a handler, falls back to deprecated NotificationHandler field.
+func (e *Executor) sendBudgetNotification(stageName string, err error) {
+	if e.Notify != nil && e.Notify.HasHandler() {
+		e.Notify.OnBudgetExceeded(stageName, err)
+		return
+	}
+	if e.NotificationHandler != nil {
+		e.NotificationHandler.OnBudgetExceeded(stageName, err)
+	}
+}
+
+// sendRateLimitNotification dispatches a notification that the run is
+// waiting out a provider rate limit.
+// Uses Notify dispatcher if it has a handler, falls back to deprecated NotificationHandler field.
+func (e *Executor) sendRateLimitNotification(stageName string, wait time.Duration) {
+	if e.Notify != nil && e.Notify.HasHandler() {
+		e.Notify.OnRateLimited(stageName, wait)
+		return
+	}
+	if e.NotificationHandler != nil {
+		e.NotificationHandler.OnRateLimited(stageName, wait)
+	}
+}
+
+// notifyHandler returns the notification handler, preferring the Notify dispatcher
+// over the deprecated NotificationHandler field. May be nil.
+func (e *Executor) notifyHandler() *notify.Handler {
+	if e.Notify != nil && e.Notify.HasHandler() {
+		return e.Notify.Handler()
+	}
+	return e.NotificationHandler
+}
+
+// watchStage starts the on_long_running watchdog for stage and returns its stop function.
+// Task and phase loops wrap the whole loop so the stage is timed across sessions.
+func (e *Executor) watchStage(stage Stage) func() {
+	return e.notifyHandler().WatchStage(string(stage))
+}
+
+// setCurrentTask names the active task or phase in later notifications,
+// long-running ones included. id is a task ID or "phase N/M".
+func (e *Executor) setCurrentTask(id, title string) {
+	h := e.notifyHandler()
+	h.SetTask(id, title)
+	if id == "" {
+		h.SetCurrentTask("")
+		return
+	}
+	h.SetCurrentTask(fmt.Sprintf("%s: %s", id, title))
+}
+
 // handleExecutionFailure handles command execution failure without sending stage notification.
 // Stage notification is handled by lifecycle.RunStage wrapper.
 // Uses Progress/Notify controllers if set, falls back to deprecated fields.
@@ -419,12 +886,13 @@ func (e *Executor) handleValidationFailure(result *StageResult, retryState *retr
 
 // handleRetryIncrement increments retry count and handles exhaustion
 func (e *Executor) handleRetryIncrement(result *StageResult, retryState *retry.RetryState, originalErr error, exhaustedMsg string) (*StageResult, error) {
+	retryState.RunID = e.RunID
 	if incrementErr := retryState.Increment(); incrementErr != nil {
 		if exhaustedErr, ok := incrementErr.(*retry.RetryExhaustedError); ok {
 			result.Exhausted = true
 			result.RetryCount = exhaustedErr.Count
 			retry.SaveRetryState(e.StateDir, retryState)
-			return result, fmt.Errorf("%s: %w", exhaustedMsg, originalErr)
+			return result, apperrors.ErrRetryExhausted.Errorf("%s: %w", exhaustedMsg, originalErr)
 		}
 		return result, incrementErr
 	}
@@ -446,6 +914,7 @@ func (e *Executor) completeStageSuccessNoNotify(result *StageResult, stageInfo p
 
 	// Complete stage in progress display
 	e.completeStageProgress(stageInfo)
+	e.Status.CompleteStage(string(stage))
 
 	e.debugLog("Resetting retry count")
 	if err := retry.ResetRetryCount(e.StateDir, specName, string(stage)); err != nil {
@@ -2,14 +2,24 @@ package workflow
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ariel-frischer/autospec/internal/budget"
 	"github.com/ariel-frischer/autospec/internal/cliagent"
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/ariel-frischer/autospec/internal/pause"
 	"github.com/ariel-frischer/autospec/internal/progress"
+	"github.com/ariel-frischer/autospec/internal/ratelimit"
 	"github.com/ariel-frischer/autospec/internal/retry"
+	"github.com/ariel-frischer/autospec/internal/statusfile"
+	"github.com/ariel-frischer/autospec/internal/transcript"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -240,6 +250,359 @@ func TestExecuteStage_RetryExhausted(t *testing.T) {
 	assert.True(t, result.Exhausted)
 }
 
+// TestExecuteStage_RecordsEvents verifies the run event log captures the
+// stage start, built command, validation failure, retry, and final success.
+func TestExecuteStage_RecordsEvents(t *testing.T) {
+	stateDir := t.TempDir()
+	specsDir := t.TempDir()
+
+	executor := &Executor{
+		Claude:     &mockClaudeExecutor{},
+		StateDir:   stateDir,
+		SpecsDir:   specsDir,
+		MaxRetries: 3,
+		Events:     events.NewRecorder(stateDir, "test_run"),
+	}
+
+	calls := 0
+	validateFunc := func(dir string) error {
+		calls++
+		if calls == 1 {
+			return errors.New("schema validation failed:\n- missing field: summary")
+		}
+		return nil
+	}
+
+	_, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", validateFunc)
+	require.NoError(t, err)
+
+	evs, err := events.Load(stateDir, "test_run")
+	require.NoError(t, err)
+
+	var types []events.Type
+	for _, ev := range evs {
+		types = append(types, ev.Type)
+	}
+	assert.Equal(t, []events.Type{
+		events.TypeStageStart,
+		events.TypeCommandBuilt,
+		events.TypeValidationFailed,
+		events.TypeRetry,
+		events.TypeValidationPassed,
+		events.TypeStageComplete,
+	}, types)
+	assert.Equal(t, "/autospec.plan", evs[1].Command)
+	assert.Equal(t, []string{"missing field: summary"}, evs[2].Errors)
+	assert.Equal(t, 2, evs[3].Attempt)
+}
+
+func TestExecuteStage_UpdatesStatusFile(t *testing.T) {
+	stateDir := t.TempDir()
+	specsDir := t.TempDir()
+	statusPath := filepath.Join(t.TempDir(), statusfile.FileName)
+
+	executor := &Executor{
+		Claude:     &mockClaudeExecutor{},
+		StateDir:   stateDir,
+		SpecsDir:   specsDir,
+		MaxRetries: 3,
+		Status:     statusfile.New(statusPath, "test_run"),
+	}
+	executor.Status.SetTotalStages(2)
+
+	_, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error { return nil })
+	require.NoError(t, err)
+
+	st, err := statusfile.Load(statusPath)
+	require.NoError(t, err)
+	assert.Equal(t, statusfile.StateRunning, st.State)
+	assert.Equal(t, "001-test", st.Spec)
+	assert.Equal(t, "plan", st.Stage.Name)
+	assert.Equal(t, 50, st.Progress.StagesPercent)
+
+	_, err = executor.ExecuteStage("001-test", StageTasks, "/autospec.tasks", func(string) error {
+		return errors.New("execution failed")
+	})
+	require.Error(t, err)
+
+	st, err = statusfile.Load(statusPath)
+	require.NoError(t, err)
+	assert.Equal(t, statusfile.StateFailed, st.State)
+	assert.Equal(t, "tasks", st.Stage.Name)
+	assert.NotEmpty(t, st.Error)
+}
+
+func TestExecuteStage_StopsWhenBudgetExceeded(t *testing.T) {
+	stateDir := t.TempDir()
+	specsDir := t.TempDir()
+	statusPath := filepath.Join(t.TempDir(), statusfile.FileName)
+
+	mock := &mockClaudeExecutor{}
+	executor := &Executor{
+		Claude:     mock,
+		StateDir:   stateDir,
+		SpecsDir:   specsDir,
+		MaxRetries: 3,
+		Status:     statusfile.New(statusPath, "test_run"),
+		// The plan prompt alone is estimated at ~4 tokens
+		Budget: budget.New(budget.Config{MaxStageTokens: 3}),
+	}
+
+	_, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error { return nil })
+	require.NoError(t, err)
+	require.Len(t, mock.executeCalls, 1)
+
+	// Other stages still have budget
+	_, err = executor.ExecuteStage("001-test", StageTasks, "/autospec.tasks", func(string) error { return nil })
+	require.NoError(t, err)
+
+	result, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error { return nil })
+	var exceededErr *budget.ExceededError
+	require.ErrorAs(t, err, &exceededErr)
+	assert.Equal(t, "stage", exceededErr.Scope)
+	assert.Equal(t, "plan", exceededErr.Stage)
+	assert.Len(t, mock.executeCalls, 2, "no agent session should start after the budget is exceeded")
+	assert.False(t, result.Exhausted)
+
+	retryState, err := retry.LoadRetryState(stateDir, "001-test", string(StagePlan), 3)
+	require.NoError(t, err)
+	assert.Equal(t, 0, retryState.Count, "budget stops must not consume retries")
+
+	st, err := statusfile.Load(statusPath)
+	require.NoError(t, err)
+	assert.Equal(t, statusfile.StateFailed, st.State)
+	assert.Contains(t, st.Error, "budget exceeded")
+}
+
+func TestExecuteStage_StopsWhenRetryBudgetUsedUp(t *testing.T) {
+	stateDir := t.TempDir()
+	mock := &mockClaudeExecutor{}
+	executor := &Executor{
+		Claude:     mock,
+		StateDir:   stateDir,
+		SpecsDir:   t.TempDir(),
+		MaxRetries: 2,
+		Budget:     budget.New(budget.Config{MaxRunRetries: 3}),
+	}
+	failing := func(string) error { return errors.New("- missing field") }
+
+	restore := executor.scopeSessions("T001", 0)
+	_, err := executor.ExecuteStage("001-test", StageImplement, "/autospec.implement --task T001", failing)
+	restore()
+	require.ErrorIs(t, err, apperrors.ErrRetryExhausted)
+	require.Len(t, mock.executeCalls, 3)
+
+	// One retry is left for plan
+	result, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", failing)
+	var exceededErr *budget.ExceededError
+	require.ErrorAs(t, err, &exceededErr)
+	assert.Equal(t, "retries", exceededErr.Resource)
+	assert.Len(t, mock.executeCalls, 5, "no retry should start once the run budget is used up")
+	assert.False(t, result.Exhausted)
+	assert.Equal(t, []budget.RetryUse{{Scope: "implement T001", Count: 2}, {Scope: "plan", Count: 1}}, executor.Budget.Retries())
+
+	retryState, err := retry.LoadRetryState(stateDir, "001-test", string(StagePlan), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, retryState.Count, "the refused retry is not recorded")
+}
+
+// sequenceClaudeExecutor returns errs in order, then nil.
+type sequenceClaudeExecutor struct {
+	mockClaudeExecutor
+	errs []error
+}
+
+func (m *sequenceClaudeExecutor) Execute(prompt string) error {
+	m.executeCalls = append(m.executeCalls, prompt)
+	if len(m.errs) == 0 {
+		return nil
+	}
+	err := m.errs[0]
+	m.errs = m.errs[1:]
+	return err
+}
+
+func TestExecuteStage_WaitsOutRateLimit(t *testing.T) {
+	rateLimited := &RateLimitedError{
+		Signal: ratelimit.Signal{Provider: "anthropic", RetryAfter: 10 * time.Millisecond, Line: `{"type":"error","error":{"type":"rate_limit_error"}}`},
+		Err:    errors.New("agent claude exited with code 1"),
+	}
+
+	tests := map[string]struct {
+		maxWait     time.Duration
+		wantErr     bool
+		wantCalls   int
+		wantRetries int
+	}{
+		"waits and reruns without using a retry": {
+			maxWait:   time.Minute,
+			wantCalls: 2,
+		},
+		"wait cap reached fails like any execution error": {
+			maxWait:     time.Millisecond,
+			wantErr:     true,
+			wantCalls:   1,
+			wantRetries: 1,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			stateDir := t.TempDir()
+			mock := &sequenceClaudeExecutor{errs: []error{rateLimited}}
+			executor := &Executor{
+				Claude:     mock,
+				StateDir:   stateDir,
+				SpecsDir:   t.TempDir(),
+				MaxRetries: 3,
+				Events:     events.NewRecorder(stateDir, "test_run"),
+				RateLimit:  ratelimit.New(ratelimit.Config{Enabled: true, MaxWait: tt.maxWait, InitialBackoff: time.Second}),
+			}
+
+			_, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error { return nil })
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Len(t, mock.executeCalls, tt.wantCalls)
+
+			retryState, err := retry.LoadRetryState(stateDir, "001-test", string(StagePlan), 3)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRetries, retryState.Count)
+
+			evs, err := events.Load(stateDir, "test_run")
+			require.NoError(t, err)
+			var limited []events.Event
+			for _, ev := range evs {
+				if ev.Type == events.TypeRateLimited {
+					limited = append(limited, ev)
+				}
+			}
+			require.Len(t, limited, 1)
+			if !tt.wantErr {
+				assert.Contains(t, limited[0].Message, "anthropic rate limit")
+				assert.Equal(t, []string{rateLimited.Signal.Line}, limited[0].Errors)
+			}
+		})
+	}
+}
+
+func TestExecuteStage_InterruptedAgentKeepsRetryState(t *testing.T) {
+	stateDir := t.TempDir()
+	statusPath := filepath.Join(t.TempDir(), statusfile.FileName)
+
+	mock := &mockClaudeExecutor{executeErr: fmt.Errorf("agent claude stopped: %w", interrupt.ErrInterrupted)}
+	executor := &Executor{
+		Claude:     mock,
+		StateDir:   stateDir,
+		SpecsDir:   t.TempDir(),
+		MaxRetries: 3,
+		Status:     statusfile.New(statusPath, "test_run"),
+	}
+
+	_, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error { return nil })
+	require.ErrorIs(t, err, interrupt.ErrInterrupted)
+	assert.Len(t, mock.executeCalls, 1, "an interrupted stage must not be retried")
+
+	retryState, err := retry.LoadRetryState(stateDir, "001-test", string(StagePlan), 3)
+	require.NoError(t, err)
+	assert.Equal(t, 0, retryState.Count, "interrupts must not consume retries")
+
+	st, err := statusfile.Load(statusPath)
+	require.NoError(t, err)
+	assert.Equal(t, statusfile.StateCancelled, st.State)
+}
+
+func TestExecutor_WaitIfPaused(t *testing.T) {
+	pauseFile := pause.Path(t.TempDir(), "/src/app")
+	statusPath := filepath.Join(t.TempDir(), statusfile.FileName)
+	executor := &Executor{
+		ProjectRoot: "/src/app",
+		PauseFile:   pauseFile,
+		Status:      statusfile.New(statusPath, "test_run"),
+	}
+
+	require.NoError(t, executor.waitIfPaused("task T001"), "no pause file means no wait")
+
+	require.NoError(t, pause.Request(pause.Path(filepath.Dir(pauseFile), "/src/other"), "/src/other"))
+	require.NoError(t, executor.waitIfPaused("task T001"), "another project's pause is ignored")
+
+	require.NoError(t, pause.Request(pauseFile, "/src/app"))
+	paused := make(chan string, 1)
+	go func() {
+		// Wait for the executor to report the pause, then continue the run
+		for {
+			if st, err := statusfile.Load(statusPath); err == nil && st.State == statusfile.StatePaused {
+				paused <- st.State
+				_, _ = pause.Clear(pauseFile)
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	require.NoError(t, executor.waitIfPaused("task T002"))
+	assert.Equal(t, statusfile.StatePaused, <-paused)
+	st, err := statusfile.Load(statusPath)
+	require.NoError(t, err)
+	assert.Equal(t, statusfile.StateRunning, st.State)
+}
+
+func TestExecuteStage_WritesTranscript(t *testing.T) {
+	specsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "001-test"), 0o755))
+
+	mock := &mockClaudeExecutor{}
+	executor := &Executor{
+		Claude:     mock,
+		StateDir:   t.TempDir(),
+		SpecsDir:   specsDir,
+		MaxRetries: 1,
+		Transcript: transcript.New(nil),
+	}
+
+	// First attempt fails validation, the retry passes
+	attempts := 0
+	_, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("missing plan.yaml")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(specsDir, "001-test", "transcript-plan.md"))
+	require.NoError(t, err)
+	content := string(data)
+	assert.Contains(t, content, "# Transcript: plan")
+	assert.Contains(t, content, "## Session 1")
+	assert.Contains(t, content, "## Session 2")
+	assert.Contains(t, content, "missing plan.yaml", "retry prompt carries the validation error")
+}
+
+func TestExecuteStage_RendersMarkdownViews(t *testing.T) {
+	specsDir := t.TempDir()
+	specDir := filepath.Join(specsDir, "001-test")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "plan.yaml"), []byte("plan:\n  branch: \"001-test\"\nsummary: \"Add login\"\n"), 0o644))
+
+	executor := &Executor{
+		Claude:        &mockClaudeExecutor{},
+		StateDir:      t.TempDir(),
+		SpecsDir:      specsDir,
+		MarkdownViews: true,
+	}
+	_, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error { return nil })
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(specDir, "plan.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "# Implementation Plan: 001-test")
+	assert.Contains(t, string(data), "## Summary\n\nAdd login\n")
+}
+
 // TestExecuteStage_ResetsRetryOnSuccess verifies retry count resets on success.
 //
 // Scenario: Pre-existing retry count (2/3) → validation succeeds → retry count
@@ -1096,6 +1459,7 @@ func TestHandleValidationFailureRetryBehavior(t *testing.T) {
 			// Verify error message
 			assert.Error(t, returnErr)
 			assert.Contains(t, returnErr.Error(), tc.wantErrorContains)
+			assert.Equal(t, tc.wantExhausted, errors.Is(returnErr, apperrors.ErrRetryExhausted))
 
 			// Verify result state
 			if tc.wantExhausted {
@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"errors"
+	"time"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/stagestats"
+)
+
+// classifyExecutionFailure classifies an agent session that failed to run.
+func classifyExecutionFailure(err error) stagestats.Kind {
+	if errors.Is(err, apperrors.ErrAgentTimeout) {
+		return stagestats.KindTimeout
+	}
+	return stagestats.KindAgentError
+}
+
+// classifyValidationFailure classifies a session whose output was rejected:
+// a failing task command or targeted test is a gate failure, anything else
+// a validation failure.
+func classifyValidationFailure(err error) stagestats.Kind {
+	var gateErr *TaskCommandError
+	if errors.As(err, &gateErr) {
+		return stagestats.KindGate
+	}
+	return stagestats.KindValidation
+}
+
+// noteAttempt counts an agent session that ran to a result; kind classifies
+// its failure, or is empty when it passed. The failure is also named in later
+// notifications.
+func (e *Executor) noteAttempt(ctx *stageExecutionContext, kind stagestats.Kind) {
+	ctx.attempts++
+	e.notifyHandler().SetFailure(string(kind))
+	if kind == "" {
+		return
+	}
+	ctx.failures = append(ctx.failures, kind)
+	ctx.result.Failure = kind
+}
+
+// recordStageStats adds the stage execution to the failure history in the
+// state directory. Interactive stages and executions stopped before any
+// session finished are not recorded. Failures only log: stats must not fail
+// a stage.
+func (e *Executor) recordStageStats(ctx *stageExecutionContext) {
+	if ctx.interactive || ctx.attempts == 0 || e.StateDir == "" {
+		return
+	}
+	err := stagestats.RecordRun(e.StateDir, stagestats.Run{
+		Spec:      ctx.specName,
+		Stage:     string(ctx.stage),
+		Attempts:  ctx.attempts,
+		Failures:  ctx.failures,
+		Succeeded: ctx.result.Success,
+		At:        time.Now(),
+	})
+	if err != nil {
+		e.debugLog("Recording stage stats: %v", err)
+	}
+}
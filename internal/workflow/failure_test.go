@@ -0,0 +1,64 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/stagestats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	t.Parallel()
+
+	gateErr := &TaskCommandError{TaskID: "T001", TaskType: "test", Command: "go test ./...", Err: errors.New("exit status 1")}
+	tests := map[string]struct {
+		got  stagestats.Kind
+		want stagestats.Kind
+	}{
+		"agent error": {got: classifyExecutionFailure(errors.New("exit status 2")), want: stagestats.KindAgentError},
+		"timeout":     {got: classifyExecutionFailure(&TimeoutError{Timeout: time.Minute, Command: "claude"}), want: stagestats.KindTimeout},
+		"validation":  {got: classifyValidationFailure(errors.New("- missing field")), want: stagestats.KindValidation},
+		"gate":        {got: classifyValidationFailure(fmt.Errorf("task T001: %w", gateErr)), want: stagestats.KindGate},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tt.got)
+		})
+	}
+}
+
+func TestExecuteStage_RecordsStageStats(t *testing.T) {
+	stateDir := t.TempDir()
+	executor := &Executor{
+		Claude:     &mockClaudeExecutor{},
+		StateDir:   stateDir,
+		SpecsDir:   t.TempDir(),
+		MaxRetries: 3,
+	}
+	calls := 0
+	result, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error {
+		calls++
+		if calls == 1 {
+			return errors.New("- missing field")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Equal(t, stagestats.KindValidation, result.Failure)
+
+	store, err := stagestats.Load(stateDir)
+	require.NoError(t, err)
+	rec := store.Records["001-test:plan"]
+	require.NotNil(t, rec)
+	assert.Equal(t, 1, rec.Runs)
+	assert.Equal(t, 2, rec.Attempts)
+	assert.Equal(t, 1, rec.Succeeded)
+	assert.Equal(t, map[stagestats.Kind]int{stagestats.KindValidation: 1}, rec.Failures)
+}
@@ -0,0 +1,24 @@
+package workflow
+
+import (
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/ariel-frischer/autospec/internal/output"
+)
+
+// syncProject mirrors tasks.yaml into the GitHub project board. Like the
+// issue tracker hooks, API failures only warn: github-project.yaml records
+// what was synced, so the next hook or 'autospec sync github-project' picks
+// up whatever was missed.
+func (e *Executor) syncProject(specDir string) {
+	if e == nil || e.Project == nil {
+		return
+	}
+	ui := output.Default()
+	result, err := e.Project.Sync(interrupt.Context(), specDir)
+	if result.Created > 0 {
+		ui.Info("Added %d task(s) to GitHub project %s", result.Created, result.URL)
+	}
+	if err != nil {
+		ui.Warn("could not update GitHub project: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/ghproject"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingProjectClient is a ghproject.Client that records calls.
+type recordingProjectClient struct {
+	calls []string
+}
+
+func (c *recordingProjectClient) Project(context.Context) (ghproject.Project, error) {
+	return ghproject.Project{ID: "PVT_1", FieldID: "F1", Options: map[string]string{"todo": "o1", "in progress": "o2", "done": "o3"}}, nil
+}
+
+func (c *recordingProjectClient) AddDraftItem(_ context.Context, _, title, _ string) (string, error) {
+	c.calls = append(c.calls, "add "+title)
+	return "item-1", nil
+}
+
+func (c *recordingProjectClient) SetStatus(_ context.Context, _ ghproject.Project, itemID, optionID string) error {
+	c.calls = append(c.calls, "move "+itemID+" "+optionID)
+	return nil
+}
+
+func TestExecutor_SyncProject(t *testing.T) {
+	t.Parallel()
+
+	specDir := t.TempDir()
+	tasks := func(status string) {
+		content := "phases:\n  - number: 1\n    title: \"Core\"\n    tasks:\n" +
+			"      - id: \"T001\"\n        title: \"Write the test\"\n        status: \"" + status + "\"\n        type: \"implementation\"\n"
+		require.NoError(t, os.WriteFile(filepath.Join(specDir, "tasks.yaml"), []byte(content), 0o644))
+	}
+	tasks("Pending")
+
+	// Without a project the hook is a no-op
+	(&Executor{}).syncProject(specDir)
+	var nilExecutor *Executor
+	nilExecutor.syncProject(specDir)
+	assert.NoFileExists(t, filepath.Join(specDir, ghproject.ItemsFileName))
+
+	client := &recordingProjectClient{}
+	executor := &Executor{Project: ghproject.NewWithClient(ghproject.Config{Owner: "acme", Number: 1}, client)}
+	executor.syncProject(specDir)
+	tasks("Completed")
+	executor.syncProject(specDir)
+	executor.syncProject(specDir)
+
+	assert.Equal(t, []string{"add T001: Write the test", "move item-1 o1", "move item-1 o3"}, client.calls)
+	assert.FileExists(t, filepath.Join(specDir, ghproject.ItemsFileName))
+}
@@ -69,6 +69,16 @@ type StageExecutorInterface interface {
 	// Clarify refines the specification by asking targeted clarification questions.
 	ExecuteClarify(specName string, prompt string) error
 
+	// ExecuteAmend applies a change description to an existing spec.yaml.
+	ExecuteAmend(specName string, change string) error
+
+	// ExecuteForecast runs the forecast stage with optional prompt.
+	// Forecast estimates effort and risk for the tasks in tasks.yaml.
+	ExecuteForecast(specName string, prompt string) error
+
+	// ExecuteRetro runs a retrospection session that appends learnings.
+	ExecuteRetro(specName string, prompt string) error
+
 	// ExecuteChecklist runs the checklist stage with optional prompt.
 	// Checklist generates a custom checklist for the current feature.
 	ExecuteChecklist(specName string, prompt string) error
@@ -0,0 +1,58 @@
+package workflow
+
+import (
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/report"
+)
+
+// Issue tracker hooks. Tracker API failures never fail a stage: the links
+// file in the spec directory records what was synced, so the next hook
+// (or the next run) picks up whatever was missed.
+
+// linkIssues creates a tracker issue for each user story of a new spec.
+func (e *Executor) linkIssues(specDir string) {
+	if e == nil || e.Issues == nil {
+		return
+	}
+	ui := output.Default()
+	created, err := e.Issues.LinkStories(interrupt.Context(), specDir)
+	if created > 0 {
+		ui.Info("Created %d tracker issue(s) for %s", created, specDir)
+	}
+	if err != nil {
+		ui.Warn("could not link user stories to issues: %v", err)
+	}
+}
+
+// syncIssues moves linked issues to match task progress.
+func (e *Executor) syncIssues(specDir string) {
+	if e == nil || e.Issues == nil {
+		return
+	}
+	if _, err := e.Issues.SyncProgress(interrupt.Context(), specDir); err != nil {
+		output.Default().Warn("could not update tracker issues: %v", err)
+	}
+}
+
+// reportIssues syncs final issue states and comments the completion report
+// on every linked issue.
+func (e *Executor) reportIssues(specDir string) {
+	if e == nil || e.Issues == nil {
+		return
+	}
+	e.syncIssues(specDir)
+	ui := output.Default()
+	r, err := report.Build(report.Options{SpecDir: specDir})
+	if err != nil {
+		ui.Warn("could not build report for tracker issues: %v", err)
+		return
+	}
+	posted, err := e.Issues.PostReport(interrupt.Context(), specDir, report.RenderMarkdown(r))
+	if posted > 0 {
+		ui.Info("Posted the completion report to %d tracker issue(s)", posted)
+	}
+	if err != nil {
+		ui.Warn("could not post the completion report: %v", err)
+	}
+}
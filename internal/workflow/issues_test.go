@@ -0,0 +1,86 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/tracker"
+)
+
+// recordingIssueClient is a tracker.Client that records calls.
+type recordingIssueClient struct {
+	calls []string
+}
+
+func (c *recordingIssueClient) CreateIssue(_ context.Context, title, _ string) (tracker.Issue, error) {
+	c.calls = append(c.calls, "create "+title)
+	return tracker.Issue{Key: fmt.Sprintf("PROJ-%d", len(c.calls))}, nil
+}
+
+func (c *recordingIssueClient) Transition(_ context.Context, key, state string) error {
+	c.calls = append(c.calls, "move "+key+" "+state)
+	return nil
+}
+
+func (c *recordingIssueClient) Comment(_ context.Context, key, body string) error {
+	if !strings.Contains(body, "001-test") {
+		return fmt.Errorf("comment on %s does not mention the spec", key)
+	}
+	c.calls = append(c.calls, "comment "+key)
+	return nil
+}
+
+func TestExecutor_IssueHooks(t *testing.T) {
+	t.Parallel()
+
+	specDir := filepath.Join(t.TempDir(), "specs", "001-test")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	specContent := `feature:
+  branch: "001-test"
+  status: "Draft"
+  input: "Add the test feature"
+user_stories:
+  - id: "US-001"
+    title: "Run the test"
+    priority: "P1"
+`
+	tasksContent := `phases:
+  - number: 1
+    title: "Core"
+    tasks:
+      - id: "T001"
+        title: "Write the test"
+        status: "Completed"
+        type: "implementation"
+        story_id: "US-001"
+`
+	if err := os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(specContent), 0o644); err != nil {
+		t.Fatalf("Failed to create spec.yaml: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(specDir, "tasks.yaml"), []byte(tasksContent), 0o644); err != nil {
+		t.Fatalf("Failed to create tasks.yaml: %v", err)
+	}
+
+	// Without a tracker the hooks are no-ops
+	(&Executor{}).linkIssues(specDir)
+	if _, err := os.Stat(filepath.Join(specDir, tracker.LinksFileName)); !os.IsNotExist(err) {
+		t.Fatalf("links file written without a tracker (stat err = %v)", err)
+	}
+
+	client := &recordingIssueClient{}
+	executor := &Executor{Issues: tracker.NewWithClient(tracker.Config{Provider: tracker.ProviderJira}, client)}
+	executor.linkIssues(specDir)
+	executor.syncIssues(specDir)
+	executor.completeSpec(specDir)
+
+	want := []string{"create US-001: Run the test", "move PROJ-1 Done", "comment PROJ-1"}
+	if strings.Join(client.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("tracker calls = %q, want %q", client.calls, want)
+	}
+}
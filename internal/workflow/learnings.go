@@ -0,0 +1,97 @@
+package workflow
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/learnings"
+	"github.com/ariel-frischer/autospec/internal/output"
+)
+
+// learningsIntro opens the learnings block added to plan and implement prompts.
+const learningsIntro = `## Project Learnings
+
+Conventions and pitfalls learned while implementing earlier specs in this
+project, most confirmed first. Follow them unless the current spec says
+otherwise:
+
+`
+
+// BuildLearningsInstructions returns an InjectableInstruction listing items.
+func BuildLearningsInstructions(items []learnings.Learning) InjectableInstruction {
+	return InjectableInstruction{
+		Name:        "Learnings",
+		DisplayHint: fmt.Sprintf("%d project learnings from earlier specs", len(items)),
+		Content:     learningsIntro + learnings.FormatForPrompt(items),
+	}
+}
+
+// learningsPath returns the learnings file the executor reads and retro writes.
+func (e *Executor) learningsPath() string {
+	if e.LearningsPath != "" {
+		return e.LearningsPath
+	}
+	return learnings.DefaultPath
+}
+
+// injectLearnings appends the top learnings to plan and implement commands,
+// dropping those that do not fit the prompt_size limit. Other stages, an empty
+// learnings file or max_in_prompt 0 leave command unchanged. An unreadable
+// file only warns.
+func (e *Executor) injectLearnings(command string, stage Stage) string {
+	if (stage != StagePlan && stage != StageImplement) || e.Learnings.MaxInPrompt <= 0 {
+		return command
+	}
+	f, err := learnings.Load(e.learningsPath())
+	if err != nil {
+		output.Default().Warn("prompt sent without learnings: %v", err)
+		return command
+	}
+	top := e.fitLearnings(command, stage, f.Top(e.Learnings.MaxInPrompt))
+	if len(top) == 0 {
+		return command
+	}
+	return InjectInstructions(command, []InjectableInstruction{BuildLearningsInstructions(top)})
+}
+
+// ValidateLearnings returns the ExecuteStage validation callback for the
+// retro stage: the learnings file at path must parse and be valid.
+func ValidateLearnings(path string) func(string) error {
+	return func(string) error {
+		f, err := learnings.Load(path)
+		if err != nil {
+			return err
+		}
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("%s is invalid:\n%w", path, err)
+		}
+		return nil
+	}
+}
+
+// countLearnings returns the number of learnings on disk, 0 when unreadable.
+func (e *Executor) countLearnings() int {
+	f, err := learnings.Load(e.learningsPath())
+	if err != nil {
+		return 0
+	}
+	return len(f.Learnings)
+}
+
+// retrospect runs a retrospection session for a completed spec when
+// learnings.retrospect is enabled. Failures only warn: the spec is already
+// complete and 'autospec retro' can be run again.
+func (e *Executor) retrospect(specDir string) {
+	if e == nil || !e.Learnings.Retrospect {
+		return
+	}
+	ui := output.Default()
+	specName := filepath.Base(specDir)
+	before := e.countLearnings()
+	ui.Info("Running retrospection for %s", specName)
+	if _, err := e.ExecuteStage(specName, StageRetro, "/autospec.retro", ValidateLearnings(e.learningsPath())); err != nil {
+		ui.Warn("retrospection failed: %v (run 'autospec retro %s' to retry)", err, specName)
+		return
+	}
+	ui.Info("Learnings: %d added to %s", e.countLearnings()-before, e.learningsPath())
+}
@@ -0,0 +1,119 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/learnings"
+	"github.com/ariel-frischer/autospec/internal/promptsize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const learningsYAML = `learnings:
+  - id: L-001
+    learning: "Wrap errors with %w"
+    category: convention
+    source: 001-auth
+    added: "2025-01-10"
+  - id: L-002
+    learning: "Run make gen after editing schemas"
+    reinforced: 3
+`
+
+func TestInjectLearnings(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "learnings.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(learningsYAML), 0o644))
+
+	tests := map[string]struct {
+		stage       Stage
+		maxInPrompt int
+		path        string
+		promptSize  promptsize.Config
+		want        []string
+		notWant     string
+		wantNone    bool
+	}{
+		"plan gets top learnings": {
+			stage:       StagePlan,
+			maxInPrompt: 10,
+			want:        []string{"<!-- AUTOSPEC_INJECT:Learnings:2 project learnings", "- Run make gen after editing schemas\n- [convention] Wrap errors with %w (from 001-auth)"},
+		},
+		"limited to max_in_prompt": {
+			stage:       StageImplement,
+			maxInPrompt: 1,
+			want:        []string{"Run make gen"},
+			notWant:     "Wrap errors",
+		},
+		"trimmed to the prompt size limit": {
+			stage:       StagePlan,
+			maxInPrompt: 10,
+			promptSize: promptsize.Config{
+				ContextWindow: promptsize.EstimateTokens("/autospec.plan" + learningsIntro + "- Run make gen after editing schemas"),
+				WarnPercent:   100,
+				Truncation:    []string{promptsize.DropLearnings},
+			},
+			want:    []string{"1 project learnings", "Run make gen"},
+			notWant: "Wrap errors",
+		},
+		"other stages unchanged": {stage: StageTasks, maxInPrompt: 10, wantNone: true},
+		"disabled":               {stage: StagePlan, maxInPrompt: 0, wantNone: true},
+		"no learnings file":      {stage: StagePlan, maxInPrompt: 10, path: filepath.Join(t.TempDir(), "none.yaml"), wantNone: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e := &Executor{Learnings: learnings.Config{MaxInPrompt: tt.maxInPrompt}, LearningsPath: path, PromptSize: tt.promptSize}
+			if tt.path != "" {
+				e.LearningsPath = tt.path
+			}
+			got := e.injectLearnings("/autospec.plan", tt.stage)
+			if tt.wantNone {
+				assert.Equal(t, "/autospec.plan", got)
+				return
+			}
+			for _, want := range tt.want {
+				assert.Contains(t, got, want)
+			}
+			if tt.notWant != "" {
+				assert.NotContains(t, got, tt.notWant)
+			}
+		})
+	}
+}
+
+func TestValidateLearnings(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "learnings.yaml")
+
+	assert.NoError(t, ValidateLearnings(path)(""), "missing file is an empty memory")
+
+	require.NoError(t, os.WriteFile(path, []byte(learningsYAML), 0o644))
+	assert.NoError(t, ValidateLearnings(path)(""))
+
+	require.NoError(t, os.WriteFile(path, []byte("learnings:\n  - id: L-001\n    learning: \"\"\n"), 0o644))
+	assert.ErrorContains(t, ValidateLearnings(path)(""), "learnings[0].learning")
+}
+
+func TestRetrospect(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	path := filepath.Join(dir, "learnings.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("learnings:\n  - id: L-001\n    learning: a\n"), 0o644))
+
+	claude := &writingClaude{files: map[string]string{path: learningsYAML}}
+	e := &Executor{Claude: claude, StateDir: t.TempDir(), SpecsDir: "specs", LearningsPath: path}
+
+	e.retrospect(filepath.Join("specs", "001-auth"))
+	assert.Empty(t, claude.executeCalls, "retrospection is opt-in")
+
+	e.Learnings.Retrospect = true
+	e.retrospect(filepath.Join("specs", "001-auth"))
+	require.Len(t, claude.executeCalls, 1)
+	assert.Contains(t, claude.executeCalls[0], "/autospec.retro")
+	assert.Equal(t, 2, e.countLearnings())
+}
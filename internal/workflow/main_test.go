@@ -0,0 +1,15 @@
+package workflow
+
+import (
+	"os"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/mockagent"
+)
+
+// TestMain lets the test binary act as the mock agent of
+// newTestOrchestratorWithSpecName.
+func TestMain(m *testing.M) {
+	mockagent.Main()
+	os.Exit(m.Run())
+}
@@ -268,6 +268,9 @@ type MockStageExecutor struct {
 	TasksError        error
 	ConstitutionError error
 	ClarifyError      error
+	AmendError        error
+	ForecastError     error
+	RetroError        error
 	ChecklistError    error
 	AnalyzeError      error
 
@@ -277,6 +280,9 @@ type MockStageExecutor struct {
 	TasksCalls        []TasksCall
 	ConstitutionCalls []string // Prompts
 	ClarifyCalls      []ClarifyCall
+	AmendCalls        []AmendCall
+	ForecastCalls     []ForecastCall
+	RetroCalls        []RetroCall
 	ChecklistCalls    []ChecklistCall
 	AnalyzeCalls      []AnalyzeCall
 }
@@ -299,6 +305,24 @@ type ClarifyCall struct {
 	Prompt   string
 }
 
+// AmendCall records a call to ExecuteAmend.
+type AmendCall struct {
+	SpecName string
+	Change   string
+}
+
+// ForecastCall records a call to ExecuteForecast.
+type ForecastCall struct {
+	SpecName string
+	Prompt   string
+}
+
+// RetroCall records a call to ExecuteRetro.
+type RetroCall struct {
+	SpecName string
+	Prompt   string
+}
+
 // ChecklistCall records a call to ExecuteChecklist.
 type ChecklistCall struct {
 	SpecName string
@@ -320,6 +344,9 @@ func NewMockStageExecutor() *MockStageExecutor {
 		TasksCalls:        make([]TasksCall, 0),
 		ConstitutionCalls: make([]string, 0),
 		ClarifyCalls:      make([]ClarifyCall, 0),
+		AmendCalls:        make([]AmendCall, 0),
+		ForecastCalls:     make([]ForecastCall, 0),
+		RetroCalls:        make([]RetroCall, 0),
 		ChecklistCalls:    make([]ChecklistCall, 0),
 		AnalyzeCalls:      make([]AnalyzeCall, 0),
 	}
@@ -355,6 +382,24 @@ func (m *MockStageExecutor) ExecuteClarify(specName string, prompt string) error
 	return m.ClarifyError
 }
 
+// ExecuteAmend implements StageExecutorInterface.
+func (m *MockStageExecutor) ExecuteAmend(specName string, change string) error {
+	m.AmendCalls = append(m.AmendCalls, AmendCall{SpecName: specName, Change: change})
+	return m.AmendError
+}
+
+// ExecuteForecast implements StageExecutorInterface.
+func (m *MockStageExecutor) ExecuteForecast(specName string, prompt string) error {
+	m.ForecastCalls = append(m.ForecastCalls, ForecastCall{SpecName: specName, Prompt: prompt})
+	return m.ForecastError
+}
+
+// ExecuteRetro implements StageExecutorInterface.
+func (m *MockStageExecutor) ExecuteRetro(specName string, prompt string) error {
+	m.RetroCalls = append(m.RetroCalls, RetroCall{SpecName: specName, Prompt: prompt})
+	return m.RetroError
+}
+
 // ExecuteChecklist implements StageExecutorInterface.
 func (m *MockStageExecutor) ExecuteChecklist(specName string, prompt string) error {
 	m.ChecklistCalls = append(m.ChecklistCalls, ChecklistCall{SpecName: specName, Prompt: prompt})
@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// setNotifyStage names the spec, stage and the stage's artifact in later
+// notifications.
+func (e *Executor) setNotifyStage(specName string, stage Stage) {
+	e.notifyHandler().SetStage(specName, string(stage), e.stageArtifact(specName, stage))
+}
+
+// stageArtifact returns the absolute path of the artifact stage writes, or ""
+// when it is not known yet (specify before the spec directory exists).
+// implement links to tasks.yaml, where task progress is tracked.
+func (e *Executor) stageArtifact(specName string, stage Stage) string {
+	var path string
+	switch stage {
+	case StageConstitution:
+		path = ConstitutionPaths[0]
+	case StageSpecify, StageClarify:
+		path = filepath.Join(e.SpecsDir, specName, "spec.yaml")
+	case StagePlan:
+		path = filepath.Join(e.SpecsDir, specName, "plan.yaml")
+	case StageTasks, StageImplement:
+		path = filepath.Join(e.SpecsDir, specName, "tasks.yaml")
+	case StageAnalyze:
+		path = filepath.Join(e.SpecsDir, specName, validation.AnalysisFileName)
+	case StageForecast:
+		path = validation.GetForecastFilePath(filepath.Join(e.SpecsDir, specName))
+	case StageChecklist:
+		path = filepath.Join(e.SpecsDir, specName, "checklists")
+	default:
+		return ""
+	}
+	if specName == "" && stage != StageConstitution {
+		return ""
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	return abs
+}
@@ -0,0 +1,41 @@
+package workflow
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStageArtifact(t *testing.T) {
+	t.Parallel()
+
+	specsDir := t.TempDir()
+	e := &Executor{SpecsDir: specsDir}
+	specDir := filepath.Join(specsDir, "001-login")
+	constitution, err := filepath.Abs(ConstitutionPaths[0])
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		spec  string
+		stage Stage
+		want  string
+	}{
+		"specify":             {spec: "001-login", stage: StageSpecify, want: filepath.Join(specDir, "spec.yaml")},
+		"specify before spec": {spec: "", stage: StageSpecify, want: ""},
+		"plan":                {spec: "001-login", stage: StagePlan, want: filepath.Join(specDir, "plan.yaml")},
+		"implement":           {spec: "001-login", stage: StageImplement, want: filepath.Join(specDir, "tasks.yaml")},
+		"analyze":             {spec: "001-login", stage: StageAnalyze, want: filepath.Join(specDir, "analysis.yaml")},
+		"checklist":           {spec: "001-login", stage: StageChecklist, want: filepath.Join(specDir, "checklists")},
+		"forecast":            {spec: "001-login", stage: StageForecast, want: filepath.Join(specDir, "forecast.yaml")},
+		"constitution":        {spec: "", stage: StageConstitution, want: constitution},
+		"unknown stage":       {spec: "001-login", stage: Stage("review"), want: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, e.stageArtifact(tt.spec, tt.stage))
+		})
+	}
+}
@@ -4,6 +4,8 @@
 package workflow
 
 import (
+	"time"
+
 	"github.com/ariel-frischer/autospec/internal/notify"
 )
 
@@ -46,6 +48,42 @@ func (n *NotifyDispatcher) OnError(stageName string, err error) {
 	n.handler.OnError(stageName, err)
 }
 
+// OnBudgetExceeded dispatches a notification that a budget limit stopped the run.
+// No-op if handler is nil (safe for tests without notifications).
+func (n *NotifyDispatcher) OnBudgetExceeded(stageName string, err error) {
+	if n.handler == nil {
+		return
+	}
+	n.handler.OnBudgetExceeded(stageName, err)
+}
+
+// OnRateLimited dispatches a notification that the run is waiting out a rate limit.
+// No-op if handler is nil (safe for tests without notifications).
+func (n *NotifyDispatcher) OnRateLimited(stageName string, wait time.Duration) {
+	if n.handler == nil {
+		return
+	}
+	n.handler.OnRateLimited(stageName, wait)
+}
+
+// WatchStage starts the long-running watchdog for a stage and returns its stop function.
+// Returns a no-op stop function if handler is nil.
+func (n *NotifyDispatcher) WatchStage(stageName string) (stop func()) {
+	if n.handler == nil {
+		return func() {}
+	}
+	return n.handler.WatchStage(stageName)
+}
+
+// SetCurrentTask records the task named in long-running notifications.
+// No-op if handler is nil.
+func (n *NotifyDispatcher) SetCurrentTask(task string) {
+	if n.handler == nil {
+		return
+	}
+	n.handler.SetCurrentTask(task)
+}
+
 // HasHandler returns true if a notification handler is configured.
 // This can be used to conditionally log messages when no handler is available.
 func (n *NotifyDispatcher) HasHandler() bool {
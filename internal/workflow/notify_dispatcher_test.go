@@ -228,6 +228,9 @@ func TestNotifyDispatcher_NilSafety(t *testing.T) {
 		dispatcher.OnStageComplete("any-stage", false)
 		dispatcher.OnError("any-stage", errors.New("error"))
 		dispatcher.OnError("any-stage", nil)
+		dispatcher.OnBudgetExceeded("any-stage", errors.New("budget exceeded"))
+		dispatcher.WatchStage("any-stage")()
+		dispatcher.SetCurrentTask("T001: any task")
 		_ = dispatcher.HasHandler()
 		_ = dispatcher.Handler()
 	})
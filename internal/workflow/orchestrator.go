@@ -10,15 +10,35 @@
 package workflow
 
 import (
-	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
+	"github.com/ariel-frischer/autospec/internal/agentenv"
+	"github.com/ariel-frischer/autospec/internal/budget"
 	"github.com/ariel-frischer/autospec/internal/config"
 	"github.com/ariel-frischer/autospec/internal/dag"
+	"github.com/ariel-frischer/autospec/internal/encrypt"
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/ghproject"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/ariel-frischer/autospec/internal/lock"
+	"github.com/ariel-frischer/autospec/internal/nextstep"
+	"github.com/ariel-frischer/autospec/internal/notify"
 	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/pause"
+	"github.com/ariel-frischer/autospec/internal/ratelimit"
+	"github.com/ariel-frischer/autospec/internal/redact"
+	"github.com/ariel-frischer/autospec/internal/review"
+	"github.com/ariel-frischer/autospec/internal/salvage"
 	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/statusfile"
+	"github.com/ariel-frischer/autospec/internal/storage"
+	"github.com/ariel-frischer/autospec/internal/tracker"
+	"github.com/ariel-frischer/autospec/internal/transcript"
 	"github.com/ariel-frischer/autospec/internal/validation"
 )
 
@@ -41,10 +61,18 @@ type WorkflowOrchestrator struct {
 	SpecsDir string
 	// SkipPreflight disables pre-flight checks when true.
 	SkipPreflight bool
-	// Debug enables debug logging when true.
+	// Debug is set when the console log level is debug.
 	Debug bool
 	// PreflightChecker is injectable for testing (nil uses default).
 	PreflightChecker PreflightChecker
+	// ForceUnlock removes an existing spec lock before implementing (--force-unlock).
+	ForceUnlock bool
+
+	// workflowType is the kind of work being specified (see SetWorkflowType).
+	// The zero value means the feature workflow.
+	workflowType WorkflowType
+	// pipelines are the custom pipelines ExecutePipeline can run, by name.
+	pipelines map[string]Pipeline
 
 	// Executor interfaces for dependency injection.
 	// These are always set by constructors - never nil during normal operation.
@@ -53,11 +81,9 @@ type WorkflowOrchestrator struct {
 	taskExecutor  TaskExecutorInterface  // Handles task-level implementation
 }
 
-// debugLog prints a debug message if debug mode is enabled
+// debugLog writes a debug record tagged with the orchestrator component.
 func (w *WorkflowOrchestrator) debugLog(format string, args ...interface{}) {
-	if w.Debug {
-		fmt.Printf("[DEBUG] "+format+"\n", args...)
-	}
+	w.Executor.logger("orchestrator").Debug(fmt.Sprintf(format, args...))
 }
 
 // NewWorkflowOrchestrator creates a new workflow orchestrator from configuration.
@@ -86,17 +112,72 @@ func NewWorkflowOrchestrator(cfg *config.Configuration) *WorkflowOrchestrator {
 	// Create NotifyDispatcher with nil handler (CLI commands set handler via deprecated field)
 	notifyDispatch := NewNotifyDispatcher(nil)
 
+	// A run ID is always assigned, even when event logging is disabled, so log
+	// lines, state files and notifications from one invocation can be correlated
+	runID, _ := events.AssignRunID()
+
+	// With encryption enabled but no usable key, run logs and transcripts are
+	// not written rather than written in plain text
+	cipher, cipherErr := encrypt.Writer()
+	if cipherErr != nil {
+		output.Default().Warn("run log and transcripts disabled: %v", cipherErr)
+	}
+
 	executor := &Executor{
-		Claude:      claude,
-		StateDir:    cfg.StateDir,
-		SpecsDir:    cfg.SpecsDir,
-		MaxRetries:  cfg.MaxRetries,
-		TotalStages: 3,     // Default to 3 stages (specify, plan, tasks)
-		Debug:       false, // Will be set by CLI command
-		AutoCommit:  cfg.AutoCommit,
-		Progress:    progressCtrl,
-		Notify:      notifyDispatch,
+		Claude:          claude,
+		RunID:           runID,
+		StateDir:        cfg.StateDir,
+		SpecsDir:        cfg.SpecsDir,
+		MaxRetries:      cfg.MaxRetries,
+		TotalStages:     3,     // Default to 3 stages (specify, plan, tasks)
+		Debug:           false, // Will be set by CLI command
+		AutoCommit:      cfg.AutoCommit,
+		Progress:        progressCtrl,
+		Notify:          notifyDispatch,
+		Events:          newRunRecorder(cfg.StateDir, runID, cfg.Redactor, cipher),
+		PromptTemplates: &PromptTemplates{Dir: DefaultPromptsDir(), Inline: cfg.SpecPrompts},
+		Budget:          budget.New(cfg.Budget),
+		Storage:         storage.NewGuard(cfg.StateDir, runID, cfg.Storage),
+		RateLimit:       ratelimit.New(cfg.RateLimit),
+		MarkdownViews:   cfg.MarkdownViews,
+		Changelog:       cfg.Changelog,
+		AgentEnv:        agentenv.Env{Global: cfg.Env, Stages: cfg.StageEnv},
+		WorkDirs:        cfg.WorkDir,
+		RetryStrategies: cfg.RetryStrategies,
+		Remote:          cfg.Remote,
+		Container:       cfg.Container,
+		Formatters:      cfg.Formatters,
+		Learnings:       cfg.Learnings,
+		PromptSize:      cfg.PromptSize,
+		Rescue:          DefaultRescuer(cfg.SkipConfirmations),
+	}
+	executor.ProjectRoot = pause.ProjectRoot()
+	executor.PauseFile = pause.Path(cfg.StateDir, executor.ProjectRoot)
+	if cfg.ArtifactStaging {
+		executor.StagingDir = DefaultStagingDir
+	}
+	if cfg.ArtifactSalvage {
+		executor.Salvage = salvage.New()
+	}
+	if cfg.Transcripts {
+		executor.Transcript = transcript.New(cfg.Redactor)
+		executor.Transcript.Cipher = cipher
+	}
+	if cipherErr != nil {
+		executor.Events, executor.Transcript = nil, nil
+	}
+	issues, err := tracker.New(cfg.IssueTracker)
+	if err != nil {
+		output.Default().Warn("issue tracker sync disabled: %v", err)
+	}
+	executor.Issues = issues
+	project, err := ghproject.New(cfg.GitHubProject)
+	if err != nil {
+		output.Default().Warn("GitHub project sync disabled: %v", err)
 	}
+	executor.Project = project
+	claude.UsageWriter = teeWriters(executor.Budget.Writer(), executor.Transcript.Writer(), executor.Salvage.Writer())
+	claude.RateLimit = executor.RateLimit
 
 	// Create default executor implementations
 	stageExec := NewStageExecutorWithOptions(executor, cfg.SpecsDir, StageExecutorOptions{
@@ -104,9 +185,13 @@ func NewWorkflowOrchestrator(cfg *config.Configuration) *WorkflowOrchestrator {
 		EnableRiskAssessment: cfg.EnableRiskAssessment,
 	})
 	phaseExec := NewPhaseExecutor(executor, cfg.SpecsDir, false)
-	taskExec := NewTaskExecutor(executor, cfg.SpecsDir, false)
+	taskExec := NewTaskExecutorWithOptions(executor, cfg.SpecsDir, TaskExecutorOptions{
+		TaskCommands:  cfg.TaskCommands,
+		TaskTimeout:   cfg.TaskTimeout,
+		TargetedTests: cfg.TargetedTests,
+	})
 
-	return &WorkflowOrchestrator{
+	orch := &WorkflowOrchestrator{
 		Executor:      executor,
 		Config:        cfg,
 		SpecsDir:      cfg.SpecsDir,
@@ -115,6 +200,56 @@ func NewWorkflowOrchestrator(cfg *config.Configuration) *WorkflowOrchestrator {
 		phaseExecutor: phaseExec,
 		taskExecutor:  taskExec,
 	}
+	// Status file shares the run ID so editors can correlate it with `autospec replay`
+	executor.Status = statusfile.New(statusfile.DefaultPath(), orch.RunID())
+	executor.Status.EnableLine(cfg.StatusLine)
+	orch.registerConfiguredPipelines(cfg)
+	return orch
+}
+
+// teeWriters combines the non-nil writers into one. Returns nil when none are set.
+func teeWriters(writers ...io.Writer) io.Writer {
+	var set []io.Writer
+	for _, w := range writers {
+		if w != nil {
+			set = append(set, w)
+		}
+	}
+	switch len(set) {
+	case 0:
+		return nil
+	case 1:
+		return set[0]
+	}
+	return io.MultiWriter(set...)
+}
+
+// newRunRecorder creates the event log recorder for a run.
+// Returns nil (events discarded) when no state dir is configured or no run ID was assigned.
+func newRunRecorder(stateDir, runID string, redactor *redact.Redactor, cipher *encrypt.Cipher) *events.Recorder {
+	recorder := events.NewRecorder(stateDir, runID)
+	if recorder != nil {
+		recorder.Redactor = redactor
+		recorder.Cipher = cipher
+	}
+	return recorder
+}
+
+// RunID returns the identifier of this run, or "" when none was assigned.
+// It tags log lines, state files and notifications, and names the event log
+// that `autospec replay` uses to reconstruct the run timeline.
+func (w *WorkflowOrchestrator) RunID() string {
+	if w.Executor == nil {
+		return ""
+	}
+	return w.Executor.RunID
+}
+
+// SetNotificationHandler attaches the command's notification handler and tags
+// its notifications with this run's ID.
+func (w *WorkflowOrchestrator) SetNotificationHandler(h *notify.Handler) {
+	h.SetRunID(w.RunID())
+	w.Executor.NotificationHandler = h
 }
 
 // ExecutorOptions holds optional executor interfaces for dependency injection.
@@ -163,7 +298,7 @@ func (w *WorkflowOrchestrator) RunCompleteWorkflow(featureDescription string) er
 
 	fmt.Println("Workflow completed successfully!")
 	fmt.Printf("Spec: specs/%s/\n", specName)
-	fmt.Println("Next: autospec implement")
+	w.printNextSteps(specName)
 
 	return nil
 }
@@ -195,8 +330,11 @@ func (w *WorkflowOrchestrator) RunFullWorkflow(featureDescription string, resume
 
 // runPreflightIfNeeded runs preflight checks if enabled
 func (w *WorkflowOrchestrator) runPreflightIfNeeded() error {
-	if ShouldRunPreflightChecks(w.SkipPreflight) {
-		return w.runPreflightChecks()
+	if !ShouldRunPreflightChecks(w.SkipPreflight) {
+		return nil
+	}
+	if err := w.runPreflightChecks(); err != nil {
+		return apperrors.ErrPreflightFailed.Errorf("%w", err)
 	}
 	return nil
 }
@@ -204,33 +342,41 @@ func (w *WorkflowOrchestrator) runPreflightIfNeeded() error {
 // executeSpecifyPlanTasks runs specify, plan, and tasks stages sequentially.
 // Delegates to StageExecutor for all stage execution.
 func (w *WorkflowOrchestrator) executeSpecifyPlanTasks(featureDescription string, totalStages int) (string, error) {
+	w.Executor.Status.SetTotalStages(totalStages)
+
 	// Stage 1: Specify
 	output.PrintStageHeader(os.Stdout, 1, totalStages, "Specify")
-	fmt.Printf("Executing: /autospec.specify \"%s\"\n", featureDescription)
+	specifyCmd, artifact := w.specifyTarget()
+	fmt.Printf("Executing: %s %s\n", specifyCmd, quotePrompt(featureDescription))
 
 	specName, err := w.stageExecutor.ExecuteSpecify(featureDescription)
 	if err != nil {
 		return "", fmt.Errorf("specify stage failed: %w", err)
 	}
-	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/spec.yaml (schema valid)", specName))
+	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/%s (schema valid)", specName, artifact))
 
 	// Stage 2: Plan
 	output.PrintStageHeader(os.Stdout, 2, totalStages, "Plan")
+	if err := w.checkReviewGate(filepath.Join(w.SpecsDir, specName)); err != nil {
+		return "", err
+	}
 	fmt.Println("Executing: /autospec.plan")
 
-	if err := w.stageExecutor.ExecutePlan(specName, ""); err != nil {
+	err = w.stageExecutor.ExecutePlan(specName, "")
+	if err != nil && !errors.Is(err, ErrStageUpToDate) {
 		return "", fmt.Errorf("plan stage failed: %w", err)
 	}
-	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/plan.yaml (schema valid)", specName))
+	printStageResult(specName, "plan.yaml", err)
 
 	// Stage 3: Tasks
 	output.PrintStageHeader(os.Stdout, 3, totalStages, "Tasks")
 	fmt.Println("Executing: /autospec.tasks")
 
-	if err := w.stageExecutor.ExecuteTasks(specName, ""); err != nil {
+	err = w.stageExecutor.ExecuteTasks(specName, "")
+	if err != nil && !errors.Is(err, ErrStageUpToDate) {
 		return "", fmt.Errorf("tasks stage failed: %w", err)
 	}
-	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/tasks.yaml (schema valid)", specName))
+	printStageResult(specName, "tasks.yaml", err)
 
 	return specName, nil
 }
@@ -258,10 +404,11 @@ func (w *WorkflowOrchestrator) printFullWorkflowSummary(specName string) {
 	}
 
 	// Mark spec as completed
-	markSpecCompletedAndPrint(specDir)
+	w.Executor.completeSpec(specDir)
 
 	fmt.Println("Completed 4 workflow stage(s): specify → plan → tasks → implement")
 	fmt.Printf("Spec: specs/%s/\n", specName)
+	w.printNextSteps(specName)
 	w.debugLog("RunFullWorkflow exiting normally")
 }
 
@@ -277,9 +424,12 @@ func (w *WorkflowOrchestrator) runPreflightChecks() error {
 	if err != nil {
 		return fmt.Errorf("pre-flight checks failed: %w", err)
 	}
+	if result.Report != nil {
+		result.Report.Print(os.Stdout)
+	}
 
 	if !result.Passed {
-		if len(result.FailedChecks) > 0 {
+		if result.Report == nil {
 			for _, check := range result.FailedChecks {
 				fmt.Printf("✗ %s\n", check)
 			}
@@ -298,11 +448,6 @@ func (w *WorkflowOrchestrator) runPreflightChecks() error {
 			// Critical failures (missing CLI tools)
 			return fmt.Errorf("pre-flight checks failed")
 		}
-	} else {
-		fmt.Println("✓ claude CLI found")
-		fmt.Println("✓ specify CLI found")
-		fmt.Println("✓ .claude/commands/ directory exists")
-		fmt.Println("✓ .autospec/ directory exists")
 	}
 
 	fmt.Println()
@@ -315,7 +460,7 @@ func (w *WorkflowOrchestrator) getPreflightChecker() PreflightChecker {
 	if w.PreflightChecker != nil {
 		return w.PreflightChecker
 	}
-	return NewDefaultPreflightChecker()
+	return &DefaultPreflightChecker{Config: w.Config}
 }
 
 // resolveSpecName resolves the spec name from argument or auto-detection.
@@ -330,21 +475,24 @@ func (w *WorkflowOrchestrator) resolveSpecName(specNameArg string) (string, erro
 		return "", fmt.Errorf("detecting current spec: %w", err)
 	}
 
-	return fmt.Sprintf("%s-%s", metadata.Number, metadata.Name), nil
+	return metadata.DirName(), nil
 }
 
 // ExecuteSpecify runs only the specify stage.
 // Delegates to the StageExecutor for execution.
 func (w *WorkflowOrchestrator) ExecuteSpecify(featureDescription string) (string, error) {
-	fmt.Printf("Executing: /autospec.specify \"%s\"\n", featureDescription)
+	specifyCmd, artifact := w.specifyTarget()
+	fmt.Printf("Executing: %s %s\n", specifyCmd, quotePrompt(featureDescription))
 
 	specName, err := w.stageExecutor.ExecuteSpecify(featureDescription)
 	if err != nil {
 		return "", err
 	}
 
-	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/spec.yaml (schema valid)", specName))
-	fmt.Println("Next: autospec plan")
+	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/%s (schema valid)", specName, artifact))
+	if artifact == "spec.yaml" {
+		w.printNextSteps(specName)
+	}
 
 	return specName, nil
 }
@@ -356,23 +504,36 @@ func (w *WorkflowOrchestrator) ExecutePlan(specNameArg string, prompt string) er
 	if err != nil {
 		return fmt.Errorf("resolving spec name: %w", err)
 	}
+	if err := w.checkReviewGate(filepath.Join(w.SpecsDir, specName)); err != nil {
+		return err
+	}
 
 	if prompt != "" {
-		fmt.Printf("Executing: /autospec.plan \"%s\"\n", prompt)
+		fmt.Printf("Executing: /autospec.plan %s\n", quotePrompt(prompt))
 	} else {
 		fmt.Println("Executing: /autospec.plan")
 	}
 
-	if err := w.stageExecutor.ExecutePlan(specName, prompt); err != nil {
+	err = w.stageExecutor.ExecutePlan(specName, prompt)
+	if err != nil && !errors.Is(err, ErrStageUpToDate) {
 		return fmt.Errorf("executing plan stage: %w", err)
 	}
-
-	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/plan.yaml (schema valid)", specName))
-	fmt.Println("Next: autospec tasks")
+	printStageResult(specName, "plan.yaml", err)
+	w.printNextSteps(specName)
 
 	return nil
 }
 
+// printStageResult reports a finished plan or tasks stage. err is nil or
+// ErrStageUpToDate, in which case the agent was not called.
+func printStageResult(specName, artifact string, err error) {
+	if errors.Is(err, ErrStageUpToDate) {
+		output.PrintStageSuccess(os.Stdout, fmt.Sprintf("specs/%s/%s is up to date (inputs unchanged since the last run; use --force to regenerate)", specName, artifact))
+		return
+	}
+	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/%s (schema valid)", specName, artifact))
+}
+
 // ExecuteTasks runs only the tasks stage for a detected or specified spec.
 // Delegates to the StageExecutor for execution.
 func (w *WorkflowOrchestrator) ExecuteTasks(specNameArg string, prompt string) error {
@@ -382,17 +543,17 @@ func (w *WorkflowOrchestrator) ExecuteTasks(specNameArg string, prompt string) e
 	}
 
 	if prompt != "" {
-		fmt.Printf("Executing: /autospec.tasks \"%s\"\n", prompt)
+		fmt.Printf("Executing: /autospec.tasks %s\n", quotePrompt(prompt))
 	} else {
 		fmt.Println("Executing: /autospec.tasks")
 	}
 
-	if err := w.stageExecutor.ExecuteTasks(specName, prompt); err != nil {
+	err = w.stageExecutor.ExecuteTasks(specName, prompt)
+	if err != nil && !errors.Is(err, ErrStageUpToDate) {
 		return fmt.Errorf("executing tasks stage: %w", err)
 	}
-
-	output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Created specs/%s/tasks.yaml (schema valid)", specName))
-	fmt.Println("Next: autospec implement")
+	printStageResult(specName, "tasks.yaml", err)
+	w.printNextSteps(specName)
 
 	return nil
 }
@@ -404,12 +565,12 @@ func (w *WorkflowOrchestrator) ExecuteImplement(specNameArg string, prompt strin
 	var err error
 
 	if specNameArg != "" {
-		specName = specNameArg
-		// Load metadata for this spec
-		metadata, err = spec.GetSpecMetadata(w.SpecsDir, specName)
+		// Load metadata for this spec; the argument may be a bare number or partial name
+		metadata, err = spec.GetSpecMetadata(w.SpecsDir, specNameArg)
 		if err != nil {
 			return fmt.Errorf("failed to load spec metadata: %w", err)
 		}
+		specName = metadata.DirName()
 	} else {
 		// Auto-detect current spec
 		metadata, err = spec.DetectCurrentSpec(w.SpecsDir)
@@ -417,15 +578,43 @@ func (w *WorkflowOrchestrator) ExecuteImplement(specNameArg string, prompt strin
 			return fmt.Errorf("failed to detect current spec: %w", err)
 		}
 		// Use full spec directory name (e.g., "003-command-timeout")
-		specName = fmt.Sprintf("%s-%s", metadata.Number, metadata.Name)
+		specName = metadata.DirName()
+	}
+
+	if err := w.checkChecklistGate(filepath.Join(w.SpecsDir, specName)); err != nil {
+		return err
+	}
+	if err := w.checkAnalysisGate(filepath.Join(w.SpecsDir, specName), "implement blocked"); err != nil {
+		return err
+	}
+	warnStaleArtifacts(filepath.Join(w.SpecsDir, specName))
+
+	specLock, err := w.acquireSpecLock(filepath.Join(w.SpecsDir, specName))
+	if err != nil {
+		return err
 	}
+	defer func() {
+		if err := specLock.Release(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to release spec lock: %v\n", err)
+		}
+	}()
+
+	if err := w.dispatchImplement(specName, metadata, prompt, resume, phaseOpts); err != nil {
+		return err
+	}
+	w.printNextSteps(specName)
+	return nil
+}
 
-	// Dispatch to appropriate execution mode based on phase options
+// dispatchImplement runs implement in the execution mode phaseOpts selects.
+func (w *WorkflowOrchestrator) dispatchImplement(specName string, metadata *spec.Metadata, prompt string, resume bool, phaseOpts PhaseExecutionOptions) error {
 	switch phaseOpts.Mode() {
 	case ModeParallel:
 		return w.ExecuteImplementParallel(specName, metadata, prompt, phaseOpts)
 	case ModeAllTasks:
 		return w.ExecuteImplementWithTasks(specName, metadata, prompt, phaseOpts.FromTask)
+	case ModeSingleTask:
+		return w.ExecuteImplementSingleTask(specName, metadata, prompt, phaseOpts.SingleTask)
 	case ModeAllPhases:
 		return w.ExecuteImplementWithPhases(specName, metadata, prompt, resume)
 	case ModeSinglePhase:
@@ -438,6 +627,108 @@ func (w *WorkflowOrchestrator) ExecuteImplement(specNameArg string, prompt strin
 	}
 }
 
+// printNextSteps prints the commands that move specName forward, worked out
+// from its artifacts and task states. An empty specName suggests specify.
+func (w *WorkflowOrchestrator) printNextSteps(specName string) {
+	var opts nextstep.Options
+	if w.Config != nil {
+		opts.Review = w.Config.Review
+		opts.AnalyzeFailOn = w.Config.AnalyzeFailOn
+	}
+	nextstep.Print(os.Stdout, nextstep.Suggest(w.SpecsDir, specName, opts))
+}
+
+// checkChecklistGate blocks implementation while checklist items are open,
+// when the checklist_gate option is enabled.
+func (w *WorkflowOrchestrator) checkChecklistGate(specDir string) error {
+	if w.Config == nil || !w.Config.ChecklistGate {
+		return nil
+	}
+	if err := validation.CheckChecklistGate(specDir); err != nil {
+		return fmt.Errorf("implement blocked: %w", err)
+	}
+	w.debugLog("Checklist gate passed for %s", specDir)
+	return nil
+}
+
+// checkReviewGate fails when review gating is enabled and the required
+// reviewers have not all approved the spec's current spec.yaml.
+func (w *WorkflowOrchestrator) checkReviewGate(specDir string) error {
+	if w.Config == nil || !w.Config.Review.Enabled {
+		return nil
+	}
+	if err := review.CheckGate(specDir, w.Config.Review); err != nil {
+		return fmt.Errorf("plan blocked: %w", err)
+	}
+	w.debugLog("Review gate passed for %s", specDir)
+	return nil
+}
+
+// checkAnalysisGate fails when the spec's analysis.yaml has findings at or above
+// the analyze_fail_on severity. prefix names the blocked step in the error.
+func (w *WorkflowOrchestrator) checkAnalysisGate(specDir, prefix string) error {
+	if w.Config == nil || w.Config.AnalyzeFailOn == "" {
+		return nil
+	}
+	if err := validation.CheckAnalysisGate(specDir, w.Config.AnalyzeFailOn); err != nil {
+		return fmt.Errorf("%s: %w", prefix, err)
+	}
+	w.debugLog("Analysis gate (%s) passed for %s", w.Config.AnalyzeFailOn, specDir)
+	return nil
+}
+
+// warnStaleArtifacts warns when plan.yaml or tasks.yaml predate a spec
+// amendment, or were generated from an older spec.yaml or plan.yaml.
+func warnStaleArtifacts(specDir string) {
+	stale, err := spec.LoadStaleArtifacts(specDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	for _, s := range stale {
+		fmt.Fprintf(os.Stderr, "Warning: %s is stale after a spec amendment; run 'autospec %s' to regenerate it\n",
+			s.Artifact, s.Stage)
+	}
+	if len(stale) > 0 {
+		return // The amendment already explains the drift
+	}
+	for _, artifactType := range []validation.ArtifactType{validation.ArtifactTypePlan, validation.ArtifactTypeTasks} {
+		path := filepath.Join(specDir, string(artifactType)+".yaml")
+		for _, w := range validation.CheckSourceHashes(path, artifactType) {
+			fmt.Fprintf(os.Stderr, "Warning: %s. %s\n", w.Message, w.Hint)
+		}
+	}
+}
+
+// acquireSpecLock takes the per-spec run lock so two processes cannot implement
+// the same spec concurrently. With ForceUnlock set, any existing lock is removed first.
+// Returns a nil lock (no locking) when no state directory is configured.
+func (w *WorkflowOrchestrator) acquireSpecLock(specDir string) (*lock.Lock, error) {
+	if w.Config == nil || w.Config.StateDir == "" {
+		return nil, nil
+	}
+
+	if w.ForceUnlock {
+		holder, err := lock.ForceUnlock(w.Config.StateDir, specDir)
+		if err != nil {
+			return nil, fmt.Errorf("force-unlocking spec: %w", err)
+		}
+		if holder != nil {
+			fmt.Fprintf(os.Stderr, "Warning: removed lock held by %s\n", holder)
+		}
+	}
+
+	specLock, err := lock.Acquire(w.Config.StateDir, specDir, "implement", w.RunID())
+	if err != nil {
+		var held *lock.HeldError
+		if errors.As(err, &held) {
+			return nil, fmt.Errorf("%w\n\nWait for that run to finish, or if it is no longer running, rerun with --force-unlock", err)
+		}
+		return nil, fmt.Errorf("acquiring spec lock: %w", err)
+	}
+	w.debugLog("Acquired spec lock: %s", specLock.Path)
+	return specLock, nil
+}
+
 // executeImplementDefault executes implementation in a single Claude session (backward compatible).
 // Delegates to PhaseExecutor.ExecuteDefault for execution.
 func (w *WorkflowOrchestrator) executeImplementDefault(specName string, metadata *spec.Metadata, prompt string, resume bool) error {
@@ -512,12 +803,36 @@ func (w *WorkflowOrchestrator) ExecuteImplementWithTasks(specName string, metada
 	}
 
 	if startIdx > 0 {
-		fmt.Printf("Starting from task %s (task %d of %d)\n\n", fromTask, startIdx+1, totalTasks)
+		fmt.Printf("Starting from task %s (task %d of %d)\n\n", orderedTasks[startIdx].ID, startIdx+1, totalTasks)
 	}
 
 	return w.taskExecutor.ExecuteTaskLoop(specName, tasksPath, orderedTasks, startIdx, totalTasks, prompt)
 }
 
+// ExecuteImplementSingleTask runs one task in a separate Claude session. The
+// task's dependencies must be completed; the spec is not marked completed.
+// Delegates to TaskExecutor for execution.
+func (w *WorkflowOrchestrator) ExecuteImplementSingleTask(specName string, metadata *spec.Metadata, prompt string, taskID string) error {
+	specDir := filepath.Join(w.SpecsDir, specName)
+	tasksPath := validation.GetTasksFilePath(specDir)
+
+	orderedTasks, idx, totalTasks, err := w.taskExecutor.PrepareTaskExecution(tasksPath, taskID)
+	if err != nil {
+		return fmt.Errorf("preparing task execution: %w", err)
+	}
+	task := orderedTasks[idx]
+	if shouldSkipTask(task, idx, totalTasks) {
+		return nil
+	}
+
+	fmt.Printf("[Task %d/%d] %s - %s\n", idx+1, totalTasks, task.ID, task.Title)
+	if err := w.taskExecutor.ExecuteSingleTask(specName, task.ID, task.Title, prompt); err != nil {
+		return fmt.Errorf("executing task %s: %w", task.ID, err)
+	}
+	output.Default().Success("Task %s complete", task.ID)
+	return nil
+}
+
 // ExecuteImplementParallel runs tasks concurrently using DAG-based wave scheduling.
 // Independent tasks within each wave run in parallel, respecting the max-parallel limit.
 func (w *WorkflowOrchestrator) ExecuteImplementParallel(specName string, metadata *spec.Metadata, prompt string, phaseOpts PhaseExecutionOptions) error {
@@ -560,6 +875,7 @@ func (w *WorkflowOrchestrator) ExecuteImplementParallel(specName string, metadat
 		WithMaxParallel(phaseOpts.MaxParallel),
 		WithParallelDebug(w.Debug),
 		WithProgressCallback(w.defaultProgressCallback),
+		WithFailFast(w.Executor.FailFast),
 	}
 
 	// Add worktree support when --worktrees is set
@@ -577,7 +893,7 @@ func (w *WorkflowOrchestrator) ExecuteImplementParallel(specName string, metadat
 	fmt.Printf("Executing %d tasks in parallel (max %d concurrent)\n", graph.Size(), phaseOpts.MaxParallel)
 	fmt.Printf("Wave structure: %s\n\n", graph.RenderCompact())
 
-	results, err := executor.ExecuteWaves(context.Background(), specName, tasksPath)
+	results, err := executor.ExecuteWaves(interrupt.Context(), specName, tasksPath)
 	if err != nil {
 		return fmt.Errorf("parallel execution failed: %w", err)
 	}
@@ -673,23 +989,43 @@ func (w *WorkflowOrchestrator) printParallelSummary(results []WaveResult, execut
 }
 
 // markSpecCompletedAndPrint marks the spec as completed and prints the result.
+// It reports whether spec.yaml now records the spec as completed.
 // This is a package-level function used by executors for consistent completion marking.
-func markSpecCompletedAndPrint(specDir string) {
+func markSpecCompletedAndPrint(specDir string) bool {
+	ui := output.Default()
 	result, err := spec.MarkSpecCompleted(specDir)
 	if err != nil {
-		fmt.Printf("Warning: could not update spec.yaml status: %v\n", err)
-		return
+		ui.Warn("could not update spec.yaml status: %v", err)
+		return false
 	}
 
 	if result.Updated {
-		fmt.Printf("Updated spec.yaml: %s → %s\n", result.PreviousStatus, result.NewStatus)
+		ui.Info("Updated spec.yaml: %s %s %s", result.PreviousStatus, ui.Symbols().Arrow, result.NewStatus)
+	}
+	return true
+}
+
+// printCompletionSummary prints the headline of a finished implement run
+// followed by the task summary from tasksPath.
+func printCompletionSummary(headline, tasksPath string) {
+	ui := output.Default()
+	ui.Success("%s", headline)
+	ui.Blank()
+	stats, statsErr := validation.GetTaskStats(tasksPath)
+	if statsErr == nil && stats.TotalTasks > 0 {
+		ui.Info("Task Summary:")
+		ui.Block(validation.FormatTaskSummary(stats))
 	}
 }
 
 // ExecuteConstitution runs the constitution stage with optional prompt.
 // Delegates to StageExecutor for execution.
 func (w *WorkflowOrchestrator) ExecuteConstitution(prompt string) error {
-	return w.stageExecutor.ExecuteConstitution(prompt)
+	if err := w.stageExecutor.ExecuteConstitution(prompt); err != nil {
+		return err
+	}
+	w.printNextSteps("")
+	return nil
 }
 
 // ExecuteClarify runs the clarify stage with optional prompt.
@@ -699,7 +1035,51 @@ func (w *WorkflowOrchestrator) ExecuteClarify(specNameArg string, prompt string)
 	if err != nil {
 		return fmt.Errorf("resolving spec name: %w", err)
 	}
-	return w.stageExecutor.ExecuteClarify(specName, prompt)
+	if err := w.stageExecutor.ExecuteClarify(specName, prompt); err != nil {
+		return err
+	}
+	w.printNextSteps(specName)
+	return nil
+}
+
+// ExecuteAmend applies a change to an existing spec.yaml and marks the plan
+// and tasks derived from it as stale. Returns the stale artifacts.
+func (w *WorkflowOrchestrator) ExecuteAmend(specNameArg string, change string) ([]spec.StaleArtifact, error) {
+	specName, err := w.resolveSpecName(specNameArg)
+	if err != nil {
+		return nil, fmt.Errorf("resolving spec name: %w", err)
+	}
+	if err := w.stageExecutor.ExecuteAmend(specName, change); err != nil {
+		return nil, err
+	}
+	return spec.MarkDownstreamStale(filepath.Join(w.SpecsDir, specName), change)
+}
+
+// ExecuteForecast runs the forecast stage and returns the rollup of the
+// forecast.yaml it wrote.
+func (w *WorkflowOrchestrator) ExecuteForecast(specNameArg string, prompt string) (*validation.ForecastRollup, error) {
+	specName, err := w.resolveSpecName(specNameArg)
+	if err != nil {
+		return nil, fmt.Errorf("resolving spec name: %w", err)
+	}
+	if err := w.stageExecutor.ExecuteForecast(specName, prompt); err != nil {
+		return nil, err
+	}
+	return validation.GetForecastRollup(filepath.Join(w.SpecsDir, specName))
+}
+
+// ExecuteRetro runs a retrospection session for a spec and returns how many
+// learnings it added to the learnings file.
+func (w *WorkflowOrchestrator) ExecuteRetro(specNameArg string, prompt string) (int, error) {
+	specName, err := w.resolveSpecName(specNameArg)
+	if err != nil {
+		return 0, fmt.Errorf("resolving spec name: %w", err)
+	}
+	before := w.Executor.countLearnings()
+	if err := w.stageExecutor.ExecuteRetro(specName, prompt); err != nil {
+		return 0, err
+	}
+	return w.Executor.countLearnings() - before, nil
 }
 
 // ExecuteChecklist runs the checklist stage with optional prompt.
@@ -709,7 +1089,11 @@ func (w *WorkflowOrchestrator) ExecuteChecklist(specNameArg string, prompt strin
 	if err != nil {
 		return fmt.Errorf("resolving spec name: %w", err)
 	}
-	return w.stageExecutor.ExecuteChecklist(specName, prompt)
+	if err := w.stageExecutor.ExecuteChecklist(specName, prompt); err != nil {
+		return err
+	}
+	w.printNextSteps(specName)
+	return nil
 }
 
 // ExecuteAnalyze runs the analyze stage with optional prompt.
@@ -719,7 +1103,14 @@ func (w *WorkflowOrchestrator) ExecuteAnalyze(specNameArg string, prompt string)
 	if err != nil {
 		return fmt.Errorf("resolving spec name: %w", err)
 	}
-	return w.stageExecutor.ExecuteAnalyze(specName, prompt)
+	if err := w.stageExecutor.ExecuteAnalyze(specName, prompt); err != nil {
+		return err
+	}
+	if err := w.checkAnalysisGate(filepath.Join(w.SpecsDir, specName), "analyze failed"); err != nil {
+		return err
+	}
+	w.printNextSteps(specName)
+	return nil
 }
 
 // newClaudeExecutorFromConfig creates a ClaudeExecutor from configuration.
@@ -732,6 +1123,7 @@ func newClaudeExecutorFromConfig(cfg *config.Configuration) *ClaudeExecutor {
 			Timeout:         cfg.Timeout,
 			CcleanConfig:    cfg.Cclean,
 			UseSubscription: cfg.UseSubscription,
+			Redactor:        cfg.Redactor,
 		}
 	}
 
@@ -741,6 +1133,7 @@ func newClaudeExecutorFromConfig(cfg *config.Configuration) *ClaudeExecutor {
 		CcleanConfig:                 cfg.Cclean,
 		UseSubscription:              cfg.UseSubscription,
 		ReplaceProcessForInteractive: true, // Default: replace process for full terminal control
+		Redactor:                     cfg.Redactor,
 	}
 }
 
@@ -758,6 +1151,25 @@ func (w *WorkflowOrchestrator) SetOutputStyle(style config.OutputStyle) {
 	}
 }
 
+// SetWorkflowType selects the kind of work the specify stage writes a spec for.
+// Uses type assertion so injected stage executors without workflow type
+// support keep running the feature workflow.
+func (w *WorkflowOrchestrator) SetWorkflowType(t WorkflowType) {
+	w.workflowType = t
+	if se, ok := w.stageExecutor.(interface{ SetWorkflowType(WorkflowType) }); ok {
+		se.SetWorkflowType(t)
+	}
+}
+
+// specifyTarget returns the slash command the specify stage runs and the
+// artifact it writes for the selected workflow type.
+func (w *WorkflowOrchestrator) specifyTarget() (command, artifact string) {
+	if w.workflowType.Name == "" {
+		return "/autospec.specify", "spec.yaml"
+	}
+	return w.workflowType.SpecifyCommand, w.workflowType.Artifact
+}
+
 // DisableProcessReplacement disables syscall.Exec for interactive stages.
 // Use this for multi-stage runs where we need to continue after interactive stages.
 // Without this, interactive stages would replace the process and prevent continuation.
@@ -1,15 +1,21 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/ariel-frischer/autospec/internal/changelog"
 	"github.com/ariel-frischer/autospec/internal/cliagent"
 	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/lock"
+	"github.com/ariel-frischer/autospec/internal/mockagent"
+	"github.com/ariel-frischer/autospec/internal/notify"
+	"github.com/ariel-frischer/autospec/internal/review"
 	"github.com/ariel-frischer/autospec/internal/spec"
 	"github.com/ariel-frischer/autospec/internal/validation"
 )
@@ -55,6 +61,41 @@ func TestNewWorkflowOrchestrator(t *testing.T) {
 	}
 }
 
+func TestWorkflowOrchestrator_RunID(t *testing.T) {
+	stateDir := t.TempDir()
+	cfg := testConfigWithAgent("./specs", stateDir, "claude")
+
+	t.Run("threaded through executor, events and notifications", func(t *testing.T) {
+		orchestrator := NewWorkflowOrchestrator(cfg)
+		runID := orchestrator.RunID()
+		if runID == "" {
+			t.Fatal("RunID() is empty")
+		}
+		if orchestrator.Executor.Events.RunID != runID {
+			t.Errorf("event log run ID = %q, want %q", orchestrator.Executor.Events.RunID, runID)
+		}
+		orchestrator.SetNotificationHandler(notify.NewHandler(notify.DefaultConfig()))
+		if orchestrator.Executor.NotificationHandler == nil {
+			t.Error("SetNotificationHandler() did not attach the handler")
+		}
+	})
+
+	t.Run("assigned without a state dir", func(t *testing.T) {
+		orchestrator := NewWorkflowOrchestrator(testConfigWithAgent("./specs", "", "claude"))
+		if orchestrator.RunID() == "" || orchestrator.Executor.Events != nil {
+			t.Errorf("RunID() = %q, Events = %v; want an ID and no event log", orchestrator.RunID(), orchestrator.Executor.Events)
+		}
+	})
+
+	t.Run("inherited from the daemon", func(t *testing.T) {
+		t.Setenv(events.RunIDEnvVar, "brave_fox_20250115_140000")
+		orchestrator := NewWorkflowOrchestrator(cfg)
+		if got := orchestrator.RunID(); got != "brave_fox_20250115_140000" {
+			t.Errorf("RunID() = %q, want the inherited ID", got)
+		}
+	})
+}
+
 func TestWorkflowOrchestrator_Configuration(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -806,10 +847,79 @@ requirements:
 	}
 
 	// This should not panic and should update the spec
-	markSpecCompletedAndPrint(specDir)
+	if !markSpecCompletedAndPrint(specDir) {
+		t.Error("expected the spec to be marked completed")
+	}
 
 	// Test with non-existent directory - should not panic
-	markSpecCompletedAndPrint(filepath.Join(tmpDir, "nonexistent"))
+	if markSpecCompletedAndPrint(filepath.Join(tmpDir, "nonexistent")) {
+		t.Error("expected a missing spec.yaml to report failure")
+	}
+}
+
+// TestExecutor_CompleteSpec_Changelog tests that completing a spec adds its
+// changelog entry once, and only when enabled.
+func TestExecutor_CompleteSpec_Changelog(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	specDir := filepath.Join(tmpDir, "specs", "001-test")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	specContent := `feature:
+  branch: "001-test"
+  status: "Draft"
+  input: "Add the test feature"
+user_stories:
+  - id: "US-001"
+    title: "Run the test"
+    priority: "P1"
+`
+	if err := os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte(specContent), 0o644); err != nil {
+		t.Fatalf("Failed to create spec.yaml: %v", err)
+	}
+	changelogPath := filepath.Join(tmpDir, "CHANGELOG.md")
+
+	(&Executor{}).completeSpec(specDir)
+	if _, err := os.Stat(changelogPath); !os.IsNotExist(err) {
+		t.Fatalf("changelog written while disabled (stat err = %v)", err)
+	}
+
+	executor := &Executor{Changelog: changelog.Config{Enabled: true, File: changelogPath}}
+	executor.completeSpec(specDir)
+	executor.completeSpec(specDir)
+
+	data, err := os.ReadFile(changelogPath)
+	if err != nil {
+		t.Fatalf("reading changelog: %v", err)
+	}
+	content := string(data)
+	if strings.Count(content, "### 001-test") != 1 {
+		t.Errorf("changelog should contain one 001-test entry, got:\n%s", content)
+	}
+	if !strings.Contains(content, "- Run the test (US-001)") {
+		t.Errorf("changelog missing user story line, got:\n%s", content)
+	}
+}
+
+// TestExecutor_CompleteSpec_ChangelogSkippedOnFailure tests that no changelog
+// entry is written when the spec cannot be marked completed.
+func TestExecutor_CompleteSpec_ChangelogSkippedOnFailure(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	specDir := filepath.Join(tmpDir, "specs", "001-test")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatalf("Failed to create test directory: %v", err)
+	}
+	changelogPath := filepath.Join(tmpDir, "CHANGELOG.md")
+
+	executor := &Executor{Changelog: changelog.Config{Enabled: true, File: changelogPath}}
+	executor.completeSpec(specDir)
+	if _, err := os.Stat(changelogPath); !os.IsNotExist(err) {
+		t.Fatalf("changelog written for a spec that was not completed (stat err = %v)", err)
+	}
 }
 
 // TestExecuteSpecify tests the ExecuteSpecify method
@@ -2536,55 +2646,25 @@ func TestExecuteSpecifyAndPlanTasks(t *testing.T) {
 // =============================================================================
 // These helpers are defined here to avoid import cycle with testutil package.
 
-// findMockClaudePath locates the mock-claude.sh script relative to the repo root.
-func findMockClaudePath(t *testing.T) string {
-	t.Helper()
-
-	// Get the path to the current source file
-	_, currentFile, _, ok := runtime.Caller(0)
-	if !ok {
-		t.Fatal("failed to determine current file location")
-	}
-
-	// Navigate from internal/workflow/ to repo root
-	repoRoot := filepath.Join(filepath.Dir(currentFile), "..", "..")
-
-	// Try the primary location first
-	mockPath := filepath.Join(repoRoot, "mocks", "scripts", "mock-claude.sh")
-	if _, err := os.Stat(mockPath); err == nil {
-		return mockPath
-	}
-
-	// Fallback location
-	mockPath = filepath.Join(repoRoot, "tests", "mocks", "mock-claude.sh")
-	if _, err := os.Stat(mockPath); err == nil {
-		return mockPath
-	}
-
-	t.Fatalf("mock-claude.sh not found at expected locations")
-	return ""
-}
-
 // newTestOrchestratorWithSpecName creates a WorkflowOrchestrator configured for testing.
-// It uses mock-claude.sh as the Claude command to avoid real API calls.
-// Sets environment variables so mock-claude.sh creates the appropriate artifacts.
+// It runs the test binary as the mock agent (see TestMain) to avoid real API calls.
+// Sets environment variables so the mock agent creates the appropriate artifacts.
 func newTestOrchestratorWithSpecName(t *testing.T, specsDir, specName string) *WorkflowOrchestrator {
 	t.Helper()
 
-	// Find the mock-claude.sh script path
-	mockClaudePath := findMockClaudePath(t)
-
 	// Create state directory within the test temp area
 	stateDir := filepath.Join(specsDir, ".autospec", "state")
 	if err := os.MkdirAll(stateDir, 0o755); err != nil {
 		t.Fatalf("failed to create state directory: %v", err)
 	}
 
+	agent, err := mockagent.Agent()
+	if err != nil {
+		t.Fatalf("configuring mock agent: %v", err)
+	}
+
 	cfg := &config.Configuration{
-		CustomAgent: &cliagent.CustomAgentConfig{
-			Command: mockClaudePath,
-			Args:    []string{"{{PROMPT}}"},
-		},
+		CustomAgent:   agent,
 		SpecsDir:      specsDir,
 		StateDir:      stateDir,
 		MaxRetries:    1, // Minimal retries for faster tests
@@ -2592,7 +2672,7 @@ func newTestOrchestratorWithSpecName(t *testing.T, specsDir, specName string) *W
 		Timeout:       30, // 30 second timeout for tests
 	}
 
-	// Set environment variables for mock-claude.sh to generate artifacts
+	// Set environment variables for the mock agent to generate artifacts
 	t.Setenv("MOCK_ARTIFACT_DIR", specsDir)
 	t.Setenv("MOCK_SPEC_NAME", specName)
 
@@ -2835,11 +2915,11 @@ func writeAllTestArtifacts(t *testing.T, specDir string) {
 // =============================================================================
 // Execute* Method Tests with Mock Infrastructure (Phase 3 Tasks T005-T008)
 // =============================================================================
-// These tests use newTestOrchestratorWithSpecName which configures mock-claude.sh
+// These tests use newTestOrchestratorWithSpecName which configures the mock agent
 // to generate valid artifact files, enabling actual Execute* method testing.
 
 // TestExecuteSpecify_Success tests ExecuteSpecify creates spec.yaml via mock
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestExecuteSpecify_Success(t *testing.T) {
 	tests := map[string]struct {
 		featureDesc string
@@ -2866,7 +2946,7 @@ func TestExecuteSpecify_Success(t *testing.T) {
 			// Create isolated temp directory
 			tmpDir := t.TempDir()
 
-			// Create orchestrator with mock - mock-claude.sh will create artifacts
+			// Create orchestrator with mock - the mock agent will create artifacts
 			orchestrator := newTestOrchestratorWithSpecName(t, tmpDir, tt.specName)
 
 			// Call ExecuteSpecify - mock will generate spec.yaml
@@ -2891,7 +2971,7 @@ func TestExecuteSpecify_Success(t *testing.T) {
 }
 
 // TestExecutePlan_Success tests ExecutePlan creates plan.yaml via mock
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestExecutePlan_Success(t *testing.T) {
 	tests := map[string]struct {
 		specName string
@@ -2938,7 +3018,7 @@ func TestExecutePlan_Success(t *testing.T) {
 }
 
 // TestExecuteTasks_Success tests ExecuteTasks creates tasks.yaml via mock
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestExecuteTasks_Success(t *testing.T) {
 	tests := map[string]struct {
 		specName string
@@ -2986,7 +3066,7 @@ func TestExecuteTasks_Success(t *testing.T) {
 }
 
 // TestExecuteImplement_Success tests ExecuteImplement completes without error
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestExecuteImplement_Success(t *testing.T) {
 	tests := map[string]struct {
 		specName  string
@@ -3035,12 +3115,193 @@ func TestExecuteImplement_Success(t *testing.T) {
 	}
 }
 
+// TestExecuteImplement_SpecLocked verifies a second implement run on a locked spec
+// is refused, and that ForceUnlock takes the lock over.
+func TestExecuteImplement_SpecLocked(t *testing.T) {
+	tmpDir := t.TempDir()
+	specName := "001-test-feature"
+
+	orchestrator := newTestOrchestratorWithSpecName(t, tmpDir, specName)
+	specDir := setupSpecDirectory(t, tmpDir, specName)
+	writeTestSpec(t, specDir)
+	writeTestPlan(t, specDir)
+	writeTestTasksCompleted(t, specDir)
+
+	// Simulate another live autospec process holding the lock
+	held, err := lock.Acquire(orchestrator.Config.StateDir, filepath.Join(tmpDir, specName), "implement", "")
+	if err != nil {
+		t.Fatalf("lock.Acquire() error = %v", err)
+	}
+
+	err = orchestrator.ExecuteImplement(specName, "", false, PhaseExecutionOptions{})
+	var heldErr *lock.HeldError
+	if !errors.As(err, &heldErr) {
+		t.Fatalf("ExecuteImplement() error = %v, want *lock.HeldError", err)
+	}
+	if !strings.Contains(err.Error(), "--force-unlock") {
+		t.Errorf("error should mention --force-unlock, got: %v", err)
+	}
+
+	orchestrator.ForceUnlock = true
+	if err := orchestrator.ExecuteImplement(specName, "", false, PhaseExecutionOptions{}); err != nil {
+		t.Fatalf("ExecuteImplement() with ForceUnlock error = %v, want nil", err)
+	}
+
+	// The run released its own lock on completion
+	if _, err := os.Stat(held.Path); !os.IsNotExist(err) {
+		t.Errorf("lock file should be removed after run, stat err = %v", err)
+	}
+}
+
+// TestExecuteImplement_ChecklistGate verifies open checklist items block implement
+// only when the checklist gate is enabled.
+func TestExecuteImplement_ChecklistGate(t *testing.T) {
+	tmpDir := t.TempDir()
+	specName := "001-test-feature"
+
+	orchestrator := newTestOrchestratorWithSpecName(t, tmpDir, specName)
+	specDir := setupSpecDirectory(t, tmpDir, specName)
+	writeTestSpec(t, specDir)
+	writeTestPlan(t, specDir)
+	writeTestTasksCompleted(t, specDir)
+
+	checklistsDir := filepath.Join(specDir, "checklists")
+	if err := os.MkdirAll(checklistsDir, 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	checklist := `categories:
+  - name: "Security"
+    items:
+      - id: "CHK001"
+        description: "Are rate limits defined?"
+        status: "pending"
+`
+	checklistPath := filepath.Join(checklistsDir, "security.yaml")
+	if err := os.WriteFile(checklistPath, []byte(checklist), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// Gate disabled: open items are advisory
+	if err := orchestrator.ExecuteImplement(specName, "", false, PhaseExecutionOptions{}); err != nil {
+		t.Fatalf("ExecuteImplement() without gate error = %v, want nil", err)
+	}
+
+	orchestrator.Config.ChecklistGate = true
+	err := orchestrator.ExecuteImplement(specName, "", false, PhaseExecutionOptions{})
+	var gateErr *validation.ChecklistGateError
+	if !errors.As(err, &gateErr) {
+		t.Fatalf("ExecuteImplement() error = %v, want *validation.ChecklistGateError", err)
+	}
+	if !strings.Contains(err.Error(), "security.yaml CHK001 [pending]") {
+		t.Errorf("error should list the open item, got: %v", err)
+	}
+
+	waived := strings.Replace(checklist, `status: "pending"`, "status: \"waived\"\n        notes: \"gateway handles it\"", 1)
+	if err := os.WriteFile(checklistPath, []byte(waived), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := orchestrator.ExecuteImplement(specName, "", false, PhaseExecutionOptions{}); err != nil {
+		t.Fatalf("ExecuteImplement() with waived items error = %v, want nil", err)
+	}
+}
+
+func TestExecuteImplement_AnalysisGate(t *testing.T) {
+	tmpDir := t.TempDir()
+	specName := "001-test-feature"
+
+	orchestrator := newTestOrchestratorWithSpecName(t, tmpDir, specName)
+	specDir := setupSpecDirectory(t, tmpDir, specName)
+	writeTestSpec(t, specDir)
+	writeTestPlan(t, specDir)
+	writeTestTasksCompleted(t, specDir)
+
+	analysis := `analysis:
+  branch: "001-test-feature"
+  timestamp: "2025-01-01T00:00:00Z"
+findings:
+  - id: "CON-001"
+    category: "constitution"
+    severity: "CRITICAL"
+    artifact: "tasks"
+    field: "phases[0].tasks[0]"
+    summary: "Missing test task"
+    recommendation: "Add a test task first"
+summary:
+  overall_status: "FAIL"
+`
+	analysisPath := filepath.Join(specDir, "analysis.yaml")
+	if err := os.WriteFile(analysisPath, []byte(analysis), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// Gate disabled: findings are advisory
+	if err := orchestrator.ExecuteImplement(specName, "", false, PhaseExecutionOptions{}); err != nil {
+		t.Fatalf("ExecuteImplement() without gate error = %v, want nil", err)
+	}
+
+	orchestrator.Config.AnalyzeFailOn = "critical"
+	err := orchestrator.ExecuteImplement(specName, "", false, PhaseExecutionOptions{})
+	var gateErr *validation.AnalysisGateError
+	if !errors.As(err, &gateErr) {
+		t.Fatalf("ExecuteImplement() error = %v, want *validation.AnalysisGateError", err)
+	}
+	if !strings.Contains(err.Error(), "implement blocked") || !strings.Contains(err.Error(), "CON-001 [CRITICAL]") {
+		t.Errorf("error should name the blocking finding, got: %v", err)
+	}
+
+	downgraded := strings.Replace(analysis, `severity: "CRITICAL"`, `severity: "HIGH"`, 1)
+	if err := os.WriteFile(analysisPath, []byte(downgraded), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	if err := orchestrator.ExecuteImplement(specName, "", false, PhaseExecutionOptions{}); err != nil {
+		t.Fatalf("ExecuteImplement() below threshold error = %v, want nil", err)
+	}
+}
+
+func TestExecutePlan_ReviewGate(t *testing.T) {
+	tmpDir := t.TempDir()
+	specName := "001-test-feature"
+
+	orchestrator := newTestOrchestratorWithSpecName(t, tmpDir, specName)
+	specDir := setupSpecDirectory(t, tmpDir, specName)
+	writeTestSpec(t, specDir)
+
+	orchestrator.Config.Review = review.Config{Enabled: true, RequiredReviewers: []string{"alice"}}
+	err := orchestrator.ExecutePlan(specName, "")
+	var gateErr *review.GateError
+	if !errors.As(err, &gateErr) {
+		t.Fatalf("ExecutePlan() error = %v, want *review.GateError", err)
+	}
+	if !strings.Contains(err.Error(), "plan blocked") || !strings.Contains(err.Error(), "alice") {
+		t.Errorf("error should name the missing reviewer, got: %v", err)
+	}
+
+	hash, err := review.SpecHash(specDir)
+	if err != nil {
+		t.Fatalf("SpecHash() error = %v", err)
+	}
+	sections, err := review.Sections(filepath.Join(specDir, "spec.yaml"))
+	if err != nil {
+		t.Fatalf("Sections() error = %v", err)
+	}
+	approval := review.Review{Reviewer: "alice", SpecHash: hash}
+	for _, s := range sections {
+		approval.Sections = append(approval.Sections, review.Decision{Section: s.Name, Decision: review.DecisionAccept})
+	}
+	if err := review.Save(specDir, &review.File{Reviews: []review.Review{approval}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := orchestrator.ExecutePlan(specName, ""); err != nil {
+		t.Fatalf("ExecutePlan() after approval error = %v, want nil", err)
+	}
+}
+
 // =============================================================================
 // Run* Workflow Tests (Phase 4 Tasks T009-T010)
 // =============================================================================
 
 // TestRunCompleteWorkflow_Success tests RunCompleteWorkflow executes specify → plan → tasks
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestRunCompleteWorkflow_Success(t *testing.T) {
 	tests := map[string]struct {
 		featureDesc string
@@ -3063,7 +3324,7 @@ func TestRunCompleteWorkflow_Success(t *testing.T) {
 			// Create isolated temp directory
 			tmpDir := t.TempDir()
 
-			// Create orchestrator with mock - mock-claude.sh will generate artifacts
+			// Create orchestrator with mock - the mock agent will generate artifacts
 			// for each stage (specify → plan → tasks)
 			orchestrator := newTestOrchestratorWithSpecName(t, tmpDir, tt.specName)
 			orchestrator.SkipPreflight = true
@@ -3100,7 +3361,7 @@ func TestRunCompleteWorkflow_Success(t *testing.T) {
 }
 
 // TestRunFullWorkflow_Success tests RunFullWorkflow executes specify → plan → tasks → implement
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestRunFullWorkflow_Success(t *testing.T) {
 	tests := map[string]struct {
 		featureDesc string
@@ -3126,7 +3387,7 @@ func TestRunFullWorkflow_Success(t *testing.T) {
 			// Create isolated temp directory
 			tmpDir := t.TempDir()
 
-			// Create orchestrator with mock - mock-claude.sh will generate artifacts
+			// Create orchestrator with mock - the mock agent will generate artifacts
 			// for all four stages (specify → plan → tasks → implement)
 			orchestrator := newTestOrchestratorWithSpecName(t, tmpDir, tt.specName)
 			orchestrator.SkipPreflight = true
@@ -3167,7 +3428,7 @@ func TestRunFullWorkflow_Success(t *testing.T) {
 // =============================================================================
 
 // TestExecuteSpecify_ValidationFailure tests ExecuteSpecify when mock returns non-zero exit code
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestExecuteSpecify_ValidationFailure(t *testing.T) {
 	tests := map[string]struct {
 		featureDesc  string
@@ -3219,7 +3480,7 @@ func TestExecuteSpecify_ValidationFailure(t *testing.T) {
 }
 
 // TestExecuteWithRetry_RetriesOnFailure tests that ExecuteSpecify retries when configured
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestExecuteWithRetry_RetriesOnFailure(t *testing.T) {
 	// This test verifies retry behavior by checking call count via MOCK_CALL_LOG
 	// The mock script logs each invocation, allowing verification of retry attempts
@@ -3271,7 +3532,7 @@ func TestExecuteWithRetry_RetriesOnFailure(t *testing.T) {
 }
 
 // TestAuxiliaryExecuteMethods tests ExecuteConstitution, ExecuteClarify, ExecuteChecklist, ExecuteAnalyze
-// Note: Cannot use t.Parallel() because tests use t.Setenv for mock-claude.sh configuration
+// Note: Cannot use t.Parallel() because tests use t.Setenv for mock agent configuration
 func TestAuxiliaryExecuteMethods(t *testing.T) {
 	// Table-driven test for all auxiliary Execute* methods
 	tests := map[string]struct {
@@ -3356,6 +3617,28 @@ func TestAuxiliaryExecuteMethods(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		"ExecuteAnalyze below fail-on threshold": {
+			methodName: "ExecuteAnalyze",
+			setup: func(t *testing.T, specDir string) {
+				writeTestSpec(t, specDir)
+			},
+			execute: func(o *WorkflowOrchestrator, specName string) error {
+				o.Config.AnalyzeFailOn = "critical" // mock writes a single LOW finding
+				return o.ExecuteAnalyze(specName, "")
+			},
+			wantErr: false,
+		},
+		"ExecuteAnalyze at fail-on threshold": {
+			methodName: "ExecuteAnalyze",
+			setup: func(t *testing.T, specDir string) {
+				writeTestSpec(t, specDir)
+			},
+			execute: func(o *WorkflowOrchestrator, specName string) error {
+				o.Config.AnalyzeFailOn = "low"
+				return o.ExecuteAnalyze(specName, "")
+			},
+			wantErr: true,
+		},
 	}
 
 	for name, tt := range tests {
@@ -3635,6 +3918,60 @@ _meta:
 	})
 }
 
+// TestExecuteImplementSingleTask tests running one task by ID
+func TestExecuteImplementSingleTask(t *testing.T) {
+	tasks := []validation.TaskItem{
+		{ID: "T001", Title: "Done", Status: "Completed"},
+		{ID: "T002", Title: "Next", Status: "Pending"},
+		{ID: "T003", Title: "Stuck", Status: "Blocked"},
+	}
+	tests := map[string]struct {
+		idx        int
+		prepareErr error
+		runErr     error
+		wantRun    bool
+		wantErr    string
+	}{
+		"runs pending task":       {idx: 1, wantRun: true},
+		"skips completed task":    {idx: 0},
+		"skips blocked task":      {idx: 2},
+		"unmet dependencies fail": {idx: 1, prepareErr: errors.New("dependencies not met"), wantErr: "dependencies not met"},
+		"session failure":         {idx: 1, runErr: errors.New("agent crashed"), wantRun: true, wantErr: "executing task T002: agent crashed"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			orchestrator := newTestOrchestratorWithSpecName(t, tmpDir, "001-test-feature")
+			mock := NewMockTaskExecutor()
+			mock.PrepareResult = tasks
+			mock.PrepareStartIdx = tt.idx
+			mock.PrepareTotalTasks = len(tasks)
+			mock.PrepareError = tt.prepareErr
+			mock.SingleTaskError = tt.runErr
+			orchestrator.taskExecutor = mock
+
+			err := orchestrator.ExecuteImplementSingleTask("001-test-feature", nil, "", tasks[tt.idx].ID)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ExecuteImplementSingleTask() error = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ExecuteImplementSingleTask() error = %v, want containing %q", err, tt.wantErr)
+			}
+			if len(mock.PrepareCalls) != 1 || mock.PrepareCalls[0].FromTask != tasks[tt.idx].ID {
+				t.Errorf("PrepareTaskExecution calls = %+v, want one for %s", mock.PrepareCalls, tasks[tt.idx].ID)
+			}
+			ran := len(mock.SingleTaskCalls) == 1 && mock.SingleTaskCalls[0].TaskID == tasks[tt.idx].ID
+			if ran != tt.wantRun || len(mock.SingleTaskCalls) > 1 {
+				t.Errorf("ExecuteSingleTask calls = %+v, want run = %v", mock.SingleTaskCalls, tt.wantRun)
+			}
+			if len(mock.TaskLoopCalls) != 0 {
+				t.Errorf("ExecuteTaskLoop called %d times, want 0", len(mock.TaskLoopCalls))
+			}
+		})
+	}
+}
+
 // TestExecuteImplementSinglePhase tests single phase execution
 func TestExecuteImplementSinglePhase(t *testing.T) {
 	t.Run("phase not found returns error", func(t *testing.T) {
@@ -5099,3 +5436,130 @@ _meta:
 		t.Fatalf("Failed to write tasks.yaml: %v", err)
 	}
 }
+
+// TestExecuteAmend_MarksDownstreamStale verifies amend delegates to the
+// StageExecutor and marks existing downstream artifacts stale.
+func TestExecuteAmend_MarksDownstreamStale(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		artifacts []string
+		amendErr  error
+		wantStale []string
+		wantErr   bool
+	}{
+		"plan and tasks become stale": {
+			artifacts: []string{"spec.yaml", "plan.yaml", "tasks.yaml"},
+			wantStale: []string{"plan.yaml", "tasks.yaml"},
+		},
+		"only existing artifacts are marked": {
+			artifacts: []string{"spec.yaml", "plan.yaml"},
+			wantStale: []string{"plan.yaml"},
+		},
+		"failed amend marks nothing": {
+			artifacts: []string{"spec.yaml", "plan.yaml"},
+			amendErr:  fmt.Errorf("agent failed"),
+			wantErr:   true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			specsDir := t.TempDir()
+			specDir := filepath.Join(specsDir, "001-test")
+			if err := os.MkdirAll(specDir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for _, artifact := range tt.artifacts {
+				if err := os.WriteFile(filepath.Join(specDir, artifact), []byte("x: 1\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mockStage := NewMockStageExecutor()
+			mockStage.AmendError = tt.amendErr
+			orch := NewWorkflowOrchestratorWithExecutors(&config.Configuration{SpecsDir: specsDir}, ExecutorOptions{
+				StageExecutor: mockStage,
+			})
+
+			stale, err := orch.ExecuteAmend("001-test", "Drop CSV export")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteAmend() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if len(mockStage.AmendCalls) != 1 || mockStage.AmendCalls[0].Change != "Drop CSV export" {
+				t.Errorf("AmendCalls = %+v, want one call with the change", mockStage.AmendCalls)
+			}
+
+			onDisk, err := spec.LoadStaleArtifacts(specDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(onDisk) != len(tt.wantStale) || len(stale) != len(tt.wantStale) {
+				t.Fatalf("stale = %+v (on disk %+v), want %v", stale, onDisk, tt.wantStale)
+			}
+			for i, want := range tt.wantStale {
+				if onDisk[i].Artifact != want {
+					t.Errorf("stale[%d] = %s, want %s", i, onDisk[i].Artifact, want)
+				}
+			}
+		})
+	}
+}
+
+// TestExecuteForecast_ReturnsRollup verifies forecast delegates to the
+// StageExecutor and returns the rollup of the written forecast.yaml.
+func TestExecuteForecast_ReturnsRollup(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		forecast    string
+		forecastErr error
+		wantRollup  *validation.ForecastRollup
+		wantErr     bool
+	}{
+		"rollup of written forecast": {
+			forecast:   "summary:\n  total_effort_hours: 7.5\n  overall_risk: high\nestimates:\n  - {task_id: T001, effort_hours: 7.5, risk: high}\n",
+			wantRollup: &validation.ForecastRollup{TotalEffortHours: 7.5, OverallRisk: "high", Tasks: 1, HighRisk: 1},
+		},
+		"failed forecast": {
+			forecastErr: fmt.Errorf("agent failed"),
+			wantErr:     true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			specsDir := t.TempDir()
+			specDir := filepath.Join(specsDir, "001-test")
+			if err := os.MkdirAll(specDir, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if tt.forecast != "" {
+				if err := os.WriteFile(filepath.Join(specDir, "forecast.yaml"), []byte(tt.forecast), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			mockStage := NewMockStageExecutor()
+			mockStage.ForecastError = tt.forecastErr
+			orch := NewWorkflowOrchestratorWithExecutors(&config.Configuration{SpecsDir: specsDir}, ExecutorOptions{
+				StageExecutor: mockStage,
+			})
+
+			rollup, err := orch.ExecuteForecast("001-test", "assume one developer")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ExecuteForecast() error = %v, wantErr = %v", err, tt.wantErr)
+			}
+			if len(mockStage.ForecastCalls) != 1 || mockStage.ForecastCalls[0].Prompt != "assume one developer" {
+				t.Errorf("ForecastCalls = %+v, want one call with the prompt", mockStage.ForecastCalls)
+			}
+			if tt.wantRollup != nil && (rollup == nil || *rollup != *tt.wantRollup) {
+				t.Errorf("rollup = %+v, want %+v", rollup, tt.wantRollup)
+			}
+		})
+	}
+}
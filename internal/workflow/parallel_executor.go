@@ -4,9 +4,11 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -30,6 +32,7 @@ type ParallelExecutor struct {
 	skippedTasks    map[string]string    // Tasks skipped due to failed dependencies
 	worktreePaths   map[string]string    // TaskID -> worktree path mapping
 	progressCb      ProgressCallback     // Callback for progress updates
+	failFast        bool                 // Cancel the run at the first task failure
 	mu              sync.Mutex           // Protects failedTasks, skippedTasks, worktreePaths
 
 	// Dependencies injected for testing
@@ -114,6 +117,14 @@ func WithWorktreeDir(dir string) ParallelExecutorOption {
 	}
 }
 
+// WithFailFast stops the run at the first task failure: running tasks are
+// cancelled and later waves do not start.
+func WithFailFast(failFast bool) ParallelExecutorOption {
+	return func(pe *ParallelExecutor) {
+		pe.failFast = failFast
+	}
+}
+
 // WithProgressCallback sets the progress callback function.
 func WithProgressCallback(cb ProgressCallback) ParallelExecutorOption {
 	return func(pe *ParallelExecutor) {
@@ -152,7 +163,7 @@ func (pe *ParallelExecutor) ExecuteWaves(ctx context.Context, specName, tasksPat
 	for _, wave := range waves {
 		select {
 		case <-ctx.Done():
-			return results, ctx.Err()
+			return results, context.Cause(ctx)
 		default:
 		}
 
@@ -162,6 +173,9 @@ func (pe *ParallelExecutor) ExecuteWaves(ctx context.Context, specName, tasksPat
 		if err != nil {
 			return results, fmt.Errorf("executing wave %d: %w", wave.Number, err)
 		}
+		if pe.failFast && waveResult.Status == dag.WavePartialFailed {
+			return results, fmt.Errorf("wave %d: %w (--fail-fast)", wave.Number, pe.firstFailure(waveResult))
+		}
 	}
 
 	return results, nil
@@ -216,6 +230,32 @@ func (pe *ParallelExecutor) executeWave(ctx context.Context, wave dag.ExecutionW
 	return result, nil
 }
 
+// firstFailure returns the error of the failed task in result that stopped
+// the wave: the first by task ID that was not cancelled because of another.
+func (pe *ParallelExecutor) firstFailure(result *WaveResult) error {
+	taskIDs := make([]string, 0, len(result.Results))
+	for taskID := range result.Results {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+
+	var cancelled error
+	for _, taskID := range taskIDs {
+		tr := result.Results[taskID]
+		if tr.Success || tr.Skipped {
+			continue
+		}
+		err := fmt.Errorf("task %s failed: %w", taskID, tr.Error)
+		if !errors.Is(tr.Error, context.Canceled) {
+			return err
+		}
+		if cancelled == nil {
+			cancelled = err
+		}
+	}
+	return cancelled
+}
+
 // filterTasksToRun checks dependencies and returns tasks that can run.
 func (pe *ParallelExecutor) filterTasksToRun(taskIDs []string) ([]string, map[string]string) {
 	pe.mu.Lock()
@@ -269,6 +309,10 @@ func (pe *ParallelExecutor) runTasksConcurrently(ctx context.Context, taskIDs []
 		g.Go(func() error {
 			result := pe.executeTask(ctx, taskID, specName, tasksPath)
 			resultsChan <- result
+			if pe.failFast && !result.Success {
+				// Cancels the wave's context, stopping the other tasks
+				return fmt.Errorf("task %s failed: %w", taskID, result.Error)
+			}
 			return nil // Don't propagate errors to allow other tasks to continue
 		})
 	}
@@ -289,6 +289,50 @@ func TestParallelExecutor_ExecuteWaves_SiblingsContinue(t *testing.T) {
 	assert.True(t, results[0].Results["T003"].Success)
 }
 
+func TestParallelExecutor_ExecuteWaves_FailFast(t *testing.T) {
+	t.Parallel()
+
+	// T001, T002 independent in wave 1; T003 depends on T002 only
+	tasks := []validation.TaskItem{
+		{ID: "T001", Dependencies: []string{}},
+		{ID: "T002", Dependencies: []string{}},
+		{ID: "T003", Dependencies: []string{"T002"}},
+	}
+
+	g, err := dag.BuildFromTasks(tasks)
+	require.NoError(t, err)
+	_, err = g.ComputeWaves()
+	require.NoError(t, err)
+
+	runner := newMockTaskRunner()
+	runner.FailTask("T001", errors.New("T001 failed"))
+
+	pe := NewParallelExecutor(g, WithTaskRunner(runner), WithFailFast(true))
+
+	results, err := pe.ExecuteWaves(context.Background(), "test-spec", "tasks.yaml")
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "task T001 failed: T001 failed")
+	assert.Contains(t, err.Error(), "--fail-fast")
+	assert.Len(t, results, 1, "wave 2 should not start")
+	assert.NotContains(t, runner.TaskHistory(), "T003")
+}
+
+func TestParallelExecutor_FirstFailure(t *testing.T) {
+	t.Parallel()
+
+	pe := NewParallelExecutor(nil)
+	result := &WaveResult{Results: map[string]*ParallelTaskResult{
+		"T001": {TaskID: "T001", Error: context.Canceled},
+		"T002": {TaskID: "T002", Error: errors.New("tests failed")},
+		"T003": {TaskID: "T003", Success: true},
+	}}
+
+	err := pe.firstFailure(result)
+	require.Error(t, err)
+	assert.Equal(t, "task T002 failed: tests failed", err.Error())
+}
+
 func TestParallelExecutor_ExecuteWaves_ContextCancellation(t *testing.T) {
 	t.Parallel()
 
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 )
 
 // ParallelExecutionState persists the state of a parallel execution.
@@ -78,7 +80,7 @@ func SaveParallelState(stateDir, specName string, state *ParallelExecutionState)
 	}
 
 	statePath := filepath.Join(stateSubDir, stateFileName)
-	if err := os.WriteFile(statePath, data, 0o644); err != nil {
+	if err := fsutil.WriteFile(statePath, data, 0o644); err != nil {
 		return fmt.Errorf("writing parallel state: %w", err)
 	}
 
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 	"gopkg.in/yaml.v3"
 )
 
@@ -295,7 +296,7 @@ func WriteContextFile(ctx *PhaseContext) (string, error) {
 
 	// Write with header comment
 	content := contextFileHeader + string(data)
-	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+	if err := fsutil.WriteFile(path, []byte(content), 0o644); err != nil {
 		return "", fmt.Errorf("failed to write context file: %w", err)
 	}
 
@@ -16,6 +16,8 @@ const (
 	ModeAllTasks
 	// ModeParallel executes independent tasks concurrently using DAG-based wave scheduling
 	ModeParallel
+	// ModeSingleTask executes only a specific task in a separate Claude session
+	ModeSingleTask
 )
 
 // PhaseExecutionOptions contains configuration for phase-based execution
@@ -30,6 +32,8 @@ type PhaseExecutionOptions struct {
 	TaskMode bool
 	// FromTask is the task ID to start from (--from-task TXXX, empty = not set)
 	FromTask string
+	// SingleTask is the only task to run (--task TXXX, empty = not set)
+	SingleTask string
 	// ParallelMode indicates --parallel flag was set (DAG-based concurrent execution)
 	ParallelMode bool
 	// MaxParallel is the maximum number of concurrent Claude sessions (default 4)
@@ -47,6 +51,9 @@ func (o *PhaseExecutionOptions) Mode() PhaseExecutionMode {
 	if o.ParallelMode {
 		return ModeParallel
 	}
+	if o.SingleTask != "" {
+		return ModeSingleTask
+	}
 	if o.TaskMode {
 		return ModeAllTasks
 	}
@@ -51,6 +51,19 @@ func TestPhaseExecutionOptions_Mode(t *testing.T) {
 			},
 			wantMode: ModeSinglePhase,
 		},
+		"single task mode": {
+			opts: PhaseExecutionOptions{
+				SingleTask: "T003",
+			},
+			wantMode: ModeSingleTask,
+		},
+		"single task over task mode": {
+			opts: PhaseExecutionOptions{
+				TaskMode:   true,
+				SingleTask: "T003",
+			},
+			wantMode: ModeSingleTask,
+		},
 	}
 
 	for name, tc := range tests {
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/ariel-frischer/autospec/internal/output"
 	"github.com/ariel-frischer/autospec/internal/validation"
 )
 
@@ -33,11 +34,9 @@ func NewPhaseExecutor(executor *Executor, specsDir string, debug bool) *PhaseExe
 	}
 }
 
-// debugLog prints a debug message if debug mode is enabled.
+// debugLog writes a debug record tagged with the phase_executor component.
 func (p *PhaseExecutor) debugLog(format string, args ...interface{}) {
-	if p.debug {
-		fmt.Printf("[DEBUG][PhaseExecutor] "+format+"\n", args...)
-	}
+	p.executor.logger("phase_executor").Debug(fmt.Sprintf(format, args...))
 }
 
 // ExecutePhaseLoop iterates through phases from startPhase to totalPhases.
@@ -51,21 +50,49 @@ func (p *PhaseExecutor) debugLog(format string, args ...interface{}) {
 func (p *PhaseExecutor) ExecutePhaseLoop(specName, tasksPath string, phases []validation.PhaseInfo, startPhase, totalPhases int, prompt string) error {
 	p.debugLog("ExecutePhaseLoop called: spec=%s, startPhase=%d, totalPhases=%d", specName, startPhase, totalPhases)
 	specDir := filepath.Join(p.specsDir, specName)
+	bar := newRunProgress("phase", totalPhases, countCompletePhases(phases))
+	p.executor.Status.HoldStage()
+	defer p.executor.watchStage(StageImplement)()
+	defer p.executor.listenForPause("phase")()
 
 	for _, phase := range phases {
 		if phase.Number < startPhase {
 			continue
 		}
 
+		// Phases complete before the run are skipped quickly and would skew the average
+		pending := !phase.IsComplete()
+		if pending {
+			if err := p.executor.waitIfPaused(fmt.Sprintf("phase %d", phase.Number)); err != nil {
+				return fmt.Errorf("waiting to start phase %d: %w", phase.Number, err)
+			}
+			bar.Start(fmt.Sprintf("Phase %d: %s", phase.Number, phase.Title))
+		}
 		if err := p.executeAndVerifyPhase(specName, tasksPath, phase, totalPhases, prompt); err != nil {
 			return fmt.Errorf("executing phase %d: %w", phase.Number, err)
 		}
+		if pending {
+			bar.Complete()
+		}
 	}
 
+	p.executor.Status.ReleaseStage(string(StageImplement))
+	p.executor.setCurrentTask("", "")
 	p.printPhasesSummary(tasksPath, specDir)
 	return nil
 }
 
+// countCompletePhases returns how many phases have no actionable tasks left.
+func countCompletePhases(phases []validation.PhaseInfo) int {
+	count := 0
+	for _, phase := range phases {
+		if phase.IsComplete() {
+			count++
+		}
+	}
+	return count
+}
+
 // ExecuteSinglePhase runs a specific phase in isolation.
 // specName: the spec directory name
 // phaseNumber: 1-based phase number to execute
@@ -80,6 +107,7 @@ func (p *PhaseExecutor) executeAndVerifyPhase(specName, tasksPath string, phase
 	taskIDs := p.getTaskIDsForPhase(tasksPath, phase.Number)
 	displayInfo := validation.BuildPhaseDisplayInfo(phase, totalPhases, taskIDs)
 	fmt.Println(validation.FormatPhaseHeader(displayInfo))
+	p.executor.setCurrentTask(fmt.Sprintf("phase %d/%d", phase.Number, totalPhases), phase.Title)
 
 	if err := p.executeSinglePhaseSession(specName, phase.Number, prompt); err != nil {
 		return fmt.Errorf("phase %d failed: %w", phase.Number, err)
@@ -98,7 +126,12 @@ func (p *PhaseExecutor) executeAndVerifyPhase(specName, tasksPath string, phase
 	}
 
 	p.printPhaseCompletion(phase.Number, updatedPhase)
+	p.executor.syncIssues(filepath.Dir(tasksPath))
+	p.executor.syncProject(filepath.Dir(tasksPath))
 	fmt.Println()
+	if p.executor.FailFast && updatedPhase != nil && updatedPhase.BlockedTasks > phase.BlockedTasks {
+		return fmt.Errorf("phase %d blocked %d task(s) (--fail-fast)", phase.Number, updatedPhase.BlockedTasks-phase.BlockedTasks)
+	}
 	return nil
 }
 
@@ -131,12 +164,32 @@ func (p *PhaseExecutor) executeSinglePhaseSession(specName string, phaseNumber i
 	EnsureContextDirGitignored()
 
 	// Build and execute command
-	command := p.buildPhaseCommand(phaseNumber, contextFilePath, prompt)
+	prompt, err = p.executor.renderPrompt(PromptImplement, PromptTemplateData{SpecName: specName, Phase: phaseNumber, Prompt: prompt})
+	if err != nil {
+		return err
+	}
+	command := p.buildPhaseCommand(phaseNumber, p.contextFileArg(specName, contextFilePath), prompt)
 	fmt.Printf("Executing: %s\n", command)
 
 	return p.executePhaseWithValidation(specName, phaseNumber, command)
 }
 
+// contextFileArg returns the context file path to pass to the agent. An agent
+// running in a workdir needs the absolute path to find the file, on the
+// remote host when it runs there.
+func (p *PhaseExecutor) contextFileArg(specName, contextFilePath string) string {
+	if dir, _ := p.executor.agentWorkDir(specName, StageImplement); dir == "" {
+		return contextFilePath
+	}
+	if remotePath, ok := p.executor.remoteContextFile(contextFilePath); ok {
+		return remotePath
+	}
+	if abs, err := filepath.Abs(contextFilePath); err == nil {
+		return abs
+	}
+	return contextFilePath
+}
+
 // checkPhaseSkipConditions checks if a phase should be skipped.
 // Returns (shouldSkip, error).
 func (p *PhaseExecutor) checkPhaseSkipConditions(tasksPath string, phaseNumber int) (bool, error) {
@@ -197,13 +250,14 @@ func (p *PhaseExecutor) buildAndWritePhaseContext(specDir string, phaseNumber, t
 // buildPhaseCommand constructs the implement command with phase filter and context file.
 func (p *PhaseExecutor) buildPhaseCommand(phaseNumber int, contextFilePath, prompt string) string {
 	if prompt != "" {
-		return fmt.Sprintf("/autospec.implement --phase %d --context-file %s \"%s\"", phaseNumber, contextFilePath, prompt)
+		return fmt.Sprintf("/autospec.implement --phase %d --context-file %s %s", phaseNumber, contextFilePath, quotePrompt(prompt))
 	}
 	return fmt.Sprintf("/autospec.implement --phase %d --context-file %s", phaseNumber, contextFilePath)
 }
 
 // executePhaseWithValidation executes the phase command with validation.
 func (p *PhaseExecutor) executePhaseWithValidation(specName string, phaseNumber int, command string) error {
+	defer p.executor.scopeSessions("", phaseNumber)()
 	result, err := p.executor.ExecuteStage(
 		specName,
 		StageImplement,
@@ -259,25 +313,20 @@ func (p *PhaseExecutor) getUpdatedPhaseInfo(tasksPath string, phaseNumber int) *
 
 // printPhaseCompletion prints the phase completion message.
 func (p *PhaseExecutor) printPhaseCompletion(phaseNumber int, updatedPhase *validation.PhaseInfo) {
+	ui := output.Default()
 	if updatedPhase != nil {
-		fmt.Println(validation.FormatPhaseCompletion(phaseNumber, updatedPhase.CompletedTasks, updatedPhase.TotalTasks, updatedPhase.BlockedTasks))
+		ui.Success("%s", validation.FormatPhaseCompletion(phaseNumber, updatedPhase.CompletedTasks, updatedPhase.TotalTasks, updatedPhase.BlockedTasks))
 	} else {
-		fmt.Printf("✓ Phase %d complete\n", phaseNumber)
+		ui.Success("Phase %d complete", phaseNumber)
 	}
 }
 
 // printPhasesSummary prints the final phase execution summary and marks spec as completed.
 func (p *PhaseExecutor) printPhasesSummary(tasksPath, specDir string) {
-	fmt.Println("✓ All phases completed!")
-	fmt.Println()
-	stats, statsErr := validation.GetTaskStats(tasksPath)
-	if statsErr == nil && stats.TotalTasks > 0 {
-		fmt.Println("Task Summary:")
-		fmt.Print(validation.FormatTaskSummary(stats))
-	}
+	printCompletionSummary("All phases completed!", tasksPath)
 
 	// Mark spec as completed
-	markSpecCompletedAndPrint(specDir)
+	p.executor.completeSpec(specDir)
 }
 
 // ExecuteDefault runs all implementation in a single Claude session.
@@ -289,6 +338,10 @@ func (p *PhaseExecutor) ExecuteDefault(specName, specDir, prompt string, resume
 	fmt.Printf("Progress: checking tasks...\n\n")
 
 	// Build command with optional prompt and resume flag
+	prompt, err := p.executor.renderPrompt(PromptImplement, PromptTemplateData{SpecName: specName, Prompt: prompt})
+	if err != nil {
+		return err
+	}
 	command := p.buildDefaultCommand(prompt, resume)
 	p.printExecuting("/autospec.implement", prompt)
 
@@ -331,9 +384,9 @@ func (p *PhaseExecutor) buildDefaultCommand(prompt string, resume bool) string {
 	}
 	if prompt != "" {
 		if resume {
-			return fmt.Sprintf("/autospec.implement --resume \"%s\"", prompt)
+			return fmt.Sprintf("/autospec.implement --resume %s", quotePrompt(prompt))
 		}
-		return fmt.Sprintf("/autospec.implement \"%s\"", prompt)
+		return fmt.Sprintf("/autospec.implement %s", quotePrompt(prompt))
 	}
 	return command
 }
@@ -341,7 +394,7 @@ func (p *PhaseExecutor) buildDefaultCommand(prompt string, resume bool) string {
 // printExecuting prints the executing message for a command.
 func (p *PhaseExecutor) printExecuting(baseCmd, prompt string) {
 	if prompt != "" {
-		fmt.Printf("Executing: %s \"%s\"\n", baseCmd, prompt)
+		fmt.Printf("Executing: %s %s\n", baseCmd, quotePrompt(prompt))
 	} else {
 		fmt.Printf("Executing: %s\n", baseCmd)
 	}
@@ -0,0 +1,200 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// Pipeline is a named, ordered list of custom stages run against one spec.
+// Pipelines come from the 'pipelines' config section or RegisterPipeline.
+type Pipeline struct {
+	Name        string
+	Description string
+	Stages      []config.PipelineStageConfig
+}
+
+// PipelineCommandData is the data available to a pipeline stage's command template.
+type PipelineCommandData struct {
+	Pipeline string
+	Stage    string
+	Spec     string
+	SpecDir  string
+	Artifact string
+	Prompt   string
+}
+
+// PipelineStage returns the Stage a pipeline stage runs as. The pipeline name
+// prefix keeps its retry state, stats and transcripts apart from built-in
+// stages and other pipelines.
+func PipelineStage(pipeline, stage string) Stage {
+	return Stage(pipeline + "." + stage)
+}
+
+// RegisterPipeline makes p available to ExecutePipeline, replacing a pipeline
+// of the same name.
+func (w *WorkflowOrchestrator) RegisterPipeline(p Pipeline) {
+	if w.pipelines == nil {
+		w.pipelines = make(map[string]Pipeline)
+	}
+	w.pipelines[p.Name] = p
+}
+
+// registerConfiguredPipelines registers the pipelines defined in config.
+func (w *WorkflowOrchestrator) registerConfiguredPipelines(cfg *config.Configuration) {
+	for name, p := range cfg.Pipelines {
+		w.RegisterPipeline(Pipeline{Name: name, Description: p.Description, Stages: p.Stages})
+	}
+}
+
+// Pipelines returns the registered pipelines sorted by name.
+func (w *WorkflowOrchestrator) Pipelines() []Pipeline {
+	pipelines := make([]Pipeline, 0, len(w.pipelines))
+	for _, p := range w.pipelines {
+		pipelines = append(pipelines, p)
+	}
+	sort.Slice(pipelines, func(i, j int) bool { return pipelines[i].Name < pipelines[j].Name })
+	return pipelines
+}
+
+// LookupPipeline returns the registered pipeline with the given name, or an
+// error listing the registered pipelines.
+func (w *WorkflowOrchestrator) LookupPipeline(name string) (Pipeline, error) {
+	if p, ok := w.pipelines[name]; ok {
+		return p, nil
+	}
+	names := make([]string, 0, len(w.pipelines))
+	for _, p := range w.Pipelines() {
+		names = append(names, p.Name)
+	}
+	if len(names) == 0 {
+		return Pipeline{}, fmt.Errorf("unknown pipeline %q (none defined; add one under 'pipelines' in config)", name)
+	}
+	return Pipeline{}, fmt.Errorf("unknown pipeline %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// ExecutePipeline runs the named pipeline's stages in order against a spec.
+// specNameArg selects the spec; empty auto-detects it. prompt is available to
+// command templates as {{.Prompt}}. The first failing stage stops the pipeline.
+func (w *WorkflowOrchestrator) ExecutePipeline(name, specNameArg, prompt string) error {
+	p, err := w.LookupPipeline(name)
+	if err != nil {
+		return err
+	}
+
+	specName := specNameArg
+	if specName == "" {
+		metadata, err := spec.DetectCurrentSpec(w.SpecsDir)
+		if err != nil {
+			return fmt.Errorf("pipeline %s: detecting spec: %w", p.Name, err)
+		}
+		specName = metadata.DirName()
+	}
+	specDir := filepath.Join(w.SpecsDir, specName)
+
+	total := len(p.Stages)
+	w.Executor.Status.SetTotalStages(total)
+	for i, s := range p.Stages {
+		output.PrintStageHeader(os.Stdout, i+1, total, s.Name)
+		command, err := RenderPipelineCommand(s, PipelineCommandData{
+			Pipeline: p.Name,
+			Stage:    s.Name,
+			Spec:     specName,
+			SpecDir:  specDir,
+			Artifact: s.Artifact,
+			Prompt:   prompt,
+		})
+		if err != nil {
+			return fmt.Errorf("pipeline %s: %w", p.Name, err)
+		}
+		fmt.Printf("Executing: %s\n", command)
+
+		if err := w.executePipelineStage(p.Name, s, specName, command); err != nil {
+			return fmt.Errorf("pipeline %s: stage %s failed: %w", p.Name, s.Name, err)
+		}
+		if s.Artifact != "" {
+			output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Wrote specs/%s/%s", specName, s.Artifact))
+		} else {
+			output.PrintStageSuccess(os.Stdout, fmt.Sprintf("Completed %s", s.Name))
+		}
+	}
+	return nil
+}
+
+// executePipelineStage runs one stage, with its retries override applied to
+// the executor for the duration of the stage.
+func (w *WorkflowOrchestrator) executePipelineStage(pipeline string, s config.PipelineStageConfig, specName, command string) error {
+	if s.Retries != nil {
+		saved := w.Executor.MaxRetries
+		w.Executor.MaxRetries = *s.Retries
+		defer func() { w.Executor.MaxRetries = saved }()
+	}
+
+	result, err := w.Executor.ExecuteStage(specName, PipelineStage(pipeline, s.Name), command, PipelineStageValidator(s))
+	if err != nil {
+		return fmt.Errorf("after %d total attempts: %w", result.RetryCount+1, err)
+	}
+	return nil
+}
+
+// RenderPipelineCommand expands a stage's command template.
+func RenderPipelineCommand(s config.PipelineStageConfig, data PipelineCommandData) (string, error) {
+	tmpl, err := template.New(s.Name).Option("missingkey=error").Parse(s.Command)
+	if err != nil {
+		return "", fmt.Errorf("parsing command of stage %s: %w", s.Name, err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("rendering command of stage %s: %w", s.Name, err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// PipelineStageValidator returns the ExecuteStage validation callback for a
+// pipeline stage, following its validator setting.
+func PipelineStageValidator(s config.PipelineStageConfig) func(string) error {
+	return func(specDir string) error {
+		if s.Artifact == "" || s.Validator == config.PipelineValidatorNone {
+			return nil
+		}
+		path := filepath.Join(specDir, s.Artifact)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("stage %s did not write %s: %w", s.Name, s.Artifact, err)
+		}
+
+		switch s.Validator {
+		case "":
+			return nil
+		case config.PipelineValidatorYAML:
+			var doc yaml.Node
+			if err := yaml.Unmarshal(data, &doc); err != nil {
+				return fmt.Errorf("%s is not valid YAML: %w", s.Artifact, err)
+			}
+			return nil
+		}
+
+		artifactType, err := validation.ParseArtifactType(s.Validator)
+		if err != nil {
+			return err
+		}
+		validator, err := validation.NewArtifactValidator(artifactType)
+		if err != nil {
+			return err
+		}
+		result := validator.Validate(path)
+		if result.Valid {
+			return nil
+		}
+		return formatValidationErrors(s.Artifact, result.Errors)
+	}
+}
@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPipelineCommand(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		command string
+		prompt  string
+		want    string
+		wantErr bool
+	}{
+		"all fields": {
+			command: "/docs.{{.Stage}} {{.SpecDir}}/spec.yaml -> {{.Artifact}} ({{.Pipeline}}, {{.Spec}}) {{.Prompt}}",
+			prompt:  "for API users",
+			want:    "/docs.outline specs/003-auth/spec.yaml -> outline.yaml (docs, 003-auth) for API users",
+		},
+		"trims empty prompt": {command: "/docs.outline {{.Prompt}}", want: "/docs.outline"},
+		"unknown field":      {command: "/docs {{.Branch}}", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			d := PipelineCommandData{Pipeline: "docs", Stage: "outline", Spec: "003-auth", SpecDir: "specs/003-auth", Artifact: "outline.yaml", Prompt: tt.prompt}
+			got, err := RenderPipelineCommand(config.PipelineStageConfig{Name: "outline", Command: tt.command}, d)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPipelineStageValidator(t *testing.T) {
+	t.Parallel()
+	validResearch, err := os.ReadFile(filepath.Join("..", "validation", "testdata", "research", "valid.yaml"))
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		stage   config.PipelineStageConfig
+		content string // written to the artifact when not empty
+		wantErr string
+	}{
+		"no artifact": {
+			stage: config.PipelineStageConfig{Name: "notify"},
+		},
+		"missing artifact": {
+			stage:   config.PipelineStageConfig{Name: "outline", Artifact: "outline.yaml"},
+			wantErr: "stage outline did not write outline.yaml",
+		},
+		"existing artifact": {
+			stage:   config.PipelineStageConfig{Name: "outline", Artifact: "outline.md"},
+			content: "# Outline\n",
+		},
+		"none skips missing artifact": {
+			stage: config.PipelineStageConfig{Name: "outline", Artifact: "outline.yaml", Validator: "none"},
+		},
+		"yaml syntax error": {
+			stage:   config.PipelineStageConfig{Name: "outline", Artifact: "outline.yaml", Validator: "yaml"},
+			content: "sections: [a, b\n",
+			wantErr: "outline.yaml is not valid YAML",
+		},
+		"schema valid": {
+			stage:   config.PipelineStageConfig{Name: "research", Artifact: "notes.yaml", Validator: "research"},
+			content: string(validResearch),
+		},
+		"schema invalid": {
+			stage:   config.PipelineStageConfig{Name: "research", Artifact: "notes.yaml", Validator: "research"},
+			content: "research:\n  branch: x\n",
+			wantErr: "schema validation failed for notes.yaml",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, tt.stage.Artifact), []byte(tt.content), 0o644))
+			}
+			err := PipelineStageValidator(tt.stage)(dir)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestExecutePipeline(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	specDir := filepath.Join("specs", "003-auth")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+
+	retries := 0
+	cfg := &config.Configuration{
+		SpecsDir:   "specs",
+		StateDir:   filepath.Join(dir, "state"),
+		MaxRetries: 2,
+		Pipelines: map[string]config.PipelineConfig{
+			"docs": {Stages: []config.PipelineStageConfig{
+				{Name: "outline", Command: "/docs.outline {{.Spec}}", Artifact: "outline.yaml", Validator: "yaml"},
+				{Name: "write", Command: "/docs.write {{.Prompt}}", Artifact: "docs.md", Retries: &retries},
+			}},
+		},
+	}
+	orch := NewWorkflowOrchestrator(cfg)
+	claude := &writingClaude{files: map[string]string{filepath.Join(specDir, "outline.yaml"): "sections: [intro]\n"}}
+	orch.Executor.Claude = claude
+
+	_, err := orch.LookupPipeline("release")
+	assert.EqualError(t, err, `unknown pipeline "release" (available: docs)`)
+
+	err = orch.ExecutePipeline("docs", "003-auth", "for API users")
+	require.Error(t, err, "write never produces docs.md")
+	assert.Contains(t, err.Error(), "pipeline docs: stage write failed")
+	require.Len(t, claude.executeCalls, 2, "outline succeeds first time; write has no retries")
+	assert.Contains(t, claude.executeCalls[0], "/docs.outline 003-auth")
+	assert.Contains(t, claude.executeCalls[1], "/docs.write for API users")
+	assert.Equal(t, 2, orch.Executor.MaxRetries, "stage retries override is restored")
+}
@@ -2,12 +2,18 @@ package workflow
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/config"
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/preflight"
 )
 
 // PreflightChecker is an interface for running preflight checks with testable injection.
@@ -25,11 +31,14 @@ type PreflightChecker interface {
 
 // DefaultPreflightChecker is the default implementation of PreflightChecker
 // that uses the system's actual preflight checks and stdin for user prompts.
-type DefaultPreflightChecker struct{}
+type DefaultPreflightChecker struct {
+	// Config selects the agent, directories, endpoints and checks (nil = defaults).
+	Config *config.Configuration
+}
 
-// RunChecks implements PreflightChecker.RunChecks using the actual RunPreflightChecks function.
+// RunChecks implements PreflightChecker.RunChecks using the actual RunPreflightChecksWithConfig function.
 func (d *DefaultPreflightChecker) RunChecks() (*PreflightResult, error) {
-	return RunPreflightChecks()
+	return RunPreflightChecksWithConfig(d.Config)
 }
 
 // PromptUser implements PreflightChecker.PromptUser using the actual PromptUserToContinue function.
@@ -62,45 +71,94 @@ type PreflightResult struct {
 	InvalidArtifacts     map[string]string // Map of artifact name to validation error message
 	Warnings             []string          // Warning messages for user
 	RequiresConfirmation bool              // Whether user confirmation is needed
+	Report               *preflight.Report // Per-check results (nil for artifact checks)
 }
 
-// RunPreflightChecks runs all pre-flight validation checks
-// Performance contract: <100ms
+// RunPreflightChecks runs the registered preflight checks with the default
+// configuration.
 func RunPreflightChecks() (*PreflightResult, error) {
+	return RunPreflightChecksWithConfig(nil)
+}
+
+// RunPreflightChecksWithConfig runs the registered preflight checks (see
+// internal/preflight) for cfg. Failed error checks fail the result, a failed
+// project check asks to continue unless an error check failed too, and failed
+// warn checks only add Warnings.
+func RunPreflightChecksWithConfig(cfg *config.Configuration) (*PreflightResult, error) {
+	env, err := PreflightEnv(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var checks preflight.Config
+	if cfg != nil {
+		checks = cfg.Preflight
+	}
+	return newPreflightResult(preflight.Run(env, checks)), nil
+}
+
+// PreflightEnv returns what the preflight checks know about a run with cfg
+// (nil = the default claude agent and no directories or endpoints).
+func PreflightEnv(cfg *config.Configuration) (preflight.Env, error) {
+	if cfg == nil {
+		return preflight.Env{Agent: cliagent.Get("claude")}, nil
+	}
+	agent, err := cfg.GetAgent()
+	if err != nil {
+		return preflight.Env{}, err
+	}
+	env := preflight.Env{
+		Agent:     agent,
+		WriteDirs: []string{cfg.StateDir, cfg.SpecsDir},
+		StateDir:  cfg.StateDir,
+		Storage:   cfg.Storage,
+	}
+	for _, rawURL := range cfg.Notifications.RemoteURLs() {
+		if endpoint := preflight.Endpoint(rawURL); endpoint != "" {
+			env.Endpoints = append(env.Endpoints, endpoint)
+		}
+	}
+	return env, nil
+}
+
+// newPreflightResult converts report into the result the orchestrator acts on.
+func newPreflightResult(report *preflight.Report) *PreflightResult {
 	result := &PreflightResult{
-		Passed:       true,
+		Passed:       report.Passed(),
 		FailedChecks: make([]string, 0),
 		MissingDirs:  make([]string, 0),
+		Warnings:     make([]string, 0),
+		Report:       report,
 	}
-
-	// Check 1: Verify claude CLI is in PATH
-	if err := checkCommandExists("claude"); err != nil {
-		result.Passed = false
-		result.FailedChecks = append(result.FailedChecks, "claude CLI not found in PATH")
+	for _, res := range report.Failed(preflight.LevelError) {
+		result.FailedChecks = append(result.FailedChecks, fmt.Sprintf("%s: %s", res.Name, res.Message))
 	}
-
-	// Check 2: Verify .claude/commands/ directory exists
-	if _, err := os.Stat(".claude/commands"); os.IsNotExist(err) {
-		result.MissingDirs = append(result.MissingDirs, ".claude/commands/")
+	for _, res := range report.Failed(preflight.LevelWarn) {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%s: %s", res.Name, res.Message))
 	}
 
-	// Check 3: Verify .autospec/ directory exists
-	if _, err := os.Stat(".autospec"); os.IsNotExist(err) {
-		result.MissingDirs = append(result.MissingDirs, ".autospec/")
+	var confirm []string
+	for _, res := range report.Failed(preflight.LevelConfirm) {
+		var missing *preflight.MissingDirsError
+		if errors.As(res.Err, &missing) {
+			result.MissingDirs = append(result.MissingDirs, missing.Dirs...)
+		} else {
+			confirm = append(confirm, fmt.Sprintf("WARNING: %s: %s\n", res.Name, res.Message))
+		}
+	}
+	if len(result.MissingDirs) == 0 && len(confirm) == 0 {
+		return result
 	}
-
-	// Get git root for helpful error messages
 	if root, err := getGitRoot(); err == nil {
 		result.GitRoot = root
 	}
-
-	// If directories are missing, generate warning
-	if len(result.MissingDirs) > 0 {
-		result.Passed = false
-		result.WarningMessage = generateMissingDirsWarning(result.MissingDirs, result.GitRoot)
+	// A failed error check stops the run whatever the answer would be
+	if len(result.FailedChecks) == 0 {
+		if len(result.MissingDirs) > 0 {
+			result.WarningMessage = generateMissingDirsWarning(result.MissingDirs, result.GitRoot)
+		}
+		result.WarningMessage += strings.Join(confirm, "")
 	}
-
-	return result, nil
+	return result
 }
 
 // checkCommandExists verifies that a command is available in PATH
@@ -184,14 +242,19 @@ func ShouldRunPreflightChecks(skipPreflight bool) bool {
 	}
 
 	// Check if running in CI/CD environment
+	return !isCI()
+}
+
+// isCI reports whether autospec runs in a CI/CD environment, where nobody
+// answers prompts.
+func isCI() bool {
 	ciEnvVars := []string{"CI", "CONTINUOUS_INTEGRATION", "GITHUB_ACTIONS", "GITLAB_CI", "CIRCLECI"}
 	for _, envVar := range ciEnvVars {
 		if os.Getenv(envVar) != "" {
-			return false
+			return true
 		}
 	}
-
-	return true
+	return false
 }
 
 // CheckDependencies checks if all required dependencies are installed
@@ -234,7 +297,7 @@ func CheckProjectStructure() error {
 // CheckSpecDirectory verifies a spec directory exists and has expected structure
 func CheckSpecDirectory(specDir string) error {
 	if _, err := os.Stat(specDir); os.IsNotExist(err) {
-		return fmt.Errorf("spec directory not found: %s", specDir)
+		return apperrors.ErrArtifactMissing.Errorf("spec directory not found: %s", specDir)
 	}
 
 	// Check if it's actually a directory
@@ -487,7 +550,12 @@ func generateConstitutionMissingError() string {
 	sb.WriteString("A constitution is required before running any workflow stages.\n")
 	sb.WriteString("The constitution defines your project's principles and guidelines.\n\n")
 	sb.WriteString("To create a constitution, run:\n")
-	sb.WriteString("  autospec constitution\n\n")
+	if pkg := os.Getenv(config.PackageEnvVar); pkg != "" {
+		// Workspace packages each have their own constitution
+		fmt.Fprintf(&sb, "  autospec constitution --package %s\n\n", pkg)
+	} else {
+		sb.WriteString("  autospec constitution\n\n")
+	}
 	sb.WriteString("Or if you have an existing constitution at .specify/memory/constitution.yaml,\n")
 	sb.WriteString("run 'autospec init' to copy it to .autospec/memory/constitution.yaml\n")
 
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ariel-frischer/autospec/internal/preflight"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -1573,3 +1574,55 @@ func TestDefaultPreflightChecker(t *testing.T) {
 		_ = err
 	})
 }
+
+func TestNewPreflightResult(t *testing.T) {
+	t.Parallel()
+
+	missingDirs := preflight.Result{Name: "project", Level: preflight.LevelConfirm,
+		Err: &preflight.MissingDirsError{Dirs: []string{".autospec/"}}, Message: "missing .autospec/"}
+	agentMissing := preflight.Result{Name: "agent", Level: preflight.LevelError, Message: "claude not found"}
+	dirty := preflight.Result{Name: "git-clean", Level: preflight.LevelWarn, Message: "2 uncommitted change(s)"}
+	ok := preflight.Result{Name: "permissions", Level: preflight.LevelError, Passed: true}
+
+	tests := map[string]struct {
+		results      []preflight.Result
+		wantPassed   bool
+		wantFailed   []string
+		wantMissing  []string
+		wantWarnings []string
+		wantPrompt   bool
+	}{
+		"all passed": {
+			results:    []preflight.Result{ok},
+			wantPassed: true,
+		},
+		"warn check fails": {
+			results:      []preflight.Result{ok, dirty},
+			wantPassed:   true,
+			wantWarnings: []string{"git-clean: 2 uncommitted change(s)"},
+		},
+		"missing dirs ask to continue": {
+			results:     []preflight.Result{ok, missingDirs},
+			wantMissing: []string{".autospec/"},
+			wantPrompt:  true,
+		},
+		"error check fails without prompt": {
+			results:     []preflight.Result{agentMissing, missingDirs},
+			wantFailed:  []string{"agent: claude not found"},
+			wantMissing: []string{".autospec/"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			result := newPreflightResult(&preflight.Report{Results: tc.results})
+
+			assert.Equal(t, tc.wantPassed, result.Passed)
+			assert.ElementsMatch(t, tc.wantFailed, result.FailedChecks)
+			assert.ElementsMatch(t, tc.wantMissing, result.MissingDirs)
+			assert.ElementsMatch(t, tc.wantWarnings, result.Warnings)
+			assert.Equal(t, tc.wantPrompt, result.WarningMessage != "")
+		})
+	}
+}
@@ -0,0 +1,119 @@
+// Package workflow provides per-command prompt template customization.
+// Teams can place <command>.md files in .autospec/prompts/ to control the prompt
+// text passed to /autospec.plan, /autospec.tasks and /autospec.implement, e.g. to
+// inject org-specific instructions into every run.
+// Related: internal/workflow/stage_executor.go, internal/workflow/phase_executor.go, internal/workflow/task_executor.go
+// Tags: workflow, prompts, templates, customization
+package workflow
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// PromptsDirName is the directory under .autospec/ holding prompt templates.
+const PromptsDirName = "prompts"
+
+// Prompt template names, one per customizable command.
+const (
+	PromptPlan      = "plan"
+	PromptTasks     = "tasks"
+	PromptImplement = "implement"
+)
+
+// PromptTemplateData holds the placeholder values available to prompt templates.
+//
+//	{{.SpecName}}  spec directory name (e.g., "003-command-timeout")
+//	{{.TaskID}}    task ID in task mode (e.g., "T004"), empty otherwise
+//	{{.Phase}}     phase number in phase mode, 0 otherwise
+//	{{.Prompt}}    the prompt given on the command line, may be empty
+type PromptTemplateData struct {
+	SpecName string
+	TaskID   string
+	Phase    int
+	Prompt   string
+}
+
+// PromptTemplates renders prompt text from template files in Dir.
+// A nil *PromptTemplates, or a missing template file, leaves the user prompt
+// unchanged. Templates are read on every render so edits apply to the next session.
+type PromptTemplates struct {
+	Dir string
+	// Inline holds templates by name that replace the files in Dir, e.g. the
+	// prompts of a spec's .autospec.yaml.
+	Inline map[string]string
+}
+
+// DefaultPromptsDir returns the prompt template directory for the project in the current directory.
+func DefaultPromptsDir() string {
+	return filepath.Join(".autospec", PromptsDirName)
+}
+
+// Path returns the template file path for the named command.
+func (p *PromptTemplates) Path(name string) string {
+	return filepath.Join(p.Dir, name+".md")
+}
+
+// Render returns the prompt for the named command. When no template exists,
+// data.Prompt is returned as-is. Surrounding whitespace is trimmed so templates
+// can end with a newline without changing the command.
+func (p *PromptTemplates) Render(name string, data PromptTemplateData) (string, error) {
+	if p == nil {
+		return data.Prompt, nil
+	}
+	path, content := "", ""
+	if text, ok := p.Inline[name]; ok {
+		path, content = "spec config prompts."+name, text
+	} else {
+		if p.Dir == "" {
+			return data.Prompt, nil
+		}
+		path = p.Path(name)
+		raw, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			return data.Prompt, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("reading prompt template %s: %w", path, err)
+		}
+		content = string(raw)
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("parsing prompt template %s: %w", path, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering prompt template %s: %w", path, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// renderPrompt applies the executor's prompt template for name, if any.
+func (e *Executor) renderPrompt(name string, data PromptTemplateData) (string, error) {
+	if e == nil {
+		return data.Prompt, nil
+	}
+	return e.PromptTemplates.Render(name, data)
+}
+
+// quotePrompt double-quotes a prompt for a slash command line, escaping the
+// characters a shell treats specially inside double quotes, so that a prompt
+// or rendered template containing quotes stays a single argument.
+func quotePrompt(prompt string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range prompt {
+		if strings.ContainsRune("\\\"$`", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
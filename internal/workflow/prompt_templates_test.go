@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptTemplates_Render(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		template  string // empty means no template file
+		data      PromptTemplateData
+		want      string
+		wantErrIn string
+	}{
+		"no template returns prompt unchanged": {
+			data: PromptTemplateData{SpecName: "001-auth", Prompt: "focus on security"},
+			want: "focus on security",
+		},
+		"placeholders are substituted": {
+			template: "Follow ACME coding standards for {{.SpecName}}.\n{{if .Prompt}}User notes: {{.Prompt}}{{end}}\n",
+			data:     PromptTemplateData{SpecName: "001-auth", Prompt: "focus on security"},
+			want:     "Follow ACME coding standards for 001-auth.\nUser notes: focus on security",
+		},
+		"task and phase placeholders": {
+			template: "Task {{.TaskID}} phase {{.Phase}}",
+			data:     PromptTemplateData{TaskID: "T004", Phase: 2},
+			want:     "Task T004 phase 2",
+		},
+		"empty prompt omitted by conditional": {
+			template: "Use ACME standards.{{if .Prompt}} {{.Prompt}}{{end}}",
+			data:     PromptTemplateData{SpecName: "001-auth"},
+			want:     "Use ACME standards.",
+		},
+		"syntax error": {
+			template:  "{{.SpecName",
+			wantErrIn: "parsing prompt template",
+		},
+		"unknown placeholder": {
+			template:  "{{.Branch}}",
+			wantErrIn: "rendering prompt template",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			if tt.template != "" {
+				require.NoError(t, os.WriteFile(filepath.Join(dir, "plan.md"), []byte(tt.template), 0o644))
+			}
+
+			got, err := (&PromptTemplates{Dir: dir}).Render(PromptPlan, tt.data)
+			if tt.wantErrIn != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrIn)
+				assert.Contains(t, err.Error(), "plan.md")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPromptTemplates_Inline(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "plan.md"), []byte("project plan"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tasks.md"), []byte("project tasks"), 0o644))
+	p := &PromptTemplates{Dir: dir, Inline: map[string]string{"plan": "spec plan for {{.SpecName}}"}}
+
+	got, err := p.Render(PromptPlan, PromptTemplateData{SpecName: "012-migration"})
+	require.NoError(t, err)
+	assert.Equal(t, "spec plan for 012-migration", got, "inline template replaces the file")
+
+	got, err = p.Render(PromptTasks, PromptTemplateData{})
+	require.NoError(t, err)
+	assert.Equal(t, "project tasks", got, "other commands keep their file")
+
+	_, err = (&PromptTemplates{Inline: map[string]string{"plan": "{{.Branch}}"}}).Render(PromptPlan, PromptTemplateData{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "spec config prompts.plan")
+}
+
+func TestPromptTemplates_NilIsNoop(t *testing.T) {
+	t.Parallel()
+
+	var p *PromptTemplates
+	got, err := p.Render(PromptImplement, PromptTemplateData{Prompt: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", got)
+
+	var e *Executor
+	got, err = e.renderPrompt(PromptImplement, PromptTemplateData{Prompt: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello", got)
+}
+
+func TestExecuteTasks_UsesPromptTemplate(t *testing.T) {
+	t.Parallel()
+
+	promptsDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(promptsDir, "tasks.md"),
+		[]byte("Keep tasks under 2 hours for {{.SpecName}}. {{.Prompt}}\n"), 0o644))
+
+	claude := &mockClaudeExecutor{}
+	executor := &Executor{
+		Claude:          claude,
+		StateDir:        t.TempDir(),
+		SpecsDir:        t.TempDir(),
+		MaxRetries:      1,
+		PromptTemplates: &PromptTemplates{Dir: promptsDir},
+	}
+	se := NewStageExecutor(executor, executor.SpecsDir, false)
+
+	// Validation fails (no tasks.yaml); only the built command matters here
+	_ = se.ExecuteTasks("001-auth", "prefer small PRs")
+
+	require.NotEmpty(t, claude.executeCalls)
+	assert.Equal(t, `/autospec.tasks "Keep tasks under 2 hours for 001-auth. prefer small PRs"`, claude.executeCalls[0])
+}
+
+func TestQuotePrompt(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"prefer small PRs": `"prefer small PRs"`,
+		`say "hi"`:         `"say \"hi\""`,
+		"$(rm -rf /)":      `"\$(rm -rf /)"`,
+		"`id` and $HOME":   "\"\\`id\\` and \\$HOME\"",
+		`C:\path`:          `"C:\\path"`,
+		"":                 `""`,
+	}
+	for prompt, want := range tests {
+		assert.Equal(t, want, quotePrompt(prompt), prompt)
+	}
+}
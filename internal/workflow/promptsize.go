@@ -0,0 +1,42 @@
+package workflow
+
+import (
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/learnings"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/promptsize"
+)
+
+// fitLearnings returns the learnings that fit in the prompt for stage. When
+// command and items together exceed the prompt_size limit, the drop_learnings
+// strategy (if configured) drops the lowest-ranked ones and the trim is shown.
+func (e *Executor) fitLearnings(command string, stage Stage, items []learnings.Learning) []learnings.Learning {
+	lines := make([]string, len(items))
+	for i, l := range items {
+		lines[i] = learnings.FormatForPrompt([]learnings.Learning{l})
+	}
+	r := promptsize.Fit([]promptsize.Part{
+		{Name: "prompt", Kind: promptsize.Fixed, Text: command + learningsIntro},
+		{Name: "learnings", Kind: promptsize.Learnings, Items: lines},
+	}, e.PromptSize)
+	if len(r.Trimmed) > 0 {
+		output.Default().Warn("trimmed %s prompt to ~%d tokens: %s", stage, r.Tokens, strings.Join(r.Trimmed, ", "))
+	}
+	return items[:len(r.Parts[1].Items)]
+}
+
+// checkPromptSize warns when the estimated size of the prompt sent for stage
+// is above prompt_size.warn_percent of the context window.
+func (e *Executor) checkPromptSize(stage Stage, prompt string) {
+	limit := e.PromptSize.Limit()
+	if limit == 0 {
+		return
+	}
+	tokens := promptsize.EstimateTokens(prompt)
+	e.debugLog("Prompt size for %s: ~%d tokens (limit %d)", stage, tokens, limit)
+	if tokens > limit {
+		output.Default().Warn("%s prompt is ~%d tokens, over %d%% of the %d-token context window (prompt_size.warn_percent)",
+			stage, tokens, e.PromptSize.WarnPercent, e.PromptSize.ContextWindow)
+	}
+}
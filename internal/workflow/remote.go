@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+)
+
+// remoteFor returns the remote config of the agent that runs stage, if that
+// stage runs on a remote host. Safe to call on a nil executor.
+func (e *Executor) remoteFor(stage Stage) (cliagent.RemoteConfig, bool) {
+	if e == nil || len(e.Remote) == 0 {
+		return cliagent.RemoteConfig{}, false
+	}
+	claude, ok := e.Claude.(*ClaudeExecutor)
+	if !ok || claude.Agent == nil {
+		return cliagent.RemoteConfig{}, false
+	}
+	remote, ok := e.Remote[claude.Agent.Name()]
+	if !ok || !remote.RunsStage(string(stage)) {
+		return cliagent.RemoteConfig{}, false
+	}
+	return remote, true
+}
+
+// applyRemote makes the stage's agent sessions run on the agent's remote
+// host until the returned function is called. The spec directory (all of
+// specs_dir while specify has no spec yet) and, for implement, the phase
+// context files are copied there before each session and back after it.
+// Call it after applyWorkDir: a workdir maps to the same directory in the
+// remote checkout, and AUTOSPEC_SPECS_DIR to the remote specs directory.
+func (e *Executor) applyRemote(specName string, stage Stage) (restore func(), err error) {
+	remote, ok := e.remoteFor(stage)
+	if !ok {
+		return func() {}, nil
+	}
+	claude := e.Claude.(*ClaudeExecutor)
+
+	specsRel, err := projectRelative(e.SpecsDir)
+	if err != nil {
+		return func() {}, fmt.Errorf("running %s on %s: specs_dir %w", stage, remote.Host, err)
+	}
+	sync := []string{filepath.Join(specsRel, specName)}
+	if stage == StageImplement {
+		sync = append(sync, filepath.Join(".autospec", "context"))
+	}
+
+	env := claude.Env
+	if localSpecs, err := filepath.Abs(e.SpecsDir); err == nil && env["AUTOSPEC_SPECS_DIR"] == localSpecs {
+		env = make(map[string]string, len(claude.Env))
+		for name, value := range claude.Env {
+			env[name] = value
+		}
+		env["AUTOSPEC_SPECS_DIR"] = path.Join(remote.Dir, filepath.ToSlash(specsRel))
+	}
+
+	previousAgent, previousEnv := claude.Agent, claude.Env
+	claude.Agent, claude.Env = cliagent.NewRemoteAgent(previousAgent, remote, sync), env
+	e.debugLog("Running %s agent on %s in %s", stage, remote.Host, remote.Dir)
+	return func() { claude.Agent, claude.Env = previousAgent, previousEnv }, nil
+}
+
+// remoteContextFile returns the path of a phase context file in the remote
+// checkout, for agents that run there in a workdir.
+func (e *Executor) remoteContextFile(contextFilePath string) (string, bool) {
+	remote, ok := e.remoteFor(StageImplement)
+	if !ok {
+		return "", false
+	}
+	rel, err := projectRelative(contextFilePath)
+	if err != nil {
+		return "", false
+	}
+	return path.Join(remote.Dir, filepath.ToSlash(rel)), true
+}
+
+// projectRelative returns p relative to the project root (the current
+// directory). Paths outside the project have no counterpart in a remote
+// checkout.
+func projectRelative(p string) (string, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(cwd, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the project directory", p)
+	}
+	return rel, nil
+}
@@ -0,0 +1,70 @@
+package workflow
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_ApplyRemote(t *testing.T) {
+	t.Parallel()
+
+	agent, err := cliagent.NewCustomAgentFromConfig(cliagent.CustomAgentConfig{Command: "myagent", Args: []string{"{{PROMPT}}"}})
+	require.NoError(t, err)
+	localSpecs, err := filepath.Abs("specs")
+	require.NoError(t, err)
+	env := map[string]string{"AUTOSPEC_SPECS_DIR": localSpecs}
+	claude := &ClaudeExecutor{Agent: agent, Env: env}
+	e := &Executor{
+		Claude:   claude,
+		SpecsDir: "specs",
+		Remote:   map[string]cliagent.RemoteConfig{agent.Name(): {Host: "devbox", Dir: "/srv/app"}},
+	}
+
+	restore, err := e.applyRemote("001-x", StagePlan)
+	require.NoError(t, err)
+	planRemote, ok := claude.Agent.(*cliagent.RemoteAgent)
+	require.True(t, ok, "every stage should run remotely by default, got %T", claude.Agent)
+	assert.Equal(t, []string{filepath.Join("specs", "001-x")}, planRemote.Sync)
+	restore()
+
+	e.Remote = map[string]cliagent.RemoteConfig{agent.Name(): {Host: "devbox", Dir: "/srv/app", Stages: []string{"implement"}}}
+	restore, err = e.applyRemote("001-x", StagePlan)
+	require.NoError(t, err)
+	assert.Same(t, agent, claude.Agent, "plan should stay local when not listed")
+	restore()
+
+	restore, err = e.applyRemote("001-x", StageImplement)
+	require.NoError(t, err)
+	remote, ok := claude.Agent.(*cliagent.RemoteAgent)
+	require.True(t, ok, "implement should run remotely, got %T", claude.Agent)
+	assert.Equal(t, []string{filepath.Join("specs", "001-x"), filepath.Join(".autospec", "context")}, remote.Sync)
+	assert.Equal(t, "/srv/app/specs", claude.Env["AUTOSPEC_SPECS_DIR"])
+
+	contextFile, ok := e.remoteContextFile(filepath.Join(".autospec", "context", "phase-1.yaml"))
+	assert.True(t, ok)
+	assert.Equal(t, "/srv/app/.autospec/context/phase-1.yaml", contextFile)
+
+	restore()
+	assert.Same(t, agent, claude.Agent, "agent should be restored")
+	assert.Equal(t, localSpecs, claude.Env["AUTOSPEC_SPECS_DIR"], "env should be restored")
+}
+
+func TestExecutor_ApplyRemote_SpecsOutsideProject(t *testing.T) {
+	t.Parallel()
+
+	agent, err := cliagent.NewCustomAgentFromConfig(cliagent.CustomAgentConfig{Command: "myagent", Args: []string{"{{PROMPT}}"}})
+	require.NoError(t, err)
+	e := &Executor{
+		Claude:   &ClaudeExecutor{Agent: agent},
+		SpecsDir: t.TempDir(),
+		Remote:   map[string]cliagent.RemoteConfig{agent.Name(): {Host: "devbox", Dir: "/srv/app"}},
+	}
+
+	_, err = e.applyRemote("001-x", StageImplement)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "outside the project directory")
+}
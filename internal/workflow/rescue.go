@@ -0,0 +1,275 @@
+package workflow
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/editor"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/progress"
+	"golang.org/x/term"
+)
+
+// RescueAction is what to do with a stage that used up its retries.
+type RescueAction int
+
+const (
+	// RescueAbort fails the stage, as when no rescue prompt is shown.
+	RescueAbort RescueAction = iota
+	// RescueRetry runs one more attempt with instructions typed by the user.
+	RescueRetry
+	// RescueRevalidate validates the artifact again after the user edited it.
+	RescueRevalidate
+	// RescueSkipTask blocks the current task and goes on with the next one.
+	RescueSkipTask
+)
+
+// RescueRequest describes a stage that used up its retries.
+type RescueRequest struct {
+	Stage    Stage
+	TaskID   string   // Task the stage's sessions work on; skipping is offered only then
+	Artifact string   // File offered for editing ("" when the stage has none)
+	Errors   []string // Validation errors of the last attempt
+}
+
+// RescueChoice is the user's answer to a rescue prompt.
+type RescueChoice struct {
+	Action       RescueAction
+	Instructions string // Extra instructions for the agent, with RescueRetry
+}
+
+// Rescuer asks how to go on when a stage exhausts its retries, instead of
+// failing the whole run.
+type Rescuer interface {
+	Rescue(req RescueRequest) (RescueChoice, error)
+}
+
+// errTaskSkipped marks a task the user skipped at the rescue prompt; it was
+// blocked and the task loop moves on to the next task.
+var errTaskSkipped = errors.New("task skipped after its retries were exhausted")
+
+// TerminalRescuer is the Rescuer of an interactive terminal. Choosing to
+// edit opens the artifact in the user's editor before RescueRevalidate is
+// returned.
+type TerminalRescuer struct {
+	Out    io.Writer
+	Editor func(path string) error // Opens path for editing (nil: editor.Edit)
+
+	in *bufio.Reader
+}
+
+// NewTerminalRescuer creates a TerminalRescuer reading answers from in.
+func NewTerminalRescuer(in io.Reader, out io.Writer) *TerminalRescuer {
+	return &TerminalRescuer{Out: out, in: bufio.NewReader(in)}
+}
+
+// DefaultRescuer returns a TerminalRescuer on stdin and stdout when both are
+// terminals, outside CI, and confirmations are not skipped. Otherwise it
+// returns nil, and exhausted stages fail straight away.
+func DefaultRescuer(skipConfirmations bool) Rescuer {
+	if skipConfirmations || isCI() {
+		return nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) || !term.IsTerminal(int(os.Stdout.Fd())) {
+		return nil
+	}
+	return NewTerminalRescuer(os.Stdin, os.Stdout)
+}
+
+// Rescue shows the last validation errors and the choices, and reads one.
+// End of input aborts.
+func (r *TerminalRescuer) Rescue(req RescueRequest) (RescueChoice, error) {
+	fmt.Fprintf(r.Out, "\n✗ %s still fails validation after its last retry:\n", req.Stage)
+	shown := req.Errors
+	if len(shown) > maxRetryErrors {
+		shown = shown[:maxRetryErrors]
+	}
+	for _, e := range shown {
+		fmt.Fprintf(r.Out, "  - %s\n", e)
+	}
+	if more := len(req.Errors) - len(shown); more > 0 {
+		fmt.Fprintf(r.Out, "  ... and %d more\n", more)
+	}
+
+	for {
+		keys := r.printChoices(req)
+		fmt.Fprintf(r.Out, "Choice [%s] (default: a): ", strings.Join(keys, "/"))
+		answer, err := r.readLine()
+		if err != nil {
+			return RescueChoice{Action: RescueAbort}, nil
+		}
+
+		switch strings.ToLower(answer) {
+		case "r", "retry":
+			return r.readInstructions()
+		case "e", "edit":
+			if req.Artifact == "" {
+				break
+			}
+			if err := r.edit(req.Artifact); err != nil {
+				fmt.Fprintf(r.Out, "Could not open the editor: %v\n", err)
+				continue
+			}
+			return RescueChoice{Action: RescueRevalidate}, nil
+		case "s", "skip":
+			if req.TaskID == "" {
+				break
+			}
+			return RescueChoice{Action: RescueSkipTask}, nil
+		case "", "a", "abort":
+			return RescueChoice{Action: RescueAbort}, nil
+		}
+		fmt.Fprintf(r.Out, "Unknown choice %q\n", answer)
+	}
+}
+
+// printChoices lists the choices that apply to req and returns their keys.
+func (r *TerminalRescuer) printChoices(req RescueRequest) []string {
+	fmt.Fprintln(r.Out, "\nHow would you like to proceed?")
+	fmt.Fprintln(r.Out, "  [r] Retry - run one more attempt with instructions you type now")
+	keys := []string{"r"}
+	if req.Artifact != "" {
+		fmt.Fprintf(r.Out, "  [e] Edit - open %s in your editor, then validate it again\n", req.Artifact)
+		keys = append(keys, "e")
+	}
+	if req.TaskID != "" {
+		fmt.Fprintf(r.Out, "  [s] Skip - mark task %s Blocked and continue with the next task\n", req.TaskID)
+		keys = append(keys, "s")
+	}
+	fmt.Fprintln(r.Out, "  [a] Abort - stop the run (retry state is kept)")
+	return append(keys, "a")
+}
+
+// readInstructions reads instructions for the retry until an empty line.
+func (r *TerminalRescuer) readInstructions() (RescueChoice, error) {
+	fmt.Fprintln(r.Out, "Instructions for the agent (end with an empty line):")
+	var lines []string
+	for {
+		line, err := r.readLine()
+		if err != nil || line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+	return RescueChoice{Action: RescueRetry, Instructions: strings.Join(lines, "\n")}, nil
+}
+
+// readLine reads one line of input without its line ending.
+func (r *TerminalRescuer) readLine() (string, error) {
+	line, err := r.in.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func (r *TerminalRescuer) edit(path string) error {
+	if r.Editor != nil {
+		return r.Editor(path)
+	}
+	return editor.Edit(path)
+}
+
+// rescueArtifactPath returns the file the user may edit to rescue the stage:
+// the artifact its validation checks, or tasks.yaml for implement, whose
+// validation reads task statuses. Returns "" when there is none.
+func (e *Executor) rescueArtifactPath(ctx *stageExecutionContext) string {
+	if ctx.stage == StageImplement {
+		path := filepath.Join(e.stageSpecDir(ctx), "tasks.yaml")
+		if _, err := os.Stat(path); err != nil {
+			return ""
+		}
+		return path
+	}
+	artType, ok := retryArtifacts[ctx.stage]
+	if !ok {
+		return ""
+	}
+	return e.retryArtifactPath(ctx, artType)
+}
+
+// rescueExhaustedStage asks the Rescuer how to go on with a stage whose
+// retries are used up. rescued is false when there is no Rescuer or the user
+// aborts, and the stage then fails as usual. Otherwise done and err are the
+// stage loop's: done false reruns the stage with the user's instructions.
+func (e *Executor) rescueExhaustedStage(ctx *stageExecutionContext, stageInfo progress.StageInfo, validationErr error) (rescued, done bool, err error) {
+	if e.Rescue == nil {
+		return false, false, nil
+	}
+	if e.Progress != nil {
+		e.Progress.StopSpinner()
+	}
+	defer e.suspendPauseKeys()()
+	e.Status.Pause()
+	defer e.Status.Resume()
+
+	req := RescueRequest{
+		Stage:    ctx.stage,
+		TaskID:   e.scope.taskID,
+		Artifact: e.rescueArtifactPath(ctx),
+		Errors:   ctx.lastValidationErrors,
+	}
+	if len(req.Errors) == 0 {
+		req.Errors = []string{validationErr.Error()}
+	}
+
+	for {
+		choice, err := e.Rescue.Rescue(req)
+		if err != nil {
+			output.Default().Warn("rescue prompt: %v", err)
+			return false, false, nil
+		}
+
+		switch choice.Action {
+		case RescueRetry:
+			retryContext := FormatRetryContext(ctx.retryState.Count, e.MaxRetries, ctx.lastValidationErrors)
+			if choice.Instructions != "" {
+				retryContext += "\n\nAdditional instructions from the user:\n" + choice.Instructions
+			}
+			ctx.currentCommand = BuildRetryCommand(ctx.command, retryContext, "")
+			e.recordStageEvent(ctx, events.TypeRetry, "rescue retry with instructions from the user", nil)
+			fmt.Printf("\n⟳ Rescue retry - %s\n", rescueRetryDescription(choice.Instructions))
+			return true, false, nil
+
+		case RescueRevalidate:
+			if err := ctx.validateFunc(e.stageSpecDir(ctx)); err != nil {
+				ctx.result.ValidationErrors = ExtractValidationErrors(err)
+				ctx.lastValidationErrors = ctx.result.ValidationErrors
+				req.Errors = ctx.lastValidationErrors
+				if len(req.Errors) == 0 {
+					req.Errors = []string{err.Error()}
+				}
+				e.recordStageEvent(ctx, events.TypeValidationFailed, "after editing by hand", ctx.lastValidationErrors)
+				continue
+			}
+			e.recordStageEvent(ctx, events.TypeValidationPassed, "after editing by hand", nil)
+			e.recordStageEvent(ctx, events.TypeStageComplete, "", nil)
+			e.completeStageSuccessNoNotify(ctx.result, stageInfo, ctx.specName, ctx.stage)
+			output.Default().Success("%s passes validation", req.Artifact)
+			return true, true, nil
+
+		case RescueSkipTask:
+			ctx.result.Error = errTaskSkipped
+			e.failStageProgress(stageInfo, errTaskSkipped)
+			e.recordStageEvent(ctx, events.TypeRetriesExhausted, fmt.Sprintf("task %s skipped by the user", req.TaskID), nil)
+			return true, true, errTaskSkipped
+
+		default:
+			return false, false, nil
+		}
+	}
+}
+
+// rescueRetryDescription describes a rescue retry for its progress line.
+func rescueRetryDescription(instructions string) string {
+	if instructions == "" {
+		return "injecting validation errors into command"
+	}
+	return "injecting validation errors and your instructions into command"
+}
@@ -0,0 +1,217 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRescuer answers rescue prompts from a fixed list of choices and
+// records the requests it was shown.
+type stubRescuer struct {
+	choices  []RescueChoice
+	requests []RescueRequest
+}
+
+func (s *stubRescuer) Rescue(req RescueRequest) (RescueChoice, error) {
+	s.requests = append(s.requests, req)
+	if len(s.choices) == 0 {
+		return RescueChoice{Action: RescueAbort}, nil
+	}
+	choice := s.choices[0]
+	s.choices = s.choices[1:]
+	return choice, nil
+}
+
+func TestTerminalRescuer(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input      string
+		req        RescueRequest
+		want       RescueChoice
+		wantEdited bool
+		wantOut    string
+	}{
+		"retry with instructions": {
+			input: "r\nUse the Error type from errors.go.\nKeep it short.\n\n",
+			want:  RescueChoice{Action: RescueRetry, Instructions: "Use the Error type from errors.go.\nKeep it short."},
+		},
+		"retry at end of input": {
+			input: "retry\nOnly this line",
+			want:  RescueChoice{Action: RescueRetry, Instructions: "Only this line"},
+		},
+		"edit": {
+			input:      "e\n",
+			req:        RescueRequest{Artifact: "specs/001-test/plan.yaml"},
+			want:       RescueChoice{Action: RescueRevalidate},
+			wantEdited: true,
+			wantOut:    "[e] Edit - open specs/001-test/plan.yaml",
+		},
+		"edit not offered": {
+			input:   "e\na\n",
+			want:    RescueChoice{Action: RescueAbort},
+			wantOut: `Unknown choice "e"`,
+		},
+		"skip": {
+			input:   "s\n",
+			req:     RescueRequest{TaskID: "T003"},
+			want:    RescueChoice{Action: RescueSkipTask},
+			wantOut: "mark task T003 Blocked",
+		},
+		"skip not offered": {
+			input: "s\n",
+			want:  RescueChoice{Action: RescueAbort},
+		},
+		"default aborts": {
+			input: "\n",
+			want:  RescueChoice{Action: RescueAbort},
+		},
+		"end of input aborts": {
+			input: "",
+			want:  RescueChoice{Action: RescueAbort},
+		},
+		"unknown choice asks again": {
+			input:   "x\nr\n\n",
+			want:    RescueChoice{Action: RescueRetry},
+			wantOut: `Unknown choice "x"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var out bytes.Buffer
+			edited := false
+			r := NewTerminalRescuer(strings.NewReader(tt.input), &out)
+			r.Editor = func(path string) error {
+				edited = true
+				assert.Equal(t, tt.req.Artifact, path)
+				return nil
+			}
+			tt.req.Stage = StagePlan
+			tt.req.Errors = []string{"missing field: summary"}
+
+			got, err := r.Rescue(tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantEdited, edited)
+			assert.Contains(t, out.String(), "- missing field: summary")
+			assert.Contains(t, out.String(), tt.wantOut)
+		})
+	}
+}
+
+func TestTerminalRescuerEditorFails(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	r := NewTerminalRescuer(strings.NewReader("e\na\n"), &out)
+	r.Editor = func(string) error { return errors.New("no editor") }
+
+	got, err := r.Rescue(RescueRequest{Stage: StagePlan, Artifact: "plan.yaml"})
+	require.NoError(t, err)
+	assert.Equal(t, RescueAbort, got.Action)
+	assert.Contains(t, out.String(), "Could not open the editor: no editor")
+}
+
+func TestExecuteStage_RescueRetry(t *testing.T) {
+	claude := &mockClaudeExecutor{}
+	rescuer := &stubRescuer{choices: []RescueChoice{{Action: RescueRetry, Instructions: "Add the summary field."}}}
+	executor := &Executor{
+		Claude:   claude,
+		StateDir: t.TempDir(),
+		SpecsDir: t.TempDir(),
+		Rescue:   rescuer,
+	}
+
+	calls := 0
+	validateFunc := func(string) error {
+		calls++
+		if calls == 1 {
+			return errors.New("schema validation failed:\n- missing field: summary")
+		}
+		return nil
+	}
+
+	result, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", validateFunc)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.False(t, result.Exhausted)
+
+	require.Len(t, rescuer.requests, 1)
+	assert.Equal(t, []string{"missing field: summary"}, rescuer.requests[0].Errors)
+	require.Len(t, claude.executeCalls, 2)
+	assert.Contains(t, claude.executeCalls[1], "missing field: summary")
+	assert.Contains(t, claude.executeCalls[1], "Additional instructions from the user:\nAdd the summary field.")
+}
+
+func TestExecuteStage_RescueRevalidate(t *testing.T) {
+	specsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "001-test"), 0o755))
+	rescuer := &stubRescuer{choices: []RescueChoice{{Action: RescueRevalidate}, {Action: RescueRevalidate}}}
+	claude := &mockClaudeExecutor{}
+	executor := &Executor{
+		Claude:   claude,
+		StateDir: t.TempDir(),
+		SpecsDir: specsDir,
+		Rescue:   rescuer,
+	}
+
+	calls := 0
+	validateFunc := func(string) error {
+		calls++
+		if calls < 3 {
+			return errors.New("schema validation failed:\n- missing field: summary")
+		}
+		return nil
+	}
+
+	result, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", validateFunc)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Len(t, claude.executeCalls, 1, "revalidating does not run the agent again")
+	assert.Len(t, rescuer.requests, 2, "prompted again while the edited artifact still fails")
+}
+
+func TestExecuteStage_RescueSkipTask(t *testing.T) {
+	rescuer := &stubRescuer{choices: []RescueChoice{{Action: RescueSkipTask}}}
+	executor := &Executor{
+		Claude:   &mockClaudeExecutor{},
+		StateDir: t.TempDir(),
+		SpecsDir: t.TempDir(),
+		Rescue:   rescuer,
+	}
+	executor.scope.taskID = "T002"
+
+	_, err := executor.ExecuteStage("001-test", StageImplement, "/autospec.implement", func(string) error {
+		return errors.New("task T002 is not completed")
+	})
+	require.ErrorIs(t, err, errTaskSkipped)
+	require.Len(t, rescuer.requests, 1)
+	assert.Equal(t, "T002", rescuer.requests[0].TaskID)
+}
+
+func TestExecuteStage_RescueAbort(t *testing.T) {
+	rescuer := &stubRescuer{}
+	executor := &Executor{
+		Claude:   &mockClaudeExecutor{},
+		StateDir: t.TempDir(),
+		SpecsDir: t.TempDir(),
+		Rescue:   rescuer,
+	}
+
+	result, err := executor.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(string) error {
+		return errors.New("validation failed")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "retry exhausted")
+	assert.True(t, result.Exhausted)
+	assert.Len(t, rescuer.requests, 1)
+}
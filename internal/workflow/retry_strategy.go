@@ -0,0 +1,199 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/retry"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"gopkg.in/yaml.v3"
+)
+
+// retryArtifacts maps stages to the artifact their validation checks. Stages
+// without an entry (implement, constitution) get error-only retries.
+var retryArtifacts = map[Stage]validation.ArtifactType{
+	StageSpecify:   validation.ArtifactTypeSpec,
+	StageClarify:   validation.ArtifactTypeSpec,
+	StagePlan:      validation.ArtifactTypePlan,
+	StageTasks:     validation.ArtifactTypeTasks,
+	StageChecklist: validation.ArtifactTypeChecklist,
+	StageAnalyze:   validation.ArtifactTypeAnalysis,
+	StageForecast:  validation.ArtifactTypeForecast,
+}
+
+// retryStrategyDescriptions is shown when a retry starts.
+var retryStrategyDescriptions = map[string]string{
+	retry.StrategyErrors:  "injecting validation errors into command",
+	retry.StrategySchema:  "injecting validation errors and the artifact schema into command",
+	retry.StrategyFixOnly: "asking the agent to fix only the failing sections",
+}
+
+// errorSectionPattern extracts the top-level key from a validation error such
+// as "line 12:3: user_stories[0].priority: invalid enum value".
+var errorSectionPattern = regexp.MustCompile(`^(?:line \d+(?::\d+)?: )?([A-Za-z_][A-Za-z0-9_]*)[.\[:]`)
+
+// retryStrategy returns the strategy of a stage's retry (1-based), from the
+// stage's ladder in RetryStrategies, else the "default" ladder.
+func (e *Executor) retryStrategy(stage Stage, retryNum int) string {
+	ladder, ok := e.RetryStrategies[string(stage)]
+	if !ok {
+		ladder = e.RetryStrategies["default"]
+	}
+	return retry.StrategyFor(ladder, retryNum)
+}
+
+// buildRetryPrompt returns the command of the next attempt and the strategy
+// it used. A strategy the stage cannot support steps down the ladder:
+// fix_only needs a single artifact file to repair and schema needs an
+// artifact type, so both end up as errors for implement.
+func (e *Executor) buildRetryPrompt(ctx *stageExecutionContext) (string, string) {
+	strategy := e.retryStrategy(ctx.stage, ctx.retryState.Count)
+	retryContext := FormatRetryContext(ctx.retryState.Count, e.MaxRetries, ctx.lastValidationErrors)
+	artType, hasArtifact := retryArtifacts[ctx.stage]
+
+	if strategy == retry.StrategyFixOnly {
+		if path := e.retryArtifactPath(ctx, artType); hasArtifact && path != "" {
+			return FormatFixOnlyPrompt(ctx.retryState.Count, e.MaxRetries, path, artType, ctx.lastValidationErrors), strategy
+		}
+		strategy = retry.StrategySchema
+	}
+	if strategy == retry.StrategySchema {
+		if !hasArtifact {
+			return BuildRetryCommand(ctx.command, retryContext, ""), retry.StrategyErrors
+		}
+		retryContext += "\n\n" + formatRetrySchema(artType, nil)
+	}
+	return BuildRetryCommand(ctx.command, retryContext, ""), strategy
+}
+
+// retryArtifactPath returns the artifact file a fix_only retry repairs, or ""
+// when it does not exist. Specify runs before the spec name is known, so its
+// spec is detected from the branch; staged stages repair the staging copy.
+// Checklists span several files and are never repaired this way.
+func (e *Executor) retryArtifactPath(ctx *stageExecutionContext, artType validation.ArtifactType) string {
+	if artType == validation.ArtifactTypeChecklist {
+		return ""
+	}
+	specDir := e.stageSpecDir(ctx)
+	if ctx.specName == "" {
+		metadata, err := spec.DetectCurrentSpec(e.SpecsDir)
+		if err != nil {
+			return ""
+		}
+		specDir = metadata.Directory
+	}
+	path := filepath.Join(specDir, string(artType)+".yaml")
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// FormatFixOnlyPrompt builds the prompt of a fix_only retry. Instead of
+// rerunning the stage command, it asks the agent to edit the existing
+// artifact in place, quoting the sections named by the validation errors and
+// their part of the schema, so a near-miss is repaired rather than
+// regenerated with new mistakes.
+func FormatFixOnlyPrompt(attemptNum, maxRetries int, path string, artType validation.ArtifactType, validationErrors []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "RETRY %d/%d (fix only)\n\n", attemptNum, maxRetries)
+	fmt.Fprintf(&sb, "%s failed schema validation. Do not regenerate it or rerun the stage. ", path)
+	sb.WriteString("Edit the file in place, changing only what is needed to fix the errors below, and leave every other part of it as it is.\n\n")
+
+	sb.WriteString("Errors:\n")
+	shown := validationErrors
+	if len(shown) > maxRetryErrors {
+		shown = shown[:maxRetryErrors]
+	}
+	for _, err := range shown {
+		fmt.Fprintf(&sb, "- %s\n", err)
+	}
+	if remaining := len(validationErrors) - len(shown); remaining > 0 {
+		fmt.Fprintf(&sb, "...and %d more errors\n", remaining)
+	}
+
+	sections := failingSections(artType, validationErrors)
+	if excerpt := sectionExcerpt(path, sections); excerpt != "" {
+		fmt.Fprintf(&sb, "\nFailing sections of %s:\n\n```yaml\n%s```\n", filepath.Base(path), excerpt)
+	}
+	sb.WriteString("\n" + formatRetrySchema(artType, sections) + "\n\n")
+	fmt.Fprintf(&sb, "When done, run `autospec artifact %s` and fix anything it still reports.", path)
+	return sb.String()
+}
+
+// failingSections returns the top-level schema fields named by the errors, in
+// error order without duplicates.
+func failingSections(artType validation.ArtifactType, validationErrors []string) []string {
+	schema, err := validation.GetSchema(artType)
+	if err != nil {
+		return nil
+	}
+	var sections []string
+	for _, msg := range validationErrors {
+		match := errorSectionPattern.FindStringSubmatch(msg)
+		if match == nil || slices.Contains(sections, match[1]) {
+			continue
+		}
+		for _, field := range schema.Fields {
+			if field.Name == match[1] {
+				sections = append(sections, match[1])
+				break
+			}
+		}
+	}
+	return sections
+}
+
+// sectionExcerpt returns the YAML of the given top-level sections of an
+// artifact, or "" when it cannot be parsed or has none of them.
+func sectionExcerpt(path string, sections []string) string {
+	data, err := os.ReadFile(path)
+	if err != nil || len(sections) == 0 {
+		return ""
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return ""
+	}
+	root := doc.Content[0]
+	excerpt := &yaml.Node{Kind: yaml.MappingNode}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if slices.Contains(sections, root.Content[i].Value) {
+			excerpt.Content = append(excerpt.Content, root.Content[i], root.Content[i+1])
+		}
+	}
+	if len(excerpt.Content) == 0 {
+		return ""
+	}
+	out, err := yaml.Marshal(excerpt)
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// formatRetrySchema returns the schema of an artifact for a retry prompt,
+// limited to the given top-level sections when there are any.
+func formatRetrySchema(artType validation.ArtifactType, sections []string) string {
+	schema, err := validation.GetSchema(artType)
+	if err != nil {
+		return ""
+	}
+	fields := schema.Fields
+	if len(sections) > 0 {
+		fields = nil
+		for _, field := range schema.Fields {
+			if slices.Contains(sections, field.Name) {
+				fields = append(fields, field)
+			}
+		}
+	}
+	var sb strings.Builder
+	validation.WriteSchemaFields(&sb, fields, "")
+	return fmt.Sprintf("Required schema for %s.yaml:\n\n```text\n%s```", artType, sb.String())
+}
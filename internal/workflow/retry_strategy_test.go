@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const retrySpecYAML = `feature:
+  branch: 001-retry
+user_stories:
+  - id: US-001
+    priority: urgent
+requirements:
+  functional:
+    - id: FR-001
+      description: The system MUST retry
+`
+
+func TestExecutor_BuildRetryPrompt(t *testing.T) {
+	t.Parallel()
+
+	specsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "001-retry"), 0o755))
+	specPath := filepath.Join(specsDir, "001-retry", "spec.yaml")
+	require.NoError(t, os.WriteFile(specPath, []byte(retrySpecYAML), 0o644))
+	errs := []string{"line 4:15: user_stories[0].priority: invalid enum value"}
+
+	tests := map[string]struct {
+		stage        Stage
+		specName     string
+		strategies   map[string][]string
+		retryNum     int
+		wantStrategy string
+		wantContains []string
+		wantMissing  []string
+	}{
+		"first retry appends errors": {
+			stage: StageSpecify, specName: "001-retry", retryNum: 1,
+			wantStrategy: retry.StrategyErrors,
+			wantContains: []string{"/autospec.specify", "RETRY 1/3", "invalid enum value"},
+			wantMissing:  []string{"Required schema"},
+		},
+		"second retry adds the schema": {
+			stage: StageSpecify, specName: "001-retry", retryNum: 2,
+			wantStrategy: retry.StrategySchema,
+			wantContains: []string{"/autospec.specify", "RETRY 2/3", "Required schema for spec.yaml", "user_stories: array (required)", "requirements: object"},
+		},
+		"third retry repairs the failing section": {
+			stage: StageSpecify, specName: "001-retry", retryNum: 3,
+			wantStrategy: retry.StrategyFixOnly,
+			wantContains: []string{"RETRY 3/3 (fix only)", specPath, "Do not regenerate it", "priority: urgent", "user_stories: array (required)", "autospec artifact " + specPath},
+			wantMissing:  []string{"/autospec.specify", "FR-001", "requirements: object"},
+		},
+		"stage ladder from config": {
+			stage: StagePlan, specName: "001-retry", retryNum: 1,
+			strategies:   map[string][]string{"plan": {retry.StrategySchema}, "default": {retry.StrategyFixOnly}},
+			wantStrategy: retry.StrategySchema,
+			wantContains: []string{"/autospec.plan", "Required schema for plan.yaml"},
+		},
+		"default ladder from config": {
+			stage: StageSpecify, specName: "001-retry", retryNum: 1,
+			strategies:   map[string][]string{"default": {retry.StrategyFixOnly}},
+			wantStrategy: retry.StrategyFixOnly,
+			wantContains: []string{"(fix only)"},
+		},
+		"fix only without artifact falls back to schema": {
+			stage: StagePlan, specName: "001-retry", retryNum: 3,
+			wantStrategy: retry.StrategySchema,
+			wantContains: []string{"/autospec.plan", "Required schema for plan.yaml"},
+		},
+		"implement has no artifact schema": {
+			stage: StageImplement, specName: "001-retry", retryNum: 3,
+			wantStrategy: retry.StrategyErrors,
+			wantContains: []string{"/autospec.implement", "RETRY 3/3"},
+			wantMissing:  []string{"Required schema"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e := &Executor{SpecsDir: specsDir, MaxRetries: 3, RetryStrategies: tt.strategies}
+			ctx := &stageExecutionContext{
+				specName:             tt.specName,
+				stage:                tt.stage,
+				command:              "/autospec." + string(tt.stage),
+				retryState:           &retry.RetryState{Count: tt.retryNum},
+				lastValidationErrors: errs,
+			}
+
+			command, strategy := e.buildRetryPrompt(ctx)
+			assert.Equal(t, tt.wantStrategy, strategy)
+			for _, want := range tt.wantContains {
+				assert.Contains(t, command, want)
+			}
+			for _, missing := range tt.wantMissing {
+				assert.NotContains(t, command, missing)
+			}
+		})
+	}
+}
+
+func TestFailingSections(t *testing.T) {
+	t.Parallel()
+
+	sections := failingSections("spec", []string{
+		"line 4:15: user_stories[0].priority: invalid enum value",
+		"requirements.functional[0].id: does not match pattern",
+		"user_stories[1]: missing required field: title",
+		"yaml: line 3: mapping values are not allowed",
+	})
+	assert.Equal(t, []string{"user_stories", "requirements"}, sections)
+}
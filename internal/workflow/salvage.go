@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/salvage"
+	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// salvageArtifact handles an agent that printed the stage's artifact instead
+// of writing it. When the session's output holds a fenced YAML block of the
+// stage's artifact type, the block is written to the artifact's path and the
+// stage is validated again. It reports whether validation now passes; if not,
+// the file the agent left (or its absence) is restored and the attempt fails
+// as usual. Checklists span several files and are never recovered this way.
+func (e *Executor) salvageArtifact(ctx *stageExecutionContext) bool {
+	if e.Salvage == nil {
+		return false
+	}
+	artType, ok := retryArtifacts[ctx.stage]
+	if !ok || artType == validation.ArtifactTypeChecklist {
+		return false
+	}
+	data, ok := salvage.Find(e.Salvage.Text(), artType)
+	if !ok {
+		return false
+	}
+
+	specDir := e.stageSpecDir(ctx)
+	if ctx.specName == "" {
+		metadata, err := spec.DetectCurrentSpec(e.SpecsDir)
+		if err != nil {
+			return false
+		}
+		specDir = metadata.Directory
+	}
+	path := filepath.Join(specDir, string(artType)+".yaml")
+	previous, readErr := os.ReadFile(path)
+	if readErr == nil && bytes.Equal(previous, data) {
+		return false
+	}
+	if err := fsutil.WriteFile(path, data, 0o644); err != nil {
+		e.debugLog("Writing recovered %s: %v", path, err)
+		return false
+	}
+
+	if err := ctx.validateFunc(e.stageSpecDir(ctx)); err != nil {
+		e.debugLog("Recovered %s fails validation: %v", path, err)
+		if readErr == nil {
+			_ = fsutil.WriteFile(path, previous, 0o644)
+		} else {
+			_ = os.Remove(path)
+		}
+		return false
+	}
+	e.recordStageEvent(ctx, events.TypeArtifactRecovered, path+" written from the agent output", nil)
+	output.Default().Warn("the agent printed %s instead of writing it; recovered it from the output", filepath.Base(path))
+	return true
+}
@@ -0,0 +1,75 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/ariel-frischer/autospec/internal/salvage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSalvageExecutor returns an executor whose plan agent prints the plan in
+// src inside a fenced YAML block instead of writing plan.yaml.
+func newSalvageExecutor(t *testing.T, src string) (*Executor, string) {
+	t.Helper()
+	root := t.TempDir()
+	specsDir := filepath.Join(root, "specs")
+	specDir := filepath.Join(specsDir, "001-test")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+
+	agent, err := cliagent.NewCustomAgentFromConfig(cliagent.CustomAgentConfig{
+		Command: "sh",
+		Args:    []string{"-c", "printf 'Here is the plan:\\n```yaml\\n'; cat \"$SRC\"; printf '```\\n'", "{{PROMPT}}"},
+	})
+	require.NoError(t, err)
+
+	capture := salvage.New()
+	abs, err := filepath.Abs(src)
+	require.NoError(t, err)
+	return &Executor{
+		Claude:   &ClaudeExecutor{Agent: agent, Env: map[string]string{"SRC": abs}, UsageWriter: capture},
+		StateDir: filepath.Join(root, "state"),
+		SpecsDir: specsDir,
+		Salvage:  capture,
+	}, specDir
+}
+
+func TestExecuteStage_SalvagesPrintedArtifact(t *testing.T) {
+	e, specDir := newSalvageExecutor(t, filepath.Join("testdata", "plan", "valid", "plan.yaml"))
+
+	result, err := e.ExecuteStage("001-test", StagePlan, "/autospec.plan", ValidatePlanSchema)
+	require.NoError(t, err)
+	assert.True(t, result.Success)
+	assert.Zero(t, result.RetryCount, "a recovered artifact should not use a retry")
+
+	want, err := os.ReadFile(filepath.Join("testdata", "plan", "valid", "plan.yaml"))
+	require.NoError(t, err)
+	got, err := os.ReadFile(filepath.Join(specDir, "plan.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+func TestExecuteStage_SalvageRestoresOnInvalidArtifact(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "plan.yaml")
+	require.NoError(t, os.WriteFile(src, []byte("plan:\n  branch: 001-test\n"), 0o644))
+	e, specDir := newSalvageExecutor(t, src)
+	written := filepath.Join(specDir, "plan.yaml")
+	require.NoError(t, os.WriteFile(written, []byte("plan: [truncated\n"), 0o644))
+
+	_, err := e.ExecuteStage("001-test", StagePlan, "/autospec.plan", ValidatePlanSchema)
+	require.Error(t, err)
+	got, err := os.ReadFile(written)
+	require.NoError(t, err)
+	assert.Equal(t, "plan: [truncated\n", string(got), "the agent's file should be restored when the printed plan is invalid too")
+}
+
+func TestExecutor_SalvageArtifact_Disabled(t *testing.T) {
+	t.Parallel()
+
+	e := &Executor{SpecsDir: t.TempDir()}
+	ctx := &stageExecutionContext{specName: "001-test", stage: StagePlan}
+	assert.False(t, e.salvageArtifact(ctx))
+}
@@ -4,11 +4,11 @@
 package workflow
 
 import (
-	"errors"
 	"fmt"
 	"path/filepath"
 	"strings"
 
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/spec"
 	"github.com/ariel-frischer/autospec/internal/validation"
 )
@@ -64,6 +64,63 @@ func ValidateTasksSchema(specDir string) error {
 	return formatValidationErrors("tasks.yaml", result.Errors)
 }
 
+// ValidateAnalysisSchema validates an analysis.yaml file against its full schema.
+// Analyze runs interactively, so this is called after the session ends rather
+// than as an ExecuteStage validation callback.
+func ValidateAnalysisSchema(specDir string) error {
+	analysisPath := filepath.Join(specDir, validation.AnalysisFileName)
+	validator := &validation.AnalysisValidator{}
+	result := validator.Validate(analysisPath)
+
+	if result.Valid {
+		return nil
+	}
+
+	return formatValidationErrors(validation.AnalysisFileName, result.Errors)
+}
+
+// ValidateForecastSchema validates a forecast.yaml file against its full schema.
+// It wraps the existing ForecastValidator and returns an error suitable for
+// ExecuteStage's validation callback.
+func ValidateForecastSchema(specDir string) error {
+	validator := &validation.ForecastValidator{}
+	result := validator.Validate(validation.GetForecastFilePath(specDir))
+
+	if result.Valid {
+		return nil
+	}
+
+	return formatValidationErrors("forecast.yaml", result.Errors)
+}
+
+// ValidateChecklistsSchema validates every checklist in the spec's checklists/
+// directory against the checklist schema. At least one checklist must exist.
+// Errors from all files are combined so a retry can fix them in one pass.
+func ValidateChecklistsSchema(specDir string) error {
+	files, err := validation.ChecklistFiles(specDir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return apperrors.ErrSchemaViolation.Errorf("schema validation failed for checklists:\n- no checklist files found in %s/",
+			filepath.Join(specDir, validation.ChecklistsDirName))
+	}
+
+	validator := &validation.ChecklistValidator{}
+	var failed []string
+	for _, path := range files {
+		result := validator.Validate(path)
+		if result.Valid {
+			continue
+		}
+		failed = append(failed, formatValidationErrors("checklists/"+filepath.Base(path), result.Errors).Error())
+	}
+	if len(failed) > 0 {
+		return apperrors.ErrSchemaViolation.Errorf("%s", strings.Join(failed, ""))
+	}
+	return nil
+}
+
 // MakeSpecSchemaValidatorWithDetection creates a validation function that first
 // detects the current spec directory, then validates spec.yaml against its schema.
 // This is necessary for the specify stage where the spec name is not known until
@@ -84,6 +141,28 @@ func MakeSpecSchemaValidatorWithDetection(specsDir string) func(string) error {
 	}
 }
 
+// MakeArtifactSchemaValidatorWithDetection is MakeSpecSchemaValidatorWithDetection
+// for workflow types whose specify stage writes another artifact, such as the
+// research.yaml of a spike.
+func MakeArtifactSchemaValidatorWithDetection(specsDir, artifact string, artifactType validation.ArtifactType) func(string) error {
+	return func(_ string) error {
+		metadata, err := spec.DetectCurrentSpec(specsDir)
+		if err != nil {
+			return fmt.Errorf("detecting spec for validation: %w", err)
+		}
+
+		validator, err := validation.NewArtifactValidator(artifactType)
+		if err != nil {
+			return err
+		}
+		result := validator.Validate(filepath.Join(metadata.Directory, artifact))
+		if result.Valid {
+			return nil
+		}
+		return formatValidationErrors(artifact, result.Errors)
+	}
+}
+
 // formatValidationErrors formats a list of validation errors into a single error.
 // The error message is formatted for inclusion in retry context.
 func formatValidationErrors(artifactName string, validationErrs []*validation.ValidationError) error {
@@ -98,5 +177,5 @@ func formatValidationErrors(artifactName string, validationErrs []*validation.Va
 		sb.WriteString(fmt.Sprintf("- %s\n", err.Error()))
 	}
 
-	return errors.New(sb.String())
+	return apperrors.ErrSchemaViolation.Errorf("%s", sb.String())
 }
@@ -1,10 +1,13 @@
 package workflow
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
 )
 
 func TestValidateSpecSchema(t *testing.T) {
@@ -50,6 +53,9 @@ func TestValidateSpecSchema(t *testing.T) {
 					t.Errorf("ValidateSpecSchema() error = %q, want error containing %q; %s",
 						err.Error(), tc.errContains, tc.description)
 				}
+				if !errors.Is(err, apperrors.ErrSchemaViolation) {
+					t.Errorf("ValidateSpecSchema() error is not ErrSchemaViolation; %s", tc.description)
+				}
 				return
 			}
 
@@ -166,6 +172,148 @@ func TestValidateTasksSchema(t *testing.T) {
 	}
 }
 
+func TestValidateChecklistsSchema(t *testing.T) {
+	t.Parallel()
+
+	validChecklist := `checklist:
+  feature: "Auth"
+  branch: "001-auth"
+  domain: "security"
+categories:
+  - name: "Security"
+    items:
+      - id: "CHK001"
+        description: "Are rate limits defined?"
+        status: "pending"
+`
+
+	tests := map[string]struct {
+		files       map[string]string
+		wantErr     bool
+		errContains []string
+	}{
+		"valid checklist": {
+			files: map[string]string{"security.yaml": validChecklist},
+		},
+		"no checklists": {
+			wantErr:     true,
+			errContains: []string{"no checklist files found"},
+		},
+		"errors from every invalid file are reported": {
+			files: map[string]string{
+				"security.yaml": validChecklist,
+				"api.yaml":      "checklist:\n  feature: x\n",
+				"ux.yaml":       strings.Replace(validChecklist, `"pending"`, `"done"`, 1),
+			},
+			wantErr:     true,
+			errContains: []string{"checklists/api.yaml", "checklists/ux.yaml"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			specDir := t.TempDir()
+			if len(tc.files) > 0 {
+				dir := filepath.Join(specDir, "checklists")
+				if err := os.MkdirAll(dir, 0o755); err != nil {
+					t.Fatal(err)
+				}
+				for file, content := range tc.files {
+					if err := os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			err := ValidateChecklistsSchema(specDir)
+			if !tc.wantErr {
+				if err != nil {
+					t.Errorf("ValidateChecklistsSchema() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateChecklistsSchema() expected error, got nil")
+			}
+			for _, want := range tc.errContains {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("ValidateChecklistsSchema() error = %q, want containing %q", err.Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAnalysisSchema(t *testing.T) {
+	t.Parallel()
+
+	validAnalysis := `analysis:
+  branch: "001-auth"
+  timestamp: "2025-01-01T00:00:00Z"
+findings:
+  - id: "COV-001"
+    category: "coverage"
+    severity: "HIGH"
+    artifact: "tasks"
+    field: "phases[1]"
+    summary: "FR-004 has no task"
+    recommendation: "Add a task for FR-004"
+summary:
+  overall_status: "WARN"
+`
+
+	tests := map[string]struct {
+		content     string
+		wantErr     bool
+		errContains string
+	}{
+		"valid analysis": {
+			content: validAnalysis,
+		},
+		"missing analysis": {
+			wantErr:     true,
+			errContains: "analysis.yaml",
+		},
+		"finding without recommendation": {
+			content: strings.Replace(validAnalysis, "    recommendation: \"Add a task for FR-004\"\n", "", 1),
+		},
+		"finding without artifact or location": {
+			content:     strings.Replace(validAnalysis, "    artifact: \"tasks\"\n", "", 1),
+			wantErr:     true,
+			errContains: "artifact",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			specDir := t.TempDir()
+			if tc.content != "" {
+				if err := os.WriteFile(filepath.Join(specDir, "analysis.yaml"), []byte(tc.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			err := ValidateAnalysisSchema(specDir)
+			if !tc.wantErr {
+				if err != nil {
+					t.Errorf("ValidateAnalysisSchema() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("ValidateAnalysisSchema() expected error, got nil")
+			}
+			if !strings.Contains(err.Error(), tc.errContains) {
+				t.Errorf("ValidateAnalysisSchema() error = %q, want containing %q", err.Error(), tc.errContains)
+			}
+		})
+	}
+}
+
 func TestFormatValidationErrors(t *testing.T) {
 	t.Parallel()
 
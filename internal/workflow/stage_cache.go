@@ -0,0 +1,159 @@
+package workflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// stageCacheFile is the state file recording the inputs of the last
+// successful plan and tasks run per spec.
+const stageCacheFile = "stage_cache.json"
+
+// cachedStage describes a stage whose agent call can be skipped when its
+// inputs are unchanged: the artifact it writes and the artifacts it reads.
+type cachedStage struct {
+	output validation.ArtifactType
+	inputs []validation.ArtifactType
+}
+
+// cachedStages lists the stages covered by the stage cache. Implement is not
+// cached: its output is the codebase, not an artifact.
+var cachedStages = map[Stage]cachedStage{
+	StagePlan:  {output: validation.ArtifactTypePlan, inputs: []validation.ArtifactType{validation.ArtifactTypeSpec}},
+	StageTasks: {output: validation.ArtifactTypeTasks, inputs: []validation.ArtifactType{validation.ArtifactTypeSpec, validation.ArtifactTypePlan}},
+}
+
+// stageCacheEntry records what a successful stage run was generated from.
+type stageCacheEntry struct {
+	// Inputs maps input names (artifact files, "constitution", "command") to
+	// content hashes.
+	Inputs map[string]string `json:"inputs"`
+	// Output is the content hash of the artifact the stage wrote, so edits
+	// to or removal of the artifact invalidate the entry.
+	Output      string    `json:"output"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// ErrStageUpToDate is returned by StageExecutor.ExecutePlan and ExecuteTasks
+// when the stage was skipped because StageUpToDate holds.
+var ErrStageUpToDate = errors.New("stage is up to date")
+
+// StageUpToDate reports whether stage's artifact in specDir was produced by a
+// successful run from the current inputs: the upstream artifacts, the
+// constitution and the agent command (which includes the user's prompt and
+// any prompt template). Uncached stages are never up to date.
+func StageUpToDate(stateDir, specDir string, stage Stage, command string) bool {
+	cs, ok := cachedStages[stage]
+	if !ok || stateDir == "" {
+		return false
+	}
+	entry, ok := loadStageCache(stateDir)[stageCacheKey(specDir, stage)]
+	if !ok {
+		return false
+	}
+
+	output, err := validation.ArtifactContentHash(filepath.Join(specDir, string(cs.output)+".yaml"), cs.output)
+	if err != nil || output != entry.Output {
+		return false
+	}
+	inputs, err := stageInputHashes(specDir, cs, command)
+	if err != nil || len(inputs) != len(entry.Inputs) {
+		return false
+	}
+	for name, hash := range inputs {
+		if entry.Inputs[name] != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// RecordStageCompletion stores the inputs and output of a successful stage
+// run, so an identical rerun can be skipped.
+func RecordStageCompletion(stateDir, specDir string, stage Stage, command string) error {
+	cs, ok := cachedStages[stage]
+	if !ok || stateDir == "" {
+		return nil
+	}
+	inputs, err := stageInputHashes(specDir, cs, command)
+	if err != nil {
+		return err
+	}
+	output, err := validation.ArtifactContentHash(filepath.Join(specDir, string(cs.output)+".yaml"), cs.output)
+	if err != nil {
+		return err
+	}
+
+	cache := loadStageCache(stateDir)
+	cache[stageCacheKey(specDir, stage)] = stageCacheEntry{Inputs: inputs, Output: output, CompletedAt: time.Now()}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling stage cache: %w", err)
+	}
+	if err := os.MkdirAll(stateDir, 0o755); err != nil {
+		return fmt.Errorf("creating state directory: %w", err)
+	}
+	if err := fsutil.WriteFile(filepath.Join(stateDir, stageCacheFile), data, 0o644); err != nil {
+		return fmt.Errorf("writing stage cache: %w", err)
+	}
+	return nil
+}
+
+// stageInputHashes hashes the inputs a cached stage is generated from.
+func stageInputHashes(specDir string, cs cachedStage, command string) (map[string]string, error) {
+	inputs := make(map[string]string, len(cs.inputs)+2)
+	for _, input := range cs.inputs {
+		name := string(input) + ".yaml"
+		hash, err := validation.ArtifactContentHash(filepath.Join(specDir, name), input)
+		if err != nil {
+			return nil, err
+		}
+		inputs[name] = hash
+	}
+	if check := CheckConstitutionExists(); check.Exists {
+		data, err := os.ReadFile(check.Path)
+		if err != nil {
+			return nil, fmt.Errorf("reading constitution: %w", err)
+		}
+		inputs["constitution"] = shortHash(data)
+	}
+	inputs["command"] = shortHash([]byte(command))
+	return inputs, nil
+}
+
+// stageCacheKey identifies a spec's stage. The absolute spec directory keeps
+// specs of different projects sharing a state directory apart.
+func stageCacheKey(specDir string, stage Stage) string {
+	if abs, err := filepath.Abs(specDir); err == nil {
+		specDir = abs
+	}
+	return specDir + ":" + string(stage)
+}
+
+// loadStageCache reads the stage cache. A missing or unreadable file is an
+// empty cache: the worst case is a stage that runs again.
+func loadStageCache(stateDir string) map[string]stageCacheEntry {
+	cache := map[string]stageCacheEntry{}
+	data, err := os.ReadFile(filepath.Join(stateDir, stageCacheFile))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil || cache == nil {
+		return map[string]stageCacheEntry{}
+	}
+	return cache
+}
+
+func shortHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
@@ -0,0 +1,120 @@
+// Package workflow tests skipping plan and tasks when their inputs are unchanged.
+// Related: internal/workflow/stage_cache.go
+// Tags: workflow, stage-cache, plan, tasks, idempotent
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCachedSpec creates a project with a constitution and a spec that has
+// spec.yaml and plan.yaml, and changes into it.
+func setupCachedSpec(t *testing.T) (stateDir, specDir string) {
+	t.Helper()
+	root := t.TempDir()
+	t.Chdir(root)
+	require.NoError(t, os.MkdirAll(".autospec/memory", 0o755))
+	require.NoError(t, os.WriteFile(".autospec/memory/constitution.yaml", []byte("constitution:\n  project_name: demo\n"), 0o644))
+
+	specDir = filepath.Join(root, "specs", "001-demo")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte("feature:\n  branch: 001-demo\n  status: Draft\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "plan.yaml"), []byte("plan:\n  branch: 001-demo\n"), 0o644))
+	return filepath.Join(root, "state"), specDir
+}
+
+func TestStageUpToDate(t *testing.T) {
+	tests := map[string]struct {
+		change  func(t *testing.T, specDir string)
+		command string
+		want    bool
+	}{
+		"unchanged inputs": {
+			want: true,
+		},
+		"spec status change is ignored": {
+			change: func(t *testing.T, specDir string) {
+				writeFile(t, filepath.Join(specDir, "spec.yaml"), "feature:\n  branch: 001-demo\n  status: Completed\n")
+			},
+			want: true,
+		},
+		"spec edited": {
+			change: func(t *testing.T, specDir string) {
+				writeFile(t, filepath.Join(specDir, "spec.yaml"), "feature:\n  branch: 001-demo\n  status: Draft\n  summary: more\n")
+			},
+		},
+		"plan edited by hand": {
+			change: func(t *testing.T, specDir string) {
+				writeFile(t, filepath.Join(specDir, "plan.yaml"), "plan:\n  branch: 001-demo\n  notes: edited\n")
+			},
+		},
+		"plan removed": {
+			change: func(t *testing.T, specDir string) {
+				require.NoError(t, os.Remove(filepath.Join(specDir, "plan.yaml")))
+			},
+		},
+		"constitution edited": {
+			change: func(t *testing.T, _ string) {
+				writeFile(t, ".autospec/memory/constitution.yaml", "constitution:\n  project_name: renamed\n")
+			},
+		},
+		"different prompt": {
+			command: `/autospec.plan "Focus on security"`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			stateDir, specDir := setupCachedSpec(t)
+			require.NoError(t, RecordStageCompletion(stateDir, specDir, StagePlan, "/autospec.plan"))
+			if tt.change != nil {
+				tt.change(t, specDir)
+			}
+			command := tt.command
+			if command == "" {
+				command = "/autospec.plan"
+			}
+			assert.Equal(t, tt.want, StageUpToDate(stateDir, specDir, StagePlan, command))
+		})
+	}
+}
+
+func TestStageUpToDate_NotRecorded(t *testing.T) {
+	stateDir, specDir := setupCachedSpec(t)
+	assert.False(t, StageUpToDate(stateDir, specDir, StagePlan, "/autospec.plan"))
+
+	require.NoError(t, RecordStageCompletion(stateDir, specDir, StagePlan, "/autospec.plan"))
+	assert.False(t, StageUpToDate(stateDir, specDir, StageTasks, "/autospec.tasks"), "entries are per stage")
+	assert.False(t, StageUpToDate(stateDir, specDir, StageImplement, "/autospec.implement"), "implement is never cached")
+	assert.False(t, StageUpToDate("", specDir, StagePlan, "/autospec.plan"))
+
+	writeFile(t, filepath.Join(stateDir, stageCacheFile), "{not json")
+	assert.False(t, StageUpToDate(stateDir, specDir, StagePlan, "/autospec.plan"), "a corrupt cache is empty")
+	require.NoError(t, RecordStageCompletion(stateDir, specDir, StagePlan, "/autospec.plan"), "a corrupt cache is overwritten")
+	assert.True(t, StageUpToDate(stateDir, specDir, StagePlan, "/autospec.plan"))
+}
+
+func TestStageExecutor_ExecutePlan_SkipsWhenUpToDate(t *testing.T) {
+	stateDir, specDir := setupCachedSpec(t)
+	executor := &Executor{StateDir: stateDir} // No Claude runner: any agent call would panic
+	se := NewStageExecutor(executor, filepath.Dir(specDir), false)
+	require.NoError(t, RecordStageCompletion(stateDir, specDir, StagePlan, se.buildPlanCommand("")))
+
+	err := se.ExecutePlan("001-demo", "")
+	assert.True(t, errors.Is(err, ErrStageUpToDate), "got %v", err)
+
+	// --force ignores the cache
+	executor.ForceRerun = true
+	assert.False(t, se.upToDate(specDir, StagePlan, se.buildPlanCommand("")))
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
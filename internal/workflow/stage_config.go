@@ -1,5 +1,11 @@
 package workflow
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
 // StageConfig represents the user's selected stages for execution.
 // It determines which workflow stages (specify, plan, tasks, implement)
 // and optional stages (constitution, clarify, checklist, analyze)
@@ -79,6 +85,55 @@ func (sc *StageConfig) GetCanonicalOrder() []Stage {
 	return sc.GetSelectedStages()
 }
 
+// stageOrder lists every stage in canonical order.
+var stageOrder = []Stage{
+	StageConstitution, StageSpecify, StageClarify, StagePlan,
+	StageTasks, StageChecklist, StageAnalyze, StageImplement,
+}
+
+// ParseStageList parses a comma-separated list of stage names, as given to
+// 'autospec run --only' and '--skip'. Names are case-insensitive.
+func ParseStageList(names []string) ([]Stage, error) {
+	stages := make([]Stage, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := artifactDependencies[Stage(name)]; !ok {
+			valid := make([]string, len(stageOrder))
+			for i, s := range stageOrder {
+				valid[i] = string(s)
+			}
+			return nil, fmt.Errorf("unknown stage %q (valid: %s)", name, strings.Join(valid, ", "))
+		}
+		stages = append(stages, Stage(name))
+	}
+	return stages, nil
+}
+
+// SetStage enables or disables a single stage.
+func (sc *StageConfig) SetStage(stage Stage, enabled bool) {
+	switch stage {
+	case StageSpecify:
+		sc.Specify = enabled
+	case StagePlan:
+		sc.Plan = enabled
+	case StageTasks:
+		sc.Tasks = enabled
+	case StageImplement:
+		sc.Implement = enabled
+	case StageConstitution:
+		sc.Constitution = enabled
+	case StageClarify:
+		sc.Clarify = enabled
+	case StageChecklist:
+		sc.Checklist = enabled
+	case StageAnalyze:
+		sc.Analyze = enabled
+	}
+}
+
 // SetAll enables all core stages.
 func (sc *StageConfig) SetAll() {
 	sc.Specify = true
@@ -234,5 +289,6 @@ func (sc *StageConfig) GetAllRequiredArtifacts() []string {
 	for artifact := range required {
 		result = append(result, artifact)
 	}
+	sort.Strings(result)
 	return result
 }
@@ -4,6 +4,7 @@
 package workflow
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -564,3 +565,46 @@ func TestGetAllRequiredArtifacts(t *testing.T) {
 		})
 	}
 }
+
+func TestParseStageList(t *testing.T) {
+	stages, err := ParseStageList([]string{"Specify", " plan", "", "analyze"})
+	if err != nil {
+		t.Fatalf("ParseStageList returned error: %v", err)
+	}
+	expected := []Stage{StageSpecify, StagePlan, StageAnalyze}
+	if len(stages) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, stages)
+	}
+	for i, stage := range stages {
+		if stage != expected[i] {
+			t.Errorf("stage %d: expected %s, got %s", i, expected[i], stage)
+		}
+	}
+
+	_, err = ParseStageList([]string{"plan", "review"})
+	if err == nil {
+		t.Fatal("expected error for unknown stage")
+	}
+	if !strings.Contains(err.Error(), `unknown stage "review"`) || !strings.Contains(err.Error(), "constitution, specify, clarify") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestSetStage(t *testing.T) {
+	sc := NewStageConfigAll()
+	for _, stage := range stageOrder {
+		sc.SetStage(stage, true)
+	}
+	if sc.Count() != len(stageOrder) {
+		t.Errorf("expected all %d stages enabled, got %d", len(stageOrder), sc.Count())
+	}
+
+	sc.SetStage(StageClarify, false)
+	sc.SetStage(StageTasks, false)
+	if sc.Clarify || sc.Tasks {
+		t.Error("SetStage(false) should disable clarify and tasks")
+	}
+	if sc.Count() != len(stageOrder)-2 {
+		t.Errorf("expected %d stages, got %d", len(stageOrder)-2, sc.Count())
+	}
+}
@@ -6,10 +6,12 @@ package workflow
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 
 	"github.com/ariel-frischer/autospec/internal/retry"
 	"github.com/ariel-frischer/autospec/internal/spec"
+	"github.com/ariel-frischer/autospec/internal/validation"
 )
 
 // StageExecutor handles specify, plan, and tasks stage execution.
@@ -17,10 +19,11 @@ import (
 // Each stage transforms artifacts: specify creates spec.yaml, plan creates plan.yaml,
 // tasks creates tasks.yaml.
 type StageExecutor struct {
-	executor             *Executor // Underlying executor for Claude command execution
-	specsDir             string    // Base directory for spec storage (e.g., "specs/")
-	debug                bool      // Enable debug logging
-	enableRiskAssessment bool      // Inject risk assessment instructions in plan command
+	executor             *Executor     // Underlying executor for Claude command execution
+	specsDir             string        // Base directory for spec storage (e.g., "specs/")
+	debug                bool          // Enable debug logging
+	enableRiskAssessment bool          // Inject risk assessment instructions in plan command
+	workflowType         *WorkflowType // Kind of work being specified; nil means feature
 }
 
 // StageExecutorOptions holds optional configuration for StageExecutor.
@@ -51,11 +54,15 @@ func NewStageExecutorWithOptions(executor *Executor, specsDir string, opts Stage
 	}
 }
 
-// debugLog prints a debug message if debug mode is enabled.
+// SetWorkflowType selects the workflow type whose command and artifact the
+// specify stage uses.
+func (s *StageExecutor) SetWorkflowType(t WorkflowType) {
+	s.workflowType = &t
+}
+
+// debugLog writes a debug record tagged with the stage_executor component.
 func (s *StageExecutor) debugLog(format string, args ...interface{}) {
-	if s.debug {
-		fmt.Printf("[DEBUG][StageExecutor] "+format+"\n", args...)
-	}
+	s.executor.logger("stage_executor").Debug(fmt.Sprintf(format, args...))
 }
 
 // ExecuteSpecify runs the specify stage for a feature description.
@@ -82,7 +89,17 @@ func (s *StageExecutor) resetSpecifyRetryState() {
 
 // runSpecifyStage executes the specify stage command
 func (s *StageExecutor) runSpecifyStage(featureDescription string) (*StageResult, error) {
-	command := fmt.Sprintf("/autospec.specify \"%s\"", featureDescription)
+	if t := s.workflowType; t != nil && t.ArtifactType != validation.ArtifactTypeSpec {
+		command := fmt.Sprintf("%s %s", t.SpecifyCommand, quotePrompt(featureDescription))
+		validateFunc := MakeArtifactSchemaValidatorWithDetection(s.specsDir, t.Artifact, t.ArtifactType)
+		return s.executor.ExecuteStage("", StageSpecify, command, validateFunc)
+	}
+
+	specifyCmd := "/autospec.specify"
+	if s.workflowType != nil {
+		specifyCmd = s.workflowType.SpecifyCommand
+	}
+	command := fmt.Sprintf("%s %s", specifyCmd, quotePrompt(featureDescription))
 	validateFunc := MakeSpecSchemaValidatorWithDetection(s.specsDir)
 	return s.executor.ExecuteStage("", StageSpecify, command, validateFunc)
 }
@@ -100,10 +117,20 @@ func (s *StageExecutor) detectAndValidateSpec() (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("detecting created spec: %w", err)
 	}
+	specName := metadata.DirName()
+	s.executor.writeTranscript(specName, StageSpecify)
+	if t := s.workflowType; t != nil && t.ArtifactType != validation.ArtifactTypeSpec {
+		if _, err := os.Stat(filepath.Join(metadata.Directory, t.Artifact)); err != nil {
+			return "", fmt.Errorf("validating %s: %w", t.Artifact, err)
+		}
+		s.debugLog("ExecuteSpecify completed successfully: %s (%s)", specName, t.Artifact)
+		return specName, nil
+	}
 	if err := s.executor.ValidateSpec(metadata.Directory); err != nil {
 		return "", fmt.Errorf("validating spec: %w", err)
 	}
-	specName := fmt.Sprintf("%s-%s", metadata.Number, metadata.Name)
+	s.executor.renderMarkdownViews(specName)
+	s.executor.linkIssues(metadata.Directory)
 	s.debugLog("ExecuteSpecify completed successfully: %s", specName)
 	return specName, nil
 }
@@ -119,8 +146,15 @@ func (s *StageExecutor) ExecutePlan(specNameArg string, prompt string) error {
 
 	s.debugLog("ExecutePlan called for spec: %s, prompt: %s", specName, prompt)
 
+	prompt, err = s.executor.renderPrompt(PromptPlan, PromptTemplateData{SpecName: specName, Prompt: prompt})
+	if err != nil {
+		return err
+	}
 	command := s.buildPlanCommand(prompt)
 	specDir := filepath.Join(s.specsDir, specName)
+	if s.upToDate(specDir, StagePlan, command) {
+		return ErrStageUpToDate
+	}
 
 	result, err := s.executor.ExecuteStage(
 		specName,
@@ -138,6 +172,9 @@ func (s *StageExecutor) ExecutePlan(specNameArg string, prompt string) error {
 			totalAttempts, result.RetryCount, err)
 	}
 
+	s.recordLineage(specDir, validation.ArtifactTypePlan)
+	s.recordCompletion(specDir, StagePlan, command)
+
 	// Check for research.md (optional but usually created)
 	researchPath := filepath.Join(specDir, "research.md")
 	if _, statErr := filepath.Glob(researchPath); statErr == nil {
@@ -159,7 +196,15 @@ func (s *StageExecutor) ExecuteTasks(specNameArg string, prompt string) error {
 
 	s.debugLog("ExecuteTasks called for spec: %s, prompt: %s", specName, prompt)
 
+	prompt, err = s.executor.renderPrompt(PromptTasks, PromptTemplateData{SpecName: specName, Prompt: prompt})
+	if err != nil {
+		return err
+	}
 	command := s.buildTasksCommand(prompt)
+	specDir := filepath.Join(s.specsDir, specName)
+	if s.upToDate(specDir, StageTasks, command) {
+		return ErrStageUpToDate
+	}
 
 	result, err := s.executor.ExecuteStage(
 		specName,
@@ -177,10 +222,45 @@ func (s *StageExecutor) ExecuteTasks(specNameArg string, prompt string) error {
 			totalAttempts, result.RetryCount, err)
 	}
 
+	s.recordLineage(specDir, validation.ArtifactTypeTasks)
+	s.recordCompletion(specDir, StageTasks, command)
+	s.executor.syncProject(specDir)
+
 	s.debugLog("ExecuteTasks completed successfully")
 	return nil
 }
 
+// recordLineage runs after a plan or tasks artifact is regenerated: it stamps
+// the upstream content hashes into the artifact's _meta and clears any stale
+// marker left by 'autospec amend'. Failures only produce warnings.
+func (s *StageExecutor) recordLineage(specDir string, artifactType validation.ArtifactType) {
+	artifact := string(artifactType) + ".yaml"
+	if err := validation.StampSourceHashes(filepath.Join(specDir, artifact), artifactType); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: recording source hashes: %v\n", err)
+	}
+	if err := spec.ClearStale(specDir, artifact); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+}
+
+// upToDate reports whether a plan or tasks run can be skipped because its
+// inputs match the last successful run (see StageUpToDate).
+func (s *StageExecutor) upToDate(specDir string, stage Stage, command string) bool {
+	if s.executor.ForceRerun || !StageUpToDate(s.executor.StateDir, specDir, stage, command) {
+		return false
+	}
+	s.debugLog("%s is up to date for %s, skipping agent call", stage, specDir)
+	return true
+}
+
+// recordCompletion stores the inputs of a successful plan or tasks run.
+// Failures only produce warnings: the next run is simply not skipped.
+func (s *StageExecutor) recordCompletion(specDir string, stage Stage, command string) {
+	if err := RecordStageCompletion(s.executor.StateDir, specDir, stage, command); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: recording %s inputs: %v\n", stage, err)
+	}
+}
+
 // resolveSpecName resolves the spec name from argument or auto-detection.
 func (s *StageExecutor) resolveSpecName(specNameArg string) (string, error) {
 	if specNameArg != "" {
@@ -193,7 +273,7 @@ func (s *StageExecutor) resolveSpecName(specNameArg string) (string, error) {
 		return "", fmt.Errorf("detecting current spec: %w", err)
 	}
 
-	return fmt.Sprintf("%s-%s", metadata.Number, metadata.Name), nil
+	return metadata.DirName(), nil
 }
 
 // buildPlanCommand constructs the plan command with optional prompt.
@@ -201,7 +281,7 @@ func (s *StageExecutor) resolveSpecName(specNameArg string) (string, error) {
 func (s *StageExecutor) buildPlanCommand(prompt string) string {
 	var command string
 	if prompt != "" {
-		command = fmt.Sprintf("/autospec.plan \"%s\"", prompt)
+		command = fmt.Sprintf("/autospec.plan %s", quotePrompt(prompt))
 	} else {
 		command = "/autospec.plan"
 	}
@@ -211,7 +291,7 @@ func (s *StageExecutor) buildPlanCommand(prompt string) string {
 // buildTasksCommand constructs the tasks command with optional prompt.
 func (s *StageExecutor) buildTasksCommand(prompt string) string {
 	if prompt != "" {
-		return fmt.Sprintf("/autospec.tasks \"%s\"", prompt)
+		return fmt.Sprintf("/autospec.tasks %s", quotePrompt(prompt))
 	}
 	return "/autospec.tasks"
 }
@@ -273,6 +353,70 @@ func (s *StageExecutor) ExecuteClarify(specName string, prompt string) error {
 	return nil
 }
 
+// ExecuteAmend applies a change description to an existing spec.yaml.
+// The session runs as an automated specify stage, so the amended spec is
+// schema-validated with the usual retry loop. Marking downstream artifacts
+// stale is left to the caller.
+func (s *StageExecutor) ExecuteAmend(specName string, change string) error {
+	s.debugLog("ExecuteAmend called for spec: %s, change: %s", specName, change)
+
+	command := s.buildCommand("/autospec.amend", change)
+	s.printExecuting("/autospec.amend", change)
+
+	result, err := s.executor.ExecuteStage(specName, StageSpecify, command, ValidateSpecSchema)
+	if err != nil {
+		if result.Exhausted {
+			return fmt.Errorf("amend exhausted retries: %w", err)
+		}
+		return fmt.Errorf("amend failed: %w", err)
+	}
+
+	s.debugLog("ExecuteAmend completed successfully")
+	return nil
+}
+
+// ExecuteForecast runs the forecast stage with optional prompt.
+// Forecast estimates effort and risk for each task in tasks.yaml and writes
+// forecast.yaml, which is schema-validated with the usual retry loop.
+func (s *StageExecutor) ExecuteForecast(specName string, prompt string) error {
+	s.debugLog("ExecuteForecast called for spec: %s, prompt: %s", specName, prompt)
+
+	command := s.buildCommand("/autospec.forecast", prompt)
+	s.printExecuting("/autospec.forecast", prompt)
+
+	result, err := s.executor.ExecuteStage(specName, StageForecast, command, ValidateForecastSchema)
+	if err != nil {
+		if result.Exhausted {
+			return fmt.Errorf("forecast stage exhausted retries: %w", err)
+		}
+		return fmt.Errorf("forecast failed: %w", err)
+	}
+
+	fmt.Printf("\n✓ Forecast written to specs/%s/forecast.yaml\n", specName)
+	return nil
+}
+
+// ExecuteRetro runs a retrospection session for a spec with optional prompt.
+// The agent appends the conventions it discovered to the learnings file,
+// which must still be valid afterwards.
+func (s *StageExecutor) ExecuteRetro(specName string, prompt string) error {
+	s.debugLog("ExecuteRetro called for spec: %s, prompt: %s", specName, prompt)
+
+	command := s.buildCommand("/autospec.retro", prompt)
+	s.printExecuting("/autospec.retro", prompt)
+
+	result, err := s.executor.ExecuteStage(specName, StageRetro, command, ValidateLearnings(s.executor.learningsPath()))
+	if err != nil {
+		if result.Exhausted {
+			return fmt.Errorf("retro stage exhausted retries: %w", err)
+		}
+		return fmt.Errorf("retro failed: %w", err)
+	}
+
+	s.debugLog("ExecuteRetro completed successfully")
+	return nil
+}
+
 // ExecuteChecklist runs the checklist stage with optional prompt.
 // Checklist generates a custom checklist for the current feature.
 func (s *StageExecutor) ExecuteChecklist(specName string, prompt string) error {
@@ -281,8 +425,7 @@ func (s *StageExecutor) ExecuteChecklist(specName string, prompt string) error {
 	command := s.buildCommand("/autospec.checklist", prompt)
 	s.printExecuting("/autospec.checklist", prompt)
 
-	result, err := s.executor.ExecuteStage(specName, StageChecklist, command,
-		func(specDir string) error { return nil })
+	result, err := s.executor.ExecuteStage(specName, StageChecklist, command, ValidateChecklistsSchema)
 	if err != nil {
 		if result.Exhausted {
 			return fmt.Errorf("checklist stage exhausted retries: %w", err)
@@ -311,6 +454,11 @@ func (s *StageExecutor) ExecuteAnalyze(specName string, prompt string) error {
 		return fmt.Errorf("analyze session failed: %w", err)
 	}
 
+	// Interactive stages skip validateFunc, so check the written findings here
+	if err := ValidateAnalysisSchema(filepath.Join(s.specsDir, specName)); err != nil {
+		return fmt.Errorf("analyze output invalid: %w", err)
+	}
+
 	fmt.Printf("\n✓ Analysis session complete for specs/%s/\n", specName)
 	return nil
 }
@@ -318,7 +466,7 @@ func (s *StageExecutor) ExecuteAnalyze(specName string, prompt string) error {
 // buildCommand constructs a command with optional prompt.
 func (s *StageExecutor) buildCommand(baseCmd, prompt string) string {
 	if prompt != "" {
-		return fmt.Sprintf("%s \"%s\"", baseCmd, prompt)
+		return fmt.Sprintf("%s %s", baseCmd, quotePrompt(prompt))
 	}
 	return baseCmd
 }
@@ -326,7 +474,7 @@ func (s *StageExecutor) buildCommand(baseCmd, prompt string) string {
 // printExecuting prints the executing message for a command.
 func (s *StageExecutor) printExecuting(baseCmd, prompt string) {
 	if prompt != "" {
-		fmt.Printf("Executing: %s \"%s\"\n", baseCmd, prompt)
+		fmt.Printf("Executing: %s %s\n", baseCmd, quotePrompt(prompt))
 	} else {
 		fmt.Printf("Executing: %s\n", baseCmd)
 	}
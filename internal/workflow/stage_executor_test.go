@@ -179,7 +179,7 @@ func TestStageExecutor_BuildPlanCommand(t *testing.T) {
 		},
 		"prompt with quotes": {
 			prompt: `test "quoted"`,
-			want:   `/autospec.plan "test "quoted""`,
+			want:   `/autospec.plan "test \"quoted\""`,
 		},
 	}
 
@@ -327,7 +327,7 @@ func TestStageExecutor_BuildTasksCommand(t *testing.T) {
 		},
 		"prompt with quotes": {
 			prompt: `test "quoted"`,
-			want:   `/autospec.tasks "test "quoted""`,
+			want:   `/autospec.tasks "test \"quoted\""`,
 		},
 	}
 
@@ -0,0 +1,157 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ariel-frischer/autospec/internal/fsutil"
+	"github.com/ariel-frischer/autospec/internal/interrupt"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/worktree"
+)
+
+// DefaultStagingDir is where staged stages write their artifacts, relative to
+// the project root (config: artifact_staging).
+var DefaultStagingDir = filepath.Join(".autospec", "staging")
+
+// stagedStages are the stages whose agent writes into a staging copy of the
+// spec directory. Their artifacts are only promoted into the spec directory
+// once they pass validation, so a failed or interrupted stage cannot leave a
+// truncated plan.yaml or tasks.yaml behind.
+var stagedStages = map[Stage]bool{
+	StagePlan:  true,
+	StageTasks: true,
+}
+
+// stageSpecDir returns the directory the stage's agent writes its artifacts
+// to: the staging copy when the stage is staged, else the spec directory.
+func (e *Executor) stageSpecDir(ctx *stageExecutionContext) string {
+	if ctx.stagingDir != "" {
+		return ctx.stagingDir
+	}
+	return filepath.Join(e.SpecsDir, ctx.specName)
+}
+
+// applyStaging copies the spec directory to the staging area and points the
+// stage's agent sessions at the copy (AUTOSPEC_SPECS_DIR and SPECIFY_FEATURE)
+// until the returned function is called. It returns the staging copy of the
+// spec directory, or "" when the stage runs in the spec directory: staging is
+// disabled, the stage is not staged, or its agent runs on a remote host,
+// which only syncs the spec directory. An earlier staging copy of the spec
+// is replaced.
+func (e *Executor) applyStaging(specName string, stage Stage) (dir string, restore func(), err error) {
+	if e.StagingDir == "" || specName == "" || !stagedStages[stage] {
+		return "", func() {}, nil
+	}
+	claude, ok := e.Claude.(*ClaudeExecutor)
+	if !ok {
+		return "", func() {}, nil
+	}
+	if _, remote := e.remoteFor(stage); remote {
+		return "", func() {}, nil
+	}
+
+	root, err := filepath.Abs(e.StagingDir)
+	if err != nil {
+		return "", func() {}, fmt.Errorf("resolving staging directory: %w", err)
+	}
+	dir = filepath.Join(root, specName)
+	if err := os.RemoveAll(dir); err != nil {
+		return "", func() {}, fmt.Errorf("clearing staging directory: %w", err)
+	}
+	if err := worktree.CopyDir(filepath.Join(e.SpecsDir, specName), dir); err != nil {
+		return "", func() {}, fmt.Errorf("staging %s: %w", specName, err)
+	}
+
+	env := make(map[string]string, len(claude.Env)+2)
+	for name, value := range claude.Env {
+		env[name] = value
+	}
+	env["AUTOSPEC_SPECS_DIR"] = root
+	env["SPECIFY_FEATURE"] = specName
+
+	previousEnv := claude.Env
+	claude.Env = env
+	e.debugLog("Staging %s artifacts in %s", stage, dir)
+	return dir, func() { claude.Env = previousEnv }, nil
+}
+
+// finishStaging ends a staged stage. After success the staged files that
+// are new or changed are promoted into the spec directory, each replaced
+// atomically with a backup of the previous version, and the staging copy is
+// removed. After an interruption the staging copy is removed; after a failure
+// it is kept so the agent's output can be inspected. Either way the spec
+// directory is left as it was before the stage.
+func (e *Executor) finishStaging(ctx *stageExecutionContext, stageErr error) error {
+	if ctx.stagingDir == "" {
+		return stageErr
+	}
+	if stageErr != nil {
+		if errors.Is(stageErr, interrupt.ErrInterrupted) {
+			_ = os.RemoveAll(ctx.stagingDir)
+			return stageErr
+		}
+		output.Default().Info("%s was not changed; the %s output is kept in %s",
+			filepath.Join(e.SpecsDir, ctx.specName), ctx.stage, ctx.stagingDir)
+		return stageErr
+	}
+
+	promoted, err := promoteStagedFiles(ctx.stagingDir, filepath.Join(e.SpecsDir, ctx.specName))
+	if err != nil {
+		ctx.result.Success = false
+		ctx.result.Error = fmt.Errorf("promoting %s artifacts (staged copy kept in %s): %w", ctx.stage, ctx.stagingDir, err)
+		return ctx.result.Error
+	}
+	if len(promoted) > 0 {
+		e.debugLog("Promoted staged files: %v", promoted)
+	}
+	if err := os.RemoveAll(ctx.stagingDir); err != nil {
+		e.debugLog("Removing staging directory: %v", err)
+	}
+	return nil
+}
+
+// promoteStagedFiles copies the files in stagingDir that are missing from
+// specDir or differ from it into specDir, and returns their paths relative to
+// specDir. Backups (<file>.bak) are skipped, since promotion writes its own;
+// files the agent deleted from the staging copy are left in place.
+func promoteStagedFiles(stagingDir, specDir string) ([]string, error) {
+	var promoted []string
+	err := filepath.WalkDir(stagingDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if !d.Type().IsRegular() || fsutil.IsBackup(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(stagingDir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(specDir, rel)
+		if current, err := os.ReadFile(target); err == nil && bytes.Equal(current, data) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := fsutil.WriteArtifact(target, data, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("writing %s: %w", target, err)
+		}
+		promoted = append(promoted, rel)
+		return nil
+	})
+	return promoted, err
+}
@@ -0,0 +1,154 @@
+package workflow
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStagingExecutor returns an executor whose plan agent writes content to
+// plan.yaml in the spec directory it is pointed at, as the plan command does.
+func newStagingExecutor(t *testing.T, content string) (*Executor, string) {
+	t.Helper()
+	root := t.TempDir()
+	specsDir := filepath.Join(root, "specs")
+	specDir := filepath.Join(specsDir, "001-test")
+	require.NoError(t, os.MkdirAll(specDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte("spec\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "plan.yaml"), []byte("old plan\n"), 0o644))
+
+	agent, err := cliagent.NewCustomAgentFromConfig(cliagent.CustomAgentConfig{
+		Command: "sh",
+		Args:    []string{"-c", `printf '%s' "$CONTENT" > "$AUTOSPEC_SPECS_DIR/$SPECIFY_FEATURE/plan.yaml"`, "{{PROMPT}}"},
+	})
+	require.NoError(t, err)
+
+	return &Executor{
+		Claude:     &ClaudeExecutor{Agent: agent, Env: map[string]string{"CONTENT": content}},
+		StateDir:   filepath.Join(root, "state"),
+		SpecsDir:   specsDir,
+		StagingDir: filepath.Join(root, ".autospec", "staging"),
+	}, specDir
+}
+
+// validPlan is the validator of the staging tests: plan.yaml must start with "plan:".
+func validPlan(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, "plan.yaml"))
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(string(data), "plan:") {
+		return errors.New("schema validation failed:\n- missing field: plan")
+	}
+	return nil
+}
+
+func TestExecuteStage_StagingPromotesValidArtifacts(t *testing.T) {
+	e, specDir := newStagingExecutor(t, "plan: new\n")
+
+	var validated string
+	_, err := e.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(dir string) error {
+		validated = dir
+		return validPlan(dir)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(e.StagingDir, "001-test"), validated, "validation should read the staging copy")
+	data, err := os.ReadFile(filepath.Join(specDir, "plan.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "plan: new\n", string(data))
+	backup, err := os.ReadFile(filepath.Join(specDir, "plan.yaml.bak"))
+	require.NoError(t, err)
+	assert.Equal(t, "old plan\n", string(backup))
+	assert.NoDirExists(t, validated, "staging copy should be removed")
+	assert.Equal(t, map[string]string{"CONTENT": "plan: new\n"}, e.Claude.(*ClaudeExecutor).Env, "env should be restored")
+}
+
+func TestExecuteStage_StagingKeepsSpecDirOnFailure(t *testing.T) {
+	e, specDir := newStagingExecutor(t, "plan: trunc")
+
+	_, err := e.ExecuteStage("001-test", StagePlan, "/autospec.plan", func(dir string) error {
+		if err := validPlan(dir); err != nil {
+			return err
+		}
+		return errors.New("schema validation failed:\n- truncated")
+	})
+	require.Error(t, err)
+
+	data, err := os.ReadFile(filepath.Join(specDir, "plan.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "old plan\n", string(data), "spec dir should be untouched")
+	assert.NoFileExists(t, filepath.Join(specDir, "plan.yaml.bak"))
+	staged, err := os.ReadFile(filepath.Join(e.StagingDir, "001-test", "plan.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "plan: trunc", string(staged), "failed output should be kept for inspection")
+}
+
+func TestExecutor_ApplyStaging_Skipped(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		specName   string
+		stage      Stage
+		stagingDir string
+		claude     ClaudeRunner
+	}{
+		"disabled":            {specName: "001-test", stage: StagePlan, claude: &ClaudeExecutor{}},
+		"specify":             {stage: StageSpecify, stagingDir: "staging", claude: &ClaudeExecutor{}},
+		"implement":           {specName: "001-test", stage: StageImplement, stagingDir: "staging", claude: &ClaudeExecutor{}},
+		"not an agent runner": {specName: "001-test", stage: StagePlan, stagingDir: "staging", claude: &mockClaudeExecutor{}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e := &Executor{Claude: tt.claude, SpecsDir: t.TempDir(), StagingDir: tt.stagingDir}
+
+			dir, restore, err := e.applyStaging(tt.specName, tt.stage)
+			require.NoError(t, err)
+			restore()
+			assert.Empty(t, dir)
+		})
+	}
+}
+
+func TestPromoteStagedFiles(t *testing.T) {
+	t.Parallel()
+
+	staging, specDir := t.TempDir(), t.TempDir()
+	files := map[string]string{
+		"spec.yaml":        "same\n",
+		"spec.yaml.bak":    "stale backup\n",
+		"plan.yaml":        "new plan\n",
+		"contracts/api.md": "new contract\n",
+	}
+	for rel, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(staging, rel)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(staging, rel), []byte(content), 0o644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "spec.yaml"), []byte("same\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "plan.yaml"), []byte("old plan\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(specDir, "research.md"), []byte("kept\n"), 0o644))
+
+	promoted, err := promoteStagedFiles(staging, specDir)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{filepath.Join("contracts", "api.md"), "plan.yaml"}, promoted)
+
+	for rel, want := range map[string]string{
+		"plan.yaml":        "new plan\n",
+		"plan.yaml.bak":    "old plan\n",
+		"contracts/api.md": "new contract\n",
+		"research.md":      "kept\n",
+	} {
+		data, err := os.ReadFile(filepath.Join(specDir, rel))
+		require.NoError(t, err, rel)
+		assert.Equal(t, want, string(data), rel)
+	}
+	assert.NoFileExists(t, filepath.Join(specDir, "spec.yaml.bak"))
+}
@@ -0,0 +1,164 @@
+// Package workflow provides per-task-type verification commands.
+// After a task's agent session marks it Completed, the command mapped to the
+// task's type (config: task_commands) and the tests selected by the files the
+// task changed (config: targeted_tests) are run; failures mark the task incomplete.
+// Related: internal/workflow/task_executor.go, internal/config/config.go (TaskCommands)
+// Tags: workflow, task-executor, verification, test-commands
+package workflow
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/shell"
+	"github.com/ariel-frischer/autospec/internal/testselect"
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// maxTaskCommandOutputLines caps how many trailing output lines of a failed
+// task command are fed into the retry prompt.
+const maxTaskCommandOutputLines = 8
+
+// TaskCommandRunner executes a shell command and returns its combined output.
+// Injectable for testing; the default runs the command through the configured
+// shell (sh, or cmd on Windows).
+type TaskCommandRunner func(command string) (string, error)
+
+// runShellCommand runs command through the shell in the current working directory.
+func runShellCommand(command string) (string, error) {
+	cmd := shell.Command(command)
+	out, err := cmd.CombinedOutput()
+	return string(out), err
+}
+
+// TaskCommandError reports a failed post-task verification command.
+// Its Error() output uses "- " bullets so ExtractValidationErrors feeds the
+// failing output into the retry prompt.
+type TaskCommandError struct {
+	TaskID   string
+	TaskType string
+	Command  string
+	Output   string
+	Err      error
+}
+
+func (e *TaskCommandError) Error() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "task %s %s command failed:\n", e.TaskID, e.TaskType)
+	fmt.Fprintf(&sb, "- `%s` failed (%v); fix the failures below and mark the task Completed again\n", e.Command, e.Err)
+	for _, line := range tailLines(e.Output, maxTaskCommandOutputLines) {
+		fmt.Fprintf(&sb, "- %s\n", line)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (e *TaskCommandError) Unwrap() error {
+	return e.Err
+}
+
+// tailLines returns up to n trailing non-empty lines of output.
+func tailLines(output string, n int) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if trimmed := strings.TrimRight(line, " \t\r"); trimmed != "" {
+			lines = append(lines, trimmed)
+		}
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines
+}
+
+// runTaskCommand runs the command configured for the task's type, if any.
+// On failure the task is reset to InProgress so it is treated as incomplete,
+// and a *TaskCommandError describing the failure is returned.
+func (te *TaskExecutor) runTaskCommand(tasksPath string, task *validation.TaskItem) error {
+	command, ok := te.taskCommands[task.Type]
+	if !ok || strings.TrimSpace(command) == "" {
+		return nil
+	}
+
+	fmt.Printf("Running %s command for task %s: %s\n", task.Type, task.ID, command)
+	out, err := te.commandRunner(command)
+	te.recordTaskCommand(task, command, err)
+	if err == nil {
+		te.debugLog("task command passed for %s", task.ID)
+		return nil
+	}
+
+	if _, updateErr := validation.UpdateTaskStatus(tasksPath, task.ID, "InProgress"); updateErr != nil {
+		te.debugLog("failed to reset task %s status: %v", task.ID, updateErr)
+	}
+	return &TaskCommandError{
+		TaskID:   task.ID,
+		TaskType: task.Type,
+		Command:  command,
+		Output:   out,
+		Err:      err,
+	}
+}
+
+// runTargetedTests runs the targeted_tests rules matching the files the
+// current task changed, from the repository root. Tasks whose changes match
+// no rule run no tests. Like runTaskCommand, a failure resets the task to
+// InProgress and returns a *TaskCommandError.
+func (te *TaskExecutor) runTargetedTests(tasksPath string, task *validation.TaskItem) error {
+	if len(te.targetedTests) == 0 {
+		return nil
+	}
+	if te.snapshot == nil {
+		te.debugLog("targeted tests skipped for %s: changed files not tracked", task.ID)
+		return nil
+	}
+	files, err := taskChangedFiles(tasksPath, te.snapshot)
+	if err != nil {
+		output.Default().Warn("Targeted tests skipped for task %s: %v", task.ID, err)
+		return nil
+	}
+	selected := testselect.Select(te.targetedTests, files)
+	if len(selected) == 0 {
+		te.debugLog("no targeted tests match the %d file(s) changed by %s", len(files), task.ID)
+		return nil
+	}
+
+	for _, sel := range selected {
+		fmt.Printf("Running %s tests for task %s (%d changed file(s)): %s\n", sel.Rule, task.ID, len(sel.Files), sel.Command)
+		out, err := te.testRunner(te.snapshot.Root, sel.Command)
+		te.recordTaskCommand(task, sel.Command, err)
+		if err == nil {
+			continue
+		}
+		if _, updateErr := validation.UpdateTaskStatus(tasksPath, task.ID, "InProgress"); updateErr != nil {
+			te.debugLog("failed to reset task %s status: %v", task.ID, updateErr)
+		}
+		return &TaskCommandError{
+			TaskID:   task.ID,
+			TaskType: "targeted test",
+			Command:  sel.Command,
+			Output:   out,
+			Err:      err,
+		}
+	}
+	return nil
+}
+
+// recordTaskCommand logs the outcome of a task command to the run event log.
+func (te *TaskExecutor) recordTaskCommand(task *validation.TaskItem, command string, err error) {
+	if te.executor == nil {
+		return
+	}
+	message := "passed"
+	if err != nil {
+		message = fmt.Sprintf("failed: %v", err)
+	}
+	te.executor.Events.Record(events.Event{
+		Type:    events.TypeTaskCommand,
+		Stage:   string(StageImplement),
+		Task:    task.ID,
+		Command: command,
+		Message: message,
+	})
+}
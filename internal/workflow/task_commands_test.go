@@ -0,0 +1,214 @@
+// Package workflow tests per-task-type verification commands.
+// Related: internal/workflow/task_commands.go, internal/workflow/task_executor.go
+// Tags: workflow, task-executor, verification, test-commands
+package workflow
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/testselect"
+	"github.com/ariel-frischer/autospec/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const taskCommandsTasksYAML = `phases:
+  - number: 1
+    title: "Phase 1"
+    tasks:
+      - id: "T001"
+        title: "Write tests"
+        status: "Completed"
+        type: "test"
+      - id: "T002"
+        title: "Write docs"
+        status: "Completed"
+        type: "documentation"
+`
+
+func TestTaskExecutor_ValidateTaskCompleted_TaskCommands(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		taskID         string
+		runnerOutput   string
+		runnerErr      error
+		wantErr        bool
+		wantRan        bool
+		wantStatus     string
+		wantErrContain []string
+	}{
+		"passing command keeps task completed": {
+			taskID:     "T001",
+			wantRan:    true,
+			wantStatus: "Completed",
+		},
+		"failing command marks task incomplete": {
+			taskID:         "T001",
+			runnerOutput:   "ok  pkg/a\n--- FAIL: TestFoo\nFAIL pkg/b\n",
+			runnerErr:      errors.New("exit status 1"),
+			wantErr:        true,
+			wantRan:        true,
+			wantStatus:     "InProgress",
+			wantErrContain: []string{"`go test ./...` failed", "- --- FAIL: TestFoo", "- FAIL pkg/b"},
+		},
+		"unmapped task type is not checked": {
+			taskID:     "T002",
+			runnerErr:  errors.New("should not run"),
+			wantRan:    false,
+			wantStatus: "Completed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			specDir := t.TempDir()
+			tasksPath := filepath.Join(specDir, "tasks.yaml")
+			require.NoError(t, os.WriteFile(tasksPath, []byte(taskCommandsTasksYAML), 0o644))
+
+			ran := false
+			te := NewTaskExecutorWithOptions(&Executor{}, filepath.Dir(specDir), TaskExecutorOptions{
+				TaskCommands: map[string]string{"test": "go test ./..."},
+				CommandRunner: func(command string) (string, error) {
+					ran = true
+					return tt.runnerOutput, tt.runnerErr
+				},
+			})
+
+			err := te.validateTaskCompleted(specDir, tt.taskID)
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+			assert.Equal(t, tt.wantRan, ran)
+			for _, want := range tt.wantErrContain {
+				assert.Contains(t, err.Error(), want)
+			}
+
+			tasks, err := validation.GetAllTasks(tasksPath)
+			require.NoError(t, err)
+			task, err := validation.GetTaskByID(tasks, tt.taskID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, task.Status)
+		})
+	}
+}
+
+func TestTaskCommandError_FeedsRetryContext(t *testing.T) {
+	t.Parallel()
+
+	lines := make([]string, 0, 20)
+	for i := 0; i < 20; i++ {
+		lines = append(lines, "line")
+	}
+	err := &TaskCommandError{
+		TaskID:   "T001",
+		TaskType: "test",
+		Command:  "make test",
+		Output:   strings.Join(lines, "\n"),
+		Err:      errors.New("exit status 2"),
+	}
+
+	extracted := ExtractValidationErrors(err)
+	assert.Len(t, extracted, maxTaskCommandOutputLines+1)
+	assert.Contains(t, extracted[0], "`make test` failed (exit status 2)")
+	assert.ErrorIs(t, err, err.Err)
+}
+
+func TestTailLines(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output string
+		n      int
+		want   []string
+	}{
+		"empty output":       {output: "", n: 3, want: nil},
+		"fewer than n":       {output: "a\nb\n", n: 3, want: []string{"a", "b"}},
+		"trims to last n":    {output: "a\nb\nc\nd", n: 2, want: []string{"c", "d"}},
+		"skips blank lines":  {output: "a\n\n  \nb\n", n: 5, want: []string{"a", "b"}},
+		"strips trailing cr": {output: "a\r\nb\r\n", n: 5, want: []string{"a", "b"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, tailLines(tt.output, tt.n))
+		})
+	}
+}
+
+func TestTaskExecutor_RunTargetedTests(t *testing.T) {
+	tests := map[string]struct {
+		changed        string
+		runnerErr      error
+		wantCommands   []string
+		wantStatus     string
+		wantErrContain string
+	}{
+		"changed package is tested": {
+			changed:      "pkg/a/a.go",
+			wantCommands: []string{"go test './pkg/a'"},
+			wantStatus:   "Completed",
+		},
+		"failing tests mark task incomplete": {
+			changed:        "pkg/a/a.go",
+			runnerErr:      errors.New("exit status 1"),
+			wantCommands:   []string{"go test './pkg/a'"},
+			wantStatus:     "InProgress",
+			wantErrContain: "task T001 targeted test command failed",
+		},
+		"unmatched changes run no tests": {
+			changed:    "README.md",
+			wantStatus: "Completed",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			t.Chdir(dir)
+			for _, args := range [][]string{{"init", "-q"}, {"config", "user.email", "t@example.com"}, {"config", "user.name", "t"}} {
+				out, err := exec.Command("git", args...).CombinedOutput()
+				require.NoError(t, err, "git %v: %s", args, out)
+			}
+			specDir := filepath.Join("specs", "001-x")
+			tasksPath := filepath.Join(specDir, "tasks.yaml")
+			require.NoError(t, os.MkdirAll(specDir, 0o755))
+			require.NoError(t, os.WriteFile(tasksPath, []byte(taskCommandsTasksYAML), 0o644))
+
+			te := NewTaskExecutorWithOptions(&Executor{}, "specs", TaskExecutorOptions{
+				TargetedTests: []testselect.Rule{{Patterns: []string{"*.go"}, Command: "go test {{dirs}}"}},
+			})
+			var commands []string
+			te.testRunner = func(dir, command string) (string, error) {
+				commands = append(commands, command)
+				return "--- FAIL: TestA", tt.runnerErr
+			}
+			te.snapshot = te.snapshotRepository()
+			require.NotNil(t, te.snapshot)
+
+			require.NoError(t, os.MkdirAll(filepath.Dir(tt.changed), 0o755))
+			require.NoError(t, os.WriteFile(tt.changed, []byte("x\n"), 0o644))
+
+			err := te.validateTaskCompleted(specDir, "T001")
+			if tt.wantErrContain != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrContain)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantCommands, commands)
+
+			tasks, err := validation.GetAllTasks(tasksPath)
+			require.NoError(t, err)
+			task, err := validation.GetTaskByID(tasks, "T001")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, task.Status)
+		})
+	}
+}
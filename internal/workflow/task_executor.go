@@ -5,9 +5,23 @@
 package workflow
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
-
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ariel-frischer/autospec/internal/codefmt"
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
+	"github.com/ariel-frischer/autospec/internal/events"
+	"github.com/ariel-frischer/autospec/internal/git"
+	"github.com/ariel-frischer/autospec/internal/output"
+	"github.com/ariel-frischer/autospec/internal/progress"
+	"github.com/ariel-frischer/autospec/internal/statusfile"
+	"github.com/ariel-frischer/autospec/internal/testselect"
 	"github.com/ariel-frischer/autospec/internal/validation"
 )
 
@@ -16,9 +30,37 @@ import (
 // Each task is executed in a separate Claude session with task-specific context,
 // providing fine-grained control over the implementation process.
 type TaskExecutor struct {
-	executor *Executor // Underlying executor for Claude command execution
-	specsDir string    // Base directory for spec storage (e.g., "specs/")
-	debug    bool      // Enable debug logging
+	executor      *Executor         // Underlying executor for Claude command execution
+	specsDir      string            // Base directory for spec storage (e.g., "specs/")
+	debug         bool              // Enable debug logging
+	taskCommands  map[string]string // Task type -> verification command run after each task
+	commandRunner TaskCommandRunner // Runs task commands (injectable for testing)
+	taskTimeout   time.Duration     // Default per-task time limit (0 = none)
+	timedOut      []timedOutTask    // Tasks blocked by their time limit in this run
+	skipped       []string          // Tasks blocked at the rescue prompt in this run
+	targetedTests []testselect.Rule // Tests selected by the files a task changed
+	testRunner    codefmt.Runner    // Runs targeted tests from the repository root
+	snapshot      *git.Snapshot     // Repository before the current task (nil when untracked)
+}
+
+// timedOutTask is a task that was blocked after exceeding its time limit.
+type timedOutTask struct {
+	ID    string
+	Title string
+	Limit time.Duration
+}
+
+// errTaskTimedOut marks a task that ran out of time and was blocked; the task
+// loop moves on to the next task instead of failing the run.
+var errTaskTimedOut = errors.New("task exceeded its time limit")
+
+// TaskExecutorOptions holds optional configuration for TaskExecutor.
+type TaskExecutorOptions struct {
+	Debug         bool              // Enable debug logging
+	TaskCommands  map[string]string // Task type -> verification command (config: task_commands)
+	CommandRunner TaskCommandRunner // Optional runner override; nil uses "sh -c"
+	TaskTimeout   time.Duration     // Default per-task time limit (config: task_timeout)
+	TargetedTests []testselect.Rule // Tests selected by changed files (config: targeted_tests)
 }
 
 // NewTaskExecutor creates a new TaskExecutor with the given dependencies.
@@ -27,19 +69,37 @@ type TaskExecutor struct {
 // debug: optional, enables verbose logging for troubleshooting
 func NewTaskExecutor(executor *Executor, specsDir string, debug bool) *TaskExecutor {
 	return &TaskExecutor{
-		executor: executor,
-		specsDir: specsDir,
-		debug:    debug,
+		executor:      executor,
+		specsDir:      specsDir,
+		debug:         debug,
+		commandRunner: runShellCommand,
+		testRunner:    codefmt.RunShell,
 	}
 }
 
-// debugLog prints a debug message if debug mode is enabled.
-func (te *TaskExecutor) debugLog(format string, args ...interface{}) {
-	if te.debug {
-		fmt.Printf("[DEBUG][TaskExecutor] "+format+"\n", args...)
+// NewTaskExecutorWithOptions creates a TaskExecutor with additional options.
+func NewTaskExecutorWithOptions(executor *Executor, specsDir string, opts TaskExecutorOptions) *TaskExecutor {
+	runner := opts.CommandRunner
+	if runner == nil {
+		runner = runShellCommand
+	}
+	return &TaskExecutor{
+		executor:      executor,
+		specsDir:      specsDir,
+		debug:         opts.Debug,
+		taskCommands:  opts.TaskCommands,
+		commandRunner: runner,
+		taskTimeout:   opts.TaskTimeout,
+		targetedTests: opts.TargetedTests,
+		testRunner:    codefmt.RunShell,
 	}
 }
 
+// debugLog writes a debug record tagged with the task_executor component.
+func (te *TaskExecutor) debugLog(format string, args ...interface{}) {
+	te.executor.logger("task_executor").Debug(fmt.Sprintf(format, args...))
+}
+
 // ExecuteTaskLoop iterates through tasks from startIdx to end.
 // Each task runs in a separate Claude session for isolation.
 // specName: the spec directory name
@@ -51,6 +111,14 @@ func (te *TaskExecutor) debugLog(format string, args ...interface{}) {
 func (te *TaskExecutor) ExecuteTaskLoop(specName, tasksPath string, orderedTasks []validation.TaskItem, startIdx, totalTasks int, prompt string) error {
 	te.debugLog("ExecuteTaskLoop called: spec=%s, startIdx=%d, totalTasks=%d", specName, startIdx, totalTasks)
 	specDir := filepath.Join(te.specsDir, specName)
+	status := te.statusWriter()
+	completed := countCompletedTasks(orderedTasks)
+	bar := newRunProgress("task", totalTasks, completed)
+	status.HoldStage()
+	if te.executor != nil {
+		defer te.executor.watchStage(StageImplement)()
+	}
+	defer te.executor.listenForPause("task")()
 
 	for i := startIdx; i < len(orderedTasks); i++ {
 		task := orderedTasks[i]
@@ -59,58 +127,320 @@ func (te *TaskExecutor) ExecuteTaskLoop(specName, tasksPath string, orderedTasks
 		if shouldSkipTask(task, i, totalTasks) {
 			continue
 		}
+		if err := te.executor.waitIfPaused("task " + task.ID); err != nil {
+			return fmt.Errorf("waiting to start task %s: %w", task.ID, err)
+		}
 
 		fmt.Printf("[Task %d/%d] %s - %s\n", i+1, totalTasks, task.ID, task.Title)
+		bar.Start(fmt.Sprintf("%s - %s", task.ID, task.Title))
+		status.StartTask(task.ID, task.Title, completed, totalTasks)
+		if te.executor != nil {
+			te.executor.setCurrentTask(task.ID, task.Title)
+		}
 
 		// Execute and verify task
-		if err := te.executeAndVerifyTask(specName, tasksPath, task, prompt); err != nil {
+		elapsed, err := te.executeAndVerifyTask(specName, tasksPath, task, prompt)
+		te.executor.syncProject(specDir)
+		if errors.Is(err, errTaskTimedOut) || errors.Is(err, errTaskSkipped) {
+			output.Default().Blank()
+			continue
+		}
+		if err != nil {
 			return fmt.Errorf("executing task %s: %w", task.ID, err)
 		}
 
-		fmt.Printf("✓ Task %s complete\n\n", task.ID)
+		completed++
+		bar.Complete()
+		status.CompleteTask()
+		ui := output.Default()
+		ui.Success("Task %s complete%s", task.ID, taskTiming(elapsed, task.Estimate()))
+		ui.Blank()
 	}
 
+	status.ReleaseStage(string(StageImplement))
+	if te.executor != nil {
+		te.executor.setCurrentTask("", "")
+	}
 	te.printTasksSummary(tasksPath, specDir)
 	return nil
 }
 
-// ExecuteSingleTask runs a specific task by ID.
+// newRunProgress creates the progress renderer for a multi-session implement
+// run, honoring the output level and --no-color.
+func newRunProgress(unit string, total, done int) *progress.RunProgress {
+	ui := output.Default()
+	caps := progress.DetectTerminalCapabilities()
+	caps.SupportsColor = caps.SupportsColor && ui.ColorEnabled()
+	var w io.Writer = os.Stdout
+	if !ui.Enabled(output.LevelNormal) {
+		w = io.Discard
+	}
+	return progress.NewRunProgress(w, caps, unit, total, done)
+}
+
+// statusWriter returns the status file writer of the underlying executor, if any.
+func (te *TaskExecutor) statusWriter() *statusfile.Writer {
+	if te.executor == nil {
+		return nil
+	}
+	return te.executor.Status
+}
+
+// countCompletedTasks returns how many tasks are already marked Completed.
+func countCompletedTasks(tasks []validation.TaskItem) int {
+	count := 0
+	for _, task := range tasks {
+		if task.Status == "Completed" || task.Status == "completed" {
+			count++
+		}
+	}
+	return count
+}
+
+// ExecuteSingleTask runs a specific task by ID in a fresh Claude session and
+// verifies it was completed, recording its time and changed files like the
+// task loop does.
 // specName: the spec directory name
 // taskID: task identifier (e.g., "T001")
 // taskTitle: human-readable task title for display
 // prompt: optional custom prompt
 func (te *TaskExecutor) ExecuteSingleTask(specName, taskID, taskTitle, prompt string) error {
 	te.debugLog("ExecuteSingleTask called: spec=%s, taskID=%s", specName, taskID)
-	return te.executeSingleTaskSession(specName, taskID, taskTitle, prompt)
+	tasksPath := validation.GetTasksFilePath(filepath.Join(te.specsDir, specName))
+	tasks, err := validation.GetAllTasks(tasksPath)
+	if err != nil {
+		return fmt.Errorf("failed to get tasks: %w", err)
+	}
+	task, err := validation.GetTaskByID(tasks, taskID)
+	if err != nil {
+		return err
+	}
+
+	if te.executor != nil {
+		te.executor.setCurrentTask(task.ID, taskTitle)
+		defer te.executor.setCurrentTask("", "")
+	}
+	_, err = te.executeAndVerifyTask(specName, tasksPath, *task, prompt)
+	return err
 }
 
 // executeAndVerifyTask executes a single task and verifies completion.
-func (te *TaskExecutor) executeAndVerifyTask(specName, tasksPath string, task validation.TaskItem, prompt string) error {
+// Returns the time spent in the task's agent session, which is also added
+// to the task's actual_duration.
+func (te *TaskExecutor) executeAndVerifyTask(specName, tasksPath string, task validation.TaskItem, prompt string) (time.Duration, error) {
 	// Validate dependencies before executing
 	freshTasks, err := validation.GetAllTasks(tasksPath)
 	if err != nil {
-		return fmt.Errorf("failed to refresh tasks: %w", err)
+		return 0, fmt.Errorf("failed to refresh tasks: %w", err)
 	}
 
 	met, unmetDeps := validation.ValidateTaskDependenciesMet(task, freshTasks)
 	if !met {
 		fmt.Printf("⚠ Skipping task %s: dependencies not met (%v)\n", task.ID, unmetDeps)
-		return nil
+		return 0, nil
+	}
+
+	limit := te.timeLimit(task)
+	var deadline time.Time
+	if limit > 0 {
+		deadline = time.Now().Add(limit)
 	}
 
 	// Execute this task in a fresh Claude session
-	if err := te.executeSingleTaskSession(specName, task.ID, task.Title, prompt); err != nil {
-		return fmt.Errorf("task %s failed: %w", task.ID, err)
+	restore := te.limitSessions(deadline)
+	snapshot := te.snapshotRepository()
+	start := time.Now()
+	te.snapshot = snapshot
+	err = te.executeSingleTaskSession(specName, task.ID, task.Title, prompt)
+	te.snapshot = nil
+	elapsed := time.Since(start)
+	restore()
+	te.recordActualDuration(tasksPath, task.ID, elapsed)
+	te.recordChangedFiles(tasksPath, task.ID, snapshot)
+	if err != nil {
+		if limit > 0 && errors.Is(err, apperrors.ErrAgentTimeout) && !time.Now().Before(deadline) {
+			return elapsed, te.blockTimedOutTask(tasksPath, task, limit)
+		}
+		if errors.Is(err, errTaskSkipped) {
+			return elapsed, te.blockSkippedTask(tasksPath, task)
+		}
+		return elapsed, fmt.Errorf("task %s failed: %w", task.ID, err)
 	}
 
 	// Verify task completion
-	return te.verifyTaskCompletion(tasksPath, task.ID)
+	return elapsed, te.verifyTaskCompletion(tasksPath, task.ID)
+}
+
+// recordActualDuration adds the time spent on a task to its actual_duration,
+// including failed and timed-out attempts, since that time was spent too.
+func (te *TaskExecutor) recordActualDuration(tasksPath, taskID string, elapsed time.Duration) {
+	total, err := validation.AddTaskActualDuration(tasksPath, taskID, elapsed)
+	if err != nil {
+		output.Default().Warn("Could not record actual_duration for task %s: %v", taskID, err)
+		return
+	}
+	te.debugLog("task %s: %s this run, actual_duration now %s", taskID, elapsed, total)
+}
+
+// snapshotRepository records the repository before a task's sessions so the
+// files they change can be listed afterwards. Returns nil outside a git
+// repository.
+func (te *TaskExecutor) snapshotRepository() *git.Snapshot {
+	snapshot, err := git.TakeSnapshot()
+	if err != nil {
+		te.debugLog("changed_files not tracked: %v", err)
+		return nil
+	}
+	return snapshot
+}
+
+// recordChangedFiles adds the files changed since snapshot to the task's
+// changed_files, including those of failed attempts. Files in the spec
+// directory, such as tasks.yaml itself, are left out.
+func (te *TaskExecutor) recordChangedFiles(tasksPath, taskID string, snapshot *git.Snapshot) {
+	if snapshot == nil {
+		return
+	}
+	files, err := taskChangedFiles(tasksPath, snapshot)
+	if err != nil {
+		output.Default().Warn("Could not list files changed by task %s: %v", taskID, err)
+		return
+	}
+	all, err := validation.AddTaskChangedFiles(tasksPath, taskID, files)
+	if err != nil {
+		output.Default().Warn("Could not record changed_files for task %s: %v", taskID, err)
+		return
+	}
+	te.debugLog("task %s: %d file(s) changed this run, %d recorded", taskID, len(files), len(all))
+}
+
+// taskChangedFiles returns the files changed since snapshot, leaving out the
+// spec directory containing tasksPath.
+func taskChangedFiles(tasksPath string, snapshot *git.Snapshot) ([]string, error) {
+	changed, err := snapshot.ChangedSince()
+	if err != nil {
+		return nil, err
+	}
+	specDir := repoRelativeDir(snapshot.Root, filepath.Dir(tasksPath))
+	var files []string
+	for _, f := range changed {
+		if specDir == "" || (f != specDir && !strings.HasPrefix(f, specDir+"/")) {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// repoRelativeDir returns dir relative to the repository root, slash
+// separated, or "" when it is the root or outside the repository.
+func repoRelativeDir(root, dir string) string {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// taskTiming formats a task's run time for its completion message, with
+// the estimate when there is one: " (12m3s, estimated 10m0s)".
+func taskTiming(elapsed, estimate time.Duration) string {
+	if elapsed <= 0 {
+		return ""
+	}
+	if estimate > 0 {
+		return fmt.Sprintf(" (%s, estimated %s)", elapsed.Round(time.Second), estimate)
+	}
+	return fmt.Sprintf(" (%s)", elapsed.Round(time.Second))
+}
+
+// timeLimit returns the time limit for a task: its max_duration if set,
+// otherwise the configured task_timeout.
+func (te *TaskExecutor) timeLimit(task validation.TaskItem) time.Duration {
+	limit, err := task.TimeLimit()
+	if err != nil {
+		output.Default().Warn("%v; using task_timeout", err)
+	}
+	if limit > 0 {
+		return limit
+	}
+	return te.taskTimeout
+}
+
+// limitSessions makes agent sessions stop at deadline until the returned
+// function is called. A zero deadline, or a runner other than ClaudeExecutor,
+// leaves sessions unlimited.
+func (te *TaskExecutor) limitSessions(deadline time.Time) (restore func()) {
+	if deadline.IsZero() || te.executor == nil {
+		return func() {}
+	}
+	claude, ok := te.executor.Claude.(*ClaudeExecutor)
+	if !ok {
+		return func() {}
+	}
+	previous := claude.Deadline
+	claude.Deadline = deadline
+	return func() { claude.Deadline = previous }
+}
+
+// blockTimedOutTask marks a task that exceeded its time limit as Blocked and
+// remembers it for the run summary.
+func (te *TaskExecutor) blockTimedOutTask(tasksPath string, task validation.TaskItem, limit time.Duration) error {
+	reason := fmt.Sprintf("timed out after %s (time limit); needs manual follow-up", limit)
+	if _, err := validation.BlockTask(tasksPath, task.ID, reason); err != nil {
+		return fmt.Errorf("blocking timed-out task %s: %w", task.ID, err)
+	}
+	te.timedOut = append(te.timedOut, timedOutTask{ID: task.ID, Title: task.Title, Limit: limit})
+	if te.executor != nil {
+		te.executor.Events.Record(events.Event{
+			Type:    events.TypeTaskTimedOut,
+			Stage:   string(StageImplement),
+			Task:    task.ID,
+			Message: reason,
+		})
+	}
+	if te.failFast() {
+		output.Default().Warn("Task %s exceeded its %s time limit and was marked Blocked; stopping (--fail-fast)", task.ID, limit)
+		return apperrors.ErrAgentTimeout.Errorf("task %s exceeded its %s time limit", task.ID, limit)
+	}
+	output.Default().Warn("Task %s exceeded its %s time limit and was marked Blocked; continuing with the next task", task.ID, limit)
+	return errTaskTimedOut
+}
+
+// failFast reports whether the run stops at the first task failure instead
+// of blocking the task and moving on.
+func (te *TaskExecutor) failFast() bool {
+	return te.executor != nil && te.executor.FailFast
+}
+
+// blockSkippedTask marks a task skipped at the rescue prompt as Blocked, so
+// tasks depending on it wait too, and remembers it for the run summary.
+func (te *TaskExecutor) blockSkippedTask(tasksPath string, task validation.TaskItem) error {
+	if _, err := validation.BlockTask(tasksPath, task.ID, "skipped after its retries were exhausted; needs manual follow-up"); err != nil {
+		return fmt.Errorf("blocking skipped task %s: %w", task.ID, err)
+	}
+	te.skipped = append(te.skipped, task.ID)
+	output.Default().Warn("Task %s was marked Blocked; continuing with the next task", task.ID)
+	return errTaskSkipped
 }
 
 // executeSingleTaskSession executes a single task in a fresh Claude session.
 func (te *TaskExecutor) executeSingleTaskSession(specName, taskID, taskTitle, prompt string) error {
 	te.debugLog("executeSingleTaskSession: taskID=%s, taskTitle=%s", taskID, taskTitle)
 
+	prompt, err := te.executor.renderPrompt(PromptImplement, PromptTemplateData{SpecName: specName, TaskID: taskID, Prompt: prompt})
+	if err != nil {
+		return err
+	}
 	command := te.buildTaskCommand(taskID, prompt)
 	fmt.Printf("Executing: %s\n", command)
 
@@ -120,13 +450,14 @@ func (te *TaskExecutor) executeSingleTaskSession(specName, taskID, taskTitle, pr
 // buildTaskCommand constructs the implement command with task filter.
 func (te *TaskExecutor) buildTaskCommand(taskID, prompt string) string {
 	if prompt != "" {
-		return fmt.Sprintf("/autospec.implement --task %s \"%s\"", taskID, prompt)
+		return fmt.Sprintf("/autospec.implement --task %s %s", taskID, quotePrompt(prompt))
 	}
 	return fmt.Sprintf("/autospec.implement --task %s", taskID)
 }
 
 // executeTaskWithValidation executes the task command with validation.
 func (te *TaskExecutor) executeTaskWithValidation(specName, taskID, command string) error {
+	defer te.executor.scopeSessions(taskID, 0)()
 	result, err := te.executor.ExecuteStage(
 		specName,
 		StageImplement,
@@ -148,7 +479,9 @@ func (te *TaskExecutor) executeTaskWithValidation(specName, taskID, command stri
 	return nil
 }
 
-// validateTaskCompleted checks if a specific task is completed.
+// validateTaskCompleted checks if a specific task is completed and, when a
+// command is configured for the task's type, that the command passes, as do
+// the targeted tests selected by the files the task changed.
 func (te *TaskExecutor) validateTaskCompleted(specDir, taskID string) error {
 	tasksPath := validation.GetTasksFilePath(specDir)
 	allTasks, err := validation.GetAllTasks(tasksPath)
@@ -164,7 +497,10 @@ func (te *TaskExecutor) validateTaskCompleted(specDir, taskID string) error {
 	if task.Status != "Completed" && task.Status != "completed" {
 		return fmt.Errorf("task %s not completed (status: %s)", taskID, task.Status)
 	}
-	return nil
+	if err := te.runTaskCommand(tasksPath, task); err != nil {
+		return err
+	}
+	return te.runTargetedTests(tasksPath, task)
 }
 
 // verifyTaskCompletion checks that a task completed successfully.
@@ -213,22 +549,17 @@ func (te *TaskExecutor) findTaskStartIndex(orderedTasks, allTasks []validation.T
 		return 0, nil
 	}
 
-	// Find task index
-	startIdx := -1
-	for i, task := range orderedTasks {
-		if task.ID == fromTask {
-			startIdx = i
-			break
-		}
+	taskIDs := make([]string, len(orderedTasks))
+	for i, t := range orderedTasks {
+		taskIDs[i] = t.ID
 	}
 
-	if startIdx == -1 {
-		taskIDs := make([]string, len(orderedTasks))
-		for i, t := range orderedTasks {
-			taskIDs[i] = t.ID
-		}
-		return 0, fmt.Errorf("task %s not found in tasks.yaml (available: %v)", fromTask, taskIDs)
+	// Resolve short references ("4" -> "T004") and find the task index
+	fromTask, err := validation.ResolveTaskID(taskIDs, fromTask)
+	if err != nil {
+		return 0, fmt.Errorf("%w (available: %v)", err, taskIDs)
 	}
+	startIdx := slices.Index(taskIDs, fromTask)
 
 	// Validate that fromTask's dependencies are met
 	fromTaskItem, _ := validation.GetTaskByID(allTasks, fromTask)
@@ -242,27 +573,56 @@ func (te *TaskExecutor) findTaskStartIndex(orderedTasks, allTasks []validation.T
 
 // printTasksSummary prints the final task execution summary and marks spec as completed.
 func (te *TaskExecutor) printTasksSummary(tasksPath, specDir string) {
-	fmt.Println("✓ All tasks processed!")
-	fmt.Println()
-	stats, statsErr := validation.GetTaskStats(tasksPath)
-	if statsErr == nil && stats.TotalTasks > 0 {
-		fmt.Println("Task Summary:")
-		fmt.Print(validation.FormatTaskSummary(stats))
+	printCompletionSummary("All tasks processed!", tasksPath)
+	if len(te.timedOut) > 0 || len(te.skipped) > 0 {
+		te.printTimedOutTasks()
+		te.printSkippedTasks()
+		return
 	}
 
 	// Mark spec as completed
-	markSpecCompletedAndPrint(specDir)
+	te.executor.completeSpec(specDir)
+}
+
+// printTimedOutTasks lists the tasks blocked by their time limit so they can
+// be followed up by hand. The spec is not marked completed while they remain.
+func (te *TaskExecutor) printTimedOutTasks() {
+	if len(te.timedOut) == 0 {
+		return
+	}
+	ui := output.Default()
+	ui.Blank()
+	ui.Warn("%d task(s) exceeded their time limit and were blocked:", len(te.timedOut))
+	for _, task := range te.timedOut {
+		ui.Info("  %s - %s (limit %s)", task.ID, task.Title, task.Limit)
+	}
+	first := te.timedOut[0].ID
+	ui.Info("Follow up manually, then run 'autospec task unblock %s' and 'autospec implement --tasks --from-task %s'", first, first)
+}
+
+// printSkippedTasks lists the tasks skipped at the rescue prompt. The spec is
+// not marked completed while they remain blocked.
+func (te *TaskExecutor) printSkippedTasks() {
+	if len(te.skipped) == 0 {
+		return
+	}
+	ui := output.Default()
+	ui.Blank()
+	ui.Warn("%d task(s) were skipped and blocked: %s", len(te.skipped), strings.Join(te.skipped, ", "))
+	first := te.skipped[0]
+	ui.Info("Follow up manually, then run 'autospec task unblock %s' and 'autospec implement --tasks --from-task %s'", first, first)
 }
 
 // shouldSkipTask checks if a task should be skipped and prints appropriate message.
 // This is a package-level function used by both TaskExecutor and WorkflowOrchestrator.
 func shouldSkipTask(task validation.TaskItem, idx, totalTasks int) bool {
+	ui := output.Default()
 	if task.Status == "Completed" || task.Status == "completed" {
-		fmt.Printf("✓ Task %d/%d: %s - %s (already completed)\n", idx+1, totalTasks, task.ID, task.Title)
+		ui.Success("Task %d/%d: %s - %s (already completed)", idx+1, totalTasks, task.ID, task.Title)
 		return true
 	}
 	if task.Status == "Blocked" || task.Status == "blocked" {
-		fmt.Printf("⚠ Task %d/%d: %s - %s (blocked)\n", idx+1, totalTasks, task.ID, task.Title)
+		ui.Warn("Task %d/%d: %s - %s (blocked)", idx+1, totalTasks, task.ID, task.Title)
 		return true
 	}
 	return false
@@ -4,10 +4,15 @@
 package workflow
 
 import (
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	apperrors "github.com/ariel-frischer/autospec/internal/errors"
 	"github.com/ariel-frischer/autospec/internal/validation"
 )
 
@@ -328,6 +333,19 @@ func TestTaskExecutor_FindTaskStartIndex(t *testing.T) {
 			wantIdx:  1,
 			wantErr:  false,
 		},
+		"bare number resolves to task ID": {
+			orderedTasks: []validation.TaskItem{
+				{ID: "T001", Status: "Completed"},
+				{ID: "T002", Status: "Pending"},
+			},
+			allTasks: []validation.TaskItem{
+				{ID: "T001", Status: "Completed"},
+				{ID: "T002", Status: "Pending", Dependencies: []string{"T001"}},
+			},
+			fromTask: "2",
+			wantIdx:  1,
+			wantErr:  false,
+		},
 	}
 
 	for name, tt := range tests {
@@ -565,3 +583,246 @@ phases:
 		})
 	}
 }
+
+// TestTaskExecutor_ExecuteTaskLoop_TimedOutTask tests that a task exceeding its
+// time limit is blocked and the loop moves on instead of failing the run.
+func TestTaskExecutor_ExecuteTaskLoop_TimedOutTask(t *testing.T) {
+	tempDir := t.TempDir()
+	specsDir := filepath.Join(tempDir, "specs")
+	specDir := filepath.Join(specsDir, "001-test")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatalf("failed to create spec dir: %v", err)
+	}
+	tasksPath := filepath.Join(specDir, "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: "Phase 1"
+    tasks:
+      - id: "T001"
+        title: "Slow task"
+        status: "Pending"
+        type: "implementation"
+        max_duration: "1ns"
+      - id: "T002"
+        title: "Done task"
+        status: "Completed"
+        type: "implementation"
+`
+	if err := os.WriteFile(tasksPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write tasks.yaml: %v", err)
+	}
+
+	executor := &Executor{
+		Claude:   &mockClaudeExecutor{executeErr: NewTimeoutError(time.Nanosecond, "claude")},
+		StateDir: t.TempDir(),
+		SpecsDir: specsDir,
+	}
+	te := NewTaskExecutorWithOptions(executor, specsDir, TaskExecutorOptions{TaskTimeout: time.Hour})
+
+	tasks, err := validation.GetAllTasks(tasksPath)
+	if err != nil {
+		t.Fatalf("GetAllTasks() error = %v", err)
+	}
+	if err := te.ExecuteTaskLoop("001-test", tasksPath, tasks, 0, len(tasks), ""); err != nil {
+		t.Fatalf("ExecuteTaskLoop() error = %v, want timed-out task to be skipped", err)
+	}
+
+	tasks, _ = validation.GetAllTasks(tasksPath)
+	task, _ := validation.GetTaskByID(tasks, "T001")
+	if task.Status != "Blocked" {
+		t.Errorf("T001 status = %q, want Blocked", task.Status)
+	}
+	if !strings.Contains(task.BlockedReason, "timed out after 1ns") {
+		t.Errorf("T001 blocked_reason = %q, want timeout reason", task.BlockedReason)
+	}
+	if len(te.timedOut) != 1 || te.timedOut[0].ID != "T001" {
+		t.Errorf("timedOut = %+v, want T001", te.timedOut)
+	}
+}
+
+// TestTaskExecutor_ExecuteTaskLoop_TimedOutTaskFailFast tests that with
+// --fail-fast a timed-out task is still blocked but stops the loop.
+func TestTaskExecutor_ExecuteTaskLoop_TimedOutTaskFailFast(t *testing.T) {
+	tempDir := t.TempDir()
+	specsDir := filepath.Join(tempDir, "specs")
+	specDir := filepath.Join(specsDir, "001-test")
+	if err := os.MkdirAll(specDir, 0o755); err != nil {
+		t.Fatalf("failed to create spec dir: %v", err)
+	}
+	tasksPath := filepath.Join(specDir, "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: "Phase 1"
+    tasks:
+      - id: "T001"
+        title: "Slow task"
+        status: "Pending"
+        type: "implementation"
+        max_duration: "1ns"
+      - id: "T002"
+        title: "Next task"
+        status: "Pending"
+        type: "implementation"
+`
+	if err := os.WriteFile(tasksPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write tasks.yaml: %v", err)
+	}
+
+	claude := &mockClaudeExecutor{executeErr: NewTimeoutError(time.Nanosecond, "claude")}
+	executor := &Executor{
+		Claude:   claude,
+		StateDir: t.TempDir(),
+		SpecsDir: specsDir,
+		FailFast: true,
+	}
+	te := NewTaskExecutorWithOptions(executor, specsDir, TaskExecutorOptions{TaskTimeout: time.Hour})
+
+	tasks, err := validation.GetAllTasks(tasksPath)
+	if err != nil {
+		t.Fatalf("GetAllTasks() error = %v", err)
+	}
+	err = te.ExecuteTaskLoop("001-test", tasksPath, tasks, 0, len(tasks), "")
+	if !errors.Is(err, apperrors.ErrAgentTimeout) {
+		t.Fatalf("ExecuteTaskLoop() error = %v, want an agent timeout", err)
+	}
+
+	tasks, _ = validation.GetAllTasks(tasksPath)
+	task, _ := validation.GetTaskByID(tasks, "T001")
+	if task.Status != "Blocked" {
+		t.Errorf("T001 status = %q, want Blocked", task.Status)
+	}
+	for _, call := range claude.executeCalls {
+		if strings.Contains(call, "T002") {
+			t.Errorf("T002 ran after T001 timed out with --fail-fast: %q", call)
+		}
+	}
+}
+
+// TestTaskExecutor_RecordActualDuration tests that session time accumulates
+// in the task's actual_duration.
+func TestTaskExecutor_RecordActualDuration(t *testing.T) {
+	t.Parallel()
+
+	tasksPath := filepath.Join(t.TempDir(), "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: "Phase 1"
+    tasks:
+      - id: "T001"
+        title: "Task"
+        status: "Pending"
+        type: "implementation"
+        estimated_duration: "10m"
+`
+	if err := os.WriteFile(tasksPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write tasks.yaml: %v", err)
+	}
+
+	te := NewTaskExecutor(&Executor{}, "specs", false)
+	te.recordActualDuration(tasksPath, "T001", 4*time.Minute)
+	te.recordActualDuration(tasksPath, "T001", 90*time.Second)
+
+	tasks, _ := validation.GetAllTasks(tasksPath)
+	task, _ := validation.GetTaskByID(tasks, "T001")
+	if task.ActualDuration != "5m30s" {
+		t.Errorf("actual_duration = %q, want 5m30s", task.ActualDuration)
+	}
+	if task.EstimatedDuration != "10m" {
+		t.Errorf("estimated_duration = %q, want it preserved", task.EstimatedDuration)
+	}
+}
+
+func TestTaskExecutor_RecordChangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	for _, args := range [][]string{{"init", "-q"}, {"config", "user.email", "t@example.com"}, {"config", "user.name", "t"}} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+	tasksPath := filepath.Join("specs", "001-x", "tasks.yaml")
+	content := `phases:
+  - number: 1
+    title: "Phase 1"
+    tasks:
+      - id: "T001"
+        title: "Task"
+        status: "Pending"
+        type: "implementation"
+`
+	if err := os.MkdirAll(filepath.Dir(tasksPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tasksPath, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write tasks.yaml: %v", err)
+	}
+
+	te := NewTaskExecutor(&Executor{}, "specs", false)
+	snapshot := te.snapshotRepository()
+	if snapshot == nil {
+		t.Fatal("expected a snapshot inside a git repository")
+	}
+	// The session writes code and updates its own task status
+	if err := os.WriteFile("main.go", []byte("package main\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := validation.UpdateTaskStatus(tasksPath, "T001", "Completed"); err != nil {
+		t.Fatal(err)
+	}
+	te.recordChangedFiles(tasksPath, "T001", snapshot)
+
+	tasks, _ := validation.GetAllTasks(tasksPath)
+	task, _ := validation.GetTaskByID(tasks, "T001")
+	if strings.Join(task.ChangedFiles, ",") != "main.go" {
+		t.Errorf("changed_files = %v, want [main.go] (spec files excluded)", task.ChangedFiles)
+	}
+}
+
+// TestTaskTiming tests the timing suffix of task completion messages.
+func TestTaskTiming(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		elapsed  time.Duration
+		estimate time.Duration
+		want     string
+	}{
+		"not run":       {want: ""},
+		"no estimate":   {elapsed: 95 * time.Second, want: " (1m35s)"},
+		"with estimate": {elapsed: 12*time.Minute + 3400*time.Millisecond, estimate: 10 * time.Minute, want: " (12m3s, estimated 10m0s)"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if got := taskTiming(tt.elapsed, tt.estimate); got != tt.want {
+				t.Errorf("taskTiming() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestTaskExecutor_TimeLimit tests that max_duration overrides task_timeout.
+func TestTaskExecutor_TimeLimit(t *testing.T) {
+	t.Parallel()
+
+	te := NewTaskExecutorWithOptions(&Executor{}, "specs", TaskExecutorOptions{TaskTimeout: time.Hour})
+	tests := map[string]struct {
+		maxDuration string
+		want        time.Duration
+	}{
+		"task limit wins":      {maxDuration: "20m", want: 20 * time.Minute},
+		"config default":       {maxDuration: "", want: time.Hour},
+		"invalid uses default": {maxDuration: "soon", want: time.Hour},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got := te.timeLimit(validation.TaskItem{ID: "T001", MaxDuration: tt.maxDuration})
+			if got != tt.want {
+				t.Errorf("timeLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
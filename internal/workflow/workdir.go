@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/spec"
+)
+
+// agentWorkDir returns the directory a stage's agent runs in, or "" for the
+// current directory (the project root). For implement, the spec's
+// feature.workdir in spec.yaml takes precedence over the workdir config entry
+// of the stage. Since spec files travel with branches and pull requests,
+// feature.workdir is resolved against the project root and must stay inside
+// it, symlinks included. The directory must exist. Safe to call on a nil
+// executor.
+func (e *Executor) agentWorkDir(specName string, stage Stage) (string, error) {
+	if e == nil {
+		return "", nil
+	}
+	dir, source := e.WorkDirs[string(stage)], "workdir."+string(stage)+" in config"
+	fromSpec := false
+	if stage == StageImplement && specName != "" {
+		specDir := filepath.Join(e.SpecsDir, specName)
+		if wd := spec.ReadFeature(specDir).WorkDir; wd != "" {
+			dir, source, fromSpec = wd, "feature.workdir in "+filepath.Join(specDir, "spec.yaml"), true
+		}
+	}
+	if dir == "" {
+		return "", nil
+	}
+
+	root := e.ProjectRoot
+	if root == "" {
+		root, _ = os.Getwd()
+	}
+	if fromSpec && !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving workdir %s (%s): %w", dir, source, err)
+	}
+	info, err := os.Stat(abs)
+	switch {
+	case os.IsNotExist(err):
+		return "", fmt.Errorf("workdir %s (%s) does not exist", dir, source)
+	case err != nil:
+		return "", fmt.Errorf("checking workdir %s (%s): %w", dir, source, err)
+	case !info.IsDir():
+		return "", fmt.Errorf("workdir %s (%s) is not a directory", dir, source)
+	}
+	if fromSpec && !insideDir(root, abs) {
+		return "", fmt.Errorf("workdir %s (%s) is outside the project %s", dir, source, root)
+	}
+	return abs, nil
+}
+
+// insideDir reports whether path is root or below it once symlinks in both
+// are resolved.
+func insideDir(root, path string) bool {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return false
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(realRoot, realPath)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// applyWorkDir makes the stage's agent sessions run in its workdir until the
+// returned function is called. Since autospec commands the agent runs there
+// cannot find the project's specs directory, its absolute path and layout are
+// passed as AUTOSPEC_SPECS_DIR and AUTOSPEC_SPECS_LAYOUT unless already set.
+// Only ClaudeExecutor runners take a working directory.
+func (e *Executor) applyWorkDir(specName string, stage Stage) (restore func(), err error) {
+	claude, ok := e.Claude.(*ClaudeExecutor)
+	if !ok {
+		return func() {}, nil
+	}
+	dir, err := e.agentWorkDir(specName, stage)
+	if err != nil || dir == "" {
+		return func() {}, err
+	}
+
+	env := make(map[string]string, len(claude.Env)+2)
+	for name, value := range claude.Env {
+		env[name] = value
+	}
+	if specsDir, err := filepath.Abs(e.SpecsDir); err == nil && env["AUTOSPEC_SPECS_DIR"] == "" {
+		env["AUTOSPEC_SPECS_DIR"] = specsDir
+	}
+	if env["AUTOSPEC_SPECS_LAYOUT"] == "" {
+		env["AUTOSPEC_SPECS_LAYOUT"] = spec.Locator().Layout()
+	}
+
+	previousDir, previousEnv := claude.WorkDir, claude.Env
+	claude.WorkDir, claude.Env = dir, env
+	e.debugLog("Running %s agent in %s", stage, dir)
+	return func() { claude.WorkDir, claude.Env = previousDir, previousEnv }, nil
+}
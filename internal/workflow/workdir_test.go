@@ -0,0 +1,162 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/cliagent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecutor_AgentWorkDir(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	specsDir := filepath.Join(root, "specs")
+	for _, dir := range []string{"services/api", "web", "specs/001-api", "specs/002-web"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(specsDir, "002-web", "spec.yaml"),
+		[]byte("feature:\n  branch: 002-web\n  workdir: "+filepath.Join(root, "web")+"\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "file.txt"), nil, 0o644))
+	outside := t.TempDir()
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "escape")))
+	for name, workdir := range map[string]string{
+		"003-relative": "services/api",
+		"004-parent":   "../" + filepath.Base(outside),
+		"005-absolute": outside,
+		"006-symlink":  "escape",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(specsDir, name), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(specsDir, name, "spec.yaml"),
+			[]byte("feature:\n  workdir: "+workdir+"\n"), 0o644))
+	}
+
+	tests := map[string]struct {
+		workDirs  map[string]string
+		specName  string
+		stage     Stage
+		want      string
+		wantErrIn string
+	}{
+		"not configured": {
+			specName: "001-api",
+			stage:    StageImplement,
+		},
+		"stage entry": {
+			workDirs: map[string]string{"implement": filepath.Join(root, "services/api")},
+			specName: "001-api",
+			stage:    StageImplement,
+			want:     filepath.Join(root, "services/api"),
+		},
+		"other stage unaffected": {
+			workDirs: map[string]string{"implement": filepath.Join(root, "services/api")},
+			specName: "001-api",
+			stage:    StagePlan,
+		},
+		"spec overrides config": {
+			workDirs: map[string]string{"implement": filepath.Join(root, "services/api")},
+			specName: "002-web",
+			stage:    StageImplement,
+			want:     filepath.Join(root, "web"),
+		},
+		"spec workdir only for implement": {
+			specName: "002-web",
+			stage:    StageTasks,
+		},
+		"spec workdir relative to project root": {
+			specName: "003-relative",
+			stage:    StageImplement,
+			want:     filepath.Join(root, "services/api"),
+		},
+		"spec workdir above project root": {
+			specName:  "004-parent",
+			stage:     StageImplement,
+			wantErrIn: "is outside the project",
+		},
+		"absolute spec workdir outside project": {
+			specName:  "005-absolute",
+			stage:     StageImplement,
+			wantErrIn: "is outside the project",
+		},
+		"spec workdir symlinked outside project": {
+			specName:  "006-symlink",
+			stage:     StageImplement,
+			wantErrIn: "is outside the project",
+		},
+		"config workdir may be anywhere": {
+			workDirs: map[string]string{"plan": outside},
+			stage:    StagePlan,
+			want:     outside,
+		},
+		"missing directory": {
+			workDirs:  map[string]string{"plan": filepath.Join(root, "nope")},
+			stage:     StagePlan,
+			wantErrIn: "(workdir.plan in config) does not exist",
+		},
+		"not a directory": {
+			workDirs:  map[string]string{"plan": filepath.Join(root, "file.txt")},
+			stage:     StagePlan,
+			wantErrIn: "is not a directory",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			e := &Executor{SpecsDir: specsDir, WorkDirs: tt.workDirs, ProjectRoot: root}
+
+			got, err := e.agentWorkDir(tt.specName, tt.stage)
+			if tt.wantErrIn != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErrIn)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExecutor_AgentWorkDir_SpecSource(t *testing.T) {
+	t.Parallel()
+
+	specsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(specsDir, "003-x"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(specsDir, "003-x", "spec.yaml"),
+		[]byte("feature:\n  workdir: /does/not/exist\n"), 0o644))
+
+	_, err := (&Executor{SpecsDir: specsDir}).agentWorkDir("003-x", StageImplement)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "feature.workdir in "+filepath.Join(specsDir, "003-x", "spec.yaml"))
+}
+
+func TestExecutor_ApplyWorkDir(t *testing.T) {
+	t.Parallel()
+
+	workDir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "pwd.txt")
+	agent, err := cliagent.NewCustomAgentFromConfig(cliagent.CustomAgentConfig{
+		Command: "sh",
+		Args:    []string{"-c", `printf '%s\n%s' "$(pwd -P)" "$AUTOSPEC_SPECS_DIR" > "$OUT"`, "{{PROMPT}}"},
+	})
+	require.NoError(t, err)
+
+	claude := &ClaudeExecutor{Agent: agent, Env: map[string]string{"OUT": out}}
+	e := &Executor{Claude: claude, SpecsDir: "/project/specs", WorkDirs: map[string]string{"implement": workDir}}
+
+	restore, err := e.applyWorkDir("", StageImplement)
+	require.NoError(t, err)
+	require.NoError(t, claude.Execute("ignored"))
+	restore()
+
+	data, err := os.ReadFile(out)
+	require.NoError(t, err)
+	resolved, err := filepath.EvalSymlinks(workDir)
+	require.NoError(t, err)
+	assert.Equal(t, resolved+"\n/project/specs", string(data))
+	assert.Empty(t, claude.WorkDir, "workdir should be restored")
+	assert.Equal(t, map[string]string{"OUT": out}, claude.Env, "env should be restored")
+}
@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+// DefaultWorkflowType is the workflow type used when none is selected.
+const DefaultWorkflowType = "feature"
+
+// WorkflowType describes a kind of work and how autospec runs it: which
+// stages 'autospec run --all' selects, which stages never run, and what the
+// specify stage writes and validates.
+type WorkflowType struct {
+	Name        string
+	Description string
+
+	// Stages are the stages --all selects, in canonical order.
+	Stages []Stage
+	// Excluded stages are dropped from every run of this type, even when
+	// selected by a flag, --only or a profile.
+	Excluded []Stage
+
+	// SpecifyCommand is the slash command the specify stage runs.
+	SpecifyCommand string
+	// Artifact is the file the specify stage writes in the spec directory.
+	Artifact string
+	// ArtifactType selects the schema Artifact is validated against.
+	ArtifactType validation.ArtifactType
+}
+
+// workflowTypes is the workflow registry, keyed by name.
+var workflowTypes = map[string]WorkflowType{}
+
+func init() {
+	core := []Stage{StageSpecify, StagePlan, StageTasks, StageImplement}
+	RegisterWorkflowType(WorkflowType{
+		Name:           DefaultWorkflowType,
+		Description:    "New functionality: full spec with user stories",
+		Stages:         core,
+		SpecifyCommand: "/autospec.specify",
+		Artifact:       "spec.yaml",
+		ArtifactType:   validation.ArtifactTypeSpec,
+	})
+	RegisterWorkflowType(WorkflowType{
+		Name:           "bugfix",
+		Description:    "Fix a defect: lighter spec describing the bug, no clarify",
+		Stages:         core,
+		Excluded:       []Stage{StageClarify},
+		SpecifyCommand: "/autospec.bugfix",
+		Artifact:       "spec.yaml",
+		ArtifactType:   validation.ArtifactTypeSpec,
+	})
+	RegisterWorkflowType(WorkflowType{
+		Name:           "refactor",
+		Description:    "Restructure code without changing behavior: spec without user stories",
+		Stages:         core,
+		SpecifyCommand: "/autospec.refactor",
+		Artifact:       "spec.yaml",
+		ArtifactType:   validation.ArtifactTypeSpec,
+	})
+	RegisterWorkflowType(WorkflowType{
+		Name:           "spike",
+		Description:    "Time-boxed investigation: research.yaml only, no code changes",
+		Stages:         []Stage{StageSpecify},
+		Excluded:       []Stage{StageClarify, StagePlan, StageTasks, StageChecklist, StageAnalyze, StageImplement},
+		SpecifyCommand: "/autospec.spike",
+		Artifact:       "research.yaml",
+		ArtifactType:   validation.ArtifactTypeResearch,
+	})
+}
+
+// RegisterWorkflowType adds t to the registry, replacing a type of the same name.
+func RegisterWorkflowType(t WorkflowType) {
+	workflowTypes[t.Name] = t
+}
+
+// LookupWorkflowType returns the registered workflow type with the given
+// name (case-insensitive). An empty name selects DefaultWorkflowType.
+func LookupWorkflowType(name string) (WorkflowType, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = DefaultWorkflowType
+	}
+	t, ok := workflowTypes[name]
+	if !ok {
+		return WorkflowType{}, fmt.Errorf("unknown workflow type %q (valid: %s)", name, strings.Join(WorkflowTypeNames(), ", "))
+	}
+	return t, nil
+}
+
+// WorkflowTypeNames returns the registered workflow type names, sorted.
+func WorkflowTypeNames() []string {
+	names := make([]string, 0, len(workflowTypes))
+	for name := range workflowTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Excludes reports whether the type never runs stage.
+func (t WorkflowType) Excludes(stage Stage) bool {
+	return slices.Contains(t.Excluded, stage)
+}
+
+// SelectAll selects the type's stages in sc, as --all does.
+func (t WorkflowType) SelectAll(sc *StageConfig) {
+	for _, stage := range t.Stages {
+		sc.SetStage(stage, true)
+	}
+}
+
+// Restrict deselects the type's excluded stages in sc and returns the ones
+// that were selected, in canonical order.
+func (t WorkflowType) Restrict(sc *StageConfig) []Stage {
+	var dropped []Stage
+	for _, stage := range sc.GetCanonicalOrder() {
+		if t.Excludes(stage) {
+			sc.SetStage(stage, false)
+			dropped = append(dropped, stage)
+		}
+	}
+	return dropped
+}
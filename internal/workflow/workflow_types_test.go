@@ -0,0 +1,150 @@
+// Package workflow tests the workflow type registry and its stage restrictions.
+// Related: internal/workflow/workflow_types.go
+// Tags: workflow, types, bugfix, refactor, spike, stages
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ariel-frischer/autospec/internal/validation"
+)
+
+func TestLookupWorkflowType(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		name        string
+		wantName    string
+		wantCommand string
+		wantErr     string
+	}{
+		"empty is feature":  {name: "", wantName: "feature", wantCommand: "/autospec.specify"},
+		"bugfix":            {name: "bugfix", wantName: "bugfix", wantCommand: "/autospec.bugfix"},
+		"case insensitive":  {name: " Spike ", wantName: "spike", wantCommand: "/autospec.spike"},
+		"unknown type":      {name: "epic", wantErr: `unknown workflow type "epic" (valid: bugfix, feature, refactor, spike)`},
+		"refactor registry": {name: "refactor", wantName: "refactor", wantCommand: "/autospec.refactor"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			got, err := LookupWorkflowType(tt.name)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Name != tt.wantName || got.SpecifyCommand != tt.wantCommand {
+				t.Errorf("got %s (%s), want %s (%s)", got.Name, got.SpecifyCommand, tt.wantName, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func TestWorkflowType_SelectAllAndRestrict(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		typeName    string
+		extra       []Stage // selected by flags or a profile
+		wantStages  []Stage
+		wantDropped []Stage
+	}{
+		"feature keeps clarify": {
+			typeName:   "feature",
+			extra:      []Stage{StageClarify},
+			wantStages: []Stage{StageSpecify, StageClarify, StagePlan, StageTasks, StageImplement},
+		},
+		"bugfix drops clarify": {
+			typeName:    "bugfix",
+			extra:       []Stage{StageClarify, StageAnalyze},
+			wantStages:  []Stage{StageSpecify, StagePlan, StageTasks, StageAnalyze, StageImplement},
+			wantDropped: []Stage{StageClarify},
+		},
+		"spike runs specify only": {
+			typeName:    "spike",
+			extra:       []Stage{StageConstitution, StagePlan},
+			wantStages:  []Stage{StageConstitution, StageSpecify},
+			wantDropped: []Stage{StagePlan},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			wfType, err := LookupWorkflowType(tt.typeName)
+			if err != nil {
+				t.Fatalf("lookup: %v", err)
+			}
+			sc := NewStageConfig()
+			wfType.SelectAll(sc)
+			for _, stage := range tt.extra {
+				sc.SetStage(stage, true)
+			}
+
+			dropped := wfType.Restrict(sc)
+			if !reflect.DeepEqual(dropped, tt.wantDropped) {
+				t.Errorf("dropped = %v, want %v", dropped, tt.wantDropped)
+			}
+			if got := sc.GetCanonicalOrder(); !reflect.DeepEqual(got, tt.wantStages) {
+				t.Errorf("stages = %v, want %v", got, tt.wantStages)
+			}
+		})
+	}
+}
+
+func TestMakeArtifactSchemaValidatorWithDetection(t *testing.T) {
+	t.Parallel()
+
+	valid, err := os.ReadFile(filepath.Join("..", "validation", "testdata", "research", "valid.yaml"))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+
+	tests := map[string]struct {
+		content     string
+		errContains string
+	}{
+		"valid research": {content: string(valid)},
+		"missing findings": {
+			content:     "research:\n  branch: \"001-spike\"\n  created: \"2025-01-01\"\n  question: \"Which queue?\"\nrecommendation:\n  summary: \"Use SQLite\"\n",
+			errContains: "schema validation failed for research.yaml",
+		},
+		"missing file": {errContains: "research.yaml"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			specsDir := filepath.Join(t.TempDir(), "specs")
+			specDir := filepath.Join(specsDir, "001-spike-queue")
+			if err := os.MkdirAll(specDir, 0o755); err != nil {
+				t.Fatalf("creating spec dir: %v", err)
+			}
+			if tt.content != "" {
+				if err := os.WriteFile(filepath.Join(specDir, "research.yaml"), []byte(tt.content), 0o644); err != nil {
+					t.Fatalf("writing research.yaml: %v", err)
+				}
+			}
+
+			err := MakeArtifactSchemaValidatorWithDetection(specsDir, "research.yaml", validation.ArtifactTypeResearch)("")
+			if tt.errContains == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("error = %v, want containing %q", err, tt.errContains)
+			}
+		})
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"runtime"
+
+	"github.com/ariel-frischer/autospec/internal/shell"
 )
 
 // SetupResult contains the result of running a setup script.
@@ -20,7 +22,8 @@ type SetupResult struct {
 }
 
 // RunSetupScript executes a setup script with the given parameters.
-// The script receives:
+// PowerShell (.ps1) scripts run through PowerShell and, on Windows, .sh
+// scripts through sh; other scripts are started directly. The script receives:
 //   - Arguments: worktreePath, worktreeName, branchName
 //   - Environment: WORKTREE_PATH, WORKTREE_NAME, WORKTREE_BRANCH, SOURCE_REPO
 //
@@ -47,15 +50,15 @@ func RunSetupScript(scriptPath, worktreePath, worktreeName, branchName, sourceRe
 		return result
 	}
 
-	// Check if script is executable
-	if info.Mode()&0o111 == 0 {
+	// Check if script is executable (Windows has no executable bit)
+	if runtime.GOOS != "windows" && !shell.Interpreted(scriptPath) && info.Mode()&0o111 == 0 {
 		result.Error = fmt.Errorf("setup script is not executable: %s", scriptPath)
 		return result
 	}
 
 	result.Executed = true
 
-	cmd := exec.Command(scriptPath, worktreePath, worktreeName, branchName)
+	cmd := shell.Script(scriptPath, worktreePath, worktreeName, branchName)
 	cmd.Dir = worktreePath
 	cmd.Env = buildSetupEnv(worktreePath, worktreeName, branchName, sourceRepo)
 
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/ariel-frischer/autospec/internal/fsutil"
 	"gopkg.in/yaml.v3"
 )
 
@@ -40,7 +41,7 @@ func LoadState(stateDir string) (*WorktreeState, error) {
 }
 
 // SaveState writes the worktree state to the state directory atomically.
-// Uses temp file + rename pattern for crash safety.
+// Uses fsutil.WriteFile for crash safety.
 func SaveState(stateDir string, state *WorktreeState) error {
 	if err := os.MkdirAll(stateDir, 0o755); err != nil {
 		return fmt.Errorf("creating state directory: %w", err)
@@ -54,15 +55,8 @@ func SaveState(stateDir string, state *WorktreeState) error {
 	}
 
 	statePath := filepath.Join(stateDir, StateFileName)
-	tmpPath := statePath + ".tmp"
-
-	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
-		return fmt.Errorf("writing temp state file: %w", err)
-	}
-
-	if err := os.Rename(tmpPath, statePath); err != nil {
-		os.Remove(tmpPath) // Best effort cleanup
-		return fmt.Errorf("renaming temp state file: %w", err)
+	if err := fsutil.WriteFile(statePath, data, 0o644); err != nil {
+		return fmt.Errorf("writing state file: %w", err)
 	}
 
 	return nil
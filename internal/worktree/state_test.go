@@ -94,9 +94,9 @@ func TestSaveState_AtomicWrite(t *testing.T) {
 	require.NoError(t, err)
 
 	// Verify temp file doesn't exist
-	tmpFile := filepath.Join(tempDir, StateFileName+".tmp")
-	_, err = os.Stat(tmpFile)
-	assert.True(t, os.IsNotExist(err))
+	tmpFiles, err := filepath.Glob(filepath.Join(tempDir, "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, tmpFiles)
 
 	// Verify state file exists
 	stateFile := filepath.Join(tempDir, StateFileName)
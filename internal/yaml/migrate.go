@@ -2,6 +2,7 @@ package yaml
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -439,6 +440,18 @@ func extractPhases(text string) []map[string]interface{} {
 	return phases
 }
 
+// isMarkdownView reports whether path was rendered by RenderMarkdown.
+func isMarkdownView(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	head := make([]byte, len(MarkdownViewMarker))
+	n, _ := io.ReadFull(f, head)
+	return string(head[:n]) == MarkdownViewMarker
+}
+
 // MigrateDirectory migrates all markdown files in a directory to YAML.
 func MigrateDirectory(dir string) ([]string, []error) {
 	var migrated []string
@@ -466,6 +479,10 @@ func MigrateDirectory(dir string) ([]string, []error) {
 		}
 
 		mdPath := filepath.Join(dir, name)
+		// Markdown views were rendered from YAML that already exists
+		if isMarkdownView(mdPath) {
+			continue
+		}
 		yamlPath, err := MigrateFile(mdPath)
 		if err != nil {
 			errors = append(errors, fmt.Errorf("%s: %w", base, err))
@@ -672,6 +672,22 @@ Test plan.
 	assert.NoError(t, err)
 }
 
+// TestMigrateDirectory_SkipsMarkdownViews tests that rendered views are not
+// migrated back into the YAML they came from
+func TestMigrateDirectory_SkipsMarkdownViews(t *testing.T) {
+	t.Parallel()
+	tmpDir := t.TempDir()
+
+	view, err := RenderMarkdown([]byte("feature:\n  branch: \"001-test\"\n"), "spec", "spec.yaml")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "spec.md"), view, 0o644))
+
+	migrated, errs := MigrateDirectory(tmpDir)
+
+	assert.Empty(t, errs)
+	assert.Empty(t, migrated)
+}
+
 // TestMigrateDirectory_NonExistent tests migrating a non-existent directory
 func TestMigrateDirectory_NonExistent(t *testing.T) {
 	t.Parallel()
@@ -0,0 +1,329 @@
+package yaml
+
+import (
+	"fmt"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// MarkdownViewMarker starts every rendered markdown view. Files that do not
+// start with it were written by hand and are never overwritten implicitly.
+const MarkdownViewMarker = "<!-- Generated by autospec from "
+
+// markdownTitles are the document titles of rendered markdown views.
+var markdownTitles = map[string]string{
+	"spec":         "Feature Specification",
+	"plan":         "Implementation Plan",
+	"tasks":        "Tasks",
+	"checklist":    "Checklist",
+	"analysis":     "Analysis",
+	"constitution": "Constitution",
+}
+
+// markdownSubjects are the fields appended to the title, e.g.
+// "Feature Specification: 001-login".
+var markdownSubjects = map[string][2]string{
+	"spec":         {"feature", "branch"},
+	"plan":         {"plan", "branch"},
+	"tasks":        {"tasks", "branch"},
+	"checklist":    {"checklist", "feature"},
+	"constitution": {"constitution", "project_name"},
+}
+
+// headingIDKeys and headingTitleKeys name the fields that make up the heading
+// of a list item, e.g. "### US-001: User can log in".
+var (
+	headingIDKeys    = []string{"id", "number"}
+	headingTitleKeys = []string{"title", "name"}
+)
+
+// RenderMarkdown renders a YAML artifact as a read-only markdown view.
+// The YAML stays the source of truth: sections follow the document's key
+// order and every field is kept, so the view needs no changes when a schema
+// gains fields. Output is deterministic, which lets callers detect a stale
+// view by rendering again and comparing. sourceName is the YAML file name
+// shown in the header comment.
+func RenderMarkdown(content []byte, artifactType, sourceName string) ([]byte, error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", sourceName, err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yamlv3.MappingNode {
+		return nil, fmt.Errorf("%s: expected a mapping at the top level", sourceName)
+	}
+	root := doc.Content[0]
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s%s; edit the YAML and run 'autospec render'. -->\n\n", MarkdownViewMarker, sourceName)
+	sb.WriteString("# " + markdownTitle(root, artifactType) + "\n")
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, value := root.Content[i].Value, root.Content[i+1]
+		if key == "_meta" || isEmptyNode(value) {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n## %s\n\n", humanizeKey(key))
+		writeSection(&sb, key, value, 3)
+	}
+	return []byte(sb.String()), nil
+}
+
+// markdownTitle returns the document title, with the artifact's subject when
+// it has one.
+func markdownTitle(root *yamlv3.Node, artifactType string) string {
+	title, ok := markdownTitles[artifactType]
+	if !ok {
+		title = humanizeKey(artifactType)
+	}
+	if path, ok := markdownSubjects[artifactType]; ok {
+		if section := mappingValue(root, path[0]); section != nil {
+			if subject := mappingValue(section, path[1]); subject != nil && subject.Value != "" {
+				return title + ": " + subject.Value
+			}
+		}
+	}
+	return title
+}
+
+// writeSection renders the value of a top-level key or of a list item field
+// that gets its own headings.
+func writeSection(sb *strings.Builder, key string, node *yamlv3.Node, level int) {
+	switch node.Kind {
+	case yamlv3.ScalarNode:
+		sb.WriteString(strings.TrimRight(node.Value, "\n") + "\n")
+	case yamlv3.MappingNode:
+		writeFields(sb, node, "", nil)
+	case yamlv3.SequenceNode:
+		if !hasHeadings(node) {
+			writeList(sb, node, "")
+			return
+		}
+		for i, item := range node.Content {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			fmt.Fprintf(sb, "%s %s\n", strings.Repeat("#", level), itemHeading(key, item))
+			writeItem(sb, item, level)
+		}
+	default:
+		writeList(sb, &yamlv3.Node{Kind: yamlv3.SequenceNode, Content: []*yamlv3.Node{node}}, "")
+	}
+}
+
+// writeItem renders the fields of a list item below its heading. Nested lists
+// of titled items (the tasks of a phase) become subheadings while headings
+// are still available; everything else is a field list.
+func writeItem(sb *strings.Builder, item *yamlv3.Node, level int) {
+	var nested []int
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		value := item.Content[i+1]
+		if level < 6 && value.Kind == yamlv3.SequenceNode && hasHeadings(value) {
+			nested = append(nested, i)
+		}
+	}
+
+	skip := headingKeys(item)
+	for _, i := range nested {
+		skip[item.Content[i].Value] = true
+	}
+	var fields strings.Builder
+	writeFields(&fields, item, "", skip)
+	if fields.Len() > 0 {
+		sb.WriteString("\n" + fields.String())
+	}
+
+	for _, i := range nested {
+		sb.WriteString("\n")
+		writeSection(sb, item.Content[i].Value, item.Content[i+1], level+1)
+	}
+}
+
+// writeFields renders a mapping as a bullet list of "**Label**: value" lines,
+// skipping empty values and the keys in skip.
+func writeFields(sb *strings.Builder, node *yamlv3.Node, indent string, skip map[string]bool) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if skip[key] || isEmptyNode(value) {
+			continue
+		}
+		label := "**" + humanizeKey(key) + "**"
+		switch value.Kind {
+		case yamlv3.ScalarNode, yamlv3.AliasNode:
+			fmt.Fprintf(sb, "%s- %s: %s\n", indent, label, indentLines(scalarText(value), indent+"  "))
+		case yamlv3.MappingNode:
+			fmt.Fprintf(sb, "%s- %s:\n", indent, label)
+			writeFields(sb, value, indent+"  ", nil)
+		case yamlv3.SequenceNode:
+			if words, ok := inlineWords(value); ok {
+				fmt.Fprintf(sb, "%s- %s: %s\n", indent, label, words)
+				continue
+			}
+			fmt.Fprintf(sb, "%s- %s:\n", indent, label)
+			writeList(sb, value, indent+"  ")
+		}
+	}
+}
+
+// inlineWords joins a list of single words, such as task IDs in
+// dependencies, so it fits on the label's line.
+func inlineWords(node *yamlv3.Node) (string, bool) {
+	words := make([]string, 0, len(node.Content))
+	for _, item := range node.Content {
+		if item.Kind != yamlv3.ScalarNode || item.Value == "" || strings.ContainsAny(item.Value, " \t\n,") {
+			return "", false
+		}
+		words = append(words, item.Value)
+	}
+	return strings.Join(words, ", "), true
+}
+
+// writeList renders a sequence as bullets. Mapping items are shown on one
+// line as "**Label** value" pairs, e.g. "**Given** x, **When** y, **Then** z",
+// led by their id when they have one, with any nested collections listed
+// below.
+func writeList(sb *strings.Builder, node *yamlv3.Node, indent string) {
+	for _, item := range node.Content {
+		switch item.Kind {
+		case yamlv3.MappingNode:
+			var id, description string
+			var pairs []string
+			rest := map[string]bool{}
+			for i := 0; i+1 < len(item.Content); i += 2 {
+				key, value := item.Content[i].Value, item.Content[i+1]
+				if value.Kind != yamlv3.ScalarNode {
+					continue
+				}
+				rest[key] = true
+				switch {
+				case isEmptyNode(value):
+				case key == "id":
+					id = "**" + value.Value + "**: "
+				case key == "description" && description == "" && firstValue(item, []string{"id"}) != "":
+					description = scalarText(value)
+				default:
+					pairs = append(pairs, "**"+humanizeKey(key)+"** "+scalarText(value))
+				}
+			}
+			// Items with an id read "**FR-001**: description, **Label** value"
+			if description != "" {
+				pairs = append([]string{description}, pairs...)
+			}
+			line := id + strings.Join(pairs, ", ")
+			fmt.Fprintf(sb, "%s- %s\n", indent, indentLines(line, indent+"  "))
+			writeFields(sb, item, indent+"  ", rest)
+		case yamlv3.SequenceNode:
+			fmt.Fprintf(sb, "%s-\n", indent)
+			writeList(sb, item, indent+"  ")
+		default:
+			fmt.Fprintf(sb, "%s- %s\n", indent, indentLines(scalarText(item), indent+"  "))
+		}
+	}
+}
+
+// hasHeadings reports whether a sequence holds mappings with a title or name,
+// which are rendered as headed subsections rather than bullets.
+func hasHeadings(node *yamlv3.Node) bool {
+	if len(node.Content) == 0 {
+		return false
+	}
+	for _, item := range node.Content {
+		if item.Kind != yamlv3.MappingNode || firstValue(item, headingTitleKeys) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// itemHeading builds a list item heading from its id and title. Numbered
+// items are prefixed with the singular of their list, e.g. "Phase 2: Core".
+func itemHeading(listKey string, item *yamlv3.Node) string {
+	title := firstValue(item, headingTitleKeys)
+	id := firstValue(item, headingIDKeys)
+	if id == "" {
+		return title
+	}
+	if mappingValue(item, "id") == nil {
+		id = strings.TrimSuffix(humanizeKey(listKey), "s") + " " + id
+	}
+	return id + ": " + title
+}
+
+// headingKeys returns the keys already shown in an item's heading.
+func headingKeys(item *yamlv3.Node) map[string]bool {
+	keys := map[string]bool{}
+	for _, group := range [][]string{headingIDKeys, headingTitleKeys} {
+		for _, key := range group {
+			if v := mappingValue(item, key); v != nil && v.Value != "" {
+				keys[key] = true
+				break
+			}
+		}
+	}
+	return keys
+}
+
+// firstValue returns the first non-empty scalar among keys.
+func firstValue(node *yamlv3.Node, keys []string) string {
+	for _, key := range keys {
+		if v := mappingValue(node, key); v != nil && v.Kind == yamlv3.ScalarNode && v.Value != "" {
+			return v.Value
+		}
+	}
+	return ""
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(node *yamlv3.Node, key string) *yamlv3.Node {
+	if node.Kind != yamlv3.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// isEmptyNode reports whether a value has nothing to show: null, an empty
+// string, or an empty collection.
+func isEmptyNode(node *yamlv3.Node) bool {
+	switch node.Kind {
+	case yamlv3.ScalarNode:
+		return node.Tag == "!!null" || node.Value == ""
+	case yamlv3.MappingNode, yamlv3.SequenceNode:
+		return len(node.Content) == 0
+	}
+	return false
+}
+
+// scalarText returns a scalar's text; booleans read as yes/no.
+func scalarText(node *yamlv3.Node) string {
+	if node.Kind == yamlv3.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	if node.Tag == "!!bool" {
+		if node.Value == "true" {
+			return "yes"
+		}
+		return "no"
+	}
+	return strings.TrimRight(node.Value, "\n")
+}
+
+// indentLines indents the continuation lines of a multi-line value so it
+// stays inside its list item.
+func indentLines(s, indent string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// humanizeKey turns a YAML key into a label: "why_this_priority" becomes
+// "Why this priority".
+func humanizeKey(key string) string {
+	label := strings.ReplaceAll(key, "_", " ")
+	if label == "" {
+		return label
+	}
+	return strings.ToUpper(label[:1]) + label[1:]
+}
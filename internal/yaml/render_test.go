@@ -0,0 +1,106 @@
+// Package yaml_test tests rendering YAML artifacts as read-only markdown views.
+// Related: internal/yaml/render.go
+// Tags: yaml, markdown, render, views
+package yaml
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const renderSpecYAML = `feature:
+  branch: "001-login"
+  status: "Draft"
+  input: "Add login"
+user_stories:
+  - id: "US-001"
+    title: "User can log in"
+    priority: "P1"
+    as_a: "registered user"
+    why_this_priority: "Core flow"
+    acceptance_scenarios:
+      - given: "an account"
+        when: "I log in"
+        then: "I see the dashboard"
+requirements:
+  functional:
+    - id: "FR-001"
+      description: "MUST support email login"
+      testable: true
+assumptions:
+  - "Email service exists"
+out_of_scope: []
+_meta:
+  artifact_type: "spec"
+`
+
+const renderTasksYAML = `tasks:
+  branch: "001-login"
+phases:
+  - number: 1
+    title: "Setup"
+    purpose: |
+      Create the models.
+      Add the migration.
+    tasks:
+      - id: "T001"
+        title: "Create user model"
+        status: "Completed"
+        story_id: null
+        dependencies: ["T000", "T003"]
+_meta:
+  artifact_type: "tasks"
+`
+
+func TestRenderMarkdown_Spec(t *testing.T) {
+	out, err := RenderMarkdown([]byte(renderSpecYAML), "spec", "spec.yaml")
+	require.NoError(t, err)
+	md := string(out)
+
+	assert.True(t, strings.HasPrefix(md, MarkdownViewMarker+"spec.yaml"), "view starts with the marker")
+	assert.Contains(t, md, "# Feature Specification: 001-login\n")
+	assert.Contains(t, md, "## User stories\n\n### US-001: User can log in\n\n- **Priority**: P1\n")
+	assert.Contains(t, md, "- **Why this priority**: Core flow\n")
+	assert.Contains(t, md, "  - **Given** an account, **When** I log in, **Then** I see the dashboard\n")
+	assert.Contains(t, md, "  - **FR-001**: MUST support email login, **Testable** yes\n")
+	assert.Contains(t, md, "## Assumptions\n\n- Email service exists\n")
+	assert.NotContains(t, md, "Out of scope", "empty sections are omitted")
+	assert.NotContains(t, md, "artifact_type", "_meta is omitted")
+}
+
+func TestRenderMarkdown_NestedHeadings(t *testing.T) {
+	out, err := RenderMarkdown([]byte(renderTasksYAML), "tasks", "tasks.yaml")
+	require.NoError(t, err)
+	md := string(out)
+
+	assert.Contains(t, md, "### Phase 1: Setup\n")
+	assert.Contains(t, md, "- **Purpose**: Create the models.\n  Add the migration.\n")
+	assert.Contains(t, md, "#### T001: Create user model\n\n- **Status**: Completed\n")
+	assert.Contains(t, md, "- **Dependencies**: T000, T003\n")
+	assert.NotContains(t, md, "Story id", "null fields are omitted")
+}
+
+func TestRenderMarkdown_Deterministic(t *testing.T) {
+	first, err := RenderMarkdown([]byte(renderSpecYAML), "spec", "spec.yaml")
+	require.NoError(t, err)
+	second, err := RenderMarkdown([]byte(renderSpecYAML), "spec", "spec.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestRenderMarkdown_Errors(t *testing.T) {
+	tests := map[string]string{
+		"invalid yaml":   "feature: [unclosed",
+		"empty document": "",
+		"top-level list": "- a\n- b\n",
+	}
+	for name, input := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := RenderMarkdown([]byte(input), "spec", "spec.yaml")
+			assert.Error(t, err)
+		})
+	}
+}
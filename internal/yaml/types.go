@@ -17,6 +17,7 @@ type Feature struct {
 	Status      string `yaml:"status"`
 	CompletedAt string `yaml:"completed_at,omitempty"`
 	Input       string `yaml:"input,omitempty"`
+	InputSource string `yaml:"input_source,omitempty"` // Issue URL input was imported from
 }
 
 // UserStory represents a user story in a spec artifact.
@@ -220,11 +221,15 @@ type AnalysisInfo struct {
 
 // Finding represents an analysis finding.
 type Finding struct {
+	ID             string `yaml:"id"`
 	Category       string `yaml:"category"`
 	Severity       string `yaml:"severity"`
-	Description    string `yaml:"description"`
-	Location       string `yaml:"location"`
-	Recommendation string `yaml:"recommendation,omitempty"`
+	Artifact       string `yaml:"artifact"`
+	Field          string `yaml:"field,omitempty"`
+	Summary        string `yaml:"summary"`
+	Description    string `yaml:"description,omitempty"`
+	Location       string `yaml:"location,omitempty"`
+	Recommendation string `yaml:"recommendation"`
 }
 
 // AnalysisSummary represents the analysis summary.